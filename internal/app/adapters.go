@@ -8,11 +8,13 @@ import (
 	"github.com/dshills/keystorm/internal/engine/buffer"
 	"github.com/dshills/keystorm/internal/engine/cursor"
 	"github.com/dshills/keystorm/internal/input/mode"
+	"github.com/dshills/keystorm/internal/renderer/statusline"
 )
 
 // Compile-time interface checks.
 var (
 	_ execctx.EngineInterface        = (*EngineExecAdapter)(nil)
+	_ execctx.SearchProvider         = (*EngineExecAdapter)(nil)
 	_ execctx.CursorManagerInterface = (*CursorManagerAdapter)(nil)
 	_ execctx.ModeManagerInterface   = (*ModeExecAdapter)(nil)
 	_ execctx.HistoryInterface       = (*HistoryAdapter)(nil)
@@ -127,6 +129,56 @@ func (a *EngineExecAdapter) RevisionID() buffer.RevisionID {
 	return a.eng.RevisionID()
 }
 
+// AutoIndentEnabled returns true if the engine indents new lines.
+func (a *EngineExecAdapter) AutoIndentEnabled() bool {
+	return a.eng.AutoIndentEnabled()
+}
+
+// AutoIndent returns the leading whitespace for a line.
+func (a *EngineExecAdapter) AutoIndent(line int) string {
+	return a.eng.AutoIndent(line)
+}
+
+// Keywords returns the keyword characters for word motions.
+func (a *EngineExecAdapter) Keywords() cursor.Keywords {
+	return a.eng.Keywords()
+}
+
+// IndentBlock returns the indentation block around line.
+func (a *EngineExecAdapter) IndentBlock(line int, around bool) buffer.Range {
+	return a.eng.IndentBlock(line, around)
+}
+
+// FindNext returns the first match of pattern at or after from.
+func (a *EngineExecAdapter) FindNext(pattern string, from buffer.ByteOffset, opts engine.SearchOptions) (buffer.Range, bool, error) {
+	return a.eng.FindNext(pattern, from, opts)
+}
+
+// FindPrev returns the last match of pattern before from.
+func (a *EngineExecAdapter) FindPrev(pattern string, from buffer.ByteOffset, opts engine.SearchOptions) (buffer.Range, bool, error) {
+	return a.eng.FindPrev(pattern, from, opts)
+}
+
+// GotoLineColumn returns the offset of a line and byte column.
+func (a *EngineExecAdapter) GotoLineColumn(line, column int, mode engine.ClampMode) (buffer.ByteOffset, error) {
+	return a.eng.GotoLineColumn(line, column, mode)
+}
+
+// PointUTF16ToOffset converts a UTF-16 line/column to a byte offset.
+func (a *EngineExecAdapter) PointUTF16ToOffset(point buffer.PointUTF16) buffer.ByteOffset {
+	return a.eng.PointUTF16ToOffset(point)
+}
+
+// PasteLinewise pastes text as whole lines at each cursor.
+func (a *EngineExecAdapter) PasteLinewise(cursors *cursor.CursorSet, text string, after bool) error {
+	return a.eng.PasteLinewise(cursors, text, after)
+}
+
+// PasteBlockwise pastes lines as a block at each cursor.
+func (a *EngineExecAdapter) PasteBlockwise(cursors *cursor.CursorSet, lines []string, after bool) error {
+	return a.eng.PasteBlockwise(cursors, lines, after)
+}
+
 // Snapshot returns a read-only snapshot of the engine.
 func (a *EngineExecAdapter) Snapshot() execctx.EngineReader {
 	return &engineReaderAdapter{eng: a.eng}
@@ -276,6 +328,52 @@ func (a *ModeExecAdapter) IsAnyMode(names ...string) bool {
 	return false
 }
 
+// insertMode returns the registered insert mode, if it is the built-in one.
+func (a *ModeExecAdapter) insertMode() *mode.InsertMode {
+	if a.manager == nil {
+		return nil
+	}
+	m, _ := a.manager.Get(mode.ModeInsert).(*mode.InsertMode)
+	return m
+}
+
+// SetBlockInsert records a pending visual-block insert on insert mode.
+func (a *ModeExecAdapter) SetBlockInsert(b execctx.BlockInsert) {
+	if m := a.insertMode(); m != nil {
+		m.SetBlockInsert(mode.BlockInsert{
+			StartLine: b.StartLine,
+			EndLine:   b.EndLine,
+			Column:    b.Column,
+			Append:    b.Append,
+		})
+	}
+}
+
+// BlockInsert returns the pending visual-block insert, if any.
+func (a *ModeExecAdapter) BlockInsert() (execctx.BlockInsert, bool) {
+	m := a.insertMode()
+	if m == nil {
+		return execctx.BlockInsert{}, false
+	}
+	b, ok := m.BlockInsert()
+	if !ok {
+		return execctx.BlockInsert{}, false
+	}
+	return execctx.BlockInsert{
+		StartLine: b.StartLine,
+		EndLine:   b.EndLine,
+		Column:    b.Column,
+		Append:    b.Append,
+	}, true
+}
+
+// ClearBlockInsert discards the pending visual-block insert.
+func (a *ModeExecAdapter) ClearBlockInsert() {
+	if m := a.insertMode(); m != nil {
+		m.ClearBlockInsert()
+	}
+}
+
 // modeWrapper wraps mode.Mode to implement execctx.ModeInterface.
 type modeWrapper struct {
 	mode mode.Mode
@@ -314,7 +412,9 @@ func (a *HistoryAdapter) CancelGroup() {
 }
 
 func (a *HistoryAdapter) IsGrouping() bool {
-	// Engine doesn't expose grouping state directly
+	if a.eng != nil {
+		return a.eng.IsUndoGrouping()
+	}
 	return false
 }
 
@@ -349,8 +449,10 @@ func (a *HistoryAdapter) RedoCount() int {
 }
 
 // RendererAdapter adapts the renderer to execctx.RendererInterface.
+// With a status line it also implements execctx.MessageDisplay.
 type RendererAdapter struct {
-	renderer RendererInterface
+	renderer   RendererInterface
+	statusLine *statusline.StatusLine
 }
 
 // RendererInterface defines the renderer methods we need.
@@ -370,6 +472,18 @@ func NewRendererAdapter(renderer RendererInterface) *RendererAdapter {
 	return &RendererAdapter{renderer: renderer}
 }
 
+// SetStatusLine sets the status line that shows handler messages.
+func (a *RendererAdapter) SetStatusLine(s *statusline.StatusLine) {
+	a.statusLine = s
+}
+
+// ShowMessage shows msg in the status line, if one is set.
+func (a *RendererAdapter) ShowMessage(msg string) {
+	if a.statusLine != nil {
+		a.statusLine.SetMessage(msg, statusline.MessageInfo)
+	}
+}
+
 func (a *RendererAdapter) ScrollTo(line, col uint32) {
 	if a.renderer != nil {
 		a.renderer.ScrollTo(line, col)
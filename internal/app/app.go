@@ -13,8 +13,11 @@ import (
 	"github.com/dshills/keystorm/internal/config"
 	"github.com/dshills/keystorm/internal/dispatcher"
 	"github.com/dshills/keystorm/internal/event"
+	"github.com/dshills/keystorm/internal/input"
 	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/keymap"
 	"github.com/dshills/keystorm/internal/input/mode"
+	"github.com/dshills/keystorm/internal/input/mouse"
 	"github.com/dshills/keystorm/internal/integration"
 	"github.com/dshills/keystorm/internal/lsp"
 	"github.com/dshills/keystorm/internal/plugin"
@@ -39,6 +42,9 @@ type Application struct {
 	backend           backend.Backend
 	statusLine        *statusline.StatusLine
 	modeManager       *mode.Manager
+	keymaps           *keymap.Registry
+	keySeq            *key.Sequence
+	input             *input.Handler
 	dispatcher        *dispatcher.Dispatcher
 	highlightRegistry *highlight.Registry
 	highlightProvider *highlight.Provider
@@ -46,13 +52,29 @@ type Application struct {
 	// Document management
 	documents *DocumentManager
 
+	// Mouse handling: the buttons held at the last mouse event, and hover
+	// targets waiting for the event loop
+	mouse       *mouse.Handler
+	mouseButton backend.MouseButton
+	hovers      chan mouse.Target
+
+	// Inlay hints: the refresher requesting them as the view changes, the
+	// view last requested, and hints waiting for the event loop
+	inlayHints   *lsp.InlayHintRefresher
+	inlayView    inlayView
+	inlayResults chan inlayResult
+
+	// Work from other goroutines waiting to run on the event loop
+	scheduled chan func()
+
 	// Workspace components
-	project   project.Project
-	lspClient *lsp.Client
+	project    project.Project
+	lspClient  *lsp.Client
+	lspHandler *lsp.Handler
 
 	// Extension components
-	plugins     *plugin.Manager
-	integration *integration.Manager
+	pluginSystem *plugin.System
+	integration  *integration.Manager
 
 	// Event subscriptions
 	subscriptions *subscriptionManager
@@ -98,9 +120,15 @@ type Options struct {
 // New creates a new Application with the given options.
 func New(opts Options) (*Application, error) {
 	app := &Application{
-		opts: opts,
-		done: make(chan struct{}),
+		opts:   opts,
+		done:   make(chan struct{}),
+		mouse:  mouse.NewHandler(mouse.DefaultConfig()),
+		hovers: make(chan mouse.Target, 1),
+
+		inlayResults: make(chan inlayResult, 1),
+		scheduled:    make(chan func(), 16),
 	}
+	app.mouse.OnHover(app.queueHover)
 
 	// Use bootstrapper for component initialization with cleanup on failure
 	b := newBootstrapper(app, opts)
@@ -145,6 +173,7 @@ func (app *Application) Run() error {
 			return &InitError{Component: "backend", Err: err}
 		}
 		defer app.backend.Shutdown()
+		app.backend.EnableFocus()
 
 		// Create status line first to know how much space to reserve
 		app.statusLine = statusline.New()
@@ -179,10 +208,10 @@ func (app *Application) Run() error {
 		_ = err
 	}
 
-	// Load plugins
-	if app.plugins != nil {
+	// Load plugins with their API modules
+	if app.pluginSystem != nil {
 		ctx := context.Background()
-		if err := app.plugins.LoadAll(ctx); err != nil {
+		if err := app.pluginSystem.LoadAll(ctx); err != nil {
 			// Non-fatal, log warning
 			_ = err
 		}
@@ -232,6 +261,15 @@ func (app *Application) eventLoop() error {
 				_ = err
 			}
 
+		case target := <-app.hovers:
+			_ = app.dispatchAction(target.Action())
+
+		case result := <-app.inlayResults:
+			app.showInlayHints(result)
+
+		case fn := <-app.scheduled:
+			fn()
+
 		case <-frameTicker.C:
 			// Calculate delta time
 			now := time.Now()
@@ -242,6 +280,7 @@ func (app *Application) eventLoop() error {
 			if app.renderer != nil {
 				app.updateRenderer()
 				app.renderer.Update(dt)
+				app.refreshInlayHints()
 				app.renderer.Render()
 			}
 
@@ -253,6 +292,16 @@ func (app *Application) eventLoop() error {
 	return nil
 }
 
+// scheduleOnLoop runs fn on the event loop, such as a debounced action
+// whose delay has passed on a timer goroutine. It drops fn once the
+// application shuts down.
+func (app *Application) scheduleOnLoop(fn func()) {
+	select {
+	case app.scheduled <- fn:
+	case <-app.done:
+	}
+}
+
 // updateRenderer updates renderer state from current document.
 func (app *Application) updateRenderer() {
 	doc := app.documents.Active()
@@ -319,8 +368,7 @@ func (app *Application) updateLSPStatus(doc *Document) {
 		return
 	}
 
-	// Detect language from file path
-	languageID := lsp.DetectLanguageID(doc.Path)
+	languageID := doc.LanguageID
 	if languageID == "" {
 		app.statusLine.SetLSPStatus(statusline.LSPStatusNone)
 		return
@@ -363,8 +411,9 @@ func (app *Application) initHighlighting() {
 	app.highlightRegistry = highlight.NewRegistry()
 	highlight.RegisterBuiltinHighlighters(app.highlightRegistry)
 
-	// Create provider with default theme
-	app.highlightProvider = highlight.NewProvider(nil, 1000)
+	// Create provider with the configured theme
+	theme := app.configuredTheme()
+	app.highlightProvider = highlight.NewProvider(theme, 1000)
 
 	// Wire to active document
 	app.updateHighlighting()
@@ -372,7 +421,55 @@ func (app *Application) initHighlighting() {
 	// Set provider on renderer
 	if app.renderer != nil {
 		app.renderer.SetHighlightProvider(app.highlightProvider)
+		app.renderer.SetTheme(theme)
+	}
+}
+
+// applyTheme switches highlighting to the theme selected by the current
+// configuration.
+func (app *Application) applyTheme() {
+	theme := app.configuredTheme()
+	if app.highlightProvider != nil {
+		app.highlightProvider.SetTheme(theme)
+	}
+	if app.renderer != nil {
+		app.renderer.SetTheme(theme)
+	}
+}
+
+// configuredTheme returns the theme selected by the ui.theme setting. The
+// setting names a built-in theme, a theme from ui.customThemes, or a
+// variant ("dark" or "light"). Unknown names fall back to the default
+// dark theme.
+func (app *Application) configuredTheme() *highlight.Theme {
+	themes := highlight.NewThemeRegistry()
+	if app.config == nil {
+		return themes.Current()
 	}
+
+	if value, ok := app.config.Get("ui.customThemes"); ok {
+		custom, _ := value.([]any)
+		for _, item := range custom {
+			section, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			theme, err := highlight.ThemeFromConfig(section)
+			if err != nil {
+				app.LogWarn("custom theme ignored", "error", err)
+				continue
+			}
+			themes.Register(theme)
+		}
+	}
+
+	name := app.config.UI().Theme
+	if v, ok := highlight.ParseVariant(name); ok {
+		themes.SetVariant(v)
+	} else if !themes.SetCurrent(name) {
+		app.LogWarn("unknown theme", "theme", name)
+	}
+	return themes.Current()
 }
 
 // updateHighlighting updates the highlight provider for the current document.
@@ -428,11 +525,11 @@ func (app *Application) shutdown() {
 	var wg sync.WaitGroup
 
 	// 1. Stop plugins
-	if app.plugins != nil {
+	if app.pluginSystem != nil {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_ = app.plugins.UnloadAll(ctx)
+			_ = app.pluginSystem.Shutdown(ctx)
 		}()
 	}
 
@@ -446,6 +543,12 @@ func (app *Application) shutdown() {
 	}
 
 	// 3. Stop LSP
+	if app.input != nil {
+		app.input.Close()
+	}
+	if app.inlayHints != nil {
+		app.inlayHints.Close()
+	}
 	if app.lspClient != nil {
 		wg.Add(1)
 		go func() {
@@ -467,8 +570,11 @@ func (app *Application) shutdown() {
 		// Timeout - continue with cleanup
 	}
 
-	// 4. Close project
+	// 4. Save the session and close project
 	if app.project != nil {
+		if err := app.SaveSession(ctx); err != nil {
+			app.LogWarn("session save failed", "error", err)
+		}
 		app.project.Close(ctx)
 	}
 
@@ -512,6 +618,12 @@ func (app *Application) Renderer() *renderer.Renderer {
 	return app.renderer
 }
 
+// Keymaps returns the keymap registry consulted for key events before the
+// current mode handles them.
+func (app *Application) Keymaps() *keymap.Registry {
+	return app.keymaps
+}
+
 // ModeManager returns the mode manager.
 func (app *Application) ModeManager() *mode.Manager {
 	return app.modeManager
@@ -539,7 +651,10 @@ func (app *Application) LSPClient() *lsp.Client {
 
 // Plugins returns the plugin manager (may be nil).
 func (app *Application) Plugins() *plugin.Manager {
-	return app.plugins
+	if app.pluginSystem == nil {
+		return nil
+	}
+	return app.pluginSystem.Manager()
 }
 
 // Integration returns the integration manager (may be nil).
@@ -7,6 +7,19 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/input"
+	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/keymap"
+	"github.com/dshills/keystorm/internal/input/mode"
+	"github.com/dshills/keystorm/internal/input/mouse"
+	"github.com/dshills/keystorm/internal/lsp"
+	"github.com/dshills/keystorm/internal/plugin/api"
+	"github.com/dshills/keystorm/internal/renderer"
+	"github.com/dshills/keystorm/internal/renderer/backend"
 )
 
 func TestNewApplication(t *testing.T) {
@@ -141,6 +154,308 @@ func TestApplication_OpenFiles(t *testing.T) {
 	}
 }
 
+func TestApplication_SaveDocumentRunsSaveHooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("one  \ntwo\t"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	app, err := New(Options{Files: []string{path}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	// The final newline is inserted by default
+	if err := app.SaveDocument(); err != nil {
+		t.Fatalf("SaveDocument() failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "one  \ntwo\t\n" {
+		t.Errorf("saved content = %q, want %q", got, "one  \ntwo\t\n")
+	}
+
+	if err := app.config.SetRuntime("files.trimTrailingWhitespace", true); err != nil {
+		t.Fatalf("SetRuntime() failed: %v", err)
+	}
+	if err := app.SaveDocument(); err != nil {
+		t.Fatalf("SaveDocument() failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "one\ntwo\n" {
+		t.Errorf("saved content = %q, want %q", got, "one\ntwo\n")
+	}
+}
+
+func TestApplication_SaveHooksPerLanguage(t *testing.T) {
+	dir := t.TempDir()
+	goPath := filepath.Join(dir, "main.go")
+	txtPath := filepath.Join(dir, "notes.txt")
+	for _, path := range []string{goPath, txtPath} {
+		if err := os.WriteFile(path, []byte("\tx  \n"), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	app, err := New(Options{Files: []string{txtPath, goPath}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	for path, value := range map[string]any{
+		"files.tabsToSpaces":                     true,
+		"files.trimTrailingWhitespace":           true,
+		"languages.go.files.tabsToSpaces":        false,
+		"languages.plaintext.files.tabsToSpaces": true,
+	} {
+		if err := app.config.SetRuntime(path, value); err != nil {
+			t.Fatalf("SetRuntime(%s) failed: %v", path, err)
+		}
+	}
+
+	for _, doc := range app.Documents().All() {
+		app.documents.SetActive(doc)
+		if err := app.SaveDocument(); err != nil {
+			t.Fatalf("SaveDocument(%s) failed: %v", doc.Path, err)
+		}
+	}
+
+	// Go keeps its tabs; other languages have them expanded
+	if got, _ := os.ReadFile(goPath); string(got) != "\tx\n" {
+		t.Errorf("main.go = %q, want %q", got, "\tx\n")
+	}
+	if got, _ := os.ReadFile(txtPath); string(got) != "    x\n" {
+		t.Errorf("notes.txt = %q, want %q", got, "    x\n")
+	}
+}
+
+func TestApplication_FocusLostAutoSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("one\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	app, err := New(Options{Files: []string{path}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	doc := app.ActiveDocument()
+	if _, err := doc.Engine.Insert(0, "zero\n"); err != nil {
+		t.Fatalf("Insert() failed: %v", err)
+	}
+	doc.SetModified(true)
+
+	// Losing focus saves nothing until autosave on focus change is set
+	focusLost := backend.Event{Type: backend.EventFocus, Focused: false}
+	if err := app.handleBackendEvent(focusLost); err != nil {
+		t.Fatalf("handleBackendEvent() failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "one\n" {
+		t.Errorf("content after focus lost = %q, want it unsaved", got)
+	}
+
+	if err := app.config.SetRuntime("files.autoSave", "onFocusChange"); err != nil {
+		t.Fatalf("SetRuntime() failed: %v", err)
+	}
+	if err := app.handleBackendEvent(focusLost); err != nil {
+		t.Fatalf("handleBackendEvent() failed: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "zero\none\n" {
+		t.Errorf("content after focus lost = %q, want %q", got, "zero\none\n")
+	}
+	if doc.IsModified() {
+		t.Error("document still modified after autosave")
+	}
+}
+
+func TestApplication_SetCommandWritesRuntimeOverrides(t *testing.T) {
+	app, err := New(Options{})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	run := func(name string, args map[string]any) {
+		t.Helper()
+		result := &mode.UnmappedResult{Action: &mode.Action{Name: name, Args: args}}
+		if err := app.processModeResult(result, key.Event{}); err != nil {
+			t.Fatalf("processModeResult(%s) failed: %v", name, err)
+		}
+	}
+
+	run(ActionSettingsSet, map[string]any{"path": "editor.tabSize", "value": "2"})
+	if got, _ := app.config.GetInt("editor.tabSize"); got != 2 {
+		t.Errorf("editor.tabSize = %d, want 2", got)
+	}
+
+	run(ActionSettingsToggle, map[string]any{"path": "editor.insertSpaces"})
+	if got, _ := app.config.GetBool("editor.insertSpaces"); got {
+		t.Error("editor.insertSpaces = true after toggle, want false")
+	}
+
+	overrides := app.config.RuntimeOverrides()
+	if overrides["editor.tabSize"] != 2 || overrides["editor.insertSpaces"] != false {
+		t.Errorf("RuntimeOverrides() = %v, want tabSize and insertSpaces overridden", overrides)
+	}
+}
+
+func TestApplication_ConfiguredTheme(t *testing.T) {
+	app, err := New(Options{})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	if got := app.configuredTheme().Name; got != "Default Dark" {
+		t.Errorf("default theme = %q, want %q", got, "Default Dark")
+	}
+
+	if err := app.config.SetRuntime("ui.theme", "light"); err != nil {
+		t.Fatalf("SetRuntime(ui.theme) failed: %v", err)
+	}
+	if got := app.configuredTheme().Name; got != "Light" {
+		t.Errorf("light variant theme = %q, want %q", got, "Light")
+	}
+
+	custom := []any{map[string]any{
+		"name":   "Custom",
+		"scopes": map[string]any{"keyword": map[string]any{"foreground": "#ff0000"}},
+	}}
+	if err := app.config.SetRuntime("ui.customThemes", custom); err != nil {
+		t.Fatalf("SetRuntime(ui.customThemes) failed: %v", err)
+	}
+	if err := app.config.SetRuntime("ui.theme", "Custom"); err != nil {
+		t.Fatalf("SetRuntime(ui.theme) failed: %v", err)
+	}
+	app.applyTheme()
+	if app.highlightProvider != nil {
+		if got := app.highlightProvider.Theme().Name; got != "Custom" {
+			t.Errorf("highlight provider theme = %q, want %q", got, "Custom")
+		}
+	}
+	if app.renderer != nil {
+		if got := app.renderer.Theme().Name; got != "Custom" {
+			t.Errorf("renderer theme = %q, want %q", got, "Custom")
+		}
+	}
+}
+
+func TestApplication_InputFollowsFiletype(t *testing.T) {
+	tmpDir := t.TempDir()
+	goFile := filepath.Join(tmpDir, "main.go")
+	pyFile := filepath.Join(tmpDir, "main.py")
+	for _, path := range []string{goFile, pyFile} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	app, err := New(Options{Files: []string{pyFile, goFile}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	registry := app.Keymaps()
+	if got := registry.ActiveFiletype(); got != "go" {
+		t.Errorf("ActiveFiletype() = %q, want %q", got, "go")
+	}
+
+	py, _ := app.Documents().Get(pyFile)
+	app.SwitchDocument(py)
+	if got := registry.ActiveFiletype(); got != "python" {
+		t.Errorf("ActiveFiletype() after switch = %q, want %q", got, "python")
+	}
+}
+
+func TestApplication_FiletypeLayerHandlesKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	goFile := filepath.Join(tmpDir, "main.go")
+	pyFile := filepath.Join(tmpDir, "main.py")
+	for _, path := range []string{goFile, pyFile} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	app, err := New(Options{Files: []string{pyFile, goFile}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	var called []string
+	app.dispatcher.RegisterHandlerFunc("test.goKey", func(action input.Action, _ *execctx.ExecutionContext) handler.Result {
+		called = append(called, action.Name)
+		return handler.Success()
+	})
+	err = app.Keymaps().PushFiletypeLayer("go", []keymap.Binding{{Keys: "g x", Action: "test.goKey"}})
+	if err != nil {
+		t.Fatalf("PushFiletypeLayer() error = %v", err)
+	}
+
+	press := func(r rune) {
+		if err := app.handleKeyEvent(backend.Event{Type: backend.EventKey, Key: backend.KeyRune, Rune: r}); err != nil {
+			t.Fatalf("handleKeyEvent(%q) error = %v", r, err)
+		}
+	}
+
+	press('g')
+	press('x')
+	if len(called) != 1 {
+		t.Fatalf("go buffer: filetype binding called %d times, want 1", len(called))
+	}
+
+	py, _ := app.Documents().Get(pyFile)
+	app.SwitchDocument(py)
+	press('g')
+	press('x')
+	if len(called) != 1 {
+		t.Errorf("python buffer: filetype binding called %d times, want 1", len(called))
+	}
+}
+
+func TestApplication_ConfiguresIndent(t *testing.T) {
+	tmpDir := t.TempDir()
+	pyFile := filepath.Join(tmpDir, "main.py")
+	goFile := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(pyFile, []byte("if x:\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(goFile, []byte("func f() {\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	app, err := New(Options{Files: []string{pyFile}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	py := app.Documents().Active()
+	if !py.Engine.AutoIndentEnabled() {
+		t.Fatal("AutoIndentEnabled() = false, want true")
+	}
+	if got := py.Engine.AutoIndent(1); got != "    " {
+		t.Errorf("AutoIndent(1) after colon = %q, want 4 spaces", got)
+	}
+
+	if err := app.config.SetRuntime("editor.insertSpaces", false); err != nil {
+		t.Fatalf("SetRuntime() failed: %v", err)
+	}
+	doc, err := app.OpenFile(goFile)
+	if err != nil {
+		t.Fatalf("OpenFile() failed: %v", err)
+	}
+	if !doc.Engine.UseTabs() {
+		t.Error("UseTabs() = false with editor.insertSpaces off")
+	}
+	if got := doc.Engine.AutoIndent(1); got != "\t" {
+		t.Errorf("AutoIndent(1) after brace = %q, want a tab", got)
+	}
+}
+
 func TestApplication_NoFiles_CreatesScratch(t *testing.T) {
 	opts := Options{
 		Files: []string{},
@@ -252,3 +567,316 @@ func TestApplication_PublishFileEvent(t *testing.T) {
 		t.Errorf("PublishFileEvent() failed: %v", err)
 	}
 }
+
+func TestApplication_OpenLink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "linked.txt")
+	if err := os.WriteFile(path, []byte("linked\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	app, err := New(Options{})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	result := app.Dispatcher().Dispatch(input.Action{
+		Name: ActionOpenLink,
+		Args: input.ActionArgs{Extra: map[string]interface{}{"url": "file://" + path}},
+	})
+	if result.Status != handler.StatusOK {
+		t.Fatalf("Dispatch(file url) status = %v, error = %v", result.Status, result.Error)
+	}
+	if got := app.Documents().Active().Path; got != path {
+		t.Errorf("active document = %q, want %q", got, path)
+	}
+
+	var opened string
+	defer func(orig func(string) error) { openURL = orig }(openURL)
+	openURL = func(rawURL string) error {
+		opened = rawURL
+		return nil
+	}
+	result = app.Dispatcher().Dispatch(input.Action{
+		Name: ActionOpenLink,
+		Args: input.ActionArgs{Extra: map[string]interface{}{"url": "https://example.com"}},
+	})
+	if result.Status != handler.StatusOK {
+		t.Fatalf("Dispatch(https url) status = %v, error = %v", result.Status, result.Error)
+	}
+	if opened != "https://example.com" {
+		t.Errorf("opened = %q, want %q", opened, "https://example.com")
+	}
+}
+
+func TestApplication_ConvertMouseEvent(t *testing.T) {
+	app, err := New(Options{})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	tests := []struct {
+		button     backend.MouseButton
+		mod        backend.ModMask
+		wantAction mouse.Action
+		wantButton mouse.Button
+	}{
+		{backend.MouseNone, 0, mouse.ActionMove, mouse.ButtonNone},
+		{backend.MouseLeft, backend.ModCtrl, mouse.ActionPress, mouse.ButtonLeft},
+		{backend.MouseLeft, 0, mouse.ActionDrag, mouse.ButtonLeft},
+		{backend.MouseNone, 0, mouse.ActionRelease, mouse.ButtonLeft},
+		{backend.MouseWheelDown, 0, mouse.ActionPress, mouse.ButtonScrollDown},
+	}
+
+	for i, tt := range tests {
+		event := app.convertMouseEvent(backend.Event{
+			Type:        backend.EventMouse,
+			MouseX:      3,
+			MouseY:      1,
+			MouseButton: tt.button,
+			Mod:         tt.mod,
+		})
+		if event.Action != tt.wantAction {
+			t.Errorf("event %d: Action = %v, want %v", i, event.Action, tt.wantAction)
+		}
+		if event.Button != tt.wantButton {
+			t.Errorf("event %d: Button = %v, want %v", i, event.Button, tt.wantButton)
+		}
+		if got := event.Modifiers.HasCtrl(); got != (tt.mod == backend.ModCtrl) {
+			t.Errorf("event %d: HasCtrl() = %v", i, got)
+		}
+	}
+}
+
+// fixedScreen maps every screen position to one buffer position.
+type fixedScreen struct {
+	line, col uint32
+}
+
+func (s fixedScreen) ScreenToBuffer(_, _ int) (uint32, uint32, bool) {
+	return s.line, s.col, true
+}
+
+func TestDocumentMouseContext(t *testing.T) {
+	eng := engine.New(engine.WithContent("first\nhé llo\n"))
+
+	tests := []struct {
+		col    uint32
+		want   int
+		wantOK bool
+	}{
+		{0, 6, true},
+		{2, 9, true},
+		{5, 12, true},
+		{6, 0, false},
+	}
+
+	for _, tt := range tests {
+		ctx := &documentMouseContext{screen: fixedScreen{line: 1, col: tt.col}, eng: eng}
+		got, ok := ctx.OffsetAt(mouse.Position{})
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("OffsetAt(col %d) = %d, %v, want %d, %v", tt.col, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	ctx := &documentMouseContext{eng: eng}
+	text, start := ctx.LineAt(9)
+	if text != "hé llo" || start != 6 {
+		t.Errorf("LineAt(9) = %q, %d, want %q, 6", text, start, "hé llo")
+	}
+}
+
+func TestApplication_InlayHintsFollowView(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("f(1, 2)\nx := g()\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	app, err := New(Options{Files: []string{path}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	doc := app.Documents().Active()
+	app.renderer = renderer.New(backend.NewNullBackend(80, 24), renderer.DefaultOptions())
+	app.renderer.SetBuffer(doc.Engine)
+
+	requests := make(chan lsp.Range, 4)
+	fetch := func(_ context.Context, _ string, rng lsp.Range) ([]lsp.InlayHint, error) {
+		requests <- rng
+		return []lsp.InlayHint{
+			{Position: lsp.Position{Line: 0, Character: 2}, Label: lsp.InlayHintLabel{{Value: "a:"}}, PaddingRight: true},
+			{Position: lsp.Position{Line: 1, Character: 1}, Label: lsp.InlayHintLabel{{Value: ": int"}}},
+		}, nil
+	}
+	app.inlayHints.Close()
+	app.inlayHints = lsp.NewInlayHintRefresher(fetch, time.Millisecond, app.queueInlayHints)
+
+	app.refreshInlayHints()
+	app.refreshInlayHints()
+
+	select {
+	case result := <-app.inlayResults:
+		app.showInlayHints(result)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no inlay hints delivered")
+	}
+	if rng := <-requests; rng.Start.Line != 0 || rng.End.Line != 2 {
+		t.Errorf("requested range = %+v, want lines 0 to 2", rng)
+	}
+	select {
+	case rng := <-requests:
+		t.Errorf("unchanged view requested again: %+v", rng)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	want := []renderer.InlayHint{{Column: 2, Text: "a: "}}
+	if got := app.renderer.InlayHints(0); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("InlayHints(0) = %+v, want %+v", got, want)
+	}
+	if got := app.renderer.InlayHints(1); len(got) != 1 || got[0].Column != 1 {
+		t.Errorf("InlayHints(1) = %+v, want one hint at column 1", got)
+	}
+
+	// Editing clears the hints until the new ones arrive
+	if _, err := doc.Engine.Insert(0, "// "); err != nil {
+		t.Fatalf("Insert() failed: %v", err)
+	}
+	app.refreshInlayHints()
+	if got := app.renderer.InlayHints(0); got != nil {
+		t.Errorf("InlayHints(0) after edit = %+v, want none", got)
+	}
+	select {
+	case <-requests:
+	case <-time.After(2 * time.Second):
+		t.Error("edit did not request new inlay hints")
+	}
+}
+
+func TestApplication_DebouncedActionsRunOnEventLoop(t *testing.T) {
+	app, err := New(Options{})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	result := app.Dispatcher().Dispatch(input.Action{Name: lsp.ActionCompletion})
+	if result.Status != handler.StatusAsync {
+		t.Fatalf("completion status = %v, want async", result.Status)
+	}
+
+	select {
+	case fn := <-app.scheduled:
+		if fn == nil {
+			t.Error("scheduled a nil func")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("debounced completion was not scheduled on the event loop")
+	}
+}
+
+func TestDocumentEditResolver(t *testing.T) {
+	dir := t.TempDir()
+	onDisk := filepath.Join(dir, "disk.go")
+	if err := os.WriteFile(onDisk, []byte("package a\nvar x = 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	openPath := filepath.Join(dir, "open.go")
+	if err := os.WriteFile(openPath, []byte("package b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dm := NewDocumentManager()
+	doc, err := dm.Open(openPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolver := &documentEditResolver{documents: dm}
+	client := lsp.NewClient()
+	edit := func(path string, edits ...lsp.TextEdit) lsp.WorkspaceEdit {
+		return lsp.WorkspaceEdit{Changes: map[lsp.DocumentURI][]lsp.TextEdit{lsp.FilePathToURI(path): edits}}
+	}
+	at := func(line, start, end int, text string) lsp.TextEdit {
+		return lsp.TextEdit{
+			Range:   lsp.Range{Start: lsp.Position{Line: line, Character: start}, End: lsp.Position{Line: line, Character: end}},
+			NewText: text,
+		}
+	}
+
+	// A failed edit leaves the open document unmodified
+	overlapping := edit(openPath, at(0, 0, 5, "x"), at(0, 2, 4, "y"))
+	if err := client.ApplyWorkspaceEdit(context.Background(), overlapping, resolver); err == nil {
+		t.Fatal("ApplyWorkspaceEdit() with overlapping edits succeeded")
+	}
+	if doc.IsModified() {
+		t.Error("open document modified by a failed edit")
+	}
+	if err := client.ApplyWorkspaceEdit(context.Background(), edit(openPath, at(0, 8, 9, "c")), resolver); err != nil {
+		t.Fatalf("ApplyWorkspaceEdit() error = %v", err)
+	}
+	if !doc.IsModified() || doc.Content() != "package c\n" {
+		t.Errorf("open document = %q, modified %v, want edited and modified", doc.Content(), doc.IsModified())
+	}
+
+	// Files that are not open are written back, keeping their mode
+	if err := client.ApplyWorkspaceEdit(context.Background(), edit(onDisk, at(0, 8, 9, "z"), at(1, 8, 9, "2")), resolver); err != nil {
+		t.Fatalf("ApplyWorkspaceEdit() error = %v", err)
+	}
+	data, err := os.ReadFile(onDisk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "package z\nvar x = 2\n" {
+		t.Errorf("file = %q, want %q", data, "package z\nvar x = 2\n")
+	}
+	info, err := os.Stat(onDisk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestApplication_PluginBufferBatch(t *testing.T) {
+	app, err := New(Options{})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer app.Shutdown()
+
+	provider, ok := app.pluginSystem.APIContext().Buffer.(api.BatchBufferProvider)
+	if !ok {
+		t.Fatal("plugin buffer provider does not support batches")
+	}
+	doc := app.Documents().Active()
+	if _, err := doc.Engine.Insert(0, "hello world"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Offsets refer to the buffer before the batch
+	err = provider.Batch("rename", func(b api.BufferBatch) error {
+		b.Replace(0, 5, "goodbye")
+		b.Insert(11, "!")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+	if got := doc.Engine.Text(); got != "goodbye world!" {
+		t.Errorf("text = %q, want %q", got, "goodbye world!")
+	}
+	if !doc.IsModified() {
+		t.Error("document not marked modified by the batch")
+	}
+
+	// The batch is a single undo step
+	if err := doc.Engine.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if got := doc.Engine.Text(); got != "hello world" {
+		t.Errorf("text after undo = %q, want %q", got, "hello world")
+	}
+}
@@ -6,11 +6,14 @@ import (
 
 	"github.com/dshills/keystorm/internal/config"
 	"github.com/dshills/keystorm/internal/dispatcher"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/file"
 	"github.com/dshills/keystorm/internal/event"
+	"github.com/dshills/keystorm/internal/input"
+	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/keymap"
 	"github.com/dshills/keystorm/internal/input/mode"
 	"github.com/dshills/keystorm/internal/integration"
 	"github.com/dshills/keystorm/internal/lsp"
-	"github.com/dshills/keystorm/internal/plugin"
 	"github.com/dshills/keystorm/internal/project"
 )
 
@@ -137,6 +140,17 @@ func (b *bootstrapper) initModeManager() error {
 	// Register default editing modes
 	b.registerModes()
 
+	// Keymaps consulted before the mode's own key handling; they follow
+	// the active buffer's filetype so its filetype layer applies
+	b.app.keymaps = keymap.NewRegistry()
+	b.app.keySeq = key.NewSequence()
+
+	// Terminal focus changes go through an input handler, which turns them
+	// into editor.focusGained and editor.focusLost actions
+	inputConfig := input.DefaultConfig()
+	inputConfig.EnableFocusEvents = true
+	b.app.input = input.NewHandler(inputConfig)
+
 	b.initOrder = append(b.initOrder, "modeManager")
 	return nil
 }
@@ -170,14 +184,35 @@ func (b *bootstrapper) initDispatcher() error {
 	// Register core handlers
 	b.registerHandlers()
 
+	// Keep repeated saves and completion requests from piling up, and run
+	// debounced actions on the event loop with every other action
+	b.app.dispatcher.SetRateLimit(file.ActionSave, saveRateLimit)
+	b.app.dispatcher.SetDebounce(lsp.ActionCompletion, completionDebounce)
+	b.app.dispatcher.SetDebounceScheduler(b.app.scheduleOnLoop)
+
 	b.initOrder = append(b.initOrder, "dispatcher")
 	return nil
 }
 
+const (
+	// saveRateLimit is the minimum time between saves, so a repeating
+	// save key writes the file once.
+	saveRateLimit = 500 * time.Millisecond
+
+	// completionDebounce is how long typing must pause before completions
+	// are requested from the language server.
+	completionDebounce = 100 * time.Millisecond
+)
+
 // registerHandlers registers all dispatcher handlers.
 func (b *bootstrapper) registerHandlers() {
 	// Register all standard handlers with the dispatcher
-	RegisterHandlers(b.app.dispatcher)
+	RegisterHandlers(b.app.dispatcher, b.app.config)
+	b.app.dispatcher.RegisterHandlerFunc(ActionOpenLink, b.app.handleOpenLink)
+	b.app.dispatcher.RegisterHandlerFunc(ActionFocusGained, b.app.handleFocusGained)
+	b.app.dispatcher.RegisterHandlerFunc(ActionFocusLost, b.app.handleFocusLost)
+	b.app.dispatcher.RegisterHandlerFunc(ActionSettingsSet, b.app.handleSettingsSet)
+	b.app.dispatcher.RegisterHandlerFunc(ActionSettingsToggle, b.app.handleSettingsToggle)
 }
 
 // initProject initializes the project/workspace manager.
@@ -222,15 +257,23 @@ func (b *bootstrapper) initLSP() error {
 	}
 
 	// Register LSP handler with dispatcher
-	RegisterLSPHandler(b.app.dispatcher, b.app.lspClient)
+	b.app.lspHandler = RegisterLSPHandler(b.app.dispatcher, b.app.lspClient,
+		lsp.WithSignatureHelpCallback(b.app.publishSignatureHelp))
+
+	// Request inlay hints for the visible range as it changes
+	b.app.inlayHints = lsp.NewInlayHintRefresher(b.app.lspClient.InlayHints, 0, b.app.queueInlayHints)
 
 	b.initOrder = append(b.initOrder, "lsp")
 	return nil
 }
 
-// initPlugins initializes the plugin manager.
+// initPlugins initializes the plugin system.
 func (b *bootstrapper) initPlugins() error {
-	b.app.plugins = plugin.NewManager(plugin.DefaultManagerConfig())
+	system, err := newPluginSystem(b.app)
+	if err != nil {
+		return err
+	}
+	b.app.pluginSystem = system
 	b.initOrder = append(b.initOrder, "plugins")
 	return nil
 }
@@ -261,6 +304,16 @@ func (b *bootstrapper) initIntegration() error {
 func (b *bootstrapper) initDocuments() error {
 	b.app.documents = NewDocumentManager()
 
+	// Code action edits apply to open documents, or to files on disk
+	if b.app.lspClient != nil {
+		b.app.lspClient.SetDocumentResolver(&documentEditResolver{documents: b.app.documents})
+	}
+
+	// Let language servers update imports when the project renames files
+	if b.app.project != nil && b.app.lspClient != nil {
+		b.app.project.AddRenameParticipant(newLSPRenameParticipant(b.app.lspClient, b.app.documents))
+	}
+
 	// Open initial files
 	for _, file := range b.opts.Files {
 		if _, err := b.app.documents.Open(file); err != nil {
@@ -269,10 +322,19 @@ func (b *bootstrapper) initDocuments() error {
 		}
 	}
 
+	// Reopen the previous session when no files were requested
+	if len(b.opts.Files) == 0 {
+		if err := b.app.RestoreSession(context.Background()); err != nil {
+			// Session errors are non-fatal for startup
+			b.app.LogWarn("session restore failed", "error", err)
+		}
+	}
+
 	// Create scratch buffer if no files opened
 	if b.app.documents.Count() == 0 {
 		b.app.documents.CreateScratch()
 	}
+	b.app.syncFiletype()
 
 	b.initOrder = append(b.initOrder, "documents")
 	return nil
@@ -305,6 +367,12 @@ func (b *bootstrapper) cleanupComponent(ctx context.Context, component string) {
 			b.app.config = nil
 		}
 	case "modeManager":
+		b.app.keymaps = nil
+		b.app.keySeq = nil
+		if b.app.input != nil {
+			b.app.input.Close()
+			b.app.input = nil
+		}
 		b.app.modeManager = nil
 	case "dispatcher":
 		b.app.dispatcher = nil
@@ -319,9 +387,9 @@ func (b *bootstrapper) cleanupComponent(ctx context.Context, component string) {
 			b.app.lspClient = nil
 		}
 	case "plugins":
-		if b.app.plugins != nil {
-			_ = b.app.plugins.UnloadAll(ctx)
-			b.app.plugins = nil
+		if b.app.pluginSystem != nil {
+			_ = b.app.pluginSystem.Shutdown(ctx)
+			b.app.pluginSystem = nil
 		}
 	case "integration":
 		if b.app.integration != nil {
@@ -383,6 +451,7 @@ func (app *Application) WireDispatcher() {
 	if app.renderer != nil {
 		rendererWrapper := NewRendererExecWrapperWithViewport(app.renderer, app.renderer.Viewport())
 		rendererAdapter := NewRendererAdapter(rendererWrapper)
+		rendererAdapter.SetStatusLine(app.statusLine)
 		app.dispatcher.SetRenderer(rendererAdapter)
 	}
 }
@@ -396,4 +465,5 @@ func (app *Application) SwitchDocument(doc *Document) {
 	app.documents.SetActive(doc)
 	app.WireDispatcher()
 	app.updateHighlighting()
+	app.syncFiletype()
 }
@@ -260,7 +260,7 @@ func DefaultTerminalConfig() map[string]any {
 		"cursorBlink": true,
 
 		// Bell
-		"bellStyle": "none", // none, sound, visual
+		"bell": "audible", // audible, visual, ignore
 
 		// Integration
 		"copyOnSelect":     true,
@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 
 	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/langid"
 	"github.com/dshills/keystorm/internal/lsp"
 	"github.com/dshills/keystorm/internal/renderer"
 )
@@ -36,6 +37,18 @@ type Document struct {
 
 	// lspOpened tracks if document was opened with LSP.
 	lspOpened atomic.Bool
+
+	// lspChanges holds the edits made since the document was opened with
+	// LSP that were not yet sent, in order. lspSync serializes sending
+	// them, so they reach the server in the order they were made.
+	lspMu      sync.Mutex
+	lspChanges []lsp.TextDocumentContentChangeEvent
+	lspSync    sync.Mutex
+
+	// indentConfigured and indentLanguage record the language the engine's
+	// indentation was last configured for (see configureIndent).
+	indentConfigured bool
+	indentLanguage   string
 }
 
 // NewDocument creates a new document from a file path.
@@ -46,13 +59,15 @@ func NewDocument(path string, content []byte) *Document {
 	}
 
 	eng := engine.New(engine.WithContent(string(content)))
+	languageID, _ := langid.Detect(path, content)
 
 	doc := &Document{
 		Path:       path,
 		Name:       name,
 		Engine:     eng,
-		LanguageID: lsp.DetectLanguageID(path),
+		LanguageID: languageID,
 	}
+	eng.OnChange(doc.recordLSPChanges)
 
 	return doc
 }
@@ -96,9 +111,45 @@ func (d *Document) IsLSPOpened() bool {
 	return d.lspOpened.Load()
 }
 
-// SetLSPOpened marks the document as opened with LSP.
+// SetLSPOpened marks the document as opened with LSP. Edits made before
+// are dropped, since opening sends the whole content.
 func (d *Document) SetLSPOpened(opened bool) {
+	d.lspMu.Lock()
+	defer d.lspMu.Unlock()
 	d.lspOpened.Store(opened)
+	d.lspChanges = nil
+}
+
+// recordLSPChanges is the engine change listener that queues the edits of
+// a document opened with LSP as incremental content changes. Each range is
+// taken from the content the edit applied to, in UTF-16 positions.
+func (d *Document) recordLSPChanges(ev engine.ChangeEvent) {
+	if !d.lspOpened.Load() {
+		return
+	}
+
+	d.lspMu.Lock()
+	defer d.lspMu.Unlock()
+	for _, c := range ev.Changes {
+		start := c.Before.OffsetToPointUTF16(c.Edit.Range.Start)
+		end := c.Before.OffsetToPointUTF16(c.Edit.Range.End)
+		d.lspChanges = append(d.lspChanges, lsp.TextDocumentContentChangeEvent{
+			Range: &lsp.Range{
+				Start: lsp.Position{Line: int(start.Line), Character: int(start.Column)},
+				End:   lsp.Position{Line: int(end.Line), Character: int(end.Column)},
+			},
+			Text: c.Edit.NewText,
+		})
+	}
+}
+
+// takeLSPChanges returns the queued content changes and clears the queue.
+func (d *Document) takeLSPChanges() []lsp.TextDocumentContentChangeEvent {
+	d.lspMu.Lock()
+	defer d.lspMu.Unlock()
+	changes := d.lspChanges
+	d.lspChanges = nil
+	return changes
 }
 
 // Content returns the full document content.
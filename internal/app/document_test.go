@@ -3,7 +3,11 @@ package app
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/lsp"
 )
 
 func TestNewDocument(t *testing.T) {
@@ -117,6 +121,48 @@ func TestDocument_LSPOpened(t *testing.T) {
 	}
 }
 
+func TestDocument_LSPChanges(t *testing.T) {
+	doc := NewDocument("/path/to/file.go", []byte("héllo\nworld\n"))
+
+	// Edits before the document is opened with LSP are not queued
+	if _, err := doc.Engine.Insert(0, "x"); err != nil {
+		t.Fatal(err)
+	}
+	doc.SetLSPOpened(true)
+	if got := doc.takeLSPChanges(); len(got) != 0 {
+		t.Fatalf("changes before open = %+v, want none", got)
+	}
+
+	// A single edit after the é, which is 2 bytes but 1 UTF-16 unit
+	if _, err := doc.Engine.Insert(4, "!"); err != nil {
+		t.Fatal(err)
+	}
+	// A batch, highest offset first, with each range in the content the
+	// edit applied to
+	if err := doc.Engine.ApplyEdits([]engine.Edit{
+		{Range: engine.Range{Start: 9, End: 14}, NewText: "there"},
+		{Range: engine.Range{Start: 0, End: 1}, NewText: ""},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []lsp.TextDocumentContentChangeEvent{
+		{Range: &lsp.Range{Start: lsp.Position{Line: 0, Character: 3}, End: lsp.Position{Line: 0, Character: 3}}, Text: "!"},
+		{Range: &lsp.Range{Start: lsp.Position{Line: 1, Character: 0}, End: lsp.Position{Line: 1, Character: 5}}, Text: "there"},
+		{Range: &lsp.Range{Start: lsp.Position{Line: 0, Character: 0}, End: lsp.Position{Line: 0, Character: 1}}, Text: ""},
+	}
+	got := doc.takeLSPChanges()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("takeLSPChanges() = %+v, want %+v", got, want)
+	}
+	if doc.Content() != "hé!llo\nthere\n" {
+		t.Errorf("Content() = %q", doc.Content())
+	}
+	if got := doc.takeLSPChanges(); len(got) != 0 {
+		t.Errorf("second takeLSPChanges() = %+v, want none", got)
+	}
+}
+
 func TestDocument_Content(t *testing.T) {
 	content := "Hello, World!"
 	doc := NewDocument("/path/to/file.txt", []byte(content))
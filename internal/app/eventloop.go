@@ -2,12 +2,15 @@
 package app
 
 import (
+	"context"
 	"strings"
 
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
 	"github.com/dshills/keystorm/internal/input"
 	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/keymap"
 	"github.com/dshills/keystorm/internal/input/mode"
 	"github.com/dshills/keystorm/internal/renderer/backend"
 )
@@ -71,24 +74,74 @@ func (app *Application) handleKeyEvent(ev backend.Event) error {
 		return nil
 	}
 
-	// Try to handle unmapped key
-	modeCtx := app.buildModeContext()
-	result := currentMode.HandleUnmapped(keyEv, modeCtx)
-	if result == nil {
-		return nil
+	// Keymap lookups use the active buffer's filetype
+	app.syncFiletype()
+
+	// Messages shown by the previous action last until the next key
+	if app.statusLine != nil {
+		app.statusLine.ClearMessage()
 	}
 
-	// Process the result
-	return app.processModeResult(result, keyEv)
-}
+	// Keymap bindings, such as the active filetype's layer, take
+	// precedence over the mode's own handling
+	keys, action := app.lookupKeymap(keyEv, currentMode.Name())
+	if action != nil {
+		return app.dispatchAction(action)
+	}
 
-// handleMouseEvent processes mouse input events.
-func (app *Application) handleMouseEvent(_ backend.Event) error {
-	// Mouse handling will be implemented in a future phase
-	// For now, we just ignore mouse events
+	// Let the mode handle keys no binding matched
+	for _, ev := range keys {
+		result := currentMode.HandleUnmapped(ev, app.buildModeContext())
+		if result == nil {
+			continue
+		}
+		if err := app.processModeResult(result, ev); err != nil {
+			return err
+		}
+		if currentMode = app.modeManager.Current(); currentMode == nil {
+			break
+		}
+	}
 	return nil
 }
 
+// lookupKeymap adds ev to the pending key sequence and looks it up in the
+// keymap registry for modeName. It returns the action of a matching
+// binding, or the keys to hand to the mode once the sequence can no longer
+// match one. While the sequence is a prefix of a binding it returns
+// neither.
+func (app *Application) lookupKeymap(ev key.Event, modeName string) ([]key.Event, *input.Action) {
+	if app.keymaps == nil || app.keySeq == nil {
+		return []key.Event{ev}, nil
+	}
+
+	app.keySeq.Add(ev)
+	ctx := keymap.NewLookupContext()
+	ctx.Mode = modeName
+
+	if binding := app.keymaps.Lookup(app.keySeq, ctx); binding != nil {
+		app.keySeq.Clear()
+		action := &input.Action{Name: binding.Action, Source: input.SourceKeyboard}
+		if len(binding.Args) > 0 {
+			action.Args.Extra = make(map[string]interface{}, len(binding.Args))
+			for k, v := range binding.Args {
+				action.Args.Extra[k] = v
+			}
+		}
+		return nil, action
+	}
+	if app.keymaps.HasPrefix(app.keySeq, ctx) {
+		return nil, nil
+	}
+
+	keys := make([]key.Event, app.keySeq.Len())
+	for i := range keys {
+		keys[i] = *app.keySeq.At(i)
+	}
+	app.keySeq.Clear()
+	return keys, nil
+}
+
 // handlePasteEvent processes paste events.
 func (app *Application) handlePasteEvent(ev backend.Event) error {
 	if ev.PasteText == "" {
@@ -122,11 +175,24 @@ func (app *Application) handlePasteEvent(ev backend.Event) error {
 	return nil
 }
 
-// handleFocusEvent processes focus change events.
-func (app *Application) handleFocusEvent(_ backend.Event) error {
-	// Focus handling will be implemented in a future phase
-	// Could be used to pause/resume certain operations
-	return nil
+// handleFocusEvent routes a terminal focus change through the input
+// handler and dispatches the focus action it produces.
+func (app *Application) handleFocusEvent(ev backend.Event) error {
+	if app.input == nil {
+		return nil
+	}
+
+	app.input.HandleFocusEvent(key.FocusEvent{Gained: ev.Focused})
+	for {
+		select {
+		case action := <-app.input.Actions():
+			if err := app.dispatchAction(&action); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
 }
 
 // convertToKeyEvent converts a backend.Event to a key.Event.
@@ -134,22 +200,25 @@ func (app *Application) convertToKeyEvent(ev backend.Event) key.Event {
 	// Map backend key to key.Key
 	k := mapBackendKey(ev.Key, ev.Rune)
 
-	// Map modifiers
+	return key.NewEvent(k, ev.Rune, mapBackendMods(ev.Mod))
+}
+
+// mapBackendMods maps backend modifiers to key modifiers.
+func mapBackendMods(m backend.ModMask) key.Modifier {
 	mods := key.ModNone
-	if ev.Mod.Has(backend.ModCtrl) {
+	if m.Has(backend.ModCtrl) {
 		mods = mods.With(key.ModCtrl)
 	}
-	if ev.Mod.Has(backend.ModAlt) {
+	if m.Has(backend.ModAlt) {
 		mods = mods.With(key.ModAlt)
 	}
-	if ev.Mod.Has(backend.ModShift) {
+	if m.Has(backend.ModShift) {
 		mods = mods.With(key.ModShift)
 	}
-	if ev.Mod.Has(backend.ModMeta) {
+	if m.Has(backend.ModMeta) {
 		mods = mods.With(key.ModMeta)
 	}
-
-	return key.NewEvent(k, ev.Rune, mods)
+	return mods
 }
 
 // mapBackendKey maps a backend.Key to a key.Key.
@@ -421,6 +490,11 @@ func (app *Application) dispatchAction(action *input.Action) error {
 			if isEditingAction(action.Name) {
 				doc.SetModified(true)
 				doc.IncrementVersion()
+
+				// Sync the edits to LSP off the keystroke path, after the
+				// post-dispatch hooks have run. The document has queued
+				// them with their ranges, so only they are sent.
+				_ = app.PublishBufferChange(context.Background(), TopicBufferContentReplaced, BufferChangePayload{Path: doc.Path})
 			}
 		}
 	}
@@ -453,6 +527,58 @@ func (app *Application) insertCharacter(ch rune) error {
 	return nil
 }
 
+// syncFiletype applies the active buffer's filetype: it sets the keymap
+// registry's active filetype, so the filetype layer of the language
+// applies to key events, and reconfigures auto-indent when the language
+// changed.
+func (app *Application) syncFiletype() {
+	if app.documents == nil {
+		return
+	}
+	doc := app.documents.Active()
+	if doc == nil {
+		return
+	}
+	if !doc.indentConfigured || doc.indentLanguage != doc.LanguageID {
+		app.configureIndent(doc)
+	}
+	if app.keymaps != nil {
+		app.keymaps.SetActiveFiletype(doc.LanguageID)
+	}
+}
+
+// configureIndent sets up auto-indent of doc's engine from the
+// editor.autoIndent, editor.tabSize and editor.insertSpaces settings and
+// the indent rules of its language. "none" disables auto-indent, "keep"
+// keeps the indent of the line above, "brackets" also indents after
+// brackets, and "full" uses the language's rules.
+func (app *Application) configureIndent(doc *Document) {
+	if app.config == nil || doc == nil || doc.Engine == nil {
+		return
+	}
+	doc.indentConfigured = true
+	doc.indentLanguage = doc.LanguageID
+
+	editor := app.config.Editor()
+	if editor.TabSize > 0 {
+		doc.Engine.SetTabWidth(editor.TabSize)
+	}
+	doc.Engine.SetUseTabs(!editor.InsertSpaces)
+
+	switch editor.AutoIndent {
+	case "none":
+		doc.Engine.SetAutoIndent(false)
+		return
+	case "keep":
+		doc.Engine.SetIndentRules(engine.IndentRules{})
+	case "brackets":
+		doc.Engine.SetIndentRules(engine.DefaultIndentRules())
+	default:
+		doc.Engine.SetIndentRules(engine.IndentRulesFor(doc.LanguageID))
+	}
+	doc.Engine.SetAutoIndent(true)
+}
+
 // buildInputContext creates an input.Context for dispatcher.
 func (app *Application) buildInputContext() *input.Context {
 	ctx := &input.Context{}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"errors"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// Focus actions, dispatched when the terminal reports that the editor's
+// window gained or lost focus.
+const (
+	ActionFocusGained = "editor.focusGained"
+	ActionFocusLost   = "editor.focusLost"
+)
+
+// handleFocusGained implements ActionFocusGained. The screen is redrawn,
+// since other programs may have drawn over it while it was unfocused.
+func (app *Application) handleFocusGained(_ input.Action, _ *execctx.ExecutionContext) handler.Result {
+	return handler.Success().WithRedraw()
+}
+
+// handleFocusLost implements ActionFocusLost. When files.autoSave is
+// "onFocusChange" or "onWindowChange", every modified document with a path
+// is saved.
+func (app *Application) handleFocusLost(_ input.Action, _ *execctx.ExecutionContext) handler.Result {
+	if app.config == nil {
+		return handler.NoOp()
+	}
+	switch app.config.Files().AutoSave {
+	case "onFocusChange", "onWindowChange":
+	default:
+		return handler.NoOp()
+	}
+
+	var errs []error
+	for _, doc := range app.documents.DirtyDocuments() {
+		if doc.IsScratch() || doc.ReadOnly {
+			continue
+		}
+		if err := app.saveDocument(doc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return handler.Error(err)
+	}
+	return handler.Success().WithRedraw()
+}
@@ -2,6 +2,7 @@
 package app
 
 import (
+	"github.com/dshills/keystorm/internal/config"
 	"github.com/dshills/keystorm/internal/dispatcher"
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	cursorhandler "github.com/dshills/keystorm/internal/dispatcher/handlers/cursor"
@@ -17,7 +18,9 @@ import (
 
 // RegisterHandlers registers all standard handlers with the dispatcher.
 // This should be called during application bootstrap after the dispatcher is created.
-func RegisterHandlers(d *dispatcher.Dispatcher) {
+// Search case handling is read from cfg's search settings; a nil cfg keeps
+// the handler defaults.
+func RegisterHandlers(d *dispatcher.Dispatcher, cfg *config.Config) {
 	// Combined cursor handler (includes basic movements and word motions)
 	d.RegisterNamespace("cursor", cursorhandler.NewCombinedHandler())
 
@@ -31,18 +34,76 @@ func RegisterHandlers(d *dispatcher.Dispatcher) {
 	d.RegisterNamespace("operator", operatorhandler.NewOperatorHandler())
 
 	// Navigation handlers
-	d.RegisterNamespace("search", searchhandler.NewHandler())
+	d.RegisterNamespace("search", newSearchHandler(cfg))
 	d.RegisterNamespace("view", viewhandler.NewHandler())
 	d.RegisterNamespace("window", windowhandler.NewHandler())
+
+	// Argument schemas of the actions reading Extra args
+	d.RegisterBuiltinSchemas()
 }
 
-// RegisterLSPHandler registers the LSP handler with the dispatcher.
-// This should be called after the LSP client is initialized.
-func RegisterLSPHandler(d *dispatcher.Dispatcher, client *lsp.Client) {
+// newSearchHandler creates the search handler with the configured case
+// handling.
+func newSearchHandler(cfg *config.Config) *searchhandler.Handler {
+	if cfg == nil {
+		return searchhandler.NewHandler()
+	}
+	search := cfg.Search()
+	return searchhandler.NewHandlerWithConfig(search.CaseSensitive, search.SmartCase)
+}
+
+// lspSchemas holds the argument schemas of the LSP actions that read
+// arguments from ActionArgs.Extra.
+var lspSchemas = map[string]dispatcher.ArgSchema{
+	lsp.ActionGotoDefinition: {Args: []dispatcher.ArgSpec{
+		{Name: "offset", Type: dispatcher.ArgInt, Description: "byte offset, defaulting to the cursor"},
+	}},
+	lsp.ActionHover: {Args: []dispatcher.ArgSpec{
+		{Name: "offset", Type: dispatcher.ArgInt, Description: "byte offset, defaulting to the cursor"},
+	}},
+	lsp.ActionCompletion: {Args: []dispatcher.ArgSpec{
+		{Name: "prefix", Type: dispatcher.ArgString, Description: "text typed before the cursor"},
+		{Name: "triggerCharacter", Type: dispatcher.ArgString, Description: "character that triggered completion"},
+	}},
+	lsp.ActionSignatureHelp: {Args: []dispatcher.ArgSpec{
+		{Name: "triggerCharacter", Type: dispatcher.ArgString, Description: "character that triggered signature help"},
+	}},
+	lsp.ActionWorkspaceSymbols: {Args: []dispatcher.ArgSpec{
+		{Name: "query", Type: dispatcher.ArgString, Description: "symbol query"},
+		{Name: "language", Type: dispatcher.ArgString, Description: "language ID, defaulting to the current file's"},
+	}},
+	lsp.ActionCodeAction: {Args: []dispatcher.ArgSpec{
+		{Name: "diagnostics", Description: "diagnostics to fix"},
+	}},
+	lsp.ActionApplyCodeEdit: {Args: []dispatcher.ArgSpec{
+		{Name: "edit", Required: true, Description: "workspace edit to apply"},
+	}},
+	lsp.ActionRename: {Args: []dispatcher.ArgSpec{
+		{Name: "newName", Type: dispatcher.ArgString, Description: "new symbol name"},
+	}},
+	lsp.ActionRestartServer: {Args: []dispatcher.ArgSpec{
+		{Name: "language", Type: dispatcher.ArgString, Description: "language ID, defaulting to the current file's"},
+	}},
+	lsp.ActionServerStatus: {Args: []dispatcher.ArgSpec{
+		{Name: "language", Type: dispatcher.ArgString, Description: "language ID, defaulting to the current file's"},
+	}},
+}
+
+// RegisterLSPHandler registers the LSP handler with the dispatcher and
+// returns it. The handler is also registered as a post-dispatch hook so
+// signature help follows typing. This should be called after the LSP client
+// is initialized.
+func RegisterLSPHandler(d *dispatcher.Dispatcher, client *lsp.Client, opts ...lsp.HandlerOption) *lsp.Handler {
 	if d == nil || client == nil {
-		return
+		return nil
+	}
+	h := lsp.NewHandler(append([]lsp.HandlerOption{lsp.WithLSPClient(client)}, opts...)...)
+	d.RegisterNamespace("lsp", h)
+	for name, schema := range lspSchemas {
+		d.RegisterActionSchema(name, schema)
 	}
-	d.RegisterNamespace("lsp", lsp.NewHandler(lsp.WithLSPClient(client)))
+	d.RegisterPostHook(h)
+	return h
 }
 
 // BuildExecutionContext creates an execctx.ExecutionContext from the application state.
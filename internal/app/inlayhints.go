@@ -0,0 +1,113 @@
+package app
+
+import (
+	"unicode/utf8"
+
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/lsp"
+	"github.com/dshills/keystorm/internal/renderer"
+)
+
+// inlayView is the part of a document whose inlay hints were last
+// requested.
+type inlayView struct {
+	path       string
+	revision   engine.RevisionID
+	start, end uint32
+}
+
+// inlayResult is the inlay hints received for part of a document.
+type inlayResult struct {
+	path  string
+	rng   lsp.Range
+	hints []lsp.InlayHint
+}
+
+// queueInlayHints hands inlay hints from the refresher to the event loop.
+// Hints still waiting to be shown are replaced.
+func (app *Application) queueInlayHints(path string, rng lsp.Range, hints []lsp.InlayHint) {
+	result := inlayResult{path: path, rng: rng, hints: hints}
+	for {
+		select {
+		case app.inlayResults <- result:
+			return
+		default:
+		}
+		select {
+		case <-app.inlayResults:
+		default:
+		}
+	}
+}
+
+// refreshInlayHints requests inlay hints when the visible part of the
+// active document changes, by scrolling, editing or switching documents.
+// It is called every frame; the refresher debounces the requests.
+func (app *Application) refreshInlayHints() {
+	if app.inlayHints == nil || app.renderer == nil {
+		return
+	}
+
+	doc := app.documents.Active()
+	if doc == nil || doc.IsScratch() {
+		if app.inlayView.path != "" {
+			app.inlayView = inlayView{}
+			app.renderer.SetInlayHints(nil)
+		}
+		return
+	}
+
+	lineCount := doc.Engine.LineCount()
+	start, end := app.renderer.Viewport().VisibleLineRange()
+	if lineCount > 0 && end >= lineCount {
+		end = lineCount - 1
+	}
+
+	view := inlayView{path: doc.Path, revision: doc.Engine.RevisionID(), start: start, end: end}
+	if view == app.inlayView {
+		return
+	}
+	if view.path != app.inlayView.path || view.revision != app.inlayView.revision {
+		// Hints from another document or revision are misplaced until new
+		// ones arrive
+		app.renderer.SetInlayHints(nil)
+	}
+	app.inlayView = view
+
+	rng := lsp.Range{
+		Start: lsp.Position{Line: int(start)},
+		End:   lsp.Position{Line: int(end) + 1},
+	}
+	if end+1 >= lineCount {
+		rng.End = lsp.Position{Line: int(end), Character: len(lsp.EncodeUTF16(doc.Engine.LineText(end)))}
+	}
+	app.inlayHints.Update(doc.Path, rng)
+}
+
+// showInlayHints passes inlay hints to the renderer if they are for the
+// text of the active document that was last requested.
+func (app *Application) showInlayHints(result inlayResult) {
+	doc := app.documents.Active()
+	if app.renderer == nil || doc == nil || doc.Path != result.path ||
+		doc.Engine.RevisionID() != app.inlayView.revision {
+		return
+	}
+
+	content := doc.Engine.Text()
+	byLine := make(map[uint32][]renderer.InlayHint)
+	for _, hint := range lsp.InlayHintOffsets(content, result.hints) {
+		line := uint32(hint.Line)
+		lineStart := int(doc.Engine.LineStartOffset(line))
+		if hint.Offset < lineStart || hint.Offset > len(content) {
+			continue
+		}
+
+		// The renderer counts characters; offsets count bytes
+		column := utf8.RuneCountInString(content[lineStart:hint.Offset])
+		byLine[line] = append(byLine[line], renderer.InlayHint{
+			Column: uint32(column),
+			Text:   hint.Text,
+		})
+	}
+	app.renderer.SetInlayHints(byLine)
+}
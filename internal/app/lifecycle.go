@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/dshills/keystorm/internal/engine"
 	"github.com/dshills/keystorm/internal/engine/buffer"
 )
 
@@ -16,7 +17,11 @@ func (app *Application) SaveDocument() error {
 	if doc == nil {
 		return ErrNoActiveDocument
 	}
+	return app.saveDocument(doc)
+}
 
+// saveDocument runs the save hooks of doc and writes it to its path.
+func (app *Application) saveDocument(doc *Document) error {
 	if doc.IsScratch() {
 		return ErrNoFilePath
 	}
@@ -25,6 +30,10 @@ func (app *Application) SaveDocument() error {
 		return ErrReadOnly
 	}
 
+	if err := app.runSaveHooks(doc); err != nil {
+		return &FileError{Op: "save", Path: doc.Path, Err: err}
+	}
+
 	// Get document content
 	content := doc.Content()
 
@@ -46,6 +55,10 @@ func (app *Application) SaveDocumentAs(path string) error {
 		return ErrNoActiveDocument
 	}
 
+	if err := app.runSaveHooks(doc); err != nil {
+		return &FileError{Op: "save", Path: path, Err: err}
+	}
+
 	// Get document content
 	content := doc.Content()
 
@@ -62,6 +75,28 @@ func (app *Application) SaveDocumentAs(path string) error {
 	return nil
 }
 
+// runSaveHooks formats doc before it is written, as enabled by the
+// files.trimTrailingWhitespace, files.insertFinalNewline and
+// files.tabsToSpaces settings and their overrides in the "languages"
+// table. The hooks are re-registered on every save so setting changes
+// apply to the next save; read-only engines are left untouched.
+func (app *Application) runSaveHooks(doc *Document) error {
+	if app.config == nil || doc.Engine.IsReadOnly() {
+		return nil
+	}
+
+	files := app.config.Files()
+	hookConfig := func(path string, enabled bool) engine.SaveHookConfig {
+		return engine.SaveHookConfig{Enabled: enabled, Languages: app.config.LanguageBools(path)}
+	}
+	doc.Engine.AddSaveHook(engine.TrimTrailingWhitespace(hookConfig("files.trimTrailingWhitespace", files.TrimTrailingWhitespace)))
+	doc.Engine.AddSaveHook(engine.TabsToSpaces(hookConfig("files.tabsToSpaces", files.TabsToSpaces)))
+	doc.Engine.AddSaveHook(engine.EnsureFinalNewline(hookConfig("files.insertFinalNewline", files.InsertFinalNewline)))
+
+	_, err := doc.Engine.RunSaveHooks(doc.LanguageID)
+	return err
+}
+
 // CloseDocument closes the specified document.
 // Returns ErrUnsavedChanges if document has unsaved changes and force is false.
 func (app *Application) CloseDocument(doc *Document, force bool) error {
@@ -79,6 +114,9 @@ func (app *Application) CloseDocument(doc *Document, force bool) error {
 		defer cancel()
 		app.lspClient.CloseDocument(ctx, doc.Path)
 	}
+	if app.inlayHints != nil && !doc.IsScratch() {
+		app.inlayHints.Forget(doc.Path)
+	}
 
 	// Remove from document manager
 	var key string
@@ -113,6 +151,7 @@ func (app *Application) OpenFile(path string) (*Document, error) {
 	if err != nil {
 		return nil, &FileError{Op: "open", Path: path, Err: err}
 	}
+	app.configureIndent(doc)
 
 	// Notify LSP if available
 	if app.lspClient != nil {
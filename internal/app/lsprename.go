@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/lsp"
+)
+
+// lspRenameParticipant lets language servers update references, such as
+// import paths, when the project renames a file.
+type lspRenameParticipant struct {
+	client   *lsp.Client
+	resolver lsp.DocumentResolver
+}
+
+// newLSPRenameParticipant creates a rename participant applying server
+// edits to the documents in dm, or to files on disk if not open.
+func newLSPRenameParticipant(client *lsp.Client, dm *DocumentManager) *lspRenameParticipant {
+	return &lspRenameParticipant{
+		client:   client,
+		resolver: &documentEditResolver{documents: dm},
+	}
+}
+
+// WillRename applies the edits the servers request before the rename.
+// Server failures do not block the rename; failing to apply their edits does.
+func (p *lspRenameParticipant) WillRename(ctx context.Context, oldPath, newPath string) error {
+	edit, err := p.client.WillRenameFiles(ctx, fileRenames(oldPath, newPath))
+	if err != nil || (len(edit.Changes) == 0 && len(edit.DocumentChanges) == 0) {
+		return nil
+	}
+	return p.client.ApplyWorkspaceEdit(ctx, edit, p.resolver)
+}
+
+// DidRename notifies the servers of the completed rename.
+func (p *lspRenameParticipant) DidRename(ctx context.Context, oldPath, newPath string) {
+	_ = p.client.DidRenameFiles(ctx, fileRenames(oldPath, newPath))
+}
+
+// fileRenames returns the LSP description of renaming oldPath to newPath.
+func fileRenames(oldPath, newPath string) []lsp.FileRename {
+	return []lsp.FileRename{{
+		OldURI: lsp.FilePathToURI(oldPath),
+		NewURI: lsp.FilePathToURI(newPath),
+	}}
+}
+
+// documentEditResolver resolves workspace edit targets to open documents,
+// falling back to editing files on disk.
+type documentEditResolver struct {
+	documents *DocumentManager
+}
+
+// Document returns the open document for path, or a document that writes
+// its edits back to the file if path is not open.
+func (r *documentEditResolver) Document(path string) (lsp.EditableDocument, error) {
+	if doc, ok := r.documents.Get(path); ok && doc.Engine != nil {
+		return &openDocument{Engine: doc.Engine, doc: doc}, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &diskDocument{
+		Engine: engine.New(engine.WithContent(string(content))),
+		path:   path,
+		mode:   info.Mode().Perm(),
+	}, nil
+}
+
+// DocumentVersion returns the LSP version of path if it is open.
+func (r *documentEditResolver) DocumentVersion(path string) (int, bool) {
+	doc, ok := r.documents.Get(path)
+	if !ok || !doc.IsLSPOpened() {
+		return 0, false
+	}
+	return int(doc.Version()), true
+}
+
+// CreateFile creates an empty file.
+func (r *documentEditResolver) CreateFile(path string, opts lsp.CreateFileOptions) error {
+	if _, err := os.Stat(path); err == nil {
+		if opts.IgnoreIfExists {
+			return nil
+		}
+		if !opts.Overwrite {
+			return os.ErrExist
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, nil, 0644)
+}
+
+// RenameFile renames a file.
+func (r *documentEditResolver) RenameFile(oldPath, newPath string, opts lsp.RenameFileOptions) error {
+	if _, err := os.Stat(newPath); err == nil {
+		if opts.IgnoreIfExists {
+			return nil
+		}
+		if !opts.Overwrite {
+			return os.ErrExist
+		}
+	}
+	return os.Rename(oldPath, newPath)
+}
+
+// DeleteFile deletes a file or directory.
+func (r *documentEditResolver) DeleteFile(path string, opts lsp.DeleteFileOptions) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) && opts.IgnoreIfNotExists {
+		return nil
+	}
+	if opts.Recursive {
+		return os.RemoveAll(path)
+	}
+	return os.Remove(path)
+}
+
+// openDocument is a document open in the editor. It is marked modified
+// once an edit has been applied.
+type openDocument struct {
+	*engine.Engine
+	doc *Document
+}
+
+// Replace replaces [start, end) with text and marks the document modified.
+func (d *openDocument) Replace(start, end buffer.ByteOffset, text string) (buffer.ByteOffset, error) {
+	offset, err := d.Engine.Replace(start, end, text)
+	if err != nil {
+		return offset, err
+	}
+	d.doc.SetModified(true)
+	return offset, nil
+}
+
+// diskDocument is a file that is not open in the editor. The edits of a
+// text document edit are written back to the file together by Save, and
+// undos immediately.
+type diskDocument struct {
+	*engine.Engine
+	path string
+	mode os.FileMode
+}
+
+// Undo reverts the last edit and saves the file.
+func (d *diskDocument) Undo() error {
+	if err := d.Engine.Undo(); err != nil {
+		return err
+	}
+	return d.Save()
+}
+
+// Save writes the document content to its file, keeping the file's mode.
+func (d *diskDocument) Save() error {
+	return os.WriteFile(d.path, []byte(d.Engine.Text()), d.mode)
+}
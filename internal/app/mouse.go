@@ -0,0 +1,204 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/input"
+	"github.com/dshills/keystorm/internal/input/mouse"
+	"github.com/dshills/keystorm/internal/renderer/backend"
+)
+
+// ActionOpenLink opens the link in its "url" argument: file URLs in the
+// editor and other URLs in the system's default application.
+const ActionOpenLink = "editor.openLink"
+
+// openURL opens a URL in the system's default application.
+var openURL = func(rawURL string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", rawURL)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", rawURL)
+	default:
+		cmd = exec.Command("xdg-open", rawURL)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// handleOpenLink implements ActionOpenLink.
+func (app *Application) handleOpenLink(action input.Action, _ *execctx.ExecutionContext) handler.Result {
+	link := action.Args.GetString("url")
+	u, err := url.Parse(link)
+	if link == "" || err != nil {
+		return handler.Errorf("openLink: invalid url %q", link)
+	}
+
+	if u.Scheme == "file" {
+		doc, err := app.OpenFile(u.Path)
+		if err != nil {
+			return handler.Error(err)
+		}
+		app.SwitchDocument(doc)
+		return handler.Success().WithRedraw()
+	}
+
+	if err := openURL(link); err != nil {
+		return handler.Error(fmt.Errorf("openLink: %w", err))
+	}
+	return handler.Success().WithMessage("opened " + link)
+}
+
+// handleMouseEvent processes mouse input events. Moving the pointer over a
+// word or link shows its hover after the mouse handler's hover delay, and
+// Ctrl/Cmd+click goes to the definition of a word or opens a link.
+func (app *Application) handleMouseEvent(ev backend.Event) error {
+	if app.mouse == nil {
+		return nil
+	}
+
+	event := app.convertMouseEvent(ev)
+	ctx := app.mouseContext()
+
+	// Movement starts the hover delay; anything else cancels it
+	app.mouse.ScheduleHover(event, ctx)
+
+	if event.Action != mouse.ActionPress {
+		return nil
+	}
+	target := app.mouse.ResolveTarget(event, ctx)
+	if target.Kind != mouse.TargetDefinition && target.Kind != mouse.TargetLink {
+		return nil
+	}
+	return app.dispatchAction(target.Action())
+}
+
+// queueHover hands a hover target from the mouse handler's timer to the
+// event loop. A hover still waiting to be shown is replaced.
+func (app *Application) queueHover(target mouse.Target) {
+	for {
+		select {
+		case app.hovers <- target:
+			return
+		default:
+		}
+		select {
+		case <-app.hovers:
+		default:
+		}
+	}
+}
+
+// convertMouseEvent converts a backend.Event to a mouse.Event. The backend
+// reports which buttons are down, so presses, drags and releases are told
+// apart by the buttons of the previous event.
+func (app *Application) convertMouseEvent(ev backend.Event) mouse.Event {
+	event := mouse.Event{
+		Position:  mouse.Position{X: ev.MouseX, Y: ev.MouseY},
+		Button:    mapMouseButton(ev.MouseButton),
+		Modifiers: mapBackendMods(ev.Mod),
+		Timestamp: time.Now(),
+	}
+
+	if event.Button.IsScroll() {
+		event.Action = mouse.ActionPress
+		return event
+	}
+
+	prev := app.mouseButton
+	app.mouseButton = ev.MouseButton
+	switch {
+	case ev.MouseButton != backend.MouseNone && ev.MouseButton != prev:
+		event.Action = mouse.ActionPress
+	case ev.MouseButton != backend.MouseNone:
+		event.Action = mouse.ActionDrag
+	case prev != backend.MouseNone:
+		event.Action = mouse.ActionRelease
+		event.Button = mapMouseButton(prev)
+	default:
+		event.Action = mouse.ActionMove
+	}
+	return event
+}
+
+// mapMouseButton maps a backend.MouseButton to a mouse.Button.
+func mapMouseButton(b backend.MouseButton) mouse.Button {
+	switch b {
+	case backend.MouseLeft:
+		return mouse.ButtonLeft
+	case backend.MouseMiddle:
+		return mouse.ButtonMiddle
+	case backend.MouseRight:
+		return mouse.ButtonRight
+	case backend.MouseWheelUp:
+		return mouse.ButtonScrollUp
+	case backend.MouseWheelDown:
+		return mouse.ButtonScrollDown
+	case backend.MouseWheelLeft:
+		return mouse.ButtonScrollLeft
+	case backend.MouseWheelRight:
+		return mouse.ButtonScrollRight
+	default:
+		return mouse.ButtonNone
+	}
+}
+
+// mouseContext returns the context mapping the screen to the active
+// document, or nil without a renderer or document.
+func (app *Application) mouseContext() mouse.Context {
+	doc := app.documents.Active()
+	if app.renderer == nil || doc == nil || doc.Engine == nil {
+		return nil
+	}
+	return &documentMouseContext{screen: app.renderer, eng: doc.Engine}
+}
+
+// documentMouseContext implements mouse.Context for a document shown by
+// the renderer.
+type documentMouseContext struct {
+	screen interface {
+		ScreenToBuffer(screenX, screenY int) (line, col uint32, ok bool)
+	}
+	eng *engine.Engine
+}
+
+// OffsetAt returns the offset of the character under pos.
+func (c *documentMouseContext) OffsetAt(pos mouse.Position) (int, bool) {
+	line, col, ok := c.screen.ScreenToBuffer(pos.X, pos.Y)
+	if !ok {
+		return 0, false
+	}
+
+	// The renderer counts characters; offsets count bytes
+	text := c.eng.LineText(line)
+	index := 0
+	for i := uint32(0); i < col; i++ {
+		if index >= len(text) {
+			return 0, false
+		}
+		_, size := utf8.DecodeRuneInString(text[index:])
+		index += size
+	}
+	if index >= len(text) {
+		return 0, false
+	}
+	return int(c.eng.LineStartOffset(line)) + index, true
+}
+
+// LineAt returns the line containing offset and the offset it starts at.
+func (c *documentMouseContext) LineAt(offset int) (string, int) {
+	line := c.eng.OffsetToPoint(engine.ByteOffset(offset)).Line
+	return c.eng.LineText(line), int(c.eng.LineStartOffset(line))
+}
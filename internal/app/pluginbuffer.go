@@ -0,0 +1,177 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/plugin/api"
+)
+
+// pluginBuffer implements api.BufferProvider and api.BatchBufferProvider
+// for plugins over the active document. Without an active document it is
+// empty and edits fail with ErrNoActiveDocument.
+type pluginBuffer struct {
+	app *Application
+}
+
+// active returns the active document, or nil.
+func (b *pluginBuffer) active() *Document {
+	if b.app.documents == nil {
+		return nil
+	}
+	doc := b.app.documents.Active()
+	if doc == nil || doc.Engine == nil {
+		return nil
+	}
+	return doc
+}
+
+// edited marks doc modified after a successful edit.
+func (b *pluginBuffer) edited(doc *Document, err error) error {
+	if err == nil {
+		doc.SetModified(true)
+	}
+	return err
+}
+
+// Text returns the full buffer text.
+func (b *pluginBuffer) Text() string {
+	if doc := b.active(); doc != nil {
+		return doc.Engine.Text()
+	}
+	return ""
+}
+
+// TextRange returns text in the given byte range.
+func (b *pluginBuffer) TextRange(start, end int) (string, error) {
+	doc := b.active()
+	if doc == nil {
+		return "", ErrNoActiveDocument
+	}
+	if start < 0 || end < start || end > int(doc.Engine.Len()) {
+		return "", fmt.Errorf("%w: range [%d, %d)", ErrInvalidOperation, start, end)
+	}
+	return doc.Engine.TextRange(engine.ByteOffset(start), engine.ByteOffset(end)), nil
+}
+
+// Line returns the text of a line (1-indexed).
+func (b *pluginBuffer) Line(lineNum int) (string, error) {
+	doc := b.active()
+	if doc == nil {
+		return "", ErrNoActiveDocument
+	}
+	if lineNum < 1 || lineNum > int(doc.Engine.LineCount()) {
+		return "", fmt.Errorf("%w: line %d", ErrInvalidOperation, lineNum)
+	}
+	return doc.Engine.LineText(uint32(lineNum - 1)), nil
+}
+
+// LineCount returns the total number of lines.
+func (b *pluginBuffer) LineCount() int {
+	if doc := b.active(); doc != nil {
+		return int(doc.Engine.LineCount())
+	}
+	return 0
+}
+
+// Len returns the buffer length in bytes.
+func (b *pluginBuffer) Len() int {
+	if doc := b.active(); doc != nil {
+		return int(doc.Engine.Len())
+	}
+	return 0
+}
+
+// Insert inserts text at the given byte offset.
+func (b *pluginBuffer) Insert(offset int, text string) (int, error) {
+	doc := b.active()
+	if doc == nil {
+		return 0, ErrNoActiveDocument
+	}
+	end, err := doc.Engine.Insert(engine.ByteOffset(offset), text)
+	return int(end), b.edited(doc, err)
+}
+
+// Delete deletes text in the given byte range.
+func (b *pluginBuffer) Delete(start, end int) error {
+	doc := b.active()
+	if doc == nil {
+		return ErrNoActiveDocument
+	}
+	return b.edited(doc, doc.Engine.Delete(engine.ByteOffset(start), engine.ByteOffset(end)))
+}
+
+// Replace replaces text in the given byte range.
+func (b *pluginBuffer) Replace(start, end int, text string) (int, error) {
+	doc := b.active()
+	if doc == nil {
+		return 0, ErrNoActiveDocument
+	}
+	newEnd, err := doc.Engine.Replace(engine.ByteOffset(start), engine.ByteOffset(end), text)
+	return int(newEnd), b.edited(doc, err)
+}
+
+// Batch collects edits from fn and applies them to the active document as
+// a single undo step named name, using Engine.WithBatch.
+func (b *pluginBuffer) Batch(name string, fn func(batch api.BufferBatch) error) error {
+	doc := b.active()
+	if doc == nil {
+		return ErrNoActiveDocument
+	}
+	before := doc.Engine.RevisionID()
+	err := doc.Engine.WithBatch(name, func(eb *engine.EditBatch) error {
+		return fn(editBatch{eb})
+	})
+	if err == nil && doc.Engine.RevisionID() != before {
+		doc.SetModified(true)
+	}
+	return err
+}
+
+// Undo undoes the last change.
+func (b *pluginBuffer) Undo() bool {
+	doc := b.active()
+	return doc != nil && b.edited(doc, doc.Engine.Undo()) == nil
+}
+
+// Redo redoes the last undone change.
+func (b *pluginBuffer) Redo() bool {
+	doc := b.active()
+	return doc != nil && b.edited(doc, doc.Engine.Redo()) == nil
+}
+
+// Path returns the file path of the buffer.
+func (b *pluginBuffer) Path() string {
+	if doc := b.active(); doc != nil {
+		return doc.Path
+	}
+	return ""
+}
+
+// Modified returns true if the buffer has unsaved changes.
+func (b *pluginBuffer) Modified() bool {
+	if doc := b.active(); doc != nil {
+		return doc.IsModified()
+	}
+	return false
+}
+
+// editBatch adapts an engine.EditBatch to api.BufferBatch.
+type editBatch struct {
+	b *engine.EditBatch
+}
+
+// Insert inserts text at the given byte offset.
+func (e editBatch) Insert(offset int, text string) {
+	e.b.Insert(engine.ByteOffset(offset), text)
+}
+
+// Delete deletes text in the given byte range.
+func (e editBatch) Delete(start, end int) {
+	e.b.Delete(engine.ByteOffset(start), engine.ByteOffset(end))
+}
+
+// Replace replaces text in the given byte range.
+func (e editBatch) Replace(start, end int, text string) {
+	e.b.Replace(engine.ByteOffset(start), engine.ByteOffset(end), text)
+}
@@ -0,0 +1,19 @@
+package app
+
+import (
+	"github.com/dshills/keystorm/internal/plugin"
+)
+
+// newPluginSystem creates the plugin system the application loads plugins
+// through. Unlike a bare plugin.Manager, the system injects the ks.* API
+// modules into each plugin, backed by the application's providers.
+func newPluginSystem(app *Application) (*plugin.System, error) {
+	config := plugin.DefaultSystemConfig()
+	config.BufferProvider = &pluginBuffer{app: app}
+
+	system := plugin.NewSystem(config)
+	if err := system.Initialize(); err != nil {
+		return nil, err
+	}
+	return system, nil
+}
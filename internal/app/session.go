@@ -0,0 +1,154 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/engine/cursor"
+	"github.com/dshills/keystorm/internal/project"
+)
+
+// SaveSession records the cursors, folds and scroll position of every open
+// file-backed document and writes the session to the workspace's
+// .keystorm/session.json. It is a no-op without an open project.
+func (app *Application) SaveSession(_ context.Context) error {
+	proj := app.project
+	if proj == nil || !proj.IsOpen() {
+		return nil
+	}
+
+	active := app.documents.Active()
+	states := make(map[string]project.DocumentState)
+	for _, doc := range app.documents.All() {
+		if doc.IsScratch() {
+			continue
+		}
+		state := documentState(doc)
+		if doc == active && app.renderer != nil {
+			state.ScrollLine = app.renderer.Viewport().TopLine()
+		} else if prev, ok := proj.DocumentState(doc.Path); ok {
+			state.ScrollLine = prev.ScrollLine
+		}
+		states[doc.Path] = state
+	}
+
+	// Documents closed since the last save drop out of the session
+	proj.ClearDocumentStates()
+	for path, state := range states {
+		proj.SetDocumentState(path, state)
+	}
+	proj.SetActiveFile("")
+	if active != nil && !active.IsScratch() {
+		proj.SetActiveFile(active.Path)
+	}
+
+	var buf bytes.Buffer
+	if err := proj.SaveSession(&buf); err != nil {
+		return err
+	}
+	path := project.SessionPath(proj.Root())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return &FileError{Op: "save session", Path: path, Err: err}
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return &FileError{Op: "save session", Path: path, Err: err}
+	}
+	return nil
+}
+
+// RestoreSession reopens the files recorded in the workspace's session file
+// and restores their cursors, folds and scroll position, and the active
+// file. Files that no longer exist are skipped with a warning. It is a no-op
+// without an open project or a session file.
+func (app *Application) RestoreSession(ctx context.Context) error {
+	proj := app.project
+	if proj == nil || !proj.IsOpen() {
+		return nil
+	}
+
+	path := project.SessionPath(proj.Root())
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return &FileError{Op: "restore session", Path: path, Err: err}
+	}
+	defer f.Close()
+
+	if err := proj.RestoreSession(ctx, f); err != nil {
+		return &FileError{Op: "restore session", Path: path, Err: err}
+	}
+	for _, warning := range proj.SessionWarnings() {
+		app.LogWarn("session: skipping file", "error", warning)
+	}
+
+	activePath := proj.ActiveFile()
+	var active *Document
+	for _, path := range proj.SessionFiles() {
+		state, ok := proj.DocumentState(path)
+		if !ok {
+			continue
+		}
+		doc, err := app.OpenFile(path)
+		if err != nil {
+			app.LogWarn("session: skipping file", "path", path, "error", err)
+			continue
+		}
+		applyDocumentState(doc, state)
+		if path == activePath {
+			active = doc
+		}
+	}
+
+	if active != nil {
+		app.documents.SetActive(active)
+		if app.renderer != nil {
+			state, _ := proj.DocumentState(active.Path)
+			app.renderer.Viewport().ScrollTo(state.ScrollLine, false)
+		}
+	}
+	return nil
+}
+
+// documentState captures the session state of a document's engine.
+func documentState(doc *Document) project.DocumentState {
+	var state project.DocumentState
+	for _, sel := range doc.Engine.Cursors().All() {
+		state.Cursors = append(state.Cursors, int(sel.Head))
+	}
+	for _, fold := range doc.Engine.Folds() {
+		state.Folds = append(state.Folds, project.FoldState{
+			StartLine: fold.StartLine,
+			EndLine:   fold.EndLine,
+		})
+	}
+	return state
+}
+
+// applyDocumentState restores session state into a document's engine.
+// Cursor offsets beyond the end of the buffer are clamped.
+func applyDocumentState(doc *Document, state project.DocumentState) {
+	if len(state.Cursors) > 0 {
+		length := doc.Engine.Len()
+		sels := make([]cursor.Selection, 0, len(state.Cursors))
+		for _, offset := range state.Cursors {
+			pos := engine.ByteOffset(offset)
+			if pos > length {
+				pos = length
+			}
+			if pos < 0 {
+				pos = 0
+			}
+			sels = append(sels, cursor.NewCursorSelection(pos))
+		}
+		doc.Engine.SetCursors(cursor.NewCursorSetFromSlice(sels))
+	}
+	for _, fold := range state.Folds {
+		doc.Engine.AddFold(int(fold.StartLine), int(fold.EndLine))
+	}
+}
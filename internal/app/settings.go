@@ -0,0 +1,96 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// Settings actions, dispatched by the :set command. Both write runtime
+// overrides, so a setting changed from inside the editor lasts for the
+// session and is never written to settings.toml.
+const (
+	ActionSettingsSet    = "settings.set"
+	ActionSettingsToggle = "settings.toggle"
+)
+
+// handleSettingsSet implements ActionSettingsSet. A string value is
+// converted to the type of the setting's current value.
+func (app *Application) handleSettingsSet(action input.Action, _ *execctx.ExecutionContext) handler.Result {
+	if app.config == nil {
+		return handler.NoOp()
+	}
+	path := action.Args.GetString("path")
+	if path == "" {
+		return handler.Errorf("settings.set: missing setting path")
+	}
+	value, _ := action.Args.Get("value")
+	if s, ok := value.(string); ok {
+		current, _ := app.config.Get(path)
+		value = parseSettingValue(current, s)
+	}
+	if err := app.config.SetRuntime(path, value); err != nil {
+		return handler.Error(err)
+	}
+	return handler.Success().WithMessage(fmt.Sprintf("%s=%v", path, value)).WithRedraw()
+}
+
+// handleSettingsToggle implements ActionSettingsToggle. A setting without
+// a value is treated as false, so toggling it enables it.
+func (app *Application) handleSettingsToggle(action input.Action, _ *execctx.ExecutionContext) handler.Result {
+	if app.config == nil {
+		return handler.NoOp()
+	}
+	path := action.Args.GetString("path")
+	if path == "" {
+		return handler.Errorf("settings.toggle: missing setting path")
+	}
+	var enabled bool
+	if current, ok := app.config.Get(path); ok {
+		b, isBool := current.(bool)
+		if !isBool {
+			return handler.Errorf("settings.toggle: %s is not a boolean setting", path)
+		}
+		enabled = b
+	}
+	if err := app.config.SetRuntime(path, !enabled); err != nil {
+		return handler.Error(err)
+	}
+	return handler.Success().WithMessage(fmt.Sprintf("%s=%v", path, !enabled)).WithRedraw()
+}
+
+// parseSettingValue converts s to the type of current. When the setting
+// has no value yet, booleans and numbers are recognised and anything else
+// stays a string.
+func parseSettingValue(current any, s string) any {
+	switch current.(type) {
+	case string:
+		return s
+	case bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case int, int64:
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+	case float64:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	default:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
@@ -20,11 +20,12 @@ const (
 	TopicBufferContentChanged  topic.Topic = "buffer.content.*"
 
 	// Config events
-	TopicConfigChanged        topic.Topic = "config.changed"
-	TopicConfigChangedUI      topic.Topic = "config.changed.ui"
-	TopicConfigChangedUITheme topic.Topic = "config.changed.ui.theme"
-	TopicConfigChangedKeymaps topic.Topic = "config.changed.keymaps"
-	TopicConfigChangedAll     topic.Topic = "config.changed.*"
+	TopicConfigChanged               topic.Topic = "config.changed"
+	TopicConfigChangedUI             topic.Topic = "config.changed.ui"
+	TopicConfigChangedUITheme        topic.Topic = "config.changed.ui.theme"
+	TopicConfigChangedUICustomThemes topic.Topic = "config.changed.ui.customThemes"
+	TopicConfigChangedKeymaps        topic.Topic = "config.changed.keymaps"
+	TopicConfigChangedAll            topic.Topic = "config.changed.*"
 
 	// Mode events
 	TopicModeChanged topic.Topic = "mode.changed"
@@ -39,6 +40,7 @@ const (
 	TopicLSPDiagnostics topic.Topic = "lsp.diagnostics"
 	TopicLSPCompletion  topic.Topic = "lsp.completion"
 	TopicLSPHover       topic.Topic = "lsp.hover"
+	TopicLSPSignature   topic.Topic = "lsp.signatureHelp"
 	TopicLSPAll         topic.Topic = "lsp.*"
 
 	// Document events
@@ -226,75 +228,52 @@ func (sm *subscriptionManager) handleBufferChangeForRenderer(_ context.Context,
 	return nil
 }
 
-// handleBufferChangeForLSP syncs document changes with LSP.
+// handleBufferChangeForLSP syncs document changes with LSP. The document
+// queues its edits as they are made, and the handler sends the queued
+// edits as incremental changes. Sending is serialized per document, so
+// the edits reach the server in order even though events are delivered
+// by several workers.
 func (sm *subscriptionManager) handleBufferChangeForLSP(ctx context.Context, ev any) error {
 	if sm.app.lspClient == nil {
 		return nil
 	}
 
 	doc := sm.app.documents.Active()
+	if payload, ok := ev.(event.Event[BufferChangePayload]); ok && payload.Payload.Path != "" {
+		doc, _ = sm.app.documents.Get(payload.Payload.Path)
+	}
 	if doc == nil || !doc.IsLSPOpened() {
 		return nil
 	}
 
-	// Mark document as modified
-	doc.SetModified(true)
-	doc.IncrementVersion()
-
-	// Try to extract incremental changes from event payload
-	// If we have a BufferChangePayload, we can send incremental changes
-	if payload, ok := ev.(event.Event[BufferChangePayload]); ok {
-		change := payload.Payload
-		if change.Path == doc.Path {
-			// Build LSP content change event with range information
-			lspChange := lsp.TextDocumentContentChangeEvent{
-				Range: &lsp.Range{
-					Start: sm.offsetToPosition(doc, change.StartOffset),
-					End:   sm.offsetToPosition(doc, change.EndOffset),
-				},
-				Text: change.Text,
-			}
-
-			// Use a short timeout for LSP notifications
-			lspCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
-			defer cancel()
-
-			// Send incremental change to LSP
-			if err := sm.app.lspClient.ChangeDocument(lspCtx, doc.Path, []lsp.TextDocumentContentChangeEvent{lspChange}); err != nil {
-				// Non-fatal, just log and continue
-				_ = err
-			}
-			return nil
-		}
-	}
+	doc.lspSync.Lock()
+	defer doc.lspSync.Unlock()
 
-	// Fall back to full document sync for events without detailed change info
-	fullChange := lsp.TextDocumentContentChangeEvent{
-		Text: doc.Content(),
+	changes := doc.takeLSPChanges()
+	if len(changes) == 0 {
+		return nil
 	}
 
+	// Use a short timeout for LSP notifications
 	lspCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	if err := sm.app.lspClient.ChangeDocument(lspCtx, doc.Path, []lsp.TextDocumentContentChangeEvent{fullChange}); err != nil {
+	if err := sm.app.lspClient.ChangeDocument(lspCtx, doc.Path, changes); err != nil {
 		// Non-fatal, just log and continue
 		_ = err
+		return nil
 	}
+	sm.documentSynced(doc.Path)
 
 	return nil
 }
 
-// offsetToPosition converts a byte offset to an LSP Position.
-func (sm *subscriptionManager) offsetToPosition(doc *Document, offset int) lsp.Position {
-	if doc == nil || doc.Engine == nil {
-		return lsp.Position{}
-	}
-
-	// Use the engine to convert byte offset to line/column
-	point := doc.Engine.OffsetToPoint(int64(offset))
-	return lsp.Position{
-		Line:      int(point.Line),
-		Character: int(point.Column),
+// documentSynced tells the LSP handler that the server has the latest text
+// for path, so signature help for characters typed before the change can
+// be requested without racing it.
+func (sm *subscriptionManager) documentSynced(path string) {
+	if sm.app.lspHandler != nil {
+		sm.app.lspHandler.DocumentSynced(path)
 	}
 }
 
@@ -307,9 +286,9 @@ func (sm *subscriptionManager) handleConfigChange(_ context.Context, ev any) err
 	}
 
 	// Handle theme changes
-	if envelope.Topic.HasPrefix(TopicConfigChangedUITheme) {
-		// Theme reload would be triggered here
-		// Currently renderer handles this internally
+	if envelope.Topic.HasPrefix(TopicConfigChangedUITheme) ||
+		envelope.Topic.HasPrefix(TopicConfigChangedUICustomThemes) {
+		sm.app.applyTheme()
 	}
 
 	// Handle keymap changes
@@ -405,6 +384,15 @@ type DiagnosticsPayload struct {
 	Diagnostics any
 }
 
+// SignatureHelpPayload contains data for LSP signature help events.
+type SignatureHelpPayload struct {
+	// Path is the document path.
+	Path string
+
+	// Help is the active signature help, or nil when it has closed.
+	Help *lsp.SignatureHelpResult
+}
+
 // PublishBufferChange publishes a buffer change event.
 func (app *Application) PublishBufferChange(ctx context.Context, topicName topic.Topic, payload BufferChangePayload) error {
 	if app.eventBus == nil {
@@ -427,6 +415,20 @@ func (app *Application) PublishModeChange(ctx context.Context, previous, current
 	return app.eventBus.PublishSync(ctx, ev)
 }
 
+// publishSignatureHelp publishes signature help updated while typing.
+// It is the LSP handler's signature help callback.
+func (app *Application) publishSignatureHelp(path string, help *lsp.SignatureHelpResult) {
+	if app.eventBus == nil {
+		return
+	}
+	payload := SignatureHelpPayload{
+		Path: path,
+		Help: help,
+	}
+	ev := event.NewEvent(TopicLSPSignature, payload, "app")
+	_ = app.eventBus.Publish(context.Background(), ev)
+}
+
 // PublishFileEvent publishes a file event.
 func (app *Application) PublishFileEvent(ctx context.Context, topicName topic.Topic, path string) error {
 	if app.eventBus == nil {
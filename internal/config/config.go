@@ -10,6 +10,7 @@ import (
 	"github.com/dshills/keystorm/internal/config/layer"
 	"github.com/dshills/keystorm/internal/config/loader"
 	"github.com/dshills/keystorm/internal/config/notify"
+	"github.com/dshills/keystorm/internal/config/registry"
 	"github.com/dshills/keystorm/internal/config/schema"
 	"github.com/dshills/keystorm/internal/config/watcher"
 )
@@ -25,6 +26,9 @@ type Config struct {
 	// Schema validator
 	validator *schema.Validator
 
+	// Settings registry (descriptions, defaults, sensitivity)
+	settings *registry.Registry
+
 	// File watcher for live reload
 	watcher *watcher.Watcher
 
@@ -45,9 +49,23 @@ type Config struct {
 	enableWatcher bool
 	enableSchema  bool
 
+	// Variable interpolation in string settings
+	enableInterpolation bool
+	strictInterpolation bool
+	variables           map[string]string
+	varResolver         layer.Resolver
+
 	// configErrors stores errors encountered during configuration access.
 	// This allows detection of type mismatches and other config problems.
 	configErrors map[string]error
+
+	// validators are cross-field rules run by Validate
+	validators []Validator
+
+	// profiles are the named overrides defined with DefineProfile, and
+	// activeProfile the one applied in the profile layer
+	profiles      map[string]map[string]any
+	activeProfile string
 }
 
 // Option configures a Config instance.
@@ -81,13 +99,52 @@ func WithSchemaValidation(enable bool) Option {
 	}
 }
 
+// WithInterpolation enables expansion of ${VAR} and $VAR references in
+// string settings (default: true). "$$" is an escaped dollar sign.
+func WithInterpolation(enable bool) Option {
+	return func(c *Config) {
+		c.enableInterpolation = enable
+	}
+}
+
+// WithStrictInterpolation makes references to unknown variables errors,
+// reported by InterpolationError. By default they are left as written.
+func WithStrictInterpolation(strict bool) Option {
+	return func(c *Config) {
+		c.strictInterpolation = strict
+	}
+}
+
+// WithVariables sets editor-provided variables for interpolation. They
+// take precedence over environment variables.
+func WithVariables(vars map[string]string) Option {
+	return func(c *Config) {
+		for name, value := range vars {
+			c.variables[name] = value
+		}
+	}
+}
+
+// WithVariableResolver replaces the environment as the fallback resolver
+// for variables not set with WithVariables or SetVariable.
+func WithVariableResolver(resolver layer.Resolver) Option {
+	return func(c *Config) {
+		c.varResolver = resolver
+	}
+}
+
 // New creates a new Config instance with the given options.
 func New(opts ...Option) *Config {
 	c := &Config{
 		layers:        layer.NewManager(),
 		notifier:      notify.New(),
+		settings:      registry.NewWithDefaults(),
 		enableWatcher: true,
 		enableSchema:  true,
+
+		enableInterpolation: true,
+		variables:           make(map[string]string),
+		varResolver:         layer.EnvResolver(),
 	}
 
 	for _, opt := range opts {
@@ -99,11 +156,21 @@ func New(opts ...Option) *Config {
 		c.userConfigDir = defaultUserConfigDir()
 	}
 
-	// Initialize schema validator
-	if c.enableSchema {
-		if s, err := schema.LoadEmbedded(); err == nil {
+	// Initialize variable interpolation
+	if _, ok := c.variables["WORKSPACE"]; !ok && c.projectConfigDir != "" {
+		c.variables["WORKSPACE"] = c.projectConfigDir
+	}
+	c.applyInterpolation()
+
+	// Initialize schema validator and array merge strategies
+	if s, err := schema.LoadEmbedded(); err == nil {
+		if c.enableSchema {
 			c.validator = schema.NewValidator(s)
 		}
+		for path, err := range c.registerMergeStrategies("", s) {
+			c.recordConfigError(path, err)
+		}
+		c.registerSensitivePaths(s)
 	}
 
 	// Initialize file watcher
@@ -162,6 +229,12 @@ func (c *Config) Load(_ context.Context) error {
 		return err
 	}
 
+	// Restore the active profile
+	if err := c.loadActiveProfile(); err != nil && !os.IsNotExist(err) {
+		c.mu.Unlock()
+		return err
+	}
+
 	// Release lock before starting watcher to avoid deadlock
 	// (watcher callbacks acquire the same lock)
 	w := c.watcher
@@ -209,12 +282,17 @@ func (c *Config) Close() {
 }
 
 // Get returns the value at the given path from the merged configuration.
+// Sensitive values are redacted; use GetSecret to read them.
 func (c *Config) Get(path string) (any, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	merged := c.layers.Merge()
-	return getPath(merged, path)
+	v, ok := getPath(merged, path)
+	if !ok {
+		return nil, false
+	}
+	return c.redact(path, v), true
 }
 
 // GetString returns a string value at the given path.
@@ -344,7 +422,7 @@ func (c *Config) Set(path string, value any) error {
 	newValue, _ := getPath(newMerged, path)
 
 	// Notify observers with effective merged values
-	c.notifier.NotifySet(path, oldValue, newValue, "user")
+	c.notifier.NotifySet(path, c.redact(path, oldValue), c.redact(path, newValue), "user")
 
 	return nil
 }
@@ -359,11 +437,105 @@ func (c *Config) SubscribePath(path string, observer notify.Observer) *notify.Su
 	return c.notifier.SubscribePath(path, observer)
 }
 
-// Merged returns the fully merged configuration.
+// Merged returns the fully merged configuration with sensitive values redacted.
 func (c *Config) Merged() map[string]any {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.layers.Merge()
+	merged := c.layers.Merge()
+	c.redact("", merged)
+	return merged
+}
+
+// SetVariable sets an editor-provided interpolation variable, such as
+// WORKSPACE, and re-expands settings that reference it.
+func (c *Config) SetVariable(name, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.variables[name] = value
+	c.applyInterpolation()
+}
+
+// Variable returns an editor-provided interpolation variable.
+func (c *Config) Variable(name string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	v, ok := c.variables[name]
+	return v, ok
+}
+
+// InterpolationError returns the errors from expanding variables in
+// string settings, or nil. Errors are reported only with
+// WithStrictInterpolation; each wraps layer.ErrUnresolvedVariable.
+func (c *Config) InterpolationError() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.layers.InterpolationError()
+}
+
+// applyInterpolation configures variable interpolation on the layer
+// manager with a snapshot of the editor variables.
+func (c *Config) applyInterpolation() {
+	if !c.enableInterpolation {
+		c.layers.SetInterpolation(nil)
+		return
+	}
+
+	vars := make(map[string]string, len(c.variables))
+	for name, value := range c.variables {
+		vars[name] = value
+	}
+	c.layers.SetInterpolation(&layer.Interpolation{
+		Resolver: layer.ChainResolvers(layer.MapResolver(vars), c.varResolver),
+		Strict:   c.strictInterpolation,
+	})
+}
+
+// SetMergeStrategy sets how array values at path are merged across layers,
+// e.g. layer.MergeAppend so a project's "files.exclude" extends the user's
+// list instead of replacing it.
+func (c *Config) SetMergeStrategy(path string, strategy layer.MergeStrategy) error {
+	if !strategy.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidMergeStrategy, strategy)
+	}
+	c.layers.SetMergeStrategy(path, strategy)
+	return nil
+}
+
+// MergeStrategy returns the array merge strategy for a setting path.
+func (c *Config) MergeStrategy(path string) layer.MergeStrategy {
+	return c.layers.MergeStrategy(path)
+}
+
+// registerMergeStrategies declares the merge strategies of a schema's
+// properties, prefixing their paths with prefix. Invalid strategies are
+// skipped and returned keyed by path.
+func (c *Config) registerMergeStrategies(prefix string, s *schema.Schema) map[string]error {
+	var errs map[string]error
+	for path, name := range s.MergeStrategies() {
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		if err := c.SetMergeStrategy(path, layer.MergeStrategy(name)); err != nil {
+			if errs == nil {
+				errs = make(map[string]error)
+			}
+			errs[path] = err
+		}
+	}
+	return errs
+}
+
+// registerSensitivePaths marks the settings the schema declares sensitive
+// so their values are redacted. Sensitive paths that are not registered
+// settings are recorded as configuration errors.
+func (c *Config) registerSensitivePaths(s *schema.Schema) {
+	for _, path := range s.SensitivePaths() {
+		if err := c.settings.MarkSensitive(path); err != nil {
+			c.recordConfigError(path, err)
+		}
+	}
 }
 
 // loadDefaults loads the default configuration layer.
@@ -561,6 +733,7 @@ func defaultConfig() map[string]any {
 		},
 		"search": map[string]any{
 			"caseSensitive": false,
+			"smartCase":     false,
 			"wholeWord":     false,
 			"regex":         false,
 			"maxResults":    1000,
@@ -2,12 +2,15 @@ package config
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/dshills/keystorm/internal/config/layer"
 	"github.com/dshills/keystorm/internal/config/notify"
 )
 
@@ -218,6 +221,59 @@ func TestConfig_GetStringSlice(t *testing.T) {
 	}
 }
 
+func TestConfig_ArrayMergeStrategy(t *testing.T) {
+	userDir := t.TempDir()
+	projectDir := t.TempDir()
+
+	userSettings := `
+[files]
+exclude = ["*.log", "tmp"]
+`
+	if err := os.WriteFile(filepath.Join(userDir, "settings.toml"), []byte(userSettings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	projectSettings := `
+[files]
+exclude = ["build", "*.log"]
+`
+	if err := os.WriteFile(filepath.Join(projectDir, "config.toml"), []byte(projectSettings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(
+		WithUserConfigDir(userDir),
+		WithProjectConfigDir(projectDir),
+		WithWatcher(false),
+	)
+	defer c.Close()
+
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// files.exclude is declared unique-merge in the schema: the project
+	// layer extends the user list, which replaces the built-in defaults
+	got, err := c.GetStringSlice("files.exclude")
+	if err != nil {
+		t.Fatalf("GetStringSlice('files.exclude') error = %v", err)
+	}
+	want := []string{"*.log", "tmp", "build"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("files.exclude = %v, want %v", got, want)
+	}
+
+	if err := c.SetMergeStrategy("files.exclude", "merge"); err == nil {
+		t.Error("SetMergeStrategy() with unknown strategy should return error")
+	}
+	if err := c.SetMergeStrategy("files.exclude", layer.MergeReplace); err != nil {
+		t.Fatalf("SetMergeStrategy() error = %v", err)
+	}
+	got, _ = c.GetStringSlice("files.exclude")
+	if want := []string{"build", "*.log"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("files.exclude with replace = %v, want %v", got, want)
+	}
+}
+
 func TestConfig_Set(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -524,3 +580,69 @@ func TestTypeName(t *testing.T) {
 		}
 	}
 }
+
+func TestConfig_Interpolation(t *testing.T) {
+	userDir := t.TempDir()
+	settings := `[terminal]
+shell = "${HOME}/bin/zsh"
+
+[editor]
+rulerLabel = "$WORKSPACE costs $$5"
+`
+	if err := os.WriteFile(filepath.Join(userDir, "settings.toml"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(
+		WithWatcher(false),
+		WithSchemaValidation(false),
+		WithUserConfigDir(userDir),
+		WithVariables(map[string]string{"WORKSPACE": "/src/project"}),
+		WithVariableResolver(layer.MapResolver(map[string]string{"HOME": "/home/user"})),
+	)
+	defer c.Close()
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	if got, _ := c.GetString("terminal.shell"); got != "/home/user/bin/zsh" {
+		t.Errorf("terminal.shell = %q, want %q", got, "/home/user/bin/zsh")
+	}
+	if got, _ := c.GetString("editor.rulerLabel"); got != "/src/project costs $5" {
+		t.Errorf("editor.rulerLabel = %q, want %q", got, "/src/project costs $5")
+	}
+
+	c.SetVariable("WORKSPACE", "/other")
+	if got, _ := c.GetString("editor.rulerLabel"); got != "/other costs $5" {
+		t.Errorf("editor.rulerLabel after SetVariable = %q, want %q", got, "/other costs $5")
+	}
+	if err := c.InterpolationError(); err != nil {
+		t.Errorf("InterpolationError() = %v, want nil", err)
+	}
+}
+
+func TestConfig_StrictInterpolation(t *testing.T) {
+	userDir := t.TempDir()
+	settings := "[terminal]\nshell = \"${MISSING}/sh\"\n"
+	if err := os.WriteFile(filepath.Join(userDir, "settings.toml"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(
+		WithWatcher(false),
+		WithUserConfigDir(userDir),
+		WithStrictInterpolation(true),
+		WithVariableResolver(layer.MapResolver(nil)),
+	)
+	defer c.Close()
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	if got, _ := c.GetString("terminal.shell"); got != "${MISSING}/sh" {
+		t.Errorf("terminal.shell = %q, want %q", got, "${MISSING}/sh")
+	}
+	if err := c.InterpolationError(); !errors.Is(err, layer.ErrUnresolvedVariable) {
+		t.Errorf("InterpolationError() = %v, want ErrUnresolvedVariable", err)
+	}
+}
@@ -24,6 +24,15 @@
 //	│  1. Built-in Defaults       │  ← Lowest priority
 //	└─────────────────────────────┘
 //
+// Arrays are replaced by higher layers unless the schema declares an
+// "x-merge-strategy" for the setting: "append", "prepend" or "unique"
+// combine the layers' arrays instead, so a project's "files.exclude"
+// extends the user's list. The built-in defaults are exempt: the first
+// layer that sets an array replaces the default, so users can still drop
+// default patterns. Plugin schemas may declare strategies for their
+// settings, and Config.SetMergeStrategy sets one programmatically. Invalid
+// strategies in the built-in schema are reported by ConfigErrors.
+//
 // # Sub-packages
 //
 //   - loader: Configuration file loading (TOML, JSON, environment variables)
@@ -142,6 +151,13 @@
 //	// Lookup binding
 //	binding, err := keymaps.Lookup("normal", "", "g g")
 //
+//	// Share or inspect customizations
+//	bindings, err := keymaps.Export("normal")
+//	err = other.Keymaps().Import("normal", bindings)
+//	for _, d := range keymaps.DiffFromDefaults() {
+//	    fmt.Println(d.Kind, d.Mode, d.Keys)
+//	}
+//
 // # Configuration Migration
 //
 // The package supports migrating configuration between versions:
@@ -156,6 +172,48 @@
 //	    }
 //	}
 //
+// # Variable Interpolation
+//
+// String settings may reference variables as ${VAR} or $VAR; "$$" is a
+// literal dollar sign. References are expanded in the merged configuration,
+// using editor-provided variables first and the environment second.
+// WORKSPACE defaults to the project config directory:
+//
+//	cfg := config.New(config.WithVariables(map[string]string{
+//	    "WORKSPACE": root,
+//	}))
+//	shell, _ := cfg.GetString("terminal.shell") // "${HOME}/bin/zsh" -> "/home/me/bin/zsh"
+//
+// Unknown variables are left as written unless WithStrictInterpolation is
+// set, in which case InterpolationError reports them.
+//
+// # Profiles
+//
+// Profiles are named sets of overrides, such as "work" or "presentation",
+// applied in a layer above user settings and below workspace settings.
+// Only one profile is active at a time; switching notifies observers of
+// every setting whose effective value changes. The active profile is
+// recorded in the user config directory and restored when it is defined
+// again in the next session:
+//
+//	_ = sys.DefineProfile("presentation", map[string]any{
+//	    "ui.fontSize":        20,
+//	    "editor.lineNumbers": "off",
+//	})
+//	_ = sys.ActivateProfile("presentation")
+//	_ = sys.ActivateProfile("") // back to the user's settings
+//
+// # Runtime Overrides
+//
+// Set writes to the user settings layer, which is replaced when
+// settings.toml changes on disk. Settings changed by the editor itself,
+// such as a toggle command, belong in the runtime layer instead: it sits
+// above every other layer, is never saved, and survives file reloads until
+// cleared:
+//
+//	_ = sys.SetRuntime("editor.wordWrap", "on")
+//	_ = sys.ClearRuntime("editor.wordWrap") // back to the files' value
+//
 // # Change Notifications
 //
 // Subscribe to configuration changes:
@@ -171,6 +229,22 @@
 //	    // Handle editor changes
 //	})
 //
+// # Cross-Field Validation
+//
+// Schema validation checks each setting on its own. Rules relating several
+// settings are registered as validators, which run on Validate and whose
+// errors are reported by Health:
+//
+//	sys.AddValidator(func(cfg *config.Config) []*config.ValidationError {
+//	    mode, _ := cfg.GetString("files.autoSave")
+//	    delay, _ := cfg.GetInt("files.autoSaveDelay")
+//	    if mode == "afterDelay" && delay > 10000 {
+//	        return []*config.ValidationError{config.NewCrossFieldError(
+//	            "delay too long for afterDelay", "files.autoSaveDelay", "files.autoSave")}
+//	    }
+//	    return nil
+//	})
+//
 // # Error Handling
 //
 // The package defines several error types:
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Errors returned by configuration operations.
@@ -33,6 +34,12 @@ var (
 
 	// ErrIncludeDepthExceeded indicates too many nested @include directives.
 	ErrIncludeDepthExceeded = errors.New("include depth exceeded")
+
+	// ErrInvalidMergeStrategy indicates an unknown array merge strategy.
+	ErrInvalidMergeStrategy = errors.New("invalid merge strategy")
+
+	// ErrProfileNotFound indicates the configuration profile is not defined.
+	ErrProfileNotFound = errors.New("profile not found")
 )
 
 // ParseError represents an error while parsing a configuration file.
@@ -75,10 +82,16 @@ type ValidationError struct {
 	Value any
 	// Code categorizes the validation error.
 	Code ValidationErrorCode
+	// Paths are all the settings involved in a cross-field error, starting
+	// with Path. Empty for single-setting errors.
+	Paths []string
 }
 
 // Error implements the error interface.
 func (e *ValidationError) Error() string {
+	if len(e.Paths) > 0 {
+		return fmt.Sprintf("%s: %s", strings.Join(e.Paths, ", "), e.Message)
+	}
 	return fmt.Sprintf("%s: %s (value: %v)", e.Path, e.Message, e.Value)
 }
 
@@ -100,6 +113,8 @@ const (
 	ErrCodeRequiredMissing
 	// ErrCodeDeprecated indicates the setting is deprecated.
 	ErrCodeDeprecated
+	// ErrCodeCrossField indicates related settings are inconsistent.
+	ErrCodeCrossField
 )
 
 // String returns a human-readable name for the error code.
@@ -119,6 +134,8 @@ func (c ValidationErrorCode) String() string {
 		return "required_missing"
 	case ErrCodeDeprecated:
 		return "deprecated"
+	case ErrCodeCrossField:
+		return "cross_field"
 	default:
 		return "unknown"
 	}
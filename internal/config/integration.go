@@ -193,6 +193,22 @@ func (s *ConfigSystem) GetFloat(path string) (float64, error) {
 	return s.config.GetFloat(path)
 }
 
+// GetSecret returns the real value of a sensitive string setting.
+// It is the only accessor that does not redact sensitive values.
+func (s *ConfigSystem) GetSecret(path string) (string, error) {
+	return s.config.GetSecret(path)
+}
+
+// Explain reports the effective value of a setting and where it comes from.
+func (s *ConfigSystem) Explain(path string) Explanation {
+	return s.config.Explain(path)
+}
+
+// DumpEffective renders the merged configuration as TOML with secrets redacted.
+func (s *ConfigSystem) DumpEffective() (string, error) {
+	return s.config.DumpEffective()
+}
+
 // GetStringSlice returns a string slice at the given path.
 func (s *ConfigSystem) GetStringSlice(path string) ([]string, error) {
 	return s.config.GetStringSlice(path)
@@ -207,6 +223,59 @@ func (s *ConfigSystem) Set(path string, value any) error {
 	return s.config.Set(path, value)
 }
 
+// SetRuntime sets an in-memory runtime override that survives reloads of
+// the configuration files. See Config.SetRuntime.
+// Returns ErrSystemClosed if the system has been closed.
+func (s *ConfigSystem) SetRuntime(path string, value any) error {
+	if s.closed.Load() {
+		return ErrSystemClosed
+	}
+	return s.config.SetRuntime(path, value)
+}
+
+// ClearRuntime removes the runtime override at path. See Config.ClearRuntime.
+// Returns ErrSystemClosed if the system has been closed.
+func (s *ConfigSystem) ClearRuntime(path string) error {
+	if s.closed.Load() {
+		return ErrSystemClosed
+	}
+	return s.config.ClearRuntime(path)
+}
+
+// RuntimeOverrides returns the runtime overrides keyed by setting path.
+func (s *ConfigSystem) RuntimeOverrides() map[string]any {
+	return s.config.RuntimeOverrides()
+}
+
+// DefineProfile defines a named configuration profile. See Config.DefineProfile.
+// Returns ErrSystemClosed if the system has been closed.
+func (s *ConfigSystem) DefineProfile(name string, overrides map[string]any) error {
+	if s.closed.Load() {
+		return ErrSystemClosed
+	}
+	return s.config.DefineProfile(name, overrides)
+}
+
+// ActivateProfile switches to the named profile, or deactivates profiles if
+// name is empty. See Config.ActivateProfile.
+// Returns ErrSystemClosed if the system has been closed.
+func (s *ConfigSystem) ActivateProfile(name string) error {
+	if s.closed.Load() {
+		return ErrSystemClosed
+	}
+	return s.config.ActivateProfile(name)
+}
+
+// ActiveProfile returns the name of the active profile, or "".
+func (s *ConfigSystem) ActiveProfile() string {
+	return s.config.ActiveProfile()
+}
+
+// Profiles returns the names of the defined profiles.
+func (s *ConfigSystem) Profiles() []string {
+	return s.config.Profiles()
+}
+
 // Subscribe registers an observer for all configuration changes.
 // Returns nil if the system has been closed.
 func (s *ConfigSystem) Subscribe(observer notify.Observer) *notify.Subscription {
@@ -310,6 +379,16 @@ func (s *ConfigSystem) ClearConfigErrors() {
 	s.config.ClearConfigErrors()
 }
 
+// AddValidator registers a cross-field validator. See Config.AddValidator.
+func (s *ConfigSystem) AddValidator(fn Validator) {
+	s.config.AddValidator(fn)
+}
+
+// Validate runs the registered cross-field validators. See Config.Validate.
+func (s *ConfigSystem) Validate() []*ValidationError {
+	return s.config.Validate()
+}
+
 // Integration returns type-safe access to integration layer settings.
 func (s *ConfigSystem) Integration() IntegrationSettings {
 	return s.config.Integration()
@@ -318,8 +397,9 @@ func (s *ConfigSystem) Integration() IntegrationSettings {
 // Health returns the health status of the configuration system.
 func (s *ConfigSystem) Health() SystemHealth {
 	errors := s.config.ConfigErrors()
+	validationErrors := s.config.Validate()
 	status := HealthOK
-	if len(errors) > 0 {
+	if len(errors) > 0 || len(validationErrors) > 0 {
 		status = HealthDegraded
 	}
 
@@ -335,11 +415,12 @@ func (s *ConfigSystem) Health() SystemHealth {
 	}
 
 	return SystemHealth{
-		Status:       status,
-		LoadTime:     loadTime,
-		LastReloadAt: lastReloadAt,
-		ErrorCount:   len(errors),
-		Errors:       errorsCopy,
+		Status:           status,
+		LoadTime:         loadTime,
+		LastReloadAt:     lastReloadAt,
+		ErrorCount:       len(errors) + len(validationErrors),
+		Errors:           errorsCopy,
+		ValidationErrors: validationErrors,
 	}
 }
 
@@ -359,6 +440,10 @@ type SystemHealth struct {
 
 	// Errors contains the configuration errors by path.
 	Errors map[string]error
+
+	// ValidationErrors contains the errors reported by cross-field
+	// validators registered with AddValidator.
+	ValidationErrors []*ValidationError
 }
 
 // HealthStatus represents the health status of a component.
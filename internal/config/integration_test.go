@@ -870,3 +870,88 @@ func TestConfigSystem_FileWatchReload(t *testing.T) {
 		t.Error("did not receive reload notification")
 	}
 }
+
+func TestConfigSystem_RuntimeOverrideSurvivesReload(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	settingsPath := filepath.Join(tmpDir, "settings.toml")
+	if err := os.WriteFile(settingsPath, []byte("[editor]\ntabSize = 4\ninsertSpaces = true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sys, err := NewConfigSystem(context.Background(),
+		WithSystemUserConfigDir(tmpDir),
+		WithSystemWatcher(true),
+	)
+	if err != nil {
+		t.Fatalf("NewConfigSystem() error = %v", err)
+	}
+	defer sys.Close()
+
+	if err := sys.SetRuntime("editor.insertSpaces", false); err != nil {
+		t.Fatalf("SetRuntime() error = %v", err)
+	}
+	if v, _ := sys.GetBool("editor.insertSpaces"); v {
+		t.Error("insertSpaces = true after SetRuntime(false)")
+	}
+
+	var reloadReceived atomic.Bool
+	sub := sys.Subscribe(func(change notify.Change) {
+		if change.Type == notify.ChangeReload {
+			reloadReceived.Store(true)
+		}
+	})
+	defer sub.Unsubscribe()
+
+	// Saving the settings file must not revert the runtime override
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(settingsPath, []byte("[editor]\ntabSize = 8\ninsertSpaces = true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !reloadReceived.Load() && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if !reloadReceived.Load() {
+		t.Fatal("did not receive reload notification")
+	}
+
+	if v, _ := sys.GetInt("editor.tabSize"); v != 8 {
+		t.Errorf("tabSize = %d after reload, want 8", v)
+	}
+	if v, _ := sys.GetBool("editor.insertSpaces"); v {
+		t.Error("runtime override of insertSpaces was lost on reload")
+	}
+	if got := sys.RuntimeOverrides(); len(got) != 1 || got["editor.insertSpaces"] != false {
+		t.Errorf("RuntimeOverrides() = %v, want editor.insertSpaces only", got)
+	}
+
+	// Clearing the override restores the file's value
+	if err := sys.ClearRuntime("editor.insertSpaces"); err != nil {
+		t.Fatalf("ClearRuntime() error = %v", err)
+	}
+	if v, _ := sys.GetBool("editor.insertSpaces"); !v {
+		t.Error("insertSpaces = false after ClearRuntime")
+	}
+	if err := sys.ClearRuntime("editor.insertSpaces"); err != nil {
+		t.Errorf("second ClearRuntime() error = %v", err)
+	}
+}
+
+func TestConfig_SetRuntimeValidation(t *testing.T) {
+	c := New(WithUserConfigDir(t.TempDir()), WithWatcher(false))
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	defer c.Close()
+
+	if err := c.SetRuntime("editor.tabSize", "wide"); err == nil {
+		t.Error("SetRuntime() with wrong type should fail validation")
+	}
+	if len(c.RuntimeOverrides()) != 0 {
+		t.Errorf("RuntimeOverrides() = %v after failed SetRuntime, want none", c.RuntimeOverrides())
+	}
+	if err := c.ClearRuntime(""); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("ClearRuntime(\"\") error = %v, want ErrInvalidPath", err)
+	}
+}
@@ -91,20 +91,23 @@ func (m *KeymapManager) Registry() *keymap.Registry {
 	return m.registry
 }
 
-// LoadDefaults loads the default keymaps into the registry.
-func (m *KeymapManager) LoadDefaults() error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	defaults := []*keymap.Keymap{
+// defaultKeymaps returns fresh copies of the built-in keymaps.
+func defaultKeymaps() []*keymap.Keymap {
+	return []*keymap.Keymap{
 		keymap.DefaultNormalKeymap(),
 		keymap.DefaultInsertKeymap(),
 		keymap.DefaultVisualKeymap(),
 		keymap.DefaultCommandKeymap(),
 		keymap.DefaultGlobalKeymap(),
 	}
+}
+
+// LoadDefaults loads the default keymaps into the registry.
+func (m *KeymapManager) LoadDefaults() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	for _, km := range defaults {
+	for _, km := range defaultKeymaps() {
 		if err := m.registry.Register(km); err != nil {
 			return fmt.Errorf("registering default keymap %q: %w", km.Name, err)
 		}
@@ -0,0 +1,313 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/keymap"
+)
+
+// BindingDiffKind describes how a binding differs from the defaults.
+type BindingDiffKind int
+
+const (
+	// BindingAdded indicates a binding for keys that have no default binding.
+	BindingAdded BindingDiffKind = iota
+
+	// BindingOverridden indicates keys bound to a different action or
+	// arguments than the default.
+	BindingOverridden
+
+	// BindingRemoved indicates a default binding that is no longer active.
+	BindingRemoved
+)
+
+// String returns the diff kind name.
+func (k BindingDiffKind) String() string {
+	switch k {
+	case BindingAdded:
+		return "added"
+	case BindingOverridden:
+		return "overridden"
+	case BindingRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// BindingDiff describes one difference between the effective bindings and
+// the default bindings.
+type BindingDiff struct {
+	// Kind is the type of difference.
+	Kind BindingDiffKind
+
+	// Mode is the mode of the keymap holding the binding. Empty for global
+	// bindings.
+	Mode string
+
+	// Keys is the key sequence.
+	Keys string
+
+	// When is the binding's condition expression.
+	When string
+
+	// Default is the default binding. Nil for added bindings.
+	Default *KeymapBinding
+
+	// Current is the effective binding. Nil for removed bindings.
+	Current *KeymapBinding
+}
+
+// bindingKey identifies a binding slot: bindings with the same mode,
+// normalized key sequence and condition compete for the same keys.
+type bindingKey struct {
+	mode string
+	keys string
+	when string
+}
+
+// Export returns the effective bindings for a mode after merging the
+// default, user and plugin keymaps, including global bindings active in
+// that mode. For each key sequence and condition only the winning binding
+// is returned. Bindings with an empty action unbind their keys and are
+// omitted. File-type specific keymaps are not included.
+//
+// Returns ErrSettingNotFound if no keymap is registered for the mode.
+func (m *KeymapManager) Export(mode string) ([]KeymapBinding, error) {
+	m.mu.RLock()
+	reg := m.registry
+	m.mu.RUnlock()
+
+	if mode != "" && !hasKeymapForMode(reg, mode) {
+		return nil, fmt.Errorf("%w: no keymap for mode %q", ErrSettingNotFound, mode)
+	}
+
+	seen := make(map[bindingKey]bool)
+	result := make([]KeymapBinding, 0)
+	for _, match := range reg.AllBindings(mode) {
+		if match.Keymap.FileType != "" {
+			continue
+		}
+		k := bindingKey{keys: match.Sequence.String(), when: match.When}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		if match.Action == "" {
+			continue
+		}
+		result = append(result, fromKeymapBinding(match.Binding))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Keys < result[j].Keys
+	})
+	return result, nil
+}
+
+// DiffFromDefaults lists how the effective bindings differ from the
+// bindings installed by LoadDefaults: bindings for new keys are added,
+// default keys bound to another action or arguments are overridden, and
+// default keys that are unbound (bound to an empty action) or whose
+// keymap is no longer registered are removed. Bindings are compared per
+// keymap mode, so a mode binding shadowing a global default is reported
+// as added. Results are ordered by mode and keys.
+func (m *KeymapManager) DiffFromDefaults() []BindingDiff {
+	m.mu.RLock()
+	reg := m.registry
+	m.mu.RUnlock()
+
+	defaults := keymap.NewRegistry()
+	for _, km := range defaultKeymaps() {
+		// The built-in keymaps always parse
+		_ = defaults.Register(km)
+	}
+
+	want := effectiveBindings(defaults)
+	have := effectiveBindings(reg)
+
+	var diffs []BindingDiff
+	for k, cur := range have {
+		def, ok := want[k]
+		switch {
+		case !ok && cur.Action != "":
+			diffs = append(diffs, newBindingDiff(BindingAdded, k, nil, &cur))
+		case ok && cur.Action == "":
+			diffs = append(diffs, newBindingDiff(BindingRemoved, k, &def, nil))
+		case ok && (cur.Action != def.Action || !reflect.DeepEqual(cur.Args, def.Args)):
+			diffs = append(diffs, newBindingDiff(BindingOverridden, k, &def, &cur))
+		}
+	}
+	for k, def := range want {
+		if _, ok := have[k]; !ok {
+			diffs = append(diffs, newBindingDiff(BindingRemoved, k, &def, nil))
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Mode != diffs[j].Mode {
+			return diffs[i].Mode < diffs[j].Mode
+		}
+		if diffs[i].Keys != diffs[j].Keys {
+			return diffs[i].Keys < diffs[j].Keys
+		}
+		return diffs[i].When < diffs[j].When
+	})
+	return diffs
+}
+
+// Import adds bindings to the user keymap for a mode, replacing existing
+// user bindings for the same keys and condition. All key sequences are
+// validated before any binding is added; empty keys fail with
+// ErrValidationFailed.
+func (m *KeymapManager) Import(mode string, bindings []KeymapBinding) error {
+	for _, b := range bindings {
+		if strings.TrimSpace(b.Keys) == "" {
+			return fmt.Errorf("%w: binding for %q has empty keys", ErrValidationFailed, b.Action)
+		}
+		if _, err := key.ParseSequence(b.Keys); err != nil {
+			return fmt.Errorf("importing binding %q: %w", b.Keys, err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keymapName := "user-" + mode
+	if mode == "" {
+		keymapName = "user-global"
+	}
+
+	entry, ok := m.userKeymaps[keymapName]
+	if !ok {
+		entry = &KeymapEntry{
+			Name:     keymapName,
+			Mode:     mode,
+			Priority: 100, // User keymaps have high priority
+			Bindings: make([]KeymapBinding, 0, len(bindings)),
+		}
+		m.userKeymaps[keymapName] = entry
+	}
+
+	for _, b := range bindings {
+		if i := indexOfBinding(entry.Bindings, b); i >= 0 {
+			entry.Bindings[i] = b
+		} else {
+			entry.Bindings = append(entry.Bindings, b)
+		}
+	}
+
+	if err := m.registerEntry(entry); err != nil {
+		return err
+	}
+
+	path := "keymaps." + keymapName
+	if m.notifier != nil {
+		m.notifier.NotifySet(path, nil, bindings, "user")
+	}
+
+	return nil
+}
+
+// indexOfBinding returns the index of the binding in bindings with the
+// same keys and condition as b, or -1.
+func indexOfBinding(bindings []KeymapBinding, b KeymapBinding) int {
+	want := normalizeKeys(b.Keys)
+	for i, existing := range bindings {
+		if existing.When == b.When && normalizeKeys(existing.Keys) == want {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeKeys returns the canonical form of a key sequence, so that
+// "C-s" and "Ctrl+S" compare equal. Unparseable keys are returned as is.
+func normalizeKeys(keys string) string {
+	seq, err := key.ParseSequence(keys)
+	if err != nil {
+		return keys
+	}
+	return seq.String()
+}
+
+// effectiveBindings returns the winning binding for every binding slot in
+// a registry, ignoring file-type specific keymaps.
+func effectiveBindings(reg *keymap.Registry) map[bindingKey]KeymapBinding {
+	var matches []keymap.BindingMatch
+	for _, km := range reg.Keymaps() {
+		if km.FileType != "" {
+			continue
+		}
+		for i := range km.ParsedBindings {
+			match := keymap.BindingMatch{
+				ParsedBinding: &km.ParsedBindings[i],
+				Keymap:        km.Keymap,
+			}
+			match.CalculateScore()
+			matches = append(matches, match)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Less(matches[j])
+	})
+
+	result := make(map[bindingKey]KeymapBinding)
+	for _, match := range matches {
+		k := bindingKey{
+			mode: match.Keymap.Mode,
+			keys: match.Sequence.String(),
+			when: match.When,
+		}
+		if _, ok := result[k]; !ok {
+			result[k] = fromKeymapBinding(match.Binding)
+		}
+	}
+	return result
+}
+
+// hasKeymapForMode returns true if a keymap is registered for the mode.
+func hasKeymapForMode(reg *keymap.Registry, mode string) bool {
+	for _, km := range reg.Keymaps() {
+		if km.Mode == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// newBindingDiff builds a diff entry for a binding slot.
+func newBindingDiff(kind BindingDiffKind, k bindingKey, def, cur *KeymapBinding) BindingDiff {
+	keys := k.keys
+	if cur != nil {
+		keys = cur.Keys
+	} else if def != nil {
+		keys = def.Keys
+	}
+	return BindingDiff{
+		Kind:    kind,
+		Mode:    k.mode,
+		Keys:    keys,
+		When:    k.when,
+		Default: def,
+		Current: cur,
+	}
+}
+
+// fromKeymapBinding converts a registry binding to a config binding.
+func fromKeymapBinding(b keymap.Binding) KeymapBinding {
+	return KeymapBinding{
+		Keys:        b.Keys,
+		Action:      b.Action,
+		Args:        b.Args,
+		When:        b.When,
+		Description: b.Description,
+		Priority:    b.Priority,
+		Category:    b.Category,
+	}
+}
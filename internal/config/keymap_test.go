@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"sync"
@@ -523,3 +524,100 @@ func TestKeymapBinding_WithArgs(t *testing.T) {
 		t.Errorf("Args[register] = %v, want 'a'", got.Args["register"])
 	}
 }
+
+func TestKeymapManager_DiffFromDefaults(t *testing.T) {
+	c := New(WithWatcher(false))
+	defer c.Close()
+
+	km := c.Keymaps()
+	if err := km.LoadDefaults(); err != nil {
+		t.Fatalf("LoadDefaults() error = %v", err)
+	}
+
+	if diffs := km.DiffFromDefaults(); len(diffs) != 0 {
+		t.Fatalf("DiffFromDefaults() = %v, want no differences", diffs)
+	}
+
+	km.AddBinding("normal", KeymapBinding{Keys: "j", Action: "cursor.moveDownWrapped"})
+	km.AddBinding("normal", KeymapBinding{Keys: "g z", Action: "fold.toggle"})
+	km.AddBinding("normal", KeymapBinding{Keys: "h", Action: ""})
+
+	diffs := km.DiffFromDefaults()
+	if len(diffs) != 3 {
+		t.Fatalf("DiffFromDefaults() returned %d diffs, want 3: %v", len(diffs), diffs)
+	}
+
+	byKeys := make(map[string]BindingDiff)
+	for _, d := range diffs {
+		if d.Mode != "normal" {
+			t.Errorf("diff %q Mode = %q, want normal", d.Keys, d.Mode)
+		}
+		byKeys[d.Keys] = d
+	}
+
+	if d := byKeys["j"]; d.Kind != BindingOverridden || d.Default == nil || d.Default.Action != "cursor.moveDown" ||
+		d.Current == nil || d.Current.Action != "cursor.moveDownWrapped" {
+		t.Errorf("diff for j = %+v, want override of cursor.moveDown", d)
+	}
+	if d := byKeys["g z"]; d.Kind != BindingAdded || d.Default != nil || d.Current == nil || d.Current.Action != "fold.toggle" {
+		t.Errorf("diff for g z = %+v, want added fold.toggle", d)
+	}
+	if d := byKeys["h"]; d.Kind != BindingRemoved || d.Default == nil || d.Current != nil {
+		t.Errorf("diff for h = %+v, want removed", d)
+	}
+}
+
+func TestKeymapManager_ExportImport(t *testing.T) {
+	c := New(WithWatcher(false))
+	defer c.Close()
+
+	km := c.Keymaps()
+	if err := km.LoadDefaults(); err != nil {
+		t.Fatalf("LoadDefaults() error = %v", err)
+	}
+
+	if _, err := km.Export("nosuchmode"); err == nil {
+		t.Error("Export() of unknown mode should fail")
+	}
+
+	err := km.Import("normal", []KeymapBinding{
+		{Keys: "j", Action: "cursor.moveDownWrapped"},
+		{Keys: "h", Action: ""},
+	})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	// Importing the same keys again replaces the user binding
+	if err := km.Import("normal", []KeymapBinding{{Keys: "j", Action: "cursor.moveDownDisplay"}}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if got := km.ListUserBindings("normal"); len(got) != 2 {
+		t.Errorf("ListUserBindings() = %v, want 2 bindings", got)
+	}
+
+	if err := km.Import("normal", []KeymapBinding{{Keys: " ", Action: "x"}}); !errors.Is(err, ErrValidationFailed) {
+		t.Errorf("Import() with empty keys error = %v, want ErrValidationFailed", err)
+	}
+
+	exported, err := km.Export("normal")
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	actions := make(map[string]string)
+	for _, b := range exported {
+		if _, dup := actions[b.Keys]; dup {
+			t.Errorf("Export() has duplicate binding for %q", b.Keys)
+		}
+		actions[b.Keys] = b.Action
+	}
+	if actions["j"] != "cursor.moveDownDisplay" {
+		t.Errorf("exported j = %q, want cursor.moveDownDisplay", actions["j"])
+	}
+	if actions["k"] != "cursor.moveUp" {
+		t.Errorf("exported k = %q, want default cursor.moveUp", actions["k"])
+	}
+	if _, ok := actions["h"]; ok {
+		t.Error("exported bindings should omit unbound h")
+	}
+}
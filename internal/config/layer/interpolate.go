@@ -0,0 +1,208 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Resolver returns the value of a variable referenced from a setting.
+type Resolver func(name string) (string, bool)
+
+// EnvResolver returns a resolver that looks variables up in the process
+// environment.
+func EnvResolver() Resolver {
+	return os.LookupEnv
+}
+
+// MapResolver returns a resolver that looks variables up in vars.
+func MapResolver(vars map[string]string) Resolver {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+// ChainResolvers returns a resolver that tries each resolver in order and
+// returns the first value found.
+func ChainResolvers(resolvers ...Resolver) Resolver {
+	return func(name string) (string, bool) {
+		for _, r := range resolvers {
+			if r == nil {
+				continue
+			}
+			if v, ok := r(name); ok {
+				return v, true
+			}
+		}
+		return "", false
+	}
+}
+
+// ErrUnresolvedVariable indicates a setting references an unknown variable.
+var ErrUnresolvedVariable = errors.New("unresolved variable")
+
+// InterpolationError reports a setting that could not be interpolated.
+type InterpolationError struct {
+	// Path is the setting path.
+	Path string
+
+	// Variable is the unresolved variable name.
+	Variable string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *InterpolationError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%v: %s", e.Err, e.Variable)
+	}
+	return fmt.Sprintf("%s: %v: %s", e.Path, e.Err, e.Variable)
+}
+
+// Unwrap returns the underlying error.
+func (e *InterpolationError) Unwrap() error {
+	return e.Err
+}
+
+// Interpolation expands ${VAR} and $VAR references in string settings.
+// "$$" is an escaped dollar sign.
+type Interpolation struct {
+	// Resolver resolves variable names. Nil resolves nothing.
+	Resolver Resolver
+
+	// Strict reports unresolved variables as errors. Otherwise the
+	// reference is left in the value as written.
+	Strict bool
+}
+
+// Expand expands the variable references in s. In strict mode an
+// unresolved variable returns an error wrapping ErrUnresolvedVariable,
+// along with s expanded as far as possible.
+func (in Interpolation) Expand(s string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	var firstErr error
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '$' || i+1 == len(s) {
+			b.WriteByte(c)
+			continue
+		}
+
+		next := s[i+1]
+		switch {
+		case next == '$':
+			b.WriteByte('$')
+			i++
+			continue
+		case next == '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+			name := s[i+2 : i+2+end]
+			ref := s[i : i+3+end]
+			i += 2 + end
+			if err := in.expandRef(&b, name, ref); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		case isVarStart(next):
+			j := i + 2
+			for j < len(s) && isVarChar(s[j]) {
+				j++
+			}
+			name := s[i+1 : j]
+			ref := s[i:j]
+			i = j - 1
+			if err := in.expandRef(&b, name, ref); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), firstErr
+}
+
+// expandRef writes the value of variable name, or ref if it is unresolved.
+func (in Interpolation) expandRef(b *strings.Builder, name, ref string) error {
+	if in.Resolver != nil && name != "" {
+		if v, ok := in.Resolver(name); ok {
+			b.WriteString(v)
+			return nil
+		}
+	}
+	b.WriteString(ref)
+	if in.Strict {
+		return &InterpolationError{Variable: name, Err: ErrUnresolvedVariable}
+	}
+	return nil
+}
+
+// ExpandMap expands the string values of data in place, including strings
+// nested in maps and arrays. Nested maps and []any arrays are modified, so
+// data must not share them with layer data. It returns one error per
+// setting that could not be interpolated, joined with errors.Join.
+func (in Interpolation) ExpandMap(data map[string]any) error {
+	var errs []error
+	in.expandMap(data, "", &errs)
+	return errors.Join(errs...)
+}
+
+func (in Interpolation) expandMap(data map[string]any, prefix string, errs *[]error) {
+	for key, val := range data {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		data[key] = in.expandValue(val, path, errs)
+	}
+}
+
+func (in Interpolation) expandValue(val any, path string, errs *[]error) any {
+	switch v := val.(type) {
+	case string:
+		expanded, err := in.Expand(v)
+		if err != nil {
+			var ie *InterpolationError
+			if errors.As(err, &ie) {
+				ie.Path = path
+			}
+			*errs = append(*errs, err)
+		}
+		return expanded
+	case map[string]any:
+		in.expandMap(v, path, errs)
+		return v
+	case []any:
+		for i, elem := range v {
+			v[i] = in.expandValue(elem, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+		return v
+	case []string:
+		// []string is not cloned by the merge, so expand into a copy.
+		expanded := make([]string, len(v))
+		for i, elem := range v {
+			expanded[i], _ = in.expandValue(elem, fmt.Sprintf("%s[%d]", path, i), errs).(string)
+		}
+		return expanded
+	default:
+		return val
+	}
+}
+
+func isVarStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isVarChar(c byte) bool {
+	return isVarStart(c) || (c >= '0' && c <= '9')
+}
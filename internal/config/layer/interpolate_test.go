@@ -0,0 +1,135 @@
+package layer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInterpolation_Expand(t *testing.T) {
+	in := Interpolation{Resolver: MapResolver(map[string]string{
+		"HOME":      "/home/user",
+		"WORKSPACE": "/src/project",
+		"EMPTY":     "",
+	})}
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{"$HOME/bin", "/home/user/bin"},
+		{"${WORKSPACE}/tools", "/src/project/tools"},
+		{"${HOME}${WORKSPACE}", "/home/user/src/project"},
+		{"$HOME_DIR", "$HOME_DIR"},
+		{"price: $$5", "price: $5"},
+		{"$$HOME", "$HOME"},
+		{"$$$HOME", "$/home/user"},
+		{"x${EMPTY}y", "xy"},
+		{"trailing $", "trailing $"},
+		{"${unclosed", "${unclosed"},
+		{"$1", "$1"},
+		{"${MISSING}", "${MISSING}"},
+	}
+
+	for _, tt := range tests {
+		got, err := in.Expand(tt.in)
+		if err != nil {
+			t.Errorf("Expand(%q) error = %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestInterpolation_Strict(t *testing.T) {
+	in := Interpolation{Resolver: MapResolver(map[string]string{"A": "a"}), Strict: true}
+
+	got, err := in.Expand("$A/${MISSING}")
+	if !errors.Is(err, ErrUnresolvedVariable) {
+		t.Fatalf("Expand() error = %v, want ErrUnresolvedVariable", err)
+	}
+	if got != "a/${MISSING}" {
+		t.Errorf("Expand() = %q, want %q", got, "a/${MISSING}")
+	}
+
+	var ie *InterpolationError
+	if !errors.As(err, &ie) || ie.Variable != "MISSING" {
+		t.Errorf("error = %#v, want InterpolationError for MISSING", err)
+	}
+}
+
+func TestChainResolvers(t *testing.T) {
+	r := ChainResolvers(
+		MapResolver(map[string]string{"A": "first"}),
+		nil,
+		MapResolver(map[string]string{"A": "second", "B": "b"}),
+	)
+
+	if v, ok := r("A"); !ok || v != "first" {
+		t.Errorf("r(A) = %q, %v, want first, true", v, ok)
+	}
+	if v, ok := r("B"); !ok || v != "b" {
+		t.Errorf("r(B) = %q, %v, want b, true", v, ok)
+	}
+	if _, ok := r("C"); ok {
+		t.Error("r(C) should not resolve")
+	}
+}
+
+func TestManager_Interpolation(t *testing.T) {
+	m := NewManager()
+	m.AddLayer(NewLayerWithData("defaults", SourceBuiltin, PriorityBuiltin, map[string]any{
+		"terminal": map[string]any{"shell": "${HOME}/bin/zsh"},
+		"lsp": map[string]any{
+			"servers": map[string]any{
+				"go": map[string]any{"args": []any{"-logfile", "$WORKSPACE/gopls.log"}},
+			},
+		},
+		"editor": map[string]any{"tabSize": 4},
+	}))
+	m.SetInterpolation(&Interpolation{Resolver: MapResolver(map[string]string{
+		"HOME":      "/home/user",
+		"WORKSPACE": "/src/project",
+	})})
+
+	if v, _ := m.GetEffectiveValue("terminal.shell"); v != "/home/user/bin/zsh" {
+		t.Errorf("terminal.shell = %v, want /home/user/bin/zsh", v)
+	}
+
+	merged := m.Merge()
+	args := merged["lsp"].(map[string]any)["servers"].(map[string]any)["go"].(map[string]any)["args"].([]any)
+	if args[1] != "/src/project/gopls.log" {
+		t.Errorf("args[1] = %v, want /src/project/gopls.log", args[1])
+	}
+
+	// Layer data keeps the reference.
+	if v, _ := m.GetLayerValue("defaults", "terminal.shell"); v != "${HOME}/bin/zsh" {
+		t.Errorf("layer terminal.shell = %v, want unexpanded", v)
+	}
+	if err := m.InterpolationError(); err != nil {
+		t.Errorf("InterpolationError() = %v, want nil", err)
+	}
+
+	m.SetInterpolation(nil)
+	if v, _ := m.GetEffectiveValue("terminal.shell"); v != "${HOME}/bin/zsh" {
+		t.Errorf("terminal.shell without interpolation = %v, want unexpanded", v)
+	}
+}
+
+func TestManager_InterpolationStrict(t *testing.T) {
+	m := NewManager()
+	m.AddLayer(NewLayerWithData("user", SourceUserGlobal, PriorityUserGlobal, map[string]any{
+		"terminal": map[string]any{"shell": "${NOPE}/sh"},
+	}))
+	m.SetInterpolation(&Interpolation{Strict: true})
+
+	err := m.InterpolationError()
+	var ie *InterpolationError
+	if !errors.As(err, &ie) {
+		t.Fatalf("InterpolationError() = %v, want InterpolationError", err)
+	}
+	if ie.Path != "terminal.shell" || ie.Variable != "NOPE" {
+		t.Errorf("error path/variable = %q/%q, want terminal.shell/NOPE", ie.Path, ie.Variable)
+	}
+}
@@ -87,6 +87,10 @@ const (
 	SourcePlugin
 	// SourceSession represents in-memory session overrides.
 	SourceSession
+	// SourceProfile represents the active named configuration profile.
+	SourceProfile
+	// SourceRuntime represents in-memory overrides set by editor commands.
+	SourceRuntime
 )
 
 // String returns a human-readable name for the source.
@@ -108,6 +112,10 @@ func (s Source) String() string {
 		return "plugin"
 	case SourceSession:
 		return "session"
+	case SourceProfile:
+		return "profile"
+	case SourceRuntime:
+		return "runtime"
 	default:
 		return "unknown"
 	}
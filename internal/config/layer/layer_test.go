@@ -101,6 +101,7 @@ func TestSource_String(t *testing.T) {
 		{SourceArgs, "arguments"},
 		{SourcePlugin, "plugin"},
 		{SourceSession, "session"},
+		{SourceProfile, "profile"},
 		{Source(255), "unknown"},
 	}
 
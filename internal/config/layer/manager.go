@@ -12,6 +12,13 @@ type Manager struct {
 	layers []*Layer       // Sorted by priority (ascending)
 	merged map[string]any // Cached merged result
 	dirty  bool           // Whether merged cache needs refresh
+
+	// strategies maps setting paths to their array merge strategy
+	strategies map[string]MergeStrategy
+
+	// interpolation expands variables in merged string settings
+	interpolation *Interpolation
+	interpErr     error
 }
 
 // NewManager creates a new layer manager.
@@ -99,31 +106,33 @@ func (m *Manager) Merge() map[string]any {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if !m.dirty && m.merged != nil {
-		return cloneMap(m.merged)
-	}
-
-	result := make(map[string]any)
-
-	// Apply layers in priority order (lowest first, highest last)
-	for _, layer := range m.layers {
-		result = DeepMerge(result, layer.Data)
-	}
-
-	m.merged = result
-	m.dirty = false
-
-	return cloneMap(result)
+	return cloneMap(m.mergedData())
 }
 
 // mergedData returns the cached merged data (internal use only).
 // This refreshes the cache if dirty but returns the internal reference.
 func (m *Manager) mergedData() map[string]any {
 	if m.dirty || m.merged == nil {
-		result := make(map[string]any)
+		// Apply layers in priority order (lowest first, highest last).
+		// Merge strategies only combine the arrays of configured layers:
+		// the first one to set an array replaces the built-in default, so
+		// a user list can drop default entries while a project extends it.
+		defaults := make(map[string]any)
+		overrides := make(map[string]any)
 		for _, layer := range m.layers {
-			result = DeepMerge(result, layer.Data)
+			if layer.Source == SourceBuiltin {
+				defaults = DeepMerge(defaults, layer.Data)
+				continue
+			}
+			overrides = DeepMergeWithStrategies(overrides, layer.Data, m.strategies)
+		}
+		result := DeepMerge(defaults, overrides)
+
+		m.interpErr = nil
+		if m.interpolation != nil {
+			m.interpErr = m.interpolation.ExpandMap(result)
 		}
+
 		m.merged = result
 		m.dirty = false
 	}
@@ -131,6 +140,65 @@ func (m *Manager) mergedData() map[string]any {
 	return m.merged
 }
 
+// SetInterpolation enables expansion of ${VAR} and $VAR references in
+// string settings of the merged configuration. Layer data keeps the
+// references as written. Nil disables interpolation.
+func (m *Manager) SetInterpolation(in *Interpolation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if in != nil {
+		copied := *in
+		in = &copied
+	}
+	m.interpolation = in
+	m.dirty = true
+}
+
+// InterpolationError returns the errors from interpolating the merged
+// configuration, joined with errors.Join, or nil. Errors are only reported
+// in strict mode.
+func (m *Manager) InterpolationError() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mergedData()
+	return m.interpErr
+}
+
+// SetMergeStrategy sets how arrays at a setting path are merged across
+// layers. Arrays of SourceBuiltin layers are always replaced. MergeReplace
+// removes any previously declared strategy.
+func (m *Manager) SetMergeStrategy(path string, strategy MergeStrategy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if strategy == MergeReplace {
+		if _, ok := m.strategies[path]; ok {
+			delete(m.strategies, path)
+			m.dirty = true
+		}
+		return
+	}
+
+	if m.strategies == nil {
+		m.strategies = make(map[string]MergeStrategy)
+	}
+	m.strategies[path] = strategy
+	m.dirty = true
+}
+
+// MergeStrategy returns the array merge strategy for a setting path.
+// Paths without a declared strategy use MergeReplace.
+func (m *Manager) MergeStrategy(path string) MergeStrategy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if strategy, ok := m.strategies[path]; ok {
+		return strategy
+	}
+	return MergeReplace
+}
+
 // Get returns the effective value for a setting path.
 // Returns the value, the layer it came from, and whether it was found.
 func (m *Manager) Get(path string) (any, *Layer, bool) {
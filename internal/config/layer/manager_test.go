@@ -1,6 +1,7 @@
 package layer
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -377,3 +378,21 @@ func TestManager_PriorityOrder(t *testing.T) {
 		t.Errorf("value = %v, want 'env' (next highest priority)", val)
 	}
 }
+
+func TestManager_MergeStrategyReplacesBuiltin(t *testing.T) {
+	m := NewManager()
+	m.SetMergeStrategy("exclude", MergeUnique)
+	m.AddLayer(NewLayerWithData("defaults", SourceBuiltin, PriorityBuiltin, map[string]any{"exclude": []string{".git"}}))
+
+	// Without configured layers the default applies
+	if got, _ := m.GetEffectiveValue("exclude"); !reflect.DeepEqual(got, []string{".git"}) {
+		t.Errorf("exclude = %v, want [.git]", got)
+	}
+
+	// The first configured layer replaces the default, later ones extend it
+	m.AddLayer(NewLayerWithData("user", SourceUserGlobal, PriorityUserGlobal, map[string]any{"exclude": []any{"tmp"}}))
+	m.AddLayer(NewLayerWithData("workspace", SourceWorkspace, PriorityWorkspace, map[string]any{"exclude": []any{"build", "tmp"}}))
+	if got, _ := m.GetEffectiveValue("exclude"); !reflect.DeepEqual(got, []any{"tmp", "build"}) {
+		t.Errorf("exclude = %v, want [tmp build]", got)
+	}
+}
@@ -1,11 +1,55 @@
 package layer
 
-import "strings"
+import (
+	"reflect"
+	"strings"
+)
+
+// MergeStrategy controls how an array from a higher priority layer is
+// combined with the array from lower priority layers.
+type MergeStrategy string
+
+const (
+	// MergeReplace replaces the lower priority array (the default).
+	MergeReplace MergeStrategy = "replace"
+
+	// MergeAppend appends the higher priority array to the lower one.
+	MergeAppend MergeStrategy = "append"
+
+	// MergePrepend inserts the higher priority array before the lower one.
+	MergePrepend MergeStrategy = "prepend"
+
+	// MergeUnique appends the higher priority elements not already present,
+	// yielding the union of both arrays in order of first appearance.
+	MergeUnique MergeStrategy = "unique"
+)
+
+// IsValid returns true if the strategy is a known merge strategy.
+func (s MergeStrategy) IsValid() bool {
+	switch s {
+	case MergeReplace, MergeAppend, MergePrepend, MergeUnique:
+		return true
+	default:
+		return false
+	}
+}
 
 // DeepMerge recursively merges src into dst.
 // Values in src override values in dst.
 // Maps are merged recursively; other types are replaced.
 func DeepMerge(dst, src map[string]any) map[string]any {
+	return DeepMergeWithStrategies(dst, src, nil)
+}
+
+// DeepMergeWithStrategies recursively merges src into dst like DeepMerge,
+// but arrays at paths listed in strategies (dot-separated, e.g.
+// "files.exclude") are combined using the given strategy instead of being
+// replaced.
+func DeepMergeWithStrategies(dst, src map[string]any, strategies map[string]MergeStrategy) map[string]any {
+	return deepMerge(dst, src, "", strategies)
+}
+
+func deepMerge(dst, src map[string]any, prefix string, strategies map[string]MergeStrategy) map[string]any {
 	if dst == nil {
 		dst = make(map[string]any)
 	}
@@ -20,20 +64,83 @@ func DeepMerge(dst, src map[string]any) map[string]any {
 			continue
 		}
 
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
 		// If both are maps, merge recursively
 		srcMap, srcIsMap := srcVal.(map[string]any)
 		dstMap, dstIsMap := dstVal.(map[string]any)
 		if srcIsMap && dstIsMap {
-			dst[key] = DeepMerge(dstMap, srcMap)
-		} else {
-			// Otherwise, src replaces dst
-			dst[key] = cloneValue(srcVal)
+			dst[key] = deepMerge(dstMap, srcMap, path, strategies)
+			continue
 		}
+
+		// Arrays with a declared strategy are combined
+		if strategy, ok := strategies[path]; ok && strategy != MergeReplace {
+			srcSlice, srcIsSlice := toSlice(srcVal)
+			dstSlice, dstIsSlice := toSlice(dstVal)
+			if srcIsSlice && dstIsSlice {
+				dst[key] = mergeSlices(dstSlice, srcSlice, strategy)
+				continue
+			}
+		}
+
+		// Otherwise, src replaces dst
+		dst[key] = cloneValue(srcVal)
 	}
 
 	return dst
 }
 
+// mergeSlices combines two arrays according to a merge strategy.
+func mergeSlices(dst, src []any, strategy MergeStrategy) []any {
+	switch strategy {
+	case MergeAppend:
+		return append(cloneSlice(dst), cloneSlice(src)...)
+	case MergePrepend:
+		return append(cloneSlice(src), cloneSlice(dst)...)
+	case MergeUnique:
+		result := cloneSlice(dst)
+		for _, v := range src {
+			if !containsValue(result, v) {
+				result = append(result, cloneValue(v))
+			}
+		}
+		return result
+	default:
+		return cloneSlice(src)
+	}
+}
+
+// toSlice converts array values to []any. Layers loaded from files hold
+// []any, while built-in defaults may use []string.
+func toSlice(val any) ([]any, bool) {
+	switch v := val.(type) {
+	case []any:
+		return v, true
+	case []string:
+		result := make([]any, len(v))
+		for i, s := range v {
+			result[i] = s
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+// containsValue returns true if values contains a value equal to v.
+func containsValue(values []any, v any) bool {
+	for _, existing := range values {
+		if valuesEqual(existing, v) {
+			return true
+		}
+	}
+	return false
+}
+
 // cloneValue creates a deep copy of a value.
 func cloneValue(val any) any {
 	switch v := val.(type) {
@@ -211,7 +318,8 @@ func valuesEqual(a, b any) bool {
 		}
 		return slicesEqual(va, vb)
 	default:
-		return a == b
+		// Typed slices, such as []string, are not comparable with ==
+		return reflect.DeepEqual(a, b)
 	}
 }
 
@@ -134,6 +134,40 @@ func TestDeepMerge(t *testing.T) {
 	}
 }
 
+func TestDeepMergeWithStrategies(t *testing.T) {
+	user := map[string]any{"files": map[string]any{"exclude": []any{"a", "b"}}}
+	project := map[string]any{"files": map[string]any{"exclude": []any{"b", "c"}}}
+
+	tests := []struct {
+		strategy MergeStrategy
+		expected []any
+	}{
+		{MergeReplace, []any{"b", "c"}},
+		{MergeAppend, []any{"a", "b", "b", "c"}},
+		{MergePrepend, []any{"b", "c", "a", "b"}},
+		{MergeUnique, []any{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			strategies := map[string]MergeStrategy{"files.exclude": tt.strategy}
+			result := DeepMergeWithStrategies(cloneMap(user), project, strategies)
+
+			got, _ := GetByPath(result, "files.exclude")
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("files.exclude = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	// Built-in []string defaults combine with []any layer values
+	defaults := map[string]any{"exclude": []string{".git"}}
+	result := DeepMergeWithStrategies(defaults, map[string]any{"exclude": []any{"dist"}}, map[string]MergeStrategy{"exclude": MergeAppend})
+	if got := result["exclude"]; !reflect.DeepEqual(got, []any{".git", "dist"}) {
+		t.Errorf("exclude = %v, want [.git dist]", got)
+	}
+}
+
 func TestGetByPath(t *testing.T) {
 	data := map[string]any{
 		"editor": map[string]any{
@@ -361,6 +395,8 @@ func TestValuesEqual(t *testing.T) {
 		{"same slice", []any{1, 2}, []any{1, 2}, true},
 		{"different slice", []any{1, 2}, []any{1, 3}, false},
 		{"different length slice", []any{1}, []any{1, 2}, false},
+		{"same string slice", []string{"a"}, []string{"a"}, true},
+		{"different string slice", []string{"a"}, []string{"b"}, false},
 	}
 
 	for _, tt := range tests {
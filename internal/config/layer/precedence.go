@@ -12,6 +12,10 @@ const (
 	// PriorityUserKeymaps is for user keymap settings.
 	PriorityUserKeymaps = 150
 
+	// PriorityProfile is for the active configuration profile, which
+	// overrides user settings but not workspace settings.
+	PriorityProfile = 175
+
 	// PriorityWorkspace is for workspace/project settings (.keystorm/).
 	PriorityWorkspace = 200
 
@@ -27,6 +31,9 @@ const (
 	// PriorityArgs is for command-line argument overrides.
 	PriorityArgs = 600
 
+	// PriorityRuntime is for overrides set by editor commands at runtime.
+	PriorityRuntime = 900
+
 	// PrioritySession is the highest priority for session overrides.
 	PrioritySession = 1000
 )
@@ -50,6 +57,10 @@ func DefaultPriority(source Source) int {
 		return PriorityPlugin
 	case SourceSession:
 		return PrioritySession
+	case SourceProfile:
+		return PriorityProfile
+	case SourceRuntime:
+		return PriorityRuntime
 	default:
 		return PriorityBuiltin
 	}
@@ -65,6 +76,8 @@ var StandardLayerNames = map[Source]string{
 	SourceArgs:       "arguments",
 	SourcePlugin:     "plugin",
 	SourceSession:    "session",
+	SourceProfile:    "profile",
+	SourceRuntime:    "runtime",
 }
 
 // StandardLayerName returns the standard name for a source.
@@ -2,9 +2,12 @@
 package config
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
+	"github.com/dshills/keystorm/internal/config/layer"
 	"github.com/dshills/keystorm/internal/config/notify"
 	"github.com/dshills/keystorm/internal/config/schema"
 )
@@ -69,6 +72,18 @@ func (m *PluginManager) RegisterPlugin(name string, schemaJSON []byte) error {
 		if err != nil {
 			return err
 		}
+
+		// Plugin settings live under plugins.<name>.settings in the layers
+		if m.config != nil {
+			if errs := m.config.registerMergeStrategies(pluginSettingsPath(name), s); len(errs) > 0 {
+				paths := make([]string, 0, len(errs))
+				for path := range errs {
+					paths = append(paths, path)
+				}
+				sort.Strings(paths)
+				return fmt.Errorf("%s: %w", paths[0], errs[paths[0]])
+			}
+		}
 		m.schemas[name] = s
 	}
 
@@ -87,6 +102,13 @@ func (m *PluginManager) UnregisterPlugin(name string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if s, ok := m.schemas[name]; ok && m.config != nil {
+		prefix := pluginSettingsPath(name)
+		for path := range s.MergeStrategies() {
+			m.config.layers.SetMergeStrategy(prefix+"."+path, layer.MergeReplace)
+		}
+	}
+
 	delete(m.schemas, name)
 	delete(m.plugins, name)
 }
@@ -429,3 +451,8 @@ func ParsePluginPath(path string) (pluginName, settingKey string) {
 
 	return pluginName, settingKey
 }
+
+// pluginSettingsPath returns the config path holding a plugin's settings.
+func pluginSettingsPath(name string) string {
+	return "plugins." + name + ".settings"
+}
@@ -2,11 +2,13 @@ package config
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"sync"
 	"testing"
 
+	"github.com/dshills/keystorm/internal/config/layer"
 	"github.com/dshills/keystorm/internal/config/notify"
 )
 
@@ -68,6 +70,36 @@ func TestPluginManager_RegisterPluginWithSchema(t *testing.T) {
 	}
 }
 
+func TestPluginManager_RegisterPluginMergeStrategy(t *testing.T) {
+	c := New(WithWatcher(false))
+	defer c.Close()
+
+	pm := c.Plugins()
+
+	schemaJSON := []byte(`{
+		"type": "object",
+		"properties": {
+			"paths": {"type": "array", "x-merge-strategy": "append"}
+		}
+	}`)
+	if err := pm.RegisterPlugin("lint", schemaJSON); err != nil {
+		t.Fatalf("RegisterPlugin() error = %v", err)
+	}
+	if got := c.MergeStrategy("plugins.lint.settings.paths"); got != layer.MergeAppend {
+		t.Errorf("MergeStrategy() = %q, want append", got)
+	}
+
+	pm.UnregisterPlugin("lint")
+	if got := c.MergeStrategy("plugins.lint.settings.paths"); got != layer.MergeReplace {
+		t.Errorf("MergeStrategy() after unregister = %q, want replace", got)
+	}
+
+	badSchema := []byte(`{"properties": {"paths": {"x-merge-strategy": "merge"}}}`)
+	if err := pm.RegisterPlugin("bad", badSchema); !errors.Is(err, ErrInvalidMergeStrategy) {
+		t.Errorf("RegisterPlugin() error = %v, want ErrInvalidMergeStrategy", err)
+	}
+}
+
 func TestPluginManager_RegisterPluginInvalidSchema(t *testing.T) {
 	c := New(WithWatcher(false))
 	defer c.Close()
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dshills/keystorm/internal/config/layer"
+)
+
+// profileLayerName is the name of the layer holding the active profile.
+const profileLayerName = "profile"
+
+// activeProfileFile is the file in the user config directory recording the
+// active profile across restarts.
+const activeProfileFile = "profile"
+
+// DefineProfile defines, or redefines, a named configuration profile such
+// as "work" or "presentation". Overrides map setting paths, such as
+// "ui.fontSize", to values. If the profile is active, its new overrides
+// take effect immediately.
+func (c *Config) DefineProfile(name string, overrides map[string]any) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty profile name", ErrInvalidPath)
+	}
+
+	data := make(map[string]any)
+	for path, value := range overrides {
+		if err := setPath(data, path, value); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.validator != nil {
+		for path, value := range layer.FlattenMap(data) {
+			if err := c.validator.ValidatePath(path, value); err != nil {
+				return fmt.Errorf("profile %q: %w", name, err)
+			}
+		}
+	}
+
+	if c.profiles == nil {
+		c.profiles = make(map[string]map[string]any)
+	}
+	c.profiles[name] = data
+
+	// A redefined active profile, or one restored from a previous
+	// session, applies as soon as it is defined
+	if c.activeProfile == name {
+		c.applyProfileLocked(name, data)
+	}
+	return nil
+}
+
+// ActivateProfile makes the named profile the active one, replacing any
+// other active profile, and records the choice in the user config
+// directory. An empty name deactivates profiles. Observers are notified of
+// every setting whose effective value changes.
+func (c *Config) ActivateProfile(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var data map[string]any
+	if name != "" {
+		var ok bool
+		if data, ok = c.profiles[name]; !ok {
+			return fmt.Errorf("%w: %q", ErrProfileNotFound, name)
+		}
+	}
+
+	if err := c.saveActiveProfile(name); err != nil {
+		return err
+	}
+	c.activeProfile = name
+	c.applyProfileLocked(name, data)
+	return nil
+}
+
+// ActiveProfile returns the name of the active profile, or "" if none is
+// active. A profile restored from a previous session is reported before it
+// is defined, but takes effect only once DefineProfile is called for it.
+func (c *Config) ActiveProfile() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.activeProfile
+}
+
+// Profiles returns the names of the defined profiles, sorted.
+func (c *Config) Profiles() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.profiles))
+	for name := range c.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfileLocked replaces the profile layer with data, removing it if
+// data is nil, and notifies observers of the settings that changed.
+// The caller must hold c.mu.
+func (c *Config) applyProfileLocked(name string, data map[string]any) {
+	oldMerged := c.layers.Merge()
+	c.setProfileLayer(data)
+	newMerged := c.layers.Merge()
+	c.notifyDiff(oldMerged, newMerged, "profile:"+name)
+}
+
+// setProfileLayer replaces the profile layer with a copy of data, removing
+// it if data is nil.
+func (c *Config) setProfileLayer(data map[string]any) {
+	c.layers.RemoveLayer(profileLayerName)
+	if data == nil {
+		return
+	}
+	l := layer.NewLayerWithData(profileLayerName, layer.SourceProfile, layer.PriorityProfile, data).Clone()
+	l.ReadOnly = true
+	c.layers.AddLayer(l)
+}
+
+// notifyDiff notifies observers of the settings that differ between two
+// merged configurations, in path order.
+func (c *Config) notifyDiff(oldMerged, newMerged map[string]any, source string) {
+	added, modified, removed := layer.DiffMaps(oldMerged, newMerged)
+
+	changed := append(added, modified...)
+	sort.Strings(changed)
+	for _, path := range changed {
+		oldValue, _ := getPath(oldMerged, path)
+		newValue, _ := getPath(newMerged, path)
+		c.notifier.NotifySet(path, c.redact(path, oldValue), c.redact(path, newValue), source)
+	}
+
+	sort.Strings(removed)
+	for _, path := range removed {
+		oldValue, _ := getPath(oldMerged, path)
+		c.notifier.NotifyDelete(path, c.redact(path, oldValue), source)
+	}
+}
+
+// loadActiveProfile restores the active profile recorded by
+// ActivateProfile. The profile takes effect once it is defined.
+func (c *Config) loadActiveProfile() error {
+	content, err := os.ReadFile(filepath.Join(c.userConfigDir, activeProfileFile))
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimSpace(string(content))
+	if c.activeProfile == "" {
+		c.activeProfile = name
+	}
+	if data, ok := c.profiles[c.activeProfile]; ok {
+		c.setProfileLayer(data)
+	}
+	return nil
+}
+
+// saveActiveProfile records the active profile in the user config
+// directory, removing the record if name is empty.
+func (c *Config) saveActiveProfile(name string) error {
+	path := filepath.Join(c.userConfigDir, activeProfileFile)
+	if name == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("clearing active profile: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(c.userConfigDir, 0o755); err != nil {
+		return fmt.Errorf("saving active profile: %w", err)
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, []byte(name+"\n"), 0o644); err != nil {
+		return fmt.Errorf("saving active profile: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("saving active profile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/config/notify"
+)
+
+func newProfileTestConfig(t *testing.T, dir string) *Config {
+	t.Helper()
+
+	settingsPath := filepath.Join(dir, "settings.toml")
+	if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
+		if err := os.WriteFile(settingsPath, []byte("[editor]\ntabSize = 2\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := New(WithUserConfigDir(dir), WithWatcher(false))
+	t.Cleanup(c.Close)
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if err := c.DefineProfile("work", map[string]any{
+		"editor.tabSize": 8,
+		"ui.theme":       "light",
+	}); err != nil {
+		t.Fatalf("DefineProfile(work) error = %v", err)
+	}
+	if err := c.DefineProfile("presentation", map[string]any{
+		"ui.fontSize": 24,
+		"ui.theme":    "light",
+	}); err != nil {
+		t.Fatalf("DefineProfile(presentation) error = %v", err)
+	}
+	return c
+}
+
+func TestConfig_ActivateProfile(t *testing.T) {
+	c := newProfileTestConfig(t, t.TempDir())
+
+	var changes []notify.Change
+	sub := c.Subscribe(func(change notify.Change) {
+		changes = append(changes, change)
+	})
+	defer sub.Unsubscribe()
+
+	changedPaths := func() []string {
+		paths := make([]string, len(changes))
+		for i, change := range changes {
+			paths[i] = change.Path
+		}
+		sort.Strings(paths)
+		changes = nil
+		return paths
+	}
+	assertPaths := func(step string, want ...string) {
+		t.Helper()
+		got := changedPaths()
+		if len(got) != len(want) {
+			t.Fatalf("%s: changed paths = %v, want %v", step, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("%s: changed paths = %v, want %v", step, got, want)
+			}
+		}
+	}
+
+	if err := c.ActivateProfile("work"); err != nil {
+		t.Fatalf("ActivateProfile(work) error = %v", err)
+	}
+	assertPaths("activate work", "editor.tabSize", "ui.theme")
+	if v, _ := c.GetInt("editor.tabSize"); v != 8 {
+		t.Errorf("editor.tabSize = %d, want 8", v)
+	}
+
+	// Switching only reports the settings whose effective value differs
+	if err := c.ActivateProfile("presentation"); err != nil {
+		t.Fatalf("ActivateProfile(presentation) error = %v", err)
+	}
+	assertPaths("switch to presentation", "editor.tabSize", "ui.fontSize")
+	if v, _ := c.GetInt("editor.tabSize"); v != 2 {
+		t.Errorf("editor.tabSize = %d, want 2", v)
+	}
+	if v, _ := c.GetInt("ui.fontSize"); v != 24 {
+		t.Errorf("ui.fontSize = %d, want 24", v)
+	}
+	if c.ActiveProfile() != "presentation" {
+		t.Errorf("ActiveProfile() = %q, want %q", c.ActiveProfile(), "presentation")
+	}
+
+	if err := c.ActivateProfile(""); err != nil {
+		t.Fatalf("ActivateProfile(\"\") error = %v", err)
+	}
+	assertPaths("deactivate", "ui.fontSize", "ui.theme")
+	if v, _ := c.GetString("ui.theme"); v != "dark" {
+		t.Errorf("ui.theme = %q, want %q", v, "dark")
+	}
+}
+
+func TestConfig_ActivateProfileNotFound(t *testing.T) {
+	c := newProfileTestConfig(t, t.TempDir())
+
+	if err := c.ActivateProfile("missing"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("ActivateProfile(missing) error = %v, want ErrProfileNotFound", err)
+	}
+	if c.ActiveProfile() != "" {
+		t.Errorf("ActiveProfile() = %q, want empty", c.ActiveProfile())
+	}
+}
+
+func TestConfig_DefineProfileValidation(t *testing.T) {
+	c := newProfileTestConfig(t, t.TempDir())
+
+	if err := c.DefineProfile("bad", map[string]any{"editor.tabSize": 100}); err == nil {
+		t.Error("DefineProfile with out-of-range value should fail")
+	}
+	if got := c.Profiles(); len(got) != 2 || got[0] != "presentation" || got[1] != "work" {
+		t.Errorf("Profiles() = %v, want [presentation work]", got)
+	}
+}
+
+func TestConfig_ProfilePersistence(t *testing.T) {
+	dir := t.TempDir()
+
+	c := newProfileTestConfig(t, dir)
+	if err := c.ActivateProfile("work"); err != nil {
+		t.Fatalf("ActivateProfile(work) error = %v", err)
+	}
+
+	// The choice is restored once the profile is defined again
+	restored := newProfileTestConfig(t, dir)
+	if restored.ActiveProfile() != "work" {
+		t.Errorf("ActiveProfile() = %q, want %q", restored.ActiveProfile(), "work")
+	}
+	if v, _ := restored.GetInt("editor.tabSize"); v != 8 {
+		t.Errorf("editor.tabSize = %d, want 8", v)
+	}
+
+	if err := restored.ActivateProfile(""); err != nil {
+		t.Fatalf("ActivateProfile(\"\") error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, activeProfileFile)); !os.IsNotExist(err) {
+		t.Errorf("active profile record not removed: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/dshills/keystorm/internal/config/registry"
+)
+
+// RedactedValue replaces sensitive values in dumps, explanations and notifications.
+const RedactedValue = "***"
+
+// Explanation describes where a setting's effective value comes from.
+type Explanation struct {
+	// Path is the setting path.
+	Path string
+
+	// Value is the effective value. Sensitive values are redacted.
+	Value any
+
+	// Found indicates whether any layer provides a value.
+	Found bool
+
+	// Layer is the name of the layer providing the value.
+	Layer string
+
+	// Source is the layer source (e.g., "user-global", "workspace").
+	Source string
+
+	// Default is the registered default value. Sensitive defaults are redacted.
+	Default any
+
+	// Description is the registered documentation for the setting.
+	Description string
+
+	// Sensitive indicates the value is a secret.
+	Sensitive bool
+}
+
+// Registry returns the settings registry describing known settings.
+func (c *Config) Registry() *registry.Registry {
+	return c.settings
+}
+
+// GetSecret returns the real value of a string setting, including sensitive
+// ones. It is the only accessor that does not redact sensitive values, so
+// credentials cannot leak through generic Get, dump or log paths.
+func (c *Config) GetSecret(path string) (string, error) {
+	c.mu.RLock()
+	v, ok := getPath(c.layers.Merge(), path)
+	c.mu.RUnlock()
+
+	if !ok {
+		return "", ErrSettingNotFound
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", &TypeError{Path: path, Expected: "string", Actual: typeName(v)}
+	}
+	return s, nil
+}
+
+// Explain reports the effective value of a setting and the layer it comes from.
+// Sensitive values are redacted.
+func (c *Config) Explain(path string) Explanation {
+	exp := Explanation{Path: path}
+
+	if value, ok := c.Get(path); ok {
+		exp.Found = true
+		exp.Value = value
+	}
+	if _, l, ok := c.layers.Get(path); ok {
+		exp.Layer = l.Name
+		exp.Source = l.Source.String()
+	}
+
+	if s := c.settings.Get(path); s != nil {
+		exp.Description = s.Description
+		exp.Sensitive = s.Sensitive
+		exp.Default = s.Default
+		if s.Sensitive {
+			exp.Default = RedactedValue
+		}
+	}
+
+	return exp
+}
+
+// DumpEffective renders the fully merged configuration as TOML with
+// sensitive values redacted.
+func (c *Config) DumpEffective() (string, error) {
+	data, err := toml.Marshal(c.Merged())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// redact replaces sensitive values at or below path with RedactedValue.
+// Map values are modified in place, so callers must pass a copy.
+func (c *Config) redact(path string, value any) any {
+	if c.settings == nil {
+		return value
+	}
+	if c.settings.IsSensitive(path) {
+		return RedactedValue
+	}
+
+	m, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	prefix := ""
+	if path != "" {
+		prefix = path + "."
+	}
+	for _, sp := range c.settings.SensitivePaths() {
+		rel, ok := strings.CutPrefix(sp, prefix)
+		if !ok {
+			continue
+		}
+		if _, exists := getPath(m, rel); exists {
+			_ = setPath(m, rel, RedactedValue)
+		}
+	}
+	return m
+}
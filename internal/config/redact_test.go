@@ -0,0 +1,164 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/config/notify"
+	"github.com/dshills/keystorm/internal/config/registry"
+	"github.com/dshills/keystorm/internal/config/schema"
+)
+
+const testAPIKey = "sk-test-0123456789"
+
+func newSystemWithSecret(t *testing.T) *ConfigSystem {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	content := "[ai]\nprovider = \"openai\"\nopenaiApiKey = \"" + testAPIKey + "\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "settings.toml"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+
+	sys, err := NewConfigSystem(context.Background(),
+		WithSystemUserConfigDir(tmpDir),
+		WithSystemWatcher(false),
+	)
+	if err != nil {
+		t.Fatalf("NewConfigSystem() error = %v", err)
+	}
+	t.Cleanup(sys.Close)
+	return sys
+}
+
+func TestConfigSystem_GetSecret(t *testing.T) {
+	sys := newSystemWithSecret(t)
+
+	got, err := sys.GetSecret("ai.openaiApiKey")
+	if err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	if got != testAPIKey {
+		t.Errorf("GetSecret() = %q, want %q", got, testAPIKey)
+	}
+
+	if _, err := sys.GetSecret("ai.anthropicApiKey"); err != ErrSettingNotFound {
+		t.Errorf("GetSecret(unset) error = %v, want ErrSettingNotFound", err)
+	}
+}
+
+func TestConfigSystem_GetRedactsSensitive(t *testing.T) {
+	sys := newSystemWithSecret(t)
+
+	v, ok := sys.Get("ai.openaiApiKey")
+	if !ok || v != RedactedValue {
+		t.Errorf("Get(sensitive) = %v, %v; want %q, true", v, ok, RedactedValue)
+	}
+
+	section, ok := sys.Get("ai")
+	if !ok {
+		t.Fatal("Get('ai') not found")
+	}
+	ai := section.(map[string]any)
+	if ai["openaiApiKey"] != RedactedValue {
+		t.Errorf("Get('ai').openaiApiKey = %v, want %q", ai["openaiApiKey"], RedactedValue)
+	}
+	if ai["provider"] != "openai" {
+		t.Errorf("Get('ai').provider = %v, want openai", ai["provider"])
+	}
+
+	// Redaction must not leak into the stored value
+	if got, _ := sys.GetSecret("ai.openaiApiKey"); got != testAPIKey {
+		t.Errorf("GetSecret() after Get = %q, want %q", got, testAPIKey)
+	}
+}
+
+func TestConfigSystem_ExplainRedacts(t *testing.T) {
+	sys := newSystemWithSecret(t)
+
+	exp := sys.Explain("ai.openaiApiKey")
+	if !exp.Found {
+		t.Fatal("Explain() Found = false")
+	}
+	if !exp.Sensitive {
+		t.Error("Explain() Sensitive = false")
+	}
+	if exp.Value != RedactedValue {
+		t.Errorf("Explain() Value = %v, want %q", exp.Value, RedactedValue)
+	}
+	if exp.Layer != "user-settings" {
+		t.Errorf("Explain() Layer = %q, want user-settings", exp.Layer)
+	}
+
+	exp = sys.Explain("ai.provider")
+	if exp.Sensitive || exp.Value != "openai" {
+		t.Errorf("Explain(provider) = %+v", exp)
+	}
+}
+
+func TestConfigSystem_DumpEffectiveRedacts(t *testing.T) {
+	sys := newSystemWithSecret(t)
+
+	dump, err := sys.DumpEffective()
+	if err != nil {
+		t.Fatalf("DumpEffective() error = %v", err)
+	}
+	if strings.Contains(dump, testAPIKey) {
+		t.Error("DumpEffective() leaked the API key")
+	}
+	if !strings.Contains(dump, RedactedValue) {
+		t.Error("DumpEffective() missing redaction marker")
+	}
+	if !strings.Contains(dump, "provider") {
+		t.Error("DumpEffective() missing non-sensitive settings")
+	}
+}
+
+func TestConfig_SetNotifiesRedacted(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "settings.toml"), []byte("[ai]\nprovider = \"anthropic\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(
+		WithUserConfigDir(tmpDir),
+		WithWatcher(false),
+		WithSchemaValidation(false),
+	)
+	defer c.Close()
+	_ = c.Load(context.Background())
+
+	var got notify.Change
+	sub := c.SubscribePath("ai.anthropicApiKey", func(ch notify.Change) {
+		got = ch
+	})
+	defer sub.Unsubscribe()
+
+	if err := c.Set("ai.anthropicApiKey", testAPIKey); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got.NewValue != RedactedValue {
+		t.Errorf("notification NewValue = %v, want %q", got.NewValue, RedactedValue)
+	}
+}
+
+func TestSchemaSensitiveMatchesRegistry(t *testing.T) {
+	s, err := schema.LoadEmbedded()
+	if err != nil {
+		t.Fatalf("LoadEmbedded() error = %v", err)
+	}
+
+	want := registry.NewWithDefaults().SensitivePaths()
+	got := s.SensitivePaths()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("schema sensitive paths = %v, registry has %v", got, want)
+	}
+
+	c := New(WithWatcher(false))
+	for path, err := range c.ConfigErrors() {
+		t.Errorf("ConfigErrors()[%q] = %v", path, err)
+	}
+}
@@ -0,0 +1,166 @@
+package registry
+
+import "sort"
+
+// SettingDescriptor describes a registered setting for settings editors:
+// enough to pick a widget, label it, and validate input before it is set.
+// A descriptor is a copy and does not change with the registry.
+type SettingDescriptor struct {
+	// Path is the dot-separated path (e.g., "editor.tabSize").
+	Path string
+
+	// Section is the top-level section the setting is grouped under
+	// (e.g., "editor").
+	Section string
+
+	// Type is the setting's data type.
+	Type SettingType
+
+	// Default is the default value.
+	Default any
+
+	// Description is human-readable documentation.
+	Description string
+
+	// Scope defines where this setting can be set.
+	Scope SettingScope
+
+	// Choices lists the allowed values of enum settings.
+	Choices []any
+
+	// Minimum and Maximum bound numeric settings (nil means unbounded).
+	Minimum *float64
+	Maximum *float64
+
+	// Pattern is the regex string values must match, if any.
+	Pattern string
+
+	// Deprecated marks settings that should be migrated.
+	Deprecated        bool
+	DeprecatedMessage string
+	ReplacedBy        string
+
+	// Tags for filtering/grouping settings.
+	Tags []string
+
+	// Sensitive marks credentials and other secrets that editors should
+	// mask.
+	Sensitive bool
+}
+
+// HasRange returns true if the setting has a minimum or maximum.
+func (d SettingDescriptor) HasRange() bool {
+	return d.Minimum != nil || d.Maximum != nil
+}
+
+// Validate checks if a value is valid for the described setting.
+func (d SettingDescriptor) Validate(value any) error {
+	s := Setting{
+		Path:    d.Path,
+		Type:    d.Type,
+		Enum:    d.Choices,
+		Minimum: d.Minimum,
+		Maximum: d.Maximum,
+		Pattern: d.Pattern,
+	}
+	return s.Validate(value)
+}
+
+// SettingGroup is the settings of one section, as shown together in a
+// settings editor.
+type SettingGroup struct {
+	// Section is the section name (e.g., "editor").
+	Section string
+
+	// Settings are the section's settings sorted by path.
+	Settings []SettingDescriptor
+}
+
+// Describe returns the descriptor of the setting at path.
+// Returns false if the setting is not registered.
+func (r *Registry) Describe(path string) (SettingDescriptor, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.settings[path]
+	if !ok {
+		return SettingDescriptor{}, false
+	}
+	return describe(s), true
+}
+
+// All returns the descriptors of all registered settings sorted by path.
+func (r *Registry) All() []SettingDescriptor {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]SettingDescriptor, 0, len(r.settings))
+	for _, s := range r.settings {
+		result = append(result, describe(s))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result
+}
+
+// Groups returns the descriptors of all registered settings grouped by
+// section. Groups are sorted by section and settings by path.
+func (r *Registry) Groups() []SettingGroup {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]SettingGroup, 0, len(r.sections))
+	for section, settings := range r.sections {
+		group := SettingGroup{
+			Section:  section,
+			Settings: make([]SettingDescriptor, 0, len(settings)),
+		}
+		for _, s := range settings {
+			group.Settings = append(group.Settings, describe(s))
+		}
+		sort.Slice(group.Settings, func(i, j int) bool {
+			return group.Settings[i].Path < group.Settings[j].Path
+		})
+		result = append(result, group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Section < result[j].Section
+	})
+
+	return result
+}
+
+// describe returns the descriptor of s. Slices and bounds are copied so
+// that callers cannot modify the registered definition.
+func describe(s *Setting) SettingDescriptor {
+	d := SettingDescriptor{
+		Path:              s.Path,
+		Section:           extractSection(s.Path),
+		Type:              s.Type,
+		Default:           s.Default,
+		Description:       s.Description,
+		Scope:             s.Scope,
+		Pattern:           s.Pattern,
+		Deprecated:        s.Deprecated,
+		DeprecatedMessage: s.DeprecatedMessage,
+		ReplacedBy:        s.ReplacedBy,
+		Sensitive:         s.Sensitive,
+	}
+	if len(s.Enum) > 0 {
+		d.Choices = append([]any(nil), s.Enum...)
+	}
+	if s.Minimum != nil {
+		d.Minimum = MinValue(*s.Minimum)
+	}
+	if s.Maximum != nil {
+		d.Maximum = MaxValue(*s.Maximum)
+	}
+	if len(s.Tags) > 0 {
+		d.Tags = append([]string(nil), s.Tags...)
+	}
+	return d
+}
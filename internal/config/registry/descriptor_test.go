@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegistry_DescribeEnum(t *testing.T) {
+	r := NewWithDefaults()
+
+	d, ok := r.Describe("editor.wordWrap")
+	if !ok {
+		t.Fatal("Describe(editor.wordWrap) not found")
+	}
+	if d.Type != TypeEnum {
+		t.Errorf("Type = %v, want enum", d.Type)
+	}
+	want := []any{"off", "on", "wordWrapColumn", "bounded"}
+	if !reflect.DeepEqual(d.Choices, want) {
+		t.Errorf("Choices = %v, want %v", d.Choices, want)
+	}
+	if d.Default != "off" || d.Section != "editor" {
+		t.Errorf("Default = %v, Section = %q, want off, editor", d.Default, d.Section)
+	}
+	if d.HasRange() {
+		t.Error("HasRange() = true for an enum setting")
+	}
+	if err := d.Validate("sideways"); err == nil {
+		t.Error("Validate(sideways) should fail")
+	}
+
+	// Descriptors are copies
+	d.Choices[0] = "changed"
+	if again, _ := r.Describe("editor.wordWrap"); again.Choices[0] != "off" {
+		t.Errorf("registered choices changed to %v", again.Choices)
+	}
+}
+
+func TestRegistry_DescribeRange(t *testing.T) {
+	r := NewWithDefaults()
+
+	d, ok := r.Describe("editor.tabSize")
+	if !ok {
+		t.Fatal("Describe(editor.tabSize) not found")
+	}
+	if !d.HasRange() || d.Minimum == nil || d.Maximum == nil {
+		t.Fatalf("range = %v..%v, want bounds", d.Minimum, d.Maximum)
+	}
+	if *d.Minimum != 1 || *d.Maximum != 16 {
+		t.Errorf("range = %v..%v, want 1..16", *d.Minimum, *d.Maximum)
+	}
+	if err := d.Validate(17); err == nil {
+		t.Error("Validate(17) should fail")
+	}
+	if err := d.Validate(8); err != nil {
+		t.Errorf("Validate(8) error = %v", err)
+	}
+
+	if _, ok := r.Describe("editor.unknown"); ok {
+		t.Error("Describe(editor.unknown) should not be found")
+	}
+}
+
+func TestRegistry_Groups(t *testing.T) {
+	r := New()
+	r.MustRegister(Setting{Path: "ui.theme", Type: TypeString})
+	r.MustRegister(Setting{Path: "editor.tabSize", Type: TypeInt})
+	r.MustRegister(Setting{Path: "editor.insertSpaces", Type: TypeBool})
+
+	groups := r.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("len(Groups()) = %d, want 2", len(groups))
+	}
+	if groups[0].Section != "editor" || groups[1].Section != "ui" {
+		t.Errorf("sections = %q, %q, want editor, ui", groups[0].Section, groups[1].Section)
+	}
+	editor := groups[0].Settings
+	if len(editor) != 2 || editor[0].Path != "editor.insertSpaces" || editor[1].Path != "editor.tabSize" {
+		t.Errorf("editor settings = %v, want insertSpaces, tabSize", editor)
+	}
+}
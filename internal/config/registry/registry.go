@@ -74,20 +74,40 @@ func (r *Registry) Has(path string) bool {
 	return exists
 }
 
-// All returns all registered settings sorted by path.
-func (r *Registry) All() []*Setting {
+// IsSensitive returns true if the setting at path is marked sensitive.
+func (r *Registry) IsSensitive(path string) bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	s := r.settings[path]
+	return s != nil && s.Sensitive
+}
 
-	result := make([]*Setting, 0, len(r.settings))
-	for _, s := range r.settings {
-		result = append(result, s)
+// MarkSensitive marks the registered setting at path as sensitive.
+// It returns ErrSettingNotFound if no setting is registered at path.
+func (r *Registry) MarkSensitive(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.settings[path]
+	if s == nil {
+		return fmt.Errorf("%w: %s", ErrSettingNotFound, path)
 	}
+	s.Sensitive = true
+	return nil
+}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Path < result[j].Path
-	})
+// SensitivePaths returns the paths of all sensitive settings, sorted.
+func (r *Registry) SensitivePaths() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
+	var result []string
+	for path, s := range r.settings {
+		if s.Sensitive {
+			result = append(result, path)
+		}
+	}
+	sort.Strings(result)
 	return result
 }
 
@@ -493,6 +513,15 @@ func (r *Registry) RegisterDefaults() {
 		Tags:        []string{"files", "formatting"},
 	})
 
+	r.MustRegister(Setting{
+		Path:        "files.tabsToSpaces",
+		Type:        TypeBool,
+		Default:     false,
+		Description: "Expand tabs in leading indentation when saving",
+		Scope:       ScopeAll,
+		Tags:        []string{"files", "formatting"},
+	})
+
 	r.MustRegister(Setting{
 		Path:        "files.autoSave",
 		Type:        TypeEnum,
@@ -533,4 +562,25 @@ func (r *Registry) RegisterDefaults() {
 		Scope:       ScopeGlobal,
 		Tags:        []string{"logging"},
 	})
+
+	// AI settings
+	r.MustRegister(Setting{
+		Path:        "ai.openaiApiKey",
+		Type:        TypeString,
+		Default:     "",
+		Description: "OpenAI API key",
+		Scope:       ScopeGlobal,
+		Tags:        []string{"ai", "secret"},
+		Sensitive:   true,
+	})
+
+	r.MustRegister(Setting{
+		Path:        "ai.anthropicApiKey",
+		Type:        TypeString,
+		Default:     "",
+		Description: "Anthropic API key",
+		Scope:       ScopeGlobal,
+		Tags:        []string{"ai", "secret"},
+		Sensitive:   true,
+	})
 }
@@ -2,7 +2,8 @@
 //
 // The registry maintains definitions of all known settings with their types,
 // defaults, validation rules, and metadata. It provides type-safe access
-// to settings values.
+// to settings values, and describes settings to settings editors through
+// Describe, All and Groups.
 package registry
 
 import (
@@ -48,6 +49,10 @@ type Setting struct {
 	// Tags for filtering/grouping settings.
 	Tags []string
 
+	// Sensitive marks credentials and other secrets. Sensitive values are
+	// redacted from dumps, explanations, and change notifications.
+	Sensitive bool
+
 	// compiledPattern is the compiled regex pattern (lazily initialized).
 	compiledPattern *regexp.Regexp
 }
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"github.com/dshills/keystorm/internal/config/layer"
+)
+
+// runtimeLayerName is the name of the layer holding runtime overrides.
+const runtimeLayerName = "runtime"
+
+// SetRuntime sets an in-memory runtime override, such as a setting toggled
+// by a command. Runtime overrides take precedence over the configuration
+// files, environment and arguments, are never written to disk and survive
+// reloads of the configuration files, so saving settings.toml does not
+// revert them.
+func (c *Config) SetRuntime(path string, value any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.validator != nil {
+		if err := c.validator.ValidatePath(path, value); err != nil {
+			return err
+		}
+	}
+
+	runtimeLayer := c.layers.GetLayer(runtimeLayerName)
+	if runtimeLayer == nil {
+		runtimeLayer = layer.NewLayer(runtimeLayerName, layer.SourceRuntime, layer.PriorityRuntime)
+		c.layers.AddLayer(runtimeLayer)
+	}
+	if runtimeLayer.Data == nil {
+		runtimeLayer.Data = make(map[string]any)
+	}
+
+	oldMerged := c.layers.Merge()
+	oldValue, _ := getPath(oldMerged, path)
+
+	if err := setPath(runtimeLayer.Data, path, value); err != nil {
+		return err
+	}
+	c.layers.Invalidate()
+
+	newValue, _ := getPath(c.layers.Merge(), path)
+	c.notifier.NotifySet(path, c.redact(path, oldValue), c.redact(path, newValue), "runtime")
+	return nil
+}
+
+// ClearRuntime removes the runtime override at path, and any overrides
+// below it, so the value from the configuration files applies again.
+// Clearing a path without an override does nothing.
+func (c *Config) ClearRuntime(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(splitPath(path)) == 0 {
+		return ErrInvalidPath
+	}
+	runtimeLayer := c.layers.GetLayer(runtimeLayerName)
+	if runtimeLayer == nil {
+		return nil
+	}
+	if _, ok := getPath(runtimeLayer.Data, path); !ok {
+		return nil
+	}
+
+	oldMerged := c.layers.Merge()
+	if err := c.layers.Delete(runtimeLayerName, path); err != nil {
+		return err
+	}
+	c.notifyDiff(oldMerged, c.layers.Merge(), "runtime")
+	return nil
+}
+
+// RuntimeOverrides returns the runtime overrides keyed by setting path,
+// with sensitive values redacted.
+func (c *Config) RuntimeOverrides() map[string]any {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	overrides := make(map[string]any)
+	if runtimeLayer := c.layers.GetLayer(runtimeLayerName); runtimeLayer != nil {
+		for path, value := range layer.FlattenMap(runtimeLayer.Data) {
+			overrides[path] = c.redact(path, value)
+		}
+	}
+	return overrides
+}
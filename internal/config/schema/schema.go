@@ -8,6 +8,7 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -121,6 +122,14 @@ type Schema struct {
 
 	// Order for display ordering.
 	Order int `json:"x-order,omitempty"`
+
+	// MergeStrategy controls how array values from higher priority layers
+	// combine with lower priority ones (replace, append, prepend, unique).
+	MergeStrategy string `json:"x-merge-strategy,omitempty"`
+
+	// Sensitive marks credentials and other secrets whose values are
+	// redacted from dumps, explanations and change notifications.
+	Sensitive bool `json:"x-sensitive,omitempty"`
 }
 
 // SchemaType represents JSON Schema type(s).
@@ -261,6 +270,55 @@ func (s *Schema) AllowsAdditionalProperties() bool {
 	return *s.AdditionalProperties
 }
 
+// MergeStrategies returns the declared array merge strategies of all
+// nested properties, keyed by dot-separated path relative to this schema.
+func (s *Schema) MergeStrategies() map[string]string {
+	result := make(map[string]string)
+	s.collectMergeStrategies("", result)
+	return result
+}
+
+func (s *Schema) collectMergeStrategies(prefix string, result map[string]string) {
+	if s == nil {
+		return
+	}
+	if prefix != "" && s.MergeStrategy != "" {
+		result[prefix] = s.MergeStrategy
+	}
+	for name, prop := range s.Properties {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		prop.collectMergeStrategies(path, result)
+	}
+}
+
+// SensitivePaths returns the dot-separated paths of all nested properties
+// marked sensitive, relative to this schema, sorted.
+func (s *Schema) SensitivePaths() []string {
+	var result []string
+	s.collectSensitivePaths("", &result)
+	sort.Strings(result)
+	return result
+}
+
+func (s *Schema) collectSensitivePaths(prefix string, result *[]string) {
+	if s == nil {
+		return
+	}
+	if prefix != "" && s.Sensitive {
+		*result = append(*result, prefix)
+	}
+	for name, prop := range s.Properties {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		prop.collectSensitivePaths(path, result)
+	}
+}
+
 // splitPath splits a dot-separated path into parts.
 func splitPath(path string) []string {
 	if path == "" {
@@ -30,6 +30,14 @@ const (
 	ScopeAll       = "all"
 )
 
+// Merge strategy constants for array settings (x-merge-strategy).
+const (
+	MergeStrategyReplace = "replace"
+	MergeStrategyAppend  = "append"
+	MergeStrategyPrepend = "prepend"
+	MergeStrategyUnique  = "unique"
+)
+
 // Builder provides a fluent API for constructing schemas.
 type Builder struct {
 	schema *Schema
@@ -231,6 +239,12 @@ func (b *Builder) Scope(scope string) *Builder {
 	return b
 }
 
+// MergeStrategy sets how array values are merged across config layers.
+func (b *Builder) MergeStrategy(strategy string) *Builder {
+	b.schema.MergeStrategy = strategy
+	return b
+}
+
 // Tags sets categorization tags.
 func (b *Builder) Tags(tags ...string) *Builder {
 	b.schema.Tags = tags
@@ -108,6 +108,9 @@ type FilesConfig struct {
 	// InsertFinalNewline inserts a final newline at end of file when saving.
 	InsertFinalNewline bool
 
+	// TabsToSpaces expands tabs in leading indentation when saving.
+	TabsToSpaces bool
+
 	// AutoSave controls auto-save behavior ("off", "afterDelay", "onFocusChange", "onWindowChange").
 	AutoSave string
 
@@ -126,6 +129,10 @@ type SearchConfig struct {
 	// CaseSensitive enables case-sensitive search.
 	CaseSensitive bool
 
+	// SmartCase makes searches case-sensitive only when the pattern
+	// contains an uppercase letter, overriding CaseSensitive.
+	SmartCase bool
+
 	// WholeWord matches whole words only.
 	WholeWord bool
 
@@ -188,6 +195,9 @@ type TerminalConfig struct {
 
 	// Scrollback is the number of scrollback lines.
 	Scrollback int
+
+	// Bell is the response to the terminal bell ("audible", "visual", "ignore").
+	Bell string
 }
 
 // LSPConfig provides type-safe access to Language Server Protocol settings.
@@ -277,6 +287,7 @@ func (c *Config) Files() FilesConfig {
 		EOL:                    c.getStringOr("files.eol", "lf"),
 		TrimTrailingWhitespace: c.getBoolOr("files.trimTrailingWhitespace", false),
 		InsertFinalNewline:     c.getBoolOr("files.insertFinalNewline", true),
+		TabsToSpaces:           c.getBoolOr("files.tabsToSpaces", false),
 		AutoSave:               c.getStringOr("files.autoSave", "off"),
 		AutoSaveDelay:          c.getIntOr("files.autoSaveDelay", 1000),
 		Exclude:                c.getStringSliceOr("files.exclude", []string{".git", "node_modules", ".DS_Store"}),
@@ -284,10 +295,46 @@ func (c *Config) Files() FilesConfig {
 	}
 }
 
+// LanguageBools returns the per-language overrides of a boolean setting,
+// keyed by language ID. Overrides are set in the "languages" table under
+// the setting's path, e.g. languages.go.files.trimTrailingWhitespace.
+// Overrides that are not booleans are recorded as config errors.
+func (c *Config) LanguageBools(path string) map[string]bool {
+	v, ok := c.Get("languages")
+	if !ok {
+		return nil
+	}
+	languages, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]bool)
+	for language, settings := range languages {
+		m, ok := settings.(map[string]any)
+		if !ok {
+			continue
+		}
+		v, ok := getPath(m, path)
+		if !ok {
+			continue
+		}
+		b, ok := v.(bool)
+		if !ok {
+			overridePath := "languages." + language + "." + path
+			c.recordConfigError(overridePath, &TypeError{Path: overridePath, Expected: "bool", Actual: typeName(v)})
+			continue
+		}
+		result[language] = b
+	}
+	return result
+}
+
 // Search returns type-safe access to search settings.
 func (c *Config) Search() SearchConfig {
 	return SearchConfig{
 		CaseSensitive: c.getBoolOr("search.caseSensitive", false),
+		SmartCase:     c.getBoolOr("search.smartCase", false),
 		WholeWord:     c.getBoolOr("search.wholeWord", false),
 		Regex:         c.getBoolOr("search.regex", false),
 		MaxResults:    c.getIntOr("search.maxResults", 1000),
@@ -324,6 +371,7 @@ func (c *Config) Terminal() TerminalConfig {
 		FontFamily:  c.getStringOr("terminal.fontFamily", "monospace"),
 		CursorStyle: c.getStringOr("terminal.cursorStyle", "block"),
 		Scrollback:  c.getIntOr("terminal.scrollback", 10000),
+		Bell:        c.getStringOr("terminal.bell", "audible"),
 	}
 }
 
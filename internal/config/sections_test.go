@@ -203,6 +203,30 @@ func TestConfig_Files(t *testing.T) {
 	}
 }
 
+func TestConfig_LanguageBools(t *testing.T) {
+	c := New(WithWatcher(false))
+	defer c.Close()
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got := c.LanguageBools("files.tabsToSpaces"); len(got) != 0 {
+		t.Errorf("LanguageBools() = %v, want none", got)
+	}
+
+	_ = c.SetRuntime("languages.go.files.tabsToSpaces", false)
+	_ = c.SetRuntime("languages.python.files.tabsToSpaces", true)
+	_ = c.SetRuntime("languages.rust.files.tabsToSpaces", "yes")
+
+	got := c.LanguageBools("files.tabsToSpaces")
+	if len(got) != 2 || got["go"] || !got["python"] {
+		t.Errorf("LanguageBools() = %v, want go=false python=true", got)
+	}
+	if _, ok := c.ConfigErrors()["languages.rust.files.tabsToSpaces"]; !ok {
+		t.Error("expected a config error for the non-boolean override")
+	}
+}
+
 func TestConfig_Search(t *testing.T) {
 	c := New(WithWatcher(false))
 	defer c.Close()
@@ -215,6 +239,9 @@ func TestConfig_Search(t *testing.T) {
 	if search.CaseSensitive {
 		t.Error("CaseSensitive = true, want false")
 	}
+	if search.SmartCase {
+		t.Error("SmartCase = true, want false")
+	}
 	if search.WholeWord {
 		t.Error("WholeWord = true, want false")
 	}
@@ -0,0 +1,57 @@
+package config
+
+// Validator checks relationships between settings that single-setting
+// schema validation cannot express, such as one setting requiring another.
+// It returns an error per violated rule, or nil if the configuration is
+// consistent. Errors should use ErrCodeCrossField and list every involved
+// setting in Paths.
+type Validator func(cfg *Config) []*ValidationError
+
+// AddValidator registers a cross-field validator. Validators run on every
+// call to Validate and are reported by ConfigSystem.Health.
+func (c *Config) AddValidator(fn Validator) {
+	if fn == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validators = append(c.validators, fn)
+}
+
+// Validate runs the registered validators against the current settings
+// and returns their combined errors, in registration order.
+func (c *Config) Validate() []*ValidationError {
+	c.mu.RLock()
+	validators := make([]Validator, len(c.validators))
+	copy(validators, c.validators)
+	c.mu.RUnlock()
+
+	// Validators read settings, so they run without the lock
+	var errs []*ValidationError
+	for _, fn := range validators {
+		for _, err := range fn(c) {
+			if err == nil {
+				continue
+			}
+			if err.Path == "" && len(err.Paths) > 0 {
+				err.Path = err.Paths[0]
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// NewCrossFieldError returns a cross-field validation error naming all
+// involved settings. The first path is the primary one.
+func NewCrossFieldError(message string, paths ...string) *ValidationError {
+	err := &ValidationError{
+		Message: message,
+		Code:    ErrCodeCrossField,
+		Paths:   paths,
+	}
+	if len(paths) > 0 {
+		err.Path = paths[0]
+	}
+	return err
+}
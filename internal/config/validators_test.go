@@ -0,0 +1,120 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// wrapColumnValidator requires the wrap column to fit at least four tab
+// stops when wrapping at a column.
+func wrapColumnValidator(cfg *Config) []*ValidationError {
+	wrap, _ := cfg.GetString("editor.wordWrap")
+	column, _ := cfg.GetInt("editor.wordWrapColumn")
+	tabSize, _ := cfg.GetInt("editor.tabSize")
+	if wrap != "wordWrapColumn" || column >= 4*tabSize {
+		return nil
+	}
+	return []*ValidationError{NewCrossFieldError(
+		"wrap column must fit at least four tab stops",
+		"editor.wordWrapColumn", "editor.tabSize", "editor.wordWrap",
+	)}
+}
+
+// newUserSettingsDir returns a config directory with a settings file, so
+// settings can be Set in the user layer.
+func newUserSettingsDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "settings.toml"), []byte("[editor]\ntabSize = 4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := New(WithUserConfigDir(newUserSettingsDir(t)), WithWatcher(false))
+	defer c.Close()
+	if err := c.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if errs := c.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() without validators = %v, want none", errs)
+	}
+
+	c.AddValidator(wrapColumnValidator)
+	for path, value := range map[string]any{
+		"editor.wordWrap":       "wordWrapColumn",
+		"editor.wordWrapColumn": 20,
+		"editor.tabSize":        8,
+	} {
+		if err := c.Set(path, value); err != nil {
+			t.Fatalf("Set(%s) error = %v", path, err)
+		}
+	}
+
+	errs := c.Validate()
+	if len(errs) != 1 {
+		t.Fatalf("Validate() = %v, want 1 error", errs)
+	}
+	err := errs[0]
+	if err.Code != ErrCodeCrossField {
+		t.Errorf("Code = %v, want %v", err.Code, ErrCodeCrossField)
+	}
+	if err.Path != "editor.wordWrapColumn" {
+		t.Errorf("Path = %q, want editor.wordWrapColumn", err.Path)
+	}
+	for _, path := range []string{"editor.wordWrapColumn", "editor.tabSize", "editor.wordWrap"} {
+		if !strings.Contains(err.Error(), path) {
+			t.Errorf("Error() = %q, want it to name %s", err.Error(), path)
+		}
+	}
+
+	if err := c.Set("editor.wordWrapColumn", 80); err != nil {
+		t.Fatal(err)
+	}
+	if errs := c.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() after fix = %v, want none", errs)
+	}
+}
+
+func TestConfigSystem_HealthValidators(t *testing.T) {
+	sys, err := NewConfigSystem(context.Background(),
+		WithSystemUserConfigDir(newUserSettingsDir(t)),
+		WithSystemWatcher(false),
+	)
+	if err != nil {
+		t.Fatalf("NewConfigSystem() error = %v", err)
+	}
+	defer sys.Close()
+
+	sys.AddValidator(wrapColumnValidator)
+	if err := sys.Set("editor.tabSize", 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := sys.Set("editor.wordWrap", "wordWrapColumn"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sys.Set("editor.wordWrapColumn", 20); err != nil {
+		t.Fatal(err)
+	}
+
+	health := sys.Health()
+	if health.Status != HealthDegraded {
+		t.Errorf("Health().Status = %v, want HealthDegraded", health.Status)
+	}
+	if health.ErrorCount != 1 || len(health.ValidationErrors) != 1 {
+		t.Errorf("Health() = %+v, want 1 validation error", health)
+	}
+
+	if err := sys.Set("editor.wordWrapColumn", 100); err != nil {
+		t.Fatal(err)
+	}
+	health = sys.Health()
+	if health.Status != HealthOK || health.ErrorCount != 0 || len(health.ValidationErrors) != 0 {
+		t.Errorf("Health() after fix = %+v, want ok", health)
+	}
+}
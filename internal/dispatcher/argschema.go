@@ -0,0 +1,188 @@
+package dispatcher
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// ArgType is the type of an action argument.
+type ArgType uint8
+
+const (
+	// ArgAny accepts a value of any type.
+	ArgAny ArgType = iota
+	// ArgString accepts a string.
+	ArgString
+	// ArgInt accepts an integer, including a float64 with an integral
+	// value as decoded from JSON.
+	ArgInt
+	// ArgFloat accepts a float64 or an integer.
+	ArgFloat
+	// ArgBool accepts a bool.
+	ArgBool
+)
+
+// String returns a string representation of the argument type.
+func (t ArgType) String() string {
+	switch t {
+	case ArgAny:
+		return "any"
+	case ArgString:
+		return "string"
+	case ArgInt:
+		return "int"
+	case ArgFloat:
+		return "float"
+	case ArgBool:
+		return "bool"
+	default:
+		return "unknown"
+	}
+}
+
+// ArgSpec describes one argument of an action.
+type ArgSpec struct {
+	// Name is the lowercase name of an input.ActionArgs field ("text",
+	// "searchpattern", "register", "motion", "textobject" or
+	// "direction") or a key of its Extra map.
+	Name string
+
+	// Type is the type of an Extra argument. Fields of input.ActionArgs
+	// are already typed and only checked for presence.
+	Type ArgType
+
+	// Required is true if the action must not run without the argument.
+	// A field is present when it is not its zero value.
+	Required bool
+
+	// Description is a short human-readable summary.
+	Description string
+}
+
+// ArgSchema describes the arguments of an action.
+type ArgSchema struct {
+	// Args lists the arguments the action reads.
+	Args []ArgSpec
+}
+
+// Validate checks args against the schema. It returns an error wrapping
+// ErrInvalidArgs naming the first missing or mistyped argument.
+func (s ArgSchema) Validate(args input.ActionArgs) error {
+	for _, spec := range s.Args {
+		present, ok := argField(args, spec.Name)
+		if !ok {
+			var value any
+			value, present = args.Get(spec.Name)
+			if present && !argTypeMatches(spec.Type, value) {
+				return fmt.Errorf("%w: argument %q must be %s, got %T", ErrInvalidArgs, spec.Name, spec.Type, value)
+			}
+		}
+		if spec.Required && !present {
+			return fmt.Errorf("%w: missing required argument %q", ErrInvalidArgs, spec.Name)
+		}
+	}
+	return nil
+}
+
+// argInfo returns the schema as handler argument descriptions.
+func (s ArgSchema) argInfo() []handler.ArgInfo {
+	info := make([]handler.ArgInfo, len(s.Args))
+	for i, spec := range s.Args {
+		info[i] = handler.ArgInfo{Name: spec.Name, Description: spec.Description, Required: spec.Required}
+	}
+	return info
+}
+
+// argField reports whether the input.ActionArgs field called name is set.
+// It returns false for ok if name is not a field.
+func argField(args input.ActionArgs, name string) (present, ok bool) {
+	switch name {
+	case "text":
+		return args.Text != "", true
+	case "searchpattern":
+		return args.SearchPattern != "", true
+	case "register":
+		return args.Register != 0, true
+	case "motion":
+		return args.Motion != nil, true
+	case "textobject":
+		return args.TextObject != nil, true
+	case "direction":
+		return args.Direction != input.DirNone, true
+	}
+	return false, false
+}
+
+// argTypeMatches reports whether value has type t.
+func argTypeMatches(t ArgType, value any) bool {
+	switch t {
+	case ArgString:
+		_, ok := value.(string)
+		return ok
+	case ArgInt:
+		switch n := value.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return n == math.Trunc(n)
+		}
+		return false
+	case ArgFloat:
+		switch value.(type) {
+		case float64, int, int64:
+			return true
+		}
+		return false
+	case ArgBool:
+		_, ok := value.(bool)
+		return ok
+	default:
+		return true
+	}
+}
+
+// RegisterActionSchema registers the argument schema of an action.
+// Dispatching the action with arguments that do not match the schema
+// returns an error result without running any handler. Registering a
+// schema replaces any previous one.
+func (d *Dispatcher) RegisterActionSchema(actionName string, schema ArgSchema) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.schemas == nil {
+		d.schemas = make(map[string]ArgSchema)
+	}
+	d.schemas[actionName] = schema
+}
+
+// UnregisterActionSchema removes the argument schema of an action.
+func (d *Dispatcher) UnregisterActionSchema(actionName string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.schemas, actionName)
+}
+
+// ActionSchema returns the argument schema registered for an action.
+func (d *Dispatcher) ActionSchema(actionName string) (ArgSchema, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	schema, ok := d.schemas[actionName]
+	return schema, ok
+}
+
+// ValidateAction checks the action's arguments against its registered
+// schema, so callers such as the command palette can reject malformed
+// actions before dispatching them. Actions without a schema are valid.
+func (d *Dispatcher) ValidateAction(action input.Action) error {
+	schema, ok := d.ActionSchema(action.Name)
+	if !ok {
+		return nil
+	}
+	if err := schema.Validate(action.Args); err != nil {
+		return fmt.Errorf("%s: %w", action.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package dispatcher_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/dispatcher"
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// goToLineSchema requires an integer "line" and accepts a "center" flag.
+var goToLineSchema = dispatcher.ArgSchema{Args: []dispatcher.ArgSpec{
+	{Name: "line", Type: dispatcher.ArgInt, Required: true, Description: "Line number"},
+	{Name: "center", Type: dispatcher.ArgBool, Description: "Center the line"},
+}}
+
+func TestActionSchemaRejectsMissingArg(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	called := false
+	d.RegisterHandlerFunc("editor.goToLine", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		called = true
+		_ = action.Args.Extra["line"].(int) // panics without the argument
+		return handler.Success()
+	})
+	d.RegisterActionSchema("editor.goToLine", goToLineSchema)
+
+	result := d.Dispatch(input.Action{Name: "editor.goToLine"})
+	if result.Status != handler.StatusError {
+		t.Fatalf("Status = %v, want error", result.Status)
+	}
+	if !errors.Is(result.Error, dispatcher.ErrInvalidArgs) {
+		t.Errorf("Error = %v, want ErrInvalidArgs", result.Error)
+	}
+	if !strings.Contains(result.Error.Error(), `"line"`) {
+		t.Errorf("Error = %v, want it to name the argument", result.Error)
+	}
+	if called {
+		t.Error("handler ran with a missing required argument")
+	}
+
+	result = d.Dispatch(input.Action{
+		Name: "editor.goToLine",
+		Args: input.ActionArgs{Extra: map[string]interface{}{"line": 12}},
+	})
+	if result.Status != handler.StatusOK || !called {
+		t.Errorf("valid dispatch: Status = %v, called = %v", result.Status, called)
+	}
+}
+
+func TestActionSchemaTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  input.ActionArgs
+		valid bool
+	}{
+		{"int", input.ActionArgs{Extra: map[string]interface{}{"line": 3}}, true},
+		{"integral float", input.ActionArgs{Extra: map[string]interface{}{"line": 3.0}}, true},
+		{"fractional float", input.ActionArgs{Extra: map[string]interface{}{"line": 3.5}}, false},
+		{"string", input.ActionArgs{Extra: map[string]interface{}{"line": "3"}}, false},
+		{"optional wrong type", input.ActionArgs{Extra: map[string]interface{}{"line": 3, "center": "yes"}}, false},
+		{"optional set", input.ActionArgs{Extra: map[string]interface{}{"line": 3, "center": true}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := goToLineSchema.Validate(tt.args)
+			if valid := err == nil; valid != tt.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, tt.valid)
+			}
+			if err != nil && !errors.Is(err, dispatcher.ErrInvalidArgs) {
+				t.Errorf("Validate() error = %v, want ErrInvalidArgs", err)
+			}
+		})
+	}
+}
+
+func TestActionSchemaFields(t *testing.T) {
+	schema := dispatcher.ArgSchema{Args: []dispatcher.ArgSpec{{Name: "text", Required: true}}}
+
+	if err := schema.Validate(input.ActionArgs{}); !errors.Is(err, dispatcher.ErrInvalidArgs) {
+		t.Errorf("Validate() without text error = %v, want ErrInvalidArgs", err)
+	}
+	if err := schema.Validate(input.ActionArgs{Text: "x"}); err != nil {
+		t.Errorf("Validate() with text error = %v", err)
+	}
+}
+
+func TestValidateActionAndListActions(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+	d.RegisterHandlerFunc("editor.goToLine", func(input.Action, *execctx.ExecutionContext) handler.Result {
+		return handler.Success()
+	})
+
+	action := input.Action{Name: "editor.goToLine"}
+	if err := d.ValidateAction(action); err != nil {
+		t.Errorf("ValidateAction() without schema error = %v", err)
+	}
+
+	d.RegisterActionSchema("editor.goToLine", goToLineSchema)
+	if err := d.ValidateAction(action); !errors.Is(err, dispatcher.ErrInvalidArgs) {
+		t.Errorf("ValidateAction() error = %v, want ErrInvalidArgs", err)
+	}
+
+	// The palette sees the schema's arguments
+	var found bool
+	for _, info := range d.ListActions() {
+		if info.Name != "editor.goToLine" {
+			continue
+		}
+		found = true
+		if len(info.Args) != 2 || info.Args[0].Name != "line" || !info.Args[0].Required {
+			t.Errorf("Args = %+v, want the schema's arguments", info.Args)
+		}
+	}
+	if !found {
+		t.Error("ListActions() missing editor.goToLine")
+	}
+
+	d.UnregisterActionSchema("editor.goToLine")
+	if result := d.Dispatch(action); result.Status != handler.StatusOK {
+		t.Errorf("Status after UnregisterActionSchema = %v, want ok", result.Status)
+	}
+}
+
+func TestBuiltinSchemas(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+	d.RegisterBuiltinSchemas()
+
+	if _, ok := d.ActionSchema("editor.pasteAfter"); !ok {
+		t.Fatal("editor.pasteAfter has no schema")
+	}
+
+	err := d.ValidateAction(input.Action{
+		Name: "editor.pasteAfter",
+		Args: input.ActionArgs{Text: "ab\ncd", Extra: map[string]interface{}{"blockwise": "yes"}},
+	})
+	if !errors.Is(err, dispatcher.ErrInvalidArgs) {
+		t.Errorf("ValidateAction(blockwise string) = %v, want ErrInvalidArgs", err)
+	}
+
+	err = d.ValidateAction(input.Action{
+		Name: "editor.pasteAfter",
+		Args: input.ActionArgs{Text: "ab\ncd", Extra: map[string]interface{}{"blockwise": true}},
+	})
+	if err != nil {
+		t.Errorf("ValidateAction(blockwise bool) = %v, want nil", err)
+	}
+
+	err = d.ValidateAction(input.Action{Name: "git.checkout"})
+	if !errors.Is(err, dispatcher.ErrInvalidArgs) {
+		t.Errorf("ValidateAction(git.checkout) = %v, want ErrInvalidArgs", err)
+	}
+}
@@ -0,0 +1,65 @@
+package dispatcher
+
+import (
+	"context"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// DispatchBatch dispatches actions in order as a single undo step.
+//
+// Each action is dispatched as by Dispatch, including pre- and post-dispatch
+// hooks, but all edits are recorded in one history group named groupName so
+// that one undo reverts the whole batch. Undo groups that handlers open
+// themselves are folded into the batch group.
+//
+// The batch stops at the first action whose result has StatusError. The
+// group still closes, so the edits of the actions that succeeded undo
+// together. The returned results correspond to the actions that ran.
+func (d *Dispatcher) DispatchBatch(actions []input.Action, groupName string) []handler.Result {
+	d.mu.RLock()
+	history := d.history
+	d.mu.RUnlock()
+
+	var override execctx.HistoryInterface
+	if history != nil {
+		// Join an enclosing group instead of closing it early.
+		if !history.IsGrouping() {
+			history.BeginGroup(groupName)
+			defer history.EndGroup()
+		}
+		override = batchHistory{history}
+	}
+
+	results := make([]handler.Result, 0, len(actions))
+	for _, action := range actions {
+		result := d.dispatchWithHistory(context.Background(), action, nil, override)
+		results = append(results, result)
+		if result.Status == handler.StatusError {
+			break
+		}
+	}
+	return results
+}
+
+// batchHistory is the history seen by handlers during DispatchBatch. It
+// ignores grouping calls so that a handler's own group cannot end the batch
+// group early.
+type batchHistory struct {
+	execctx.HistoryInterface
+}
+
+// BeginGroup is a no-op; the batch group is already open.
+func (batchHistory) BeginGroup(name string) {}
+
+// EndGroup is a no-op; the batch group closes when the batch ends.
+func (batchHistory) EndGroup() {}
+
+// CancelGroup is a no-op; cancelling would drop the history of earlier
+// actions in the batch while leaving their edits applied.
+func (batchHistory) CancelGroup() {}
+
+// IsGrouping returns true; every action in a batch runs inside its group.
+func (batchHistory) IsGrouping() bool { return true }
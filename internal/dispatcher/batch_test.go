@@ -0,0 +1,145 @@
+package dispatcher_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/dispatcher"
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// engineHistory adapts an engine's undo history to execctx.HistoryInterface.
+type engineHistory struct {
+	eng      *engine.Engine
+	grouping bool
+}
+
+func (h *engineHistory) BeginGroup(name string) {
+	h.grouping = true
+	h.eng.BeginUndoGroup(name)
+}
+
+func (h *engineHistory) EndGroup() {
+	h.grouping = false
+	h.eng.EndUndoGroup()
+}
+
+func (h *engineHistory) CancelGroup() {
+	h.grouping = false
+	h.eng.CancelUndoGroup()
+}
+
+func (h *engineHistory) IsGrouping() bool { return h.grouping }
+func (h *engineHistory) CanUndo() bool    { return h.eng.CanUndo() }
+func (h *engineHistory) CanRedo() bool    { return h.eng.CanRedo() }
+func (h *engineHistory) UndoCount() int   { return h.eng.UndoCount() }
+func (h *engineHistory) RedoCount() int   { return h.eng.RedoCount() }
+
+// newBatchDispatcher returns a dispatcher whose "test.append" action appends
+// its "text" argument to eng and whose "test.fail" action fails.
+func newBatchDispatcher(eng *engine.Engine) *dispatcher.Dispatcher {
+	d := dispatcher.NewWithDefaults()
+	d.SetHistory(&engineHistory{eng: eng})
+
+	d.RegisterHandlerFunc("test.append", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		// Handlers may group their own edits; the batch group absorbs it.
+		ctx.History.BeginGroup("append")
+		defer ctx.History.EndGroup()
+
+		if _, err := eng.Insert(eng.Len(), action.Args.GetString("text")); err != nil {
+			return handler.Error(err)
+		}
+		return handler.Success()
+	})
+	d.RegisterHandlerFunc("test.fail", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		return handler.Error(errors.New("failed"))
+	})
+	return d
+}
+
+func appendAction(text string) input.Action {
+	action := input.Action{Name: "test.append"}
+	action.Args.Extra = map[string]interface{}{"text": text}
+	return action
+}
+
+func TestDispatchBatchUndoesAsOne(t *testing.T) {
+	eng := engine.New(engine.WithContent("start"))
+	d := newBatchDispatcher(eng)
+
+	var preCount, postCount int
+	d.RegisterPreHook(dispatcher.PreDispatchFunc(func(action *input.Action, ctx *execctx.ExecutionContext) bool {
+		preCount++
+		return true
+	}))
+	d.RegisterPostHook(dispatcher.PostDispatchFunc(func(action *input.Action, ctx *execctx.ExecutionContext, result *handler.Result) {
+		postCount++
+	}))
+
+	results := d.DispatchBatch([]input.Action{
+		appendAction(" one"),
+		appendAction(" two"),
+		appendAction(" three"),
+	}, "batch")
+
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d, want 3", len(results))
+	}
+	for i, r := range results {
+		if r.Status != handler.StatusOK {
+			t.Errorf("results[%d].Status = %v, want OK", i, r.Status)
+		}
+	}
+	if preCount != 3 || postCount != 3 {
+		t.Errorf("hooks ran pre=%d post=%d times, want 3 each", preCount, postCount)
+	}
+	if got := eng.Text(); got != "start one two three" {
+		t.Fatalf("Text() = %q, want %q", got, "start one two three")
+	}
+	if got := eng.UndoCount(); got != 1 {
+		t.Errorf("UndoCount() = %d, want 1", got)
+	}
+
+	if err := eng.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if got := eng.Text(); got != "start" {
+		t.Errorf("Text() after undo = %q, want %q", got, "start")
+	}
+}
+
+func TestDispatchBatchStopsOnFailure(t *testing.T) {
+	eng := engine.New(engine.WithContent("start"))
+	d := newBatchDispatcher(eng)
+
+	results := d.DispatchBatch([]input.Action{
+		appendAction(" one"),
+		appendAction(" two"),
+		{Name: "test.fail"},
+		appendAction(" three"),
+	}, "batch")
+
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d, want 3", len(results))
+	}
+	if results[2].Status != handler.StatusError {
+		t.Errorf("results[2].Status = %v, want Error", results[2].Status)
+	}
+	if got := eng.Text(); got != "start one two" {
+		t.Fatalf("Text() = %q, want %q", got, "start one two")
+	}
+
+	// The group closed with the edits that succeeded
+	if d.History().IsGrouping() {
+		t.Error("history group should be closed after a failed batch")
+	}
+	if err := eng.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if got := eng.Text(); got != "start" {
+		t.Errorf("Text() after undo = %q, want %q", got, "start")
+	}
+}
@@ -24,6 +24,10 @@ type Config struct {
 	// MaxRepeatCount limits the maximum repeat count for actions.
 	// Zero means no limit.
 	MaxRepeatCount int
+
+	// MaxRewrites limits how many times rewriters may replace a single
+	// dispatched action. Zero means DefaultMaxRewrites.
+	MaxRewrites int
 }
 
 // DefaultConfig returns a configuration with sensible defaults.
@@ -35,6 +39,7 @@ func DefaultConfig() Config {
 		RecoverFromPanic: true,
 		DefaultTimeout:   0,
 		MaxRepeatCount:   10000,
+		MaxRewrites:      DefaultMaxRewrites,
 	}
 }
 
@@ -70,3 +75,9 @@ func (c Config) WithMaxRepeatCount(max int) Config {
 	c.MaxRepeatCount = max
 	return c
 }
+
+// WithMaxRewrites returns a copy of the config with the max rewrite count set.
+func (c Config) WithMaxRewrites(max int) Config {
+	c.MaxRewrites = max
+	return c
+}
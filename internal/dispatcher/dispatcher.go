@@ -2,7 +2,10 @@
 package dispatcher
 
 import (
+	"context"
+	"errors"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -40,12 +43,30 @@ type Dispatcher struct {
 	// Hook manager for priority-based hooks
 	hookManager *hook.Manager
 
+	// Action rewriters by namespace ("" applies to all actions)
+	rewriters map[string][]ActionRewriter
+
+	// Argument schemas by action name
+	schemas map[string]ArgSchema
+
+	// Per-action rate limits and debouncing
+	throttle throttle
+
 	// Async dispatch
 	actionChan chan input.Action
 	resultChan chan handler.Result
 	done       chan struct{}
+
+	// In-flight dispatches, cancelled by Cancel
+	inflightMu   sync.Mutex
+	inflight     map[uint64]context.CancelFunc
+	nextInflight uint64
 }
 
+// escapeAction is the action bound to Escape. Dispatching it cancels any
+// in-flight action.
+const escapeAction = "mode.normal"
+
 // New creates a new dispatcher with the given configuration.
 func New(config Config) *Dispatcher {
 	d := &Dispatcher{
@@ -148,48 +169,138 @@ func (d *Dispatcher) Renderer() execctx.RendererInterface {
 
 // Dispatch executes an action synchronously.
 func (d *Dispatcher) Dispatch(action input.Action) handler.Result {
-	return d.dispatchInternal(action, nil)
+	return d.dispatchInternal(context.Background(), action, nil)
 }
 
 // DispatchWithContext executes an action with explicit input context.
 func (d *Dispatcher) DispatchWithContext(action input.Action, inputCtx *input.Context) handler.Result {
-	return d.dispatchInternal(action, inputCtx)
+	return d.dispatchInternal(context.Background(), action, inputCtx)
+}
+
+// DispatchCancellable executes an action whose handler observes c. The
+// handler's context is also cancelled by Cancel.
+func (d *Dispatcher) DispatchCancellable(c context.Context, action input.Action, inputCtx *input.Context) handler.Result {
+	return d.dispatchInternal(c, action, inputCtx)
+}
+
+// Cancel cancels the contexts of all in-flight actions. Handlers that
+// observe their context return early with a cancelled result.
+func (d *Dispatcher) Cancel() {
+	d.inflightMu.Lock()
+	defer d.inflightMu.Unlock()
+
+	for id, cancel := range d.inflight {
+		cancel()
+		delete(d.inflight, id)
+	}
+}
+
+// trackInflight derives a cancellable context for a dispatch and registers
+// it with Cancel. The returned release func must be called when the
+// dispatch finishes.
+func (d *Dispatcher) trackInflight(parent context.Context) (context.Context, func()) {
+	c, cancel := context.WithCancel(parent)
+
+	d.inflightMu.Lock()
+	defer d.inflightMu.Unlock()
+	if d.inflight == nil {
+		d.inflight = make(map[uint64]context.CancelFunc)
+	}
+	d.nextInflight++
+	id := d.nextInflight
+	d.inflight[id] = cancel
+
+	return c, func() {
+		d.inflightMu.Lock()
+		delete(d.inflight, id)
+		d.inflightMu.Unlock()
+		cancel()
+	}
 }
 
 // dispatchInternal is the core dispatch logic.
-func (d *Dispatcher) dispatchInternal(action input.Action, inputCtx *input.Context) handler.Result {
+func (d *Dispatcher) dispatchInternal(parent context.Context, action input.Action, inputCtx *input.Context) handler.Result {
+	return d.dispatchWithHistory(parent, action, inputCtx, nil)
+}
+
+// dispatchWithHistory dispatches an action. A non-nil history replaces the
+// dispatcher's history in the execution context.
+func (d *Dispatcher) dispatchWithHistory(parent context.Context, action input.Action, inputCtx *input.Context, history execctx.HistoryInterface) handler.Result {
 	startTime := time.Now()
 
+	// Escape aborts whatever is still running
+	if action.Name == escapeAction {
+		d.Cancel()
+	}
+
+	c, release := d.trackInflight(parent)
+	defer release()
+
 	// Build execution context
-	ctx := d.buildContext(inputCtx)
+	ctx := d.buildContext(inputCtx).WithContext(c)
+	if history != nil {
+		ctx.History = history
+	}
 
 	// Apply repeat count from action if specified
 	if action.Count > 0 {
 		ctx.Count = action.Count
 	}
 
+	// Rate-limited and debounced actions may not execute now
+	throttledName := action.Name
+	grouping := ctx.History != nil && ctx.History.IsGrouping()
+	if result, held := d.throttleAction(parent, action, inputCtx, grouping); held {
+		return result
+	}
+
+	// Rewrite the action before hooks see it
+	action, err := d.rewriteAction(action, ctx)
+	if err != nil {
+		return handler.Error(err)
+	}
+
 	// Run pre-dispatch hooks
 	if !d.runPreHooks(&action, ctx) {
 		return handler.CancelledWithMessage("cancelled by hook")
 	}
 
-	// Find handler
-	h := d.router.Route(action.Name)
-	if h == nil {
-		h = d.registry.Get(action.Name)
+	// Reject malformed arguments before any handler sees them
+	if err := d.ValidateAction(action); err != nil {
+		return handler.Error(err)
 	}
-	if h == nil {
+
+	// Find handlers
+	handlers := d.handlersFor(action.Name)
+	if len(handlers) == 0 {
 		return handler.Errorf("no handler for action: %s", action.Name)
 	}
 
-	// Execute handler
+	// Execute handlers until one does not fall through
 	var result handler.Result
-	if d.config.RecoverFromPanic {
-		result = d.executeWithRecovery(h, action, ctx)
-	} else {
-		result = h.Handle(action, ctx)
+	for _, h := range handlers {
+		if d.config.RecoverFromPanic {
+			result = d.executeWithRecovery(h, action, ctx)
+		} else {
+			result = h.Handle(action, ctx)
+		}
+		if !result.IsFallthrough() {
+			break
+		}
+	}
+	if result.IsFallthrough() {
+		result = handler.Errorf("no handler for action: %s", action.Name)
 	}
 
+	// A handler that gave up because it was cancelled reports a cancelled
+	// result rather than an error
+	if result.Status == handler.StatusError && c.Err() != nil && errors.Is(result.Error, c.Err()) {
+		result = handler.CancelledWithMessage(action.Name + " cancelled")
+	}
+
+	// Only an action that ran starts its rate limit interval
+	d.recordRun(throttledName, result)
+
 	// Process result (mode changes, view updates, etc.)
 	d.processResult(action, result, ctx)
 
@@ -204,6 +315,26 @@ func (d *Dispatcher) dispatchInternal(action input.Action, inputCtx *input.Conte
 	return result
 }
 
+// handlersFor returns the handlers for an action in the order they are
+// tried: the namespace handler and the handlers registered for the exact
+// action name by descending priority, then the router's fallback. The
+// namespace handler has priority 0 and comes before exact handlers of the
+// same priority. Later handlers only run if the earlier ones fall through.
+func (d *Dispatcher) handlersFor(actionName string) []handler.Handler {
+	var handlers []handler.Handler
+	if h := d.router.routeNamespace(actionName); h != nil {
+		handlers = append(handlers, h)
+	}
+	handlers = append(handlers, d.registry.GetAll(actionName)...)
+	sort.SliceStable(handlers, func(i, j int) bool {
+		return handlers[i].Priority() > handlers[j].Priority()
+	})
+	if h := d.router.Fallback(); h != nil {
+		handlers = append(handlers, h)
+	}
+	return handlers
+}
+
 // executeWithRecovery executes a handler with panic recovery.
 func (d *Dispatcher) executeWithRecovery(h handler.Handler, action input.Action, ctx *execctx.ExecutionContext) (result handler.Result) {
 	defer func() {
@@ -237,51 +368,11 @@ func (d *Dispatcher) buildContext(inputCtx *input.Context) *execctx.ExecutionCon
 	return ctx
 }
 
-// processResult processes a handler result.
+// processResult applies the view effects of a handler result, including
+// its mode change and view update.
 func (d *Dispatcher) processResult(action input.Action, result handler.Result, ctx *execctx.ExecutionContext) {
-	// Handle mode change
-	if result.ModeChange != "" && ctx.ModeManager != nil {
-		_ = ctx.ModeManager.Switch(result.ModeChange)
-	}
-
-	// Handle view updates
-	if ctx.Renderer != nil {
-		if result.ViewUpdate.Redraw {
-			ctx.Renderer.Redraw()
-		} else if len(result.ViewUpdate.RedrawLines) > 0 {
-			ctx.Renderer.RedrawLines(result.ViewUpdate.RedrawLines)
-		}
-
-		if result.ViewUpdate.ScrollTo != nil {
-			st := result.ViewUpdate.ScrollTo
-			if st.Center {
-				ctx.Renderer.CenterOnLine(st.Line)
-			} else {
-				ctx.Renderer.ScrollTo(st.Line, st.Column)
-			}
-		} else if result.ViewUpdate.CenterLine != nil {
-			ctx.Renderer.CenterOnLine(*result.ViewUpdate.CenterLine)
-		} else {
-			// Auto-scroll to keep cursor visible after any action
-			d.ensureCursorVisible(ctx)
-		}
-	}
-}
-
-// ensureCursorVisible scrolls the viewport to keep the primary cursor visible.
-func (d *Dispatcher) ensureCursorVisible(ctx *execctx.ExecutionContext) {
-	if ctx.Cursors == nil || ctx.Engine == nil || ctx.Renderer == nil {
-		return
-	}
-
-	// Get primary cursor position
-	primary := ctx.Cursors.Primary()
-	point := ctx.Engine.OffsetToPoint(primary.Cursor())
-
-	// Check if cursor is visible
-	if !ctx.Renderer.IsLineVisible(point.Line) {
-		// Scroll to reveal cursor with some context
-		ctx.Renderer.ScrollToReveal(point.Line, point.Column)
+	for _, effect := range result.AllEffects() {
+		effect.Apply(ctx)
 	}
 }
 
@@ -305,6 +396,66 @@ func (d *Dispatcher) UnregisterHandler(actionName string) {
 	d.registry.Unregister(actionName)
 }
 
+// ListActions returns every action the dispatcher can handle, sorted by
+// name. Namespace handlers contribute the actions they list through
+// handler.DescribableHandler; handlers that do not implement it are
+// skipped, as their actions cannot be enumerated. Handlers registered by
+// exact name are always listed, with a description if their handler
+// provides one.
+func (d *Dispatcher) ListActions() []handler.ActionInfo {
+	actions := make(map[string]handler.ActionInfo)
+
+	for _, namespace := range d.router.Namespaces() {
+		h := d.router.GetNamespaceHandler(namespace)
+		if h == nil {
+			continue
+		}
+		for _, info := range handler.DescribeActions(h) {
+			// Only actions the router would actually send to this handler
+			if extractNamespace(info.Name) != namespace || !h.CanHandle(info.Name) {
+				continue
+			}
+			info.Namespace = namespace
+			actions[info.Name] = info
+		}
+	}
+
+	for _, name := range d.registry.List() {
+		if _, ok := actions[name]; ok {
+			continue // routed to the namespace handler first
+		}
+		info := handler.ActionInfo{Name: name, Namespace: extractNamespace(name)}
+		for _, described := range handler.DescribeActions(d.registry.Get(name)) {
+			if described.Name == name {
+				info.Description = described.Description
+				info.Args = described.Args
+				break
+			}
+		}
+		actions[name] = info
+	}
+
+	// Registered schemas describe the arguments of actions whose handlers
+	// do not
+	d.mu.RLock()
+	for name, schema := range d.schemas {
+		if info, ok := actions[name]; ok && len(info.Args) == 0 {
+			info.Args = schema.argInfo()
+			actions[name] = info
+		}
+	}
+	d.mu.RUnlock()
+
+	result := make([]handler.ActionInfo, 0, len(actions))
+	for _, info := range actions {
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
 // RegisterPreHook registers a pre-dispatch hook.
 func (d *Dispatcher) RegisterPreHook(hook PreDispatchHook) {
 	d.mu.Lock()
@@ -378,8 +529,10 @@ func (d *Dispatcher) Start() {
 	go d.dispatchLoop()
 }
 
-// Stop stops the async dispatch loop.
+// Stop stops the async dispatch loop and drops pending debounced actions.
 func (d *Dispatcher) Stop() {
+	d.throttle.stopPending()
+
 	select {
 	case <-d.done:
 		// Already closed
@@ -393,18 +546,49 @@ func (d *Dispatcher) dispatchLoop() {
 	for {
 		select {
 		case action := <-d.actionChan:
-			result := d.Dispatch(action)
-			select {
-			case d.resultChan <- result:
-			default:
-				// Result channel full, drop result
-			}
+			d.dispatchAsync(action)
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// dispatchAsync runs one queued action. If another action arrives while the
+// handler is still running, the in-flight action is cancelled and the new
+// one is dispatched once the handler returns, so actions never overlap.
+func (d *Dispatcher) dispatchAsync(action input.Action) {
+	for {
+		c, cancel := context.WithCancel(context.Background())
+		done := make(chan handler.Result, 1)
+		go func(action input.Action) {
+			done <- d.dispatchInternal(c, action, nil)
+		}(action)
+
+		select {
+		case result := <-done:
+			cancel()
+			d.sendResult(result)
+			return
+		case next := <-d.actionChan:
+			cancel()
+			d.sendResult(<-done)
+			action = next
 		case <-d.done:
+			cancel()
 			return
 		}
 	}
 }
 
+// sendResult publishes an async result.
+func (d *Dispatcher) sendResult(result handler.Result) {
+	select {
+	case d.resultChan <- result:
+	default:
+		// Result channel full, drop result
+	}
+}
+
 // Actions returns the action channel for async dispatch.
 // Returns nil if async dispatch is not enabled.
 func (d *Dispatcher) Actions() chan<- input.Action {
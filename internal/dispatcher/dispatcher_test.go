@@ -1,6 +1,7 @@
 package dispatcher_test
 
 import (
+	"context"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -8,7 +9,15 @@ import (
 	"github.com/dshills/keystorm/internal/dispatcher"
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/cursor"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/editor"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/mode"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/operator"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/search"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/view"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/window"
 	"github.com/dshills/keystorm/internal/input"
+	"github.com/dshills/keystorm/internal/lsp"
 )
 
 func TestNewWithDefaults(t *testing.T) {
@@ -276,6 +285,140 @@ func TestAsyncDispatch(t *testing.T) {
 	}
 }
 
+// blockingHandler registers a handler that signals started and then blocks
+// until its context is cancelled, returning the context error.
+func blockingHandler(d *dispatcher.Dispatcher, name string, started chan<- struct{}) {
+	d.RegisterHandlerFunc(name, func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		started <- struct{}{}
+		select {
+		case <-ctx.Context().Done():
+			return handler.Error(ctx.Context().Err())
+		case <-time.After(5 * time.Second):
+			return handler.Success()
+		}
+	})
+}
+
+func TestDispatchDefaultContext(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	var cancelled bool
+	d.RegisterHandlerFunc("test", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		if ctx.Context() == nil {
+			t.Error("handler context should not be nil")
+		}
+		cancelled = ctx.Cancelled()
+		return handler.Success()
+	})
+
+	result := d.Dispatch(input.Action{Name: "test"})
+	if result.Status != handler.StatusOK {
+		t.Errorf("expected StatusOK, got %v", result.Status)
+	}
+	if cancelled {
+		t.Error("sync dispatch context should not be cancelled")
+	}
+}
+
+func TestDispatchCancelMidHandler(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	started := make(chan struct{}, 1)
+	blockingHandler(d, "lsp.references", started)
+
+	go func() {
+		<-started
+		d.Cancel()
+	}()
+
+	result := d.Dispatch(input.Action{Name: "lsp.references"})
+	if result.Status != handler.StatusCancelled {
+		t.Errorf("expected StatusCancelled, got %v (%v)", result.Status, result.Error)
+	}
+}
+
+func TestDispatchEscapeCancelsInFlight(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	started := make(chan struct{}, 1)
+	blockingHandler(d, "lsp.references", started)
+	d.RegisterHandlerFunc("mode.normal", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		return handler.Success()
+	})
+
+	results := make(chan handler.Result, 1)
+	go func() {
+		results <- d.Dispatch(input.Action{Name: "lsp.references"})
+	}()
+	<-started
+
+	d.Dispatch(input.Action{Name: "mode.normal"})
+
+	select {
+	case result := <-results:
+		if result.Status != handler.StatusCancelled {
+			t.Errorf("expected StatusCancelled, got %v", result.Status)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Escape did not cancel in-flight action")
+	}
+}
+
+func TestDispatchCancellableParent(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	started := make(chan struct{}, 1)
+	blockingHandler(d, "git.fetch", started)
+
+	parent, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	result := d.DispatchCancellable(parent, input.Action{Name: "git.fetch"}, nil)
+	if result.Status != handler.StatusCancelled {
+		t.Errorf("expected StatusCancelled, got %v", result.Status)
+	}
+}
+
+func TestAsyncDispatchPreempt(t *testing.T) {
+	config := dispatcher.DefaultConfig().WithAsyncDispatch(10)
+	d := dispatcher.New(config)
+
+	started := make(chan struct{}, 1)
+	blockingHandler(d, "lsp.references", started)
+
+	var after int32
+	d.RegisterHandlerFunc("cursor.moveDown", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		atomic.AddInt32(&after, 1)
+		return handler.Success()
+	})
+
+	d.Start()
+	defer d.Stop()
+
+	d.Actions() <- input.Action{Name: "lsp.references"}
+	<-started
+	d.Actions() <- input.Action{Name: "cursor.moveDown"}
+
+	want := []handler.ResultStatus{handler.StatusCancelled, handler.StatusOK}
+	for i, status := range want {
+		select {
+		case result := <-d.Results():
+			if result.Status != status {
+				t.Errorf("result %d: expected %v, got %v", i, status, result.Status)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for result %d", i)
+		}
+	}
+
+	if atomic.LoadInt32(&after) != 1 {
+		t.Errorf("expected preempting action to run once, got %d", after)
+	}
+}
+
 func TestMetricsRecording(t *testing.T) {
 	config := dispatcher.DefaultConfig().WithMetrics()
 	d := dispatcher.New(config)
@@ -324,3 +467,177 @@ func TestRouterPrecedence(t *testing.T) {
 		t.Errorf("expected namespace handler to take precedence, got message %q", result.Message)
 	}
 }
+
+func TestHandlerFallthrough(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	var calls []string
+	d.RegisterHandler("test.action", handler.NewHandlerFuncWithPriority(func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		calls = append(calls, "low")
+		return handler.Success().WithMessage("low")
+	}, 10))
+	d.RegisterHandler("test.action", handler.NewHandlerFuncWithPriority(func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		calls = append(calls, "high")
+		if action.Count > 1 {
+			return handler.Success().WithMessage("high")
+		}
+		return handler.Fallthrough()
+	}, 100))
+
+	result := d.Dispatch(input.Action{Name: "test.action"})
+	if result.Message != "low" {
+		t.Errorf("Dispatch message = %q, want %q", result.Message, "low")
+	}
+	if len(calls) != 2 || calls[0] != "high" || calls[1] != "low" {
+		t.Errorf("calls = %v, want [high low]", calls)
+	}
+
+	calls = nil
+	result = d.Dispatch(input.Action{Name: "test.action", Count: 2})
+	if result.Message != "high" {
+		t.Errorf("Dispatch message = %q, want %q", result.Message, "high")
+	}
+	if len(calls) != 1 {
+		t.Errorf("calls = %v, want [high]", calls)
+	}
+}
+
+func TestHandlerFallthroughFromNamespace(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	bnh := handler.NewBaseNamespaceHandler("test")
+	bnh.Register("test.action", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		return handler.Fallthrough()
+	})
+	d.RegisterNamespace("test", bnh)
+	d.RegisterHandlerFunc("test.action", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		return handler.Success().WithMessage("exact")
+	})
+
+	result := d.Dispatch(input.Action{Name: "test.action"})
+	if result.Message != "exact" {
+		t.Errorf("Dispatch message = %q, want %q", result.Message, "exact")
+	}
+}
+
+func TestHandlerPriorityBeforeNamespace(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	bnh := handler.NewBaseNamespaceHandler("test")
+	bnh.Register("test.action", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		return handler.Success().WithMessage("namespace")
+	})
+	d.RegisterNamespace("test", bnh)
+
+	// A high-priority handler sits in front of the namespace handler and
+	// defers to it unless the action is for it
+	d.RegisterHandler("test.action", handler.NewHandlerFuncWithPriority(func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		if action.Args.GetString("target") != "remote" {
+			return handler.Fallthrough()
+		}
+		return handler.Success().WithMessage("plugin")
+	}, 100))
+
+	result := d.Dispatch(input.Action{
+		Name: "test.action",
+		Args: input.ActionArgs{Extra: map[string]interface{}{"target": "remote"}},
+	})
+	if result.Message != "plugin" {
+		t.Errorf("Dispatch(remote) message = %q, want %q", result.Message, "plugin")
+	}
+
+	result = d.Dispatch(input.Action{Name: "test.action"})
+	if result.Message != "namespace" {
+		t.Errorf("Dispatch(local) message = %q, want %q", result.Message, "namespace")
+	}
+
+	// Handlers below the namespace handler's priority come after it
+	d.RegisterHandler("test.action", handler.NewHandlerFuncWithPriority(func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		return handler.Success().WithMessage("low")
+	}, -10))
+	result = d.Dispatch(input.Action{Name: "test.action"})
+	if result.Message != "namespace" {
+		t.Errorf("Dispatch with low-priority handler message = %q, want %q", result.Message, "namespace")
+	}
+}
+
+func TestHandlerFallthroughUnhandled(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	d.RegisterHandlerFunc("test.action", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		return handler.Fallthrough()
+	})
+
+	result := d.Dispatch(input.Action{Name: "test.action"})
+	if result.Status != handler.StatusError {
+		t.Errorf("Dispatch status = %v, want %v", result.Status, handler.StatusError)
+	}
+}
+
+func TestListActions(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+	d.RegisterNamespace("editor", editor.NewCombinedHandler())
+	d.RegisterNamespace("cursor", cursor.NewCombinedHandler())
+	d.RegisterHandlerFunc("app.quit", func(input.Action, *execctx.ExecutionContext) handler.Result {
+		return handler.Success()
+	})
+
+	actions := d.ListActions()
+	byName := make(map[string]handler.ActionInfo)
+	for i, info := range actions {
+		if i > 0 && actions[i-1].Name >= info.Name {
+			t.Errorf("actions not sorted: %q before %q", actions[i-1].Name, info.Name)
+		}
+		byName[info.Name] = info
+	}
+
+	for _, name := range []string{cursor.ActionMoveDown, cursor.ActionWordForward, editor.ActionDeleteLine, editor.ActionPasteAfter} {
+		info, ok := byName[name]
+		if !ok {
+			t.Errorf("ListActions missing %q", name)
+			continue
+		}
+		if info.Description == "" {
+			t.Errorf("%q has no description", name)
+		}
+	}
+
+	insert := byName[editor.ActionInsertText]
+	if insert.Namespace != "editor" {
+		t.Errorf("%q namespace = %q, want editor", insert.Name, insert.Namespace)
+	}
+	if len(insert.Args) != 1 || insert.Args[0].Name != "text" || !insert.Args[0].Required {
+		t.Errorf("%q args = %+v, want required text", insert.Name, insert.Args)
+	}
+
+	quit, ok := byName["app.quit"]
+	if !ok || quit.Namespace != "app" || quit.Description != "" {
+		t.Errorf("app.quit = %+v, %v, want undescribed action in app namespace", quit, ok)
+	}
+}
+
+func TestNamespaceHandlersDescribeActions(t *testing.T) {
+	handlers := []handler.NamespaceHandler{
+		mode.NewModeHandler(),
+		operator.NewOperatorHandler(),
+		search.NewHandler(),
+		view.NewHandler(),
+		window.NewHandler(),
+		lsp.NewHandler(),
+	}
+
+	for _, h := range handlers {
+		actions := handler.DescribeActions(h)
+		if len(actions) == 0 {
+			t.Errorf("%s: no actions described", h.Namespace())
+		}
+		for _, info := range actions {
+			if !h.CanHandle(info.Name) {
+				t.Errorf("%s: described action %q is not handled", h.Namespace(), info.Name)
+			}
+			if info.Description == "" {
+				t.Errorf("%s: %q has no description", h.Namespace(), info.Name)
+			}
+		}
+	}
+}
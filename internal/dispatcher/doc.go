@@ -19,13 +19,26 @@
 //
 // When an action is dispatched:
 //
-//  1. Pre-dispatch hooks are called (can modify or cancel the action)
-//  2. The router finds the appropriate handler
-//  3. An ExecutionContext is built with references to editor subsystems
-//  4. The handler is executed (with optional panic recovery)
-//  5. The result is processed (mode changes, view updates)
-//  6. Post-dispatch hooks are called
-//  7. Metrics are recorded (if enabled)
+//  1. Action rewriters may replace the action with another
+//  2. Pre-dispatch hooks are called (can modify or cancel the action)
+//  3. Arguments are checked against the action's registered ArgSchema
+//  4. The router finds the appropriate handler
+//  5. An ExecutionContext is built with references to editor subsystems
+//  6. The handler is executed (with optional panic recovery); if it returns
+//     handler.Fallthrough(), the next handler is tried
+//  7. The result is processed (mode changes, view updates, view effects)
+//  8. Post-dispatch hooks are called
+//  9. Metrics are recorded (if enabled)
+//
+// Actions whose arguments do not match their schema fail with an error
+// wrapping ErrInvalidArgs instead of reaching a handler:
+//
+//	dispatcher.RegisterActionSchema("editor.goToLine", ArgSchema{Args: []ArgSpec{
+//	    {Name: "line", Type: ArgInt, Required: true, Description: "Line number"},
+//	}})
+//
+// RegisterBuiltinSchemas registers the schemas of the built-in actions that
+// read Extra arguments, such as the "blockwise" flag of editor.pasteAfter.
 //
 // # Handlers
 //
@@ -45,6 +58,44 @@
 //	    Namespace() string
 //	}
 //
+// Handlers are tried by descending priority: the namespace handler, which
+// has priority 0 and comes first among equals, and the handlers registered
+// for the exact action name, then the router's fallback. Only the first
+// runs, unless it returns handler.Fallthrough() to decline the action. This
+// lets a handler, such as a plugin's, sit in front of a built-in action,
+// handle some cases and defer the rest to it:
+//
+//	d.RegisterHandler("editor.save", handler.NewHandlerFuncWithPriority(
+//	    func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+//	        if !isRemote(ctx) {
+//	            return handler.Fallthrough()
+//	        }
+//	        return saveRemote(ctx)
+//	    }, 100))
+//
+// Handlers that also implement DescribableHandler list their actions with
+// descriptions and arguments. ListActions collects them, plus every action
+// registered by name, to populate a command palette or documentation:
+//
+//	for _, info := range dispatcher.ListActions() {
+//	    fmt.Println(info.Name, info.Description)
+//	}
+//
+// # View Effects
+//
+// Handlers declare what should happen to the view by returning effects
+// rather than calling the renderer, and the dispatcher applies them in
+// order. Effects implement ViewEffect, so new ones need no dispatcher
+// changes:
+//
+//	return handler.Success().WithEffects(
+//	    handler.ScrollTo{Line: target},
+//	    handler.ShowMessage{Text: "search wrapped"},
+//	)
+//
+// The built-in effects are ScrollTo, CenterCursor, Redraw, ShowMessage
+// (shown by renderers implementing execctx.MessageDisplay) and EnterMode.
+//
 // # Execution Context
 //
 // The ExecutionContext provides handlers with access to:
@@ -54,6 +105,8 @@
 //   - History: Undo/redo grouping
 //   - Renderer: View operations (scroll, redraw)
 //   - Input: Input context (pending state, counts)
+//   - Context: A context.Context cancelled when the user presses Escape, a
+//     newer async action preempts the running one, or Cancel is called
 //
 // # Usage
 //
@@ -82,6 +135,25 @@
 //
 //	dispatcher.Stop()
 //
+// Several actions triggered together can be dispatched as one undo step;
+// the batch stops at the first error and the edits that succeeded still
+// undo together:
+//
+//	results := dispatcher.DispatchBatch(actions, "surround")
+//
+// Actions that should not run on every key repeat can be rate limited or
+// debounced. A rate-limited action dispatched within its interval returns a
+// StatusThrottled result without executing; a debounced action returns
+// StatusAsync and executes once dispatches stop for its delay:
+//
+//	dispatcher.SetRateLimit("editor.save", 500*time.Millisecond)
+//	dispatcher.SetDebounce("completion.trigger", 100*time.Millisecond)
+//
+// Debounced actions fire on a timer goroutine unless a scheduler hands
+// them to the editor's event loop:
+//
+//	dispatcher.SetDebounceScheduler(func(fire func()) { loop <- fire })
+//
 // # Hooks
 //
 // Pre-dispatch hooks can modify or cancel actions:
@@ -91,6 +163,18 @@
 //	    return true
 //	}))
 //
+// Rewriters change what is dispatched. The first rewriter returning a
+// replacement wins and the replacement is offered to the rewriters again;
+// a dispatch rewritten more than Config.MaxRewrites times fails with
+// ErrRewriteLoop:
+//
+//	dispatcher.RegisterRewriter("editor", ActionRewriterFunc(func(action input.Action, ctx *execctx.ExecutionContext) *input.Action {
+//	    if action.Name == "editor.save" && ctx.IsReadOnly() {
+//	        return &input.Action{Name: "editor.saveAs", Args: action.Args}
+//	    }
+//	    return nil
+//	}))
+//
 // Post-dispatch hooks can observe or modify results:
 //
 //	dispatcher.RegisterPostHook(PostDispatchFunc(func(action *input.Action, ctx *execctx.ExecutionContext, result *handler.Result) {
@@ -22,6 +22,13 @@ var (
 	// ErrInvalidAction indicates the action is invalid.
 	ErrInvalidAction = errors.New("dispatcher: invalid action")
 
+	// ErrInvalidArgs indicates an action's arguments do not match its
+	// registered schema.
+	ErrInvalidArgs = errors.New("dispatcher: invalid action arguments")
+
+	// ErrRewriteLoop indicates action rewriters kept rewriting an action.
+	ErrRewriteLoop = errors.New("dispatcher: action rewrite loop")
+
 	// ErrAsyncNotEnabled indicates async dispatch is not enabled.
 	ErrAsyncNotEnabled = errors.New("dispatcher: async dispatch not enabled")
 )
@@ -2,8 +2,12 @@
 package execctx
 
 import (
+	"context"
+
+	"github.com/dshills/keystorm/internal/engine"
 	"github.com/dshills/keystorm/internal/engine/buffer"
 	"github.com/dshills/keystorm/internal/engine/cursor"
+	"github.com/dshills/keystorm/internal/engine/rope"
 	"github.com/dshills/keystorm/internal/input"
 )
 
@@ -49,6 +53,110 @@ type EngineReader interface {
 	PointToOffset(point buffer.Point) buffer.ByteOffset
 }
 
+// RopeProvider is implemented by engines that can expose their text as a
+// rope without copying it.
+type RopeProvider interface {
+	Rope() rope.Rope
+}
+
+// EngineRope returns the engine's text as a rope. Engines implementing
+// RopeProvider return their rope directly; others are copied via Text.
+func EngineRope(engine EngineInterface) rope.Rope {
+	if rp, ok := engine.(RopeProvider); ok {
+		return rp.Rope()
+	}
+	return rope.FromString(engine.Text())
+}
+
+// KeywordProvider is implemented by engines with configurable keyword
+// characters (iskeyword) for word motions.
+type KeywordProvider interface {
+	Keywords() cursor.Keywords
+}
+
+// EngineKeywords returns the keyword characters for word motions. Engines
+// implementing KeywordProvider return their own; others use the defaults.
+func EngineKeywords(engine EngineInterface) cursor.Keywords {
+	if kp, ok := engine.(KeywordProvider); ok {
+		return kp.Keywords()
+	}
+	return cursor.DefaultKeywords()
+}
+
+// IndentBlockProvider is implemented by engines that find indentation
+// blocks with their own tab width and blank line settings.
+type IndentBlockProvider interface {
+	IndentBlock(line int, around bool) buffer.Range
+}
+
+// EngineIndentBlock returns the indentation block around line for the "ii"
+// and "ai" text objects. Engines implementing IndentBlockProvider use their
+// own settings; others use cursor.IndentBlock's defaults.
+func EngineIndentBlock(engine EngineInterface, line int, around bool) buffer.Range {
+	if ip, ok := engine.(IndentBlockProvider); ok {
+		return ip.IndentBlock(line, around)
+	}
+	start, end := cursor.IndentBlock(EngineRope(engine), uint32(max(line, 0)), around, cursor.IndentBlockOptions{})
+	return buffer.Range{Start: start, End: end}
+}
+
+// SearchProvider is implemented by engines that search their own text.
+type SearchProvider interface {
+	FindNext(pattern string, from buffer.ByteOffset, opts engine.SearchOptions) (buffer.Range, bool, error)
+	FindPrev(pattern string, from buffer.ByteOffset, opts engine.SearchOptions) (buffer.Range, bool, error)
+}
+
+// EngineSearcher returns a searcher for the engine's text. Engines
+// implementing SearchProvider are returned directly; others are copied
+// via Text into a new engine.
+func EngineSearcher(e EngineInterface) SearchProvider {
+	if sp, ok := e.(SearchProvider); ok {
+		return sp
+	}
+	return engine.New(engine.WithContent(e.Text()))
+}
+
+// GotoProvider is implemented by engines that resolve line and column
+// positions themselves.
+type GotoProvider interface {
+	GotoLineColumn(line, column int, mode engine.ClampMode) (buffer.ByteOffset, error)
+}
+
+// EngineGotoLineColumn returns the offset of a line and byte column, clamped
+// or rejected as requested by mode. Engines implementing GotoProvider
+// resolve it directly; others are copied via Text into a new engine.
+func EngineGotoLineColumn(e EngineInterface, line, column int, mode engine.ClampMode) (buffer.ByteOffset, error) {
+	if gp, ok := e.(GotoProvider); ok {
+		return gp.GotoLineColumn(line, column, mode)
+	}
+	return engine.New(engine.WithContent(e.Text())).GotoLineColumn(line, column, mode)
+}
+
+// UTF16Provider is implemented by engines that convert UTF-16 positions,
+// as used by language servers, themselves.
+type UTF16Provider interface {
+	PointUTF16ToOffset(point buffer.PointUTF16) buffer.ByteOffset
+}
+
+// EnginePointUTF16ToOffset returns the offset of a line and UTF-16 column.
+// Lines past the end clamp to the end of the buffer and columns past the
+// end of their line to the end of the line. Engines implementing
+// UTF16Provider convert it directly; others are copied via Text into a new
+// engine.
+func EnginePointUTF16ToOffset(e EngineInterface, point buffer.PointUTF16) buffer.ByteOffset {
+	if up, ok := e.(UTF16Provider); ok {
+		return up.PointUTF16ToOffset(point)
+	}
+	return engine.New(engine.WithContent(e.Text())).PointUTF16ToOffset(point)
+}
+
+// PasteProvider is implemented by engines that paste whole lines and
+// blocks at each cursor as one undoable edit.
+type PasteProvider interface {
+	PasteLinewise(cursors *cursor.CursorSet, text string, after bool) error
+	PasteBlockwise(cursors *cursor.CursorSet, lines []string, after bool) error
+}
+
 // CursorManagerInterface abstracts cursor management for handlers.
 type CursorManagerInterface interface {
 	// Primary cursor
@@ -90,6 +198,46 @@ type ModeManagerInterface interface {
 	IsAnyMode(names ...string) bool
 }
 
+// BlockInsert describes a pending visual-block insert: text typed on
+// StartLine at Column is replicated on the following lines up to EndLine
+// when insert mode is left.
+type BlockInsert struct {
+	// StartLine is the top line of the block, where text is typed.
+	StartLine uint32
+
+	// EndLine is the bottom line of the block (inclusive).
+	EndLine uint32
+
+	// Column is the character column where the text is inserted.
+	Column uint32
+
+	// Append is true for block append (A), which pads lines shorter than
+	// Column with spaces. Block insert (I) skips such lines.
+	Append bool
+}
+
+// BlockInsertTracker is implemented by mode managers that can carry a
+// visual-block insert across insert mode.
+type BlockInsertTracker interface {
+	// SetBlockInsert records a pending block insert.
+	SetBlockInsert(b BlockInsert)
+
+	// BlockInsert returns the pending block insert, if any.
+	BlockInsert() (BlockInsert, bool)
+
+	// ClearBlockInsert discards the pending block insert.
+	ClearBlockInsert()
+}
+
+// AutoIndenter is implemented by engines that indent new lines.
+type AutoIndenter interface {
+	// AutoIndentEnabled returns true if new lines should be indented.
+	AutoIndentEnabled() bool
+
+	// AutoIndent returns the leading whitespace for a line.
+	AutoIndent(line int) string
+}
+
 // ModeInterface represents an editor mode.
 type ModeInterface interface {
 	Name() string
@@ -127,6 +275,13 @@ type RendererInterface interface {
 	IsLineVisible(line uint32) bool
 }
 
+// MessageDisplay is implemented by renderers that can show a message to
+// the user, such as in a status line.
+type MessageDisplay interface {
+	// ShowMessage displays msg.
+	ShowMessage(msg string)
+}
+
 // ExecutionContext provides context for action execution.
 // It contains references to all editor subsystems needed by handlers.
 type ExecutionContext struct {
@@ -158,6 +313,9 @@ type ExecutionContext struct {
 
 	// Data holds handler-specific context data.
 	Data map[string]interface{}
+
+	// goCtx is cancelled when the user aborts the action (see Context).
+	goCtx context.Context
 }
 
 // New creates a new execution context.
@@ -217,6 +375,28 @@ func (ctx *ExecutionContext) WithRenderer(renderer RendererInterface) *Execution
 	return ctx
 }
 
+// WithContext returns the context with the cancellation context set.
+func (ctx *ExecutionContext) WithContext(c context.Context) *ExecutionContext {
+	ctx.goCtx = c
+	return ctx
+}
+
+// Context returns the context for the action, which is cancelled when the
+// user aborts it (e.g. presses Escape) or a newer action preempts it.
+// Handlers doing I/O should pass it to their requests and check Done.
+// Returns context.Background if none was set.
+func (ctx *ExecutionContext) Context() context.Context {
+	if ctx.goCtx == nil {
+		return context.Background()
+	}
+	return ctx.goCtx
+}
+
+// Cancelled returns true if the action's context has been cancelled.
+func (ctx *ExecutionContext) Cancelled() bool {
+	return ctx.Context().Err() != nil
+}
+
 // WithCount returns the context with repeat count set.
 func (ctx *ExecutionContext) WithCount(count int) *ExecutionContext {
 	if count > 0 {
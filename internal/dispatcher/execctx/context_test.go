@@ -1,6 +1,7 @@
 package execctx_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
@@ -289,3 +290,20 @@ func TestValidateForEditReadOnly(t *testing.T) {
 		t.Error("expected IsReadOnly to return true")
 	}
 }
+
+func TestContext(t *testing.T) {
+	ctx := execctx.New()
+	if ctx.Context() != context.Background() {
+		t.Error("Context() should default to context.Background()")
+	}
+	if ctx.Cancelled() {
+		t.Error("default context should not be cancelled")
+	}
+
+	c, cancel := context.WithCancel(context.Background())
+	ctx.WithContext(c)
+	cancel()
+	if !ctx.Cancelled() {
+		t.Error("Cancelled() should be true after cancel")
+	}
+}
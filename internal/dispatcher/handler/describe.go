@@ -0,0 +1,48 @@
+package handler
+
+// ActionInfo describes an action, for command palettes and generated
+// documentation.
+type ActionInfo struct {
+	// Name is the full action name (e.g., "cursor.moveDown").
+	Name string
+
+	// Namespace is the action's namespace (e.g., "cursor"). Handlers may
+	// leave it empty; the dispatcher fills it in.
+	Namespace string
+
+	// Description is a short human-readable summary.
+	Description string
+
+	// Args lists the arguments the action reads.
+	Args []ArgInfo
+}
+
+// ArgInfo describes an argument an action reads from input.ActionArgs.
+type ArgInfo struct {
+	// Name is the lowercase name of an input.ActionArgs field (e.g.,
+	// "text") or a key of its Extra map.
+	Name string
+
+	// Description is a short human-readable summary.
+	Description string
+
+	// Required is true if the action does nothing without the argument.
+	Required bool
+}
+
+// DescribableHandler is implemented by handlers that can describe the
+// actions they handle. Namespace handlers list every action in their
+// namespace.
+type DescribableHandler interface {
+	// DescribeActions returns the actions this handler handles.
+	DescribeActions() []ActionInfo
+}
+
+// DescribeActions returns the actions described by h, or nil if h does
+// not implement DescribableHandler.
+func DescribeActions(h any) []ActionInfo {
+	if d, ok := h.(DescribableHandler); ok {
+		return d.DescribeActions()
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package handler
+
+import "github.com/dshills/keystorm/internal/dispatcher/execctx"
+
+// ViewEffect is an effect of an action on the view, declared by a handler
+// in Result.Effects and applied by the dispatcher after the handler
+// returns. New effects implement Apply; the dispatcher needs no changes.
+type ViewEffect interface {
+	// Apply performs the effect. Effects do nothing when the subsystems
+	// they need are missing from the context.
+	Apply(ctx *execctx.ExecutionContext)
+}
+
+// ScrollTo scrolls the view to a position.
+type ScrollTo struct {
+	// Line is the target line number.
+	Line uint32
+	// Column is the target column number.
+	Column uint32
+}
+
+// Apply scrolls the renderer to the target.
+func (e ScrollTo) Apply(ctx *execctx.ExecutionContext) {
+	if ctx.Renderer != nil {
+		ctx.Renderer.ScrollTo(e.Line, e.Column)
+	}
+}
+
+// CenterLine centers the view on a line.
+type CenterLine struct {
+	// Line is the line to center on.
+	Line uint32
+}
+
+// Apply centers the renderer on the line.
+func (e CenterLine) Apply(ctx *execctx.ExecutionContext) {
+	if ctx.Renderer != nil {
+		ctx.Renderer.CenterOnLine(e.Line)
+	}
+}
+
+// RevealCursor scrolls the view, if needed, so the primary cursor is
+// visible.
+type RevealCursor struct{}
+
+// Apply scrolls the renderer to the primary cursor's line if it is not
+// visible.
+func (RevealCursor) Apply(ctx *execctx.ExecutionContext) {
+	if ctx.Renderer == nil || ctx.Engine == nil || ctx.Cursors == nil {
+		return
+	}
+	point := ctx.Engine.OffsetToPoint(ctx.Cursors.Primary().Cursor())
+	if !ctx.Renderer.IsLineVisible(point.Line) {
+		ctx.Renderer.ScrollToReveal(point.Line, point.Column)
+	}
+}
+
+// CenterCursor centers the view on the primary cursor.
+type CenterCursor struct{}
+
+// Apply centers the renderer on the primary cursor's line.
+func (CenterCursor) Apply(ctx *execctx.ExecutionContext) {
+	if ctx.Renderer == nil || ctx.Engine == nil || ctx.Cursors == nil {
+		return
+	}
+	point := ctx.Engine.OffsetToPoint(ctx.Cursors.Primary().Cursor())
+	ctx.Renderer.CenterOnLine(point.Line)
+}
+
+// Redraw redraws the view.
+type Redraw struct {
+	// Lines are the lines to redraw. Empty redraws the entire view.
+	Lines []uint32
+}
+
+// Apply redraws the lines, or the entire view.
+func (e Redraw) Apply(ctx *execctx.ExecutionContext) {
+	if ctx.Renderer == nil {
+		return
+	}
+	if len(e.Lines) == 0 {
+		ctx.Renderer.Redraw()
+	} else {
+		ctx.Renderer.RedrawLines(e.Lines)
+	}
+}
+
+// ShowMessage shows a message to the user.
+type ShowMessage struct {
+	// Text is the message.
+	Text string
+}
+
+// Apply shows the message if the renderer implements
+// execctx.MessageDisplay.
+func (e ShowMessage) Apply(ctx *execctx.ExecutionContext) {
+	if display, ok := ctx.Renderer.(execctx.MessageDisplay); ok {
+		display.ShowMessage(e.Text)
+	}
+}
+
+// EnterMode switches to a mode.
+type EnterMode struct {
+	// Mode is the name of the mode.
+	Mode string
+}
+
+// Apply switches the mode manager to the mode.
+func (e EnterMode) Apply(ctx *execctx.ExecutionContext) {
+	if ctx.ModeManager != nil {
+		_ = ctx.ModeManager.Switch(e.Mode)
+	}
+}
@@ -2,6 +2,8 @@
 package handler
 
 import (
+	"sort"
+
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	"github.com/dshills/keystorm/internal/input"
 )
@@ -119,6 +121,11 @@ func (a *namespaceAdapter) Priority() int {
 	return 0
 }
 
+// DescribeActions forwards to the namespace handler, if it is describable.
+func (a *namespaceAdapter) DescribeActions() []ActionInfo {
+	return DescribeActions(a.h)
+}
+
 // BaseNamespaceHandler provides a base implementation for namespace handlers.
 type BaseNamespaceHandler struct {
 	namespace string
@@ -149,6 +156,19 @@ func (h *BaseNamespaceHandler) CanHandle(actionName string) bool {
 	return ok
 }
 
+// DescribeActions implements DescribableHandler, listing the registered
+// action names in order.
+func (h *BaseNamespaceHandler) DescribeActions() []ActionInfo {
+	actions := make([]ActionInfo, 0, len(h.actions))
+	for name := range h.actions {
+		actions = append(actions, ActionInfo{Name: name, Namespace: h.namespace})
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].Name < actions[j].Name
+	})
+	return actions
+}
+
 // HandleAction implements NamespaceHandler.HandleAction.
 func (h *BaseNamespaceHandler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) Result {
 	fn, ok := h.actions[action.Name]
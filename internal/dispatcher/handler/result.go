@@ -20,6 +20,12 @@ const (
 	StatusAsync
 	// StatusCancelled indicates the operation was cancelled.
 	StatusCancelled
+	// StatusThrottled indicates the action was not executed because it was
+	// dispatched again too soon.
+	StatusThrottled
+	// StatusFallthrough indicates the handler declined the action, so the
+	// next handler that can handle it should be tried.
+	StatusFallthrough
 )
 
 // String returns a string representation of the status.
@@ -35,6 +41,10 @@ func (s ResultStatus) String() string {
 		return "async"
 	case StatusCancelled:
 		return "cancelled"
+	case StatusThrottled:
+		return "throttled"
+	case StatusFallthrough:
+		return "fallthrough"
 	default:
 		return "unknown"
 	}
@@ -105,6 +115,10 @@ type Result struct {
 	// ViewUpdate indicates required view updates.
 	ViewUpdate ViewUpdate
 
+	// Effects are view effects the dispatcher applies in order after the
+	// mode change and view update.
+	Effects []ViewEffect
+
 	// RegisterContent holds text to be stored in a register (for yank/delete).
 	RegisterContent string
 
@@ -120,6 +134,11 @@ func (r Result) IsOK() bool {
 	return r.Status == StatusOK
 }
 
+// IsFallthrough returns true if the handler declined the action.
+func (r Result) IsFallthrough() bool {
+	return r.Status == StatusFallthrough
+}
+
 // IsError returns true if the result indicates an error.
 func (r Result) IsError() bool {
 	return r.Status == StatusError
@@ -186,6 +205,17 @@ func CancelledWithMessage(msg string) Result {
 	return Result{Status: StatusCancelled, Message: msg}
 }
 
+// Throttled creates a throttled result with a message.
+func Throttled(msg string) Result {
+	return Result{Status: StatusThrottled, Message: msg}
+}
+
+// Fallthrough creates a result declining the action so that the next
+// handler in priority order handles it instead.
+func Fallthrough() Result {
+	return Result{Status: StatusFallthrough}
+}
+
 // WithMessage returns a copy of the result with the specified message.
 func (r Result) WithMessage(msg string) Result {
 	r.Message = msg
@@ -232,6 +262,43 @@ func (r Result) WithRedrawLines(lines ...uint32) Result {
 	return r
 }
 
+// WithEffects returns a copy of the result with view effects added.
+func (r Result) WithEffects(effects ...ViewEffect) Result {
+	r.Effects = append(r.Effects, effects...)
+	return r
+}
+
+// AllEffects returns the view effects of the result in the order the
+// dispatcher applies them. ModeChange and ViewUpdate are shorthand for
+// EnterMode, Redraw, ScrollTo and CenterLine effects and come first;
+// without a scroll target the view reveals the cursor. Effects follow.
+func (r Result) AllEffects() []ViewEffect {
+	var effects []ViewEffect
+	if r.ModeChange != "" {
+		effects = append(effects, EnterMode{Mode: r.ModeChange})
+	}
+
+	vu := r.ViewUpdate
+	if vu.Redraw {
+		effects = append(effects, Redraw{})
+	} else if len(vu.RedrawLines) > 0 {
+		effects = append(effects, Redraw{Lines: vu.RedrawLines})
+	}
+
+	switch {
+	case vu.ScrollTo != nil && vu.ScrollTo.Center:
+		effects = append(effects, CenterLine{Line: vu.ScrollTo.Line})
+	case vu.ScrollTo != nil:
+		effects = append(effects, ScrollTo{Line: vu.ScrollTo.Line, Column: vu.ScrollTo.Column})
+	case vu.CenterLine != nil:
+		effects = append(effects, CenterLine{Line: *vu.CenterLine})
+	default:
+		effects = append(effects, RevealCursor{})
+	}
+
+	return append(effects, r.Effects...)
+}
+
 // WithEdit returns a copy of the result with an edit added.
 func (r Result) WithEdit(edit Edit) Result {
 	r.Edits = append(r.Edits, edit)
@@ -2,6 +2,7 @@ package handler_test
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/dshills/keystorm/internal/dispatcher/handler"
@@ -17,6 +18,8 @@ func TestResultStatus(t *testing.T) {
 		{handler.StatusError, "error"},
 		{handler.StatusAsync, "async"},
 		{handler.StatusCancelled, "cancelled"},
+		{handler.StatusThrottled, "throttled"},
+		{handler.StatusFallthrough, "fallthrough"},
 		{handler.ResultStatus(99), "unknown"},
 	}
 
@@ -327,6 +330,43 @@ func TestResultWithCursorDelta(t *testing.T) {
 	}
 }
 
+func TestResultWithEffects(t *testing.T) {
+	result := handler.Success().
+		WithEffects(handler.ScrollTo{Line: 10}).
+		WithEffects(handler.Redraw{}, handler.EnterMode{Mode: "normal"})
+
+	if len(result.Effects) != 3 {
+		t.Fatalf("expected 3 effects, got %d", len(result.Effects))
+	}
+	if st, ok := result.Effects[0].(handler.ScrollTo); !ok || st.Line != 10 {
+		t.Errorf("expected ScrollTo line 10 first, got %#v", result.Effects[0])
+	}
+}
+
+func TestResultAllEffects(t *testing.T) {
+	result := handler.Success().
+		WithModeChange("insert").
+		WithRedrawLines(3).
+		WithScrollTo(7, 0, true).
+		WithEffects(handler.ShowMessage{Text: "done"})
+
+	want := []handler.ViewEffect{
+		handler.EnterMode{Mode: "insert"},
+		handler.Redraw{Lines: []uint32{3}},
+		handler.CenterLine{Line: 7},
+		handler.ShowMessage{Text: "done"},
+	}
+	if got := result.AllEffects(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllEffects() = %#v, want %#v", got, want)
+	}
+
+	// Without a scroll target the cursor is revealed
+	got := handler.Success().AllEffects()
+	if len(got) != 1 || got[0] != (handler.RevealCursor{}) {
+		t.Errorf("AllEffects() = %#v, want [RevealCursor{}]", got)
+	}
+}
+
 func TestResultGetData(t *testing.T) {
 	result := handler.Success().WithData("key", "value")
 
@@ -31,6 +31,11 @@ func (h *CombinedHandler) CanHandle(actionName string) bool {
 	return h.basic.CanHandle(actionName) || h.motion.CanHandle(actionName)
 }
 
+// DescribeActions returns the actions of the basic and motion handlers.
+func (h *CombinedHandler) DescribeActions() []handler.ActionInfo {
+	return append(h.basic.DescribeActions(), h.motion.DescribeActions()...)
+}
+
 // HandleAction processes a cursor action by delegating to the appropriate handler.
 func (h *CombinedHandler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	// Try basic handler first
@@ -46,6 +46,20 @@ func (h *Handler) CanHandle(actionName string) bool {
 	return false
 }
 
+// DescribeActions returns the cursor movement actions.
+func (h *Handler) DescribeActions() []handler.ActionInfo {
+	return []handler.ActionInfo{
+		{Name: ActionMoveLeft, Description: "Move cursor left"},
+		{Name: ActionMoveRight, Description: "Move cursor right"},
+		{Name: ActionMoveUp, Description: "Move cursor up"},
+		{Name: ActionMoveDown, Description: "Move cursor down"},
+		{Name: ActionMoveLineStart, Description: "Move cursor to start of line"},
+		{Name: ActionMoveLineEnd, Description: "Move cursor to end of line"},
+		{Name: ActionMoveFirstLine, Description: "Move cursor to first line"},
+		{Name: ActionMoveLastLine, Description: "Move cursor to last line"},
+	}
+}
+
 // HandleAction processes a cursor action.
 func (h *Handler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	// Validate context
@@ -3,7 +3,11 @@ package cursor_test
 import (
 	"testing"
 
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	cursorhandler "github.com/dshills/keystorm/internal/dispatcher/handlers/cursor"
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/cursor"
 	"github.com/dshills/keystorm/internal/input"
 )
 
@@ -386,3 +390,42 @@ func TestActionForInput(t *testing.T) {
 		t.Errorf("expected count 5, got %d", action.Count)
 	}
 }
+
+// textEngine implements the parts of execctx.EngineInterface that go-to
+// motions read over a real engine; other methods panic.
+type textEngine struct {
+	execctx.EngineInterface
+	eng *engine.Engine
+}
+
+func (e textEngine) Text() string                                   { return e.eng.Text() }
+func (e textEngine) OffsetToPoint(o buffer.ByteOffset) buffer.Point { return e.eng.OffsetToPoint(o) }
+
+func TestGotoLineClamps(t *testing.T) {
+	tests := []struct {
+		name string
+		args input.ActionArgs
+		want buffer.ByteOffset
+	}{
+		{"ex command digits", input.ActionArgs{Extra: map[string]interface{}{"line": "2"}}, 4},
+		{"int argument", input.ActionArgs{Extra: map[string]interface{}{"line": 3}}, 8},
+		{"past last line", input.ActionArgs{Extra: map[string]interface{}{"line": "42"}}, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eng := textEngine{eng: engine.New(engine.WithContent("one\ntwo\nthree"))}
+			cursors := cursor.NewCursorSetAt(0)
+			ctx := execctx.New().WithEngine(eng).WithCursors(cursors)
+
+			h := cursorhandler.NewMotionHandler()
+			result := h.HandleAction(input.Action{Name: cursorhandler.ActionGotoLine, Args: tt.args}, ctx)
+			if result.Error != nil {
+				t.Fatalf("HandleAction() error = %v", result.Error)
+			}
+			if got := cursors.Primary().Head; got != tt.want {
+				t.Errorf("cursor = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
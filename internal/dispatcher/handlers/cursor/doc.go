@@ -41,6 +41,10 @@
 //   - cursor.sentenceForward ()): Move to next sentence
 //   - cursor.sentenceBackward ((): Move to previous sentence
 //
+// Boundaries follow Vim's definitions and are computed by the engine's
+// cursor package (cursor.NextParagraph, cursor.NextSentence, ...), which the
+// operator text objects share.
+//
 // # Screen Motions
 //
 //   - cursor.screenTop (H): Move to top of visible screen
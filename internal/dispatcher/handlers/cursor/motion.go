@@ -1,13 +1,16 @@
 package cursor
 
 import (
+	"strconv"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
 	"github.com/dshills/keystorm/internal/engine/buffer"
 	"github.com/dshills/keystorm/internal/engine/cursor"
+	"github.com/dshills/keystorm/internal/engine/rope"
 	"github.com/dshills/keystorm/internal/input"
 )
 
@@ -67,6 +70,30 @@ func (h *MotionHandler) CanHandle(actionName string) bool {
 	return false
 }
 
+// DescribeActions returns the motion actions.
+func (h *MotionHandler) DescribeActions() []handler.ActionInfo {
+	return []handler.ActionInfo{
+		{Name: ActionWordForward, Description: "Move to start of next word"},
+		{Name: ActionWordBackward, Description: "Move to start of previous word"},
+		{Name: ActionWordEndForward, Description: "Move to end of word"},
+		{Name: ActionBigWordForward, Description: "Move to start of next WORD"},
+		{Name: ActionBigWordBackward, Description: "Move to start of previous WORD"},
+		{Name: ActionBigWordEndForward, Description: "Move to end of WORD"},
+		{Name: ActionFirstNonBlank, Description: "Move to first non-blank character of line"},
+		{Name: ActionGotoLine, Description: "Go to line given by count"},
+		{Name: ActionGotoColumn, Description: "Go to column given by count"},
+		{Name: ActionMatchingBracket, Description: "Jump to matching bracket"},
+		{Name: ActionGotoPercent, Description: "Go to percentage of file given by count"},
+		{Name: ActionParagraphForward, Description: "Move to next paragraph"},
+		{Name: ActionParagraphBackward, Description: "Move to previous paragraph"},
+		{Name: ActionSentenceForward, Description: "Move to next sentence"},
+		{Name: ActionSentenceBackward, Description: "Move to previous sentence"},
+		{Name: ActionScreenTop, Description: "Move to top of screen"},
+		{Name: ActionScreenMiddle, Description: "Move to middle of screen"},
+		{Name: ActionScreenBottom, Description: "Move to bottom of screen"},
+	}
+}
+
 // HandleAction processes a motion action.
 func (h *MotionHandler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	if ctx.Engine == nil {
@@ -97,7 +124,7 @@ func (h *MotionHandler) HandleAction(action input.Action, ctx *execctx.Execution
 	case ActionFirstNonBlank:
 		return h.firstNonBlank(ctx)
 	case ActionGotoLine:
-		return h.gotoLine(ctx, count)
+		return h.gotoLine(ctx, gotoLineArg(action, count))
 	case ActionGotoColumn:
 		return h.gotoColumn(ctx, count)
 	case ActionMatchingBracket:
@@ -134,12 +161,13 @@ func (h *MotionHandler) wordForward(ctx *execctx.ExecutionContext, count int, bi
 	engine := ctx.Engine
 	text := engine.Text()
 	maxOffset := engine.Len()
+	keywords := execctx.EngineKeywords(engine)
 
 	ctx.Cursors.MapInPlace(func(sel cursor.Selection) cursor.Selection {
 		offset := sel.Head
 
 		for i := 0; i < count && offset < maxOffset; i++ {
-			offset = findNextWordStart(text, offset, maxOffset, bigWord)
+			offset = keywords.NextWordStart(text, offset, bigWord)
 		}
 
 		if ctx.HasSelection() {
@@ -155,12 +183,13 @@ func (h *MotionHandler) wordForward(ctx *execctx.ExecutionContext, count int, bi
 func (h *MotionHandler) wordBackward(ctx *execctx.ExecutionContext, count int, bigWord bool) handler.Result {
 	engine := ctx.Engine
 	text := engine.Text()
+	keywords := execctx.EngineKeywords(engine)
 
 	ctx.Cursors.MapInPlace(func(sel cursor.Selection) cursor.Selection {
 		offset := sel.Head
 
 		for i := 0; i < count && offset > 0; i++ {
-			offset = findPrevWordStart(text, offset, bigWord)
+			offset = keywords.PrevWordStart(text, offset, bigWord)
 		}
 
 		if ctx.HasSelection() {
@@ -177,12 +206,13 @@ func (h *MotionHandler) wordEndForward(ctx *execctx.ExecutionContext, count int,
 	engine := ctx.Engine
 	text := engine.Text()
 	maxOffset := engine.Len()
+	keywords := execctx.EngineKeywords(engine)
 
 	ctx.Cursors.MapInPlace(func(sel cursor.Selection) cursor.Selection {
 		offset := sel.Head
 
 		for i := 0; i < count && offset < maxOffset; i++ {
-			offset = findWordEnd(text, offset, maxOffset, bigWord)
+			offset = keywords.WordEnd(text, offset, bigWord)
 		}
 
 		if ctx.HasSelection() {
@@ -223,23 +253,31 @@ func (h *MotionHandler) firstNonBlank(ctx *execctx.ExecutionContext) handler.Res
 	return handler.Success().WithRedraw()
 }
 
+// gotoLineArg returns the line given by the action's "line" argument, as
+// an integer or the digits typed in ":42", or count without it.
+func gotoLineArg(action input.Action, count int) int {
+	v, ok := action.Args.Get("line")
+	if !ok {
+		return count
+	}
+	if s, ok := v.(string); ok {
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+		return count
+	}
+	return action.Args.GetInt("line")
+}
+
 // gotoLine moves to a specific line (1-indexed from user perspective).
+// Lines past the end of the buffer go to the last line.
 func (h *MotionHandler) gotoLine(ctx *execctx.ExecutionContext, lineNum int) handler.Result {
-	engine := ctx.Engine
-	lineCount := int(engine.LineCount())
-
-	// Clamp line number (convert from 1-indexed to 0-indexed)
-	targetLine := lineNum - 1
-	if targetLine < 0 {
-		targetLine = 0
-	}
-	if targetLine >= lineCount {
-		targetLine = lineCount - 1
+	newOffset, err := execctx.EngineGotoLineColumn(ctx.Engine, lineNum, 1, engine.ClampAll|engine.OneIndexed)
+	if err != nil {
+		return handler.Error(err)
 	}
 
 	ctx.Cursors.MapInPlace(func(sel cursor.Selection) cursor.Selection {
-		newOffset := engine.LineStartOffset(uint32(targetLine))
-
 		if ctx.HasSelection() {
 			return sel.Extend(newOffset)
 		}
@@ -251,20 +289,17 @@ func (h *MotionHandler) gotoLine(ctx *execctx.ExecutionContext, lineNum int) han
 
 // gotoColumn moves to a specific column on the current line.
 func (h *MotionHandler) gotoColumn(ctx *execctx.ExecutionContext, col int) handler.Result {
-	engine := ctx.Engine
+	eng := ctx.Engine
 
 	ctx.Cursors.MapInPlace(func(sel cursor.Selection) cursor.Selection {
-		point := engine.OffsetToPoint(sel.Head)
+		point := eng.OffsetToPoint(sel.Head)
 
-		// Clamp column (convert from 1-indexed to 0-indexed)
-		targetCol := uint32(col - 1)
-		lineLen := engine.LineLen(point.Line)
-		if targetCol > lineLen {
-			targetCol = lineLen
+		// Clamp the 1-indexed column to the line
+		newOffset, err := execctx.EngineGotoLineColumn(eng, int(point.Line)+1, col, engine.ClampColumn|engine.OneIndexed)
+		if err != nil {
+			return sel
 		}
 
-		newOffset := engine.PointToOffset(buffer.Point{Line: point.Line, Column: targetCol})
-
 		if ctx.HasSelection() {
 			return sel.Extend(newOffset)
 		}
@@ -359,104 +394,37 @@ func (h *MotionHandler) gotoPercent(ctx *execctx.ExecutionContext, percent int)
 
 // paragraphForward moves forward to the next paragraph boundary.
 func (h *MotionHandler) paragraphForward(ctx *execctx.ExecutionContext, count int) handler.Result {
-	engine := ctx.Engine
-	lineCount := engine.LineCount()
-
-	// Handle empty buffer
-	if lineCount == 0 {
-		return handler.NoOp()
-	}
-
-	lastLine := lineCount - 1
-
-	ctx.Cursors.MapInPlace(func(sel cursor.Selection) cursor.Selection {
-		point := engine.OffsetToPoint(sel.Head)
-		line := point.Line
-
-		for i := 0; i < count && line < lastLine; i++ {
-			// Skip non-empty lines
-			for line < lastLine && !isEmptyLine(engine, line) {
-				line++
-			}
-			// Skip empty lines
-			for line < lastLine && isEmptyLine(engine, line) {
-				line++
-			}
-		}
-
-		newOffset := engine.LineStartOffset(line)
-
-		if ctx.HasSelection() {
-			return sel.Extend(newOffset)
-		}
-		return sel.MoveTo(newOffset)
-	})
-
-	return handler.Success().WithRedraw()
+	return h.boundaryMotion(ctx, count, cursor.NextParagraph)
 }
 
 // paragraphBackward moves backward to the previous paragraph boundary.
 func (h *MotionHandler) paragraphBackward(ctx *execctx.ExecutionContext, count int) handler.Result {
-	engine := ctx.Engine
-
-	ctx.Cursors.MapInPlace(func(sel cursor.Selection) cursor.Selection {
-		point := engine.OffsetToPoint(sel.Head)
-		line := point.Line
-
-		for i := 0; i < count && line > 0; i++ {
-			// Skip empty lines
-			for line > 0 && isEmptyLine(engine, line) {
-				line--
-			}
-			// Skip non-empty lines
-			for line > 0 && !isEmptyLine(engine, line) {
-				line--
-			}
-		}
-
-		newOffset := engine.LineStartOffset(line)
-
-		if ctx.HasSelection() {
-			return sel.Extend(newOffset)
-		}
-		return sel.MoveTo(newOffset)
-	})
-
-	return handler.Success().WithRedraw()
+	return h.boundaryMotion(ctx, count, cursor.PrevParagraph)
 }
 
 // sentenceForward moves forward to the start of the next sentence.
 func (h *MotionHandler) sentenceForward(ctx *execctx.ExecutionContext, count int) handler.Result {
-	engine := ctx.Engine
-	text := engine.Text()
-	maxOffset := engine.Len()
-
-	ctx.Cursors.MapInPlace(func(sel cursor.Selection) cursor.Selection {
-		offset := sel.Head
-
-		for i := 0; i < count && offset < maxOffset; i++ {
-			offset = findNextSentenceStart(text, offset, maxOffset)
-		}
-
-		if ctx.HasSelection() {
-			return sel.Extend(offset)
-		}
-		return sel.MoveTo(offset)
-	})
-
-	return handler.Success().WithRedraw()
+	return h.boundaryMotion(ctx, count, cursor.NextSentence)
 }
 
 // sentenceBackward moves backward to the start of the previous sentence.
 func (h *MotionHandler) sentenceBackward(ctx *execctx.ExecutionContext, count int) handler.Result {
-	engine := ctx.Engine
-	text := engine.Text()
+	return h.boundaryMotion(ctx, count, cursor.PrevSentence)
+}
+
+// boundaryMotion moves each cursor count times using a boundary function
+// from the cursor package.
+func (h *MotionHandler) boundaryMotion(ctx *execctx.ExecutionContext, count int, next func(rope.Rope, cursor.ByteOffset) cursor.ByteOffset) handler.Result {
+	r := execctx.EngineRope(ctx.Engine)
 
 	ctx.Cursors.MapInPlace(func(sel cursor.Selection) cursor.Selection {
 		offset := sel.Head
-
-		for i := 0; i < count && offset > 0; i++ {
-			offset = findPrevSentenceStart(text, offset)
+		for i := 0; i < count; i++ {
+			moved := next(r, offset)
+			if moved == offset {
+				break
+			}
+			offset = moved
 		}
 
 		if ctx.HasSelection() {
@@ -560,148 +528,6 @@ func (h *MotionHandler) screenBottom(ctx *execctx.ExecutionContext) handler.Resu
 
 // Helper functions
 
-// findNextWordStart finds the start of the next word.
-func findNextWordStart(text string, offset, maxOffset buffer.ByteOffset, bigWord bool) buffer.ByteOffset {
-	textLen := buffer.ByteOffset(len(text))
-	if maxOffset > textLen {
-		maxOffset = textLen
-	}
-	if offset >= maxOffset {
-		return maxOffset
-	}
-	if offset < 0 {
-		offset = 0
-	}
-
-	// First, skip current word (if any)
-	inWord := false
-	for offset < maxOffset {
-		r, size := utf8.DecodeRuneInString(text[offset:])
-		if size == 0 {
-			break
-		}
-		isWS := unicode.IsSpace(r)
-		isWordChar := isWordCharacter(r, bigWord)
-
-		if !inWord && isWordChar {
-			inWord = true
-		} else if inWord && !isWordChar {
-			// Reached end of word
-			break
-		}
-
-		offset += buffer.ByteOffset(size)
-		if !inWord && !isWS {
-			// Skip punctuation
-			continue
-		}
-	}
-
-	// Then, skip whitespace to find next word
-	for offset < maxOffset {
-		r, size := utf8.DecodeRuneInString(text[offset:])
-		if !unicode.IsSpace(r) {
-			break
-		}
-		offset += buffer.ByteOffset(size)
-	}
-
-	return offset
-}
-
-// findPrevWordStart finds the start of the previous word.
-func findPrevWordStart(text string, offset buffer.ByteOffset, bigWord bool) buffer.ByteOffset {
-	textLen := buffer.ByteOffset(len(text))
-	if offset <= 0 {
-		return 0
-	}
-	if offset > textLen {
-		offset = textLen
-	}
-
-	// Move back one character to start
-	offset = prevRuneStart(text, offset)
-
-	// Skip whitespace backwards
-	for offset > 0 {
-		r, _ := utf8.DecodeRuneInString(text[offset:])
-		if !unicode.IsSpace(r) {
-			break
-		}
-		offset = prevRuneStart(text, offset)
-	}
-
-	// Find the start of the word
-	for offset > 0 {
-		prevOffset := prevRuneStart(text, offset)
-		r, _ := utf8.DecodeRuneInString(text[prevOffset:])
-		if !isWordCharacter(r, bigWord) {
-			break
-		}
-		offset = prevOffset
-	}
-
-	return offset
-}
-
-// findWordEnd finds the end of the current or next word.
-func findWordEnd(text string, offset, maxOffset buffer.ByteOffset, bigWord bool) buffer.ByteOffset {
-	textLen := buffer.ByteOffset(len(text))
-	if maxOffset > textLen {
-		maxOffset = textLen
-	}
-	if offset >= maxOffset {
-		return maxOffset
-	}
-	if offset < 0 {
-		offset = 0
-	}
-
-	// Move forward one to get off current position
-	_, size := utf8.DecodeRuneInString(text[offset:])
-	if size == 0 {
-		return offset
-	}
-	offset += buffer.ByteOffset(size)
-
-	// Skip whitespace
-	for offset < maxOffset {
-		r, size := utf8.DecodeRuneInString(text[offset:])
-		if !unicode.IsSpace(r) {
-			break
-		}
-		offset += buffer.ByteOffset(size)
-	}
-
-	// Find end of word
-	for offset < maxOffset {
-		_, size := utf8.DecodeRuneInString(text[offset:])
-		nextOffset := offset + buffer.ByteOffset(size)
-
-		if nextOffset >= maxOffset {
-			return offset
-		}
-
-		nextR, _ := utf8.DecodeRuneInString(text[nextOffset:])
-		if !isWordCharacter(nextR, bigWord) {
-			return offset
-		}
-
-		offset = nextOffset
-	}
-
-	return offset
-}
-
-// isWordCharacter returns true if r is a word character.
-// For bigWord, only whitespace is not a word character.
-func isWordCharacter(r rune, bigWord bool) bool {
-	if bigWord {
-		return !unicode.IsSpace(r)
-	}
-	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
-}
-
 // isBracket returns true if r is a bracket character.
 func isBracket(r rune) bool {
 	switch r {
@@ -802,110 +628,3 @@ func findMatchingBracket(text string, offset, maxOffset buffer.ByteOffset, brack
 
 	return 0, false // No match found
 }
-
-// isEmptyLine returns true if the line is empty or only whitespace.
-func isEmptyLine(engine execctx.EngineInterface, line uint32) bool {
-	lineText := engine.LineText(line)
-	for _, r := range lineText {
-		if !unicode.IsSpace(r) {
-			return false
-		}
-	}
-	return true
-}
-
-// findNextSentenceStart finds the start of the next sentence.
-func findNextSentenceStart(text string, offset, maxOffset buffer.ByteOffset) buffer.ByteOffset {
-	textLen := buffer.ByteOffset(len(text))
-	if maxOffset > textLen {
-		maxOffset = textLen
-	}
-	if offset < 0 {
-		offset = 0
-	}
-	if offset >= maxOffset {
-		return maxOffset
-	}
-
-	// A sentence ends with '.', '!', or '?' followed by whitespace
-	foundEnd := false
-
-	for offset < maxOffset {
-		r, size := utf8.DecodeRuneInString(text[offset:])
-		if size == 0 {
-			break
-		}
-
-		if r == '.' || r == '!' || r == '?' {
-			foundEnd = true
-		} else if foundEnd && !unicode.IsSpace(r) {
-			return offset
-		} else if foundEnd && r == '\n' {
-			// Double newline also ends sentence
-			nextOffset := offset + buffer.ByteOffset(size)
-			if nextOffset < maxOffset {
-				nextR, _ := utf8.DecodeRuneInString(text[nextOffset:])
-				if nextR == '\n' {
-					return nextOffset
-				}
-			}
-		}
-
-		offset += buffer.ByteOffset(size)
-	}
-
-	return maxOffset
-}
-
-// findPrevSentenceStart finds the start of the previous sentence.
-func findPrevSentenceStart(text string, offset buffer.ByteOffset) buffer.ByteOffset {
-	textLen := buffer.ByteOffset(len(text))
-	if offset <= 0 {
-		return 0
-	}
-	if offset > textLen {
-		offset = textLen
-	}
-
-	// Move back to start
-	offset = prevRuneStart(text, offset)
-
-	// Skip whitespace
-	for offset > 0 {
-		r, _ := utf8.DecodeRuneInString(text[offset:])
-		if !unicode.IsSpace(r) {
-			break
-		}
-		offset = prevRuneStart(text, offset)
-	}
-
-	// Skip to before sentence ending
-	for offset > 0 {
-		r, _ := utf8.DecodeRuneInString(text[offset:])
-		if r == '.' || r == '!' || r == '?' {
-			break
-		}
-		offset = prevRuneStart(text, offset)
-	}
-
-	// Find the actual start of this sentence
-	for offset > 0 {
-		prevOffset := prevRuneStart(text, offset)
-		r, _ := utf8.DecodeRuneInString(text[prevOffset:])
-		if r == '.' || r == '!' || r == '?' {
-			// Skip trailing whitespace of previous sentence
-			r2, _ := utf8.DecodeRuneInString(text[offset:])
-			for offset < buffer.ByteOffset(len(text)) && unicode.IsSpace(r2) {
-				_, size := utf8.DecodeRuneInString(text[offset:])
-				offset += buffer.ByteOffset(size)
-				if offset < buffer.ByteOffset(len(text)) {
-					r2, _ = utf8.DecodeRuneInString(text[offset:])
-				}
-			}
-			return offset
-		}
-		offset = prevOffset
-	}
-
-	return 0
-}
@@ -38,6 +38,15 @@ func (h *CombinedHandler) CanHandle(actionName string) bool {
 		h.indent.CanHandle(actionName)
 }
 
+// DescribeActions returns the actions of all editor handlers.
+func (h *CombinedHandler) DescribeActions() []handler.ActionInfo {
+	var actions []handler.ActionInfo
+	actions = append(actions, h.insert.DescribeActions()...)
+	actions = append(actions, h.delete.DescribeActions()...)
+	actions = append(actions, h.yank.DescribeActions()...)
+	return append(actions, h.indent.DescribeActions()...)
+}
+
 // HandleAction processes an editor action by delegating to the appropriate handler.
 func (h *CombinedHandler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	if h.insert.CanHandle(action.Name) {
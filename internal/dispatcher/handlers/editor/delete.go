@@ -47,6 +47,19 @@ func (h *DeleteHandler) CanHandle(actionName string) bool {
 	return false
 }
 
+// DescribeActions returns the delete actions.
+func (h *DeleteHandler) DescribeActions() []handler.ActionInfo {
+	return []handler.ActionInfo{
+		{Name: ActionDeleteChar, Description: "Delete character under cursor"},
+		{Name: ActionDeleteCharBack, Description: "Delete character before cursor"},
+		{Name: ActionDeleteLine, Description: "Delete line"},
+		{Name: ActionDeleteToEnd, Description: "Delete to end of line"},
+		{Name: ActionDeleteSelection, Description: "Delete selected text"},
+		{Name: ActionDeleteWord, Description: "Delete word"},
+		{Name: ActionDeleteWordBack, Description: "Delete word backward"},
+	}
+}
+
 // HandleAction processes a delete action.
 func (h *DeleteHandler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	if err := ctx.ValidateForEdit(); err != nil {
@@ -361,6 +374,7 @@ func (h *DeleteHandler) deleteSelection(ctx *execctx.ExecutionContext) handler.R
 func (h *DeleteHandler) deleteWord(ctx *execctx.ExecutionContext, count int) handler.Result {
 	engine := ctx.Engine
 	cursors := ctx.Cursors
+	keywords := execctx.EngineKeywords(engine)
 
 	if ctx.History != nil && cursors.Count() > 1 {
 		ctx.History.BeginGroup("deleteWord")
@@ -379,12 +393,9 @@ func (h *DeleteHandler) deleteWord(ctx *execctx.ExecutionContext, count int) han
 
 		// Get fresh text for this iteration
 		text := engine.Text()
-		textLen := buffer.ByteOffset(len(text))
 
 		// Find end of count words
-		for i := 0; i < count && end < textLen; i++ {
-			end = findNextWordStartUTF8(text, end, textLen)
-		}
+		end = keywords.OperatorWordEnd(text, start, count, false)
 
 		if start == end {
 			continue
@@ -416,6 +427,7 @@ func (h *DeleteHandler) deleteWord(ctx *execctx.ExecutionContext, count int) han
 func (h *DeleteHandler) deleteWordBack(ctx *execctx.ExecutionContext, count int) handler.Result {
 	engine := ctx.Engine
 	cursors := ctx.Cursors
+	keywords := execctx.EngineKeywords(engine)
 
 	if ctx.History != nil && cursors.Count() > 1 {
 		ctx.History.BeginGroup("deleteWordBack")
@@ -437,7 +449,7 @@ func (h *DeleteHandler) deleteWordBack(ctx *execctx.ExecutionContext, count int)
 
 		// Find start of count words backward
 		for j := 0; j < count && start > 0; j++ {
-			start = findPrevWordStartUTF8(text, start)
+			start = keywords.PrevWordStart(text, start, false)
 		}
 
 		if start == end {
@@ -515,100 +527,6 @@ func prevRuneStartUTF8(text string, offset buffer.ByteOffset) buffer.ByteOffset
 	return offset - buffer.ByteOffset(size)
 }
 
-// findNextWordStartUTF8 finds the start of the next word using proper UTF-8 iteration.
-func findNextWordStartUTF8(text string, offset, maxOffset buffer.ByteOffset) buffer.ByteOffset {
-	textLen := buffer.ByteOffset(len(text))
-	if offset >= textLen || offset >= maxOffset {
-		return min(textLen, maxOffset)
-	}
-
-	// Use for-range to properly iterate over runes
-	inWord := false
-	foundNonWord := false
-
-	for i, r := range text[offset:] {
-		pos := offset + buffer.ByteOffset(i)
-		if pos >= maxOffset {
-			return maxOffset
-		}
-
-		if isWordChar(r) {
-			if foundNonWord {
-				// Found start of next word
-				return pos
-			}
-			inWord = true
-		} else if isWhitespace(r) {
-			if inWord {
-				// Exited word, now in whitespace
-				foundNonWord = true
-			}
-		} else {
-			// Punctuation or other non-word char
-			if inWord {
-				foundNonWord = true
-			} else if foundNonWord {
-				// Found non-word, non-whitespace after whitespace
-				return pos
-			}
-		}
-	}
-
-	return min(textLen, maxOffset)
-}
-
-// findPrevWordStartUTF8 finds the start of the previous word using proper UTF-8 handling.
-func findPrevWordStartUTF8(text string, offset buffer.ByteOffset) buffer.ByteOffset {
-	if offset <= 0 {
-		return 0
-	}
-
-	textLen := buffer.ByteOffset(len(text))
-	if offset > textLen {
-		offset = textLen
-	}
-
-	// Work backwards through the string
-	// First skip any trailing whitespace
-	for offset > 0 {
-		_, size := utf8.DecodeLastRuneInString(text[:offset])
-		if size == 0 {
-			break
-		}
-		r, _ := utf8.DecodeRuneInString(text[offset-buffer.ByteOffset(size):])
-		if !isWhitespace(r) {
-			break
-		}
-		offset -= buffer.ByteOffset(size)
-	}
-
-	// Now skip word characters to find the start of the word
-	for offset > 0 {
-		_, size := utf8.DecodeLastRuneInString(text[:offset])
-		if size == 0 {
-			break
-		}
-		r, _ := utf8.DecodeRuneInString(text[offset-buffer.ByteOffset(size):])
-		if !isWordChar(r) {
-			break
-		}
-		offset -= buffer.ByteOffset(size)
-	}
-
-	return offset
-}
-
-// isWordChar returns true if r is a word character (alphanumeric or underscore).
-func isWordChar(r rune) bool {
-	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
-		(r >= '0' && r <= '9') || r == '_'
-}
-
-// isWhitespace returns true if r is whitespace.
-func isWhitespace(r rune) bool {
-	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
-}
-
 // reverseStrings reverses a slice of strings in place.
 func reverseStrings(s []string) {
 	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
@@ -68,6 +68,17 @@ func (h *IndentHandler) CanHandle(actionName string) bool {
 	return false
 }
 
+// DescribeActions returns the indentation actions.
+func (h *IndentHandler) DescribeActions() []handler.ActionInfo {
+	return []handler.ActionInfo{
+		{Name: ActionIndent, Description: "Indent line"},
+		{Name: ActionOutdent, Description: "Outdent line"},
+		{Name: ActionAutoIndent, Description: "Auto-indent selection"},
+		{Name: ActionIndentBlock, Description: "Indent block"},
+		{Name: ActionOutdentBlock, Description: "Outdent block"},
+	}
+}
+
 // HandleAction processes an indent action.
 func (h *IndentHandler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	if err := ctx.ValidateForEdit(); err != nil {
@@ -3,6 +3,7 @@ package editor
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	"github.com/dshills/keystorm/internal/dispatcher/handler"
@@ -44,6 +45,19 @@ func (h *InsertHandler) CanHandle(actionName string) bool {
 	return false
 }
 
+// DescribeActions returns the insert actions.
+func (h *InsertHandler) DescribeActions() []handler.ActionInfo {
+	textArg := []handler.ArgInfo{{Name: "text", Description: "Text to insert", Required: true}}
+	return []handler.ActionInfo{
+		{Name: ActionInsertChar, Description: "Insert a character", Args: textArg},
+		{Name: ActionInsertText, Description: "Insert text", Args: textArg},
+		{Name: ActionInsertNewline, Description: "Insert a line break"},
+		{Name: ActionInsertLineAbove, Description: "Open a line above"},
+		{Name: ActionInsertLineBelow, Description: "Open a line below"},
+		{Name: ActionInsertTab, Description: "Insert a tab or indentation"},
+	}
+}
+
 // HandleAction processes an insert action.
 func (h *InsertHandler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	if err := ctx.ValidateForEdit(); err != nil {
@@ -134,9 +148,67 @@ func (h *InsertHandler) insertText(ctx *execctx.ExecutionContext, text string) h
 	return handler.Success().WithRedrawLines(uniqueLines(affectedLines)...)
 }
 
-// insertNewline inserts a newline at all cursor positions.
+// insertNewline inserts a newline at all cursor positions. If the engine
+// auto-indents, each new line is indented and the cursors are placed after
+// the indent.
 func (h *InsertHandler) insertNewline(ctx *execctx.ExecutionContext) handler.Result {
-	return h.insertText(ctx, "\n")
+	indenter, ok := ctx.Engine.(execctx.AutoIndenter)
+	if !ok || !indenter.AutoIndentEnabled() {
+		return h.insertText(ctx, "\n")
+	}
+
+	engine := ctx.Engine
+	cursors := ctx.Cursors
+
+	if ctx.History != nil {
+		ctx.History.BeginGroup("insertNewline")
+		defer ctx.History.EndGroup()
+	}
+
+	selections := cursors.All()
+	sortSelectionsReverseInsert(selections)
+
+	var affectedLines []uint32
+
+	for i, sel := range selections {
+		insertOffset := sel.Head
+		var removed buffer.ByteOffset
+		if !sel.IsEmpty() {
+			r := sel.Range()
+			if _, err := engine.Delete(r.Start, r.End); err != nil {
+				return handler.Error(err)
+			}
+			insertOffset = r.Start
+			removed = r.End - r.Start
+		}
+
+		if _, err := engine.Insert(insertOffset, "\n"); err != nil {
+			return handler.Error(err)
+		}
+
+		// Replace the whitespace carried onto the new line with its indent
+		line := engine.OffsetToPoint(insertOffset).Line + 1
+		lineStart := engine.LineStartOffset(line)
+		text := engine.LineText(line)
+		whitespace := buffer.ByteOffset(len(text) - len(strings.TrimLeft(text, " \t")))
+		indent := indenter.AutoIndent(int(line))
+		if _, err := engine.Replace(lineStart, lineStart+whitespace, indent); err != nil {
+			return handler.Error(err)
+		}
+
+		// Selections after this one shift by the net change
+		delta := 1 + buffer.ByteOffset(len(indent)) - whitespace - removed
+		for j := 0; j < i; j++ {
+			selections[j] = selections[j].MoveTo(selections[j].Head + delta)
+		}
+		selections[i] = sel.MoveTo(lineStart + buffer.ByteOffset(len(indent)))
+
+		affectedLines = append(affectedLines, line-1, line)
+	}
+
+	cursors.SetAll(selections)
+
+	return handler.Success().WithRedrawLines(uniqueLines(affectedLines)...)
 }
 
 // insertLineAbove inserts a new line above the cursor and moves to it.
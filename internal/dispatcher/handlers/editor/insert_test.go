@@ -0,0 +1,173 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/cursor"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// indentEngine implements execctx.EngineInterface and execctx.AutoIndenter
+// over a string, computing indents with the engine's default rules.
+type indentEngine struct {
+	text    string
+	enabled bool
+}
+
+func (e *indentEngine) Insert(offset buffer.ByteOffset, text string) (buffer.EditResult, error) {
+	e.text = e.text[:offset] + text + e.text[offset:]
+	return buffer.EditResult{}, nil
+}
+
+func (e *indentEngine) Delete(start, end buffer.ByteOffset) (buffer.EditResult, error) {
+	e.text = e.text[:start] + e.text[end:]
+	return buffer.EditResult{}, nil
+}
+
+func (e *indentEngine) Replace(start, end buffer.ByteOffset, text string) (buffer.EditResult, error) {
+	e.text = e.text[:start] + text + e.text[end:]
+	return buffer.EditResult{}, nil
+}
+
+func (e *indentEngine) Text() string { return e.text }
+
+func (e *indentEngine) TextRange(start, end buffer.ByteOffset) string { return e.text[start:end] }
+
+func (e *indentEngine) LineText(line uint32) string {
+	return e.text[e.LineStartOffset(line):e.LineEndOffset(line)]
+}
+
+func (e *indentEngine) Len() buffer.ByteOffset { return buffer.ByteOffset(len(e.text)) }
+
+func (e *indentEngine) LineCount() uint32 { return uint32(strings.Count(e.text, "\n")) + 1 }
+
+func (e *indentEngine) LineStartOffset(line uint32) buffer.ByteOffset {
+	offset := 0
+	for i := uint32(0); i < line; i++ {
+		next := strings.IndexByte(e.text[offset:], '\n')
+		if next < 0 {
+			return e.Len()
+		}
+		offset += next + 1
+	}
+	return buffer.ByteOffset(offset)
+}
+
+func (e *indentEngine) LineEndOffset(line uint32) buffer.ByteOffset {
+	start := e.LineStartOffset(line)
+	if end := strings.IndexByte(e.text[start:], '\n'); end >= 0 {
+		return start + buffer.ByteOffset(end)
+	}
+	return e.Len()
+}
+
+func (e *indentEngine) LineLen(line uint32) uint32 {
+	return uint32(e.LineEndOffset(line) - e.LineStartOffset(line))
+}
+
+func (e *indentEngine) OffsetToPoint(offset buffer.ByteOffset) buffer.Point {
+	line := uint32(strings.Count(e.text[:offset], "\n"))
+	return buffer.Point{Line: line, Column: uint32(offset - e.LineStartOffset(line))}
+}
+
+func (e *indentEngine) PointToOffset(point buffer.Point) buffer.ByteOffset {
+	return e.LineStartOffset(point.Line) + buffer.ByteOffset(point.Column)
+}
+
+func (e *indentEngine) Snapshot() execctx.EngineReader { return e }
+func (e *indentEngine) RevisionID() buffer.RevisionID  { return 0 }
+
+func (e *indentEngine) AutoIndentEnabled() bool { return e.enabled }
+
+func (e *indentEngine) AutoIndent(line int) string {
+	return engine.New(engine.WithContent(e.text), engine.WithTabWidth(4)).AutoIndent(line)
+}
+
+func TestInsertNewlineAutoIndent(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		cursors    []buffer.ByteOffset
+		enabled    bool
+		wantText   string
+		wantCursor []buffer.ByteOffset
+	}{
+		{
+			name:       "disabled",
+			text:       "\tif x {",
+			cursors:    []buffer.ByteOffset{7},
+			wantText:   "\tif x {\n",
+			wantCursor: []buffer.ByteOffset{8},
+		},
+		{
+			name:       "keeps indent",
+			text:       "    x := 1",
+			cursors:    []buffer.ByteOffset{10},
+			enabled:    true,
+			wantText:   "    x := 1\n    ",
+			wantCursor: []buffer.ByteOffset{15},
+		},
+		{
+			name:       "indents after brace",
+			text:       "func f() {",
+			cursors:    []buffer.ByteOffset{10},
+			enabled:    true,
+			wantText:   "func f() {\n    ",
+			wantCursor: []buffer.ByteOffset{15},
+		},
+		{
+			name:       "dedents closing brace",
+			text:       "func f() {}",
+			cursors:    []buffer.ByteOffset{10},
+			enabled:    true,
+			wantText:   "func f() {\n}",
+			wantCursor: []buffer.ByteOffset{11},
+		},
+		{
+			name:       "multiple cursors",
+			text:       "a {\nb {",
+			cursors:    []buffer.ByteOffset{3, 7},
+			enabled:    true,
+			wantText:   "a {\n    \nb {\n    ",
+			wantCursor: []buffer.ByteOffset{8, 17},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eng := &indentEngine{text: tt.text, enabled: tt.enabled}
+			cursors := cursor.NewCursorSetAt(tt.cursors[0])
+			for _, c := range tt.cursors[1:] {
+				cursors.Add(cursor.NewCursorSelection(c))
+			}
+			ctx := execctx.New().WithEngine(eng).WithCursors(cursors)
+
+			result := NewInsertHandler().HandleAction(input.Action{Name: ActionInsertNewline}, ctx)
+			if result.Status != handler.StatusOK {
+				t.Fatalf("status = %v, error = %v", result.Status, result.Error)
+			}
+			if eng.text != tt.wantText {
+				t.Errorf("text = %q, want %q", eng.text, tt.wantText)
+			}
+
+			var got []buffer.ByteOffset
+			for _, sel := range cursors.All() {
+				got = append(got, sel.Head)
+			}
+			if len(got) != len(tt.wantCursor) {
+				t.Fatalf("cursors = %v, want %v", got, tt.wantCursor)
+			}
+			for i := range got {
+				if got[i] != tt.wantCursor[i] {
+					t.Errorf("cursors = %v, want %v", got, tt.wantCursor)
+					break
+				}
+			}
+		})
+	}
+}
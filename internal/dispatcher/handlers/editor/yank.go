@@ -3,6 +3,7 @@ package editor
 
 import (
 	"sort"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
@@ -45,6 +46,19 @@ func (h *YankHandler) CanHandle(actionName string) bool {
 	return false
 }
 
+// DescribeActions returns the yank and paste actions.
+func (h *YankHandler) DescribeActions() []handler.ActionInfo {
+	textArg := []handler.ArgInfo{{Name: "text", Description: "Text to paste", Required: true}}
+	return []handler.ActionInfo{
+		{Name: ActionYankSelection, Description: "Yank selection"},
+		{Name: ActionYankLine, Description: "Yank line"},
+		{Name: ActionYankToEnd, Description: "Yank to end of line"},
+		{Name: ActionYankWord, Description: "Yank word"},
+		{Name: ActionPasteAfter, Description: "Paste after cursor", Args: textArg},
+		{Name: ActionPasteBefore, Description: "Paste before cursor", Args: textArg},
+	}
+}
+
 // HandleAction processes a yank/paste action.
 func (h *YankHandler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	// Yank operations only need engine and cursors
@@ -67,8 +81,14 @@ func (h *YankHandler) HandleAction(action input.Action, ctx *execctx.ExecutionCo
 	case ActionYankWord:
 		return h.yankWord(ctx, count)
 	case ActionPasteAfter:
+		if result, ok := h.pasteWithProvider(ctx, action, count, true); ok {
+			return result
+		}
 		return h.pasteAfter(ctx, action.Args.Text, count)
 	case ActionPasteBefore:
+		if result, ok := h.pasteWithProvider(ctx, action, count, false); ok {
+			return result
+		}
 		return h.pasteBefore(ctx, action.Args.Text, count)
 	default:
 		return handler.Errorf("unknown yank action: %s", action.Name)
@@ -184,7 +204,7 @@ func (h *YankHandler) yankWord(ctx *execctx.ExecutionContext, count int) handler
 	cursors := ctx.Cursors
 
 	text := engine.Text()
-	textLen := buffer.ByteOffset(len(text))
+	keywords := execctx.EngineKeywords(engine)
 
 	// Sort selections for consistent ordering
 	selections := cursors.All()
@@ -193,12 +213,7 @@ func (h *YankHandler) yankWord(ctx *execctx.ExecutionContext, count int) handler
 	var yankedParts []string
 	for _, sel := range selections {
 		start := sel.Head
-		end := start
-
-		// Find end of count words using UTF-8 safe function
-		for i := 0; i < count && end < textLen; i++ {
-			end = findNextWordStartYank(text, end, textLen)
-		}
+		end := keywords.OperatorWordEnd(text, start, count, false)
 
 		if start == end {
 			continue
@@ -215,6 +230,45 @@ func (h *YankHandler) yankWord(ctx *execctx.ExecutionContext, count int) handler
 	return handler.Success().WithRegisterContent(yankedText)
 }
 
+// pasteWithProvider pastes linewise text, or a block when the "blockwise"
+// arg is set, through the engine's PasteProvider. It returns false when the
+// engine has none or the text is characterwise, leaving the paste to
+// pasteAfter and pasteBefore.
+func (h *YankHandler) pasteWithProvider(ctx *execctx.ExecutionContext, action input.Action, count int, after bool) (handler.Result, bool) {
+	provider, ok := ctx.Engine.(execctx.PasteProvider)
+	if !ok {
+		return handler.Result{}, false
+	}
+	text := action.Args.Text
+	blockwise := action.Args.GetBool("blockwise")
+	if text == "" || (!blockwise && !strings.HasSuffix(text, "\n")) {
+		return handler.Result{}, false
+	}
+
+	if err := ctx.ValidateForEdit(); err != nil {
+		return handler.Error(err), true
+	}
+
+	cursors := ctx.Cursors.Clone()
+	var err error
+	if blockwise {
+		// Each row of the block is repeated count times across
+		lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+		for i, line := range lines {
+			lines[i] = strings.Repeat(line, count)
+		}
+		err = provider.PasteBlockwise(cursors, lines, after)
+	} else {
+		err = provider.PasteLinewise(cursors, strings.Repeat(text, count), after)
+	}
+	if err != nil {
+		return handler.Error(err), true
+	}
+	ctx.Cursors.SetAll(cursors.All())
+
+	return handler.Success().WithRedraw(), true
+}
+
 // pasteAfter pastes text after cursor position.
 func (h *YankHandler) pasteAfter(ctx *execctx.ExecutionContext, text string, count int) handler.Result {
 	if text == "" {
@@ -429,56 +483,3 @@ func uniqueLinesYank(lines []uint32) []uint32 {
 
 	return result
 }
-
-// findNextWordStartYank finds the start of the next word using proper UTF-8 iteration.
-func findNextWordStartYank(text string, offset, maxOffset buffer.ByteOffset) buffer.ByteOffset {
-	textLen := buffer.ByteOffset(len(text))
-	if offset >= textLen || offset >= maxOffset {
-		return min(textLen, maxOffset)
-	}
-
-	// Use for-range to properly iterate over runes
-	inWord := false
-	foundNonWord := false
-
-	for i, r := range text[offset:] {
-		pos := offset + buffer.ByteOffset(i)
-		if pos >= maxOffset {
-			return maxOffset
-		}
-
-		if isWordCharYank(r) {
-			if foundNonWord {
-				// Found start of next word
-				return pos
-			}
-			inWord = true
-		} else if isWhitespaceYank(r) {
-			if inWord {
-				// Exited word, now in whitespace
-				foundNonWord = true
-			}
-		} else {
-			// Punctuation or other non-word char
-			if inWord {
-				foundNonWord = true
-			} else if foundNonWord {
-				// Found non-word, non-whitespace after whitespace
-				return pos
-			}
-		}
-	}
-
-	return min(textLen, maxOffset)
-}
-
-// isWordCharYank returns true if r is a word character.
-func isWordCharYank(r rune) bool {
-	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
-		(r >= '0' && r <= '9') || r == '_'
-}
-
-// isWhitespaceYank returns true if r is whitespace.
-func isWhitespaceYank(r rune) bool {
-	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
-}
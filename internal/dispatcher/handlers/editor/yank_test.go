@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/cursor"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// pasteEngine implements execctx.PasteProvider over an indentEngine by
+// pasting into an engine with the same text.
+type pasteEngine struct {
+	indentEngine
+}
+
+func (e *pasteEngine) PasteLinewise(cursors *cursor.CursorSet, text string, after bool) error {
+	eng := engine.New(engine.WithContent(e.text))
+	err := eng.PasteLinewise(cursors, text, after)
+	e.text = eng.Text()
+	return err
+}
+
+func (e *pasteEngine) PasteBlockwise(cursors *cursor.CursorSet, lines []string, after bool) error {
+	eng := engine.New(engine.WithContent(e.text))
+	err := eng.PasteBlockwise(cursors, lines, after)
+	e.text = eng.Text()
+	return err
+}
+
+func TestPasteWithProvider(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		cursor     buffer.ByteOffset
+		action     input.Action
+		count      int
+		wantText   string
+		wantCursor buffer.ByteOffset
+	}{
+		{
+			name:       "linewise after",
+			text:       "a\nb",
+			action:     input.Action{Name: ActionPasteAfter, Args: input.ActionArgs{Text: "x\n"}},
+			wantText:   "a\nx\nb",
+			wantCursor: 2,
+		},
+		{
+			name:       "linewise before with count",
+			text:       "a\nb",
+			cursor:     2,
+			action:     input.Action{Name: ActionPasteBefore, Args: input.ActionArgs{Text: "x\n"}},
+			count:      2,
+			wantText:   "a\nx\nx\nb",
+			wantCursor: 2,
+		},
+		{
+			name: "blockwise after with count",
+			text: "xy\nzw",
+			action: input.Action{Name: ActionPasteAfter, Args: input.ActionArgs{
+				Text:  "ab\ncd",
+				Extra: map[string]interface{}{"blockwise": true},
+			}},
+			count:      2,
+			wantText:   "xababy\nzcdcdw",
+			wantCursor: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eng := &pasteEngine{indentEngine{text: tt.text}}
+			cursors := cursor.NewCursorSetAt(tt.cursor)
+			ctx := execctx.New().WithEngine(eng).WithCursors(cursors).WithCount(tt.count)
+
+			result := NewYankHandler().HandleAction(tt.action, ctx)
+			if result.Status != handler.StatusOK {
+				t.Fatalf("status = %v, error = %v", result.Status, result.Error)
+			}
+			if eng.text != tt.wantText {
+				t.Errorf("text = %q, want %q", eng.text, tt.wantText)
+			}
+			if got := cursors.Primary().Head; got != tt.wantCursor {
+				t.Errorf("cursor = %d, want %d", got, tt.wantCursor)
+			}
+		})
+	}
+}
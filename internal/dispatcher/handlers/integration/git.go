@@ -1,6 +1,8 @@
 package integration
 
 import (
+	"context"
+
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	"github.com/dshills/keystorm/internal/dispatcher/handler"
 	"github.com/dshills/keystorm/internal/input"
@@ -24,52 +26,54 @@ const (
 )
 
 // GitManager provides git operations.
-// This is typically satisfied by *git.Repository.
+// Every method takes the context of the action that requested it, so that
+// Escape or a newer action stops the git commands it runs.
+// RepositoryManager adapts a *git.Repository to this interface.
 type GitManager interface {
 	// Status returns the current repository status.
-	Status() (*git.Status, error)
+	Status(ctx context.Context) (*git.Status, error)
 
 	// CurrentBranch returns the current branch name.
-	CurrentBranch() (string, error)
+	CurrentBranch(ctx context.Context) (string, error)
 
 	// Branches lists all branches.
-	Branches() ([]*git.Reference, error)
+	Branches(ctx context.Context) ([]*git.Reference, error)
 
 	// Checkout switches to a branch.
-	Checkout(branch string) error
+	Checkout(ctx context.Context, branch string) error
 
 	// Commit creates a commit with the given message.
-	Commit(message string, opts git.CommitOptions) (*git.Commit, error)
+	Commit(ctx context.Context, message string, opts git.CommitOptions) (*git.Commit, error)
 
 	// Add stages files for commit.
-	Add(paths ...string) error
+	Add(ctx context.Context, paths ...string) error
 
 	// AddAll stages all changes.
-	AddAll() error
+	AddAll(ctx context.Context) error
 
 	// Diff returns the diff for staged or unstaged changes.
-	Diff(staged bool) (string, error)
+	Diff(ctx context.Context, staged bool) (string, error)
 
 	// DiffFile returns the diff for a specific file.
-	DiffFile(path string, staged bool) (string, error)
+	DiffFile(ctx context.Context, path string, staged bool) (string, error)
 
 	// Log returns commit history.
-	Log(n int) ([]*git.Commit, error)
+	Log(ctx context.Context, n int) ([]*git.Commit, error)
 
 	// Pull pulls from remote.
-	Pull() error
+	Pull(ctx context.Context) error
 
 	// Push pushes to remote.
-	Push() error
+	Push(ctx context.Context) error
 
 	// Stash stashes changes.
-	Stash(message string) error
+	Stash(ctx context.Context, message string) error
 
 	// StashPop pops the most recent stash.
-	StashPop() error
+	StashPop(ctx context.Context) error
 
 	// Blame returns blame information for a file.
-	Blame(path string) ([]git.BlameLine, error)
+	Blame(ctx context.Context, path string) ([]git.BlameLine, error)
 }
 
 const gitManagerKey = "_git_manager"
@@ -162,7 +166,7 @@ func (h *GitHandler) status(ctx *execctx.ExecutionContext) handler.Result {
 		return handler.Errorf("git.status: no git manager available")
 	}
 
-	status, err := gm.Status()
+	status, err := gm.Status(ctx.Context())
 	if err != nil {
 		return handler.Error(err)
 	}
@@ -185,7 +189,7 @@ func (h *GitHandler) branch(ctx *execctx.ExecutionContext) handler.Result {
 		return handler.Errorf("git.branch: no git manager available")
 	}
 
-	branch, err := gm.CurrentBranch()
+	branch, err := gm.CurrentBranch(ctx.Context())
 	if err != nil {
 		return handler.Error(err)
 	}
@@ -201,7 +205,7 @@ func (h *GitHandler) branches(ctx *execctx.ExecutionContext) handler.Result {
 		return handler.Errorf("git.branches: no git manager available")
 	}
 
-	refs, err := gm.Branches()
+	refs, err := gm.Branches(ctx.Context())
 	if err != nil {
 		return handler.Error(err)
 	}
@@ -227,7 +231,7 @@ func (h *GitHandler) checkout(action input.Action, ctx *execctx.ExecutionContext
 		return handler.Errorf("git.checkout: branch required")
 	}
 
-	if err := gm.Checkout(branch); err != nil {
+	if err := gm.Checkout(ctx.Context(), branch); err != nil {
 		return handler.Error(err)
 	}
 
@@ -251,9 +255,10 @@ func (h *GitHandler) commit(action input.Action, ctx *execctx.ExecutionContext)
 		Amend:      action.Args.GetBool("amend"),
 		AllowEmpty: action.Args.GetBool("allowEmpty"),
 		SignOff:    action.Args.GetBool("signoff"),
+		NoVerify:   action.Args.GetBool("noVerify"),
 	}
 
-	commit, err := gm.Commit(message, opts)
+	commit, err := gm.Commit(ctx.Context(), message, opts)
 	if err != nil {
 		return handler.Error(err)
 	}
@@ -272,7 +277,7 @@ func (h *GitHandler) add(action input.Action, ctx *execctx.ExecutionContext) han
 
 	// Check for --all flag
 	if action.Args.GetBool("all") {
-		if err := gm.AddAll(); err != nil {
+		if err := gm.AddAll(ctx.Context()); err != nil {
 			return handler.Error(err)
 		}
 		return handler.Success().WithMessage("Staged all changes")
@@ -292,7 +297,7 @@ func (h *GitHandler) add(action input.Action, ctx *execctx.ExecutionContext) han
 		return handler.Errorf("git.add: paths required")
 	}
 
-	if err := gm.Add(paths...); err != nil {
+	if err := gm.Add(ctx.Context(), paths...); err != nil {
 		return handler.Error(err)
 	}
 
@@ -314,9 +319,9 @@ func (h *GitHandler) diff(action input.Action, ctx *execctx.ExecutionContext) ha
 	var err error
 
 	if path != "" {
-		diff, err = gm.DiffFile(path, staged)
+		diff, err = gm.DiffFile(ctx.Context(), path, staged)
 	} else {
-		diff, err = gm.Diff(staged)
+		diff, err = gm.Diff(ctx.Context(), staged)
 	}
 
 	if err != nil {
@@ -340,7 +345,7 @@ func (h *GitHandler) log(action input.Action, ctx *execctx.ExecutionContext) han
 		n = 10
 	}
 
-	commits, err := gm.Log(n)
+	commits, err := gm.Log(ctx.Context(), n)
 	if err != nil {
 		return handler.Error(err)
 	}
@@ -356,7 +361,7 @@ func (h *GitHandler) pull(ctx *execctx.ExecutionContext) handler.Result {
 		return handler.Errorf("git.pull: no git manager available")
 	}
 
-	if err := gm.Pull(); err != nil {
+	if err := gm.Pull(ctx.Context()); err != nil {
 		return handler.Error(err)
 	}
 
@@ -371,7 +376,7 @@ func (h *GitHandler) push(ctx *execctx.ExecutionContext) handler.Result {
 		return handler.Errorf("git.push: no git manager available")
 	}
 
-	if err := gm.Push(); err != nil {
+	if err := gm.Push(ctx.Context()); err != nil {
 		return handler.Error(err)
 	}
 
@@ -386,7 +391,7 @@ func (h *GitHandler) stash(action input.Action, ctx *execctx.ExecutionContext) h
 
 	// Check for pop operation
 	if action.Args.GetBool("pop") {
-		if err := gm.StashPop(); err != nil {
+		if err := gm.StashPop(ctx.Context()); err != nil {
 			return handler.Error(err)
 		}
 		return handler.Success().
@@ -395,7 +400,7 @@ func (h *GitHandler) stash(action input.Action, ctx *execctx.ExecutionContext) h
 	}
 
 	message := action.Args.GetString("message")
-	if err := gm.Stash(message); err != nil {
+	if err := gm.Stash(ctx.Context(), message); err != nil {
 		return handler.Error(err)
 	}
 
@@ -416,7 +421,7 @@ func (h *GitHandler) blame(action input.Action, ctx *execctx.ExecutionContext) h
 		return handler.Errorf("git.blame: path required")
 	}
 
-	lines, err := gm.Blame(path)
+	lines, err := gm.Blame(ctx.Context(), path)
 	if err != nil {
 		return handler.Error(err)
 	}
@@ -426,6 +431,124 @@ func (h *GitHandler) blame(action input.Action, ctx *execctx.ExecutionContext) h
 		WithData("path", path)
 }
 
+// RepositoryManager adapts a *git.Repository to GitManager. Each operation
+// runs its git commands under the context it is given.
+type RepositoryManager struct {
+	repo *git.Repository
+}
+
+// NewRepositoryManager creates a GitManager backed by repo.
+func NewRepositoryManager(repo *git.Repository) *RepositoryManager {
+	return &RepositoryManager{repo: repo}
+}
+
+// Status returns the current repository status.
+func (m *RepositoryManager) Status(ctx context.Context) (*git.Status, error) {
+	return m.repo.StatusContext(ctx)
+}
+
+// CurrentBranch returns the current branch name.
+func (m *RepositoryManager) CurrentBranch(ctx context.Context) (string, error) {
+	return m.repo.CurrentBranchContext(ctx)
+}
+
+// Branches lists the local branches.
+func (m *RepositoryManager) Branches(ctx context.Context) ([]*git.Reference, error) {
+	branches, err := m.repo.ListBranchesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]*git.Reference, len(branches))
+	for i, b := range branches {
+		refs[i] = &git.Reference{Name: b.FullName, ShortName: b.Name, Hash: b.Hash}
+	}
+	return refs, nil
+}
+
+// Checkout switches to a branch.
+func (m *RepositoryManager) Checkout(ctx context.Context, branch string) error {
+	return m.repo.SwitchBranchContext(ctx, branch)
+}
+
+// Commit creates a commit with the given message.
+func (m *RepositoryManager) Commit(ctx context.Context, message string, opts git.CommitOptions) (*git.Commit, error) {
+	return m.repo.CommitContext(ctx, message, opts)
+}
+
+// Add stages files for commit.
+func (m *RepositoryManager) Add(ctx context.Context, paths ...string) error {
+	return m.repo.StageContext(ctx, paths...)
+}
+
+// AddAll stages all changes.
+func (m *RepositoryManager) AddAll(ctx context.Context) error {
+	return m.repo.StageAllContext(ctx)
+}
+
+// Diff returns the diff for staged or unstaged changes.
+func (m *RepositoryManager) Diff(ctx context.Context, staged bool) (string, error) {
+	return m.repo.DiffRawContext(ctx, git.DiffOptions{Staged: staged, Context: -1})
+}
+
+// DiffFile returns the diff for a specific file.
+func (m *RepositoryManager) DiffFile(ctx context.Context, path string, staged bool) (string, error) {
+	return m.repo.DiffRawContext(ctx, git.DiffOptions{Staged: staged, Paths: []string{path}, Context: -1})
+}
+
+// Log returns the last n commits.
+func (m *RepositoryManager) Log(ctx context.Context, n int) ([]*git.Commit, error) {
+	return m.repo.LogContext(ctx, git.LogOptions{MaxCount: n})
+}
+
+// Pull pulls from the upstream branch and waits for it to finish.
+func (m *RepositoryManager) Pull(ctx context.Context) error {
+	events, err := m.repo.Pull(ctx, "", git.PullOptions{})
+	if err != nil {
+		return err
+	}
+	return waitProgress(events)
+}
+
+// Push pushes to the upstream branch and waits for it to finish.
+func (m *RepositoryManager) Push(ctx context.Context) error {
+	events, err := m.repo.Push(ctx, "", git.PushOptions{})
+	if err != nil {
+		return err
+	}
+	return waitProgress(events)
+}
+
+// Stash stashes changes.
+func (m *RepositoryManager) Stash(ctx context.Context, message string) error {
+	return m.repo.StashContext(ctx, message)
+}
+
+// StashPop pops the most recent stash.
+func (m *RepositoryManager) StashPop(ctx context.Context) error {
+	return m.repo.StashPopContext(ctx)
+}
+
+// Blame returns blame information for a file.
+func (m *RepositoryManager) Blame(ctx context.Context, path string) ([]git.BlameLine, error) {
+	result, err := m.repo.BlameContext(ctx, path, git.BlameOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Lines, nil
+}
+
+// waitProgress drains a remote operation's progress events and returns
+// the error it finished with.
+func waitProgress(events <-chan git.ProgressEvent) error {
+	var err error
+	for ev := range events {
+		if ev.Done {
+			err = ev.Err
+		}
+	}
+	return err
+}
+
 // Helper functions
 
 func formatStatusMessage(status *git.Status) string {
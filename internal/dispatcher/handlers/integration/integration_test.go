@@ -23,86 +23,88 @@ type mockGitManager struct {
 	commits  []*git.Commit
 	blame    []git.BlameLine
 	err      error
+	ctx      context.Context
 }
 
-func (m *mockGitManager) Status() (*git.Status, error) {
+func (m *mockGitManager) Status(ctx context.Context) (*git.Status, error) {
+	m.ctx = ctx
 	if m.err != nil {
 		return nil, m.err
 	}
 	return m.status, nil
 }
 
-func (m *mockGitManager) CurrentBranch() (string, error) {
+func (m *mockGitManager) CurrentBranch(ctx context.Context) (string, error) {
 	if m.err != nil {
 		return "", m.err
 	}
 	return m.branch, nil
 }
 
-func (m *mockGitManager) Branches() ([]*git.Reference, error) {
+func (m *mockGitManager) Branches(ctx context.Context) ([]*git.Reference, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	return m.branches, nil
 }
 
-func (m *mockGitManager) Checkout(branch string) error {
+func (m *mockGitManager) Checkout(ctx context.Context, branch string) error {
 	return m.err
 }
 
-func (m *mockGitManager) Commit(message string, opts git.CommitOptions) (*git.Commit, error) {
+func (m *mockGitManager) Commit(ctx context.Context, message string, opts git.CommitOptions) (*git.Commit, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	return m.commit, nil
 }
 
-func (m *mockGitManager) Add(paths ...string) error {
+func (m *mockGitManager) Add(ctx context.Context, paths ...string) error {
 	return m.err
 }
 
-func (m *mockGitManager) AddAll() error {
+func (m *mockGitManager) AddAll(ctx context.Context) error {
 	return m.err
 }
 
-func (m *mockGitManager) Diff(staged bool) (string, error) {
+func (m *mockGitManager) Diff(ctx context.Context, staged bool) (string, error) {
 	if m.err != nil {
 		return "", m.err
 	}
 	return m.diff, nil
 }
 
-func (m *mockGitManager) DiffFile(path string, staged bool) (string, error) {
+func (m *mockGitManager) DiffFile(ctx context.Context, path string, staged bool) (string, error) {
 	if m.err != nil {
 		return "", m.err
 	}
 	return m.diff, nil
 }
 
-func (m *mockGitManager) Log(n int) ([]*git.Commit, error) {
+func (m *mockGitManager) Log(ctx context.Context, n int) ([]*git.Commit, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	return m.commits, nil
 }
 
-func (m *mockGitManager) Pull() error {
+func (m *mockGitManager) Pull(ctx context.Context) error {
 	return m.err
 }
 
-func (m *mockGitManager) Push() error {
+func (m *mockGitManager) Push(ctx context.Context) error {
 	return m.err
 }
 
-func (m *mockGitManager) Stash(message string) error {
+func (m *mockGitManager) Stash(ctx context.Context, message string) error {
 	return m.err
 }
 
-func (m *mockGitManager) StashPop() error {
+func (m *mockGitManager) StashPop(ctx context.Context) error {
 	return m.err
 }
 
-func (m *mockGitManager) Blame(path string) ([]git.BlameLine, error) {
+func (m *mockGitManager) Blame(ctx context.Context, path string) ([]git.BlameLine, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -337,6 +339,19 @@ func TestGitHandler_Status(t *testing.T) {
 	}
 }
 
+func TestGitHandler_PassesActionContext(t *testing.T) {
+	mock := &mockGitManager{status: &git.Status{Branch: "main"}}
+	h := NewGitHandlerWithManager(mock)
+
+	goCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	h.HandleAction(input.Action{Name: ActionGitStatus}, execctx.New().WithContext(goCtx))
+
+	if mock.ctx == nil || mock.ctx.Err() != context.Canceled {
+		t.Errorf("manager context = %v, want the action's cancelled context", mock.ctx)
+	}
+}
+
 func TestGitHandler_Branch(t *testing.T) {
 	mock := &mockGitManager{branch: "feature-x"}
 	h := NewGitHandlerWithManager(mock)
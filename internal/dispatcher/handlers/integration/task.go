@@ -177,7 +177,7 @@ func (h *TaskHandler) list(ctx *execctx.ExecutionContext) handler.Result {
 	}
 
 	// Use a reasonable timeout for discovery
-	discoverCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	discoverCtx, cancel := context.WithTimeout(ctx.Context(), 10*time.Second)
 	defer cancel()
 
 	tasks, err := discoverer.DiscoverTasks(discoverCtx, workspace)
@@ -220,7 +220,7 @@ func (h *TaskHandler) run(action input.Action, ctx *execctx.ExecutionContext) ha
 	}
 
 	// Find the task
-	discoverCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	discoverCtx, cancel := context.WithTimeout(ctx.Context(), 10*time.Second)
 	defer cancel()
 
 	tasks, err := discoverer.DiscoverTasks(discoverCtx, workspace)
@@ -0,0 +1,103 @@
+package macro
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/dispatcher/hook"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// RecordMode selects what a macro records.
+type RecordMode uint8
+
+const (
+	// RecordKeys records key events. Playback feeds them through the key
+	// bindings in effect at the time, so a macro changes meaning if keys
+	// are rebound after recording.
+	RecordKeys RecordMode = iota
+
+	// RecordActions records the actions the keys resolved to. Playback
+	// re-dispatches them, independent of the current key bindings, which
+	// makes macros safe to share between keymaps.
+	RecordActions
+)
+
+// String returns the string representation of the mode.
+func (m RecordMode) String() string {
+	switch m {
+	case RecordKeys:
+		return "keys"
+	case RecordActions:
+		return "actions"
+	default:
+		return "unknown"
+	}
+}
+
+// ActionRecorder is a MacroRecorder that records dispatched actions.
+// Register it as a post-dispatch hook: while recording in RecordActions
+// mode, every action that is dispatched without error is added to the
+// macro, together with the count and register it was given. The mode can
+// be switched at any time; it starts as RecordKeys, in which the recorder
+// leaves recording to the input system.
+type ActionRecorder struct {
+	*DefaultMacroRecorder
+	mode atomic.Uint32
+}
+
+// NewActionRecorder creates a new action-level macro recorder.
+func NewActionRecorder() *ActionRecorder {
+	return &ActionRecorder{DefaultMacroRecorder: NewDefaultMacroRecorder()}
+}
+
+// Mode returns what the recorder records.
+func (r *ActionRecorder) Mode() RecordMode {
+	return RecordMode(r.mode.Load())
+}
+
+// SetMode switches between recording keys and actions. A macro being
+// recorded keeps the actions recorded so far.
+func (r *ActionRecorder) SetMode(mode RecordMode) {
+	r.mode.Store(uint32(mode))
+}
+
+// Name implements hook.Hook.
+func (r *ActionRecorder) Name() string { return "macro-recorder" }
+
+// Priority implements hook.Hook.
+func (r *ActionRecorder) Priority() int { return hook.PriorityMacro }
+
+// PostDispatch records the dispatched action if a macro is being recorded.
+// Macro commands themselves, actions replayed from a macro and actions
+// that failed or were cancelled are not recorded.
+func (r *ActionRecorder) PostDispatch(action *input.Action, ctx *execctx.ExecutionContext, result *handler.Result) {
+	if r.Mode() != RecordActions || !r.IsRecording() {
+		return
+	}
+	if strings.HasPrefix(action.Name, "macro.") || action.Source == input.SourceMacro {
+		return
+	}
+	if result.Status == handler.StatusError || result.Status == handler.StatusCancelled {
+		return
+	}
+
+	recorded := RecordedAction{
+		Name:  action.Name,
+		Args:  hook.CopyAction(action).Args,
+		Count: action.Count,
+	}
+
+	// A count or register typed before the keys reaches the handler
+	// through the context rather than the action
+	if recorded.Count == 0 && ctx.Count > 1 {
+		recorded.Count = ctx.Count
+	}
+	if recorded.Args.Register == 0 {
+		recorded.Args.Register = ctx.PendingRegister()
+	}
+
+	_ = r.RecordAction(recorded)
+}
@@ -10,4 +10,11 @@
 //
 // Macros are stored in named registers (a-z) and can be played back
 // multiple times with a count prefix.
+//
+// By default macros record keys, which the input system feeds back through
+// the current key bindings on playback. An ActionRecorder instead records
+// the actions the keys resolved to, including their count and register, as
+// a post-dispatch hook; playback re-dispatches them, so the macro does the
+// same thing after keys are rebound. Call System.SetMacroRecordMode with
+// RecordActions to switch the dispatcher system to it at runtime.
 package macro
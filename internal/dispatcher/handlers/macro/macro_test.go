@@ -553,3 +553,49 @@ func TestHandler_PlayWithCallback(t *testing.T) {
 		t.Errorf("expected 2 actions executed, got %d", executed)
 	}
 }
+
+func TestActionRecorder_PostDispatch(t *testing.T) {
+	r := NewActionRecorder()
+	ctx := execctx.New()
+	ok := handler.Success()
+
+	// Nothing is recorded outside of a recording
+	r.PostDispatch(&input.Action{Name: "editor.insertText"}, ctx, &ok)
+
+	if err := r.StartRecording('a'); err != nil {
+		t.Fatalf("StartRecording failed: %v", err)
+	}
+
+	// Nor while keys are recorded
+	r.PostDispatch(&input.Action{Name: "editor.insertText"}, ctx, &ok)
+	r.SetMode(RecordActions)
+
+	failed := handler.Errorf("failed")
+	motion := &input.Motion{Name: "word", Count: 2}
+	r.PostDispatch(&input.Action{Name: "cursor.moveDown", Count: 5}, ctx, &ok)
+	r.PostDispatch(&input.Action{Name: "editor.delete", Args: input.ActionArgs{Motion: motion}}, ctx, &ok)
+	r.PostDispatch(&input.Action{Name: "editor.insertText"}, ctx, &failed)
+	r.PostDispatch(&input.Action{Name: "editor.insertText", Source: input.SourceMacro}, ctx, &ok)
+	r.PostDispatch(&input.Action{Name: ActionPlay}, ctx, &ok)
+	motion.Count = 9
+
+	m, err := r.StopRecording()
+	if err != nil {
+		t.Fatalf("StopRecording failed: %v", err)
+	}
+	if len(m.Actions) != 2 {
+		t.Fatalf("recorded %d actions, want 2: %+v", len(m.Actions), m.Actions)
+	}
+	if m.Actions[0].Name != "cursor.moveDown" || m.Actions[0].Count != 5 {
+		t.Errorf("action 0 = %+v, want cursor.moveDown with count 5", m.Actions[0])
+	}
+	if m.Actions[1].Args.Motion == nil || m.Actions[1].Args.Motion.Count != 2 {
+		t.Errorf("action 1 motion = %+v, want a copy with count 2", m.Actions[1].Args.Motion)
+	}
+}
+
+func TestRecordMode_String(t *testing.T) {
+	if RecordKeys.String() != "keys" || RecordActions.String() != "actions" {
+		t.Errorf("RecordMode strings = %q, %q", RecordKeys, RecordActions)
+	}
+}
@@ -0,0 +1,194 @@
+package mode
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/cursor"
+)
+
+// insertAtSelection handles I and A in visual modes. In visual block mode
+// it starts a block insert or append; otherwise it collapses each selection
+// to its start or end and enters insert mode.
+func (h *ModeHandler) insertAtSelection(ctx *execctx.ExecutionContext, atEnd bool) handler.Result {
+	if ctx.Engine == nil || ctx.Cursors == nil {
+		return handler.Error(execctx.ErrMissingEngine)
+	}
+
+	if ctx.ModeManager != nil && ctx.ModeManager.IsMode("visual-block") {
+		return h.blockInsert(ctx, atEnd)
+	}
+
+	selections := ctx.Cursors.All()
+	for i, sel := range selections {
+		r := sel.Range()
+		offset := r.Start
+		if atEnd {
+			offset = r.End
+		}
+		selections[i] = cursor.NewCursorSelection(offset)
+	}
+	ctx.Cursors.SetAll(selections)
+
+	if ctx.ModeManager != nil {
+		if err := ctx.ModeManager.Switch("insert"); err != nil {
+			return handler.Error(err)
+		}
+	}
+
+	return handler.Success().WithModeChange("insert")
+}
+
+// blockInsert starts a visual-block insert (I) or append (A). The cursor
+// moves to the block's left column (or just past its right column) on the
+// top line and insert mode is entered. When insert mode ends, switchToNormal
+// replicates the typed text on the other lines of the block.
+func (h *ModeHandler) blockInsert(ctx *execctx.ExecutionContext, appendMode bool) handler.Result {
+	if err := ctx.ValidateForEdit(); err != nil {
+		return handler.Error(err)
+	}
+
+	engine := ctx.Engine
+	sel := ctx.Cursors.Primary()
+	anchor := engine.OffsetToPoint(sel.Anchor)
+	head := engine.OffsetToPoint(sel.Head)
+
+	startLine, endLine := anchor.Line, head.Line
+	if startLine > endLine {
+		startLine, endLine = endLine, startLine
+	}
+
+	anchorCol := charColumn(engine, anchor)
+	headCol := charColumn(engine, head)
+	column := min(anchorCol, headCol)
+	if appendMode {
+		column = max(anchorCol, headCol) + 1
+	}
+
+	// Block append pads a short top line so typing starts at the column
+	lineText := engine.LineText(startLine)
+	offset, short := columnOffset(lineText, column)
+	padded := false
+	if short > 0 && appendMode {
+		if _, err := engine.Insert(engine.LineEndOffset(startLine), strings.Repeat(" ", short)); err != nil {
+			return handler.Error(err)
+		}
+		offset += short
+		padded = true
+	}
+	ctx.Cursors.SetAll([]cursor.Selection{
+		cursor.NewCursorSelection(engine.LineStartOffset(startLine) + buffer.ByteOffset(offset)),
+	})
+
+	if ctx.ModeManager != nil {
+		if err := ctx.ModeManager.Switch("insert"); err != nil {
+			return handler.Error(err)
+		}
+		if tracker, ok := ctx.ModeManager.(execctx.BlockInsertTracker); ok {
+			tracker.SetBlockInsert(execctx.BlockInsert{
+				StartLine: startLine,
+				EndLine:   endLine,
+				Column:    column,
+				Append:    appendMode,
+			})
+		}
+	}
+
+	result := handler.Success().WithModeChange("insert")
+	if padded {
+		result = result.WithRedrawLines(startLine)
+	}
+	return result
+}
+
+// finishBlockInsert replicates the text typed during a visual-block insert
+// on the remaining lines of the block, as a single undo group. Lines shorter
+// than the insert column are padded with spaces for block append and
+// skipped for block insert. Nothing is replicated if the text spans lines
+// or the cursor left the top line. Returns the lines that were changed.
+func (h *ModeHandler) finishBlockInsert(ctx *execctx.ExecutionContext) ([]uint32, error) {
+	tracker, ok := ctx.ModeManager.(execctx.BlockInsertTracker)
+	if !ok {
+		return nil, nil
+	}
+	b, ok := tracker.BlockInsert()
+	if !ok {
+		return nil, nil
+	}
+	tracker.ClearBlockInsert()
+
+	if !ctx.ModeManager.IsMode("insert") || ctx.ValidateForEdit() != nil {
+		return nil, nil
+	}
+
+	engine := ctx.Engine
+	offset, short := columnOffset(engine.LineText(b.StartLine), b.Column)
+	if short > 0 {
+		return nil, nil
+	}
+	start := engine.LineStartOffset(b.StartLine) + buffer.ByteOffset(offset)
+	end := ctx.Cursors.Primary().Head
+	if end <= start || end > engine.LineEndOffset(b.StartLine) {
+		return nil, nil
+	}
+	text := engine.TextRange(start, end)
+
+	if ctx.History != nil {
+		ctx.History.BeginGroup("blockInsert")
+		defer ctx.History.EndGroup()
+	}
+
+	lineCount := engine.LineCount()
+	var affectedLines []uint32
+	for line := b.EndLine; line > b.StartLine; line-- {
+		if line >= lineCount {
+			continue
+		}
+
+		offset, short := columnOffset(engine.LineText(line), b.Column)
+		insertText := text
+		if short > 0 {
+			if !b.Append {
+				continue
+			}
+			insertText = strings.Repeat(" ", short) + text
+		}
+
+		if _, err := engine.Insert(engine.LineStartOffset(line)+buffer.ByteOffset(offset), insertText); err != nil {
+			return affectedLines, err
+		}
+		affectedLines = append(affectedLines, line)
+	}
+
+	// Like Vim, leave the cursor at the start of the inserted text
+	ctx.Cursors.SetAll([]cursor.Selection{cursor.NewCursorSelection(start)})
+
+	return affectedLines, nil
+}
+
+// charColumn returns the character column of a point.
+func charColumn(engine execctx.EngineInterface, point buffer.Point) uint32 {
+	lineText := engine.LineText(point.Line)
+	col := int(point.Column)
+	if col > len(lineText) {
+		col = len(lineText)
+	}
+	return uint32(utf8.RuneCountInString(lineText[:col]))
+}
+
+// columnOffset returns the byte offset of a character column within a line.
+// If the line is shorter than the column, it returns the line length and
+// the number of missing characters.
+func columnOffset(lineText string, column uint32) (offset, short int) {
+	var chars uint32
+	for i := range lineText {
+		if chars == column {
+			return i, 0
+		}
+		chars++
+	}
+	return len(lineText), int(column - chars)
+}
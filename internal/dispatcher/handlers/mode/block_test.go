@@ -0,0 +1,231 @@
+package mode_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/mode"
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/cursor"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// textEngine implements execctx.EngineInterface over a string.
+type textEngine struct {
+	text string
+}
+
+func (e *textEngine) Insert(offset buffer.ByteOffset, text string) (buffer.EditResult, error) {
+	e.text = e.text[:offset] + text + e.text[offset:]
+	return buffer.EditResult{}, nil
+}
+
+func (e *textEngine) Delete(start, end buffer.ByteOffset) (buffer.EditResult, error) {
+	e.text = e.text[:start] + e.text[end:]
+	return buffer.EditResult{}, nil
+}
+
+func (e *textEngine) Replace(start, end buffer.ByteOffset, text string) (buffer.EditResult, error) {
+	e.text = e.text[:start] + text + e.text[end:]
+	return buffer.EditResult{}, nil
+}
+
+func (e *textEngine) Text() string { return e.text }
+
+func (e *textEngine) TextRange(start, end buffer.ByteOffset) string { return e.text[start:end] }
+
+func (e *textEngine) LineText(line uint32) string {
+	return e.text[e.LineStartOffset(line):e.LineEndOffset(line)]
+}
+
+func (e *textEngine) Len() buffer.ByteOffset { return buffer.ByteOffset(len(e.text)) }
+
+func (e *textEngine) LineCount() uint32 { return uint32(strings.Count(e.text, "\n")) + 1 }
+
+func (e *textEngine) LineStartOffset(line uint32) buffer.ByteOffset {
+	offset := 0
+	for i := uint32(0); i < line; i++ {
+		next := strings.IndexByte(e.text[offset:], '\n')
+		if next < 0 {
+			return e.Len()
+		}
+		offset += next + 1
+	}
+	return buffer.ByteOffset(offset)
+}
+
+func (e *textEngine) LineEndOffset(line uint32) buffer.ByteOffset {
+	start := e.LineStartOffset(line)
+	if end := strings.IndexByte(e.text[start:], '\n'); end >= 0 {
+		return start + buffer.ByteOffset(end)
+	}
+	return e.Len()
+}
+
+func (e *textEngine) LineLen(line uint32) uint32 {
+	return uint32(e.LineEndOffset(line) - e.LineStartOffset(line))
+}
+
+func (e *textEngine) OffsetToPoint(offset buffer.ByteOffset) buffer.Point {
+	line := uint32(strings.Count(e.text[:offset], "\n"))
+	return buffer.Point{Line: line, Column: uint32(offset - e.LineStartOffset(line))}
+}
+
+func (e *textEngine) PointToOffset(point buffer.Point) buffer.ByteOffset {
+	return e.LineStartOffset(point.Line) + buffer.ByteOffset(point.Column)
+}
+
+func (e *textEngine) Snapshot() execctx.EngineReader { return e }
+func (e *textEngine) RevisionID() buffer.RevisionID  { return 0 }
+
+// blockModeManager implements execctx.ModeManagerInterface and
+// execctx.BlockInsertTracker.
+type blockModeManager struct {
+	current string
+	block   *execctx.BlockInsert
+}
+
+type namedMode string
+
+func (m namedMode) Name() string        { return string(m) }
+func (m namedMode) DisplayName() string { return string(m) }
+
+func (m *blockModeManager) Current() execctx.ModeInterface { return namedMode(m.current) }
+func (m *blockModeManager) CurrentName() string            { return m.current }
+func (m *blockModeManager) Switch(name string) error {
+	m.current = name
+	m.block = nil // entering a mode clears pending block state
+	return nil
+}
+func (m *blockModeManager) Push(name string) error { return m.Switch(name) }
+func (m *blockModeManager) Pop() error             { return nil }
+func (m *blockModeManager) IsMode(name string) bool {
+	return m.current == name
+}
+func (m *blockModeManager) IsAnyMode(names ...string) bool {
+	for _, name := range names {
+		if m.current == name {
+			return true
+		}
+	}
+	return false
+}
+func (m *blockModeManager) SetBlockInsert(b execctx.BlockInsert) { m.block = &b }
+func (m *blockModeManager) BlockInsert() (execctx.BlockInsert, bool) {
+	if m.block == nil {
+		return execctx.BlockInsert{}, false
+	}
+	return *m.block, true
+}
+func (m *blockModeManager) ClearBlockInsert() { m.block = nil }
+
+// groupHistory implements execctx.HistoryInterface, recording groups.
+type groupHistory struct {
+	groups []string
+	open   int
+}
+
+func (h *groupHistory) BeginGroup(name string) { h.groups = append(h.groups, name); h.open++ }
+func (h *groupHistory) EndGroup()              { h.open-- }
+func (h *groupHistory) CancelGroup()           { h.open-- }
+func (h *groupHistory) IsGrouping() bool       { return h.open > 0 }
+func (h *groupHistory) CanUndo() bool          { return false }
+func (h *groupHistory) CanRedo() bool          { return false }
+func (h *groupHistory) UndoCount() int         { return 0 }
+func (h *groupHistory) RedoCount() int         { return 0 }
+
+// runBlockInsert selects a block from anchor to head in visual block mode,
+// runs action, types text and leaves insert mode.
+func runBlockInsert(t *testing.T, text string, anchor, head buffer.Point, action, typed string) (*textEngine, *groupHistory, *cursor.CursorSet) {
+	t.Helper()
+
+	engine := &textEngine{text: text}
+	cursors := cursor.NewCursorSetAt(0)
+	cursors.Set(cursor.NewSelection(engine.PointToOffset(anchor), engine.PointToOffset(head)))
+	modes := &blockModeManager{current: "visual-block"}
+	history := &groupHistory{}
+
+	ctx := execctx.New().WithEngine(engine).WithCursors(cursors).WithModeManager(modes).WithHistory(history)
+	h := mode.NewModeHandler()
+
+	result := h.HandleAction(input.Action{Name: action}, ctx)
+	if result.Status != handler.StatusOK {
+		t.Fatalf("%s: status = %v, error = %v", action, result.Status, result.Error)
+	}
+	if modes.current != "insert" {
+		t.Fatalf("mode after %s = %q, want insert", action, modes.current)
+	}
+
+	// Type on the top line, as the insert handler would
+	pos := cursors.PrimaryCursor()
+	engine.Insert(pos, typed)
+	cursors.Set(cursor.NewCursorSelection(pos + buffer.ByteOffset(len(typed))))
+
+	result = h.HandleAction(input.Action{Name: mode.ActionNormal}, ctx)
+	if result.Status != handler.StatusOK {
+		t.Fatalf("normal: status = %v, error = %v", result.Status, result.Error)
+	}
+	if modes.block != nil {
+		t.Error("block insert state should be cleared after leaving insert mode")
+	}
+	return engine, history, cursors
+}
+
+func TestBlockInsertFiveLines(t *testing.T) {
+	text := "alpha\nbeta\nx\ngamma\ndelta\nomega"
+	engine, history, cursors := runBlockInsert(t, text,
+		buffer.Point{Line: 0, Column: 2}, buffer.Point{Line: 4, Column: 3},
+		mode.ActionInsertAtSelectionStart, "--")
+
+	want := "al--pha\nbe--ta\nx\nga--mma\nde--lta\nomega"
+	if engine.text != want {
+		t.Errorf("text = %q, want %q (short line skipped)", engine.text, want)
+	}
+	if len(history.groups) != 1 || history.groups[0] != "blockInsert" || history.open != 0 {
+		t.Errorf("undo groups = %v (open %d), want one closed blockInsert group", history.groups, history.open)
+	}
+	if got := cursors.PrimaryCursor(); got != 2 {
+		t.Errorf("cursor = %d, want 2 (start of inserted text)", got)
+	}
+}
+
+func TestBlockAppendPadsShortLines(t *testing.T) {
+	text := "alpha\nbeta\nx\ngamma\ndelta"
+	engine, _, _ := runBlockInsert(t, text,
+		buffer.Point{Line: 4, Column: 3}, buffer.Point{Line: 0, Column: 1},
+		mode.ActionInsertAtSelectionEnd, "|")
+
+	want := "alph|a\nbeta|\nx   |\ngamm|a\ndelt|a"
+	if engine.text != want {
+		t.Errorf("text = %q, want %q", engine.text, want)
+	}
+}
+
+func TestBlockInsertMultiByte(t *testing.T) {
+	text := "héllo\nwörld\nabcde"
+	engine, _, _ := runBlockInsert(t, text,
+		buffer.Point{Line: 0, Column: 3}, buffer.Point{Line: 2, Column: 2},
+		mode.ActionInsertAtSelectionStart, "*")
+
+	want := "hé*llo\nwö*rld\nab*cde"
+	if engine.text != want {
+		t.Errorf("text = %q, want %q", engine.text, want)
+	}
+}
+
+func TestBlockInsertNewlineNotReplicated(t *testing.T) {
+	text := "alpha\nbeta\ngamma"
+	engine, history, _ := runBlockInsert(t, text,
+		buffer.Point{Line: 0, Column: 1}, buffer.Point{Line: 2, Column: 1},
+		mode.ActionInsertAtSelectionStart, "x\ny")
+
+	want := "ax\nylpha\nbeta\ngamma"
+	if engine.text != want {
+		t.Errorf("text = %q, want %q", engine.text, want)
+	}
+	if len(history.groups) != 0 {
+		t.Errorf("undo groups = %v, want none", history.groups)
+	}
+}
@@ -19,6 +19,18 @@
 //   - mode.command (:): Command line mode
 //   - mode.replace (R): Replace mode
 //   - mode.replaceChar (r): Replace single character
+//   - mode.insertAtSelectionStart (I in visual): Insert at selection start
+//   - mode.insertAtSelectionEnd (A in visual): Append at selection end
+//
+// # Block Insert
+//
+// In visual block mode, I and A start a block insert: the cursor moves to
+// the block's left column (or past its right column) on the top line and
+// insert mode is entered. When insert mode is left with mode.normal, the
+// text typed on the top line is inserted at the same column on every other
+// line of the block as one undo group. Block append pads short lines with
+// spaces; block insert skips them. The mode manager carries the pending
+// block between the two actions via execctx.BlockInsertTracker.
 //
 // # Cursor Behavior
 //
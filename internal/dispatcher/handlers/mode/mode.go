@@ -27,6 +27,9 @@ const (
 	ActionCommand         = "mode.command"         // : - command line mode
 	ActionReplace         = "mode.replace"         // R - replace mode
 	ActionReplaceChar     = "mode.replaceChar"     // r - replace single character
+
+	ActionInsertAtSelectionStart = "mode.insertAtSelectionStart" // I in visual modes - insert at selection start
+	ActionInsertAtSelectionEnd   = "mode.insertAtSelectionEnd"   // A in visual modes - append at selection end
 )
 
 // ModeHandler handles mode switching operations.
@@ -48,12 +51,35 @@ func (h *ModeHandler) CanHandle(actionName string) bool {
 	case ActionNormal, ActionInsert, ActionInsertLineStart,
 		ActionAppend, ActionAppendLineEnd, ActionOpenBelow, ActionOpenAbove,
 		ActionVisual, ActionVisualLine, ActionVisualBlock,
-		ActionCommand, ActionReplace, ActionReplaceChar:
+		ActionCommand, ActionReplace, ActionReplaceChar,
+		ActionInsertAtSelectionStart, ActionInsertAtSelectionEnd:
 		return true
 	}
 	return false
 }
 
+// DescribeActions returns the mode switching actions.
+func (h *ModeHandler) DescribeActions() []handler.ActionInfo {
+	return []handler.ActionInfo{
+		{Name: ActionNormal, Description: "Switch to normal mode"},
+		{Name: ActionInsert, Description: "Insert before cursor"},
+		{Name: ActionInsertLineStart, Description: "Insert at first non-blank"},
+		{Name: ActionAppend, Description: "Append after cursor"},
+		{Name: ActionAppendLineEnd, Description: "Append at end of line"},
+		{Name: ActionOpenBelow, Description: "Open a line below and insert"},
+		{Name: ActionOpenAbove, Description: "Open a line above and insert"},
+		{Name: ActionVisual, Description: "Switch to visual mode"},
+		{Name: ActionVisualLine, Description: "Switch to visual line mode"},
+		{Name: ActionVisualBlock, Description: "Switch to visual block mode"},
+		{Name: ActionCommand, Description: "Switch to command line mode"},
+		{Name: ActionReplace, Description: "Switch to replace mode"},
+		{Name: ActionReplaceChar, Description: "Replace character under cursor",
+			Args: []handler.ArgInfo{{Name: "text", Description: "Replacement character", Required: true}}},
+		{Name: ActionInsertAtSelectionStart, Description: "Insert at selection start"},
+		{Name: ActionInsertAtSelectionEnd, Description: "Append at selection end"},
+	}
+}
+
 // HandleAction processes a mode action.
 func (h *ModeHandler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	switch action.Name {
@@ -83,14 +109,26 @@ func (h *ModeHandler) HandleAction(action input.Action, ctx *execctx.ExecutionCo
 		return h.switchToReplace(ctx)
 	case ActionReplaceChar:
 		return h.replaceChar(ctx, action.Args.Text)
+	case ActionInsertAtSelectionStart:
+		return h.insertAtSelection(ctx, false)
+	case ActionInsertAtSelectionEnd:
+		return h.insertAtSelection(ctx, true)
 	default:
 		return handler.Errorf("unknown mode action: %s", action.Name)
 	}
 }
 
-// switchToNormal switches to normal mode.
+// switchToNormal switches to normal mode, completing any pending
+// visual-block insert first.
 func (h *ModeHandler) switchToNormal(ctx *execctx.ExecutionContext) handler.Result {
+	var blockLines []uint32
 	if ctx.ModeManager != nil {
+		lines, err := h.finishBlockInsert(ctx)
+		if err != nil {
+			return handler.Error(err)
+		}
+		blockLines = lines
+
 		if err := ctx.ModeManager.Switch("normal"); err != nil {
 			return handler.Error(err)
 		}
@@ -108,7 +146,11 @@ func (h *ModeHandler) switchToNormal(ctx *execctx.ExecutionContext) handler.Resu
 		ctx.Cursors.SetAll(selections)
 	}
 
-	return handler.Success().WithModeChange("normal")
+	result := handler.Success().WithModeChange("normal")
+	if len(blockLines) > 0 {
+		result = result.WithRedrawLines(blockLines...)
+	}
+	return result
 }
 
 // switchToInsert switches to insert mode at current cursor position.
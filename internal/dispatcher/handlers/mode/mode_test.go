@@ -36,6 +36,8 @@ func TestModeHandlerCanHandle(t *testing.T) {
 		{mode.ActionCommand, true},
 		{mode.ActionReplace, true},
 		{mode.ActionReplaceChar, true},
+		{mode.ActionInsertAtSelectionStart, true},
+		{mode.ActionInsertAtSelectionEnd, true},
 		{"mode.unknown", false},
 		{"cursor.moveLeft", false},
 	}
@@ -63,6 +65,8 @@ func TestModeActionConstants(t *testing.T) {
 		mode.ActionCommand,
 		mode.ActionReplace,
 		mode.ActionReplaceChar,
+		mode.ActionInsertAtSelectionStart,
+		mode.ActionInsertAtSelectionEnd,
 	}
 
 	for _, action := range actions {
@@ -46,6 +46,7 @@
 //   - brace (i{/a{): Inner/around braces
 //   - angle (i</a<): Inner/around angle brackets
 //   - tag (it/at): Inner/around XML/HTML tags
+//   - indent (ii/ai): Indentation block, around adds its header line
 //
 // # Visual Selection
 //
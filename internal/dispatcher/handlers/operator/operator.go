@@ -12,6 +12,7 @@ import (
 	"github.com/dshills/keystorm/internal/dispatcher/handler"
 	"github.com/dshills/keystorm/internal/engine/buffer"
 	"github.com/dshills/keystorm/internal/engine/cursor"
+	"github.com/dshills/keystorm/internal/engine/rope"
 	"github.com/dshills/keystorm/internal/input"
 )
 
@@ -53,6 +54,26 @@ func (h *OperatorHandler) CanHandle(actionName string) bool {
 	return false
 }
 
+// DescribeActions returns the operator actions.
+func (h *OperatorHandler) DescribeActions() []handler.ActionInfo {
+	rangeArgs := []handler.ArgInfo{
+		{Name: "motion", Description: "Motion defining the range"},
+		{Name: "textObject", Description: "Text object defining the range"},
+	}
+	registerArgs := append([]handler.ArgInfo{{Name: "register", Description: "Register to store the text in"}}, rangeArgs...)
+	return []handler.ActionInfo{
+		{Name: ActionDelete, Description: "Delete text", Args: registerArgs},
+		{Name: ActionChange, Description: "Delete text and enter insert mode", Args: registerArgs},
+		{Name: ActionYank, Description: "Copy text", Args: registerArgs},
+		{Name: ActionIndent, Description: "Indent lines", Args: rangeArgs},
+		{Name: ActionOutdent, Description: "Outdent lines", Args: rangeArgs},
+		{Name: ActionLowercase, Description: "Make text lowercase", Args: rangeArgs},
+		{Name: ActionUppercase, Description: "Make text uppercase", Args: rangeArgs},
+		{Name: ActionToggleCase, Description: "Toggle case of text", Args: rangeArgs},
+		{Name: ActionFormat, Description: "Format lines", Args: rangeArgs},
+	}
+}
+
 // HandleAction processes an operator action.
 func (h *OperatorHandler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	// Operators need either a motion, text object, or visual selection
@@ -139,28 +160,29 @@ func (h *OperatorHandler) resolveMotionRange(motion *input.Motion, ctx *execctx.
 
 	var end buffer.ByteOffset
 	linewise := false
+	keywords := execctx.EngineKeywords(engine)
 
 	switch motion.Name {
 	case "word", "w":
-		end = h.findWordEnd(text, start, textLen, count, false)
+		end = keywords.OperatorWordEnd(text, start, count, false)
 	case "WORD", "W":
-		end = h.findWordEnd(text, start, textLen, count, true)
+		end = keywords.OperatorWordEnd(text, start, count, true)
 	case "wordEnd", "e":
-		end = h.findWordEndPosition(text, start, textLen, count, false)
+		end = repeatWordEnd(keywords, text, start, count, false)
 		if motion.Inclusive {
 			end = nextRuneEnd(text, end, textLen)
 		}
 	case "WORDEND", "E":
-		end = h.findWordEndPosition(text, start, textLen, count, true)
+		end = repeatWordEnd(keywords, text, start, count, true)
 		if motion.Inclusive {
 			end = nextRuneEnd(text, end, textLen)
 		}
 	case "wordBack", "b":
-		end = h.findWordBackward(text, start, count, false)
+		end = repeatPrevWordStart(keywords, text, start, count, false)
 		// Swap start and end for backward motion
 		start, end = end, start
 	case "WORDBACK", "B":
-		end = h.findWordBackward(text, start, count, true)
+		end = repeatPrevWordStart(keywords, text, start, count, true)
 		start, end = end, start
 	case "line", "l":
 		linewise = true
@@ -200,10 +222,10 @@ func (h *OperatorHandler) resolveMotionRange(motion *input.Motion, ctx *execctx.
 			start, end = end, start
 		}
 	case "paragraph", "}":
-		end = h.findParagraphEnd(text, start, textLen, count)
+		end = repeatBoundary(execctx.EngineRope(engine), start, count, cursor.NextParagraph)
 		linewise = true
 	case "paragraphBack", "{":
-		end = h.findParagraphStart(text, start, count)
+		end = repeatBoundary(execctx.EngineRope(engine), start, count, cursor.PrevParagraph)
 		start, end = end, start
 		linewise = true
 	case "documentEnd", "G":
@@ -239,19 +261,20 @@ func (h *OperatorHandler) resolveTextObjectRange(textObj *input.TextObject, ctx
 	sel := ctx.Cursors.Primary()
 	offset := sel.Head
 	text := engine.Text()
+	keywords := execctx.EngineKeywords(engine)
 
 	switch textObj.Name {
 	case "word", "w":
-		start, end := h.findWordBounds(text, offset, textObj.Inner, false)
+		start, end := findWordBounds(keywords, text, offset, textObj.Inner, false)
 		return OperatorRange{Start: start, End: end}, nil
 	case "WORD", "W":
-		start, end := h.findWordBounds(text, offset, textObj.Inner, true)
+		start, end := findWordBounds(keywords, text, offset, textObj.Inner, true)
 		return OperatorRange{Start: start, End: end}, nil
 	case "sentence", "s":
-		start, end := h.findSentenceBounds(text, offset, textObj.Inner)
+		start, end := h.findSentenceBounds(execctx.EngineRope(engine), text, offset, textObj.Inner)
 		return OperatorRange{Start: start, End: end}, nil
 	case "paragraph", "p":
-		start, end := h.findParagraphBounds(text, offset, textObj.Inner)
+		start, end := h.findParagraphBounds(execctx.EngineRope(engine), offset, textObj.Inner)
 		return OperatorRange{Start: start, End: end, Linewise: true}, nil
 	case "quote", `"`, "'", "`":
 		delimiter := textObj.Delimiter
@@ -275,6 +298,10 @@ func (h *OperatorHandler) resolveTextObjectRange(textObj *input.TextObject, ctx
 	case "tag", "t":
 		start, end := h.findTagBounds(text, offset, textObj.Inner)
 		return OperatorRange{Start: start, End: end}, nil
+	case "indent", "i":
+		line := engine.OffsetToPoint(offset).Line
+		r := execctx.EngineIndentBlock(engine, int(line), !textObj.Inner)
+		return OperatorRange{Start: r.Start, End: r.End, Linewise: true}, nil
 	default:
 		return OperatorRange{}, handler.Errorf("unknown text object: %s", textObj.Name).Error
 	}
@@ -521,212 +548,133 @@ func (h *OperatorHandler) format(ctx *execctx.ExecutionContext, opRange Operator
 
 // Motion helper functions
 
-// findWordEnd finds the end of count words forward.
-func (h *OperatorHandler) findWordEnd(text string, offset, maxOffset buffer.ByteOffset, count int, bigWord bool) buffer.ByteOffset {
-	for i := 0; i < count && offset < maxOffset; i++ {
-		// Skip current word
-		for offset < maxOffset && h.isWordChar(text, offset, bigWord) {
-			offset = nextRuneEnd(text, offset, maxOffset)
-		}
-		// Skip whitespace/non-word
-		for offset < maxOffset && !h.isWordChar(text, offset, bigWord) {
-			if text[offset] == '\n' {
-				offset++
-				break
-			}
-			offset = nextRuneEnd(text, offset, maxOffset)
-		}
-	}
-	return offset
-}
-
-// findWordEndPosition finds the end position of the current/next word.
-func (h *OperatorHandler) findWordEndPosition(text string, offset, maxOffset buffer.ByteOffset, count int, bigWord bool) buffer.ByteOffset {
-	for i := 0; i < count && offset < maxOffset; i++ {
-		// Move forward one to start searching
-		if offset < maxOffset {
-			offset = nextRuneEnd(text, offset, maxOffset)
-		}
-		// Skip whitespace
-		for offset < maxOffset && unicode.IsSpace(getRune(text, offset)) {
-			offset = nextRuneEnd(text, offset, maxOffset)
-		}
-		// Move to end of word
-		for offset < maxOffset && h.isWordChar(text, offset, bigWord) {
-			nextOff := nextRuneEnd(text, offset, maxOffset)
-			if nextOff >= maxOffset || !h.isWordChar(text, nextOff, bigWord) {
-				break
-			}
-			offset = nextOff
+// repeatWordEnd moves to the end of the count-th word forward, stopping
+// early once the offset no longer moves.
+func repeatWordEnd(keywords cursor.Keywords, text string, offset buffer.ByteOffset, count int, bigWord bool) buffer.ByteOffset {
+	for i := 0; i < count; i++ {
+		moved := keywords.WordEnd(text, offset, bigWord)
+		if moved == offset {
+			break
 		}
+		offset = moved
 	}
 	return offset
 }
 
-// findWordBackward finds the start of count words backward.
-func (h *OperatorHandler) findWordBackward(text string, offset buffer.ByteOffset, count int, bigWord bool) buffer.ByteOffset {
+// repeatPrevWordStart moves to the start of the count-th word backward.
+func repeatPrevWordStart(keywords cursor.Keywords, text string, offset buffer.ByteOffset, count int, bigWord bool) buffer.ByteOffset {
 	for i := 0; i < count && offset > 0; i++ {
-		// Move back one to start searching
-		offset = prevRuneStart(text, offset)
-		// Skip whitespace
-		for offset > 0 && unicode.IsSpace(getRune(text, offset)) {
-			offset = prevRuneStart(text, offset)
-		}
-		// Move to start of word
-		for offset > 0 && h.isWordChar(text, offset, bigWord) {
-			prevOff := prevRuneStart(text, offset)
-			if !h.isWordChar(text, prevOff, bigWord) {
-				break
-			}
-			offset = prevOff
-		}
+		offset = keywords.PrevWordStart(text, offset, bigWord)
 	}
 	return offset
 }
 
-// findParagraphEnd finds the end of count paragraphs forward.
-func (h *OperatorHandler) findParagraphEnd(text string, offset, maxOffset buffer.ByteOffset, count int) buffer.ByteOffset {
-	for i := 0; i < count && offset < maxOffset; i++ {
-		// Skip non-empty lines
-		for offset < maxOffset {
-			if text[offset] == '\n' {
-				offset++
-				if offset < maxOffset && text[offset] == '\n' {
-					break
-				}
-			} else {
-				offset++
-			}
-		}
-		// Skip empty lines
-		for offset < maxOffset && text[offset] == '\n' {
-			offset++
-		}
-	}
-	return offset
-}
-
-// findParagraphStart finds the start of count paragraphs backward.
-func (h *OperatorHandler) findParagraphStart(text string, offset buffer.ByteOffset, count int) buffer.ByteOffset {
-	for i := 0; i < count && offset > 0; i++ {
-		offset--
-		// Skip empty lines
-		for offset > 0 && text[offset] == '\n' {
-			offset--
-		}
-		// Skip non-empty lines until empty line
-		for offset > 0 {
-			if text[offset] == '\n' && offset > 0 && text[offset-1] == '\n' {
-				break
-			}
-			offset--
+// repeatBoundary applies a boundary function from the cursor package count
+// times, stopping early once the offset no longer moves.
+func repeatBoundary(r rope.Rope, offset buffer.ByteOffset, count int, next func(rope.Rope, cursor.ByteOffset) cursor.ByteOffset) buffer.ByteOffset {
+	for i := 0; i < count; i++ {
+		moved := next(r, offset)
+		if moved == offset {
+			break
 		}
-	}
-	if offset > 0 {
-		offset++ // Move past the newline
+		offset = moved
 	}
 	return offset
 }
 
 // Text object helper functions
 
-// findWordBounds finds the boundaries of the word at offset.
-func (h *OperatorHandler) findWordBounds(text string, offset buffer.ByteOffset, inner, bigWord bool) (buffer.ByteOffset, buffer.ByteOffset) {
+// findWordBounds finds the boundaries of the word at offset. On blanks the
+// inner variant is the run of blanks itself. The around variant adds the
+// blanks following the word on the same line, or the blanks before it if
+// there are none.
+func findWordBounds(keywords cursor.Keywords, text string, offset buffer.ByteOffset, inner, bigWord bool) (buffer.ByteOffset, buffer.ByteOffset) {
 	textLen := buffer.ByteOffset(len(text))
 	if offset >= textLen {
 		return offset, offset
 	}
 
-	// Find start of word
-	start := offset
-	for start > 0 && h.isWordChar(text, start-1, bigWord) {
-		start--
+	if isLineBlank(text[offset]) {
+		start, end := blankRun(text, offset)
+		if !inner {
+			// Around blanks includes the word after them
+			if end < textLen && text[end] != '\n' {
+				_, end = keywords.WordBounds(text, end, bigWord, 1)
+			}
+		}
+		return start, end
 	}
 
-	// Find end of word
-	end := offset
-	for end < textLen && h.isWordChar(text, end, bigWord) {
-		end = nextRuneEnd(text, end, textLen)
+	start, end := keywords.WordBounds(text, offset, bigWord, 1)
+	if inner {
+		return start, end
 	}
 
-	if !inner {
-		// Include trailing whitespace
-		for end < textLen && unicode.IsSpace(getRune(text, end)) && text[end] != '\n' {
-			end = nextRuneEnd(text, end, textLen)
-		}
+	if end < textLen && isLineBlank(text[end]) {
+		_, end = blankRun(text, end)
+	} else if start > 0 && isLineBlank(text[start-1]) {
+		start, _ = blankRun(text, start-1)
 	}
-
 	return start, end
 }
 
-// findSentenceBounds finds the boundaries of the sentence at offset.
-func (h *OperatorHandler) findSentenceBounds(text string, offset buffer.ByteOffset, inner bool) (buffer.ByteOffset, buffer.ByteOffset) {
-	textLen := buffer.ByteOffset(len(text))
+// isLineBlank returns true if c is a space or tab.
+func isLineBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
 
-	// Find start of sentence (after previous sentence-ending punctuation)
-	start := offset
-	for start > 0 {
-		r := getRune(text, start-1)
-		if r == '.' || r == '!' || r == '?' {
-			break
-		}
+// blankRun returns the run of spaces and tabs containing offset.
+func blankRun(text string, offset buffer.ByteOffset) (buffer.ByteOffset, buffer.ByteOffset) {
+	start, end := offset, offset
+	for start > 0 && isLineBlank(text[start-1]) {
 		start--
 	}
-	// Skip whitespace after punctuation
-	for start < textLen && unicode.IsSpace(getRune(text, start)) {
-		start++
+	for end < buffer.ByteOffset(len(text)) && isLineBlank(text[end]) {
+		end++
 	}
+	return start, end
+}
 
-	// Find end of sentence
-	end := offset
-	for end < textLen {
-		r := getRune(text, end)
-		end = nextRuneEnd(text, end, textLen)
-		if r == '.' || r == '!' || r == '?' {
-			break
-		}
+// findSentenceBounds finds the boundaries of the sentence at offset.
+// The around variant adds the white space following the sentence on the
+// same line, or the white space before it if there is none.
+func (h *OperatorHandler) findSentenceBounds(r rope.Rope, text string, offset buffer.ByteOffset, inner bool) (buffer.ByteOffset, buffer.ByteOffset) {
+	start := cursor.SentenceStart(r, offset)
+	end := cursor.SentenceEnd(r, offset)
+	if inner {
+		return start, end
 	}
 
-	if !inner {
-		// Include trailing whitespace
-		for end < textLen && unicode.IsSpace(getRune(text, end)) {
-			end = nextRuneEnd(text, end, textLen)
-		}
+	textLen := buffer.ByteOffset(len(text))
+	trailing := end
+	for trailing < textLen && (text[trailing] == ' ' || text[trailing] == '\t') {
+		trailing++
+	}
+	if trailing > end {
+		return start, trailing
+	}
+	for start > 0 && (text[start-1] == ' ' || text[start-1] == '\t') {
+		start--
 	}
-
 	return start, end
 }
 
 // findParagraphBounds finds the boundaries of the paragraph at offset.
-func (h *OperatorHandler) findParagraphBounds(text string, offset buffer.ByteOffset, inner bool) (buffer.ByteOffset, buffer.ByteOffset) {
-	textLen := buffer.ByteOffset(len(text))
-
-	// Find start of paragraph
-	start := offset
-	for start > 0 {
-		if text[start-1] == '\n' && (start < 2 || text[start-2] == '\n') {
-			break
-		}
-		start--
+// The around variant adds the empty lines following the paragraph, or the
+// empty lines before it if there are none.
+func (h *OperatorHandler) findParagraphBounds(r rope.Rope, offset buffer.ByteOffset, inner bool) (buffer.ByteOffset, buffer.ByteOffset) {
+	start := cursor.ParagraphStart(r, offset)
+	end := cursor.ParagraphEnd(r, offset)
+	if inner {
+		return start, end
 	}
 
-	// Find end of paragraph
-	end := offset
-	for end < textLen {
-		if text[end] == '\n' && (end+1 >= textLen || text[end+1] == '\n') {
-			end++
-			break
-		}
-		end++
+	if textLen := buffer.ByteOffset(r.Len()); end < textLen {
+		return start, cursor.ParagraphEnd(r, end)
 	}
-
-	if !inner {
-		// Include trailing empty lines
-		for end < textLen && text[end] == '\n' {
-			end++
-		}
+	if start > 0 {
+		start = cursor.ParagraphStart(r, start-1)
 	}
-
 	return start, end
 }
 
@@ -815,20 +763,6 @@ func (h *OperatorHandler) findTagBounds(text string, offset buffer.ByteOffset, i
 	return h.findBracketBounds(text, offset, '<', '>', inner)
 }
 
-// isWordChar returns true if the character at offset is a word character.
-func (h *OperatorHandler) isWordChar(text string, offset buffer.ByteOffset, bigWord bool) bool {
-	if int(offset) >= len(text) {
-		return false
-	}
-	r := getRune(text, offset)
-	if bigWord {
-		// WORD: non-whitespace
-		return !unicode.IsSpace(r)
-	}
-	// word: alphanumeric + underscore
-	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
-}
-
 // getRune returns the rune at the given byte offset.
 func getRune(text string, offset buffer.ByteOffset) rune {
 	if int(offset) >= len(text) {
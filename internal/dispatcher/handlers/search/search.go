@@ -7,6 +7,7 @@ import (
 
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
 	"github.com/dshills/keystorm/internal/engine/buffer"
 	"github.com/dshills/keystorm/internal/input"
 )
@@ -35,16 +36,28 @@ type SearchState struct {
 	Forward bool
 	// CaseSensitive indicates case-sensitive search.
 	CaseSensitive bool
+	// Options are the engine search options for Pattern.
+	Options engine.SearchOptions
 }
 
 const searchStateKey = "_search_state"
 
 // Handler implements namespace-based search handling.
-type Handler struct{}
+type Handler struct {
+	caseSensitive bool
+	smartCase     bool
+}
 
-// NewHandler creates a new search handler.
+// NewHandler creates a new search handler with case-sensitive searches.
 func NewHandler() *Handler {
-	return &Handler{}
+	return &Handler{caseSensitive: true}
+}
+
+// NewHandlerWithConfig creates a search handler with custom case handling.
+// With smartCase, typed patterns without an uppercase letter match
+// case-insensitively and caseSensitive only applies to word searches.
+func NewHandlerWithConfig(caseSensitive, smartCase bool) *Handler {
+	return &Handler{caseSensitive: caseSensitive, smartCase: smartCase}
 }
 
 // Namespace returns the search namespace.
@@ -63,6 +76,29 @@ func (h *Handler) CanHandle(actionName string) bool {
 	return false
 }
 
+// DescribeActions returns the search and replace actions.
+func (h *Handler) DescribeActions() []handler.ActionInfo {
+	patternArg := handler.ArgInfo{Name: "searchPattern", Description: "Pattern to search for", Required: true}
+	replaceArgs := []handler.ArgInfo{
+		patternArg,
+		{Name: "replacement", Description: "Replacement text"},
+		{Name: "global", Description: "Replace every match on a line"},
+	}
+	return []handler.ActionInfo{
+		{Name: ActionSearchForward, Description: "Search forward", Args: []handler.ArgInfo{patternArg}},
+		{Name: ActionSearchBackward, Description: "Search backward", Args: []handler.ArgInfo{patternArg}},
+		{Name: ActionSearchNext, Description: "Go to next match"},
+		{Name: ActionSearchPrev, Description: "Go to previous match"},
+		{Name: ActionSearchWordForward, Description: "Search word under cursor forward"},
+		{Name: ActionSearchWordBackward, Description: "Search word under cursor backward"},
+		{Name: ActionReplace, Description: "Replace in a line range", Args: append(replaceArgs,
+			handler.ArgInfo{Name: "startLine", Description: "First line of the range"},
+			handler.ArgInfo{Name: "endLine", Description: "Line after the range"})},
+		{Name: ActionReplaceAll, Description: "Replace in the whole buffer", Args: replaceArgs},
+		{Name: ActionClearSearch, Description: "Clear search highlight"},
+	}
+}
+
 // HandleAction processes a search action.
 func (h *Handler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	if ctx.Engine == nil {
@@ -101,7 +137,7 @@ func (h *Handler) searchForward(action input.Action, ctx *execctx.ExecutionConte
 		return handler.NoOpWithMessage("search: pattern required")
 	}
 
-	state, err := compilePattern(pattern, true, true)
+	state, err := compilePattern(pattern, true, h.regexOptions())
 	if err != nil {
 		return handler.Errorf("search: invalid pattern: %v", err)
 	}
@@ -120,7 +156,7 @@ func (h *Handler) searchBackward(action input.Action, ctx *execctx.ExecutionCont
 		return handler.NoOpWithMessage("search: pattern required")
 	}
 
-	state, err := compilePattern(pattern, false, true)
+	state, err := compilePattern(pattern, false, h.regexOptions())
 	if err != nil {
 		return handler.Errorf("search: invalid pattern: %v", err)
 	}
@@ -166,9 +202,7 @@ func (h *Handler) searchWordForward(ctx *execctx.ExecutionContext) handler.Resul
 		return handler.NoOpWithMessage("search: no word under cursor")
 	}
 
-	// Create word boundary pattern
-	pattern := `\b` + regexp.QuoteMeta(word) + `\b`
-	state, err := compilePattern(pattern, true, true)
+	state, err := compilePattern(word, true, h.wordOptions())
 	if err != nil {
 		return handler.Errorf("search: invalid pattern: %v", err)
 	}
@@ -187,8 +221,7 @@ func (h *Handler) searchWordBackward(ctx *execctx.ExecutionContext) handler.Resu
 		return handler.NoOpWithMessage("search: no word under cursor")
 	}
 
-	pattern := `\b` + regexp.QuoteMeta(word) + `\b`
-	state, err := compilePattern(pattern, false, true)
+	state, err := compilePattern(word, false, h.wordOptions())
 	if err != nil {
 		return handler.Errorf("search: invalid pattern: %v", err)
 	}
@@ -209,7 +242,7 @@ func (h *Handler) replace(action input.Action, ctx *execctx.ExecutionContext) ha
 		return handler.NoOpWithMessage("replace: pattern required")
 	}
 
-	state, err := compilePattern(pattern, true, true)
+	state, err := compilePattern(pattern, true, h.regexOptions())
 	if err != nil {
 		return handler.Errorf("replace: invalid pattern: %v", err)
 	}
@@ -243,7 +276,7 @@ func (h *Handler) replaceAll(action input.Action, ctx *execctx.ExecutionContext)
 		return handler.NoOpWithMessage("replace: pattern required")
 	}
 
-	state, err := compilePattern(pattern, true, true)
+	state, err := compilePattern(pattern, true, h.regexOptions())
 	if err != nil {
 		return handler.Errorf("replace: invalid pattern: %v", err)
 	}
@@ -260,80 +293,55 @@ func (h *Handler) clearSearch(ctx *execctx.ExecutionContext) handler.Result {
 
 // findNext finds the next match after the current cursor position.
 func (h *Handler) findNext(ctx *execctx.ExecutionContext, state *SearchState) handler.Result {
-	engine := ctx.Engine
-	text := engine.Text()
-	textLen := len(text)
-
-	// Get current position
-	startOffset := 0
+	// Start after cursor
+	from := buffer.ByteOffset(0)
 	if ctx.Cursors != nil {
-		startOffset = int(ctx.Cursors.Primary().Head) + 1 // Start after cursor
+		from = ctx.Cursors.Primary().Head + 1
 	}
 
-	if startOffset >= textLen {
-		startOffset = 0 // Wrap to beginning
+	match, found, err := execctx.EngineSearcher(ctx.Engine).FindNext(state.Pattern, from, state.Options)
+	if err != nil {
+		return handler.Errorf("search: %v", err)
 	}
-
-	// Search from current position to end
-	loc := state.Regex.FindStringIndex(text[startOffset:])
-	if loc != nil {
-		matchStart := buffer.ByteOffset(startOffset + loc[0])
-		return h.moveCursorToMatch(ctx, matchStart).
-			WithMessage("search: " + state.Pattern)
+	if !found {
+		return handler.NoOpWithMessage("search: pattern not found: " + state.Pattern)
 	}
 
-	// Wrap around: search from beginning to current position
-	if startOffset > 0 {
-		loc = state.Regex.FindStringIndex(text[:startOffset])
-		if loc != nil {
-			matchStart := buffer.ByteOffset(loc[0])
-			return h.moveCursorToMatch(ctx, matchStart).
-				WithMessage("search: " + state.Pattern + " (wrapped)")
-		}
+	msg := "search: " + state.Pattern
+	result := h.moveCursorToMatch(ctx, match.Start)
+	if match.Start < from {
+		msg += " (wrapped)"
+		result = result.WithEffects(handler.ShowMessage{Text: "search hit BOTTOM, continuing at TOP"})
 	}
-
-	return handler.NoOpWithMessage("search: pattern not found: " + state.Pattern)
+	return result.WithMessage(msg)
 }
 
 // findPrev finds the previous match before the current cursor position.
 func (h *Handler) findPrev(ctx *execctx.ExecutionContext, state *SearchState) handler.Result {
-	engine := ctx.Engine
-	text := engine.Text()
-
-	// Get current position
-	endOffset := len(text)
+	from := ctx.Engine.Len()
 	if ctx.Cursors != nil {
-		endOffset = int(ctx.Cursors.Primary().Head)
+		from = ctx.Cursors.Primary().Head
 	}
 
-	if endOffset <= 0 {
-		endOffset = len(text) // Wrap to end
+	match, found, err := execctx.EngineSearcher(ctx.Engine).FindPrev(state.Pattern, from, state.Options)
+	if err != nil {
+		return handler.Errorf("search: %v", err)
 	}
-
-	// Find all matches before current position and take the last one
-	matches := state.Regex.FindAllStringIndex(text[:endOffset], -1)
-	if len(matches) > 0 {
-		lastMatch := matches[len(matches)-1]
-		matchStart := buffer.ByteOffset(lastMatch[0])
-		return h.moveCursorToMatch(ctx, matchStart).
-			WithMessage("search: " + state.Pattern)
+	if !found {
+		return handler.NoOpWithMessage("search: pattern not found: " + state.Pattern)
 	}
 
-	// Wrap around: search from current position to end
-	if endOffset < len(text) {
-		matches = state.Regex.FindAllStringIndex(text[endOffset:], -1)
-		if len(matches) > 0 {
-			lastMatch := matches[len(matches)-1]
-			matchStart := buffer.ByteOffset(endOffset + lastMatch[0])
-			return h.moveCursorToMatch(ctx, matchStart).
-				WithMessage("search: " + state.Pattern + " (wrapped)")
-		}
+	msg := "search: " + state.Pattern
+	result := h.moveCursorToMatch(ctx, match.Start)
+	if match.Start >= from {
+		msg += " (wrapped)"
+		result = result.WithEffects(handler.ShowMessage{Text: "search hit TOP, continuing at BOTTOM"})
 	}
-
-	return handler.NoOpWithMessage("search: pattern not found: " + state.Pattern)
+	return result.WithMessage(msg)
 }
 
-// moveCursorToMatch moves the cursor to the match position.
+// moveCursorToMatch moves the cursor to the match position and centers the
+// view on it.
 func (h *Handler) moveCursorToMatch(ctx *execctx.ExecutionContext, offset buffer.ByteOffset) handler.Result {
 	if ctx.Cursors == nil {
 		return handler.Error(execctx.ErrMissingCursors)
@@ -342,9 +350,7 @@ func (h *Handler) moveCursorToMatch(ctx *execctx.ExecutionContext, offset buffer
 	sel := ctx.Cursors.Primary().MoveTo(offset)
 	ctx.Cursors.SetPrimary(sel)
 
-	// Calculate line for scrolling
-	point := ctx.Engine.OffsetToPoint(offset)
-	return handler.Success().WithScrollTo(point.Line, point.Column, true)
+	return handler.Success().WithEffects(handler.CenterCursor{})
 }
 
 // replaceInRange replaces matches in the specified line range.
@@ -434,14 +440,20 @@ func (h *Handler) wordUnderCursor(ctx *execctx.ExecutionContext) (string, error)
 	return text[start:end], nil
 }
 
-// compilePattern compiles a search pattern into a regex.
-func compilePattern(pattern string, forward, caseSensitive bool) (*SearchState, error) {
-	flags := ""
-	if !caseSensitive {
-		flags = "(?i)"
-	}
+// regexOptions returns the search options for typed patterns.
+func (h *Handler) regexOptions() engine.SearchOptions {
+	return engine.SearchOptions{Regex: true, CaseSensitive: h.caseSensitive, SmartCase: h.smartCase}
+}
+
+// wordOptions returns the search options for the word under the cursor.
+// Like Vim, smart case does not apply, since the word was not typed.
+func (h *Handler) wordOptions() engine.SearchOptions {
+	return engine.SearchOptions{CaseSensitive: h.caseSensitive, WholeWord: true}
+}
 
-	re, err := regexp.Compile(flags + pattern)
+// compilePattern compiles a search pattern with the engine's options.
+func compilePattern(pattern string, forward bool, opts engine.SearchOptions) (*SearchState, error) {
+	re, err := engine.CompileSearch(pattern, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -450,7 +462,8 @@ func compilePattern(pattern string, forward, caseSensitive bool) (*SearchState,
 		Pattern:       pattern,
 		Regex:         re,
 		Forward:       forward,
-		CaseSensitive: caseSensitive,
+		CaseSensitive: opts.IsCaseSensitive(pattern),
+		Options:       opts,
 	}, nil
 }
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
 	"github.com/dshills/keystorm/internal/engine/buffer"
 	"github.com/dshills/keystorm/internal/engine/cursor"
 	"github.com/dshills/keystorm/internal/input"
@@ -250,6 +251,42 @@ func TestHandler_SearchForward(t *testing.T) {
 	}
 }
 
+func TestHandler_SearchSmartCase(t *testing.T) {
+	tests := []struct {
+		pattern       string
+		caseSensitive bool
+		want          buffer.ByteOffset
+	}{
+		{"hello", false, 4},
+		{"Hello", true, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			h := NewHandlerWithConfig(false, true)
+			cursors := newMockCursorManager(0)
+
+			ctx := execctx.New()
+			ctx.Engine = newMockEngine("abc HELLO Hello")
+			ctx.Cursors = cursors
+
+			action := input.Action{
+				Name: ActionSearchForward,
+				Args: input.ActionArgs{SearchPattern: tt.pattern},
+			}
+			if result := h.HandleAction(action, ctx); result.Status != handler.StatusOK {
+				t.Fatalf("expected StatusOK, got %v: %v", result.Status, result.Error)
+			}
+			if got := cursors.Primary().Head; got != tt.want {
+				t.Errorf("cursor = %d, want %d", got, tt.want)
+			}
+			if got := getSearchState(ctx).CaseSensitive; got != tt.caseSensitive {
+				t.Errorf("CaseSensitive = %v, want %v", got, tt.caseSensitive)
+			}
+		})
+	}
+}
+
 func TestHandler_SearchBackward(t *testing.T) {
 	h := NewHandler()
 	engine := newMockEngine("hello world hello")
@@ -451,6 +488,20 @@ func TestHandler_SearchWrapAround(t *testing.T) {
 	if cursors.Primary().Head != 0 {
 		t.Errorf("expected cursor at 0 (wrapped), got %d", cursors.Primary().Head)
 	}
+
+	want := []handler.ViewEffect{
+		handler.CenterCursor{},
+		handler.ShowMessage{Text: "search hit BOTTOM, continuing at TOP"},
+	}
+	if len(result.Effects) != len(want) {
+		t.Fatalf("Effects = %v, want %v", result.Effects, want)
+	}
+	for i := range want {
+		if result.Effects[i] != want[i] {
+			t.Errorf("Effects = %v, want %v", result.Effects, want)
+			break
+		}
+	}
 }
 
 func TestHandler_InvalidPattern(t *testing.T) {
@@ -528,7 +579,8 @@ func TestCompilePattern(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			state, err := compilePattern(tt.pattern, tt.forward, tt.caseSensitive)
+			opts := engine.SearchOptions{Regex: true, CaseSensitive: tt.caseSensitive}
+			state, err := compilePattern(tt.pattern, tt.forward, opts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("compilePattern() error = %v, wantErr %v", err, tt.wantErr)
 				return
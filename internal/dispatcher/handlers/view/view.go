@@ -56,6 +56,26 @@ func (h *Handler) CanHandle(actionName string) bool {
 	return false
 }
 
+// DescribeActions returns the scrolling and view positioning actions.
+func (h *Handler) DescribeActions() []handler.ActionInfo {
+	return []handler.ActionInfo{
+		{Name: ActionScrollDown, Description: "Scroll down one line"},
+		{Name: ActionScrollUp, Description: "Scroll up one line"},
+		{Name: ActionPageDown, Description: "Scroll down one page"},
+		{Name: ActionPageUp, Description: "Scroll up one page"},
+		{Name: ActionHalfPageDown, Description: "Scroll down half a page"},
+		{Name: ActionHalfPageUp, Description: "Scroll up half a page"},
+		{Name: ActionScrollToTop, Description: "Scroll to top of buffer"},
+		{Name: ActionScrollToBottom, Description: "Scroll to bottom of buffer"},
+		{Name: ActionMoveToTop, Description: "Move cursor to top of screen"},
+		{Name: ActionMoveToMiddle, Description: "Move cursor to middle of screen"},
+		{Name: ActionMoveToBottom, Description: "Move cursor to bottom of screen"},
+		{Name: ActionCenterCursor, Description: "Center view on cursor"},
+		{Name: ActionTopCursor, Description: "Scroll cursor line to top"},
+		{Name: ActionBottomCursor, Description: "Scroll cursor line to bottom"},
+	}
+}
+
 // HandleAction processes a view action.
 func (h *Handler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	if ctx.Engine == nil {
@@ -127,6 +127,35 @@ func (h *Handler) CanHandle(actionName string) bool {
 	return false
 }
 
+// DescribeActions returns the window actions.
+func (h *Handler) DescribeActions() []handler.ActionInfo {
+	return []handler.ActionInfo{
+		{Name: ActionSplitHorizontal, Description: "Split window horizontally"},
+		{Name: ActionSplitVertical, Description: "Split window vertically"},
+		{Name: ActionFocusLeft, Description: "Focus window to the left"},
+		{Name: ActionFocusDown, Description: "Focus window below"},
+		{Name: ActionFocusUp, Description: "Focus window above"},
+		{Name: ActionFocusRight, Description: "Focus window to the right"},
+		{Name: ActionFocusNext, Description: "Focus next window"},
+		{Name: ActionFocusPrev, Description: "Focus previous window"},
+		{Name: ActionFocusTop, Description: "Focus top-left window"},
+		{Name: ActionFocusBottom, Description: "Focus bottom-right window"},
+		{Name: ActionClose, Description: "Close window"},
+		{Name: ActionCloseOther, Description: "Close all other windows"},
+		{Name: ActionSwap, Description: "Swap with next window"},
+		{Name: ActionIncreaseHeight, Description: "Increase window height"},
+		{Name: ActionDecreaseHeight, Description: "Decrease window height"},
+		{Name: ActionIncreaseWidth, Description: "Increase window width"},
+		{Name: ActionDecreaseWidth, Description: "Decrease window width"},
+		{Name: ActionEqualize, Description: "Make windows equal size"},
+		{Name: ActionMaximize, Description: "Maximize window height"},
+		{Name: ActionMaximizeWidth, Description: "Maximize window width"},
+		{Name: ActionRotateDown, Description: "Rotate windows downward"},
+		{Name: ActionRotateUp, Description: "Rotate windows upward"},
+		{Name: ActionMoveToTab, Description: "Move window to a new tab"},
+	}
+}
+
 // HandleAction processes a window action.
 func (h *Handler) HandleAction(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
 	wm := h.getWindowManager(ctx)
@@ -17,6 +17,7 @@ const (
 	PriorityCountLimit = 900  // Enforce count limits early
 	PriorityValidation = 800  // Validate before processing
 	PriorityRepeat     = 500  // Capture for repeat command
+	PriorityMacro      = 400  // Record actions into macros
 	PriorityAIContext  = 100  // Build AI context
 )
 
@@ -109,7 +110,7 @@ func (h *RepeatHook) PostDispatch(action *input.Action, ctx *execctx.ExecutionCo
 	defer h.mu.Unlock()
 
 	// Deep copy the action including nested maps
-	h.lastAction = copyAction(action)
+	h.lastAction = CopyAction(action)
 	h.lastCount = ctx.Count
 }
 
@@ -124,11 +125,12 @@ func (h *RepeatHook) LastAction() (*input.Action, int) {
 	}
 
 	// Return a copy to prevent mutation of internal state
-	return copyAction(h.lastAction), h.lastCount
+	return CopyAction(h.lastAction), h.lastCount
 }
 
-// copyAction creates a deep copy of an action including nested maps.
-func copyAction(action *input.Action) *input.Action {
+// CopyAction creates a deep copy of an action including nested maps, so
+// that hooks can keep actions after dispatch returns.
+func CopyAction(action *input.Action) *input.Action {
 	if action == nil {
 		return nil
 	}
@@ -27,6 +27,7 @@
 //	PriorityCountLimit = 900  // Enforce limits early
 //	PriorityValidation = 800  // Validation before processing
 //	PriorityRepeat    = 500  // Capture for repeat command
+//	PriorityMacro     = 400  // Record actions into macros
 //	PriorityAIContext = 100  // Build AI context
 //
 // # Built-in Hooks
@@ -59,6 +59,10 @@ type System struct {
 	// Macro recorder (shared between handler and system)
 	macroRecorder *macro.DefaultMacroRecorder
 
+	// Action recorder, which records into macroRecorder while macros
+	// record actions rather than keys
+	actionRecorder *macro.ActionRecorder
+
 	// Event publishing for integration events
 	eventPublisher integration.EventPublisher
 
@@ -123,7 +127,8 @@ func NewSystem(config SystemConfig) *System {
 	}
 
 	// Create macro recorder
-	s.macroRecorder = macro.NewDefaultMacroRecorder()
+	s.actionRecorder = macro.NewActionRecorder()
+	s.macroRecorder = s.actionRecorder.DefaultMacroRecorder
 
 	// Initialize handlers
 	s.initializeHandlers(config)
@@ -209,6 +214,9 @@ func (s *System) registerHandlers() {
 	// Register additional editor handlers for specific actions
 	// Delete, yank, indent share the "editor" namespace so we register by action
 	s.registerEditorActions()
+
+	// Argument schemas of the actions reading Extra args
+	s.dispatcher.RegisterBuiltinSchemas()
 }
 
 // registerEditorActions registers individual editor actions.
@@ -261,6 +269,9 @@ func (s *System) initializeHooks(config SystemConfig) {
 		s.aiContextHook = hook.NewAIContextHook(config.AIContextMaxChanges)
 		s.hookManager.RegisterPost(s.aiContextHook)
 	}
+
+	// Action-level macro recording, active in macro.RecordActions mode
+	s.hookManager.RegisterPost(s.actionRecorder)
 }
 
 // SetEngine sets the text engine for all operations.
@@ -411,6 +422,22 @@ func (s *System) MacroRecorder() *macro.DefaultMacroRecorder {
 	return s.macroRecorder
 }
 
+// MacroRecordMode returns whether macros record keys or actions.
+func (s *System) MacroRecordMode() macro.RecordMode {
+	return s.actionRecorder.Mode()
+}
+
+// SetMacroRecordMode switches between recording keys and actions. With
+// macro.RecordActions, the system records dispatched actions and replays
+// them through the dispatcher, so macros keep working after keys are
+// rebound. Key recording is done by the input system.
+func (s *System) SetMacroRecordMode(mode macro.RecordMode) {
+	s.actionRecorder.SetMode(mode)
+	if mode == macro.RecordActions {
+		s.EnableMacroDispatch()
+	}
+}
+
 // Metrics returns the metrics collector (may be nil if disabled).
 func (s *System) Metrics() *Metrics {
 	return s.dispatcher.Metrics()
@@ -11,6 +11,9 @@ import (
 	"github.com/dshills/keystorm/internal/engine/buffer"
 	"github.com/dshills/keystorm/internal/engine/cursor"
 	"github.com/dshills/keystorm/internal/input"
+	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/keymap"
+	"github.com/dshills/keystorm/internal/input/mode"
 )
 
 // Mock implementations that satisfy the execctx interfaces
@@ -242,6 +245,9 @@ type mockRenderer struct {
 	centerCalls   int
 	firstLine     uint32
 	lastLine      uint32
+	lastScroll    [2]uint32
+	lastCenter    uint32
+	messages      []string
 }
 
 func newMockRenderer() *mockRenderer {
@@ -253,13 +259,20 @@ func newMockRenderer() *mockRenderer {
 
 func (r *mockRenderer) Redraw()                    { r.redrawCalled = true }
 func (r *mockRenderer) RedrawLines(lines []uint32) { r.redrawCalled = true }
-func (r *mockRenderer) ScrollTo(line, col uint32)  { r.scrollToCalls++ }
+func (r *mockRenderer) ScrollTo(line, col uint32) {
+	r.scrollToCalls++
+	r.lastScroll = [2]uint32{line, col}
+}
 func (r *mockRenderer) ScrollToReveal(line, col uint32) {
 	if line < r.firstLine || line > r.lastLine {
 		r.scrollToCalls++
 	}
 }
-func (r *mockRenderer) CenterOnLine(line uint32) { r.centerCalls++ }
+func (r *mockRenderer) CenterOnLine(line uint32) {
+	r.centerCalls++
+	r.lastCenter = line
+}
+func (r *mockRenderer) ShowMessage(msg string) { r.messages = append(r.messages, msg) }
 func (r *mockRenderer) VisibleLineRange() (uint32, uint32) {
 	return r.firstLine, r.lastLine
 }
@@ -501,6 +514,76 @@ func TestSystem_MacroRecording(t *testing.T) {
 	}
 }
 
+func TestSystem_ActionMacroSurvivesRebinding(t *testing.T) {
+	sys := NewSystem(DefaultSystemConfig())
+	if sys.MacroRecordMode() != macro.RecordKeys {
+		t.Errorf("MacroRecordMode() = %v, want keys", sys.MacroRecordMode())
+	}
+	sys.SetMacroRecordMode(macro.RecordActions)
+	sys.SetSubsystems(newMockEngine("test"), newMockCursorManager(0), nil, nil, nil)
+
+	var dispatched []input.Action
+	for _, name := range []string{"test.first", "test.second"} {
+		sys.RegisterHandlerFunc(name, func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+			dispatched = append(dispatched, action)
+			return handler.Success()
+		})
+	}
+
+	h := input.NewHandler(input.DefaultConfig())
+	defer h.Close()
+	bind := func(action string) {
+		h.KeymapRegistry().Unregister("test")
+		km := keymap.NewKeymap("test").ForMode(mode.ModeNormal).WithPriority(100).Add("Q", action)
+		if err := h.KeymapRegistry().Register(km); err != nil {
+			t.Fatalf("failed to register keymap: %v", err)
+		}
+	}
+	// pressQ resolves Q through the current keymap and dispatches the action
+	pressQ := func() {
+		h.HandleKeyEvent(key.NewRuneEvent('Q', key.ModNone))
+		select {
+		case action := <-h.Actions():
+			sys.Dispatch(action)
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("expected action from key")
+		}
+	}
+
+	bind("test.first")
+	sys.Dispatch(input.Action{Name: macro.ActionStartRecord, Args: input.ActionArgs{Register: 'q'}})
+	h.SetCount(3)
+	h.SetRegister('a')
+	pressQ()
+	sys.Dispatch(input.Action{Name: macro.ActionStopRecord})
+
+	m := sys.MacroRecorder().GetMacro('q')
+	if m == nil || len(m.Actions) != 1 {
+		t.Fatalf("recorded macro = %+v, want 1 action", m)
+	}
+	if got := m.Actions[0]; got.Name != "test.first" || got.Count != 3 || got.Args.Register != 'a' {
+		t.Errorf("recorded action = %+v, want test.first with count 3 and register 'a'", got)
+	}
+
+	// After rebinding, Q dispatches a different action but the macro does not
+	bind("test.second")
+	pressQ()
+	dispatched = nil
+	result := sys.Dispatch(input.Action{Name: macro.ActionPlay, Args: input.ActionArgs{Register: 'q'}})
+	if result.Status != handler.StatusOK {
+		t.Fatalf("play status = %v: %s", result.Status, result.Message)
+	}
+	if len(dispatched) != 1 {
+		t.Fatalf("replayed %d actions, want 1", len(dispatched))
+	}
+	if got := dispatched[0]; got.Name != "test.first" || got.Count != 3 || got.Args.Register != 'a' {
+		t.Errorf("replayed action = %+v, want test.first with count 3 and register 'a'", got)
+	}
+	if m := sys.MacroRecorder().GetMacro('q'); len(m.Actions) != 1 {
+		t.Errorf("macro has %d actions after playback, want 1", len(m.Actions))
+	}
+}
+
 func TestSystem_RegisterHook(t *testing.T) {
 	sys := NewSystemWithDefaults()
 
@@ -851,3 +934,40 @@ func TestDefaultHotPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestDispatchViewEffects(t *testing.T) {
+	d := NewWithDefaults()
+	renderer := newMockRenderer()
+	modeManager := newMockModeManager("normal")
+	d.SetEngine(newMockEngine("hello world"))
+	d.SetCursors(newMockCursorManager(3))
+	d.SetRenderer(renderer)
+	d.SetModeManager(modeManager)
+
+	d.RegisterHandlerFunc("test.effects", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		return handler.Success().WithEffects(
+			handler.ScrollTo{Line: 40, Column: 2},
+			handler.CenterCursor{},
+			handler.ShowMessage{Text: "done"},
+			handler.EnterMode{Mode: "insert"},
+		)
+	})
+
+	result := d.Dispatch(input.Action{Name: "test.effects"})
+	if result.Status != handler.StatusOK {
+		t.Fatalf("expected StatusOK, got %v", result.Status)
+	}
+
+	if renderer.scrollToCalls != 1 || renderer.lastScroll != [2]uint32{40, 2} {
+		t.Errorf("ScrollTo calls = %d, last = %v, want 1 call to {40 2}", renderer.scrollToCalls, renderer.lastScroll)
+	}
+	if renderer.centerCalls != 1 || renderer.lastCenter != 0 {
+		t.Errorf("CenterOnLine calls = %d, last = %d, want 1 call to 0", renderer.centerCalls, renderer.lastCenter)
+	}
+	if len(renderer.messages) != 1 || renderer.messages[0] != "done" {
+		t.Errorf("messages = %v, want [done]", renderer.messages)
+	}
+	if modeManager.CurrentName() != "insert" {
+		t.Errorf("mode = %q, want insert", modeManager.CurrentName())
+	}
+}
@@ -0,0 +1,90 @@
+package dispatcher
+
+import (
+	"fmt"
+
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// DefaultMaxRewrites is the default limit on consecutive rewrites of a
+// single dispatch before it is treated as a rewrite loop.
+const DefaultMaxRewrites = 16
+
+// ActionRewriter transparently replaces an action before it is dispatched,
+// for example turning "editor.save" on a read-only buffer into "editor.saveAs".
+type ActionRewriter interface {
+	// Rewrite returns the action to dispatch instead, or nil to leave
+	// the action unchanged.
+	Rewrite(action input.Action, ctx *execctx.ExecutionContext) *input.Action
+}
+
+// ActionRewriterFunc is a function adapter for ActionRewriter.
+type ActionRewriterFunc func(action input.Action, ctx *execctx.ExecutionContext) *input.Action
+
+// Rewrite implements ActionRewriter.
+func (f ActionRewriterFunc) Rewrite(action input.Action, ctx *execctx.ExecutionContext) *input.Action {
+	return f(action, ctx)
+}
+
+// RegisterRewriter registers a rewriter for actions in a namespace (the
+// prefix before the first dot, e.g. "editor"). An empty namespace
+// registers the rewriter for all actions.
+func (d *Dispatcher) RegisterRewriter(namespace string, r ActionRewriter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.rewriters == nil {
+		d.rewriters = make(map[string][]ActionRewriter)
+	}
+	d.rewriters[namespace] = append(d.rewriters[namespace], r)
+}
+
+// rewritersFor returns the rewriters that apply to an action name,
+// namespace-specific rewriters first.
+func (d *Dispatcher) rewritersFor(actionName string) []ActionRewriter {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if len(d.rewriters) == 0 {
+		return nil
+	}
+
+	var result []ActionRewriter
+	if ns := extractNamespace(actionName); ns != "" {
+		result = append(result, d.rewriters[ns]...)
+	}
+	return append(result, d.rewriters[""]...)
+}
+
+// rewriteAction applies rewriters until none replaces the action.
+// The first rewriter returning a replacement wins, and the replacement is
+// offered to the rewriters again. Returns ErrRewriteLoop if the action is
+// still being rewritten after the configured maximum.
+func (d *Dispatcher) rewriteAction(action input.Action, ctx *execctx.ExecutionContext) (input.Action, error) {
+	limit := d.config.MaxRewrites
+	if limit <= 0 {
+		limit = DefaultMaxRewrites
+	}
+
+	original := action.Name
+	for rewrites := 0; ; rewrites++ {
+		var replacement *input.Action
+		for _, r := range d.rewritersFor(action.Name) {
+			if replacement = r.Rewrite(action, ctx); replacement != nil {
+				break
+			}
+		}
+		if replacement == nil {
+			return action, nil
+		}
+		if rewrites == limit {
+			return action, fmt.Errorf("%w: %s rewritten more than %d times", ErrRewriteLoop, original, limit)
+		}
+
+		action = *replacement
+		if action.Count > 0 {
+			ctx.Count = action.Count
+		}
+	}
+}
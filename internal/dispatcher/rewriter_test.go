@@ -0,0 +1,113 @@
+package dispatcher_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/dispatcher"
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// renameRewriter rewrites actions named from into actions named to.
+func renameRewriter(from, to string) dispatcher.ActionRewriter {
+	return dispatcher.ActionRewriterFunc(func(action input.Action, ctx *execctx.ExecutionContext) *input.Action {
+		if action.Name != from {
+			return nil
+		}
+		action.Name = to
+		return &action
+	})
+}
+
+func TestRewriterReadOnlySave(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	var handled []string
+	for _, name := range []string{"editor.save", "editor.saveAs"} {
+		name := name
+		d.RegisterHandlerFunc(name, func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+			handled = append(handled, name)
+			return handler.Success()
+		})
+	}
+	d.RegisterRewriter("editor", dispatcher.ActionRewriterFunc(func(action input.Action, ctx *execctx.ExecutionContext) *input.Action {
+		if action.Name == "editor.save" && ctx.IsReadOnly() {
+			return &input.Action{Name: "editor.saveAs", Args: action.Args}
+		}
+		return nil
+	}))
+
+	d.DispatchWithContext(input.Action{Name: "editor.save"}, &input.Context{IsReadOnly: true})
+	d.DispatchWithContext(input.Action{Name: "editor.save"}, &input.Context{})
+
+	if len(handled) != 2 || handled[0] != "editor.saveAs" || handled[1] != "editor.save" {
+		t.Errorf("handled = %v, want [editor.saveAs editor.save]", handled)
+	}
+}
+
+func TestRewriterChainTerminates(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	var handledName string
+	d.RegisterHandlerFunc("c.final", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		handledName = action.Name
+		return handler.Success()
+	})
+
+	var hookSaw string
+	d.RegisterPreHook(dispatcher.PreDispatchFunc(func(action *input.Action, ctx *execctx.ExecutionContext) bool {
+		hookSaw = action.Name
+		return true
+	}))
+
+	// a.start -> b.middle (namespace rewriter) -> c.final (global rewriter)
+	d.RegisterRewriter("a", renameRewriter("a.start", "b.middle"))
+	d.RegisterRewriter("", renameRewriter("b.middle", "c.final"))
+
+	result := d.Dispatch(input.Action{Name: "a.start"})
+
+	if result.Status != handler.StatusOK {
+		t.Fatalf("Status = %v, want OK (error %v)", result.Status, result.Error)
+	}
+	if handledName != "c.final" {
+		t.Errorf("handled action = %q, want c.final", handledName)
+	}
+	if hookSaw != "c.final" {
+		t.Errorf("pre-hook saw %q, want rewritten action c.final", hookSaw)
+	}
+}
+
+func TestRewriterLoopErrors(t *testing.T) {
+	d := dispatcher.New(dispatcher.DefaultConfig().WithMaxRewrites(5))
+
+	called := false
+	for _, name := range []string{"a.ping", "a.pong"} {
+		d.RegisterHandlerFunc(name, func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+			called = true
+			return handler.Success()
+		})
+	}
+
+	var rewrites int
+	d.RegisterRewriter("a", dispatcher.ActionRewriterFunc(func(action input.Action, ctx *execctx.ExecutionContext) *input.Action {
+		rewrites++
+		if action.Name == "a.ping" {
+			return &input.Action{Name: "a.pong"}
+		}
+		return &input.Action{Name: "a.ping"}
+	}))
+
+	result := d.Dispatch(input.Action{Name: "a.ping"})
+
+	if !errors.Is(result.Error, dispatcher.ErrRewriteLoop) {
+		t.Fatalf("Error = %v, want ErrRewriteLoop", result.Error)
+	}
+	if called {
+		t.Error("handler should not run when rewriting loops")
+	}
+	if rewrites != 6 {
+		t.Errorf("rewriter called %d times, want 6 (limit 5 plus the rejected rewrite)", rewrites)
+	}
+}
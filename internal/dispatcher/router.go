@@ -51,21 +51,33 @@ func (r *Router) SetFallback(h handler.Handler) {
 // Route finds the appropriate handler for an action.
 // Returns nil if no handler is found.
 func (r *Router) Route(actionName string) handler.Handler {
+	if h := r.routeNamespace(actionName); h != nil {
+		return h
+	}
+	return r.Fallback()
+}
+
+// Fallback returns the fallback handler, or nil if none is set.
+func (r *Router) Fallback() handler.Handler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.fallback
+}
+
+// routeNamespace returns the namespace handler for an action, or nil if
+// its namespace has no handler that can handle it.
+func (r *Router) routeNamespace(actionName string) handler.Handler {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Extract namespace prefix
 	namespace := extractNamespace(actionName)
-	if namespace != "" {
-		if h, ok := r.namespaces[namespace]; ok {
-			if h.CanHandle(actionName) {
-				return handler.NewNamespaceAdapter(h)
-			}
-		}
+	if namespace == "" {
+		return nil
 	}
-
-	// Fallback
-	return r.fallback
+	if h, ok := r.namespaces[namespace]; ok && h.CanHandle(actionName) {
+		return handler.NewNamespaceAdapter(h)
+	}
+	return nil
 }
 
 // GetNamespaceHandler returns the handler for a namespace.
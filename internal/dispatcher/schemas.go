@@ -0,0 +1,126 @@
+package dispatcher
+
+import (
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/editor"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/file"
+	inthandlers "github.com/dshills/keystorm/internal/dispatcher/handlers/integration"
+	"github.com/dshills/keystorm/internal/dispatcher/handlers/search"
+)
+
+// sessionArg is the optional debug session argument. Without it the
+// debug handler uses the only active session.
+var sessionArg = ArgSpec{Name: "session", Type: ArgString, Description: "debug session ID"}
+
+// builtinSchemas holds the argument schemas of the built-in actions that
+// read arguments from ActionArgs.Extra.
+var builtinSchemas = map[string]ArgSchema{
+	// Editor
+	editor.ActionPasteAfter: {Args: []ArgSpec{
+		{Name: "blockwise", Type: ArgBool, Description: "paste the text as a block"},
+	}},
+	editor.ActionPasteBefore: {Args: []ArgSpec{
+		{Name: "blockwise", Type: ArgBool, Description: "paste the text as a block"},
+	}},
+
+	// Search
+	search.ActionReplace: {Args: []ArgSpec{
+		{Name: "replacement", Type: ArgString, Description: "replacement text"},
+		{Name: "startLine", Type: ArgInt, Description: "first line of the range"},
+		{Name: "endLine", Type: ArgInt, Description: "line after the range"},
+		{Name: "global", Type: ArgBool, Description: "replace every match on a line"},
+	}},
+	search.ActionReplaceAll: {Args: []ArgSpec{
+		{Name: "replacement", Type: ArgString, Description: "replacement text"},
+		{Name: "global", Type: ArgBool, Description: "replace every match on a line"},
+	}},
+
+	// File
+	file.ActionSaveAs: {Args: []ArgSpec{
+		{Name: "path", Type: ArgString, Required: true, Description: "path to save to"},
+	}},
+	file.ActionOpen: {Args: []ArgSpec{
+		{Name: "path", Type: ArgString, Required: true, Description: "path of the file to open"},
+	}},
+
+	// Git
+	inthandlers.ActionGitCheckout: {Args: []ArgSpec{
+		{Name: "branch", Type: ArgString, Required: true, Description: "branch to switch to"},
+	}},
+	inthandlers.ActionGitCommit: {Args: []ArgSpec{
+		{Name: "message", Type: ArgString, Required: true, Description: "commit message"},
+		{Name: "amend", Type: ArgBool, Description: "amend the last commit"},
+		{Name: "allowEmpty", Type: ArgBool, Description: "allow a commit without changes"},
+		{Name: "signoff", Type: ArgBool, Description: "add a Signed-off-by trailer"},
+		{Name: "noVerify", Type: ArgBool, Description: "skip the commit hooks"},
+	}},
+	inthandlers.ActionGitAdd: {Args: []ArgSpec{
+		{Name: "all", Type: ArgBool, Description: "stage all changes"},
+		{Name: "path", Type: ArgString, Description: "path to stage"},
+		{Name: "paths", Description: "paths to stage"},
+	}},
+	inthandlers.ActionGitDiff: {Args: []ArgSpec{
+		{Name: "staged", Type: ArgBool, Description: "diff the staged changes"},
+		{Name: "path", Type: ArgString, Description: "path to diff"},
+	}},
+	inthandlers.ActionGitLog: {Args: []ArgSpec{
+		{Name: "count", Type: ArgInt, Description: "number of commits"},
+	}},
+	inthandlers.ActionGitStash: {Args: []ArgSpec{
+		{Name: "pop", Type: ArgBool, Description: "pop the latest stash"},
+		{Name: "message", Type: ArgString, Description: "stash message"},
+	}},
+	inthandlers.ActionGitBlame: {Args: []ArgSpec{
+		{Name: "path", Type: ArgString, Description: "path to blame"},
+	}},
+
+	// Task
+	inthandlers.ActionTaskRun: {Args: []ArgSpec{
+		{Name: "name", Type: ArgString, Required: true, Description: "task name"},
+	}},
+	inthandlers.ActionTaskStop: {Args: []ArgSpec{
+		{Name: "id", Type: ArgString, Required: true, Description: "execution ID"},
+	}},
+	inthandlers.ActionTaskStatus: {Args: []ArgSpec{
+		{Name: "id", Type: ArgString, Required: true, Description: "execution ID"},
+	}},
+	inthandlers.ActionTaskOutput: {Args: []ArgSpec{
+		{Name: "id", Type: ArgString, Required: true, Description: "execution ID"},
+	}},
+
+	// Debug
+	inthandlers.ActionDebugStart: {Args: []ArgSpec{
+		{Name: "adapter", Type: ArgString, Required: true, Description: "debug adapter"},
+		{Name: "program", Type: ArgString, Required: true, Description: "program to debug"},
+		{Name: "args", Description: "program arguments"},
+		{Name: "cwd", Type: ArgString, Description: "working directory"},
+		{Name: "stopOnEntry", Type: ArgBool, Description: "stop at the program entry"},
+	}},
+	inthandlers.ActionDebugStop:     {Args: []ArgSpec{sessionArg}},
+	inthandlers.ActionDebugContinue: {Args: []ArgSpec{sessionArg}},
+	inthandlers.ActionDebugStepOver: {Args: []ArgSpec{sessionArg}},
+	inthandlers.ActionDebugStepInto: {Args: []ArgSpec{sessionArg}},
+	inthandlers.ActionDebugStepOut:  {Args: []ArgSpec{sessionArg}},
+	inthandlers.ActionDebugPause:    {Args: []ArgSpec{sessionArg}},
+	inthandlers.ActionDebugBreakpointSet: {Args: []ArgSpec{
+		{Name: "file", Type: ArgString, Description: "file, defaulting to the current one"},
+		{Name: "line", Type: ArgInt, Required: true, Description: "1-based line"},
+	}},
+	inthandlers.ActionDebugBreakpointDel: {Args: []ArgSpec{
+		{Name: "id", Type: ArgString, Required: true, Description: "breakpoint ID"},
+	}},
+	inthandlers.ActionDebugVariables: {Args: []ArgSpec{sessionArg}},
+	inthandlers.ActionDebugStack:     {Args: []ArgSpec{sessionArg}},
+	inthandlers.ActionDebugEvaluate: {Args: []ArgSpec{
+		sessionArg,
+		{Name: "expression", Type: ArgString, Required: true, Description: "expression to evaluate"},
+	}},
+}
+
+// RegisterBuiltinSchemas registers the argument schemas of the built-in
+// actions, so dispatching them with mistyped or missing arguments fails
+// before any handler runs.
+func (d *Dispatcher) RegisterBuiltinSchemas() {
+	for name, schema := range builtinSchemas {
+		d.RegisterActionSchema(name, schema)
+	}
+}
@@ -0,0 +1,178 @@
+package dispatcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// throttleBypassKey marks the context of a debounced dispatch that is now
+// firing, so it is not debounced again.
+type throttleBypassKey struct{}
+
+// throttle holds the per-action rate limits and debounce delays.
+type throttle struct {
+	mu sync.Mutex
+
+	// Minimum interval between executions, by action name
+	limits map[string]time.Duration
+	// Time of the last execution of rate-limited actions
+	lastRun map[string]time.Time
+
+	// Debounce delay, by action name
+	delays map[string]time.Duration
+	// Pending debounced dispatches, by action name
+	pending map[string]*time.Timer
+	// Runs debounced dispatches once their delay passes, if set
+	schedule func(fire func())
+}
+
+// SetRateLimit limits actionName to one execution per minInterval. An
+// action dispatched again within the interval is not executed and returns
+// a StatusThrottled result. Only executions that succeed start the
+// interval, so a failed or cancelled action can be retried at once. A
+// minInterval of zero or less removes the limit.
+func (d *Dispatcher) SetRateLimit(actionName string, minInterval time.Duration) {
+	d.throttle.mu.Lock()
+	defer d.throttle.mu.Unlock()
+
+	if minInterval <= 0 {
+		delete(d.throttle.limits, actionName)
+		delete(d.throttle.lastRun, actionName)
+		return
+	}
+	if d.throttle.limits == nil {
+		d.throttle.limits = make(map[string]time.Duration)
+		d.throttle.lastRun = make(map[string]time.Time)
+	}
+	d.throttle.limits[actionName] = minInterval
+}
+
+// SetDebounce delays actionName until it has not been dispatched for
+// delay. Dispatching it returns a StatusAsync result at once; only the
+// last dispatch of a burst executes, with its own arguments and input
+// context. With async dispatch enabled its result is sent to Results. A
+// delay of zero or less removes the debounce and cancels a pending
+// dispatch.
+//
+// Actions dispatched in a batch or an open history group are not
+// debounced, so their edits stay in the group.
+func (d *Dispatcher) SetDebounce(actionName string, delay time.Duration) {
+	d.throttle.mu.Lock()
+	defer d.throttle.mu.Unlock()
+
+	if delay <= 0 {
+		delete(d.throttle.delays, actionName)
+		if timer := d.throttle.pending[actionName]; timer != nil {
+			timer.Stop()
+			delete(d.throttle.pending, actionName)
+		}
+		return
+	}
+	if d.throttle.delays == nil {
+		d.throttle.delays = make(map[string]time.Duration)
+		d.throttle.pending = make(map[string]*time.Timer)
+	}
+	d.throttle.delays[actionName] = delay
+}
+
+// SetDebounceScheduler sets how debounced actions run once their delay
+// passes. schedule is called on a timer goroutine and must call fire, for
+// example by posting it to the editor's event loop so the action does not
+// race other edits. Without a scheduler, debounced actions run on the
+// timer goroutine.
+func (d *Dispatcher) SetDebounceScheduler(schedule func(fire func())) {
+	d.throttle.mu.Lock()
+	defer d.throttle.mu.Unlock()
+	d.throttle.schedule = schedule
+}
+
+// throttleAction applies the rate limit and debounce of action. It returns
+// a result and true if the action must not execute now. Actions dispatched
+// while grouping history are not debounced.
+func (d *Dispatcher) throttleAction(parent context.Context, action input.Action, inputCtx *input.Context, grouping bool) (handler.Result, bool) {
+	t := &d.throttle
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if delay, ok := t.delays[action.Name]; ok && !grouping && parent.Value(throttleBypassKey{}) == nil {
+		if timer := t.pending[action.Name]; timer != nil {
+			timer.Stop()
+		}
+		var timer *time.Timer
+		timer = time.AfterFunc(delay, func() {
+			// A timer stopped too late to prevent it firing is stale
+			t.mu.Lock()
+			current := t.pending[action.Name] == timer
+			if current {
+				delete(t.pending, action.Name)
+			}
+			t.mu.Unlock()
+
+			if current {
+				d.fireDebounced(action, inputCtx)
+			}
+		})
+		t.pending[action.Name] = timer
+		return handler.AsyncWithMessage(action.Name + " debounced"), true
+	}
+
+	if interval, ok := t.limits[action.Name]; ok {
+		if last, ran := t.lastRun[action.Name]; ran && time.Since(last) < interval {
+			return handler.Throttled(action.Name + " throttled"), true
+		}
+	}
+	return handler.Result{}, false
+}
+
+// recordRun starts the rate limit interval of actionName if result shows
+// that it executed successfully.
+func (d *Dispatcher) recordRun(actionName string, result handler.Result) {
+	if result.Status != handler.StatusOK && result.Status != handler.StatusAsync {
+		return
+	}
+
+	t := &d.throttle
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.limits[actionName]; ok {
+		t.lastRun[actionName] = time.Now()
+	}
+}
+
+// fireDebounced dispatches a debounced action once its delay has passed,
+// through the debounce scheduler if one is set.
+func (d *Dispatcher) fireDebounced(action input.Action, inputCtx *input.Context) {
+	fire := func() {
+		c := context.WithValue(context.Background(), throttleBypassKey{}, true)
+		result := d.dispatchInternal(c, action, inputCtx)
+		if d.resultChan != nil {
+			d.sendResult(result)
+		}
+	}
+
+	d.throttle.mu.Lock()
+	schedule := d.throttle.schedule
+	d.throttle.mu.Unlock()
+
+	if schedule != nil {
+		schedule(fire)
+		return
+	}
+	fire()
+}
+
+// stopPending drops all pending debounced dispatches.
+func (t *throttle) stopPending() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for name, timer := range t.pending {
+		timer.Stop()
+		delete(t.pending, name)
+	}
+}
@@ -0,0 +1,169 @@
+package dispatcher_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dshills/keystorm/internal/dispatcher"
+	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/input"
+)
+
+func TestDispatcherRateLimit(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	var saves atomic.Int32
+	d.RegisterHandlerFunc("editor.save", func(input.Action, *execctx.ExecutionContext) handler.Result {
+		saves.Add(1)
+		return handler.Success()
+	})
+	d.SetRateLimit("editor.save", time.Hour)
+
+	first := d.Dispatch(input.Action{Name: "editor.save"})
+	if first.Status != handler.StatusOK {
+		t.Errorf("first save status = %v, want ok", first.Status)
+	}
+	for i := 0; i < 5; i++ {
+		if result := d.Dispatch(input.Action{Name: "editor.save"}); result.Status != handler.StatusThrottled {
+			t.Errorf("repeated save status = %v, want throttled", result.Status)
+		}
+	}
+	if got := saves.Load(); got != 1 {
+		t.Errorf("save executed %d times, want 1", got)
+	}
+
+	// Removing the limit lets it run again
+	d.SetRateLimit("editor.save", 0)
+	d.Dispatch(input.Action{Name: "editor.save"})
+	if got := saves.Load(); got != 2 {
+		t.Errorf("save executed %d times after removing limit, want 2", got)
+	}
+}
+
+func TestDispatcherDebounce(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+	defer d.Stop()
+
+	fired := make(chan int, 10)
+	d.RegisterHandlerFunc("completion.trigger", func(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+		fired <- action.Count
+		return handler.Success()
+	})
+	d.SetDebounce("completion.trigger", 30*time.Millisecond)
+
+	for i := 1; i <= 5; i++ {
+		result := d.Dispatch(input.Action{Name: "completion.trigger", Count: i})
+		if result.Status != handler.StatusAsync {
+			t.Errorf("debounced dispatch status = %v, want async", result.Status)
+		}
+	}
+
+	select {
+	case count := <-fired:
+		t.Fatalf("action fired before the delay with count %d", count)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	select {
+	case count := <-fired:
+		if count != 5 {
+			t.Errorf("fired with count %d, want the last dispatch's 5", count)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("debounced action never fired")
+	}
+
+	select {
+	case count := <-fired:
+		t.Errorf("debounced action fired again with count %d", count)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestDispatcherRateLimitIgnoresFailedRuns(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+
+	var saves atomic.Int32
+	d.RegisterHandlerFunc("editor.save", func(input.Action, *execctx.ExecutionContext) handler.Result {
+		if saves.Add(1) == 1 {
+			return handler.Errorf("disk full")
+		}
+		return handler.Success()
+	})
+	d.SetRateLimit("editor.save", time.Hour)
+
+	if result := d.Dispatch(input.Action{Name: "editor.save"}); result.Status != handler.StatusError {
+		t.Fatalf("failing save status = %v, want error", result.Status)
+	}
+	if result := d.Dispatch(input.Action{Name: "editor.save"}); result.Status != handler.StatusOK {
+		t.Errorf("retried save status = %v, want ok", result.Status)
+	}
+	if result := d.Dispatch(input.Action{Name: "editor.save"}); result.Status != handler.StatusThrottled {
+		t.Errorf("save after success status = %v, want throttled", result.Status)
+	}
+	if got := saves.Load(); got != 2 {
+		t.Errorf("save executed %d times, want 2", got)
+	}
+}
+
+func TestDispatcherDebounceInBatch(t *testing.T) {
+	eng := engine.New(engine.WithContent(""))
+	d := newBatchDispatcher(eng)
+	defer d.Stop()
+	d.SetDebounce("test.append", time.Hour)
+
+	results := d.DispatchBatch([]input.Action{
+		{Name: "test.append", Args: input.ActionArgs{Extra: map[string]interface{}{"text": "a"}}},
+		{Name: "test.append", Args: input.ActionArgs{Extra: map[string]interface{}{"text": "b"}}},
+	}, "batch")
+	for i, result := range results {
+		if result.Status != handler.StatusOK {
+			t.Errorf("result %d status = %v, want ok", i, result.Status)
+		}
+	}
+	if got := eng.Text(); got != "ab" {
+		t.Errorf("text = %q, want %q", got, "ab")
+	}
+
+	// The whole batch undoes in one step
+	if err := eng.Undo(); err != nil {
+		t.Fatalf("Undo() failed: %v", err)
+	}
+	if got := eng.Text(); got != "" {
+		t.Errorf("text after undo = %q, want empty", got)
+	}
+}
+
+func TestDispatcherDebounceScheduler(t *testing.T) {
+	d := dispatcher.NewWithDefaults()
+	defer d.Stop()
+
+	var fired atomic.Int32
+	d.RegisterHandlerFunc("completion.trigger", func(input.Action, *execctx.ExecutionContext) handler.Result {
+		fired.Add(1)
+		return handler.Success()
+	})
+
+	scheduled := make(chan func(), 1)
+	d.SetDebounceScheduler(func(fire func()) { scheduled <- fire })
+	d.SetDebounce("completion.trigger", time.Millisecond)
+
+	d.Dispatch(input.Action{Name: "completion.trigger"})
+
+	var fire func()
+	select {
+	case fire = <-scheduled:
+	case <-time.After(time.Second):
+		t.Fatal("debounced action was never scheduled")
+	}
+	if got := fired.Load(); got != 0 {
+		t.Fatalf("action ran %d times before the scheduler ran it", got)
+	}
+	fire()
+	if got := fired.Load(); got != 1 {
+		t.Errorf("action ran %d times, want 1", got)
+	}
+}
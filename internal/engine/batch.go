@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/dshills/keystorm/internal/engine/buffer"
+)
+
+// ChangeEvent describes the buffer changes made by one engine operation.
+// A single edit produces an event with one change; ApplyEdits and WithBatch
+// produce one event for all their edits.
+type ChangeEvent struct {
+	// Changes are the applied edits in application order.
+	Changes []buffer.BufferChange
+
+	// Revision is the buffer revision after the changes.
+	Revision RevisionID
+}
+
+// changeListeners holds the listeners registered with OnChange.
+type changeListeners struct {
+	mu      sync.Mutex
+	nextID  uint64
+	entries map[uint64]func(ChangeEvent)
+}
+
+// OnChange registers fn to be called after each operation that changes the
+// buffer, and returns a function that removes it. Listeners run while the
+// engine's write lock is held and must not call back into the Engine; they
+// should read content through the snapshots in the event.
+func (e *Engine) OnChange(fn func(ChangeEvent)) func() {
+	if fn == nil {
+		return func() {}
+	}
+
+	l := &e.changeListeners
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.entries == nil {
+		l.entries = make(map[uint64]func(ChangeEvent))
+	}
+	l.nextID++
+	id := l.nextID
+	l.entries[id] = fn
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.entries, id)
+	}
+}
+
+// collectChange is the buffer change listener feeding OnChange listeners.
+// While edits are coalesced the change is held back for a single event.
+// It runs with the engine write lock held.
+func (e *Engine) collectChange(change buffer.BufferChange) {
+	if e.coalesceChanges {
+		e.pendingChanges = append(e.pendingChanges, change)
+		return
+	}
+	e.emitChange([]buffer.BufferChange{change})
+}
+
+// emitChange calls the OnChange listeners with changes, if any.
+func (e *Engine) emitChange(changes []buffer.BufferChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	l := &e.changeListeners
+	l.mu.Lock()
+	ids := make([]uint64, 0, len(l.entries))
+	for id := range l.entries {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	fns := make([]func(ChangeEvent), len(ids))
+	for i, id := range ids {
+		fns[i] = l.entries[id]
+	}
+	l.mu.Unlock()
+
+	event := ChangeEvent{Changes: changes, Revision: changes[len(changes)-1].Revision}
+	for _, fn := range fns {
+		fn(event)
+	}
+}
+
+// EditBatch collects edits for WithBatch. Offsets refer to the buffer as it
+// was when the batch started, so earlier edits in the batch do not shift
+// later ones.
+type EditBatch struct {
+	edits []Edit
+}
+
+// Insert inserts text at offset.
+func (b *EditBatch) Insert(offset ByteOffset, text string) {
+	b.Replace(offset, offset, text)
+}
+
+// Delete removes the text in [start, end).
+func (b *EditBatch) Delete(start, end ByteOffset) {
+	b.Replace(start, end, "")
+}
+
+// Replace replaces the text in [start, end) with text.
+func (b *EditBatch) Replace(start, end ByteOffset, text string) {
+	b.edits = append(b.edits, Edit{Range: Range{Start: start, End: end}, NewText: text})
+}
+
+// Len returns the number of edits collected.
+func (b *EditBatch) Len() int {
+	return len(b.edits)
+}
+
+// ordered returns the edits in the reverse order ApplyEdits expects.
+// Text inserted at the same offset keeps the order of the batch.
+func (b *EditBatch) ordered() []Edit {
+	edits := slices.Clone(b.edits)
+	slices.Reverse(edits)
+	slices.SortStableFunc(edits, func(x, y Edit) int {
+		if x.Range.Start != y.Range.Start {
+			return int(y.Range.Start - x.Range.Start)
+		}
+		return int(y.Range.End - x.Range.End)
+	})
+	return edits
+}
+
+// WithBatch calls fn to collect edits, then applies them atomically as a
+// single undo step named name and a single change event. It is the
+// counterpart of ApplyEdits for callers, such as plugins, that produce
+// many edits one at a time.
+//
+// Nothing is applied if fn returns an error, if edits overlap or are out of
+// range, or if the buffer changed while fn ran (ErrBatchStale).
+func (e *Engine) WithBatch(name string, fn func(b *EditBatch) error) error {
+	rev := e.RevisionID()
+
+	b := &EditBatch{}
+	if err := fn(b); err != nil {
+		return err
+	}
+	if len(b.edits) == 0 {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.readOnly {
+		return ErrReadOnly
+	}
+	if e.buf.RevisionID() != rev {
+		return ErrBatchStale
+	}
+
+	return e.applyGroupedEditsLocked(name, b.ordered())
+}
@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithBatch(t *testing.T) {
+	e := New(WithContent(strings.Repeat("x\n", 100)))
+
+	var events []ChangeEvent
+	e.OnChange(func(ev ChangeEvent) { events = append(events, ev) })
+	undoBefore := e.UndoCount()
+
+	err := e.WithBatch("number lines", func(b *EditBatch) error {
+		for line := uint32(0); line < 100; line++ {
+			b.Insert(e.LineStartOffset(line), "> ")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithBatch() error = %v", err)
+	}
+
+	if want := strings.Repeat("> x\n", 100); e.Text() != want {
+		t.Errorf("Text() = %q, want %q", e.Text()[:20], want[:20])
+	}
+	if got := e.UndoCount() - undoBefore; got != 1 {
+		t.Errorf("undo entries added = %d, want 1", got)
+	}
+	if len(events) != 1 {
+		t.Fatalf("change events = %d, want 1", len(events))
+	}
+	if len(events[0].Changes) != 100 || events[0].Revision != e.RevisionID() {
+		t.Errorf("event has %d changes at %v, want 100 at %v", len(events[0].Changes), events[0].Revision, e.RevisionID())
+	}
+
+	if err := e.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if want := strings.Repeat("x\n", 100); e.Text() != want {
+		t.Error("Undo() should revert the whole batch")
+	}
+}
+
+func TestWithBatchOrder(t *testing.T) {
+	e := New(WithContent("hello world"))
+
+	err := e.WithBatch("edit", func(b *EditBatch) error {
+		b.Insert(0, "a")
+		b.Insert(0, "b")
+		b.Replace(6, 11, "there")
+		b.Delete(5, 6)
+		b.Insert(11, "!")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithBatch() error = %v", err)
+	}
+	if got, want := e.Text(), "abhellothere!"; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestWithBatchRollback(t *testing.T) {
+	e := New(WithContent("hello"))
+	var events int
+	e.OnChange(func(ChangeEvent) { events++ })
+	undoBefore := e.UndoCount()
+
+	errAbort := errors.New("abort")
+	err := e.WithBatch("edit", func(b *EditBatch) error {
+		b.Insert(0, "x")
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Errorf("WithBatch() error = %v, want %v", err, errAbort)
+	}
+
+	err = e.WithBatch("edit", func(b *EditBatch) error {
+		b.Insert(0, "x")
+		b.Delete(2, 99)
+		return nil
+	})
+	if err == nil {
+		t.Error("WithBatch() with an out of range edit should fail")
+	}
+
+	err = e.WithBatch("edit", func(b *EditBatch) error {
+		b.Insert(0, "x")
+		_, _ = e.Insert(5, "!")
+		return nil
+	})
+	if !errors.Is(err, ErrBatchStale) {
+		t.Errorf("WithBatch() error = %v, want ErrBatchStale", err)
+	}
+
+	if got := e.Text(); got != "hello!" {
+		t.Errorf("Text() = %q, want only the direct insert applied", got)
+	}
+	if got := e.UndoCount() - undoBefore; got != 1 {
+		t.Errorf("undo entries added = %d, want 1", got)
+	}
+	if events != 1 {
+		t.Errorf("change events = %d, want 1", events)
+	}
+}
+
+func TestWithBatchInvalidEditsApplyNothing(t *testing.T) {
+	tests := []struct {
+		name  string
+		edits func(b *EditBatch)
+		want  error
+	}{
+		{"overlap", func(b *EditBatch) {
+			b.Replace(5, 10, "X")
+			b.Replace(3, 7, "Y")
+		}, ErrEditsOverlap},
+		{"out of range", func(b *EditBatch) {
+			b.Insert(5, "A")
+			b.Delete(2, 100)
+		}, ErrRangeInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent("abcdefghij"))
+			var events int
+			e.OnChange(func(ChangeEvent) { events++ })
+
+			err := e.WithBatch("edit", func(b *EditBatch) error {
+				tt.edits(b)
+				return nil
+			})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("WithBatch() error = %v, want %v", err, tt.want)
+			}
+			if got := e.Text(); got != "abcdefghij" {
+				t.Errorf("Text() = %q, want the buffer untouched", got)
+			}
+			if e.CanUndo() {
+				t.Error("CanUndo() = true, want no undo entry")
+			}
+			if events != 0 {
+				t.Errorf("change events = %d, want 0", events)
+			}
+		})
+	}
+}
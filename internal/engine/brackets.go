@@ -0,0 +1,263 @@
+package engine
+
+import (
+	"unicode/utf8"
+
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/rope"
+)
+
+// BracketPair defines an opening and closing bracket.
+type BracketPair struct {
+	Open  rune
+	Close rune
+}
+
+// DefaultBracketPairs are the bracket pairs used for matching and auto-pairing.
+var DefaultBracketPairs = []BracketPair{
+	{Open: '(', Close: ')'},
+	{Open: '[', Close: ']'},
+	{Open: '{', Close: '}'},
+}
+
+// BracketSkipper reports whether the character at offset should be ignored
+// by bracket matching, typically because it is inside a string or comment.
+// Implementations are language-aware and supplied by the caller.
+type BracketSkipper func(offset ByteOffset) bool
+
+// WithAutoPair enables auto-pair insertion for TypeText.
+func WithAutoPair(enabled bool) Option {
+	return func(e *Engine) {
+		e.autoPair = enabled
+	}
+}
+
+// WithBracketSkipper sets the skipper used by MatchBracket.
+func WithBracketSkipper(skip BracketSkipper) Option {
+	return func(e *Engine) {
+		e.bracketSkipper = skip
+	}
+}
+
+// SetAutoPair enables or disables auto-pair insertion.
+func (e *Engine) SetAutoPair(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.autoPair = enabled
+	if !enabled {
+		e.autoClosers = nil
+	}
+}
+
+// AutoPairEnabled returns true if auto-pair insertion is enabled.
+func (e *Engine) AutoPairEnabled() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.autoPair
+}
+
+// SetBracketSkipper sets the skipper used by MatchBracket.
+// Pass nil to match brackets everywhere.
+func (e *Engine) SetBracketSkipper(skip BracketSkipper) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.bracketSkipper = skip
+}
+
+// MatchBracket finds the bracket matching the one at or after offset on the
+// same line, respecting nesting. Brackets for which the configured
+// BracketSkipper returns true are ignored. Returns the offset of the
+// matching bracket and true, or false if there is no bracket on the line,
+// it is unbalanced, or the line is longer than the maximum line scan length.
+func (e *Engine) MatchBracket(offset ByteOffset) (ByteOffset, bool) {
+	e.mu.RLock()
+	snap := e.buf.Snapshot()
+	if e.longLineLocked(snap, snap.OffsetToPoint(offset).Line) {
+		e.mu.RUnlock()
+		return 0, false
+	}
+	r := snap.Rope()
+	skip := e.bracketSkipper
+	e.mu.RUnlock()
+
+	return matchBracket(r, offset, skip)
+}
+
+// matchBracket implements MatchBracket on a rope.
+func matchBracket(r rope.Rope, offset ByteOffset, skip BracketSkipper) (ByteOffset, bool) {
+	length := ByteOffset(r.Len())
+	if offset < 0 || offset >= length {
+		return 0, false
+	}
+
+	// Find a bracket at or after offset on the current line
+	line := r.OffsetToPoint(rope.ByteOffset(offset)).Line
+	lineEnd := ByteOffset(r.LineEndOffset(line))
+
+	c := rope.NewCursor(r)
+	c.SeekOffset(rope.ByteOffset(offset))
+	var (
+		start ByteOffset = -1
+		pair  BracketPair
+		open  bool
+	)
+	for pos := offset; pos < lineEnd; {
+		ch, size := c.Rune()
+		if size == 0 {
+			break
+		}
+		if p, isOpen, ok := lookupBracket(ch); ok && (skip == nil || !skip(pos)) {
+			start, pair, open = pos, p, isOpen
+			break
+		}
+		pos += ByteOffset(size)
+		c.Next()
+	}
+	if start < 0 {
+		return 0, false
+	}
+
+	if open {
+		return scanForward(c, start, pair, skip)
+	}
+	return scanBackward(c, pair, skip)
+}
+
+// scanForward scans from the opening bracket under c for its closer.
+func scanForward(c *rope.Cursor, start ByteOffset, pair BracketPair, skip BracketSkipper) (ByteOffset, bool) {
+	depth := 0
+	pos := start
+	for {
+		ch, size := c.Rune()
+		if size == 0 {
+			return 0, false
+		}
+		if skip == nil || !skip(pos) {
+			switch ch {
+			case pair.Open:
+				depth++
+			case pair.Close:
+				depth--
+				if depth == 0 {
+					return pos, true
+				}
+			}
+		}
+		pos += ByteOffset(size)
+		if !c.Next() {
+			return 0, false
+		}
+	}
+}
+
+// scanBackward scans backward from the closing bracket under c for its
+// opener.
+func scanBackward(c *rope.Cursor, pair BracketPair, skip BracketSkipper) (ByteOffset, bool) {
+	depth := 1
+	for c.Prev() {
+		pos := ByteOffset(c.Offset())
+		if skip != nil && skip(pos) {
+			continue
+		}
+		switch ch, _ := c.Rune(); ch {
+		case pair.Close:
+			depth++
+		case pair.Open:
+			depth--
+			if depth == 0 {
+				return pos, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// lookupBracket returns the pair containing ch and whether ch is the opener.
+func lookupBracket(ch rune) (BracketPair, bool, bool) {
+	for _, p := range DefaultBracketPairs {
+		if ch == p.Open {
+			return p, true, true
+		}
+		if ch == p.Close {
+			return p, false, true
+		}
+	}
+	return BracketPair{}, false, false
+}
+
+// TypeText inserts text as if typed by the user and returns the offset
+// where the cursor should be placed afterwards.
+//
+// With auto-pair enabled, typing an opening bracket also inserts its closer
+// and places the cursor between them; typing a closer directly over one that
+// was auto-inserted moves past it instead of inserting a duplicate.
+// Otherwise TypeText behaves like Insert.
+func (e *Engine) TypeText(offset ByteOffset, text string) (ByteOffset, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.readOnly {
+		return 0, ErrReadOnly
+	}
+
+	ch, size := utf8.DecodeRuneInString(text)
+	if !e.autoPair || size == 0 || size != len(text) {
+		return e.insertLocked(offset, text)
+	}
+
+	pair, isOpen, ok := lookupBracket(ch)
+	if !ok {
+		return e.insertLocked(offset, text)
+	}
+
+	if !isOpen {
+		// Type over an auto-inserted closer
+		if i := e.autoCloserIndex(offset); i >= 0 {
+			if next, _ := e.buf.RuneAt(offset); next == ch {
+				e.autoClosers = append(e.autoClosers[:i], e.autoClosers[i+1:]...)
+				return offset + ByteOffset(size), nil
+			}
+		}
+		return e.insertLocked(offset, text)
+	}
+
+	closer := string(pair.Close)
+	if _, err := e.insertLocked(offset, text+closer); err != nil {
+		return 0, err
+	}
+	cursorPos := offset + ByteOffset(size)
+	e.autoClosers = append(e.autoClosers, cursorPos)
+	return cursorPos, nil
+}
+
+// autoCloserIndex returns the index of the auto-inserted closer at offset, or -1.
+func (e *Engine) autoCloserIndex(offset ByteOffset) int {
+	for i, pos := range e.autoClosers {
+		if pos == offset {
+			return i
+		}
+	}
+	return -1
+}
+
+// trackAutoClosers keeps auto-inserted closer positions in sync with edits.
+// Closers inside a modified range are forgotten. It is registered as a buffer
+// change listener and therefore runs with the engine write lock held.
+func (e *Engine) trackAutoClosers(change buffer.BufferChange) {
+	if len(e.autoClosers) == 0 {
+		return
+	}
+
+	edit := change.Edit
+	delta := edit.Delta()
+	kept := e.autoClosers[:0]
+	for _, pos := range e.autoClosers {
+		switch {
+		case pos < edit.Range.Start:
+			kept = append(kept, pos)
+		case pos >= edit.Range.End:
+			kept = append(kept, pos+delta)
+		}
+	}
+	e.autoClosers = kept
+}
@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchBracket(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		offset  ByteOffset
+		want    ByteOffset
+		found   bool
+	}{
+		{"simple forward", "(a)", 0, 2, true},
+		{"simple backward", "(a)", 2, 0, true},
+		{"nested outer", "f(a(b)c)", 1, 7, true},
+		{"nested inner", "f(a(b)c)", 3, 5, true},
+		{"nested backward", "f(a(b)c)", 7, 1, true},
+		{"search forward on line", "x = [1, 2]", 0, 9, true},
+		{"mixed kinds", "{[()]}", 1, 4, true},
+		{"multi-line", "func() {\n\treturn\n}", 7, 17, true},
+		{"unbalanced open", "((a)", 0, 0, false},
+		{"unbalanced close", "a))", 1, 0, false},
+		{"no bracket on line", "abc\n()", 0, 0, false},
+		{"out of range", "()", 5, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(tt.content))
+			got, found := e.MatchBracket(tt.offset)
+			if found != tt.found {
+				t.Fatalf("MatchBracket(%d) found = %v, want %v", tt.offset, found, tt.found)
+			}
+			if found && got != tt.want {
+				t.Errorf("MatchBracket(%d) = %d, want %d", tt.offset, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchBracketMultiByte(t *testing.T) {
+	content := "(héllo 世界 ✓)"
+	e := New(WithContent(content))
+
+	closeOffset := ByteOffset(strings.LastIndex(content, ")"))
+	got, found := e.MatchBracket(0)
+	if !found || got != closeOffset {
+		t.Errorf("MatchBracket(0) = %d, %v; want %d, true", got, found, closeOffset)
+	}
+
+	got, found = e.MatchBracket(closeOffset)
+	if !found || got != 0 {
+		t.Errorf("MatchBracket(%d) = %d, %v; want 0, true", closeOffset, got, found)
+	}
+}
+
+func TestMatchBracketLongRange(t *testing.T) {
+	content := "(" + strings.Repeat("héllo [世界]\n", 2000) + ")"
+	e := New(WithContent(content))
+
+	closeOffset := ByteOffset(len(content) - 1)
+	if got, found := e.MatchBracket(closeOffset); !found || got != 0 {
+		t.Errorf("MatchBracket(%d) = %d, %v; want 0, true", closeOffset, got, found)
+	}
+}
+
+func TestMatchBracketSkipper(t *testing.T) {
+	content := `f(")", x)`
+	quoteStart := ByteOffset(strings.Index(content, `"`))
+	quoteEnd := ByteOffset(strings.LastIndex(content, `"`))
+
+	inString := func(offset ByteOffset) bool {
+		return offset >= quoteStart && offset <= quoteEnd
+	}
+
+	e := New(WithContent(content))
+	if got, _ := e.MatchBracket(1); got != 3 {
+		t.Errorf("without skipper MatchBracket(1) = %d, want 3", got)
+	}
+
+	e.SetBracketSkipper(inString)
+	want := ByteOffset(len(content) - 1)
+	if got, found := e.MatchBracket(1); !found || got != want {
+		t.Errorf("with skipper MatchBracket(1) = %d, %v; want %d, true", got, found, want)
+	}
+}
+
+func TestTypeTextAutoPair(t *testing.T) {
+	e := New(WithAutoPair(true))
+
+	pos, err := e.TypeText(0, "(")
+	if err != nil {
+		t.Fatalf("TypeText failed: %v", err)
+	}
+	if e.Text() != "()" || pos != 1 {
+		t.Fatalf("after '(' text = %q pos = %d, want \"()\" 1", e.Text(), pos)
+	}
+
+	pos, _ = e.TypeText(pos, "a")
+	if e.Text() != "(a)" || pos != 2 {
+		t.Fatalf("after 'a' text = %q pos = %d, want \"(a)\" 2", e.Text(), pos)
+	}
+
+	// Typing the closer moves past the auto-inserted one
+	pos, _ = e.TypeText(pos, ")")
+	if e.Text() != "(a)" || pos != 3 {
+		t.Fatalf("after ')' text = %q pos = %d, want \"(a)\" 3", e.Text(), pos)
+	}
+
+	// A second closer is inserted normally
+	pos, _ = e.TypeText(pos, ")")
+	if e.Text() != "(a))" || pos != 4 {
+		t.Errorf("after second ')' text = %q pos = %d, want \"(a))\" 4", e.Text(), pos)
+	}
+}
+
+func TestTypeTextAutoPairNested(t *testing.T) {
+	e := New(WithAutoPair(true))
+
+	pos, _ := e.TypeText(0, "{")
+	pos, _ = e.TypeText(pos, "[")
+	if e.Text() != "{[]}" || pos != 2 {
+		t.Fatalf("text = %q pos = %d, want \"{[]}\" 2", e.Text(), pos)
+	}
+
+	pos, _ = e.TypeText(pos, "]")
+	pos, _ = e.TypeText(pos, "}")
+	if e.Text() != "{[]}" || pos != 4 {
+		t.Errorf("text = %q pos = %d, want \"{[]}\" 4", e.Text(), pos)
+	}
+}
+
+func TestTypeTextAutoPairForgetsEditedCloser(t *testing.T) {
+	e := New(WithAutoPair(true))
+
+	pos, _ := e.TypeText(0, "(")
+	if err := e.Delete(1, 2); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	pos, _ = e.TypeText(pos, ")")
+	if e.Text() != "()" || pos != 2 {
+		t.Errorf("text = %q pos = %d, want \"()\" 2", e.Text(), pos)
+	}
+}
+
+func TestTypeTextWithoutAutoPair(t *testing.T) {
+	e := New()
+
+	pos, _ := e.TypeText(0, "(")
+	if e.Text() != "(" || pos != 1 {
+		t.Errorf("text = %q pos = %d, want \"(\" 1", e.Text(), pos)
+	}
+}
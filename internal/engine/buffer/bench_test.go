@@ -0,0 +1,45 @@
+package buffer
+
+import (
+	"strings"
+	"testing"
+)
+
+// newLargeBuffer creates a buffer with the given number of short lines.
+func newLargeBuffer(lines int) *Buffer {
+	var sb strings.Builder
+	sb.Grow(lines * 32)
+	for i := 0; i < lines; i++ {
+		sb.WriteString("the quick brown fox jumps over\n")
+	}
+	return NewBufferFromString(sb.String())
+}
+
+func BenchmarkLinesWindow(b *testing.B) {
+	buf := newLargeBuffer(1_000_000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r, err := buf.Lines(500_000, 500_999)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_ = r.Len()
+	}
+}
+
+func BenchmarkLinesWindowNaive(b *testing.B) {
+	buf := newLargeBuffer(1_000_000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var sb strings.Builder
+		for line := uint32(500_000); line <= 500_999; line++ {
+			if line > 500_000 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(buf.LineText(line))
+		}
+		_ = sb.Len()
+	}
+}
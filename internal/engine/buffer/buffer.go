@@ -63,6 +63,7 @@ type Buffer struct {
 	revisionID RevisionID
 	lineEnding LineEnding
 	tabWidth   int
+	listeners  changeListeners
 }
 
 // NewBuffer creates a new empty buffer.
@@ -156,14 +157,38 @@ func (b *Buffer) LineCount() uint32 {
 	return b.rope.LineCount()
 }
 
-// LineText returns the text of a specific line (without newline).
+// LineText returns the text of a specific line (without "\n" or "\r\n").
 func (b *Buffer) LineText(line uint32) string {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 	return b.rope.LineText(line)
 }
 
-// LineLen returns the length of a specific line in bytes (without newline).
+// Lines returns a rope covering lines startLine through endLine, inclusive.
+// The result includes the line endings between the lines but not the
+// terminator of endLine, matching LineText. Line numbers are clamped to the
+// buffer's line range; ErrRangeInvalid is returned if startLine > endLine
+// after clamping.
+//
+// The returned rope shares structure with the buffer, so extraction costs
+// O(log n + k) rather than copying the text line by line.
+func (b *Buffer) Lines(startLine, endLine int) (rope.Rope, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	lastLine := int(b.rope.LineCount()) - 1
+	startLine = max(startLine, 0)
+	endLine = min(endLine, lastLine)
+	if startLine > endLine {
+		return rope.Rope{}, ErrRangeInvalid
+	}
+
+	start := b.rope.LineStartOffset(uint32(startLine))
+	end := b.rope.LineEndOffset(uint32(endLine))
+	return b.rope.SubRope(start, end), nil
+}
+
+// LineLen returns the length of a specific line in bytes (without "\n" or "\r\n").
 func (b *Buffer) LineLen(line uint32) int {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -255,7 +280,7 @@ func (b *Buffer) LineStartOffset(line uint32) ByteOffset {
 	return ByteOffset(b.rope.LineStartOffset(line))
 }
 
-// LineEndOffset returns the byte offset of the end of a line (before newline).
+// LineEndOffset returns the byte offset of the end of a line, before its terminator.
 func (b *Buffer) LineEndOffset(line uint32) ByteOffset {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -275,8 +300,10 @@ func (b *Buffer) Insert(offset ByteOffset, text string) (ByteOffset, error) {
 	}
 
 	text = b.normalizeLineEndings(text)
+	before, oldRev := b.rope, b.revisionID
 	b.rope = b.rope.Insert(rope.ByteOffset(offset), text)
 	b.revisionID = NewRevisionID()
+	b.notifyLocked(oldRev, changeStep{before: before, after: b.rope, edit: NewInsert(offset, text)})
 
 	return offset + ByteOffset(len(text)), nil
 }
@@ -290,8 +317,10 @@ func (b *Buffer) Delete(start, end ByteOffset) error {
 		return ErrRangeInvalid
 	}
 
+	before, oldRev := b.rope, b.revisionID
 	b.rope = b.rope.Delete(rope.ByteOffset(start), rope.ByteOffset(end))
 	b.revisionID = NewRevisionID()
+	b.notifyLocked(oldRev, changeStep{before: before, after: b.rope, edit: NewDelete(start, end)})
 
 	return nil
 }
@@ -307,8 +336,10 @@ func (b *Buffer) Replace(start, end ByteOffset, text string) (ByteOffset, error)
 	}
 
 	text = b.normalizeLineEndings(text)
+	before, oldRev := b.rope, b.revisionID
 	b.rope = b.rope.Replace(rope.ByteOffset(start), rope.ByteOffset(end), text)
 	b.revisionID = NewRevisionID()
+	b.notifyLocked(oldRev, changeStep{before: before, after: b.rope, edit: NewEdit(Range{Start: start, End: end}, text)})
 
 	return start + ByteOffset(len(text)), nil
 }
@@ -325,8 +356,10 @@ func (b *Buffer) ApplyEdit(edit Edit) (EditResult, error) {
 
 	oldText := b.rope.Slice(rope.ByteOffset(edit.Range.Start), rope.ByteOffset(edit.Range.End))
 	text := b.normalizeLineEndings(edit.NewText)
+	before, oldRev := b.rope, b.revisionID
 	b.rope = b.rope.Replace(rope.ByteOffset(edit.Range.Start), rope.ByteOffset(edit.Range.End), text)
 	b.revisionID = NewRevisionID()
+	b.notifyLocked(oldRev, changeStep{before: before, after: b.rope, edit: NewEdit(edit.Range, text)})
 
 	newEnd := edit.Range.Start + ByteOffset(len(text))
 
@@ -364,16 +397,46 @@ func (b *Buffer) ApplyEdits(edits []Edit) error {
 		}
 	}
 
-	// Apply edits in reverse order
-	for _, edit := range edits {
-		text := b.normalizeLineEndings(edit.NewText)
-		b.rope = b.rope.Replace(rope.ByteOffset(edit.Range.Start), rope.ByteOffset(edit.Range.End), text)
+	// Apply all edits in a single pass over the rope
+	ropeEdits := make([]rope.Edit, len(edits))
+	for i, edit := range edits {
+		ropeEdits[i] = rope.Edit{
+			Start: rope.ByteOffset(edit.Range.Start),
+			End:   rope.ByteOffset(edit.Range.End),
+			Text:  b.normalizeLineEndings(edit.NewText),
+		}
+	}
+	after, err := b.rope.ApplyEdits(ropeEdits)
+	if err != nil {
+		return ErrRangeInvalid
 	}
 
+	before, oldRev := b.rope, b.revisionID
+	b.rope = after
 	b.revisionID = NewRevisionID()
+	if !b.listeners.empty() {
+		b.notifyLocked(oldRev, editSteps(before, after, ropeEdits)...)
+	}
 	return nil
 }
 
+// editSteps describes edits, given in reverse order, that turned before
+// into after, for listeners that are notified of each edit in turn. No
+// intermediate rope is built: every step carries the ropes on either side
+// of the whole batch.
+func editSteps(before, after rope.Rope, edits []rope.Edit) []changeStep {
+	steps := make([]changeStep, len(edits))
+	for i, e := range edits {
+		steps[i] = changeStep{
+			before:    before,
+			after:     after,
+			edit:      NewEdit(Range{Start: ByteOffset(e.Start), End: ByteOffset(e.End)}, e.Text),
+			remaining: len(edits) - 1 - i,
+		}
+	}
+	return steps
+}
+
 // Buffer State
 
 // RevisionID returns the current revision ID.
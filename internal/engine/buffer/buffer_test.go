@@ -407,6 +407,19 @@ func TestBufferWithCRLFLineEnding(t *testing.T) {
 	if b.Text() != expected {
 		t.Errorf("expected %q, got %q", expected, b.Text())
 	}
+
+	// Line lengths and ends exclude the "\r\n" terminator
+	for line := uint32(0); line < 3; line++ {
+		if got := b.LineLen(line); got != 5 {
+			t.Errorf("LineLen(%d) = %d, want 5", line, got)
+		}
+		if got, want := b.LineEndOffset(line), b.LineStartOffset(line)+5; got != want {
+			t.Errorf("LineEndOffset(%d) = %d, want %d", line, got, want)
+		}
+	}
+	if got := b.Snapshot().LineLen(0); got != 5 {
+		t.Errorf("Snapshot().LineLen(0) = %d, want 5", got)
+	}
 }
 
 func TestBufferRevisionID(t *testing.T) {
@@ -610,3 +623,42 @@ func TestChangeInvert(t *testing.T) {
 		t.Error("inverted should have original old text as new text")
 	}
 }
+
+func TestBufferLines(t *testing.T) {
+	b := NewBufferFromString("zero\none\ntwo\nthree")
+
+	tests := []struct {
+		name       string
+		start, end int
+		want       string
+	}{
+		{"single line", 1, 1, "one"},
+		{"inclusive range", 1, 2, "one\ntwo"},
+		{"whole buffer", 0, 3, "zero\none\ntwo\nthree"},
+		{"clamp negative start", -5, 0, "zero"},
+		{"clamp end past last line", 2, 100, "two\nthree"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := b.Lines(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("Lines(%d, %d) error = %v", tt.start, tt.end, err)
+			}
+			if r.String() != tt.want {
+				t.Errorf("Lines(%d, %d) = %q, want %q", tt.start, tt.end, r.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestBufferLinesInvalid(t *testing.T) {
+	b := NewBufferFromString("a\nb")
+
+	if _, err := b.Lines(1, 0); !errors.Is(err, ErrRangeInvalid) {
+		t.Errorf("Lines(1, 0) error = %v, want ErrRangeInvalid", err)
+	}
+	if _, err := b.Lines(5, 10); !errors.Is(err, ErrRangeInvalid) {
+		t.Errorf("Lines(5, 10) error = %v, want ErrRangeInvalid", err)
+	}
+}
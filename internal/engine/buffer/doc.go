@@ -11,6 +11,7 @@
 //   - Read-only snapshots for concurrent access
 //   - Line ending normalization
 //   - Revision tracking for change management
+//   - Change listeners notified synchronously after each mutation
 //
 // Basic usage:
 //
@@ -46,4 +47,8 @@
 // while write operations acquire an exclusive write lock. For scenarios
 // requiring multiple reads without the possibility of intervening writes,
 // use Snapshot() to obtain a consistent read-only view.
+//
+// Change listeners registered with OnChange run while the write lock is held.
+// They must read content from the BufferChange snapshots, never from the
+// Buffer itself.
 package buffer
@@ -0,0 +1,220 @@
+package buffer
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/dshills/keystorm/internal/engine/rope"
+)
+
+// BufferChange describes a single mutation applied to a buffer.
+//
+// Before and After are immutable snapshots of the content around the edit.
+// Listeners must read buffer content through these snapshots rather than
+// through the Buffer itself, since listeners run while the buffer's write
+// lock is held.
+//
+// The edits of an ApplyEdits batch are notified one by one, highest offset
+// first, but no content is built between them: Before and After are the
+// content before and after the whole batch. The positions of each change
+// are computed as if the edits were applied in turn, so they agree with
+// Before up to the end of the edit, and listeners needing the new content
+// should wait for the change with Remaining zero.
+type BufferChange struct {
+	// Edit is the applied edit, with line endings already normalized.
+	Edit Edit
+
+	// Revision is the buffer revision after the change.
+	Revision RevisionID
+
+	// StartLine is the first line affected by the change.
+	StartLine uint32
+
+	// OldEndLine is the last affected line (inclusive) in the content before the change.
+	OldEndLine uint32
+
+	// NewEndLine is the last affected line (inclusive) in the content after the change.
+	NewEndLine uint32
+
+	// Start is the position of the start of the edit.
+	Start Point
+
+	// OldEnd is the position of the end of the replaced range before the change.
+	OldEnd Point
+
+	// NewEnd is the position of the end of the inserted text after the change.
+	NewEnd Point
+
+	// Remaining is the number of changes of the same batch still to be
+	// notified after this one. It is zero for a single edit.
+	Remaining int
+
+	// Before is the buffer content before the change.
+	Before *Snapshot
+
+	// After is the buffer content after the change.
+	After *Snapshot
+}
+
+// ChangeListener is called after each buffer mutation.
+type ChangeListener func(change BufferChange)
+
+// listenerEntry pairs a listener with the identity used for removal.
+type listenerEntry struct {
+	id uint64
+	fn ChangeListener
+}
+
+// changeListeners manages the set of registered change listeners.
+// It has its own lock so listeners can be added or removed from within
+// a callback without touching the buffer lock.
+type changeListeners struct {
+	mu      sync.Mutex
+	nextID  uint64
+	entries []listenerEntry
+}
+
+// add registers a listener and returns its id.
+func (l *changeListeners) add(fn ChangeListener) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextID++
+	l.entries = append(l.entries, listenerEntry{id: l.nextID, fn: fn})
+	return l.nextID
+}
+
+// remove unregisters the listener with the given id.
+func (l *changeListeners) remove(id uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, e := range l.entries {
+		if e.id == id {
+			l.entries = append(l.entries[:i:i], l.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// empty returns true if no listeners are registered.
+func (l *changeListeners) empty() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries) == 0
+}
+
+// snapshot returns a copy of the registered listeners in registration order.
+func (l *changeListeners) snapshot() []ChangeListener {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) == 0 {
+		return nil
+	}
+	fns := make([]ChangeListener, len(l.entries))
+	for i, e := range l.entries {
+		fns[i] = e.fn
+	}
+	return fns
+}
+
+// OnChange registers a listener that is invoked after every mutation,
+// in registration order. It returns a function that removes the listener;
+// calling it more than once is safe.
+//
+// Listeners run synchronously after the mutation is applied and before the
+// mutating method returns, while the buffer's write lock is still held.
+// A listener must not call methods on the Buffer; it should use the Before
+// and After snapshots in the BufferChange instead.
+func (b *Buffer) OnChange(fn ChangeListener) func() {
+	if fn == nil {
+		return func() {}
+	}
+
+	id := b.listeners.add(fn)
+	var once sync.Once
+	return func() {
+		once.Do(func() { b.listeners.remove(id) })
+	}
+}
+
+// changeStep records one edit and the ropes on either side of it. For an
+// edit of a batch, before and after are the ropes on either side of the
+// whole batch and remaining counts the edits that follow.
+type changeStep struct {
+	before    rope.Rope
+	after     rope.Rope
+	edit      Edit
+	remaining int
+}
+
+// notifyLocked informs listeners of the applied steps, in order.
+// oldRev is the revision before the first step. Caller must hold the write lock.
+func (b *Buffer) notifyLocked(oldRev RevisionID, steps ...changeStep) {
+	fns := b.listeners.snapshot()
+	if len(fns) == 0 {
+		return
+	}
+
+	// The steps of a batch share the ropes on either side of it
+	before := b.snapshotOf(steps[0].before, oldRev)
+	after := b.snapshotOf(steps[0].after, b.revisionID)
+	for _, step := range steps {
+		change := b.buildChange(step, before, after)
+		for _, fn := range fns {
+			fn(change)
+		}
+	}
+}
+
+// buildChange builds the change record for a single step. The positions
+// are computed from the rope before the edit and the inserted text. For an
+// edit of a batch the rope before the batch serves, since the edits
+// applied before it all lie at higher offsets.
+func (b *Buffer) buildChange(step changeStep, before, after *Snapshot) BufferChange {
+	edit := step.edit
+	start := toPoint(step.before.OffsetToPoint(rope.ByteOffset(edit.Range.Start)))
+	oldEnd := toPoint(step.before.OffsetToPoint(rope.ByteOffset(edit.Range.End)))
+	newEnd := advancePoint(start, edit.NewText)
+	return BufferChange{
+		Edit:       edit,
+		Revision:   b.revisionID,
+		StartLine:  start.Line,
+		OldEndLine: oldEnd.Line,
+		NewEndLine: newEnd.Line,
+		Start:      start,
+		OldEnd:     oldEnd,
+		NewEnd:     newEnd,
+		Remaining:  step.remaining,
+		Before:     before,
+		After:      after,
+	}
+}
+
+// toPoint converts a rope point to a buffer point.
+func toPoint(p rope.Point) Point {
+	return Point{Line: p.Line, Column: p.Column}
+}
+
+// advancePoint returns the position reached by inserting text at p.
+func advancePoint(p Point, text string) Point {
+	last := strings.LastIndexByte(text, '\n')
+	if last < 0 {
+		return Point{Line: p.Line, Column: p.Column + uint32(len(text))}
+	}
+	return Point{
+		Line:   p.Line + uint32(strings.Count(text, "\n")),
+		Column: uint32(len(text) - last - 1),
+	}
+}
+
+// snapshotOf wraps a rope in a snapshot carrying the buffer's settings.
+func (b *Buffer) snapshotOf(r rope.Rope, rev RevisionID) *Snapshot {
+	return &Snapshot{
+		rope:       r,
+		revisionID: rev,
+		lineEnding: b.lineEnding,
+		tabWidth:   b.tabWidth,
+	}
+}
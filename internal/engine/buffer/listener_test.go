@@ -0,0 +1,183 @@
+package buffer
+
+import "testing"
+
+func TestOnChangeInsert(t *testing.T) {
+	b := NewBufferFromString("hello\nworld")
+
+	var got []BufferChange
+	b.OnChange(func(c BufferChange) {
+		got = append(got, c)
+	})
+
+	if _, err := b.Insert(5, "\nthere"); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 change, got %d", len(got))
+	}
+	c := got[0]
+	if !c.Edit.IsInsert() || c.Edit.NewText != "\nthere" {
+		t.Errorf("unexpected edit %v", c.Edit)
+	}
+	if c.Revision != b.RevisionID() {
+		t.Errorf("change revision %v, want %v", c.Revision, b.RevisionID())
+	}
+	if c.StartLine != 0 || c.OldEndLine != 0 || c.NewEndLine != 1 {
+		t.Errorf("lines = %d/%d/%d, want 0/0/1", c.StartLine, c.OldEndLine, c.NewEndLine)
+	}
+	if c.Before.Text() != "hello\nworld" {
+		t.Errorf("before = %q", c.Before.Text())
+	}
+	if c.After.Text() != "hello\nthere\nworld" {
+		t.Errorf("after = %q", c.After.Text())
+	}
+}
+
+func TestOnChangeOrder(t *testing.T) {
+	b := NewBufferFromString("abc")
+
+	var order []int
+	for i := 1; i <= 3; i++ {
+		n := i
+		b.OnChange(func(BufferChange) {
+			order = append(order, n)
+		})
+	}
+
+	if err := b.Delete(0, 1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if len(order) != 3 || order[0] != 1 || order[1] != 2 || order[2] != 3 {
+		t.Errorf("listener order = %v, want [1 2 3]", order)
+	}
+}
+
+func TestOnChangeRemove(t *testing.T) {
+	b := NewBufferFromString("abc")
+
+	calls := 0
+	remove := b.OnChange(func(BufferChange) {
+		calls++
+	})
+
+	_, _ = b.Insert(0, "x")
+	remove()
+	remove() // second call is a no-op
+	_, _ = b.Insert(0, "y")
+
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestOnChangeRemoveFromCallback(t *testing.T) {
+	b := NewBufferFromString("abc")
+
+	calls := 0
+	var remove func()
+	remove = b.OnChange(func(BufferChange) {
+		calls++
+		remove()
+	})
+
+	_, _ = b.Insert(0, "x")
+	_, _ = b.Insert(0, "y")
+
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestOnChangeApplyEdits(t *testing.T) {
+	b := NewBufferFromString("one two three")
+
+	var got []BufferChange
+	b.OnChange(func(c BufferChange) {
+		got = append(got, c)
+	})
+
+	edits := []Edit{
+		NewEdit(Range{Start: 8, End: 13}, "3"),
+		NewEdit(Range{Start: 0, End: 3}, "1"),
+	}
+	if err := b.ApplyEdits(edits); err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 changes, got %d", len(got))
+	}
+	// Both changes carry the content on either side of the batch
+	for i, c := range got {
+		if c.Before.Text() != "one two three" || c.After.Text() != "1 two 3" {
+			t.Errorf("change %d before/after = %q/%q", i, c.Before.Text(), c.After.Text())
+		}
+		if c.Revision != b.RevisionID() {
+			t.Errorf("change revision %v, want %v", c.Revision, b.RevisionID())
+		}
+		if c.Remaining != 1-i {
+			t.Errorf("change %d Remaining = %d, want %d", i, c.Remaining, 1-i)
+		}
+	}
+	if got[0].Start != (Point{Line: 0, Column: 8}) || got[0].NewEnd != (Point{Line: 0, Column: 9}) {
+		t.Errorf("first change Start/NewEnd = %v/%v, want (0:8)/(0:9)", got[0].Start, got[0].NewEnd)
+	}
+}
+
+func TestOnChangeApplyEditsPositions(t *testing.T) {
+	b := NewBufferFromString("a\nb\nc\nd")
+
+	var got []BufferChange
+	b.OnChange(func(c BufferChange) {
+		got = append(got, c)
+	})
+
+	// The first edit adds lines, which must not shift the second, lower edit
+	edits := []Edit{
+		NewEdit(Range{Start: 6, End: 7}, "x\ny\nz"),
+		NewEdit(Range{Start: 0, End: 3}, "q"),
+	}
+	if err := b.ApplyEdits(edits); err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 changes, got %d", len(got))
+	}
+
+	tests := []struct {
+		start, oldEnd, newEnd Point
+	}{
+		{Point{Line: 3, Column: 0}, Point{Line: 3, Column: 1}, Point{Line: 5, Column: 1}},
+		{Point{Line: 0, Column: 0}, Point{Line: 1, Column: 1}, Point{Line: 0, Column: 1}},
+	}
+	for i, tt := range tests {
+		c := got[i]
+		if c.Start != tt.start || c.OldEnd != tt.oldEnd || c.NewEnd != tt.newEnd {
+			t.Errorf("change %d = %v-%v->%v, want %v-%v->%v",
+				i, c.Start, c.OldEnd, c.NewEnd, tt.start, tt.oldEnd, tt.newEnd)
+		}
+		if c.StartLine != tt.start.Line || c.OldEndLine != tt.oldEnd.Line || c.NewEndLine != tt.newEnd.Line {
+			t.Errorf("change %d lines = %d/%d/%d, want %d/%d/%d", i,
+				c.StartLine, c.OldEndLine, c.NewEndLine, tt.start.Line, tt.oldEnd.Line, tt.newEnd.Line)
+		}
+	}
+}
+
+func TestOnChangeFailedEditDoesNotNotify(t *testing.T) {
+	b := NewBufferFromString("abc")
+
+	calls := 0
+	b.OnChange(func(BufferChange) {
+		calls++
+	})
+
+	if err := b.Delete(2, 10); err == nil {
+		t.Fatal("expected error for invalid range")
+	}
+	if calls != 0 {
+		t.Errorf("expected no calls, got %d", calls)
+	}
+}
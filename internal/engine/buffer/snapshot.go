@@ -36,12 +36,12 @@ func (s *Snapshot) LineCount() uint32 {
 	return s.rope.LineCount()
 }
 
-// LineText returns the text of a specific line (without newline).
+// LineText returns the text of a specific line (without "\n" or "\r\n").
 func (s *Snapshot) LineText(line uint32) string {
 	return s.rope.LineText(line)
 }
 
-// LineLen returns the length of a specific line in bytes (without newline).
+// LineLen returns the length of a specific line in bytes (without "\n" or "\r\n").
 func (s *Snapshot) LineLen(line uint32) int {
 	start := s.rope.LineStartOffset(line)
 	end := s.rope.LineEndOffset(line)
@@ -110,7 +110,7 @@ func (s *Snapshot) LineStartOffset(line uint32) ByteOffset {
 	return ByteOffset(s.rope.LineStartOffset(line))
 }
 
-// LineEndOffset returns the byte offset of the end of a line (before newline).
+// LineEndOffset returns the byte offset of the end of a line, before its terminator.
 func (s *Snapshot) LineEndOffset(line uint32) ByteOffset {
 	return ByteOffset(s.rope.LineEndOffset(line))
 }
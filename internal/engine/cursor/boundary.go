@@ -0,0 +1,372 @@
+package cursor
+
+import "github.com/dshills/keystorm/internal/engine/rope"
+
+// Sentence and paragraph boundaries follow Vim's definitions:
+//
+//   - A paragraph is a run of non-empty lines. Empty lines (containing no
+//     characters at all) separate paragraphs; a run of empty lines is
+//     treated as a paragraph of its own by paragraphStart and paragraphEnd.
+//   - A sentence ends at '.', '!' or '?' followed by the end of a line or
+//     by a space or tab. Any number of closing ')', ']', '"' and '\''
+//     characters may appear between the punctuation and the white space.
+//   - A paragraph boundary is also a sentence boundary.
+//
+// The functions work on the rope line by line and only extract the text of
+// the paragraph containing the offset, so they are cheap on large buffers.
+
+// ParagraphStart returns the start offset of the paragraph containing
+// offset. On an empty line it returns the start of the run of empty lines.
+func ParagraphStart(r rope.Rope, offset ByteOffset) ByteOffset {
+	return ByteOffset(paragraphStart(r, ropeOffset(offset)))
+}
+
+// ParagraphEnd returns the end offset (exclusive) of the paragraph containing
+// offset, including the newline of its last line. On an empty line it
+// returns the end of the run of empty lines.
+func ParagraphEnd(r rope.Rope, offset ByteOffset) ByteOffset {
+	return ByteOffset(paragraphEnd(r, ropeOffset(offset)))
+}
+
+// NextParagraph returns the target of Vim's "}" motion from offset: the
+// start of the next empty line after the current paragraph, or the end of
+// the last line if there is none.
+func NextParagraph(r rope.Rope, offset ByteOffset) ByteOffset {
+	return ByteOffset(nextParagraph(r, ropeOffset(offset)))
+}
+
+// PrevParagraph returns the target of Vim's "{" motion from offset: the
+// start of the empty line before the current paragraph, or the start of
+// the buffer if there is none.
+func PrevParagraph(r rope.Rope, offset ByteOffset) ByteOffset {
+	return ByteOffset(prevParagraph(r, ropeOffset(offset)))
+}
+
+// SentenceStart returns the start offset of the sentence containing offset.
+// White space following a sentence belongs to that sentence. On an empty
+// line it returns the start of the line.
+func SentenceStart(r rope.Rope, offset ByteOffset) ByteOffset {
+	return ByteOffset(sentenceStart(r, ropeOffset(offset)))
+}
+
+// SentenceEnd returns the end offset (exclusive) of the sentence containing
+// offset: just past its terminating punctuation and closing characters,
+// before any trailing white space. On an empty line it returns the start of
+// the line.
+func SentenceEnd(r rope.Rope, offset ByteOffset) ByteOffset {
+	return ByteOffset(sentenceEnd(r, ropeOffset(offset)))
+}
+
+// NextSentence returns the target of Vim's ")" motion from offset: the
+// start of the next sentence. The empty line ending a paragraph counts as
+// a sentence; from an empty line the motion moves to the first sentence of
+// the next paragraph. Returns the buffer length if there is no next sentence.
+func NextSentence(r rope.Rope, offset ByteOffset) ByteOffset {
+	return ByteOffset(nextSentence(r, ropeOffset(offset)))
+}
+
+// PrevSentence returns the target of Vim's "(" motion from offset: the
+// start of the current sentence, or of the previous sentence if offset is
+// already at a sentence start. A run of empty lines before a paragraph
+// counts as one sentence.
+func PrevSentence(r rope.Rope, offset ByteOffset) ByteOffset {
+	return ByteOffset(prevSentence(r, ropeOffset(offset)))
+}
+
+// paragraphStart implements ParagraphStart.
+func paragraphStart(r rope.Rope, offset rope.ByteOffset) rope.ByteOffset {
+	if r.Len() == 0 {
+		return 0
+	}
+
+	line := lineOf(r, offset)
+	empty := isEmptyLine(r, line)
+	for line > 0 && isEmptyLine(r, line-1) == empty {
+		line--
+	}
+	return r.LineStartOffset(line)
+}
+
+// paragraphEnd implements ParagraphEnd.
+func paragraphEnd(r rope.Rope, offset rope.ByteOffset) rope.ByteOffset {
+	if r.Len() == 0 {
+		return 0
+	}
+
+	line := lineOf(r, offset)
+	last := lastLine(r)
+	empty := isEmptyLine(r, line)
+	for line < last && isEmptyLine(r, line+1) == empty {
+		line++
+	}
+	if line == last {
+		return r.Len()
+	}
+	return r.LineStartOffset(line + 1)
+}
+
+// nextParagraph implements NextParagraph.
+func nextParagraph(r rope.Rope, offset rope.ByteOffset) rope.ByteOffset {
+	if r.Len() == 0 {
+		return 0
+	}
+
+	line := lineOf(r, offset)
+	last := lastLine(r)
+	for line < last && isEmptyLine(r, line) {
+		line++
+	}
+	for line < last && !isEmptyLine(r, line) {
+		line++
+	}
+	if !isEmptyLine(r, line) {
+		return r.LineEndOffset(line)
+	}
+	return r.LineStartOffset(line)
+}
+
+// prevParagraph implements PrevParagraph.
+func prevParagraph(r rope.Rope, offset rope.ByteOffset) rope.ByteOffset {
+	if r.Len() == 0 {
+		return 0
+	}
+
+	line := lineOf(r, offset)
+	for line > 0 && isEmptyLine(r, line) {
+		line--
+	}
+	for line > 0 && !isEmptyLine(r, line) {
+		line--
+	}
+	return r.LineStartOffset(line)
+}
+
+// sentenceStart implements SentenceStart.
+func sentenceStart(r rope.Rope, offset rope.ByteOffset) rope.ByteOffset {
+	p, ok := paragraphSentences(r, offset)
+	if !ok {
+		return p.start
+	}
+	return p.start + p.sentences[p.index].start
+}
+
+// sentenceEnd implements SentenceEnd.
+func sentenceEnd(r rope.Rope, offset rope.ByteOffset) rope.ByteOffset {
+	p, ok := paragraphSentences(r, offset)
+	if !ok {
+		return p.start
+	}
+	return p.start + p.sentences[p.index].end
+}
+
+// nextSentence implements NextSentence.
+func nextSentence(r rope.Rope, offset rope.ByteOffset) rope.ByteOffset {
+	if r.Len() == 0 {
+		return 0
+	}
+
+	p, ok := paragraphSentences(r, offset)
+	if ok && p.index+1 < len(p.sentences) {
+		return p.start + p.sentences[p.index+1].start
+	}
+
+	// Move to the paragraph boundary, then past any empty lines
+	next := paragraphEnd(r, offset)
+	if ok {
+		return next
+	}
+	if next >= r.Len() {
+		return r.Len()
+	}
+	q, _ := paragraphSentences(r, next)
+	if len(q.sentences) == 0 {
+		return next
+	}
+	return q.start + q.sentences[0].start
+}
+
+// prevSentence implements PrevSentence.
+func prevSentence(r rope.Rope, offset rope.ByteOffset) rope.ByteOffset {
+	if offset > r.Len() {
+		offset = r.Len()
+	}
+	if offset == 0 {
+		return 0
+	}
+
+	p, ok := paragraphSentences(r, offset)
+	if ok {
+		if start := p.start + p.sentences[p.index].start; start < offset {
+			return start
+		}
+		if p.index > 0 {
+			return p.start + p.sentences[p.index-1].start
+		}
+		// At the first sentence: the preceding run of empty lines is the boundary
+		if p.start == 0 {
+			return 0
+		}
+		return paragraphStart(r, p.start-1)
+	}
+
+	// On empty lines: move to the last sentence of the previous paragraph
+	start := paragraphStart(r, offset)
+	if start == 0 {
+		return 0
+	}
+	q, ok := paragraphSentences(r, start-1)
+	if !ok {
+		return q.start
+	}
+	return q.start + q.sentences[len(q.sentences)-1].start
+}
+
+// sentenceSpan is a sentence within a paragraph, as offsets relative to the
+// paragraph start.
+type sentenceSpan struct {
+	start rope.ByteOffset
+	end   rope.ByteOffset
+}
+
+// paragraphText holds the sentences of one paragraph.
+type paragraphText struct {
+	// start is the paragraph's offset in the rope.
+	start rope.ByteOffset
+
+	// sentences are the paragraph's sentences in order.
+	sentences []sentenceSpan
+
+	// index is the sentence containing the queried offset.
+	index int
+}
+
+// paragraphSentences splits the paragraph containing offset into sentences.
+// Returns false if offset is on an empty line (or the buffer is empty), in
+// which case only start is set, to the start of that line.
+func paragraphSentences(r rope.Rope, offset rope.ByteOffset) (paragraphText, bool) {
+	if offset > r.Len() {
+		offset = r.Len()
+	}
+	if r.Len() == 0 {
+		return paragraphText{}, false
+	}
+
+	line := lineOf(r, offset)
+	if isEmptyLine(r, line) {
+		return paragraphText{start: r.LineStartOffset(line)}, false
+	}
+
+	p := paragraphText{start: paragraphStart(r, offset)}
+	text := r.Slice(p.start, paragraphEnd(r, offset))
+	p.sentences = splitSentences(text)
+
+	rel := offset - p.start
+	for i, s := range p.sentences {
+		if s.start > rel {
+			break
+		}
+		p.index = i
+	}
+	return p, true
+}
+
+// splitSentences returns the sentences of a paragraph's text. Leading
+// white space belongs to the first sentence's start; trailing white space
+// is excluded from each sentence's end.
+func splitSentences(text string) []sentenceSpan {
+	n := rope.ByteOffset(len(text))
+	start := skipSentenceSpace(text, 0)
+	if start == n {
+		return []sentenceSpan{{start: 0, end: 0}}
+	}
+
+	var sentences []sentenceSpan
+	for i := start; i < n; i++ {
+		if !isSentenceTerminator(text[i]) {
+			continue
+		}
+
+		end := i + 1
+		for end < n && isSentenceCloser(text[end]) {
+			end++
+		}
+		if end < n && !isSentenceSpace(text[end]) {
+			i = end - 1
+			continue
+		}
+
+		sentences = append(sentences, sentenceSpan{start: start, end: end})
+		start = skipSentenceSpace(text, end)
+		i = start - 1
+	}
+
+	if start < n {
+		end := n
+		for end > start && isSentenceSpace(text[end-1]) {
+			end--
+		}
+		sentences = append(sentences, sentenceSpan{start: start, end: end})
+	}
+	return sentences
+}
+
+// skipSentenceSpace returns the first offset at or after i that is not
+// white space.
+func skipSentenceSpace(text string, i rope.ByteOffset) rope.ByteOffset {
+	for i < rope.ByteOffset(len(text)) && isSentenceSpace(text[i]) {
+		i++
+	}
+	return i
+}
+
+func isSentenceTerminator(b byte) bool {
+	return b == '.' || b == '!' || b == '?'
+}
+
+func isSentenceCloser(b byte) bool {
+	return b == ')' || b == ']' || b == '"' || b == '\''
+}
+
+func isSentenceSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// lineOf returns the line containing offset, clamped to the last line.
+func lineOf(r rope.Rope, offset rope.ByteOffset) uint32 {
+	if offset > r.Len() {
+		offset = r.Len()
+	}
+	line := r.OffsetToPoint(offset).Line
+	if last := lastLine(r); line > last {
+		line = last
+	}
+	return line
+}
+
+// lastLine returns the index of the last line. A trailing newline does not
+// start a new line, matching how Vim presents the buffer.
+func lastLine(r rope.Rope) uint32 {
+	count := r.LineCount()
+	if count == 0 {
+		return 0
+	}
+	last := count - 1
+	if last > 0 && r.LineStartOffset(last) == r.Len() {
+		last--
+	}
+	return last
+}
+
+// isEmptyLine reports whether a line contains no characters.
+func isEmptyLine(r rope.Rope, line uint32) bool {
+	return r.LineStartOffset(line) == r.LineEndOffset(line)
+}
+
+// ropeOffset converts a cursor offset to a rope offset, clamping negative
+// offsets to the start of the buffer.
+func ropeOffset(offset ByteOffset) rope.ByteOffset {
+	if offset < 0 {
+		return 0
+	}
+	return rope.ByteOffset(offset)
+}
@@ -0,0 +1,144 @@
+package cursor
+
+import (
+	"testing"
+
+	"github.com/dshills/keystorm/internal/engine/rope"
+)
+
+func TestSentenceBoundaries(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		offset ByteOffset
+		start  ByteOffset
+		end    ByteOffset
+		next   ByteOffset
+	}{
+		{"first sentence", "One. Two.", 1, 0, 4, 5},
+		{"trailing space belongs to sentence", "One. Two.", 4, 0, 4, 5},
+		{"last sentence", "One. Two.", 6, 5, 9, 9},
+		{"closing paren", "Hello.) World", 2, 0, 7, 8},
+		{"closing quote", `He said "hi." Then left.`, 3, 0, 13, 14},
+		{"abbreviation mid-word", "Use e.g.this one. Next", 5, 0, 17, 18},
+		{"repeated punctuation", "Wait?! Yes", 1, 0, 6, 7},
+		{"tab separator", "One.\tTwo.", 6, 5, 9, 9},
+		{"end of line", "One.\nTwo.", 7, 5, 9, 9},
+		{"no space after period", "v1.2 is out", 3, 0, 11, 11},
+		{"leading indent", "  Indented. Next", 4, 2, 11, 12},
+		{"empty line ends sentence", "No period\n\nNext.", 2, 0, 9, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rope.FromString(tt.text)
+			if got := SentenceStart(r, tt.offset); got != tt.start {
+				t.Errorf("SentenceStart(%d) = %d, want %d", tt.offset, got, tt.start)
+			}
+			if got := SentenceEnd(r, tt.offset); got != tt.end {
+				t.Errorf("SentenceEnd(%d) = %d, want %d", tt.offset, got, tt.end)
+			}
+			if got := NextSentence(r, tt.offset); got != tt.next {
+				t.Errorf("NextSentence(%d) = %d, want %d", tt.offset, got, tt.next)
+			}
+		})
+	}
+}
+
+func TestSentenceMotionsAcrossParagraphs(t *testing.T) {
+	// Offsets: "One. Two." 0-8, "\n" 9, "" 10, "" 11, "Three." 12-17
+	r := rope.FromString("One. Two.\n\n\nThree.")
+
+	forward := []ByteOffset{0, 5, 10, 12, 18}
+	for i := 0; i < len(forward)-1; i++ {
+		if got := NextSentence(r, forward[i]); got != forward[i+1] {
+			t.Errorf("NextSentence(%d) = %d, want %d", forward[i], got, forward[i+1])
+		}
+	}
+
+	backward := []ByteOffset{18, 12, 10, 5, 0}
+	for i := 0; i < len(backward)-1; i++ {
+		if got := PrevSentence(r, backward[i]); got != backward[i+1] {
+			t.Errorf("PrevSentence(%d) = %d, want %d", backward[i], got, backward[i+1])
+		}
+	}
+
+	// From the middle of a sentence, "(" goes to its start
+	if got := PrevSentence(r, 7); got != 5 {
+		t.Errorf("PrevSentence(7) = %d, want 5", got)
+	}
+}
+
+func TestParagraphBoundaries(t *testing.T) {
+	// Lines: 0 "a" (0), 1 "b" (2), 2 "" (4), 3 "" (5), 4 "c" (6), 5 "  " (8), 6 "d" (11)
+	r := rope.FromString("a\nb\n\n\nc\n  \nd\n")
+
+	tests := []struct {
+		name   string
+		offset ByteOffset
+		start  ByteOffset
+		end    ByteOffset
+	}{
+		{"first paragraph", 2, 0, 4},
+		{"empty line run", 5, 4, 6},
+		{"whitespace-only line is not a boundary", 9, 6, 13},
+		{"last paragraph", 11, 6, 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParagraphStart(r, tt.offset); got != tt.start {
+				t.Errorf("ParagraphStart(%d) = %d, want %d", tt.offset, got, tt.start)
+			}
+			if got := ParagraphEnd(r, tt.offset); got != tt.end {
+				t.Errorf("ParagraphEnd(%d) = %d, want %d", tt.offset, got, tt.end)
+			}
+		})
+	}
+}
+
+func TestParagraphMotions(t *testing.T) {
+	r := rope.FromString("a\nb\n\n\nc\n  \nd\n")
+
+	forward := []struct{ from, want ByteOffset }{
+		{0, 4},
+		{4, 12}, // skips the empty run, then "c", "  " and "d" to the end of the last line
+		{5, 12},
+		{12, 12},
+	}
+	for _, tt := range forward {
+		if got := NextParagraph(r, tt.from); got != tt.want {
+			t.Errorf("NextParagraph(%d) = %d, want %d", tt.from, got, tt.want)
+		}
+	}
+
+	backward := []struct{ from, want ByteOffset }{
+		{11, 5},
+		{5, 0},
+		{2, 0},
+		{0, 0},
+	}
+	for _, tt := range backward {
+		if got := PrevParagraph(r, tt.from); got != tt.want {
+			t.Errorf("PrevParagraph(%d) = %d, want %d", tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestBoundariesEmptyRope(t *testing.T) {
+	r := rope.New()
+	for name, fn := range map[string]func(rope.Rope, ByteOffset) ByteOffset{
+		"SentenceStart":  SentenceStart,
+		"SentenceEnd":    SentenceEnd,
+		"NextSentence":   NextSentence,
+		"PrevSentence":   PrevSentence,
+		"ParagraphStart": ParagraphStart,
+		"ParagraphEnd":   ParagraphEnd,
+		"NextParagraph":  NextParagraph,
+		"PrevParagraph":  PrevParagraph,
+	} {
+		if got := fn(r, 3); got != 0 {
+			t.Errorf("%s on empty rope = %d, want 0", name, got)
+		}
+	}
+}
@@ -6,6 +6,9 @@
 //   - Text selections with anchor/head model via Selection type
 //   - Multi-cursor support with CursorSet
 //   - Cursor transformation after buffer edits
+//   - Vim sentence and paragraph boundaries over a rope
+//   - Vim word and WORD boundaries with a configurable iskeyword
+//   - Indentation blocks for the "ii" and "ai" text objects
 //
 // Selection Model:
 //
@@ -40,6 +43,14 @@
 //	edit := buffer.Edit{Range: buffer.Range{Start: 0, End: 5}, NewText: "Hello"}
 //	cursor.TransformCursorSet(cs, edit)
 //
+// Text Objects:
+//
+// SentenceStart, SentenceEnd, NextSentence, PrevSentence and their paragraph
+// counterparts compute Vim's sentence and paragraph boundaries as pure
+// functions of a rope.Rope and an offset. Motions ("(", ")", "{", "}") and
+// text objects ("is", "ip") share them so both agree on punctuation and
+// empty-line handling.
+//
 // Thread Safety:
 //
 // Cursor and Selection types are immutable value types and safe for
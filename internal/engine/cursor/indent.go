@@ -0,0 +1,168 @@
+package cursor
+
+import (
+	"strings"
+
+	"github.com/dshills/keystorm/internal/engine/rope"
+)
+
+// defaultIndentTabWidth is the tab width used for indentation levels when
+// IndentBlockOptions.TabWidth is not set.
+const defaultIndentTabWidth = 8
+
+// IndentBlockOptions configures IndentBlock.
+type IndentBlockOptions struct {
+	// TabWidth is the width of a tab when comparing indentation.
+	// Zero or less uses 8.
+	TabWidth int
+
+	// BlankLinesBreak ends the block at a blank line. By default blank
+	// lines inside the block belong to it.
+	BlankLinesBreak bool
+
+	// IsTrailer reports whether a line, without leading white space,
+	// closes the block above it (such as "}" or "end"), so the around
+	// variant includes it. Nil accepts lines starting with ')', ']' or '}'.
+	IsTrailer func(text string) bool
+}
+
+// IndentBlock returns the indentation block around line, the target of the
+// "ii" and "ai" text objects, as the offsets [start, end) of whole lines.
+// The block is the run of lines indented at least as far as line; blank
+// lines at its edges are excluded. On a blank line the next non-blank line
+// is used, or the previous one if there is none.
+//
+// The around variant adds the header, the less indented line introducing
+// the block, and the trailer: the line after the block at the header's
+// indentation, if IsTrailer accepts it.
+func IndentBlock(r rope.Rope, line uint32, around bool, opts IndentBlockOptions) (start, end ByteOffset) {
+	if r.Len() == 0 {
+		return 0, 0
+	}
+
+	last := lastLine(r)
+	line = min(line, last)
+
+	tabWidth := opts.TabWidth
+	if tabWidth <= 0 {
+		tabWidth = defaultIndentTabWidth
+	}
+	blank := func(l uint32) bool {
+		return strings.TrimSpace(r.LineText(l)) == ""
+	}
+	width := func(l uint32) int {
+		return indentWidth(r.LineText(l), tabWidth)
+	}
+
+	// Anchor on a non-blank line
+	anchor, ok := nextNonBlank(line, last, blank)
+	if !ok {
+		anchor, ok = prevNonBlank(line, blank)
+	}
+	if !ok {
+		return ByteOffset(r.LineStartOffset(line)), lineEnd(r, line, last)
+	}
+
+	level := width(anchor)
+	first, final := anchor, anchor
+	for l := first; l > 0; {
+		prev, ok := adjacentNonBlank(l, -1, last, blank, opts.BlankLinesBreak)
+		if !ok || width(prev) < level {
+			break
+		}
+		first, l = prev, prev
+	}
+	for l := final; l < last; {
+		next, ok := adjacentNonBlank(l, 1, last, blank, opts.BlankLinesBreak)
+		if !ok || width(next) < level {
+			break
+		}
+		final, l = next, next
+	}
+
+	if around {
+		headerWidth := -1
+		if header, ok := adjacentNonBlank(first, -1, last, blank, opts.BlankLinesBreak); ok {
+			first, headerWidth = header, width(header)
+		}
+		if trailer, ok := adjacentNonBlank(final, 1, last, blank, opts.BlankLinesBreak); ok {
+			w := width(trailer)
+			if (w == headerWidth || headerWidth < 0 && w < level) && isTrailer(opts, r.LineText(trailer)) {
+				final = trailer
+			}
+		}
+	}
+
+	return ByteOffset(r.LineStartOffset(first)), lineEnd(r, final, last)
+}
+
+// indentWidth returns the display width of the leading white space of text.
+func indentWidth(text string, tabWidth int) int {
+	w := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case ' ':
+			w++
+		case '\t':
+			w += tabWidth - w%tabWidth
+		default:
+			return w
+		}
+	}
+	return w
+}
+
+// isTrailer applies opts.IsTrailer to a line without its indentation.
+func isTrailer(opts IndentBlockOptions, text string) bool {
+	text = strings.TrimLeft(text, " \t")
+	if opts.IsTrailer != nil {
+		return opts.IsTrailer(text)
+	}
+	return text != "" && strings.ContainsRune(")]}", rune(text[0]))
+}
+
+// adjacentNonBlank returns the nearest non-blank line before (dir < 0) or
+// after line. With stopAtBlank, a blank line in between means there is none.
+func adjacentNonBlank(line uint32, dir int, last uint32, blank func(uint32) bool, stopAtBlank bool) (uint32, bool) {
+	if dir < 0 {
+		if line == 0 || stopAtBlank && blank(line-1) {
+			return 0, false
+		}
+		return prevNonBlank(line-1, blank)
+	}
+	if line >= last || stopAtBlank && blank(line+1) {
+		return 0, false
+	}
+	return nextNonBlank(line+1, last, blank)
+}
+
+// nextNonBlank returns the first non-blank line at or after line.
+func nextNonBlank(line, last uint32, blank func(uint32) bool) (uint32, bool) {
+	for ; line <= last; line++ {
+		if !blank(line) {
+			return line, true
+		}
+	}
+	return 0, false
+}
+
+// prevNonBlank returns the last non-blank line at or before line.
+func prevNonBlank(line uint32, blank func(uint32) bool) (uint32, bool) {
+	for {
+		if !blank(line) {
+			return line, true
+		}
+		if line == 0 {
+			return 0, false
+		}
+		line--
+	}
+}
+
+// lineEnd returns the end of line including its newline.
+func lineEnd(r rope.Rope, line, last uint32) ByteOffset {
+	if line >= last {
+		return ByteOffset(r.Len())
+	}
+	return ByteOffset(r.LineStartOffset(line + 1))
+}
@@ -0,0 +1,392 @@
+package cursor
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Word boundaries follow Vim's definitions:
+//
+//   - A word is a run of keyword characters, or a run of other non-blank
+//     characters (punctuation). "foo.bar" is three words.
+//   - A WORD is a run of non-blank characters. "foo.bar" is one WORD.
+//   - An empty line is also a word and a WORD.
+//
+// Keyword characters are configured by an iskeyword spec; see ParseKeywords.
+
+// ErrInvalidKeywords is returned by ParseKeywords for a malformed spec.
+var ErrInvalidKeywords = errors.New("invalid iskeyword spec")
+
+// DefaultIsKeyword is Vim's default iskeyword spec: letters, digits,
+// underscore and the Latin-1 letters.
+const DefaultIsKeyword = "@,48-57,_,192-255"
+
+// WordClass is the class of a character for word motions. Two adjacent
+// non-blank characters belong to the same word if they have the same class.
+type WordClass uint8
+
+const (
+	// WordClassBlank is white space, including line breaks.
+	WordClassBlank WordClass = iota
+	// WordClassPunct is a non-blank, non-keyword character.
+	WordClassPunct
+	// WordClassKeyword is a keyword character.
+	WordClassKeyword
+)
+
+// Keywords is a set of keyword characters, parsed from an iskeyword spec.
+// The zero value uses DefaultIsKeyword.
+type Keywords struct {
+	spec  string
+	latin [256]bool
+	set   bool
+}
+
+// DefaultKeywords returns the keywords of DefaultIsKeyword.
+func DefaultKeywords() Keywords {
+	kw, _ := ParseKeywords(DefaultIsKeyword)
+	return kw
+}
+
+// ParseKeywords parses a Vim iskeyword spec: a comma-separated list of
+// parts, each a character ("_"), a character code ("48"), a range of
+// either ("a-z", "48-57"), or "@" for all letters. "@-@" is the character
+// '@' itself. A part starting with '^' removes characters instead. Parts
+// apply in order.
+//
+// The spec only affects characters below 256. Other characters are keyword
+// characters if they are letters or digits.
+func ParseKeywords(spec string) (Keywords, error) {
+	kw := Keywords{spec: spec, set: true}
+	if spec == "" {
+		return kw, nil
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		include := true
+		if len(part) > 1 && part[0] == '^' {
+			include = false
+			part = part[1:]
+		}
+
+		if part == "@" {
+			for c := 0; c < 256; c++ {
+				if unicode.IsLetter(rune(c)) {
+					kw.latin[c] = include
+				}
+			}
+			continue
+		}
+
+		lo, hi, err := parseKeywordRange(part)
+		if err != nil {
+			return Keywords{}, err
+		}
+		for c := lo; c <= hi; c++ {
+			kw.latin[c] = include
+		}
+	}
+	return kw, nil
+}
+
+// parseKeywordRange parses a character or a range of characters of an
+// iskeyword part.
+func parseKeywordRange(part string) (lo, hi int, err error) {
+	// A '-' separates a range unless it is the first character
+	from, to := part, part
+	if i := strings.Index(part[min(1, len(part)):], "-"); i >= 0 {
+		from, to = part[:i+1], part[i+2:]
+	}
+
+	if lo, err = parseKeywordChar(from); err != nil {
+		return 0, 0, err
+	}
+	if hi, err = parseKeywordChar(to); err != nil {
+		return 0, 0, err
+	}
+	if lo > hi {
+		return 0, 0, ErrInvalidKeywords
+	}
+	return lo, hi, nil
+}
+
+// parseKeywordChar parses a character or decimal character code.
+func parseKeywordChar(s string) (int, error) {
+	if s == "" {
+		return 0, ErrInvalidKeywords
+	}
+	if s[0] >= '0' && s[0] <= '9' {
+		n, err := strconv.Atoi(s)
+		if err != nil || n > 255 {
+			return 0, ErrInvalidKeywords
+		}
+		return n, nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size != len(s) || r > 255 {
+		return 0, ErrInvalidKeywords
+	}
+	return int(r), nil
+}
+
+// String returns the iskeyword spec the keywords were parsed from.
+func (k Keywords) String() string {
+	if !k.set {
+		return DefaultIsKeyword
+	}
+	return k.spec
+}
+
+// IsKeyword returns true if r is a keyword character.
+func (k Keywords) IsKeyword(r rune) bool {
+	if !k.set {
+		k = defaultKeywords
+	}
+	if r >= 0 && r < 256 {
+		return k.latin[r]
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Class returns the word class of r. For WORDs (bigWord) all non-blank
+// characters are WordClassPunct.
+func (k Keywords) Class(r rune, bigWord bool) WordClass {
+	switch {
+	case unicode.IsSpace(r):
+		return WordClassBlank
+	case bigWord:
+		return WordClassPunct
+	case k.IsKeyword(r):
+		return WordClassKeyword
+	default:
+		return WordClassPunct
+	}
+}
+
+// defaultKeywords are the keywords used by the zero Keywords.
+var defaultKeywords = DefaultKeywords()
+
+// classAt returns the class of the rune at offset and its size.
+func (k Keywords) classAt(text string, offset ByteOffset, bigWord bool) (WordClass, ByteOffset) {
+	r, size := utf8.DecodeRuneInString(text[offset:])
+	return k.Class(r, bigWord), ByteOffset(size)
+}
+
+// classBefore returns the class of the rune before offset and its size.
+func (k Keywords) classBefore(text string, offset ByteOffset, bigWord bool) (WordClass, ByteOffset) {
+	r, size := utf8.DecodeLastRuneInString(text[:offset])
+	return k.Class(r, bigWord), ByteOffset(size)
+}
+
+// startsEmptyLine returns true if offset is the start of an empty line.
+func startsEmptyLine(text string, offset ByteOffset) bool {
+	return offset < ByteOffset(len(text)) && text[offset] == '\n' &&
+		(offset == 0 || text[offset-1] == '\n')
+}
+
+// clampOffset limits offset to text.
+func clampOffset(text string, offset ByteOffset) ByteOffset {
+	return max(0, min(offset, ByteOffset(len(text))))
+}
+
+// NextWordStart returns the start of the word after the one at offset, as
+// Vim's w (W if bigWord). Returns len(text) if there is none.
+func (k Keywords) NextWordStart(text string, offset ByteOffset, bigWord bool) ByteOffset {
+	end := ByteOffset(len(text))
+	offset = clampOffset(text, offset)
+	if offset >= end {
+		return end
+	}
+
+	// Leave the current word
+	if class, _ := k.classAt(text, offset, bigWord); class != WordClassBlank {
+		for offset < end {
+			next, size := k.classAt(text, offset, bigWord)
+			if next != class {
+				break
+			}
+			offset += size
+		}
+	} else if startsEmptyLine(text, offset) {
+		offset++
+	}
+
+	// Skip blanks, stopping at an empty line
+	for offset < end {
+		class, size := k.classAt(text, offset, bigWord)
+		if class != WordClassBlank || startsEmptyLine(text, offset) {
+			break
+		}
+		offset += size
+	}
+	return offset
+}
+
+// PrevWordStart returns the start of the word before offset, as Vim's b
+// (B if bigWord). Returns 0 if there is none.
+func (k Keywords) PrevWordStart(text string, offset ByteOffset, bigWord bool) ByteOffset {
+	offset = clampOffset(text, offset)
+
+	// Skip blanks backward, stopping at an empty line
+	for offset > 0 {
+		class, size := k.classBefore(text, offset, bigWord)
+		if class != WordClassBlank || startsEmptyLine(text, offset-size) {
+			break
+		}
+		offset -= size
+	}
+	if offset == 0 {
+		return 0
+	}
+
+	class, size := k.classBefore(text, offset, bigWord)
+	if class == WordClassBlank {
+		// An empty line
+		return offset - size
+	}
+	for offset > 0 {
+		prev, size := k.classBefore(text, offset, bigWord)
+		if prev != class {
+			break
+		}
+		offset -= size
+	}
+	return offset
+}
+
+// WordEnd returns the offset of the last character of the word ending
+// after offset, as Vim's e (E if bigWord). Returns offset if there is none.
+func (k Keywords) WordEnd(text string, offset ByteOffset, bigWord bool) ByteOffset {
+	end := ByteOffset(len(text))
+	offset = clampOffset(text, offset)
+	if offset >= end {
+		return offset
+	}
+	start := offset
+
+	// Move off the current character and skip blanks
+	_, size := k.classAt(text, offset, bigWord)
+	offset += size
+	for offset < end {
+		class, size := k.classAt(text, offset, bigWord)
+		if class != WordClassBlank {
+			break
+		}
+		offset += size
+	}
+	if offset >= end {
+		return start
+	}
+
+	class, size := k.classAt(text, offset, bigWord)
+	for offset+size < end {
+		next, nextSize := k.classAt(text, offset+size, bigWord)
+		if next != class {
+			break
+		}
+		offset += size
+		size = nextSize
+	}
+	return offset
+}
+
+// WordBounds returns the word at offset as [start, end). If offset is on
+// blanks, it returns the next word in direction: forward if direction is
+// positive or zero, backward if negative. Returns an empty range at offset
+// if there is no such word. Line breaks end a word but are not part of it.
+func (k Keywords) WordBounds(text string, offset ByteOffset, bigWord bool, direction int) (start, end ByteOffset) {
+	textLen := ByteOffset(len(text))
+	offset = clampOffset(text, offset)
+
+	onBlank := offset >= textLen
+	if !onBlank {
+		class, _ := k.classAt(text, offset, bigWord)
+		onBlank = class == WordClassBlank
+	}
+
+	if onBlank && direction < 0 {
+		// Move onto the last character of the previous word
+		for {
+			if offset == 0 {
+				return 0, 0
+			}
+			class, size := k.classBefore(text, offset, bigWord)
+			offset -= size
+			if class != WordClassBlank {
+				break
+			}
+		}
+	} else if onBlank {
+		for offset < textLen {
+			class, size := k.classAt(text, offset, bigWord)
+			if class != WordClassBlank {
+				break
+			}
+			offset += size
+		}
+		if offset >= textLen {
+			return textLen, textLen
+		}
+	}
+
+	class, _ := k.classAt(text, offset, bigWord)
+	start, end = offset, offset
+	for start > 0 {
+		prev, size := k.classBefore(text, start, bigWord)
+		if prev != class {
+			break
+		}
+		start -= size
+	}
+	for end < textLen {
+		next, size := k.classAt(text, end, bigWord)
+		if next != class {
+			break
+		}
+		end += size
+	}
+	return start, end
+}
+
+// WordsInRange returns the words overlapping [start, end) of text, in
+// order, each clipped to the range.
+func (k Keywords) WordsInRange(text string, start, end ByteOffset, bigWord bool) [][2]ByteOffset {
+	start = clampOffset(text, start)
+	end = clampOffset(text, end)
+
+	var words [][2]ByteOffset
+	for offset := start; offset < end; {
+		class, size := k.classAt(text, offset, bigWord)
+		if class == WordClassBlank {
+			offset += size
+			continue
+		}
+		ws, we := k.WordBounds(text, offset, bigWord, 1)
+		words = append(words, [2]ByteOffset{max(ws, start), min(we, end)})
+		offset = we
+	}
+	return words
+}
+
+// OperatorWordEnd returns the exclusive end of count words from offset
+// when w (W if bigWord) is the motion of an operator, as in dw or yw. As in
+// Vim, if the last word moved over ends its line, the operation stops at
+// the end of that line instead of the start of the next word.
+func (k Keywords) OperatorWordEnd(text string, offset ByteOffset, count int, bigWord bool) ByteOffset {
+	offset = clampOffset(text, offset)
+	end := offset
+	last := offset
+	for i := 0; i < max(count, 1) && end < ByteOffset(len(text)); i++ {
+		last = end
+		end = k.NextWordStart(text, end, bigWord)
+	}
+
+	// Stop at the end of the line of the last word moved over
+	if nl := strings.IndexByte(text[last:end], '\n'); nl >= 0 && last+ByteOffset(nl) > offset {
+		return last + ByteOffset(nl)
+	}
+	return end
+}
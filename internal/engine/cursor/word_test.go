@@ -0,0 +1,125 @@
+package cursor
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// wordTexts returns the text of each word of WordsInRange over all of text.
+func wordTexts(k Keywords, text string, bigWord bool) []string {
+	var words []string
+	for _, w := range k.WordsInRange(text, 0, ByteOffset(len(text)), bigWord) {
+		words = append(words, text[w[0]:w[1]])
+	}
+	return words
+}
+
+func TestWordClassification(t *testing.T) {
+	text := "foo.bar(baz_1) -> qux\t\"é!\""
+	k := DefaultKeywords()
+
+	wantWords := []string{"foo", ".", "bar", "(", "baz_1", ")", "->", "qux", "\"", "é", "!\""}
+	if got := wordTexts(k, text, false); !reflect.DeepEqual(got, wantWords) {
+		t.Errorf("words = %q, want %q", got, wantWords)
+	}
+
+	wantWORDs := []string{"foo.bar(baz_1)", "->", "qux", "\"é!\""}
+	if got := wordTexts(k, text, true); !reflect.DeepEqual(got, wantWORDs) {
+		t.Errorf("WORDs = %q, want %q", got, wantWORDs)
+	}
+
+	// The zero value behaves as the defaults
+	var zero Keywords
+	if got := wordTexts(zero, text, false); !reflect.DeepEqual(got, wantWords) {
+		t.Errorf("zero Keywords words = %q, want %q", got, wantWords)
+	}
+}
+
+func TestParseKeywords(t *testing.T) {
+	k, err := ParseKeywords("@,48-57,_,-")
+	if err != nil {
+		t.Fatalf("ParseKeywords() error = %v", err)
+	}
+	if got := wordTexts(k, "foo-bar.baz", false); !reflect.DeepEqual(got, []string{"foo-bar", ".", "baz"}) {
+		t.Errorf("words with '-' keyword = %q", got)
+	}
+	if k.String() != "@,48-57,_,-" {
+		t.Errorf("String() = %q, want the spec", k.String())
+	}
+
+	k, err = ParseKeywords("a-z,^x,@-@")
+	if err != nil {
+		t.Fatalf("ParseKeywords() error = %v", err)
+	}
+	for _, tt := range []struct {
+		r    rune
+		want bool
+	}{{'a', true}, {'x', false}, {'@', true}, {'A', false}, {'1', false}, {'λ', true}} {
+		if got := k.IsKeyword(tt.r); got != tt.want {
+			t.Errorf("IsKeyword(%q) = %v, want %v", tt.r, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"z-a", "300", "a-", "ab"} {
+		if _, err := ParseKeywords(spec); !errors.Is(err, ErrInvalidKeywords) {
+			t.Errorf("ParseKeywords(%q) error = %v, want ErrInvalidKeywords", spec, err)
+		}
+	}
+}
+
+func TestWordMotions(t *testing.T) {
+	// Offsets: "foo.bar" 0-6, "baz" 8-10, empty line 12, "qux" 13-15
+	text := "foo.bar baz\n\nqux"
+	k := DefaultKeywords()
+
+	tests := []struct {
+		name    string
+		offset  ByteOffset
+		bigWord bool
+		next    ByteOffset
+		prev    ByteOffset
+		end     ByteOffset
+	}{
+		{"word start", 0, false, 3, 0, 2},
+		{"punctuation", 3, false, 4, 0, 6},
+		{"WORD", 0, true, 8, 0, 6},
+		{"stops at empty line", 8, false, 12, 4, 10},
+		{"from empty line", 12, false, 13, 8, 15},
+		{"last word", 14, true, 16, 13, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := k.NextWordStart(text, tt.offset, tt.bigWord); got != tt.next {
+				t.Errorf("NextWordStart(%d) = %d, want %d", tt.offset, got, tt.next)
+			}
+			if got := k.PrevWordStart(text, tt.offset, tt.bigWord); got != tt.prev {
+				t.Errorf("PrevWordStart(%d) = %d, want %d", tt.offset, got, tt.prev)
+			}
+			if got := k.WordEnd(text, tt.offset, tt.bigWord); got != tt.end {
+				t.Errorf("WordEnd(%d) = %d, want %d", tt.offset, got, tt.end)
+			}
+		})
+	}
+}
+
+func TestOperatorWordEnd(t *testing.T) {
+	text := "one two\n  three"
+	k := DefaultKeywords()
+
+	tests := []struct {
+		offset ByteOffset
+		count  int
+		want   ByteOffset
+	}{
+		{0, 1, 4},  // dw deletes "one "
+		{4, 1, 7},  // the last word of a line stops at the line break
+		{0, 2, 7},  // so does a count ending on it
+		{0, 3, 15}, // crossing the line break reaches "three"'s end
+	}
+	for _, tt := range tests {
+		if got := k.OperatorWordEnd(text, tt.offset, tt.count, false); got != tt.want {
+			t.Errorf("OperatorWordEnd(%d, %d) = %d, want %d", tt.offset, tt.count, got, tt.want)
+		}
+	}
+}
@@ -68,6 +68,15 @@
 //
 //	// Result: "Xfoo bar Xfoo"
 //
+// Register contents are pasted at every cursor with vim placement:
+// PasteLinewise puts whole lines above or below each cursor's line, and
+// PasteBlockwise puts a block at the same column on consecutive rows,
+// padding short rows and extending the buffer as needed:
+//
+//	cursors := e.Cursors()
+//	e.PasteBlockwise(cursors, []string{"a", "b"}, true)
+//	e.SetCursors(cursors)
+//
 // # Undo/Redo
 //
 // The engine maintains full undo/redo history:
@@ -89,6 +98,35 @@
 //
 //	e.Undo() // Undoes both operations at once
 //
+// # Edit Batches
+//
+// WithBatch collects many edits, such as those of a plugin, and applies
+// them as one undo step and one OnChange event. Offsets refer to the text
+// before the batch; if the callback fails nothing is applied:
+//
+//	err := e.WithBatch("comment lines", func(b *engine.EditBatch) error {
+//	    for line := uint32(0); line < e.LineCount(); line++ {
+//	        b.Insert(e.LineStartOffset(line), "// ")
+//	    }
+//	    return nil
+//	})
+//
+// # Save Hooks
+//
+// Save hooks transform the content before it is written to disk. Built-in
+// hooks trim trailing whitespace, ensure a final newline and expand
+// indentation tabs; each is enabled globally or per language:
+//
+//	e.AddSaveHook(engine.TrimTrailingWhitespace(engine.SaveHookConfig{
+//		Enabled:   true,
+//		Languages: map[string]bool{"markdown": false},
+//	}))
+//
+//	changed, err := e.RunSaveHooks("go")
+//
+// All changes made by the hooks form one undo entry, so a single Undo
+// reverts the automatic formatting.
+//
 // # Change Tracking for AI Context
 //
 // The engine tracks changes for AI context generation:
@@ -162,6 +200,12 @@
 //	utf16Point := e.OffsetToPointUTF16(offset)
 //	offset = e.PointUTF16ToOffset(utf16Point)
 //
+// GotoLineColumn resolves a possibly out-of-range line and column, such as
+// ":42" or an LSP location, either clamping it into the buffer or
+// reporting ErrLineOutOfRange or ErrColumnOutOfRange:
+//
+//	offset, err := e.GotoLineColumn(42, 1, engine.ClampAll|engine.OneIndexed)
+//
 // # Snapshots
 //
 // Snapshots provide efficient read-only views of buffer state:
@@ -177,6 +221,138 @@
 //	// ... edits ...
 //	snapText, _ := e.GetSnapshotText(id)
 //
+// # Brackets
+//
+// MatchBracket finds the partner of the bracket at or after an offset.
+// A language-aware BracketSkipper can exclude strings and comments.
+// With auto-pair enabled, TypeText inserts closing brackets automatically
+// and types over them:
+//
+//	e := engine.New(engine.WithAutoPair(true))
+//	pos, _ := e.TypeText(0, "(")   // "()" with pos between
+//	pos, _ = e.TypeText(pos, ")")  // still "()", pos after ")"
+//
+// # Surround
+//
+// Surround, ChangeSurround and DeleteSurround add, change and delete the
+// pair around text as one undo unit, like vim-surround's ys, cs and ds.
+// Pairs may be quotes, brackets, longer delimiters or HTML tags; AnyTag
+// matches the innermost tag pair:
+//
+//	e.ChangeSurround(offset, [2]string{`"`, `"`}, [2]string{"'", "'"})
+//	e.DeleteSurround(offset, engine.AnyTag)
+//
+// # Indentation
+//
+// ComputeIndent returns the indent for a line from the line above it and a
+// language's IndentRules. With WithAutoIndent, the editor indents each new
+// line with AutoIndent, which uses the rules set by SetIndentRules:
+//
+//	e.SetIndentRules(engine.IndentRulesFor("python"))
+//	indent := e.AutoIndent(line)
+//
+// IndentRulesFor returns the rules of a language ID: built-in rules for
+// languages such as Python, Lua and shell scripts, rules added with
+// RegisterIndentRules, or DefaultIndentRules for brace languages.
+//
+// Indents are made of tabs with WithUseTabs and of TabWidth spaces
+// otherwise.
+//
+// IndentBlock finds the indentation block around a line for the "ii" and
+// "ai" text objects: the lines indented at least as far, plus the header
+// line and a closing line such as "}" for "ai". Blank lines belong to the
+// block unless WithIndentBlockBlankLines(true) is set.
+//
+// # Folding
+//
+// AddFold collapses a range of lines, keeping the first line visible.
+// Folds follow edits: they shift when lines are inserted or deleted above
+// them, grow or shrink with edits inside them, and disappear when their
+// lines are deleted. VisibleLines returns what remains for display:
+//
+//	id := e.AddFold(2, 5)      // lines 3-5 hidden
+//	ranges := e.VisibleLines() // [{0 2} {6 ...}]
+//	e.RemoveFold(id)
+//
+// # Marks
+//
+// Marks are named positions that follow edits, for anchoring diagnostics
+// or plugin state. A mark's gravity decides whether text inserted exactly
+// at the mark goes after it (GravityLeft) or before it (GravityRight). A
+// mark whose surrounding text is deleted clamps to the start of the
+// deletion and is flagged as invalidated:
+//
+//	id := e.CreateMark(10)
+//	e.Insert(0, "abc")
+//	offset, _ := e.MarkOffset(id) // 13
+//	e.RemoveMark(id)
+//
+// # Tokens
+//
+// SetTokenizer plugs in a line-based Tokenizer for syntax highlighting.
+// The engine caches the styled spans of every line and, after each edit,
+// re-tokenizes only the edited lines plus any following lines whose
+// starting state changed, such as the rest of a string that was opened:
+//
+//	e.SetTokenizer(goTokenizer)
+//	spans := e.Tokens(top, top+height-1)
+//
+// # Long Lines
+//
+// Lines longer than the maximum line scan length (WithMaxLineScanLength,
+// 10000 bytes by default) are not tokenized or scanned for brackets.
+// Position conversion stays O(log n) however long a line is. Renderers can
+// check for long lines and switch to a degraded mode:
+//
+//	if e.HasLongLines() && e.LongLineThresholdExceeded(line) {
+//	    // draw the line without highlighting
+//	}
+//
+// # Read Transactions
+//
+// Separate read calls may observe different revisions if another goroutine
+// edits in between. Read holds the read lock for a whole callback so that
+// all reads through its ReadView are consistent:
+//
+//	err := e.Read(func(v *engine.ReadView) error {
+//		text := v.Text()
+//		pos := v.OffsetToPoint(offset) // same revision as text
+//		return nil
+//	})
+//
+// The callback must not call Engine write methods; they would deadlock.
+//
+// # Search
+//
+// FindAll, FindNext and FindPrev search the buffer literally or with a
+// regular expression. SmartCase makes a lowercase pattern case-insensitive
+// and a pattern with uppercase letters case-sensitive; WholeWord skips
+// matches that are part of a larger word. Matches are found line by line
+// in the rope, so they never span a newline:
+//
+//	opts := engine.SearchOptions{SmartCase: true, WholeWord: true}
+//	match, found, err := e.FindNext("count", cursorOffset+1, opts)
+//
+// SelectAllMatches turns every match into a selection for multi-cursor
+// editing; AddCursorAtNextMatch adds them one at a time, like Ctrl-D in
+// VS Code, starting from the word under the cursor:
+//
+//	cs := e.Cursors()
+//	opts := engine.SearchOptions{CaseSensitive: true}
+//	_ = e.AddCursorAtNextMatch(cs, "", opts) // select the word under the cursor
+//	_ = e.AddCursorAtNextMatch(cs, "", opts) // and its next whole-word occurrence
+//	e.SetCursors(cs)
+//
+// # Words
+//
+// WordBoundaries and WordsInRange find Vim words and WORDs for motions,
+// operators and text objects. A word is a run of keyword characters or a
+// run of other non-blank characters; a WORD is any run of non-blank
+// characters. Keyword characters follow an iskeyword spec:
+//
+//	e.SetIsKeyword("@,48-57,_,-") // "foo-bar" is one word
+//	start, end := e.WordBoundaries(offset, false, 1)
+//
 // # Error Handling
 //
 // The package defines several error types:
@@ -188,4 +364,5 @@
 //   - ErrNothingToRedo: Redo stack is empty
 //   - ErrSnapshotNotFound: Requested snapshot does not exist
 //   - ErrReadOnly: Write operation on read-only engine
+//   - ErrEmptyPattern: Search with an empty pattern
 package engine
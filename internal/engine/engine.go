@@ -3,6 +3,7 @@ package engine
 import (
 	"io"
 	"sync"
+	"time"
 
 	"github.com/dshills/keystorm/internal/engine/buffer"
 	"github.com/dshills/keystorm/internal/engine/cursor"
@@ -114,6 +115,11 @@ func (c *appliedEditCommand) Description() string {
 	return "Replace"
 }
 
+// Size returns the number of bytes of text retained for undo/redo.
+func (c *appliedEditCommand) Size() int64 {
+	return int64(len(c.oldText) + len(c.newText))
+}
+
 // Engine is the main facade for the text editor engine.
 // It combines buffer management, cursor handling, undo/redo,
 // and change tracking into a unified, thread-safe API.
@@ -132,10 +138,53 @@ type Engine struct {
 	tabWidth       int
 	lineEnding     buffer.LineEnding
 	maxUndoEntries int
+	maxUndoBytes   int64
 	maxChanges     int
 	maxRevisions   int
 	readOnly       bool
 
+	// Long line handling
+	maxLineScanLength int
+
+	// Indentation
+	autoIndent  bool
+	useTabs     bool
+	indentRules IndentRules
+
+	// blankLinesBreakIndentBlock ends indentation blocks at blank lines
+	blankLinesBreakIndentBlock bool
+
+	// keywords are the keyword characters for word boundaries
+	keywords cursor.Keywords
+
+	// Bracket handling
+	autoPair       bool
+	bracketSkipper BracketSkipper
+	autoClosers    []ByteOffset
+
+	// Folding
+	folds      []Fold
+	nextFoldID FoldID
+
+	// Marks
+	marks      map[MarkID]*Mark
+	nextMarkID MarkID
+
+	// Syntax tokens, and the lines still to tokenize while the edits of
+	// a batch are notified
+	tokenizer  Tokenizer
+	tokenLines []tokenLine
+	staleLines []staleTokens
+
+	// Save hooks
+	saveHooks []SaveHook
+
+	// Change events: listeners, and the changes held back while the edits
+	// of one operation are coalesced into a single event
+	changeListeners changeListeners
+	coalesceChanges bool
+	pendingChanges  []buffer.BufferChange
+
 	// Initialization
 	initContent string
 }
@@ -148,6 +197,9 @@ func New(opts ...Option) *Engine {
 		maxUndoEntries: DefaultMaxUndoEntries,
 		maxChanges:     DefaultMaxChanges,
 		maxRevisions:   DefaultMaxRevisions,
+
+		maxLineScanLength: DefaultMaxLineScanLength,
+		indentRules:       DefaultIndentRules(),
 	}
 
 	// Apply options to get configuration
@@ -166,11 +218,18 @@ func New(opts ...Option) *Engine {
 		e.buf = buffer.NewBuffer(bufOpts...)
 	}
 
+	e.buf.OnChange(e.trackAutoClosers)
+	e.buf.OnChange(e.transformFolds)
+	e.buf.OnChange(e.transformMarks)
+	e.buf.OnChange(e.retokenize)
+	e.buf.OnChange(e.collectChange)
+
 	// Create cursor set at start of buffer
 	e.cursors = cursor.NewCursorSetAt(0)
 
 	// Create history manager
 	e.history = history.NewHistory(e.maxUndoEntries)
+	e.history.SetMaxBytes(e.maxUndoBytes)
 
 	// Create change tracker
 	e.tracker = tracking.NewTracker(
@@ -189,6 +248,9 @@ func NewFromReader(r io.Reader, opts ...Option) (*Engine, error) {
 		maxUndoEntries: DefaultMaxUndoEntries,
 		maxChanges:     DefaultMaxChanges,
 		maxRevisions:   DefaultMaxRevisions,
+
+		maxLineScanLength: DefaultMaxLineScanLength,
+		indentRules:       DefaultIndentRules(),
 	}
 
 	// Apply options
@@ -207,11 +269,18 @@ func NewFromReader(r io.Reader, opts ...Option) (*Engine, error) {
 		return nil, err
 	}
 
+	e.buf.OnChange(e.trackAutoClosers)
+	e.buf.OnChange(e.transformFolds)
+	e.buf.OnChange(e.transformMarks)
+	e.buf.OnChange(e.retokenize)
+	e.buf.OnChange(e.collectChange)
+
 	// Create cursor set at start
 	e.cursors = cursor.NewCursorSetAt(0)
 
 	// Create history manager
 	e.history = history.NewHistory(e.maxUndoEntries)
+	e.history.SetMaxBytes(e.maxUndoBytes)
 
 	// Create change tracker
 	e.tracker = tracking.NewTracker(
@@ -329,7 +398,7 @@ func (e *Engine) LineStartOffset(line uint32) ByteOffset {
 	return e.buf.LineStartOffset(line)
 }
 
-// LineEndOffset returns the byte offset of the end of a line (before newline).
+// LineEndOffset returns the byte offset of the end of a line, before its terminator.
 func (e *Engine) LineEndOffset(line uint32) ByteOffset {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -490,6 +559,11 @@ func (e *Engine) ApplyEdit(edit Edit) (EditResult, error) {
 		return EditResult{}, ErrReadOnly
 	}
 
+	return e.applyEditLocked(edit)
+}
+
+// applyEditLocked applies a single edit without acquiring the lock.
+func (e *Engine) applyEditLocked(edit Edit) (EditResult, error) {
 	// Capture state before change
 	beforeRope := e.buf.Snapshot().Rope()
 	oldText := e.buf.TextRange(edit.Range.Start, edit.Range.End)
@@ -536,6 +610,57 @@ func (e *Engine) ApplyEdits(edits []Edit) error {
 		return ErrReadOnly
 	}
 
+	return e.applyEditsLocked(edits)
+}
+
+// applyGroupedEditsLocked applies edits, highest offset first, one at a
+// time as a single undo step named name and one change event, without
+// acquiring the lock. Each edit records its own undo command, so undo
+// restores every edit at the offset it was applied at. The edits are
+// checked before any is applied, so invalid edits leave the buffer
+// untouched.
+func (e *Engine) applyGroupedEditsLocked(name string, edits []Edit) error {
+	if err := e.validateEditsLocked(edits); err != nil {
+		return err
+	}
+
+	if !e.history.IsGrouping() {
+		e.history.BeginGroup(name)
+		defer e.history.EndGroup()
+	}
+
+	e.coalesceChanges = true
+	var err error
+	for _, edit := range edits {
+		if _, err = e.applyEditLocked(edit); err != nil {
+			break
+		}
+	}
+	applied := e.pendingChanges
+	e.coalesceChanges, e.pendingChanges = false, nil
+
+	e.emitChange(applied)
+	return err
+}
+
+// validateEditsLocked checks that edits are in range and in reverse order
+// without overlapping, as applying them requires.
+func (e *Engine) validateEditsLocked(edits []Edit) error {
+	length := e.buf.Len()
+	for i, edit := range edits {
+		if edit.Range.Start < 0 || edit.Range.Start > edit.Range.End || edit.Range.End > length {
+			return ErrRangeInvalid
+		}
+		if i > 0 && edit.Range.End > edits[i-1].Range.Start {
+			return ErrEditsOverlap
+		}
+	}
+	return nil
+}
+
+// applyEditsLocked applies edits as one undo step and one change event,
+// without acquiring the lock.
+func (e *Engine) applyEditsLocked(edits []Edit) error {
 	// Capture state before change
 	beforeRope := e.buf.Snapshot().Rope()
 	cursorsBefore := e.cursors.All()
@@ -553,8 +678,12 @@ func (e *Engine) ApplyEdits(edits []Edit) error {
 		}
 	}
 
-	// Apply all edits
-	if err := e.buf.ApplyEdits(edits); err != nil {
+	// Apply all edits, holding back their change events
+	e.coalesceChanges = true
+	err := e.buf.ApplyEdits(edits)
+	applied := e.pendingChanges
+	e.coalesceChanges, e.pendingChanges = false, nil
+	if err != nil {
 		return err
 	}
 
@@ -603,6 +732,7 @@ func (e *Engine) ApplyEdits(edits []Edit) error {
 	compound := history.NewCompoundCommand("multi-edit", cmds...)
 	e.history.Push(compound)
 
+	e.emitChange(applied)
 	return nil
 }
 
@@ -654,6 +784,12 @@ func (e *Engine) RedoCount() int {
 	return e.history.RedoCount()
 }
 
+// UndoMemoryUsage returns the number of bytes of text retained by undo
+// history.
+func (e *Engine) UndoMemoryUsage() int64 {
+	return e.history.MemoryUsage()
+}
+
 // BeginUndoGroup starts a new undo group.
 // All operations until EndUndoGroup will be undone as a single unit.
 func (e *Engine) BeginUndoGroup(name string) {
@@ -670,11 +806,42 @@ func (e *Engine) CancelUndoGroup() {
 	e.history.CancelGroup()
 }
 
+// IsUndoGrouping returns true if an undo group is open.
+func (e *Engine) IsUndoGrouping() bool {
+	return e.history.IsGrouping()
+}
+
 // ClearHistory removes all undo/redo history.
 func (e *Engine) ClearHistory() {
 	e.history.Clear()
 }
 
+// Checkpoint names the current undo state so that RestoreCheckpoint can
+// return to it. Unlike a snapshot, returning goes through undo and redo,
+// so it can itself be undone.
+func (e *Engine) Checkpoint(name string) history.CheckpointID {
+	return e.history.Checkpoint(name)
+}
+
+// RestoreCheckpoint undoes or redoes to the state of a checkpoint.
+// Returns history.ErrCheckpointNotFound if the checkpoint's state is no
+// longer in the undo history.
+func (e *Engine) RestoreCheckpoint(id history.CheckpointID) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.readOnly {
+		return ErrReadOnly
+	}
+
+	return e.history.RestoreCheckpoint(id, e.buf, e.cursors)
+}
+
+// Checkpoints returns the checkpoints that can be restored, oldest first.
+func (e *Engine) Checkpoints() []history.CheckpointInfo {
+	return e.history.Checkpoints()
+}
+
 // ============================================================================
 // Command Execution
 // ============================================================================
@@ -840,6 +1007,28 @@ func (e *Engine) SnapshotCount() int {
 	return e.tracker.SnapshotCount()
 }
 
+// SetSnapshotLimit sets the snapshot eviction policy.
+// See tracking.Tracker.SetSnapshotLimit.
+func (e *Engine) SetSnapshotLimit(maxCount int, ttl time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tracker.SetSnapshotLimit(maxCount, ttl)
+}
+
+// PinSnapshot protects a snapshot from eviction.
+func (e *Engine) PinSnapshot(id SnapshotID) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.tracker.PinSnapshot(id)
+}
+
+// UnpinSnapshot makes a pinned snapshot subject to eviction again.
+func (e *Engine) UnpinSnapshot(id SnapshotID) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tracker.UnpinSnapshot(id)
+}
+
 // ============================================================================
 // Change Tracking Operations
 // ============================================================================
@@ -340,6 +340,35 @@ func TestUndoGroup(t *testing.T) {
 	}
 }
 
+func TestRestoreCheckpoint(t *testing.T) {
+	e := New()
+
+	e.Insert(0, "Hello")
+	id := e.Checkpoint("before refactor")
+	e.Insert(5, " World")
+	e.Insert(0, ">> ")
+
+	if err := e.RestoreCheckpoint(id); err != nil {
+		t.Fatalf("RestoreCheckpoint failed: %v", err)
+	}
+	if e.Text() != "Hello" {
+		t.Errorf("expected %q after restore, got %q", "Hello", e.Text())
+	}
+
+	// The edits after the checkpoint can be redone
+	if err := e.Redo(); err != nil {
+		t.Fatalf("redo failed: %v", err)
+	}
+	if e.Text() != "Hello World" {
+		t.Errorf("expected %q after redo, got %q", "Hello World", e.Text())
+	}
+
+	checkpoints := e.Checkpoints()
+	if len(checkpoints) != 1 || checkpoints[0].Name != "before refactor" {
+		t.Errorf("Checkpoints() = %+v", checkpoints)
+	}
+}
+
 func TestClearHistory(t *testing.T) {
 	e := New()
 
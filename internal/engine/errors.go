@@ -7,6 +7,12 @@ var (
 	// ErrOffsetOutOfRange indicates an offset is outside the valid buffer range.
 	ErrOffsetOutOfRange = errors.New("offset out of range")
 
+	// ErrLineOutOfRange indicates a line number is outside the buffer.
+	ErrLineOutOfRange = errors.New("line out of range")
+
+	// ErrColumnOutOfRange indicates a column is past the end of its line.
+	ErrColumnOutOfRange = errors.New("column out of range")
+
 	// ErrRangeInvalid indicates an invalid range (e.g., end < start).
 	ErrRangeInvalid = errors.New("invalid range")
 
@@ -27,4 +33,17 @@ var (
 
 	// ErrReadOnly indicates an operation was attempted on a read-only engine.
 	ErrReadOnly = errors.New("engine is read-only")
+
+	// ErrEmptyPattern indicates a search was attempted with an empty pattern.
+	ErrEmptyPattern = errors.New("empty search pattern")
+
+	// ErrNoMatch indicates a search found no (further) match.
+	ErrNoMatch = errors.New("no match")
+
+	// ErrNoSurroundingPair indicates no pair encloses an offset.
+	ErrNoSurroundingPair = errors.New("no surrounding pair")
+
+	// ErrBatchStale indicates the buffer changed while an edit batch was
+	// being collected, so its offsets no longer apply.
+	ErrBatchStale = errors.New("buffer changed during edit batch")
 )
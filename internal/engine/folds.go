@@ -0,0 +1,276 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/dshills/keystorm/internal/engine/buffer"
+)
+
+// FoldID identifies a fold region. The zero value is never a valid fold.
+type FoldID uint64
+
+// Fold is a collapsed region of lines. The first line stays visible as the
+// fold's header; the lines after it, up to and including EndLine, are hidden.
+type Fold struct {
+	ID        FoldID
+	StartLine uint32
+	EndLine   uint32
+}
+
+// LineRange is an inclusive range of lines.
+type LineRange struct {
+	Start uint32
+	End   uint32
+}
+
+// Len returns the number of lines in the range.
+func (r LineRange) Len() int {
+	return int(r.End-r.Start) + 1
+}
+
+// AddFold collapses the lines from startLine to endLine (0-indexed,
+// inclusive). endLine is clamped to the last line. Folds may be nested.
+// Returns 0 if the range covers fewer than two lines, since there would be
+// nothing to hide. Adding a fold identical to an existing one returns the
+// existing fold's ID.
+func (e *Engine) AddFold(startLine, endLine int) FoldID {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if startLine < 0 {
+		return 0
+	}
+	if last := int(e.buf.LineCount()) - 1; endLine > last {
+		endLine = last
+	}
+	if endLine <= startLine {
+		return 0
+	}
+
+	start, end := uint32(startLine), uint32(endLine)
+	for _, f := range e.folds {
+		if f.StartLine == start && f.EndLine == end {
+			return f.ID
+		}
+	}
+
+	e.nextFoldID++
+	e.folds = append(e.folds, Fold{ID: e.nextFoldID, StartLine: start, EndLine: end})
+	sortFolds(e.folds)
+	return e.nextFoldID
+}
+
+// RemoveFold expands and forgets a fold. Removing an unknown or
+// invalidated fold is a no-op.
+func (e *Engine) RemoveFold(id FoldID) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i, f := range e.folds {
+		if f.ID == id {
+			e.folds = append(e.folds[:i], e.folds[i+1:]...)
+			return
+		}
+	}
+}
+
+// ClearFolds removes all folds.
+func (e *Engine) ClearFolds() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.folds = nil
+}
+
+// Folds returns the current folds ordered by start line, outer folds
+// before the folds nested in them.
+func (e *Engine) Folds() []Fold {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if len(e.folds) == 0 {
+		return nil
+	}
+	result := make([]Fold, len(e.folds))
+	copy(result, e.folds)
+	return result
+}
+
+// VisibleLines returns the ranges of lines that remain visible after
+// applying all folds, in order. A buffer without folds yields a single
+// range covering every line.
+func (e *Engine) VisibleLines() []LineRange {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	lineCount := e.buf.LineCount()
+	if lineCount == 0 {
+		return nil
+	}
+
+	var result []LineRange
+	next := uint32(0) // first line not yet emitted or hidden
+	for _, f := range e.folds {
+		// Folds are sorted by start line, so a fold nested in (or hidden
+		// by) an earlier fold starts before next and only extends it.
+		if f.StartLine >= next {
+			result = append(result, LineRange{Start: next, End: f.StartLine})
+		}
+		if f.EndLine+1 > next {
+			next = f.EndLine + 1
+		}
+	}
+	if next < lineCount {
+		result = append(result, LineRange{Start: next, End: lineCount - 1})
+	}
+	return result
+}
+
+// IsLineVisible returns true if line is not hidden by any fold.
+func (e *Engine) IsLineVisible(line uint32) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, f := range e.folds {
+		if f.StartLine >= line {
+			break
+		}
+		if line <= f.EndLine {
+			return false
+		}
+	}
+	return true
+}
+
+// transformFolds keeps fold regions in sync with edits. Folds after the
+// edit shift by the change in line count, folds containing it grow or
+// shrink, and folds whose lines are deleted entirely or that collapse to a
+// single line are dropped. Folds that end up covering the same lines are
+// merged. It is registered as a buffer change listener and therefore runs
+// with the engine write lock held.
+func (e *Engine) transformFolds(change buffer.BufferChange) {
+	if len(e.folds) == 0 {
+		return
+	}
+
+	span := changedLines(change)
+	kept := e.folds[:0]
+	for _, f := range e.folds {
+		if f, ok := span.transform(f); ok {
+			kept = append(kept, f)
+		}
+	}
+	sortFolds(kept)
+	e.folds = dedupeFolds(kept)
+}
+
+// lineSpan describes an edit in terms of lines: the lines [start, end) of
+// the old content are replaced by count lines in the new content.
+type lineSpan struct {
+	start uint32
+	end   uint32
+	count uint32
+
+	// whole is true if the edit replaces whole lines, i.e. it starts and
+	// ends at line starts and the inserted text ends with a line break.
+	// Otherwise the first and last replaced lines are partially kept and
+	// joined with the inserted text.
+	whole bool
+}
+
+// changedLines computes the line span of a buffer change.
+func changedLines(change buffer.BufferChange) lineSpan {
+	whole := change.Start.Column == 0 && change.OldEnd.Column == 0 && change.NewEnd.Column == 0
+	if whole {
+		return lineSpan{
+			start: change.StartLine,
+			end:   change.OldEndLine,
+			count: change.NewEndLine - change.StartLine,
+			whole: true,
+		}
+	}
+	return lineSpan{
+		start: change.StartLine,
+		end:   change.OldEndLine + 1,
+		count: change.NewEndLine - change.StartLine + 1,
+	}
+}
+
+// delta returns the change in line count.
+func (s lineSpan) delta() int64 {
+	return int64(s.count) - int64(s.end-s.start)
+}
+
+// joined returns 1 if the first and last replaced lines survive joined to
+// the inserted text, or 0 for whole-line edits.
+func (s lineSpan) joined() uint32 {
+	if s.whole {
+		return 0
+	}
+	return 1
+}
+
+// transform maps a fold through the edit. Returns false if the fold no
+// longer hides any lines.
+func (s lineSpan) transform(f Fold) (Fold, bool) {
+	shift := func(line uint32) uint32 { return uint32(int64(line) + s.delta()) }
+
+	switch {
+	case f.EndLine < s.start:
+		// Entirely before the edit
+		return f, true
+
+	case f.StartLine >= s.end:
+		// Entirely after the edit
+		f.StartLine = shift(f.StartLine)
+		f.EndLine = shift(f.EndLine)
+		return f, true
+
+	case s.start <= f.StartLine && f.EndLine < s.end:
+		// The edit replaces every line of the fold
+		return f, false
+
+	case s.start <= f.StartLine:
+		// The edit replaces the header: the fold now starts at the line
+		// holding what remains of it
+		f.StartLine = s.start + s.count - s.joined()
+		f.EndLine = shift(f.EndLine)
+
+	case s.end <= f.EndLine+1:
+		// The edit is inside the fold body
+		f.EndLine = shift(f.EndLine)
+
+	default:
+		// The edit starts inside the fold and extends past its end: the
+		// fold is cut where the edit starts
+		f.EndLine = s.start - 1 + s.joined()
+	}
+
+	return f, f.EndLine > f.StartLine
+}
+
+// sortFolds orders folds by start line, outer folds first.
+func sortFolds(folds []Fold) {
+	sort.SliceStable(folds, func(i, j int) bool {
+		if folds[i].StartLine != folds[j].StartLine {
+			return folds[i].StartLine < folds[j].StartLine
+		}
+		return folds[i].EndLine > folds[j].EndLine
+	})
+}
+
+// dedupeFolds drops folds covering the same lines as the preceding fold.
+// folds must be sorted.
+func dedupeFolds(folds []Fold) []Fold {
+	if len(folds) < 2 {
+		return folds
+	}
+	kept := folds[:1]
+	for _, f := range folds[1:] {
+		last := kept[len(kept)-1]
+		if f.StartLine == last.StartLine && f.EndLine == last.EndLine {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
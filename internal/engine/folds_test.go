@@ -0,0 +1,254 @@
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// numberedLines returns n one-letter lines, each terminated by a newline.
+func numberedLines(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteString(string(rune('a' + i)))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func foldRange(t *testing.T, e *Engine, id FoldID) (uint32, uint32, bool) {
+	t.Helper()
+	for _, f := range e.Folds() {
+		if f.ID == id {
+			return f.StartLine, f.EndLine, true
+		}
+	}
+	return 0, 0, false
+}
+
+func TestAddFold(t *testing.T) {
+	e := New(WithContent(numberedLines(10)))
+
+	if id := e.AddFold(3, 3); id != 0 {
+		t.Errorf("AddFold(3, 3) = %d, want 0", id)
+	}
+	if id := e.AddFold(-1, 3); id != 0 {
+		t.Errorf("AddFold(-1, 3) = %d, want 0", id)
+	}
+
+	id := e.AddFold(2, 5)
+	if id == 0 {
+		t.Fatal("AddFold(2, 5) = 0, want valid id")
+	}
+	if again := e.AddFold(2, 5); again != id {
+		t.Errorf("AddFold of existing range = %d, want %d", again, id)
+	}
+
+	clamped := e.AddFold(8, 100)
+	if start, end, ok := foldRange(t, e, clamped); !ok || start != 8 || end != 10 {
+		t.Errorf("clamped fold = %d-%d (%v), want 8-10", start, end, ok)
+	}
+
+	e.RemoveFold(id)
+	if _, _, ok := foldRange(t, e, id); ok {
+		t.Error("fold still present after RemoveFold")
+	}
+	e.RemoveFold(id) // no-op
+}
+
+func TestVisibleLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		folds [][2]int
+		want  []LineRange
+	}{
+		{"no folds", nil, []LineRange{{0, 10}}},
+		{"single", [][2]int{{2, 5}}, []LineRange{{0, 2}, {6, 10}}},
+		{"at start", [][2]int{{0, 3}}, []LineRange{{0, 0}, {4, 10}}},
+		{"to end", [][2]int{{7, 10}}, []LineRange{{0, 7}}},
+		{"adjacent", [][2]int{{1, 3}, {4, 6}}, []LineRange{{0, 1}, {4, 4}, {7, 10}}},
+		{"nested", [][2]int{{1, 8}, {3, 5}}, []LineRange{{0, 1}, {9, 10}}},
+		{"overlapping", [][2]int{{1, 4}, {3, 6}}, []LineRange{{0, 1}, {7, 10}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(numberedLines(10)))
+			for _, f := range tt.folds {
+				e.AddFold(f[0], f[1])
+			}
+			if got := e.VisibleLines(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VisibleLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsLineVisible(t *testing.T) {
+	e := New(WithContent(numberedLines(10)))
+	e.AddFold(2, 5)
+
+	for line, want := range map[uint32]bool{1: true, 2: true, 3: false, 5: false, 6: true} {
+		if got := e.IsLineVisible(line); got != want {
+			t.Errorf("IsLineVisible(%d) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestFoldTransform(t *testing.T) {
+	type span struct{ start, end uint32 }
+
+	tests := []struct {
+		name string
+		edit func(e *Engine)
+		want span
+		gone bool
+	}{
+		{
+			name: "insert lines above",
+			edit: func(e *Engine) { e.Insert(e.LineStartOffset(1), "x\ny\n") },
+			want: span{6, 9},
+		},
+		{
+			name: "insert lines at header",
+			edit: func(e *Engine) { e.Insert(e.LineStartOffset(4), "x\n") },
+			want: span{5, 8},
+		},
+		{
+			name: "delete lines above",
+			edit: func(e *Engine) { e.Delete(e.LineStartOffset(0), e.LineStartOffset(2)) },
+			want: span{2, 5},
+		},
+		{
+			name: "insert lines inside",
+			edit: func(e *Engine) { e.Insert(e.LineStartOffset(5), "x\ny\n") },
+			want: span{4, 9},
+		},
+		{
+			name: "delete lines inside",
+			edit: func(e *Engine) { e.Delete(e.LineStartOffset(5), e.LineStartOffset(7)) },
+			want: span{4, 5},
+		},
+		{
+			name: "split line inside",
+			edit: func(e *Engine) { e.Insert(e.LineStartOffset(5)+1, "\n") },
+			want: span{4, 8},
+		},
+		{
+			name: "join lines inside",
+			edit: func(e *Engine) { e.Delete(e.LineEndOffset(5), e.LineStartOffset(6)) },
+			want: span{4, 6},
+		},
+		{
+			name: "edit header text",
+			edit: func(e *Engine) { e.Insert(e.LineStartOffset(4)+1, "xyz") },
+			want: span{4, 7},
+		},
+		{
+			name: "insert lines below",
+			edit: func(e *Engine) { e.Insert(e.LineStartOffset(8), "x\n") },
+			want: span{4, 7},
+		},
+		{
+			name: "delete header line",
+			edit: func(e *Engine) { e.Delete(e.LineStartOffset(3), e.LineStartOffset(5)) },
+			want: span{3, 5},
+		},
+		{
+			name: "delete across end",
+			edit: func(e *Engine) { e.Delete(e.LineStartOffset(6), e.LineStartOffset(9)) },
+			want: span{4, 5},
+		},
+		{
+			name: "delete folded region",
+			edit: func(e *Engine) { e.Delete(e.LineStartOffset(4), e.LineStartOffset(8)) },
+			gone: true,
+		},
+		{
+			name: "delete around folded region",
+			edit: func(e *Engine) { e.Delete(e.LineStartOffset(2)+1, e.LineStartOffset(9)) },
+			gone: true,
+		},
+		{
+			name: "delete body",
+			edit: func(e *Engine) { e.Delete(e.LineStartOffset(5), e.LineStartOffset(8)) },
+			gone: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(numberedLines(10)))
+			id := e.AddFold(4, 7)
+
+			tt.edit(e)
+
+			start, end, ok := foldRange(t, e, id)
+			if tt.gone {
+				if ok {
+					t.Errorf("fold = %d-%d, want removed", start, end)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("fold removed, want kept")
+			}
+			if start != tt.want.start || end != tt.want.end {
+				t.Errorf("fold = %d-%d, want %d-%d", start, end, tt.want.start, tt.want.end)
+			}
+		})
+	}
+}
+
+func TestFoldTransformNested(t *testing.T) {
+	e := New(WithContent(numberedLines(12)))
+	outer := e.AddFold(1, 9)
+	inner := e.AddFold(3, 5)
+
+	// Inserting above the inner fold grows the outer fold and shifts the inner one
+	e.Insert(e.LineStartOffset(2), "x\n")
+	if start, end, _ := foldRange(t, e, outer); start != 1 || end != 10 {
+		t.Errorf("outer = %d-%d, want 1-10", start, end)
+	}
+	if start, end, _ := foldRange(t, e, inner); start != 4 || end != 6 {
+		t.Errorf("inner = %d-%d, want 4-6", start, end)
+	}
+
+	// Deleting the inner fold's lines leaves the outer fold
+	e.Delete(e.LineStartOffset(4), e.LineStartOffset(7))
+	if _, _, ok := foldRange(t, e, inner); ok {
+		t.Error("inner fold should be removed")
+	}
+	if start, end, _ := foldRange(t, e, outer); start != 1 || end != 7 {
+		t.Errorf("outer = %d-%d, want 1-7", start, end)
+	}
+
+	if got, want := e.VisibleLines(), []LineRange{{0, 1}, {8, 10}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("VisibleLines() = %v, want %v", got, want)
+	}
+}
+
+func TestFoldTransformMerge(t *testing.T) {
+	e := New(WithContent(numberedLines(10)))
+	e.AddFold(2, 6)
+	e.AddFold(2, 4)
+
+	// Deleting lines 5-6 shrinks the outer fold onto the inner one
+	e.Delete(e.LineStartOffset(5), e.LineStartOffset(7))
+	if folds := e.Folds(); len(folds) != 1 || folds[0].StartLine != 2 || folds[0].EndLine != 4 {
+		t.Errorf("Folds() = %v, want a single fold 2-4", folds)
+	}
+}
+
+func TestFoldUndo(t *testing.T) {
+	e := New(WithContent(numberedLines(10)))
+	id := e.AddFold(4, 7)
+
+	e.Insert(e.LineStartOffset(0), "x\n")
+	if err := e.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if start, end, _ := foldRange(t, e, id); start != 4 || end != 7 {
+		t.Errorf("fold after undo = %d-%d, want 4-7", start, end)
+	}
+}
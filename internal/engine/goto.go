@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ClampMode controls how GotoLineColumn treats positions outside the
+// buffer. Flags are combined with |.
+type ClampMode uint8
+
+const (
+	// ClampNone rejects out-of-range lines and columns with an error.
+	ClampNone ClampMode = 0
+
+	// ClampLine moves lines before the first line to the first line and
+	// lines after the last line to the last line.
+	ClampLine ClampMode = 1 << 0
+
+	// ClampColumn moves negative columns to the start of the line and
+	// columns past the end of the line to the end of the line.
+	ClampColumn ClampMode = 1 << 1
+
+	// OneIndexed treats line and column as 1-indexed, as typed in Ex
+	// commands like ":42". Without it they are 0-indexed.
+	OneIndexed ClampMode = 1 << 2
+
+	// ClampAll clamps both lines and columns.
+	ClampAll = ClampLine | ClampColumn
+)
+
+// GotoLineColumn returns the offset of a line and byte column. The column
+// may equal the line length, which addresses the end of the line before
+// its terminator; a column inside a multi-byte character moves to the
+// start of that character. Out-of-range positions are clamped as
+// requested by mode, or rejected with ErrLineOutOfRange or
+// ErrColumnOutOfRange.
+func (e *Engine) GotoLineColumn(line, column int, mode ClampMode) (ByteOffset, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if mode&OneIndexed != 0 {
+		line--
+		column--
+	}
+
+	lastLine := int(e.buf.LineCount()) - 1
+	if line < 0 || line > lastLine {
+		if mode&ClampLine == 0 {
+			return 0, fmt.Errorf("%w: line %d of %d", ErrLineOutOfRange, userIndex(line, mode), lastLine+1)
+		}
+		line = max(min(line, lastLine), 0)
+	}
+
+	text := e.buf.LineText(uint32(line))
+	if column < 0 || column > len(text) {
+		if mode&ClampColumn == 0 {
+			return 0, fmt.Errorf("%w: column %d of line %d", ErrColumnOutOfRange, userIndex(column, mode), userIndex(line, mode))
+		}
+		column = max(min(column, len(text)), 0)
+	}
+
+	// Never land inside a multi-byte character
+	for column > 0 && column < len(text) && !utf8.RuneStart(text[column]) {
+		column--
+	}
+
+	return e.buf.LineStartOffset(uint32(line)) + ByteOffset(column), nil
+}
+
+// userIndex converts a 0-indexed position back to the indexing of mode,
+// for error messages.
+func userIndex(n int, mode ClampMode) int {
+	if mode&OneIndexed != 0 {
+		return n + 1
+	}
+	return n
+}
@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGotoLineColumn(t *testing.T) {
+	e := New(WithContent("first\nsecond line\nété"))
+
+	tests := []struct {
+		name         string
+		line, column int
+		mode         ClampMode
+		want         ByteOffset
+		wantErr      error
+	}{
+		{"start", 0, 0, ClampNone, 0, nil},
+		{"inside line", 1, 3, ClampNone, 9, nil},
+		{"end of line", 1, 11, ClampNone, 17, nil},
+		{"one-indexed", 2, 4, OneIndexed, 9, nil},
+		{"line past end clamped to last line", 99, 0, ClampLine, 18, nil},
+		{"line past end", 99, 0, ClampColumn, 0, ErrLineOutOfRange},
+		{"negative line clamped", -5, 2, ClampLine, 2, nil},
+		{"one-indexed line zero clamped", 0, 1, ClampLine | OneIndexed, 0, nil},
+		{"one-indexed line zero", 0, 1, OneIndexed, 0, ErrLineOutOfRange},
+		{"column past end clamped to end of line", 1, 50, ClampColumn, 17, nil},
+		{"column past end", 1, 50, ClampLine, 0, ErrColumnOutOfRange},
+		{"negative column clamped", 1, -3, ClampColumn, 6, nil},
+		{"column inside character", 2, 1, ClampNone, 18, nil},
+		{"both clamped", 99, 99, ClampAll, 23, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := e.GotoLineColumn(tt.line, tt.column, tt.mode)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GotoLineColumn() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GotoLineColumn() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GotoLineColumn() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGotoLineColumnEmptyBuffer(t *testing.T) {
+	e := New()
+
+	if got, err := e.GotoLineColumn(42, 7, ClampAll|OneIndexed); err != nil || got != 0 {
+		t.Errorf("GotoLineColumn() = %d, %v, want 0, nil", got, err)
+	}
+	if _, err := e.GotoLineColumn(1, 0, ClampNone); !errors.Is(err, ErrLineOutOfRange) {
+		t.Errorf("GotoLineColumn() error = %v, want ErrLineOutOfRange", err)
+	}
+}
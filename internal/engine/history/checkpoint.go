@@ -0,0 +1,212 @@
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/cursor"
+)
+
+// CheckpointID identifies a checkpoint within a History.
+type CheckpointID uint64
+
+// CheckpointInfo describes a checkpoint.
+type CheckpointInfo struct {
+	ID        CheckpointID
+	Name      string
+	Timestamp time.Time
+
+	// Current is true if the history is at the checkpoint's state.
+	Current bool
+}
+
+// namedCheckpoint marks a state of the history.
+type namedCheckpoint struct {
+	name      string
+	timestamp time.Time
+
+	// entry is the entry on top of the undo stack in the checkpoint's
+	// state, or nil if the undo stack is empty
+	entry *undoEntry
+
+	// pending is true if the checkpoint was made during a group; the
+	// group's entry is recorded when the group ends
+	pending bool
+}
+
+// Checkpoint names the current state so that RestoreCheckpoint can return
+// to it by undoing or redoing. Unlike a CreateCheckpoint depth, a named
+// checkpoint tracks its exact entry, so it is never restored to a different
+// state after an undo followed by a new edit. A checkpoint made during a
+// group marks the state after the group once EndGroup is called.
+//
+// A checkpoint is removed when its state can no longer be reached: when
+// its entry is pruned from the undo or redo stack, when a new edit discards
+// the redo stack it is on, when its group is cancelled, or by Clear.
+func (h *History) Checkpoint(name string) CheckpointID {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.checkpoints == nil {
+		h.checkpoints = make(map[CheckpointID]*namedCheckpoint)
+	}
+	h.nextCheckpoint++
+	id := h.nextCheckpoint
+	h.checkpoints[id] = &namedCheckpoint{
+		name:      name,
+		timestamp: time.Now(),
+		entry:     h.topLocked(),
+		pending:   h.grouping,
+	}
+	return id
+}
+
+// RestoreCheckpoint undoes or redoes until the history is at the
+// checkpoint's state. It returns ErrCheckpointNotFound if the checkpoint
+// was removed or its group has not ended. If a command fails, the history
+// is left where it stopped and the error is returned.
+func (h *History) RestoreCheckpoint(id CheckpointID, buf *buffer.Buffer, cursors *cursor.CursorSet) error {
+	for {
+		h.mu.Lock()
+		cp, ok := h.checkpoints[id]
+		if !ok || cp.pending {
+			h.mu.Unlock()
+			return ErrCheckpointNotFound
+		}
+		undo, done := h.directionLocked(cp.entry)
+		h.mu.Unlock()
+
+		if done {
+			return nil
+		}
+
+		var err error
+		if undo {
+			err = h.Undo(buf, cursors)
+		} else {
+			err = h.Redo(buf, cursors)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Checkpoints returns the checkpoints that can be restored, oldest first.
+func (h *History) Checkpoints() []CheckpointInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	top := h.topLocked()
+	result := make([]CheckpointInfo, 0, len(h.checkpoints))
+	for id, cp := range h.checkpoints {
+		if cp.pending {
+			continue
+		}
+		result = append(result, CheckpointInfo{
+			ID:        id,
+			Name:      cp.name,
+			Timestamp: cp.timestamp,
+			Current:   cp.entry == top,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// topLocked returns the entry on top of the undo stack, or nil.
+// Caller must hold the lock.
+func (h *History) topLocked() *undoEntry {
+	if len(h.undoStack) == 0 {
+		return nil
+	}
+	return h.undoStack[len(h.undoStack)-1]
+}
+
+// directionLocked returns whether reaching the state with entry on top of
+// the undo stack needs an undo or a redo, or done if it is the current
+// state. An entry on neither stack is treated as done.
+// Caller must hold the lock.
+func (h *History) directionLocked(entry *undoEntry) (undo, done bool) {
+	if entry == h.topLocked() {
+		return false, true
+	}
+	if entry == nil {
+		return true, false
+	}
+	for _, e := range h.undoStack {
+		if e == entry {
+			return true, false
+		}
+	}
+	for _, e := range h.redoStack {
+		if e == entry {
+			return false, false
+		}
+	}
+	return false, true
+}
+
+// forgetCheckpointsLocked removes the checkpoints on the given entries.
+// Caller must hold the lock.
+func (h *History) forgetCheckpointsLocked(entries []*undoEntry) {
+	if len(h.checkpoints) == 0 || len(entries) == 0 {
+		return
+	}
+	for id, cp := range h.checkpoints {
+		if cp.pending || cp.entry == nil {
+			continue
+		}
+		for _, e := range entries {
+			if cp.entry == e {
+				delete(h.checkpoints, id)
+				break
+			}
+		}
+	}
+}
+
+// rebaseCheckpointsLocked updates checkpoints after the oldest entries
+// were dropped from the undo stack. The state before the oldest remaining
+// entry is the new base, so checkpoints on the last dropped entry move to
+// the base, and the old base and the other dropped entries are forgotten.
+// Caller must hold the lock.
+func (h *History) rebaseCheckpointsLocked(dropped []*undoEntry) {
+	if len(h.checkpoints) == 0 || len(dropped) == 0 {
+		return
+	}
+	last := dropped[len(dropped)-1]
+	for id, cp := range h.checkpoints {
+		if !cp.pending && cp.entry == nil {
+			delete(h.checkpoints, id)
+		}
+	}
+	h.forgetCheckpointsLocked(dropped[:len(dropped)-1])
+	for _, cp := range h.checkpoints {
+		if !cp.pending && cp.entry == last {
+			cp.entry = nil
+		}
+	}
+}
+
+// resolvePendingCheckpointsLocked records the current state for the
+// checkpoints made during the group that just ended, or forgets them if
+// the group was cancelled.
+// Caller must hold the lock.
+func (h *History) resolvePendingCheckpointsLocked(cancelled bool) {
+	top := h.topLocked()
+	for id, cp := range h.checkpoints {
+		if !cp.pending {
+			continue
+		}
+		if cancelled {
+			delete(h.checkpoints, id)
+			continue
+		}
+		cp.pending = false
+		cp.entry = top
+	}
+}
@@ -20,6 +20,21 @@ type Command interface {
 	Description() string
 }
 
+// Sizer is implemented by commands that can report how many bytes of text
+// they retain for undo/redo. History uses it for memory accounting;
+// commands that do not implement it count as zero bytes.
+type Sizer interface {
+	Size() int64
+}
+
+// commandSize returns the retained size of a command.
+func commandSize(cmd Command) int64 {
+	if s, ok := cmd.(Sizer); ok {
+		return s.Size()
+	}
+	return 0
+}
+
 // InsertCommand inserts text at all cursor positions.
 type InsertCommand struct {
 	Text       string
@@ -138,6 +153,11 @@ func (c *InsertCommand) Description() string {
 	return fmt.Sprintf("Insert %d characters", utf8.RuneCountInString(c.Text))
 }
 
+// Size returns the number of bytes of text retained for undo.
+func (c *InsertCommand) Size() int64 {
+	return c.operations.Size()
+}
+
 // DeleteDirection specifies the direction of deletion.
 type DeleteDirection int
 
@@ -323,6 +343,11 @@ func (c *DeleteCommand) Description() string {
 	return fmt.Sprintf("Delete %d characters", c.Count)
 }
 
+// Size returns the number of bytes of text retained for undo.
+func (c *DeleteCommand) Size() int64 {
+	return c.operations.Size()
+}
+
 // ReplaceCommand replaces text in a specific range.
 type ReplaceCommand struct {
 	Range      Range
@@ -398,6 +423,11 @@ func (c *ReplaceCommand) Description() string {
 	return fmt.Sprintf("Replace %d with %d characters", oldLen, newLen)
 }
 
+// Size returns the number of bytes of text retained for undo.
+func (c *ReplaceCommand) Size() int64 {
+	return c.operations.Size()
+}
+
 // CompoundCommand groups multiple commands as one undo unit.
 type CompoundCommand struct {
 	Name     string
@@ -447,6 +477,15 @@ func (c *CompoundCommand) Description() string {
 	return fmt.Sprintf("%d operations", len(c.Commands))
 }
 
+// Size returns the total size of the grouped commands.
+func (c *CompoundCommand) Size() int64 {
+	var total int64
+	for _, cmd := range c.Commands {
+		total += commandSize(cmd)
+	}
+	return total
+}
+
 // Add adds a command to the compound command.
 func (c *CompoundCommand) Add(cmd Command) {
 	c.Commands = append(c.Commands, cmd)
@@ -42,6 +42,26 @@
 //
 // Now all edits undo together with one Ctrl+Z.
 //
+// # Checkpoints
+//
+// A named checkpoint marks a state that can be returned to by undoing or
+// redoing:
+//
+//	id := history.Checkpoint("before refactor")
+//	// ... edits, undos ...
+//	history.RestoreCheckpoint(id, buffer, cursors)
+//
+// Checkpoints whose state falls out of the history, through pruning or a
+// new edit discarding the redo stack, are removed.
+//
+// # Memory Limits
+//
+// Besides the entry count, history can be bounded by the bytes of text it
+// retains. Commands report their size through the Sizer interface:
+//
+//	history.SetMaxBytes(16 << 20) // Prune oldest entries beyond 16MB
+//	used := history.MemoryUsage()
+//
 // # Cursor Restoration
 //
 // Commands track cursor positions before and after execution,
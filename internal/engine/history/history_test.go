@@ -2,6 +2,7 @@ package history
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/dshills/keystorm/internal/engine/buffer"
@@ -433,6 +434,116 @@ func TestHistoryMaxEntries(t *testing.T) {
 	}
 }
 
+func TestHistoryMemoryUsage(t *testing.T) {
+	buf, cursors := newTestBufferAndCursors("hello", 5)
+	history := NewHistory(100)
+
+	history.Execute(NewInsertCommand(" world"), buf, cursors)
+	if got := history.MemoryUsage(); got != 6 {
+		t.Errorf("MemoryUsage() = %d, want 6", got)
+	}
+
+	// Undo moves the entry to the redo stack without changing usage
+	history.Undo(buf, cursors)
+	if got := history.MemoryUsage(); got != 6 {
+		t.Errorf("MemoryUsage() after undo = %d, want 6", got)
+	}
+
+	// A new command clears the redo stack
+	history.Execute(NewInsertCommand("!"), buf, cursors)
+	if got := history.MemoryUsage(); got != 1 {
+		t.Errorf("MemoryUsage() after push = %d, want 1", got)
+	}
+
+	history.Clear()
+	if got := history.MemoryUsage(); got != 0 {
+		t.Errorf("MemoryUsage() after clear = %d, want 0", got)
+	}
+}
+
+func TestHistoryMaxBytesPrunesOnLargeEdit(t *testing.T) {
+	buf, cursors := newTestBufferAndCursors("", 0)
+	history := NewHistory(100)
+	history.SetMaxBytes(1000)
+
+	for i := 0; i < 10; i++ {
+		history.Execute(NewInsertCommand("0123456789"), buf, cursors)
+	}
+	if history.UndoCount() != 10 {
+		t.Fatalf("undo count = %d, want 10", history.UndoCount())
+	}
+
+	// A 950-byte edit leaves room for only five of the small edits
+	history.Execute(NewInsertCommand(strings.Repeat("x", 950)), buf, cursors)
+
+	if got := history.MemoryUsage(); got > 1000 {
+		t.Errorf("MemoryUsage() = %d, want <= 1000", got)
+	}
+	if history.UndoCount() != 6 {
+		t.Errorf("undo count = %d, want 6", history.UndoCount())
+	}
+
+	// The remaining entries still undo consistently, newest first
+	for history.CanUndo() {
+		if err := history.Undo(buf, cursors); err != nil {
+			t.Fatalf("Undo() error = %v", err)
+		}
+	}
+	if want := strings.Repeat("0123456789", 5); buf.Text() != want {
+		t.Errorf("after undoing all: got %q, want %q", buf.Text(), want)
+	}
+	if history.RedoCount() != 6 {
+		t.Errorf("redo count = %d, want 6", history.RedoCount())
+	}
+}
+
+func TestHistoryMaxBytesKeepsLatestEntry(t *testing.T) {
+	buf, cursors := newTestBufferAndCursors("", 0)
+	history := NewHistory(100)
+	history.SetMaxBytes(10)
+
+	history.Execute(NewInsertCommand("abc"), buf, cursors)
+	history.Execute(NewInsertCommand("this edit alone exceeds the limit"), buf, cursors)
+
+	if history.UndoCount() != 1 {
+		t.Errorf("undo count = %d, want 1", history.UndoCount())
+	}
+	if err := history.Undo(buf, cursors); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if buf.Text() != "abc" {
+		t.Errorf("after undo: got %q, want %q", buf.Text(), "abc")
+	}
+}
+
+func TestHistoryMaxBytesOpenGroup(t *testing.T) {
+	buf, cursors := newTestBufferAndCursors("", 0)
+	history := NewHistory(100)
+
+	history.Execute(NewInsertCommand("aaaa"), buf, cursors)
+	history.Execute(NewInsertCommand("bbbb"), buf, cursors)
+
+	history.BeginGroup("typing")
+	history.Execute(NewInsertCommand("cccc"), buf, cursors)
+	history.SetMaxBytes(6)
+
+	// Nothing is pruned while the group is open
+	if history.UndoCount() != 2 {
+		t.Errorf("undo count with open group = %d, want 2", history.UndoCount())
+	}
+	if got := history.MemoryUsage(); got != 12 {
+		t.Errorf("MemoryUsage() with open group = %d, want 12", got)
+	}
+
+	history.EndGroup()
+	if history.UndoCount() != 1 {
+		t.Errorf("undo count after EndGroup = %d, want 1", history.UndoCount())
+	}
+	if got := history.MemoryUsage(); got != 4 {
+		t.Errorf("MemoryUsage() after EndGroup = %d, want 4", got)
+	}
+}
+
 func TestHistoryCanUndoRedo(t *testing.T) {
 	buf, cursors := newTestBufferAndCursors("hello", 5)
 	history := NewHistory(100)
@@ -643,6 +754,111 @@ func TestHistoryCheckpoint(t *testing.T) {
 	}
 }
 
+func TestHistoryNamedCheckpoint(t *testing.T) {
+	buf, cursors := newTestBufferAndCursors("hello", 5)
+	history := NewHistory(100)
+
+	history.Execute(NewInsertCommand(" "), buf, cursors)
+	before := history.Checkpoint("before refactor")
+	history.Execute(NewInsertCommand("world"), buf, cursors)
+	after := history.Checkpoint("after refactor")
+	history.Execute(NewInsertCommand("!"), buf, cursors)
+
+	if err := history.RestoreCheckpoint(before, buf, cursors); err != nil {
+		t.Fatalf("RestoreCheckpoint(before) error = %v", err)
+	}
+	if buf.Text() != "hello " {
+		t.Errorf("after restoring before: got %q, want %q", buf.Text(), "hello ")
+	}
+
+	// Restoring a later checkpoint redoes
+	if err := history.RestoreCheckpoint(after, buf, cursors); err != nil {
+		t.Fatalf("RestoreCheckpoint(after) error = %v", err)
+	}
+	if buf.Text() != "hello world" {
+		t.Errorf("after restoring after: got %q, want %q", buf.Text(), "hello world")
+	}
+
+	infos := history.Checkpoints()
+	if len(infos) != 2 || infos[0].Name != "before refactor" || infos[1].Name != "after refactor" {
+		t.Fatalf("Checkpoints() = %+v, want both in order", infos)
+	}
+	if infos[0].Current || !infos[1].Current {
+		t.Errorf("Checkpoints() = %+v, want only after current", infos)
+	}
+
+	// Back to before, then a new edit discards the redo path to after
+	history.RestoreCheckpoint(before, buf, cursors)
+	history.Execute(NewInsertCommand("there"), buf, cursors)
+	if err := history.RestoreCheckpoint(after, buf, cursors); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("RestoreCheckpoint(discarded) error = %v, want ErrCheckpointNotFound", err)
+	}
+	if err := history.RestoreCheckpoint(before, buf, cursors); err != nil {
+		t.Fatalf("RestoreCheckpoint(before) error = %v", err)
+	}
+	if buf.Text() != "hello " {
+		t.Errorf("after second restore: got %q, want %q", buf.Text(), "hello ")
+	}
+}
+
+func TestHistoryNamedCheckpointPruned(t *testing.T) {
+	buf, cursors := newTestBufferAndCursors("", 0)
+	history := NewHistory(2)
+
+	base := history.Checkpoint("base")
+	history.Execute(NewInsertCommand("a"), buf, cursors)
+	first := history.Checkpoint("first")
+	history.Execute(NewInsertCommand("b"), buf, cursors)
+	history.Execute(NewInsertCommand("c"), buf, cursors)
+
+	// "a" was pruned: the empty buffer is unreachable, but "a" is the new base
+	if err := history.RestoreCheckpoint(base, buf, cursors); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("RestoreCheckpoint(base) error = %v, want ErrCheckpointNotFound", err)
+	}
+	if err := history.RestoreCheckpoint(first, buf, cursors); err != nil {
+		t.Fatalf("RestoreCheckpoint(first) error = %v", err)
+	}
+	if buf.Text() != "a" {
+		t.Errorf("after restoring first: got %q, want %q", buf.Text(), "a")
+	}
+
+	history.Execute(NewInsertCommand("x"), buf, cursors)
+	history.Execute(NewInsertCommand("y"), buf, cursors)
+	history.Execute(NewInsertCommand("z"), buf, cursors)
+	if infos := history.Checkpoints(); len(infos) != 0 {
+		t.Errorf("Checkpoints() = %+v, want none after pruning", infos)
+	}
+}
+
+func TestHistoryNamedCheckpointInGroup(t *testing.T) {
+	buf, cursors := newTestBufferAndCursors("", 0)
+	history := NewHistory(100)
+
+	history.BeginGroup("typing")
+	history.Execute(NewInsertCommand("a"), buf, cursors)
+	id := history.Checkpoint("in group")
+	if err := history.RestoreCheckpoint(id, buf, cursors); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("RestoreCheckpoint(open group) error = %v, want ErrCheckpointNotFound", err)
+	}
+	history.Execute(NewInsertCommand("b"), buf, cursors)
+	history.EndGroup()
+
+	history.Execute(NewInsertCommand("c"), buf, cursors)
+	if err := history.RestoreCheckpoint(id, buf, cursors); err != nil {
+		t.Fatalf("RestoreCheckpoint() error = %v", err)
+	}
+	if buf.Text() != "ab" {
+		t.Errorf("got %q, want %q", buf.Text(), "ab")
+	}
+
+	history.BeginGroup("cancelled")
+	cancelled := history.Checkpoint("cancelled")
+	history.CancelGroup()
+	if err := history.RestoreCheckpoint(cancelled, buf, cursors); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("RestoreCheckpoint(cancelled) error = %v, want ErrCheckpointNotFound", err)
+	}
+}
+
 // Multi-cursor Tests
 
 func TestInsertMultiCursor(t *testing.T) {
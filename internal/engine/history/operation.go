@@ -166,6 +166,15 @@ func (ops OperationList) Invert() OperationList {
 	return result
 }
 
+// Size returns the number of bytes of text stored by the operations.
+func (ops OperationList) Size() int64 {
+	var total int64
+	for _, op := range ops {
+		total += int64(len(op.OldText) + len(op.NewText))
+	}
+	return total
+}
+
 // TotalBytesDelta returns the total change in document length.
 func (ops OperationList) TotalBytesDelta() int {
 	total := 0
@@ -13,12 +13,15 @@ import (
 var (
 	ErrNothingToUndo = errors.New("nothing to undo")
 	ErrNothingToRedo = errors.New("nothing to redo")
+
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
 )
 
 // undoEntry wraps a command with metadata.
 type undoEntry struct {
 	command   Command
 	timestamp time.Time
+	size      int64
 }
 
 // History manages undo/redo state for a buffer.
@@ -33,8 +36,16 @@ type History struct {
 	groupName string
 	groupCmds []Command
 
+	// Memory accounting: total size of entries on both stacks
+	totalBytes int64
+
 	// Configuration
 	maxEntries int
+	maxBytes   int64 // 0 means unlimited
+
+	// Named checkpoints
+	checkpoints    map[CheckpointID]*namedCheckpoint
+	nextCheckpoint CheckpointID
 }
 
 // NewHistory creates a new history manager.
@@ -73,19 +84,56 @@ func (h *History) Push(cmd Command) {
 
 // pushLocked adds a command without acquiring the lock.
 func (h *History) pushLocked(cmd Command) {
-	h.undoStack = append(h.undoStack, &undoEntry{
+	entry := &undoEntry{
 		command:   cmd,
 		timestamp: time.Now(),
-	})
+		size:      commandSize(cmd),
+	}
+	h.undoStack = append(h.undoStack, entry)
+	h.totalBytes += entry.size
 
 	// Clear redo stack
+	for _, e := range h.redoStack {
+		h.totalBytes -= e.size
+	}
+	h.forgetCheckpointsLocked(h.redoStack)
 	h.redoStack = nil
 
 	// Enforce max entries
 	if len(h.undoStack) > h.maxEntries {
 		// Remove oldest entries
-		excess := len(h.undoStack) - h.maxEntries
-		h.undoStack = h.undoStack[excess:]
+		h.dropOldestLocked(len(h.undoStack) - h.maxEntries)
+	}
+
+	h.pruneLocked()
+}
+
+// dropOldestLocked removes the n oldest undo entries.
+func (h *History) dropOldestLocked(n int) {
+	for _, e := range h.undoStack[:n] {
+		h.totalBytes -= e.size
+	}
+	h.rebaseCheckpointsLocked(h.undoStack[:n])
+	h.undoStack = h.undoStack[n:]
+}
+
+// pruneLocked removes the oldest undo entries until the stored text fits
+// in maxBytes. The most recent undo entry is always kept so the last edit
+// can be undone, and nothing is pruned while a group is open; the group's
+// EndGroup prunes once it is pushed. If the undo entries alone cannot bring
+// usage under the limit, redo entries furthest from the current state are
+// dropped as well.
+func (h *History) pruneLocked() {
+	if h.maxBytes <= 0 || h.grouping {
+		return
+	}
+	for h.totalBytes > h.maxBytes && len(h.undoStack) > 1 {
+		h.dropOldestLocked(1)
+	}
+	for h.totalBytes > h.maxBytes && len(h.redoStack) > 0 {
+		h.totalBytes -= h.redoStack[0].size
+		h.forgetCheckpointsLocked(h.redoStack[:1])
+		h.redoStack = h.redoStack[1:]
 	}
 }
 
@@ -205,6 +253,7 @@ func (h *History) EndGroup() {
 
 	if len(h.groupCmds) == 0 {
 		h.groupCmds = nil
+		h.resolvePendingCheckpointsLocked(false)
 		return
 	}
 
@@ -216,6 +265,7 @@ func (h *History) EndGroup() {
 
 	h.pushLocked(compound)
 	h.groupCmds = nil
+	h.resolvePendingCheckpointsLocked(false)
 }
 
 // CancelGroup cancels a command group without adding to history.
@@ -226,6 +276,7 @@ func (h *History) CancelGroup() {
 
 	h.grouping = false
 	h.groupCmds = nil
+	h.resolvePendingCheckpointsLocked(true)
 }
 
 // IsGrouping returns true if currently in a command group.
@@ -235,15 +286,17 @@ func (h *History) IsGrouping() bool {
 	return h.grouping
 }
 
-// Clear removes all undo/redo history.
+// Clear removes all undo/redo history and checkpoints.
 func (h *History) Clear() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	h.undoStack = nil
 	h.redoStack = nil
+	h.totalBytes = 0
 	h.grouping = false
 	h.groupCmds = nil
+	h.checkpoints = nil
 }
 
 // UndoInfo returns info about available undo operations.
@@ -321,8 +374,7 @@ func (h *History) SetMaxEntries(maxEntries int) {
 	h.maxEntries = maxEntries
 
 	if len(h.undoStack) > maxEntries {
-		excess := len(h.undoStack) - maxEntries
-		h.undoStack = h.undoStack[excess:]
+		h.dropOldestLocked(len(h.undoStack) - maxEntries)
 	}
 }
 
@@ -332,3 +384,38 @@ func (h *History) MaxEntries() int {
 	defer h.mu.Unlock()
 	return h.maxEntries
 }
+
+// SetMaxBytes limits the total size of the text retained by undo and redo
+// entries, independent of the entry count. When the limit is exceeded the
+// oldest entries are pruned (see MemoryUsage). A limit <= 0 disables
+// memory-based pruning.
+func (h *History) SetMaxBytes(maxBytes int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	h.maxBytes = maxBytes
+	h.pruneLocked()
+}
+
+// MaxBytes returns the memory limit, or 0 if unlimited.
+func (h *History) MaxBytes() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.maxBytes
+}
+
+// MemoryUsage returns the number of bytes of text retained by the undo and
+// redo stacks and any open group.
+func (h *History) MemoryUsage() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	total := h.totalBytes
+	for _, cmd := range h.groupCmds {
+		total += commandSize(cmd)
+	}
+	return total
+}
@@ -0,0 +1,259 @@
+package engine
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dshills/keystorm/internal/engine/buffer"
+)
+
+// IndentRules are the language rules for indenting a new line relative to
+// the line above it.
+type IndentRules struct {
+	// IncreaseAfter are line endings, ignoring trailing whitespace, after
+	// which the next line is indented one level more (e.g., "{" or ":").
+	// An ending that starts with a word character only matches at a word
+	// boundary, so "do" matches "for x; do" but not "echo todo".
+	IncreaseAfter []string
+
+	// DecreaseBefore are line beginnings, ignoring leading whitespace,
+	// that indent their line one level less (e.g., "}"). A beginning that
+	// ends with a word character only matches at a word boundary, so "fi"
+	// matches "fi" but not "find".
+	DecreaseBefore []string
+}
+
+// DefaultIndentRules returns rules for brace languages: lines ending in an
+// opening bracket increase the indent and lines starting with a closing
+// bracket decrease it.
+func DefaultIndentRules() IndentRules {
+	return IndentRules{
+		IncreaseAfter:  []string{"{", "[", "("},
+		DecreaseBefore: []string{"}", "]", ")"},
+	}
+}
+
+// languageIndentRules holds the rules of languages whose blocks are not
+// (only) delimited by brackets, by language ID.
+var (
+	languageIndentMu    sync.RWMutex
+	languageIndentRules = map[string]IndentRules{
+		"python": {
+			IncreaseAfter:  []string{":", "{", "[", "("},
+			DecreaseBefore: []string{"elif ", "else:", "except", "finally:", "}", "]", ")"},
+		},
+		"yaml": {
+			IncreaseAfter: []string{":"},
+		},
+		"lua": {
+			IncreaseAfter:  []string{"then", "do", "else", "{", "("},
+			DecreaseBefore: []string{"end", "else", "elseif", "}", ")"},
+		},
+		"ruby": {
+			IncreaseAfter:  []string{"do", "then", "else", "{", "[", "("},
+			DecreaseBefore: []string{"end", "else", "elsif", "when", "}", "]", ")"},
+		},
+		"shellscript": {
+			IncreaseAfter:  []string{"then", "do", "else", "{", "("},
+			DecreaseBefore: []string{"fi", "done", "else", "elif", "esac", "}", ")"},
+		},
+		"markdown":  {},
+		"plaintext": {},
+	}
+)
+
+// RegisterIndentRules sets the rules IndentRulesFor returns for a language
+// ID, replacing any built-in rules.
+func RegisterIndentRules(languageID string, rules IndentRules) {
+	languageIndentMu.Lock()
+	defer languageIndentMu.Unlock()
+	languageIndentRules[languageID] = rules.clone()
+}
+
+// IndentRulesFor returns the rules registered for a language ID, or
+// DefaultIndentRules for languages without rules of their own.
+func IndentRulesFor(languageID string) IndentRules {
+	languageIndentMu.RLock()
+	defer languageIndentMu.RUnlock()
+	if rules, ok := languageIndentRules[languageID]; ok {
+		return rules.clone()
+	}
+	return DefaultIndentRules()
+}
+
+// increasesAfter returns true if the next line after text is indented.
+func (r IndentRules) increasesAfter(text string) bool {
+	text = strings.TrimRight(text, " \t")
+	for _, suffix := range r.IncreaseAfter {
+		if suffix == "" || !strings.HasSuffix(text, suffix) {
+			continue
+		}
+		// \b before a suffix starting with a word character
+		rest := text[:len(text)-len(suffix)]
+		if isWordByte(suffix[0]) && rest != "" && isWordByte(rest[len(rest)-1]) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// decreasesBefore returns true if a line with text is dedented.
+func (r IndentRules) decreasesBefore(text string) bool {
+	text = strings.TrimLeft(text, " \t")
+	for _, prefix := range r.DecreaseBefore {
+		if prefix == "" || !strings.HasPrefix(text, prefix) {
+			continue
+		}
+		// \b after a prefix ending with a word character
+		rest := text[len(prefix):]
+		if isWordByte(prefix[len(prefix)-1]) && rest != "" && isWordByte(rest[0]) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// isWordByte returns true for the ASCII bytes of identifiers.
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// WithAutoIndent enables indenting new lines with AutoIndent.
+func WithAutoIndent(enabled bool) Option {
+	return func(e *Engine) {
+		e.autoIndent = enabled
+	}
+}
+
+// WithIndentRules sets the rules used by AutoIndent.
+func WithIndentRules(rules IndentRules) Option {
+	return func(e *Engine) {
+		e.indentRules = rules.clone()
+	}
+}
+
+// WithUseTabs indents with tabs instead of TabWidth spaces.
+func WithUseTabs(useTabs bool) Option {
+	return func(e *Engine) {
+		e.useTabs = useTabs
+	}
+}
+
+// SetAutoIndent enables or disables indenting new lines.
+func (e *Engine) SetAutoIndent(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.autoIndent = enabled
+}
+
+// AutoIndentEnabled returns true if new lines are indented.
+func (e *Engine) AutoIndentEnabled() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.autoIndent
+}
+
+// SetIndentRules sets the rules used by AutoIndent, typically when the
+// buffer's language changes.
+func (e *Engine) SetIndentRules(rules IndentRules) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.indentRules = rules.clone()
+}
+
+// IndentRules returns the rules used by AutoIndent.
+func (e *Engine) IndentRules() IndentRules {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.indentRules.clone()
+}
+
+// SetUseTabs sets whether indentation uses tabs instead of spaces.
+func (e *Engine) SetUseTabs(useTabs bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.useTabs = useTabs
+}
+
+// UseTabs returns true if indentation uses tabs instead of spaces.
+func (e *Engine) UseTabs() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.useTabs
+}
+
+// AutoIndent returns the leading whitespace for line under the engine's
+// indent rules. See ComputeIndent.
+func (e *Engine) AutoIndent(line int) string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.computeIndentLocked(e.buf.Snapshot(), line, e.indentRules)
+}
+
+// ComputeIndent returns the leading whitespace line should have: the
+// indent of the nearest non-blank line above it, one level more if that
+// line ends with an IncreaseAfter trigger, and one level less if line
+// starts with a DecreaseBefore trigger. A level is a tab, or TabWidth
+// spaces, depending on UseTabs. Line may be one past the last line.
+func (e *Engine) ComputeIndent(line int, rules IndentRules) string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.computeIndentLocked(e.buf.Snapshot(), line, rules)
+}
+
+// computeIndentLocked implements ComputeIndent.
+// Caller must hold the lock.
+func (e *Engine) computeIndentLocked(snap *buffer.Snapshot, line int, rules IndentRules) string {
+	lineCount := int(snap.LineCount())
+	if line <= 0 || line > lineCount {
+		return ""
+	}
+
+	// Find the nearest non-blank line above
+	prev := line - 1
+	for prev > 0 && strings.TrimSpace(snap.LineText(uint32(prev))) == "" {
+		prev--
+	}
+	prevText := snap.LineText(uint32(prev))
+
+	unit := strings.Repeat(" ", snap.TabWidth())
+	if e.useTabs {
+		unit = "\t"
+	}
+
+	indent := getIndent(prevText)
+	if rules.increasesAfter(prevText) {
+		indent += unit
+	}
+	if line < lineCount && rules.decreasesBefore(snap.LineText(uint32(line))) {
+		indent = dedent(indent, snap.TabWidth())
+	}
+	return indent
+}
+
+// clone returns a copy of the rules that shares no slices.
+func (r IndentRules) clone() IndentRules {
+	return IndentRules{
+		IncreaseAfter:  slices.Clone(r.IncreaseAfter),
+		DecreaseBefore: slices.Clone(r.DecreaseBefore),
+	}
+}
+
+// getIndent returns the leading spaces and tabs of text.
+func getIndent(text string) string {
+	return text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+}
+
+// dedent removes one level of indentation from the end of indent: a tab,
+// or up to tabWidth spaces.
+func dedent(indent string, tabWidth int) string {
+	if strings.HasSuffix(indent, "\t") {
+		return indent[:len(indent)-1]
+	}
+	trimmed := strings.TrimRight(indent, " ")
+	spaces := min(len(indent)-len(trimmed), tabWidth)
+	return indent[:len(indent)-spaces]
+}
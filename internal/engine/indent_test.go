@@ -0,0 +1,93 @@
+package engine
+
+import "testing"
+
+func TestComputeIndent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		useTabs bool
+		want    string
+	}{
+		{"first line", "x", 0, false, ""},
+		{"keeps indent", "  x\n", 1, false, "  "},
+		{"after brace", "func f() {\n", 1, false, "    "},
+		{"after brace with tabs", "\tif x {\n", 1, true, "\t\t"},
+		{"trailing whitespace", "if x {  \n", 1, false, "    "},
+		{"skips blank lines", "if x {\n\n\n", 3, false, "    "},
+		{"dedent closing brace", "func f() {\n}", 1, false, ""},
+		{"dedent nested", "    if x {\n        y()\n    }", 2, false, "    "},
+		{"dedent with tabs", "\t\ty()\n\t}", 1, true, "\t"},
+		{"brace pair", "\t[\n]", 1, true, "\t"},
+		{"past end", "x", 2, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(tt.content), WithTabWidth(4), WithUseTabs(tt.useTabs))
+			if got := e.ComputeIndent(tt.line, DefaultIndentRules()); got != tt.want {
+				t.Errorf("ComputeIndent(%d) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoIndentRules(t *testing.T) {
+	e := New(WithContent("def f():\n"), WithTabWidth(4))
+	if got := e.AutoIndent(1); got != "" {
+		t.Errorf("AutoIndent() with default rules = %q, want empty", got)
+	}
+
+	e.SetIndentRules(IndentRules{IncreaseAfter: []string{":"}})
+	if got := e.AutoIndent(1); got != "    " {
+		t.Errorf("AutoIndent() with python rules = %q, want 4 spaces", got)
+	}
+
+	e.SetUseTabs(true)
+	if got := e.AutoIndent(1); got != "\t" {
+		t.Errorf("AutoIndent() with tabs = %q, want tab", got)
+	}
+
+	if e.AutoIndentEnabled() {
+		t.Error("AutoIndentEnabled() should default to false")
+	}
+	e.SetAutoIndent(true)
+	if !e.AutoIndentEnabled() {
+		t.Error("AutoIndentEnabled() = false after SetAutoIndent(true)")
+	}
+}
+
+func TestIndentRulesFor(t *testing.T) {
+	tests := []struct {
+		language string
+		content  string
+		line     int
+		want     string
+	}{
+		{"go", "func f() {\n", 1, "    "},
+		{"python", "if x:\n", 1, "    "},
+		{"python", "if x:\n    y()\nelse:", 2, ""},
+		{"lua", "if x then\n", 1, "    "},
+		{"shellscript", "if x; then\n    y\nfi", 2, ""},
+		{"shellscript", "echo todo\n", 1, ""},
+		{"shellscript", "if x; then\n    find .", 1, "    "},
+		{"lua", "if x then\n    endpoint()", 1, "    "},
+		{"markdown", "- item {\n", 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			e := New(WithContent(tt.content), WithTabWidth(4))
+			if got := e.ComputeIndent(tt.line, IndentRulesFor(tt.language)); got != tt.want {
+				t.Errorf("ComputeIndent(%d) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+
+	RegisterIndentRules("test-language", IndentRules{IncreaseAfter: []string{"begin"}})
+	e := New(WithContent("begin\n"), WithTabWidth(2))
+	if got := e.ComputeIndent(1, IndentRulesFor("test-language")); got != "  " {
+		t.Errorf("ComputeIndent() with registered rules = %q, want 2 spaces", got)
+	}
+}
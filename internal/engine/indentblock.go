@@ -0,0 +1,46 @@
+package engine
+
+import "github.com/dshills/keystorm/internal/engine/cursor"
+
+// WithIndentBlockBlankLines sets whether blank lines end indentation
+// blocks. By default blank lines inside a block belong to it.
+func WithIndentBlockBlankLines(breakBlock bool) Option {
+	return func(e *Engine) {
+		e.blankLinesBreakIndentBlock = breakBlock
+	}
+}
+
+// SetIndentBlockBlankLines sets whether blank lines end indentation blocks.
+func (e *Engine) SetIndentBlockBlankLines(breakBlock bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.blankLinesBreakIndentBlock = breakBlock
+}
+
+// IndentBlockBlankLines returns true if blank lines end indentation blocks.
+func (e *Engine) IndentBlockBlankLines() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.blankLinesBreakIndentBlock
+}
+
+// IndentBlock returns the indentation block around line as a range of whole
+// lines, for the "ii" and "ai" text objects: the consecutive lines indented
+// at least as far as line. The around variant adds the header line above
+// the block and, if the indent rules' DecreaseBefore triggers start it, the
+// closing line below it, such as "}". See cursor.IndentBlock.
+func (e *Engine) IndentBlock(line int, around bool) Range {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	snap := e.buf.Snapshot()
+	rules := e.indentRules
+	start, end := cursor.IndentBlock(snap.Rope(), uint32(max(line, 0)), around, cursor.IndentBlockOptions{
+		TabWidth:        snap.TabWidth(),
+		BlankLinesBreak: e.blankLinesBreakIndentBlock,
+		IsTrailer: func(text string) bool {
+			return rules.decreasesBefore(text) && !rules.increasesAfter(text)
+		},
+	})
+	return Range{Start: start, End: end}
+}
@@ -0,0 +1,75 @@
+package engine
+
+import "testing"
+
+const pythonSample = `class Foo:
+    def bar(self):
+        x = 1
+
+        if x:
+            return x
+        return 0
+
+    def baz(self):
+        pass
+`
+
+func TestIndentBlock(t *testing.T) {
+	e := New(WithContent(pythonSample))
+	lines := func(first, last uint32) Range {
+		return Range{Start: e.LineStartOffset(first), End: e.LineStartOffset(last + 1)}
+	}
+
+	tests := []struct {
+		name   string
+		line   int
+		around bool
+		want   Range
+	}{
+		{"method body", 2, false, lines(2, 6)},
+		{"method body around", 2, true, lines(1, 6)},
+		{"nested block", 5, false, lines(5, 5)},
+		{"nested block around", 5, true, lines(4, 5)},
+		{"blank line inside", 3, false, lines(2, 6)},
+		{"class body", 1, false, lines(1, 9)},
+		{"top level", 0, false, lines(0, 9)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.IndentBlock(tt.line, tt.around); got != tt.want {
+				t.Errorf("IndentBlock(%d, %v) = %v, want %v", tt.line, tt.around, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndentBlockBlankLines(t *testing.T) {
+	e := New(WithContent(pythonSample), WithIndentBlockBlankLines(true))
+	if !e.IndentBlockBlankLines() {
+		t.Fatal("IndentBlockBlankLines() = false, want true")
+	}
+
+	want := Range{Start: e.LineStartOffset(4), End: e.LineStartOffset(7)}
+	if got := e.IndentBlock(5, false); got != (Range{Start: e.LineStartOffset(5), End: e.LineStartOffset(6)}) {
+		t.Errorf("IndentBlock(5) = %v, want line 5", got)
+	}
+	if got := e.IndentBlock(4, false); got != want {
+		t.Errorf("IndentBlock(4) = %v, want lines 4-6 %v", got, want)
+	}
+	if got := e.IndentBlock(2, true); got != (Range{Start: e.LineStartOffset(1), End: e.LineStartOffset(3)}) {
+		t.Errorf("IndentBlock(2, around) = %v, want lines 1-2", got)
+	}
+}
+
+func TestIndentBlockTrailer(t *testing.T) {
+	e := New(WithContent("func f() {\n\tif x {\n\t\ty()\n\t}\n}\n"))
+
+	want := Range{Start: e.LineStartOffset(1), End: e.LineStartOffset(4)}
+	if got := e.IndentBlock(2, true); got != want {
+		t.Errorf("IndentBlock(2, around) = %v, want the if statement %v", got, want)
+	}
+	want = Range{Start: e.LineStartOffset(2), End: e.LineStartOffset(3)}
+	if got := e.IndentBlock(2, false); got != want {
+		t.Errorf("IndentBlock(2) = %v, want the if body %v", got, want)
+	}
+}
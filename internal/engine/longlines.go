@@ -0,0 +1,41 @@
+package engine
+
+import "github.com/dshills/keystorm/internal/engine/buffer"
+
+// MaxLineScanLength returns the line length in bytes beyond which a line is
+// considered long.
+func (e *Engine) MaxLineScanLength() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.maxLineScanLength
+}
+
+// HasLongLines returns true if any line is longer than the maximum line
+// scan length. The longest line is tracked by the rope, so this is O(1).
+func (e *Engine) HasLongLines() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return int(e.buf.Snapshot().Rope().Summary().LongestLine) > e.maxLineScanLength
+}
+
+// LongLineThresholdExceeded returns true if the line is longer than the
+// maximum line scan length. Renderers use it to switch to a degraded mode,
+// such as drawing the line without highlighting or truncating it.
+func (e *Engine) LongLineThresholdExceeded(line int) bool {
+	if line < 0 {
+		return false
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.longLineLocked(e.buf.Snapshot(), uint32(line))
+}
+
+// longLineLocked returns true if the line of snap is longer than the
+// maximum line scan length. Caller must hold the lock.
+func (e *Engine) longLineLocked(snap *buffer.Snapshot, line uint32) bool {
+	if line >= snap.LineCount() {
+		return false
+	}
+	return snap.LineLen(line) > e.maxLineScanLength
+}
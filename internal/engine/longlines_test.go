@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLongLineThreshold(t *testing.T) {
+	e := New(WithContent("short\n"+strings.Repeat("x", 20)+"\n(a)"), WithMaxLineScanLength(10))
+
+	if got := e.MaxLineScanLength(); got != 10 {
+		t.Errorf("MaxLineScanLength() = %d, want 10", got)
+	}
+	if !e.HasLongLines() {
+		t.Error("HasLongLines() = false, want true")
+	}
+	for line, want := range []bool{false, true, false} {
+		if got := e.LongLineThresholdExceeded(line); got != want {
+			t.Errorf("LongLineThresholdExceeded(%d) = %v, want %v", line, got, want)
+		}
+	}
+	if e.LongLineThresholdExceeded(-1) || e.LongLineThresholdExceeded(3) {
+		t.Error("LongLineThresholdExceeded() = true for a line outside the buffer")
+	}
+
+	// Shortening the line clears the flag
+	if err := e.Delete(10, 26); err != nil {
+		t.Fatal(err)
+	}
+	if e.HasLongLines() {
+		t.Errorf("HasLongLines() = true after shortening, text %q", e.Text())
+	}
+
+	if New(WithContent("abc")).MaxLineScanLength() != DefaultMaxLineScanLength {
+		t.Error("MaxLineScanLength() should default to DefaultMaxLineScanLength")
+	}
+}
+
+func TestLongLineSkipsScanning(t *testing.T) {
+	long := "\"" + strings.Repeat("(", 20)
+	e := New(WithContent("(a)\n"+long+"\n\"b\""), WithMaxLineScanLength(10))
+
+	tok := &stringTokenizer{}
+	e.SetTokenizer(tok)
+	if got := tok.reset(); len(got) != 2 {
+		t.Errorf("tokenized %q, want the long line skipped", got)
+	}
+	// The long line's open quote is not seen, so the last line is a string
+	if got := stringLines(e); len(got) != 1 || got[0] != 2 {
+		t.Errorf("string lines = %v, want [2]", got)
+	}
+
+	if _, ok := e.MatchBracket(5); ok {
+		t.Error("MatchBracket() on a long line should not match")
+	}
+	if got, ok := e.MatchBracket(0); !ok || got != 2 {
+		t.Errorf("MatchBracket(0) = %d, %v, want 2, true", got, ok)
+	}
+}
+
+func TestLongLineColumnComputation(t *testing.T) {
+	const size = 10 << 20
+	e := New(WithContent(strings.Repeat("x", size)))
+
+	if !e.HasLongLines() {
+		t.Error("HasLongLines() = false for a 10MB line")
+	}
+
+	start := time.Now()
+	for i := 1; i <= 100; i++ {
+		offset := ByteOffset(size / 100 * i)
+		if got := e.OffsetToPoint(offset); got.Line != 0 || got.Column != uint32(offset) {
+			t.Fatalf("OffsetToPoint(%d) = %+v, want column %d", offset, got, offset)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("100 OffsetToPoint calls on a 10MB line took %v", elapsed)
+	}
+}
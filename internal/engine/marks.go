@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/cursor"
+)
+
+// MarkID identifies a mark. The zero value is never a valid mark.
+type MarkID uint64
+
+// MarkGravity determines where a mark goes when text is inserted exactly
+// at its position.
+type MarkGravity uint8
+
+const (
+	// GravityLeft keeps the mark before text inserted at its position, like
+	// a cursor.
+	GravityLeft MarkGravity = iota
+
+	// GravityRight moves the mark after text inserted at its position.
+	GravityRight
+)
+
+// Mark is a durable buffer position that follows edits, e.g. the location
+// of a diagnostic or a plugin's anchor.
+type Mark struct {
+	ID      MarkID
+	Offset  ByteOffset
+	Gravity MarkGravity
+
+	// Invalidated is set once an edit deletes the text around the mark. The
+	// mark is then clamped to the start of that edit. It stays set even if
+	// the edit is undone.
+	Invalidated bool
+}
+
+// CreateMark creates a mark at offset with left gravity. The offset is
+// clamped to the buffer.
+func (e *Engine) CreateMark(offset int) MarkID {
+	return e.CreateMarkWithGravity(offset, GravityLeft)
+}
+
+// CreateMarkWithGravity creates a mark at offset with the given gravity.
+// The offset is clamped to the buffer.
+func (e *Engine) CreateMarkWithGravity(offset int, gravity MarkGravity) MarkID {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pos := ByteOffset(offset)
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > e.buf.Len() {
+		pos = e.buf.Len()
+	}
+
+	if e.marks == nil {
+		e.marks = make(map[MarkID]*Mark)
+	}
+	e.nextMarkID++
+	e.marks[e.nextMarkID] = &Mark{ID: e.nextMarkID, Offset: pos, Gravity: gravity}
+	return e.nextMarkID
+}
+
+// MarkOffset returns the current offset of a mark. Returns false if the
+// mark does not exist.
+func (e *Engine) MarkOffset(id MarkID) (int, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	m, ok := e.marks[id]
+	if !ok {
+		return 0, false
+	}
+	return int(m.Offset), true
+}
+
+// GetMark returns a copy of a mark. Returns false if the mark does not
+// exist.
+func (e *Engine) GetMark(id MarkID) (Mark, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	m, ok := e.marks[id]
+	if !ok {
+		return Mark{}, false
+	}
+	return *m, true
+}
+
+// RemoveMark removes a mark. Removing an unknown mark is a no-op.
+func (e *Engine) RemoveMark(id MarkID) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.marks, id)
+}
+
+// transformMarks moves marks through an edit. It is registered as a buffer
+// change listener and therefore runs with the engine write lock held.
+func (e *Engine) transformMarks(change buffer.BufferChange) {
+	for _, m := range e.marks {
+		var deleted bool
+		m.Offset, deleted = transformMarkOffset(m.Offset, change.Edit, m.Gravity)
+		if deleted {
+			m.Invalidated = true
+		}
+	}
+}
+
+// transformMarkOffset maps a mark offset through an edit. Offsets strictly
+// inside the replaced range are deleted: they clamp to the edit start and
+// deleted is true. A mark at the edit start keeps its position with left
+// gravity and moves past the new text with right gravity. All other offsets
+// transform like cursors.
+func transformMarkOffset(offset ByteOffset, edit buffer.Edit, gravity MarkGravity) (ByteOffset, bool) {
+	if edit.Range.Start < offset && offset < edit.Range.End {
+		return edit.Range.Start, true
+	}
+	if offset == edit.Range.Start {
+		if gravity == GravityRight {
+			return offset + ByteOffset(len(edit.NewText)), false
+		}
+		return offset, false
+	}
+	return cursor.TransformOffset(offset, edit), false
+}
@@ -0,0 +1,133 @@
+package engine
+
+import "testing"
+
+func TestCreateMark(t *testing.T) {
+	e := New(WithContent("hello world"))
+
+	id := e.CreateMark(6)
+	if id == 0 {
+		t.Fatal("CreateMark returned 0")
+	}
+	if offset, ok := e.MarkOffset(id); !ok || offset != 6 {
+		t.Errorf("MarkOffset = %d, %v, want 6, true", offset, ok)
+	}
+
+	clamped := e.CreateMark(100)
+	if offset, _ := e.MarkOffset(clamped); offset != 11 {
+		t.Errorf("clamped MarkOffset = %d, want 11", offset)
+	}
+
+	e.RemoveMark(id)
+	if _, ok := e.MarkOffset(id); ok {
+		t.Error("mark still present after RemoveMark")
+	}
+	e.RemoveMark(id) // no-op
+}
+
+func TestMarkTransform(t *testing.T) {
+	tests := []struct {
+		name        string
+		gravity     MarkGravity
+		edit        func(e *Engine)
+		want        int
+		invalidated bool
+	}{
+		{
+			name: "insert before",
+			edit: func(e *Engine) { e.Insert(0, "abc") },
+			want: 9,
+		},
+		{
+			name: "insert after",
+			edit: func(e *Engine) { e.Insert(8, "abc") },
+			want: 6,
+		},
+		{
+			name:    "insert at mark left gravity",
+			gravity: GravityLeft,
+			edit:    func(e *Engine) { e.Insert(6, "abc") },
+			want:    6,
+		},
+		{
+			name:    "insert at mark right gravity",
+			gravity: GravityRight,
+			edit:    func(e *Engine) { e.Insert(6, "abc") },
+			want:    9,
+		},
+		{
+			name:    "replace from mark left gravity",
+			gravity: GravityLeft,
+			edit:    func(e *Engine) { e.Replace(6, 8, "W") },
+			want:    6,
+		},
+		{
+			name:    "replace from mark right gravity",
+			gravity: GravityRight,
+			edit:    func(e *Engine) { e.Replace(6, 8, "W") },
+			want:    7,
+		},
+		{
+			name: "delete ending at mark",
+			edit: func(e *Engine) { e.Delete(2, 6) },
+			want: 2,
+		},
+		{
+			name: "delete before",
+			edit: func(e *Engine) { e.Delete(0, 2) },
+			want: 4,
+		},
+		{
+			name:        "delete around mark",
+			edit:        func(e *Engine) { e.Delete(4, 8) },
+			want:        4,
+			invalidated: true,
+		},
+		{
+			name:        "replace around mark",
+			gravity:     GravityRight,
+			edit:        func(e *Engine) { e.Replace(5, 9, "XXXXXX") },
+			want:        5,
+			invalidated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent("hello world"))
+			id := e.CreateMarkWithGravity(6, tt.gravity)
+
+			tt.edit(e)
+
+			m, ok := e.GetMark(id)
+			if !ok {
+				t.Fatal("mark removed by edit")
+			}
+			if int(m.Offset) != tt.want {
+				t.Errorf("Offset = %d, want %d", m.Offset, tt.want)
+			}
+			if m.Invalidated != tt.invalidated {
+				t.Errorf("Invalidated = %v, want %v", m.Invalidated, tt.invalidated)
+			}
+		})
+	}
+}
+
+func TestMarkInvalidationPersists(t *testing.T) {
+	e := New(WithContent("hello world"))
+	id := e.CreateMark(6)
+
+	e.Delete(4, 8)
+	e.Insert(0, "ab")
+	if err := e.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+
+	m, _ := e.GetMark(id)
+	if !m.Invalidated {
+		t.Error("Invalidated should remain set after further edits")
+	}
+	if m.Offset != 4 {
+		t.Errorf("Offset = %d, want 4", m.Offset)
+	}
+}
@@ -10,6 +10,10 @@ const (
 	DefaultMaxUndoEntries = 1000
 	DefaultMaxChanges     = 10000
 	DefaultMaxRevisions   = 100
+
+	// DefaultMaxLineScanLength is the line length in bytes beyond which
+	// per-line work such as tokenizing and bracket matching is skipped.
+	DefaultMaxLineScanLength = 10000
 )
 
 // Option configures an Engine during creation.
@@ -47,6 +51,17 @@ func WithMaxUndoEntries(maxEntries int) Option {
 	}
 }
 
+// WithMaxUndoBytes limits the total size of the text retained by undo
+// history. The oldest entries are pruned when the limit is exceeded.
+// Zero (the default) means no memory limit.
+func WithMaxUndoBytes(maxBytes int64) Option {
+	return func(e *Engine) {
+		if maxBytes > 0 {
+			e.maxUndoBytes = maxBytes
+		}
+	}
+}
+
 // WithMaxChanges sets the maximum number of tracked changes.
 func WithMaxChanges(maxChanges int) Option {
 	return func(e *Engine) {
@@ -65,6 +80,17 @@ func WithMaxRevisions(maxRevisions int) Option {
 	}
 }
 
+// WithMaxLineScanLength sets the line length in bytes beyond which a line
+// is considered long. Long lines are not tokenized or scanned for brackets,
+// and LongLineThresholdExceeded reports them so renderers can degrade.
+func WithMaxLineScanLength(n int) Option {
+	return func(e *Engine) {
+		if n > 0 {
+			e.maxLineScanLength = n
+		}
+	}
+}
+
 // WithReadOnly creates a read-only engine.
 // Write operations will return ErrReadOnly.
 func WithReadOnly() Option {
@@ -0,0 +1,243 @@
+package engine
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dshills/keystorm/internal/engine/cursor"
+	"github.com/dshills/keystorm/internal/textwidth"
+)
+
+// PasteLinewise pastes text as whole lines below (after) or above the line
+// of each cursor. A missing final newline is added, so the text always
+// becomes lines of its own, and several cursors on one line paste once.
+// The cursors are moved to the start of the first pasted line of each
+// paste. All pastes are undone together.
+func (e *Engine) PasteLinewise(cursors *cursor.CursorSet, text string, after bool) error {
+	if text == "" || cursors == nil || cursors.Count() == 0 {
+		return nil
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	pastedLines := uint32(strings.Count(text, "\n"))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.readOnly {
+		return ErrReadOnly
+	}
+
+	lines := e.cursorLinesLocked(cursors)
+	lastLine := e.buf.LineCount() - 1
+
+	// Edits are built top down and applied in reverse order
+	edits := make([]Edit, 0, len(lines))
+	for _, line := range lines {
+		switch {
+		case !after:
+			offset := e.buf.LineStartOffset(line)
+			edits = append(edits, Edit{Range: Range{Start: offset, End: offset}, NewText: text})
+		case line < lastLine:
+			offset := e.buf.LineStartOffset(line + 1)
+			edits = append(edits, Edit{Range: Range{Start: offset, End: offset}, NewText: text})
+		default:
+			// Below the last line the text starts a new line instead of
+			// ending one
+			offset := e.buf.Len()
+			edits = append(edits, Edit{Range: Range{Start: offset, End: offset}, NewText: "\n" + strings.TrimSuffix(text, "\n")})
+		}
+	}
+	reverseEdits(edits)
+	if err := e.applyGroupedEditsLocked("Paste", edits); err != nil {
+		return err
+	}
+
+	// Each paste moves the lines below it down
+	selections := make([]Selection, len(lines))
+	for i, line := range lines {
+		first := line + uint32(i)*pastedLines
+		if after {
+			first++
+		}
+		selections[i] = cursor.NewCursorSelection(e.buf.LineStartOffset(first))
+	}
+	cursors.SetAll(selections)
+	return nil
+}
+
+// PasteBlockwise pastes lines as a block: each line is inserted at the same
+// display column on consecutive rows, starting at the row of each cursor,
+// at the cursor's column or after the character under it. Columns count
+// tabs to the next tab stop and wide characters twice, so the block lines
+// up on screen. Rows shorter than the column, or with a tab or wide
+// character across it, are padded with spaces, and rows past the end of
+// the buffer are added. The cursors are moved to the top-left corner of
+// their block. All pastes are undone together.
+func (e *Engine) PasteBlockwise(cursors *cursor.CursorSet, lines []string, after bool) error {
+	if len(lines) == 0 || cursors == nil || cursors.Count() == 0 {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.readOnly {
+		return ErrReadOnly
+	}
+
+	lineCount := e.buf.LineCount()
+	tabWidth := e.buf.TabWidth()
+
+	// Rows past the end of the buffer are built up as text and appended at
+	// once, so that blocks of several cursors can share them
+	var added []string
+
+	var edits []Edit
+	corners := make([]int, 0, cursors.Count())
+	for _, sel := range sortedSelections(cursors) {
+		point := e.buf.OffsetToPoint(sel.Head)
+		byteColumn := int(point.Column)
+		if after && byteColumn < e.buf.LineLen(point.Line) {
+			_, size := e.buf.RuneAt(sel.Head)
+			byteColumn += size
+		}
+		column := displayWidth(e.buf.LineText(point.Line)[:byteColumn], tabWidth)
+
+		corners = append(corners, len(edits))
+		for i, text := range lines {
+			row := point.Line + uint32(i)
+			if row >= lineCount {
+				j := int(row - lineCount)
+				for len(added) <= j {
+					added = append(added, "")
+				}
+				added[j] = spliceColumn(added[j], column, text, tabWidth)
+				continue
+			}
+
+			index, padding := columnIndex(e.buf.LineText(row), column, tabWidth)
+			offset := e.buf.LineStartOffset(row) + ByteOffset(index)
+			edits = append(edits, Edit{Range: Range{Start: offset, End: offset}, NewText: padding + text})
+		}
+	}
+
+	// Corners are the insert points of the first rows, after any padding
+	cornerOffsets := make([]ByteOffset, len(corners))
+	for i, index := range corners {
+		edit := edits[index]
+		cornerOffsets[i] = edit.Range.Start + ByteOffset(len(edit.NewText)-len(lines[0]))
+	}
+
+	if len(added) > 0 {
+		offset := e.buf.Len()
+		edits = append(edits, Edit{Range: Range{Start: offset, End: offset}, NewText: "\n" + strings.Join(added, "\n")})
+	}
+
+	// Sort top down, keeping the order of inserts at the same offset, and
+	// shift each corner by the text inserted before it
+	order := make([]int, len(edits))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return edits[order[a]].Range.Start < edits[order[b]].Range.Start
+	})
+	shifts := make([]ByteOffset, len(edits))
+	sorted := make([]Edit, len(edits))
+	var shift ByteOffset
+	for i, index := range order {
+		shifts[index] = shift
+		sorted[i] = edits[index]
+		shift += ByteOffset(len(edits[index].NewText))
+	}
+
+	reverseEdits(sorted)
+	if err := e.applyGroupedEditsLocked("Paste", sorted); err != nil {
+		return err
+	}
+
+	selections := make([]Selection, len(corners))
+	for i, index := range corners {
+		selections[i] = cursor.NewCursorSelection(cornerOffsets[i] + shifts[index])
+	}
+	cursors.SetAll(selections)
+	return nil
+}
+
+// cursorLinesLocked returns the distinct lines of the cursors, top down.
+func (e *Engine) cursorLinesLocked(cursors *cursor.CursorSet) []uint32 {
+	var lines []uint32
+	for _, sel := range sortedSelections(cursors) {
+		line := e.buf.OffsetToPoint(sel.Head).Line
+		if len(lines) == 0 || lines[len(lines)-1] != line {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// sortedSelections returns the selections of cursors ordered by head.
+func sortedSelections(cursors *cursor.CursorSet) []Selection {
+	selections := cursors.All()
+	sort.SliceStable(selections, func(i, j int) bool {
+		return selections[i].Head < selections[j].Head
+	})
+	return selections
+}
+
+// spliceColumn inserts text into line at display column column, padding
+// with spaces as columnIndex does.
+func spliceColumn(line string, column int, text string, tabWidth int) string {
+	index, padding := columnIndex(line, column, tabWidth)
+	return line[:index] + padding + text + line[index:]
+}
+
+// columnIndex returns the byte index in line where display column column
+// starts, and the spaces to insert there to reach it. The padding is only
+// needed when line is shorter than column or when a tab or wide character
+// spans it; the index is then before that character.
+func columnIndex(line string, column, tabWidth int) (int, string) {
+	width := 0
+	for i, r := range line {
+		if width >= column {
+			return i, ""
+		}
+		next := width + runeWidth(r, width, tabWidth)
+		if next > column {
+			return i, strings.Repeat(" ", column-width)
+		}
+		width = next
+	}
+	return len(line), strings.Repeat(" ", max(column-width, 0))
+}
+
+// displayWidth returns the number of screen columns text takes up when it
+// starts a line.
+func displayWidth(text string, tabWidth int) int {
+	width := 0
+	for _, r := range text {
+		width += runeWidth(r, width, tabWidth)
+	}
+	return width
+}
+
+// runeWidth returns the screen columns r takes up at display column
+// column. Tabs reach the next multiple of tabWidth.
+func runeWidth(r rune, column, tabWidth int) int {
+	if r == '\t' {
+		if tabWidth <= 0 {
+			tabWidth = DefaultTabWidth
+		}
+		return tabWidth - column%tabWidth
+	}
+	return textwidth.RuneWidth(r)
+}
+
+// reverseEdits reverses edits in place.
+func reverseEdits(edits []Edit) {
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+}
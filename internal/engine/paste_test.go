@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/dshills/keystorm/internal/engine/cursor"
+)
+
+func TestPasteLinewise(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		offsets []ByteOffset
+		text    string
+		after   bool
+		want    string
+		cursors []ByteOffset
+	}{
+		{"after", "one\ntwo\nthree", []ByteOffset{1}, "new\n", true, "one\nnew\ntwo\nthree", []ByteOffset{4}},
+		{"before", "one\ntwo\nthree", []ByteOffset{5}, "new\n", false, "one\nnew\ntwo\nthree", []ByteOffset{4}},
+		{"after last line", "one\ntwo", []ByteOffset{5}, "new\n", true, "one\ntwo\nnew", []ByteOffset{8}},
+		{"missing newline", "one\ntwo", []ByteOffset{0}, "new", true, "one\nnew\ntwo", []ByteOffset{4}},
+		{"several lines", "one\ntwo", []ByteOffset{0}, "a\nb\n", false, "a\nb\none\ntwo", []ByteOffset{0}},
+		{"multiple cursors", "one\ntwo\nthree", []ByteOffset{0, 4}, "x\n", true, "one\nx\ntwo\nx\nthree", []ByteOffset{4, 10}},
+		{"cursors on one line", "one\ntwo", []ByteOffset{0, 2}, "x\n", true, "one\nx\ntwo", []ByteOffset{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(tt.content))
+			cursors := cursorsAt(tt.offsets...)
+
+			if err := e.PasteLinewise(cursors, tt.text, tt.after); err != nil {
+				t.Fatalf("PasteLinewise() error = %v", err)
+			}
+			if got := e.Text(); got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+			assertCursorOffsets(t, cursors, tt.cursors)
+		})
+	}
+}
+
+func TestPasteBlockwise(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		offsets []ByteOffset
+		lines   []string
+		after   bool
+		want    string
+		cursors []ByteOffset
+	}{
+		{"before", "abcd\nefgh", []ByteOffset{1}, []string{"X", "Y"}, false, "aXbcd\neYfgh", []ByteOffset{1}},
+		{"after", "abcd\nefgh", []ByteOffset{1}, []string{"X", "Y"}, true, "abXcd\nefYgh", []ByteOffset{2}},
+		{"pads short lines", "abcd\na\nabcd", []ByteOffset{3}, []string{"1", "2", "3"}, false, "abc1d\na  2\nabc3d", []ByteOffset{3}},
+		{"extends buffer", "abcd\nab", []ByteOffset{2}, []string{"1", "2", "3"}, false, "ab1cd\nab2\n  3", []ByteOffset{2}},
+		{"multiple cursors", "abcd\nefgh\nijkl\nmnop", []ByteOffset{0, 12}, []string{"X", "Y"}, false, "Xabcd\nYefgh\nijXkl\nmnYop", []ByteOffset{0, 14}},
+		{"display column after tab", "\tab\nabcdefgh", []ByteOffset{1}, []string{"X", "Y"}, false, "\tXab\nabcdYefgh", []ByteOffset{1}},
+		{"display column after wide characters", "日本\nabcd", []ByteOffset{3}, []string{"X", "Y"}, false, "日X本\nabYcd", []ByteOffset{3}},
+		{"pads before tab spanning column", "ab\n\tcd", []ByteOffset{1}, []string{"X", "Y"}, false, "aXb\n Y\tcd", []ByteOffset{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(tt.content))
+			cursors := cursorsAt(tt.offsets...)
+
+			if err := e.PasteBlockwise(cursors, tt.lines, tt.after); err != nil {
+				t.Fatalf("PasteBlockwise() error = %v", err)
+			}
+			if got := e.Text(); got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+			assertCursorOffsets(t, cursors, tt.cursors)
+		})
+	}
+}
+
+func TestPasteUndo(t *testing.T) {
+	content := "abcd\nab"
+	e := New(WithContent(content))
+
+	if err := e.PasteBlockwise(cursorsAt(0, 6), []string{"1", "2", "3"}, true); err != nil {
+		t.Fatalf("PasteBlockwise() error = %v", err)
+	}
+	if err := e.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if got := e.Text(); got != content {
+		t.Errorf("text after undo = %q, want %q", got, content)
+	}
+
+	e = New(WithContent(content), WithReadOnly())
+	if err := e.PasteLinewise(cursorsAt(0), "x", true); err != ErrReadOnly {
+		t.Errorf("PasteLinewise() on read-only engine error = %v, want ErrReadOnly", err)
+	}
+}
+
+// cursorsAt returns a cursor set with a cursor at each offset.
+func cursorsAt(offsets ...ByteOffset) *cursor.CursorSet {
+	selections := make([]Selection, len(offsets))
+	for i, offset := range offsets {
+		selections[i] = cursor.NewCursorSelection(offset)
+	}
+	return cursor.NewCursorSetFromSlice(selections)
+}
+
+func assertCursorOffsets(t *testing.T, cursors *cursor.CursorSet, want []ByteOffset) {
+	t.Helper()
+	got := cursors.All()
+	if len(got) != len(want) {
+		t.Fatalf("cursors = %v, want %v", got, want)
+	}
+	for i, sel := range got {
+		if sel.Head != want[i] {
+			t.Errorf("cursor %d = %d, want %d", i, sel.Head, want[i])
+		}
+	}
+}
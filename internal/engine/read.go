@@ -0,0 +1,107 @@
+package engine
+
+import "github.com/dshills/keystorm/internal/engine/buffer"
+
+// ReadView is a consistent, read-only view of the buffer, valid only for the
+// duration of an Engine.Read callback. All reads through one view observe
+// the same revision.
+type ReadView struct {
+	buf    *buffer.Buffer
+	closed bool
+}
+
+// Read calls fn with a ReadView while holding the engine read lock, so that
+// several reads (e.g. Text followed by OffsetToPoint) see the same buffer
+// state. Writers block until fn returns. Read returns the error from fn.
+//
+// fn must not call Engine write methods (Insert, Delete, Undo, ...): they
+// wait for the read lock to be released and therefore deadlock. The view
+// must not be retained; using it after fn returns panics.
+func (e *Engine) Read(fn func(v *ReadView) error) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	v := &ReadView{buf: e.buf}
+	defer func() { v.closed = true }()
+	return fn(v)
+}
+
+// check panics if the view is used outside its Read callback.
+func (v *ReadView) check() {
+	if v.closed {
+		panic("engine: ReadView used after Read returned")
+	}
+}
+
+// Text returns the entire buffer content.
+func (v *ReadView) Text() string {
+	v.check()
+	return v.buf.Text()
+}
+
+// TextRange returns text in the given byte range.
+func (v *ReadView) TextRange(start, end ByteOffset) string {
+	v.check()
+	return v.buf.TextRange(start, end)
+}
+
+// Len returns the total byte length of the buffer.
+func (v *ReadView) Len() ByteOffset {
+	v.check()
+	return v.buf.Len()
+}
+
+// LineCount returns the number of lines.
+func (v *ReadView) LineCount() uint32 {
+	v.check()
+	return v.buf.LineCount()
+}
+
+// LineText returns the text of a specific line (without newline).
+func (v *ReadView) LineText(line uint32) string {
+	v.check()
+	return v.buf.LineText(line)
+}
+
+// LineLen returns the length of a specific line in bytes (without newline).
+func (v *ReadView) LineLen(line uint32) int {
+	v.check()
+	return v.buf.LineLen(line)
+}
+
+// LineStartOffset returns the byte offset of the start of a line.
+func (v *ReadView) LineStartOffset(line uint32) ByteOffset {
+	v.check()
+	return v.buf.LineStartOffset(line)
+}
+
+// LineEndOffset returns the byte offset of the end of a line, before its terminator.
+func (v *ReadView) LineEndOffset(line uint32) ByteOffset {
+	v.check()
+	return v.buf.LineEndOffset(line)
+}
+
+// OffsetToPoint converts a byte offset to line/column.
+func (v *ReadView) OffsetToPoint(offset ByteOffset) Point {
+	v.check()
+	return v.buf.OffsetToPoint(offset)
+}
+
+// PointToOffset converts line/column to byte offset.
+func (v *ReadView) PointToOffset(point Point) ByteOffset {
+	v.check()
+	return v.buf.PointToOffset(point)
+}
+
+// RevisionID returns the revision the view observes.
+func (v *ReadView) RevisionID() RevisionID {
+	v.check()
+	return v.buf.RevisionID()
+}
+
+// Snapshot returns an immutable snapshot of the viewed state. Unlike the
+// view itself, the snapshot may be kept after Read returns.
+func (v *ReadView) Snapshot() *buffer.Snapshot {
+	v.check()
+	return v.buf.Snapshot()
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRead(t *testing.T) {
+	e := New(WithContent("hello\nworld"))
+
+	err := e.Read(func(v *ReadView) error {
+		if got := v.Text(); got != "hello\nworld" {
+			t.Errorf("Text() = %q, want %q", got, "hello\nworld")
+		}
+		if got := v.LineText(1); got != "world" {
+			t.Errorf("LineText(1) = %q, want %q", got, "world")
+		}
+		if got := v.OffsetToPoint(8); got != (Point{Line: 1, Column: 2}) {
+			t.Errorf("OffsetToPoint(8) = %v, want {1 2}", got)
+		}
+		if got := v.Snapshot().Text(); got != v.Text() {
+			t.Errorf("Snapshot().Text() = %q, want %q", got, v.Text())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	want := errors.New("stop")
+	if err := e.Read(func(*ReadView) error { return want }); err != want {
+		t.Errorf("Read() error = %v, want %v", err, want)
+	}
+}
+
+func TestReadBlocksWriters(t *testing.T) {
+	e := New(WithContent("hello"))
+
+	wrote := make(chan struct{})
+	err := e.Read(func(v *ReadView) error {
+		before := v.Text()
+		go func() {
+			e.Insert(0, "X")
+			close(wrote)
+		}()
+
+		select {
+		case <-wrote:
+			t.Error("writer completed during read transaction")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		if got := v.Text(); got != before {
+			t.Errorf("Text() = %q during read, want %q", got, before)
+		}
+		if got := v.Len(); got != ByteOffset(len(before)) {
+			t.Errorf("Len() = %d during read, want %d", got, len(before))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	<-wrote
+	if got := e.Text(); got != "Xhello" {
+		t.Errorf("Text() after read = %q, want %q", got, "Xhello")
+	}
+}
+
+func TestReadViewUseAfterReturn(t *testing.T) {
+	e := New(WithContent("hello"))
+
+	var kept *ReadView
+	_ = e.Read(func(v *ReadView) error {
+		kept = v
+		return nil
+	})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic using ReadView after Read returned")
+		}
+	}()
+	kept.Text()
+}
@@ -192,6 +192,45 @@ func BenchmarkDeleteMiddle(b *testing.B) {
 	}
 }
 
+// Benchmarks for batch edits
+
+// scatteredEdits returns n non-overlapping edits spread over size bytes,
+// ordered by offset.
+func scatteredEdits(size, n int) []Edit {
+	edits := make([]Edit, n)
+	stride := size / n
+	for i := range edits {
+		start := ByteOffset(i*stride + rand.Intn(stride-2))
+		edits[i] = Edit{Start: start, End: start + 2, Text: "xyz"}
+	}
+	return edits
+}
+
+func BenchmarkApplyEdits(b *testing.B) {
+	size := 100000
+	r := FromString(generateText(size))
+	edits := scatteredEdits(size, 1000)
+
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := r.ApplyEdits(edits); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("sequential", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			result := r
+			for j := len(edits) - 1; j >= 0; j-- {
+				result = result.Replace(edits[j].Start, edits[j].End, edits[j].Text)
+			}
+		}
+	})
+}
+
 // Benchmarks for concatenation
 
 func BenchmarkConcat(b *testing.B) {
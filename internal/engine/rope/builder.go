@@ -54,6 +54,13 @@ func (b *Builder) WriteRune(r rune) (int, error) {
 	return n, err
 }
 
+// writeChunk appends an existing chunk without copying its text.
+func (b *Builder) writeChunk(c Chunk) {
+	b.flushBuffer()
+	b.chunks = append(b.chunks, c)
+	b.totalLen += c.Len()
+}
+
 // flushBuffer converts the buffer contents to chunks.
 func (b *Builder) flushBuffer() {
 	if b.buffer.Len() == 0 {
@@ -1,6 +1,9 @@
 package rope
 
-import "unicode/utf8"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
 // Cursor enables efficient traversal of a rope.
 // It maintains a path from root to the current position, allowing
@@ -82,36 +85,37 @@ func (c *Cursor) Point() Point {
 
 // computePoint calculates the current line/column from the path.
 func (c *Cursor) computePoint() {
-	c.point = Point{Line: 0, Column: 0}
+	c.point = Point{Line: c.computeLine()}
+
+	// Calculate column (bytes from last newline)
+	c.point.Column = c.computeColumn()
+	c.pointSet = true
+}
+
+// computeLine calculates the current line from the path.
+func (c *Cursor) computeLine() uint32 {
+	var line uint32
 
 	// Sum up lines from path
 	for _, frame := range c.path {
 		for i := 0; i < frame.childIdx; i++ {
-			c.point.Line += frame.node.childSummaries[i].Lines
+			line += frame.node.childSummaries[i].Lines
 		}
 	}
 
 	// Count lines in current leaf up to current position
 	if c.leafNode != nil {
 		for i := 0; i < c.chunkIdx; i++ {
-			c.point.Line += c.leafNode.chunks[i].Summary().Lines
+			line += c.leafNode.chunks[i].Summary().Lines
 		}
 
 		// Count lines within current chunk up to current offset
 		if c.chunkIdx < len(c.leafNode.chunks) {
 			chunk := c.leafNode.chunks[c.chunkIdx]
-			text := chunk.String()[:c.chunkOff]
-			for _, ch := range text {
-				if ch == '\n' {
-					c.point.Line++
-				}
-			}
+			line += uint32(strings.Count(chunk.String()[:c.chunkOff], "\n"))
 		}
 	}
-
-	// Calculate column (bytes from last newline)
-	c.point.Column = c.computeColumn()
-	c.pointSet = true
+	return line
 }
 
 // computeColumn calculates the column (bytes from the last newline).
@@ -157,18 +161,10 @@ func (c *Cursor) LineStartOffset() ByteOffset {
 			}
 		}
 
-		// Not found in current leaf - fall back to rope-level search
-		// This is O(log n) per byte but only for cross-leaf searches
-		searchOffset := chunkStart
-		for searchOffset > 0 {
-			b, ok := c.rope.ByteAt(searchOffset - 1)
-			if !ok {
-				break
-			}
-			if b == '\n' {
-				return searchOffset
-			}
-			searchOffset--
+		// Not found in current leaf - look the line up in the tree, which
+		// is O(log n) however long the line is
+		if line := c.computeLine(); line > 0 {
+			return c.rope.LineStartOffset(line)
 		}
 	}
 
@@ -550,6 +546,21 @@ func (c *Cursor) Prev() bool {
 		return false
 	}
 
+	// Within the current chunk, step back without seeking
+	if c.leafNode != nil && c.chunkIdx < len(c.leafNode.chunks) && c.chunkOff > 0 {
+		r, size := utf8.DecodeLastRuneInString(c.leafNode.chunks[c.chunkIdx].String()[:c.chunkOff])
+		c.offset -= ByteOffset(size)
+		c.chunkOff -= size
+		if c.pointSet {
+			if r == '\n' {
+				c.pointSet = false
+			} else {
+				c.point.Column -= uint32(size)
+			}
+		}
+		return true
+	}
+
 	// Find the previous rune by looking at the byte before current position
 	prevOffset := c.offset - 1
 
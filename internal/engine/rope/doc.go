@@ -34,6 +34,17 @@
 //	text := r.String()  // full text
 //	slice := r.Slice(0, 4)  // "univ"
 //
+// # Batch Edits
+//
+// ApplyEdits applies many edits at once. The ranges all refer to the
+// original rope, and the result is built in a single pass that reuses the
+// unchanged chunks instead of creating a rope per edit:
+//
+//	r, err := r.ApplyEdits([]rope.Edit{
+//	    {Start: 0, End: 0, Text: "// "},
+//	    {Start: 20, End: 25, Text: "value"},
+//	})  // err is ErrEditsOverlap if two ranges overlap
+//
 // # Immutability
 //
 // All operations return new ropes without modifying the original:
@@ -46,6 +57,23 @@
 //
 // This enables cheap snapshots and safe concurrent access.
 //
+// Freeze marks a rope as a snapshot and Verify checks that every cached
+// summary matches the text below it. Building with -tags ropedebug makes
+// Freeze verify the tree and panic if one of its nodes is later recycled
+// through the pool or has its summary recomputed:
+//
+//	snap := r.Freeze()
+//	if err := snap.Verify(); err != nil {
+//	    // err wraps ErrCorrupt and names the bad node
+//	}
+//
+// SameAs and ContentHash let callers key caches by rope content. SameAs is
+// O(1) for a rope and its unchanged snapshots, which share a root:
+//
+//	if tokens != nil && cachedRope.SameAs(current) {
+//	    return tokens // content unchanged
+//	}
+//
 // # Line Operations
 //
 // The rope efficiently tracks line information:
@@ -58,6 +86,11 @@
 //	// Get line text
 //	text := r.LineText(1)  // "line 2"
 //
+//	// LineText excludes the terminator ("\n" or "\r\n"); LineTextRaw keeps
+//	// it and LineTerminator reports it
+//	raw := r.LineTextRaw(1)     // "line 2\n"
+//	term := r.LineTerminator(2) // "" (last line)
+//
 //	// Get line offsets
 //	start := r.LineStartOffset(1)  // 7
 //	end := r.LineEndOffset(1)      // 13
@@ -142,6 +175,8 @@
 //   - String:          O(n)
 //   - Len:             O(1)
 //   - LineCount:       O(1)
+//   - ContentHash:     O(1)
+//   - SameAs:          O(1) with a shared root, otherwise O(n)
 //
 // # Memory Efficiency
 //
@@ -0,0 +1,96 @@
+package rope
+
+import "sort"
+
+// Edit replaces the byte range [Start, End) with Text.
+// An empty range inserts, an empty Text deletes.
+type Edit struct {
+	Start ByteOffset
+	End   ByteOffset
+	Text  string
+}
+
+// ApplyEdits applies a batch of edits in a single pass and returns the
+// resulting rope; the original is unchanged. All ranges refer to the
+// original rope, so the edits may be given in any order. Inserts at the
+// same offset are applied in the order given.
+//
+// Unlike a sequence of Insert and Delete calls, no intermediate ropes are
+// built: unchanged chunks are carried over as they are and only the text
+// around each edit is copied.
+//
+// Returns ErrEditOutOfRange if a range is invalid and ErrEditsOverlap if
+// two ranges overlap.
+func (r Rope) ApplyEdits(edits []Edit) (Rope, error) {
+	if len(edits) == 0 {
+		return r, nil
+	}
+
+	length := r.Len()
+	for _, e := range edits {
+		if e.Start > e.End || e.End > length {
+			return r, ErrEditOutOfRange
+		}
+	}
+
+	sorted := make([]Edit, len(edits))
+	copy(sorted, edits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Start != sorted[j].Start {
+			return sorted[i].Start < sorted[j].Start
+		}
+		return sorted[i].End < sorted[j].End
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Start < sorted[i-1].End {
+			return r, ErrEditsOverlap
+		}
+	}
+
+	var b Builder
+	w := editWriter{b: &b, it: r.Chunks()}
+	w.valid = w.it.Next()
+	for _, e := range sorted {
+		w.copyTo(e.Start)
+		b.WriteString(e.Text)
+		w.pos = e.End
+	}
+	w.copyTo(length)
+
+	return b.Build(), nil
+}
+
+// editWriter copies the unchanged text between edits into a builder.
+type editWriter struct {
+	b     *Builder
+	it    *ChunkIterator
+	valid bool
+	pos   ByteOffset
+}
+
+// copyTo copies the text from pos up to end, reusing the chunks that lie
+// entirely within it.
+func (w *editWriter) copyTo(end ByteOffset) {
+	for w.pos < end && w.valid {
+		chunk := w.it.Chunk()
+		chunkStart := w.it.Offset()
+		chunkEnd := chunkStart + ByteOffset(chunk.Len())
+		if chunkEnd <= w.pos {
+			w.valid = w.it.Next()
+			continue
+		}
+
+		from := w.pos - chunkStart
+		to := min(end, chunkEnd) - chunkStart
+		if from == 0 && to == ByteOffset(chunk.Len()) {
+			w.b.writeChunk(chunk)
+		} else {
+			w.b.WriteString(chunk.String()[from:to])
+		}
+
+		w.pos = chunkStart + to
+		if w.pos == chunkEnd {
+			w.valid = w.it.Next()
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package rope
+
+import "errors"
+
+// Sentinel errors for rope operations.
+var (
+	// ErrEditsOverlap indicates that edits in a batch overlap.
+	ErrEditsOverlap = errors.New("edits overlap")
+
+	// ErrEditOutOfRange indicates an edit range outside the rope.
+	ErrEditOutOfRange = errors.New("edit range out of bounds")
+
+	// ErrCorrupt indicates a rope whose cached metrics do not match its
+	// contents.
+	ErrCorrupt = errors.New("rope corrupt")
+)
@@ -0,0 +1,46 @@
+//go:build ropedebug
+
+package rope
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"weak"
+)
+
+// frozenNodes records the nodes reachable from frozen ropes. Weak keys
+// let frozen nodes be collected; a cleanup removes their entries.
+var frozenNodes sync.Map // weak.Pointer[Node] -> struct{}
+
+// freezeNodes verifies the subtree rooted at n and records its nodes as
+// frozen. It panics if the tree is corrupt.
+func freezeNodes(n *Node) {
+	if err := (Rope{root: n}).Verify(); err != nil {
+		panic(fmt.Sprintf("rope: Freeze: %v", err))
+	}
+	markFrozen(n)
+}
+
+// markFrozen records n and its descendants. Subtrees that are already
+// frozen, such as those shared with an earlier snapshot, are skipped.
+func markFrozen(n *Node) {
+	key := weak.Make(n)
+	if _, loaded := frozenNodes.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+	runtime.AddCleanup(n, func(key weak.Pointer[Node]) {
+		frozenNodes.Delete(key)
+	}, key)
+	for _, child := range n.children {
+		markFrozen(child)
+	}
+}
+
+// checkMutable panics if n belongs to a frozen rope. It is called before
+// a node is modified in place.
+func checkMutable(n *Node, op string) {
+	if _, frozen := frozenNodes.Load(weak.Make(n)); frozen {
+		panic(fmt.Sprintf("rope: %s of a node shared with a frozen rope", op))
+	}
+}
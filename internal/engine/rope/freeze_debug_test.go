@@ -0,0 +1,43 @@
+//go:build ropedebug
+
+package rope
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFreezePanicsOnRecycledNode(t *testing.T) {
+	r := FromString(strings.Repeat("abcdefghij\n", 1000)).Freeze()
+
+	// Editing a frozen rope copies the path and leaves its nodes alone
+	edited := r.Insert(500, "new text")
+	if err := edited.Verify(); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	leaf := r.root
+	for !leaf.IsLeaf() {
+		leaf = leaf.children[0]
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("PutLeaf of a frozen node did not panic")
+		}
+	}()
+	DefaultPool.PutLeaf(leaf)
+}
+
+func TestFreezePanicsOnCorruptRope(t *testing.T) {
+	r := FromString("hello\nworld")
+	root := r.root.clone()
+	root.summary.Lines = 5
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Freeze of a corrupt rope did not panic")
+		}
+	}()
+	Rope{root: root}.Freeze()
+}
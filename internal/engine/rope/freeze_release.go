@@ -0,0 +1,9 @@
+//go:build !ropedebug
+
+package rope
+
+// freezeNodes does nothing without the ropedebug build tag.
+func freezeNodes(*Node) {}
+
+// checkMutable does nothing without the ropedebug build tag.
+func checkMutable(*Node, string) {}
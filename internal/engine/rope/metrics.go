@@ -35,8 +35,19 @@ type TextSummary struct {
 
 	// Flags indicate text properties for fast paths.
 	Flags TextFlags
+
+	// Hash is a polynomial hash of the text. It depends only on the
+	// content, not on how the text is split into chunks.
+	Hash uint64
+
+	// hashPow is hashBase raised to the byte count, used to combine hashes.
+	hashPow uint64
 }
 
+// hashBase is the multiplier of the polynomial content hash (the 64-bit
+// FNV prime). Arithmetic is modulo 2^64.
+const hashBase = 0x100000001b3
+
 // TextFlags indicate text properties for optimization fast paths.
 type TextFlags uint8
 
@@ -66,6 +77,8 @@ func (s TextSummary) Add(other TextSummary) TextSummary {
 		UTF16Units: s.UTF16Units + other.UTF16Units,
 		Lines:      s.Lines + other.Lines,
 		Flags:      s.Flags & other.Flags, // AND for flags (all must have property)
+		Hash:       s.Hash*other.hashPow + other.Hash,
+		hashPow:    s.hashPow * other.hashPow,
 	}
 
 	// Update line length tracking
@@ -116,6 +129,7 @@ func ComputeSummary(s string) TextSummary {
 	var sum TextSummary
 	sum.Bytes = ByteOffset(len(s))
 	sum.Flags = FlagASCII // Start optimistic
+	sum.Hash, sum.hashPow = hashBytes(s)
 
 	var lineLen uint32
 
@@ -163,6 +177,17 @@ func ComputeSummary(s string) TextSummary {
 	return sum
 }
 
+// hashBytes returns the polynomial hash of s and hashBase raised to len(s).
+// Bytes are offset by one so that leading NUL bytes change the hash.
+func hashBytes(s string) (hash, pow uint64) {
+	pow = 1
+	for i := 0; i < len(s); i++ {
+		hash = hash*hashBase + uint64(s[i]) + 1
+		pow *= hashBase
+	}
+	return hash, pow
+}
+
 // CountLines returns the number of newlines in a string.
 func CountLines(s string) uint32 {
 	var count uint32
@@ -44,14 +44,20 @@ func newLeafNodeWithChunks(chunks []Chunk) *Node {
 	return n
 }
 
-// newInternalNode creates an internal node with the given children.
+// newInternalNode creates an internal node with the given children, one
+// level taller than the tallest child.
 // Uses the default pool for efficient allocation.
 func newInternalNode(children []*Node) *Node {
 	if len(children) == 0 {
 		return newLeafNode()
 	}
 
-	height := children[0].height + 1
+	// Siblings may differ in height after edits; the node must be taller
+	// than all of them
+	var height uint8
+	for _, child := range children {
+		height = max(height, child.height+1)
+	}
 	n := DefaultPool.GetInternal(height)
 
 	var total TextSummary
@@ -82,6 +88,7 @@ func (n *Node) LineCount() uint32 {
 
 // recomputeSummary recalculates the summary from children or chunks.
 func (n *Node) recomputeSummary() {
+	checkMutable(n, "recomputeSummary")
 	if n.IsLeaf() {
 		n.summary = TextSummary{Flags: FlagASCII}
 		for _, chunk := range n.chunks {
@@ -72,6 +72,7 @@ func (p *NodePool) PutLeaf(n *Node) {
 	if n == nil || !n.IsLeaf() {
 		return
 	}
+	checkMutable(n, "PutLeaf")
 	// Clear references to allow GC of chunk data
 	for i := range n.chunks {
 		n.chunks[i] = Chunk{}
@@ -86,6 +87,7 @@ func (p *NodePool) PutInternal(n *Node) {
 	if n == nil || n.IsLeaf() {
 		return
 	}
+	checkMutable(n, "PutInternal")
 	// Clear references to allow GC of children
 	for i := range n.children {
 		n.children[i] = nil
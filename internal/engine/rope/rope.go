@@ -249,6 +249,22 @@ func (r Rope) Split(offset ByteOffset) (Rope, Rope) {
 	return Rope{root: leftRoot}, Rope{root: rightRoot}
 }
 
+// SubRope returns the rope covering the byte range [start, end).
+// The result shares structure with the original, so extraction costs
+// O(log n) regardless of range size. End is clamped to the rope length.
+func (r Rope) SubRope(start, end ByteOffset) Rope {
+	if length := r.Len(); end > length {
+		end = length
+	}
+	if r.root == nil || start >= end {
+		return New()
+	}
+
+	_, right := r.Split(start)
+	mid, _ := right.Split(end - start)
+	return mid
+}
+
 // Concat concatenates two ropes.
 // Returns a new rope; originals are unchanged.
 func (r Rope) Concat(other Rope) Rope {
@@ -290,8 +306,8 @@ func (r Rope) LineStartOffset(line uint32) ByteOffset {
 	return r.Len()
 }
 
-// LineEndOffset returns the byte offset of the end of the given line
-// (not including the newline character).
+// LineEndOffset returns the byte offset of the end of the given line, not
+// including its terminator. For a CRLF line this is the offset of the '\r'.
 func (r Rope) LineEndOffset(line uint32) ByteOffset {
 	if r.root == nil {
 		return 0
@@ -307,18 +323,48 @@ func (r Rope) LineEndOffset(line uint32) ByteOffset {
 		return r.Len()
 	}
 
-	nextLineStart := r.LineStartOffset(line + 1)
-	if nextLineStart > 0 {
-		return nextLineStart - 1
-	}
-	return 0
+	return r.LineStartOffset(line+1) - ByteOffset(r.lineTerminatorLen(line))
 }
 
-// LineText returns the text of the given line (not including newline).
+// LineText returns the text of the given line without its terminator.
+// Both "\n" and "\r\n" terminators are excluded, so CRLF lines never end
+// in a stray carriage return.
 func (r Rope) LineText(line uint32) string {
-	start := r.LineStartOffset(line)
-	end := r.LineEndOffset(line)
-	return r.Slice(start, end)
+	return r.Slice(r.LineStartOffset(line), r.LineEndOffset(line))
+}
+
+// LineTextRaw returns the text of the given line including its terminator,
+// if any. Concatenating LineTextRaw for every line reproduces the rope.
+func (r Rope) LineTextRaw(line uint32) string {
+	return r.Slice(r.LineStartOffset(line), r.LineStartOffset(line+1))
+}
+
+// LineTerminator returns the terminator of the given line: "\r\n", "\n",
+// or "" for the last line and lines out of range.
+func (r Rope) LineTerminator(line uint32) string {
+	switch r.lineTerminatorLen(line) {
+	case 2:
+		return "\r\n"
+	case 1:
+		return "\n"
+	default:
+		return ""
+	}
+}
+
+// lineTerminatorLen returns the byte length of a line's terminator: 2 for
+// "\r\n", 1 for "\n" and 0 for the last line and lines out of range.
+func (r Rope) lineTerminatorLen(line uint32) int {
+	if r.root == nil || line+1 >= r.LineCount() {
+		return 0
+	}
+	newline := r.LineStartOffset(line+1) - 1
+	if newline > r.LineStartOffset(line) {
+		if b, ok := r.ByteAt(newline - 1); ok && b == '\r' {
+			return 2
+		}
+	}
+	return 1
 }
 
 // OffsetToPoint converts a byte offset to a line/column position.
@@ -386,23 +432,59 @@ func countChunks(n *Node) int {
 	return count
 }
 
+// SameAs returns true if two ropes contain the same text. It is O(1) when
+// the ropes share a root, as a rope and its unchanged snapshots do, and when
+// their lengths or content hashes differ; otherwise it compares content.
+// This makes ropes usable as cache keys for derived data such as tokens or
+// layout.
+func (r Rope) SameAs(other Rope) bool {
+	if r.root == other.root {
+		return true
+	}
+	if r.Len() != other.Len() || r.ContentHash() != other.ContentHash() {
+		return false
+	}
+	return r.Equals(other)
+}
+
+// ContentHash returns a hash of the rope's text in O(1). Ropes with equal
+// text have equal hashes regardless of their tree structure. The hash is
+// maintained in node summaries as the rope is edited; it is not
+// collision-resistant, so use SameAs to confirm equality.
+func (r Rope) ContentHash() uint64 {
+	return r.Summary().Hash
+}
+
 // Equals returns true if two ropes contain the same text.
-// Note: This compares content, not structure.
+// Note: This compares content, not structure, so ropes whose text is split
+// into different chunks are equal.
 func (r Rope) Equals(other Rope) bool {
 	if r.Len() != other.Len() {
 		return false
 	}
-	// For efficiency, compare chunk by chunk using iterators
+	// Compare chunk by chunk using iterators, advancing through whichever
+	// chunk ends first
 	iter1 := r.Chunks()
 	iter2 := other.Chunks()
-
-	for iter1.Next() {
-		if !iter2.Next() {
-			return false
+	var s1, s2 string
+	for {
+		if s1 == "" {
+			if !iter1.Next() {
+				break
+			}
+			s1 = iter1.Chunk().String()
+		}
+		if s2 == "" {
+			if !iter2.Next() {
+				break
+			}
+			s2 = iter2.Chunk().String()
 		}
-		if iter1.Chunk().String() != iter2.Chunk().String() {
+		n := min(len(s1), len(s2))
+		if s1[:n] != s2[:n] {
 			return false
 		}
+		s1, s2 = s1[n:], s2[n:]
 	}
-	return !iter2.Next()
+	return s1 == "" && s2 == ""
 }
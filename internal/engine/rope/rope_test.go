@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 	"testing/quick"
+	"unicode/utf8"
 )
 
 func TestNew(t *testing.T) {
@@ -132,6 +133,88 @@ func TestReplace(t *testing.T) {
 	}
 }
 
+func TestApplyEdits(t *testing.T) {
+	tests := []struct {
+		name     string
+		initial  string
+		edits    []Edit
+		expected string
+	}{
+		{"no edits", "hello", nil, "hello"},
+		{"at start and end", "hello", []Edit{{0, 0, "<"}, {5, 5, ">"}}, "<hello>"},
+		{"unsorted", "hello world", []Edit{{6, 11, "there"}, {0, 5, "hi"}}, "hi there"},
+		{"adjacent", "abcdef", []Edit{{2, 4, "X"}, {0, 2, ""}, {4, 6, "Y"}}, "XY"},
+		{"inserts at same offset keep order", "ab", []Edit{{1, 1, "1"}, {1, 1, "2"}}, "a12b"},
+		{"insert before delete at same offset", "abcd", []Edit{{1, 3, ""}, {1, 1, "X"}}, "aXd"},
+		{"delete everything", "hello", []Edit{{0, 5, ""}}, ""},
+		{"empty rope", "", []Edit{{0, 0, "new"}}, "new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := FromString(tt.initial)
+			got, err := r.ApplyEdits(tt.edits)
+			if err != nil {
+				t.Fatalf("ApplyEdits failed: %v", err)
+			}
+			if got.String() != tt.expected {
+				t.Errorf("got %q, want %q", got.String(), tt.expected)
+			}
+			if r.String() != tt.initial {
+				t.Errorf("original modified: %q", r.String())
+			}
+		})
+	}
+}
+
+func TestApplyEditsLarge(t *testing.T) {
+	text := strings.Repeat("0123456789\n", 1000)
+	r := FromString(text)
+
+	// Replace every tenth line, checking against sequential edits applied
+	// from the end so earlier offsets stay valid
+	var edits []Edit
+	for line := 0; line < 1000; line += 10 {
+		start := ByteOffset(line * 11)
+		edits = append(edits, Edit{Start: start, End: start + 5, Text: "line"})
+	}
+	want := r
+	for i := len(edits) - 1; i >= 0; i-- {
+		want = want.Replace(edits[i].Start, edits[i].End, edits[i].Text)
+	}
+
+	got, err := r.ApplyEdits(edits)
+	if err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if got.String() != want.String() {
+		t.Error("ApplyEdits result differs from sequential edits")
+	}
+	if got.LineCount() != 1001 {
+		t.Errorf("LineCount = %d, want 1001", got.LineCount())
+	}
+	if got.LineText(10) != "line56789" {
+		t.Errorf("LineText(10) = %q, want %q", got.LineText(10), "line56789")
+	}
+}
+
+func TestApplyEditsErrors(t *testing.T) {
+	r := FromString("hello world")
+
+	if _, err := r.ApplyEdits([]Edit{{0, 5, "a"}, {4, 8, "b"}}); err != ErrEditsOverlap {
+		t.Errorf("overlapping edits: err = %v, want ErrEditsOverlap", err)
+	}
+	if _, err := r.ApplyEdits([]Edit{{2, 2, "x"}, {0, 5, ""}}); err != ErrEditsOverlap {
+		t.Errorf("insert inside delete: err = %v, want ErrEditsOverlap", err)
+	}
+	if _, err := r.ApplyEdits([]Edit{{5, 20, ""}}); err != ErrEditOutOfRange {
+		t.Errorf("past end: err = %v, want ErrEditOutOfRange", err)
+	}
+	if _, err := r.ApplyEdits([]Edit{{5, 3, ""}}); err != ErrEditOutOfRange {
+		t.Errorf("reversed range: err = %v, want ErrEditOutOfRange", err)
+	}
+}
+
 func TestSplit(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -160,6 +243,37 @@ func TestSplit(t *testing.T) {
 	}
 }
 
+func TestSubRope(t *testing.T) {
+	long := strings.Repeat("abcdefghij", 1000)
+	tests := []struct {
+		name     string
+		input    string
+		start    ByteOffset
+		end      ByteOffset
+		expected string
+	}{
+		{"middle", "hello world", 2, 7, "llo w"},
+		{"whole", "hello", 0, 5, "hello"},
+		{"empty range", "hello", 3, 3, ""},
+		{"inverted range", "hello", 4, 2, ""},
+		{"clamped", "hello", 1, 50, "ello"},
+		{"across chunks", long, 1234, 8765, long[1234:8765]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := FromString(tt.input)
+			sub := r.SubRope(tt.start, tt.end)
+			if sub.String() != tt.expected {
+				t.Errorf("SubRope(%d, %d) = %q, want %q", tt.start, tt.end, sub.String(), tt.expected)
+			}
+			if r.String() != tt.input {
+				t.Error("original rope was modified")
+			}
+		})
+	}
+}
+
 func TestConcat(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -261,6 +375,62 @@ func TestLineText(t *testing.T) {
 	}
 }
 
+func TestLineTextLineEndings(t *testing.T) {
+	// Mixed LF and CRLF endings, a lone CR inside a line, and a final line
+	// without a terminator
+	text := "unix\nwindows\r\nold\rmac\n\r\nlast"
+	r := FromString(text)
+
+	tests := []struct {
+		line       uint32
+		text       string
+		raw        string
+		terminator string
+		end        ByteOffset
+	}{
+		{0, "unix", "unix\n", "\n", 4},
+		{1, "windows", "windows\r\n", "\r\n", 12},
+		{2, "old\rmac", "old\rmac\n", "\n", 21},
+		{3, "", "\r\n", "\r\n", 22},
+		{4, "last", "last", "", 28},
+	}
+
+	var joined string
+	for _, tt := range tests {
+		if got := r.LineText(tt.line); got != tt.text {
+			t.Errorf("LineText(%d) = %q, want %q", tt.line, got, tt.text)
+		}
+		if got := r.LineTextRaw(tt.line); got != tt.raw {
+			t.Errorf("LineTextRaw(%d) = %q, want %q", tt.line, got, tt.raw)
+		}
+		if got := r.LineTerminator(tt.line); got != tt.terminator {
+			t.Errorf("LineTerminator(%d) = %q, want %q", tt.line, got, tt.terminator)
+		}
+		if got := r.LineEndOffset(tt.line); got != tt.end {
+			t.Errorf("LineEndOffset(%d) = %d, want %d", tt.line, got, tt.end)
+		}
+		joined += r.LineTextRaw(tt.line)
+	}
+	if joined != text {
+		t.Errorf("joined LineTextRaw = %q, want %q", joined, text)
+	}
+
+	// A trailing newline leaves an empty, unterminated final line
+	r = FromString("a\r\n")
+	if got := r.LineTerminator(1); got != "" {
+		t.Errorf("LineTerminator(1) = %q, want empty", got)
+	}
+	if got := r.LineText(1); got != "" {
+		t.Errorf("LineText(1) = %q, want empty", got)
+	}
+	if got := r.LineEndOffset(0); got != 1 {
+		t.Errorf("LineEndOffset(0) = %d, want 1", got)
+	}
+	if got := r.LineTerminator(5); got != "" {
+		t.Errorf("LineTerminator(5) out of range = %q, want empty", got)
+	}
+}
+
 func TestLineStartOffset(t *testing.T) {
 	r := FromString("hello\nworld\nfoo")
 
@@ -304,6 +474,29 @@ func TestOffsetToPoint(t *testing.T) {
 	}
 }
 
+func TestOffsetToPointLongLine(t *testing.T) {
+	// A line spanning many leaves, after a short one
+	long := strings.Repeat("x", 1<<20)
+	r := FromString("ab\n" + long + "\ncd")
+
+	tests := []struct {
+		offset   ByteOffset
+		expected Point
+	}{
+		{3, Point{1, 0}},
+		{3 + 1<<19, Point{1, 1 << 19}},
+		{3 + 1<<20, Point{1, 1 << 20}},
+		{5 + 1<<20, Point{2, 1}},
+	}
+
+	for _, tt := range tests {
+		got := r.OffsetToPoint(tt.offset)
+		if got != tt.expected {
+			t.Errorf("OffsetToPoint(%d) = %+v, want %+v", tt.offset, got, tt.expected)
+		}
+	}
+}
+
 func TestPointToOffset(t *testing.T) {
 	r := FromString("hello\nworld\nfoo")
 
@@ -482,6 +675,29 @@ func TestCursor(t *testing.T) {
 	}
 }
 
+func TestCursorPrev(t *testing.T) {
+	// Enough text for several chunks and leaves, with multi-byte runes
+	text := strings.Repeat("héllo 世界\n", 500)
+	r := FromString(text)
+
+	c := NewCursor(r)
+	c.SeekOffset(r.Len())
+	want := len(text)
+	for c.Prev() {
+		_, size := utf8.DecodeLastRuneInString(text[:want])
+		want -= size
+		if c.Offset() != ByteOffset(want) {
+			t.Fatalf("after Prev, offset = %d, want %d", c.Offset(), want)
+		}
+		if got, wantPoint := c.Point(), r.OffsetToPoint(ByteOffset(want)); got != wantPoint {
+			t.Fatalf("Point() at %d = %v, want %v", want, got, wantPoint)
+		}
+	}
+	if want != 0 {
+		t.Errorf("Prev stopped at %d, want 0", want)
+	}
+}
+
 func TestBuilder(t *testing.T) {
 	b := NewBuilder()
 	b.WriteString("hello")
@@ -656,3 +872,51 @@ func TestSummaryAdd(t *testing.T) {
 		t.Errorf("Combined lines = %d, want 1", combined.Lines)
 	}
 }
+
+func TestSameAs(t *testing.T) {
+	r := FromString(strings.Repeat("hello world\n", 200))
+	snapshot := r
+
+	if !r.SameAs(snapshot) {
+		t.Error("rope should be SameAs its unchanged snapshot")
+	}
+
+	edited := r.Insert(5, "!")
+	if r.SameAs(edited) {
+		t.Error("rope should not be SameAs an edited version")
+	}
+	if edited.SameAs(r.Replace(5, 6, "!")) {
+		t.Error("ropes with different text should not be SameAs")
+	}
+
+	restored := edited.Delete(5, 6)
+	if !r.SameAs(restored) {
+		t.Error("rope should be SameAs a version edited back to the same text")
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	text := strings.Repeat("the quick brown fox\n", 500)
+	r := FromString(text)
+
+	// Build the same text with a different tree structure
+	other := New()
+	for _, line := range strings.SplitAfter(text, "\n") {
+		other = other.Concat(FromString(line))
+	}
+	if r.ContentHash() != other.ContentHash() {
+		t.Errorf("ContentHash differs for equal text: %x != %x", r.ContentHash(), other.ContentHash())
+	}
+
+	edited := r.Replace(100, 101, "X")
+	if r.ContentHash() == edited.ContentHash() {
+		t.Error("ContentHash should change when text changes")
+	}
+	if got, want := edited.Replace(100, 101, text[100:101]).ContentHash(), r.ContentHash(); got != want {
+		t.Errorf("ContentHash after undoing edit = %x, want %x", got, want)
+	}
+
+	if FromString("\x00a").ContentHash() == FromString("a").ContentHash() {
+		t.Error("leading NUL byte should change ContentHash")
+	}
+}
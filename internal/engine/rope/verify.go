@@ -0,0 +1,120 @@
+package rope
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Freeze returns the rope as a snapshot that must never change. Ropes are
+// already immutable, so Freeze returns r itself; it documents intent at
+// the point a snapshot is taken. Built with the ropedebug tag, Freeze also
+// verifies the tree and records its nodes, and returning a recorded node
+// to the pool or recomputing its summary panics. This catches code that
+// recycles or rewrites a node still shared with a snapshot.
+func (r Rope) Freeze() Rope {
+	if r.root != nil {
+		freezeNodes(r.root)
+	}
+	return r
+}
+
+// Verify walks the tree and checks that every cached summary matches the
+// text below it: chunk summaries match their data, leaf summaries sum
+// their chunks, and internal nodes sum their children with per-child
+// summaries in sync. It also checks that every node is taller than its
+// children. The first violation is returned wrapping ErrCorrupt, with the
+// path of child indexes from the root. Verify is O(n) and intended for
+// tests and debugging.
+func (r Rope) Verify() error {
+	if r.root == nil {
+		return nil
+	}
+	var path []int
+	return verifyNode(r.root, &path)
+}
+
+// verifyNode checks the subtree rooted at n. path holds the child indexes
+// leading to n.
+func verifyNode(n *Node, path *[]int) error {
+	if n.IsLeaf() {
+		if len(n.children) != 0 {
+			return corruptf(*path, "leaf has %d children", len(n.children))
+		}
+		total := TextSummary{}.Zero()
+		for i, chunk := range n.chunks {
+			if want := ComputeSummary(chunk.data); !summariesMatch(chunk.summary, want) {
+				return corruptf(*path, "chunk %d summary %s, text has %s", i, formatSummary(chunk.summary), formatSummary(want))
+			}
+			total = total.Add(chunk.summary)
+		}
+		if !summariesMatch(n.summary, total) {
+			return corruptf(*path, "leaf summary %s, chunks sum to %s", formatSummary(n.summary), formatSummary(total))
+		}
+		return nil
+	}
+
+	if len(n.children) == 0 {
+		return corruptf(*path, "internal node has no children")
+	}
+	if len(n.childSummaries) != len(n.children) {
+		return corruptf(*path, "%d child summaries for %d children", len(n.childSummaries), len(n.children))
+	}
+
+	total := TextSummary{}.Zero()
+	for i, child := range n.children {
+		if child == nil {
+			return corruptf(*path, "child %d is nil", i)
+		}
+		if child.height >= n.height {
+			return corruptf(*path, "child %d has height %d under height %d", i, child.height, n.height)
+		}
+
+		*path = append(*path, i)
+		err := verifyNode(child, path)
+		*path = (*path)[:len(*path)-1]
+		if err != nil {
+			return err
+		}
+
+		if !summariesMatch(n.childSummaries[i], child.summary) {
+			return corruptf(*path, "child %d cached summary %s, child has %s", i, formatSummary(n.childSummaries[i]), formatSummary(child.summary))
+		}
+		total = total.Add(child.summary)
+	}
+	if !summariesMatch(n.summary, total) {
+		return corruptf(*path, "node summary %s, children sum to %s", formatSummary(n.summary), formatSummary(total))
+	}
+	return nil
+}
+
+// summariesMatch reports whether two summaries describe the same text.
+// Summaries of empty text match regardless of their flags, which depend
+// on how the empty summary was started.
+func summariesMatch(a, b TextSummary) bool {
+	if a.Bytes == 0 && b.Bytes == 0 {
+		return true
+	}
+	return a == b
+}
+
+// formatSummary formats the metrics of a summary for error messages.
+func formatSummary(s TextSummary) string {
+	return fmt.Sprintf("{bytes %d, lines %d, utf16 %d, hash %#x}", s.Bytes, s.Lines, s.UTF16Units, s.Hash)
+}
+
+// corruptf returns an error wrapping ErrCorrupt for the node at path.
+func corruptf(path []int, format string, args ...any) error {
+	return fmt.Errorf("%w at %s: %s", ErrCorrupt, formatPath(path), fmt.Sprintf(format, args...))
+}
+
+// formatPath formats child indexes from the root, such as "root/2/0".
+func formatPath(path []int) string {
+	var sb strings.Builder
+	sb.WriteString("root")
+	for _, i := range path {
+		sb.WriteByte('/')
+		sb.WriteString(strconv.Itoa(i))
+	}
+	return sb.String()
+}
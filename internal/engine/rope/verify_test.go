@@ -0,0 +1,171 @@
+package rope
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestVerifyValidRopes(t *testing.T) {
+	big := strings.Repeat("line of text\twith a tab, héllo 世界\n", 500)
+
+	b := NewBuilder()
+	b.WriteString(big)
+
+	ropes := map[string]Rope{
+		"zero":    {},
+		"empty":   New(),
+		"small":   FromString("hello\nworld"),
+		"large":   FromString(big),
+		"builder": b.Build(),
+		"concat":  FromString(big).Concat(FromString(big)),
+		"subrope": FromString(big).SubRope(100, 9000),
+	}
+
+	// Random edits exercise the split and rebalance paths
+	rng := rand.New(rand.NewSource(1))
+	r := FromString(big)
+	for i := 0; i < 500; i++ {
+		start := ByteOffset(rng.Intn(int(r.Len()) + 1))
+		end := min(start+ByteOffset(rng.Intn(40)), r.Len())
+		s := r.String()
+		for start > 0 && start < ByteOffset(len(s)) && s[start]&0xC0 == 0x80 {
+			start--
+		}
+		for end < ByteOffset(len(s)) && s[end]&0xC0 == 0x80 {
+			end++
+		}
+		r = r.Replace(start, end, strings.Repeat("ab\n", rng.Intn(5)))
+	}
+	ropes["edited"] = r
+
+	edited, err := FromString(big).ApplyEdits([]Edit{
+		{Start: 0, End: 10, Text: "x\ny"},
+		{Start: 5000, End: 5000, Text: strings.Repeat("z", 1000)},
+	})
+	if err != nil {
+		t.Fatalf("ApplyEdits() error = %v", err)
+	}
+	ropes["applyEdits"] = edited
+
+	for name, r := range ropes {
+		if err := r.Verify(); err != nil {
+			t.Errorf("%s: Verify() error = %v", name, err)
+		}
+		if frozen := r.Freeze(); !frozen.SameAs(r) || frozen.root != r.root {
+			t.Errorf("%s: Freeze() returned a different rope", name)
+		}
+	}
+}
+
+func TestNewInternalNodeUnequalHeights(t *testing.T) {
+	leaf := func(s string) *Node { return newLeafNodeWithChunks([]Chunk{NewChunk(s)}) }
+	short := leaf("ab\n")
+	tall := newInternalNode([]*Node{leaf("cd\n"), leaf("ef")})
+
+	for _, children := range [][]*Node{{short, tall}, {tall, short}} {
+		n := newInternalNode(children)
+		if n.height != 2 {
+			t.Errorf("height = %d, want 2", n.height)
+		}
+		r := Rope{root: n}
+		if err := r.Verify(); err != nil {
+			t.Errorf("Verify() error = %v", err)
+		}
+		if got := r.LineCount(); got != 3 {
+			t.Errorf("LineCount() = %d, want 3", got)
+		}
+	}
+}
+
+func TestVerifyDetectsCorruption(t *testing.T) {
+	r := FromString(strings.Repeat("abcdefghij\n", 1000))
+	if r.root.IsLeaf() {
+		t.Fatal("test rope should have internal nodes")
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(root *Node)
+		want    string
+	}{
+		{
+			name: "child byte count",
+			corrupt: func(root *Node) {
+				child := root.children[1].clone()
+				child.summary.Bytes++
+				root.children[1] = child
+			},
+			want: "root/1",
+		},
+		{
+			name: "cached line count",
+			corrupt: func(root *Node) {
+				root.childSummaries[0].Lines++
+			},
+			want: "child 0 cached summary",
+		},
+		{
+			name: "root summary",
+			corrupt: func(root *Node) {
+				root.summary.Lines--
+			},
+			want: "children sum to",
+		},
+		{
+			name: "chunk summary",
+			corrupt: func(root *Node) {
+				n := root
+				for !n.IsLeaf() {
+					n = n.children[0]
+				}
+				leaf := n.clone()
+				leaf.chunks[0].summary.Bytes += 3
+				replaceFirstLeaf(root, leaf)
+			},
+			want: "chunk 0 summary",
+		},
+		{
+			name: "child height",
+			corrupt: func(root *Node) {
+				child := root.children[0].clone()
+				child.height++
+				root.children[0] = child
+			},
+			want: "height",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := r.root.clone()
+			tt.corrupt(root)
+
+			err := Rope{root: root}.Verify()
+			if !errors.Is(err, ErrCorrupt) {
+				t.Fatalf("Verify() error = %v, want ErrCorrupt", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Verify() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	// The corrupted copies did not touch the original
+	if err := r.Verify(); err != nil {
+		t.Errorf("original Verify() error = %v", err)
+	}
+}
+
+// replaceFirstLeaf replaces the leftmost leaf under root with leaf,
+// cloning the nodes on the path so other ropes are not affected.
+func replaceFirstLeaf(root, leaf *Node) {
+	n := root
+	for !n.children[0].IsLeaf() {
+		child := n.children[0].clone()
+		n.children[0] = child
+		n = child
+	}
+	n.children[0] = leaf
+}
@@ -0,0 +1,306 @@
+package engine
+
+import (
+	"strings"
+)
+
+// SaveHook transforms buffer content before it is written to disk, e.g.
+// trimming trailing whitespace. Hooks are registered with AddSaveHook and
+// run by RunSaveHooks.
+type SaveHook interface {
+	// Name identifies the hook (e.g. "trimTrailingWhitespace").
+	Name() string
+
+	// Enabled reports whether the hook runs for a language ID.
+	// The language is "" when it is unknown.
+	Enabled(language string) bool
+
+	// Apply returns the transformed content.
+	Apply(content string, ctx SaveContext) string
+}
+
+// SaveContext describes the buffer being saved.
+type SaveContext struct {
+	// Language is the language ID of the buffer, or "".
+	Language string
+
+	// TabWidth is the buffer's tab width.
+	TabWidth int
+
+	// Newline is the buffer's line ending sequence.
+	Newline string
+}
+
+// SaveHookConfig enables a built-in save hook, globally and per language.
+// It is typically filled from settings such as files.trimTrailingWhitespace
+// and their language-specific overrides.
+type SaveHookConfig struct {
+	// Enabled is the default for languages without an override.
+	Enabled bool
+
+	// Languages overrides Enabled for specific language IDs.
+	Languages map[string]bool
+}
+
+// EnabledFor reports whether the hook is enabled for a language.
+func (c SaveHookConfig) EnabledFor(language string) bool {
+	if enabled, ok := c.Languages[language]; ok {
+		return enabled
+	}
+	return c.Enabled
+}
+
+// Names of the built-in save hooks.
+const (
+	SaveHookTrimTrailingWhitespace = "trimTrailingWhitespace"
+	SaveHookEnsureFinalNewline     = "ensureFinalNewline"
+	SaveHookTabsToSpaces           = "tabsToSpaces"
+)
+
+// TrimTrailingWhitespace returns a hook that removes spaces and tabs at the
+// end of every line.
+func TrimTrailingWhitespace(cfg SaveHookConfig) SaveHook {
+	return &lineHook{name: SaveHookTrimTrailingWhitespace, cfg: cfg, fn: trimLineEnd}
+}
+
+// TabsToSpaces returns a hook that replaces tabs in leading indentation
+// with spaces, honoring tab stops. Tabs after the indentation are kept.
+func TabsToSpaces(cfg SaveHookConfig) SaveHook {
+	return &lineHook{name: SaveHookTabsToSpaces, cfg: cfg, fn: expandIndent}
+}
+
+// EnsureFinalNewline returns a hook that terminates non-empty content with
+// the buffer's line ending.
+func EnsureFinalNewline(cfg SaveHookConfig) SaveHook {
+	return &finalNewlineHook{cfg: cfg}
+}
+
+// lineHook is a save hook that transforms each line independently.
+type lineHook struct {
+	name string
+	cfg  SaveHookConfig
+	fn   func(line string, ctx SaveContext) string
+}
+
+// Name implements SaveHook.
+func (h *lineHook) Name() string { return h.name }
+
+// Enabled implements SaveHook.
+func (h *lineHook) Enabled(language string) bool { return h.cfg.EnabledFor(language) }
+
+// Apply implements SaveHook. A trailing '\r' of CRLF lines is preserved.
+func (h *lineHook) Apply(content string, ctx SaveContext) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		cr := strings.HasSuffix(line, "\r")
+		if cr {
+			line = line[:len(line)-1]
+		}
+		line = h.fn(line, ctx)
+		if cr {
+			line += "\r"
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimLineEnd removes trailing spaces and tabs.
+func trimLineEnd(line string, _ SaveContext) string {
+	return strings.TrimRight(line, " \t")
+}
+
+// expandIndent replaces tabs in the leading whitespace of line.
+func expandIndent(line string, ctx SaveContext) string {
+	indentLen := len(line) - len(strings.TrimLeft(line, " \t"))
+	indent := line[:indentLen]
+	if !strings.Contains(indent, "\t") {
+		return line
+	}
+
+	tabWidth := ctx.TabWidth
+	if tabWidth <= 0 {
+		tabWidth = DefaultTabWidth
+	}
+
+	var b strings.Builder
+	col := 0
+	for _, r := range indent {
+		if r == '\t' {
+			n := tabWidth - col%tabWidth
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		b.WriteRune(r)
+		col++
+	}
+	b.WriteString(line[indentLen:])
+	return b.String()
+}
+
+// finalNewlineHook terminates content with a line ending.
+type finalNewlineHook struct {
+	cfg SaveHookConfig
+}
+
+// Name implements SaveHook.
+func (h *finalNewlineHook) Name() string { return SaveHookEnsureFinalNewline }
+
+// Enabled implements SaveHook.
+func (h *finalNewlineHook) Enabled(language string) bool { return h.cfg.EnabledFor(language) }
+
+// Apply implements SaveHook.
+func (h *finalNewlineHook) Apply(content string, ctx SaveContext) string {
+	if content == "" || strings.HasSuffix(content, "\n") || strings.HasSuffix(content, "\r") {
+		return content
+	}
+	newline := ctx.Newline
+	if newline == "" {
+		newline = "\n"
+	}
+	return content + newline
+}
+
+// AddSaveHook registers a save hook. Hooks run in registration order; a
+// hook with the same name as a registered hook replaces it in place.
+func (e *Engine) AddSaveHook(hook SaveHook) {
+	if hook == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i, h := range e.saveHooks {
+		if h.Name() == hook.Name() {
+			e.saveHooks[i] = hook
+			return
+		}
+	}
+	e.saveHooks = append(e.saveHooks, hook)
+}
+
+// RemoveSaveHook unregisters the save hook with the given name.
+func (e *Engine) RemoveSaveHook(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	filtered := e.saveHooks[:0]
+	for _, h := range e.saveHooks {
+		if h.Name() != name {
+			filtered = append(filtered, h)
+		}
+	}
+	for i := len(filtered); i < len(e.saveHooks); i++ {
+		e.saveHooks[i] = nil
+	}
+	e.saveHooks = filtered
+}
+
+// SaveHooks returns the registered save hooks.
+func (e *Engine) SaveHooks() []SaveHook {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]SaveHook(nil), e.saveHooks...)
+}
+
+// RunSaveHooks runs the save hooks enabled for language on the buffer
+// content. It should be called before the content is written to disk.
+// All changes are recorded as a single undo entry, so the user can revert
+// the automatic formatting with one Undo. Returns true if the content
+// changed.
+func (e *Engine) RunSaveHooks(language string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.saveHooks) == 0 {
+		return false, nil
+	}
+
+	ctx := SaveContext{
+		Language: language,
+		TabWidth: e.buf.TabWidth(),
+		Newline:  e.buf.LineEnding().Sequence(),
+	}
+
+	// Group the edits unless the caller already opened a group.
+	if !e.history.IsGrouping() {
+		e.history.BeginGroup("Format on save")
+		defer e.history.EndGroup()
+	}
+
+	changed := false
+	for _, hook := range e.saveHooks {
+		if !hook.Enabled(language) {
+			continue
+		}
+
+		content := e.buf.Text()
+		edits := diffEdits(content, hook.Apply(content, ctx))
+		if len(edits) == 0 {
+			continue
+		}
+		if e.readOnly {
+			return changed, ErrReadOnly
+		}
+		if err := e.applyGroupedEditsLocked("Format on save", edits); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// diffEdits returns edits, highest offset first, that turn old into new.
+// When both have the same number of lines each changed line becomes its
+// own edit, so cursors on untouched text do not move; otherwise the
+// differing middle is replaced.
+func diffEdits(old, new string) []Edit {
+	if old == new {
+		return nil
+	}
+
+	oldLines := strings.SplitAfter(old, "\n")
+	newLines := strings.SplitAfter(new, "\n")
+	if len(oldLines) != len(newLines) {
+		return []Edit{replaceEdit(old, new, 0)}
+	}
+
+	var edits []Edit
+	offset := ByteOffset(0)
+	for i, line := range oldLines {
+		if line != newLines[i] {
+			edits = append(edits, replaceEdit(line, newLines[i], offset))
+		}
+		offset += ByteOffset(len(line))
+	}
+
+	// Highest offset first.
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// replaceEdit returns the edit replacing the part of old that differs from
+// new, with old starting at base.
+func replaceEdit(old, new string, base ByteOffset) Edit {
+	prefix := 0
+	for prefix < len(old) && prefix < len(new) && old[prefix] == new[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(new)-prefix &&
+		old[len(old)-1-suffix] == new[len(new)-1-suffix] {
+		suffix++
+	}
+
+	return Edit{
+		Range: Range{
+			Start: base + ByteOffset(prefix),
+			End:   base + ByteOffset(len(old)-suffix),
+		},
+		NewText: new[prefix : len(new)-suffix],
+	}
+}
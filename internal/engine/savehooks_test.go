@@ -0,0 +1,150 @@
+package engine
+
+import "testing"
+
+func TestRunSaveHooks_TrimTrailingWhitespace(t *testing.T) {
+	const content = "one  \ntwo\t\nthree\n  \nfour"
+	e := New(WithContent(content))
+	e.AddSaveHook(TrimTrailingWhitespace(SaveHookConfig{Enabled: true}))
+
+	undoBefore := e.UndoCount()
+	changed, err := e.RunSaveHooks("go")
+	if err != nil {
+		t.Fatalf("RunSaveHooks error: %v", err)
+	}
+	if !changed {
+		t.Error("RunSaveHooks changed = false, want true")
+	}
+	if got, want := e.Text(), "one\ntwo\nthree\n\nfour"; got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+	if got := e.UndoCount(); got != undoBefore+1 {
+		t.Errorf("UndoCount = %d, want %d", got, undoBefore+1)
+	}
+
+	if err := e.Undo(); err != nil {
+		t.Fatalf("Undo error: %v", err)
+	}
+	if got := e.Text(); got != content {
+		t.Errorf("Text after Undo = %q, want %q", got, content)
+	}
+}
+
+func TestRunSaveHooks_Disabled(t *testing.T) {
+	const content = "one  \ntwo\t"
+
+	tests := []struct {
+		name     string
+		cfg      SaveHookConfig
+		language string
+	}{
+		{"disabled", SaveHookConfig{}, "go"},
+		{"disabled for language", SaveHookConfig{Enabled: true, Languages: map[string]bool{"markdown": false}}, "markdown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(content))
+			e.AddSaveHook(TrimTrailingWhitespace(tt.cfg))
+
+			changed, err := e.RunSaveHooks(tt.language)
+			if err != nil {
+				t.Fatalf("RunSaveHooks error: %v", err)
+			}
+			if changed {
+				t.Error("RunSaveHooks changed = true, want false")
+			}
+			if got := e.Text(); got != content {
+				t.Errorf("Text = %q, want %q", got, content)
+			}
+			if got := e.UndoCount(); got != 0 {
+				t.Errorf("UndoCount = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestRunSaveHooks_MultipleHooks(t *testing.T) {
+	e := New(WithContent("\tfunc  \n\t\tbody"), WithTabWidth(4))
+	e.AddSaveHook(TrimTrailingWhitespace(SaveHookConfig{Enabled: true}))
+	e.AddSaveHook(TabsToSpaces(SaveHookConfig{Enabled: true}))
+	e.AddSaveHook(EnsureFinalNewline(SaveHookConfig{Enabled: true}))
+
+	if _, err := e.RunSaveHooks(""); err != nil {
+		t.Fatalf("RunSaveHooks error: %v", err)
+	}
+	if got, want := e.Text(), "    func\n        body\n"; got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+	if got := e.UndoCount(); got != 1 {
+		t.Errorf("UndoCount = %d, want 1", got)
+	}
+}
+
+func TestRunSaveHooks_ReadOnly(t *testing.T) {
+	e := New(WithContent("x  "), WithReadOnly())
+	e.AddSaveHook(TrimTrailingWhitespace(SaveHookConfig{Enabled: true}))
+
+	if _, err := e.RunSaveHooks(""); err != ErrReadOnly {
+		t.Errorf("RunSaveHooks error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestSaveHookRegistration(t *testing.T) {
+	e := New()
+	e.AddSaveHook(TrimTrailingWhitespace(SaveHookConfig{}))
+	e.AddSaveHook(EnsureFinalNewline(SaveHookConfig{}))
+	e.AddSaveHook(TrimTrailingWhitespace(SaveHookConfig{Enabled: true}))
+
+	hooks := e.SaveHooks()
+	if len(hooks) != 2 {
+		t.Fatalf("len(SaveHooks) = %d, want 2", len(hooks))
+	}
+	if !hooks[0].Enabled("") {
+		t.Error("re-added hook did not replace the original")
+	}
+
+	e.RemoveSaveHook(SaveHookTrimTrailingWhitespace)
+	hooks = e.SaveHooks()
+	if len(hooks) != 1 || hooks[0].Name() != SaveHookEnsureFinalNewline {
+		t.Errorf("SaveHooks after remove = %v", hooks)
+	}
+}
+
+func TestTabsToSpaces(t *testing.T) {
+	hook := TabsToSpaces(SaveHookConfig{Enabled: true})
+	ctx := SaveContext{TabWidth: 4}
+
+	tests := []struct {
+		in, want string
+	}{
+		{"\tx", "    x"},
+		{"  \tx", "    x"},
+		{"\t\tx\ty", "        x\ty"},
+		{"\tx\r\n", "    x\r\n"},
+		{"x", "x"},
+	}
+	for _, tt := range tests {
+		if got := hook.Apply(tt.in, ctx); got != tt.want {
+			t.Errorf("Apply(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEnsureFinalNewline(t *testing.T) {
+	hook := EnsureFinalNewline(SaveHookConfig{Enabled: true})
+
+	tests := []struct {
+		in, newline, want string
+	}{
+		{"", "\n", ""},
+		{"x", "\n", "x\n"},
+		{"x\n", "\n", "x\n"},
+		{"x", "\r\n", "x\r\n"},
+	}
+	for _, tt := range tests {
+		if got := hook.Apply(tt.in, SaveContext{Newline: tt.newline}); got != tt.want {
+			t.Errorf("Apply(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package engine
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dshills/keystorm/internal/engine/cursor"
+	"github.com/dshills/keystorm/internal/engine/rope"
+)
+
+// SearchOptions configures buffer searches.
+type SearchOptions struct {
+	// Regex treats the pattern as a regular expression (RE2 syntax).
+	// Otherwise the pattern is matched literally.
+	Regex bool
+
+	// CaseSensitive matches case exactly. Ignored when SmartCase is set.
+	CaseSensitive bool
+
+	// SmartCase matches case-insensitively unless the pattern contains an
+	// uppercase letter, like Vim's 'smartcase'. In regex mode escape
+	// sequences such as \W or \p{Lu} do not count as uppercase.
+	SmartCase bool
+
+	// WholeWord only accepts matches that are not part of a larger word.
+	// Word characters are Unicode letters, digits and underscore.
+	WholeWord bool
+}
+
+// IsCaseSensitive reports whether a search for pattern with these options
+// is case-sensitive.
+func (o SearchOptions) IsCaseSensitive(pattern string) bool {
+	if o.SmartCase {
+		return hasUpper(pattern, o.Regex)
+	}
+	return o.CaseSensitive
+}
+
+// CompileSearch compiles a search pattern into a regular expression that
+// honors the case options. Like Vim, ^ and $ match at line boundaries.
+// WholeWord is applied when matching, not by the returned expression.
+func CompileSearch(pattern string, opts SearchOptions) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, ErrEmptyPattern
+	}
+
+	expr := pattern
+	if !opts.Regex {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	flags := "(?m)"
+	if !opts.IsCaseSensitive(pattern) {
+		flags = "(?mi)"
+	}
+	return regexp.Compile(flags + expr)
+}
+
+// FindAll returns every match of pattern in the buffer, in order.
+// Matches do not overlap. They span lines only if the pattern matches a
+// newline, as with a multiline regex such as "foo\nbar".
+func (e *Engine) FindAll(pattern string, opts SearchOptions) ([]Range, error) {
+	re, err := CompileSearch(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return findMatches(e.buf.Snapshot().Rope(), re, opts.WholeWord), nil
+}
+
+// FindNext returns the first match starting at or after from, wrapping
+// around to the start of the buffer. Returns false if there is no match.
+func (e *Engine) FindNext(pattern string, from ByteOffset, opts SearchOptions) (Range, bool, error) {
+	matches, err := e.FindAll(pattern, opts)
+	if err != nil || len(matches) == 0 {
+		return Range{}, false, err
+	}
+	for _, m := range matches {
+		if m.Start >= from {
+			return m, true, nil
+		}
+	}
+	return matches[0], true, nil
+}
+
+// FindPrev returns the last match starting before from, wrapping around to
+// the end of the buffer. Returns false if there is no match.
+func (e *Engine) FindPrev(pattern string, from ByteOffset, opts SearchOptions) (Range, bool, error) {
+	matches, err := e.FindAll(pattern, opts)
+	if err != nil || len(matches) == 0 {
+		return Range{}, false, err
+	}
+	for i := len(matches) - 1; i >= 0; i-- {
+		if matches[i].Start < from {
+			return matches[i], true, nil
+		}
+	}
+	return matches[len(matches)-1], true, nil
+}
+
+// SelectAllMatches returns a cursor set with a selection for every match
+// of pattern, each anchored at the start of its match with the cursor at
+// the end. Touching matches are merged into one selection. Returns
+// ErrNoMatch if pattern does not occur.
+func (e *Engine) SelectAllMatches(pattern string, opts SearchOptions) (*cursor.CursorSet, error) {
+	matches, err := e.FindAll(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, ErrNoMatch
+	}
+
+	sels := make([]Selection, len(matches))
+	for i, m := range matches {
+		sels[i] = cursor.NewSelection(m.Start, m.End)
+	}
+	return cursor.NewCursorSetFromSlice(sels), nil
+}
+
+// AddCursorAtNextMatch adds a selection for the next occurrence of pattern
+// after the last selection in current, wrapping around to the start of the
+// buffer, like Ctrl-D in VS Code. Occurrences already selected are
+// skipped. The pattern is matched literally with the case and whole-word
+// handling of opts; opts.Regex is ignored.
+//
+// If pattern is empty it is the text of the primary selection. If that
+// selection is empty, it is first expanded to the word under the cursor
+// and no selection is added. A selection that spans exactly the word it is
+// on, as expanded, only matches whole words, so expanding "foo" does not
+// go on to select the start of "food".
+//
+// Returns ErrNoMatch if every occurrence is already selected, or if the
+// cursor is not on a word.
+func (e *Engine) AddCursorAtNextMatch(current *cursor.CursorSet, pattern string, opts SearchOptions) error {
+	e.mu.RLock()
+	r := e.buf.Snapshot().Rope()
+	keywords := e.keywords
+	e.mu.RUnlock()
+
+	opts.Regex = false
+	if pattern == "" {
+		primary := current.Primary()
+		if primary.IsEmpty() {
+			start, end := keywords.WordBounds(r.String(), primary.Head, false, 1)
+			if start == end || start > primary.Head {
+				return ErrNoMatch
+			}
+			current.SetPrimary(cursor.NewSelection(start, end))
+			return nil
+		}
+		start, end := keywords.WordBounds(r.String(), primary.Start(), false, 1)
+		if start == primary.Start() && end == primary.End() {
+			opts.WholeWord = true
+		}
+		pattern = r.Slice(rope.ByteOffset(primary.Start()), rope.ByteOffset(primary.End()))
+	}
+
+	re, err := CompileSearch(pattern, opts)
+	if err != nil {
+		return err
+	}
+	matches := findMatches(r, re, opts.WholeWord)
+
+	sels := current.All()
+	selected := func(m Range) bool {
+		for _, sel := range sels {
+			if sel.Start() < m.End && m.Start < sel.End() {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The first unselected match after the last selection, else the first
+	// unselected match from the start of the buffer
+	from := sels[len(sels)-1].End()
+	next := -1
+	for i, m := range matches {
+		if selected(m) {
+			continue
+		}
+		if m.Start >= from {
+			next = i
+			break
+		}
+		if next < 0 {
+			next = i
+		}
+	}
+	if next < 0 {
+		return ErrNoMatch
+	}
+
+	current.Add(cursor.NewSelection(matches[next].Start, matches[next].End))
+	return nil
+}
+
+// findMatches runs re over the rope, dropping matches that are part of a
+// larger word when wholeWord is set.
+func findMatches(r rope.Rope, re *regexp.Regexp, wholeWord bool) []Range {
+	locs := re.FindAllStringIndex(r.String(), -1)
+
+	matches := make([]Range, 0, len(locs))
+	for _, loc := range locs {
+		m := Range{Start: ByteOffset(loc[0]), End: ByteOffset(loc[1])}
+		if wholeWord && (m.IsEmpty() || !isWholeWord(r, m)) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// isWholeWord reports whether a match is not part of a larger word: it
+// does not start inside a word or end inside a word.
+func isWholeWord(r rope.Rope, m Range) bool {
+	c := rope.NewCursor(r)
+
+	c.SeekOffset(rope.ByteOffset(m.Start))
+	first, _ := c.Rune()
+	if isWordRune(first) && m.Start > 0 {
+		lookback := m.Start - utf8.UTFMax
+		if lookback < 0 {
+			lookback = 0
+		}
+		prev, _ := utf8.DecodeLastRuneInString(r.Slice(rope.ByteOffset(lookback), rope.ByteOffset(m.Start)))
+		if isWordRune(prev) {
+			return false
+		}
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(r.Slice(rope.ByteOffset(m.Start), rope.ByteOffset(m.End)))
+	if isWordRune(last) {
+		c.SeekOffset(rope.ByteOffset(m.End))
+		if next, size := c.Rune(); size > 0 && isWordRune(next) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordRune returns true for characters that form words.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// hasUpper reports whether pattern contains an uppercase letter. In regex
+// mode escaped characters (\W, \S, \p{Lu}, ...) are not considered.
+func hasUpper(pattern string, regex bool) bool {
+	for i := 0; i < len(pattern); {
+		r, size := utf8.DecodeRuneInString(pattern[i:])
+		i += size
+
+		if regex && r == '\\' && i < len(pattern) {
+			esc := pattern[i]
+			i++
+			// Skip the class name of \p{...} and \P{...}.
+			if (esc == 'p' || esc == 'P') && i < len(pattern) {
+				if pattern[i] == '{' {
+					for i < len(pattern) && pattern[i] != '}' {
+						i++
+					}
+				}
+				i++
+			}
+			continue
+		}
+
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
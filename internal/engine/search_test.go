@@ -0,0 +1,335 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/engine/cursor"
+)
+
+func TestFindAllSmartCase(t *testing.T) {
+	content := "Foo foo FOO fOo"
+
+	tests := []struct {
+		name    string
+		pattern string
+		opts    SearchOptions
+		want    []ByteOffset
+	}{
+		{"lowercase is insensitive", "foo", SearchOptions{SmartCase: true}, []ByteOffset{0, 4, 8, 12}},
+		{"uppercase is sensitive", "Foo", SearchOptions{SmartCase: true}, []ByteOffset{0}},
+		{"mixed case is sensitive", "fOo", SearchOptions{SmartCase: true}, []ByteOffset{12}},
+		{"smartcase overrides case sensitive", "foo", SearchOptions{SmartCase: true, CaseSensitive: true}, []ByteOffset{0, 4, 8, 12}},
+		{"case sensitive", "foo", SearchOptions{CaseSensitive: true}, []ByteOffset{4}},
+		{"case insensitive", "FOO", SearchOptions{}, []ByteOffset{0, 4, 8, 12}},
+		{"regex escapes are not uppercase", `f\w\w\W`, SearchOptions{Regex: true, SmartCase: true}, []ByteOffset{0, 4, 8}},
+		{"regex class name is not uppercase", `\p{Lu}OO`, SearchOptions{Regex: true, SmartCase: true}, []ByteOffset{8}},
+		{"regex literal uppercase", `F\w+`, SearchOptions{Regex: true, SmartCase: true}, []ByteOffset{0, 8}},
+		{"literal metacharacters", "f.o", SearchOptions{SmartCase: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(content))
+			matches, err := e.FindAll(tt.pattern, tt.opts)
+			if err != nil {
+				t.Fatalf("FindAll(%q) error = %v", tt.pattern, err)
+			}
+			var starts []ByteOffset
+			for _, m := range matches {
+				starts = append(starts, m.Start)
+			}
+			if !reflect.DeepEqual(starts, tt.want) {
+				t.Errorf("FindAll(%q) starts = %v, want %v", tt.pattern, starts, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindAllWholeWord(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		pattern string
+		opts    SearchOptions
+		want    []Range
+	}{
+		{
+			name:    "excludes substrings",
+			content: "cat concat cats cat_x cat",
+			pattern: "cat",
+			opts:    SearchOptions{WholeWord: true},
+			want:    []Range{{Start: 0, End: 3}, {Start: 22, End: 25}},
+		},
+		{
+			name:    "punctuation is a boundary",
+			content: "(cat) cat.",
+			pattern: "cat",
+			opts:    SearchOptions{WholeWord: true},
+			want:    []Range{{Start: 1, End: 4}, {Start: 6, End: 9}},
+		},
+		{
+			name:    "unicode letters are word characters",
+			content: "écat cat",
+			pattern: "cat",
+			opts:    SearchOptions{WholeWord: true},
+			want:    []Range{{Start: 6, End: 9}},
+		},
+		{
+			name:    "pattern with non-word edges",
+			content: "a-b x-b",
+			pattern: "-b",
+			opts:    SearchOptions{WholeWord: true},
+			want:    []Range{{Start: 1, End: 3}, {Start: 5, End: 7}},
+		},
+		{
+			name:    "composes with regex and smartcase",
+			content: "Cat cat category CAT",
+			pattern: `c\w+`,
+			opts:    SearchOptions{Regex: true, SmartCase: true, WholeWord: true},
+			want:    []Range{{Start: 0, End: 3}, {Start: 4, End: 7}, {Start: 8, End: 16}, {Start: 17, End: 20}},
+		},
+		{
+			name:    "regex whole word excludes partial",
+			content: "cat category",
+			pattern: `cat`,
+			opts:    SearchOptions{Regex: true, WholeWord: true},
+			want:    []Range{{Start: 0, End: 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(tt.content))
+			got, err := e.FindAll(tt.pattern, tt.opts)
+			if err != nil {
+				t.Fatalf("FindAll(%q) error = %v", tt.pattern, err)
+			}
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindNextPrev(t *testing.T) {
+	e := New(WithContent("one two one two one"))
+	opts := SearchOptions{WholeWord: true}
+
+	m, ok, err := e.FindNext("one", 1, opts)
+	if err != nil || !ok || m.Start != 8 {
+		t.Errorf("FindNext(1) = %v, %v, %v, want start 8", m, ok, err)
+	}
+
+	// Wraps to the start
+	m, ok, _ = e.FindNext("one", 17, opts)
+	if !ok || m.Start != 0 {
+		t.Errorf("FindNext(17) = %v, %v, want start 0", m, ok)
+	}
+
+	m, ok, _ = e.FindPrev("one", 8, opts)
+	if !ok || m.Start != 0 {
+		t.Errorf("FindPrev(8) = %v, %v, want start 0", m, ok)
+	}
+
+	// Wraps to the end
+	m, ok, _ = e.FindPrev("one", 0, opts)
+	if !ok || m.Start != 16 {
+		t.Errorf("FindPrev(0) = %v, %v, want start 16", m, ok)
+	}
+
+	if _, ok, err := e.FindNext("three", 0, opts); ok || err != nil {
+		t.Errorf("FindNext(three) = %v, %v, want no match", ok, err)
+	}
+	if _, _, err := e.FindNext("", 0, opts); !errors.Is(err, ErrEmptyPattern) {
+		t.Errorf("FindNext(\"\") error = %v, want ErrEmptyPattern", err)
+	}
+	if _, err := e.FindAll("(", SearchOptions{Regex: true}); err == nil {
+		t.Error("FindAll with invalid regex should fail")
+	}
+}
+
+func TestFindAllMultiline(t *testing.T) {
+	e := New(WithContent("foo bar\nfoo\nbarfoo"))
+
+	got, err := e.FindAll("^foo", SearchOptions{Regex: true})
+	if err != nil {
+		t.Fatalf("FindAll(^foo) error = %v", err)
+	}
+	want := []Range{{Start: 0, End: 3}, {Start: 8, End: 11}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll(^foo) = %v, want %v", got, want)
+	}
+
+	got, _ = e.FindAll("foo$", SearchOptions{Regex: true})
+	want = []Range{{Start: 8, End: 11}, {Start: 15, End: 18}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll(foo$) = %v, want %v", got, want)
+	}
+
+	// Multiline patterns match across line boundaries
+	got, _ = e.FindAll(`bar\s+foo`, SearchOptions{Regex: true})
+	want = []Range{{Start: 4, End: 11}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll(bar\\s+foo) = %v, want %v", got, want)
+	}
+}
+
+// selectionRanges returns the ranges of the selections in cs.
+func selectionRanges(cs *cursor.CursorSet) []Range {
+	var ranges []Range
+	for _, sel := range cs.All() {
+		ranges = append(ranges, sel.Range())
+	}
+	return ranges
+}
+
+func TestSelectAllMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		pattern string
+		opts    SearchOptions
+		want    []Range
+	}{
+		{
+			name:    "case insensitive",
+			content: "Foo foo food",
+			pattern: "foo",
+			want:    []Range{{Start: 0, End: 3}, {Start: 4, End: 7}, {Start: 8, End: 11}},
+		},
+		{
+			name:    "case sensitive whole word",
+			content: "Foo foo food",
+			pattern: "foo",
+			opts:    SearchOptions{CaseSensitive: true, WholeWord: true},
+			want:    []Range{{Start: 4, End: 7}},
+		},
+		{
+			name:    "touching matches merge",
+			content: "ababx ab",
+			pattern: "ab",
+			want:    []Range{{Start: 0, End: 4}, {Start: 6, End: 8}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(tt.content))
+			cs, err := e.SelectAllMatches(tt.pattern, tt.opts)
+			if err != nil {
+				t.Fatalf("SelectAllMatches(%q) error = %v", tt.pattern, err)
+			}
+			if got := selectionRanges(cs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectAllMatches(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+			if cs.Primary().Head != tt.want[0].End {
+				t.Errorf("primary head = %d, want %d", cs.Primary().Head, tt.want[0].End)
+			}
+		})
+	}
+
+	e := New(WithContent("foo"))
+	if _, err := e.SelectAllMatches("bar", SearchOptions{}); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("SelectAllMatches(bar) error = %v, want ErrNoMatch", err)
+	}
+}
+
+func TestAddCursorAtNextMatch(t *testing.T) {
+	e := New(WithContent("foo bar Foo foo baz foo"))
+	cs := cursor.NewCursorSetAt(13)
+	caseSensitive := SearchOptions{CaseSensitive: true}
+
+	// The first press selects the word under the cursor
+	if err := e.AddCursorAtNextMatch(cs, "", caseSensitive); err != nil {
+		t.Fatalf("AddCursorAtNextMatch() error = %v", err)
+	}
+	want := []Range{{Start: 12, End: 15}}
+	if got := selectionRanges(cs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after 1 = %v, want %v", got, want)
+	}
+
+	// Then occurrences are added one at a time, case-sensitively, wrapping
+	// around to the start of the buffer
+	steps := [][]Range{
+		{{Start: 12, End: 15}, {Start: 20, End: 23}},
+		{{Start: 0, End: 3}, {Start: 12, End: 15}, {Start: 20, End: 23}},
+	}
+	for i, want := range steps {
+		if err := e.AddCursorAtNextMatch(cs, "", caseSensitive); err != nil {
+			t.Fatalf("AddCursorAtNextMatch() step %d error = %v", i+2, err)
+		}
+		if got := selectionRanges(cs); !reflect.DeepEqual(got, want) {
+			t.Fatalf("after %d = %v, want %v", i+2, got, want)
+		}
+	}
+
+	if err := e.AddCursorAtNextMatch(cs, "", caseSensitive); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("AddCursorAtNextMatch() with all selected error = %v, want ErrNoMatch", err)
+	}
+	if cs.Count() != 3 {
+		t.Errorf("Count() = %d, want 3", cs.Count())
+	}
+}
+
+func TestAddCursorAtNextMatchWholeWord(t *testing.T) {
+	e := New(WithContent("foo food Foo foo"))
+
+	// A word expanded from the cursor skips longer words
+	cs := cursor.NewCursorSetAt(1)
+	_ = e.AddCursorAtNextMatch(cs, "", SearchOptions{})
+	if err := e.AddCursorAtNextMatch(cs, "", SearchOptions{}); err != nil {
+		t.Fatalf("AddCursorAtNextMatch() error = %v", err)
+	}
+	want := []Range{{Start: 0, End: 3}, {Start: 9, End: 12}}
+	if got := selectionRanges(cs); !reflect.DeepEqual(got, want) {
+		t.Errorf("case-insensitive = %v, want %v", got, want)
+	}
+
+	cs = cursor.NewCursorSetAt(1)
+	_ = e.AddCursorAtNextMatch(cs, "", SearchOptions{CaseSensitive: true})
+	if err := e.AddCursorAtNextMatch(cs, "", SearchOptions{CaseSensitive: true}); err != nil {
+		t.Fatalf("AddCursorAtNextMatch() error = %v", err)
+	}
+	want = []Range{{Start: 0, End: 3}, {Start: 13, End: 16}}
+	if got := selectionRanges(cs); !reflect.DeepEqual(got, want) {
+		t.Errorf("case-sensitive = %v, want %v", got, want)
+	}
+
+	// A selection that is part of a word matches inside words
+	cs = cursor.NewCursorSetFromSlice([]Selection{cursor.NewSelection(4, 6)})
+	if err := e.AddCursorAtNextMatch(cs, "", SearchOptions{}); err != nil {
+		t.Fatalf("AddCursorAtNextMatch() error = %v", err)
+	}
+	want = []Range{{Start: 4, End: 6}, {Start: 9, End: 11}}
+	if got := selectionRanges(cs); !reflect.DeepEqual(got, want) {
+		t.Errorf("partial word = %v, want %v", got, want)
+	}
+}
+
+func TestAddCursorAtNextMatchPattern(t *testing.T) {
+	e := New(WithContent("x.y x.y xy"))
+	cs := cursor.NewCursorSetAt(0)
+
+	if err := e.AddCursorAtNextMatch(cs, "x.y", SearchOptions{}); err != nil {
+		t.Fatalf("AddCursorAtNextMatch(x.y) error = %v", err)
+	}
+	if err := e.AddCursorAtNextMatch(cs, "x.y", SearchOptions{}); err != nil {
+		t.Fatalf("AddCursorAtNextMatch(x.y) error = %v", err)
+	}
+	// The cursor at 0 merges into the first match; "xy" does not match
+	want := []Range{{Start: 0, End: 3}, {Start: 4, End: 7}}
+	if got := selectionRanges(cs); !reflect.DeepEqual(got, want) {
+		t.Errorf("selections = %v, want %v", got, want)
+	}
+
+	// Not on a word
+	blank := cursor.NewCursorSetAt(3)
+	if err := New(WithContent("foo   ")).AddCursorAtNextMatch(blank, "", SearchOptions{}); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("AddCursorAtNextMatch() on blank error = %v, want ErrNoMatch", err)
+	}
+}
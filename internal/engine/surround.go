@@ -0,0 +1,295 @@
+package engine
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AnyTag is a surround pair that matches any HTML or XML tag pair, for
+// ChangeSurround and DeleteSurround.
+var AnyTag = [2]string{"<>", "</>"}
+
+// tagPattern matches an opening, closing or self-closing tag, capturing the
+// slash of a closing tag, the name, and the slash of a self-closing tag.
+var tagPattern = regexp.MustCompile(`<(/?)([A-Za-z][\w:.-]*)(?:\s[^<>]*?)?(/?)>`)
+
+// surroundSpan locates a surrounding pair: the open delimiter is
+// [openStart, openEnd) and the close delimiter [closeStart, closeEnd).
+type surroundSpan struct {
+	openStart, openEnd   ByteOffset
+	closeStart, closeEnd ByteOffset
+}
+
+// contains returns true if offset is on or between the delimiters.
+func (s surroundSpan) contains(offset ByteOffset) bool {
+	return s.openStart <= offset && offset < s.closeEnd
+}
+
+// size returns the length of the span.
+func (s surroundSpan) size() ByteOffset {
+	return s.closeEnd - s.openStart
+}
+
+// Surround inserts open before and close after a range as a single undo
+// unit, as vim-surround's ys. If close is empty it is derived from open:
+// the closing tag of a tag such as `<div class="x">`, the closing bracket
+// of an opening bracket, or open itself.
+func (e *Engine) Surround(selection Range, open, close string) error {
+	if selection.End < selection.Start {
+		return ErrRangeInvalid
+	}
+	if close == "" {
+		close = surroundClose(open)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.readOnly {
+		return ErrReadOnly
+	}
+	if selection.End > e.buf.Len() {
+		return ErrOffsetOutOfRange
+	}
+
+	return e.applyGroupedEditsLocked("Surround", []Edit{
+		{Range: Range{Start: selection.End, End: selection.End}, NewText: close},
+		{Range: Range{Start: selection.Start, End: selection.Start}, NewText: open},
+	})
+}
+
+// ChangeSurround replaces the innermost fromPair enclosing offset with
+// toPair as a single undo unit, as vim-surround's cs. Offset may be on
+// either delimiter. Tags in fromPair match regardless of attributes, and
+// AnyTag matches any tag. An empty close in either pair is derived from its
+// open as for Surround. Returns ErrNoSurroundingPair if offset is not
+// enclosed by fromPair.
+func (e *Engine) ChangeSurround(offset ByteOffset, fromPair, toPair [2]string) error {
+	if fromPair[1] == "" {
+		fromPair[1] = surroundClose(fromPair[0])
+	}
+	if toPair[1] == "" {
+		toPair[1] = surroundClose(toPair[0])
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.readOnly {
+		return ErrReadOnly
+	}
+
+	span, ok := e.findSurroundLocked(offset, fromPair)
+	if !ok {
+		return ErrNoSurroundingPair
+	}
+	return e.applyGroupedEditsLocked("Change surround", []Edit{
+		{Range: Range{Start: span.closeStart, End: span.closeEnd}, NewText: toPair[1]},
+		{Range: Range{Start: span.openStart, End: span.openEnd}, NewText: toPair[0]},
+	})
+}
+
+// DeleteSurround removes the innermost pair enclosing offset as a single
+// undo unit, as vim-surround's ds. Pairs match as for ChangeSurround.
+// Returns ErrNoSurroundingPair if offset is not enclosed by pair.
+func (e *Engine) DeleteSurround(offset ByteOffset, pair [2]string) error {
+	if pair[1] == "" {
+		pair[1] = surroundClose(pair[0])
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.readOnly {
+		return ErrReadOnly
+	}
+
+	span, ok := e.findSurroundLocked(offset, pair)
+	if !ok {
+		return ErrNoSurroundingPair
+	}
+	return e.applyGroupedEditsLocked("Delete surround", []Edit{
+		{Range: Range{Start: span.closeStart, End: span.closeEnd}},
+		{Range: Range{Start: span.openStart, End: span.openEnd}},
+	})
+}
+
+// findSurroundLocked finds the innermost pair enclosing offset.
+// Caller must hold the lock.
+func (e *Engine) findSurroundLocked(offset ByteOffset, pair [2]string) (surroundSpan, bool) {
+	if pair[0] == "" || pair[1] == "" || offset < 0 || offset > e.buf.Len() {
+		return surroundSpan{}, false
+	}
+
+	if name, ok := tagName(pair[0]); ok || pair == AnyTag {
+		return findTagSurround(e.buf.Text(), offset, name)
+	}
+
+	if pair[0] == pair[1] {
+		// Quotes pair up within a line
+		line := e.buf.OffsetToPoint(offset).Line
+		start := e.buf.LineStartOffset(line)
+		text := e.buf.TextRange(start, e.buf.LineEndOffset(line))
+		span, ok := findQuoteSurround(text, offset-start, pair[0])
+		if !ok {
+			return surroundSpan{}, false
+		}
+		span.openStart += start
+		span.openEnd += start
+		span.closeStart += start
+		span.closeEnd += start
+		return span, true
+	}
+
+	return findPairSurround(e.buf.Text(), offset, pair[0], pair[1])
+}
+
+// findPairSurround finds the innermost open/close pair in text enclosing
+// offset, respecting nesting.
+func findPairSurround(text string, offset ByteOffset, open, close string) (surroundSpan, bool) {
+	var best surroundSpan
+	found := false
+	var stack []ByteOffset
+
+	for i := 0; i < len(text); {
+		switch {
+		case strings.HasPrefix(text[i:], open):
+			stack = append(stack, ByteOffset(i))
+			i += len(open)
+		case strings.HasPrefix(text[i:], close):
+			if len(stack) > 0 {
+				start := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				span := surroundSpan{
+					openStart:  start,
+					openEnd:    start + ByteOffset(len(open)),
+					closeStart: ByteOffset(i),
+					closeEnd:   ByteOffset(i + len(close)),
+				}
+				if span.contains(offset) && (!found || span.size() < best.size()) {
+					best, found = span, true
+				}
+			}
+			i += len(close)
+		default:
+			i++
+		}
+	}
+	return best, found
+}
+
+// findQuoteSurround finds the quote pair in a line enclosing offset.
+// Quotes pair up from the start of the line; escaped quotes are skipped.
+func findQuoteSurround(line string, offset ByteOffset, quote string) (surroundSpan, bool) {
+	var open ByteOffset = -1
+	for i := 0; i < len(line); {
+		if line[i] == '\\' {
+			i += 2
+			continue
+		}
+		if !strings.HasPrefix(line[i:], quote) {
+			i++
+			continue
+		}
+		if open < 0 {
+			open = ByteOffset(i)
+		} else {
+			span := surroundSpan{
+				openStart:  open,
+				openEnd:    open + ByteOffset(len(quote)),
+				closeStart: ByteOffset(i),
+				closeEnd:   ByteOffset(i + len(quote)),
+			}
+			if span.contains(offset) {
+				return span, true
+			}
+			open = -1
+		}
+		i += len(quote)
+	}
+	return surroundSpan{}, false
+}
+
+// findTagSurround finds the innermost element in text enclosing offset,
+// with the given tag name or any name if name is empty. Unclosed tags,
+// such as <br>, are ignored.
+func findTagSurround(text string, offset ByteOffset, name string) (surroundSpan, bool) {
+	type openTag struct {
+		name       string
+		start, end ByteOffset
+	}
+
+	var best surroundSpan
+	found := false
+	var stack []openTag
+
+	for _, m := range tagPattern.FindAllStringSubmatchIndex(text, -1) {
+		closing := m[3] > m[2]
+		selfClosing := m[7] > m[6]
+		tag := text[m[4]:m[5]]
+		switch {
+		case selfClosing:
+			continue
+		case !closing:
+			stack = append(stack, openTag{name: tag, start: ByteOffset(m[0]), end: ByteOffset(m[1])})
+			continue
+		}
+
+		// Match the nearest open tag of the same name, dropping unclosed
+		// tags opened after it
+		i := len(stack) - 1
+		for i >= 0 && stack[i].name != tag {
+			i--
+		}
+		if i < 0 {
+			continue
+		}
+		open := stack[i]
+		stack = stack[:i]
+
+		span := surroundSpan{
+			openStart:  open.start,
+			openEnd:    open.end,
+			closeStart: ByteOffset(m[0]),
+			closeEnd:   ByteOffset(m[1]),
+		}
+		if (name == "" || name == tag) && span.contains(offset) && (!found || span.size() < best.size()) {
+			best, found = span, true
+		}
+	}
+	return best, found
+}
+
+// tagName returns the name of an opening or closing tag such as
+// `<div class="x">` or `</div>`.
+func tagName(s string) (string, bool) {
+	m := tagPattern.FindStringSubmatch(s)
+	if m == nil || len(m[0]) != len(s) {
+		return "", false
+	}
+	return m[2], true
+}
+
+// surroundClose returns the close delimiter for open: its closing tag or
+// bracket, or open itself.
+func surroundClose(open string) string {
+	if tag := closingTag(open); tag != "" {
+		return tag
+	}
+	for _, pair := range DefaultBracketPairs {
+		if open == string(pair.Open) {
+			return string(pair.Close)
+		}
+	}
+	return open
+}
+
+// closingTag returns the closing tag for an opening tag, or "" if open is
+// not an opening tag.
+func closingTag(open string) string {
+	m := tagPattern.FindStringSubmatch(open)
+	if m == nil || len(m[0]) != len(open) || m[1] != "" || m[3] != "" {
+		return ""
+	}
+	return "</" + m[2] + ">"
+}
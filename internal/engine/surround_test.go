@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSurround(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		start, end  ByteOffset
+		open, close string
+		want        string
+	}{
+		{"quotes", "say hello now", 4, 9, `"`, "", `say "hello" now`},
+		{"bracket", "f x", 2, 3, "(", "", "f (x)"},
+		{"explicit close", "x", 0, 1, "{{ ", " }}", "{{ x }}"},
+		{"tag", "hi", 0, 2, `<a href="/">`, "", `<a href="/">hi</a>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(tt.content))
+			if err := e.Surround(Range{Start: tt.start, End: tt.end}, tt.open, tt.close); err != nil {
+				t.Fatalf("Surround() error = %v", err)
+			}
+			if e.Text() != tt.want {
+				t.Errorf("Surround() = %q, want %q", e.Text(), tt.want)
+			}
+
+			// The whole surround undoes at once
+			if err := e.Undo(); err != nil {
+				t.Fatal(err)
+			}
+			if e.Text() != tt.content {
+				t.Errorf("after undo = %q, want %q", e.Text(), tt.content)
+			}
+		})
+	}
+}
+
+func TestChangeSurround(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		offset   ByteOffset
+		from, to [2]string
+		want     string
+	}{
+		{"quotes", `x = "abc"`, 6, [2]string{`"`, `"`}, [2]string{"'", "'"}, `x = 'abc'`},
+		{"on open quote", `x = "abc"`, 4, [2]string{`"`}, [2]string{"'"}, `x = 'abc'`},
+		{"second quoted string", `"a" + "b"`, 7, [2]string{`"`}, [2]string{"`"}, "\"a\" + `b`"},
+		{"escaped quote", `"a\"b"`, 3, [2]string{`"`}, [2]string{"'"}, `'a\"b'`},
+		{"nested brackets", "f(g(x), y)", 8, [2]string{"(", ")"}, [2]string{"[", "]"}, "f[g(x), y]"},
+		{"innermost bracket", "f(g(x), y)", 4, [2]string{"("}, [2]string{"{"}, "f(g{x}, y)"},
+		{"bracket to tag", "(x)", 1, [2]string{"("}, [2]string{"<b>"}, "<b>x</b>"},
+		{"tag", `<div class="a"><p>hi</p></div>`, 18, [2]string{"<p>", "</p>"}, [2]string{"<em>"}, `<div class="a"><em>hi</em></div>`},
+		{"tag with attributes", `<div class="a"><p>hi</p></div>`, 18, [2]string{"<div>"}, [2]string{"<section>"}, "<section><p>hi</p></section>"},
+		{"any tag", `<ul><li>x<br></li></ul>`, 8, AnyTag, [2]string{"<b>"}, `<ul><b>x<br></b></ul>`},
+		{"on closing tag", "<i>x</i>", 5, AnyTag, [2]string{"<b>"}, "<b>x</b>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(tt.content))
+			if err := e.ChangeSurround(tt.offset, tt.from, tt.to); err != nil {
+				t.Fatalf("ChangeSurround() error = %v", err)
+			}
+			if e.Text() != tt.want {
+				t.Errorf("ChangeSurround() = %q, want %q", e.Text(), tt.want)
+			}
+			if e.UndoCount() != 1 {
+				t.Errorf("UndoCount() = %d, want 1", e.UndoCount())
+			}
+		})
+	}
+}
+
+func TestDeleteSurround(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		offset  ByteOffset
+		pair    [2]string
+		want    string
+	}{
+		{"quotes", `print("hi")`, 8, [2]string{`"`}, "print(hi)"},
+		{"brackets", "a[b[c]]", 5, [2]string{"["}, "a[bc]"},
+		{"multi-char pair", "{{ name }}", 4, [2]string{"{{ ", " }}"}, "name"},
+		{"tag", "<p>a <b>b</b> c</p>", 3, [2]string{"<p>", "</p>"}, "a <b>b</b> c"},
+		{"nested tags", "<div><div>x</div></div>", 10, AnyTag, "<div>x</div>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithContent(tt.content))
+			if err := e.DeleteSurround(tt.offset, tt.pair); err != nil {
+				t.Fatalf("DeleteSurround() error = %v", err)
+			}
+			if e.Text() != tt.want {
+				t.Errorf("DeleteSurround() = %q, want %q", e.Text(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSurroundErrors(t *testing.T) {
+	e := New(WithContent(`"a" b (c)`))
+
+	if err := e.DeleteSurround(4, [2]string{`"`}); !errors.Is(err, ErrNoSurroundingPair) {
+		t.Errorf("DeleteSurround(outside quotes) error = %v, want ErrNoSurroundingPair", err)
+	}
+	if err := e.ChangeSurround(1, [2]string{"("}, [2]string{"["}); !errors.Is(err, ErrNoSurroundingPair) {
+		t.Errorf("ChangeSurround(outside brackets) error = %v, want ErrNoSurroundingPair", err)
+	}
+	if err := e.DeleteSurround(1, AnyTag); !errors.Is(err, ErrNoSurroundingPair) {
+		t.Errorf("DeleteSurround(no tags) error = %v, want ErrNoSurroundingPair", err)
+	}
+	if err := e.Surround(Range{Start: 2, End: 1}, "(", ")"); !errors.Is(err, ErrRangeInvalid) {
+		t.Errorf("Surround(reversed) error = %v, want ErrRangeInvalid", err)
+	}
+
+	e = New(WithContent("x"), WithReadOnly())
+	if err := e.Surround(Range{Start: 0, End: 1}, "(", ")"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Surround(read-only) error = %v, want ErrReadOnly", err)
+	}
+}
@@ -0,0 +1,192 @@
+package engine
+
+import "github.com/dshills/keystorm/internal/engine/buffer"
+
+// TokenState is a tokenizer's state at a line boundary, carrying constructs
+// that span lines such as block comments and multi-line strings from one
+// line to the next. The zero value is the state at the start of the buffer.
+type TokenState uint32
+
+// TokenSpan is a styled span of a line.
+type TokenSpan struct {
+	// Line is the line number (0-indexed).
+	Line uint32
+
+	// StartCol and EndCol are the byte columns of the span; EndCol is
+	// exclusive.
+	StartCol uint32
+	EndCol   uint32
+
+	// Style is the scope name of the span (e.g., "keyword", "string").
+	Style string
+}
+
+// Tokenizer splits lines into styled spans. The engine calls it one line at
+// a time, threading the state from the end of each line into the next, so
+// that only the lines affected by an edit need to be tokenized again.
+// Implementations may be regex or grammar based.
+type Tokenizer interface {
+	// TokenizeLine returns the spans of a line, without its line ending,
+	// given the state at the start of the line, along with the state at its
+	// end. The Line field of the returned spans is filled in by the engine.
+	TokenizeLine(text string, state TokenState) ([]TokenSpan, TokenState)
+}
+
+// tokenLine is the cached tokenization of a line. The Line field of its
+// spans is set when they are returned, as lines shift with edits.
+type tokenLine struct {
+	spans []TokenSpan
+	end   TokenState
+}
+
+// SetTokenizer sets the tokenizer used for syntax highlighting and
+// tokenizes the buffer. From then on, each edit re-tokenizes the lines it
+// touched, and the lines after them until the state at a line end matches
+// the state cached before the edit. Lines longer than the maximum line scan
+// length are not tokenized. A nil tokenizer removes the tokens.
+func (e *Engine) SetTokenizer(t Tokenizer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.tokenizer = t
+	e.tokenLines = nil
+	e.staleLines = nil
+	if t == nil {
+		return
+	}
+
+	snap := e.buf.Snapshot()
+	e.tokenLines = make([]tokenLine, snap.LineCount())
+	var state TokenState
+	for line := range e.tokenLines {
+		state = e.tokenizeLineLocked(snap, uint32(line), state)
+	}
+}
+
+// Tokens returns the spans of the lines from startLine to endLine
+// (0-indexed, inclusive), in order. Line numbers are clamped to the
+// buffer. Returns nil if no tokenizer is set.
+func (e *Engine) Tokens(startLine, endLine int) []TokenSpan {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if startLine < 0 {
+		startLine = 0
+	}
+	if last := len(e.tokenLines) - 1; endLine > last {
+		endLine = last
+	}
+
+	var result []TokenSpan
+	for line := startLine; line <= endLine; line++ {
+		for _, span := range e.tokenLines[line].spans {
+			span.Line = uint32(line)
+			result = append(result, span)
+		}
+	}
+	return result
+}
+
+// staleTokens is a range of lines whose tokens were dropped by an edit and
+// still have to be tokenized.
+type staleTokens struct {
+	// start and end are the first and last stale line (inclusive).
+	start, end int
+
+	// before is the state cached at the end of the last replaced line
+	// before the edit.
+	before TokenState
+}
+
+// retokenize keeps the token cache in sync with edits. The lines replaced
+// by the edit are tokenized again, then the following lines are re-scanned
+// as long as the state entering them differs from before the edit, e.g.
+// after a string is opened or closed. For the edits of a batch only the
+// cache is spliced until the last edit, whose snapshot holds the content
+// after the batch, and the stale lines are tokenized then. It is
+// registered as a buffer change listener and therefore runs with the
+// engine write lock held.
+func (e *Engine) retokenize(change buffer.BufferChange) {
+	if e.tokenizer == nil {
+		return
+	}
+
+	// Lines StartLine..OldEndLine are replaced by StartLine..NewEndLine
+	start, oldEnd, newEnd := int(change.StartLine), int(change.OldEndLine), int(change.NewEndLine)
+	if oldEnd >= len(e.tokenLines) {
+		oldEnd = len(e.tokenLines) - 1
+	}
+	before := e.tokenLines[oldEnd].end
+
+	lines := make([]tokenLine, 0, len(e.tokenLines)+newEnd-oldEnd)
+	lines = append(lines, e.tokenLines[:start]...)
+	lines = append(lines, make([]tokenLine, newEnd-start+1)...)
+	lines = append(lines, e.tokenLines[oldEnd+1:]...)
+	e.tokenLines = lines
+	e.markStaleLocked(staleTokens{start: start, end: newEnd, before: before}, newEnd-oldEnd)
+
+	if change.Remaining > 0 {
+		return
+	}
+	stale := e.staleLines
+	e.staleLines = nil
+	for i, r := range stale {
+		limit := len(e.tokenLines)
+		if i+1 < len(stale) {
+			limit = stale[i+1].start
+		}
+		e.tokenizeStaleLocked(change.After, r, limit)
+	}
+}
+
+// markStaleLocked records the lines of an edit as stale. The edits of a
+// batch arrive highest offset first, so the ranges recorded before lie
+// after the edit and shift by its change in line count, delta. A range
+// starting on the edit's last line is merged into it. Caller must hold
+// the lock.
+func (e *Engine) markStaleLocked(r staleTokens, delta int) {
+	for i := range e.staleLines {
+		e.staleLines[i].start += delta
+		e.staleLines[i].end += delta
+	}
+	if len(e.staleLines) > 0 && e.staleLines[0].start <= r.end {
+		r.end, r.before = e.staleLines[0].end, e.staleLines[0].before
+		e.staleLines = e.staleLines[1:]
+	}
+	e.staleLines = append([]staleTokens{r}, e.staleLines...)
+}
+
+// tokenizeStaleLocked tokenizes the stale lines r of snap, then the lines
+// after them, up to limit, while the state at a line end differs from the
+// state cached before the edit. Caller must hold the lock.
+func (e *Engine) tokenizeStaleLocked(snap *buffer.Snapshot, r staleTokens, limit int) {
+	var state TokenState
+	if r.start > 0 {
+		state = e.tokenLines[r.start-1].end
+	}
+	for line := r.start; line <= r.end; line++ {
+		state = e.tokenizeLineLocked(snap, uint32(line), state)
+	}
+
+	// Lines after the edit only change if the state entering them did
+	before := r.before
+	for line := r.end + 1; line < limit && state != before; line++ {
+		before = e.tokenLines[line].end
+		state = e.tokenizeLineLocked(snap, uint32(line), state)
+	}
+}
+
+// tokenizeLineLocked tokenizes line of snap starting in state, caches the
+// result and returns the state at the end of the line. Caller must hold
+// the lock.
+func (e *Engine) tokenizeLineLocked(snap *buffer.Snapshot, line uint32, state TokenState) TokenState {
+	// Long lines are left unstyled and carry the state through unchanged
+	if e.longLineLocked(snap, line) {
+		e.tokenLines[line] = tokenLine{end: state}
+		return state
+	}
+
+	spans, end := e.tokenizer.TokenizeLine(snap.LineText(line), state)
+	e.tokenLines[line] = tokenLine{spans: spans, end: end}
+	return end
+}
@@ -0,0 +1,185 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// stringTokenizer styles double-quoted strings, which may span lines, and
+// records the lines it is given.
+type stringTokenizer struct {
+	seen []string
+}
+
+const inString TokenState = 1
+
+func (t *stringTokenizer) TokenizeLine(text string, state TokenState) ([]TokenSpan, TokenState) {
+	t.seen = append(t.seen, text)
+
+	var spans []TokenSpan
+	start := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '"' {
+			continue
+		}
+		if state == inString {
+			spans = append(spans, TokenSpan{StartCol: uint32(start), EndCol: uint32(i + 1), Style: "string"})
+			state = 0
+		} else {
+			start = i
+			state = inString
+		}
+	}
+	if state == inString {
+		spans = append(spans, TokenSpan{StartCol: uint32(start), EndCol: uint32(len(text)), Style: "string"})
+	}
+	return spans, state
+}
+
+func (t *stringTokenizer) reset() []string {
+	seen := t.seen
+	t.seen = nil
+	return seen
+}
+
+// stringLines returns the lines that have a string span.
+func stringLines(e *Engine) []uint32 {
+	var lines []uint32
+	for _, span := range e.Tokens(0, int(e.LineCount())-1) {
+		if span.Style == "string" && (len(lines) == 0 || lines[len(lines)-1] != span.Line) {
+			lines = append(lines, span.Line)
+		}
+	}
+	return lines
+}
+
+func TestTokensIncremental(t *testing.T) {
+	e := New(WithContent("a := 1\nb := \"x\"\nc := 2\nd := 3\ne := 4"))
+	tok := &stringTokenizer{}
+	e.SetTokenizer(tok)
+
+	if got := tok.reset(); len(got) != 5 {
+		t.Fatalf("initial tokenization saw %d lines, want 5", len(got))
+	}
+	want := []TokenSpan{{Line: 1, StartCol: 5, EndCol: 8, Style: "string"}}
+	if got := e.Tokens(1, 1); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokens(1, 1) = %+v, want %+v", got, want)
+	}
+
+	// An edit inside a line only re-tokenizes that line
+	if _, err := e.Insert(e.LineStartOffset(2)+5, "4"); err != nil {
+		t.Fatal(err)
+	}
+	if got := tok.reset(); !reflect.DeepEqual(got, []string{"c := 42"}) {
+		t.Errorf("re-tokenized %q, want only the edited line", got)
+	}
+
+	// Inserting lines shifts the cached tokens after them
+	if _, err := e.Insert(0, "x\ny\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := tok.reset(); len(got) != 3 {
+		t.Errorf("re-tokenized %q, want the 3 lines touched by the insert", got)
+	}
+	if got := stringLines(e); !reflect.DeepEqual(got, []uint32{3}) {
+		t.Errorf("string lines = %v, want [3]", got)
+	}
+}
+
+func TestTokensMultiLineString(t *testing.T) {
+	e := New(WithContent("a\nb\nc\nd"))
+	tok := &stringTokenizer{}
+	e.SetTokenizer(tok)
+	tok.reset()
+
+	// Opening a string re-scans the following lines it now covers
+	if _, err := e.Insert(e.LineStartOffset(1), "\""); err != nil {
+		t.Fatal(err)
+	}
+	if got := tok.reset(); !reflect.DeepEqual(got, []string{"\"b", "c", "d"}) {
+		t.Errorf("re-tokenized %q, want the rest of the buffer", got)
+	}
+	if got := stringLines(e); !reflect.DeepEqual(got, []uint32{1, 2, 3}) {
+		t.Errorf("string lines = %v, want [1 2 3]", got)
+	}
+
+	// Closing it stops at the first line whose end state is unchanged
+	if _, err := e.Insert(e.LineEndOffset(2), "\""); err != nil {
+		t.Fatal(err)
+	}
+	if got := tok.reset(); !reflect.DeepEqual(got, []string{"c\"", "d"}) {
+		t.Errorf("re-tokenized %q, want the closed line and the next", got)
+	}
+	if got := stringLines(e); !reflect.DeepEqual(got, []uint32{1, 2}) {
+		t.Errorf("string lines = %v, want [1 2]", got)
+	}
+
+	// Undo restores the tokens of the restored text
+	if err := e.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := stringLines(e); !reflect.DeepEqual(got, []uint32{1, 2, 3}) {
+		t.Errorf("string lines after undo = %v, want [1 2 3]", got)
+	}
+
+	// Deleting the lines of the string leaves a single line to tokenize
+	tok.reset()
+	if err := e.Delete(e.LineStartOffset(1), e.LineStartOffset(3)); err != nil {
+		t.Fatal(err)
+	}
+	if got := stringLines(e); len(got) != 0 {
+		t.Errorf("string lines after delete = %v, want none", got)
+	}
+	if got := tok.reset(); !reflect.DeepEqual(got, []string{"d"}) {
+		t.Errorf("re-tokenized %q after delete, want [\"d\"]", got)
+	}
+}
+
+func TestTokensApplyEdits(t *testing.T) {
+	e := New(WithContent("a\nb\nc\nd\ne"))
+	tok := &stringTokenizer{}
+	e.SetTokenizer(tok)
+	tok.reset()
+
+	// The batch opens a string on line 3, adds lines before it and edits
+	// line 0 twice; the touched lines are tokenized once, against the
+	// final text
+	edits := []Edit{
+		{Range: Range{Start: 7, End: 7}, NewText: "\""},
+		{Range: Range{Start: 2, End: 2}, NewText: "x\ny\n"},
+		{Range: Range{Start: 1, End: 1}, NewText: "\""},
+		{Range: Range{Start: 0, End: 0}, NewText: "\""},
+	}
+	if err := e.ApplyEdits(edits); err != nil {
+		t.Fatalf("ApplyEdits failed: %v", err)
+	}
+	if got := e.Text(); got != "\"a\"\nx\ny\nb\nc\nd\"\ne" {
+		t.Fatalf("Text() = %q", got)
+	}
+	want := []string{"\"a\"", "x", "y", "b", "d\"", "e"}
+	if got := tok.reset(); !reflect.DeepEqual(got, want) {
+		t.Errorf("re-tokenized %q, want %q", got, want)
+	}
+
+	fresh := New(WithContent(e.Text()))
+	fresh.SetTokenizer(&stringTokenizer{})
+	if got, want := e.Tokens(0, 6), fresh.Tokens(0, 6); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokens() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTokensWithoutTokenizer(t *testing.T) {
+	e := New(WithContent("a\n\"b\""))
+	if got := e.Tokens(0, 1); got != nil {
+		t.Errorf("Tokens without tokenizer = %v, want nil", got)
+	}
+
+	e.SetTokenizer(&stringTokenizer{})
+	if got := e.Tokens(-5, 100); len(got) != 1 {
+		t.Errorf("Tokens(-5, 100) = %v, want 1 span", got)
+	}
+	e.SetTokenizer(nil)
+	if got := e.Tokens(0, 1); got != nil {
+		t.Errorf("Tokens after SetTokenizer(nil) = %v, want nil", got)
+	}
+}
@@ -192,51 +192,112 @@ func computeLineDiffFromLines(oldLines, newLines []string, opts DiffOptions) Dif
 	n := len(oldLines)
 	m := len(newLines)
 
-	// Check line count limits
+	// Fall back to heuristic diff for large or memory-intensive inputs.
+	// Myers uses O((n+m) * d) memory where d is the edit distance; in the
+	// worst case d = n + m.
+	if useHeuristicDiff(n, m, opts) {
+		return heuristicDiff(oldLines, newLines, opts)
+	}
+
+	// Run Myers diff
+	script := myersDiff(oldLines, newLines, opts)
+
+	// Convert edit script to hunks with context
+	hunks := buildHunks(oldLines, newLines, script, opts.ContextLines)
+
+	return DiffResult{
+		Hunks:        hunks,
+		OldLineCount: n,
+		NewLineCount: m,
+	}
+}
+
+// useHeuristicDiff returns true if diffing n old and m new lines exceeds the
+// line or memory limits of opts, so heuristicDiff must be used instead of
+// Myers.
+func useHeuristicDiff(n, m int, opts DiffOptions) bool {
 	maxLines := opts.MaxLines
 	if maxLines == 0 {
 		maxLines = DefaultMaxDiffLines
 	}
 	if maxLines > 0 && (n > maxLines || m > maxLines) {
-		// Fall back to heuristic diff for large inputs
-		return heuristicDiff(oldLines, newLines, opts)
+		return true
 	}
 
-	// Check estimated memory usage
-	// Myers algorithm uses O((n+m) * d) memory where d is the edit distance
-	// In worst case, d = n + m, so memory is O((n+m)^2) integers
-	// Each V vector copy is 2*(n+m)+1 integers = 8 bytes each on 64-bit
 	maxMemMB := opts.MaxMemoryMB
 	if maxMemMB == 0 {
 		maxMemMB = DefaultMaxDiffMemoryMB
 	}
 	if maxMemMB > 0 {
 		maxD := n + m
-		// Estimated memory: maxD iterations * (2*maxD+1) * 8 bytes per int
 		estimatedBytes := int64(maxD) * int64(2*maxD+1) * 8
-		estimatedMB := estimatedBytes / (1024 * 1024)
-		if estimatedMB > int64(maxMemMB) {
-			// Fall back to heuristic diff for memory-intensive inputs
-			return heuristicDiff(oldLines, newLines, opts)
+		if estimatedBytes/(1024*1024) > int64(maxMemMB) {
+			return true
 		}
 	}
+	return false
+}
 
-	// Run Myers diff
-	script := myersDiff(oldLines, newLines, opts)
+// LineEdit is a single step of an edit script transforming an old sequence
+// of lines into a new one.
+type LineEdit struct {
+	// Type is DiffEqual, DiffInsert or DiffDelete.
+	Type DiffType
 
-	// Convert edit script to hunks with context
-	hunks := buildHunks(oldLines, newLines, script, opts.ContextLines)
+	// OldIndex is the index in the old sequence, or -1 for an insert.
+	OldIndex int
 
-	return DiffResult{
-		Hunks:        hunks,
-		OldLineCount: n,
-		NewLineCount: m,
+	// NewIndex is the index in the new sequence, or -1 for a delete.
+	NewIndex int
+}
+
+// ComputeLineEdits returns the edit script transforming oldRope into newRope,
+// with one step per line. Unlike ComputeLineDiff it includes unchanged
+// lines, which makes it suitable for aligning the two texts side by side.
+func ComputeLineEdits(oldRope, newRope rope.Rope, opts DiffOptions) []LineEdit {
+	return ComputeEdits(toLines(oldRope), toLines(newRope), opts)
+}
+
+// ComputeEdits returns the edit script transforming oldItems into newItems.
+// The items are usually lines but may be any tokens, such as the words of a
+// line for an intra-line diff.
+func ComputeEdits(oldItems, newItems []string, opts DiffOptions) []LineEdit {
+	var ops []editOp
+	if useHeuristicDiff(len(oldItems), len(newItems), opts) {
+		ops = heuristicOps(oldItems, newItems, opts)
+	} else {
+		ops = myersDiff(oldItems, newItems, opts)
 	}
+
+	edits := make([]LineEdit, len(ops))
+	for i, op := range ops {
+		edit := LineEdit{Type: op.op, OldIndex: op.oldIndex, NewIndex: op.newIndex}
+		switch op.op {
+		case DiffInsert:
+			edit.OldIndex = -1
+		case DiffDelete:
+			edit.NewIndex = -1
+		}
+		edits[i] = edit
+	}
+	return edits
 }
 
 // heuristicDiff provides a simple line-by-line diff for large inputs.
 // It's less optimal than Myers but uses O(n+m) memory.
 func heuristicDiff(oldLines, newLines []string, opts DiffOptions) DiffResult {
+	ops := heuristicOps(oldLines, newLines, opts)
+	hunks := buildHunks(oldLines, newLines, ops, opts.ContextLines)
+
+	return DiffResult{
+		Hunks:        hunks,
+		OldLineCount: len(oldLines),
+		NewLineCount: len(newLines),
+	}
+}
+
+// heuristicOps builds the edit script for heuristicDiff.
+func heuristicOps(oldLines, newLines []string, opts DiffOptions) []editOp {
 	n := len(oldLines)
 	m := len(newLines)
 
@@ -289,13 +350,7 @@ func heuristicDiff(oldLines, newLines []string, opts DiffOptions) DiffResult {
 		}
 	}
 
-	hunks := buildHunks(oldLines, newLines, ops, opts.ContextLines)
-
-	return DiffResult{
-		Hunks:        hunks,
-		OldLineCount: n,
-		NewLineCount: m,
-	}
+	return ops
 }
 
 // normalizeLineForDiff normalizes a line based on diff options.
@@ -43,6 +43,13 @@
 //	// Later, get changes since that snapshot
 //	changes, err := tracker.DiffSinceSnapshot(snapID, currentRope)
 //
+// Snapshots pin the rope nodes they reference. Long sessions can bound
+// them with an eviction policy; operations on an evicted snapshot return
+// ErrSnapshotEvicted:
+//
+//	tracker.SetSnapshotLimit(50, 30*time.Minute)
+//	tracker.PinSnapshot(snapID) // never evicted
+//
 // # Diffing
 //
 // Compute line-level diffs for AI context:
@@ -51,6 +58,17 @@
 //	    ContextLines: 3,
 //	})
 //
+// ComputeLineEdits returns the full edit script, including unchanged
+// lines, for aligning two texts side by side. ComputeEdits diffs arbitrary
+// tokens, such as the words of a line.
+//
+// PatchSeries returns the changes since a snapshot as an ordered series of
+// small patches with line context, showing the editing trajectory rather
+// than only its result. Adjacent keystrokes are coalesced and long series
+// are bounded by folding the oldest patches together:
+//
+//	patches, err := tracker.PatchSeries(snapID, currentRope, tracking.DefaultPatchSeriesOptions())
+//
 // # Thread Safety
 //
 // All Tracker operations are thread-safe through internal locking.
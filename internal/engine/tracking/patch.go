@@ -0,0 +1,270 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/rope"
+)
+
+// ErrHistoryMismatch is returned when the recorded changes since a
+// snapshot do not transform the snapshot into the current text, usually
+// because older changes were dropped from the bounded history.
+var ErrHistoryMismatch = errors.New("change history does not match buffer text")
+
+// Default patch series limits.
+const (
+	// DefaultMaxPatches is the default maximum length of a patch series.
+	DefaultMaxPatches = 20
+
+	// DefaultPatchContextLines is the default number of context lines.
+	DefaultPatchContextLines = 2
+
+	// DefaultCoalesceBytes is the default size below which adjacent
+	// edits are coalesced.
+	DefaultCoalesceBytes = 16
+)
+
+// PatchSeriesOptions configures patch series generation.
+type PatchSeriesOptions struct {
+	// MaxPatches bounds the length of the series. When exceeded, the
+	// oldest patches are folded into the first one so the most recent
+	// trajectory is kept. 0 means no limit.
+	MaxPatches int
+
+	// ContextLines is the number of unchanged lines to include around
+	// each patch.
+	ContextLines int
+
+	// CoalesceBytes merges an edit into the preceding patch when it
+	// touches that patch, changes at most this many bytes and contains
+	// no newline, so typing a word yields one patch rather than one per
+	// keystroke. 0 disables coalescing.
+	CoalesceBytes int
+}
+
+// DefaultPatchSeriesOptions returns default patch series options.
+func DefaultPatchSeriesOptions() PatchSeriesOptions {
+	return PatchSeriesOptions{
+		MaxPatches:    DefaultMaxPatches,
+		ContextLines:  DefaultPatchContextLines,
+		CoalesceBytes: DefaultCoalesceBytes,
+	}
+}
+
+// Patch is one step of a patch series. Applying the patches of a series
+// in order, each replacing Range with NewText, transforms the snapshot
+// text into the current text.
+type Patch struct {
+	// Type indicates whether this is an insert, delete, or replace.
+	Type ChangeType
+
+	// Description is a short human-readable summary of the patch, with
+	// 1-based line numbers.
+	Description string
+
+	// Range is the affected range in the text before this patch.
+	Range buffer.Range
+
+	// OldText is the text that was removed (empty for inserts).
+	OldText string
+
+	// NewText is the text that was added (empty for deletes).
+	NewText string
+
+	// Line is the 0-based line where the patch starts.
+	Line uint32
+
+	// Before holds the complete lines touched by the patch before it was
+	// applied, without the trailing newline.
+	Before string
+
+	// After holds the same lines after the patch was applied.
+	After string
+
+	// ContextBefore holds the unchanged lines preceding the patch.
+	ContextBefore []string
+
+	// ContextAfter holds the unchanged lines following the patch.
+	ContextAfter []string
+
+	// StartRevision is the revision of the first change in the patch.
+	StartRevision RevisionID
+
+	// EndRevision is the revision of the last change in the patch.
+	EndRevision RevisionID
+
+	// Changes is the number of recorded changes merged into the patch.
+	Changes int
+}
+
+// PatchSeries returns the changes since a snapshot as an ordered series of
+// small patches, showing how the snapshot text became currentRope. It
+// returns ErrHistoryMismatch if the recorded changes do not reconstruct
+// currentRope.
+func (t *Tracker) PatchSeries(id SnapshotID, currentRope rope.Rope, opts PatchSeriesOptions) ([]Patch, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snap, err := t.snapshots.Lookup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := replayChanges(snap.Rope(), t.changesSinceLocked(snap.Revision), opts.CoalesceBytes)
+	if err != nil {
+		return nil, err
+	}
+	text := snap.Rope()
+	if len(steps) > 0 {
+		text = steps[len(steps)-1].after
+	}
+	if !text.Equals(currentRope) {
+		return nil, ErrHistoryMismatch
+	}
+
+	if opts.MaxPatches > 0 && len(steps) > opts.MaxPatches {
+		first := steps[0]
+		fold := len(steps) - opts.MaxPatches
+		for _, s := range steps[1 : fold+1] {
+			first = composeSteps(first, s)
+		}
+		steps = append([]patchStep{first}, steps[fold+1:]...)
+	}
+
+	patches := make([]Patch, 0, len(steps))
+	for _, s := range steps {
+		if s.oldText == s.newText {
+			continue
+		}
+		patches = append(patches, s.patch(opts.ContextLines))
+	}
+	return patches, nil
+}
+
+// patchStep is a patch under construction, keeping the text on both sides.
+type patchStep struct {
+	before, after    rope.Rope
+	start            buffer.ByteOffset
+	oldEnd, newEnd   buffer.ByteOffset
+	oldText, newText string
+	startRev, endRev RevisionID
+	changes          int
+}
+
+// replayChanges applies changes to text in order, coalescing micro-edits.
+func replayChanges(text rope.Rope, changes []Change, coalesceBytes int) ([]patchStep, error) {
+	var steps []patchStep
+	for _, c := range changes {
+		start, end := c.Range.Start, c.Range.End
+		if start < 0 || start > end || end > buffer.ByteOffset(text.Len()) ||
+			text.Slice(rope.ByteOffset(start), rope.ByteOffset(end)) != c.OldText {
+			return nil, ErrHistoryMismatch
+		}
+
+		after := text.Replace(rope.ByteOffset(start), rope.ByteOffset(end), c.NewText)
+		step := patchStep{
+			before:   text,
+			after:    after,
+			start:    start,
+			oldEnd:   end,
+			newEnd:   start + buffer.ByteOffset(len(c.NewText)),
+			oldText:  c.OldText,
+			newText:  c.NewText,
+			startRev: c.RevisionID,
+			endRev:   c.RevisionID,
+			changes:  1,
+		}
+		text = after
+
+		if n := len(steps); n > 0 && coalesces(steps[n-1], c, coalesceBytes) {
+			steps[n-1] = composeSteps(steps[n-1], step)
+			continue
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// coalesces reports whether change c is a micro-edit touching prev.
+func coalesces(prev patchStep, c Change, coalesceBytes int) bool {
+	if coalesceBytes <= 0 || len(c.OldText)+len(c.NewText) > coalesceBytes {
+		return false
+	}
+	if strings.Contains(c.OldText, "\n") || strings.Contains(c.NewText, "\n") {
+		return false
+	}
+	return c.Range.Start <= prev.newEnd && c.Range.End >= prev.start
+}
+
+// composeSteps merges two consecutive steps into one covering both.
+func composeSteps(a, b patchStep) patchStep {
+	start := min(a.start, b.start)
+	// End of the combined region in the text between a and b
+	mid := max(a.newEnd, b.oldEnd)
+	oldEnd := mid - (a.newEnd - a.oldEnd)
+	newEnd := mid + (b.newEnd - b.oldEnd)
+
+	return patchStep{
+		before:   a.before,
+		after:    b.after,
+		start:    start,
+		oldEnd:   oldEnd,
+		newEnd:   newEnd,
+		oldText:  a.before.Slice(rope.ByteOffset(start), rope.ByteOffset(oldEnd)),
+		newText:  b.after.Slice(rope.ByteOffset(start), rope.ByteOffset(newEnd)),
+		startRev: a.startRev,
+		endRev:   b.endRev,
+		changes:  a.changes + b.changes,
+	}
+}
+
+// patch converts the step to a Patch with line context.
+func (s patchStep) patch(contextLines int) Patch {
+	line := s.before.OffsetToPoint(rope.ByteOffset(s.start)).Line
+	oldLast := s.before.OffsetToPoint(rope.ByteOffset(s.oldEnd)).Line
+	newLast := s.after.OffsetToPoint(rope.ByteOffset(s.newEnd)).Line
+
+	p := Patch{
+		Range:         buffer.Range{Start: s.start, End: s.oldEnd},
+		OldText:       s.oldText,
+		NewText:       s.newText,
+		Line:          line,
+		Before:        s.before.Slice(s.before.LineStartOffset(line), s.before.LineEndOffset(oldLast)),
+		After:         s.after.Slice(s.after.LineStartOffset(line), s.after.LineEndOffset(newLast)),
+		StartRevision: s.startRev,
+		EndRevision:   s.endRev,
+		Changes:       s.changes,
+	}
+
+	for l := max(int(line)-contextLines, 0); l < int(line); l++ {
+		p.ContextBefore = append(p.ContextBefore, s.before.LineText(uint32(l)))
+	}
+	for l := newLast + 1; l < s.after.LineCount() && int(l-newLast) <= contextLines; l++ {
+		p.ContextAfter = append(p.ContextAfter, s.after.LineText(l))
+	}
+
+	switch {
+	case s.oldText == "":
+		p.Type = ChangeInsert
+		p.Description = fmt.Sprintf("insert %q at line %d", truncatePatchText(s.newText, 40), line+1)
+	case s.newText == "":
+		p.Type = ChangeDelete
+		p.Description = fmt.Sprintf("delete %q at line %d", truncatePatchText(s.oldText, 40), line+1)
+	default:
+		p.Type = ChangeReplace
+		p.Description = fmt.Sprintf("replace %q with %q at line %d",
+			truncatePatchText(s.oldText, 20), truncatePatchText(s.newText, 20), line+1)
+	}
+	return p
+}
+
+// truncatePatchText shortens text to at most n bytes for descriptions.
+func truncatePatchText(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	return text[:n-3] + "..."
+}
@@ -0,0 +1,183 @@
+package tracking
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/rope"
+)
+
+// patchEditor applies edits to a rope and records them in a tracker.
+type patchEditor struct {
+	tracker *Tracker
+	text    rope.Rope
+	rev     uint64
+}
+
+func (e *patchEditor) replace(start, end int, text string) {
+	e.rev++
+	old := e.text.Slice(rope.ByteOffset(start), rope.ByteOffset(end))
+	var c Change
+	switch {
+	case old == "":
+		c = NewInsertChange(buffer.ByteOffset(start), text, testRevisionID(e.rev))
+	case text == "":
+		c = NewDeleteChange(buffer.ByteOffset(start), buffer.ByteOffset(end), old, testRevisionID(e.rev))
+	default:
+		c = NewReplaceChange(buffer.ByteOffset(start), buffer.ByteOffset(end), old, text, testRevisionID(e.rev))
+	}
+	e.tracker.RecordChange(testRevisionID(e.rev), c, e.text)
+	e.text = e.text.Replace(rope.ByteOffset(start), rope.ByteOffset(end), text)
+}
+
+func (e *patchEditor) typeText(offset int, text string) {
+	for i := range text {
+		e.replace(offset+i, offset+i, text[i:i+1])
+	}
+}
+
+// applyPatches replays a patch series onto text.
+func applyPatches(t *testing.T, text string, patches []Patch) string {
+	t.Helper()
+	for i, p := range patches {
+		if got := text[p.Range.Start:p.Range.End]; got != p.OldText {
+			t.Fatalf("patch %d OldText = %q, text has %q", i, p.OldText, got)
+		}
+		text = text[:p.Range.Start] + p.NewText + text[p.Range.End:]
+	}
+	return text
+}
+
+func newPatchEditor(text string) (*patchEditor, SnapshotID) {
+	e := &patchEditor{tracker: NewTracker(), text: rope.FromString(text)}
+	return e, e.tracker.CreateSnapshot("before", e.text, testRevisionID(0))
+}
+
+func TestPatchSeries(t *testing.T) {
+	const original = "package main\n\nfunc main() {\n}\n"
+	e, snapID := newPatchEditor(original)
+
+	// Type a statement one keystroke at a time, fix a typo, then rename
+	body := len("package main\n\nfunc main() {\n")
+	e.replace(body, body, "\t")
+	e.typeText(body+1, "fmt.Printn()")
+	e.replace(body+len("\tfmt.Print"), body+len("\tfmt.Printn"), "ln")
+	e.replace(body+len("\tfmt.Println()"), body+len("\tfmt.Println()"), "\n")
+	e.replace(len("package "), len("package main"), "app")
+
+	patches, err := e.tracker.PatchSeries(snapID, e.text, DefaultPatchSeriesOptions())
+	if err != nil {
+		t.Fatalf("PatchSeries() error = %v", err)
+	}
+
+	if got := applyPatches(t, original, patches); got != e.text.String() {
+		t.Fatalf("replayed text = %q, want %q", got, e.text.String())
+	}
+
+	// The keystrokes and the typo fix coalesce into one patch
+	if len(patches) != 3 {
+		for _, p := range patches {
+			t.Logf("%s", p.Description)
+		}
+		t.Fatalf("got %d patches, want 3", len(patches))
+	}
+
+	typed := patches[0]
+	if typed.Type != ChangeInsert || typed.NewText != "\tfmt.Println()" {
+		t.Errorf("patch 0 = %v %q, want insert of the statement", typed.Type, typed.NewText)
+	}
+	if typed.Changes != 14 {
+		t.Errorf("patch 0 Changes = %d, want 14", typed.Changes)
+	}
+	if typed.StartRevision != testRevisionID(1) || typed.EndRevision != testRevisionID(14) {
+		t.Errorf("patch 0 revisions = %d-%d, want 1-14", typed.StartRevision, typed.EndRevision)
+	}
+	if typed.Line != 3 || typed.Before != "}" || typed.After != "\tfmt.Println()}" {
+		t.Errorf("patch 0 line %d: %q -> %q", typed.Line, typed.Before, typed.After)
+	}
+	if len(typed.ContextBefore) != 2 || typed.ContextBefore[1] != "func main() {" {
+		t.Errorf("patch 0 ContextBefore = %q", typed.ContextBefore)
+	}
+
+	if patches[1].Type != ChangeInsert || patches[1].NewText != "\n" {
+		t.Errorf("patch 1 = %v %q, want newline insert", patches[1].Type, patches[1].NewText)
+	}
+
+	rename := patches[2]
+	if rename.Type != ChangeReplace || rename.OldText != "main" || rename.NewText != "app" {
+		t.Errorf("patch 2 = %v %q -> %q, want replace main with app", rename.Type, rename.OldText, rename.NewText)
+	}
+	if rename.Before != "package main" || rename.After != "package app" {
+		t.Errorf("patch 2 lines = %q -> %q", rename.Before, rename.After)
+	}
+	if want := `replace "main" with "app" at line 1`; rename.Description != want {
+		t.Errorf("patch 2 Description = %q, want %q", rename.Description, want)
+	}
+	if len(rename.ContextBefore) != 0 || len(rename.ContextAfter) != 2 || rename.ContextAfter[1] != "func main() {" {
+		t.Errorf("patch 2 context = %q / %q", rename.ContextBefore, rename.ContextAfter)
+	}
+}
+
+func TestPatchSeriesMaxPatches(t *testing.T) {
+	const original = "a\nb\nc\nd\ne\n"
+	e, snapID := newPatchEditor(original)
+	for line := 0; line < 5; line++ {
+		e.replace(line*2, line*2+1, "X")
+	}
+
+	opts := DefaultPatchSeriesOptions()
+	patches, err := e.tracker.PatchSeries(snapID, e.text, opts)
+	if err != nil {
+		t.Fatalf("PatchSeries() error = %v", err)
+	}
+	if len(patches) != 5 {
+		t.Fatalf("got %d patches, want 5 separate edits", len(patches))
+	}
+
+	// The oldest edits fold into the first patch
+	opts.MaxPatches = 2
+	patches, err = e.tracker.PatchSeries(snapID, e.text, opts)
+	if err != nil {
+		t.Fatalf("PatchSeries() error = %v", err)
+	}
+	if len(patches) != 2 {
+		t.Fatalf("got %d patches, want 2", len(patches))
+	}
+	if patches[0].Changes != 4 || patches[0].OldText != "a\nb\nc\nd" || patches[0].NewText != "X\nX\nX\nX" {
+		t.Errorf("folded patch = %d changes %q -> %q", patches[0].Changes, patches[0].OldText, patches[0].NewText)
+	}
+	if got := applyPatches(t, original, patches); got != e.text.String() {
+		t.Errorf("replayed text = %q, want %q", got, e.text.String())
+	}
+}
+
+func TestPatchSeriesDropsNoOps(t *testing.T) {
+	e, snapID := newPatchEditor("hello")
+	e.typeText(5, "xy")
+	e.replace(6, 7, "")
+	e.replace(5, 6, "")
+
+	patches, err := e.tracker.PatchSeries(snapID, e.text, DefaultPatchSeriesOptions())
+	if err != nil {
+		t.Fatalf("PatchSeries() error = %v", err)
+	}
+	if len(patches) != 0 {
+		t.Errorf("got %d patches for typing and erasing, want 0", len(patches))
+	}
+}
+
+func TestPatchSeriesMismatch(t *testing.T) {
+	e, snapID := newPatchEditor("hello")
+	e.typeText(5, "!")
+
+	_, err := e.tracker.PatchSeries(snapID, rope.FromString("goodbye"), DefaultPatchSeriesOptions())
+	if !errors.Is(err, ErrHistoryMismatch) {
+		t.Errorf("PatchSeries() error = %v, want ErrHistoryMismatch", err)
+	}
+
+	_, err = e.tracker.PatchSeries(SnapshotID(0), e.text, DefaultPatchSeriesOptions())
+	if !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("PatchSeries() error = %v, want ErrSnapshotNotFound", err)
+	}
+}
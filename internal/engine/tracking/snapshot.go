@@ -13,8 +13,16 @@ import (
 // Errors returned by snapshot operations.
 var (
 	ErrSnapshotNotFound = errors.New("snapshot not found")
+
+	// ErrSnapshotEvicted is returned when a snapshot was removed by the
+	// eviction policy (see SnapshotManager.SetLimit).
+	ErrSnapshotEvicted = errors.New("snapshot evicted")
 )
 
+// maxEvictedIDs bounds how many evicted snapshot IDs are remembered for
+// reporting ErrSnapshotEvicted.
+const maxEvictedIDs = 1024
+
 // SnapshotID uniquely identifies a named snapshot.
 type SnapshotID uint64
 
@@ -86,10 +94,31 @@ func (s *Snapshot) Age() time.Duration {
 
 // SnapshotManager manages named snapshots.
 // All operations are thread-safe.
+//
+// By default snapshots are kept until deleted. SetLimit enables an eviction
+// policy that bounds the number of snapshots and drops snapshots that have
+// not been accessed recently, so that long sessions do not pin old rope
+// nodes indefinitely. Pinned snapshots are never evicted.
 type SnapshotManager struct {
 	mu        sync.RWMutex
 	snapshots map[SnapshotID]*Snapshot
 	byName    map[string]*Snapshot
+
+	// Eviction policy
+	limit      int
+	ttl        time.Duration
+	access     map[SnapshotID]snapshotAccess
+	accessSeq  uint64
+	pinned     map[SnapshotID]bool
+	evicted    map[SnapshotID]struct{}
+	evictedIDs []SnapshotID // eviction order, oldest first
+	now        func() time.Time
+}
+
+// snapshotAccess records when a snapshot was last created or read.
+type snapshotAccess struct {
+	seq uint64 // orders accesses that share a timestamp
+	at  time.Time
 }
 
 // NewSnapshotManager creates a new snapshot manager.
@@ -97,7 +126,68 @@ func NewSnapshotManager() *SnapshotManager {
 	return &SnapshotManager{
 		snapshots: make(map[SnapshotID]*Snapshot),
 		byName:    make(map[string]*Snapshot),
+		access:    make(map[SnapshotID]snapshotAccess),
+		pinned:    make(map[SnapshotID]bool),
+		evicted:   make(map[SnapshotID]struct{}),
+		now:       time.Now,
+	}
+}
+
+// SetLimit sets the eviction policy. When more than maxCount unpinned
+// snapshots exist, the least recently accessed ones are evicted; pinned
+// snapshots do not count toward the limit.
+// Snapshots not accessed within ttl are evicted as well. A maxCount or ttl
+// of 0 disables that part of the policy.
+func (sm *SnapshotManager) SetLimit(maxCount int, ttl time.Duration) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if maxCount < 0 {
+		maxCount = 0
+	}
+	if ttl < 0 {
+		ttl = 0
 	}
+	sm.limit = maxCount
+	sm.ttl = ttl
+	sm.evictLocked()
+}
+
+// Limit returns the eviction policy set by SetLimit.
+func (sm *SnapshotManager) Limit() (maxCount int, ttl time.Duration) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.limit, sm.ttl
+}
+
+// Pin protects a snapshot from eviction.
+func (sm *SnapshotManager) Pin(id SnapshotID) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if _, err := sm.lookupLocked(id); err != nil {
+		return err
+	}
+	sm.pinned[id] = true
+	return nil
+}
+
+// Unpin makes a pinned snapshot subject to eviction again.
+func (sm *SnapshotManager) Unpin(id SnapshotID) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if sm.pinned[id] {
+		delete(sm.pinned, id)
+		sm.evictLocked()
+	}
+}
+
+// IsPinned returns true if the snapshot is pinned.
+func (sm *SnapshotManager) IsPinned(id SnapshotID) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.pinned[id]
 }
 
 // Create creates a new named snapshot.
@@ -108,7 +198,7 @@ func (sm *SnapshotManager) Create(name string, rp rope.Rope, revision RevisionID
 
 	// Remove existing snapshot with same name
 	if existing, ok := sm.byName[name]; ok {
-		delete(sm.snapshots, existing.ID)
+		sm.removeLocked(existing)
 	}
 
 	snap := NewSnapshot(name, rp, revision)
@@ -117,24 +207,47 @@ func (sm *SnapshotManager) Create(name string, rp rope.Rope, revision RevisionID
 	if name != "" {
 		sm.byName[name] = snap
 	}
+	sm.touchLocked(snap.ID)
+	sm.evictLocked()
 
 	return snap.ID
 }
 
 // Get retrieves a snapshot by ID.
 func (sm *SnapshotManager) Get(id SnapshotID) (*Snapshot, bool) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
-	snap, ok := sm.snapshots[id]
-	return snap, ok
+	snap, err := sm.Lookup(id)
+	return snap, err == nil
+}
+
+// Lookup retrieves a snapshot by ID. It returns ErrSnapshotEvicted if the
+// snapshot was removed by the eviction policy and ErrSnapshotNotFound if
+// it never existed or was deleted.
+func (sm *SnapshotManager) Lookup(id SnapshotID) (*Snapshot, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	snap, err := sm.lookupLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	sm.touchLocked(id)
+	return snap, nil
 }
 
 // GetByName retrieves a snapshot by name.
 func (sm *SnapshotManager) GetByName(name string) (*Snapshot, bool) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
 	snap, ok := sm.byName[name]
-	return snap, ok
+	if !ok {
+		return nil, false
+	}
+	if _, err := sm.lookupLocked(snap.ID); err != nil {
+		return nil, false
+	}
+	sm.touchLocked(snap.ID)
+	return snap, true
 }
 
 // Delete removes a snapshot by ID.
@@ -143,10 +256,7 @@ func (sm *SnapshotManager) Delete(id SnapshotID) {
 	defer sm.mu.Unlock()
 
 	if snap, ok := sm.snapshots[id]; ok {
-		if snap.Name != "" {
-			delete(sm.byName, snap.Name)
-		}
-		delete(sm.snapshots, id)
+		sm.removeLocked(snap)
 	}
 }
 
@@ -156,16 +266,16 @@ func (sm *SnapshotManager) DeleteByName(name string) {
 	defer sm.mu.Unlock()
 
 	if snap, ok := sm.byName[name]; ok {
-		delete(sm.snapshots, snap.ID)
-		delete(sm.byName, name)
+		sm.removeLocked(snap)
 	}
 }
 
 // List returns all snapshots, sorted by timestamp (oldest first).
 func (sm *SnapshotManager) List() []*Snapshot {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 
+	sm.evictLocked()
 	snapshots := make([]*Snapshot, 0, len(sm.snapshots))
 	for _, snap := range sm.snapshots {
 		snapshots = append(snapshots, snap)
@@ -181,8 +291,10 @@ func (sm *SnapshotManager) List() []*Snapshot {
 
 // Count returns the number of snapshots.
 func (sm *SnapshotManager) Count() int {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.evictLocked()
 	return len(sm.snapshots)
 }
 
@@ -192,6 +304,10 @@ func (sm *SnapshotManager) Clear() {
 	defer sm.mu.Unlock()
 	sm.snapshots = make(map[SnapshotID]*Snapshot)
 	sm.byName = make(map[string]*Snapshot)
+	sm.access = make(map[SnapshotID]snapshotAccess)
+	sm.pinned = make(map[SnapshotID]bool)
+	sm.evicted = make(map[SnapshotID]struct{})
+	sm.evictedIDs = nil
 }
 
 // Names returns all snapshot names.
@@ -215,12 +331,9 @@ func (sm *SnapshotManager) Prune(maxAge time.Duration) int {
 	cutoff := time.Now().Add(-maxAge)
 	var removed int
 
-	for id, snap := range sm.snapshots {
+	for _, snap := range sm.snapshots {
 		if snap.Timestamp.Before(cutoff) {
-			if snap.Name != "" {
-				delete(sm.byName, snap.Name)
-			}
-			delete(sm.snapshots, id)
+			sm.removeLocked(snap)
 			removed++
 		}
 	}
@@ -252,13 +365,96 @@ func (sm *SnapshotManager) PruneKeepN(n int) int {
 	// Remove oldest
 	removed := 0
 	for i := n; i < len(snapshots); i++ {
-		snap := snapshots[i]
-		if snap.Name != "" {
-			delete(sm.byName, snap.Name)
-		}
-		delete(sm.snapshots, snap.ID)
+		sm.removeLocked(snapshots[i])
 		removed++
 	}
 
 	return removed
 }
+
+// lookupLocked finds a snapshot, evicting it first if its TTL expired
+// (must hold write lock).
+func (sm *SnapshotManager) lookupLocked(id SnapshotID) (*Snapshot, error) {
+	snap, ok := sm.snapshots[id]
+	if !ok {
+		if _, evicted := sm.evicted[id]; evicted {
+			return nil, ErrSnapshotEvicted
+		}
+		return nil, ErrSnapshotNotFound
+	}
+	if sm.expiredLocked(id, sm.now()) {
+		sm.evictSnapshotLocked(snap)
+		return nil, ErrSnapshotEvicted
+	}
+	return snap, nil
+}
+
+// touchLocked marks a snapshot as accessed now (must hold write lock).
+func (sm *SnapshotManager) touchLocked(id SnapshotID) {
+	sm.accessSeq++
+	sm.access[id] = snapshotAccess{seq: sm.accessSeq, at: sm.now()}
+}
+
+// expiredLocked returns true if an unpinned snapshot has not been accessed
+// within the TTL (must hold lock).
+func (sm *SnapshotManager) expiredLocked(id SnapshotID, now time.Time) bool {
+	if sm.ttl <= 0 || sm.pinned[id] {
+		return false
+	}
+	return now.Sub(sm.access[id].at) > sm.ttl
+}
+
+// evictLocked applies the eviction policy (must hold write lock).
+func (sm *SnapshotManager) evictLocked() {
+	if sm.ttl > 0 {
+		now := sm.now()
+		for id, snap := range sm.snapshots {
+			if sm.expiredLocked(id, now) {
+				sm.evictSnapshotLocked(snap)
+			}
+		}
+	}
+
+	if sm.limit <= 0 || len(sm.snapshots)-len(sm.pinned) <= sm.limit {
+		return
+	}
+
+	candidates := make([]*Snapshot, 0, len(sm.snapshots))
+	for id, snap := range sm.snapshots {
+		if !sm.pinned[id] {
+			candidates = append(candidates, snap)
+		}
+	}
+	// Least recently accessed first
+	sort.Slice(candidates, func(i, j int) bool {
+		return sm.access[candidates[i].ID].seq < sm.access[candidates[j].ID].seq
+	})
+
+	for _, snap := range candidates[:len(candidates)-sm.limit] {
+		sm.evictSnapshotLocked(snap)
+	}
+}
+
+// evictSnapshotLocked removes a snapshot and remembers that it was evicted
+// (must hold write lock).
+func (sm *SnapshotManager) evictSnapshotLocked(snap *Snapshot) {
+	sm.removeLocked(snap)
+
+	sm.evicted[snap.ID] = struct{}{}
+	sm.evictedIDs = append(sm.evictedIDs, snap.ID)
+	if len(sm.evictedIDs) > maxEvictedIDs {
+		delete(sm.evicted, sm.evictedIDs[0])
+		sm.evictedIDs = sm.evictedIDs[1:]
+	}
+}
+
+// removeLocked removes a snapshot and its bookkeeping (must hold write
+// lock).
+func (sm *SnapshotManager) removeLocked(snap *Snapshot) {
+	if snap.Name != "" && sm.byName[snap.Name] == snap {
+		delete(sm.byName, snap.Name)
+	}
+	delete(sm.snapshots, snap.ID)
+	delete(sm.access, snap.ID)
+	delete(sm.pinned, snap.ID)
+}
@@ -2,6 +2,7 @@ package tracking
 
 import (
 	"sync"
+	"time"
 
 	"github.com/dshills/keystorm/internal/engine/buffer"
 	"github.com/dshills/keystorm/internal/engine/rope"
@@ -33,6 +34,14 @@ func WithMaxRevisions(maxRevisions int) TrackerOption {
 	}
 }
 
+// WithSnapshotLimit sets the snapshot eviction policy.
+// See Tracker.SetSnapshotLimit.
+func WithSnapshotLimit(maxCount int, ttl time.Duration) TrackerOption {
+	return func(t *Tracker) {
+		t.snapshots.SetLimit(maxCount, ttl)
+	}
+}
+
 // Tracker records changes for AI context queries.
 // It maintains a bounded history of changes and supports named snapshots.
 // All operations are thread-safe.
@@ -201,14 +210,40 @@ func (t *Tracker) CreateSnapshot(name string, currentRope rope.Rope, rev Revisio
 	return t.snapshots.Create(name, currentRope, rev)
 }
 
+// SetSnapshotLimit bounds the snapshots the tracker retains. When more
+// than maxCount unpinned snapshots exist, the least recently accessed ones
+// are evicted, and snapshots not accessed within ttl are evicted as well.
+// Pinned snapshots are never evicted and do not count toward maxCount. A
+// maxCount or ttl of 0 disables that limit. Operations on an evicted
+// snapshot return ErrSnapshotEvicted.
+func (t *Tracker) SetSnapshotLimit(maxCount int, ttl time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.snapshots.SetLimit(maxCount, ttl)
+}
+
+// PinSnapshot protects a snapshot from eviction.
+func (t *Tracker) PinSnapshot(id SnapshotID) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.snapshots.Pin(id)
+}
+
+// UnpinSnapshot makes a pinned snapshot subject to eviction again.
+func (t *Tracker) UnpinSnapshot(id SnapshotID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.snapshots.Unpin(id)
+}
+
 // GetSnapshot retrieves a snapshot by ID.
 func (t *Tracker) GetSnapshot(id SnapshotID) (*Snapshot, error) {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	snap, ok := t.snapshots.Get(id)
-	if !ok {
-		return nil, ErrSnapshotNotFound
+	snap, err := t.snapshots.Lookup(id)
+	if err != nil {
+		return nil, err
 	}
 	return snap, nil
 }
@@ -261,9 +296,9 @@ func (t *Tracker) DiffSinceSnapshot(id SnapshotID) ([]Change, error) {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	snap, ok := t.snapshots.Get(id)
-	if !ok {
-		return nil, ErrSnapshotNotFound
+	snap, err := t.snapshots.Lookup(id)
+	if err != nil {
+		return nil, err
 	}
 
 	return t.changesSinceLocked(snap.Revision), nil
@@ -274,9 +309,9 @@ func (t *Tracker) ComputeDiffSinceSnapshot(id SnapshotID, currentRope rope.Rope,
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	snap, ok := t.snapshots.Get(id)
-	if !ok {
-		return DiffResult{}, ErrSnapshotNotFound
+	snap, err := t.snapshots.Lookup(id)
+	if err != nil {
+		return DiffResult{}, err
 	}
 
 	return ComputeLineDiff(snap.Rope(), currentRope, opts), nil
@@ -287,14 +322,14 @@ func (t *Tracker) ComputeDiffBetweenSnapshots(fromID, toID SnapshotID, opts Diff
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	fromSnap, ok := t.snapshots.Get(fromID)
-	if !ok {
-		return DiffResult{}, ErrSnapshotNotFound
+	fromSnap, err := t.snapshots.Lookup(fromID)
+	if err != nil {
+		return DiffResult{}, err
 	}
 
-	toSnap, ok := t.snapshots.Get(toID)
-	if !ok {
-		return DiffResult{}, ErrSnapshotNotFound
+	toSnap, err := t.snapshots.Lookup(toID)
+	if err != nil {
+		return DiffResult{}, err
 	}
 
 	return ComputeLineDiff(fromSnap.Rope(), toSnap.Rope(), opts), nil
@@ -305,9 +340,9 @@ func (t *Tracker) GetSnapshotText(id SnapshotID) (string, error) {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	snap, ok := t.snapshots.Get(id)
-	if !ok {
-		return "", ErrSnapshotNotFound
+	snap, err := t.snapshots.Lookup(id)
+	if err != nil {
+		return "", err
 	}
 
 	return snap.Text(), nil
@@ -1,6 +1,8 @@
 package tracking
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -604,6 +606,138 @@ func TestTrackerObserver(t *testing.T) {
 	}
 }
 
+// TestSnapshotEviction tests the snapshot eviction policy
+func TestSnapshotEviction(t *testing.T) {
+	t.Run("count limit evicts least recently accessed", func(t *testing.T) {
+		tracker := NewTracker()
+		tracker.SetSnapshotLimit(3, 0)
+		rp := rope.FromString("text")
+
+		a := tracker.CreateSnapshot("a", rp, testRevisionID(1))
+		b := tracker.CreateSnapshot("b", rp, testRevisionID(2))
+		c := tracker.CreateSnapshot("c", rp, testRevisionID(3))
+
+		// Reading a makes b the least recently accessed.
+		if _, err := tracker.GetSnapshot(a); err != nil {
+			t.Fatalf("GetSnapshot(a) error = %v", err)
+		}
+		d := tracker.CreateSnapshot("d", rp, testRevisionID(4))
+
+		if got := tracker.SnapshotCount(); got != 3 {
+			t.Errorf("SnapshotCount() = %d, want 3", got)
+		}
+		if _, err := tracker.DiffSinceSnapshot(b); !errors.Is(err, ErrSnapshotEvicted) {
+			t.Errorf("DiffSinceSnapshot(b) error = %v, want ErrSnapshotEvicted", err)
+		}
+		for _, id := range []SnapshotID{a, c, d} {
+			if _, err := tracker.DiffSinceSnapshot(id); err != nil {
+				t.Errorf("DiffSinceSnapshot(%d) error = %v", id, err)
+			}
+		}
+		if _, err := tracker.GetSnapshotByName("b"); !errors.Is(err, ErrSnapshotNotFound) {
+			t.Errorf("GetSnapshotByName(b) error = %v, want ErrSnapshotNotFound", err)
+		}
+	})
+
+	t.Run("pinned snapshots survive", func(t *testing.T) {
+		tracker := NewTracker(WithSnapshotLimit(2, 0))
+		rp := rope.FromString("text")
+
+		pinned := tracker.CreateSnapshot("before_ai_edit", rp, testRevisionID(1))
+		if err := tracker.PinSnapshot(pinned); err != nil {
+			t.Fatalf("PinSnapshot() error = %v", err)
+		}
+
+		var last SnapshotID
+		for i := 0; i < 10; i++ {
+			last = tracker.CreateSnapshot(fmt.Sprintf("checkpoint_%d", i), rp, testRevisionID(uint64(i+2)))
+		}
+
+		// The pinned snapshot plus the two most recent checkpoints
+		if got := tracker.SnapshotCount(); got != 3 {
+			t.Errorf("SnapshotCount() = %d, want 3", got)
+		}
+		if _, err := tracker.GetSnapshot(pinned); err != nil {
+			t.Errorf("pinned snapshot error = %v", err)
+		}
+		if _, err := tracker.GetSnapshot(last); err != nil {
+			t.Errorf("newest snapshot error = %v", err)
+		}
+
+		// Once unpinned, the snapshot is subject to the limit again.
+		tracker.UnpinSnapshot(pinned)
+		tracker.CreateSnapshot("final", rp, testRevisionID(20))
+		if _, err := tracker.GetSnapshot(pinned); !errors.Is(err, ErrSnapshotEvicted) {
+			t.Errorf("unpinned snapshot error = %v, want ErrSnapshotEvicted", err)
+		}
+	})
+
+	t.Run("pinned snapshots do not count toward limit", func(t *testing.T) {
+		tracker := NewTracker(WithSnapshotLimit(1, 0))
+		rp := rope.FromString("text")
+
+		a := tracker.CreateSnapshot("a", rp, testRevisionID(1))
+		if err := tracker.PinSnapshot(a); err != nil {
+			t.Fatalf("PinSnapshot() error = %v", err)
+		}
+		b := tracker.CreateSnapshot("b", rp, testRevisionID(2))
+
+		if got := tracker.SnapshotCount(); got != 2 {
+			t.Errorf("SnapshotCount() = %d, want 2", got)
+		}
+		if _, err := tracker.GetSnapshot(b); err != nil {
+			t.Errorf("new snapshot error = %v", err)
+		}
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		tracker := NewTracker()
+		now := time.Now()
+		tracker.snapshots.now = func() time.Time { return now }
+		tracker.SetSnapshotLimit(0, time.Minute)
+		rp := rope.FromString("text")
+
+		idle := tracker.CreateSnapshot("idle", rp, testRevisionID(1))
+		active := tracker.CreateSnapshot("active", rp, testRevisionID(2))
+		pinned := tracker.CreateSnapshot("pinned", rp, testRevisionID(3))
+		if err := tracker.PinSnapshot(pinned); err != nil {
+			t.Fatalf("PinSnapshot() error = %v", err)
+		}
+
+		now = now.Add(40 * time.Second)
+		if _, err := tracker.GetSnapshot(active); err != nil {
+			t.Fatalf("GetSnapshot(active) error = %v", err)
+		}
+		now = now.Add(40 * time.Second)
+
+		if _, err := tracker.GetSnapshotText(idle); !errors.Is(err, ErrSnapshotEvicted) {
+			t.Errorf("idle snapshot error = %v, want ErrSnapshotEvicted", err)
+		}
+		if got := tracker.SnapshotCount(); got != 2 {
+			t.Errorf("SnapshotCount() = %d, want 2", got)
+		}
+		if _, err := tracker.GetSnapshot(active); err != nil {
+			t.Errorf("active snapshot error = %v", err)
+		}
+		if _, err := tracker.GetSnapshot(pinned); err != nil {
+			t.Errorf("pinned snapshot error = %v", err)
+		}
+	})
+
+	t.Run("deleted is not evicted", func(t *testing.T) {
+		tracker := NewTracker(WithSnapshotLimit(5, 0))
+		id := tracker.CreateSnapshot("a", rope.FromString("text"), testRevisionID(1))
+		tracker.DeleteSnapshot(id)
+
+		if _, err := tracker.DiffSinceSnapshot(id); !errors.Is(err, ErrSnapshotNotFound) {
+			t.Errorf("DiffSinceSnapshot() error = %v, want ErrSnapshotNotFound", err)
+		}
+		if err := tracker.PinSnapshot(id); !errors.Is(err, ErrSnapshotNotFound) {
+			t.Errorf("PinSnapshot() error = %v, want ErrSnapshotNotFound", err)
+		}
+	})
+}
+
 // Benchmark tests
 func BenchmarkTrackerRecordChange(b *testing.B) {
 	tracker := NewTracker()
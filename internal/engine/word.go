@@ -0,0 +1,69 @@
+package engine
+
+import "github.com/dshills/keystorm/internal/engine/cursor"
+
+// WithIsKeyword sets the keyword characters for word boundaries, as parsed
+// by cursor.ParseKeywords. The default is cursor.DefaultIsKeyword.
+func WithIsKeyword(keywords cursor.Keywords) Option {
+	return func(e *Engine) {
+		e.keywords = keywords
+	}
+}
+
+// SetIsKeyword sets the keyword characters for word boundaries from a Vim
+// iskeyword spec such as "@,48-57,_,-". Returns cursor.ErrInvalidKeywords
+// if the spec is malformed, leaving the keywords unchanged.
+func (e *Engine) SetIsKeyword(spec string) error {
+	keywords, err := cursor.ParseKeywords(spec)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.keywords = keywords
+	return nil
+}
+
+// IsKeyword returns the iskeyword spec of the keyword characters.
+func (e *Engine) IsKeyword() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.keywords.String()
+}
+
+// Keywords returns the keyword characters for word boundaries, for
+// motions and operators that scan text themselves.
+func (e *Engine) Keywords() cursor.Keywords {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.keywords
+}
+
+// WordBoundaries returns the word (WORD if bigWord) at from as [start, end).
+// If from is on white space it returns the next word in direction: forward
+// if direction is positive or zero, backward if negative. Returns an empty
+// range if there is no such word. Words follow Vim's rules: a run of
+// keyword characters or a run of other non-blank characters.
+func (e *Engine) WordBoundaries(from ByteOffset, bigWord bool, direction int) (start, end ByteOffset) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.keywords.WordBounds(e.buf.Text(), from, bigWord, direction)
+}
+
+// WordsInRange returns the words (WORDs if bigWord) overlapping r, in
+// order, each clipped to r.
+func (e *Engine) WordsInRange(r Range, bigWord bool) []Range {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	words := e.keywords.WordsInRange(e.buf.Text(), r.Start, r.End, bigWord)
+	if len(words) == 0 {
+		return nil
+	}
+	result := make([]Range, len(words))
+	for i, w := range words {
+		result[i] = Range{Start: w[0], End: w[1]}
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/engine/cursor"
+)
+
+func TestWordBoundaries(t *testing.T) {
+	e := New(WithContent("call(foo-bar)  x"))
+
+	tests := []struct {
+		name       string
+		from       ByteOffset
+		bigWord    bool
+		direction  int
+		start, end ByteOffset
+	}{
+		{"keyword", 6, false, 1, 5, 8},
+		{"punctuation", 8, false, 1, 8, 9},
+		{"WORD", 6, true, 1, 0, 13},
+		{"blank forward", 13, false, 1, 15, 16},
+		{"blank backward", 14, false, -1, 12, 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := e.WordBoundaries(tt.from, tt.bigWord, tt.direction)
+			if start != tt.start || end != tt.end {
+				t.Errorf("WordBoundaries(%d) = [%d, %d), want [%d, %d)", tt.from, start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestWordsInRange(t *testing.T) {
+	e := New(WithContent("a.b c"))
+
+	got := e.WordsInRange(Range{Start: 0, End: 5}, false)
+	want := []Range{{Start: 0, End: 1}, {Start: 1, End: 2}, {Start: 2, End: 3}, {Start: 4, End: 5}}
+	if len(got) != len(want) {
+		t.Fatalf("WordsInRange() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("WordsInRange()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// Words are clipped to the range
+	got = e.WordsInRange(Range{Start: 1, End: 4}, true)
+	if len(got) != 1 || got[0] != (Range{Start: 1, End: 3}) {
+		t.Errorf("WordsInRange(clipped WORDs) = %v, want [{1 3}]", got)
+	}
+}
+
+func TestSetIsKeyword(t *testing.T) {
+	e := New(WithContent("foo-bar"))
+	if e.IsKeyword() != cursor.DefaultIsKeyword {
+		t.Errorf("IsKeyword() = %q, want default", e.IsKeyword())
+	}
+
+	if err := e.SetIsKeyword("@,48-57,_,-"); err != nil {
+		t.Fatalf("SetIsKeyword() error = %v", err)
+	}
+	if start, end := e.WordBoundaries(0, false, 1); start != 0 || end != 7 {
+		t.Errorf("WordBoundaries() = [%d, %d), want foo-bar as one word", start, end)
+	}
+
+	if err := e.SetIsKeyword("9-0"); !errors.Is(err, cursor.ErrInvalidKeywords) {
+		t.Errorf("SetIsKeyword(invalid) error = %v, want ErrInvalidKeywords", err)
+	}
+	if e.IsKeyword() != "@,48-57,_,-" {
+		t.Errorf("IsKeyword() = %q after invalid spec, want unchanged", e.IsKeyword())
+	}
+}
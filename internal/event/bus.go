@@ -3,6 +3,7 @@ package event
 import (
 	"context"
 	"sync/atomic"
+	"time"
 
 	"github.com/dshills/keystorm/internal/event/dispatch"
 	"github.com/dshills/keystorm/internal/event/topic"
@@ -19,6 +20,7 @@ type Bus interface {
 	Subscribe(topicPattern topic.Topic, handler Handler, opts ...SubscriptionOption) (Subscription, error)
 	SubscribeFunc(topicPattern topic.Topic, fn HandlerFunc, opts ...SubscriptionOption) (Subscription, error)
 	Unsubscribe(sub Subscription) error
+	NewGroup() *SubscriptionGroup
 
 	// Lifecycle
 	Start() error
@@ -28,10 +30,21 @@ type Bus interface {
 
 	// Status
 	Stats() Stats
+	Metrics() Metrics
+	ResetMetrics()
 	IsRunning() bool
 	IsPaused() bool
 }
 
+// Drainer is implemented by buses that can wait for queued async events to
+// be delivered. The Bus returned by NewBus implements it. It is a test hook,
+// kept out of Bus so other implementations need not provide it:
+//
+//	bus.(event.Drainer).Drain(ctx)
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
 // bus is the default Bus implementation.
 type bus struct {
 	// Subscription management
@@ -55,7 +68,12 @@ type bus struct {
 	handlersExecuted atomic.Uint64
 	handlerErrors    atomic.Uint64
 	handlerPanics    atomic.Uint64
+	handlersDeferred atomic.Uint64
 	totalDeliveryNs  atomic.Int64
+
+	// Metrics (see metrics.go)
+	topicCounts  topicCounters
+	metricsSince atomic.Int64
 }
 
 // NewBus creates a new event bus with the given options.
@@ -79,6 +97,7 @@ func NewBus(opts ...BusOption) Bus {
 		registry: NewRegistry(),
 		config:   config,
 	}
+	b.metricsSince.Store(time.Now().UnixNano())
 
 	b.syncDispatcher = dispatch.NewSyncDispatcher(
 		dispatch.WithPanicHandler(dispatchPanicHandler),
@@ -89,6 +108,7 @@ func NewBus(opts ...BusOption) Bus {
 		dispatch.WithWorkerCount(config.asyncWorkerCount),
 		dispatch.WithAsyncTimeout(config.defaultTimeout),
 		dispatch.WithAsyncPanicHandler(dispatchPanicHandler),
+		dispatch.WithAsyncClock(config.clock),
 	)
 
 	return b
@@ -115,6 +135,19 @@ func (b *bus) Stop(ctx context.Context) error {
 	return b.asyncDispatcher.Stop(ctx)
 }
 
+// Drain blocks until all queued async events have been delivered and the
+// worker pool is idle, or until ctx is done. Events that handlers publish
+// asynchronously while Drain waits are delivered before it returns, so a
+// whole cascade completes. Sync handlers still running in the background
+// after a sync deadline are not waited for.
+//
+// Drain is intended for tests, which can then assert on the effects of
+// async handlers without sleeping. Production code should not depend on
+// the bus becoming idle.
+func (b *bus) Drain(ctx context.Context) error {
+	return b.asyncDispatcher.Drain(ctx)
+}
+
 // Pause temporarily stops event delivery.
 // Events can still be published but will not be delivered to handlers.
 func (b *bus) Pause() {
@@ -142,7 +175,11 @@ func (b *bus) Publish(ctx context.Context, event any) error {
 }
 
 // PublishSync sends an event synchronously.
-// The call blocks until all sync handlers complete.
+// The call blocks until all sync handlers complete, unless a sync deadline
+// is configured (see WithSyncDeadline), in which case handlers below
+// PriorityHigh may be deferred once the deadline has passed.
+// A failing handler does not stop the others; if any handler that ran to
+// completion failed, a *PublishErrors naming each of them is returned.
 func (b *bus) PublishSync(ctx context.Context, event any) error {
 	if !b.running.Load() {
 		return ErrBusNotRunning
@@ -156,6 +193,8 @@ func (b *bus) PublishSync(ctx context.Context, event any) error {
 		return ErrInvalidEvent
 	}
 
+	b.countPublish(eventTopic)
+
 	// Get matching subscriptions
 	subs := b.registry.MatchActive(eventTopic)
 	if len(subs) == 0 {
@@ -165,7 +204,12 @@ func (b *bus) PublishSync(ctx context.Context, event any) error {
 	// Update metrics
 	b.eventsPublished.Add(1)
 
-	// Dispatch to sync handlers
+	// Dispatch to sync handlers. Subscriptions are ordered by priority, so
+	// Critical and High handlers run before any handler the deadline applies to.
+	clock := b.config.clock
+	start := clock.Now()
+	deadline := b.config.syncDeadline
+	var errs []error
 	for _, sub := range subs {
 		if sub.Config().DeliveryMode != DeliverySync {
 			continue
@@ -174,28 +218,119 @@ func (b *bus) PublishSync(ctx context.Context, event any) error {
 			continue
 		}
 
-		result := b.syncDispatcher.Dispatch(ctx, event, sub.Handler())
-		b.handlersExecuted.Add(1)
+		var err error
+		if deadline <= 0 || sub.Config().Priority <= PriorityHigh {
+			err = b.recordSyncResult(sub, b.syncDispatcher.Dispatch(ctx, event, sub.Handler()))
+		} else if remaining := deadline - clock.Now().Sub(start); remaining <= 0 {
+			b.deferSync(ctx, event, sub)
+		} else {
+			err = b.dispatchWithDeadline(ctx, event, sub, remaining)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &PublishErrors{Topic: string(eventTopic), Errors: errs}
+	}
+	return nil
+}
+
+// dispatchWithDeadline runs a sync handler, waiting at most wait for it.
+// A handler that is still running afterwards finishes in the background
+// and is counted as deferred. Since it may outlive PublishSync, the handler
+// runs with a context detached from ctx's cancellation. Returns the
+// handler's error if it finished in time.
+func (b *bus) dispatchWithDeadline(ctx context.Context, event any, sub *subscription, wait time.Duration) error {
+	ctx = context.WithoutCancel(ctx)
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		err = b.recordSyncResult(sub, b.syncDispatcher.Dispatch(ctx, event, sub.Handler()))
+	}()
+
+	expired := make(chan struct{})
+	stop := b.config.clock.AfterFunc(wait, func() { close(expired) })
+	defer stop()
+
+	select {
+	case <-done:
+		return err
+	case <-expired:
+		b.handlersDeferred.Add(1)
+		return nil
+	}
+}
 
-		switch {
-		case result.Panicked:
-			b.handlerPanics.Add(1)
-		case result.Error != nil:
-			b.handlerErrors.Add(1)
-		case result.Success:
-			b.eventsDelivered.Add(1)
+// deferSync queues a sync handler on the async dispatcher without waiting
+// for it, so deferred handlers share its bounded queue, workers, timeout
+// and statistics. A one-time subscription is removed once its deferred
+// handler succeeds, as in PublishSync. The handler's context is detached
+// from ctx, which the publisher may cancel as soon as PublishSync returns.
+// A handler that does not fit in the queue is dropped and counted by the
+// async dispatcher.
+func (b *bus) deferSync(ctx context.Context, event any, sub *subscription) {
+	ctx = context.WithoutCancel(ctx)
+	handler := b.asyncHandler(sub)
+	deferred := HandlerFunc(func(ctx context.Context, event any) error {
+		if err := handler.Handle(ctx, event); err != nil {
+			return err
 		}
+		b.completeOnce(sub)
+		return nil
+	})
+	if err := b.asyncDispatcher.Enqueue(ctx, event, deferred); err != nil {
+		return
+	}
+	b.handlersDeferred.Add(1)
+}
 
-		b.totalDeliveryNs.Add(result.Duration.Nanoseconds())
+// completeOnce cancels and removes sub if it is a one-time subscription.
+// It is called after a handler of sub succeeded.
+func (b *bus) completeOnce(sub *subscription) {
+	if sub.Config().Once {
+		sub.Cancel()
+		b.registry.Remove(sub.ID())
+	}
+}
 
-		// Handle one-time subscriptions
-		if sub.Config().Once && result.Success {
-			sub.Cancel()
-			b.registry.Remove(sub.ID())
+// recordSyncResult updates metrics for a sync handler execution and
+// removes one-time subscriptions that succeeded. Returns a *HandlerError
+// or *PanicError if the handler failed.
+func (b *bus) recordSyncResult(sub *subscription, result dispatch.Result) error {
+	b.handlersExecuted.Add(1)
+
+	var err error
+	switch {
+	case result.Panicked:
+		b.handlerPanics.Add(1)
+		err = &PanicError{
+			SubscriptionID: sub.ID(),
+			Topic:          string(sub.Topic()),
+			Value:          result.PanicValue,
+			Stack:          string(result.PanicStack),
+		}
+	case result.Error != nil:
+		b.handlerErrors.Add(1)
+		if !result.Skipped {
+			err = &HandlerError{SubscriptionID: sub.ID(), Topic: string(sub.Topic()), Err: result.Error}
 		}
+	case result.Success:
+		b.eventsDelivered.Add(1)
 	}
 
-	return nil
+	b.totalDeliveryNs.Add(result.Duration.Nanoseconds())
+	if b.config.metricsEnabled && !result.Skipped {
+		sub.metrics.record(result.Duration, !result.Success)
+	}
+
+	// Handle one-time subscriptions
+	if result.Success {
+		b.completeOnce(sub)
+	}
+	return err
 }
 
 // PublishAsync queues an event for asynchronous delivery.
@@ -212,6 +347,8 @@ func (b *bus) PublishAsync(ctx context.Context, event any) error {
 		return ErrInvalidEvent
 	}
 
+	b.countPublish(eventTopic)
+
 	subs := b.registry.MatchActive(eventTopic)
 	if len(subs) == 0 {
 		return nil // No subscribers
@@ -228,7 +365,7 @@ func (b *bus) PublishAsync(ctx context.Context, event any) error {
 			continue
 		}
 
-		err := b.asyncDispatcher.Enqueue(ctx, event, sub.Handler())
+		err := b.asyncDispatcher.Enqueue(ctx, event, b.asyncHandler(sub))
 		if err != nil {
 			b.eventsDropped.Add(1)
 			// Queue full - event dropped, but continue trying other handlers
@@ -276,6 +413,12 @@ func (b *bus) Unsubscribe(sub Subscription) error {
 	return nil
 }
 
+// NewGroup creates a subscription group on this bus.
+// All subscriptions made through the group are removed by its Close method.
+func (b *bus) NewGroup() *SubscriptionGroup {
+	return newSubscriptionGroup(b)
+}
+
 // Stats returns current bus statistics.
 func (b *bus) Stats() Stats {
 	asyncStats := b.asyncDispatcher.Stats()
@@ -299,6 +442,7 @@ func (b *bus) Stats() Stats {
 		HandlersExecuted:  handlersExecuted,
 		HandlerErrors:     handlerErrors,
 		HandlerPanics:     handlerPanics,
+		HandlersDeferred:  b.handlersDeferred.Load(),
 		AvgDeliveryTimeNs: avgNs,
 		ActiveSubscribers: b.registry.CountActive(),
 		QueueDepth:        asyncStats.QueueDepth,
@@ -3,6 +3,8 @@ package event
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -180,6 +182,62 @@ func TestBus_PublishSync(t *testing.T) {
 	}
 }
 
+func TestBus_PublishSyncAggregatesErrors(t *testing.T) {
+	bus := NewBus()
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	errFirst := errors.New("first rejected")
+	errThird := errors.New("third rejected")
+	calls := 0
+	subscribe := func(err error) Subscription {
+		sub, subErr := bus.SubscribeFunc(topic.Topic("test.event"),
+			func(ctx context.Context, event any) error {
+				calls++
+				return err
+			},
+			WithDeliveryMode(DeliverySync),
+		)
+		if subErr != nil {
+			t.Fatalf("Subscribe() failed: %v", subErr)
+		}
+		return sub
+	}
+	first := subscribe(errFirst)
+	subscribe(nil)
+	third := subscribe(errThird)
+
+	err := bus.PublishSync(context.Background(), NewEvent(topic.Topic("test.event"), "payload", "test"))
+	if calls != 3 {
+		t.Errorf("handlers called = %d, want 3", calls)
+	}
+
+	var pubErrs *PublishErrors
+	if !errors.As(err, &pubErrs) {
+		t.Fatalf("PublishSync() error = %v, want *PublishErrors", err)
+	}
+	if len(pubErrs.Errors) != 2 {
+		t.Fatalf("len(Errors) = %d, want 2", len(pubErrs.Errors))
+	}
+	ids := pubErrs.SubscriptionIDs()
+	if !reflect.DeepEqual(ids, []string{first.ID(), third.ID()}) && !reflect.DeepEqual(ids, []string{third.ID(), first.ID()}) {
+		t.Errorf("SubscriptionIDs() = %v, want %s and %s", ids, first.ID(), third.ID())
+	}
+	for _, want := range []string{first.ID(), third.ID(), "first rejected", "third rejected"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Error() = %q, want it to name %q", err.Error(), want)
+		}
+	}
+
+	if !errors.Is(err, errFirst) || !errors.Is(err, errThird) {
+		t.Error("errors.Is should match both handler errors")
+	}
+	var handlerErr *HandlerError
+	if !errors.As(err, &handlerErr) || handlerErr.Topic != "test.event" {
+		t.Errorf("errors.As(*HandlerError) = %+v, want topic test.event", handlerErr)
+	}
+}
+
 func TestBus_PublishAsync(t *testing.T) {
 	bus := NewBus()
 	bus.Start()
@@ -334,6 +392,176 @@ func TestBus_Priority(t *testing.T) {
 	}
 }
 
+func TestBus_SyncDeadline(t *testing.T) {
+	bus := NewBus(WithSyncDeadline(20 * time.Millisecond))
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	var critical, high atomic.Int32
+	release := make(chan struct{})
+	slowStarted := make(chan struct{}, 2)
+	lowDone := make(chan struct{}, 2)
+
+	// A misbehaving Normal handler, subscribed first
+	bus.SubscribeFunc(topic.Topic("test"),
+		func(ctx context.Context, event any) error {
+			slowStarted <- struct{}{}
+			<-release
+			return nil
+		},
+		WithPriority(PriorityNormal),
+		WithDeliveryMode(DeliverySync),
+	)
+	bus.SubscribeFunc(topic.Topic("test"),
+		func(ctx context.Context, event any) error {
+			lowDone <- struct{}{}
+			return nil
+		},
+		WithPriority(PriorityLow),
+		WithDeliveryMode(DeliverySync),
+	)
+	bus.SubscribeFunc(topic.Topic("test"),
+		func(ctx context.Context, event any) error {
+			critical.Add(1)
+			return nil
+		},
+		WithPriority(PriorityCritical),
+		WithDeliveryMode(DeliverySync),
+	)
+	bus.SubscribeFunc(topic.Topic("test"),
+		func(ctx context.Context, event any) error {
+			high.Add(1)
+			return nil
+		},
+		WithPriority(PriorityHigh),
+		WithDeliveryMode(DeliverySync),
+	)
+
+	for i := 1; i <= 2; i++ {
+		start := time.Now()
+		if err := bus.PublishSync(context.Background(), NewEvent(topic.Topic("test"), struct{}{}, "test")); err != nil {
+			t.Fatalf("PublishSync() failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+			t.Errorf("publish %d: PublishSync blocked for %v", i, elapsed)
+		}
+
+		// Critical and High handlers ran before PublishSync returned, even
+		// while an earlier slow handler is still blocked
+		if got := critical.Load(); got != int32(i) {
+			t.Errorf("publish %d: critical handler ran %d times", i, got)
+		}
+		if got := high.Load(); got != int32(i) {
+			t.Errorf("publish %d: high handler ran %d times", i, got)
+		}
+
+		select {
+		case <-slowStarted:
+		case <-time.After(time.Second):
+			t.Fatalf("publish %d: slow handler did not start", i)
+		}
+
+		// The Low handler after the slow one is deferred to the background
+		select {
+		case <-lowDone:
+		case <-time.After(time.Second):
+			t.Fatalf("publish %d: deferred low handler did not run", i)
+		}
+	}
+
+	if got := bus.Stats().HandlersDeferred; got != 4 {
+		t.Errorf("HandlersDeferred = %d, want 4", got)
+	}
+	close(release)
+}
+
+func TestBus_SyncDeadlineDeferredOnce(t *testing.T) {
+	bus := NewBus(WithSyncDeadline(20 * time.Millisecond))
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	release := make(chan struct{})
+	defer close(release)
+	bus.SubscribeFunc(topic.Topic("test"),
+		func(ctx context.Context, event any) error {
+			<-release
+			return nil
+		},
+		WithPriority(PriorityNormal),
+		WithDeliveryMode(DeliverySync),
+	)
+	var onceRuns atomic.Int32
+	once, _ := bus.SubscribeFunc(topic.Topic("test"),
+		func(ctx context.Context, event any) error {
+			onceRuns.Add(1)
+			return nil
+		},
+		WithPriority(PriorityLow),
+		WithDeliveryMode(DeliverySync),
+		WithOnce(),
+	)
+
+	bus.PublishSync(context.Background(), NewEvent(topic.Topic("test"), struct{}{}, "test"))
+
+	// The deferred handler cancels the one-time subscription once it
+	// succeeds, as in PublishSync
+	deadline := time.Now().Add(time.Second)
+	for once.IsActive() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if once.IsActive() {
+		t.Fatal("deferred one-time subscription is still active")
+	}
+
+	bus.PublishSync(context.Background(), NewEvent(topic.Topic("test"), struct{}{}, "test"))
+	if got := onceRuns.Load(); got != 1 {
+		t.Errorf("one-time handler ran %d times, want 1", got)
+	}
+	if got := bus.Stats().EventsDelivered; got != 1 {
+		t.Errorf("EventsDelivered = %d, want 1", got)
+	}
+}
+
+func TestBus_SyncDeadlineFastHandlers(t *testing.T) {
+	bus := NewBus(WithSyncDeadline(time.Second))
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	var order []string
+	var mu sync.Mutex
+	for _, p := range []Priority{PriorityLow, PriorityNormal, PriorityCritical} {
+		name := p.String()
+		bus.SubscribeFunc(topic.Topic("test"),
+			func(ctx context.Context, event any) error {
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+				return nil
+			},
+			WithPriority(p),
+			WithDeliveryMode(DeliverySync),
+		)
+	}
+
+	bus.PublishSync(context.Background(), NewEvent(topic.Topic("test"), struct{}{}, "test"))
+
+	// Handlers within the deadline still complete in order before returning
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{PriorityCritical.String(), PriorityNormal.String(), PriorityLow.String()}
+	if len(order) != len(want) {
+		t.Fatalf("handlers run = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("position %d: got %s, want %s", i, order[i], want[i])
+		}
+	}
+	if got := bus.Stats().HandlersDeferred; got != 0 {
+		t.Errorf("HandlersDeferred = %d, want 0", got)
+	}
+}
+
 func TestBus_Filter(t *testing.T) {
 	bus := NewBus()
 	bus.Start()
@@ -684,3 +912,204 @@ func BenchmarkBus_ManySubscribers(b *testing.B) {
 		bus.PublishSync(ctx, event)
 	}
 }
+
+func TestBus_DrainCascade(t *testing.T) {
+	bus := NewBus(WithAsyncWorkerCount(2))
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	// Each handler publishes the next event of the chain asynchronously
+	var delivered []string
+	var mu sync.Mutex
+	chain := []string{"chain.a", "chain.b", "chain.c", "chain.d"}
+	for i, name := range chain {
+		next := ""
+		if i+1 < len(chain) {
+			next = chain[i+1]
+		}
+		bus.SubscribeFunc(topic.Topic(name), func(ctx context.Context, event any) error {
+			// Give Drain a chance to observe an idle moment if it could
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			delivered = append(delivered, name)
+			mu.Unlock()
+			if next != "" {
+				return bus.PublishAsync(context.Background(), NewEvent(topic.Topic(next), struct{}{}, "test"))
+			}
+			return nil
+		}, WithDeliveryMode(DeliveryAsync))
+	}
+
+	if err := bus.PublishAsync(context.Background(), NewEvent(topic.Topic(chain[0]), struct{}{}, "test")); err != nil {
+		t.Fatalf("PublishAsync() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := bus.(Drainer).Drain(ctx); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(delivered, chain) {
+		t.Errorf("delivered after Drain = %v, want %v", delivered, chain)
+	}
+}
+
+func TestBus_DrainIdle(t *testing.T) {
+	bus := NewBus()
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := bus.(Drainer).Drain(ctx); err != nil {
+		t.Errorf("Drain() on idle bus error = %v", err)
+	}
+}
+
+// fakeClock is a Clock that only advances when told to.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Time
+	f       func()
+	stopped bool
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &fakeTimer{at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		wasActive := !timer.stopped
+		timer.stopped = true
+		return wasActive
+	}
+}
+
+// Advance moves the clock forward by d and fires the timers due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var due []func()
+	for _, timer := range c.timers {
+		if !timer.stopped && !timer.at.After(c.now) {
+			timer.stopped = true
+			due = append(due, timer.f)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, f := range due {
+		go f()
+	}
+}
+
+func TestBus_SyncDeadlineDefersToAsyncWorkers(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	bus := NewBus(WithClock(clock), WithSyncDeadline(time.Second), WithAsyncWorkerCount(1))
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	// The high priority handler uses up the deadline
+	bus.SubscribeFunc(topic.Topic("test"),
+		func(ctx context.Context, event any) error {
+			clock.Advance(time.Minute)
+			return nil
+		},
+		WithPriority(PriorityHigh),
+		WithDeliveryMode(DeliverySync),
+	)
+	var running, maxRunning, runs atomic.Int32
+	for range 3 {
+		bus.SubscribeFunc(topic.Topic("test"),
+			func(ctx context.Context, event any) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				if n > maxRunning.Load() {
+					maxRunning.Store(n)
+				}
+				time.Sleep(5 * time.Millisecond)
+				runs.Add(1)
+				return nil
+			},
+			WithPriority(PriorityLow),
+			WithDeliveryMode(DeliverySync),
+		)
+	}
+
+	if err := bus.PublishSync(context.Background(), NewEvent(topic.Topic("test"), struct{}{}, "test")); err != nil {
+		t.Fatalf("PublishSync() failed: %v", err)
+	}
+
+	// The deferred handlers run on the single async worker, one at a time,
+	// and Drain waits for them
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := bus.(Drainer).Drain(ctx); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if got := runs.Load(); got != 3 {
+		t.Errorf("deferred handlers ran %d times, want 3", got)
+	}
+	if got := maxRunning.Load(); got != 1 {
+		t.Errorf("deferred handlers ran %d at a time, want 1", got)
+	}
+	if got := bus.Stats().HandlersDeferred; got != 3 {
+		t.Errorf("HandlersDeferred = %d, want 3", got)
+	}
+}
+
+func TestBus_ClockTimeout(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	bus := NewBus(WithClock(clock), WithDefaultTimeout(time.Minute))
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	started := make(chan struct{})
+	var handlerErr error
+	bus.SubscribeFunc(topic.Topic("slow"), func(ctx context.Context, event any) error {
+		close(started)
+		<-ctx.Done()
+		handlerErr = ctx.Err()
+		return handlerErr
+	}, WithDeliveryMode(DeliveryAsync))
+
+	if err := bus.PublishAsync(context.Background(), NewEvent(topic.Topic("slow"), struct{}{}, "test")); err != nil {
+		t.Fatalf("PublishAsync() failed: %v", err)
+	}
+	<-started
+
+	// The timeout fires when the fake clock passes it, not in real time
+	clock.Advance(30 * time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	if err := bus.(Drainer).Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain() before the timeout error = %v, want the handler still running", err)
+	}
+	cancel()
+
+	clock.Advance(time.Minute)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := bus.(Drainer).Drain(ctx); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if !errors.Is(handlerErr, context.DeadlineExceeded) {
+		t.Errorf("handler context error = %v, want DeadlineExceeded", handlerErr)
+	}
+}
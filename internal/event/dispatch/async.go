@@ -24,6 +24,12 @@ type AsyncDispatcher struct {
 
 	// Handlers
 	panicHandler PanicHandler
+	clock        Clock
+
+	// Tasks enqueued and not yet executed, for Drain
+	pendingMu sync.Mutex
+	pending   int
+	idle      chan struct{} // closed while pending is zero
 
 	// Stats
 	enqueued    atomic.Uint64
@@ -51,7 +57,10 @@ func NewAsyncDispatcher(opts ...AsyncOption) *AsyncDispatcher {
 		workerCount:  10,
 		timeout:      5 * time.Second,
 		panicHandler: defaultPanicHandler,
+		clock:        SystemClock(),
+		idle:         make(chan struct{}),
 	}
+	close(d.idle)
 	for _, opt := range opts {
 		opt(d)
 	}
@@ -93,6 +102,15 @@ func WithAsyncPanicHandler(h PanicHandler) AsyncOption {
 	}
 }
 
+// WithAsyncClock sets the clock handler timeouts are measured on.
+func WithAsyncClock(clock Clock) AsyncOption {
+	return func(d *AsyncDispatcher) {
+		if clock != nil {
+			d.clock = clock
+		}
+	}
+}
+
 // Start starts the worker pool.
 func (d *AsyncDispatcher) Start() error {
 	d.mu.Lock()
@@ -169,6 +187,10 @@ func (d *AsyncDispatcher) EnqueueWithTimeout(ctx context.Context, event any, han
 		timeout: timeout,
 	}
 
+	// The task is counted before it is sent, since a worker may finish it
+	// before the send returns; it is uncounted if the queue is full.
+	d.addPending(1)
+
 	// While holding the lock, we know the channel is open because:
 	// 1. running is true (checked above)
 	// 2. Stop() acquires the same lock before closing the channel
@@ -177,6 +199,7 @@ func (d *AsyncDispatcher) EnqueueWithTimeout(ctx context.Context, event any, han
 		d.enqueued.Add(1)
 		return nil
 	default:
+		d.addPending(-1)
 		d.dropped.Add(1)
 		return ErrQueueFull
 	}
@@ -186,10 +209,46 @@ func (d *AsyncDispatcher) EnqueueWithTimeout(ctx context.Context, event any, han
 func (d *AsyncDispatcher) worker() {
 	defer d.wg.Done()
 
-	executor := NewExecutor(WithExecutorPanicHandler(d.panicHandler))
+	executor := NewExecutor(
+		WithExecutorPanicHandler(d.panicHandler),
+		WithExecutorClock(d.clock),
+	)
 
 	for task := range d.queue {
 		d.executeTask(executor, task)
+		d.addPending(-1)
+	}
+}
+
+// addPending adjusts the number of pending tasks, opening or closing the
+// idle channel as it leaves or reaches zero.
+func (d *AsyncDispatcher) addPending(delta int) {
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+
+	was := d.pending
+	d.pending += delta
+	switch {
+	case was == 0 && d.pending > 0:
+		d.idle = make(chan struct{})
+	case was > 0 && d.pending == 0:
+		close(d.idle)
+	}
+}
+
+// Drain blocks until every enqueued task has been executed, including tasks
+// that handlers enqueue while Drain waits, or until ctx is done. It is
+// intended for tests that assert on the effects of async handlers.
+func (d *AsyncDispatcher) Drain(ctx context.Context) error {
+	d.pendingMu.Lock()
+	idle := d.idle
+	d.pendingMu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
@@ -144,6 +144,53 @@ func TestAsyncDispatcher_QueueFull(t *testing.T) {
 	d.Stop(ctx)
 }
 
+func TestAsyncDispatcher_DrainWaitsForRunningTask(t *testing.T) {
+	d := NewAsyncDispatcher(
+		WithQueueSize(8),
+		WithWorkerCount(4),
+	)
+	d.Start()
+	defer d.Stop(context.Background())
+
+	blocker := make(chan struct{})
+	started := make(chan struct{})
+	slow := newTestHandler(func(ctx context.Context, event any) error {
+		close(started)
+		<-blocker
+		return nil
+	})
+	quick := newTestHandler(func(ctx context.Context, event any) error {
+		return nil
+	})
+
+	if err := d.Enqueue(context.Background(), "slow", slow); err != nil {
+		t.Fatalf("Enqueue() failed: %v", err)
+	}
+	<-started
+
+	d.pendingMu.Lock()
+	idle := d.idle
+	d.pendingMu.Unlock()
+
+	// Workers may finish a quick task before Enqueue returns; that must
+	// not count the still running slow task as done
+	for i := 0; i < 2000; i++ {
+		_ = d.Enqueue(context.Background(), i, quick)
+	}
+	select {
+	case <-idle:
+		t.Error("dispatcher went idle while a task was running")
+	default:
+	}
+	close(blocker)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := d.Drain(ctx); err != nil {
+		t.Fatalf("Drain() failed: %v", err)
+	}
+}
+
 func TestAsyncDispatcher_HandlerExecution(t *testing.T) {
 	d := NewAsyncDispatcher(
 		WithQueueSize(100),
@@ -0,0 +1,99 @@
+package dispatch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for handler timeouts and deadlines. The
+// default is the system clock; tests inject a fake clock to trigger
+// timeouts without sleeping.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// AfterFunc calls f in its own goroutine once d has elapsed. The
+	// returned func stops the timer, returning false if f already ran.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// SystemClock returns the clock backed by package time.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// systemClock implements Clock with package time.
+type systemClock struct{}
+
+// Now returns time.Now.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc wraps time.AfterFunc.
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// WithClockTimeout returns a copy of ctx that is cancelled with
+// context.DeadlineExceeded once timeout has elapsed on clock, or when ctx
+// is done. With the system clock it is context.WithTimeout.
+func WithClockTimeout(ctx context.Context, clock Clock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if clock == nil {
+		clock = SystemClock()
+	}
+	if _, ok := clock.(systemClock); ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	c := &clockContext{
+		Context:  ctx,
+		deadline: clock.Now().Add(timeout),
+		done:     make(chan struct{}),
+	}
+	stopTimer := clock.AfterFunc(timeout, func() { c.cancel(context.DeadlineExceeded) })
+	stopParent := context.AfterFunc(ctx, func() { c.cancel(ctx.Err()) })
+	return c, func() {
+		stopTimer()
+		stopParent()
+		c.cancel(context.Canceled)
+	}
+}
+
+// clockContext is a context with a deadline on a Clock.
+type clockContext struct {
+	context.Context
+	deadline time.Time
+
+	mu   sync.Mutex
+	done chan struct{}
+	err  error
+}
+
+// Deadline returns the deadline on the context's clock.
+func (c *clockContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+// Done returns a channel closed when the context is cancelled.
+func (c *clockContext) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns why the context was cancelled, or nil.
+func (c *clockContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// cancel cancels the context with err unless it is already cancelled.
+func (c *clockContext) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+		close(c.done)
+	}
+}
@@ -25,6 +25,10 @@
 // cancelled before or during handler execution, the dispatch returns
 // context.Canceled or context.DeadlineExceeded.
 //
+// Timeouts are measured on a Clock, the system clock by default. Tests
+// inject a fake clock with WithAsyncClock or WithExecutorClock, and use
+// AsyncDispatcher.Drain to wait until every enqueued task has run.
+//
 // # Usage
 //
 // Synchronous dispatch:
@@ -10,12 +10,14 @@ import (
 // panic recovery and timing.
 type Executor struct {
 	panicHandler PanicHandler
+	clock        Clock
 }
 
 // NewExecutor creates a new executor with the given options.
 func NewExecutor(opts ...ExecutorOption) *Executor {
 	e := &Executor{
 		panicHandler: defaultPanicHandler,
+		clock:        SystemClock(),
 	}
 	for _, opt := range opts {
 		opt(e)
@@ -33,6 +35,15 @@ func WithExecutorPanicHandler(h PanicHandler) ExecutorOption {
 	}
 }
 
+// WithExecutorClock sets the clock timeouts are measured on.
+func WithExecutorClock(clock Clock) ExecutorOption {
+	return func(e *Executor) {
+		if clock != nil {
+			e.clock = clock
+		}
+	}
+}
+
 // Execute runs a handler with the given event and returns the result.
 // It recovers from panics and captures timing information.
 func (e *Executor) Execute(ctx context.Context, event any, handler Handler) (result Result) {
@@ -96,7 +107,7 @@ func (e *Executor) ExecuteWithTimeout(ctx context.Context, event any, handler Ha
 		return e.Execute(ctx, event, handler)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel := WithClockTimeout(ctx, e.clock, timeout)
 	defer cancel()
 
 	return e.Execute(ctx, event, handler)
@@ -135,7 +146,7 @@ func (e *Executor) ExecuteAllWithTimeout(ctx context.Context, event any, handler
 		return e.ExecuteAll(ctx, event, handlers)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+	ctx, cancel := WithClockTimeout(ctx, e.clock, timeout)
 	defer cancel()
 
 	return e.ExecuteAll(ctx, event, handlers)
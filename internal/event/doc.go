@@ -89,9 +89,13 @@
 //	evt := event.NewEvent(event.Topic("buffer.content.inserted"), payload, "engine")
 //	bus.Publish(ctx, evt)
 //
-//	// Synchronous publish with error handling
+//	// Synchronous publish with error handling. Every handler runs; the
+//	// failures are reported together in a *PublishErrors.
 //	if err := bus.PublishSync(ctx, evt); err != nil {
-//	    log.Printf("publish failed: %v", err)
+//	    var pubErrs *event.PublishErrors
+//	    if errors.As(err, &pubErrs) {
+//	        log.Printf("rejected by %v: %v", pubErrs.SubscriptionIDs(), err)
+//	    }
 //	}
 //
 // # Type-Safe Events
@@ -119,6 +123,18 @@
 //	    return nil
 //	})
 //
+// # Subscription Groups
+//
+// Components that register many subscriptions can track them as a group and
+// remove them all at once, for example when a plugin is unloaded:
+//
+//	group := bus.NewGroup()
+//	group.SubscribeFunc("buffer.*", onBuffer)
+//	group.SubscribeFunc("cursor.*", onCursor)
+//
+//	// Later, during teardown
+//	group.Close()
+//
 // # Filtering
 //
 // Use filters to conditionally process events:
@@ -152,6 +168,8 @@
 //   - Use filters to reduce unnecessary handler invocations
 //   - The trie-based topic matcher provides O(k) matching where k is segments
 //   - Sync dispatch adds ~500ns overhead; async adds ~2-5us for goroutine spawn
+//   - Bus.Metrics reports per-topic publish counts and per-handler latency
+//     to find hot topics and slow handlers; disable it with WithMetrics(false)
 //
 // # Thread Safety
 //
@@ -159,6 +177,20 @@
 // be added/removed while events are being published. However, individual handlers
 // must manage their own thread safety.
 //
+// # Testing
+//
+// Drainer.Drain is a test hook that blocks until every queued async event
+// has been delivered, including events handlers publish while it waits, so
+// tests can assert on async effects without sleeping. WithClock injects a
+// fake clock that drives sync deadlines and async handler timeouts:
+//
+//	bus := event.NewBus(event.WithClock(fake), event.WithDefaultTimeout(time.Second))
+//	bus.PublishAsync(ctx, evt)
+//	fake.Advance(2 * time.Second) // time out the handler
+//	bus.(event.Drainer).Drain(ctx)
+//
+// Neither is meant for production code.
+//
 // # Subpackages
 //
 //   - events: Strongly-typed event payload definitions
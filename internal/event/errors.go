@@ -1,6 +1,10 @@
 package event
 
-import "errors"
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
 
 // Sentinel errors for the event bus.
 var (
@@ -40,6 +44,9 @@ var (
 	// ErrSubscriberClosed is returned when operations are attempted on a closed subscriber.
 	ErrSubscriberClosed = errors.New("subscriber is closed")
 
+	// ErrGroupClosed is returned when operations are attempted on a closed subscription group.
+	ErrGroupClosed = errors.New("subscription group is closed")
+
 	// ErrAdapterClosed is returned when operations are attempted on a closed adapter.
 	ErrAdapterClosed = errors.New("adapter is closed")
 )
@@ -90,3 +97,46 @@ func (e *PanicError) Error() string {
 func (e *PanicError) Is(target error) bool {
 	return target == ErrHandlerPanic
 }
+
+// PublishErrors is returned by PublishSync when one or more handlers fail.
+// Every handler still runs; each failure is recorded as a *HandlerError, or
+// a *PanicError for a handler that panicked, so errors.As and errors.Is see
+// through to the individual failures.
+type PublishErrors struct {
+	// Topic is the topic of the published event.
+	Topic string
+
+	// Errors holds the failures in the order the handlers ran.
+	Errors []error
+}
+
+// Error implements the error interface.
+func (e *PublishErrors) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strconv.Itoa(len(e.Errors)) + " handlers failed for topic " + e.Topic + ": " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the per-handler errors.
+func (e *PublishErrors) Unwrap() []error {
+	return e.Errors
+}
+
+// SubscriptionIDs returns the IDs of the subscriptions whose handlers failed.
+func (e *PublishErrors) SubscriptionIDs() []string {
+	ids := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		switch err := err.(type) {
+		case *HandlerError:
+			ids = append(ids, err.SubscriptionID)
+		case *PanicError:
+			ids = append(ids, err.SubscriptionID)
+		}
+	}
+	return ids
+}
@@ -51,4 +51,14 @@
 // Subscribers can use wildcards to match multiple topics:
 //   - "*" matches exactly one segment: "buffer.*" matches "buffer.cleared"
 //   - "**" matches zero or more segments: "buffer.**" matches "buffer.content.inserted"
+//
+// # Topic Catalog
+//
+// AllTopics lists every concrete topic defined here. Tooling can expand a
+// subscription pattern against it, for example to document a plugin's
+// subscriptions or to check that a pattern matches anything:
+//
+//	topics := topic.NewMatcher().ExpandPattern("buffer.**", events.AllTopics())
+//
+// New topic constants must also be added to the catalog in topics.go.
 package events
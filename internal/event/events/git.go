@@ -56,6 +56,10 @@ const (
 	// TopicGitRemoteRemoved is published when a remote is removed.
 	TopicGitRemoteRemoved topic.Topic = "git.remote.removed"
 
+	// TopicGitRemoteUpdated is published when a fetch, pull or push
+	// completes.
+	TopicGitRemoteUpdated topic.Topic = "git.remote.updated"
+
 	// TopicGitOperationStarted is published when a git operation starts.
 	TopicGitOperationStarted topic.Topic = "git.operation.started"
 
@@ -22,6 +22,11 @@ const (
 	// TopicPluginError is published when a plugin encounters an error.
 	TopicPluginError topic.Topic = "plugin.error"
 
+	// TopicPluginDisabled is published when a plugin is deactivated because
+	// it crashed repeatedly. The plugin system publishes it through its
+	// event provider with the plugin, reason and crash_count fields.
+	TopicPluginDisabled topic.Topic = "plugin.disabled"
+
 	// TopicPluginActionRegistered is published when a plugin registers an action.
 	TopicPluginActionRegistered topic.Topic = "plugin.action.registered"
 
@@ -144,6 +149,19 @@ type PluginError struct {
 	Stack string
 }
 
+// PluginDisabled is published when a plugin is deactivated because it
+// crashed repeatedly.
+type PluginDisabled struct {
+	// PluginName is the unique plugin identifier.
+	PluginName string
+
+	// Reason explains why the plugin was disabled.
+	Reason string
+
+	// CrashCount is the number of crashes that led to disabling it.
+	CrashCount int
+}
+
 // PluginActionRegistered is published when a plugin registers an action.
 type PluginActionRegistered struct {
 	// PluginName is the unique plugin identifier.
@@ -0,0 +1,207 @@
+package events
+
+import "github.com/dshills/keystorm/internal/event/topic"
+
+// allTopics lists every concrete topic constant in this package.
+var allTopics = []topic.Topic{
+	// Buffer
+	TopicBufferContentInserted,
+	TopicBufferContentDeleted,
+	TopicBufferContentReplaced,
+	TopicBufferRevisionChanged,
+	TopicBufferSnapshotCreated,
+	TopicBufferCleared,
+	TopicBufferReadOnlyChanged,
+	TopicBufferCreated,
+	TopicBufferClosed,
+	TopicBufferSaved,
+	TopicBufferDirtyChanged,
+
+	// Cursor
+	TopicCursorMoved,
+	TopicCursorAdded,
+	TopicCursorRemoved,
+	TopicCursorSelectionChanged,
+	TopicCursorAllCleared,
+
+	// Input
+	TopicInputKeystroke,
+	TopicInputSequenceResolved,
+	TopicInputSequencePending,
+	TopicInputSequenceAborted,
+	TopicInputModeChanged,
+	TopicInputMacroStarted,
+	TopicInputMacroStopped,
+	TopicInputMacroPlayed,
+	TopicInputMouseClicked,
+	TopicInputMouseDragged,
+	TopicInputMouseScrolled,
+
+	// Config
+	TopicConfigChanged,
+	TopicConfigSectionReloaded,
+	TopicConfigKeymapUpdated,
+	TopicConfigFileWatched,
+	TopicConfigFileModified,
+	TopicConfigValidationError,
+
+	// Project
+	TopicProjectFileOpened,
+	TopicProjectFileClosed,
+	TopicProjectFileSaved,
+	TopicProjectFileChanged,
+	TopicProjectFileDirtyChanged,
+	TopicProjectFileRenamed,
+	TopicProjectWorkspaceOpened,
+	TopicProjectWorkspaceClosed,
+	TopicProjectWorkspaceFolderAdded,
+	TopicProjectWorkspaceFolderRemoved,
+	TopicProjectIndexStarted,
+	TopicProjectIndexProgress,
+	TopicProjectIndexCompleted,
+	TopicProjectIndexChanged,
+	TopicProjectSearchStarted,
+	TopicProjectSearchResult,
+	TopicProjectSearchCompleted,
+
+	// Plugin
+	TopicPluginLoaded,
+	TopicPluginUnloaded,
+	TopicPluginActivated,
+	TopicPluginDeactivated,
+	TopicPluginReloaded,
+	TopicPluginError,
+	TopicPluginDisabled,
+	TopicPluginActionRegistered,
+	TopicPluginActionUnregistered,
+	TopicPluginSettingsChanged,
+	TopicPluginMessage,
+	TopicPluginAPICall,
+	TopicPluginDiscovered,
+	TopicPluginInstalled,
+	TopicPluginUninstalled,
+
+	// LSP
+	TopicLSPServerInitialized,
+	TopicLSPServerShutdown,
+	TopicLSPServerError,
+	TopicLSPServerRestarted,
+	TopicLSPDiagnosticsPublished,
+	TopicLSPDiagnosticsCleared,
+	TopicLSPCompletionAvailable,
+	TopicLSPCompletionResolved,
+	TopicLSPHoverAvailable,
+	TopicLSPSignatureAvailable,
+	TopicLSPDefinitionFound,
+	TopicLSPReferencesFound,
+	TopicLSPSymbolsFound,
+	TopicLSPSemanticTokensUpdated,
+	TopicLSPCodeActionsAvailable,
+	TopicLSPCodeActionApplied,
+	TopicLSPFormatApplied,
+	TopicLSPRenameApplied,
+	TopicLSPProgressStarted,
+	TopicLSPProgressUpdated,
+	TopicLSPProgressEnded,
+
+	// Terminal
+	TopicTerminalCreated,
+	TopicTerminalClosed,
+	TopicTerminalOutput,
+	TopicTerminalInput,
+	TopicTerminalExited,
+	TopicTerminalResized,
+	TopicTerminalTitleChanged,
+	TopicTerminalCwdChanged,
+	TopicTerminalBell,
+	TopicTerminalFocused,
+	TopicTerminalBlurred,
+
+	// Git
+	TopicGitStatusChanged,
+	TopicGitBranchChanged,
+	TopicGitCommitCreated,
+	TopicGitConflictDetected,
+	TopicGitConflictResolved,
+	TopicGitStashCreated,
+	TopicGitStashApplied,
+	TopicGitStashDropped,
+	TopicGitFetchCompleted,
+	TopicGitPullCompleted,
+	TopicGitPushCompleted,
+	TopicGitMergeCompleted,
+	TopicGitRebaseCompleted,
+	TopicGitTagCreated,
+	TopicGitRemoteAdded,
+	TopicGitRemoteRemoved,
+	TopicGitRemoteUpdated,
+	TopicGitOperationStarted,
+	TopicGitOperationProgress,
+	TopicGitOperationFailed,
+
+	// Debug
+	TopicDebugSessionStarted,
+	TopicDebugSessionStopped,
+	TopicDebugSessionPaused,
+	TopicDebugSessionResumed,
+	TopicDebugBreakpointHit,
+	TopicDebugBreakpointAdded,
+	TopicDebugBreakpointRemoved,
+	TopicDebugBreakpointChanged,
+	TopicDebugStepCompleted,
+	TopicDebugVariablesUpdated,
+	TopicDebugCallStackUpdated,
+	TopicDebugOutputReceived,
+	TopicDebugExceptionThrown,
+	TopicDebugThreadStarted,
+	TopicDebugThreadExited,
+	TopicDebugModuleLoaded,
+	TopicDebugWatchEvaluated,
+
+	// Task
+	TopicTaskDiscovered,
+	TopicTaskStarted,
+	TopicTaskOutput,
+	TopicTaskCompleted,
+	TopicTaskProblemFound,
+	TopicTaskCancelled,
+	TopicTaskFailed,
+	TopicTaskQueueUpdated,
+	TopicTaskDependencyResolved,
+
+	// Dispatcher
+	TopicDispatcherActionDispatched,
+	TopicDispatcherActionExecuted,
+	TopicDispatcherActionFailed,
+	TopicDispatcherActionCancelled,
+	TopicDispatcherModeChanged,
+	TopicDispatcherViewUpdateRequested,
+	TopicDispatcherUndoRedoPerformed,
+	TopicDispatcherRepeatRequested,
+	TopicDispatcherActionRegistered,
+	TopicDispatcherActionUnregistered,
+	TopicDispatcherQueueUpdated,
+
+	// Renderer
+	TopicRendererFrameRendered,
+	TopicRendererRedrawNeeded,
+	TopicRendererResizeHandled,
+	TopicRendererScrollChanged,
+	TopicRendererHighlightInvalidated,
+	TopicRendererCursorBlink,
+	TopicRendererThemeChanged,
+	TopicRendererFontChanged,
+	TopicRendererViewportChanged,
+	TopicRendererSelectionRendered,
+	TopicRendererDiagnosticsRendered,
+	TopicRendererGutterUpdated,
+	TopicRendererStatusLineUpdated,
+	TopicRendererPanelToggled,
+}
+
+// AllTopics returns every concrete topic defined by this package, in the
+// order their modules are listed in the package documentation. The result
+// is a copy and may be modified.
+func AllTopics() []topic.Topic {
+	return append([]topic.Topic(nil), allTopics...)
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/event/topic"
+)
+
+func TestAllTopicsListsEveryConstant(t *testing.T) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listed := make(map[string]bool)
+	for _, tp := range AllTopics() {
+		if listed[string(tp)] {
+			t.Errorf("AllTopics() lists %q twice", tp)
+		}
+		listed[string(tp)] = true
+	}
+
+	declared := 0
+	for _, file := range pkgs["events"].Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				value := spec.(*ast.ValueSpec)
+				sel, ok := value.Type.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "Topic" {
+					continue
+				}
+				for _, v := range value.Values {
+					lit := v.(*ast.BasicLit)
+					declared++
+					if !listed[lit.Value[1:len(lit.Value)-1]] {
+						t.Errorf("AllTopics() is missing %s", lit.Value)
+					}
+				}
+			}
+		}
+	}
+	if declared != len(AllTopics()) {
+		t.Errorf("AllTopics() has %d topics, %d are declared", len(AllTopics()), declared)
+	}
+}
+
+func TestExpandBufferTopics(t *testing.T) {
+	got := topic.NewMatcher().ExpandPattern("buffer.**", AllTopics())
+
+	var want []topic.Topic
+	for _, tp := range AllTopics() {
+		if tp.HasPrefix("buffer") {
+			want = append(want, tp)
+		}
+	}
+	if len(want) != 11 {
+		t.Fatalf("catalog has %d buffer topics, want 11", len(want))
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ExpandPattern(buffer.**) = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("ExpandPattern(buffer.**)[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if got := topic.NewMatcher().ExpandPattern("buffer.nothing.*", AllTopics()); len(got) != 0 {
+		t.Errorf("ExpandPattern(buffer.nothing.*) = %v, want none", got)
+	}
+}
@@ -0,0 +1,265 @@
+package event
+
+import (
+	"context"
+	"math/bits"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dshills/keystorm/internal/event/topic"
+)
+
+// latencyBuckets is the number of buckets in a latency histogram. Bucket i
+// holds durations below latencyBase<<i; the last bucket is unbounded.
+const latencyBuckets = 32
+
+// latencyBase is the upper bound of the first latency bucket.
+const latencyBase = time.Microsecond
+
+// Metrics is a snapshot of per-topic and per-subscription bus metrics,
+// returned by Bus.Metrics. It is empty when metrics are disabled with
+// WithMetrics(false).
+type Metrics struct {
+	// Topics holds publish counts keyed by event topic.
+	Topics map[topic.Topic]TopicMetrics
+
+	// Subscriptions holds handler metrics for registered subscriptions,
+	// sorted by total handler time, slowest first.
+	Subscriptions []SubscriptionMetrics
+
+	// Since is when collection started or ResetMetrics was last called.
+	Since time.Time
+}
+
+// TopicMetrics contains metrics for a single event topic.
+type TopicMetrics struct {
+	// Published is the number of events published on the topic, including
+	// events that had no subscribers.
+	Published uint64
+}
+
+// SubscriptionMetrics contains handler metrics for a subscription.
+type SubscriptionMetrics struct {
+	// ID is the subscription ID.
+	ID string
+
+	// Topic is the subscribed topic pattern.
+	Topic topic.Topic
+
+	// Invocations is the number of times the handler ran.
+	Invocations uint64
+
+	// Errors is the number of invocations that returned an error or
+	// panicked.
+	Errors uint64
+
+	// TotalTime is the cumulative handler execution time.
+	TotalTime time.Duration
+
+	// MaxTime is the longest handler execution.
+	MaxTime time.Duration
+
+	// P50, P90 and P99 are handler latency percentiles. They are
+	// approximate: each is the upper bound of a power-of-two histogram
+	// bucket, capped at MaxTime.
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// handlerMetrics collects lock-free handler metrics for a subscription.
+type handlerMetrics struct {
+	invocations atomic.Uint64
+	errors      atomic.Uint64
+	totalNs     atomic.Int64
+	maxNs       atomic.Int64
+	buckets     [latencyBuckets]atomic.Uint64
+}
+
+// record adds one handler execution.
+func (m *handlerMetrics) record(d time.Duration, failed bool) {
+	m.invocations.Add(1)
+	if failed {
+		m.errors.Add(1)
+	}
+
+	ns := d.Nanoseconds()
+	m.totalNs.Add(ns)
+	for {
+		max := m.maxNs.Load()
+		if ns <= max || m.maxNs.CompareAndSwap(max, ns) {
+			break
+		}
+	}
+	m.buckets[latencyBucket(d)].Add(1)
+}
+
+// reset zeroes all counters.
+func (m *handlerMetrics) reset() {
+	m.invocations.Store(0)
+	m.errors.Store(0)
+	m.totalNs.Store(0)
+	m.maxNs.Store(0)
+	for i := range m.buckets {
+		m.buckets[i].Store(0)
+	}
+}
+
+// snapshot returns the metrics for sub.
+func (m *handlerMetrics) snapshot(sub *subscription) SubscriptionMetrics {
+	var counts [latencyBuckets]uint64
+	var total uint64
+	for i := range m.buckets {
+		counts[i] = m.buckets[i].Load()
+		total += counts[i]
+	}
+
+	max := time.Duration(m.maxNs.Load())
+	return SubscriptionMetrics{
+		ID:          sub.id,
+		Topic:       sub.topic,
+		Invocations: m.invocations.Load(),
+		Errors:      m.errors.Load(),
+		TotalTime:   time.Duration(m.totalNs.Load()),
+		MaxTime:     max,
+		P50:         latencyPercentile(counts[:], total, 0.50, max),
+		P90:         latencyPercentile(counts[:], total, 0.90, max),
+		P99:         latencyPercentile(counts[:], total, 0.99, max),
+	}
+}
+
+// latencyBucket returns the histogram bucket for d.
+func latencyBucket(d time.Duration) int {
+	if d < latencyBase {
+		return 0
+	}
+	i := bits.Len64(uint64(d / latencyBase))
+	if i >= latencyBuckets {
+		return latencyBuckets - 1
+	}
+	return i
+}
+
+// latencyPercentile returns the upper bound of the bucket containing
+// percentile p, capped at max.
+func latencyPercentile(counts []uint64, total uint64, p float64, max time.Duration) time.Duration {
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(p*float64(total) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+
+	var seen uint64
+	for i, c := range counts {
+		seen += c
+		if seen >= rank {
+			bound := latencyBase << i
+			if i == len(counts)-1 || bound > max {
+				return max
+			}
+			return bound
+		}
+	}
+	return max
+}
+
+// timedHandler wraps an async handler to record its metrics.
+type timedHandler struct {
+	sub *subscription
+}
+
+// Handle implements Handler. Panics are recorded as errors and re-raised
+// for the dispatcher to recover.
+func (h timedHandler) Handle(ctx context.Context, event any) (err error) {
+	start := time.Now()
+	completed := false
+	defer func() {
+		h.sub.metrics.record(time.Since(start), !completed || err != nil)
+	}()
+
+	err = h.sub.handler.Handle(ctx, event)
+	completed = true
+	return err
+}
+
+// topicCounters holds per-topic publish counters.
+type topicCounters struct {
+	counts sync.Map // topic.Topic -> *atomic.Uint64
+}
+
+// inc increments the publish count of t.
+func (c *topicCounters) inc(t topic.Topic) {
+	if v, ok := c.counts.Load(t); ok {
+		v.(*atomic.Uint64).Add(1)
+		return
+	}
+	v, _ := c.counts.LoadOrStore(t, new(atomic.Uint64))
+	v.(*atomic.Uint64).Add(1)
+}
+
+// snapshot returns the current counts.
+func (c *topicCounters) snapshot() map[topic.Topic]TopicMetrics {
+	topics := make(map[topic.Topic]TopicMetrics)
+	c.counts.Range(func(k, v any) bool {
+		topics[k.(topic.Topic)] = TopicMetrics{Published: v.(*atomic.Uint64).Load()}
+		return true
+	})
+	return topics
+}
+
+// Metrics returns per-topic publish counts and per-subscription handler
+// metrics. Unlike Stats, it identifies hot topics and slow handlers.
+func (b *bus) Metrics() Metrics {
+	if !b.config.metricsEnabled {
+		return Metrics{Topics: map[topic.Topic]TopicMetrics{}}
+	}
+
+	subs := b.registry.All()
+	metrics := Metrics{
+		Topics:        b.topicCounts.snapshot(),
+		Subscriptions: make([]SubscriptionMetrics, 0, len(subs)),
+		Since:         time.Unix(0, b.metricsSince.Load()),
+	}
+	for _, sub := range subs {
+		metrics.Subscriptions = append(metrics.Subscriptions, sub.metrics.snapshot(sub))
+	}
+	sort.SliceStable(metrics.Subscriptions, func(i, j int) bool {
+		a, c := metrics.Subscriptions[i], metrics.Subscriptions[j]
+		if a.TotalTime != c.TotalTime {
+			return a.TotalTime > c.TotalTime
+		}
+		return a.ID < c.ID
+	})
+	return metrics
+}
+
+// ResetMetrics clears the metrics returned by Metrics, starting a new
+// sampling window. Stats are not affected.
+func (b *bus) ResetMetrics() {
+	b.topicCounts.counts.Clear()
+	for _, sub := range b.registry.All() {
+		sub.metrics.reset()
+	}
+	b.metricsSince.Store(time.Now().UnixNano())
+}
+
+// countPublish records a publish on t if metrics are enabled.
+func (b *bus) countPublish(t topic.Topic) {
+	if b.config.metricsEnabled {
+		b.topicCounts.inc(t)
+	}
+}
+
+// asyncHandler returns the handler to enqueue for sub, timed if metrics
+// are enabled.
+func (b *bus) asyncHandler(sub *subscription) Handler {
+	if b.config.metricsEnabled {
+		return sub.timed
+	}
+	return sub.handler
+}
@@ -0,0 +1,150 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dshills/keystorm/internal/event/topic"
+)
+
+func TestBus_Metrics(t *testing.T) {
+	bus := NewBus()
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	syncSub, _ := bus.SubscribeFunc(topic.Topic("buffer.changed"),
+		func(ctx context.Context, event any) error {
+			return errors.New("fail")
+		},
+		WithDeliveryMode(DeliverySync),
+	)
+
+	var wg sync.WaitGroup
+	// Only the async publishes reach the async wildcard handler.
+	wg.Add(5)
+	allSub, _ := bus.SubscribeFunc(topic.Topic("**"),
+		func(ctx context.Context, event any) error {
+			defer wg.Done()
+			time.Sleep(2 * time.Millisecond)
+			return nil
+		},
+		WithDeliveryMode(DeliveryAsync),
+	)
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		bus.PublishSync(ctx, NewEvent(topic.Topic("buffer.changed"), struct{}{}, "test"))
+		bus.PublishAsync(ctx, NewEvent(topic.Topic("buffer.changed"), struct{}{}, "test"))
+	}
+	bus.PublishAsync(ctx, NewEvent(topic.Topic("cursor.moved"), struct{}{}, "test"))
+	bus.PublishAsync(ctx, NewEvent(topic.Topic("cursor.moved"), struct{}{}, "test"))
+	wg.Wait()
+	// The async handler records its metrics as it returns.
+	time.Sleep(10 * time.Millisecond)
+
+	m := bus.Metrics()
+	if got := m.Topics["buffer.changed"].Published; got != 6 {
+		t.Errorf("buffer.changed Published = %d, want 6", got)
+	}
+	if got := m.Topics["cursor.moved"].Published; got != 2 {
+		t.Errorf("cursor.moved Published = %d, want 2", got)
+	}
+	if len(m.Subscriptions) != 2 {
+		t.Fatalf("len(Subscriptions) = %d, want 2", len(m.Subscriptions))
+	}
+
+	// The slow wildcard handler sorts first.
+	all := m.Subscriptions[0]
+	if all.ID != allSub.ID() {
+		t.Errorf("Subscriptions[0].ID = %q, want %q", all.ID, allSub.ID())
+	}
+	if all.Invocations != 5 {
+		t.Errorf("wildcard Invocations = %d, want 5", all.Invocations)
+	}
+	if all.Errors != 0 {
+		t.Errorf("wildcard Errors = %d, want 0", all.Errors)
+	}
+	if all.P50 < 2*time.Millisecond || all.P99 > all.MaxTime || all.MaxTime < all.P50 {
+		t.Errorf("wildcard latency P50 = %v, P99 = %v, Max = %v", all.P50, all.P99, all.MaxTime)
+	}
+
+	s := m.Subscriptions[1]
+	if s.ID != syncSub.ID() {
+		t.Errorf("Subscriptions[1].ID = %q, want %q", s.ID, syncSub.ID())
+	}
+	if s.Invocations != 3 || s.Errors != 3 {
+		t.Errorf("sync Invocations, Errors = %d, %d, want 3, 3", s.Invocations, s.Errors)
+	}
+}
+
+func TestBus_ResetMetrics(t *testing.T) {
+	bus := NewBus()
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	bus.SubscribeFunc(topic.Topic("test"),
+		func(ctx context.Context, event any) error { return nil },
+		WithDeliveryMode(DeliverySync),
+	)
+	bus.PublishSync(context.Background(), NewEvent(topic.Topic("test"), struct{}{}, "test"))
+
+	before := bus.Metrics().Since
+	bus.ResetMetrics()
+
+	m := bus.Metrics()
+	if len(m.Topics) != 0 {
+		t.Errorf("len(Topics) = %d, want 0", len(m.Topics))
+	}
+	if got := m.Subscriptions[0].Invocations; got != 0 {
+		t.Errorf("Invocations = %d, want 0", got)
+	}
+	if m.Since.Before(before) {
+		t.Errorf("Since = %v, want after %v", m.Since, before)
+	}
+
+	// Stats are unaffected.
+	if got := bus.Stats().EventsPublished; got != 1 {
+		t.Errorf("EventsPublished = %d, want 1", got)
+	}
+}
+
+func TestBus_MetricsDisabled(t *testing.T) {
+	bus := NewBus(WithMetrics(false))
+	bus.Start()
+	defer bus.Stop(context.Background())
+
+	bus.SubscribeFunc(topic.Topic("test"),
+		func(ctx context.Context, event any) error { return nil },
+		WithDeliveryMode(DeliverySync),
+	)
+	bus.PublishSync(context.Background(), NewEvent(topic.Topic("test"), struct{}{}, "test"))
+
+	m := bus.Metrics()
+	if len(m.Topics) != 0 || len(m.Subscriptions) != 0 {
+		t.Errorf("Metrics() = %+v, want empty", m)
+	}
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	var m handlerMetrics
+	for i := 0; i < 90; i++ {
+		m.record(500*time.Nanosecond, false)
+	}
+	for i := 0; i < 10; i++ {
+		m.record(3*time.Millisecond, false)
+	}
+
+	s := m.snapshot(&subscription{})
+	if s.P50 != latencyBase {
+		t.Errorf("P50 = %v, want %v", s.P50, latencyBase)
+	}
+	if s.P90 != latencyBase {
+		t.Errorf("P90 = %v, want %v", s.P90, latencyBase)
+	}
+	if s.P99 != 3*time.Millisecond {
+		t.Errorf("P99 = %v, want %v", s.P99, 3*time.Millisecond)
+	}
+}
@@ -1,6 +1,10 @@
 package event
 
-import "time"
+import (
+	"time"
+
+	"github.com/dshills/keystorm/internal/event/dispatch"
+)
 
 // BusOption configures an event Bus.
 type BusOption func(*busConfig)
@@ -21,6 +25,13 @@ type busConfig struct {
 
 	// metricsEnabled controls whether metrics are collected.
 	metricsEnabled bool
+
+	// syncDeadline bounds how long PublishSync waits for handlers below
+	// PriorityHigh. Zero disables the deadline.
+	syncDeadline time.Duration
+
+	// clock measures sync deadlines and async handler timeouts.
+	clock Clock
 }
 
 // defaultBusConfig returns sensible default configuration.
@@ -31,6 +42,7 @@ func defaultBusConfig() busConfig {
 		defaultTimeout:   5 * time.Second,
 		panicHandler:     DefaultPanicHandler,
 		metricsEnabled:   true,
+		clock:            dispatch.SystemClock(),
 	}
 }
 
@@ -68,9 +80,42 @@ func WithBusPanicHandler(h PanicHandler) BusOption {
 	}
 }
 
-// WithMetrics enables or disables metrics collection.
+// WithMetrics enables or disables metrics collection. When disabled,
+// Bus.Metrics returns no data and publishing skips per-topic and
+// per-handler bookkeeping. Default: enabled.
 func WithMetrics(enabled bool) BusOption {
 	return func(c *busConfig) {
 		c.metricsEnabled = enabled
 	}
 }
+
+// WithSyncDeadline bounds how long PublishSync may block on handlers with a
+// priority below PriorityHigh. Critical and High handlers always run to
+// completion first. Once a publish has taken longer than d, the remaining
+// lower-priority handlers are queued on the async dispatcher without
+// waiting for them, and a lower-priority handler still running when the
+// deadline passes keeps running in the background. This keeps the render
+// path responsive when a plugin's sync handler misbehaves. Zero (the
+// default) disables the deadline.
+func WithSyncDeadline(d time.Duration) BusOption {
+	return func(c *busConfig) {
+		if d >= 0 {
+			c.syncDeadline = d
+		}
+	}
+}
+
+// Clock is the source of time for sync deadlines and async handler
+// timeouts. See WithClock.
+type Clock = dispatch.Clock
+
+// WithClock sets the clock sync deadlines and async handler timeouts are
+// measured on. Tests inject a fake clock to trigger timeouts
+// deterministically instead of sleeping. Default: the system clock.
+func WithClock(clock Clock) BusOption {
+	return func(c *busConfig) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
@@ -192,34 +192,64 @@ func (s *Subscriber) Bus() Bus {
 	return s.bus
 }
 
+// subscriptionSource creates and removes subscriptions.
+// Both Bus and Subscriber satisfy it.
+type subscriptionSource interface {
+	Subscribe(topicPattern topic.Topic, handler Handler, opts ...SubscriptionOption) (Subscription, error)
+	Unsubscribe(sub Subscription) error
+}
+
 // SubscriptionGroup manages a group of related subscriptions.
-// Useful for components that need to subscribe to multiple topics.
+// Useful for components that need to subscribe to multiple topics and
+// tear them all down at once, such as a plugin being unloaded.
 type SubscriptionGroup struct {
-	subscriber *Subscriber
-	subs       []Subscription
-	mu         sync.Mutex
+	source subscriptionSource
+	subs   []Subscription
+	mu     sync.Mutex
+	closed bool
 }
 
 // NewSubscriptionGroup creates a new subscription group.
 func NewSubscriptionGroup(subscriber *Subscriber) *SubscriptionGroup {
+	return newSubscriptionGroup(subscriber)
+}
+
+// newSubscriptionGroup creates a group backed by the given source.
+func newSubscriptionGroup(source subscriptionSource) *SubscriptionGroup {
 	return &SubscriptionGroup{
-		subscriber: subscriber,
-		subs:       make([]Subscription, 0),
+		source: source,
+		subs:   make([]Subscription, 0),
 	}
 }
 
-// Add creates a subscription and adds it to the group.
-func (g *SubscriptionGroup) Add(topicPattern topic.Topic, handler Handler, opts ...SubscriptionOption) error {
+// Subscribe creates a subscription and adds it to the group.
+// Returns ErrGroupClosed if the group has been closed.
+func (g *SubscriptionGroup) Subscribe(topicPattern topic.Topic, handler Handler, opts ...SubscriptionOption) (Subscription, error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	sub, err := g.subscriber.Subscribe(topicPattern, handler, opts...)
+	if g.closed {
+		return nil, ErrGroupClosed
+	}
+
+	sub, err := g.source.Subscribe(topicPattern, handler, opts...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	g.subs = append(g.subs, sub)
-	return nil
+	return sub, nil
+}
+
+// SubscribeFunc creates a subscription with a function handler and adds it to the group.
+func (g *SubscriptionGroup) SubscribeFunc(topicPattern topic.Topic, fn HandlerFunc, opts ...SubscriptionOption) (Subscription, error) {
+	return g.Subscribe(topicPattern, fn, opts...)
+}
+
+// Add creates a subscription and adds it to the group.
+func (g *SubscriptionGroup) Add(topicPattern topic.Topic, handler Handler, opts ...SubscriptionOption) error {
+	_, err := g.Subscribe(topicPattern, handler, opts...)
+	return err
 }
 
 // AddFunc creates a subscription with a function handler and adds it to the group.
@@ -248,14 +278,34 @@ func (g *SubscriptionGroup) ResumeAll() {
 }
 
 // CancelAll cancels all subscriptions in the group.
+// The group remains usable for new subscriptions.
 func (g *SubscriptionGroup) CancelAll() {
-	g.mu.Lock()
-	defer g.mu.Unlock()
+	g.unsubscribe(g.take())
+}
 
-	for _, sub := range g.subs {
-		_ = g.subscriber.Unsubscribe(sub)
+// Close cancels all subscriptions in the group and prevents new ones.
+// It is idempotent and safe to call from within one of the group's
+// handlers while an event is being delivered.
+func (g *SubscriptionGroup) Close() error {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return nil
 	}
-	g.subs = g.subs[:0]
+	g.closed = true
+	subs := g.subs
+	g.subs = nil
+	g.mu.Unlock()
+
+	g.unsubscribe(subs)
+	return nil
+}
+
+// IsClosed returns true if the group has been closed.
+func (g *SubscriptionGroup) IsClosed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.closed
 }
 
 // Count returns the number of subscriptions in the group.
@@ -264,3 +314,23 @@ func (g *SubscriptionGroup) Count() int {
 	defer g.mu.Unlock()
 	return len(g.subs)
 }
+
+// take removes and returns the tracked subscriptions.
+func (g *SubscriptionGroup) take() []Subscription {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	subs := g.subs
+	g.subs = make([]Subscription, 0)
+	return subs
+}
+
+// unsubscribe removes subs from the source. It runs without the group lock
+// held so handlers being delivered may freely call back into the group.
+// Subscriptions that were already removed (e.g. one-time subscriptions
+// that fired) are ignored.
+func (g *SubscriptionGroup) unsubscribe(subs []Subscription) {
+	for _, sub := range subs {
+		_ = g.source.Unsubscribe(sub)
+	}
+}
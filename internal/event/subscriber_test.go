@@ -444,3 +444,109 @@ func TestSubscriptionGroup(t *testing.T) {
 		t.Errorf("Count = %d after CancelAll, want 0", group.Count())
 	}
 }
+
+func TestBus_NewGroup_BulkClose(t *testing.T) {
+	bus := NewBus()
+	if err := bus.Start(); err != nil {
+		t.Fatalf("bus.Start failed: %v", err)
+	}
+	defer bus.Stop(context.Background())
+
+	group := bus.NewGroup()
+
+	var count atomic.Int32
+	for i := 0; i < 100; i++ {
+		_, err := group.SubscribeFunc("test.group.bulk", func(ctx context.Context, event any) error {
+			count.Add(1)
+			return nil
+		}, WithDeliveryMode(DeliverySync))
+		if err != nil {
+			t.Fatalf("Subscribe %d failed: %v", i, err)
+		}
+	}
+
+	if group.Count() != 100 {
+		t.Errorf("Count = %d, want 100", group.Count())
+	}
+	if n := bus.Stats().ActiveSubscribers; n != 100 {
+		t.Errorf("ActiveSubscribers = %d, want 100", n)
+	}
+
+	_ = bus.PublishSync(context.Background(), Envelope{Topic: "test.group.bulk"})
+	if count.Load() != 100 {
+		t.Errorf("count = %d, want 100", count.Load())
+	}
+
+	if err := group.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if n := bus.Stats().ActiveSubscribers; n != 0 {
+		t.Errorf("ActiveSubscribers after Close = %d, want 0", n)
+	}
+
+	_ = bus.PublishSync(context.Background(), Envelope{Topic: "test.group.bulk"})
+	if count.Load() != 100 {
+		t.Errorf("count = %d after Close, want 100", count.Load())
+	}
+
+	// Close is idempotent
+	if err := group.Close(); err != nil {
+		t.Errorf("second Close failed: %v", err)
+	}
+	if !group.IsClosed() {
+		t.Error("expected group to be closed")
+	}
+
+	_, err := group.SubscribeFunc("test.group.bulk", func(ctx context.Context, event any) error {
+		return nil
+	})
+	if err != ErrGroupClosed {
+		t.Errorf("Subscribe after Close: err = %v, want ErrGroupClosed", err)
+	}
+}
+
+func TestSubscriptionGroup_CloseDuringDelivery(t *testing.T) {
+	bus := NewBus()
+	if err := bus.Start(); err != nil {
+		t.Fatalf("bus.Start failed: %v", err)
+	}
+	defer bus.Stop(context.Background())
+
+	group := bus.NewGroup()
+
+	var count atomic.Int32
+	_, err := group.SubscribeFunc("test.group.close", func(ctx context.Context, event any) error {
+		count.Add(1)
+		return group.Close()
+	}, WithDeliveryMode(DeliverySync), WithPriority(PriorityCritical))
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	_, err = group.SubscribeFunc("test.group.close", func(ctx context.Context, event any) error {
+		count.Add(1)
+		return nil
+	}, WithDeliveryMode(DeliverySync), WithPriority(PriorityLow))
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = bus.PublishSync(context.Background(), Envelope{Topic: "test.group.close"})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("PublishSync deadlocked when closing group from a handler")
+	}
+
+	// The lower-priority handler was cancelled before it could run
+	if count.Load() != 1 {
+		t.Errorf("count = %d, want 1", count.Load())
+	}
+	if group.Count() != 0 {
+		t.Errorf("Count = %d, want 0", group.Count())
+	}
+}
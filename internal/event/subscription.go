@@ -127,6 +127,11 @@ type subscription struct {
 	handler Handler
 	config  SubscriptionConfig
 	state   atomic.Int32
+
+	// metrics collects handler metrics; timed wraps handler to record
+	// them for async delivery.
+	metrics handlerMetrics
+	timed   Handler
 }
 
 // newSubscription creates a new subscription.
@@ -142,6 +147,7 @@ func newSubscription(id string, t topic.Topic, h Handler, opts ...SubscriptionOp
 		handler: h,
 		config:  config,
 	}
+	s.timed = timedHandler{sub: s}
 	s.state.Store(int32(SubscriptionStateActive))
 	return s
 }
@@ -42,6 +42,27 @@ func (m *Matcher) MatchExact(topic Topic) bool {
 	return m.trie.MatchExact(topic)
 }
 
+// ExpandPattern returns the topics in universe that pattern matches, in
+// universe order and without duplicates. Wildcard topics in universe are
+// skipped. It is used to list what a subscription would receive, such as
+// the concrete topics from events.AllTopics. The matcher's own patterns are
+// not consulted.
+func (m *Matcher) ExpandPattern(pattern Topic, universe []Topic) []Topic {
+	var matched []Topic
+	seen := make(map[Topic]struct{})
+	for _, t := range universe {
+		if t.IsWildcard() || !t.Matches(pattern) {
+			continue
+		}
+		if _, dup := seen[t]; dup {
+			continue
+		}
+		seen[t] = struct{}{}
+		matched = append(matched, t)
+	}
+	return matched
+}
+
 // Patterns returns all patterns in the matcher.
 func (m *Matcher) Patterns() []Topic {
 	return m.trie.All()
@@ -438,3 +438,39 @@ func BenchmarkMatcher_Match_ManyPatterns(b *testing.B) {
 		_ = m.Match(topic)
 	}
 }
+
+func TestMatcher_ExpandPattern(t *testing.T) {
+	m := NewMatcher()
+	universe := []Topic{
+		"buffer.content.inserted",
+		"buffer.cleared",
+		"buffer.*",
+		"cursor.moved",
+		"buffer.cleared",
+	}
+
+	tests := []struct {
+		pattern Topic
+		want    []Topic
+	}{
+		{"buffer.**", []Topic{"buffer.content.inserted", "buffer.cleared"}},
+		{"buffer.*", []Topic{"buffer.cleared"}},
+		{"*.moved", []Topic{"cursor.moved"}},
+		{"cursor.moved", []Topic{"cursor.moved"}},
+		{"git.**", nil},
+	}
+
+	for _, tt := range tests {
+		got := m.ExpandPattern(tt.pattern, universe)
+		if len(got) != len(tt.want) {
+			t.Errorf("ExpandPattern(%q) = %v, want %v", tt.pattern, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ExpandPattern(%q) = %v, want %v", tt.pattern, got, tt.want)
+				break
+			}
+		}
+	}
+}
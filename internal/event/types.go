@@ -127,6 +127,11 @@ type Stats struct {
 	// HandlerPanics is the number of handlers that panicked.
 	HandlerPanics uint64
 
+	// HandlersDeferred is the number of sync handlers started or left
+	// running in the background because a publish exceeded the sync
+	// deadline.
+	HandlersDeferred uint64
+
 	// AvgDeliveryTimeNs is the average event delivery time in nanoseconds.
 	AvgDeliveryTimeNs int64
 
@@ -0,0 +1,213 @@
+package input
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/mode"
+)
+
+// DefaultChordWindow is the time within which all keys of a chord must be
+// pressed when a ChordBinding does not set a window.
+const DefaultChordWindow = 100 * time.Millisecond
+
+// ErrInvalidChord is returned when registering a chord without at least two
+// keys or without an action.
+var ErrInvalidChord = errors.New("chord needs at least two keys and an action")
+
+// ChordBinding maps keys pressed in rapid succession to an action, e.g.
+// "jk" to leave insert mode. Unlike key sequences, chords are detected by
+// timing: if the keys arrive within Window the action fires and the keys are
+// not inserted; otherwise the keys are processed normally.
+type ChordBinding struct {
+	// Keys are the keys of the chord.
+	Keys []key.Event
+
+	// Window is the maximum time between the first and last key.
+	// Default: DefaultChordWindow.
+	Window time.Duration
+
+	// Action is the action dispatched when the chord completes.
+	Action string
+
+	// Mode is the mode the chord applies to. Default: insert.
+	Mode string
+
+	// AnyOrder allows the keys to be pressed in any order.
+	AnyOrder bool
+}
+
+// chordState tracks keys held back while a chord may still complete.
+type chordState struct {
+	pending []key.Event
+	timer   *time.Timer
+
+	// generation identifies the current pending chord so that a timer that
+	// fires after the chord was resolved is ignored.
+	generation uint64
+}
+
+// AddChord registers a chord binding.
+func (h *Handler) AddChord(chord ChordBinding) error {
+	if len(chord.Keys) < 2 || chord.Action == "" {
+		return ErrInvalidChord
+	}
+	if chord.Window <= 0 {
+		chord.Window = DefaultChordWindow
+	}
+	if chord.Mode == "" {
+		chord.Mode = mode.ModeInsert
+	}
+	chord.Keys = append([]key.Event(nil), chord.Keys...)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.chords = append(h.chords, chord)
+	return nil
+}
+
+// RemoveChord removes all chord bindings for an action.
+func (h *Handler) RemoveChord(action string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	filtered := h.chords[:0]
+	for _, c := range h.chords {
+		if c.Action != action {
+			filtered = append(filtered, c)
+		}
+	}
+	h.chords = filtered
+}
+
+// Chords returns the registered chord bindings.
+func (h *Handler) Chords() []ChordBinding {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]ChordBinding(nil), h.chords...)
+}
+
+// handleChordLocked feeds event to chord detection. It returns true if the
+// event was held back or completed a chord. Otherwise any held keys have
+// been processed literally and the caller processes event normally.
+// Caller must hold the lock.
+func (h *Handler) handleChordLocked(event key.Event) bool {
+	if len(h.chords) == 0 {
+		return false
+	}
+	if len(h.chord.pending) == 0 && h.context.PendingSequence != nil && h.context.PendingSequence.Len() > 0 {
+		return false // a chord never starts inside a key sequence
+	}
+
+	candidate := append(append([]key.Event(nil), h.chord.pending...), event)
+	var prefix bool
+	var window time.Duration
+	for _, c := range h.chords {
+		if c.Mode != h.context.Mode {
+			continue
+		}
+		if chordMatches(c, candidate) {
+			h.resetChordLocked()
+			h.dispatchAction(Action{
+				Name:   c.Action,
+				Source: SourceKeyboard,
+				Count:  1,
+			})
+			return true
+		}
+		if chordHasPrefix(c, candidate) && (!prefix || c.Window > window) {
+			prefix = true
+			window = c.Window
+		}
+	}
+
+	if prefix {
+		if len(h.chord.pending) == 0 {
+			h.startChordTimerLocked(window)
+		}
+		h.chord.pending = candidate
+		return true
+	}
+
+	// The event breaks the chord: release the held keys, then let the
+	// caller process the event, which may itself start a new chord.
+	if len(h.chord.pending) > 0 {
+		h.flushChordLocked()
+		return h.handleChordLocked(event)
+	}
+	return false
+}
+
+// startChordTimerLocked starts the window for a new pending chord.
+// Caller must hold the lock.
+func (h *Handler) startChordTimerLocked(window time.Duration) {
+	h.chord.generation++
+	generation := h.chord.generation
+	h.chord.timer = time.AfterFunc(window, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.closed || h.chord.generation != generation {
+			return
+		}
+		h.flushChordLocked()
+	})
+}
+
+// flushChordLocked processes held keys literally after a chord failed to
+// complete. Caller must hold the lock.
+func (h *Handler) flushChordLocked() {
+	pending := h.chord.pending
+	h.resetChordLocked()
+	for _, event := range pending {
+		h.processKeyLocked(event)
+	}
+}
+
+// resetChordLocked discards held keys and stops the window timer.
+// Caller must hold the lock.
+func (h *Handler) resetChordLocked() {
+	if h.chord.timer != nil {
+		h.chord.timer.Stop()
+		h.chord.timer = nil
+	}
+	h.chord.pending = nil
+	h.chord.generation++
+}
+
+// chordMatches returns true if events are exactly the keys of c.
+func chordMatches(c ChordBinding, events []key.Event) bool {
+	return len(events) == len(c.Keys) && chordHasPrefix(c, events)
+}
+
+// chordHasPrefix returns true if events can begin c: the first keys of c in
+// order, or with AnyOrder any subset of its keys.
+func chordHasPrefix(c ChordBinding, events []key.Event) bool {
+	if len(events) > len(c.Keys) {
+		return false
+	}
+	if !c.AnyOrder {
+		for i, e := range events {
+			if !e.Equals(c.Keys[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	used := make([]bool, len(c.Keys))
+	for _, e := range events {
+		found := false
+		for i, k := range c.Keys {
+			if !used[i] && e.Equals(k) {
+				used[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package input
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/mode"
+)
+
+// newChordHandler returns a handler in insert mode with "jk" chorded to
+// leave insert mode.
+func newChordHandler(t *testing.T, anyOrder bool) *Handler {
+	t.Helper()
+
+	h := NewHandler(DefaultConfig())
+	t.Cleanup(h.Close)
+
+	if err := h.SwitchMode(mode.ModeInsert); err != nil {
+		t.Fatalf("SwitchMode() error = %v", err)
+	}
+	err := h.AddChord(ChordBinding{
+		Keys:     []key.Event{key.NewRuneEvent('j', key.ModNone), key.NewRuneEvent('k', key.ModNone)},
+		Window:   100 * time.Millisecond,
+		Action:   "mode.normal",
+		AnyOrder: anyOrder,
+	})
+	if err != nil {
+		t.Fatalf("AddChord() error = %v", err)
+	}
+	return h
+}
+
+// collectActions returns the action names dispatched within d.
+func collectActions(h *Handler, d time.Duration) []string {
+	var names []string
+	timeout := time.After(d)
+	for {
+		select {
+		case a := <-h.Actions():
+			name := a.Name
+			if a.Args.Text != "" {
+				name += ":" + a.Args.Text
+			}
+			names = append(names, name)
+		case <-timeout:
+			return names
+		}
+	}
+}
+
+func equalNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestChordFires(t *testing.T) {
+	h := newChordHandler(t, false)
+
+	h.HandleKeyEvent(key.NewRuneEvent('j', key.ModNone))
+	h.HandleKeyEvent(key.NewRuneEvent('k', key.ModNone))
+
+	got := collectActions(h, 200*time.Millisecond)
+	if want := []string{"mode.normal"}; !equalNames(got, want) {
+		t.Errorf("actions = %v, want %v", got, want)
+	}
+}
+
+func TestChordWindowExpires(t *testing.T) {
+	h := newChordHandler(t, false)
+
+	h.HandleKeyEvent(key.NewRuneEvent('j', key.ModNone))
+
+	got := collectActions(h, 200*time.Millisecond)
+	if want := []string{"editor.insertText:j"}; !equalNames(got, want) {
+		t.Errorf("actions after pause = %v, want %v", got, want)
+	}
+
+	h.HandleKeyEvent(key.NewRuneEvent('k', key.ModNone))
+	got = collectActions(h, 50*time.Millisecond)
+	if want := []string{"editor.insertText:k"}; !equalNames(got, want) {
+		t.Errorf("actions after k = %v, want %v", got, want)
+	}
+}
+
+func TestChordBrokenByOtherKey(t *testing.T) {
+	h := newChordHandler(t, false)
+
+	h.HandleKeyEvent(key.NewRuneEvent('j', key.ModNone))
+	h.HandleKeyEvent(key.NewRuneEvent('x', key.ModNone))
+
+	got := collectActions(h, 50*time.Millisecond)
+	if want := []string{"editor.insertText:j", "editor.insertText:x"}; !equalNames(got, want) {
+		t.Errorf("actions = %v, want %v", got, want)
+	}
+}
+
+func TestChordOrder(t *testing.T) {
+	ordered := newChordHandler(t, false)
+	ordered.HandleKeyEvent(key.NewRuneEvent('k', key.ModNone))
+	ordered.HandleKeyEvent(key.NewRuneEvent('j', key.ModNone))
+
+	got := collectActions(ordered, 200*time.Millisecond)
+	if want := []string{"editor.insertText:k", "editor.insertText:j"}; !equalNames(got, want) {
+		t.Errorf("ordered chord actions = %v, want %v", got, want)
+	}
+
+	anyOrder := newChordHandler(t, true)
+	anyOrder.HandleKeyEvent(key.NewRuneEvent('k', key.ModNone))
+	anyOrder.HandleKeyEvent(key.NewRuneEvent('j', key.ModNone))
+
+	got = collectActions(anyOrder, 200*time.Millisecond)
+	if want := []string{"mode.normal"}; !equalNames(got, want) {
+		t.Errorf("any-order chord actions = %v, want %v", got, want)
+	}
+}
+
+func TestChordOtherMode(t *testing.T) {
+	h := newChordHandler(t, false)
+	if err := h.SwitchMode(mode.ModeNormal); err != nil {
+		t.Fatalf("SwitchMode() error = %v", err)
+	}
+
+	h.HandleKeyEvent(key.NewRuneEvent('j', key.ModNone))
+	h.HandleKeyEvent(key.NewRuneEvent('k', key.ModNone))
+
+	got := collectActions(h, 50*time.Millisecond)
+	if want := []string{"cursor.moveDown", "cursor.moveUp"}; !equalNames(got, want) {
+		t.Errorf("normal mode actions = %v, want %v", got, want)
+	}
+}
+
+func TestAddChordInvalid(t *testing.T) {
+	h := NewHandler(DefaultConfig())
+	defer h.Close()
+
+	err := h.AddChord(ChordBinding{Keys: []key.Event{key.NewRuneEvent('j', key.ModNone)}, Action: "mode.normal"})
+	if !errors.Is(err, ErrInvalidChord) {
+		t.Errorf("AddChord(one key) error = %v, want ErrInvalidChord", err)
+	}
+
+	err = h.AddChord(ChordBinding{Keys: []key.Event{key.NewRuneEvent('j', key.ModNone), key.NewRuneEvent('k', key.ModNone)}})
+	if !errors.Is(err, ErrInvalidChord) {
+		t.Errorf("AddChord(no action) error = %v, want ErrInvalidChord", err)
+	}
+
+	if len(h.Chords()) != 0 {
+		t.Errorf("Chords() = %v, want none", h.Chords())
+	}
+}
@@ -0,0 +1,231 @@
+package input
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/mode"
+)
+
+// DefaultComposeTimeout is how long a partial compose sequence waits for its
+// next key when a ComposeTable does not set a timeout.
+const DefaultComposeTimeout = time.Second
+
+// ErrInvalidCompose is returned when adding a compose sequence of fewer
+// than two keys.
+var ErrInvalidCompose = errors.New("compose sequence needs at least two keys")
+
+// ComposeTable maps sequences of typed characters to a composed character,
+// e.g. "'" followed by "e" to "é". It emulates dead keys and compose keys
+// for terminals and layouts that do not provide them. A ComposeTable is
+// safe for concurrent use.
+type ComposeTable struct {
+	mu        sync.RWMutex
+	sequences map[string]rune
+	prefixes  map[string]int
+
+	// Timeout is how long a partial sequence waits for its next key before
+	// its keys are typed literally. Default: DefaultComposeTimeout.
+	Timeout time.Duration
+}
+
+// NewComposeTable creates an empty compose table.
+func NewComposeTable() *ComposeTable {
+	return &ComposeTable{
+		sequences: make(map[string]rune),
+		prefixes:  make(map[string]int),
+	}
+}
+
+// DefaultComposeTable returns a table of common accents: acute ('),
+// grave (`), circumflex (^), diaeresis ("), tilde (~) and cedilla (,)
+// followed by a letter.
+func DefaultComposeTable() *ComposeTable {
+	t := NewComposeTable()
+	accents := []struct {
+		mark    rune
+		letters string
+		results string
+	}{
+		{'\'', "aeiouyAEIOUY", "áéíóúýÁÉÍÓÚÝ"},
+		{'`', "aeiouAEIOU", "àèìòùÀÈÌÒÙ"},
+		{'^', "aeiouAEIOU", "âêîôûÂÊÎÔÛ"},
+		{'"', "aeiouyAEIOU", "äëïöüÿÄËÏÖÜ"},
+		{'~', "anoANO", "ãñõÃÑÕ"},
+		{',', "cC", "çÇ"},
+	}
+	for _, a := range accents {
+		results := []rune(a.results)
+		for i, letter := range []rune(a.letters) {
+			_ = t.Add(string([]rune{a.mark, letter}), results[i])
+		}
+	}
+	return t
+}
+
+// Add maps a sequence of characters to a composed character.
+func (t *ComposeTable) Add(sequence string, composed rune) error {
+	runes := []rune(sequence)
+	if len(runes) < 2 {
+		return ErrInvalidCompose
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, exists := t.sequences[sequence]; !exists {
+		for i := 1; i < len(runes); i++ {
+			t.prefixes[string(runes[:i])]++
+		}
+	}
+	t.sequences[sequence] = composed
+	return nil
+}
+
+// Remove removes a sequence from the table.
+func (t *ComposeTable) Remove(sequence string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, exists := t.sequences[sequence]; !exists {
+		return
+	}
+	delete(t.sequences, sequence)
+	runes := []rune(sequence)
+	for i := 1; i < len(runes); i++ {
+		prefix := string(runes[:i])
+		if t.prefixes[prefix]--; t.prefixes[prefix] == 0 {
+			delete(t.prefixes, prefix)
+		}
+	}
+}
+
+// Lookup returns the composed character for sequence, if it is complete,
+// and whether it is the beginning of a longer sequence.
+func (t *ComposeTable) Lookup(sequence string) (composed rune, complete, prefix bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	composed, complete = t.sequences[sequence]
+	return composed, complete, t.prefixes[sequence] > 0
+}
+
+// timeout returns the effective timeout of the table.
+func (t *ComposeTable) timeout() time.Duration {
+	if t.Timeout > 0 {
+		return t.Timeout
+	}
+	return DefaultComposeTimeout
+}
+
+// composeState tracks keys held back while a compose sequence may still
+// complete.
+type composeState struct {
+	pending []key.Event
+	timer   *time.Timer
+
+	// generation identifies the current pending sequence so that a timer
+	// that fires after the sequence was resolved is ignored.
+	generation uint64
+}
+
+// SetComposeTable sets the table used to compose characters in insert and
+// replace modes. A nil table disables composition.
+func (h *Handler) SetComposeTable(table *ComposeTable) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.flushComposeLocked()
+	h.composeTable = table
+}
+
+// ComposeTable returns the compose table, or nil if composition is disabled.
+func (h *Handler) ComposeTable() *ComposeTable {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.composeTable
+}
+
+// handleComposeLocked feeds event to character composition. It returns true
+// if the event was held back or completed a sequence, whose character has
+// then been typed in place of its keys. Otherwise any held keys have been
+// typed literally and the caller processes event normally.
+// Caller must hold the lock.
+func (h *Handler) handleComposeLocked(event key.Event) bool {
+	if h.composeTable == nil {
+		return false
+	}
+	if h.context.Mode != mode.ModeInsert && h.context.Mode != mode.ModeReplace {
+		h.flushComposeLocked()
+		return false
+	}
+
+	composable := event.Key == key.KeyRune && !event.Modifiers.HasCtrl() &&
+		!event.Modifiers.HasAlt() && !event.Modifiers.HasMeta()
+	if !composable {
+		h.flushComposeLocked()
+		return false
+	}
+
+	runes := make([]rune, 0, len(h.compose.pending)+1)
+	for _, e := range h.compose.pending {
+		runes = append(runes, e.Rune)
+	}
+	runes = append(runes, event.Rune)
+
+	composed, complete, prefix := h.composeTable.Lookup(string(runes))
+	switch {
+	case complete:
+		h.resetComposeLocked()
+		h.feedKeyLocked(key.NewRuneEvent(composed, key.ModNone))
+		return true
+	case prefix:
+		if len(h.compose.pending) == 0 {
+			h.startComposeTimerLocked(h.composeTable.timeout())
+		}
+		h.compose.pending = append(h.compose.pending, event)
+		return true
+	}
+
+	// The event breaks the sequence: type the held keys, then let the event
+	// start a new one
+	if len(h.compose.pending) > 0 {
+		h.flushComposeLocked()
+		return h.handleComposeLocked(event)
+	}
+	return false
+}
+
+// startComposeTimerLocked starts the timeout of a new pending sequence.
+// Caller must hold the lock.
+func (h *Handler) startComposeTimerLocked(timeout time.Duration) {
+	h.compose.generation++
+	generation := h.compose.generation
+	h.compose.timer = time.AfterFunc(timeout, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.closed || h.compose.generation != generation {
+			return
+		}
+		h.flushComposeLocked()
+	})
+}
+
+// flushComposeLocked types held keys literally after a sequence failed to
+// complete. Caller must hold the lock.
+func (h *Handler) flushComposeLocked() {
+	pending := h.compose.pending
+	h.resetComposeLocked()
+	for _, event := range pending {
+		h.feedKeyLocked(event)
+	}
+}
+
+// resetComposeLocked discards held keys and stops the timeout.
+// Caller must hold the lock.
+func (h *Handler) resetComposeLocked() {
+	if h.compose.timer != nil {
+		h.compose.timer.Stop()
+		h.compose.timer = nil
+	}
+	h.compose.pending = nil
+	h.compose.generation++
+}
@@ -0,0 +1,116 @@
+package input
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/mode"
+)
+
+// newComposeHandler returns a handler in insert mode composing accents
+// with a 50ms timeout.
+func newComposeHandler(t *testing.T) *Handler {
+	t.Helper()
+
+	h := NewHandler(DefaultConfig())
+	t.Cleanup(h.Close)
+
+	if err := h.SwitchMode(mode.ModeInsert); err != nil {
+		t.Fatalf("SwitchMode() error = %v", err)
+	}
+	table := DefaultComposeTable()
+	table.Timeout = 50 * time.Millisecond
+	h.SetComposeTable(table)
+	return h
+}
+
+func typeRunes(h *Handler, s string) {
+	for _, r := range s {
+		h.HandleKeyEvent(key.NewRuneEvent(r, key.ModNone))
+	}
+}
+
+func TestComposeTwoKeys(t *testing.T) {
+	h := newComposeHandler(t)
+
+	typeRunes(h, "^e")
+
+	got := collectActions(h, 20*time.Millisecond)
+	want := []string{"editor.insertText:ê"}
+	if !equalNames(got, want) {
+		t.Errorf("actions = %v, want %v", got, want)
+	}
+}
+
+func TestComposeTimeoutTypesKeys(t *testing.T) {
+	h := newComposeHandler(t)
+
+	// Nothing is typed while the sequence may still complete
+	typeRunes(h, "^")
+	if got := collectActions(h, 20*time.Millisecond); len(got) != 0 {
+		t.Fatalf("actions before timeout = %v, want none", got)
+	}
+
+	// After the timeout the key is typed, and the next key on its own
+	got := collectActions(h, 60*time.Millisecond)
+	typeRunes(h, "e")
+	got = append(got, collectActions(h, 20*time.Millisecond)...)
+	want := []string{"editor.insertText:^", "editor.insertText:e"}
+	if !equalNames(got, want) {
+		t.Errorf("actions = %v, want %v", got, want)
+	}
+}
+
+func TestComposeBrokenSequence(t *testing.T) {
+	h := newComposeHandler(t)
+
+	// "^x" is not a sequence; the second "^" starts a new one
+	typeRunes(h, "^x^a")
+
+	got := collectActions(h, 20*time.Millisecond)
+	want := []string{"editor.insertText:^", "editor.insertText:x", "editor.insertText:â"}
+	if !equalNames(got, want) {
+		t.Errorf("actions = %v, want %v", got, want)
+	}
+}
+
+func TestComposeOnlyInInsertModes(t *testing.T) {
+	h := newComposeHandler(t)
+	if err := h.SwitchMode(mode.ModeNormal); err != nil {
+		t.Fatalf("SwitchMode() error = %v", err)
+	}
+
+	// In normal mode the keys are commands
+	typeRunes(h, "^e")
+	got := collectActions(h, 20*time.Millisecond)
+	if len(got) != 2 {
+		t.Fatalf("actions = %v, want two motions", got)
+	}
+	for _, name := range got {
+		if strings.HasPrefix(name, "editor.insertText") {
+			t.Errorf("actions = %v, want no insert", got)
+		}
+	}
+}
+
+func TestComposeTableRemove(t *testing.T) {
+	table := NewComposeTable()
+	if err := table.Add("x", 'y'); err != ErrInvalidCompose {
+		t.Errorf("Add single key error = %v, want ErrInvalidCompose", err)
+	}
+	_ = table.Add("oe", 'œ')
+	_ = table.Add("oeu", 'ø')
+
+	if r, complete, prefix := table.Lookup("oe"); r != 'œ' || !complete || !prefix {
+		t.Errorf("Lookup(oe) = %q, %v, %v, want œ, true, true", r, complete, prefix)
+	}
+	table.Remove("oeu")
+	if _, _, prefix := table.Lookup("oe"); prefix {
+		t.Error("oe still a prefix after removing oeu")
+	}
+	if _, _, prefix := table.Lookup("o"); !prefix {
+		t.Error("o should still be a prefix of oe")
+	}
+}
@@ -24,6 +24,48 @@
 // or "d i w" (delete inner word). Sequences are accumulated until they match
 // a binding or timeout.
 //
+// # Chords
+//
+// Chords are keys pressed in rapid succession, such as "jk" to leave insert
+// mode. They are detected by timing rather than by sequence matching: the
+// first key is held back, and if the rest of the chord arrives within the
+// chord's window the action fires without inserting the keys. Otherwise the
+// held keys are processed normally:
+//
+//	h.AddChord(input.ChordBinding{
+//	    Keys:   []key.Event{key.NewRuneEvent('j', 0), key.NewRuneEvent('k', 0)},
+//	    Action: "mode.normal",
+//	})
+//
+// # Compose Sequences
+//
+// In insert and replace modes a ComposeTable turns sequences of typed
+// characters into a single character, emulating dead keys: with
+// DefaultComposeTable, "^" followed by "e" inserts "ê". Keys that may begin
+// a sequence are held back until it completes, breaks or times out; keys of
+// an incomplete sequence are then typed literally. Composed characters go
+// through chords and key bindings like any typed key:
+//
+//	table := input.DefaultComposeTable()
+//	_ = table.Add("ae", 'æ')
+//	h.SetComposeTable(table)
+//
+// # Pending Commands
+//
+// A count, register or operator set with SetCount, SetRegister or
+// SetOperator stays pending until the next action is dispatched. While a
+// command is incomplete, PendingState reports it along with the keys typed
+// so far, for a showcmd display:
+//
+//	if state := handler.PendingState(); !state.IsEmpty() {
+//	    showcmd = state.Display // e.g. `"a3d`
+//	}
+//
+// Escape or the sequence timeout clears it. Cancelling never dispatches an
+// action: Escape after "2d" leaves the buffer untouched and discards the
+// count along with the operator. A pending operator waits
+// Config.OperatorTimeout for its motion before it is abandoned.
+//
 // # Modal Editing
 //
 // By default, Keystorm uses Vim-style modal editing:
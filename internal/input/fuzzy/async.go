@@ -132,12 +132,9 @@ func (m *AsyncMatcher) MatchParallel(ctx context.Context, query string, items []
 		allResults = append(allResults, chunk...)
 	}
 
-	// Sort by score descending, then by text
+	// Sort pinned first, then by score, weight and text
 	sort.Slice(allResults, func(i, j int) bool {
-		if allResults[i].Score != allResults[j].Score {
-			return allResults[i].Score > allResults[j].Score
-		}
-		return allResults[i].Item.Text < allResults[j].Item.Text
+		return resultLess(allResults[i], allResults[j])
 	})
 
 	// Apply limit
@@ -162,19 +159,16 @@ func (m *AsyncMatcher) matchChunkTopK(ctx context.Context, queryRunes []rune, ch
 
 		score, matches := m.matcher.matchItem(queryRunes, item.Text)
 		if score > m.matcher.options.MinScore {
+			r := Result{
+				Item:    item,
+				Score:   m.matcher.weightedScore(item, score),
+				Matches: matches,
+			}
 			if h.Len() < k {
-				heap.Push(h, Result{
-					Item:    item,
-					Score:   score,
-					Matches: matches,
-				})
-			} else if score > (*h)[0].Score {
-				// Replace minimum if new score is better
-				(*h)[0] = Result{
-					Item:    item,
-					Score:   score,
-					Matches: matches,
-				}
+				heap.Push(h, r)
+			} else if resultLess(r, (*h)[0]) {
+				// Replace the worst result if the new one ranks higher
+				(*h)[0] = r
 				heap.Fix(h, 0)
 			}
 		}
@@ -198,7 +192,7 @@ func (m *AsyncMatcher) matchChunkAll(ctx context.Context, queryRunes []rune, chu
 		if score > m.matcher.options.MinScore {
 			results = append(results, Result{
 				Item:    item,
-				Score:   score,
+				Score:   m.matcher.weightedScore(item, score),
 				Matches: matches,
 			})
 		}
@@ -216,7 +210,7 @@ func (m *AsyncMatcher) matchParallel(ctx context.Context, query string, items []
 	query = strings.TrimSpace(query)
 
 	if query == "" {
-		for i, item := range items {
+		for i, item := range rankedItems(items) {
 			if limit > 0 && i >= limit {
 				break
 			}
@@ -236,10 +230,7 @@ func (m *AsyncMatcher) matchParallel(ctx context.Context, query string, items []
 
 	// Sort and send results
 	sort.Slice(collected, func(i, j int) bool {
-		if collected[i].Score != collected[j].Score {
-			return collected[i].Score > collected[j].Score
-		}
-		return collected[i].Item.Text < collected[j].Item.Text
+		return resultLess(collected[i], collected[j])
 	})
 
 	sent := 0
@@ -305,11 +296,12 @@ func (m *AsyncMatcher) collectResultsTopK(ctx context.Context, queryRunes []rune
 	return collected
 }
 
-// resultHeap is a min-heap of Results by score (for top-k selection).
+// resultHeap is a min-heap of Results by rank (for top-k selection).
+// The root is the lowest ranked result.
 type resultHeap []Result
 
 func (h resultHeap) Len() int           { return len(h) }
-func (h resultHeap) Less(i, j int) bool { return h[i].Score < h[j].Score } // Min-heap by score
+func (h resultHeap) Less(i, j int) bool { return resultLess(h[j], h[i]) } // Min-heap by rank
 func (h resultHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
 
 func (h *resultHeap) Push(x any) {
@@ -35,6 +35,16 @@
 //	    fmt.Printf("%s (score: %d)\n", r.Item.Text, r.Score)
 //	}
 //
+// Items can carry a Weight (for example, how often a file was opened) and
+// a Pinned flag. Pinned matches always sort first; weights order items with
+// equal scores, and Options.WeightInfluence blends them into the score.
+// Neither causes non-matching items to appear.
+//
+// File pickers should use MatchPaths, which boosts matches in the basename
+// and treats path separators as strong boundaries, so "main" ranks
+// "src/main.go" above "maintenance/readme.md". PathOptions tunes the
+// bonuses.
+//
 // For large item sets, use async matching:
 //
 //	results, cancel := matcher.MatchAsync(query, items, 10)
@@ -43,6 +53,15 @@
 //	    // Process results as they arrive
 //	}
 //
+// MatchStream instead sends the best k results found so far after each
+// batch of items, so a picker over 100k+ items can show an approximate
+// ranking while matching continues. Rankings only improve from one update
+// to the next, and the last update is marked Final:
+//
+//	for update := range asyncMatcher.MatchStream(ctx, query, items, 50) {
+//	    picker.Show(update.Results, update.Final)
+//	}
+//
 // # Thread Safety
 //
 // The Matcher is safe for concurrent use. The cache is internally synchronized.
@@ -189,6 +189,99 @@ func TestMatcherDeterministicOrder(t *testing.T) {
 	}
 }
 
+func TestMatcherWeightTieBreak(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+
+	// Same length and shape, so equal fuzzy scores
+	items := []Item{
+		{Text: "alpha.go", Weight: 1},
+		{Text: "bravo.go", Weight: 5},
+		{Text: "delta.go"},
+	}
+
+	results := matcher.Match("go", items, 10)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Score != results[1].Score {
+		t.Fatalf("expected equal scores, got %d and %d", results[0].Score, results[1].Score)
+	}
+
+	got := []string{results[0].Item.Text, results[1].Item.Text, results[2].Item.Text}
+	want := []string{"bravo.go", "alpha.go", "delta.go"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestMatcherWeightInfluence(t *testing.T) {
+	opts := DefaultOptions()
+	opts.WeightInfluence = 10
+	matcher := NewMatcher(opts)
+
+	items := []Item{
+		{Text: "main.go"},
+		{Text: "cmd/server/main_test.go", Weight: 20},
+		{Text: "unrelated.txt", Weight: 1000},
+	}
+
+	results := matcher.Match("main", items, 10)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results (weight must not add non-matches), got %d", len(results))
+	}
+	if results[0].Item.Text != "cmd/server/main_test.go" {
+		t.Errorf("expected heavily weighted item first, got %s", results[0].Item.Text)
+	}
+}
+
+func TestMatcherPinned(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+
+	items := []Item{
+		{Text: "main.go"},
+		{Text: "internal/app/domain_service.go", Pinned: true},
+		{Text: "pinned_nomatch.txt", Pinned: true},
+	}
+
+	results := matcher.Match("main", items, 10)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Item.Text != "internal/app/domain_service.go" {
+		t.Errorf("expected pinned match first, got %s", results[0].Item.Text)
+	}
+	if results[0].Score >= results[1].Score {
+		t.Errorf("test expects the pinned match to have the lower score, got %d >= %d", results[0].Score, results[1].Score)
+	}
+
+	// Empty query lists pinned items first
+	results = matcher.Match("", items, 10)
+	if results[0].Item.Text != "internal/app/domain_service.go" || results[1].Item.Text != "pinned_nomatch.txt" {
+		t.Errorf("empty query order = %s, %s; want pinned items first", results[0].Item.Text, results[1].Item.Text)
+	}
+}
+
+func TestAsyncMatcherPinned(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+	asyncMatcher := NewAsyncMatcher(matcher, 2)
+
+	items := make([]Item, 1000)
+	for i := range items {
+		items[i] = Item{Text: fmt.Sprintf("file%d.go", i)}
+	}
+	items[999] = Item{Text: "some/deep/path/f_i_l_e_999.go", Pinned: true}
+
+	results := asyncMatcher.MatchParallel(context.Background(), "file", items, 5)
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+	if !results[0].Item.Pinned {
+		t.Errorf("expected pinned match first, got %s", results[0].Item.Text)
+	}
+}
+
 func TestScorerConsecutiveBonus(t *testing.T) {
 	scorer := DefaultScorer{}
 
@@ -435,6 +528,90 @@ loop:
 	t.Logf("received %d results before cancel/timeout", count)
 }
 
+func TestAsyncMatcherMatchStream(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+	asyncMatcher := NewAsyncMatcher(matcher, 2)
+
+	// The best matches come last, so rankings improve as matching goes on
+	items := make([]Item, 20000)
+	for i := range items {
+		items[i] = Item{Text: fmt.Sprintf("file%d.go", len(items)-i)}
+	}
+
+	var last TopKUpdate
+	updates := 0
+	for update := range asyncMatcher.MatchStream(context.Background(), "file1", items, 10) {
+		if last.Final {
+			t.Fatal("received an update after the final one")
+		}
+		if update.Processed < last.Processed || update.Total != len(items) {
+			t.Errorf("progress %d/%d after %d", update.Processed, update.Total, last.Processed)
+		}
+		if len(update.Results) < len(last.Results) {
+			t.Errorf("results shrank from %d to %d", len(last.Results), len(update.Results))
+		}
+		for i := range last.Results {
+			if resultLess(last.Results[i], update.Results[i]) {
+				t.Errorf("result %d got worse: %q -> %q", i, last.Results[i].Item.Text, update.Results[i].Item.Text)
+			}
+		}
+		last = update
+		updates++
+	}
+
+	if !last.Final || last.Processed != len(items) {
+		t.Fatalf("last update = final %v, processed %d, want final after %d", last.Final, last.Processed, len(items))
+	}
+	want := asyncMatcher.MatchParallel(context.Background(), "file1", items, 10)
+	if len(last.Results) != len(want) {
+		t.Fatalf("final results = %d, want %d", len(last.Results), len(want))
+	}
+	for i := range want {
+		if last.Results[i].Item.Text != want[i].Item.Text {
+			t.Errorf("final result %d = %q, want %q", i, last.Results[i].Item.Text, want[i].Item.Text)
+		}
+	}
+	t.Logf("received %d updates", updates)
+}
+
+func TestAsyncMatcherMatchStreamCancel(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+	asyncMatcher := NewAsyncMatcher(matcher, 2)
+
+	items := make([]Item, 200000)
+	for i := range items {
+		items[i] = Item{Text: fmt.Sprintf("file%d.go", i)}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := asyncMatcher.MatchStream(ctx, "file", items, 100)
+
+	// Cancel after the first update
+	first, ok := <-updates
+	if !ok {
+		t.Fatal("channel closed before the first update")
+	}
+	cancel()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				if first.Final {
+					t.Error("matching finished before it could be cancelled")
+				}
+				return
+			}
+			if update.Final {
+				t.Error("received a final update after cancellation")
+			}
+		case <-timeout:
+			t.Fatal("channel not closed after cancellation")
+		}
+	}
+}
+
 func TestStreamingMatcher(t *testing.T) {
 	matcher := NewMatcher(DefaultOptions())
 	streaming := NewStreamingMatcher(matcher)
@@ -637,3 +814,81 @@ func BenchmarkCache(b *testing.B) {
 		}
 	})
 }
+
+func TestMatchPathsBasename(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+	items := []Item{
+		{Text: "maintenance/readme.md"},
+		{Text: "src/main.go"},
+	}
+
+	results := matcher.MatchPaths("main", items, 10)
+	if len(results) != 2 {
+		t.Fatalf("got %d matches, want 2", len(results))
+	}
+	if results[0].Item.Text != "src/main.go" {
+		t.Errorf("first = %q, want %q", results[0].Item.Text, "src/main.go")
+	}
+	if want := []int{4, 5, 6, 7}; fmt.Sprint(results[0].Matches) != fmt.Sprint(want) {
+		t.Errorf("matches = %v, want %v", results[0].Matches, want)
+	}
+}
+
+func TestMatchPathsBasenameBeatsDeepPath(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+	items := []Item{
+		{Text: "internal/storage/cache/lru.go"},
+		{Text: "pkg/db/sql_tor.go"},
+	}
+
+	// The directory match "stor" is fully consecutive, but the fuzzy
+	// basename match should still rank first.
+	results := matcher.MatchPaths("stor", items, 10)
+	if len(results) != 2 {
+		t.Fatalf("got %d matches, want 2", len(results))
+	}
+	if results[0].Item.Text != "pkg/db/sql_tor.go" {
+		t.Errorf("first = %q, want %q", results[0].Item.Text, "pkg/db/sql_tor.go")
+	}
+
+	// Plain Match prefers the consecutive directory match.
+	plain := matcher.Match("stor", items, 10)
+	if plain[0].Item.Text != "internal/storage/cache/lru.go" {
+		t.Errorf("Match first = %q, want %q", plain[0].Item.Text, "internal/storage/cache/lru.go")
+	}
+}
+
+func TestMatchPathsSubsequence(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+	items := []Item{
+		{Text: "internal/input/fuzzy/matcher.go"},
+		{Text: "internal/engine/buffer.go"},
+		{Text: "README.md"},
+	}
+
+	results := matcher.MatchPaths("infzmat", items, 10)
+	if len(results) != 1 {
+		t.Fatalf("got %d matches, want 1", len(results))
+	}
+	if results[0].Item.Text != "internal/input/fuzzy/matcher.go" {
+		t.Errorf("first = %q, want %q", results[0].Item.Text, "internal/input/fuzzy/matcher.go")
+	}
+
+	if got := matcher.MatchPaths("xyz", items, 10); len(got) != 0 {
+		t.Errorf("got %d matches for non-matching query, want 0", len(got))
+	}
+}
+
+func TestMatchPathsSeparateCache(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+	items := []Item{
+		{Text: "internal/storage/cache/lru.go"},
+		{Text: "pkg/db/sql_tor.go"},
+	}
+
+	plain := matcher.Match("stor", items, 10)
+	paths := matcher.MatchPaths("stor", items, 10)
+	if plain[0].Item.Text == paths[0].Item.Text {
+		t.Errorf("MatchPaths reused Match results: both first %q", paths[0].Item.Text)
+	}
+}
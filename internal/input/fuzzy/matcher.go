@@ -1,6 +1,7 @@
 package fuzzy
 
 import (
+	"math"
 	"sort"
 	"strings"
 	"sync"
@@ -13,6 +14,14 @@ type Item struct {
 
 	// Data is arbitrary data associated with this item.
 	Data any
+
+	// Weight ranks the item independently of the query, for example by
+	// how often it was opened. Higher weights rank higher; see
+	// Options.WeightInfluence.
+	Weight float64
+
+	// Pinned items sort before all unpinned items when they match.
+	Pinned bool
 }
 
 // Result represents a match result with scoring information.
@@ -29,10 +38,11 @@ type Result struct {
 
 // Matcher performs fuzzy string matching.
 type Matcher struct {
-	mu      sync.RWMutex
-	cache   *Cache
-	scorer  Scorer
-	options Options
+	mu       sync.RWMutex
+	cache    *Cache
+	scorer   Scorer
+	options  Options
+	pathOpts PathOptions
 }
 
 // Options configures the matcher behavior.
@@ -48,6 +58,12 @@ type Options struct {
 	// CaseSensitive enables case-sensitive matching.
 	// Default is false (case-insensitive).
 	CaseSensitive bool
+
+	// WeightInfluence is the number of score points added per unit of
+	// item weight. Zero uses weights only to order items with equal scores.
+	// MinScore applies to the fuzzy score before weighting, so weights
+	// never make a non-matching item appear.
+	WeightInfluence float64
 }
 
 // DefaultOptions returns sensible default options.
@@ -67,9 +83,10 @@ func NewMatcher(opts Options) *Matcher {
 	}
 
 	return &Matcher{
-		cache:   cache,
-		scorer:  DefaultScorer{},
-		options: opts,
+		cache:    cache,
+		scorer:   DefaultScorer{},
+		options:  opts,
+		pathOpts: DefaultPathOptions(),
 	}
 }
 
@@ -82,6 +99,12 @@ func (m *Matcher) SetScorer(scorer Scorer) {
 
 // Match finds items matching the query and returns results sorted by score.
 func (m *Matcher) Match(query string, items []Item, limit int) []Result {
+	return m.match(query, items, limit, "", m.matchItem)
+}
+
+// match implements Match and MatchPaths. Results are cached under
+// cachePrefix+query so that different match modes do not share entries.
+func (m *Matcher) match(query string, items []Item, limit int, cachePrefix string, matchFn func(queryRunes []rune, text string) (int, []int)) []Result {
 	// Normalize query
 	if !m.options.CaseSensitive {
 		query = strings.ToLower(query)
@@ -95,7 +118,7 @@ func (m *Matcher) Match(query string, items []Item, limit int) []Result {
 
 	// Check cache
 	if m.cache != nil {
-		if cached := m.cache.Get(query); cached != nil {
+		if cached := m.cache.Get(cachePrefix + query); cached != nil {
 			return m.applyLimit(cached, limit)
 		}
 	}
@@ -106,27 +129,24 @@ func (m *Matcher) Match(query string, items []Item, limit int) []Result {
 	// Match all items
 	results := make([]Result, 0, len(items))
 	for _, item := range items {
-		score, matches := m.matchItem(queryRunes, item.Text)
+		score, matches := matchFn(queryRunes, item.Text)
 		if score > m.options.MinScore {
 			results = append(results, Result{
 				Item:    item,
-				Score:   score,
+				Score:   m.weightedScore(item, score),
 				Matches: matches,
 			})
 		}
 	}
 
-	// Sort by score descending, then by text for deterministic ordering
+	// Sort pinned first, then by score, weight and text for deterministic ordering
 	sort.Slice(results, func(i, j int) bool {
-		if results[i].Score != results[j].Score {
-			return results[i].Score > results[j].Score
-		}
-		return results[i].Item.Text < results[j].Item.Text
+		return resultLess(results[i], results[j])
 	})
 
 	// Cache results
 	if m.cache != nil {
-		m.cache.Set(query, results)
+		m.cache.Set(cachePrefix+query, results)
 	}
 
 	return m.applyLimit(results, limit)
@@ -194,8 +214,59 @@ func (m *Matcher) matchItem(queryRunes []rune, text string) (int, []int) {
 	return score, matches
 }
 
-// emptyQueryResults returns results for an empty query.
+// weightedScore blends an item's weight into its fuzzy score.
+func (m *Matcher) weightedScore(item Item, score int) int {
+	if item.Weight == 0 || m.options.WeightInfluence == 0 {
+		return score
+	}
+	return score + int(math.Round(item.Weight*m.options.WeightInfluence))
+}
+
+// resultLess reports whether a ranks before b: pinned results first, then
+// higher scores, then higher weights, then text order.
+func resultLess(a, b Result) bool {
+	if a.Item.Pinned != b.Item.Pinned {
+		return a.Item.Pinned
+	}
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Item.Weight != b.Item.Weight {
+		return a.Item.Weight > b.Item.Weight
+	}
+	return a.Item.Text < b.Item.Text
+}
+
+// rankedItems returns items ordered pinned first, then by descending
+// weight, keeping the original order otherwise. It returns items itself
+// when no item is pinned or weighted.
+func rankedItems(items []Item) []Item {
+	ranked := false
+	for _, item := range items {
+		if item.Pinned || item.Weight != 0 {
+			ranked = true
+			break
+		}
+	}
+	if !ranked {
+		return items
+	}
+
+	sorted := make([]Item, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Pinned != sorted[j].Pinned {
+			return sorted[i].Pinned
+		}
+		return sorted[i].Weight > sorted[j].Weight
+	})
+	return sorted
+}
+
+// emptyQueryResults returns results for an empty query, pinned and
+// heavier items first.
 func (m *Matcher) emptyQueryResults(items []Item, limit int) []Result {
+	items = rankedItems(items)
 	count := len(items)
 	if limit > 0 && limit < count {
 		count = limit
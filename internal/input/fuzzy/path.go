@@ -0,0 +1,174 @@
+package fuzzy
+
+import "strings"
+
+// pathCachePrefix separates MatchPaths results from Match results in the
+// cache. It cannot occur in a trimmed query.
+const pathCachePrefix = "\x00path\x00"
+
+// PathOptions configures path-aware scoring used by MatchPaths.
+type PathOptions struct {
+	// BasenameBonus is added for each query character matched in the
+	// basename (the part after the last path separator).
+	BasenameBonus int
+
+	// FullBasenameBonus is added when the whole query matches within the
+	// basename.
+	FullBasenameBonus int
+
+	// BasenamePrefixBonus is added when the first match starts the
+	// basename.
+	BasenamePrefixBonus int
+
+	// SeparatorBonus is added for each match directly after a path
+	// separator, making separators stronger boundaries than punctuation.
+	SeparatorBonus int
+}
+
+// DefaultPathOptions returns the default path scoring options.
+func DefaultPathOptions() PathOptions {
+	return PathOptions{
+		BasenameBonus:       20,
+		FullBasenameBonus:   100,
+		BasenamePrefixBonus: 40,
+		SeparatorBonus:      30,
+	}
+}
+
+// SetPathOptions sets the options used by MatchPaths and clears the cache.
+func (m *Matcher) SetPathOptions(opts PathOptions) {
+	m.mu.Lock()
+	m.pathOpts = opts
+	m.mu.Unlock()
+	m.ClearCache()
+}
+
+// PathOptions returns the options used by MatchPaths.
+func (m *Matcher) PathOptions() PathOptions {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pathOpts
+}
+
+// MatchPaths is like Match for items whose text is a file path. Matches in
+// the basename rank far above matches in the directory portion, and path
+// separators ('/' or '\') count as strong word boundaries, so "main" ranks
+// "src/main.go" above "maintenance/readme.md". The query is still matched
+// as a fuzzy subsequence of the whole path.
+func (m *Matcher) MatchPaths(query string, paths []Item, limit int) []Result {
+	return m.match(query, paths, limit, pathCachePrefix, m.matchPath)
+}
+
+// matchPath scores a path against the query. It considers the query
+// matched within the basename, and matched against the whole path from the
+// left and from the right, and keeps the best scoring placement.
+func (m *Matcher) matchPath(queryRunes []rune, text string) (int, []int) {
+	if text == "" || len(queryRunes) == 0 {
+		return 0, nil
+	}
+
+	var textRunes []rune
+	if m.options.CaseSensitive {
+		textRunes = []rune(text)
+	} else {
+		textRunes = []rune(strings.ToLower(text))
+	}
+	originalRunes := []rune(text)
+	base := basenameStart(originalRunes)
+
+	m.mu.RLock()
+	opts := m.pathOpts
+	m.mu.RUnlock()
+	scorer := NewFilePathScorer().base
+
+	bestScore, bestMatches := 0, []int(nil)
+	candidates := [][]int{
+		matchForward(queryRunes, textRunes, base),
+		matchForward(queryRunes, textRunes, 0),
+		matchBackward(queryRunes, textRunes),
+	}
+	for _, matches := range candidates {
+		if matches == nil {
+			continue
+		}
+		score := scorer.Score(queryRunes, originalRunes, textRunes, matches) +
+			pathBonus(opts, originalRunes, base, matches)
+		if score > bestScore {
+			bestScore, bestMatches = score, matches
+		}
+	}
+	return bestScore, bestMatches
+}
+
+// pathBonus returns the path-specific score for a placement.
+func pathBonus(opts PathOptions, runes []rune, base int, matches []int) int {
+	bonus := 0
+	inBasename := 0
+	for _, idx := range matches {
+		if idx >= base {
+			inBasename++
+		}
+		if idx > 0 && isPathSeparator(runes[idx-1]) {
+			bonus += opts.SeparatorBonus
+		}
+	}
+
+	bonus += inBasename * opts.BasenameBonus
+	if inBasename == len(matches) {
+		bonus += opts.FullBasenameBonus
+	}
+	if matches[0] == base {
+		bonus += opts.BasenamePrefixBonus
+	}
+	return bonus
+}
+
+// matchForward greedily matches the query as a subsequence of text
+// starting at from. Returns nil if it does not match.
+func matchForward(queryRunes, textRunes []rune, from int) []int {
+	matches := make([]int, 0, len(queryRunes))
+	q := 0
+	for i := from; i < len(textRunes) && q < len(queryRunes); i++ {
+		if textRunes[i] == queryRunes[q] {
+			matches = append(matches, i)
+			q++
+		}
+	}
+	if q != len(queryRunes) {
+		return nil
+	}
+	return matches
+}
+
+// matchBackward greedily matches the query as a subsequence of text from
+// the end, placing matches as far right (toward the basename) as possible.
+// Returns nil if it does not match.
+func matchBackward(queryRunes, textRunes []rune) []int {
+	matches := make([]int, len(queryRunes))
+	q := len(queryRunes) - 1
+	for i := len(textRunes) - 1; i >= 0 && q >= 0; i-- {
+		if textRunes[i] == queryRunes[q] {
+			matches[q] = i
+			q--
+		}
+	}
+	if q >= 0 {
+		return nil
+	}
+	return matches
+}
+
+// basenameStart returns the rune index where the basename begins.
+func basenameStart(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if isPathSeparator(runes[i]) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// isPathSeparator returns true for '/' and '\'.
+func isPathSeparator(r rune) bool {
+	return r == '/' || r == '\\'
+}
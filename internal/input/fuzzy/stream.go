@@ -0,0 +1,195 @@
+package fuzzy
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// streamBatchSize is the number of items matched between TopKUpdates.
+const streamBatchSize = 2048
+
+// TopKUpdate is the best-so-far ranking sent by MatchStream.
+type TopKUpdate struct {
+	// Results are the best results among the items processed so far,
+	// sorted by rank. Each update ranks every position at least as well as
+	// the one before.
+	Results []Result
+
+	// Processed is the number of items matched so far, out of Total.
+	Processed int
+	Total     int
+
+	// Final is true for the last update, whose Results are the same as
+	// MatchParallel would return.
+	Final bool
+}
+
+// MatchStream matches items in batches and sends the current top k results
+// as matching progresses, so that a UI can show an approximate ranking of a
+// large item set early. If k is 0 or negative, all matches are kept.
+//
+// Intermediate updates are coalesced: a consumer that falls behind receives
+// the latest ranking rather than every update. The last update has Final
+// set, after which the channel is closed. When ctx is cancelled, matching
+// stops and the channel is closed without a final update once all workers
+// have returned.
+func (m *AsyncMatcher) MatchStream(ctx context.Context, query string, items []Item, k int) <-chan TopKUpdate {
+	updates := make(chan TopKUpdate, 1)
+
+	go func() {
+		defer close(updates)
+		m.matchStream(ctx, query, items, k, updates)
+	}()
+
+	return updates
+}
+
+// matchStream matches batches of items in parallel, merging each batch's
+// top k into the running ranking and sending it to updates.
+func (m *AsyncMatcher) matchStream(ctx context.Context, query string, items []Item, k int, updates chan TopKUpdate) {
+	if !m.matcher.options.CaseSensitive {
+		query = strings.ToLower(query)
+	}
+	query = strings.TrimSpace(query)
+
+	if query == "" {
+		final := TopKUpdate{
+			Results:   m.matcher.emptyQueryResults(items, k),
+			Processed: len(items),
+			Total:     len(items),
+			Final:     true,
+		}
+		select {
+		case updates <- final:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	queryRunes := []rune(query)
+	if k <= 0 {
+		k = len(items)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Workers take batches and report each batch's top k
+	type batchResult struct {
+		results []Result
+		size    int
+	}
+	batches := make(chan []Item)
+	partials := make(chan batchResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < m.numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				results := m.matchChunkTopK(ctx, queryRunes, batch, k)
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case partials <- batchResult{results: results, size: len(batch)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(batches)
+		for i := 0; i < len(items); i += streamBatchSize {
+			select {
+			case batches <- items[i:min(i+streamBatchSize, len(items))]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(partials)
+	}()
+
+	// Stop the workers before returning so that a closed channel means
+	// matching has stopped
+	defer func() {
+		cancel()
+		for range partials {
+		}
+	}()
+
+	best := &resultHeap{}
+	processed := 0
+	for partial := range partials {
+		for _, r := range partial.results {
+			if best.Len() < k {
+				heap.Push(best, r)
+			} else if resultLess(r, (*best)[0]) {
+				(*best)[0] = r
+				heap.Fix(best, 0)
+			}
+		}
+		processed += partial.size
+
+		if processed < len(items) {
+			sendLatest(updates, TopKUpdate{
+				Results:   best.sorted(),
+				Processed: processed,
+				Total:     len(items),
+			})
+		}
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	final := TopKUpdate{
+		Results:   best.sorted(),
+		Processed: processed,
+		Total:     len(items),
+		Final:     true,
+	}
+	// Replace an intermediate update the consumer has not read
+	select {
+	case <-updates:
+	default:
+	}
+	select {
+	case updates <- final:
+	case <-ctx.Done():
+	}
+}
+
+// sendLatest sends update without blocking, replacing an update the
+// consumer has not read yet. It must only be called by the channel's sole
+// sender.
+func sendLatest(updates chan TopKUpdate, update TopKUpdate) {
+	select {
+	case <-updates:
+	default:
+	}
+	select {
+	case updates <- update:
+	default:
+	}
+}
+
+// sorted returns the heap's results sorted by rank.
+func (h *resultHeap) sorted() []Result {
+	results := h.toSlice()
+	sort.Slice(results, func(i, j int) bool {
+		return resultLess(results[i], results[j])
+	})
+	return results
+}
@@ -22,6 +22,12 @@ type Config struct {
 	// Default: 1000ms
 	SequenceTimeout time.Duration
 
+	// OperatorTimeout is how long a pending operator (e.g., "d") waits for
+	// its motion or text object before it is abandoned without dispatching
+	// an action. Zero uses SequenceTimeout; a negative value waits forever.
+	// Default: 1000ms
+	OperatorTimeout time.Duration
+
 	// ShowPendingKeys shows pending keys in the status bar.
 	ShowPendingKeys bool
 
@@ -34,6 +40,13 @@ type Config struct {
 
 	// UseSystemClipboard uses the system clipboard for yank/paste.
 	UseSystemClipboard bool
+
+	// EnableFocusEvents turns terminal focus changes into
+	// editor.focusGained and editor.focusLost actions. The terminal only
+	// reports focus changes once focus reporting is enabled with
+	// key.EnableFocusReporting.
+	// Default: false
+	EnableFocusEvents bool
 }
 
 // DefaultConfig returns a configuration with sensible defaults.
@@ -42,10 +55,12 @@ func DefaultConfig() Config {
 		DefaultMode:        mode.ModeNormal,
 		EnableModes:        true,
 		SequenceTimeout:    1000 * time.Millisecond,
+		OperatorTimeout:    1000 * time.Millisecond,
 		ShowPendingKeys:    true,
 		EnableMouse:        true,
 		DoubleClickTime:    400 * time.Millisecond,
 		UseSystemClipboard: true,
+		EnableFocusEvents:  false,
 	}
 }
 
@@ -80,6 +95,14 @@ type Handler struct {
 
 	// Error from loading default keymaps (if any)
 	keymapLoadErr error
+
+	// Chord bindings and keys held back while a chord may complete
+	chords []ChordBinding
+	chord  chordState
+
+	// Compose table and keys held back while a character may be composed
+	composeTable *ComposeTable
+	compose      composeState
 }
 
 // Hook allows interception and modification of input handling.
@@ -170,6 +193,40 @@ func (h *Handler) HandleKeyEvent(event key.Event) {
 		return
 	}
 
+	// Hold back keys that may compose a character
+	var action *Action
+	if !h.handleComposeLocked(event) {
+		action = h.feedKeyLocked(event)
+	}
+
+	// Copy context again for post-hooks
+	ctxClone = h.context.Clone()
+
+	h.mu.Unlock()
+
+	// Run post-hooks outside lock
+	for _, hook := range hooks {
+		hook.PostKeyEvent(&eventCopy, action, ctxClone)
+	}
+}
+
+// feedKeyLocked processes a key after composition: keys that may form a
+// chord are held back, others are processed. Caller must hold the lock.
+func (h *Handler) feedKeyLocked(event key.Event) *Action {
+	if h.handleChordLocked(event) {
+		return nil
+	}
+	return h.processKeyLocked(event)
+}
+
+// processKeyLocked appends event to the pending sequence and tries to
+// resolve it. Caller must hold the lock.
+func (h *Handler) processKeyLocked(event key.Event) *Action {
+	// Escape cancels a pending count, register or operator
+	if h.cancelPendingLocked(event) {
+		return nil
+	}
+
 	// Add to pending sequence
 	h.context.AppendToSequence(event)
 
@@ -177,17 +234,78 @@ func (h *Handler) HandleKeyEvent(event key.Event) {
 	h.resetSequenceTimeout()
 
 	// Try to resolve the sequence
-	action := h.resolveSequence()
+	return h.resolveSequence()
+}
 
-	// Copy context again for post-hooks
-	ctxClone = h.context.Clone()
+// HandlePasteEvent processes a bracketed paste.
+// Pasted text is inserted literally: it bypasses keymaps, mode handling and
+// autoindent so that pasted code is never interpreted as commands.
+// Any pending key sequence is discarded.
+func (h *Handler) HandlePasteEvent(event key.PasteEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
-	h.mu.Unlock()
+	if h.closed {
+		return
+	}
 
-	// Run post-hooks outside lock
-	for _, hook := range hooks {
-		hook.PostKeyEvent(&eventCopy, action, ctxClone)
+	h.flushComposeLocked()
+	h.flushChordLocked()
+	h.clearSequence()
+
+	if event.Text == "" {
+		return
 	}
+
+	h.dispatchAction(Action{
+		Name:   "editor.insertText",
+		Source: SourceKeyboard,
+		Count:  1,
+		Args: ActionArgs{
+			Text:  event.Text,
+			Extra: map[string]interface{}{"paste": true, "autoindent": false},
+		},
+	})
+}
+
+// HandleFocusEvent processes a terminal focus change, dispatching
+// editor.focusGained or editor.focusLost so that the editor can autosave,
+// pause the cursor blink or refresh git status. Pending keys and operators
+// are kept. Focus events are ignored unless Config.EnableFocusEvents is set.
+func (h *Handler) HandleFocusEvent(event key.FocusEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed || !h.config.EnableFocusEvents {
+		return
+	}
+
+	action := Action{
+		Name:   "editor.focusLost",
+		Source: SourceTerminal,
+		Count:  1,
+	}
+	if event.Gained {
+		action.Name = "editor.focusGained"
+	}
+	if h.runPreActionHooks(&action) {
+		return
+	}
+	h.sendAction(action)
+}
+
+// HandleInput routes a decoded terminal input to the key, paste or focus
+// path.
+func (h *Handler) HandleInput(in key.Input) {
+	if in.IsPaste() {
+		h.HandlePasteEvent(*in.Paste)
+		return
+	}
+	if in.IsFocus() {
+		h.HandleFocusEvent(*in.Focus)
+		return
+	}
+	h.HandleKeyEvent(in.Key)
 }
 
 // resolveSequence attempts to resolve the pending key sequence to an action.
@@ -231,6 +349,7 @@ func (h *Handler) buildLookupContext() *keymap.LookupContext {
 	ctx := keymap.NewLookupContext()
 	ctx.Mode = h.context.Mode
 	ctx.FileType = h.context.FileType
+	ctx.Operator = h.context.PendingOperator
 
 	// Defensively initialize maps before copying
 	if ctx.Conditions == nil {
@@ -341,6 +460,23 @@ func (h *Handler) handleUnmatchedSequence(currentMode mode.Mode) *Action {
 // Caller must hold the lock. This method will temporarily release the lock
 // to invoke hooks safely, then re-acquire it.
 func (h *Handler) dispatchAction(action Action) {
+	if h.runPreActionHooks(&action) {
+		return // Hook consumed the action
+	}
+
+	// Clear pending state after action
+	h.context.PendingCount = 0
+	h.context.PendingRegister = 0
+	h.context.PendingOperator = ""
+
+	h.sendAction(action)
+}
+
+// runPreActionHooks runs the PreAction hooks and returns true if one
+// consumed the action.
+// Caller must hold the lock. This method will temporarily release the lock
+// to invoke hooks safely, then re-acquire it.
+func (h *Handler) runPreActionHooks(action *Action) bool {
 	// Copy hooks and context for safe invocation outside lock
 	hooks := make([]Hook, len(h.hooks))
 	copy(hooks, h.hooks)
@@ -351,23 +487,18 @@ func (h *Handler) dispatchAction(action Action) {
 	// Run pre-action hooks outside lock to avoid deadlock
 	consumed := false
 	for _, hook := range hooks {
-		if hook.PreAction(&action, ctxClone) {
+		if hook.PreAction(action, ctxClone) {
 			consumed = true
 			break
 		}
 	}
 
 	h.mu.Lock()
+	return consumed
+}
 
-	if consumed {
-		return // Hook consumed the action
-	}
-
-	// Clear pending state after action
-	h.context.PendingCount = 0
-	h.context.PendingRegister = 0
-	h.context.PendingOperator = ""
-
+// sendAction sends an action to the output channel.
+func (h *Handler) sendAction(action Action) {
 	// Non-blocking send with overflow protection.
 	// Note: If the channel is full, the oldest action is dropped to make room.
 	// This prevents blocking the input handler but may lose actions if the
@@ -393,12 +524,17 @@ func (h *Handler) clearSequence() {
 	h.stopSequenceTimeout()
 }
 
-// resetSequenceTimeout resets the sequence timeout timer.
+// resetSequenceTimeout resets the sequence timeout timer. While an
+// operator is pending the operator timeout is used instead.
 func (h *Handler) resetSequenceTimeout() {
 	h.stopSequenceTimeout()
 
-	if h.config.SequenceTimeout > 0 {
-		h.seqTimer = time.AfterFunc(h.config.SequenceTimeout, func() {
+	timeout := h.config.SequenceTimeout
+	if h.context.PendingOperator != "" && h.config.OperatorTimeout != 0 {
+		timeout = h.config.OperatorTimeout
+	}
+	if timeout > 0 {
+		h.seqTimer = time.AfterFunc(timeout, func() {
 			h.handleSequenceTimeout()
 		})
 	}
@@ -417,15 +553,19 @@ func (h *Handler) handleSequenceTimeout() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	if h.closed || h.context.PendingSequence == nil {
+	if h.closed {
 		return
 	}
 
-	// Try to resolve with what we have, or clear
-	h.resolveSequence()
+	// Try to resolve with what we have, or clear. A pending operator is
+	// abandoned: resolving a partial motion such as "i" would dispatch an
+	// unrelated command.
+	if h.context.PendingSequence != nil && h.context.PendingOperator == "" {
+		h.resolveSequence()
+	}
 
-	// Clear any remaining sequence
-	h.clearSequence()
+	// Clear any remaining sequence and pending command
+	h.clearPendingLocked()
 }
 
 // Actions returns the channel for receiving dispatched actions.
@@ -510,10 +650,31 @@ func (h *Handler) SetOperator(operator string) {
 }
 
 // UpdateContext updates the context from an editor state provider.
+// When the buffer's file type changes, the keymap registry's active
+// filetype follows it.
 func (h *Handler) UpdateContext(editor EditorStateProvider) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.context.UpdateFromEditor(editor)
+	h.syncFiletypeLocked()
+}
+
+// SetFileType sets the file type of the active buffer and activates its
+// keymap filetype layer.
+func (h *Handler) SetFileType(fileType string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.context.FileType = fileType
+	h.context.SetVariable("resourceLangId", fileType)
+	h.syncFiletypeLocked()
+}
+
+// syncFiletypeLocked updates the keymap registry's active filetype from
+// the context. Caller must hold h.mu.
+func (h *Handler) syncFiletypeLocked() {
+	if h.keymapRegistry.ActiveFiletype() != h.context.FileType {
+		h.keymapRegistry.SetActiveFiletype(h.context.FileType)
+	}
 }
 
 // AddHook adds an input hook.
@@ -547,6 +708,8 @@ func (h *Handler) Close() {
 
 	h.closed = true
 	h.stopSequenceTimeout()
+	h.resetChordLocked()
+	h.resetComposeLocked()
 	close(h.actionChan)
 }
 
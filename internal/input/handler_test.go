@@ -260,6 +260,34 @@ func TestHandlerWithBinding(t *testing.T) {
 	}
 }
 
+func TestHandlerVisualBlockInsertKeys(t *testing.T) {
+	h := NewHandler(DefaultConfig())
+	defer h.Close()
+
+	if err := h.SwitchMode(mode.ModeVisualBlock); err != nil {
+		t.Fatalf("SwitchMode() error = %v", err)
+	}
+
+	tests := []struct {
+		r    rune
+		want string
+	}{
+		{'I', "mode.insertAtSelectionStart"},
+		{'A', "mode.insertAtSelectionEnd"},
+	}
+	for _, tt := range tests {
+		h.HandleKeyEvent(key.NewRuneEvent(tt.r, key.ModNone))
+		select {
+		case action := <-h.Actions():
+			if action.Name != tt.want {
+				t.Errorf("%c in visual block = %q, want %q", tt.r, action.Name, tt.want)
+			}
+		case <-time.After(100 * time.Millisecond):
+			t.Errorf("%c in visual block dispatched no action", tt.r)
+		}
+	}
+}
+
 func TestHandlerWithSequence(t *testing.T) {
 	h := NewHandler(DefaultConfig())
 	defer h.Close()
@@ -603,3 +631,232 @@ func TestHandlerUpdateContext(t *testing.T) {
 		t.Errorf("expected column 5, got %d", ctx.ColumnNumber)
 	}
 }
+
+func TestHandlerSyncsActiveFiletype(t *testing.T) {
+	h := NewHandler(DefaultConfig())
+	defer h.Close()
+
+	h.UpdateContext(&mockEditorState{mode: mode.ModeNormal, fileType: "go"})
+	if got := h.KeymapRegistry().ActiveFiletype(); got != "go" {
+		t.Errorf("expected active filetype 'go', got %q", got)
+	}
+
+	h.SetFileType("python")
+	if got := h.KeymapRegistry().ActiveFiletype(); got != "python" {
+		t.Errorf("expected active filetype 'python', got %q", got)
+	}
+	if got := h.Context().GetVariable("resourceLangId"); got != "python" {
+		t.Errorf("expected resourceLangId 'python', got %q", got)
+	}
+}
+
+func TestHandlerPasteBypassesKeymaps(t *testing.T) {
+	h := NewHandler(DefaultConfig())
+	defer h.Close()
+
+	km := keymap.NewKeymap("test").
+		ForMode(mode.ModeNormal).
+		WithPriority(100).
+		Add("Q", "test.action")
+	if err := h.KeymapRegistry().Register(km); err != nil {
+		t.Fatalf("failed to register keymap: %v", err)
+	}
+
+	// Leave a pending sequence that the paste must discard
+	h.HandleKeyEvent(key.NewRuneEvent('g', key.ModNone))
+
+	text := "Q:wq\n\tdd"
+	p := key.NewStreamParser()
+	for _, in := range p.Feed([]byte(key.PasteStart + text + key.PasteEnd)) {
+		h.HandleInput(in)
+	}
+
+	select {
+	case action := <-h.Actions():
+		if action.Name != "editor.insertText" {
+			t.Fatalf("expected action 'editor.insertText', got %q", action.Name)
+		}
+		if action.Args.Text != text {
+			t.Errorf("expected text %q, got %q", text, action.Args.Text)
+		}
+		if !action.Args.GetBool("paste") {
+			t.Error("expected paste flag to be set")
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected paste action to be dispatched")
+	}
+
+	select {
+	case action := <-h.Actions():
+		t.Errorf("unexpected extra action %q", action.Name)
+	default:
+	}
+
+	if h.PendingKeys() != "" {
+		t.Errorf("expected pending keys to be cleared, got %q", h.PendingKeys())
+	}
+}
+
+func TestHandlerFocusEvents(t *testing.T) {
+	config := DefaultConfig()
+	config.EnableFocusEvents = true
+	h := NewHandler(config)
+	defer h.Close()
+
+	// Focus changes keep a pending operator
+	h.SetOperator("operator.delete")
+
+	p := key.NewStreamParser()
+	p.SetFocusReporting(true)
+	for _, in := range p.Feed([]byte(key.FocusOut + key.FocusIn)) {
+		h.HandleInput(in)
+	}
+
+	for _, want := range []string{"editor.focusLost", "editor.focusGained"} {
+		select {
+		case action := <-h.Actions():
+			if action.Name != want {
+				t.Errorf("expected action %q, got %q", want, action.Name)
+			}
+			if action.Source != SourceTerminal {
+				t.Errorf("expected source terminal, got %v", action.Source)
+			}
+		case <-time.After(100 * time.Millisecond):
+			t.Fatalf("expected action %q to be dispatched", want)
+		}
+	}
+
+	if got := h.PendingState().Operator; got != "operator.delete" {
+		t.Errorf("Operator = %q, want %q", got, "operator.delete")
+	}
+}
+
+func TestHandlerFocusEventsDisabled(t *testing.T) {
+	// Focus events are off by default
+	h := NewHandler(DefaultConfig())
+	defer h.Close()
+
+	h.HandleFocusEvent(key.FocusEvent{Gained: true})
+
+	select {
+	case action := <-h.Actions():
+		t.Errorf("unexpected action %q with focus events disabled", action.Name)
+	default:
+	}
+}
+
+func TestHandlerPendingState(t *testing.T) {
+	h := NewHandler(DefaultConfig())
+	defer h.Close()
+
+	if state := h.PendingState(); !state.IsEmpty() {
+		t.Fatalf("PendingState = %+v, want empty", state)
+	}
+
+	h.SetRegister('a')
+	h.SetCount(3)
+	h.SetOperator("operator.delete")
+
+	state := h.PendingState()
+	if state.Register != 'a' {
+		t.Errorf("Register = %q, want 'a'", state.Register)
+	}
+	if state.Count != 3 {
+		t.Errorf("Count = %d, want 3", state.Count)
+	}
+	if state.Operator != "operator.delete" {
+		t.Errorf("Operator = %q, want %q", state.Operator, "operator.delete")
+	}
+	if state.Display != `"a3d` {
+		t.Errorf("Display = %q, want %q", state.Display, `"a3d`)
+	}
+
+	h.HandleKeyEvent(key.NewRuneEvent('i', key.ModNone))
+	state = h.PendingState()
+	if state.Keys != "i" || state.Display != `"a3di` {
+		t.Errorf("PendingState after 'i' = %+v, want keys %q and display %q", state, "i", `"a3di`)
+	}
+
+	h.HandleKeyEvent(key.NewSpecialEvent(key.KeyEscape, key.ModNone))
+	if state := h.PendingState(); !state.IsEmpty() || state.Count != 0 ||
+		state.Register != 0 || state.Operator != "" {
+		t.Errorf("PendingState after Escape = %+v, want empty", state)
+	}
+	select {
+	case action := <-h.Actions():
+		t.Errorf("unexpected action %q after Escape", action.Name)
+	default:
+	}
+}
+
+func TestHandlerPendingStateTimeout(t *testing.T) {
+	config := DefaultConfig()
+	config.SequenceTimeout = 20 * time.Millisecond
+	h := NewHandler(config)
+	defer h.Close()
+
+	h.SetCount(5)
+	h.HandleKeyEvent(key.NewRuneEvent('g', key.ModNone))
+	if got := h.PendingState().Display; got != "5g" {
+		t.Fatalf("Display = %q, want %q", got, "5g")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if state := h.PendingState(); !state.IsEmpty() {
+		t.Errorf("PendingState after timeout = %+v, want empty", state)
+	}
+}
+
+func TestHandlerEscapeCancelsOperator(t *testing.T) {
+	h := NewHandler(DefaultConfig())
+	defer h.Close()
+
+	h.SetCount(2)
+	h.SetOperator("operator.delete")
+	h.HandleKeyEvent(key.NewSpecialEvent(key.KeyEscape, key.ModNone))
+
+	select {
+	case action := <-h.Actions():
+		t.Errorf("unexpected action %q after 2d<Esc>", action.Name)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if state := h.PendingState(); !state.IsEmpty() || state.Count != 0 || state.Operator != "" {
+		t.Errorf("PendingState after 2d<Esc> = %+v, want empty", state)
+	}
+
+	// The cancelled count must not apply to the next command
+	h.HandleKeyEvent(key.NewRuneEvent('d', key.ModNone))
+	select {
+	case action := <-h.Actions():
+		if action.Name != "operator.delete" || action.Count > 1 {
+			t.Errorf("action = %q count %d, want operator.delete without count", action.Name, action.Count)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected d to dispatch an action")
+	}
+}
+
+func TestHandlerOperatorTimeout(t *testing.T) {
+	config := DefaultConfig()
+	config.SequenceTimeout = time.Hour
+	config.OperatorTimeout = 20 * time.Millisecond
+	h := NewHandler(config)
+	defer h.Close()
+
+	h.SetCount(3)
+	h.SetOperator("operator.delete")
+	h.HandleKeyEvent(key.NewRuneEvent('i', key.ModNone))
+	if got := h.PendingState().Display; got != "3di" {
+		t.Fatalf("Display = %q, want %q", got, "3di")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if state := h.PendingState(); !state.IsEmpty() {
+		t.Errorf("PendingState after operator timeout = %+v, want empty", state)
+	}
+	select {
+	case action := <-h.Actions():
+		t.Errorf("unexpected action %q after operator timeout", action.Name)
+	default:
+	}
+}
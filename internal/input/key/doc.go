@@ -20,4 +20,16 @@
 // Multi-key sequences like Vim's "g g" or "d i w" are represented as
 // KeySequence values. The sequence parser handles timeout logic and
 // prefix matching for incomplete sequences.
+//
+// # Bracketed Paste
+//
+// StreamParser decodes raw terminal bytes and collapses bracketed paste
+// sequences (ESC[200~ ... ESC[201~) into a single PasteEvent, so pasted
+// text is inserted literally instead of being replayed as key presses.
+//
+// # Focus Events
+//
+// After writing EnableFocusReporting to the terminal and calling
+// SetFocusReporting, StreamParser decodes the focus sequences ESC[I and
+// ESC[O into FocusEvent values.
 package key
@@ -0,0 +1,48 @@
+package key
+
+import "bytes"
+
+// Terminal focus reporting (DECSET 1004). Once enabled, the terminal sends
+// FocusIn and FocusOut as the window gains and loses focus.
+const (
+	EnableFocusReporting  = "\x1b[?1004h"
+	DisableFocusReporting = "\x1b[?1004l"
+
+	FocusIn  = "\x1b[I"
+	FocusOut = "\x1b[O"
+)
+
+// FocusEvent represents the terminal window gaining or losing focus.
+type FocusEvent struct {
+	// Gained is true when focus was gained and false when it was lost.
+	Gained bool
+}
+
+// IsFocus returns true if this input is a focus change rather than a key
+// event.
+func (i Input) IsFocus() bool {
+	return i.Focus != nil
+}
+
+// SetFocusReporting sets whether the focus sequences ESC[I and ESC[O are
+// decoded as focus events. Enable it together with EnableFocusReporting;
+// while disabled the sequences are reported as keys.
+func (p *StreamParser) SetFocusReporting(enabled bool) {
+	p.focusReporting = enabled
+}
+
+// FocusReporting returns true if focus sequences are decoded.
+func (p *StreamParser) FocusReporting() bool {
+	return p.focusReporting
+}
+
+// decodeFocus decodes a focus sequence at the start of data.
+func decodeFocus(data []byte) (FocusEvent, bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte(FocusIn)):
+		return FocusEvent{Gained: true}, true
+	case bytes.HasPrefix(data, []byte(FocusOut)):
+		return FocusEvent{Gained: false}, true
+	}
+	return FocusEvent{}, false
+}
@@ -0,0 +1,52 @@
+package key
+
+import "testing"
+
+func TestStreamParserFocus(t *testing.T) {
+	p := NewStreamParser()
+	p.SetFocusReporting(true)
+
+	inputs := p.Feed([]byte("a" + FocusOut + FocusIn + "b"))
+	if len(inputs) != 4 {
+		t.Fatalf("expected 4 inputs, got %d", len(inputs))
+	}
+	if !inputs[1].IsFocus() || inputs[1].Focus.Gained {
+		t.Errorf("input[1] = %+v, want focus lost", inputs[1])
+	}
+	if !inputs[2].IsFocus() || !inputs[2].Focus.Gained {
+		t.Errorf("input[2] = %+v, want focus gained", inputs[2])
+	}
+	if inputs[3].IsFocus() || inputs[3].Key.Rune != 'b' {
+		t.Errorf("input[3] = %+v, want rune 'b'", inputs[3])
+	}
+}
+
+func TestStreamParserFocusSplit(t *testing.T) {
+	p := NewStreamParser()
+	p.SetFocusReporting(true)
+
+	if inputs := p.Feed([]byte("\x1b[")); len(inputs) != 0 {
+		t.Fatalf("expected partial sequence to be held, got %d inputs", len(inputs))
+	}
+	inputs := p.Feed([]byte("I"))
+	if len(inputs) != 1 || !inputs[0].IsFocus() || !inputs[0].Focus.Gained {
+		t.Errorf("inputs = %+v, want focus gained", inputs)
+	}
+}
+
+func TestStreamParserFocusDisabled(t *testing.T) {
+	p := NewStreamParser()
+
+	inputs := p.Feed([]byte(FocusIn))
+	if len(inputs) != 3 {
+		t.Fatalf("expected 3 inputs, got %d", len(inputs))
+	}
+	for i, in := range inputs {
+		if in.IsFocus() {
+			t.Errorf("input[%d] decoded as focus with reporting disabled", i)
+		}
+	}
+	if inputs[0].Key.Key != KeyEscape || inputs[2].Key.Rune != 'I' {
+		t.Errorf("inputs = %+v, want Escape [ I", inputs)
+	}
+}
@@ -0,0 +1,214 @@
+package key
+
+import (
+	"bytes"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Bracketed paste markers sent by terminals when bracketed paste mode is enabled.
+const (
+	PasteStart = "\x1b[200~"
+	PasteEnd   = "\x1b[201~"
+)
+
+// PasteEvent represents a block of text pasted into the terminal.
+// The text is delivered verbatim and must not be interpreted as key presses.
+type PasteEvent struct {
+	// Text is the pasted content without the bracketing markers.
+	Text string
+
+	// Timestamp is when the paste completed.
+	Timestamp time.Time
+}
+
+// NewPasteEvent creates a paste event with the current timestamp.
+func NewPasteEvent(text string) PasteEvent {
+	return PasteEvent{
+		Text:      text,
+		Timestamp: time.Now(),
+	}
+}
+
+// Input is a single item decoded from raw terminal input.
+// Exactly one of Key, Paste or Focus is meaningful; use IsPaste and IsFocus
+// to tell them apart.
+type Input struct {
+	// Key is the decoded key event when Paste and Focus are nil.
+	Key Event
+
+	// Paste is set when the item is a bracketed paste.
+	Paste *PasteEvent
+
+	// Focus is set when the item is a focus change.
+	Focus *FocusEvent
+}
+
+// IsPaste returns true if this input is a paste rather than a key event.
+func (i Input) IsPaste() bool {
+	return i.Paste != nil
+}
+
+// StreamParser decodes raw terminal bytes into key events, collapsing
+// bracketed paste sequences (ESC[200~ ... ESC[201~) into a single PasteEvent.
+//
+// Input may arrive split across any number of Feed calls; incomplete UTF-8
+// sequences and partial paste markers are held back until more data arrives.
+//
+// StreamParser only decodes plain characters, control characters, the
+// paste markers and, when enabled with SetFocusReporting, the focus
+// sequences. Other escape sequences (arrow keys, function keys) are the
+// terminal backend's responsibility and are reported as Escape followed by
+// the remaining characters.
+//
+// StreamParser is not safe for concurrent use.
+type StreamParser struct {
+	pending []byte
+	inPaste bool
+	paste   bytes.Buffer
+
+	focusReporting bool
+}
+
+// NewStreamParser creates a new stream parser.
+func NewStreamParser() *StreamParser {
+	return &StreamParser{}
+}
+
+// InPaste returns true if the parser is inside a bracketed paste
+// and waiting for the end marker.
+func (p *StreamParser) InPaste() bool {
+	return p.inPaste
+}
+
+// Feed consumes a chunk of raw input and returns the inputs that could be
+// fully decoded, in order.
+func (p *StreamParser) Feed(data []byte) []Input {
+	buf := append(p.pending, data...)
+	p.pending = nil
+
+	var out []Input
+	i := 0
+	for i < len(buf) {
+		if p.inPaste {
+			rest := buf[i:]
+			if end := bytes.Index(rest, []byte(PasteEnd)); end >= 0 {
+				p.paste.Write(rest[:end])
+				out = append(out, Input{Paste: p.finishPaste()})
+				i += end + len(PasteEnd)
+				continue
+			}
+			// Hold back a trailing partial end marker
+			keep := partialSuffix(rest, PasteEnd)
+			p.paste.Write(rest[:len(rest)-keep])
+			p.pending = append(p.pending, rest[len(rest)-keep:]...)
+			return out
+		}
+
+		rest := buf[i:]
+		if rest[0] == 0x1b {
+			if bytes.HasPrefix(rest, []byte(PasteStart)) {
+				p.inPaste = true
+				p.paste.Reset()
+				i += len(PasteStart)
+				continue
+			}
+			if p.focusReporting {
+				if focus, ok := decodeFocus(rest); ok {
+					out = append(out, Input{Focus: &focus})
+					i += len(FocusIn)
+					continue
+				}
+			}
+			if len(rest) < len(PasteStart) && bytes.HasPrefix([]byte(PasteStart), rest) {
+				p.pending = append(p.pending, rest...)
+				return out
+			}
+		}
+
+		if !utf8.FullRune(rest) {
+			p.pending = append(p.pending, rest...)
+			return out
+		}
+
+		r, size := utf8.DecodeRune(rest)
+		out = append(out, Input{Key: decodeRune(r)})
+		i += size
+	}
+
+	return out
+}
+
+// Flush returns any held-back bytes as key events. Call it when no more
+// input is expected soon (for example after an escape timeout) so that a
+// lone Escape is not held indefinitely. Bytes inside an unterminated paste
+// remain buffered.
+func (p *StreamParser) Flush() []Input {
+	if p.inPaste || len(p.pending) == 0 {
+		return nil
+	}
+
+	buf := p.pending
+	p.pending = nil
+
+	var out []Input
+	for len(buf) > 0 {
+		r, size := utf8.DecodeRune(buf)
+		out = append(out, Input{Key: decodeRune(r)})
+		buf = buf[size:]
+	}
+	return out
+}
+
+// Reset discards all buffered state.
+func (p *StreamParser) Reset() {
+	p.pending = nil
+	p.inPaste = false
+	p.paste.Reset()
+}
+
+// finishPaste completes the current paste and returns it.
+func (p *StreamParser) finishPaste() *PasteEvent {
+	ev := NewPasteEvent(p.paste.String())
+	p.paste.Reset()
+	p.inPaste = false
+	return &ev
+}
+
+// partialSuffix returns the length of the longest suffix of data
+// that is a proper prefix of marker.
+func partialSuffix(data []byte, marker string) int {
+	n := len(marker) - 1
+	if n > len(data) {
+		n = len(data)
+	}
+	for ; n > 0; n-- {
+		if bytes.HasPrefix([]byte(marker), data[len(data)-n:]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// decodeRune converts a single decoded rune from the terminal into a key event.
+func decodeRune(r rune) Event {
+	switch {
+	case r == '\r' || r == '\n':
+		return NewSpecialEvent(KeyEnter, ModNone)
+	case r == '\t':
+		return NewSpecialEvent(KeyTab, ModNone)
+	case r == 0x7f || r == 0x08:
+		return NewSpecialEvent(KeyBackspace, ModNone)
+	case r == 0x1b:
+		return NewSpecialEvent(KeyEscape, ModNone)
+	case r == 0x00:
+		return NewRuneEvent(' ', ModCtrl)
+	case r >= 0x01 && r <= 0x1a:
+		return NewRuneEvent('a'+r-1, ModCtrl)
+	case unicode.IsUpper(r):
+		return NewRuneEvent(r, ModShift)
+	default:
+		return NewRuneEvent(r, ModNone)
+	}
+}
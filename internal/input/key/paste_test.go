@@ -0,0 +1,129 @@
+package key
+
+import "testing"
+
+func TestStreamParserPlainKeys(t *testing.T) {
+	p := NewStreamParser()
+	inputs := p.Feed([]byte("ab\r"))
+
+	if len(inputs) != 3 {
+		t.Fatalf("expected 3 inputs, got %d", len(inputs))
+	}
+	if inputs[0].IsPaste() || inputs[0].Key.Rune != 'a' {
+		t.Errorf("input[0] = %+v, want rune 'a'", inputs[0])
+	}
+	if inputs[2].Key.Key != KeyEnter {
+		t.Errorf("input[2] key = %v, want KeyEnter", inputs[2].Key.Key)
+	}
+}
+
+func TestStreamParserControlKeys(t *testing.T) {
+	p := NewStreamParser()
+	inputs := p.Feed([]byte{0x13, 0x7f, '\t'})
+
+	if len(inputs) != 3 {
+		t.Fatalf("expected 3 inputs, got %d", len(inputs))
+	}
+	if inputs[0].Key.Rune != 's' || !inputs[0].Key.Modifiers.HasCtrl() {
+		t.Errorf("input[0] = %v, want Ctrl+s", inputs[0].Key)
+	}
+	if inputs[1].Key.Key != KeyBackspace {
+		t.Errorf("input[1] key = %v, want KeyBackspace", inputs[1].Key.Key)
+	}
+	if inputs[2].Key.Key != KeyTab {
+		t.Errorf("input[2] key = %v, want KeyTab", inputs[2].Key.Key)
+	}
+}
+
+func TestStreamParserPaste(t *testing.T) {
+	p := NewStreamParser()
+	text := "if x {\n\tdd:wq\x1b<Esc>\n}"
+	inputs := p.Feed([]byte("i" + PasteStart + text + PasteEnd + "x"))
+
+	if len(inputs) != 3 {
+		t.Fatalf("expected 3 inputs, got %d: %+v", len(inputs), inputs)
+	}
+	if inputs[0].IsPaste() || inputs[0].Key.Rune != 'i' {
+		t.Errorf("input[0] = %+v, want rune 'i'", inputs[0])
+	}
+	if !inputs[1].IsPaste() {
+		t.Fatalf("input[1] should be a paste")
+	}
+	if inputs[1].Paste.Text != text {
+		t.Errorf("paste text = %q, want %q", inputs[1].Paste.Text, text)
+	}
+	if inputs[2].IsPaste() || inputs[2].Key.Rune != 'x' {
+		t.Errorf("input[2] = %+v, want rune 'x'", inputs[2])
+	}
+	if p.InPaste() {
+		t.Error("parser should not be in paste after end marker")
+	}
+}
+
+func TestStreamParserPasteSplitAcrossReads(t *testing.T) {
+	raw := PasteStart + "héllo\nwörld" + PasteEnd
+	chunkSizes := []int{1, 2, 3, 5, 7}
+
+	for _, size := range chunkSizes {
+		p := NewStreamParser()
+		var inputs []Input
+		data := []byte(raw)
+		for len(data) > 0 {
+			n := size
+			if n > len(data) {
+				n = len(data)
+			}
+			inputs = append(inputs, p.Feed(data[:n])...)
+			data = data[n:]
+		}
+
+		if len(inputs) != 1 {
+			t.Fatalf("chunk %d: expected 1 input, got %d: %+v", size, len(inputs), inputs)
+		}
+		if !inputs[0].IsPaste() {
+			t.Fatalf("chunk %d: expected paste input", size)
+		}
+		if inputs[0].Paste.Text != "héllo\nwörld" {
+			t.Errorf("chunk %d: paste text = %q", size, inputs[0].Paste.Text)
+		}
+	}
+}
+
+func TestStreamParserUnterminatedPaste(t *testing.T) {
+	p := NewStreamParser()
+	inputs := p.Feed([]byte(PasteStart + "abc"))
+
+	if len(inputs) != 0 {
+		t.Fatalf("expected no inputs, got %d", len(inputs))
+	}
+	if !p.InPaste() {
+		t.Error("parser should be in paste")
+	}
+	if flushed := p.Flush(); len(flushed) != 0 {
+		t.Errorf("Flush() inside paste returned %d inputs, want 0", len(flushed))
+	}
+
+	inputs = p.Feed([]byte("def" + PasteEnd))
+	if len(inputs) != 1 || inputs[0].Paste.Text != "abcdef" {
+		t.Errorf("expected paste %q, got %+v", "abcdef", inputs)
+	}
+}
+
+func TestStreamParserLoneEscape(t *testing.T) {
+	p := NewStreamParser()
+	inputs := p.Feed([]byte{0x1b})
+
+	if len(inputs) != 0 {
+		t.Fatalf("lone escape should be held back, got %d inputs", len(inputs))
+	}
+
+	flushed := p.Flush()
+	if len(flushed) != 1 || flushed[0].Key.Key != KeyEscape {
+		t.Errorf("Flush() = %+v, want single Escape", flushed)
+	}
+
+	inputs = p.Feed([]byte("\x1bj"))
+	if len(inputs) != 2 || inputs[0].Key.Key != KeyEscape || inputs[1].Key.Rune != 'j' {
+		t.Errorf("Feed(ESC j) = %+v, want Escape then 'j'", inputs)
+	}
+}
@@ -2,13 +2,18 @@ package keymap
 
 import "github.com/dshills/keystorm/internal/input/mode"
 
+// SourceDefault is the Source of the default keymaps.
+const SourceDefault = "default"
+
 // LoadDefaults loads all default keymaps into the registry.
 func LoadDefaults(r *Registry) error {
 	keymaps := []*Keymap{
 		DefaultNormalKeymap(),
 		DefaultInsertKeymap(),
 		DefaultVisualKeymap(),
+		DefaultVisualBlockKeymap(),
 		DefaultCommandKeymap(),
+		DefaultOperatorPendingKeymap(),
 		DefaultGlobalKeymap(),
 	}
 
@@ -26,7 +31,7 @@ func DefaultNormalKeymap() *Keymap {
 	return &Keymap{
 		Name:   "default-normal",
 		Mode:   mode.ModeNormal,
-		Source: "default",
+		Source: SourceDefault,
 		Bindings: []Binding{
 			// Movement - basic
 			{Keys: "h", Action: "cursor.moveLeft", Description: "Move left", Category: "Movement"},
@@ -187,7 +192,7 @@ func DefaultInsertKeymap() *Keymap {
 	return &Keymap{
 		Name:   "default-insert",
 		Mode:   mode.ModeInsert,
-		Source: "default",
+		Source: SourceDefault,
 		Bindings: []Binding{
 			// Exit insert mode
 			{Keys: "Esc", Action: "mode.normal", Description: "Return to normal mode", Category: "Mode"},
@@ -231,10 +236,10 @@ func DefaultInsertKeymap() *Keymap {
 
 // DefaultVisualKeymap returns default visual mode bindings.
 func DefaultVisualKeymap() *Keymap {
-	return &Keymap{
+	km := &Keymap{
 		Name:   "default-visual",
 		Mode:   mode.ModeVisual,
-		Source: "default",
+		Source: SourceDefault,
 		Bindings: []Binding{
 			// Exit visual mode
 			{Keys: "Esc", Action: "mode.normal", Description: "Return to normal mode", Category: "Mode"},
@@ -290,46 +295,20 @@ func DefaultVisualKeymap() *Keymap {
 			{Keys: "g g", Action: "cursor.moveFirstLine", Description: "Extend to document start", Category: "Movement"},
 			{Keys: "G", Action: "cursor.moveLastLine", Description: "Extend to document end", Category: "Movement"},
 			{Keys: "%", Action: "cursor.matchingBracket", Description: "Extend to matching bracket", Category: "Movement"},
-
-			// Text objects
-			{Keys: "i w", Action: "textobj.innerWord", Description: "Inner word", Category: "Text Objects"},
-			{Keys: "a w", Action: "textobj.aWord", Description: "A word", Category: "Text Objects"},
-			{Keys: "i W", Action: "textobj.innerWORD", Description: "Inner WORD", Category: "Text Objects"},
-			{Keys: "a W", Action: "textobj.aWORD", Description: "A WORD", Category: "Text Objects"},
-			{Keys: "i s", Action: "textobj.innerSentence", Description: "Inner sentence", Category: "Text Objects"},
-			{Keys: "a s", Action: "textobj.aSentence", Description: "A sentence", Category: "Text Objects"},
-			{Keys: "i p", Action: "textobj.innerParagraph", Description: "Inner paragraph", Category: "Text Objects"},
-			{Keys: "a p", Action: "textobj.aParagraph", Description: "A paragraph", Category: "Text Objects"},
-			{Keys: "i (", Action: "textobj.innerParen", Description: "Inner parentheses", Category: "Text Objects"},
-			{Keys: "a (", Action: "textobj.aParen", Description: "A parentheses", Category: "Text Objects"},
-			{Keys: "i )", Action: "textobj.innerParen", Description: "Inner parentheses", Category: "Text Objects"},
-			{Keys: "a )", Action: "textobj.aParen", Description: "A parentheses", Category: "Text Objects"},
-			{Keys: "i [", Action: "textobj.innerBracket", Description: "Inner brackets", Category: "Text Objects"},
-			{Keys: "a [", Action: "textobj.aBracket", Description: "A brackets", Category: "Text Objects"},
-			{Keys: "i ]", Action: "textobj.innerBracket", Description: "Inner brackets", Category: "Text Objects"},
-			{Keys: "a ]", Action: "textobj.aBracket", Description: "A brackets", Category: "Text Objects"},
-			{Keys: "i {", Action: "textobj.innerBrace", Description: "Inner braces", Category: "Text Objects"},
-			{Keys: "a {", Action: "textobj.aBrace", Description: "A braces", Category: "Text Objects"},
-			{Keys: "i }", Action: "textobj.innerBrace", Description: "Inner braces", Category: "Text Objects"},
-			{Keys: "a }", Action: "textobj.aBrace", Description: "A braces", Category: "Text Objects"},
-			{Keys: "i <", Action: "textobj.innerAngle", Description: "Inner angle brackets", Category: "Text Objects"},
-			{Keys: "a <", Action: "textobj.aAngle", Description: "A angle brackets", Category: "Text Objects"},
-			{Keys: "i >", Action: "textobj.innerAngle", Description: "Inner angle brackets", Category: "Text Objects"},
-			{Keys: "a >", Action: "textobj.aAngle", Description: "A angle brackets", Category: "Text Objects"},
-			{Keys: "i \"", Action: "textobj.innerDoubleQuote", Description: "Inner double quotes", Category: "Text Objects"},
-			{Keys: "a \"", Action: "textobj.aDoubleQuote", Description: "A double quotes", Category: "Text Objects"},
-			{Keys: "i '", Action: "textobj.innerSingleQuote", Description: "Inner single quotes", Category: "Text Objects"},
-			{Keys: "a '", Action: "textobj.aSingleQuote", Description: "A single quotes", Category: "Text Objects"},
-			{Keys: "i `", Action: "textobj.innerBacktick", Description: "Inner backticks", Category: "Text Objects"},
-			{Keys: "a `", Action: "textobj.aBacktick", Description: "A backticks", Category: "Text Objects"},
-			{Keys: "i t", Action: "textobj.innerTag", Description: "Inner tag", Category: "Text Objects"},
-			{Keys: "a t", Action: "textobj.aTag", Description: "A tag", Category: "Text Objects"},
-			{Keys: "i b", Action: "textobj.innerBlock", Description: "Inner block", Category: "Text Objects"},
-			{Keys: "a b", Action: "textobj.aBlock", Description: "A block", Category: "Text Objects"},
-			{Keys: "i B", Action: "textobj.innerBigBlock", Description: "Inner big block", Category: "Text Objects"},
-			{Keys: "a B", Action: "textobj.aBigBlock", Description: "A big block", Category: "Text Objects"},
 		},
 	}
+	km.Bindings = append(km.Bindings, textObjectBindings()...)
+	return km
+}
+
+// DefaultVisualBlockKeymap returns default visual block mode bindings.
+// They are the visual mode bindings, so I and A start a block insert or
+// append on every line of the block.
+func DefaultVisualBlockKeymap() *Keymap {
+	km := DefaultVisualKeymap()
+	km.Name = "default-visual-block"
+	km.Mode = mode.ModeVisualBlock
+	return km
 }
 
 // DefaultCommandKeymap returns default command-line mode bindings.
@@ -337,7 +316,7 @@ func DefaultCommandKeymap() *Keymap {
 	return &Keymap{
 		Name:   "default-command",
 		Mode:   mode.ModeCommand,
-		Source: "default",
+		Source: SourceDefault,
 		Bindings: []Binding{
 			// Exit
 			{Keys: "Esc", Action: "mode.normal", Description: "Cancel and return to normal", Category: "Mode"},
@@ -385,7 +364,7 @@ func DefaultGlobalKeymap() *Keymap {
 	return &Keymap{
 		Name:   "default-global",
 		Mode:   "", // All modes
-		Source: "default",
+		Source: SourceDefault,
 		Bindings: []Binding{
 			// File operations
 			{Keys: "C-s", Action: "file.save", Description: "Save file", Category: "File"},
@@ -441,3 +420,97 @@ func DefaultGlobalKeymap() *Keymap {
 		},
 	}
 }
+
+// DefaultOperatorPendingKeymap returns the motions and text objects that may
+// follow an operator such as "d" or "c". Only these bindings are valid while
+// an operator is pending; any other key cancels the operator.
+func DefaultOperatorPendingKeymap() *Keymap {
+	km := &Keymap{
+		Name:   "default-operator-pending",
+		Mode:   mode.ModeOperatorPending,
+		Source: SourceDefault,
+		Bindings: []Binding{
+			// Cancel
+			{Keys: "Esc", Action: "mode.normal", Description: "Cancel operator", Category: "Mode"},
+			{Keys: "C-c", Action: "mode.normal", Description: "Cancel operator", Category: "Mode"},
+
+			// Motions
+			{Keys: "h", Action: "cursor.moveLeft", Description: "Left", Category: "Movement"},
+			{Keys: "j", Action: "cursor.moveDown", Description: "Down", Category: "Movement"},
+			{Keys: "k", Action: "cursor.moveUp", Description: "Up", Category: "Movement"},
+			{Keys: "l", Action: "cursor.moveRight", Description: "Right", Category: "Movement"},
+			{Keys: "w", Action: "cursor.wordForward", Description: "To next word", Category: "Movement"},
+			{Keys: "W", Action: "cursor.bigWordForward", Description: "To next WORD", Category: "Movement"},
+			{Keys: "b", Action: "cursor.wordBackward", Description: "To previous word", Category: "Movement"},
+			{Keys: "B", Action: "cursor.bigWordBackward", Description: "To previous WORD", Category: "Movement"},
+			{Keys: "e", Action: "cursor.wordEndForward", Description: "To end of word", Category: "Movement"},
+			{Keys: "E", Action: "cursor.bigWordEndForward", Description: "To end of WORD", Category: "Movement"},
+			{Keys: "g e", Action: "cursor.wordEndBackward", Description: "To end of previous word", Category: "Movement"},
+			{Keys: "0", Action: "cursor.moveLineStart", Description: "To line start", Category: "Movement"},
+			{Keys: "$", Action: "cursor.moveLineEnd", Description: "To line end", Category: "Movement"},
+			{Keys: "^", Action: "cursor.firstNonBlank", Description: "To first non-blank", Category: "Movement"},
+			{Keys: "|", Action: "cursor.gotoColumn", Description: "To column", Category: "Movement"},
+			{Keys: "g g", Action: "cursor.moveFirstLine", Description: "To document start", Category: "Movement"},
+			{Keys: "G", Action: "cursor.moveLastLine", Description: "To document end", Category: "Movement"},
+			{Keys: "H", Action: "cursor.screenTop", Description: "To top of screen", Category: "Movement"},
+			{Keys: "M", Action: "cursor.screenMiddle", Description: "To middle of screen", Category: "Movement"},
+			{Keys: "L", Action: "cursor.screenBottom", Description: "To bottom of screen", Category: "Movement"},
+			{Keys: "f", Action: "cursor.findForward", Description: "To char forward", Category: "Movement"},
+			{Keys: "F", Action: "cursor.findBackward", Description: "To char backward", Category: "Movement"},
+			{Keys: "t", Action: "cursor.tillForward", Description: "Till char forward", Category: "Movement"},
+			{Keys: "T", Action: "cursor.tillBackward", Description: "Till char backward", Category: "Movement"},
+			{Keys: ";", Action: "cursor.repeatFind", Description: "Repeat last f/t/F/T", Category: "Movement"},
+			{Keys: ",", Action: "cursor.repeatFindReverse", Description: "Repeat last f/t/F/T reverse", Category: "Movement"},
+			{Keys: "%", Action: "cursor.matchingBracket", Description: "To matching bracket", Category: "Movement"},
+			{Keys: "n", Action: "search.next", Description: "To next search result", Category: "Movement"},
+			{Keys: "N", Action: "search.previous", Description: "To previous search result", Category: "Movement"},
+		},
+	}
+	km.Bindings = append(km.Bindings, textObjectBindings()...)
+	return km
+}
+
+// textObjectBindings returns the text object bindings shared by visual and
+// operator-pending modes.
+func textObjectBindings() []Binding {
+	return []Binding{
+		{Keys: "i w", Action: "textobj.innerWord", Description: "Inner word", Category: "Text Objects"},
+		{Keys: "a w", Action: "textobj.aWord", Description: "A word", Category: "Text Objects"},
+		{Keys: "i W", Action: "textobj.innerWORD", Description: "Inner WORD", Category: "Text Objects"},
+		{Keys: "a W", Action: "textobj.aWORD", Description: "A WORD", Category: "Text Objects"},
+		{Keys: "i s", Action: "textobj.innerSentence", Description: "Inner sentence", Category: "Text Objects"},
+		{Keys: "a s", Action: "textobj.aSentence", Description: "A sentence", Category: "Text Objects"},
+		{Keys: "i p", Action: "textobj.innerParagraph", Description: "Inner paragraph", Category: "Text Objects"},
+		{Keys: "a p", Action: "textobj.aParagraph", Description: "A paragraph", Category: "Text Objects"},
+		{Keys: "i (", Action: "textobj.innerParen", Description: "Inner parentheses", Category: "Text Objects"},
+		{Keys: "a (", Action: "textobj.aParen", Description: "A parentheses", Category: "Text Objects"},
+		{Keys: "i )", Action: "textobj.innerParen", Description: "Inner parentheses", Category: "Text Objects"},
+		{Keys: "a )", Action: "textobj.aParen", Description: "A parentheses", Category: "Text Objects"},
+		{Keys: "i [", Action: "textobj.innerBracket", Description: "Inner brackets", Category: "Text Objects"},
+		{Keys: "a [", Action: "textobj.aBracket", Description: "A brackets", Category: "Text Objects"},
+		{Keys: "i ]", Action: "textobj.innerBracket", Description: "Inner brackets", Category: "Text Objects"},
+		{Keys: "a ]", Action: "textobj.aBracket", Description: "A brackets", Category: "Text Objects"},
+		{Keys: "i {", Action: "textobj.innerBrace", Description: "Inner braces", Category: "Text Objects"},
+		{Keys: "a {", Action: "textobj.aBrace", Description: "A braces", Category: "Text Objects"},
+		{Keys: "i }", Action: "textobj.innerBrace", Description: "Inner braces", Category: "Text Objects"},
+		{Keys: "a }", Action: "textobj.aBrace", Description: "A braces", Category: "Text Objects"},
+		{Keys: "i <", Action: "textobj.innerAngle", Description: "Inner angle brackets", Category: "Text Objects"},
+		{Keys: "a <", Action: "textobj.aAngle", Description: "A angle brackets", Category: "Text Objects"},
+		{Keys: "i >", Action: "textobj.innerAngle", Description: "Inner angle brackets", Category: "Text Objects"},
+		{Keys: "a >", Action: "textobj.aAngle", Description: "A angle brackets", Category: "Text Objects"},
+		{Keys: "i \"", Action: "textobj.innerDoubleQuote", Description: "Inner double quotes", Category: "Text Objects"},
+		{Keys: "a \"", Action: "textobj.aDoubleQuote", Description: "A double quotes", Category: "Text Objects"},
+		{Keys: "i '", Action: "textobj.innerSingleQuote", Description: "Inner single quotes", Category: "Text Objects"},
+		{Keys: "a '", Action: "textobj.aSingleQuote", Description: "A single quotes", Category: "Text Objects"},
+		{Keys: "i `", Action: "textobj.innerBacktick", Description: "Inner backticks", Category: "Text Objects"},
+		{Keys: "a `", Action: "textobj.aBacktick", Description: "A backticks", Category: "Text Objects"},
+		{Keys: "i t", Action: "textobj.innerTag", Description: "Inner tag", Category: "Text Objects"},
+		{Keys: "a t", Action: "textobj.aTag", Description: "A tag", Category: "Text Objects"},
+		{Keys: "i b", Action: "textobj.innerBlock", Description: "Inner block", Category: "Text Objects"},
+		{Keys: "a b", Action: "textobj.aBlock", Description: "A block", Category: "Text Objects"},
+		{Keys: "i B", Action: "textobj.innerBigBlock", Description: "Inner big block", Category: "Text Objects"},
+		{Keys: "a B", Action: "textobj.aBigBlock", Description: "A big block", Category: "Text Objects"},
+		{Keys: "i i", Action: "textobj.innerIndent", Description: "Inner indentation block", Category: "Text Objects"},
+		{Keys: "a i", Action: "textobj.aIndent", Description: "An indentation block with its header", Category: "Text Objects"},
+	}
+}
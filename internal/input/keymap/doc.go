@@ -19,6 +19,19 @@
 //  2. Specificity (mode-specific > global)
 //  3. Registration order (later wins)
 //
+// # Filetype Layers
+//
+// A filetype layer holds normal mode bindings for one language. It is
+// consulted while that language is active, above mode defaults but below
+// user bindings:
+//
+//	registry.PushFiletypeLayer("go", []keymap.Binding{
+//	    {Keys: "g t", Action: "go.test"},
+//	})
+//	registry.SetActiveFiletype("go") // usually done by input.Handler
+//
+// A LookupContext with a FileType overrides the active filetype.
+//
 // # Key Sequence Parsing
 //
 // Key sequences can be specified in multiple formats:
@@ -40,6 +53,18 @@
 //	    When:   "editorTextFocus && !editorReadonly",
 //	}
 //
+// # Operator-Pending Resolution
+//
+// Operators such as "d" and "c" (actions in the "operator." namespace) wait
+// for a motion or text object. Setting LookupContext.Operator (or using
+// WithOperator) resolves keys against operator-pending keymaps only, so "w"
+// is a motion while "x" does not match and cancels the operator:
+//
+//	opCtx := ctx.WithOperator("operator.delete", 2)
+//	if registry.IsOperatorContinuation(seq, opCtx) {
+//	    m := registry.LookupOperatorMotion(seq, opCtx, 3) // m.Count == 6
+//	}
+//
 // # Usage
 //
 //	registry := keymap.NewRegistry()
@@ -0,0 +1,86 @@
+package keymap
+
+import "fmt"
+
+// FiletypeLayerPriority is the priority of keymaps created by
+// PushFiletypeLayer. It places filetype bindings above the default
+// keymaps (priority 0) and below user keymaps (priority 100).
+const FiletypeLayerPriority = 10
+
+// SourceFiletype is the Source of keymaps created by PushFiletypeLayer.
+const SourceFiletype = "filetype"
+
+// FiletypeLayerMode is the mode of keymaps created by PushFiletypeLayer.
+const FiletypeLayerMode = "normal"
+
+// FiletypeLayerName returns the name of the keymap holding the filetype
+// layer for a language.
+func FiletypeLayerName(language string) string {
+	return "filetype:" + language
+}
+
+// PushFiletypeLayer adds bindings to the filetype layer for a language.
+// The layer is consulted by lookups while the language is active (see
+// SetActiveFiletype), above mode defaults but below user bindings.
+// Bindings apply in normal mode; bindings for other modes are registered
+// as a Keymap with a FileType. Repeated calls for the same language add
+// to its layer.
+func (r *Registry) PushFiletypeLayer(language string, bindings []Binding) error {
+	if language == "" {
+		return fmt.Errorf("filetype layer requires a language")
+	}
+
+	name := FiletypeLayerName(language)
+	km := &Keymap{
+		Name:     name,
+		Mode:     FiletypeLayerMode,
+		FileType: language,
+		Priority: FiletypeLayerPriority,
+		Source:   SourceFiletype,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.keymaps[name]; ok {
+		km.Bindings = append(km.Bindings, existing.Bindings...)
+	}
+	km.Bindings = append(km.Bindings, bindings...)
+
+	parsed, err := km.Parse()
+	if err != nil {
+		return fmt.Errorf("parsing filetype layer %q: %w", language, err)
+	}
+	r.registerLocked(parsed)
+	return nil
+}
+
+// RemoveFiletypeLayer removes the filetype layer for a language.
+func (r *Registry) RemoveFiletypeLayer(language string) {
+	r.Unregister(FiletypeLayerName(language))
+}
+
+// SetActiveFiletype sets the language of the active buffer. Lookups whose
+// context has no FileType use it to select filetype-specific keymaps.
+// An empty language deactivates all filetype layers.
+func (r *Registry) SetActiveFiletype(language string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.activeFiletype = language
+}
+
+// ActiveFiletype returns the language set by SetActiveFiletype.
+func (r *Registry) ActiveFiletype() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.activeFiletype
+}
+
+// fileTypeLocked returns the file type lookups in ctx resolve against.
+// Caller must hold the read lock.
+func (r *Registry) fileTypeLocked(ctx *LookupContext) string {
+	if ctx.FileType != "" {
+		return ctx.FileType
+	}
+	return r.activeFiletype
+}
@@ -529,6 +529,86 @@ func TestRegistryFileType(t *testing.T) {
 	}
 }
 
+func TestRegistryFiletypeLayer(t *testing.T) {
+	reg := NewRegistry()
+
+	normal := NewKeymap("normal").
+		ForMode("normal").
+		Add("g d", "editor.gotoDefinition").
+		Add("g f", "editor.format")
+	user := NewKeymap("user-normal").
+		ForMode("normal").
+		WithPriority(100).
+		WithSource("user").
+		Add("g f", "user.format")
+	for _, km := range []*Keymap{normal, user} {
+		if err := reg.Register(km); err != nil {
+			t.Fatalf("Register(%s) error = %v", km.Name, err)
+		}
+	}
+
+	err := reg.PushFiletypeLayer("go", []Binding{
+		{Keys: "g d", Action: "go.definition"},
+		{Keys: "g f", Action: "go.format"},
+	})
+	if err != nil {
+		t.Fatalf("PushFiletypeLayer error = %v", err)
+	}
+
+	gd, _ := key.ParseSequence("g d")
+	gf, _ := key.ParseSequence("g f")
+	ctx := &LookupContext{Mode: "normal"}
+
+	if b := reg.Lookup(gd, ctx); b == nil || b.Action != "editor.gotoDefinition" {
+		t.Errorf("Lookup(g d) without filetype = %v, want editor.gotoDefinition", b)
+	}
+
+	reg.SetActiveFiletype("go")
+	if got := reg.ActiveFiletype(); got != "go" {
+		t.Errorf("ActiveFiletype = %q, want %q", got, "go")
+	}
+	if b := reg.Lookup(gd, ctx); b == nil || b.Action != "go.definition" {
+		t.Errorf("Lookup(g d) with go active = %v, want go.definition", b)
+	}
+	if b := reg.Lookup(gf, ctx); b == nil || b.Action != "user.format" {
+		t.Errorf("Lookup(g f) with go active = %v, want user binding to win", b)
+	}
+
+	// An explicit context filetype overrides the active filetype.
+	pyCtx := &LookupContext{Mode: "normal", FileType: "python"}
+	if b := reg.Lookup(gd, pyCtx); b == nil || b.Action != "editor.gotoDefinition" {
+		t.Errorf("Lookup(g d) for python context = %v, want editor.gotoDefinition", b)
+	}
+
+	reg.SetActiveFiletype("python")
+	if b := reg.Lookup(gd, ctx); b == nil || b.Action != "editor.gotoDefinition" {
+		t.Errorf("Lookup(g d) with python active = %v, want editor.gotoDefinition", b)
+	}
+
+	// Pushing again adds to the layer.
+	if err := reg.PushFiletypeLayer("go", []Binding{{Keys: "g t", Action: "go.test"}}); err != nil {
+		t.Fatalf("PushFiletypeLayer error = %v", err)
+	}
+	if got := len(reg.Get(FiletypeLayerName("go")).Bindings); got != 3 {
+		t.Errorf("go layer bindings = %d, want 3", got)
+	}
+
+	// The layer only applies in normal mode.
+	if b := reg.Lookup(gd, &LookupContext{Mode: "insert", FileType: "go"}); b != nil {
+		t.Errorf("Lookup(g d) in insert mode = %v, want nil", b)
+	}
+
+	reg.SetActiveFiletype("go")
+	reg.RemoveFiletypeLayer("go")
+	if b := reg.Lookup(gd, ctx); b == nil || b.Action != "editor.gotoDefinition" {
+		t.Errorf("Lookup(g d) after RemoveFiletypeLayer = %v, want editor.gotoDefinition", b)
+	}
+
+	if err := reg.PushFiletypeLayer("", nil); err == nil {
+		t.Error("PushFiletypeLayer with empty language should fail")
+	}
+}
+
 func TestRegistryAllBindings(t *testing.T) {
 	reg := NewRegistry()
 
@@ -851,6 +931,7 @@ func TestDefaultKeymapCoverage(t *testing.T) {
 		DefaultNormalKeymap(),
 		DefaultInsertKeymap(),
 		DefaultVisualKeymap(),
+		DefaultVisualBlockKeymap(),
 		DefaultCommandKeymap(),
 		DefaultGlobalKeymap(),
 	}
@@ -870,3 +951,87 @@ func TestDefaultKeymapCoverage(t *testing.T) {
 		})
 	}
 }
+
+func TestOperatorPendingLookup(t *testing.T) {
+	reg := NewRegistry()
+	if err := LoadDefaults(reg); err != nil {
+		t.Fatalf("LoadDefaults() error = %v", err)
+	}
+
+	ctx := &LookupContext{Mode: mode.ModeNormal}
+	seq, _ := key.ParseSequence("d")
+	op := reg.Lookup(seq, ctx)
+	if op == nil || !IsOperatorAction(op.Action) {
+		t.Fatalf("'d' in normal mode = %v, want an operator", op)
+	}
+
+	opCtx := ctx.WithOperator(op.Action, 2)
+
+	w, _ := key.ParseSequence("w")
+	if !reg.IsOperatorContinuation(w, opCtx) {
+		t.Error("'w' should continue a pending operator")
+	}
+	motion := reg.LookupOperatorMotion(w, opCtx, 3)
+	if motion == nil {
+		t.Fatal("'w' should resolve as a motion")
+	}
+	if motion.Motion.Action != "cursor.wordForward" {
+		t.Errorf("motion action = %q, want %q", motion.Motion.Action, "cursor.wordForward")
+	}
+	if motion.Operator != "operator.delete" {
+		t.Errorf("Operator = %q, want %q", motion.Operator, "operator.delete")
+	}
+	if motion.Count != 6 {
+		t.Errorf("Count = %d, want 6", motion.Count)
+	}
+
+	x, _ := key.ParseSequence("x")
+	if reg.IsOperatorContinuation(x, opCtx) {
+		t.Error("'x' should not continue a pending operator")
+	}
+	if b := reg.Lookup(x, opCtx); b != nil {
+		t.Errorf("'x' while operator pending = %q, want nil", b.Action)
+	}
+	if b := reg.Lookup(x, ctx); b == nil || b.Action != "editor.deleteChar" {
+		t.Errorf("'x' in normal mode = %v, want editor.deleteChar", b)
+	}
+
+	// Text objects are only valid while an operator is pending.
+	i, _ := key.ParseSequence("i")
+	if !reg.IsOperatorContinuation(i, opCtx) {
+		t.Error("'i' should be a prefix of a text object")
+	}
+	iw, _ := key.ParseSequence("i w")
+	if b := reg.Lookup(iw, opCtx); b == nil || b.Action != "textobj.innerWord" {
+		t.Errorf("'i w' while operator pending = %v, want textobj.innerWord", b)
+	}
+	if b := reg.Lookup(iw, ctx); b != nil && b.Action == "textobj.innerWord" {
+		t.Error("'i w' should not resolve as a text object in normal mode")
+	}
+
+	// Global bindings do not apply while an operator is pending.
+	gt, _ := key.ParseSequence("g t")
+	if b := reg.Lookup(gt, opCtx); b != nil {
+		t.Errorf("'g t' while operator pending = %q, want nil", b.Action)
+	}
+
+	if reg.IsOperatorContinuation(w, ctx) {
+		t.Error("IsOperatorContinuation should be false without a pending operator")
+	}
+}
+
+func TestComposeCounts(t *testing.T) {
+	tests := []struct {
+		op, motion, want int
+	}{
+		{0, 0, 1},
+		{2, 0, 2},
+		{0, 3, 3},
+		{2, 3, 6},
+	}
+	for _, tt := range tests {
+		if got := ComposeCounts(tt.op, tt.motion); got != tt.want {
+			t.Errorf("ComposeCounts(%d, %d) = %d, want %d", tt.op, tt.motion, got, tt.want)
+		}
+	}
+}
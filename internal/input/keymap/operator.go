@@ -0,0 +1,92 @@
+package keymap
+
+import (
+	"strings"
+
+	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/mode"
+)
+
+// operatorActionPrefix is the action namespace of operators that wait for a
+// motion or text object.
+const operatorActionPrefix = "operator."
+
+// IsOperatorAction returns true if action is an operator that enters
+// operator-pending mode (e.g., "operator.delete" bound to "d").
+func IsOperatorAction(action string) bool {
+	return strings.HasPrefix(action, operatorActionPrefix)
+}
+
+// WithOperator returns a copy of the context with op pending, typed after
+// count. Lookups with the returned context resolve motions and text objects.
+// The Conditions and Variables maps are shared with c.
+func (c *LookupContext) WithOperator(op string, count int) *LookupContext {
+	clone := *c
+	clone.Operator = op
+	clone.OperatorCount = count
+	return &clone
+}
+
+// operatorPending returns true if lookups resolve in operator-pending
+// context, either because an operator is set or the mode is
+// operator-pending.
+func (c *LookupContext) operatorPending() bool {
+	return c.Operator != "" || c.Mode == mode.ModeOperatorPending
+}
+
+// OperatorMotion is a pending operator resolved together with the motion or
+// text object it applies to.
+type OperatorMotion struct {
+	// Operator is the operator action (e.g., "operator.delete").
+	Operator string
+
+	// Motion is the motion or text object binding.
+	Motion *Binding
+
+	// Count is the effective count: the operator count multiplied by the
+	// motion count, so that "2d3w" deletes six words.
+	Count int
+}
+
+// IsOperatorContinuation reports whether seq is a valid continuation of the
+// operator pending in ctx: a complete motion or text object, or a prefix of
+// one (e.g., "i" before "iw"). Returns false if no operator is pending.
+func (r *Registry) IsOperatorContinuation(seq *key.Sequence, ctx *LookupContext) bool {
+	if seq == nil || ctx == nil || !ctx.operatorPending() {
+		return false
+	}
+	return r.HasPrefix(seq, ctx)
+}
+
+// LookupOperatorMotion resolves seq as the motion or text object of the
+// operator pending in ctx. motionCount is the count typed after the
+// operator. Returns nil if no operator is pending or seq is not a complete
+// motion or text object.
+func (r *Registry) LookupOperatorMotion(seq *key.Sequence, ctx *LookupContext, motionCount int) *OperatorMotion {
+	if seq == nil || ctx == nil || !ctx.operatorPending() {
+		return nil
+	}
+
+	binding := r.Lookup(seq, ctx)
+	if binding == nil {
+		return nil
+	}
+	return &OperatorMotion{
+		Operator: ctx.Operator,
+		Motion:   binding,
+		Count:    ComposeCounts(ctx.OperatorCount, motionCount),
+	}
+}
+
+// ComposeCounts combines the count typed before an operator with the count
+// typed before its motion. A zero count means no count was typed; the
+// result is always at least 1.
+func ComposeCounts(operatorCount, motionCount int) int {
+	if operatorCount <= 0 {
+		operatorCount = 1
+	}
+	if motionCount <= 0 {
+		motionCount = 1
+	}
+	return operatorCount * motionCount
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/mode"
 )
 
 // Registry manages all keymaps and provides binding lookup.
@@ -20,6 +21,9 @@ type Registry struct {
 
 	// conditionEvaluator evaluates "when" conditions.
 	conditionEvaluator ConditionEvaluator
+
+	// activeFiletype is the language of the active buffer.
+	activeFiletype string
 }
 
 // ConditionEvaluator evaluates binding conditions.
@@ -43,6 +47,15 @@ type LookupContext struct {
 	// Variables holds context variables.
 	// Keys: "resourceLangId", "activeEditor", etc.
 	Variables map[string]string
+
+	// Operator is the pending operator action (e.g., "operator.delete").
+	// When set, lookup resolves in operator-pending context: only bindings
+	// of mode.ModeOperatorPending keymaps (motions and text objects) match,
+	// regardless of Mode.
+	Operator string
+
+	// OperatorCount is the count typed before the pending operator.
+	OperatorCount int
 }
 
 // NewLookupContext creates a new lookup context.
@@ -84,6 +97,15 @@ func (r *Registry) Register(km *Keymap) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.registerLocked(parsed)
+	return nil
+}
+
+// registerLocked adds a parsed keymap, replacing one with the same name.
+// Caller must hold the write lock.
+func (r *Registry) registerLocked(parsed *ParsedKeymap) {
+	km := parsed.Keymap
+
 	// Remove existing keymap with same name if present
 	r.unregisterLocked(km.Name)
 
@@ -94,8 +116,6 @@ func (r *Registry) Register(km *Keymap) error {
 		pb := &parsed.ParsedBindings[i]
 		r.prefixTree.Insert(pb.Sequence, km.Mode, pb, km)
 	}
-
-	return nil
 }
 
 // Unregister removes a keymap from the registry.
@@ -181,10 +201,14 @@ func (r *Registry) HasPrefix(seq *key.Sequence, ctx *LookupContext) bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	if ctx.operatorPending() {
+		return r.prefixTree.HasModePrefix(seq, mode.ModeOperatorPending)
+	}
+
 	// Check mode-specific and global bindings
 	modes := []string{ctx.Mode, ""}
-	for _, mode := range modes {
-		if r.prefixTree.HasPrefix(seq, mode) {
+	for _, m := range modes {
+		if r.prefixTree.HasPrefix(seq, m) {
 			return true
 		}
 	}
@@ -195,13 +219,24 @@ func (r *Registry) HasPrefix(seq *key.Sequence, ctx *LookupContext) bool {
 func (r *Registry) findMatches(seq *key.Sequence, ctx *LookupContext) []BindingMatch {
 	matches := make([]BindingMatch, 0)
 
-	// Check mode-specific bindings first, then global
+	fileType := r.fileTypeLocked(ctx)
+
+	// Check mode-specific bindings first, then global. While an operator
+	// is pending only operator-pending bindings apply.
 	modes := []string{ctx.Mode, ""}
-	for _, mode := range modes {
-		entries := r.prefixTree.Lookup(seq, mode)
+	operatorPending := ctx.operatorPending()
+	if operatorPending {
+		modes = []string{mode.ModeOperatorPending}
+	}
+	for _, m := range modes {
+		entries := r.prefixTree.Lookup(seq, m)
 		for _, entry := range entries {
+			if operatorPending && entry.Mode != mode.ModeOperatorPending {
+				continue
+			}
+
 			// Check filetype match
-			if entry.Keymap.FileType != "" && entry.Keymap.FileType != ctx.FileType {
+			if entry.Keymap.FileType != "" && entry.Keymap.FileType != fileType {
 				continue
 			}
 
@@ -412,6 +447,36 @@ func (t *PrefixTree) HasPrefix(seq *key.Sequence, mode string) bool {
 	return len(node.children) > 0 || t.hasMatchingEntry(node, mode)
 }
 
+// HasModePrefix is like HasPrefix but only considers bindings registered
+// for exactly the given mode, ignoring global bindings.
+func (t *PrefixTree) HasModePrefix(seq *key.Sequence, mode string) bool {
+	node := t.root
+	for _, event := range seq.Events {
+		child, ok := node.children[event.String()]
+		if !ok {
+			return false
+		}
+		node = child
+	}
+	return hasModeEntry(node, mode)
+}
+
+// hasModeEntry reports whether node or any descendant has an entry for
+// exactly the given mode.
+func hasModeEntry(node *prefixNode, mode string) bool {
+	for _, entry := range node.entries {
+		if entry.Mode == mode {
+			return true
+		}
+	}
+	for _, child := range node.children {
+		if hasModeEntry(child, mode) {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *PrefixTree) hasMatchingEntry(node *prefixNode, mode string) bool {
 	for _, entry := range node.entries {
 		if entry.Mode == mode || entry.Mode == "" {
@@ -1,6 +1,7 @@
 package mode
 
 import (
+	"strings"
 	"unicode"
 
 	"github.com/dshills/keystorm/internal/input/key"
@@ -268,6 +269,13 @@ func (m *CommandMode) parseCommand(cmd string) *Action {
 			}
 		}
 
+		// :set option, :set nooption, :set option!, :set option=value
+		if arg, ok := strings.CutPrefix(cmd, "set "); ok {
+			if action := parseSetCommand(strings.TrimSpace(arg)); action != nil {
+				return action
+			}
+		}
+
 		// :s/pattern/replacement/ - substitute (simplified)
 		if cmd[0] == 's' && len(cmd) > 1 && cmd[1] == '/' {
 			return &Action{
@@ -605,3 +613,34 @@ func (m *ReplaceMode) HandleUnmapped(event key.Event, ctx *Context) *UnmappedRes
 
 	return &UnmappedResult{Consumed: false}
 }
+
+// parseSetCommand parses the argument of a :set command. "path=value" sets
+// a setting, "path" enables it, "nopath" disables it and "path!" toggles it.
+// It returns nil for an empty argument.
+func parseSetCommand(arg string) *Action {
+	if arg == "" {
+		return nil
+	}
+	if path, value, ok := strings.Cut(arg, "="); ok {
+		return &Action{
+			Name: "settings.set",
+			Args: map[string]any{"path": path, "value": value},
+		}
+	}
+	if path, ok := strings.CutSuffix(arg, "!"); ok {
+		return &Action{
+			Name: "settings.toggle",
+			Args: map[string]any{"path": path},
+		}
+	}
+	if path, ok := strings.CutPrefix(arg, "no"); ok && path != "" {
+		return &Action{
+			Name: "settings.set",
+			Args: map[string]any{"path": path, "value": false},
+		}
+	}
+	return &Action{
+		Name: "settings.set",
+		Args: map[string]any{"path": arg, "value": true},
+	}
+}
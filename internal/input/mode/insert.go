@@ -14,6 +14,26 @@ type InsertMode struct {
 
 	// insertStart records where insert mode began (for undo grouping).
 	insertStart Position
+
+	// blockInsert is the pending visual-block insert, if any.
+	blockInsert *BlockInsert
+}
+
+// BlockInsert describes a visual-block insert (I or A in visual block
+// mode): the text typed on StartLine at Column is replicated on every
+// other line of the block when insert mode ends.
+type BlockInsert struct {
+	// StartLine is the top line of the block, where text is typed.
+	StartLine uint32
+
+	// EndLine is the bottom line of the block (inclusive).
+	EndLine uint32
+
+	// Column is the character column where the text is inserted.
+	Column uint32
+
+	// Append is true for block append, which pads short lines with spaces.
+	Append bool
 }
 
 // NewInsertMode creates a new insert mode instance.
@@ -39,6 +59,7 @@ func (m *InsertMode) CursorStyle() CursorStyle {
 // Enter is called when entering insert mode.
 func (m *InsertMode) Enter(ctx *Context) error {
 	m.completionActive = false
+	m.blockInsert = nil
 
 	// Record where insert mode started
 	if ctx.Editor != nil {
@@ -53,6 +74,7 @@ func (m *InsertMode) Enter(ctx *Context) error {
 func (m *InsertMode) Exit(ctx *Context) error {
 	// Hide any active completion
 	m.completionActive = false
+	m.blockInsert = nil
 	return nil
 }
 
@@ -208,3 +230,23 @@ func (m *InsertMode) SetCompletionActive(active bool) {
 func (m *InsertMode) InsertStart() Position {
 	return m.insertStart
 }
+
+// SetBlockInsert records a visual-block insert to replicate when insert
+// mode ends. Entering or leaving insert mode clears it, so it must be set
+// after switching to insert mode.
+func (m *InsertMode) SetBlockInsert(b BlockInsert) {
+	m.blockInsert = &b
+}
+
+// BlockInsert returns the pending visual-block insert, if any.
+func (m *InsertMode) BlockInsert() (BlockInsert, bool) {
+	if m.blockInsert == nil {
+		return BlockInsert{}, false
+	}
+	return *m.blockInsert, true
+}
+
+// ClearBlockInsert discards the pending visual-block insert.
+func (m *InsertMode) ClearBlockInsert() {
+	m.blockInsert = nil
+}
@@ -303,6 +303,41 @@ func TestCommandModeBuffer(t *testing.T) {
 	}
 }
 
+func TestCommandModeSetCommand(t *testing.T) {
+	m := NewCommandMode()
+
+	tests := []struct {
+		cmd   string
+		name  string
+		path  string
+		value any
+	}{
+		{"set editor.tabSize=2", "settings.set", "editor.tabSize", "2"},
+		{"set editor.insertSpaces", "settings.set", "editor.insertSpaces", true},
+		{"set noeditor.insertSpaces", "settings.set", "editor.insertSpaces", false},
+		{"set editor.insertSpaces!", "settings.toggle", "editor.insertSpaces", nil},
+	}
+	for _, tt := range tests {
+		action := m.parseCommand(tt.cmd)
+		if action == nil {
+			t.Fatalf("parseCommand(%q) = nil", tt.cmd)
+		}
+		if action.Name != tt.name {
+			t.Errorf("parseCommand(%q).Name = %q, want %q", tt.cmd, action.Name, tt.name)
+		}
+		if action.Args["path"] != tt.path {
+			t.Errorf("parseCommand(%q) path = %v, want %q", tt.cmd, action.Args["path"], tt.path)
+		}
+		if action.Args["value"] != tt.value {
+			t.Errorf("parseCommand(%q) value = %v, want %v", tt.cmd, action.Args["value"], tt.value)
+		}
+	}
+
+	if action := m.parseCommand("set"); action == nil || action.Name != "settings.show" {
+		t.Errorf("parseCommand(%q) = %+v, want settings.show", "set", action)
+	}
+}
+
 func TestCommandModeHistory(t *testing.T) {
 	m := NewCommandMode()
 
@@ -54,6 +54,21 @@
 //   - Shift+scroll: Scroll by single line
 //   - Ctrl+scroll: Zoom in/out
 //
+// # Pointer Targets
+//
+// ResolveTarget reports the buffer offset, word and link under the pointer,
+// given a Context that maps screen positions to text. Ctrl/Cmd+click
+// resolves to a go-to-definition or link target, whose Action can be
+// dispatched:
+//
+//	target := handler.ResolveTarget(event, textContext)
+//	if action := target.Action(); action != nil {
+//	    dispatch(action) // e.g. lsp.gotoDefinition at target.Offset
+//	}
+//
+// Hover is debounced: ScheduleHover reports a hover target to the OnHover
+// function once the pointer has been still for Config.HoverDelay.
+//
 // # Thread Safety
 //
 // Handler is safe for concurrent use. All state mutations are properly
@@ -167,6 +167,10 @@ type Config struct {
 
 	// EnableZoom enables Ctrl+scroll zoom.
 	EnableZoom bool
+
+	// HoverDelay is how long the pointer must stay still before a hover
+	// is reported (see Handler.ScheduleHover). Zero disables hover.
+	HoverDelay time.Duration
 }
 
 // DefaultConfig returns sensible default configuration.
@@ -180,6 +184,7 @@ func DefaultConfig() Config {
 		EnableMiddleClickPaste: true,
 		EnableContextMenu:      true,
 		EnableZoom:             true,
+		HoverDelay:             500 * time.Millisecond,
 	}
 }
 
@@ -193,6 +198,11 @@ type Handler struct {
 
 	// Drag tracking
 	drag *dragTracker
+
+	// Hover debouncing
+	hoverFn    func(Target)
+	hoverTimer *time.Timer
+	hoverSeq   uint64
 }
 
 // NewHandler creates a new mouse handler with the given configuration.
@@ -209,6 +219,11 @@ func (h *Handler) Handle(event Event) *input.Action {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	// Any action by the user interrupts a pending hover
+	if event.Action != ActionMove {
+		h.cancelHoverLocked()
+	}
+
 	switch event.Action {
 	case ActionPress:
 		return h.handlePress(event)
@@ -487,6 +502,7 @@ func (h *Handler) Reset() {
 
 	h.click.reset()
 	h.drag.end()
+	h.cancelHoverLocked()
 }
 
 // IsDragging returns true if a drag operation is in progress.
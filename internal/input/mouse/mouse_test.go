@@ -842,3 +842,122 @@ func BenchmarkHandlerScroll(b *testing.B) {
 		handler.Handle(event)
 	}
 }
+
+// textContext maps screen cells to a buffer of lines, one cell per byte.
+type textContext struct {
+	lines []string
+}
+
+func (c *textContext) OffsetAt(pos Position) (int, bool) {
+	if pos.Y < 0 || pos.Y >= len(c.lines) || pos.X < 0 || pos.X > len(c.lines[pos.Y]) {
+		return 0, false
+	}
+	offset := 0
+	for _, line := range c.lines[:pos.Y] {
+		offset += len(line) + 1
+	}
+	return offset + pos.X, true
+}
+
+func (c *textContext) LineAt(offset int) (string, int) {
+	start := 0
+	for _, line := range c.lines {
+		if offset <= start+len(line) {
+			return line, start
+		}
+		start += len(line) + 1
+	}
+	return "", start
+}
+
+func TestResolveTargetCtrlClick(t *testing.T) {
+	h := NewHandler(DefaultConfig())
+	ctx := &textContext{lines: []string{"package main", "", "func main() { fmt.Println(x) }"}}
+
+	event := Event{
+		Position:  Position{X: 20, Y: 2},
+		Button:    ButtonLeft,
+		Modifiers: key.ModCtrl,
+		Action:    ActionPress,
+		Timestamp: time.Now(),
+	}
+	target := h.ResolveTarget(event, ctx)
+
+	if target.Kind != TargetDefinition {
+		t.Fatalf("Kind = %v, want definition", target.Kind)
+	}
+	if target.Offset != 34 {
+		t.Errorf("Offset = %d, want 34", target.Offset)
+	}
+	if target.Word != "Println" || target.WordStart != 32 || target.WordEnd != 39 {
+		t.Errorf("Word = %q [%d, %d), want Println [32, 39)", target.Word, target.WordStart, target.WordEnd)
+	}
+	if !target.Modifier {
+		t.Error("Modifier = false, want true")
+	}
+
+	action := target.Action()
+	if action == nil || action.Name != "lsp.gotoDefinition" || action.Args.GetInt("offset") != 34 {
+		t.Errorf("Action() = %+v, want lsp.gotoDefinition at 34", action)
+	}
+
+	// Without the modifier the click is plain text
+	event.Modifiers = key.ModNone
+	if target := h.ResolveTarget(event, ctx); target.Kind != TargetText || target.Action() != nil {
+		t.Errorf("plain click Kind = %v, want text with no action", target.Kind)
+	}
+}
+
+func TestResolveTargetLinkAndOutside(t *testing.T) {
+	h := NewHandler(DefaultConfig())
+	ctx := &textContext{lines: []string{"// See https://example.com/docs. Thanks"}}
+
+	target := h.ResolveTarget(Event{Position: Position{X: 12, Y: 0}, Button: ButtonLeft, Modifiers: key.ModMeta, Action: ActionPress}, ctx)
+	if target.Kind != TargetLink || target.Link != "https://example.com/docs" {
+		t.Errorf("link target = %+v, want link https://example.com/docs", target)
+	}
+
+	// Trailing punctuation is not part of the link
+	target = h.ResolveTarget(Event{Position: Position{X: 31, Y: 0}, Button: ButtonLeft, Modifiers: key.ModCtrl, Action: ActionPress}, ctx)
+	if target.Link != "" {
+		t.Errorf("Link = %q on trailing period, want empty", target.Link)
+	}
+
+	target = h.ResolveTarget(Event{Position: Position{X: 0, Y: 5}, Action: ActionMove}, ctx)
+	if target.Kind != TargetNone {
+		t.Errorf("Kind outside text = %v, want none", target.Kind)
+	}
+}
+
+func TestScheduleHover(t *testing.T) {
+	config := DefaultConfig()
+	config.HoverDelay = 10 * time.Millisecond
+	h := NewHandler(config)
+	ctx := &textContext{lines: []string{"hello world"}}
+
+	hovers := make(chan Target, 10)
+	h.OnHover(func(target Target) { hovers <- target })
+
+	// Moving again restarts the delay; only the last position is reported
+	h.ScheduleHover(Event{Position: Position{X: 1, Y: 0}, Action: ActionMove}, ctx)
+	h.ScheduleHover(Event{Position: Position{X: 8, Y: 0}, Action: ActionMove}, ctx)
+
+	select {
+	case target := <-hovers:
+		if target.Kind != TargetHover || target.Word != "world" || target.Offset != 8 {
+			t.Errorf("hover = %+v, want world at 8", target)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no hover reported")
+	}
+
+	// A click cancels a pending hover
+	h.ScheduleHover(Event{Position: Position{X: 1, Y: 0}, Action: ActionMove}, ctx)
+	h.Handle(Event{Position: Position{X: 1, Y: 0}, Button: ButtonLeft, Action: ActionPress, Timestamp: time.Now()})
+
+	select {
+	case target := <-hovers:
+		t.Errorf("hover after click = %+v, want none", target)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
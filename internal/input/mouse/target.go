@@ -0,0 +1,256 @@
+package mouse
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dshills/keystorm/internal/input"
+)
+
+// Context maps screen positions to buffer text for ResolveTarget.
+type Context interface {
+	// OffsetAt returns the buffer byte offset under a screen position, or
+	// false if no text is under it.
+	OffsetAt(pos Position) (offset int, ok bool)
+
+	// LineAt returns the text of the line containing offset, without its
+	// line ending, and the offset at which the line starts.
+	LineAt(offset int) (text string, start int)
+}
+
+// TargetKind is what a pointer event over the text should trigger.
+type TargetKind uint8
+
+const (
+	// TargetNone means no text is under the pointer.
+	TargetNone TargetKind = iota
+	// TargetText is text with no special action.
+	TargetText
+	// TargetHover requests hover information for the word under a
+	// stationary pointer.
+	TargetHover
+	// TargetDefinition requests going to the definition of the word under
+	// a Ctrl/Cmd+click.
+	TargetDefinition
+	// TargetLink requests opening the link under a Ctrl/Cmd+click.
+	TargetLink
+)
+
+// String returns a human-readable name for the target kind.
+func (k TargetKind) String() string {
+	switch k {
+	case TargetText:
+		return "text"
+	case TargetHover:
+		return "hover"
+	case TargetDefinition:
+		return "definition"
+	case TargetLink:
+		return "link"
+	default:
+		return "none"
+	}
+}
+
+// Target describes the text under the pointer.
+type Target struct {
+	// Kind is what the event should trigger.
+	Kind TargetKind
+
+	// Position is the screen position of the event.
+	Position Position
+
+	// Offset is the buffer byte offset under the pointer. It is only
+	// meaningful if Kind is not TargetNone.
+	Offset int
+
+	// Word is the identifier under the pointer, spanning the byte offsets
+	// [WordStart, WordEnd). Empty if the pointer is not over a word.
+	Word      string
+	WordStart int
+	WordEnd   int
+
+	// Link is the URL under the pointer, if any.
+	Link string
+
+	// Modifier is true if Ctrl or Cmd was held.
+	Modifier bool
+}
+
+// Action returns the editor action for the target, or nil if it has none.
+// Hover and definition targets map to the LSP actions at the target's
+// offset; link targets open the link.
+func (t Target) Action() *input.Action {
+	var name string
+	switch t.Kind {
+	case TargetHover:
+		name = "lsp.hover"
+	case TargetDefinition:
+		name = "lsp.gotoDefinition"
+	case TargetLink:
+		return &input.Action{
+			Name:   "editor.openLink",
+			Source: input.SourceMouse,
+			Args: input.ActionArgs{
+				Extra: map[string]interface{}{
+					"url": t.Link,
+				},
+			},
+		}
+	default:
+		return nil
+	}
+	return &input.Action{
+		Name:   name,
+		Source: input.SourceMouse,
+		Args: input.ActionArgs{
+			Extra: map[string]interface{}{
+				"offset": t.Offset,
+				"word":   t.Word,
+				"x":      t.Position.X,
+				"y":      t.Position.Y,
+			},
+		},
+	}
+}
+
+// linkPattern matches URLs in text.
+var linkPattern = regexp.MustCompile(`(?:https?|file)://[^\s<>"'` + "`" + `()\[\]{}]+`)
+
+// ResolveTarget reports what is under the pointer for an event: the buffer
+// offset, the word and link there, and whether Ctrl or Cmd is held.
+//
+// A left press with Ctrl or Cmd resolves to TargetLink over a link and to
+// TargetDefinition over a word. Movement without a button resolves to
+// TargetHover over a word or link. ResolveTarget does not change the
+// handler's click or drag state.
+func (h *Handler) ResolveTarget(event Event, ctx Context) Target {
+	target := Target{
+		Position: event.Position,
+		Modifier: event.Modifiers.HasCtrl() || event.Modifiers.HasMeta(),
+	}
+	if ctx == nil {
+		return target
+	}
+
+	offset, ok := ctx.OffsetAt(event.Position)
+	if !ok {
+		return target
+	}
+	target.Kind = TargetText
+	target.Offset = offset
+
+	text, start := ctx.LineAt(offset)
+	col := offset - start
+	if col < 0 || col > len(text) {
+		return target
+	}
+
+	if ws, we := wordBounds(text, col); ws < we {
+		target.Word = text[ws:we]
+		target.WordStart = start + ws
+		target.WordEnd = start + we
+	}
+	for _, loc := range linkPattern.FindAllStringIndex(text, -1) {
+		end := loc[0] + len(strings.TrimRight(text[loc[0]:loc[1]], ".,;:!?"))
+		if col >= loc[0] && col < end {
+			target.Link = text[loc[0]:end]
+			break
+		}
+	}
+
+	switch {
+	case event.Action == ActionPress && event.Button == ButtonLeft && target.Modifier:
+		if target.Link != "" {
+			target.Kind = TargetLink
+		} else if target.Word != "" {
+			target.Kind = TargetDefinition
+		}
+	case event.Action == ActionMove:
+		if target.Word != "" || target.Link != "" {
+			target.Kind = TargetHover
+		}
+	}
+	return target
+}
+
+// wordBounds returns the byte range of the identifier containing or
+// starting at col in text, or an empty range.
+func wordBounds(text string, col int) (start, end int) {
+	start, end = col, col
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:start])
+		if !isWordRune(r) {
+			break
+		}
+		start -= size
+	}
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(r) {
+			break
+		}
+		end += size
+	}
+	// The pointer must be over the word, not just after it
+	if end == col {
+		return col, col
+	}
+	return start, end
+}
+
+// isWordRune returns true if r is part of an identifier.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// OnHover sets the function called with a TargetHover when the pointer
+// stays over a word or link for Config.HoverDelay. See ScheduleHover.
+func (h *Handler) OnHover(fn func(Target)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hoverFn = fn
+}
+
+// ScheduleHover starts the hover delay for a movement event. If no other
+// event reaches ScheduleHover or Handle before the delay passes, the target
+// under the pointer is resolved and, if it is a hover target, passed to
+// the OnHover function. Events other than movement cancel a pending hover.
+// A HoverDelay of zero disables hover.
+func (h *Handler) ScheduleHover(event Event, ctx Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.cancelHoverLocked()
+	if event.Action != ActionMove || h.config.HoverDelay <= 0 || h.hoverFn == nil {
+		return
+	}
+
+	seq := h.hoverSeq
+	h.hoverTimer = time.AfterFunc(h.config.HoverDelay, func() {
+		h.mu.Lock()
+		fn := h.hoverFn
+		current := seq == h.hoverSeq
+		h.mu.Unlock()
+		if !current || fn == nil {
+			return
+		}
+
+		if target := h.ResolveTarget(event, ctx); target.Kind == TargetHover {
+			fn(target)
+		}
+	})
+}
+
+// cancelHoverLocked stops a pending hover.
+// Caller must hold the lock.
+func (h *Handler) cancelHoverLocked() {
+	h.hoverSeq++
+	if h.hoverTimer != nil {
+		h.hoverTimer.Stop()
+		h.hoverTimer = nil
+	}
+}
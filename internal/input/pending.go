@@ -0,0 +1,107 @@
+package input
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dshills/keystorm/internal/input/key"
+	"github.com/dshills/keystorm/internal/input/mode"
+)
+
+// PendingState describes a partially typed command, as shown by Vim's
+// showcmd option.
+type PendingState struct {
+	// Count is the count typed before the command, or 0 if none.
+	Count int
+
+	// Register is the selected register, or 0 if none.
+	Register rune
+
+	// Operator is the pending operator action (e.g., "operator.delete").
+	Operator string
+
+	// Keys is the partial key sequence typed after the operator, if any.
+	Keys string
+
+	// Display is the pending command as typed (e.g., `"a3d`).
+	Display string
+}
+
+// IsEmpty returns true if no command is pending.
+func (s PendingState) IsEmpty() bool {
+	return s.Display == ""
+}
+
+// PendingState returns the partially typed command: the pending count,
+// register and operator, and the keys typed so far. It reflects the state
+// as keys arrive and is empty once the command completes, is cancelled
+// with Escape or times out.
+func (h *Handler) PendingState() PendingState {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	state := PendingState{
+		Count:    h.context.PendingCount,
+		Register: h.context.PendingRegister,
+		Operator: h.context.PendingOperator,
+	}
+	if h.context.PendingSequence != nil {
+		state.Keys = h.context.PendingSequence.VimString()
+	}
+
+	var b strings.Builder
+	if state.Register != 0 {
+		b.WriteByte('"')
+		b.WriteRune(state.Register)
+	}
+	if state.Count > 0 {
+		b.WriteString(strconv.Itoa(state.Count))
+	}
+	b.WriteString(h.operatorKeysLocked())
+	b.WriteString(state.Keys)
+	state.Display = b.String()
+	return state
+}
+
+// operatorKeysLocked returns the normal mode keys bound to the pending
+// operator, or "" if none is pending. Caller must hold the lock.
+func (h *Handler) operatorKeysLocked() string {
+	op := h.context.PendingOperator
+	if op == "" {
+		return ""
+	}
+	for _, match := range h.keymapRegistry.AllBindings(mode.ModeNormal) {
+		if match.Action == op && match.Sequence != nil {
+			return match.Sequence.VimString()
+		}
+	}
+	return op
+}
+
+// cancelPendingLocked cancels a pending count, register or operator when
+// event is Escape, without dispatching an action. It returns true if
+// Escape was consumed; outside normal mode without a pending operator it
+// is then handled as usual, so that it still leaves visual mode. Caller
+// must hold the lock.
+func (h *Handler) cancelPendingLocked(event key.Event) bool {
+	if !event.IsEscape() || !h.hasPendingCommandLocked() {
+		return false
+	}
+	consume := h.context.Mode == mode.ModeNormal || h.context.PendingOperator != ""
+	h.clearPendingLocked()
+	return consume
+}
+
+// hasPendingCommandLocked returns true if a count, register or operator is
+// pending. Caller must hold the lock.
+func (h *Handler) hasPendingCommandLocked() bool {
+	return h.context.PendingCount > 0 || h.context.PendingRegister != 0 ||
+		h.context.PendingOperator != ""
+}
+
+// clearPendingLocked clears the pending command and key sequence. Caller
+// must hold the lock.
+func (h *Handler) clearPendingLocked() {
+	h.context.ClearPending()
+	h.stopSequenceTimeout()
+}
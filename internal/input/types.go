@@ -56,6 +56,9 @@ const (
 	SourcePlugin
 	// SourceAPI indicates the action originated from an API call.
 	SourceAPI
+	// SourceTerminal indicates the action originated from a terminal
+	// event other than a key press, such as a focus change.
+	SourceTerminal
 )
 
 // String returns a string representation of the action source.
@@ -73,6 +76,8 @@ func (s ActionSource) String() string {
 		return "plugin"
 	case SourceAPI:
 		return "api"
+	case SourceTerminal:
+		return "terminal"
 	default:
 		return "unknown"
 	}
@@ -82,6 +82,15 @@ var (
 		RequiresDelimiter: false,
 	}
 
+	// Indentation text object
+	TextObjIndent = TextObject{
+		Name:              "indent",
+		Key:               'i',
+		InnerAction:       "select.innerIndent",
+		AroundAction:      "select.aroundIndent",
+		RequiresDelimiter: false,
+	}
+
 	// Delimiter-based text objects
 	TextObjParen = TextObject{
 		Name:              "paren",
@@ -182,6 +191,7 @@ var textObjects = map[rune]*TextObject{
 	'b':  &TextObjBlock,
 	'B':  &TextObjBigBlock,
 	't':  &TextObjTag,
+	'i':  &TextObjIndent,
 	'(':  &TextObjParen,
 	')':  &TextObjParenClose,
 	'[':  &TextObjBracket,
@@ -756,7 +756,7 @@ func (c *Client) SetVariable(ctx context.Context, args SetVariableArguments) (*S
 	}
 
 	if !resp.Success {
-		return nil, fmt.Errorf("setVariable failed: %s", resp.Message)
+		return nil, newRequestError(resp)
 	}
 
 	var body SetVariableResponseBody
@@ -767,6 +767,25 @@ func (c *Client) SetVariable(ctx context.Context, args SetVariableArguments) (*S
 	return &body, nil
 }
 
+// SetExpression sends the setExpression request.
+func (c *Client) SetExpression(ctx context.Context, args SetExpressionArguments) (*SetExpressionResponseBody, error) {
+	resp, err := c.sendRequest(ctx, "setExpression", args)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, newRequestError(resp)
+	}
+
+	var body SetExpressionResponseBody
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		return nil, fmt.Errorf("unmarshal setExpression: %w", err)
+	}
+
+	return &body, nil
+}
+
 // Evaluate sends the evaluate request.
 func (c *Client) Evaluate(ctx context.Context, args EvaluateArguments) (*EvaluateResponseBody, error) {
 	resp, err := c.sendRequest(ctx, "evaluate", args)
@@ -2,6 +2,7 @@ package dap
 
 import (
 	"encoding/json"
+	"strings"
 )
 
 // ProtocolMessage is the base for all DAP messages.
@@ -49,6 +50,46 @@ type ErrorMessage struct {
 	Variables map[string]string `json:"variables,omitempty"`
 }
 
+// RequestError is returned when the adapter rejects a request, e.g. a
+// setVariable whose value does not match the variable's type.
+type RequestError struct {
+	// Command is the rejected request's command.
+	Command string
+
+	// Message is the short error message of the response.
+	Message string
+
+	// Detail is the error message from the response body, with its
+	// variables substituted, if the adapter sent one.
+	Detail string
+}
+
+// Error implements the error interface.
+func (e *RequestError) Error() string {
+	msg := e.Command + " failed: " + e.Message
+	if e.Detail != "" && e.Detail != e.Message {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// newRequestError builds a RequestError from a failed response.
+func newRequestError(resp *Response) *RequestError {
+	err := &RequestError{Command: resp.Command, Message: resp.Message}
+
+	var body struct {
+		Error *ErrorMessage `json:"error,omitempty"`
+	}
+	if json.Unmarshal(resp.Body, &body) == nil && body.Error != nil {
+		detail := body.Error.Format
+		for name, value := range body.Error.Variables {
+			detail = strings.ReplaceAll(detail, "{"+name+"}", value)
+		}
+		err.Detail = detail
+	}
+	return err
+}
+
 // Capabilities describes what features the debug adapter supports.
 type Capabilities struct {
 	SupportsConfigurationDoneRequest      bool `json:"supportsConfigurationDoneRequest,omitempty"`
@@ -500,6 +541,23 @@ type SetVariableResponseBody struct {
 	IndexedVariables   int    `json:"indexedVariables,omitempty"`
 }
 
+// SetExpressionArguments are the arguments for setExpression.
+type SetExpressionArguments struct {
+	Expression string `json:"expression"`
+	Value      string `json:"value"`
+	FrameID    int    `json:"frameId,omitempty"`
+}
+
+// SetExpressionResponseBody is the response body for setExpression.
+type SetExpressionResponseBody struct {
+	Value              string                    `json:"value"`
+	Type               string                    `json:"type,omitempty"`
+	PresentationHint   *VariablePresentationHint `json:"presentationHint,omitempty"`
+	VariablesReference int                       `json:"variablesReference,omitempty"`
+	NamedVariables     int                       `json:"namedVariables,omitempty"`
+	IndexedVariables   int                       `json:"indexedVariables,omitempty"`
+}
+
 // SourceArguments are the arguments for source.
 type SourceArguments struct {
 	Source          *Source `json:"source,omitempty"`
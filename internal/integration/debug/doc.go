@@ -50,6 +50,20 @@
 //   - Watch expressions
 //   - Arbitrary expression evaluation
 //
+// Values can be changed with SetVariable, and assignable expressions with
+// SetExpression, if the adapter supports them. Both return the new value as
+// the adapter formats it; a value the adapter rejects, such as one of the
+// wrong type, is reported as a *ValueError.
+//
+// # Threads
+//
+// Sessions track which threads are stopped, honoring the adapter's
+// allThreadsStopped flag. The active thread follows each stop event and
+// can be switched with SetActiveThread to inspect another goroutine;
+// stack traces, EvaluateInThread and VariablesInThread given a thread ID
+// of 0 use the active thread. ThreadStates reports each thread's state.
+// Stack frames are loaded lazily in windows via startFrame and levels.
+//
 // # Usage
 //
 // Create and start a debug session:
@@ -0,0 +1,59 @@
+package debug
+
+import (
+	"errors"
+
+	"github.com/dshills/keystorm/internal/integration/debug/dap"
+)
+
+// Sentinel errors for debug sessions.
+var (
+	// ErrSetVariableNotSupported is returned when the adapter does not
+	// support the setVariable request.
+	ErrSetVariableNotSupported = errors.New("debug adapter does not support setting variables")
+
+	// ErrSetExpressionNotSupported is returned when the adapter does not
+	// support the setExpression request.
+	ErrSetExpressionNotSupported = errors.New("debug adapter does not support setting expressions")
+)
+
+// ValueError is returned when the adapter rejects a new value, typically
+// because it does not parse or does not match the variable's type. The
+// Reason is the adapter's explanation, suitable for display.
+type ValueError struct {
+	// Name is the variable name or expression being set.
+	Name string
+
+	// Value is the rejected value.
+	Value string
+
+	// Reason is the adapter's error message.
+	Reason string
+
+	// Err is the underlying request error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ValueError) Error() string {
+	return "cannot set " + e.Name + " to " + e.Value + ": " + e.Reason
+}
+
+// Unwrap returns the underlying error.
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// valueError converts an adapter rejection into a ValueError. Other errors,
+// such as transport failures, are returned unchanged.
+func valueError(name, value string, err error) error {
+	var reqErr *dap.RequestError
+	if !errors.As(err, &reqErr) {
+		return err
+	}
+	reason := reqErr.Detail
+	if reason == "" {
+		reason = reqErr.Message
+	}
+	return &ValueError{Name: name, Value: value, Reason: reason, Err: err}
+}
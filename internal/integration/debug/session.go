@@ -62,9 +62,12 @@ type Session struct {
 	// Current thread ID (when stopped)
 	currentThread int
 
-	// All threads
-	threads   []dap.Thread
-	threadsMu sync.RWMutex
+	// All threads and their stop state
+	threads        []dap.Thread
+	activeThread   int
+	stoppedThreads map[int]bool
+	allStopped     bool
+	threadsMu      sync.RWMutex
 
 	// Breakpoints by source path
 	breakpoints   map[string][]dap.Breakpoint
@@ -135,9 +138,10 @@ func DefaultSessionConfig() SessionConfig {
 // NewSession creates a new debug session with the given client.
 func NewSession(client *dap.Client) *Session {
 	s := &Session{
-		client:      client,
-		state:       StateConnected,
-		breakpoints: make(map[string][]dap.Breakpoint),
+		client:         client,
+		state:          StateConnected,
+		stoppedThreads: make(map[int]bool),
+		breakpoints:    make(map[string][]dap.Breakpoint),
 	}
 
 	// Set up event handlers
@@ -214,20 +218,14 @@ func (s *Session) Capabilities() *dap.Capabilities {
 	return s.capabilities
 }
 
-// CurrentThread returns the current thread ID.
+// CurrentThread returns the ID of the thread that caused the last stop.
+// Use ActiveThread for the thread the user is inspecting.
 func (s *Session) CurrentThread() int {
 	s.stateMu.RLock()
 	defer s.stateMu.RUnlock()
 	return s.currentThread
 }
 
-// Threads returns the current list of threads.
-func (s *Session) Threads() []dap.Thread {
-	s.threadsMu.RLock()
-	defer s.threadsMu.RUnlock()
-	return append([]dap.Thread{}, s.threads...)
-}
-
 // Initialize initializes the debug session.
 func (s *Session) Initialize(ctx context.Context, config SessionConfig) error {
 	args := dap.InitializeRequestArguments{
@@ -385,10 +383,12 @@ func (s *Session) Continue(ctx context.Context, threadID int) error {
 		ThreadID: threadID,
 	}
 
-	if _, err := s.client.Continue(ctx, args); err != nil {
+	result, err := s.client.Continue(ctx, args)
+	if err != nil {
 		return err
 	}
 
+	s.markContinued(threadID, result.AllThreadsContinued)
 	s.setState(StateRunning)
 	return nil
 }
@@ -403,6 +403,7 @@ func (s *Session) Next(ctx context.Context, threadID int) error {
 		return err
 	}
 
+	s.markContinued(threadID, !args.SingleThread)
 	s.setState(StateRunning)
 	return nil
 }
@@ -417,6 +418,7 @@ func (s *Session) StepIn(ctx context.Context, threadID int) error {
 		return err
 	}
 
+	s.markContinued(threadID, !args.SingleThread)
 	s.setState(StateRunning)
 	return nil
 }
@@ -431,6 +433,7 @@ func (s *Session) StepOut(ctx context.Context, threadID int) error {
 		return err
 	}
 
+	s.markContinued(threadID, !args.SingleThread)
 	s.setState(StateRunning)
 	return nil
 }
@@ -444,22 +447,15 @@ func (s *Session) Pause(ctx context.Context, threadID int) error {
 	return s.client.Pause(ctx, args)
 }
 
-// GetThreads retrieves the current threads.
-func (s *Session) GetThreads(ctx context.Context) ([]dap.Thread, error) {
-	threads, err := s.client.Threads(ctx)
-	if err != nil {
-		return nil, err
+// GetStackTrace retrieves the stack trace for a thread. A threadID of 0
+// selects the active thread. Frames are loaded lazily: startFrame and
+// levels select a window of the stack (levels 0 means all remaining frames)
+// and the returned total is the full stack depth, if the adapter reports it.
+func (s *Session) GetStackTrace(ctx context.Context, threadID int, startFrame, levels int) ([]dap.StackFrame, int, error) {
+	if threadID == 0 {
+		threadID = s.ActiveThread()
 	}
 
-	s.threadsMu.Lock()
-	s.threads = threads
-	s.threadsMu.Unlock()
-
-	return threads, nil
-}
-
-// GetStackTrace retrieves the stack trace for a thread.
-func (s *Session) GetStackTrace(ctx context.Context, threadID int, startFrame, levels int) ([]dap.StackFrame, int, error) {
 	args := dap.StackTraceArguments{
 		ThreadID:   threadID,
 		StartFrame: startFrame,
@@ -492,8 +488,15 @@ func (s *Session) GetVariables(ctx context.Context, variablesRef int) ([]dap.Var
 	return s.client.Variables(ctx, args)
 }
 
-// SetVariable sets a variable value.
-func (s *Session) SetVariable(ctx context.Context, variablesRef int, name, value string) (string, error) {
+// SetVariable sets the value of the variable name in the scope or
+// structured variable variablesRef, and returns its new value as the
+// adapter reports it. Returns ErrSetVariableNotSupported if the adapter
+// lacks supportsSetVariable, and a *ValueError if it rejects the value.
+func (s *Session) SetVariable(ctx context.Context, variablesRef int, name, value string) (VariableValue, error) {
+	if caps := s.Capabilities(); caps == nil || !caps.SupportsSetVariable {
+		return VariableValue{}, ErrSetVariableNotSupported
+	}
+
 	args := dap.SetVariableArguments{
 		VariablesReference: variablesRef,
 		Name:               name,
@@ -502,10 +505,45 @@ func (s *Session) SetVariable(ctx context.Context, variablesRef int, name, value
 
 	result, err := s.client.SetVariable(ctx, args)
 	if err != nil {
-		return "", err
+		return VariableValue{}, valueError(name, value, err)
+	}
+
+	return VariableValue{
+		Value:              result.Value,
+		Type:               result.Type,
+		VariablesReference: result.VariablesReference,
+		NamedVariables:     result.NamedVariables,
+		IndexedVariables:   result.IndexedVariables,
+	}, nil
+}
+
+// SetExpression assigns value to an assignable expression, such as a field
+// or an element of a slice, evaluated in the given stack frame. Returns
+// ErrSetExpressionNotSupported if the adapter lacks supportsSetExpression,
+// and a *ValueError if it rejects the value.
+func (s *Session) SetExpression(ctx context.Context, expression, value string, frameID int) (VariableValue, error) {
+	if caps := s.Capabilities(); caps == nil || !caps.SupportsSetExpression {
+		return VariableValue{}, ErrSetExpressionNotSupported
+	}
+
+	args := dap.SetExpressionArguments{
+		Expression: expression,
+		Value:      value,
+		FrameID:    frameID,
+	}
+
+	result, err := s.client.SetExpression(ctx, args)
+	if err != nil {
+		return VariableValue{}, valueError(expression, value, err)
 	}
 
-	return result.Value, nil
+	return VariableValue{
+		Value:              result.Value,
+		Type:               result.Type,
+		VariablesReference: result.VariablesReference,
+		NamedVariables:     result.NamedVariables,
+		IndexedVariables:   result.IndexedVariables,
+	}, nil
 }
 
 // Evaluate evaluates an expression.
@@ -531,6 +569,8 @@ func (s *Session) onStopped(body dap.StoppedEventBody) {
 	s.currentThread = body.ThreadID
 	s.stateMu.Unlock()
 
+	s.markStopped(body)
+
 	// Use setState to properly notify state change handlers
 	s.setState(StateStopped)
 
@@ -544,6 +584,7 @@ func (s *Session) onStopped(body dap.StoppedEventBody) {
 }
 
 func (s *Session) onContinued(body dap.ContinuedEventBody) {
+	s.markContinued(body.ThreadID, body.AllThreadsContinued)
 	s.setState(StateRunning)
 }
 
@@ -564,6 +605,10 @@ func (s *Session) onTerminated(body dap.TerminatedEventBody) {
 }
 
 func (s *Session) onThread(body dap.ThreadEventBody) {
+	if body.Reason == "exited" {
+		s.removeThread(body.ThreadID)
+	}
+
 	s.handlersMu.RLock()
 	handler := s.handlers.OnThreadChanged
 	s.handlersMu.RUnlock()
@@ -3,7 +3,9 @@ package debug
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -576,6 +578,108 @@ func TestSessionEvaluate(t *testing.T) {
 	}
 }
 
+// newIntVariableAdapter returns a mock adapter with a single int local "x"
+// in scope 1, supporting variables, setVariable and setExpression.
+func newIntVariableAdapter(x *int) *mockTransport {
+	mt := newMockTransport()
+	mt.onSend = func(msg *dap.Message) {
+		var req dap.Request
+		json.Unmarshal(msg.Content, &req)
+
+		resp := dap.Response{
+			ProtocolMessage: dap.ProtocolMessage{Seq: 1, Type: "response"},
+			RequestSeq:      req.Seq,
+			Success:         true,
+			Command:         req.Command,
+		}
+
+		// set assigns an int value, rejecting anything else like an adapter
+		// for a typed language would
+		set := func(value string) {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				resp.Success = false
+				resp.Message = "type mismatch"
+				resp.Body, _ = json.Marshal(map[string]any{
+					"error": dap.ErrorMessage{
+						ID:        1,
+						Format:    "cannot use {value} as int",
+						Variables: map[string]string{"value": value},
+					},
+				})
+				return
+			}
+			*x = n
+			resp.Body, _ = json.Marshal(dap.SetVariableResponseBody{Value: strconv.Itoa(n), Type: "int"})
+		}
+
+		switch req.Command {
+		case "variables":
+			resp.Body, _ = json.Marshal(dap.VariablesResponseBody{
+				Variables: []dap.Variable{{Name: "x", Value: strconv.Itoa(*x), Type: "int"}},
+			})
+		case "setVariable":
+			var args dap.SetVariableArguments
+			json.Unmarshal(req.Arguments, &args)
+			set(args.Value)
+		case "setExpression":
+			var args dap.SetExpressionArguments
+			json.Unmarshal(req.Arguments, &args)
+			set(args.Value)
+		}
+
+		content, _ := json.Marshal(resp)
+		mt.queueResponse(&dap.Message{ContentLength: len(content), Content: content})
+	}
+	return mt
+}
+
+func TestSessionSetVariable(t *testing.T) {
+	x := 42
+	client := dap.NewClient(newIntVariableAdapter(&x))
+	session := NewSession(client)
+	defer session.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Without the capability the request is not sent
+	if _, err := session.SetVariable(ctx, 1, "x", "7"); !errors.Is(err, ErrSetVariableNotSupported) {
+		t.Fatalf("SetVariable without capability: err = %v, want ErrSetVariableNotSupported", err)
+	}
+
+	session.capabilities = &dap.Capabilities{SupportsSetVariable: true, SupportsSetExpression: true}
+
+	value, err := session.SetVariable(ctx, 1, "x", "7")
+	if err != nil {
+		t.Fatalf("SetVariable: %v", err)
+	}
+	if value.Value != "7" || value.Type != "int" || x != 7 {
+		t.Errorf("SetVariable = %+v (x = %d), want 7 of type int", value, x)
+	}
+
+	// A type mismatch is reported with the adapter's reason
+	_, err = session.SetVariable(ctx, 1, "x", "\"seven\"")
+	var valueErr *ValueError
+	if !errors.As(err, &valueErr) {
+		t.Fatalf("SetVariable with string: err = %v, want *ValueError", err)
+	}
+	if valueErr.Reason != "cannot use \"seven\" as int" {
+		t.Errorf("Reason = %q", valueErr.Reason)
+	}
+	if x != 7 {
+		t.Errorf("x = %d after rejected set, want 7", x)
+	}
+
+	value, err = session.SetExpression(ctx, "x", "9", 1000)
+	if err != nil {
+		t.Fatalf("SetExpression: %v", err)
+	}
+	if value.Value != "9" || x != 9 {
+		t.Errorf("SetExpression = %+v (x = %d), want 9", value, x)
+	}
+}
+
 func TestSessionDisconnect(t *testing.T) {
 	mt := newMockTransport()
 
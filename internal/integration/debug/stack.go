@@ -150,8 +150,14 @@ func (n *StackNavigator) SetVariableInspector(vi *VariableInspector) {
 	n.mu.Unlock()
 }
 
-// GetCallStack retrieves the call stack for a thread.
+// GetCallStack retrieves the call stack for a thread. A threadID of 0
+// selects the session's active thread. Only the first frames are loaded;
+// use FetchMoreFrames to load the rest.
 func (n *StackNavigator) GetCallStack(ctx context.Context, threadID int) (*CallStack, error) {
+	if threadID == 0 {
+		threadID = n.session.ActiveThread()
+	}
+
 	frames, totalFrames, err := n.session.GetStackTrace(ctx, threadID, 0, n.maxFramesPerRequest)
 	if err != nil {
 		return nil, fmt.Errorf("get stack trace: %w", err)
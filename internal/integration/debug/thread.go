@@ -0,0 +1,192 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dshills/keystorm/internal/integration/debug/dap"
+)
+
+// Thread represents a debuggee thread (a goroutine, for Go programs).
+type Thread struct {
+	// ID is the adapter's thread identifier.
+	ID int
+
+	// Name is the display name of the thread.
+	Name string
+
+	// Stopped indicates the thread is currently stopped.
+	Stopped bool
+
+	// Active indicates this is the thread being inspected.
+	Active bool
+}
+
+// GetThreads retrieves the current threads from the debug adapter.
+// Use ThreadStates for their stop state afterwards.
+func (s *Session) GetThreads(ctx context.Context) ([]dap.Thread, error) {
+	threads, err := s.client.Threads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.threadsMu.Lock()
+	s.threads = threads
+	if s.allStopped {
+		for _, t := range threads {
+			s.stoppedThreads[t.ID] = true
+		}
+	}
+	s.threadsMu.Unlock()
+
+	return threads, nil
+}
+
+// Threads returns the current list of threads.
+func (s *Session) Threads() []dap.Thread {
+	s.threadsMu.RLock()
+	defer s.threadsMu.RUnlock()
+	return append([]dap.Thread{}, s.threads...)
+}
+
+// ThreadStates returns the threads from the last GetThreads call with
+// their stop state and which one is active.
+func (s *Session) ThreadStates() []Thread {
+	s.threadsMu.RLock()
+	defer s.threadsMu.RUnlock()
+
+	result := make([]Thread, len(s.threads))
+	for i, t := range s.threads {
+		result[i] = Thread{
+			ID:      t.ID,
+			Name:    t.Name,
+			Stopped: s.stoppedThreads[t.ID],
+			Active:  t.ID == s.activeThread,
+		}
+	}
+	return result
+}
+
+// ActiveThread returns the ID of the thread being inspected. It follows
+// the thread of each stop event until changed with SetActiveThread, and
+// is the default for thread-scoped requests given a thread ID of 0.
+func (s *Session) ActiveThread() int {
+	s.threadsMu.RLock()
+	defer s.threadsMu.RUnlock()
+	return s.activeThread
+}
+
+// SetActiveThread switches the thread being inspected. The thread must be
+// among those returned by the last GetThreads call.
+func (s *Session) SetActiveThread(threadID int) error {
+	s.threadsMu.Lock()
+	defer s.threadsMu.Unlock()
+
+	for _, t := range s.threads {
+		if t.ID == threadID {
+			s.activeThread = threadID
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown thread %d", threadID)
+}
+
+// IsThreadStopped reports whether the given thread is stopped.
+func (s *Session) IsThreadStopped(threadID int) bool {
+	s.threadsMu.RLock()
+	defer s.threadsMu.RUnlock()
+	return s.stoppedThreads[threadID]
+}
+
+// EvaluateInThread evaluates an expression in the top stack frame of a
+// thread. A threadID of 0 selects the active thread.
+func (s *Session) EvaluateInThread(ctx context.Context, expression string, threadID int, context string) (*dap.EvaluateResponseBody, error) {
+	frames, _, err := s.GetStackTrace(ctx, threadID, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("thread %d has no stack frames", threadID)
+	}
+
+	return s.Evaluate(ctx, expression, frames[0].ID, context)
+}
+
+// VariablesInThread returns the variables of every scope of the top stack
+// frame of a thread. A threadID of 0 selects the active thread. Scopes
+// whose variables cannot be fetched are skipped.
+func (s *Session) VariablesInThread(ctx context.Context, threadID int) ([]dap.Variable, error) {
+	frames, _, err := s.GetStackTrace(ctx, threadID, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(frames) == 0 {
+		return nil, nil
+	}
+
+	scopes, err := s.GetScopes(ctx, frames[0].ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dap.Variable
+	for _, scope := range scopes {
+		vars, err := s.GetVariables(ctx, scope.VariablesReference)
+		if err != nil {
+			continue
+		}
+		result = append(result, vars...)
+	}
+	return result, nil
+}
+
+// markStopped records the threads stopped by a stop event.
+func (s *Session) markStopped(body dap.StoppedEventBody) {
+	s.threadsMu.Lock()
+	defer s.threadsMu.Unlock()
+
+	if body.AllThreadsStopped {
+		s.allStopped = true
+		for _, t := range s.threads {
+			s.stoppedThreads[t.ID] = true
+		}
+	}
+	if body.ThreadID != 0 {
+		s.stoppedThreads[body.ThreadID] = true
+
+		// The adapter may ask us not to move focus away from the user's thread
+		if !body.PreserveFocusHint || s.activeThread == 0 {
+			s.activeThread = body.ThreadID
+		}
+	}
+}
+
+// markContinued records that a thread, or all threads, resumed.
+func (s *Session) markContinued(threadID int, all bool) {
+	s.threadsMu.Lock()
+	defer s.threadsMu.Unlock()
+
+	s.allStopped = false
+	if all {
+		s.stoppedThreads = make(map[int]bool)
+		return
+	}
+	delete(s.stoppedThreads, threadID)
+}
+
+// removeThread forgets a thread that exited.
+func (s *Session) removeThread(threadID int) {
+	s.threadsMu.Lock()
+	defer s.threadsMu.Unlock()
+
+	delete(s.stoppedThreads, threadID)
+	for i, t := range s.threads {
+		if t.ID == threadID {
+			s.threads = append(s.threads[:i:i], s.threads[i+1:]...)
+			break
+		}
+	}
+	if s.activeThread == threadID {
+		s.activeThread = 0
+	}
+}
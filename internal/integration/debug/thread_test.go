@@ -0,0 +1,250 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dshills/keystorm/internal/integration/debug/dap"
+)
+
+// newThreadedAdapter returns a mock transport that answers threads,
+// stackTrace and evaluate requests for two goroutines, recording the
+// stackTrace arguments it receives.
+func newThreadedAdapter(stackArgs chan<- dap.StackTraceArguments) *mockTransport {
+	mt := newMockTransport()
+	mt.onSend = func(msg *dap.Message) {
+		var req dap.Request
+		json.Unmarshal(msg.Content, &req)
+
+		var body []byte
+		switch req.Command {
+		case "threads":
+			body, _ = json.Marshal(dap.ThreadsResponseBody{Threads: []dap.Thread{
+				{ID: 1, Name: "main"},
+				{ID: 2, Name: "worker"},
+			}})
+		case "stackTrace":
+			var args dap.StackTraceArguments
+			json.Unmarshal(req.Arguments, &args)
+			stackArgs <- args
+
+			// Frame IDs encode thread and depth: thread*100 + depth
+			frames := []dap.StackFrame{}
+			for depth := args.StartFrame; depth < 10 && (args.Levels == 0 || depth < args.StartFrame+args.Levels); depth++ {
+				frames = append(frames, dap.StackFrame{ID: args.ThreadID*100 + depth, Name: "frame"})
+			}
+			body, _ = json.Marshal(dap.StackTraceResponseBody{StackFrames: frames, TotalFrames: 10})
+		case "scopes":
+			var args dap.ScopesArguments
+			json.Unmarshal(req.Arguments, &args)
+			body, _ = json.Marshal(dap.ScopesResponseBody{Scopes: []dap.Scope{
+				{Name: "Locals", VariablesReference: args.FrameID},
+			}})
+		case "variables":
+			var args dap.VariablesArguments
+			json.Unmarshal(req.Arguments, &args)
+			body, _ = json.Marshal(dap.VariablesResponseBody{Variables: []dap.Variable{
+				{Name: "thread", Value: string(rune('0' + args.VariablesReference/100))},
+			}})
+		case "evaluate":
+			var args dap.EvaluateArguments
+			json.Unmarshal(req.Arguments, &args)
+			body, _ = json.Marshal(dap.EvaluateResponseBody{Result: string(rune('0' + args.FrameID/100))})
+		default:
+			body = []byte(`{}`)
+		}
+
+		resp := dap.Response{
+			ProtocolMessage: dap.ProtocolMessage{Seq: 1, Type: "response"},
+			RequestSeq:      req.Seq,
+			Success:         true,
+			Command:         req.Command,
+			Body:            body,
+		}
+		content, _ := json.Marshal(resp)
+		mt.queueResponse(&dap.Message{ContentLength: len(content), Content: content})
+	}
+	return mt
+}
+
+func queueEvent(mt *mockTransport, event string, body any) {
+	data, _ := json.Marshal(body)
+	evt := dap.Event{
+		ProtocolMessage: dap.ProtocolMessage{Seq: 1, Type: "event"},
+		Event:           event,
+		Body:            data,
+	}
+	content, _ := json.Marshal(evt)
+	mt.queueResponse(&dap.Message{ContentLength: len(content), Content: content})
+}
+
+func TestSessionSwitchActiveThread(t *testing.T) {
+	stackArgs := make(chan dap.StackTraceArguments, 10)
+	mt := newThreadedAdapter(stackArgs)
+	session := NewSession(dap.NewClient(mt))
+	defer session.Close()
+
+	stopped := make(chan struct{}, 1)
+	session.SetHandlers(SessionHandlers{
+		OnStopped: func(reason string, threadID int, allStopped bool) {
+			stopped <- struct{}{}
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := session.GetThreads(ctx); err != nil {
+		t.Fatalf("GetThreads() error = %v", err)
+	}
+
+	queueEvent(mt, "stopped", dap.StoppedEventBody{Reason: "breakpoint", ThreadID: 1, AllThreadsStopped: true})
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for stopped event")
+	}
+
+	if got := session.ActiveThread(); got != 1 {
+		t.Errorf("ActiveThread() = %d, want 1", got)
+	}
+	for _, th := range session.ThreadStates() {
+		if !th.Stopped {
+			t.Errorf("thread %d Stopped = false, want true (allThreadsStopped)", th.ID)
+		}
+		if th.Active != (th.ID == 1) {
+			t.Errorf("thread %d Active = %v", th.ID, th.Active)
+		}
+	}
+
+	if err := session.SetActiveThread(2); err != nil {
+		t.Fatalf("SetActiveThread(2) error = %v", err)
+	}
+	if err := session.SetActiveThread(99); err == nil {
+		t.Error("SetActiveThread(99) error = nil, want error for unknown thread")
+	}
+
+	// Thread 0 defaults to the active thread; frames load in windows
+	frames, total, err := session.GetStackTrace(ctx, 0, 0, 3)
+	if err != nil {
+		t.Fatalf("GetStackTrace() error = %v", err)
+	}
+	args := <-stackArgs
+	if args.ThreadID != 2 {
+		t.Errorf("stackTrace threadId = %d, want 2", args.ThreadID)
+	}
+	if len(frames) != 3 || total != 10 || frames[0].ID != 200 {
+		t.Errorf("GetStackTrace() = %d frames (first %d), total %d; want 3 frames from 200, total 10", len(frames), frames[0].ID, total)
+	}
+
+	frames, _, err = session.GetStackTrace(ctx, 0, 3, 3)
+	if err != nil {
+		t.Fatalf("GetStackTrace(startFrame 3) error = %v", err)
+	}
+	if args := <-stackArgs; args.StartFrame != 3 || args.Levels != 3 {
+		t.Errorf("stackTrace window = (%d, %d), want (3, 3)", args.StartFrame, args.Levels)
+	}
+	if len(frames) != 3 || frames[0].ID != 203 {
+		t.Errorf("second window starts at frame %d, want 203", frames[0].ID)
+	}
+
+	result, err := session.EvaluateInThread(ctx, "x", 0, "repl")
+	if err != nil {
+		t.Fatalf("EvaluateInThread() error = %v", err)
+	}
+	<-stackArgs
+	if result.Result != "2" {
+		t.Errorf("EvaluateInThread() evaluated in thread %s, want 2", result.Result)
+	}
+
+	vars, err := session.VariablesInThread(ctx, 0)
+	if err != nil {
+		t.Fatalf("VariablesInThread() error = %v", err)
+	}
+	<-stackArgs
+	if len(vars) != 1 || vars[0].Value != "2" {
+		t.Errorf("VariablesInThread() = %+v, want the variables of thread 2", vars)
+	}
+
+	// An explicit thread ID overrides the active thread
+	if _, _, err := session.GetStackTrace(ctx, 1, 0, 1); err != nil {
+		t.Fatalf("GetStackTrace(thread 1) error = %v", err)
+	}
+	if args := <-stackArgs; args.ThreadID != 1 {
+		t.Errorf("stackTrace threadId = %d, want 1", args.ThreadID)
+	}
+}
+
+func TestSessionThreadStopTracking(t *testing.T) {
+	mt := newThreadedAdapter(make(chan dap.StackTraceArguments, 10))
+	session := NewSession(dap.NewClient(mt))
+	defer session.Close()
+
+	events := make(chan struct{}, 4)
+	session.SetHandlers(SessionHandlers{
+		OnStopped:       func(string, int, bool) { events <- struct{}{} },
+		OnStateChanged:  func(old, new SessionState) {},
+		OnThreadChanged: func(string, int) { events <- struct{}{} },
+	})
+	wait := func() {
+		t.Helper()
+		select {
+		case <-events:
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for event")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := session.GetThreads(ctx); err != nil {
+		t.Fatalf("GetThreads() error = %v", err)
+	}
+
+	queueEvent(mt, "stopped", dap.StoppedEventBody{Reason: "breakpoint", ThreadID: 1})
+	wait()
+	if !session.IsThreadStopped(1) || session.IsThreadStopped(2) {
+		t.Error("only thread 1 should be stopped")
+	}
+
+	// A stop that preserves focus does not steal the active thread
+	queueEvent(mt, "stopped", dap.StoppedEventBody{Reason: "breakpoint", ThreadID: 2, PreserveFocusHint: true})
+	wait()
+	if got := session.ActiveThread(); got != 1 {
+		t.Errorf("ActiveThread() = %d, want 1", got)
+	}
+	if !session.IsThreadStopped(2) {
+		t.Error("thread 2 should be stopped")
+	}
+
+	if err := session.Continue(ctx, 2); err != nil {
+		t.Fatalf("Continue() error = %v", err)
+	}
+	if session.IsThreadStopped(2) || !session.IsThreadStopped(1) {
+		t.Error("continuing thread 2 should leave only thread 1 stopped")
+	}
+
+	// Stepping resumes the threads until the next stop event
+	if err := session.Next(ctx, 1); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if session.IsThreadStopped(1) {
+		t.Error("thread 1 should not be stopped while stepping")
+	}
+	queueEvent(mt, "stopped", dap.StoppedEventBody{Reason: "step", ThreadID: 1})
+	wait()
+	if !session.IsThreadStopped(1) {
+		t.Error("thread 1 should be stopped after the step")
+	}
+
+	queueEvent(mt, "thread", dap.ThreadEventBody{Reason: "exited", ThreadID: 1})
+	wait()
+	if got := session.ActiveThread(); got != 0 {
+		t.Errorf("ActiveThread() after exit = %d, want 0", got)
+	}
+	if threads := session.ThreadStates(); len(threads) != 1 || threads[0].ID != 2 {
+		t.Errorf("ThreadStates() = %+v, want only thread 2", threads)
+	}
+}
@@ -110,6 +110,24 @@ func (v *Variable) TotalChildren() int {
 	return v.NamedVariables + v.IndexedVariables
 }
 
+// VariableValue is the value of a variable after it has been set.
+type VariableValue struct {
+	// Value is the new value as formatted by the adapter.
+	Value string
+
+	// Type is the variable type, if the adapter reports it.
+	Type string
+
+	// VariablesReference is the reference for the new value's children.
+	VariablesReference int
+
+	// NamedVariables is the number of named children.
+	NamedVariables int
+
+	// IndexedVariables is the number of indexed children.
+	IndexedVariables int
+}
+
 // VariableInspector provides variable inspection capabilities.
 type VariableInspector struct {
 	session *Session
@@ -252,21 +270,56 @@ func (v *VariableInspector) CollapseVariable(variable *Variable) {
 	variable.Expanded = false
 }
 
-// SetVariable sets the value of a variable.
-func (v *VariableInspector) SetVariable(ctx context.Context, variablesRef int, name, value string) (string, error) {
+// SetVariable sets the value of a variable and returns its new value.
+// The cached variables of the scope, and of the variable's old children,
+// are invalidated so the next GetVariables fetches the updated tree.
+func (v *VariableInspector) SetVariable(ctx context.Context, variablesRef int, name, value string) (VariableValue, error) {
 	newValue, err := v.session.SetVariable(ctx, variablesRef, name, value)
 	if err != nil {
-		return "", err
+		return VariableValue{}, err
 	}
 
-	// Invalidate cache for this reference
 	v.mu.Lock()
+	for _, cached := range v.cache[variablesRef] {
+		if cached.Name == name {
+			v.invalidateLocked(cached.VariablesReference)
+		}
+	}
 	delete(v.cache, variablesRef)
 	v.mu.Unlock()
 
 	return newValue, nil
 }
 
+// SetExpression assigns a value to an assignable expression in a stack
+// frame and returns its new value. Since the expression may refer to any
+// variable, the whole cache is cleared.
+func (v *VariableInspector) SetExpression(ctx context.Context, expression, value string, frameID int) (VariableValue, error) {
+	newValue, err := v.session.SetExpression(ctx, expression, value, frameID)
+	if err != nil {
+		return VariableValue{}, err
+	}
+
+	v.ClearCache()
+	return newValue, nil
+}
+
+// invalidateLocked removes the cached variables of ref and of all their
+// descendants. Caller must hold the lock.
+func (v *VariableInspector) invalidateLocked(ref int) {
+	if ref <= 0 {
+		return
+	}
+	children, ok := v.cache[ref]
+	if !ok {
+		return
+	}
+	delete(v.cache, ref)
+	for _, child := range children {
+		v.invalidateLocked(child.VariablesReference)
+	}
+}
+
 // Evaluate evaluates an expression in the given context.
 func (v *VariableInspector) Evaluate(ctx context.Context, expression string, frameID int, evalContext string) (*Variable, error) {
 	result, err := v.session.Evaluate(ctx, expression, frameID, evalContext)
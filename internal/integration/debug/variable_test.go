@@ -1,7 +1,9 @@
 package debug
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/dshills/keystorm/internal/integration/debug/dap"
 )
@@ -299,3 +301,32 @@ func TestScopeTypeConstants(t *testing.T) {
 		t.Errorf("ScopeRegisters should be 'registers'")
 	}
 }
+
+func TestVariableInspector_SetVariableRefreshesCache(t *testing.T) {
+	x := 1
+	session := NewSession(dap.NewClient(newIntVariableAdapter(&x)))
+	defer session.Close()
+	session.capabilities = &dap.Capabilities{SupportsSetVariable: true}
+	vi := NewVariableInspector(session)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	vars, err := vi.GetVariables(ctx, 1)
+	if err != nil || len(vars) != 1 || vars[0].Value != "1" {
+		t.Fatalf("GetVariables = %+v, %v, want x = 1", vars, err)
+	}
+
+	value, err := vi.SetVariable(ctx, 1, "x", "5")
+	if err != nil {
+		t.Fatalf("SetVariable: %v", err)
+	}
+	if value.Value != "5" {
+		t.Errorf("SetVariable value = %q, want 5", value.Value)
+	}
+
+	vars, err = vi.GetVariables(ctx, 1)
+	if err != nil || len(vars) != 1 || vars[0].Value != "5" {
+		t.Errorf("GetVariables after set = %+v, %v, want x = 5", vars, err)
+	}
+}
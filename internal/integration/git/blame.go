@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -57,6 +58,12 @@ type BlameResult struct {
 
 // Blame returns blame information for a file.
 func (r *Repository) Blame(path string, opts BlameOptions) (*BlameResult, error) {
+	return r.BlameContext(context.Background(), path, opts)
+}
+
+// BlameContext is like Blame but kills the git commands it runs when ctx is
+// done.
+func (r *Repository) BlameContext(ctx context.Context, path string, opts BlameOptions) (*BlameResult, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -80,7 +87,7 @@ func (r *Repository) Blame(path string, opts BlameOptions) (*BlameResult, error)
 
 	args = append(args, "--", path)
 
-	output, err := r.git(args...)
+	output, err := r.gitContext(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("blame %s: %w", path, err)
 	}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -34,10 +35,16 @@ type Branch struct {
 
 // ListBranches returns all local branches.
 func (r *Repository) ListBranches() ([]Branch, error) {
+	return r.ListBranchesContext(context.Background())
+}
+
+// ListBranchesContext is like ListBranches but kills the git commands it
+// runs when ctx is done.
+func (r *Repository) ListBranchesContext(ctx context.Context) ([]Branch, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.listBranchesLocked(false)
+	return r.listBranchesLocked(ctx, false)
 }
 
 // ListAllBranches returns all local and remote branches.
@@ -45,11 +52,11 @@ func (r *Repository) ListAllBranches() ([]Branch, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.listBranchesLocked(true)
+	return r.listBranchesLocked(context.Background(), true)
 }
 
 // listBranchesLocked lists branches (caller must hold lock).
-func (r *Repository) listBranchesLocked(includeRemote bool) ([]Branch, error) {
+func (r *Repository) listBranchesLocked(ctx context.Context, includeRemote bool) ([]Branch, error) {
 	// Format: refname, objectname, upstream, HEAD indicator, upstream tracking
 	format := "%(refname)%00%(objectname)%00%(upstream:short)%00%(HEAD)%00%(upstream:track)"
 
@@ -58,7 +65,7 @@ func (r *Repository) listBranchesLocked(includeRemote bool) ([]Branch, error) {
 		args = append(args, "refs/remotes")
 	}
 
-	output, err := r.git(args...)
+	output, err := r.gitContext(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list branches: %w", err)
 	}
@@ -112,7 +119,7 @@ func (r *Repository) GetBranch(name string) (*Branch, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	branches, err := r.listBranchesLocked(true)
+	branches, err := r.listBranchesLocked(context.Background(), true)
 	if err != nil {
 		return nil, err
 	}
@@ -129,10 +136,16 @@ func (r *Repository) GetBranch(name string) (*Branch, error) {
 // CurrentBranch returns the current branch name.
 // Returns empty string if in detached HEAD state.
 func (r *Repository) CurrentBranch() (string, error) {
+	return r.CurrentBranchContext(context.Background())
+}
+
+// CurrentBranchContext is like CurrentBranch but kills the git commands it
+// runs when ctx is done.
+func (r *Repository) CurrentBranchContext(ctx context.Context) (string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	output, err := r.git("symbolic-ref", "--short", "HEAD")
+	output, err := r.gitContext(ctx, "symbolic-ref", "--short", "HEAD")
 	if err != nil {
 		// Check if we're in detached HEAD state
 		if strings.Contains(err.Error(), "not a symbolic ref") {
@@ -235,10 +248,16 @@ func (r *Repository) RenameBranch(oldName, newName string) error {
 
 // SwitchBranch switches to an existing branch.
 func (r *Repository) SwitchBranch(name string) error {
+	return r.SwitchBranchContext(context.Background(), name)
+}
+
+// SwitchBranchContext is like SwitchBranch but kills the git commands it
+// runs when ctx is done.
+func (r *Repository) SwitchBranchContext(ctx context.Context, name string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, err := r.git("checkout", name); err != nil {
+	if _, err := r.gitContext(ctx, "checkout", name); err != nil {
 		return fmt.Errorf("switch to branch %s: %w", name, err)
 	}
 
@@ -271,44 +290,12 @@ func (r *Repository) SwitchBranchDetach(ref string) error {
 	return nil
 }
 
-// MergeBranch merges the given branch into the current branch.
+// MergeBranch merges the given branch into the current branch. It is Merge
+// without the result: a merge that stops on conflicts returns a
+// *MergeConflictError, which matches ErrConflict.
 func (r *Repository) MergeBranch(name string, opts MergeOptions) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	args := []string{"merge", name}
-
-	if opts.NoFF {
-		args = append(args, "--no-ff")
-	}
-	if opts.FFOnly {
-		args = append(args, "--ff-only")
-	}
-	if opts.Squash {
-		args = append(args, "--squash")
-	}
-	if opts.Message != "" {
-		args = append(args, "-m", opts.Message)
-	}
-
-	output, err := r.git(args...)
-	if err != nil {
-		// Check for merge conflicts
-		if strings.Contains(output, "CONFLICT") || strings.Contains(err.Error(), "CONFLICT") {
-			return ErrConflict
-		}
-		return fmt.Errorf("merge branch %s: %w", name, err)
-	}
-
-	// Invalidate status cache
-	r.statusCache = nil
-
-	r.publishEvent("git.branch.merged", map[string]any{
-		"name":   name,
-		"squash": opts.Squash,
-	})
-
-	return nil
+	_, err := r.Merge(name, opts)
+	return err
 }
 
 // MergeOptions configures merge behavior.
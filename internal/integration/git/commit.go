@@ -1,7 +1,9 @@
 package git
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -12,12 +14,23 @@ import (
 const commitLogFormat = "%H%n%h%n%s%n%an%n%ae%n%at%n%cn%n%ce%n%ct%n%P"
 
 // Commit creates a new commit with the given message.
+//
+// Unless opts.NoVerify is set, the repository's pre-commit hook runs first
+// and the commit-msg hook may then rewrite or reject the message. A hook
+// that exits non-zero aborts the commit with a *HookError holding its
+// output. Hooks run under the process supervisor when one is configured.
 func (r *Repository) Commit(message string, opts CommitOptions) (*Commit, error) {
+	return r.CommitContext(context.Background(), message, opts)
+}
+
+// CommitContext is like Commit but kills the git commands it runs when ctx
+// is done.
+func (r *Repository) CommitContext(ctx context.Context, message string, opts CommitOptions) (*Commit, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	// Get status once for all checks (avoids redundant git calls and race conditions)
-	status, err := r.statusLocked()
+	status, err := r.statusLocked(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("check status: %w", err)
 	}
@@ -34,8 +47,40 @@ func (r *Repository) Commit(message string, opts CommitOptions) (*Commit, error)
 		}
 	}
 
-	// Build commit command
-	args := []string{"commit", "-m", message}
+	message, err = r.resolveCommitMessage(message, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.NoVerify {
+		if err := r.runHook("pre-commit"); err != nil {
+			return nil, err
+		}
+	}
+
+	// The message goes through a file so that the commit-msg hook can
+	// edit it, as with git commit.
+	msgFile, err := r.gitPath("COMMIT_EDITMSG")
+	if err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	if err := os.WriteFile(msgFile, []byte(message+"\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("write commit message: %w", err)
+	}
+	if !opts.NoVerify {
+		if err := r.runHook("commit-msg", msgFile); err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(msgFile)
+		if err != nil {
+			return nil, fmt.Errorf("read commit message: %w", err)
+		}
+		message = strings.TrimSpace(string(data))
+	}
+
+	// Build commit command. Hooks have already run, so git must not run
+	// them again.
+	args := []string{"commit", "-F", msgFile, "--no-verify"}
 
 	if opts.Amend {
 		args = append(args, "--amend")
@@ -54,7 +99,7 @@ func (r *Repository) Commit(message string, opts CommitOptions) (*Commit, error)
 	}
 
 	// Execute commit
-	output, err := r.git(args...)
+	output, err := r.gitContext(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("commit: %w", err)
 	}
@@ -63,7 +108,7 @@ func (r *Repository) Commit(message string, opts CommitOptions) (*Commit, error)
 	r.statusCache = nil
 
 	// Get the new commit info
-	commit, err := r.getHeadCommit()
+	commit, err := r.getHeadCommit(ctx)
 	if err != nil {
 		// Commit succeeded but we couldn't get details
 		// Return minimal commit with a wrapped error for visibility
@@ -83,9 +128,32 @@ func (r *Repository) Commit(message string, opts CommitOptions) (*Commit, error)
 	return commit, nil
 }
 
+// resolveCommitMessage falls back to the commit template when message is
+// empty (must hold lock).
+func (r *Repository) resolveCommitMessage(message string, opts CommitOptions) (string, error) {
+	if strings.TrimSpace(message) != "" {
+		return message, nil
+	}
+
+	template := opts.Template
+	if template == "" {
+		var err error
+		template, err = r.commitTemplateLocked()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	message = stripCommentLines(template)
+	if message == "" {
+		return "", ErrEmptyCommitMessage
+	}
+	return message, nil
+}
+
 // getHeadCommit returns the HEAD commit.
-func (r *Repository) getHeadCommit() (*Commit, error) {
-	output, err := r.git("log", "-1", "--format="+commitLogFormat)
+func (r *Repository) getHeadCommit(ctx context.Context) (*Commit, error) {
+	output, err := r.gitContext(ctx, "log", "-1", "--format="+commitLogFormat)
 	if err != nil {
 		return nil, fmt.Errorf("get head commit: %w", err)
 	}
@@ -133,6 +201,12 @@ func parseCommitOutput(output string) (*Commit, error) {
 
 // Log returns commit history.
 func (r *Repository) Log(opts LogOptions) ([]*Commit, error) {
+	return r.LogContext(context.Background(), opts)
+}
+
+// LogContext is like Log but kills the git commands it runs when ctx is
+// done.
+func (r *Repository) LogContext(ctx context.Context, opts LogOptions) ([]*Commit, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -165,7 +239,7 @@ func (r *Repository) Log(opts LogOptions) ([]*Commit, error) {
 		args = append(args, "--", opts.Path)
 	}
 
-	output, err := r.git(args...)
+	output, err := r.gitContext(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("log: %w", err)
 	}
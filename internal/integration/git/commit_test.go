@@ -1,9 +1,15 @@
 package git
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/dshills/keystorm/internal/integration/process"
 )
 
 func TestRepositoryCommit(t *testing.T) {
@@ -413,3 +419,153 @@ func TestCommitParents(t *testing.T) {
 		t.Errorf("expected parent %s, got %s", firstHead, commit.Parents[0])
 	}
 }
+
+// installHook writes an executable hook script into the repository.
+func installHook(t *testing.T, dir, name, script string) {
+	t.Helper()
+	path := filepath.Join(dir, ".git", "hooks", name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir hooks: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("write hook: %v", err)
+	}
+}
+
+func TestRepositoryCommitPreCommitHookFails(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	supervisor := process.NewSupervisor()
+	defer supervisor.Shutdown(time.Second)
+	mgr := NewManager(ManagerConfig{Supervisor: supervisor})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	installHook(t, dir, "pre-commit", "echo 'running lint'\necho 'lint failed' >&2\nexit 3")
+	createFile(t, dir, "file.txt", "content")
+	if err := repo.Stage("file.txt"); err != nil {
+		t.Fatalf("stage: %v", err)
+	}
+
+	_, err = repo.Commit("blocked", CommitOptions{})
+	if !errors.Is(err, ErrHookFailed) {
+		t.Fatalf("expected ErrHookFailed, got %v", err)
+	}
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) {
+		t.Fatalf("expected *HookError, got %T", err)
+	}
+	if hookErr.Hook != "pre-commit" || hookErr.ExitCode != 3 {
+		t.Errorf("hook error = %s/%d, want pre-commit/3", hookErr.Hook, hookErr.ExitCode)
+	}
+	if !strings.Contains(hookErr.Stdout, "running lint") {
+		t.Errorf("Stdout = %q, want hook output", hookErr.Stdout)
+	}
+	if !strings.Contains(hookErr.Stderr, "lint failed") {
+		t.Errorf("Stderr = %q, want hook error output", hookErr.Stderr)
+	}
+
+	if _, err := repo.getHeadCommit(context.Background()); err == nil {
+		t.Error("commit should not have been created")
+	}
+
+	commit, err := repo.Commit("bypassed", CommitOptions{NoVerify: true})
+	if err != nil {
+		t.Fatalf("commit with NoVerify: %v", err)
+	}
+	if commit.Message != "bypassed" {
+		t.Errorf("expected 'bypassed', got %q", commit.Message)
+	}
+}
+
+func TestRepositoryCommitMsgHook(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	installHook(t, dir, "commit-msg", `grep -q '^[A-Z]' "$1" || { echo 'subject must be capitalized' >&2; exit 1; }
+printf '\nReviewed-by: hook\n' >> "$1"`)
+	createFile(t, dir, "file.txt", "content")
+	if err := repo.Stage("file.txt"); err != nil {
+		t.Fatalf("stage: %v", err)
+	}
+
+	_, err = repo.Commit("lowercase subject", CommitOptions{})
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) || hookErr.Hook != "commit-msg" {
+		t.Fatalf("expected commit-msg HookError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "subject must be capitalized") {
+		t.Errorf("error %q should include hook output", err)
+	}
+
+	if _, err := repo.Commit("Capitalized subject", CommitOptions{}); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	body := gitCmd(t, dir, "log", "-1", "--format=%B")
+	if !strings.Contains(body, "Reviewed-by: hook") {
+		t.Errorf("commit message %q should include the hook's trailer", body)
+	}
+}
+
+func TestRepositoryCommitTemplate(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	if tmpl, err := repo.CommitTemplate(); err != nil || tmpl != "" {
+		t.Errorf("CommitTemplate() = %q, %v, want empty", tmpl, err)
+	}
+
+	createFile(t, dir, ".gitmessage", "# Summary of the change\nDefault subject\n")
+	tmpl, err := repo.CommitTemplate()
+	if err != nil || !strings.Contains(tmpl, "Default subject") {
+		t.Errorf("CommitTemplate() = %q, %v, want .gitmessage contents", tmpl, err)
+	}
+
+	createFile(t, dir, "msg/template.txt", "# Configured\nConfigured subject\n")
+	gitCmd(t, dir, "config", "commit.template", "msg/template.txt")
+	tmpl, err = repo.CommitTemplate()
+	if err != nil || !strings.Contains(tmpl, "Configured subject") {
+		t.Errorf("CommitTemplate() = %q, %v, want configured template", tmpl, err)
+	}
+
+	createFile(t, dir, "file.txt", "content")
+	if err := repo.Stage("file.txt"); err != nil {
+		t.Fatalf("stage: %v", err)
+	}
+	commit, err := repo.Commit("", CommitOptions{})
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if commit.Message != "Configured subject" {
+		t.Errorf("expected template subject, got %q", commit.Message)
+	}
+
+	createFile(t, dir, "other.txt", "content")
+	if err := repo.Stage("other.txt"); err != nil {
+		t.Fatalf("stage: %v", err)
+	}
+	if _, err := repo.Commit("", CommitOptions{Template: "# only comments\n"}); !errors.Is(err, ErrEmptyCommitMessage) {
+		t.Errorf("expected ErrEmptyCommitMessage, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -81,12 +82,18 @@ type Diff struct {
 	Stats DiffStats
 }
 
-// DiffStaged returns the diff of staged changes.
-func (r *Repository) DiffStaged() (*Diff, error) {
+// DiffStaged returns the diff of staged changes, the index against HEAD,
+// limited to paths if any are given.
+func (r *Repository) DiffStaged(paths ...string) (*Diff, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.diffLocked("--cached")
+	args := []string{"--cached"}
+	if len(paths) > 0 {
+		args = append(args, "--")
+		args = append(args, paths...)
+	}
+	return r.diffLocked(args...)
 }
 
 // DiffUnstaged returns the diff of unstaged changes.
@@ -138,6 +145,38 @@ func (r *Repository) DiffCommit(hash string) (*Diff, error) {
 	return parseDiff(output), nil
 }
 
+// DiffRefs returns the per-file diff between two refs, such as commits,
+// tags or branches, limited to paths if any are given. An empty to diffs
+// from against the working tree. Renamed files are reported once with
+// their old and new paths, provided paths includes both.
+func (r *Repository) DiffRefs(ctx context.Context, from, to string, paths []string) ([]FileDiff, error) {
+	if from == "" {
+		return nil, fmt.Errorf("%w: empty ref", ErrInvalidRef)
+	}
+	// A leading dash would be parsed as an option
+	for _, ref := range []string{from, to} {
+		if strings.HasPrefix(ref, "-") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRef, ref)
+		}
+	}
+
+	args := []string{"diff", "-M", from}
+	if to != "" {
+		args = append(args, to)
+	}
+	args = append(args, "--")
+	args = append(args, paths...)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	output, err := r.gitContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("diff: %w", err)
+	}
+	return parseDiff(output).Files, nil
+}
+
 // DiffBranches returns the diff between two branches.
 func (r *Repository) DiffBranches(from, to string) (*Diff, error) {
 	r.mu.RLock()
@@ -159,6 +198,12 @@ func (r *Repository) diffLocked(args ...string) (*Diff, error) {
 
 // DiffRaw returns the raw diff output as a string.
 func (r *Repository) DiffRaw(opts DiffOptions) (string, error) {
+	return r.DiffRawContext(context.Background(), opts)
+}
+
+// DiffRawContext is like DiffRaw but kills the git commands it runs when ctx
+// is done.
+func (r *Repository) DiffRawContext(ctx context.Context, opts DiffOptions) (string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -209,7 +254,7 @@ func (r *Repository) DiffRaw(opts DiffOptions) (string, error) {
 		args = append(args, opts.Paths...)
 	}
 
-	output, err := r.git(args...)
+	output, err := r.gitContext(ctx, args...)
 	if err != nil {
 		return "", fmt.Errorf("diff: %w", err)
 	}
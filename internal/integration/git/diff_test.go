@@ -1,6 +1,8 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -363,3 +365,107 @@ func TestDiffNoChanges(t *testing.T) {
 		t.Errorf("expected 0 files, got %d", len(diff.Files))
 	}
 }
+
+func TestDiffRefs(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	createFile(t, dir, "a.txt", "one\ntwo\nthree\n")
+	createFile(t, dir, "moved.txt", "alpha\nbeta\ngamma\ndelta\n")
+	gitCmd(t, dir, "add", ".")
+	gitCmd(t, dir, "commit", "-m", "first")
+	gitCmd(t, dir, "tag", "v1")
+
+	createFile(t, dir, "a.txt", "one\nTWO\nthree\n")
+	gitCmd(t, dir, "mv", "moved.txt", "renamed.txt")
+	gitCmd(t, dir, "add", ".")
+	gitCmd(t, dir, "commit", "-m", "second")
+	gitCmd(t, dir, "tag", "v2")
+
+	// Later work must not show up between the tags
+	createFile(t, dir, "a.txt", "uncommitted\n")
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	files, err := repo.DiffRefs(context.Background(), "v1", "v2", nil)
+	if err != nil {
+		t.Fatalf("DiffRefs() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("DiffRefs() files = %d, want 2", len(files))
+	}
+
+	modified, renamed := files[0], files[1]
+	if modified.NewPath != "a.txt" || modified.Status != StatusModified {
+		t.Errorf("files[0] = %s %v, want modified a.txt", modified.NewPath, modified.Status)
+	}
+	if len(modified.Hunks) != 1 || modified.Stats.Additions != 1 || modified.Stats.Deletions != 1 {
+		t.Errorf("a.txt hunks = %d, stats = %+v, want 1 hunk, +1 -1", len(modified.Hunks), modified.Stats)
+	}
+	if renamed.Status != StatusRenamed || renamed.OldPath != "moved.txt" || renamed.NewPath != "renamed.txt" {
+		t.Errorf("files[1] = %v %s -> %s, want rename moved.txt -> renamed.txt", renamed.Status, renamed.OldPath, renamed.NewPath)
+	}
+
+	// A single file, and a ref against the working tree
+	files, err = repo.DiffRefs(context.Background(), "v2", "", []string{"a.txt"})
+	if err != nil {
+		t.Fatalf("DiffRefs(working tree) error = %v", err)
+	}
+	if len(files) != 1 || files[0].NewPath != "a.txt" || files[0].Stats.Additions != 1 || files[0].Stats.Deletions != 3 {
+		t.Errorf("DiffRefs(working tree) = %+v, want a.txt with +1 -3", files)
+	}
+
+	if _, err := repo.DiffRefs(context.Background(), "--output=x", "v2", nil); !errors.Is(err, ErrInvalidRef) {
+		t.Errorf("DiffRefs(option) error = %v, want ErrInvalidRef", err)
+	}
+	if _, err := repo.DiffRefs(context.Background(), "v1", "missing", nil); err == nil {
+		t.Error("DiffRefs(missing ref) should fail")
+	}
+}
+
+func TestDiffStagedPath(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	createFile(t, dir, "a.txt", "a\n")
+	createFile(t, dir, "b.txt", "b\n")
+	gitCmd(t, dir, "add", ".")
+	gitCmd(t, dir, "commit", "-m", "initial")
+
+	createFile(t, dir, "a.txt", "a2\n")
+	createFile(t, dir, "b.txt", "b2\n")
+	gitCmd(t, dir, "add", ".")
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	diff, err := repo.DiffStaged("b.txt")
+	if err != nil {
+		t.Fatalf("DiffStaged(b.txt) error = %v", err)
+	}
+	if len(diff.Files) != 1 || diff.Files[0].NewPath != "b.txt" {
+		t.Fatalf("DiffStaged(b.txt) files = %+v, want only b.txt", diff.Files)
+	}
+	if len(diff.Files[0].Hunks) != 1 {
+		t.Errorf("b.txt hunks = %d, want 1", len(diff.Files[0].Hunks))
+	}
+
+	diff, err = repo.DiffStaged()
+	if err != nil {
+		t.Fatalf("DiffStaged() error = %v", err)
+	}
+	if len(diff.Files) != 2 {
+		t.Errorf("DiffStaged() files = %d, want 2", len(diff.Files))
+	}
+}
@@ -48,12 +48,90 @@
 //	// Create commit
 //	commit, err := repo.Commit("Add new feature", git.CommitOptions{})
 //
+// Commit runs the repository's pre-commit and commit-msg hooks, like the git
+// CLI. A failing hook aborts the commit with a *HookError carrying the hook's
+// output; CommitOptions.NoVerify skips the hooks. An empty message falls back
+// to the commit template (commit.template or .gitmessage):
+//
+//	var hookErr *git.HookError
+//	if errors.As(err, &hookErr) {
+//	    // Show hookErr.Stderr
+//	}
+//
 //	// Unstage files
 //	err = repo.Unstage("file1.go")
 //
 //	// Discard changes
 //	err = repo.Discard("file1.go")
 //
+// # Rebase Plans and Worktrees
+//
+// Interactive rebases run without an editor by generating a plan, editing
+// it and applying it:
+//
+//	plan, err := repo.RebasePlan("main")
+//	plan.Steps[1].Action = git.RebaseSquash
+//	err = repo.ApplyRebasePlan(plan)
+//
+//	var conflict *git.RebaseConflictError
+//	if errors.As(err, &conflict) {
+//	    // Resolve conflict.Files, then repo.ContinueRebase()
+//	}
+//
+// AddWorktree and ListWorktrees manage linked working trees for parallel
+// checkouts. When ManagerConfig.Supervisor is set, long-running commands run
+// under the process supervisor so they can be tracked and cancelled.
+//
+// # Diffs
+//
+// Diffs are parsed into FileDiff values with hunks and line numbers.
+// DiffRefs compares any two commits, tags or branches, or a ref against
+// the working tree, for the whole tree or selected paths, and reports
+// renamed files with their old and new paths. DiffStaged compares the
+// index against HEAD:
+//
+//	files, err := repo.DiffRefs(ctx, "v1.0", "v1.1", nil)
+//	for _, file := range files {
+//	    if file.Status == git.StatusRenamed {
+//	        // file.OldPath was renamed to file.NewPath
+//	    }
+//	}
+//
+// # Remotes
+//
+// Fetch, Pull and Push run in the background and stream progress parsed
+// from git's output. The channel closes after a final event with Done set;
+// cancelling the context kills git:
+//
+//	events, err := repo.Fetch(ctx, "origin", git.FetchOptions{Prune: true})
+//	for event := range events {
+//	    if event.Done {
+//	        err = event.Err
+//	    } else {
+//	        // Show event.Phase and event.Percent
+//	    }
+//	}
+//
+// When git needs a username and password that no credential helper
+// provides, ManagerConfig.Credentials is asked for them; without it, or if
+// they are rejected, the operation fails with ErrAuthenticationFailed.
+//
+// # Merging
+//
+// Merge reports whether the branch was already merged, fast-forwarded or
+// merged with a commit. A merge that stops on conflicts returns the
+// conflicted files with their hunks parsed from the conflict markers, for
+// display in a merge tool:
+//
+//	result, err := repo.Merge("feature", git.MergeOptions{})
+//	if errors.Is(err, git.ErrConflict) {
+//	    for _, file := range result.Conflicts {
+//	        // Show file.Hunks (Ours, Base, Theirs), then:
+//	        err = repo.ResolveConflict(file.Path, git.Resolution{Kind: git.ResolveTheirs})
+//	    }
+//	    // Commit the merge, or repo.AbortMerge()
+//	}
+//
 // # Status Caching
 //
 // Status queries are cached for performance. The cache is automatically
@@ -78,6 +156,10 @@
 //   - git.status.changed: Working tree status changed
 //   - git.commit.created: New commit created
 //   - git.branch.changed: Current branch changed
+//   - git.rebase.conflict: Rebase plan stopped on a conflict
+//   - git.merge.conflict: Merge stopped on a conflict
+//   - git.conflict.resolved: Conflicted file resolved and staged
+//   - git.worktree.added: Linked worktree created
 //
 // # Thread Safety
 //
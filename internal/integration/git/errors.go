@@ -49,6 +49,21 @@ var (
 	// ErrNoUpstream indicates no upstream branch is configured.
 	ErrNoUpstream = errors.New("no upstream branch configured")
 
+	// ErrInvalidRebasePlan indicates a rebase plan cannot be applied.
+	ErrInvalidRebasePlan = errors.New("invalid rebase plan")
+
 	// ErrStashEmpty indicates no stash entries exist.
 	ErrStashEmpty = errors.New("no stash entries")
+
+	// ErrHookFailed indicates a git hook rejected the operation.
+	ErrHookFailed = errors.New("hook failed")
+
+	// ErrEmptyCommitMessage indicates the commit message is empty.
+	ErrEmptyCommitMessage = errors.New("empty commit message")
+
+	// ErrInvalidConflictMarkers indicates conflict markers are malformed.
+	ErrInvalidConflictMarkers = errors.New("invalid conflict markers")
+
+	// ErrInvalidRef indicates a ref name that cannot be passed to git.
+	ErrInvalidRef = errors.New("invalid ref")
 )
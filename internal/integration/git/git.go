@@ -4,6 +4,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/dshills/keystorm/internal/integration/process"
 )
 
 // StatusCode represents the status of a file in the working tree.
@@ -183,6 +185,16 @@ type CommitOptions struct {
 
 	// SignOff adds a Signed-off-by line.
 	SignOff bool
+
+	// Template is a commit message template. When the message passed to
+	// Commit is empty, the template with comment lines removed becomes the
+	// message. If Template is empty, the template configured by
+	// commit.template (or .gitmessage in the repository root) is used.
+	Template string
+
+	// NoVerify skips the pre-commit and commit-msg hooks, like
+	// git commit --no-verify.
+	NoVerify bool
 }
 
 // EventPublisher publishes git events.
@@ -201,6 +213,12 @@ type Manager struct {
 
 	// Event publishing
 	eventBus EventPublisher
+
+	// Process supervision for long-running operations
+	supervisor *process.Supervisor
+
+	// Credential prompts of remote operations
+	credentials CredentialFunc
 }
 
 // ManagerConfig configures a git manager.
@@ -211,6 +229,15 @@ type ManagerConfig struct {
 
 	// EventBus for publishing git events.
 	EventBus EventPublisher
+
+	// Supervisor runs long-running operations such as rebases so they
+	// can be tracked and cancelled. Optional.
+	Supervisor *process.Supervisor
+
+	// Credentials supplies a username and password when a fetch, pull or
+	// push asks for them. Optional; without it such operations fail with
+	// ErrAuthenticationFailed.
+	Credentials CredentialFunc
 }
 
 // NewManager creates a new git manager.
@@ -223,6 +250,8 @@ func NewManager(cfg ManagerConfig) *Manager {
 		repos:          make(map[string]*Repository),
 		statusCacheTTL: cfg.StatusCacheTTL,
 		eventBus:       cfg.EventBus,
+		supervisor:     cfg.Supervisor,
+		credentials:    cfg.Credentials,
 	}
 }
 
@@ -246,6 +275,8 @@ func (m *Manager) Open(path string) (*Repository, error) {
 	if err != nil {
 		return nil, err
 	}
+	repo.supervisor = m.supervisor
+	repo.credentials = m.credentials
 
 	m.repos[path] = repo
 	return repo, nil
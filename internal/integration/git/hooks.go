@@ -0,0 +1,165 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// HookError describes a git hook that rejected an operation by exiting
+// with a non-zero status.
+type HookError struct {
+	// Hook is the hook name (e.g., "pre-commit").
+	Hook string
+
+	// ExitCode is the hook's exit status, or -1 if it could not be run.
+	ExitCode int
+
+	// Stdout and Stderr hold the hook's output.
+	Stdout string
+	Stderr string
+}
+
+// Error implements the error interface.
+func (e *HookError) Error() string {
+	msg := strings.TrimSpace(e.Stderr)
+	if msg == "" {
+		msg = strings.TrimSpace(e.Stdout)
+	}
+	if msg == "" {
+		return fmt.Sprintf("%s hook failed with exit code %d", e.Hook, e.ExitCode)
+	}
+	return fmt.Sprintf("%s hook failed with exit code %d: %s", e.Hook, e.ExitCode, msg)
+}
+
+// Is allows errors.Is to match HookError with ErrHookFailed.
+func (e *HookError) Is(target error) bool {
+	return target == ErrHookFailed
+}
+
+// CommitTemplate returns the commit message template configured by
+// commit.template, or the contents of .gitmessage in the repository root
+// when none is configured. Returns "" if there is no template.
+func (r *Repository) CommitTemplate() (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.commitTemplateLocked()
+}
+
+// commitTemplateLocked loads the commit template (must hold lock).
+func (r *Repository) commitTemplateLocked() (string, error) {
+	path := filepath.Join(r.path, ".gitmessage")
+	// git config exits non-zero when the key is unset.
+	if out, err := r.git("config", "--path", "--get", "commit.template"); err == nil {
+		if configured := strings.TrimSpace(out); configured != "" {
+			path = configured
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(r.path, path)
+			}
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read commit template: %w", err)
+	}
+	return string(data), nil
+}
+
+// stripCommentLines removes '#' comment lines and surrounding blank lines
+// from a commit message, as git does for edited messages.
+func stripCommentLines(message string) string {
+	lines := strings.Split(message, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// gitPath resolves a path inside the git directory, honoring worktrees and
+// core.hooksPath.
+func (r *Repository) gitPath(name string) (string, error) {
+	out, err := r.git("rev-parse", "--git-path", name)
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimSpace(out)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.path, path)
+	}
+	return path, nil
+}
+
+// hookPath returns the path of an installed, executable hook, or "" if the
+// hook is not installed. Like git, non-executable hooks are ignored.
+func (r *Repository) hookPath(name string) string {
+	path, err := r.gitPath("hooks/" + name)
+	if err != nil {
+		return ""
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+		return ""
+	}
+	return path
+}
+
+// runHook runs a hook from the repository root. It returns a *HookError if
+// the hook exits non-zero and nil if the hook is not installed. When the
+// repository has a process supervisor the hook runs under it.
+func (r *Repository) runHook(name string, args ...string) error {
+	path := r.hookPath(name)
+	if path == "" {
+		return nil
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Dir = r.path
+	cmd.Stdin = strings.NewReader("")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	exitCode := -1
+	var err error
+	if r.supervisor == nil {
+		err = cmd.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+	} else {
+		proc, startErr := r.supervisor.Start("git hook "+name, cmd)
+		if startErr != nil {
+			return fmt.Errorf("%s hook: %w", name, startErr)
+		}
+		<-proc.Done()
+		err = proc.ExitError()
+		exitCode = proc.ExitCode()
+	}
+
+	if err != nil {
+		if exitCode == -1 && stderr.Len() == 0 {
+			stderr.WriteString(err.Error())
+		}
+		return &HookError{
+			Hook:     name,
+			ExitCode: exitCode,
+			Stdout:   stdout.String(),
+			Stderr:   stderr.String(),
+		}
+	}
+	return nil
+}
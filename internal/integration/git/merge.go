@@ -0,0 +1,411 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MergeStatus describes the outcome of a merge.
+type MergeStatus int
+
+const (
+	// MergeUpToDate indicates the branch was already merged; nothing changed.
+	MergeUpToDate MergeStatus = iota
+	// MergeFastForward indicates HEAD was moved forward to the branch.
+	MergeFastForward
+	// MergeCommitted indicates a merge commit was created.
+	MergeCommitted
+	// MergeSquashed indicates the changes were staged without committing.
+	MergeSquashed
+	// MergeConflicted indicates the merge stopped on conflicts.
+	MergeConflicted
+)
+
+// String returns the string representation of a MergeStatus.
+func (s MergeStatus) String() string {
+	switch s {
+	case MergeUpToDate:
+		return "up-to-date"
+	case MergeFastForward:
+		return "fast-forward"
+	case MergeCommitted:
+		return "committed"
+	case MergeSquashed:
+		return "squashed"
+	case MergeConflicted:
+		return "conflicted"
+	default:
+		return "unknown"
+	}
+}
+
+// MergeResult describes the outcome of Merge.
+type MergeResult struct {
+	// Status is the kind of merge performed.
+	Status MergeStatus
+
+	// Head is the commit hash HEAD points to after the merge.
+	Head string
+
+	// Conflicts lists the conflicted files when Status is MergeConflicted.
+	Conflicts []ConflictFile
+}
+
+// ConflictFile is a file left with unresolved conflicts by a merge.
+type ConflictFile struct {
+	// Path is the file path relative to repository root.
+	Path string
+
+	// Hunks are the conflict regions in the file. Conflicts without
+	// markers, such as a file deleted on one side, have no hunks.
+	Hunks []ConflictHunk
+}
+
+// ConflictHunk is a region of a file delimited by conflict markers.
+type ConflictHunk struct {
+	// StartLine is the 0-based line of the "<<<<<<<" marker.
+	StartLine int
+
+	// EndLine is the 0-based line of the ">>>>>>>" marker.
+	EndLine int
+
+	// OursLabel, BaseLabel and TheirsLabel are the marker labels
+	// (e.g., "HEAD" and "feature").
+	OursLabel   string
+	BaseLabel   string
+	TheirsLabel string
+
+	// Ours is the content from the current branch.
+	Ours string
+
+	// Base is the content from the common ancestor. It is only set for
+	// diff3-style markers; see HasBase.
+	Base string
+
+	// Theirs is the content from the merged branch.
+	Theirs string
+
+	// HasBase indicates the hunk has a "|||||||" base section.
+	HasBase bool
+}
+
+// MergeConflictError describes a merge that stopped on conflicts.
+// The merge is left in progress so the conflicts can be resolved with
+// ResolveConflict and committed, or abandoned with AbortMerge.
+type MergeConflictError struct {
+	// Branch is the branch being merged.
+	Branch string
+
+	// Files lists the paths with unresolved conflicts.
+	Files []string
+}
+
+// Error implements the error interface.
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("merge conflict merging %s in %s", e.Branch, strings.Join(e.Files, ", "))
+}
+
+// Is allows errors.Is to match MergeConflictError with ErrConflict.
+func (e *MergeConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Merge merges branch into the current branch. Merging a branch that is
+// already contained in HEAD does nothing and reports MergeUpToDate.
+//
+// If the merge stops on conflicts, the result lists the conflicted files
+// with their hunks (including the base, as conflicts are written in diff3
+// style) and a *MergeConflictError is returned alongside it.
+func (r *Repository) Merge(branch string, opts MergeOptions) (MergeResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	head, err := r.revParse("HEAD")
+	if err != nil {
+		return MergeResult{}, ErrNoHead
+	}
+	target, err := r.revParse(branch + "^{commit}")
+	if err != nil {
+		return MergeResult{}, fmt.Errorf("merge %s: %w", branch, ErrBranchNotFound)
+	}
+
+	if r.isAncestor(target, head) {
+		return MergeResult{Status: MergeUpToDate, Head: head}, nil
+	}
+	fastForward := r.isAncestor(head, target)
+
+	args := []string{"-c", "merge.conflictStyle=diff3", "merge", "--no-edit"}
+	if opts.NoFF {
+		args = append(args, "--no-ff")
+	}
+	if opts.FFOnly {
+		args = append(args, "--ff-only")
+	}
+	if opts.Squash {
+		args = append(args, "--squash")
+	}
+	if opts.Message != "" {
+		args = append(args, "-m", opts.Message)
+	}
+	args = append(args, branch)
+
+	_, mergeErr := r.git(args...)
+
+	// Invalidate status cache
+	r.statusCache = nil
+
+	if mergeErr != nil {
+		files, _ := r.gitLines("diff", "--name-only", "--diff-filter=U")
+		if len(files) == 0 {
+			return MergeResult{}, fmt.Errorf("merge %s: %w", branch, mergeErr)
+		}
+
+		result := MergeResult{Status: MergeConflicted, Head: head}
+		for _, path := range files {
+			file := ConflictFile{Path: path}
+			if data, err := os.ReadFile(filepath.Join(r.path, path)); err == nil {
+				file.Hunks, _ = ParseConflictHunks(string(data))
+			}
+			result.Conflicts = append(result.Conflicts, file)
+		}
+
+		r.publishEvent("git.merge.conflict", map[string]any{
+			"name":  branch,
+			"files": files,
+		})
+		return result, &MergeConflictError{Branch: branch, Files: files}
+	}
+
+	result := MergeResult{Status: MergeCommitted, Head: head}
+	switch {
+	case opts.Squash:
+		result.Status = MergeSquashed
+	case fastForward && !opts.NoFF:
+		result.Status = MergeFastForward
+	}
+	if newHead, err := r.revParse("HEAD"); err == nil {
+		result.Head = newHead
+	}
+
+	r.publishEvent("git.branch.merged", map[string]any{
+		"name":   branch,
+		"squash": opts.Squash,
+		"status": result.Status.String(),
+	})
+
+	return result, nil
+}
+
+// ResolutionKind selects how a conflicted file is resolved.
+type ResolutionKind int
+
+const (
+	// ResolveOurs keeps the current branch's side of every hunk.
+	ResolveOurs ResolutionKind = iota
+	// ResolveTheirs keeps the merged branch's side of every hunk.
+	ResolveTheirs
+	// ResolveBoth keeps our side followed by their side in every hunk.
+	ResolveBoth
+	// ResolveContent replaces the file with Resolution.Content.
+	ResolveContent
+)
+
+// Resolution is the chosen resolution of a conflicted file.
+type Resolution struct {
+	// Kind selects the resolution.
+	Kind ResolutionKind
+
+	// Content is the resolved file content for ResolveContent, typically
+	// produced by an interactive merge tool.
+	Content []byte
+}
+
+// ResolveConflict writes the resolved content of the conflicted file at
+// path (relative to repository root) and stages it. Only the conflict hunks
+// are replaced; changes git merged cleanly are kept.
+func (r *Repository) ResolveConflict(path string, resolution Resolution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fullPath := filepath.Join(r.path, path)
+
+	switch resolution.Kind {
+	case ResolveContent:
+		if err := os.WriteFile(fullPath, resolution.Content, 0644); err != nil {
+			return fmt.Errorf("resolve %s: %w", path, err)
+		}
+	case ResolveOurs, ResolveTheirs, ResolveBoth:
+		data, err := os.ReadFile(fullPath)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("resolve %s: %w", path, err)
+		}
+		resolved, hunks, err := resolveConflictText(string(data), resolution.Kind)
+		if err != nil {
+			return fmt.Errorf("resolve %s: %w", path, err)
+		}
+
+		if hunks > 0 {
+			info, err := os.Stat(fullPath)
+			if err != nil {
+				return fmt.Errorf("resolve %s: %w", path, err)
+			}
+			if err := os.WriteFile(fullPath, []byte(resolved), info.Mode().Perm()); err != nil {
+				return fmt.Errorf("resolve %s: %w", path, err)
+			}
+		} else if resolution.Kind != ResolveBoth {
+			// No markers (e.g., modify/delete or binary): take one side whole
+			side := "--ours"
+			if resolution.Kind == ResolveTheirs {
+				side = "--theirs"
+			}
+			if _, err := r.git("checkout", side, "--", path); err != nil {
+				return fmt.Errorf("resolve %s: %w", path, err)
+			}
+		}
+	default:
+		return fmt.Errorf("resolve %s: unknown resolution kind %d", path, resolution.Kind)
+	}
+
+	if _, err := r.git("add", "--", path); err != nil {
+		return fmt.Errorf("stage %s: %w", path, err)
+	}
+
+	// Invalidate status cache
+	r.statusCache = nil
+
+	r.publishEvent("git.conflict.resolved", map[string]any{
+		"path": path,
+	})
+
+	return nil
+}
+
+// Conflict marker prefixes. Each marker is seven characters long.
+const (
+	markerOurs   = "<<<<<<<"
+	markerBase   = "|||||||"
+	markerSplit  = "======="
+	markerTheirs = ">>>>>>>"
+)
+
+// ParseConflictHunks parses the conflict markers in content. Both the
+// default style and the diff3 style (with a base section) are recognized.
+// It returns ErrInvalidConflictMarkers if a hunk is not terminated.
+func ParseConflictHunks(content string) ([]ConflictHunk, error) {
+	var hunks []ConflictHunk
+	var hunk *ConflictHunk
+	var section *strings.Builder
+	var ours, base, theirs strings.Builder
+
+	for i, line := range strings.SplitAfter(content, "\n") {
+		text := strings.TrimRight(line, "\r\n")
+
+		switch {
+		case hunk == nil:
+			if label, ok := markerLabel(text, markerOurs); ok {
+				hunk = &ConflictHunk{StartLine: i, OursLabel: label}
+				ours.Reset()
+				base.Reset()
+				theirs.Reset()
+				section = &ours
+			}
+			continue
+		case section == &ours:
+			if label, ok := markerLabel(text, markerBase); ok {
+				hunk.BaseLabel = label
+				hunk.HasBase = true
+				section = &base
+				continue
+			}
+			if text == markerSplit {
+				section = &theirs
+				continue
+			}
+		case section == &base:
+			if text == markerSplit {
+				section = &theirs
+				continue
+			}
+		case section == &theirs:
+			if label, ok := markerLabel(text, markerTheirs); ok {
+				hunk.EndLine = i
+				hunk.TheirsLabel = label
+				hunk.Ours = ours.String()
+				hunk.Base = base.String()
+				hunk.Theirs = theirs.String()
+				hunks = append(hunks, *hunk)
+				hunk = nil
+				continue
+			}
+		}
+		section.WriteString(line)
+	}
+
+	if hunk != nil {
+		return hunks, fmt.Errorf("hunk at line %d: %w", hunk.StartLine+1, ErrInvalidConflictMarkers)
+	}
+	return hunks, nil
+}
+
+// markerLabel reports whether line is the given conflict marker and returns
+// its label.
+func markerLabel(line, marker string) (string, bool) {
+	if line == marker {
+		return "", true
+	}
+	if strings.HasPrefix(line, marker+" ") {
+		return line[len(marker)+1:], true
+	}
+	return "", false
+}
+
+// resolveConflictText replaces each conflict hunk in content with the side
+// chosen by kind. It returns the resolved content and the number of hunks
+// replaced.
+func resolveConflictText(content string, kind ResolutionKind) (string, int, error) {
+	hunks, err := ParseConflictHunks(content)
+	if err != nil || len(hunks) == 0 {
+		return content, 0, err
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	var b strings.Builder
+	next := 0
+	for _, h := range hunks {
+		for _, line := range lines[next:h.StartLine] {
+			b.WriteString(line)
+		}
+		switch kind {
+		case ResolveOurs:
+			b.WriteString(h.Ours)
+		case ResolveTheirs:
+			b.WriteString(h.Theirs)
+		case ResolveBoth:
+			b.WriteString(h.Ours)
+			b.WriteString(h.Theirs)
+		}
+		next = h.EndLine + 1
+	}
+	for _, line := range lines[next:] {
+		b.WriteString(line)
+	}
+	return b.String(), len(hunks), nil
+}
+
+// revParse resolves rev to a commit hash. Caller must hold the lock.
+func (r *Repository) revParse(rev string) (string, error) {
+	out, err := r.git("rev-parse", "--verify", "-q", rev)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// isAncestor reports whether commit a is an ancestor of (or equal to)
+// commit b. Caller must hold the lock.
+func (r *Repository) isAncestor(a, b string) bool {
+	_, err := r.git("merge-base", "--is-ancestor", a, b)
+	return err == nil
+}
@@ -0,0 +1,215 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConflictHunks(t *testing.T) {
+	content := strings.Join([]string{
+		"package main",
+		"<<<<<<< HEAD",
+		"ours one",
+		"ours two",
+		"=======",
+		"theirs",
+		">>>>>>> feature",
+		"middle",
+		"<<<<<<< HEAD",
+		"a",
+		"||||||| base",
+		"b",
+		"=======",
+		">>>>>>> feature",
+		"end",
+	}, "\n") + "\n"
+
+	hunks, err := ParseConflictHunks(content)
+	if err != nil {
+		t.Fatalf("ParseConflictHunks error: %v", err)
+	}
+	if len(hunks) != 2 {
+		t.Fatalf("len(hunks) = %d, want 2", len(hunks))
+	}
+
+	h := hunks[0]
+	if h.StartLine != 1 || h.EndLine != 6 {
+		t.Errorf("hunk 0 lines = %d-%d, want 1-6", h.StartLine, h.EndLine)
+	}
+	if h.OursLabel != "HEAD" || h.TheirsLabel != "feature" {
+		t.Errorf("hunk 0 labels = %q/%q, want HEAD/feature", h.OursLabel, h.TheirsLabel)
+	}
+	if h.Ours != "ours one\nours two\n" {
+		t.Errorf("hunk 0 Ours = %q", h.Ours)
+	}
+	if h.Theirs != "theirs\n" {
+		t.Errorf("hunk 0 Theirs = %q", h.Theirs)
+	}
+	if h.HasBase {
+		t.Error("hunk 0 HasBase = true, want false")
+	}
+
+	h = hunks[1]
+	if h.StartLine != 8 || h.EndLine != 13 {
+		t.Errorf("hunk 1 lines = %d-%d, want 8-13", h.StartLine, h.EndLine)
+	}
+	if !h.HasBase || h.BaseLabel != "base" || h.Base != "b\n" {
+		t.Errorf("hunk 1 base = %v %q %q, want base section %q", h.HasBase, h.BaseLabel, h.Base, "b\n")
+	}
+	if h.Ours != "a\n" || h.Theirs != "" {
+		t.Errorf("hunk 1 Ours/Theirs = %q/%q, want %q/%q", h.Ours, h.Theirs, "a\n", "")
+	}
+}
+
+func TestParseConflictHunksUnterminated(t *testing.T) {
+	_, err := ParseConflictHunks("<<<<<<< HEAD\nours\n=======\ntheirs\n")
+	if !errors.Is(err, ErrInvalidConflictMarkers) {
+		t.Errorf("error = %v, want ErrInvalidConflictMarkers", err)
+	}
+}
+
+func TestResolveConflictText(t *testing.T) {
+	content := "top\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feature\nbottom\n"
+
+	tests := []struct {
+		kind ResolutionKind
+		want string
+	}{
+		{ResolveOurs, "top\nours\nbottom\n"},
+		{ResolveTheirs, "top\ntheirs\nbottom\n"},
+		{ResolveBoth, "top\nours\ntheirs\nbottom\n"},
+	}
+	for _, tt := range tests {
+		got, n, err := resolveConflictText(content, tt.kind)
+		if err != nil {
+			t.Fatalf("resolveConflictText(%d) error: %v", tt.kind, err)
+		}
+		if n != 1 {
+			t.Errorf("resolveConflictText(%d) hunks = %d, want 1", tt.kind, n)
+		}
+		if got != tt.want {
+			t.Errorf("resolveConflictText(%d) = %q, want %q", tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	createFile(t, dir, "file.txt", "line\n")
+	gitCmd(t, dir, "add", "file.txt")
+	gitCmd(t, dir, "commit", "-m", "initial")
+	base := strings.TrimSpace(gitCmd(t, dir, "rev-parse", "--abbrev-ref", "HEAD"))
+
+	gitCmd(t, dir, "checkout", "-b", "feature")
+	createFile(t, dir, "feature.txt", "feature\n")
+	gitCmd(t, dir, "add", "feature.txt")
+	gitCmd(t, dir, "commit", "-m", "feature commit")
+	gitCmd(t, dir, "checkout", base)
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	result, err := repo.Merge("feature", MergeOptions{})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if result.Status != MergeFastForward {
+		t.Errorf("Status = %v, want %v", result.Status, MergeFastForward)
+	}
+	if want := strings.TrimSpace(gitCmd(t, dir, "rev-parse", "feature")); result.Head != want {
+		t.Errorf("Head = %s, want %s", result.Head, want)
+	}
+
+	result, err = repo.Merge("feature", MergeOptions{})
+	if err != nil {
+		t.Fatalf("merge again: %v", err)
+	}
+	if result.Status != MergeUpToDate {
+		t.Errorf("Status = %v, want %v", result.Status, MergeUpToDate)
+	}
+
+	if _, err := repo.Merge("missing", MergeOptions{}); !errors.Is(err, ErrBranchNotFound) {
+		t.Errorf("merge missing branch error = %v, want ErrBranchNotFound", err)
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	createFile(t, dir, "file.txt", "one\nshared\nthree\n")
+	gitCmd(t, dir, "add", "file.txt")
+	gitCmd(t, dir, "commit", "-m", "initial")
+	base := strings.TrimSpace(gitCmd(t, dir, "rev-parse", "--abbrev-ref", "HEAD"))
+
+	gitCmd(t, dir, "checkout", "-b", "feature")
+	createFile(t, dir, "file.txt", "one\ntheirs\nthree\n")
+	gitCmd(t, dir, "commit", "-am", "feature change")
+	gitCmd(t, dir, "checkout", base)
+	createFile(t, dir, "file.txt", "one\nours\nthree\n")
+	gitCmd(t, dir, "commit", "-am", "base change")
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	result, err := repo.Merge("feature", MergeOptions{})
+	var conflict *MergeConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+		t.Fatalf("merge error = %v, want *MergeConflictError", err)
+	}
+	if result.Status != MergeConflicted {
+		t.Errorf("Status = %v, want %v", result.Status, MergeConflicted)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Path != "file.txt" {
+		t.Fatalf("Conflicts = %+v, want file.txt", result.Conflicts)
+	}
+	hunks := result.Conflicts[0].Hunks
+	if len(hunks) != 1 {
+		t.Fatalf("len(Hunks) = %d, want 1", len(hunks))
+	}
+	if hunks[0].Ours != "ours\n" || hunks[0].Base != "shared\n" || hunks[0].Theirs != "theirs\n" {
+		t.Errorf("hunk = %+v", hunks[0])
+	}
+
+	if err := repo.ResolveConflict("file.txt", Resolution{Kind: ResolveTheirs}); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if got, want := string(data), "one\ntheirs\nthree\n"; got != want {
+		t.Errorf("resolved content = %q, want %q", got, want)
+	}
+
+	status, err := repo.RefreshStatus()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if status.HasConflicts() {
+		t.Errorf("Conflicts after resolve = %v, want none", status.Conflicts)
+	}
+
+	if err := repo.AbortMerge(); err != nil {
+		t.Fatalf("abort: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "file.txt"))
+	if got, want := string(data), "one\nours\nthree\n"; got != want {
+		t.Errorf("content after abort = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,354 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProgressEvent reports the progress of a fetch, pull or push. The last
+// event sent for an operation has Done set, and Err if it failed.
+type ProgressEvent struct {
+	// Operation is "fetch", "pull" or "push".
+	Operation string
+
+	// Phase is the stage git reports, such as "Receiving objects".
+	Phase string
+
+	// Percent is the completion of the phase, or -1 if not reported.
+	Percent int
+
+	// Current and Total count the items of the phase, if reported.
+	Current int
+	Total   int
+
+	// FromRemote indicates the remote reported the phase ("remote: ").
+	FromRemote bool
+
+	// Message is the line of git output the event was parsed from.
+	Message string
+
+	// Done indicates the operation finished.
+	Done bool
+
+	// Err is the error the operation failed with. Only set with Done.
+	Err error
+}
+
+// CredentialFunc supplies credentials when git asks for a username and
+// password for url, typically an HTTPS remote without a credential helper.
+// Returning an error or nil credential fails the operation with
+// ErrAuthenticationFailed.
+type CredentialFunc func(ctx context.Context, url string) (*Credential, error)
+
+// progressEventBuffer is the number of progress events buffered for a
+// slow reader. Further progress events are dropped until there is room;
+// the last slot is kept for the final event.
+const progressEventBuffer = 64
+
+// remoteWaitDelay bounds how long a killed git waits for helpers it started,
+// such as ssh or upload-pack, to release its output.
+const remoteWaitDelay = time.Second
+
+// progressPattern matches git's progress lines, such as
+// "Receiving objects:  45% (450/1000), 1.2 MiB | 500 KiB/s".
+var progressPattern = regexp.MustCompile(`^(remote: )?([A-Za-z][A-Za-z ]*):\s+(\d+)%\s+\((\d+)/(\d+)\)`)
+
+// countPattern matches progress lines without a total, such as
+// "remote: Enumerating objects: 5, done.".
+var countPattern = regexp.MustCompile(`^(remote: )?([A-Za-z][A-Za-z ]*):\s+(\d+)(,|$)`)
+
+// promptURLPattern extracts the URL git prompted for from its error, such
+// as "could not read Username for 'https://host': terminal prompts disabled".
+var promptURLPattern = regexp.MustCompile(`could not read (?:Username|Password) for '([^']+)'`)
+
+// parseProgress parses a line of git's progress output.
+func parseProgress(line string) (ProgressEvent, bool) {
+	if m := progressPattern.FindStringSubmatch(line); m != nil {
+		percent, _ := strconv.Atoi(m[3])
+		current, _ := strconv.Atoi(m[4])
+		total, _ := strconv.Atoi(m[5])
+		return ProgressEvent{
+			Phase:      strings.TrimSpace(m[2]),
+			Percent:    percent,
+			Current:    current,
+			Total:      total,
+			FromRemote: m[1] != "",
+			Message:    line,
+		}, true
+	}
+	if m := countPattern.FindStringSubmatch(line); m != nil {
+		current, _ := strconv.Atoi(m[3])
+		return ProgressEvent{
+			Phase:      strings.TrimSpace(m[2]),
+			Percent:    -1,
+			Current:    current,
+			FromRemote: m[1] != "",
+			Message:    line,
+		}, true
+	}
+	return ProgressEvent{}, false
+}
+
+// scanProgress reads git's stderr, passing progress lines to emit. Git
+// redraws progress with carriage returns, so both '\r' and '\n' end a
+// line. It returns the other lines, for error messages.
+func scanProgress(r io.Reader, emit func(ProgressEvent)) string {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanProgressLines)
+
+	var other strings.Builder
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if event, ok := parseProgress(line); ok {
+			emit(event)
+			continue
+		}
+		other.WriteString(line)
+		other.WriteByte('\n')
+	}
+	return other.String()
+}
+
+// scanProgressLines is a bufio.SplitFunc splitting on '\r' or '\n'.
+func scanProgressLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// isAuthFailure reports whether git's error output indicates missing or
+// rejected credentials.
+func isAuthFailure(stderr string) bool {
+	for _, s := range []string{
+		"Authentication failed",
+		"could not read Username",
+		"could not read Password",
+		"Permission denied (publickey",
+		"Invalid username or password",
+	} {
+		if strings.Contains(stderr, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// credentialHelper answers git's credential requests with what is written
+// to file descriptor 3. The pipe holds one answer and is drained by the
+// first read, so the credentials never appear in the environment of git or
+// of the processes it starts.
+const credentialHelper = `!f() { test "$1" = get && cat <&3; }; f`
+
+// credentialEnv configures git to use only credentialHelper.
+var credentialEnv = []string{
+	"GIT_CONFIG_COUNT=2",
+	"GIT_CONFIG_KEY_0=credential.helper",
+	"GIT_CONFIG_VALUE_0=",
+	"GIT_CONFIG_KEY_1=credential.helper",
+	"GIT_CONFIG_VALUE_1=" + credentialHelper,
+}
+
+// credentialPipe returns the read end of a pipe holding cred in git's
+// credential helper format. The caller closes it.
+func credentialPipe(cred *Credential) (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("credential pipe: %w", err)
+	}
+
+	data := fmt.Appendf(nil, "username=%s\npassword=%s\n", cred.Username, cred.Password)
+	_, err = w.Write(data)
+	clear(data)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		r.Close()
+		return nil, fmt.Errorf("write credentials: %w", err)
+	}
+	return r, nil
+}
+
+// remoteResult is the outcome of one run of a remote git command.
+type remoteResult struct {
+	stdout string
+	stderr string
+	err    error
+}
+
+// runRemote runs a fetch, pull or push in the background, streaming its
+// progress. If git asks for credentials and the repository has a
+// CredentialFunc, the command is retried once with the credentials it
+// supplies. success is called after the command succeeds, before the final
+// event is sent. The final event is sent after the repository lock is
+// released and never blocks, so a caller that stops reading leaks nothing.
+func (r *Repository) runRemote(ctx context.Context, op string, args []string, lock bool, classify func(remoteResult) error, success func(stdout string)) (<-chan ProgressEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Progress is only written to a terminal unless asked for
+	args = append([]string{args[0], "--progress"}, args[1:]...)
+
+	events := make(chan ProgressEvent, progressEventBuffer)
+	go func() {
+		defer close(events)
+
+		emit := func(event ProgressEvent) {
+			// The last slot of the buffer is kept for the final event
+			if len(events) >= cap(events)-1 {
+				return
+			}
+			event.Operation = op
+			select {
+			case events <- event:
+			default:
+				// Slow reader, drop progress
+			}
+		}
+
+		err := func() error {
+			if lock {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+			}
+
+			result := r.runRemoteOnce(ctx, args, nil, nil, emit)
+			if result.err != nil && ctx.Err() == nil && isAuthFailure(result.stderr) && r.credentials != nil {
+				result = r.runRemoteWithCredentials(ctx, args, result, emit)
+			}
+
+			switch {
+			case result.err == nil:
+				success(result.stdout)
+				return nil
+			case ctx.Err() != nil:
+				return fmt.Errorf("%s: %w", op, ctx.Err())
+			case isAuthFailure(result.stderr):
+				return fmt.Errorf("%s: %w: %s", op, ErrAuthenticationFailed, strings.TrimSpace(result.stderr))
+			}
+			if err := classify(result); err != nil {
+				return err
+			}
+			return remoteError(op, result)
+		}()
+
+		// Only this goroutine sends, so the reserved slot is still free and
+		// the final event never blocks, even if the caller stopped reading
+		events <- ProgressEvent{Operation: op, Percent: -1, Done: true, Err: err}
+	}()
+	return events, nil
+}
+
+// runRemoteWithCredentials asks the CredentialFunc for the credentials git
+// prompted for and runs the command again with them.
+func (r *Repository) runRemoteWithCredentials(ctx context.Context, args []string, failed remoteResult, emit func(ProgressEvent)) remoteResult {
+	url := ""
+	if m := promptURLPattern.FindStringSubmatch(failed.stderr); m != nil {
+		url = m[1]
+	}
+
+	cred, err := r.credentials(ctx, url)
+	if err != nil || cred == nil {
+		return failed
+	}
+	defer ClearCredential(cred)
+
+	creds, err := credentialPipe(cred)
+	if err != nil {
+		return remoteResult{err: err}
+	}
+	defer creds.Close()
+
+	return r.runRemoteOnce(ctx, args, credentialEnv, []*os.File{creds}, emit)
+}
+
+// runRemoteOnce runs a remote git command until it exits or ctx is done,
+// in which case the process is killed. Terminal prompts are disabled so a
+// missing credential fails instead of hanging. files are passed to git from
+// file descriptor 3 on.
+func (r *Repository) runRemoteOnce(ctx context.Context, args []string, env []string, files []*os.File, emit func(ProgressEvent)) remoteResult {
+	cmd := newGitCommand(r.path, args...).toExecCmd()
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	cmd.Env = append(cmd.Env, env...)
+	cmd.ExtraFiles = files
+	cmd.Stdin = strings.NewReader("")
+	cmd.WaitDelay = remoteWaitDelay
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	stderrReader, stderrWriter := io.Pipe()
+	cmd.Stderr = stderrWriter
+
+	stderr := make(chan string, 1)
+	go func() {
+		stderr <- scanProgress(stderrReader, emit)
+	}()
+
+	done, kill, exitErr, err := r.startRemote(cmd, args[0])
+	if err != nil {
+		stderrWriter.Close()
+		<-stderr
+		return remoteResult{err: fmt.Errorf("git %s: %w", args[0], err)}
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		_ = kill()
+		<-done
+	}
+	stderrWriter.Close()
+
+	return remoteResult{stdout: stdout.String(), stderr: <-stderr, err: exitErr()}
+}
+
+// startRemote starts cmd, under the repository's supervisor if it has one.
+// It returns a channel closed when the process exits, a func killing it and
+// a func returning its exit error once it has exited.
+func (r *Repository) startRemote(cmd *exec.Cmd, subcommand string) (<-chan struct{}, func() error, func() error, error) {
+	if r.supervisor != nil {
+		proc, err := r.supervisor.Start("git "+subcommand, cmd)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return proc.Done(), proc.Kill, proc.ExitError, nil
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, nil, err
+	}
+	done := make(chan struct{})
+	var waitErr error
+	go func() {
+		waitErr = cmd.Wait()
+		close(done)
+	}()
+	return done, cmd.Process.Kill, func() error { return waitErr }, nil
+}
+
+// remoteError returns the error of a failed remote command with git's
+// output, without progress lines.
+func remoteError(op string, result remoteResult) error {
+	msg := strings.TrimSpace(result.stderr)
+	if msg == "" {
+		return fmt.Errorf("%s: %w", op, result.err)
+	}
+	return fmt.Errorf("%s: %s", op, msg)
+}
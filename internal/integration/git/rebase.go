@@ -0,0 +1,244 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RebaseAction is the action applied to a commit in a rebase plan.
+type RebaseAction string
+
+// Rebase actions supported by ApplyRebasePlan.
+const (
+	// RebasePick keeps the commit as is.
+	RebasePick RebaseAction = "pick"
+
+	// RebaseSquash melds the commit into the previous one, combining messages.
+	RebaseSquash RebaseAction = "squash"
+
+	// RebaseFixup melds the commit into the previous one, discarding its message.
+	RebaseFixup RebaseAction = "fixup"
+
+	// RebaseDrop removes the commit.
+	RebaseDrop RebaseAction = "drop"
+)
+
+// RebaseStep is a single line of a rebase todo list.
+type RebaseStep struct {
+	// Action is what to do with the commit.
+	Action RebaseAction
+
+	// Hash is the full commit hash.
+	Hash string
+
+	// Subject is the first line of the commit message.
+	Subject string
+}
+
+// String returns the step formatted as a sequencer todo line.
+func (s RebaseStep) String() string {
+	if s.Subject == "" {
+		return string(s.Action) + " " + s.Hash
+	}
+	return string(s.Action) + " " + s.Hash + " " + s.Subject
+}
+
+// RebasePlan is a rebase todo list together with the commit it is replayed
+// onto.
+type RebasePlan struct {
+	// Onto is the hash of the commit the steps are replayed onto.
+	Onto string
+
+	// Steps is the todo list, oldest commit first.
+	Steps []RebaseStep
+}
+
+// RebaseConflictError describes a rebase that stopped on a conflict.
+// The rebase is left in progress so the conflict can be resolved and the
+// rebase continued with ContinueRebase, or abandoned with AbortRebase.
+type RebaseConflictError struct {
+	// Commit is the hash of the commit that could not be applied.
+	Commit string
+
+	// Files lists the paths with unresolved conflicts.
+	Files []string
+}
+
+// Error implements the error interface.
+func (e *RebaseConflictError) Error() string {
+	return fmt.Sprintf("rebase conflict applying %s in %s", shortHash(e.Commit), strings.Join(e.Files, ", "))
+}
+
+// Is allows errors.Is to match RebaseConflictError with ErrConflict.
+func (e *RebaseConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// RebasePlan returns the plan for rebasing the current branch onto the
+// given ref: one pick step per commit in onto..HEAD, oldest first. The ref
+// is resolved to a commit, so the plan is applied onto the commit it was
+// made against. Merge commits are omitted, as a plain rebase would drop
+// them.
+func (r *Repository) RebasePlan(onto string) (RebasePlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out, err := r.git("rev-parse", "--verify", onto+"^{commit}")
+	if err != nil {
+		return RebasePlan{}, fmt.Errorf("rebase plan onto %s: %w", onto, err)
+	}
+	ontoHash := strings.TrimSpace(out)
+
+	lines, err := r.gitLines("log", "--reverse", "--no-merges", "--format=%H%x00%s", ontoHash+"..HEAD")
+	if err != nil {
+		return RebasePlan{}, fmt.Errorf("rebase plan onto %s: %w", onto, err)
+	}
+
+	plan := RebasePlan{Onto: ontoHash, Steps: make([]RebaseStep, 0, len(lines))}
+	for _, line := range lines {
+		hash, subject, _ := strings.Cut(line, "\x00")
+		plan.Steps = append(plan.Steps, RebaseStep{
+			Action:  RebasePick,
+			Hash:    hash,
+			Subject: subject,
+		})
+	}
+	return plan, nil
+}
+
+// ApplyRebasePlan rewrites the current branch by replaying the plan's
+// steps, typically reordered or edited from RebasePlan, onto plan.Onto.
+// The steps are written as the sequencer todo list and the rebase runs
+// without user interaction; squashed commits keep their combined messages.
+//
+// If a step cannot be applied, a *RebaseConflictError is returned and the
+// rebase is left in progress. When the repository has a process supervisor
+// the rebase runs under it, so it can be cancelled through the supervisor.
+func (r *Repository) ApplyRebasePlan(plan RebasePlan) error {
+	if err := validateRebasePlan(plan); err != nil {
+		return err
+	}
+	steps := plan.Steps
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	todo, err := os.CreateTemp("", "keystorm-rebase-todo-*")
+	if err != nil {
+		return fmt.Errorf("create rebase todo: %w", err)
+	}
+	defer os.Remove(todo.Name())
+
+	var buf bytes.Buffer
+	for _, step := range steps {
+		buf.WriteString(step.String())
+		buf.WriteByte('\n')
+	}
+	if _, err := todo.Write(buf.Bytes()); err != nil {
+		todo.Close()
+		return fmt.Errorf("write rebase todo: %w", err)
+	}
+	if err := todo.Close(); err != nil {
+		return fmt.Errorf("write rebase todo: %w", err)
+	}
+
+	// The sequence editor replaces git's generated todo with the plan;
+	// the message editor accepts squash messages unchanged.
+	env := []string{
+		"GIT_SEQUENCE_EDITOR=cp " + shellQuote(todo.Name()),
+		"GIT_EDITOR=true",
+	}
+	_, runErr := r.gitSupervised(env, "rebase", "-i", plan.Onto)
+	r.statusCache = nil
+
+	if runErr != nil {
+		if conflict := r.rebaseConflict(); conflict != nil {
+			r.publishEvent("git.rebase.conflict", map[string]any{
+				"commit": conflict.Commit,
+				"files":  conflict.Files,
+			})
+			return conflict
+		}
+		return fmt.Errorf("apply rebase plan: %w", runErr)
+	}
+
+	r.publishEvent("git.branch.rebased", map[string]any{
+		"steps": len(steps),
+	})
+
+	return nil
+}
+
+// validateRebasePlan checks that a plan can be applied.
+func validateRebasePlan(plan RebasePlan) error {
+	if plan.Onto == "" || strings.HasPrefix(plan.Onto, "-") || strings.ContainsAny(plan.Onto, " \t\n") {
+		return fmt.Errorf("%w: invalid onto commit %q", ErrInvalidRebasePlan, plan.Onto)
+	}
+	if len(plan.Steps) == 0 {
+		return fmt.Errorf("%w: empty plan", ErrInvalidRebasePlan)
+	}
+
+	picked := false
+	for i, step := range plan.Steps {
+		if step.Hash == "" || strings.ContainsAny(step.Hash, " \t\n") {
+			return fmt.Errorf("%w: step %d has invalid commit %q", ErrInvalidRebasePlan, i, step.Hash)
+		}
+		if strings.ContainsAny(step.Subject, "\n\r") {
+			return fmt.Errorf("%w: step %d subject contains a newline", ErrInvalidRebasePlan, i)
+		}
+		switch step.Action {
+		case RebasePick:
+			picked = true
+		case RebaseSquash, RebaseFixup:
+			if !picked {
+				return fmt.Errorf("%w: step %d cannot %s without a previous commit", ErrInvalidRebasePlan, i, step.Action)
+			}
+		case RebaseDrop:
+		default:
+			return fmt.Errorf("%w: step %d has unsupported action %q", ErrInvalidRebasePlan, i, step.Action)
+		}
+	}
+	return nil
+}
+
+// rebaseConflict returns the conflict that stopped an in-progress rebase,
+// or nil if no rebase is stopped. Caller must hold the lock.
+func (r *Repository) rebaseConflict() *RebaseConflictError {
+	out, err := r.git("rev-parse", "--git-path", "rebase-merge")
+	if err != nil {
+		return nil
+	}
+	dir := strings.TrimSpace(out)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.path, dir)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil
+	}
+
+	conflict := &RebaseConflictError{}
+	if data, err := os.ReadFile(filepath.Join(dir, "stopped-sha")); err == nil {
+		conflict.Commit = strings.TrimSpace(string(data))
+	}
+	if head, err := r.git("rev-parse", "--verify", "-q", "REBASE_HEAD"); err == nil {
+		conflict.Commit = strings.TrimSpace(head)
+	}
+	conflict.Files, _ = r.gitLines("diff", "--name-only", "--diff-filter=U")
+	return conflict
+}
+
+// shortHash abbreviates a commit hash for display.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// shellQuote quotes s for use as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,232 @@
+package git
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/integration/process"
+)
+
+// linearHistory creates a base commit followed by commits one, two and three,
+// each adding a file. It returns the base commit hash.
+func linearHistory(t *testing.T, dir string) string {
+	t.Helper()
+
+	createFile(t, dir, "base.txt", "base")
+	gitCmd(t, dir, "add", ".")
+	gitCmd(t, dir, "commit", "-m", "base")
+	base := strings.TrimSpace(gitCmd(t, dir, "rev-parse", "HEAD"))
+
+	for _, name := range []string{"one", "two", "three"} {
+		createFile(t, dir, name+".txt", name)
+		gitCmd(t, dir, "add", ".")
+		gitCmd(t, dir, "commit", "-m", name)
+	}
+	return base
+}
+
+func TestRebasePlan(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+	base := linearHistory(t, dir)
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	plan, err := repo.RebasePlan(base)
+	if err != nil {
+		t.Fatalf("rebase plan: %v", err)
+	}
+	if plan.Onto != base {
+		t.Errorf("expected onto %s, got %s", base, plan.Onto)
+	}
+
+	steps := plan.Steps
+	if len(steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(steps))
+	}
+
+	wantSubjects := []string{"one", "two", "three"}
+	for i, step := range steps {
+		if step.Action != RebasePick {
+			t.Errorf("step %d: expected pick, got %s", i, step.Action)
+		}
+		if step.Subject != wantSubjects[i] {
+			t.Errorf("step %d: expected subject %q, got %q", i, wantSubjects[i], step.Subject)
+		}
+		if len(step.Hash) != 40 {
+			t.Errorf("step %d: expected full hash, got %q", i, step.Hash)
+		}
+	}
+
+	if got := steps[0].String(); got != "pick "+steps[0].Hash+" one" {
+		t.Errorf("unexpected todo line: %q", got)
+	}
+
+	// Nothing to rebase onto HEAD itself
+	plan, err = repo.RebasePlan("HEAD")
+	if err != nil {
+		t.Fatalf("rebase plan onto HEAD: %v", err)
+	}
+	if len(plan.Steps) != 0 {
+		t.Errorf("expected empty plan, got %d steps", len(plan.Steps))
+	}
+}
+
+func TestApplyRebasePlan(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+	base := linearHistory(t, dir)
+
+	supervisor := process.NewSupervisor()
+	mgr := NewManager(ManagerConfig{Supervisor: supervisor})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	plan, err := repo.RebasePlan(base)
+	if err != nil {
+		t.Fatalf("rebase plan: %v", err)
+	}
+
+	// Reorder: three first, fold two into one
+	steps := plan.Steps
+	plan.Steps = []RebaseStep{
+		steps[2],
+		steps[0],
+		{Action: RebaseFixup, Hash: steps[1].Hash, Subject: steps[1].Subject},
+	}
+	if err := repo.ApplyRebasePlan(plan); err != nil {
+		t.Fatalf("apply rebase plan: %v", err)
+	}
+
+	log := strings.Fields(gitCmd(t, dir, "log", "--format=%s", base+"..HEAD"))
+	want := []string{"one", "three"}
+	if strings.Join(log, ",") != strings.Join(want, ",") {
+		t.Errorf("expected history %v, got %v", want, log)
+	}
+
+	files := gitCmd(t, dir, "show", "--name-only", "--format=", "HEAD")
+	if !strings.Contains(files, "one.txt") || !strings.Contains(files, "two.txt") {
+		t.Errorf("expected squashed commit to contain one.txt and two.txt, got %q", files)
+	}
+}
+
+func TestApplyRebasePlan_Conflict(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	createFile(t, dir, "file.txt", "base\n")
+	gitCmd(t, dir, "add", ".")
+	gitCmd(t, dir, "commit", "-m", "base")
+	base := strings.TrimSpace(gitCmd(t, dir, "rev-parse", "HEAD"))
+
+	createFile(t, dir, "file.txt", "first\n")
+	gitCmd(t, dir, "commit", "-am", "first")
+	createFile(t, dir, "file.txt", "second\n")
+	gitCmd(t, dir, "commit", "-am", "second")
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	plan, err := repo.RebasePlan(base)
+	if err != nil {
+		t.Fatalf("rebase plan: %v", err)
+	}
+
+	// Swapping the commits makes "second" conflict when applied to base
+	steps := plan.Steps
+	plan.Steps = []RebaseStep{steps[1], steps[0]}
+	err = repo.ApplyRebasePlan(plan)
+	var conflict *RebaseConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected RebaseConflictError, got %v", err)
+	}
+	if !errors.Is(err, ErrConflict) {
+		t.Error("expected error to match ErrConflict")
+	}
+	if conflict.Commit != steps[1].Hash {
+		t.Errorf("expected conflict on %s, got %s", steps[1].Hash, conflict.Commit)
+	}
+	if len(conflict.Files) != 1 || conflict.Files[0] != "file.txt" {
+		t.Errorf("expected conflict in file.txt, got %v", conflict.Files)
+	}
+
+	if err := repo.AbortRebase(); err != nil {
+		t.Fatalf("abort rebase: %v", err)
+	}
+}
+
+func TestApplyRebasePlan_Onto(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+	base := linearHistory(t, dir)
+
+	// upstream moves on from base while the branch has one, two and three
+	gitCmd(t, dir, "checkout", "-q", "-b", "upstream", base)
+	createFile(t, dir, "up.txt", "up")
+	gitCmd(t, dir, "add", ".")
+	gitCmd(t, dir, "commit", "-m", "up")
+	gitCmd(t, dir, "checkout", "-q", "-")
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	plan, err := repo.RebasePlan("upstream")
+	if err != nil {
+		t.Fatalf("rebase plan: %v", err)
+	}
+	if err := repo.ApplyRebasePlan(plan); err != nil {
+		t.Fatalf("apply rebase plan: %v", err)
+	}
+
+	// The branch now sits on upstream rather than on base
+	log := strings.Fields(gitCmd(t, dir, "log", "--format=%s", base+"..HEAD"))
+	want := []string{"three", "two", "one", "up"}
+	if strings.Join(log, ",") != strings.Join(want, ",") {
+		t.Errorf("expected history %v, got %v", want, log)
+	}
+}
+
+func TestApplyRebasePlan_Invalid(t *testing.T) {
+	pick := []RebaseStep{{Action: RebasePick, Hash: "abc"}}
+	tests := []struct {
+		name string
+		plan RebasePlan
+	}{
+		{"empty", RebasePlan{Onto: "abc"}},
+		{"missing onto", RebasePlan{Steps: pick}},
+		{"option onto", RebasePlan{Onto: "--root", Steps: pick}},
+		{"squash first", RebasePlan{Onto: "abc", Steps: []RebaseStep{{Action: RebaseSquash, Hash: "abc"}}}},
+		{"unknown action", RebasePlan{Onto: "abc", Steps: []RebaseStep{{Action: "edit", Hash: "abc"}}}},
+		{"missing hash", RebasePlan{Onto: "abc", Steps: []RebaseStep{{Action: RebasePick}}}},
+		{"newline subject", RebasePlan{Onto: "abc", Steps: []RebaseStep{{Action: RebasePick, Hash: "abc", Subject: "a\nexec rm -rf /"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRebasePlan(tt.plan); !errors.Is(err, ErrInvalidRebasePlan) {
+				t.Errorf("expected ErrInvalidRebasePlan, got %v", err)
+			}
+		})
+	}
+}
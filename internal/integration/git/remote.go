@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -155,17 +156,20 @@ func (r *Repository) SetRemoteURL(name, url string, push bool) error {
 	return nil
 }
 
-// Fetch fetches from a remote.
-func (r *Repository) Fetch(opts FetchOptions) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
+// Fetch fetches from remote in the background, or from the default remote
+// if remote is empty. Progress is streamed on the returned channel, which
+// is closed after a final event with Done set. Progress a slow reader
+// misses is dropped, and a reader may stop early without blocking the
+// fetch. Cancelling ctx kills git. A fetch that needs credentials the
+// CredentialFunc cannot supply fails with ErrAuthenticationFailed. On
+// success git.fetch.completed and git.remote.updated are published.
+func (r *Repository) Fetch(ctx context.Context, remote string, opts FetchOptions) (<-chan ProgressEvent, error) {
 	args := []string{"fetch"}
 
 	if opts.All {
 		args = append(args, "--all")
-	} else if opts.Remote != "" {
-		args = append(args, opts.Remote)
+	} else if remote != "" {
+		args = append(args, remote)
 		if opts.RefSpec != "" {
 			args = append(args, opts.RefSpec)
 		}
@@ -183,25 +187,22 @@ func (r *Repository) Fetch(opts FetchOptions) error {
 		args = append(args, fmt.Sprintf("--depth=%d", opts.Depth))
 	}
 
-	output, err := r.git(args...)
-	if err != nil {
-		return fmt.Errorf("fetch: %w", err)
-	}
-
-	r.publishEvent("git.fetch.completed", map[string]any{
-		"remote": opts.Remote,
-		"all":    opts.All,
-		"output": output,
+	classify := func(remoteResult) error { return nil }
+	return r.runRemote(ctx, "fetch", args, false, classify, func(output string) {
+		r.publishEvent("git.fetch.completed", map[string]any{
+			"remote": remote,
+			"all":    opts.All,
+			"output": output,
+		})
+		r.publishEvent("git.remote.updated", map[string]any{
+			"remote":    remote,
+			"operation": "fetch",
+		})
 	})
-
-	return nil
 }
 
 // FetchOptions configures fetch behavior.
 type FetchOptions struct {
-	// Remote is the remote to fetch from.
-	Remote string
-
 	// RefSpec is the refspec to fetch.
 	RefSpec string
 
@@ -218,15 +219,16 @@ type FetchOptions struct {
 	Depth int
 }
 
-// Pull fetches and integrates changes from a remote.
-func (r *Repository) Pull(opts PullOptions) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
+// Pull fetches and integrates changes from remote in the background, or
+// from the upstream branch if remote is empty. Progress is reported as for
+// Fetch. The repository is locked until the pull finishes, whether or not
+// the final event is read. A pull that
+// stops with conflicts fails with ErrConflict.
+func (r *Repository) Pull(ctx context.Context, remote string, opts PullOptions) (<-chan ProgressEvent, error) {
 	args := []string{"pull"}
 
-	if opts.Remote != "" {
-		args = append(args, opts.Remote)
+	if remote != "" {
+		args = append(args, remote)
 		if opts.Branch != "" {
 			args = append(args, opts.Branch)
 		}
@@ -244,33 +246,32 @@ func (r *Repository) Pull(opts PullOptions) error {
 		args = append(args, "--no-ff")
 	}
 
-	output, err := r.git(args...)
-	if err != nil {
+	classify := func(result remoteResult) error {
 		// Check for merge conflicts
-		if strings.Contains(output, "CONFLICT") || strings.Contains(err.Error(), "CONFLICT") {
+		if strings.Contains(result.stdout, "CONFLICT") || strings.Contains(result.stderr, "CONFLICT") {
 			return ErrConflict
 		}
-		return fmt.Errorf("pull: %w", err)
-	}
-
-	// Invalidate status cache
-	r.statusCache = nil
-
-	r.publishEvent("git.pull.completed", map[string]any{
-		"remote": opts.Remote,
-		"branch": opts.Branch,
-		"rebase": opts.Rebase,
-		"output": output,
+		return nil
+	}
+	return r.runRemote(ctx, "pull", args, true, classify, func(output string) {
+		// Invalidate status cache
+		r.statusCache = nil
+
+		r.publishEvent("git.pull.completed", map[string]any{
+			"remote": remote,
+			"branch": opts.Branch,
+			"rebase": opts.Rebase,
+			"output": output,
+		})
+		r.publishEvent("git.remote.updated", map[string]any{
+			"remote":    remote,
+			"operation": "pull",
+		})
 	})
-
-	return nil
 }
 
 // PullOptions configures pull behavior.
 type PullOptions struct {
-	// Remote is the remote to pull from.
-	Remote string
-
 	// Branch is the branch to pull.
 	Branch string
 
@@ -284,15 +285,15 @@ type PullOptions struct {
 	NoFF bool
 }
 
-// Push pushes changes to a remote.
-func (r *Repository) Push(opts PushOptions) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
+// Push pushes changes to remote in the background, or to the default
+// remote if remote is empty. Progress is reported as for Fetch. A push the
+// remote refuses fails with ErrPushRejected, and a push of a branch without
+// an upstream fails with ErrNoUpstream.
+func (r *Repository) Push(ctx context.Context, remote string, opts PushOptions) (<-chan ProgressEvent, error) {
 	args := []string{"push"}
 
-	if opts.Remote != "" {
-		args = append(args, opts.Remote)
+	if remote != "" {
+		args = append(args, remote)
 		if opts.RefSpec != "" {
 			args = append(args, opts.RefSpec)
 		}
@@ -322,33 +323,32 @@ func (r *Repository) Push(opts PushOptions) error {
 		args = append(args, "--dry-run")
 	}
 
-	output, err := r.git(args...)
-	if err != nil {
+	classify := func(result remoteResult) error {
 		// Check for common push errors
-		if strings.Contains(err.Error(), "rejected") {
+		if strings.Contains(result.stderr, "rejected") {
 			return ErrPushRejected
 		}
-		if strings.Contains(err.Error(), "no upstream") {
+		if strings.Contains(result.stderr, "no upstream") {
 			return ErrNoUpstream
 		}
-		return fmt.Errorf("push: %w", err)
-	}
-
-	r.publishEvent("git.push.completed", map[string]any{
-		"remote":  opts.Remote,
-		"refSpec": opts.RefSpec,
-		"force":   opts.Force,
-		"output":  output,
+		return nil
+	}
+	return r.runRemote(ctx, "push", args, false, classify, func(output string) {
+		r.publishEvent("git.push.completed", map[string]any{
+			"remote":  remote,
+			"refSpec": opts.RefSpec,
+			"force":   opts.Force,
+			"output":  output,
+		})
+		r.publishEvent("git.remote.updated", map[string]any{
+			"remote":    remote,
+			"operation": "push",
+		})
 	})
-
-	return nil
 }
 
 // PushOptions configures push behavior.
 type PushOptions struct {
-	// Remote is the remote to push to.
-	Remote string
-
 	// RefSpec is the refspec to push.
 	RefSpec string
 
@@ -1,8 +1,17 @@
 package git
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/dshills/keystorm/internal/integration/process"
 )
 
 func TestListRemotes(t *testing.T) {
@@ -203,3 +212,286 @@ func TestGetUpstreamNoUpstream(t *testing.T) {
 		t.Errorf("expected ErrNoUpstream, got %v", err)
 	}
 }
+
+// remoteEventRecorder records published event types.
+type remoteEventRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *remoteEventRecorder) Publish(eventType string, data map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, eventType)
+}
+
+func (r *remoteEventRecorder) has(eventType string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// drainProgress reads events until the channel closes and returns the
+// final event.
+func drainProgress(t *testing.T, events <-chan ProgressEvent, timeout time.Duration) ProgressEvent {
+	t.Helper()
+	var last ProgressEvent
+	deadline := time.After(timeout)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				if !last.Done {
+					t.Fatal("progress channel closed without a final event")
+				}
+				return last
+			}
+			last = event
+		case <-deadline:
+			t.Fatal("remote operation did not finish")
+		}
+	}
+}
+
+func TestScanProgress(t *testing.T) {
+	output := "remote: Enumerating objects: 5, done.\n" +
+		"remote: Counting objects:  50% (2/4)\rremote: Counting objects: 100% (4/4), done.\n" +
+		"Receiving objects:  33% (1/3)\rReceiving objects: 100% (3/3), 1.20 KiB | 1.20 MiB/s, done.\n" +
+		"From /tmp/upstream\n" +
+		" * [new branch]      main       -> origin/main\n"
+
+	var events []ProgressEvent
+	other := scanProgress(strings.NewReader(output), func(e ProgressEvent) {
+		events = append(events, e)
+	})
+
+	want := []ProgressEvent{
+		{Phase: "Enumerating objects", Percent: -1, Current: 5, FromRemote: true},
+		{Phase: "Counting objects", Percent: 50, Current: 2, Total: 4, FromRemote: true},
+		{Phase: "Counting objects", Percent: 100, Current: 4, Total: 4, FromRemote: true},
+		{Phase: "Receiving objects", Percent: 33, Current: 1, Total: 3},
+		{Phase: "Receiving objects", Percent: 100, Current: 3, Total: 3},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d progress events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		got := events[i]
+		got.Message = ""
+		if got != w {
+			t.Errorf("event %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	if !strings.Contains(other, "[new branch]") || strings.Contains(other, "objects") {
+		t.Errorf("non-progress output = %q, want only the ref updates", other)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	upstream, cleanupUpstream := testRepo(t)
+	defer cleanupUpstream()
+	createFile(t, upstream, "file.txt", "content\n")
+	gitCmd(t, upstream, "add", ".")
+	gitCmd(t, upstream, "commit", "-m", "initial")
+
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+	gitCmd(t, dir, "remote", "add", "origin", upstream)
+
+	bus := &remoteEventRecorder{}
+	mgr := NewManager(ManagerConfig{EventBus: bus})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	events, err := repo.Fetch(context.Background(), "origin", FetchOptions{})
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	final := drainProgress(t, events, 30*time.Second)
+	if final.Err != nil {
+		t.Fatalf("fetch failed: %v", final.Err)
+	}
+	if final.Operation != "fetch" {
+		t.Errorf("final event operation = %q, want fetch", final.Operation)
+	}
+
+	remoteHead := strings.TrimSpace(gitCmd(t, dir, "rev-parse", "FETCH_HEAD"))
+	head := strings.TrimSpace(gitCmd(t, upstream, "rev-parse", "HEAD"))
+	if remoteHead != head {
+		t.Errorf("FETCH_HEAD = %s, want %s", remoteHead, head)
+	}
+	if !bus.has("git.remote.updated") {
+		t.Errorf("events = %v, want git.remote.updated", bus.events)
+	}
+}
+
+func TestFetchCancel(t *testing.T) {
+	upstream, cleanupUpstream := testRepo(t)
+	defer cleanupUpstream()
+
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+	gitCmd(t, dir, "remote", "add", "origin", upstream)
+	// The remote never answers
+	gitCmd(t, dir, "config", "remote.origin.uploadpack", "sleep 30;:")
+
+	supervisor := process.NewSupervisor()
+	defer supervisor.KillAll()
+	bus := &remoteEventRecorder{}
+	mgr := NewManager(ManagerConfig{Supervisor: supervisor, EventBus: bus})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := repo.Fetch(ctx, "origin", FetchOptions{})
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+
+	// Wait for git to start under the supervisor
+	deadline := time.Now().Add(5 * time.Second)
+	var procs []*process.Process
+	for len(procs) == 0 && time.Now().Before(deadline) {
+		procs = supervisor.List()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(procs) == 0 {
+		t.Fatal("fetch did not start a supervised process")
+	}
+
+	cancel()
+	final := drainProgress(t, events, 10*time.Second)
+	if !errors.Is(final.Err, context.Canceled) {
+		t.Errorf("final error = %v, want context.Canceled", final.Err)
+	}
+	if state := procs[0].State(); state != process.StateKilled {
+		t.Errorf("git process state = %v, want %v", state, process.StateKilled)
+	}
+	if bus.has("git.remote.updated") {
+		t.Error("cancelled fetch published git.remote.updated")
+	}
+}
+
+func TestFetchCredentials(t *testing.T) {
+	// Keep the user's credential helpers out of the test
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	var mu sync.Mutex
+	var users []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if user, pass, ok := req.BasicAuth(); ok {
+			mu.Lock()
+			users = append(users, user+":"+pass)
+			mu.Unlock()
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+	gitCmd(t, dir, "remote", "add", "origin", server.URL+"/repo.git")
+
+	var promptedURL string
+	mgr := NewManager(ManagerConfig{
+		Credentials: func(ctx context.Context, url string) (*Credential, error) {
+			promptedURL = url
+			return &Credential{Username: "alice", Password: "s3cret"}, nil
+		},
+	})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	events, err := repo.Fetch(context.Background(), "origin", FetchOptions{})
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	final := drainProgress(t, events, 30*time.Second)
+	if !errors.Is(final.Err, ErrAuthenticationFailed) {
+		t.Errorf("final error = %v, want ErrAuthenticationFailed", final.Err)
+	}
+	if !strings.HasPrefix(promptedURL, server.URL) {
+		t.Errorf("credentials requested for %q, want %s", promptedURL, server.URL)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(users) == 0 || users[0] != "alice:s3cret" {
+		t.Errorf("server received credentials %v, want alice:s3cret", users)
+	}
+}
+
+func TestPullUnreadReleasesRepository(t *testing.T) {
+	upstream, cleanupUpstream := testRepo(t)
+	defer cleanupUpstream()
+	createFile(t, upstream, "file.txt", "content\n")
+	gitCmd(t, upstream, "add", ".")
+	gitCmd(t, upstream, "commit", "-m", "initial")
+	branch := strings.TrimSpace(gitCmd(t, upstream, "rev-parse", "--abbrev-ref", "HEAD"))
+
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+	gitCmd(t, dir, "remote", "add", "origin", upstream)
+	gitCmd(t, dir, "pull", "origin", branch)
+
+	// Enough new objects for git to report more progress than is buffered
+	for i := 0; i < 300; i++ {
+		createFile(t, upstream, fmt.Sprintf("dir%d/file%d.txt", i%30, i), fmt.Sprintf("content %d\n", i))
+	}
+	gitCmd(t, upstream, "add", ".")
+	gitCmd(t, upstream, "commit", "-m", "many files")
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	events, err := repo.Pull(context.Background(), "origin", PullOptions{Branch: branch})
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+
+	// Nothing reads the events; once they fill the buffer the pull must
+	// still finish and release the repository
+	deadline := time.Now().Add(30 * time.Second)
+	for len(events) < progressEventBuffer && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	done := make(chan error, 1)
+	go func() { done <- repo.CreateBranch("after-pull", "") }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("create branch after pull: %v", err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("repository still locked by an unread pull")
+	}
+
+	final := drainProgress(t, events, 30*time.Second)
+	if final.Err != nil {
+		t.Errorf("pull failed: %v", final.Err)
+	}
+}
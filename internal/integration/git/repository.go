@@ -3,6 +3,7 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,6 +12,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/dshills/keystorm/internal/integration/process"
 )
 
 // Repository represents a git repository.
@@ -26,6 +29,12 @@ type Repository struct {
 
 	// Event publishing
 	eventBus EventPublisher
+
+	// Supervisor runs long operations so they can be cancelled (optional)
+	supervisor *process.Supervisor
+
+	// Credentials answers git's credential prompts for remote operations (optional)
+	credentials CredentialFunc
 }
 
 // openRepository opens an existing git repository.
@@ -175,6 +184,43 @@ func (r *Repository) git(args ...string) (string, error) {
 	return cmd.run()
 }
 
+// gitContext executes a git command that is killed when ctx is done.
+func (r *Repository) gitContext(ctx context.Context, args ...string) (string, error) {
+	cmd := newGitCommand(r.path, args...)
+	return cmd.runContext(ctx)
+}
+
+// gitSupervised executes a potentially long-running git command with extra
+// environment variables. If the repository has a process supervisor the
+// command is started under it, so it is tracked and can be terminated
+// through the supervisor; otherwise it runs directly.
+func (r *Repository) gitSupervised(env []string, args ...string) (string, error) {
+	cmd := newGitCommand(r.path, args...).toExecCmd()
+	cmd.Env = append(os.Environ(), env...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	var err error
+	if r.supervisor == nil {
+		err = cmd.Run()
+	} else {
+		cmd.Stdin = strings.NewReader("")
+		proc, startErr := r.supervisor.Start("git "+args[0], cmd)
+		if startErr != nil {
+			return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), startErr)
+		}
+		<-proc.Done()
+		err = proc.ExitError()
+	}
+
+	if err != nil {
+		return stdout.String(), fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
 // gitCommand represents a git command to execute outside a repository context.
 // This is used by Clone and other operations that don't require an existing repo.
 type gitCommand struct {
@@ -189,7 +235,12 @@ func newGitCommand(dir string, args ...string) *gitCommand {
 
 // run executes the git command.
 func (c *gitCommand) run() (string, error) {
-	cmd := exec.Command("git", c.args...)
+	return c.runContext(context.Background())
+}
+
+// runContext executes the git command, killing it when ctx is done.
+func (c *gitCommand) runContext(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", c.args...)
 	if c.dir != "" {
 		cmd.Dir = c.dir
 	}
@@ -199,6 +250,9 @@ func (c *gitCommand) run() (string, error) {
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", fmt.Errorf("git %s: %w", strings.Join(c.args, " "), ctxErr)
+		}
 		return "", fmt.Errorf("git %s: %s", strings.Join(c.args, " "), strings.TrimSpace(stderr.String()))
 	}
 
@@ -236,6 +290,12 @@ func (r *Repository) gitLines(args ...string) ([]string, error) {
 // Status returns the working tree status.
 // Results are cached for performance.
 func (r *Repository) Status() (*Status, error) {
+	return r.StatusContext(context.Background())
+}
+
+// StatusContext is like Status but kills the git commands it runs when ctx
+// is done.
+func (r *Repository) StatusContext(ctx context.Context) (*Status, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -244,7 +304,7 @@ func (r *Repository) Status() (*Status, error) {
 		return r.statusCache, nil
 	}
 
-	status, err := r.statusLocked()
+	status, err := r.statusLocked(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -255,13 +315,15 @@ func (r *Repository) Status() (*Status, error) {
 }
 
 // statusLocked fetches fresh status (caller must hold lock).
-func (r *Repository) statusLocked() (*Status, error) {
+func (r *Repository) statusLocked(ctx context.Context) (*Status, error) {
 	status := &Status{}
 
 	// Get branch info
-	branchOutput, err := r.git("branch", "--show-current")
+	branchOutput, err := r.gitContext(ctx, "branch", "--show-current")
 	if err == nil {
 		status.Branch = strings.TrimSpace(branchOutput)
+	} else if ctx.Err() != nil {
+		return nil, err
 	}
 
 	if status.Branch == "" {
@@ -275,12 +337,12 @@ func (r *Repository) statusLocked() (*Status, error) {
 
 	// Get ahead/behind counts
 	if status.Branch != "" && !status.IsDetached {
-		upstream, err := r.git("rev-parse", "--abbrev-ref", status.Branch+"@{upstream}")
+		upstream, err := r.gitContext(ctx, "rev-parse", "--abbrev-ref", status.Branch+"@{upstream}")
 		if err == nil {
 			status.Upstream = strings.TrimSpace(upstream)
 
 			// Get ahead/behind
-			revList, err := r.git("rev-list", "--left-right", "--count", status.Branch+"..."+status.Upstream)
+			revList, err := r.gitContext(ctx, "rev-list", "--left-right", "--count", status.Branch+"..."+status.Upstream)
 			if err == nil {
 				parts := strings.Fields(revList)
 				if len(parts) >= 2 {
@@ -292,7 +354,7 @@ func (r *Repository) statusLocked() (*Status, error) {
 	}
 
 	// Get file status using porcelain v2
-	output, err := r.git("status", "--porcelain=v2", "--branch", "--untracked-files=all")
+	output, err := r.gitContext(ctx, "status", "--porcelain=v2", "--branch", "--untracked-files=all")
 	if err != nil {
 		return nil, fmt.Errorf("git status: %w", err)
 	}
@@ -1,6 +1,8 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -86,6 +88,30 @@ func TestRepositoryStatus(t *testing.T) {
 	}
 }
 
+func TestRepositoryStatusContextCancelled(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := repo.StatusContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("StatusContext() error = %v, want context.Canceled", err)
+	}
+
+	// A cancelled call is not cached
+	if _, err := repo.StatusContext(context.Background()); err != nil {
+		t.Errorf("StatusContext() after cancel error = %v", err)
+	}
+}
+
 func TestRepositoryStatusStaged(t *testing.T) {
 	dir, cleanup := testRepo(t)
 	defer cleanup()
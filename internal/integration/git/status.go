@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -305,7 +306,7 @@ func (r *Repository) RefreshStatus() (*Status, error) {
 	// Clear cache to force refresh
 	r.statusCache = nil
 
-	status, err := r.statusLocked()
+	status, err := r.statusLocked(context.Background())
 	if err != nil {
 		return nil, err
 	}
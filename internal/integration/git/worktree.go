@@ -1,14 +1,22 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // Stage stages files for commit.
 // If no paths are provided, nothing is staged.
 func (r *Repository) Stage(paths ...string) error {
+	return r.StageContext(context.Background(), paths...)
+}
+
+// StageContext is like Stage but kills the git commands it runs when ctx is
+// done.
+func (r *Repository) StageContext(ctx context.Context, paths ...string) error {
 	if len(paths) == 0 {
 		return nil
 	}
@@ -23,7 +31,7 @@ func (r *Repository) Stage(paths ...string) error {
 			// File might be deleted, check if it's tracked
 			if os.IsNotExist(err) {
 				// Check if it's a deleted tracked file
-				output, gitErr := r.git("ls-files", "--error-unmatch", p)
+				output, gitErr := r.gitContext(ctx, "ls-files", "--error-unmatch", p)
 				if gitErr != nil || output == "" {
 					return fmt.Errorf("%w: %s", ErrPathNotFound, p)
 				}
@@ -35,7 +43,7 @@ func (r *Repository) Stage(paths ...string) error {
 
 	// Stage the files
 	args := append([]string{"add", "--"}, paths...)
-	if _, err := r.git(args...); err != nil {
+	if _, err := r.gitContext(ctx, args...); err != nil {
 		return fmt.Errorf("stage files: %w", err)
 	}
 
@@ -53,10 +61,16 @@ func (r *Repository) Stage(paths ...string) error {
 
 // StageAll stages all changes (tracked and untracked).
 func (r *Repository) StageAll() error {
+	return r.StageAllContext(context.Background())
+}
+
+// StageAllContext is like StageAll but kills the git commands it runs when
+// ctx is done.
+func (r *Repository) StageAllContext(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, err := r.git("add", "-A"); err != nil {
+	if _, err := r.gitContext(ctx, "add", "-A"); err != nil {
 		return fmt.Errorf("stage all: %w", err)
 	}
 
@@ -235,6 +249,12 @@ func (r *Repository) CleanAll() error {
 
 // Stash stashes working tree changes.
 func (r *Repository) Stash(message string) error {
+	return r.StashContext(context.Background(), message)
+}
+
+// StashContext is like Stash but kills the git commands it runs when ctx is
+// done.
+func (r *Repository) StashContext(ctx context.Context, message string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -243,7 +263,7 @@ func (r *Repository) Stash(message string) error {
 		args = append(args, "-m", message)
 	}
 
-	if _, err := r.git(args...); err != nil {
+	if _, err := r.gitContext(ctx, args...); err != nil {
 		return fmt.Errorf("stash: %w", err)
 	}
 
@@ -261,10 +281,16 @@ func (r *Repository) Stash(message string) error {
 
 // StashPop pops the most recent stash.
 func (r *Repository) StashPop() error {
+	return r.StashPopContext(context.Background())
+}
+
+// StashPopContext is like StashPop but kills the git commands it runs when
+// ctx is done.
+func (r *Repository) StashPopContext(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, err := r.git("stash", "pop"); err != nil {
+	if _, err := r.gitContext(ctx, "stash", "pop"); err != nil {
 		return fmt.Errorf("stash pop: %w", err)
 	}
 
@@ -291,3 +317,105 @@ func (r *Repository) StashList() ([]string, error) {
 
 	return lines, nil
 }
+
+// Worktree is a linked working tree checked out from the repository.
+type Worktree struct {
+	// Path is the absolute path of the working tree.
+	Path string
+
+	// Head is the commit checked out in the working tree.
+	Head string
+
+	// Branch is the checked out branch name, empty if detached.
+	Branch string
+
+	// Detached indicates HEAD is detached.
+	Detached bool
+
+	// Bare indicates the entry is a bare repository.
+	Bare bool
+
+	// Locked indicates the working tree is locked against pruning.
+	Locked bool
+}
+
+// AddWorktree checks out branch into a new working tree at path, allowing
+// parallel checkouts of the same repository. If the branch does not exist
+// it is created at HEAD.
+func (r *Repository) AddWorktree(path, branch string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	args := []string{"worktree", "add"}
+	if _, err := r.git("rev-parse", "--verify", "-q", "refs/heads/"+branch); err == nil {
+		args = append(args, "--", path, branch)
+	} else {
+		args = append(args, "-b", branch, "--", path)
+	}
+
+	if _, err := r.gitSupervised(nil, args...); err != nil {
+		return fmt.Errorf("add worktree %s: %w", path, err)
+	}
+
+	r.publishEvent("git.worktree.added", map[string]any{
+		"path":   path,
+		"branch": branch,
+	})
+
+	return nil
+}
+
+// ListWorktrees returns the main working tree followed by all linked ones.
+func (r *Repository) ListWorktrees() ([]Worktree, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	output, err := r.git("worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("list worktrees: %w", err)
+	}
+
+	return parseWorktreeList(output), nil
+}
+
+// parseWorktreeList parses the output of git worktree list --porcelain.
+// Entries are separated by blank lines.
+func parseWorktreeList(output string) []Worktree {
+	var (
+		worktrees []Worktree
+		current   *Worktree
+	)
+
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "worktree":
+			worktrees = append(worktrees, Worktree{Path: value})
+			current = &worktrees[len(worktrees)-1]
+		case "HEAD":
+			if current != nil {
+				current.Head = value
+			}
+		case "branch":
+			if current != nil {
+				current.Branch = strings.TrimPrefix(value, "refs/heads/")
+			}
+		case "detached":
+			if current != nil {
+				current.Detached = true
+			}
+		case "bare":
+			if current != nil {
+				current.Bare = true
+			}
+		case "locked":
+			if current != nil {
+				current.Locked = true
+			}
+		case "":
+			current = nil
+		}
+	}
+
+	return worktrees
+}
@@ -431,3 +431,73 @@ func TestRepositoryStageDeletion(t *testing.T) {
 		t.Errorf("expected StatusDeleted, got %v", status.Staged[0].Status)
 	}
 }
+
+func TestAddAndListWorktrees(t *testing.T) {
+	dir, cleanup := testRepo(t)
+	defer cleanup()
+
+	createFile(t, dir, "file.txt", "content")
+	gitCmd(t, dir, "add", "file.txt")
+	gitCmd(t, dir, "commit", "-m", "initial")
+
+	mgr := NewManager(ManagerConfig{})
+	defer mgr.Close()
+
+	repo, err := mgr.Open(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	if err := repo.AddWorktree(wtPath, "feature"); err != nil {
+		t.Fatalf("add worktree: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(wtPath, "file.txt")); err != nil {
+		t.Errorf("expected file.txt checked out in worktree: %v", err)
+	}
+
+	worktrees, err := repo.ListWorktrees()
+	if err != nil {
+		t.Fatalf("list worktrees: %v", err)
+	}
+	if len(worktrees) != 2 {
+		t.Fatalf("expected 2 worktrees, got %d", len(worktrees))
+	}
+
+	linked := worktrees[1]
+	if linked.Branch != "feature" {
+		t.Errorf("expected branch feature, got %q", linked.Branch)
+	}
+	if linked.Head == "" || linked.Detached {
+		t.Errorf("unexpected worktree state: %+v", linked)
+	}
+	if got, _ := filepath.EvalSymlinks(linked.Path); got != mustEvalSymlinks(t, wtPath) {
+		t.Errorf("expected path %s, got %s", wtPath, linked.Path)
+	}
+}
+
+func TestParseWorktreeList(t *testing.T) {
+	output := "worktree /repo\nHEAD abc123\nbranch refs/heads/main\n\n" +
+		"worktree /repo-detached\nHEAD def456\ndetached\nlocked\n\n"
+
+	worktrees := parseWorktreeList(output)
+	if len(worktrees) != 2 {
+		t.Fatalf("expected 2 worktrees, got %d", len(worktrees))
+	}
+	if worktrees[0].Path != "/repo" || worktrees[0].Branch != "main" || worktrees[0].Head != "abc123" {
+		t.Errorf("unexpected main worktree: %+v", worktrees[0])
+	}
+	if !worktrees[1].Detached || !worktrees[1].Locked || worktrees[1].Branch != "" {
+		t.Errorf("unexpected detached worktree: %+v", worktrees[1])
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatalf("eval symlinks: %v", err)
+	}
+	return resolved
+}
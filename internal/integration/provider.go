@@ -441,8 +441,8 @@ func (d *debugProviderAdapter) Continue(sessionID string) error {
 	if !ok {
 		return ErrSessionNotFound
 	}
-	// Use current thread from session
-	threadID := session.CurrentThread()
+	// Use the thread being inspected
+	threadID := session.ActiveThread()
 	return session.Continue(context.Background(), threadID)
 }
 
@@ -451,7 +451,7 @@ func (d *debugProviderAdapter) StepOver(sessionID string) error {
 	if !ok {
 		return ErrSessionNotFound
 	}
-	threadID := session.CurrentThread()
+	threadID := session.ActiveThread()
 	return session.Next(context.Background(), threadID)
 }
 
@@ -460,7 +460,7 @@ func (d *debugProviderAdapter) StepInto(sessionID string) error {
 	if !ok {
 		return ErrSessionNotFound
 	}
-	threadID := session.CurrentThread()
+	threadID := session.ActiveThread()
 	return session.StepIn(context.Background(), threadID)
 }
 
@@ -469,7 +469,7 @@ func (d *debugProviderAdapter) StepOut(sessionID string) error {
 	if !ok {
 		return ErrSessionNotFound
 	}
-	threadID := session.CurrentThread()
+	threadID := session.ActiveThread()
 	return session.StepOut(context.Background(), threadID)
 }
 
@@ -479,37 +479,19 @@ func (d *debugProviderAdapter) Variables(sessionID string) ([]api.DebugVariable,
 		return nil, ErrSessionNotFound
 	}
 
-	// Get stack trace to find the current frame's variables reference
-	threadID := session.CurrentThread()
-	frames, _, err := session.GetStackTrace(context.Background(), threadID, 0, 1)
+	// Variables of the top frame of the thread being inspected
+	vars, err := session.VariablesInThread(context.Background(), session.ActiveThread())
 	if err != nil {
 		return nil, err
 	}
 
-	if len(frames) == 0 {
-		return []api.DebugVariable{}, nil
-	}
-
-	// Get scopes for the current frame
-	scopes, err := session.GetScopes(context.Background(), frames[0].ID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Collect variables from all scopes
-	var result []api.DebugVariable
-	for _, scope := range scopes {
-		vars, err := session.GetVariables(context.Background(), scope.VariablesReference)
-		if err != nil {
-			continue // Skip scopes that fail
-		}
-		for _, v := range vars {
-			result = append(result, api.DebugVariable{
-				Name:  v.Name,
-				Value: v.Value,
-				Type:  v.Type,
-			})
-		}
+	result := make([]api.DebugVariable, 0, len(vars))
+	for _, v := range vars {
+		result = append(result, api.DebugVariable{
+			Name:  v.Name,
+			Value: v.Value,
+			Type:  v.Type,
+		})
 	}
 	return result, nil
 }
@@ -58,6 +58,15 @@
 //	}
 //
 // Built-in matchers are provided for common tools (Go, TypeScript, ESLint, etc.).
+// Matchers run against output with ANSI escape sequences stripped, so
+// colorized tool output is matched like plain output.
+//
+// # Output Retention
+//
+// Each output line carries its stream (stdout or stderr), a timestamp, the
+// raw colored text for display and the stripped plain text. Executions keep
+// the most recent ExecutorConfig.MaxOutputLines lines in a ring buffer,
+// available through Execution.OutputBuffer after the task completes.
 //
 // # Usage
 //
@@ -30,6 +30,10 @@ type ExecutorConfig struct {
 	// OutputBufferSize is the size of output buffers.
 	OutputBufferSize int
 
+	// MaxOutputLines bounds the output lines retained per execution; older
+	// lines are discarded once the limit is reached (0 = unlimited).
+	MaxOutputLines int
+
 	// MaxConcurrent is the maximum concurrent task executions (0 = unlimited).
 	MaxConcurrent int
 }
@@ -45,6 +49,7 @@ func DefaultExecutorConfig() ExecutorConfig {
 		DefaultShell:     shell,
 		DefaultShellArgs: []string{"-c"},
 		OutputBufferSize: 64 * 1024, // 64KB
+		MaxOutputLines:   10000,
 		MaxConcurrent:    4,
 	}
 }
@@ -426,6 +431,7 @@ func (e *Executor) runExecution(ctx context.Context, exec *Execution, extraEnv m
 
 	// Create output processor
 	outputProc := NewOutputProcessor(e.config.OutputBufferSize)
+	outputProc.SetMaxLines(e.config.MaxOutputLines)
 	exec.mu.Lock()
 	exec.output = outputProc
 	exec.mu.Unlock()
@@ -650,9 +656,9 @@ func (e *Executor) processOutput(exec *Execution, r io.Reader, stream OutputStre
 		// Notify listeners
 		e.notifyOutput(exec, line)
 
-		// Check for problems
+		// Check for problems, ignoring color codes
 		if matcher != nil {
-			if problem, ok := matcher.Match(line.Content); ok {
+			if problem, ok := matcher.Match(line.Plain); ok {
 				exec.mu.Lock()
 				exec.Problems = append(exec.Problems, problem)
 				exec.mu.Unlock()
@@ -810,6 +816,22 @@ func (ex *Execution) Output() []OutputLine {
 	return ex.output.Lines()
 }
 
+// OutputBuffer returns the most recent maxLines retained output lines, or
+// all retained lines if maxLines is 0 or less. Output is retained after the
+// execution completes, up to ExecutorConfig.MaxOutputLines lines.
+func (ex *Execution) OutputBuffer(maxLines int) []OutputLine {
+	ex.mu.RLock()
+	defer ex.mu.RUnlock()
+
+	if ex.output == nil {
+		return nil
+	}
+	if maxLines <= 0 {
+		return ex.output.Lines()
+	}
+	return ex.output.LastLines(maxLines)
+}
+
 // StdoutLines returns only stdout lines.
 func (ex *Execution) StdoutLines() []OutputLine {
 	ex.mu.RLock()
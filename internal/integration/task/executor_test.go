@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -514,6 +515,72 @@ func TestExecution_Output(t *testing.T) {
 	}
 }
 
+func TestExecution_ProblemMatchingColoredOutput(t *testing.T) {
+	e := NewExecutor(DefaultExecutorConfig())
+
+	// Colorized go test output, as produced by tools like gotestsum
+	task := &Task{
+		Name:           "colored-go-test",
+		Type:           TaskTypeShell,
+		Command:        "printf",
+		Args:           []string{`\033[31m--- FAIL: TestAdd (0.00s)\033[0m\n    \033[1mmath_test.go:12:\033[0m got 3, want 4\n`},
+		ProblemMatcher: "$go-test",
+	}
+
+	exec, err := e.ExecuteSync(context.Background(), task)
+	if err != nil {
+		t.Fatalf("ExecuteSync() error = %v", err)
+	}
+
+	if len(exec.Problems) != 1 {
+		t.Fatalf("len(Problems) = %d, want 1: %+v", len(exec.Problems), exec.Problems)
+	}
+	problem := exec.Problems[0]
+	if problem.File != "math_test.go" || problem.Line != 12 || problem.Message != "got 3, want 4" {
+		t.Errorf("Problem = %+v, want math_test.go:12 got 3, want 4", problem)
+	}
+
+	output := exec.Output()
+	if len(output) != 2 {
+		t.Fatalf("len(Output()) = %d, want 2: %+v", len(output), output)
+	}
+	if !strings.Contains(output[1].Content, "\x1b[1m") {
+		t.Errorf("Content = %q, want colors preserved for display", output[1].Content)
+	}
+}
+
+func TestExecution_OutputBufferBounded(t *testing.T) {
+	config := DefaultExecutorConfig()
+	config.MaxOutputLines = 5
+	e := NewExecutor(config)
+
+	task := &Task{
+		Name:    "many-lines",
+		Type:    TaskTypeShell,
+		Command: "seq",
+		Args:    []string{"-f", "line%g", "200"},
+	}
+
+	exec, err := e.ExecuteSync(context.Background(), task)
+	if err != nil {
+		t.Fatalf("ExecuteSync() error = %v", err)
+	}
+
+	// Output remains available after completion, capped at MaxOutputLines
+	lines := exec.OutputBuffer(0)
+	if len(lines) != 5 {
+		t.Fatalf("len(OutputBuffer(0)) = %d, want 5", len(lines))
+	}
+	if lines[4].Content != "line200" || lines[4].Stream != OutputStreamStdout {
+		t.Errorf("last line = %+v, want stdout line200", lines[4])
+	}
+
+	lines = exec.OutputBuffer(2)
+	if len(lines) != 2 || lines[0].Content != "line199" {
+		t.Errorf("OutputBuffer(2) = %v, want [line199 line200]", lines)
+	}
+}
+
 func TestExecution_Cancel(t *testing.T) {
 	e := NewExecutor(DefaultExecutorConfig())
 
@@ -3,6 +3,8 @@ package task
 import (
 	"bufio"
 	"io"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 )
@@ -31,9 +33,14 @@ func (s OutputStream) String() string {
 
 // OutputLine represents a single line of output.
 type OutputLine struct {
-	// Content is the line content (without newline).
+	// Content is the line content (without newline), including any ANSI
+	// escape sequences, for colored display.
 	Content string
 
+	// Plain is Content with ANSI escape sequences removed. Problem
+	// matchers run against Plain.
+	Plain string
+
 	// Stream identifies the source (stdout or stderr).
 	Stream OutputStream
 
@@ -46,9 +53,12 @@ type OutputLine struct {
 
 // OutputProcessor handles output stream processing.
 type OutputProcessor struct {
-	// lines stores all output lines.
+	// lines stores all output lines when unbounded.
 	lines []OutputLine
 
+	// ring stores the most recent lines when bounded by SetMaxLines.
+	ring *OutputBuffer
+
 	// bufferSize is the maximum buffer size for reading.
 	bufferSize int
 
@@ -83,14 +93,20 @@ func (p *OutputProcessor) Process(r io.Reader, stream OutputStream, callback fun
 		p.lineCount++
 		lineNum := p.lineCount
 
+		content := scanner.Text()
 		line := OutputLine{
-			Content:    scanner.Text(),
+			Content:    content,
+			Plain:      StripANSI(content),
 			Stream:     stream,
 			Timestamp:  time.Now(),
 			LineNumber: lineNum,
 		}
 
-		p.lines = append(p.lines, line)
+		if p.ring != nil {
+			p.ring.Add(line)
+		} else {
+			p.lines = append(p.lines, line)
+		}
 		p.mu.Unlock()
 
 		if callback != nil {
@@ -115,13 +131,45 @@ func (p *OutputProcessor) ProcessAsync(r io.Reader, stream OutputStream, callbac
 	return done
 }
 
+// SetMaxLines bounds the number of retained lines, keeping the most recent
+// ones in a ring buffer. Lines already captured beyond the limit are dropped.
+// A value of 0 or less removes the bound.
+func (p *OutputProcessor) SetMaxLines(maxLines int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lines := p.storedLocked()
+	if maxLines <= 0 {
+		p.ring = nil
+		p.lines = append([]OutputLine(nil), lines...)
+		return
+	}
+
+	p.ring = NewOutputBuffer(maxLines)
+	p.lines = nil
+	for _, line := range lines {
+		p.ring.Add(line)
+	}
+}
+
+// storedLocked returns the retained lines in order.
+// The result must not be modified. Caller must hold the lock.
+func (p *OutputProcessor) storedLocked() []OutputLine {
+	if p.ring != nil {
+		return p.ring.Lines()
+	}
+	return p.lines
+}
+
 // Lines returns all captured output lines.
+// If the processor is bounded, only the most recent lines are returned.
 func (p *OutputProcessor) Lines() []OutputLine {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	result := make([]OutputLine, len(p.lines))
-	copy(result, p.lines)
+	lines := p.storedLocked()
+	result := make([]OutputLine, len(lines))
+	copy(result, lines)
 	return result
 }
 
@@ -131,7 +179,7 @@ func (p *OutputProcessor) StdoutLines() []OutputLine {
 	defer p.mu.RUnlock()
 
 	var result []OutputLine
-	for _, line := range p.lines {
+	for _, line := range p.storedLocked() {
 		if line.Stream == OutputStreamStdout {
 			result = append(result, line)
 		}
@@ -145,7 +193,7 @@ func (p *OutputProcessor) StderrLines() []OutputLine {
 	defer p.mu.RUnlock()
 
 	var result []OutputLine
-	for _, line := range p.lines {
+	for _, line := range p.storedLocked() {
 		if line.Stream == OutputStreamStderr {
 			result = append(result, line)
 		}
@@ -165,19 +213,27 @@ func (p *OutputProcessor) LastLines(n int) []OutputLine {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	if n <= 0 || len(p.lines) == 0 {
+	if n <= 0 {
+		return nil
+	}
+	if p.ring != nil {
+		return p.ring.Last(n)
+	}
+
+	lines := p.lines
+	if len(lines) == 0 {
 		return nil
 	}
 
-	if n >= len(p.lines) {
-		result := make([]OutputLine, len(p.lines))
-		copy(result, p.lines)
+	if n >= len(lines) {
+		result := make([]OutputLine, len(lines))
+		copy(result, lines)
 		return result
 	}
 
-	start := len(p.lines) - n
+	start := len(lines) - n
 	result := make([]OutputLine, n)
-	copy(result, p.lines[start:])
+	copy(result, lines[start:])
 	return result
 }
 
@@ -186,21 +242,22 @@ func (p *OutputProcessor) Content() string {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	if len(p.lines) == 0 {
+	lines := p.storedLocked()
+	if len(lines) == 0 {
 		return ""
 	}
 
 	// Calculate total size
 	size := 0
-	for _, line := range p.lines {
+	for _, line := range lines {
 		size += len(line.Content) + 1 // +1 for newline
 	}
 
 	// Build string
 	result := make([]byte, 0, size)
-	for i, line := range p.lines {
+	for i, line := range lines {
 		result = append(result, line.Content...)
-		if i < len(p.lines)-1 {
+		if i < len(lines)-1 {
 			result = append(result, '\n')
 		}
 	}
@@ -256,6 +313,9 @@ func (p *OutputProcessor) Clear() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.lines = p.lines[:0]
+	if p.ring != nil {
+		p.ring.Clear()
+	}
 	p.lineCount = 0
 }
 
@@ -307,6 +367,23 @@ func (b *OutputBuffer) Lines() []OutputLine {
 	return result
 }
 
+// Last returns the most recent n lines in order.
+// If n is 0 or less, or exceeds the count, all lines are returned.
+func (b *OutputBuffer) Last(n int) []OutputLine {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if n <= 0 || n > b.count {
+		n = b.count
+	}
+	result := make([]OutputLine, n)
+	start := b.head + b.count - n
+	for i := 0; i < n; i++ {
+		result[i] = b.lines[(start+i)%b.capacity]
+	}
+	return result
+}
+
 // Count returns the number of lines in the buffer.
 func (b *OutputBuffer) Count() int {
 	b.mu.RLock()
@@ -321,3 +398,16 @@ func (b *OutputBuffer) Clear() {
 	b.head = 0
 	b.count = 0
 }
+
+// ansiPattern matches ANSI escape sequences: CSI sequences (colors, cursor
+// movement), OSC sequences terminated by BEL or ST, and other escapes such
+// as charset selection.
+var ansiPattern = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[ -/]*[0-~])`)
+
+// StripANSI removes ANSI escape sequences from s.
+func StripANSI(s string) string {
+	if !strings.ContainsRune(s, '\x1b') {
+		return s
+	}
+	return ansiPattern.ReplaceAllString(s, "")
+}
@@ -1,6 +1,7 @@
 package task
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -281,6 +282,104 @@ func TestOutputBuffer_Clear(t *testing.T) {
 	}
 }
 
+func TestOutputBuffer_Last(t *testing.T) {
+	b := NewOutputBuffer(3)
+	for i := 0; i < 5; i++ {
+		b.Add(OutputLine{Content: string(rune('a' + i))})
+	}
+
+	lines := b.Last(2)
+	if len(lines) != 2 || lines[0].Content != "d" || lines[1].Content != "e" {
+		t.Errorf("Last(2) = %v, want [d e]", lines)
+	}
+	if got := len(b.Last(10)); got != 3 {
+		t.Errorf("len(Last(10)) = %d, want 3", got)
+	}
+}
+
+func TestOutputProcessor_SetMaxLines(t *testing.T) {
+	p := NewOutputProcessor(1024)
+	p.SetMaxLines(10)
+
+	var input strings.Builder
+	for i := 1; i <= 1000; i++ {
+		fmt.Fprintf(&input, "line %d\n", i)
+	}
+	p.Process(strings.NewReader(input.String()), OutputStreamStdout, nil)
+
+	lines := p.Lines()
+	if len(lines) != 10 {
+		t.Fatalf("len(Lines()) = %d, want 10", len(lines))
+	}
+	if lines[0].Content != "line 991" || lines[9].Content != "line 1000" {
+		t.Errorf("Lines() = %q .. %q, want line 991 .. line 1000", lines[0].Content, lines[9].Content)
+	}
+	if lines[9].LineNumber != 1000 {
+		t.Errorf("LineNumber = %d, want 1000", lines[9].LineNumber)
+	}
+	if p.LineCount() != 1000 {
+		t.Errorf("LineCount() = %d, want 1000", p.LineCount())
+	}
+	if len(p.lines) != 0 || p.ring.capacity != 10 {
+		t.Errorf("storage not bounded: %d unbounded lines, ring capacity %d", len(p.lines), p.ring.capacity)
+	}
+	if last := p.LastLines(2); len(last) != 2 || last[1].Content != "line 1000" {
+		t.Errorf("LastLines(2) = %v", last)
+	}
+
+	// Shrinking keeps the newest lines; removing the bound keeps them too
+	p.SetMaxLines(3)
+	p.SetMaxLines(0)
+	if got := p.Content(); got != "line 998\nline 999\nline 1000" {
+		t.Errorf("Content() = %q", got)
+	}
+}
+
+func TestOutputProcessor_PlainText(t *testing.T) {
+	p := NewOutputProcessor(1024)
+
+	var received OutputLine
+	p.Process(strings.NewReader("\x1b[1;31mFAIL\x1b[0m ok"), OutputStreamStderr, func(line OutputLine) {
+		received = line
+	})
+
+	if received.Content != "\x1b[1;31mFAIL\x1b[0m ok" {
+		t.Errorf("Content = %q, want colored text preserved", received.Content)
+	}
+	if received.Plain != "FAIL ok" {
+		t.Errorf("Plain = %q, want %q", received.Plain, "FAIL ok")
+	}
+	if received.Stream != OutputStreamStderr || received.Timestamp.IsZero() {
+		t.Errorf("line missing stream tag or timestamp: %+v", received)
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "no escapes", "no escapes"},
+		{"sgr", "\x1b[31mred\x1b[0m", "red"},
+		{"truecolor", "\x1b[38;2;255;0;0mred\x1b[m", "red"},
+		{"cursor", "\x1b[2K\x1b[1Gprogress", "progress"},
+		{"private mode", "\x1b[?25lhidden", "hidden"},
+		{"osc bel", "\x1b]0;title\x07text", "text"},
+		{"osc st", "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"two byte", "\x1b=keypad", "keypad"},
+		{"charset", "\x1b(Bascii", "ascii"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripANSI(tt.input); got != tt.want {
+				t.Errorf("StripANSI(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestOutputLine_Fields(t *testing.T) {
 	now := time.Now()
 	line := OutputLine{
@@ -0,0 +1,100 @@
+package terminal
+
+import "time"
+
+// BellMode controls how a terminal responds to the BEL character.
+type BellMode string
+
+const (
+	// BellAudible calls the OnBell callback, typically to play a sound.
+	BellAudible BellMode = "audible"
+
+	// BellVisual flashes the screen: the screen's bell flag is set for
+	// VisualBellDuration so the renderer can draw it inverted.
+	BellVisual BellMode = "visual"
+
+	// BellIgnore ignores BEL entirely, without publishing an event.
+	BellIgnore BellMode = "ignore"
+)
+
+// DefaultBellMode is the bell mode used when none is configured.
+const DefaultBellMode = BellAudible
+
+// DefaultBellInterval is the minimum time between bells. Bells within the
+// interval of the previous one are dropped, so a stream of BELs, such as
+// from catting a binary file, rings once.
+const DefaultBellInterval = 200 * time.Millisecond
+
+// VisualBellDuration is how long the screen's bell flag stays set for a
+// visual bell, long enough for the renderer to show at least one frame.
+const VisualBellDuration = 100 * time.Millisecond
+
+// ParseBellMode returns the bell mode named by s, as configured by the
+// terminal.bell setting. The names "sound" and "none" are accepted for
+// audible and ignore. Unknown names return DefaultBellMode and false.
+func ParseBellMode(s string) (BellMode, bool) {
+	switch s {
+	case "audible", "sound":
+		return BellAudible, true
+	case "visual":
+		return BellVisual, true
+	case "ignore", "none":
+		return BellIgnore, true
+	default:
+		return DefaultBellMode, false
+	}
+}
+
+// BellMode returns how the terminal responds to BEL.
+func (t *Terminal) BellMode() BellMode {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.bellMode
+}
+
+// SetBellMode changes how the terminal responds to BEL. An empty mode
+// selects DefaultBellMode.
+func (t *Terminal) SetBellMode(mode BellMode) {
+	if mode == "" {
+		mode = DefaultBellMode
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.bellMode = mode
+}
+
+// ringBell handles a BEL received from the shell.
+func (t *Terminal) ringBell() {
+	now := time.Now()
+
+	t.mu.Lock()
+	mode := t.bellMode
+	interval := t.bellInterval
+	if interval <= 0 {
+		interval = DefaultBellInterval
+	}
+	if mode == BellIgnore || (!t.lastBell.IsZero() && now.Sub(t.lastBell) < interval) {
+		t.mu.Unlock()
+		return
+	}
+	t.lastBell = now
+	onBell := t.onBell
+	onBellEvent := t.onBellEvent
+	t.mu.Unlock()
+
+	switch mode {
+	case BellVisual:
+		t.screen.SetBell(true)
+		time.AfterFunc(VisualBellDuration, func() {
+			t.screen.SetBell(false)
+		})
+	default:
+		if onBell != nil {
+			onBell()
+		}
+	}
+
+	if onBellEvent != nil {
+		onBellEvent(mode)
+	}
+}
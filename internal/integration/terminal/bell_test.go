@@ -0,0 +1,156 @@
+package terminal
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newBellTerminal returns a terminal without a process whose parser rings
+// its bell.
+func newBellTerminal(mode BellMode, interval time.Duration) *Terminal {
+	screen := NewScreen(80, 24)
+	term := &Terminal{
+		screen:       screen,
+		parser:       NewParser(screen),
+		bellMode:     mode,
+		bellInterval: interval,
+	}
+	term.parser.SetBellCallback(term.ringBell)
+	return term
+}
+
+func TestParseBellMode(t *testing.T) {
+	tests := []struct {
+		name string
+		want BellMode
+		ok   bool
+	}{
+		{"audible", BellAudible, true},
+		{"sound", BellAudible, true},
+		{"visual", BellVisual, true},
+		{"ignore", BellIgnore, true},
+		{"none", BellIgnore, true},
+		{"loud", DefaultBellMode, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseBellMode(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseBellMode(%q) = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTerminalBellRateLimit(t *testing.T) {
+	term := newBellTerminal(BellAudible, time.Hour)
+	var rings, events atomic.Int32
+	term.onBell = func() { rings.Add(1) }
+	term.onBellEvent = func(mode BellMode) {
+		if mode != BellAudible {
+			t.Errorf("bell event mode = %v, want %v", mode, BellAudible)
+		}
+		events.Add(1)
+	}
+
+	term.parser.ParseString("make: \x07\x07\x07done\x07")
+
+	if got := rings.Load(); got != 1 {
+		t.Errorf("OnBell called %d times, want 1", got)
+	}
+	if got := events.Load(); got != 1 {
+		t.Errorf("bell event published %d times, want 1", got)
+	}
+	if got := term.screen.GetText(); !strings.HasPrefix(got, "make: done") {
+		t.Errorf("screen text = %q, want BEL not printed", got)
+	}
+
+	// Once the interval passes the bell rings again
+	term.mu.Lock()
+	term.lastBell = time.Now().Add(-2 * time.Hour)
+	term.mu.Unlock()
+	term.parser.ParseString("\x07")
+	if got := rings.Load(); got != 2 {
+		t.Errorf("OnBell called %d times after interval, want 2", got)
+	}
+}
+
+func TestTerminalBellVisual(t *testing.T) {
+	term := newBellTerminal(BellVisual, 0)
+	var rings atomic.Int32
+	term.onBell = func() { rings.Add(1) }
+
+	if term.screen.Bell() {
+		t.Fatal("Bell() = true before BEL")
+	}
+	term.parser.ParseString("\x07")
+	if !term.screen.Bell() {
+		t.Error("Bell() = false after visual BEL")
+	}
+	if rings.Load() != 0 {
+		t.Error("OnBell called in visual mode")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for term.screen.Bell() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if term.screen.Bell() {
+		t.Error("Bell() still set after VisualBellDuration")
+	}
+}
+
+func TestTerminalBellIgnore(t *testing.T) {
+	term := newBellTerminal(BellIgnore, 0)
+	var events atomic.Int32
+	term.onBell = func() { events.Add(1) }
+	term.onBellEvent = func(BellMode) { events.Add(1) }
+
+	term.parser.ParseString("\x07")
+	if events.Load() != 0 || term.screen.Bell() {
+		t.Error("ignored bell should have no effect")
+	}
+
+	term.SetBellMode("")
+	if term.BellMode() != DefaultBellMode {
+		t.Errorf("BellMode() = %v after SetBellMode(\"\"), want %v", term.BellMode(), DefaultBellMode)
+	}
+}
+
+func TestManagerBellEvent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping terminal test in short mode")
+	}
+
+	pub := &mockEventPublisher{}
+	m := NewManager(ManagerConfig{EventBus: pub, Bell: BellVisual})
+	defer m.Shutdown(5 * time.Second)
+
+	term, err := m.Create(Options{Name: "test"})
+	if err != nil {
+		t.Skipf("skipping: failed to create terminal (may not have PTY): %v", err)
+	}
+	defer term.Close()
+
+	if term.BellMode() != BellVisual {
+		t.Errorf("BellMode() = %v, want manager default %v", term.BellMode(), BellVisual)
+	}
+
+	for i := 0; i < 5; i++ {
+		term.ringBell()
+	}
+
+	count := 0
+	for _, e := range pub.getEvents() {
+		if e.eventType != "terminal.bell" {
+			continue
+		}
+		count++
+		if e.data["id"] != term.ID() || e.data["mode"] != "visual" {
+			t.Errorf("terminal.bell data = %v, want id and visual mode", e.data)
+		}
+	}
+	if count != 1 {
+		t.Errorf("published %d terminal.bell events, want 1", count)
+	}
+}
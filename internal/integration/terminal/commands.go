@@ -0,0 +1,219 @@
+package terminal
+
+import (
+	"strconv"
+	"strings"
+)
+
+// maxCommandBlocks is the number of shell commands a screen remembers.
+const maxCommandBlocks = 1000
+
+// CommandBlock is one shell command as marked by OSC 133 shell integration:
+// the prompt, the command typed at it, and the output it produced.
+//
+// Lines are absolute: line 0 is the first line the terminal displayed, and
+// lines keep their number when they scroll into the history. Fields are -1
+// until the shell has marked them.
+type CommandBlock struct {
+	// PromptLine is the line the prompt starts on (OSC 133;A).
+	PromptLine int
+
+	// CommandLine and CommandColumn are where command input starts, after
+	// the prompt (OSC 133;B).
+	CommandLine   int
+	CommandColumn int
+
+	// Command is the command text, read from the screen when the command
+	// is executed (OSC 133;C).
+	Command string
+
+	// OutputStart is the first line of output and OutputEnd the line after
+	// the last (OSC 133;C and D).
+	OutputStart int
+	OutputEnd   int
+
+	// ExitCode is the exit status reported when the command finished
+	// (OSC 133;D), or -1 if none was reported.
+	ExitCode int
+
+	// Finished is true once the command has finished or a new prompt was
+	// shown.
+	Finished bool
+}
+
+// Running returns true if the command was executed and has not finished.
+func (c CommandBlock) Running() bool {
+	return c.OutputStart >= 0 && !c.Finished
+}
+
+// handleSemanticPrompt processes the payload of an OSC 133 sequence:
+// "A" (prompt start), "B" (command start), "C" (command executed) and
+// "D[;exit code]" (command finished). Further ';' separated options are
+// ignored.
+func (p *Parser) handleSemanticPrompt(value string) {
+	fields := strings.Split(value, ";")
+	switch fields[0] {
+	case "A":
+		p.screen.MarkPromptStart()
+	case "B":
+		p.screen.MarkCommandStart()
+	case "C":
+		p.screen.MarkCommandExecuted()
+	case "D":
+		exitCode := -1
+		if len(fields) > 1 {
+			if code, err := strconv.Atoi(fields[1]); err == nil {
+				exitCode = code
+			}
+		}
+		p.screen.MarkCommandFinished(exitCode)
+	}
+}
+
+// MarkPromptStart starts a new command block at the cursor line. A
+// command still running is considered finished.
+func (s *Screen) MarkPromptStart() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.finishCommandLocked(-1)
+	if len(s.commands) == maxCommandBlocks {
+		s.commands = append(s.commands[:0], s.commands[1:]...)
+	}
+	s.commands = append(s.commands, CommandBlock{
+		PromptLine:    s.absoluteLineLocked(s.cursorY),
+		CommandLine:   -1,
+		CommandColumn: -1,
+		OutputStart:   -1,
+		OutputEnd:     -1,
+		ExitCode:      -1,
+	})
+}
+
+// MarkCommandStart marks the cursor position as the start of command
+// input in the current command block.
+func (s *Screen) MarkCommandStart() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	block := s.currentCommandLocked()
+	if block == nil {
+		return
+	}
+	block.CommandLine = s.absoluteLineLocked(s.cursorY)
+	block.CommandColumn = s.cursorX
+}
+
+// MarkCommandExecuted records the command text typed since
+// MarkCommandStart and starts the output at the cursor line.
+func (s *Screen) MarkCommandExecuted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	block := s.currentCommandLocked()
+	if block == nil {
+		return
+	}
+	if block.CommandLine >= 0 {
+		block.Command = s.commandTextLocked(block.CommandLine, block.CommandColumn)
+	}
+	block.OutputStart = s.absoluteLineLocked(s.cursorY)
+}
+
+// MarkCommandFinished ends the output of the current command block at the
+// cursor and records its exit code (-1 if unknown).
+func (s *Screen) MarkCommandFinished(exitCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finishCommandLocked(exitCode)
+}
+
+// Commands returns the shell commands marked by OSC 133 shell
+// integration, oldest first.
+func (s *Screen) Commands() []CommandBlock {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]CommandBlock, len(s.commands))
+	copy(result, s.commands)
+	return result
+}
+
+// AbsoluteLine converts a screen row to an absolute line, as used by
+// CommandBlock.
+func (s *Screen) AbsoluteLine(y int) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.absoluteLineLocked(y)
+}
+
+// absoluteLineLocked converts a screen row to an absolute line.
+// Caller must hold the lock.
+func (s *Screen) absoluteLineLocked(y int) int {
+	return s.scrolledLines + y
+}
+
+// currentCommandLocked returns the most recent command block unless it has
+// finished. Caller must hold the lock.
+func (s *Screen) currentCommandLocked() *CommandBlock {
+	if len(s.commands) == 0 {
+		return nil
+	}
+	block := &s.commands[len(s.commands)-1]
+	if block.Finished {
+		return nil
+	}
+	return block
+}
+
+// finishCommandLocked finishes the current command block. Output ends
+// before the cursor line, or after it if the cursor is past the start of
+// the line. Caller must hold the lock.
+func (s *Screen) finishCommandLocked(exitCode int) {
+	block := s.currentCommandLocked()
+	if block == nil {
+		return
+	}
+	block.Finished = true
+	block.ExitCode = exitCode
+	if block.OutputStart < 0 {
+		return
+	}
+	end := s.absoluteLineLocked(s.cursorY)
+	if s.cursorX > 0 {
+		end++
+	}
+	block.OutputEnd = max(end, block.OutputStart)
+}
+
+// commandTextLocked returns the text from the given absolute position to
+// the cursor, joining soft-wrapped lines. Lines that have scrolled off the
+// screen are skipped. Caller must hold the lock.
+func (s *Screen) commandTextLocked(line, column int) string {
+	startY := line - s.scrolledLines
+	if startY < 0 {
+		startY, column = 0, 0
+	}
+
+	var b strings.Builder
+	for y := startY; y <= s.cursorY && y < len(s.lines); y++ {
+		cells := s.lines[y].Cells
+		from, to := 0, len(cells)
+		if y == startY {
+			from = min(column, to)
+		}
+		if y == s.cursorY {
+			to = min(s.cursorX, to)
+		}
+
+		var text []rune
+		for x := from; x < to; x++ {
+			text = append(text, cells[x].Rune)
+		}
+		b.WriteString(strings.TrimRight(string(text), " "))
+		if y < s.cursorY && !s.lines[y].Wrapped {
+			b.WriteByte('\n')
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,109 @@
+package terminal
+
+import (
+	"testing"
+)
+
+// Shell integration marks as emitted by a shell, terminated by BEL.
+const (
+	markPrompt   = "\x1b]133;A\x07"
+	markCommand  = "\x1b]133;B\x07"
+	markExecuted = "\x1b]133;C\x07"
+)
+
+func markFinished(code string) string {
+	return "\x1b]133;D;" + code + "\x07"
+}
+
+func TestParserSemanticPrompts(t *testing.T) {
+	s := NewScreen(40, 5)
+	p := NewParser(s)
+
+	// A successful command with two lines of output, a failing one, and a
+	// prompt waiting for input. The screen scrolls on the way.
+	p.Parse([]byte(markPrompt + "$ " + markCommand + "echo hi\r\n" + markExecuted +
+		"hi\r\nthere\r\n" + markFinished("0")))
+	p.Parse([]byte(markPrompt + "$ " + markCommand + "false\r\n" + markExecuted + markFinished("1")))
+	p.Parse([]byte(markPrompt + "$ " + markCommand + "sleep 10\r\n" + markExecuted))
+
+	got := s.Commands()
+	want := []CommandBlock{
+		{PromptLine: 0, CommandLine: 0, CommandColumn: 2, Command: "echo hi", OutputStart: 1, OutputEnd: 3, ExitCode: 0, Finished: true},
+		{PromptLine: 3, CommandLine: 3, CommandColumn: 2, Command: "false", OutputStart: 4, OutputEnd: 4, ExitCode: 1, Finished: true},
+		{PromptLine: 4, CommandLine: 4, CommandColumn: 2, Command: "sleep 10", OutputStart: 5, OutputEnd: -1, ExitCode: -1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Commands() = %+v, want %d commands", got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("command %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if !got[2].Running() || got[1].Running() {
+		t.Errorf("Running() = %v, %v, want false, true", got[1].Running(), got[2].Running())
+	}
+
+	// A new prompt ends the running command without an exit code
+	p.Parse([]byte("^C\r\n" + markPrompt))
+	got = s.Commands()
+	if last := got[2]; !last.Finished || last.ExitCode != -1 || last.OutputEnd != 6 {
+		t.Errorf("interrupted command = %+v, want finished at line 6 without exit code", last)
+	}
+}
+
+func TestParserSemanticPromptWrappedCommand(t *testing.T) {
+	s := NewScreen(10, 5)
+	p := NewParser(s)
+
+	p.Parse([]byte(markPrompt + "$ " + markCommand + "echo 123456789\r\n" + markExecuted + markFinished("")))
+
+	got := s.Commands()
+	if len(got) != 1 {
+		t.Fatalf("Commands() = %+v, want 1 command", got)
+	}
+	if got[0].Command != "echo 123456789" {
+		t.Errorf("Command = %q, want %q", got[0].Command, "echo 123456789")
+	}
+	if got[0].ExitCode != -1 {
+		t.Errorf("ExitCode = %d, want -1 for an empty status", got[0].ExitCode)
+	}
+}
+
+func TestParserSemanticPromptForwarded(t *testing.T) {
+	s := NewScreen(40, 5)
+	p := NewParser(s)
+
+	var got []string
+	p.SetOSCCallback(func(cmd int, data string) {
+		if cmd == 133 {
+			got = append(got, data)
+		}
+	})
+	p.Parse([]byte(markPrompt + "$ " + markCommand + "true\r\n" + markExecuted + markFinished("0")))
+
+	want := []string{"A", "B", "C", "D;0"}
+	if len(got) != len(want) {
+		t.Fatalf("forwarded marks = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("forwarded marks = %q, want %q", got, want)
+			break
+		}
+	}
+	if len(s.Commands()) != 1 {
+		t.Errorf("Commands() = %+v, want 1 command", s.Commands())
+	}
+}
+
+func TestParserSemanticPromptWithoutPrompt(t *testing.T) {
+	s := NewScreen(40, 5)
+	p := NewParser(s)
+
+	// Marks outside a prompt are ignored
+	p.Parse([]byte(markCommand + "ls\r\n" + markExecuted + markFinished("0")))
+	if got := s.Commands(); len(got) != 0 {
+		t.Errorf("Commands() = %+v, want none", got)
+	}
+}
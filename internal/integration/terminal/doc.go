@@ -5,8 +5,9 @@
 //   - PTY management for Unix and ConPTY for Windows
 //   - ANSI escape sequence parsing (CSI, SGR, OSC)
 //   - Screen buffer with cell-based rendering
-//   - Scrollback history
-//   - Shell integration (working directory tracking)
+//   - Scrollback history, fed by lines scrolled off the screen
+//   - Text selection (linear or block) across scrollback and screen
+//   - Shell integration (working directory tracking, command blocks)
 //
 // # Architecture
 //
@@ -41,6 +42,9 @@
 //	// Write to terminal
 //	term.Write([]byte("ls -la\n"))
 //
+//	// Paste text (bracketed if the program enabled bracketed paste)
+//	term.Paste([]byte("line one\nline two\n"))
+//
 //	// Read screen state
 //	screen := term.Screen()
 //	for y := 0; y < screen.Height; y++ {
@@ -50,15 +54,57 @@
 //	    }
 //	}
 //
+// # Selection
+//
+// Terminal.StartSelection and ExtendSelection take screen positions in the
+// current view (see SetScrollOffset). SelectedText trims trailing whitespace
+// from each line and joins soft-wrapped lines, so copied output has no
+// artificial line breaks. ToggleBlockSelection switches to rectangular
+// selection.
+//
 // # ANSI Support
 //
 // The parser supports common ANSI escape sequences:
 //
 //   - CSI sequences for cursor movement and screen control
-//   - SGR sequences for colors and text attributes
+//   - SGR sequences for colors and text attributes, including 24-bit
+//     color in both the ';' and ':' separated forms
 //   - OSC sequences for title and shell integration
+//   - OSC 8 hyperlinks, queried with Screen.HyperlinkAt
 //   - DEC private modes
 //
+// # Shell Integration
+//
+// Shells that emit OSC 133 semantic prompt sequences (A: prompt, B: command
+// input, C: command executed, D;status: command finished) get their
+// commands recorded. Terminal.Commands returns one CommandBlock per
+// command with its prompt line, command text, output lines and exit code,
+// for jumping between prompts or re-running commands. Lines are numbered
+// absolutely, so they stay valid as output scrolls into the history.
+//
+// # Bell
+//
+// BEL is handled according to the terminal's BellMode, set from the
+// terminal.bell setting: BellAudible calls Options.OnBell, BellVisual sets
+// Screen.Bell for VisualBellDuration so the renderer can flash the screen,
+// and BellIgnore drops it. Bells are rate limited to one per BellInterval,
+// and the Manager publishes a "terminal.bell" event for each bell rung.
+//
+// # Recording
+//
+// Terminal.StartRecording writes the terminal's raw output, with its
+// timing, as an asciinema v2 cast that asciinema can play back or attach
+// to bug reports. StopRecording, or closing the terminal, ends it.
+// ReplayCast feeds a cast back through the parser to reproduce the
+// recorded screen without a shell:
+//
+//	f, _ := os.Create("session.cast")
+//	_ = term.StartRecording(f, terminal.RecordAsciicastV2)
+//	// ...
+//	_ = term.StopRecording()
+//
+//	screen, err := terminal.ReplayCast(bytes.NewReader(cast))
+//
 // # Thread Safety
 //
 // All types in this package are safe for concurrent use.
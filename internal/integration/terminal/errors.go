@@ -21,4 +21,16 @@ var (
 
 	// ErrManagerClosed is returned when operations are attempted on a closed manager.
 	ErrManagerClosed = errors.New("terminal manager is closed")
+
+	// ErrRecordingActive is returned when starting a recording while one is in progress.
+	ErrRecordingActive = errors.New("terminal is already recording")
+
+	// ErrNotRecording is returned when stopping a recording that was not started.
+	ErrNotRecording = errors.New("terminal is not recording")
+
+	// ErrUnsupportedRecordFormat is returned for an unknown recording format.
+	ErrUnsupportedRecordFormat = errors.New("unsupported recording format")
+
+	// ErrInvalidCast is returned when reading a malformed asciicast recording.
+	ErrInvalidCast = errors.New("invalid asciicast recording")
 )
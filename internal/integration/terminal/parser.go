@@ -12,6 +12,7 @@ type Parser struct {
 	// Parser state
 	state  parserState
 	params []int
+	colons []bool // colons[i] is true if params[i] is a ':' sub-parameter
 	inter  []byte // intermediate bytes
 	osc    []byte // OSC data
 
@@ -24,6 +25,7 @@ type Parser struct {
 	onTitle   func(string)
 	onOSC     func(cmd int, data string)
 	onUnknown func(seq string)
+	onBell    func()
 }
 
 type parserState int
@@ -45,6 +47,7 @@ func NewParser(screen *Screen) *Parser {
 		screen: screen,
 		state:  stateGround,
 		params: make([]int, 0, 16),
+		colons: make([]bool, 0, 16),
 		inter:  make([]byte, 0, 4),
 		osc:    make([]byte, 0, 256),
 	}
@@ -60,6 +63,11 @@ func (p *Parser) SetOSCCallback(fn func(cmd int, data string)) {
 	p.onOSC = fn
 }
 
+// SetBellCallback sets the callback for the BEL character.
+func (p *Parser) SetBellCallback(fn func()) {
+	p.onBell = fn
+}
+
 // SetUnknownCallback sets the callback for unknown sequences.
 func (p *Parser) SetUnknownCallback(fn func(seq string)) {
 	p.onUnknown = fn
@@ -115,9 +123,12 @@ func (p *Parser) processGround(b byte) {
 		}
 		p.state = stateEscape
 		p.params = p.params[:0]
+		p.colons = p.colons[:0]
 		p.inter = p.inter[:0]
 	case b == 0x07: // BEL
-		// Bell - ignore
+		if p.onBell != nil {
+			p.onBell()
+		}
 	case b == 0x08: // BS - Backspace
 		p.screen.MoveCursorRelative(-1, 0)
 	case b == 0x09: // HT - Tab
@@ -266,10 +277,11 @@ func (p *Parser) processEscapeInter(b byte) {
 func (p *Parser) processCSI(b byte) {
 	switch {
 	case b >= '0' && b <= '9':
-		p.params = append(p.params, int(b-'0'))
+		p.addParam(false)
+		p.params[len(p.params)-1] = int(b - '0')
 		p.state = stateCSIParam
-	case b == ';':
-		p.params = append(p.params, 0)
+	case b == ';', b == ':':
+		p.addParam(false)
 		p.state = stateCSIParam
 	case b == '?', b == '>', b == '!': // Private mode prefix
 		p.inter = append(p.inter, b)
@@ -288,11 +300,13 @@ func (p *Parser) processCSIParam(b byte) {
 	switch {
 	case b >= '0' && b <= '9':
 		if len(p.params) == 0 {
-			p.params = append(p.params, 0)
+			p.addParam(false)
 		}
 		p.params[len(p.params)-1] = p.params[len(p.params)-1]*10 + int(b-'0')
 	case b == ';':
-		p.params = append(p.params, 0)
+		p.addParam(false)
+	case b == ':':
+		p.addParam(true)
 	case b >= 0x20 && b <= 0x2F: // Intermediate
 		p.inter = append(p.inter, b)
 		p.state = stateCSIInter
@@ -304,6 +318,13 @@ func (p *Parser) processCSIParam(b byte) {
 	}
 }
 
+// addParam starts a new CSI parameter. sub marks a ':' separated
+// sub-parameter, as used by ITU T.416 colors (38:2::r:g:b).
+func (p *Parser) addParam(sub bool) {
+	p.params = append(p.params, 0)
+	p.colons = append(p.colons, sub)
+}
+
 func (p *Parser) processCSIInter(b byte) {
 	switch {
 	case b >= 0x20 && b <= 0x2F: // More intermediate
@@ -529,7 +550,7 @@ func (p *Parser) handlePrivateMode(set bool) {
 		case 1049: // Alternate screen buffer with save/restore cursor
 			// TODO: Implement alternate buffer
 		case 2004: // Bracketed paste mode
-			// Ignore
+			p.screen.SetBracketedPaste(set)
 		}
 	}
 }
@@ -667,6 +688,9 @@ func (p *Parser) parseExtendedColor(i int, foreground bool) int {
 	if i+1 >= len(p.params) {
 		return i
 	}
+	if p.colons[i+1] {
+		return p.parseColonColor(i, foreground)
+	}
 
 	switch p.params[i+1] {
 	case 5: // 256-color
@@ -704,6 +728,43 @@ func (p *Parser) parseExtendedColor(i int, foreground bool) int {
 	return i
 }
 
+// parseColonColor handles the ITU T.416 form of extended colors, where
+// sub-parameters are separated by colons: 38:5:idx, 38:2:r:g:b, or
+// 38:2:colorspace:r:g:b (colorspace is usually empty and always ignored).
+// Returns the index of the last sub-parameter consumed.
+func (p *Parser) parseColonColor(i int, foreground bool) int {
+	end := i + 1
+	for end+1 < len(p.params) && p.colons[end+1] {
+		end++
+	}
+	sub := p.params[i+1 : end+1]
+
+	var color Color
+	switch {
+	case sub[0] == 5 && len(sub) >= 2:
+		idx := sub[1]
+		if idx < 0 {
+			idx = 0
+		} else if idx > 255 {
+			idx = 255
+		}
+		color = ColorFromIndex(idx)
+	case sub[0] == 2 && len(sub) >= 5:
+		color = ColorFromRGB(clampColorValue(sub[2]), clampColorValue(sub[3]), clampColorValue(sub[4]))
+	case sub[0] == 2 && len(sub) == 4:
+		color = ColorFromRGB(clampColorValue(sub[1]), clampColorValue(sub[2]), clampColorValue(sub[3]))
+	default:
+		return end
+	}
+
+	if foreground {
+		p.screen.SetForeground(color)
+	} else {
+		p.screen.SetBackground(color)
+	}
+	return end
+}
+
 // clampColorValue clamps an integer to valid RGB range (0-255).
 func clampColorValue(v int) uint8 {
 	if v < 0 {
@@ -745,6 +806,14 @@ func (p *Parser) handleOSC() {
 		if p.onTitle != nil {
 			p.onTitle(value)
 		}
+	case 8: // Hyperlink: OSC 8 ; params ; URI ST
+		p.handleHyperlink(value)
+	case 133: // Semantic prompt: OSC 133 ; A|B|C|D[;exit code] ST
+		p.handleSemanticPrompt(value)
+		// Also forwarded, so hosts can react to shell integration marks
+		if p.onOSC != nil {
+			p.onOSC(cmd, value)
+		}
 	default:
 		if p.onOSC != nil {
 			p.onOSC(cmd, value)
@@ -752,6 +821,24 @@ func (p *Parser) handleOSC() {
 	}
 }
 
+// handleHyperlink processes the payload of an OSC 8 sequence. The params
+// field is a ':' separated list of key=value pairs, of which only id is
+// used. An empty URI closes the current hyperlink.
+func (p *Parser) handleHyperlink(value string) {
+	params, uri, ok := strings.Cut(value, ";")
+	if !ok {
+		return
+	}
+
+	id := ""
+	for _, kv := range strings.Split(params, ":") {
+		if v, found := strings.CutPrefix(kv, "id="); found {
+			id = v
+		}
+	}
+	p.screen.SetHyperlink(uri, id)
+}
+
 func (p *Parser) param(index, defaultValue int) int {
 	if index < len(p.params) && p.params[index] > 0 {
 		return p.params[index]
@@ -307,6 +307,43 @@ func TestParserSGRRGBBackground(t *testing.T) {
 	}
 }
 
+func TestParserSGRRGBColonForm(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		fg, bg  Color
+		wantFg  bool
+		wantBg  bool
+		trailer rune
+	}{
+		{"fg with colorspace", "\x1b[38:2::10:20:30mX", ColorFromRGB(10, 20, 30), Color{}, true, false, 'X'},
+		{"fg without colorspace", "\x1b[38:2:10:20:30mX", ColorFromRGB(10, 20, 30), Color{}, true, false, 'X'},
+		{"bg with colorspace", "\x1b[48:2::40:50:60mX", Color{}, ColorFromRGB(40, 50, 60), false, true, 'X'},
+		{"fg and bg with bold", "\x1b[1;38:2::1:2:3;48:2::4:5:6mX", ColorFromRGB(1, 2, 3), ColorFromRGB(4, 5, 6), true, true, 'X'},
+		{"256 color", "\x1b[38:5:196mX", ColorFromIndex(196), Color{}, true, false, 'X'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScreen(80, 24)
+			p := NewParser(s)
+
+			p.Parse([]byte(tt.input))
+
+			cell := s.Cell(0, 0)
+			if cell.Rune != tt.trailer {
+				t.Errorf("Rune = %q, want %q", cell.Rune, tt.trailer)
+			}
+			if tt.wantFg && cell.Foreground != tt.fg {
+				t.Errorf("Foreground = %+v, want %+v", cell.Foreground, tt.fg)
+			}
+			if tt.wantBg && cell.Background != tt.bg {
+				t.Errorf("Background = %+v, want %+v", cell.Background, tt.bg)
+			}
+		})
+	}
+}
+
 func TestParserSGRUnderline(t *testing.T) {
 	s := NewScreen(80, 24)
 	p := NewParser(s)
@@ -450,6 +487,42 @@ func TestParserOSCCallback(t *testing.T) {
 	}
 }
 
+func TestParserOSCHyperlink(t *testing.T) {
+	terminators := map[string]string{
+		"BEL": "\x07",
+		"ST":  "\x1b\\",
+	}
+
+	for name, st := range terminators {
+		t.Run(name, func(t *testing.T) {
+			s := NewScreen(10, 5)
+			p := NewParser(s)
+
+			// Link text wraps onto the second line
+			p.Parse([]byte("ab\x1b]8;id=x;https://example.com" + st + "linktext!" + "\x1b]8;;" + st + "cd"))
+
+			for i, pos := range [][2]int{{2, 0}, {9, 0}, {0, 1}} {
+				uri, ok := s.HyperlinkAt(pos[0], pos[1])
+				if !ok || uri != "https://example.com" {
+					t.Errorf("%d: HyperlinkAt(%d, %d) = %q, %v, want link", i, pos[0], pos[1], uri, ok)
+				}
+			}
+			if link := s.Cell(2, 0).Link; link == nil || link.ID != "x" {
+				t.Errorf("Link = %+v, want id x", link)
+			}
+
+			for _, pos := range [][2]int{{0, 0}, {1, 0}, {1, 1}, {2, 1}, {-1, 0}, {10, 0}} {
+				if uri, ok := s.HyperlinkAt(pos[0], pos[1]); ok {
+					t.Errorf("HyperlinkAt(%d, %d) = %q, want no link", pos[0], pos[1], uri)
+				}
+			}
+			if got := s.Cell(1, 1).Rune; got != 'c' {
+				t.Errorf("Cell(1, 1) = %q, want 'c'", got)
+			}
+		})
+	}
+}
+
 func TestParserCSIScrollUp(t *testing.T) {
 	s := NewScreen(80, 24)
 	p := NewParser(s)
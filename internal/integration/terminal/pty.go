@@ -55,6 +55,10 @@ func (p *ptyWrapper) Close() error {
 type History struct {
 	lines    []*Line
 	maxLines int
+
+	// trimmed counts lines dropped from the front, so that
+	// trimmed+index identifies a line even as old lines are discarded.
+	trimmed int
 }
 
 // NewHistory creates a new history buffer.
@@ -81,6 +85,7 @@ func (h *History) Add(line *Line) {
 
 	// Trim if exceeds max
 	if len(h.lines) > h.maxLines {
+		h.trimmed += len(h.lines) - h.maxLines
 		h.lines = h.lines[len(h.lines)-h.maxLines:]
 	}
 }
@@ -100,6 +105,7 @@ func (h *History) Len() int {
 
 // Clear clears the history.
 func (h *History) Clear() {
+	h.trimmed += len(h.lines)
 	h.lines = h.lines[:0]
 }
 
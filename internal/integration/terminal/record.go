@@ -0,0 +1,285 @@
+package terminal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// RecordFormat is the file format of a terminal recording.
+type RecordFormat string
+
+const (
+	// RecordAsciicastV2 is the asciinema v2 cast format: a JSON header line
+	// followed by one JSON array per event, [time, type, data], with time
+	// in seconds since the start of the recording.
+	RecordAsciicastV2 RecordFormat = "asciicast-v2"
+)
+
+// Cast event types.
+const (
+	// CastEventOutput is output written to the terminal.
+	CastEventOutput = "o"
+
+	// CastEventResize is a terminal resize, with data "COLSxROWS".
+	CastEventResize = "r"
+)
+
+// CastHeader is the header line of an asciicast v2 recording.
+type CastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// CastEvent is one event of an asciicast v2 recording.
+type CastEvent struct {
+	// Time is the time of the event in seconds since the recording started.
+	Time float64
+
+	// Type is the event type, such as CastEventOutput.
+	Type string
+
+	// Data is the event data, such as the output text.
+	Data string
+}
+
+// StartRecording starts recording the terminal's output to w in format,
+// with the time of each output chunk, for reproducing rendering bugs or
+// making demos. The recording ends with StopRecording or Close. Write
+// errors stop recording the remaining output and are returned by
+// StopRecording.
+func (t *Terminal) StartRecording(w io.Writer, format RecordFormat) error {
+	if format != RecordAsciicastV2 {
+		return fmt.Errorf("%w: %q", ErrUnsupportedRecordFormat, format)
+	}
+	if t.closed.Load() {
+		return ErrTerminalClosed
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.recorder != nil {
+		return ErrRecordingActive
+	}
+
+	header := CastHeader{
+		Version:   2,
+		Width:     t.screen.Width(),
+		Height:    t.screen.Height(),
+		Timestamp: time.Now().Unix(),
+		Title:     t.name,
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	}
+	if t.cmd != nil {
+		header.Env["SHELL"] = t.cmd.Path
+	}
+
+	rec, err := newCastRecorder(w, header, time.Now)
+	if err != nil {
+		return err
+	}
+	t.recorder = rec
+	return nil
+}
+
+// StopRecording ends the recording started by StartRecording and returns
+// the first error writing it, if any.
+func (t *Terminal) StopRecording() error {
+	t.mu.Lock()
+	rec := t.recorder
+	t.recorder = nil
+	t.mu.Unlock()
+
+	if rec == nil {
+		return ErrNotRecording
+	}
+	return rec.close()
+}
+
+// IsRecording returns true if the terminal's output is being recorded.
+func (t *Terminal) IsRecording() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.recorder != nil
+}
+
+// activeRecorder returns the current recorder, or nil.
+func (t *Terminal) activeRecorder() *castRecorder {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.recorder
+}
+
+// castRecorder writes an asciicast v2 recording.
+type castRecorder struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	now   func() time.Time
+
+	// pending holds an incomplete UTF-8 sequence at the end of the last
+	// output chunk, so that characters split across reads are not mangled
+	pending []byte
+
+	err error
+}
+
+// newCastRecorder writes header to w and returns a recorder for the events.
+func newCastRecorder(w io.Writer, header CastHeader, now func() time.Time) (*castRecorder, error) {
+	line, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(line, '\n')); err != nil {
+		return nil, err
+	}
+	return &castRecorder{w: w, start: now(), now: now}, nil
+}
+
+// output records an output chunk.
+func (r *castRecorder) output(data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data = append(r.pending, data...)
+	r.pending = nil
+	if tail := incompleteRuneLen(data); tail > 0 {
+		r.pending = append([]byte(nil), data[len(data)-tail:]...)
+		data = data[:len(data)-tail]
+	}
+	if len(data) > 0 {
+		r.writeEventLocked(CastEventOutput, string(data))
+	}
+}
+
+// resize records a terminal resize.
+func (r *castRecorder) resize(cols, rows int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writeEventLocked(CastEventResize, fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// close flushes any pending output and returns the first write error.
+func (r *castRecorder) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) > 0 {
+		r.writeEventLocked(CastEventOutput, string(r.pending))
+		r.pending = nil
+	}
+	return r.err
+}
+
+// writeEventLocked writes one event line. Invalid UTF-8 is replaced with
+// U+FFFD, as asciicast data must be valid JSON strings.
+func (r *castRecorder) writeEventLocked(eventType, data string) {
+	if r.err != nil {
+		return
+	}
+
+	// Microsecond precision, as written by asciinema
+	elapsed := math.Round(r.now().Sub(r.start).Seconds()*1e6) / 1e6
+	line, err := json.Marshal([]any{elapsed, eventType, data})
+	if err != nil {
+		r.err = err
+		return
+	}
+	_, r.err = r.w.Write(append(line, '\n'))
+}
+
+// incompleteRuneLen returns the length of the incomplete UTF-8 sequence at
+// the end of data, or 0.
+func incompleteRuneLen(data []byte) int {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		b := data[len(data)-i]
+		if utf8.RuneStart(b) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				return i
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// ReadCast reads an asciicast v2 recording.
+func ReadCast(r io.Reader) (CastHeader, []CastEvent, error) {
+	br := bufio.NewReader(r)
+
+	var header CastHeader
+	line, err := br.ReadBytes('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && len(line) > 0) {
+		return header, nil, fmt.Errorf("%w: missing header", ErrInvalidCast)
+	}
+	if err := json.Unmarshal(line, &header); err != nil {
+		return header, nil, fmt.Errorf("%w: header: %v", ErrInvalidCast, err)
+	}
+	if header.Version != 2 {
+		return header, nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidCast, header.Version)
+	}
+
+	var events []CastEvent
+	for lineNum := 2; ; lineNum++ {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 && len(bytes.TrimSpace(line)) > 0 {
+			var fields []json.RawMessage
+			var event CastEvent
+			if jerr := json.Unmarshal(line, &fields); jerr != nil || len(fields) != 3 {
+				return header, events, fmt.Errorf("%w: line %d: malformed event", ErrInvalidCast, lineNum)
+			}
+			if json.Unmarshal(fields[0], &event.Time) != nil ||
+				json.Unmarshal(fields[1], &event.Type) != nil ||
+				json.Unmarshal(fields[2], &event.Data) != nil {
+				return header, events, fmt.Errorf("%w: line %d: malformed event", ErrInvalidCast, lineNum)
+			}
+			events = append(events, event)
+		}
+		if errors.Is(err, io.EOF) {
+			return header, events, nil
+		}
+		if err != nil {
+			return header, events, err
+		}
+	}
+}
+
+// ReplayCast feeds an asciicast v2 recording through a parser and returns
+// the resulting screen, sized and resized as recorded. It reproduces the
+// rendering of a recorded session without a shell.
+func ReplayCast(r io.Reader) (*Screen, error) {
+	header, events, err := ReadCast(r)
+	if err != nil {
+		return nil, err
+	}
+	if header.Width < 1 || header.Height < 1 {
+		return nil, fmt.Errorf("%w: invalid size %dx%d", ErrInvalidCast, header.Width, header.Height)
+	}
+
+	screen := NewScreen(header.Width, header.Height)
+	parser := NewParser(screen)
+	for _, event := range events {
+		switch event.Type {
+		case CastEventOutput:
+			parser.ParseString(event.Data)
+		case CastEventResize:
+			var cols, rows int
+			if _, err := fmt.Sscanf(event.Data, "%dx%d", &cols, &rows); err == nil && cols > 0 && rows > 0 {
+				screen.Resize(cols, rows)
+			}
+		}
+	}
+	return screen, nil
+}
@@ -0,0 +1,217 @@
+package terminal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// safeBuffer is a bytes.Buffer safe for concurrent use.
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
+
+// fakeCastClock returns a clock advancing by step on every call.
+func fakeCastClock(step time.Duration) func() time.Time {
+	now := time.Unix(1700000000, 0)
+	return func() time.Time {
+		t := now
+		now = now.Add(step)
+		return t
+	}
+}
+
+func TestCastRecorderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := newCastRecorder(&buf, CastHeader{Version: 2, Width: 20, Height: 3, Title: "demo"}, fakeCastClock(250*time.Millisecond))
+	if err != nil {
+		t.Fatalf("newCastRecorder() error = %v", err)
+	}
+
+	rec.output([]byte("$ echo hi\r\n"))
+	rec.output([]byte("\x1b[1mhi\x1b[0m\r\n"))
+	rec.resize(30, 4)
+	rec.output([]byte("$ "))
+	if err := rec.close(); err != nil {
+		t.Fatalf("close() error = %v", err)
+	}
+
+	// Every line must be standalone JSON as asciinema expects: an object
+	// header, then [time, type, data] arrays
+	scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 5 {
+		t.Fatalf("cast has %d lines, want 5:\n%s", len(lines), buf.String())
+	}
+	var header map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("header is not JSON: %v", err)
+	}
+	if header["version"] != float64(2) || header["width"] != float64(20) || header["height"] != float64(3) {
+		t.Errorf("header = %v, want version 2, 20x3", header)
+	}
+	var first []any
+	if err := json.Unmarshal([]byte(lines[1]), &first); err != nil {
+		t.Fatalf("event is not JSON: %v", err)
+	}
+	if len(first) != 3 || first[0] != 0.25 || first[1] != "o" || first[2] != "$ echo hi\r\n" {
+		t.Errorf("first event = %v, want [0.25 o \"$ echo hi\\r\\n\"]", first)
+	}
+
+	h, events, err := ReadCast(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadCast() error = %v", err)
+	}
+	if h.Title != "demo" {
+		t.Errorf("Title = %q, want %q", h.Title, "demo")
+	}
+	if len(events) != 4 {
+		t.Fatalf("ReadCast() events = %d, want 4", len(events))
+	}
+	if events[2].Type != CastEventResize || events[2].Data != "30x4" {
+		t.Errorf("events[2] = %+v, want resize 30x4", events[2])
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Time <= events[i-1].Time {
+			t.Errorf("event times not increasing: %v then %v", events[i-1].Time, events[i].Time)
+		}
+	}
+
+	screen, err := ReplayCast(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReplayCast() error = %v", err)
+	}
+	if screen.Width() != 30 || screen.Height() != 4 {
+		t.Errorf("replayed size = %dx%d, want 30x4", screen.Width(), screen.Height())
+	}
+	lines = strings.Split(screen.GetText(), "\n")
+	if got := strings.TrimRight(lines[0], " \x00"); got != "$ echo hi" {
+		t.Errorf("line 0 = %q, want %q", got, "$ echo hi")
+	}
+	if got := strings.TrimRight(lines[1], " \x00"); got != "hi" {
+		t.Errorf("line 1 = %q, want %q", got, "hi")
+	}
+	if cell := screen.Cell(0, 1); !cell.Attributes.Has(AttrBold) {
+		t.Error("replayed output lost bold attribute")
+	}
+}
+
+func TestCastRecorderSplitRune(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := newCastRecorder(&buf, CastHeader{Version: 2, Width: 10, Height: 1}, fakeCastClock(time.Millisecond))
+	if err != nil {
+		t.Fatalf("newCastRecorder() error = %v", err)
+	}
+
+	euro := []byte("€")
+	rec.output(append([]byte("a"), euro[:2]...))
+	rec.output(append(euro[2:], 'b'))
+	if err := rec.close(); err != nil {
+		t.Fatalf("close() error = %v", err)
+	}
+
+	_, events, err := ReadCast(&buf)
+	if err != nil {
+		t.Fatalf("ReadCast() error = %v", err)
+	}
+	var got string
+	for _, event := range events {
+		got += event.Data
+	}
+	if got != "a€b" {
+		t.Errorf("recorded output = %q, want %q", got, "a€b")
+	}
+}
+
+func TestReadCastInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cast string
+	}{
+		{"empty", ""},
+		{"bad header", "not json\n"},
+		{"version 1", `{"version": 1, "width": 80, "height": 24}` + "\n"},
+		{"bad event", `{"version": 2, "width": 80, "height": 24}` + "\n[1.0, \"o\"]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ReadCast(strings.NewReader(tt.cast)); !errors.Is(err, ErrInvalidCast) {
+				t.Errorf("ReadCast() error = %v, want ErrInvalidCast", err)
+			}
+		})
+	}
+}
+
+func TestTerminalRecording(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping terminal test in short mode")
+	}
+
+	m := NewManager(ManagerConfig{DefaultShell: "/bin/sh"})
+	defer m.Shutdown(5 * time.Second)
+
+	term, err := m.Create(Options{Name: "rec", Args: []string{"-c", "sleep 0.3; printf 'recorded\\n'"}})
+	if err != nil {
+		t.Skipf("skipping: failed to create terminal (may not have PTY): %v", err)
+	}
+
+	var buf safeBuffer
+	if err := term.StartRecording(&buf, RecordAsciicastV2); err != nil {
+		t.Fatalf("StartRecording() error = %v", err)
+	}
+	if err := term.StartRecording(&buf, RecordAsciicastV2); !errors.Is(err, ErrRecordingActive) {
+		t.Errorf("second StartRecording() error = %v, want ErrRecordingActive", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !bytes.Contains(buf.Bytes(), []byte("recorded")) {
+		if time.Now().After(deadline) {
+			t.Fatal("output was not recorded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := term.StopRecording(); err != nil {
+		t.Fatalf("StopRecording() error = %v", err)
+	}
+	term.Close()
+
+	screen, err := ReplayCast(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReplayCast() error = %v", err)
+	}
+	if !strings.Contains(screen.GetText(), "recorded") {
+		t.Errorf("replayed screen missing output:\n%s", screen.GetText())
+	}
+}
+
+func TestTerminalRecordingFormat(t *testing.T) {
+	term := &Terminal{}
+	if err := term.StartRecording(&bytes.Buffer{}, "gif"); !errors.Is(err, ErrUnsupportedRecordFormat) {
+		t.Errorf("StartRecording(gif) error = %v, want ErrUnsupportedRecordFormat", err)
+	}
+	if err := term.StopRecording(); !errors.Is(err, ErrNotRecording) {
+		t.Errorf("StopRecording() error = %v, want ErrNotRecording", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 )
 
 // Color represents a terminal color.
+// Indexed palette colors carry both their index and RGB value; 24-bit
+// colors set by SGR 38;2 / 48;2 have Index -1.
 type Color struct {
 	R, G, B uint8
 	Index   int  // -1 for RGB, 0-255 for indexed
@@ -94,6 +96,16 @@ func (a CellAttributes) Has(attr CellAttributes) bool {
 	return a&attr != 0
 }
 
+// Hyperlink is a link attached to cells by an OSC 8 sequence.
+// Cells written within the same OSC 8 region share one Hyperlink.
+type Hyperlink struct {
+	// ID groups cells into one link even when not contiguous (optional).
+	ID string
+
+	// URI is the link target.
+	URI string
+}
+
 // Cell represents a single character cell in the terminal.
 type Cell struct {
 	Rune       rune
@@ -101,6 +113,7 @@ type Cell struct {
 	Foreground Color
 	Background Color
 	Attributes CellAttributes
+	Link       *Hyperlink // nil if the cell is not part of a hyperlink
 }
 
 // EmptyCell returns a cell with default values.
@@ -174,6 +187,7 @@ type Screen struct {
 	currentFg    Color
 	currentBg    Color
 	currentAttrs CellAttributes
+	currentLink  *Hyperlink
 
 	// Saved cursor state
 	savedX, savedY   int
@@ -181,8 +195,22 @@ type Screen struct {
 	savedAttrs       CellAttributes
 
 	// Mode flags
-	originMode bool // DECOM - origin mode
-	autoWrap   bool // DECAWM - auto wrap mode
+	originMode     bool // DECOM - origin mode
+	autoWrap       bool // DECAWM - auto wrap mode
+	bracketedPaste bool // DEC 2004 - bracketed paste mode
+
+	// bell is set while a visual bell is showing
+	bell bool
+
+	// scrollback receives lines scrolled off the top of the screen
+	scrollback func(line *Line)
+
+	// scrolledLines counts lines scrolled off the top of the screen, to
+	// number lines absolutely
+	scrolledLines int
+
+	// commands are the shell commands marked by OSC 133, oldest first
+	commands []CommandBlock
 }
 
 // CursorStyle represents the cursor appearance.
@@ -329,6 +357,7 @@ func (s *Screen) writeRuneLocked(r rune) {
 		Foreground: s.currentFg,
 		Background: s.currentBg,
 		Attributes: s.currentAttrs,
+		Link:       s.currentLink,
 	}
 
 	s.lines[s.cursorY].Cells[s.cursorX] = cell
@@ -396,6 +425,13 @@ func (s *Screen) LineFeed() {
 
 func (s *Screen) lineFeedLocked() {
 	if s.cursorY >= s.scrollBottom {
+		// Lines leaving the top of the screen go to scrollback
+		if s.scrollTop == 0 {
+			if s.scrollback != nil {
+				s.scrollback(s.lines[0])
+			}
+			s.scrolledLines++
+		}
 		// Scroll up
 		s.scrollUpLocked(1)
 	} else {
@@ -403,6 +439,15 @@ func (s *Screen) lineFeedLocked() {
 	}
 }
 
+// SetScrollbackHandler sets a function that receives each line scrolled
+// off the top of the screen by a line feed, e.g. History.Add. The function
+// is called with the screen locked and must not retain line.
+func (s *Screen) SetScrollbackHandler(fn func(line *Line)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scrollback = fn
+}
+
 // ReverseLineFeed moves cursor up one line, scrolling if needed.
 func (s *Screen) ReverseLineFeed() {
 	s.mu.Lock()
@@ -756,6 +801,35 @@ func (s *Screen) ResetAttributes() {
 	s.currentAttrs = AttrNone
 }
 
+// SetHyperlink starts a hyperlink region: characters written afterwards
+// link to uri until the hyperlink is cleared. An empty uri ends the region.
+func (s *Screen) SetHyperlink(uri, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if uri == "" {
+		s.currentLink = nil
+		return
+	}
+	s.currentLink = &Hyperlink{ID: id, URI: uri}
+}
+
+// HyperlinkAt returns the URI of the hyperlink at the given position.
+// Returns false if the cell is out of bounds or not part of a hyperlink.
+func (s *Screen) HyperlinkAt(x, y int) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if x < 0 || x >= s.width || y < 0 || y >= s.height {
+		return "", false
+	}
+	link := s.lines[y].Cells[x].Link
+	if link == nil {
+		return "", false
+	}
+	return link.URI, true
+}
+
 // SaveCursor saves the current cursor position and attributes.
 func (s *Screen) SaveCursor() {
 	s.mu.Lock()
@@ -808,6 +882,38 @@ func (s *Screen) SetAutoWrap(enabled bool) {
 	s.autoWrap = enabled
 }
 
+// SetBracketedPaste records whether the application enabled bracketed
+// paste mode.
+func (s *Screen) SetBracketedPaste(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bracketedPaste = enabled
+}
+
+// BracketedPaste returns true if the application enabled bracketed paste
+// mode.
+func (s *Screen) BracketedPaste() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bracketedPaste
+}
+
+// SetBell sets or clears the visual bell flag.
+func (s *Screen) SetBell(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bell = on
+}
+
+// Bell returns true while a visual bell is showing. Renderers should flash
+// the screen, such as by drawing it inverted, while it is set. The flag is
+// transient: it is cleared shortly after the bell rings.
+func (s *Screen) Bell() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bell
+}
+
 // Resize resizes the screen.
 func (s *Screen) Resize(width, height int) {
 	s.mu.Lock()
@@ -911,8 +1017,10 @@ func (s *Screen) Reset() {
 	s.currentFg = DefaultForeground
 	s.currentBg = DefaultBackground
 	s.currentAttrs = AttrNone
+	s.currentLink = nil
 	s.originMode = false
 	s.autoWrap = true
+	s.bracketedPaste = false
 }
 
 // GetText returns the text content of the screen as a string.
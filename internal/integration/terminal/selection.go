@@ -0,0 +1,278 @@
+package terminal
+
+import "strings"
+
+// SelectionMode determines how a selection covers the cells between its
+// anchor and its end.
+type SelectionMode int
+
+const (
+	// SelectionLinear selects text in reading order, from the anchor to
+	// the end across whole lines in between.
+	SelectionLinear SelectionMode = iota
+
+	// SelectionBlock selects the rectangle of cells between the anchor
+	// and the end.
+	SelectionBlock
+)
+
+// selectionPoint is a cell position whose row is an absolute line number
+// (see Terminal.absRowLocked), so it stays on the same text as output
+// scrolls lines into history.
+type selectionPoint struct {
+	x   int
+	row int
+}
+
+// selection is the terminal's text selection.
+type selection struct {
+	active bool
+	mode   SelectionMode
+	anchor selectionPoint
+	end    selectionPoint
+}
+
+// ScrollOffset returns how many lines the view is scrolled back into
+// history. Zero shows the live screen.
+func (t *Terminal) ScrollOffset() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.scrollOffset
+}
+
+// SetScrollOffset scrolls the view back n lines into history. The offset
+// is clamped to the available history. Screen coordinates passed to the
+// selection methods are relative to this view.
+func (t *Terminal) SetScrollOffset(n int) {
+	t.screen.mu.RLock()
+	hLen := t.history.Len()
+	t.screen.mu.RUnlock()
+
+	if n < 0 {
+		n = 0
+	}
+	if n > hLen {
+		n = hLen
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scrollOffset = n
+}
+
+// StartSelection starts a new selection at the cell at screen position
+// (x, y), replacing any existing selection. The selection mode is kept.
+func (t *Terminal) StartSelection(x, y int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := t.selectionPointLocked(x, y)
+	t.selection.active = true
+	t.selection.anchor = p
+	t.selection.end = p
+}
+
+// ExtendSelection moves the end of the selection to the cell at screen
+// position (x, y). It starts a selection if none is active.
+func (t *Terminal) ExtendSelection(x, y int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := t.selectionPointLocked(x, y)
+	if !t.selection.active {
+		t.selection.active = true
+		t.selection.anchor = p
+	}
+	t.selection.end = p
+}
+
+// ClearSelection removes the selection.
+func (t *Terminal) ClearSelection() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.selection.active = false
+}
+
+// HasSelection returns true if a selection is active.
+func (t *Terminal) HasSelection() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.selection.active
+}
+
+// SelectionMode returns the selection mode.
+func (t *Terminal) SelectionMode() SelectionMode {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.selection.mode
+}
+
+// SetSelectionMode sets the selection mode. It applies to the current
+// selection as well as later ones.
+func (t *Terminal) SetSelectionMode(mode SelectionMode) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.selection.mode = mode
+}
+
+// ToggleBlockSelection switches between linear and block selection and
+// returns the new mode.
+func (t *Terminal) ToggleBlockSelection() SelectionMode {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.selection.mode == SelectionBlock {
+		t.selection.mode = SelectionLinear
+	} else {
+		t.selection.mode = SelectionBlock
+	}
+	return t.selection.mode
+}
+
+// SelectedText returns the selected text, or "" if there is no selection.
+// Trailing whitespace is trimmed from each line. In linear mode, lines
+// that were wrapped by the terminal are joined back into one logical line
+// rather than being split by a newline. In block mode each row of the
+// rectangle becomes one line.
+func (t *Terminal) SelectedText() string {
+	t.mu.RLock()
+	sel := t.selection
+	t.mu.RUnlock()
+
+	if !sel.active {
+		return ""
+	}
+
+	start, end := sel.anchor, sel.end
+	if end.row < start.row || (end.row == start.row && end.x < start.x) {
+		start, end = end, start
+	}
+
+	t.screen.mu.RLock()
+	defer t.screen.mu.RUnlock()
+
+	if sel.mode == SelectionBlock {
+		return t.blockTextLocked(start, end)
+	}
+	return t.linearTextLocked(start, end)
+}
+
+// linearTextLocked returns the text from start to end in reading order.
+// Caller must hold the screen lock.
+func (t *Terminal) linearTextLocked(start, end selectionPoint) string {
+	var b strings.Builder
+	for row := start.row; row <= end.row; row++ {
+		line := t.lineAtLocked(row)
+
+		from, to := 0, -1
+		if line != nil {
+			to = len(line.Cells) - 1
+		}
+		if row == start.row {
+			from = start.x
+		}
+		if row == end.row && end.x < to {
+			to = end.x
+		}
+
+		text := cellText(line, from, to)
+		if row < end.row && line != nil && line.Wrapped {
+			// Soft wrap: the logical line continues on the next row.
+			b.WriteString(text)
+			continue
+		}
+		b.WriteString(strings.TrimRight(text, " \t"))
+		if row < end.row {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// blockTextLocked returns the rectangle of cells between start and end.
+// Caller must hold the screen lock.
+func (t *Terminal) blockTextLocked(start, end selectionPoint) string {
+	left, right := start.x, end.x
+	if right < left {
+		left, right = right, left
+	}
+
+	var b strings.Builder
+	for row := start.row; row <= end.row; row++ {
+		text := cellText(t.lineAtLocked(row), left, right)
+		b.WriteString(strings.TrimRight(text, " \t"))
+		if row < end.row {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// selectionPointLocked converts a screen position in the current view to
+// a selection point. Caller must hold t.mu.
+func (t *Terminal) selectionPointLocked(x, y int) selectionPoint {
+	t.screen.mu.RLock()
+	defer t.screen.mu.RUnlock()
+
+	if x < 0 {
+		x = 0
+	}
+	if x >= t.screen.width {
+		x = t.screen.width - 1
+	}
+	if y < 0 {
+		y = 0
+	}
+	if y >= t.screen.height {
+		y = t.screen.height - 1
+	}
+	return selectionPoint{x: x, row: t.absRowLocked(y)}
+}
+
+// absRowLocked returns the absolute line number of view row y. Absolute
+// line numbers count every line that has entered history, so a line keeps
+// its number when it scrolls off the screen. Caller must hold t.mu and the
+// screen lock.
+func (t *Terminal) absRowLocked(y int) int {
+	return t.history.trimmed + t.history.Len() - t.scrollOffset + y
+}
+
+// lineAtLocked returns the line with an absolute line number, or nil if it
+// was discarded from history. Caller must hold the screen lock.
+func (t *Terminal) lineAtLocked(row int) *Line {
+	i := row - t.history.trimmed
+	if i < 0 {
+		return nil
+	}
+	if i < t.history.Len() {
+		return t.history.Line(i)
+	}
+	i -= t.history.Len()
+	if i < len(t.screen.lines) {
+		return t.screen.lines[i]
+	}
+	return nil
+}
+
+// cellText returns the text of cells [from, to] of line.
+func cellText(line *Line, from, to int) string {
+	if line == nil {
+		return ""
+	}
+	if from < 0 {
+		from = 0
+	}
+	if to >= len(line.Cells) {
+		to = len(line.Cells) - 1
+	}
+
+	var b strings.Builder
+	for x := from; x <= to; x++ {
+		if r := line.Cells[x].Rune; r != 0 {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package terminal
+
+import "testing"
+
+// newSelectionTerminal returns a terminal without a PTY whose screen
+// scrolls into history, with output already written.
+func newSelectionTerminal(cols, rows int, output string) *Terminal {
+	screen := NewScreen(cols, rows)
+	history := NewHistory(100)
+	screen.SetScrollbackHandler(history.Add)
+	parser := NewParser(screen)
+	parser.ParseString(output)
+	return &Terminal{screen: screen, history: history, parser: parser}
+}
+
+func TestSelectionLinear(t *testing.T) {
+	term := newSelectionTerminal(10, 3, "hello   \r\nworld")
+
+	if term.HasSelection() {
+		t.Error("HasSelection() = true before selecting")
+	}
+
+	term.StartSelection(2, 0)
+	term.ExtendSelection(9, 0)
+	if got := term.SelectedText(); got != "llo" {
+		t.Errorf("SelectedText() = %q, want %q", got, "llo")
+	}
+
+	// Dragging backwards selects the same text in reading order.
+	term.StartSelection(2, 1)
+	term.ExtendSelection(1, 0)
+	if got, want := term.SelectedText(), "ello\nwor"; got != want {
+		t.Errorf("SelectedText() = %q, want %q", got, want)
+	}
+
+	term.ClearSelection()
+	if got := term.SelectedText(); got != "" {
+		t.Errorf("SelectedText() after clear = %q, want empty", got)
+	}
+}
+
+func TestSelectionWrappedLine(t *testing.T) {
+	// "the quick brown fox" wraps after "the quick " at width 10.
+	term := newSelectionTerminal(10, 4, "the quick brown fox\r\nnext")
+
+	term.StartSelection(0, 0)
+	term.ExtendSelection(9, 2)
+	if got, want := term.SelectedText(), "the quick brown fox\nnext"; got != want {
+		t.Errorf("SelectedText() = %q, want %q", got, want)
+	}
+}
+
+func TestSelectionBlock(t *testing.T) {
+	term := newSelectionTerminal(12, 3, "abc 123 xyz\r\ndef 456\r\nghi 789 uvw")
+
+	if got := term.ToggleBlockSelection(); got != SelectionBlock {
+		t.Fatalf("ToggleBlockSelection() = %v, want %v", got, SelectionBlock)
+	}
+
+	term.StartSelection(4, 0)
+	term.ExtendSelection(9, 2)
+	if got, want := term.SelectedText(), "123 xy\n456\n789 uv"; got != want {
+		t.Errorf("SelectedText() = %q, want %q", got, want)
+	}
+
+	// The rectangle does not depend on drag direction.
+	term.StartSelection(9, 2)
+	term.ExtendSelection(4, 0)
+	if got, want := term.SelectedText(), "123 xy\n456\n789 uv"; got != want {
+		t.Errorf("SelectedText() reversed = %q, want %q", got, want)
+	}
+
+	if got := term.ToggleBlockSelection(); got != SelectionLinear {
+		t.Errorf("ToggleBlockSelection() = %v, want %v", got, SelectionLinear)
+	}
+}
+
+func TestSelectionScrollback(t *testing.T) {
+	term := newSelectionTerminal(10, 2, "one\r\ntwo\r\nthree\r\nfour")
+
+	if got := term.History().Len(); got != 2 {
+		t.Fatalf("History().Len() = %d, want 2", got)
+	}
+
+	// Scrolled back two lines, the view shows "one" and "two".
+	term.SetScrollOffset(2)
+	term.StartSelection(0, 0)
+
+	// Back on the live screen, extend to the end of "four".
+	term.SetScrollOffset(0)
+	term.ExtendSelection(9, 1)
+	if got, want := term.SelectedText(), "one\ntwo\nthree\nfour"; got != want {
+		t.Errorf("SelectedText() = %q, want %q", got, want)
+	}
+
+	// The selection follows its text as more output scrolls.
+	term.parser.ParseString("\r\nfive")
+	if got, want := term.SelectedText(), "one\ntwo\nthree\nfour"; got != want {
+		t.Errorf("SelectedText() after scroll = %q, want %q", got, want)
+	}
+
+	term.SetScrollOffset(100)
+	if got := term.ScrollOffset(); got != 3 {
+		t.Errorf("ScrollOffset() = %d, want 3", got)
+	}
+}
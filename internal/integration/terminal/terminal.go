@@ -1,6 +1,7 @@
 package terminal
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -32,10 +33,25 @@ type Terminal struct {
 	onOutput func(data []byte)
 	onTitle  func(title string)
 	onClose  func()
+	onLink   func(uri string)
+
+	// Bell handling, guarded by mu
+	bellMode     BellMode
+	bellInterval time.Duration
+	lastBell     time.Time
+	onBell       func()
+	onBellEvent  func(mode BellMode)
 
 	// Shell integration
 	cwd     string
 	cwdLock sync.RWMutex
+
+	// Selection and scrollback view, guarded by mu
+	selection    selection
+	scrollOffset int
+
+	// Output recording, guarded by mu
+	recorder *castRecorder
 }
 
 // Options configures a new terminal.
@@ -72,6 +88,20 @@ type Options struct {
 
 	// OnClose is called when the terminal closes.
 	OnClose func()
+
+	// OnHyperlinkClick is called when Click hits a cell that is part of
+	// an OSC 8 hyperlink.
+	OnHyperlinkClick func(uri string)
+
+	// Bell is how the terminal responds to BEL (default DefaultBellMode).
+	Bell BellMode
+
+	// BellInterval is the minimum time between bells (default
+	// DefaultBellInterval).
+	BellInterval time.Duration
+
+	// OnBell is called when the bell rings in BellAudible mode.
+	OnBell func()
 }
 
 // newTerminal creates a new terminal with the given options.
@@ -95,6 +125,12 @@ func newTerminal(opts Options) (*Terminal, error) {
 	if opts.Name == "" {
 		opts.Name = "terminal"
 	}
+	if opts.Bell == "" {
+		opts.Bell = DefaultBellMode
+	}
+	if opts.BellInterval <= 0 {
+		opts.BellInterval = DefaultBellInterval
+	}
 
 	// Verify shell exists
 	if _, err := exec.LookPath(opts.Shell); err != nil {
@@ -120,6 +156,7 @@ func newTerminal(opts Options) (*Terminal, error) {
 	// Create screen and parser
 	screen := NewScreen(opts.Cols, opts.Rows)
 	history := NewHistory(opts.Scrollback)
+	screen.SetScrollbackHandler(history.Add)
 	parser := NewParser(screen)
 
 	t := &Terminal{
@@ -134,7 +171,12 @@ func newTerminal(opts Options) (*Terminal, error) {
 		onOutput: opts.OnOutput,
 		onTitle:  opts.OnTitle,
 		onClose:  opts.OnClose,
+		onLink:   opts.OnHyperlinkClick,
 		cwd:      opts.WorkDir,
+
+		bellMode:     opts.Bell,
+		bellInterval: opts.BellInterval,
+		onBell:       opts.OnBell,
 	}
 
 	t.exitCode.Store(-1)
@@ -146,6 +188,8 @@ func newTerminal(opts Options) (*Terminal, error) {
 		}
 	})
 
+	parser.SetBellCallback(t.ringBell)
+
 	parser.SetOSCCallback(func(cmd int, data string) {
 		// Handle shell integration OSC sequences
 		if cmd == 7 {
@@ -189,6 +233,41 @@ func (t *Terminal) Write(data []byte) (int, error) {
 	return t.pty.Write(data)
 }
 
+// Bracketed paste delimiters (DEC private mode 2004).
+var (
+	pasteStart = []byte("\x1b[200~")
+	pasteEnd   = []byte("\x1b[201~")
+)
+
+// Paste sends text to the terminal as pasted input. If the running
+// application enabled bracketed paste mode, the text is wrapped in
+// ESC[200~ ... ESC[201~ so that newlines are not executed as commands;
+// any end delimiter inside the text is removed so it cannot terminate the
+// paste early. Otherwise the text is sent unchanged.
+func (t *Terminal) Paste(text []byte) error {
+	if !t.screen.BracketedPaste() {
+		_, err := t.Write(text)
+		return err
+	}
+
+	data := make([]byte, 0, len(pasteStart)+len(text)+len(pasteEnd))
+	data = append(data, pasteStart...)
+	data = append(data, stripPasteEnd(text)...)
+	data = append(data, pasteEnd...)
+	_, err := t.Write(data)
+	return err
+}
+
+// stripPasteEnd removes every end delimiter from text. Removal repeats
+// until none is left, since removing one can join the bytes around it into
+// another (e.g. "\x1b[20\x1b[201~1~").
+func stripPasteEnd(text []byte) []byte {
+	for bytes.Contains(text, pasteEnd) {
+		text = bytes.ReplaceAll(text, pasteEnd, nil)
+	}
+	return text
+}
+
 // WriteString sends a string to the terminal.
 func (t *Terminal) WriteString(s string) (int, error) {
 	return t.Write([]byte(s))
@@ -199,6 +278,27 @@ func (t *Terminal) Screen() *Screen {
 	return t.screen
 }
 
+// Click handles a click on the cell at the given screen position.
+// If the cell is part of a hyperlink, its URI is returned and passed to
+// the OnHyperlinkClick callback.
+func (t *Terminal) Click(x, y int) (string, bool) {
+	uri, ok := t.screen.HyperlinkAt(x, y)
+	if !ok {
+		return "", false
+	}
+	if t.onLink != nil {
+		t.onLink(uri)
+	}
+	return uri, true
+}
+
+// Commands returns the shell commands run in the terminal, oldest first.
+// They are only known if the shell marks its prompts with OSC 133 shell
+// integration sequences.
+func (t *Terminal) Commands() []CommandBlock {
+	return t.screen.Commands()
+}
+
 // History returns the scrollback history.
 func (t *Terminal) History() *History {
 	return t.history
@@ -219,6 +319,9 @@ func (t *Terminal) Resize(cols, rows int) error {
 	}
 
 	t.screen.Resize(cols, rows)
+	if rec := t.activeRecorder(); rec != nil {
+		rec.resize(cols, rows)
+	}
 	return nil
 }
 
@@ -239,6 +342,9 @@ func (t *Terminal) Close() error {
 	// Wait for read loop to finish
 	<-t.done
 
+	// Finish any recording with the last output
+	_ = t.StopRecording()
+
 	// Call close callback
 	if t.onClose != nil {
 		t.onClose()
@@ -291,6 +397,10 @@ func (t *Terminal) readLoop() {
 			// Parse ANSI sequences and update screen
 			t.parser.Parse(data)
 
+			if rec := t.activeRecorder(); rec != nil {
+				rec.output(data)
+			}
+
 			// Call output callback
 			if t.onOutput != nil {
 				t.onOutput(data)
@@ -329,6 +439,7 @@ type Manager struct {
 	defaultCols  int
 	defaultRows  int
 	scrollback   int
+	bell         BellMode
 
 	// Callbacks
 	eventBus EventPublisher
@@ -356,6 +467,10 @@ type ManagerConfig struct {
 	// Scrollback is the default scrollback lines.
 	Scrollback int
 
+	// Bell is the default bell mode, typically from the terminal.bell
+	// setting (defaults to DefaultBellMode).
+	Bell BellMode
+
 	// EventBus for publishing terminal events.
 	EventBus EventPublisher
 }
@@ -377,6 +492,9 @@ func NewManager(cfg ManagerConfig) *Manager {
 	if cfg.Scrollback <= 0 {
 		cfg.Scrollback = 10000
 	}
+	if cfg.Bell == "" {
+		cfg.Bell = DefaultBellMode
+	}
 
 	return &Manager{
 		terminals:    make(map[string]*Terminal),
@@ -384,6 +502,7 @@ func NewManager(cfg ManagerConfig) *Manager {
 		defaultCols:  cfg.DefaultCols,
 		defaultRows:  cfg.DefaultRows,
 		scrollback:   cfg.Scrollback,
+		bell:         cfg.Bell,
 		eventBus:     cfg.EventBus,
 	}
 }
@@ -407,6 +526,9 @@ func (m *Manager) Create(opts Options) (*Terminal, error) {
 	if opts.Scrollback <= 0 {
 		opts.Scrollback = m.scrollback
 	}
+	if opts.Bell == "" {
+		opts.Bell = m.bell
+	}
 
 	// Create terminal
 	term, err := newTerminal(opts)
@@ -438,6 +560,16 @@ func (m *Manager) Create(opts Options) (*Terminal, error) {
 		}
 	}
 
+	// Publish rate-limited bells
+	term.mu.Lock()
+	term.onBellEvent = func(mode BellMode) {
+		m.publishEvent("terminal.bell", map[string]any{
+			"id":   term.id,
+			"mode": string(mode),
+		})
+	}
+	term.mu.Unlock()
+
 	// Publish event
 	m.publishEvent("terminal.created", map[string]any{
 		"id":   term.id,
@@ -1,6 +1,8 @@
 package terminal
 
 import (
+	"bytes"
+	"os"
 	"strings"
 	"sync"
 	"testing"
@@ -796,3 +798,69 @@ func TestOptionsDefaults(t *testing.T) {
 		t.Errorf("expected 100x50, got %dx%d", screen.Width(), screen.Height())
 	}
 }
+
+// recordingPTY is a PTY that records what is written to it.
+type recordingPTY struct {
+	written bytes.Buffer
+}
+
+func (p *recordingPTY) File() *os.File                 { return nil }
+func (p *recordingPTY) Read(b []byte) (int, error)     { return 0, nil }
+func (p *recordingPTY) Write(b []byte) (int, error)    { return p.written.Write(b) }
+func (p *recordingPTY) Resize(cols, rows uint16) error { return nil }
+func (p *recordingPTY) Close() error                   { return nil }
+
+func TestTerminalPaste(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string // program output setting DEC modes
+		text   string
+		want   string
+	}{
+		{
+			name: "raw without bracketed paste",
+			text: "echo a\necho b\n",
+			want: "echo a\necho b\n",
+		},
+		{
+			name:   "wrapped when enabled",
+			output: "\x1b[?2004h",
+			text:   "echo a\necho b\n",
+			want:   "\x1b[200~echo a\necho b\n\x1b[201~",
+		},
+		{
+			name:   "raw after disable",
+			output: "\x1b[?2004h\x1b[?2004l",
+			text:   "ls\n",
+			want:   "ls\n",
+		},
+		{
+			name:   "embedded end marker removed",
+			output: "\x1b[?2004h",
+			text:   "a\x1b[201~rm -rf x\n",
+			want:   "\x1b[200~arm -rf x\n\x1b[201~",
+		},
+		{
+			name:   "nested end marker removed",
+			output: "\x1b[?2004h",
+			text:   "a\x1b[20\x1b[201~1~rm -rf x\n",
+			want:   "\x1b[200~arm -rf x\n\x1b[201~",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pty := &recordingPTY{}
+			screen := NewScreen(80, 24)
+			term := &Terminal{pty: pty, screen: screen, parser: NewParser(screen)}
+			term.parser.ParseString(tt.output)
+
+			if err := term.Paste([]byte(tt.text)); err != nil {
+				t.Fatalf("Paste() error = %v", err)
+			}
+			if got := pty.written.String(); got != tt.want {
+				t.Errorf("Paste() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+// Package langid detects the language of a file from its path and
+// content. It is the single source of language IDs for the LSP client, the
+// project, the file store and the editor, so that all of them agree on the
+// language of a file. Language IDs follow the LSP language identifiers.
+package langid
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// Detection confidence levels, from a well-known file name down to an
+// ambiguous extension. No match at all has confidence 0.
+const (
+	ConfidenceFileName  = 0.95
+	ConfidenceExtension = 0.9
+	ConfidenceShebang   = 0.85
+	ConfidenceContent   = 0.6
+	ConfidenceAmbiguous = 0.5
+)
+
+// Plaintext is reported when no rule matches.
+const Plaintext = "plaintext"
+
+// fileNames maps well-known file names that have no useful
+// extension to their language.
+var fileNames = map[string]string{
+	"Makefile":       "makefile",
+	"makefile":       "makefile",
+	"GNUmakefile":    "makefile",
+	"Dockerfile":     "dockerfile",
+	"Containerfile":  "dockerfile",
+	"CMakeLists.txt": "cmake",
+	"Gemfile":        "ruby",
+	"Rakefile":       "ruby",
+	"Vagrantfile":    "ruby",
+	"Jenkinsfile":    "groovy",
+	"go.mod":         "go.mod",
+	"go.sum":         "go.sum",
+	".bashrc":        "shellscript",
+	".bash_profile":  "shellscript",
+	".zshrc":         "shellscript",
+	".profile":       "shellscript",
+	".gitignore":     "ignore",
+	".dockerignore":  "ignore",
+	".gitconfig":     "ini",
+	".editorconfig":  "ini",
+}
+
+// extensions maps file extensions, including the dot, to their
+// language. The IDs follow the LSP language identifiers.
+var extensions = map[string]string{
+	".go":         "go",
+	".rs":         "rust",
+	".ts":         "typescript",
+	".tsx":        "typescriptreact",
+	".js":         "javascript",
+	".mjs":        "javascript",
+	".cjs":        "javascript",
+	".jsx":        "javascriptreact",
+	".py":         "python",
+	".pyi":        "python",
+	".rb":         "ruby",
+	".java":       "java",
+	".c":          "c",
+	".cpp":        "cpp",
+	".cc":         "cpp",
+	".cxx":        "cpp",
+	".hpp":        "cpp",
+	".hh":         "cpp",
+	".hxx":        "cpp",
+	".cs":         "csharp",
+	".swift":      "swift",
+	".kt":         "kotlin",
+	".kts":        "kotlin",
+	".scala":      "scala",
+	".php":        "php",
+	".lua":        "lua",
+	".sh":         "shellscript",
+	".bash":       "shellscript",
+	".zsh":        "shellscript",
+	".ps1":        "powershell",
+	".json":       "json",
+	".yaml":       "yaml",
+	".yml":        "yaml",
+	".toml":       "toml",
+	".ini":        "ini",
+	".xml":        "xml",
+	".html":       "html",
+	".htm":        "html",
+	".css":        "css",
+	".scss":       "scss",
+	".less":       "less",
+	".md":         "markdown",
+	".markdown":   "markdown",
+	".sql":        "sql",
+	".dockerfile": "dockerfile",
+	".mk":         "makefile",
+	".cmake":      "cmake",
+	".proto":      "protobuf",
+	".zig":        "zig",
+	".ex":         "elixir",
+	".exs":        "elixir",
+	".erl":        "erlang",
+	".hs":         "haskell",
+	".ml":         "ocaml",
+	".clj":        "clojure",
+	".cljs":       "clojure",
+	".cljc":       "clojure",
+	".vim":        "vim",
+	".hrl":        "erlang",
+	".lhs":        "haskell",
+	".mli":        "ocaml",
+	".fs":         "fsharp",
+	".fsi":        "fsharp",
+	".fsx":        "fsharp",
+	".sass":       "sass",
+	".r":          "r",
+	".vb":         "vb",
+	".dart":       "dart",
+	".tf":         "terraform",
+	".tfvars":     "terraform",
+	".vue":        "vue",
+	".svelte":     "svelte",
+	".nim":        "nim",
+	".cr":         "crystal",
+	".jl":         "julia",
+	".v":          "v",
+	".d":          "d",
+	".txt":        Plaintext,
+}
+
+// interpreters maps shebang interpreters to their language.
+var interpreters = map[string]string{
+	"sh":      "shellscript",
+	"bash":    "shellscript",
+	"zsh":     "shellscript",
+	"dash":    "shellscript",
+	"ksh":     "shellscript",
+	"python":  "python",
+	"python2": "python",
+	"python3": "python",
+	"ruby":    "ruby",
+	"node":    "javascript",
+	"deno":    "typescript",
+	"perl":    "perl",
+	"php":     "php",
+	"lua":     "lua",
+}
+
+// cppHeaderMarkers are constructs that only appear in C++, used to tell
+// C++ headers from C headers.
+var cppHeaderMarkers = [][]byte{
+	[]byte("class "),
+	[]byte("namespace "),
+	[]byte("template<"),
+	[]byte("template <"),
+	[]byte("std::"),
+	[]byte("public:"),
+	[]byte("private:"),
+	[]byte("#include <iostream>"),
+}
+
+// Detect returns the language of a file and how confident the detection is,
+// from 0 (no idea, reported as "plaintext") to 1. The path is matched by
+// well-known file names and extensions. Files without either fall back to
+// their shebang line and then to simple content heuristics. Ambiguous
+// extensions such as ".h" are resolved by content. The content may be nil,
+// in which case only the path is used.
+//
+// User overrides are applied by project.DefaultProject.DetectLanguage.
+func Detect(path string, content []byte) (language string, confidence float64) {
+	name := filepath.Base(path)
+	if lang, ok := fileNames[name]; ok {
+		return lang, ConfidenceFileName
+	}
+	if strings.HasPrefix(name, "Dockerfile.") {
+		return "dockerfile", ConfidenceFileName
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == ".h" {
+		return detectHeader(content)
+	}
+	if lang, ok := extensions[ext]; ok {
+		return lang, ConfidenceExtension
+	}
+
+	if lang, ok := detectShebang(content); ok {
+		return lang, ConfidenceShebang
+	}
+	if lang, ok := detectContent(content); ok {
+		return lang, ConfidenceContent
+	}
+	return Plaintext, 0
+}
+
+// detectHeader tells C++ headers from C headers by looking for C++-only
+// constructs. Without any, the header is assumed to be C.
+func detectHeader(content []byte) (string, float64) {
+	for _, marker := range cppHeaderMarkers {
+		if bytes.Contains(content, marker) {
+			return "cpp", ConfidenceContent
+		}
+	}
+	return "c", ConfidenceAmbiguous
+}
+
+// detectShebang returns the language of the interpreter named on a "#!"
+// first line, handling "/usr/bin/env" and versioned names like
+// "python3.12".
+func detectShebang(content []byte) (string, bool) {
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return "", false
+	}
+	line := content[2:]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return "", false
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		// Skip env options such as -S
+		fields = fields[1:]
+		for len(fields) > 0 && strings.HasPrefix(fields[0], "-") {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			return "", false
+		}
+		interpreter = fields[0]
+	}
+
+	if lang, ok := interpreters[interpreter]; ok {
+		return lang, true
+	}
+	// Versioned interpreters, e.g. python3.12
+	if i := strings.IndexByte(interpreter, '.'); i > 0 {
+		lang, ok := interpreters[interpreter[:i]]
+		return lang, ok
+	}
+	return "", false
+}
+
+// detectContent recognizes languages by the way files start.
+func detectContent(content []byte) (string, bool) {
+	start := bytes.TrimSpace(content)
+	if len(start) > 512 {
+		start = start[:512]
+	}
+	lower := bytes.ToLower(start)
+
+	switch {
+	case len(start) == 0:
+		return "", false
+	case bytes.HasPrefix(lower, []byte("<?php")):
+		return "php", true
+	case bytes.HasPrefix(lower, []byte("<?xml")):
+		return "xml", true
+	case bytes.HasPrefix(lower, []byte("<!doctype html")), bytes.HasPrefix(lower, []byte("<html")):
+		return "html", true
+	case bytes.HasPrefix(start, []byte("---\n")):
+		return "yaml", true
+	case (start[0] == '{' || start[0] == '[') && bytes.Contains(start, []byte("\":")):
+		return "json", true
+	}
+	return "", false
+}
+
+// FromPath returns the language of a file from its path alone. Ambiguous
+// extensions such as ".h" resolve to their most common language.
+func FromPath(path string) string {
+	lang, _ := Detect(path, nil)
+	return lang
+}
+
+// FromExtension returns the language of a file extension, with or without
+// the leading dot, or "" if the extension is unknown.
+func FromExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	if ext == ".h" {
+		return "c"
+	}
+	return extensions[ext]
+}
@@ -0,0 +1,76 @@
+package langid
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+	}{
+		{"extension", "/src/main.go", "", "go"},
+		{"extension case", "/src/README.MD", "", "markdown"},
+		{"makefile", "/src/Makefile", "all:\n\tgo build", "makefile"},
+		{"dockerfile", "/src/Dockerfile", "FROM alpine", "dockerfile"},
+		{"dockerfile variant", "/src/Dockerfile.dev", "FROM alpine", "dockerfile"},
+		{"c header", "/src/util.h", "int add(int a, int b);", "c"},
+		{"cpp header", "/src/util.h", "namespace util {\nclass Adder;\n}", "cpp"},
+		{"shebang", "/usr/local/bin/tool", "#!/usr/bin/env python3\nprint(1)", "python"},
+		{"xml content", "/src/data", "<?xml version=\"1.0\"?>", "xml"},
+		{"unknown", "/src/notes", "just some words", Plaintext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := Detect(tt.path, []byte(tt.content)); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/path/to/file.go", "go"},
+		{"/path/to/file.rs", "rust"},
+		{"/path/to/file.tsx", "typescriptreact"},
+		{"/path/to/file.jsx", "javascriptreact"},
+		{"/path/to/file.h", "c"},
+		{"/path/to/file.hpp", "cpp"},
+		{"/path/to/file.kt", "kotlin"},
+		{"/path/to/file.fsx", "fsharp"},
+		{"/path/to/file.bash", "shellscript"},
+		{"/path/to/CMakeLists.txt", "cmake"},
+		{"/path/to/file.unknown", Plaintext},
+		{"/path/to/file", Plaintext},
+	}
+
+	for _, tt := range tests {
+		if got := FromPath(tt.path); got != tt.want {
+			t.Errorf("FromPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFromExtension(t *testing.T) {
+	tests := []struct {
+		ext  string
+		want string
+	}{
+		{"go", "go"},
+		{".go", "go"},
+		{".PY", "python"},
+		{"h", "c"},
+		{"unknown", ""},
+	}
+
+	for _, tt := range tests {
+		if got := FromExtension(tt.ext); got != tt.want {
+			t.Errorf("FromExtension(%q) = %q, want %q", tt.ext, got, tt.want)
+		}
+	}
+}
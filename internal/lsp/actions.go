@@ -2,12 +2,16 @@ package lsp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // ActionsService provides high-level code actions, formatting, and refactoring features.
@@ -28,6 +32,9 @@ type ActionsService struct {
 	// Rename settings
 	renameConfirmation bool // whether to preview rename before applying
 
+	// Applies workspace edits to the editor's documents
+	applyEdit func(ctx context.Context, edit WorkspaceEdit) error
+
 	// Signature help tracking
 	activeSignature *signatureState
 
@@ -106,6 +113,14 @@ func WithRenameConfirmation(enable bool) ActionsOption {
 	}
 }
 
+// WithWorkspaceEditApplier sets the function applying workspace edits to
+// the editor's documents. Without one, ApplyWorkspaceEdit fails.
+func WithWorkspaceEditApplier(apply func(ctx context.Context, edit WorkspaceEdit) error) ActionsOption {
+	return func(as *ActionsService) {
+		as.applyEdit = apply
+	}
+}
+
 // WithCodeActionCacheAge sets the code action cache max age in seconds.
 func WithCodeActionCacheAge(seconds int64) ActionsOption {
 	return func(as *ActionsService) {
@@ -425,22 +440,103 @@ type RenameChange struct {
 	NewText      string
 }
 
-// PrepareRename checks if rename is valid at the given position and returns the range to rename.
-func (as *ActionsService) PrepareRename(ctx context.Context, path string, pos Position) (*Range, string, error) {
+// RenameRange is the symbol a rename applies to, as found by PrepareRename.
+type RenameRange struct {
+	// Range is the range of the symbol.
+	Range Range
+
+	// Placeholder is the current name, used to pre-fill the new name.
+	Placeholder string
+}
+
+// PrepareRename checks that the symbol at pos can be renamed and returns
+// its range and current name. Servers supporting prepareRename decide
+// themselves, rejecting e.g. keywords; for other servers the identifier
+// under the position is used. Returns ErrCannotRename if there is nothing
+// to rename at pos.
+func (as *ActionsService) PrepareRename(ctx context.Context, path string, pos Position) (RenameRange, error) {
 	server, err := as.getServer(ctx, path)
 	if err != nil {
-		return nil, "", err
+		return RenameRange{}, err
 	}
+	return as.prepareRename(ctx, server, path, pos)
+}
 
-	// Check if server supports prepareRename
-	caps := server.Capabilities()
-	if caps.RenameProvider == nil {
-		return nil, "", ErrNotSupported
+// prepareRename implements PrepareRename for a server.
+func (as *ActionsService) prepareRename(ctx context.Context, server *Server, path string, pos Position) (RenameRange, error) {
+	if !HasCapability(server.Capabilities().RenameProvider) {
+		return RenameRange{}, ErrNotSupported
+	}
+
+	var content string
+	if doc, ok := server.GetDocument(path); ok {
+		content = doc.Content
+	}
+
+	if !HasPrepareRename(server.Capabilities().RenameProvider) {
+		return wordRenameRange(content, pos)
 	}
 
-	// For now, we don't have PrepareRename in the server, so we return a simple range
-	// A real implementation would call textDocument/prepareRename
-	return &Range{Start: pos, End: pos}, "", nil
+	result, err := server.PrepareRename(ctx, path, pos)
+	if err != nil {
+		return RenameRange{}, err
+	}
+	if result == nil {
+		return RenameRange{}, ErrCannotRename
+	}
+	if result.DefaultBehavior {
+		return wordRenameRange(content, pos)
+	}
+
+	rr := RenameRange{Range: result.Range, Placeholder: result.Placeholder}
+	if rr.Placeholder == "" && content != "" {
+		pc := NewPositionConverter(content)
+		start, end := pc.RangeToByteOffsets(rr.Range)
+		if start >= 0 && start <= end && end <= len(content) {
+			rr.Placeholder = content[start:end]
+		}
+	}
+	return rr, nil
+}
+
+// wordRenameRange returns the identifier under pos in content, for servers
+// that leave finding the symbol to the client.
+func wordRenameRange(content string, pos Position) (RenameRange, error) {
+	pc := NewPositionConverter(content)
+	lineStart, lineEnd := pc.LineByteRange(pos.Line)
+	offset := pc.PositionToByteOffset(pos)
+	if offset < lineStart || offset > lineEnd {
+		return RenameRange{}, ErrCannotRename
+	}
+
+	start := offset
+	for start > lineStart {
+		r, size := utf8.DecodeLastRuneInString(content[:start])
+		if !isIdentifierRune(r) {
+			break
+		}
+		start -= size
+	}
+	end := offset
+	for end < lineEnd {
+		r, size := utf8.DecodeRuneInString(content[end:])
+		if !isIdentifierRune(r) {
+			break
+		}
+		end += size
+	}
+
+	// Identifiers do not start with a digit
+	word := content[start:end]
+	if r, _ := utf8.DecodeRuneInString(word); word == "" || unicode.IsDigit(r) {
+		return RenameRange{}, ErrCannotRename
+	}
+	return RenameRange{Range: pc.ByteOffsetsToRange(start, end), Placeholder: word}, nil
+}
+
+// isIdentifierRune returns true if r can be part of an identifier.
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
 }
 
 // Rename performs a rename operation.
@@ -588,6 +684,10 @@ type ParameterDisplay struct {
 	IsActive bool
 }
 
+// defaultSignatureTriggerCharacters are used when a server supports
+// signature help but does not list trigger characters.
+var defaultSignatureTriggerCharacters = []string{"(", ","}
+
 // GetSignatureHelp returns signature help at the given position.
 func (as *ActionsService) GetSignatureHelp(ctx context.Context, path string, pos Position) (*SignatureHelpResult, error) {
 	server, err := as.getServer(ctx, path)
@@ -604,8 +704,91 @@ func (as *ActionsService) GetSignatureHelp(ctx context.Context, path string, pos
 		return &SignatureHelpResult{}, nil
 	}
 
-	// Track state
+	as.setActiveSignature(path, pos, help)
+	return as.buildSignatureResult(help), nil
+}
+
+// SignatureHelpOnType updates signature help after a character is typed at
+// pos. A trigger character (by default "(" and ",") requests signature help;
+// while help is active the request is sent as a retrigger carrying the
+// current help, so the server can advance the active parameter and keep the
+// selected overload. Typing ")" closes active help. Other characters leave
+// the state unchanged.
+//
+// Returns the active signature help after the update, or nil if none is
+// active. Servers that do not provide signature help yield nil without an
+// error.
+func (as *ActionsService) SignatureHelpOnType(ctx context.Context, path string, pos Position, char string) (*SignatureHelpResult, error) {
+	server, err := as.getServer(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return as.signatureHelpOnType(ctx, server, path, pos, char)
+}
+
+// signatureHelpOnType implements SignatureHelpOnType for a resolved server.
+func (as *ActionsService) signatureHelpOnType(ctx context.Context, server *Server, path string, pos Position, char string) (*SignatureHelpResult, error) {
+	as.mu.RLock()
+	active := as.activeSignature
+	as.mu.RUnlock()
+	if active != nil && active.path != path {
+		active = nil
+	}
+
+	if char == ")" {
+		as.ClearSignatureHelp()
+		return nil, nil
+	}
+
+	opts := server.Capabilities().SignatureHelpProvider
+	if opts == nil {
+		return nil, nil
+	}
+	triggers := opts.TriggerCharacters
+	if len(triggers) == 0 {
+		triggers = defaultSignatureTriggerCharacters
+	}
+	isTrigger := slices.Contains(triggers, char)
+	isRetrigger := active != nil && (isTrigger || slices.Contains(opts.RetriggerCharacters, char))
+	if !isTrigger && !isRetrigger {
+		if active == nil {
+			return nil, nil
+		}
+		return as.GetActiveSignature(), nil
+	}
+
+	sigCtx := &SignatureHelpContext{
+		TriggerKind:      SignatureHelpTriggerKindTriggerCharacter,
+		TriggerCharacter: char,
+		IsRetrigger:      active != nil,
+	}
+	if active != nil {
+		current := *active.help
+		current.ActiveSignature = active.activeSignature
+		sigCtx.ActiveSignatureHelp = &current
+	}
+
+	help, err := server.SignatureHelpWithContext(ctx, path, pos, sigCtx)
+	if errors.Is(err, ErrNotSupported) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if help == nil || len(help.Signatures) == 0 {
+		as.ClearSignatureHelp()
+		return nil, nil
+	}
+
+	as.setActiveSignature(path, pos, help)
+	return as.buildSignatureResult(help), nil
+}
+
+// setActiveSignature records help as the active signature help.
+func (as *ActionsService) setActiveSignature(path string, pos Position, help *SignatureHelp) {
 	as.mu.Lock()
+	defer as.mu.Unlock()
 	as.activeSignature = &signatureState{
 		path:            path,
 		pos:             pos,
@@ -614,9 +797,6 @@ func (as *ActionsService) GetSignatureHelp(ctx context.Context, path string, pos
 		activeParameter: help.ActiveParameter,
 		timestamp:       time.Now().Unix(),
 	}
-	as.mu.Unlock()
-
-	return as.buildSignatureResult(help), nil
 }
 
 // GetActiveSignature returns the currently active signature (if tracking).
@@ -626,11 +806,29 @@ func (as *ActionsService) GetActiveSignature() *SignatureHelpResult {
 		as.mu.RUnlock()
 		return nil
 	}
-	// Copy the help pointer while holding the lock to avoid races
-	help := as.activeSignature.help
+	// Copy the help while holding the lock to avoid races
+	help := *as.activeSignature.help
+	help.ActiveSignature = as.activeSignature.activeSignature
 	as.mu.RUnlock()
 
-	return as.buildSignatureResult(help)
+	return as.buildSignatureResult(&help)
+}
+
+// CycleSignature selects another overload of the active signature help,
+// moving delta signatures forward (or backward if negative) and wrapping
+// around. Returns the updated signature help, or nil if none is active.
+func (as *ActionsService) CycleSignature(delta int) *SignatureHelpResult {
+	as.mu.Lock()
+	state := as.activeSignature
+	if state == nil || state.help == nil || len(state.help.Signatures) == 0 {
+		as.mu.Unlock()
+		return nil
+	}
+	n := len(state.help.Signatures)
+	state.activeSignature = ((state.activeSignature+delta)%n + n) % n
+	as.mu.Unlock()
+
+	return as.GetActiveSignature()
 }
 
 // ClearSignatureHelp clears the active signature help state.
@@ -669,8 +867,9 @@ type ApplyEditResult struct {
 	FailureReason string
 }
 
-// ApplyWorkspaceEdit applies a workspace edit.
-// Note: This is a placeholder - actual implementation depends on the editor's buffer system.
+// ApplyWorkspaceEdit applies a workspace edit through the service's
+// workspace edit applier. If the edit cannot be applied, the result reports
+// why along with the error.
 func (as *ActionsService) ApplyWorkspaceEdit(ctx context.Context, edit WorkspaceEdit) (*ApplyEditResult, error) {
 	result := &ApplyEditResult{
 		ModifiedFiles: make([]string, 0),
@@ -719,10 +918,20 @@ func (as *ActionsService) ApplyWorkspaceEdit(ctx context.Context, edit Workspace
 	// Sort for consistent ordering
 	sort.Strings(result.ModifiedFiles)
 
-	// In a real implementation, this would apply edits through the engine/buffer system
-	// For now, we just report what would be changed
-	result.Applied = true
+	as.mu.RLock()
+	apply := as.applyEdit
+	as.mu.RUnlock()
 
+	err := ErrNoDocumentResolver
+	if apply != nil {
+		err = apply(ctx, edit)
+	}
+	if err != nil {
+		result.FailureReason = err.Error()
+		return result, err
+	}
+
+	result.Applied = true
 	return result, nil
 }
 
@@ -840,24 +1049,24 @@ func (as *ActionsService) buildSignatureResult(help *SignatureHelp) *SignatureHe
 	}
 
 	for i, sig := range help.Signatures {
-		display := SignatureDisplay{
-			Label:                sig.Label,
-			Documentation:        extractDocumentation(sig.Documentation),
-			Parameters:           make([]ParameterDisplay, len(sig.Parameters)),
-			ActiveParameterIndex: sig.ActiveParameter,
-		}
-
 		// Determine active parameter index.
-		// LSP spec: SignatureInformation.activeParameter takes precedence if set (>= 0).
+		// LSP spec: SignatureInformation.activeParameter takes precedence if set.
 		// If the signature doesn't specify, fall back to SignatureHelp.activeParameter.
-		// Both default to 0 which is valid (first parameter), so we use the signature's
-		// value for the active signature, otherwise use the help's global value.
+		// An omitted field decodes as 0, so a signature-level 0 is treated as unset;
+		// otherwise servers that only report the help's value (as most do when
+		// retriggered) would never advance past the first parameter.
 		activeParam := help.ActiveParameter
-		if i == help.ActiveSignature {
-			// For the active signature, prefer the signature-level activeParameter
+		if i == help.ActiveSignature && sig.ActiveParameter != 0 {
 			activeParam = sig.ActiveParameter
 		}
 
+		display := SignatureDisplay{
+			Label:                sig.Label,
+			Documentation:        extractDocumentation(sig.Documentation),
+			Parameters:           make([]ParameterDisplay, len(sig.Parameters)),
+			ActiveParameterIndex: activeParam,
+		}
+
 		for j, param := range sig.Parameters {
 			paramDisplay := ParameterDisplay{
 				Label:         extractParameterLabel(param.Label),
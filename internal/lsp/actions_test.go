@@ -1,6 +1,11 @@
 package lsp
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -544,3 +549,268 @@ func TestNeedsRenameConfirmation(t *testing.T) {
 		t.Error("Should not require confirmation when disabled")
 	}
 }
+
+// signatureTestServer is a ready Server whose signature help requests are
+// answered in-process.
+type signatureTestServer struct {
+	*Server
+
+	mu       sync.Mutex
+	requests []SignatureHelpParams
+}
+
+// newSignatureTestServer creates a server advertising opts (nil for no
+// signature help support) that answers requests with respond.
+func newSignatureTestServer(t *testing.T, opts *SignatureHelpOptions, respond func(SignatureHelpParams) *SignatureHelp) *signatureTestServer {
+	t.Helper()
+
+	s := &signatureTestServer{}
+	caps := ServerCapabilities{SignatureHelpProvider: opts}
+	s.Server, _ = newFakeLSPServer(t, caps, func(_ string, params json.RawMessage) any {
+		var p SignatureHelpParams
+		json.Unmarshal(params, &p)
+		s.mu.Lock()
+		s.requests = append(s.requests, p)
+		s.mu.Unlock()
+		return respond(p)
+	})
+
+	return s
+}
+
+// requestCount returns the number of signature help requests received.
+func (s *signatureTestServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// lastRequest returns the most recent signature help request.
+func (s *signatureTestServer) lastRequest() SignatureHelpParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[len(s.requests)-1]
+}
+
+// respondByCommas answers like a server would for the call in text: the
+// active parameter is the number of commas between "(" and the position.
+// Retriggers keep the overload selected in the active signature help.
+func respondByCommas(text string) func(SignatureHelpParams) *SignatureHelp {
+	return func(params SignatureHelpParams) *SignatureHelp {
+		before := text[:params.Position.Character]
+		open := strings.LastIndex(before, "(")
+		if open < 0 || strings.Contains(before[open:], ")") {
+			return nil
+		}
+		help := &SignatureHelp{
+			Signatures: []SignatureInformation{
+				{Label: "foo(a, b, c)", Parameters: []ParameterInformation{{Label: "a"}, {Label: "b"}, {Label: "c"}}},
+				{Label: "foo(x, y)", Parameters: []ParameterInformation{{Label: "x"}, {Label: "y"}}},
+			},
+			ActiveParameter: strings.Count(before[open:], ","),
+		}
+		if params.Context != nil && params.Context.ActiveSignatureHelp != nil {
+			help.ActiveSignature = params.Context.ActiveSignatureHelp.ActiveSignature
+		}
+		return help
+	}
+}
+
+func TestSignatureHelpOnTypeActiveParameter(t *testing.T) {
+	const text = "foo(a, b, c)"
+	server := newSignatureTestServer(t, &SignatureHelpOptions{TriggerCharacters: []string{"(", ","}}, respondByCommas(text))
+	as := NewActionsService(nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		char        string
+		wantParam   int // -1 for no active help
+		wantRequest bool
+	}{
+		{"f", -1, false},
+		{"o", -1, false},
+		{"o", -1, false},
+		{"(", 0, true},
+		{"a", 0, false},
+		{",", 1, true},
+		{" ", 1, false},
+		{"b", 1, false},
+		{",", 2, true},
+		{" ", 2, false},
+		{"c", 2, false},
+		{")", -1, false},
+	}
+
+	for i, tt := range tests {
+		if text[i:i+1] != tt.char {
+			t.Fatalf("test %d: char %q does not match text", i, tt.char)
+		}
+		before := server.requestCount()
+		pos := Position{Line: 0, Character: i + 1}
+
+		result, err := as.signatureHelpOnType(ctx, server.Server, "/test.go", pos, tt.char)
+		if err != nil {
+			t.Fatalf("typing %q: error = %v", tt.char, err)
+		}
+
+		if requested := server.requestCount() > before; requested != tt.wantRequest {
+			t.Errorf("typing %q at %d: requested = %v, want %v", tt.char, i, requested, tt.wantRequest)
+		}
+		if tt.wantParam < 0 {
+			if result != nil || as.GetActiveSignature() != nil {
+				t.Errorf("typing %q at %d: signature help should not be active", tt.char, i)
+			}
+			continue
+		}
+		if result == nil || result.ActiveSignature == nil {
+			t.Fatalf("typing %q at %d: no active signature", tt.char, i)
+		}
+		if got := result.ActiveSignature.ActiveParameterIndex; got != tt.wantParam {
+			t.Errorf("typing %q at %d: active parameter = %d, want %d", tt.char, i, got, tt.wantParam)
+		}
+		if tt.wantRequest && tt.char == "," {
+			req := server.lastRequest()
+			if req.Context == nil || !req.Context.IsRetrigger || req.Context.ActiveSignatureHelp == nil {
+				t.Errorf("typing %q at %d: request should be a retrigger carrying the active help", tt.char, i)
+			}
+		}
+	}
+}
+
+func TestSignatureHelpCycleOverloads(t *testing.T) {
+	const text = "foo(a, b)"
+	server := newSignatureTestServer(t, &SignatureHelpOptions{TriggerCharacters: []string{"(", ","}}, respondByCommas(text))
+	as := NewActionsService(nil)
+	ctx := context.Background()
+
+	if as.CycleSignature(1) != nil {
+		t.Error("CycleSignature with no active help should return nil")
+	}
+
+	if _, err := as.signatureHelpOnType(ctx, server.Server, "/test.go", Position{Character: 4}, "("); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	result := as.CycleSignature(1)
+	if result == nil || result.ActiveSignature.Label != "foo(x, y)" {
+		t.Fatalf("after CycleSignature(1): %+v, want foo(x, y)", result)
+	}
+
+	// The selected overload survives a retrigger
+	result, err := as.signatureHelpOnType(ctx, server.Server, "/test.go", Position{Character: 6}, ",")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if result.ActiveSignature.Label != "foo(x, y)" || result.ActiveSignature.ActiveParameterIndex != 1 {
+		t.Errorf("after retrigger: %q param %d, want foo(x, y) param 1",
+			result.ActiveSignature.Label, result.ActiveSignature.ActiveParameterIndex)
+	}
+
+	// Cycling wraps around
+	if result := as.CycleSignature(1); result.ActiveSignature.Label != "foo(a, b, c)" {
+		t.Errorf("after wrapping: %q, want foo(a, b, c)", result.ActiveSignature.Label)
+	}
+	if result := as.CycleSignature(-1); result.ActiveSignature.Label != "foo(x, y)" {
+		t.Errorf("after CycleSignature(-1): %q, want foo(x, y)", result.ActiveSignature.Label)
+	}
+}
+
+func TestSignatureHelpOnTypeUnsupported(t *testing.T) {
+	server := newSignatureTestServer(t, nil, respondByCommas("foo("))
+	as := NewActionsService(nil)
+
+	result, err := as.signatureHelpOnType(context.Background(), server.Server, "/test.go", Position{Character: 4}, "(")
+	if err != nil || result != nil {
+		t.Errorf("signatureHelpOnType = %v, %v, want nil, nil", result, err)
+	}
+	if server.requestCount() != 0 {
+		t.Errorf("requests = %d, want 0", server.requestCount())
+	}
+}
+
+// newRenameTestServer creates a ready server advertising provider as its
+// rename capability, with content open as path, that answers prepareRename
+// requests with respond.
+func newRenameTestServer(t *testing.T, provider any, path, content string, respond func(PrepareRenameParams) any) *Server {
+	t.Helper()
+
+	s, _ := newFakeLSPServer(t, ServerCapabilities{RenameProvider: provider}, func(_ string, params json.RawMessage) any {
+		var p PrepareRenameParams
+		json.Unmarshal(params, &p)
+		return respond(p)
+	})
+	s.documents[FilePathToURI(path)] = &Document{URI: FilePathToURI(path), LanguageID: "go", Content: content}
+
+	return s
+}
+
+func TestPrepareRename(t *testing.T) {
+	const content = "func main() {\n\tcount := 1\n}\n"
+
+	// Like a real server, only identifiers can be renamed
+	respond := func(params PrepareRenameParams) any {
+		if params.Position.Line != 1 {
+			return nil
+		}
+		return Range{Start: Position{Line: 1, Character: 1}, End: Position{Line: 1, Character: 6}}
+	}
+	server := newRenameTestServer(t, map[string]any{"prepareProvider": true}, "/test.go", content, respond)
+	as := NewActionsService(nil)
+	ctx := context.Background()
+
+	// The keyword "func"
+	if _, err := as.prepareRename(ctx, server, "/test.go", Position{Line: 0, Character: 1}); !errors.Is(err, ErrCannotRename) {
+		t.Errorf("prepareRename(keyword) error = %v, want ErrCannotRename", err)
+	}
+
+	// The identifier "count"
+	got, err := as.prepareRename(ctx, server, "/test.go", Position{Line: 1, Character: 3})
+	if err != nil {
+		t.Fatalf("prepareRename(identifier) error = %v", err)
+	}
+	wantRange := Range{Start: Position{Line: 1, Character: 1}, End: Position{Line: 1, Character: 6}}
+	if got.Range != wantRange || got.Placeholder != "count" {
+		t.Errorf("prepareRename(identifier) = %+v, want %v with placeholder count", got, wantRange)
+	}
+}
+
+func TestPrepareRenameDefaultBehavior(t *testing.T) {
+	const content = "x := strings.Repeat(name, 2)\n"
+	ctx := context.Background()
+	as := NewActionsService(nil)
+
+	tests := []struct {
+		name     string
+		provider any
+	}{
+		{"without prepare support", true},
+		{"server default behavior", &RenameOptions{PrepareProvider: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newRenameTestServer(t, tt.provider, "/test.go", content, func(PrepareRenameParams) any {
+				return map[string]bool{"defaultBehavior": true}
+			})
+
+			got, err := as.prepareRename(ctx, server, "/test.go", Position{Character: 22})
+			if err != nil {
+				t.Fatalf("prepareRename() error = %v", err)
+			}
+			want := Range{Start: Position{Character: 20}, End: Position{Character: 24}}
+			if got.Range != want || got.Placeholder != "name" {
+				t.Errorf("prepareRename() = %+v, want %v with placeholder name", got, want)
+			}
+
+			// Punctuation is not renameable
+			if _, err := as.prepareRename(ctx, server, "/test.go", Position{Character: 2}); !errors.Is(err, ErrCannotRename) {
+				t.Errorf("prepareRename(:=) error = %v, want ErrCannotRename", err)
+			}
+		})
+	}
+
+	server := newRenameTestServer(t, nil, "/test.go", content, nil)
+	if _, err := as.prepareRename(ctx, server, "/test.go", Position{}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("prepareRename() without rename support error = %v, want ErrNotSupported", err)
+	}
+}
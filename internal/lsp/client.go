@@ -30,6 +30,12 @@ type Client struct {
 	// Configuration
 	config ClientConfig
 
+	// Applied workspace edits, most recent last, for UndoWorkspaceEdit
+	editHistory []*appliedWorkspaceEdit
+
+	// Resolves the documents that code action edits apply to
+	resolver DocumentResolver
+
 	// Event callbacks
 	onDiagnostics func(path string, diagnostics []Diagnostic)
 	// Note: Server lifecycle callbacks are reserved for future use.
@@ -280,6 +286,7 @@ func (c *Client) Start(ctx context.Context) error {
 		WithCodeActionKinds(c.config.CodeActionKinds),
 		WithCodeActionCacheAge(c.config.CodeActionCacheAge),
 		WithRenameConfirmation(c.config.RenameConfirmation),
+		WithWorkspaceEditApplier(c.applyWithResolver),
 	)
 
 	c.mu.Lock()
@@ -426,6 +433,17 @@ func (c *Client) CompleteWithTrigger(ctx context.Context, path string, pos Posit
 	return svc.completion.CompleteWithTrigger(ctx, path, pos, triggerChar)
 }
 
+// CompleteLatest returns code completions at a position, cancelling any
+// CompleteLatest request still in flight for the same file. The cancelled
+// call returns context.Canceled.
+func (c *Client) CompleteLatest(ctx context.Context, path string, pos Position, prefix string) (*CompletionResult, error) {
+	svc, err := c.getServices()
+	if err != nil {
+		return nil, err
+	}
+	return svc.completion.CompleteLatest(ctx, path, pos, prefix)
+}
+
 // ResolveCompletion resolves additional details for a completion item.
 func (c *Client) ResolveCompletion(ctx context.Context, path string, item CompletionItem) (*CompletionItem, error) {
 	svc, err := c.getServices()
@@ -554,6 +572,17 @@ func (c *Client) FindReferences(ctx context.Context, path string, pos Position)
 	return svc.navigation.FindReferences(ctx, path, pos)
 }
 
+// DocumentHighlight returns the occurrences of the symbol at a position,
+// marked as text, read or write accesses. Returns ErrNotSupported if the
+// server has no document highlight support.
+func (c *Client) DocumentHighlight(ctx context.Context, path string, pos Position) ([]DocumentHighlight, error) {
+	svc, err := c.getServices()
+	if err != nil {
+		return nil, err
+	}
+	return svc.manager.DocumentHighlight(ctx, path, pos)
+}
+
 // DocumentSymbols returns all symbols in a document.
 func (c *Client) DocumentSymbols(ctx context.Context, path string) ([]DocumentSymbol, error) {
 	svc, err := c.getServices()
@@ -590,6 +619,54 @@ func (c *Client) SymbolAtPosition(ctx context.Context, path string, pos Position
 	return svc.navigation.GetSymbolAtPosition(ctx, path, pos)
 }
 
+// --- Code Lens ---
+
+// CodeLens returns the code lenses of a document, such as "run test" or
+// "N references". Lenses may lack a command until resolved with
+// ResolveCodeLens. Returns ErrNotSupported if the server has no code lens
+// support.
+func (c *Client) CodeLens(ctx context.Context, path string) ([]CodeLens, error) {
+	svc, err := c.getServices()
+	if err != nil {
+		return nil, err
+	}
+	return svc.manager.CodeLens(ctx, path)
+}
+
+// ResolveCodeLens resolves the command of a code lens returned by CodeLens.
+// Resolving is lazy: callers resolve only the lenses they display.
+func (c *Client) ResolveCodeLens(ctx context.Context, path string, lens CodeLens) (*CodeLens, error) {
+	svc, err := c.getServices()
+	if err != nil {
+		return nil, err
+	}
+	return svc.manager.ResolveCodeLens(ctx, path, lens)
+}
+
+// --- Inlay Hints ---
+
+// InlayHints returns the inlay hints of a document within rng, typically
+// the visible lines, such as inferred types and parameter names. Hints may
+// lack tooltips and edits until resolved with ResolveInlayHint. Returns
+// ErrNotSupported if the server has no inlay hint support.
+func (c *Client) InlayHints(ctx context.Context, path string, rng Range) ([]InlayHint, error) {
+	svc, err := c.getServices()
+	if err != nil {
+		return nil, err
+	}
+	return svc.manager.InlayHints(ctx, path, rng)
+}
+
+// ResolveInlayHint resolves the tooltip, edits and label part details of an
+// inlay hint returned by InlayHints, typically when the hint is hovered.
+func (c *Client) ResolveInlayHint(ctx context.Context, path string, hint InlayHint) (*InlayHint, error) {
+	svc, err := c.getServices()
+	if err != nil {
+		return nil, err
+	}
+	return svc.manager.ResolveInlayHint(ctx, path, hint)
+}
+
 // --- Code Actions and Formatting ---
 
 // CodeActions returns available code actions for a range.
@@ -657,11 +734,12 @@ func (c *Client) FormatRange(ctx context.Context, path string, rng Range) (*Form
 
 // --- Rename ---
 
-// PrepareRename checks if rename is valid at a position.
-func (c *Client) PrepareRename(ctx context.Context, path string, pos Position) (*Range, string, error) {
+// PrepareRename checks that the symbol at a position can be renamed and
+// returns its range and current name. Returns ErrCannotRename if it cannot.
+func (c *Client) PrepareRename(ctx context.Context, path string, pos Position) (RenameRange, error) {
 	svc, err := c.getServices()
 	if err != nil {
-		return nil, "", err
+		return RenameRange{}, err
 	}
 	return svc.actions.PrepareRename(ctx, path, pos)
 }
@@ -694,6 +772,29 @@ func (c *Client) NeedsRenameConfirmation() bool {
 	return c.actions.NeedsRenameConfirmation()
 }
 
+// --- File Operations ---
+
+// WillRenameFiles asks the running servers for the edits to apply before
+// files are renamed, such as updated imports, merged into one workspace
+// edit. Each server only sees the renames matching its registered filters.
+// Apply the result with ApplyWorkspaceEdit before moving the files.
+func (c *Client) WillRenameFiles(ctx context.Context, renames []FileRename) (WorkspaceEdit, error) {
+	svc, err := c.getServices()
+	if err != nil {
+		return WorkspaceEdit{}, err
+	}
+	return svc.manager.WillRenameFiles(ctx, renames)
+}
+
+// DidRenameFiles notifies the running servers that files were renamed.
+func (c *Client) DidRenameFiles(ctx context.Context, renames []FileRename) error {
+	svc, err := c.getServices()
+	if err != nil {
+		return err
+	}
+	return svc.manager.DidRenameFiles(ctx, renames)
+}
+
 // --- Signature Help ---
 
 // SignatureHelp returns signature help at a position.
@@ -705,6 +806,28 @@ func (c *Client) SignatureHelp(ctx context.Context, path string, pos Position) (
 	return svc.actions.GetSignatureHelp(ctx, path, pos)
 }
 
+// SignatureHelpOnType updates signature help after a character is typed.
+// Trigger characters request or retrigger signature help and ")" closes it.
+// Returns the active signature help, or nil if none is active.
+func (c *Client) SignatureHelpOnType(ctx context.Context, path string, pos Position, char string) (*SignatureHelpResult, error) {
+	svc, err := c.getServices()
+	if err != nil {
+		return nil, err
+	}
+	return svc.actions.SignatureHelpOnType(ctx, path, pos, char)
+}
+
+// CycleSignature selects another overload of the active signature help.
+// Returns nil if no signature help is active.
+func (c *Client) CycleSignature(delta int) *SignatureHelpResult {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.status != ClientStatusReady || c.actions == nil {
+		return nil
+	}
+	return c.actions.CycleSignature(delta)
+}
+
 // ActiveSignature returns the currently tracked active signature.
 func (c *Client) ActiveSignature() *SignatureHelpResult {
 	c.mu.RLock()
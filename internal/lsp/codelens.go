@@ -0,0 +1,43 @@
+package lsp
+
+import "sort"
+
+// CodeLensRange is a code lens resolved to byte offsets, ready to be shown
+// by the renderer as virtual text above its line.
+type CodeLensRange struct {
+	// Line is the zero-based line the lens is shown above.
+	Line int
+
+	// Start and End are the byte offsets of the lens range.
+	Start int
+	End   int
+
+	// Lens is the code lens.
+	Lens CodeLens
+}
+
+// CodeLensRanges converts code lenses, whose positions are in UTF-16 code
+// units, to byte offsets in content. The result is ordered by position so
+// lenses on the same line keep their order. Returns nil for no lenses.
+func CodeLensRanges(content string, lenses []CodeLens) []CodeLensRange {
+	if len(lenses) == 0 {
+		return nil
+	}
+
+	pc := NewPositionConverter(content)
+	result := make([]CodeLensRange, 0, len(lenses))
+	for _, lens := range lenses {
+		start, end := pc.RangeToByteOffsets(lens.Range)
+		result = append(result, CodeLensRange{
+			Line:  lens.Range.Start.Line,
+			Start: start,
+			End:   end,
+			Lens:  lens,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Start < result[j].Start
+	})
+	return result
+}
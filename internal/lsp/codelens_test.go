@@ -0,0 +1,142 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// newCodeLensTestServer creates a ready server advertising opts as its
+// code lens capability. textDocument/codeLens answers with lenses and
+// codeLens/resolve answers with resolve applied to the request lens.
+// The returned function reports the methods requested so far.
+func newCodeLensTestServer(t *testing.T, opts *CodeLensOptions, lenses []CodeLens, resolve func(CodeLens) CodeLens) (*Server, func() []string) {
+	t.Helper()
+
+	return newFakeLSPServer(t, ServerCapabilities{CodeLensProvider: opts}, func(method string, params json.RawMessage) any {
+		if method != "codeLens/resolve" {
+			return lenses
+		}
+		var lens CodeLens
+		json.Unmarshal(params, &lens)
+		return resolve(lens)
+	})
+}
+
+func TestServerCodeLensResolve(t *testing.T) {
+	lenses := []CodeLens{
+		{Range: Range{Start: Position{Line: 2}, End: Position{Line: 2, Character: 4}}, Data: "refs"},
+		{
+			Range:   Range{Start: Position{Line: 0}, End: Position{Line: 0, Character: 4}},
+			Command: &Command{Title: "run test", Command: "go.test"},
+		},
+	}
+	resolve := func(lens CodeLens) CodeLens {
+		lens.Command = &Command{Title: "3 references", Command: "go.references", Arguments: []any{lens.Data}}
+		return lens
+	}
+	s, methods := newCodeLensTestServer(t, &CodeLensOptions{ResolveProvider: true}, lenses, resolve)
+
+	got, err := s.CodeLens(context.Background(), "/tmp/main_test.go")
+	if err != nil {
+		t.Fatalf("CodeLens() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("CodeLens() returned %d lenses, want 2", len(got))
+	}
+	if got[0].IsResolved() {
+		t.Error("first lens should be unresolved")
+	}
+
+	resolved, err := s.ResolveCodeLens(context.Background(), got[0])
+	if err != nil {
+		t.Fatalf("ResolveCodeLens() error = %v", err)
+	}
+	if !resolved.IsResolved() || resolved.Command.Title != "3 references" {
+		t.Errorf("resolved command = %+v, want title %q", resolved.Command, "3 references")
+	}
+	if resolved.Range != got[0].Range {
+		t.Errorf("resolved range = %+v, want %+v", resolved.Range, got[0].Range)
+	}
+	if len(resolved.Command.Arguments) != 1 || resolved.Command.Arguments[0] != "refs" {
+		t.Errorf("resolved arguments = %v, want data passed back", resolved.Command.Arguments)
+	}
+
+	// Already resolved lenses do not go to the server.
+	same, err := s.ResolveCodeLens(context.Background(), got[1])
+	if err != nil {
+		t.Fatalf("ResolveCodeLens() error = %v", err)
+	}
+	if same.Command.Title != "run test" {
+		t.Errorf("resolved lens title = %q, want %q", same.Command.Title, "run test")
+	}
+
+	want := []string{"textDocument/codeLens", "codeLens/resolve"}
+	if m := methods(); strings.Join(m, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", m, want)
+	}
+}
+
+func TestServerCodeLensWithoutResolve(t *testing.T) {
+	lenses := []CodeLens{{Range: Range{Start: Position{Line: 1}}}}
+	s, methods := newCodeLensTestServer(t, &CodeLensOptions{}, lenses, nil)
+
+	got, err := s.CodeLens(context.Background(), "/tmp/main.go")
+	if err != nil {
+		t.Fatalf("CodeLens() error = %v", err)
+	}
+	if s.SupportsCodeLensResolve() {
+		t.Error("SupportsCodeLensResolve() = true, want false")
+	}
+
+	lens, err := s.ResolveCodeLens(context.Background(), got[0])
+	if err != nil {
+		t.Fatalf("ResolveCodeLens() error = %v", err)
+	}
+	if lens.IsResolved() {
+		t.Error("lens should stay unresolved")
+	}
+	if m := methods(); len(m) != 1 {
+		t.Errorf("requests = %v, want only textDocument/codeLens", m)
+	}
+}
+
+func TestServerCodeLensNotSupported(t *testing.T) {
+	s, _ := newCodeLensTestServer(t, nil, nil, nil)
+
+	_, err := s.CodeLens(context.Background(), "/tmp/main.go")
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("CodeLens() error = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestCodeLensRanges(t *testing.T) {
+	content := "package é\n\nfunc 😀Test() {}\n"
+	lenses := []CodeLens{
+		{Range: Range{Start: Position{Line: 2, Character: 5}, End: Position{Line: 2, Character: 11}}},
+		{Range: Range{Start: Position{Line: 0, Character: 8}, End: Position{Line: 0, Character: 9}}},
+	}
+
+	ranges := CodeLensRanges(content, lenses)
+	want := []CodeLensRange{
+		{Line: 0, Start: 8, End: 10, Lens: lenses[1]},
+		{Line: 2, Start: 17, End: 25, Lens: lenses[0]},
+	}
+	if len(ranges) != len(want) {
+		t.Fatalf("CodeLensRanges() returned %d ranges, want %d", len(ranges), len(want))
+	}
+	for i, r := range ranges {
+		if r.Line != want[i].Line || r.Start != want[i].Start || r.End != want[i].End {
+			t.Errorf("CodeLensRanges()[%d] = %+v, want %+v", i, r, want[i])
+		}
+	}
+	if text := content[ranges[1].Start:ranges[1].End]; text != "😀Test" {
+		t.Errorf("lens covers %q, want %q", text, "😀Test")
+	}
+
+	if CodeLensRanges(content, nil) != nil {
+		t.Error("CodeLensRanges(nil) should return nil")
+	}
+}
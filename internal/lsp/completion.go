@@ -7,6 +7,8 @@ import (
 	"sync"
 	"time"
 	"unicode"
+
+	"github.com/dshills/keystorm/internal/langid"
 )
 
 // CompletionService provides high-level completion functionality with
@@ -26,6 +28,16 @@ type CompletionService struct {
 
 	// Trigger character tracking
 	triggerChars map[string][]string // languageID -> trigger chars
+
+	// In-flight CompleteLatest requests by path
+	latest     map[string]latestCompletion
+	nextLatest uint64
+}
+
+// latestCompletion is an in-flight CompleteLatest request.
+type latestCompletion struct {
+	id     uint64
+	cancel context.CancelFunc
 }
 
 // cacheKey identifies a cached completion result by position (not prefix).
@@ -77,6 +89,7 @@ func NewCompletionService(mgr *Manager, opts ...CompletionServiceOption) *Comple
 		cacheCleanupCap: 100,
 		cache:           make(map[cacheKey]*cachedCompletion),
 		triggerChars:    make(map[string][]string),
+		latest:          make(map[string]latestCompletion),
 	}
 
 	for _, opt := range opts {
@@ -98,7 +111,7 @@ func (cs *CompletionService) Complete(ctx context.Context, path string, pos Posi
 		return nil, err
 	}
 
-	languageID := DetectLanguageID(path)
+	languageID := langid.FromPath(path)
 
 	// Check cache - key by position, not prefix
 	key := cacheKey{
@@ -143,6 +156,53 @@ func (cs *CompletionService) CompleteWithTrigger(ctx context.Context, path strin
 	return cs.processResults(list, ""), nil
 }
 
+// CompleteLatest is Complete for completion as the user types: it cancels
+// any CompleteLatest request still in flight for the same document, whose
+// caller then gets context.Canceled, so only the newest request keeps the
+// server busy.
+func (cs *CompletionService) CompleteLatest(ctx context.Context, path string, pos Position, prefix string) (*CompletionResult, error) {
+	ctx, release := cs.trackLatest(ctx, path)
+	defer release()
+	return cs.Complete(ctx, path, pos, prefix)
+}
+
+// CancelLatest cancels the CompleteLatest request in flight for a
+// document, if any.
+func (cs *CompletionService) CancelLatest(path string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if prev, ok := cs.latest[path]; ok {
+		prev.cancel()
+		delete(cs.latest, path)
+	}
+}
+
+// trackLatest cancels the previous CompleteLatest request for path and
+// registers a new one. The returned release func must be called when the
+// request finishes.
+func (cs *CompletionService) trackLatest(parent context.Context, path string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+
+	cs.mu.Lock()
+	if prev, ok := cs.latest[path]; ok {
+		prev.cancel()
+	}
+	cs.nextLatest++
+	id := cs.nextLatest
+	cs.latest[path] = latestCompletion{id: id, cancel: cancel}
+	cs.mu.Unlock()
+
+	return ctx, func() {
+		cs.mu.Lock()
+		if cur, ok := cs.latest[path]; ok && cur.id == id {
+			delete(cs.latest, path)
+		}
+		cs.mu.Unlock()
+		cancel()
+	}
+}
+
 // ResolveItem resolves additional details for a completion item.
 func (cs *CompletionService) ResolveItem(ctx context.Context, path string, item CompletionItem) (*CompletionItem, error) {
 	if cs.manager == nil {
@@ -163,7 +223,7 @@ func (cs *CompletionService) IsTriggerCharacter(ctx context.Context, path string
 		return false
 	}
 
-	languageID := DetectLanguageID(path)
+	languageID := langid.FromPath(path)
 
 	cs.mu.RLock()
 	chars, ok := cs.triggerChars[languageID]
@@ -205,7 +265,7 @@ func (cs *CompletionService) GetTriggerCharacters(ctx context.Context, path stri
 		return nil
 	}
 
-	languageID := DetectLanguageID(path)
+	languageID := langid.FromPath(path)
 
 	cs.mu.RLock()
 	chars, ok := cs.triggerChars[languageID]
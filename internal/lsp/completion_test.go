@@ -1,7 +1,10 @@
 package lsp
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestFuzzyMatch(t *testing.T) {
@@ -470,3 +473,69 @@ func TestCompletionResult_Empty(t *testing.T) {
 		t.Error("processResults(empty) should return nil items")
 	}
 }
+
+func TestCompletionService_CompleteLatest(t *testing.T) {
+	transport, fake := newFakeServerTransport(t)
+
+	server := &Server{
+		config:       ServerConfig{Timeout: 5 * time.Second},
+		languageID:   "go",
+		transport:    transport,
+		capabilities: ServerCapabilities{CompletionProvider: &CompletionOptions{}},
+	}
+	server.status.Store(int32(ServerStatusReady))
+	mgr := NewManager()
+	mgr.servers["go"] = server
+	cs := NewCompletionService(mgr, WithCacheTimeout(0))
+
+	type result struct {
+		res *CompletionResult
+		err error
+	}
+	first := make(chan result, 1)
+	go func() {
+		res, err := cs.CompleteLatest(context.Background(), "/tmp/main.go", Position{Line: 1, Character: 2}, "")
+		first <- result{res, err}
+	}()
+	req1 := fake.next(t)
+
+	second := make(chan result, 1)
+	go func() {
+		res, err := cs.CompleteLatest(context.Background(), "/tmp/main.go", Position{Line: 1, Character: 3}, "")
+		second <- result{res, err}
+	}()
+
+	// The new request cancels the one still in flight for the document
+	var req2 Request
+	for i := 0; i < 2; i++ {
+		if msg := fake.next(t); msg.Method == "$/cancelRequest" {
+			if id := cancelledID(t, msg); id != req1.ID {
+				t.Errorf("cancelled ID = %d, want %d", id, req1.ID)
+			}
+		} else {
+			req2 = msg
+		}
+	}
+	if r := <-first; !errors.Is(r.err, context.Canceled) {
+		t.Errorf("first CompleteLatest() error = %v, want context.Canceled", r.err)
+	}
+
+	fake.reply(req2.ID, CompletionList{Items: []CompletionItem{{Label: "Println"}}})
+	select {
+	case r := <-second:
+		if r.err != nil {
+			t.Fatalf("second CompleteLatest() error = %v", r.err)
+		}
+		if len(r.res.Items) != 1 || r.res.Items[0].Label != "Println" {
+			t.Errorf("second CompleteLatest() items = %v", r.res.Items)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("second CompleteLatest() did not return")
+	}
+
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	if len(cs.latest) != 0 {
+		t.Errorf("latest = %v after requests finished, want empty", cs.latest)
+	}
+}
@@ -2,12 +2,16 @@ package lsp
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
 	"github.com/dshills/keystorm/internal/dispatcher/handler"
 	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/cursor"
 	"github.com/dshills/keystorm/internal/input"
+	"github.com/dshills/keystorm/internal/langid"
 )
 
 // Action names for LSP operations.
@@ -22,6 +26,8 @@ const (
 	ActionHover         = "lsp.hover"
 	ActionCompletion    = "lsp.completion"
 	ActionSignatureHelp = "lsp.signatureHelp"
+	ActionNextSignature = "lsp.nextSignature"
+	ActionPrevSignature = "lsp.prevSignature"
 
 	// Symbols
 	ActionDocumentSymbols  = "lsp.documentSymbols"
@@ -53,18 +59,37 @@ const (
 	ActionServerStatus  = "lsp.serverStatus"
 )
 
+// editorInsertCharAction is the editor action for typing a character,
+// watched by PostDispatch to keep signature help up to date.
+const editorInsertCharAction = "editor.insertChar"
+
 // Handler provides LSP operations as dispatcher actions.
 // It implements the dispatcher's NamespaceHandler interface for the "lsp" namespace.
 //
 // Handler is NOT safe for concurrent use. SetClient should only be called
 // during initialization or when the handler is not being used by the dispatcher.
 // The actions map is populated once at construction and never modified afterward.
+// PostDispatch and DocumentSynced may be called from different goroutines.
 type Handler struct {
 	client         *Client
 	requestTimeout time.Duration
 
 	// Actions registered by name (immutable after construction)
 	actions map[string]func(action input.Action, ctx *execctx.ExecutionContext) handler.Result
+
+	// Signature help while typing
+	onSignatureHelp func(path string, help *SignatureHelpResult)
+	sigMu           sync.Mutex
+	sigPending      map[string]signatureTrigger
+	sigSeq          uint64
+}
+
+// signatureTrigger is a typed character waiting for its didChange to be
+// sent before signature help is requested.
+type signatureTrigger struct {
+	pos     Position
+	char    string
+	hadHelp bool
 }
 
 // HandlerOption configures the LSP handler.
@@ -84,11 +109,21 @@ func WithHandlerTimeout(timeout time.Duration) HandlerOption {
 	}
 }
 
+// WithSignatureHelpCallback sets the callback that receives signature help
+// updated while typing. It is called from a background goroutine with the
+// active help, or with nil when help that was showing has closed.
+func WithSignatureHelpCallback(cb func(path string, help *SignatureHelpResult)) HandlerOption {
+	return func(h *Handler) {
+		h.onSignatureHelp = cb
+	}
+}
+
 // NewHandler creates a new LSP dispatcher handler.
 func NewHandler(opts ...HandlerOption) *Handler {
 	h := &Handler{
 		requestTimeout: 5 * time.Second,
 		actions:        make(map[string]func(action input.Action, ctx *execctx.ExecutionContext) handler.Result),
+		sigPending:     make(map[string]signatureTrigger),
 	}
 
 	for _, opt := range opts {
@@ -138,6 +173,8 @@ func (h *Handler) registerActions() {
 	h.actions[ActionHover] = h.handleHover
 	h.actions[ActionCompletion] = h.handleCompletion
 	h.actions[ActionSignatureHelp] = h.handleSignatureHelp
+	h.actions[ActionNextSignature] = h.handleNextSignature
+	h.actions[ActionPrevSignature] = h.handlePrevSignature
 
 	// Symbols
 	h.actions[ActionDocumentSymbols] = h.handleDocumentSymbols
@@ -169,11 +206,64 @@ func (h *Handler) registerActions() {
 	h.actions[ActionServerStatus] = h.handleServerStatus
 }
 
+// DescribeActions implements handler.DescribableHandler.
+func (h *Handler) DescribeActions() []handler.ActionInfo {
+	offsetArg := []handler.ArgInfo{{Name: "offset", Description: "Byte offset to use instead of the cursor"}}
+	languageArg := []handler.ArgInfo{{Name: "language", Description: "Language of the server"}}
+	return []handler.ActionInfo{
+		{Name: ActionGotoDefinition, Description: "Go to definition", Args: offsetArg},
+		{Name: ActionGotoTypeDefinition, Description: "Go to type definition"},
+		{Name: ActionGotoImplementation, Description: "Go to implementation"},
+		{Name: ActionFindReferences, Description: "Find references"},
+		{Name: ActionHover, Description: "Show hover information", Args: offsetArg},
+		{Name: ActionCompletion, Description: "Request completions", Args: []handler.ArgInfo{
+			{Name: "prefix", Description: "Text typed before the cursor"},
+			{Name: "triggerCharacter", Description: "Character that triggered completion"},
+		}},
+		{Name: ActionSignatureHelp, Description: "Show signature help", Args: []handler.ArgInfo{
+			{Name: "triggerCharacter", Description: "Character that triggered signature help"},
+		}},
+		{Name: ActionNextSignature, Description: "Show next signature"},
+		{Name: ActionPrevSignature, Description: "Show previous signature"},
+		{Name: ActionDocumentSymbols, Description: "List document symbols"},
+		{Name: ActionWorkspaceSymbols, Description: "Search workspace symbols", Args: []handler.ArgInfo{
+			{Name: "query", Description: "Symbol search query"},
+			{Name: "language", Description: "Language of the server to query"},
+		}},
+		{Name: ActionCodeAction, Description: "List code actions", Args: []handler.ArgInfo{
+			{Name: "diagnostics", Description: "Diagnostics to fix"},
+		}},
+		{Name: ActionApplyCodeEdit, Description: "Apply a workspace edit", Args: []handler.ArgInfo{
+			{Name: "edit", Description: "Workspace edit to apply", Required: true},
+		}},
+		{Name: ActionFormat, Description: "Format document"},
+		{Name: ActionFormatRange, Description: "Format selection"},
+		{Name: ActionFormatOnType, Description: "Format after typing"},
+		{Name: ActionOrganizeImports, Description: "Organize imports"},
+		{Name: ActionRename, Description: "Rename symbol", Args: []handler.ArgInfo{
+			{Name: "newName", Description: "New symbol name", Required: true},
+		}},
+		{Name: ActionPrepareRename, Description: "Check that the symbol can be renamed"},
+		{Name: ActionExtractVariable, Description: "Extract variable"},
+		{Name: ActionExtractFunction, Description: "Extract function"},
+		{Name: ActionNextDiagnostic, Description: "Go to next diagnostic"},
+		{Name: ActionPrevDiagnostic, Description: "Go to previous diagnostic"},
+		{Name: ActionShowDiagnostic, Description: "Show diagnostic at cursor"},
+		{Name: ActionRestartServer, Description: "Restart language server", Args: languageArg},
+		{Name: ActionServerStatus, Description: "Show language server status", Args: languageArg},
+	}
+}
+
 // --- Helper Methods ---
 
-// getContext creates a context with timeout.
-func (h *Handler) getContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), h.requestTimeout)
+// getContext creates a request context with timeout. It is derived from the
+// action's context so that aborting the action cancels the request.
+func (h *Handler) getContext(ctx *execctx.ExecutionContext) (context.Context, context.CancelFunc) {
+	parent := context.Background()
+	if ctx != nil {
+		parent = ctx.Context()
+	}
+	return context.WithTimeout(parent, h.requestTimeout)
 }
 
 // ensureClient checks that a client is configured.
@@ -203,6 +293,21 @@ func (h *Handler) getPositionFromContext(ctx *execctx.ExecutionContext) Position
 	}
 }
 
+// getActionPosition returns the position given by the action's "offset"
+// argument, such as the text under the mouse pointer, or the cursor
+// position if the action has none.
+func (h *Handler) getActionPosition(action input.Action, ctx *execctx.ExecutionContext) Position {
+	if _, ok := action.Args.Get("offset"); !ok || ctx.Engine == nil {
+		return h.getPositionFromContext(ctx)
+	}
+
+	point := ctx.Engine.OffsetToPoint(buffer.ByteOffset(action.Args.GetInt("offset")))
+	return Position{
+		Line:      int(point.Line),
+		Character: int(point.Column),
+	}
+}
+
 // getFilePath extracts the file path from execution context.
 func (h *Handler) getFilePath(ctx *execctx.ExecutionContext) string {
 	return ctx.FilePath
@@ -216,14 +321,35 @@ func (h *Handler) positionToOffset(ctx *execctx.ExecutionContext, pos Position)
 	return ctx.Engine.PointToOffset(positionToPoint(pos))
 }
 
+// gotoPosition moves the primary cursor to pos in the current buffer and
+// centers the view on it. The position's Character counts UTF-16 code
+// units. Positions past the end of the buffer or of their line, as servers
+// report for stale documents, are clamped.
+func (h *Handler) gotoPosition(ctx *execctx.ExecutionContext, pos Position) handler.Result {
+	if ctx.Engine == nil || ctx.Cursors == nil {
+		return handler.Success().WithScrollTo(uint32(pos.Line), uint32(pos.Character), true)
+	}
+	offset := execctx.EnginePointUTF16ToOffset(ctx.Engine, buffer.PointUTF16{
+		Line:   uint32(max(pos.Line, 0)),
+		Column: uint32(max(pos.Character, 0)),
+	})
+	ctx.Cursors.SetPrimary(cursor.NewCursorSelection(offset))
+	point := ctx.Engine.OffsetToPoint(offset)
+	return handler.Success().WithScrollTo(point.Line, point.Column, true)
+}
+
 // navigationResultToHandler converts a NavigationResult to a handler result.
-func (h *Handler) navigationResultToHandler(result *NavigationResult) handler.Result {
+// A single location in the current buffer moves the cursor there.
+func (h *Handler) navigationResultToHandler(ctx *execctx.ExecutionContext, result *NavigationResult) handler.Result {
 	if result == nil || len(result.Locations) == 0 {
 		return handler.NoOpWithMessage("no results found")
 	}
 
 	if len(result.Locations) == 1 {
 		loc := result.Locations[0]
+		if URIToFilePath(loc.URI) == h.getFilePath(ctx) {
+			return h.gotoPosition(ctx, loc.Range.Start).WithData("location", loc)
+		}
 		return handler.Success().
 			WithScrollTo(uint32(loc.Range.Start.Line), uint32(loc.Range.Start.Character), true).
 			WithData("location", loc)
@@ -243,18 +369,18 @@ func (h *Handler) handleGotoDefinition(action input.Action, ctx *execctx.Executi
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
-	pos := h.getPositionFromContext(ctx)
+	pos := h.getActionPosition(action, ctx)
 
 	result, err := h.client.GoToDefinition(reqCtx, path, pos)
 	if err != nil {
 		return handler.Error(err)
 	}
 
-	return h.navigationResultToHandler(result)
+	return h.navigationResultToHandler(ctx, result)
 }
 
 func (h *Handler) handleGotoTypeDefinition(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
@@ -262,7 +388,7 @@ func (h *Handler) handleGotoTypeDefinition(action input.Action, ctx *execctx.Exe
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -273,7 +399,7 @@ func (h *Handler) handleGotoTypeDefinition(action input.Action, ctx *execctx.Exe
 		return handler.Error(err)
 	}
 
-	return h.navigationResultToHandler(result)
+	return h.navigationResultToHandler(ctx, result)
 }
 
 func (h *Handler) handleGotoImplementation(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
@@ -281,7 +407,7 @@ func (h *Handler) handleGotoImplementation(action input.Action, ctx *execctx.Exe
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -292,7 +418,7 @@ func (h *Handler) handleGotoImplementation(action input.Action, ctx *execctx.Exe
 		return handler.Error(err)
 	}
 
-	return h.navigationResultToHandler(result)
+	return h.navigationResultToHandler(ctx, result)
 }
 
 func (h *Handler) handleFindReferences(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
@@ -300,7 +426,7 @@ func (h *Handler) handleFindReferences(action input.Action, ctx *execctx.Executi
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -311,7 +437,7 @@ func (h *Handler) handleFindReferences(action input.Action, ctx *execctx.Executi
 		return handler.Error(err)
 	}
 
-	return h.navigationResultToHandler(result)
+	return h.navigationResultToHandler(ctx, result)
 }
 
 // --- Intelligence Handlers ---
@@ -321,11 +447,11 @@ func (h *Handler) handleHover(action input.Action, ctx *execctx.ExecutionContext
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
-	pos := h.getPositionFromContext(ctx)
+	pos := h.getActionPosition(action, ctx)
 
 	hover, err := h.client.Hover(reqCtx, path, pos)
 	if err != nil {
@@ -346,7 +472,7 @@ func (h *Handler) handleCompletion(action input.Action, ctx *execctx.ExecutionCo
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -362,7 +488,11 @@ func (h *Handler) handleCompletion(action input.Action, ctx *execctx.ExecutionCo
 	if triggerChar != "" {
 		result, err = h.client.CompleteWithTrigger(reqCtx, path, pos, triggerChar)
 	} else {
-		result, err = h.client.Complete(reqCtx, path, pos, prefix)
+		result, err = h.client.CompleteLatest(reqCtx, path, pos, prefix)
+	}
+	if errors.Is(err, context.Canceled) {
+		// A newer completion request for this file replaced this one
+		return handler.NoOpWithMessage("completion superseded")
 	}
 	if err != nil {
 		return handler.Error(err)
@@ -383,13 +513,20 @@ func (h *Handler) handleSignatureHelp(action input.Action, ctx *execctx.Executio
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
 	pos := h.getPositionFromContext(ctx)
 
-	result, err := h.client.SignatureHelp(reqCtx, path, pos)
+	// A trigger character re-requests help as it would when typed
+	var result *SignatureHelpResult
+	var err error
+	if triggerChar := action.Args.GetString("triggerCharacter"); triggerChar != "" {
+		result, err = h.client.SignatureHelpOnType(reqCtx, path, pos, triggerChar)
+	} else {
+		result, err = h.client.SignatureHelp(reqCtx, path, pos)
+	}
 	if err != nil {
 		return handler.Error(err)
 	}
@@ -402,6 +539,101 @@ func (h *Handler) handleSignatureHelp(action input.Action, ctx *execctx.Executio
 		WithData("signatureHelp", result)
 }
 
+func (h *Handler) handleNextSignature(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+	return h.cycleSignature(1)
+}
+
+func (h *Handler) handlePrevSignature(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
+	return h.cycleSignature(-1)
+}
+
+// cycleSignature selects another overload of the active signature help.
+func (h *Handler) cycleSignature(delta int) handler.Result {
+	if err := h.ensureClient(); err != nil {
+		return handler.Error(err)
+	}
+
+	result := h.client.CycleSignature(delta)
+	if result == nil || !result.HasActiveSignature {
+		return handler.NoOpWithMessage("no signature help")
+	}
+
+	return handler.Success().
+		WithData("signatureHelp", result)
+}
+
+// PostDispatch implements dispatcher.PostDispatchHook. After a character is
+// typed it remembers the character so that signature help can be updated
+// once the edit has reached the server; see DocumentSynced. No request is
+// made on the keystroke path and the result is left untouched.
+func (h *Handler) PostDispatch(action *input.Action, ctx *execctx.ExecutionContext, result *handler.Result) {
+	if h.client == nil || h.onSignatureHelp == nil {
+		return
+	}
+	if action.Name != editorInsertCharAction || result.Status != handler.StatusOK {
+		return
+	}
+	path := h.getFilePath(ctx)
+	if action.Args.Text == "" || path == "" {
+		return
+	}
+
+	trigger := signatureTrigger{
+		pos:     h.getPositionFromContext(ctx),
+		char:    action.Args.Text,
+		hadHelp: h.client.ActiveSignature() != nil,
+	}
+	h.sigMu.Lock()
+	h.sigPending[path] = trigger
+	h.sigMu.Unlock()
+}
+
+// DocumentSynced reports that didChange has been sent for path. If a
+// character was typed into path since the last call, signature help is
+// updated in the background: trigger characters request or retrigger it and
+// ")" closes it. The outcome is passed to the signature help callback, and
+// only the newest request delivers. Errors are ignored so that typing is
+// never interrupted.
+func (h *Handler) DocumentSynced(path string) {
+	h.sigMu.Lock()
+	trigger, ok := h.sigPending[path]
+	delete(h.sigPending, path)
+	h.sigSeq++
+	seq := h.sigSeq
+	h.sigMu.Unlock()
+
+	if !ok || h.client == nil || h.onSignatureHelp == nil {
+		return
+	}
+	go h.updateSignatureHelp(path, trigger, seq)
+}
+
+// updateSignatureHelp requests signature help for a typed character and
+// delivers the outcome unless a newer request has started.
+func (h *Handler) updateSignatureHelp(path string, trigger signatureTrigger, seq uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.requestTimeout)
+	defer cancel()
+
+	sig, err := h.client.SignatureHelpOnType(ctx, path, trigger.pos, trigger.char)
+	if err != nil {
+		return
+	}
+
+	h.sigMu.Lock()
+	stale := seq != h.sigSeq
+	h.sigMu.Unlock()
+	if stale {
+		return
+	}
+
+	switch {
+	case sig != nil && sig.HasActiveSignature:
+		h.onSignatureHelp(path, sig)
+	case trigger.hadHelp:
+		h.onSignatureHelp(path, nil)
+	}
+}
+
 // --- Symbol Handlers ---
 
 func (h *Handler) handleDocumentSymbols(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
@@ -409,7 +641,7 @@ func (h *Handler) handleDocumentSymbols(action input.Action, ctx *execctx.Execut
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -433,7 +665,7 @@ func (h *Handler) handleWorkspaceSymbols(action input.Action, ctx *execctx.Execu
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	// Get query from action args
@@ -442,7 +674,7 @@ func (h *Handler) handleWorkspaceSymbols(action input.Action, ctx *execctx.Execu
 	// Get languageID from action args or detect from file
 	languageID := action.Args.GetString("language")
 	if languageID == "" {
-		languageID = DetectLanguageID(h.getFilePath(ctx))
+		languageID = langid.FromPath(h.getFilePath(ctx))
 	}
 
 	symbols, err := h.client.WorkspaceSymbols(reqCtx, query, languageID)
@@ -466,7 +698,7 @@ func (h *Handler) handleCodeAction(action input.Action, ctx *execctx.ExecutionCo
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -571,7 +803,7 @@ func (h *Handler) handleFormat(action input.Action, ctx *execctx.ExecutionContex
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -597,7 +829,7 @@ func (h *Handler) handleFormatRange(action input.Action, ctx *execctx.ExecutionC
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -644,7 +876,7 @@ func (h *Handler) handleOrganizeImports(action input.Action, ctx *execctx.Execut
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -703,16 +935,28 @@ func (h *Handler) handleRename(action input.Action, ctx *execctx.ExecutionContex
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
 	pos := h.getPositionFromContext(ctx)
 
-	// Get new name from action args
+	// Reject renames of keywords, literals and other non-symbols before
+	// asking for a name
+	prepared, err := h.client.PrepareRename(reqCtx, path, pos)
+	if errors.Is(err, ErrCannotRename) {
+		return handler.NoOpWithMessage("rename not available here")
+	}
+	if err != nil {
+		return handler.Error(err)
+	}
+
+	// Without a new name, return the current one to pre-fill the prompt
 	newName := action.Args.GetString("newName")
 	if newName == "" {
-		return handler.Errorf("no new name provided")
+		return handler.NoOpWithMessage("no new name provided").
+			WithData("range", prepared.Range).
+			WithData("placeholder", prepared.Placeholder)
 	}
 
 	result, err := h.client.Rename(reqCtx, path, pos, newName)
@@ -737,24 +981,23 @@ func (h *Handler) handlePrepareRename(action input.Action, ctx *execctx.Executio
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
 	pos := h.getPositionFromContext(ctx)
 
-	rng, placeholder, err := h.client.PrepareRename(reqCtx, path, pos)
+	prepared, err := h.client.PrepareRename(reqCtx, path, pos)
+	if errors.Is(err, ErrCannotRename) {
+		return handler.NoOpWithMessage("rename not available here")
+	}
 	if err != nil {
 		return handler.Error(err)
 	}
 
-	if rng == nil {
-		return handler.NoOpWithMessage("rename not available here")
-	}
-
 	return handler.Success().
-		WithData("range", rng).
-		WithData("placeholder", placeholder)
+		WithData("range", prepared.Range).
+		WithData("placeholder", prepared.Placeholder)
 }
 
 func (h *Handler) handleExtractVariable(action input.Action, ctx *execctx.ExecutionContext) handler.Result {
@@ -763,7 +1006,7 @@ func (h *Handler) handleExtractVariable(action input.Action, ctx *execctx.Execut
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -796,7 +1039,7 @@ func (h *Handler) handleExtractFunction(action input.Action, ctx *execctx.Execut
 		return handler.Error(err)
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	path := h.getFilePath(ctx)
@@ -860,8 +1103,7 @@ func (h *Handler) handleNextDiagnostic(action input.Action, ctx *execctx.Executi
 	for _, diag := range diags {
 		if diag.Range.Start.Line > pos.Line ||
 			(diag.Range.Start.Line == pos.Line && diag.Range.Start.Character > pos.Character) {
-			return handler.Success().
-				WithScrollTo(uint32(diag.Range.Start.Line), uint32(diag.Range.Start.Character), true).
+			return h.gotoPosition(ctx, diag.Range.Start).
 				WithData("diagnostic", diag).
 				WithMessage(diag.Message)
 		}
@@ -869,8 +1111,7 @@ func (h *Handler) handleNextDiagnostic(action input.Action, ctx *execctx.Executi
 
 	// Wrap around to first diagnostic
 	first := diags[0]
-	return handler.Success().
-		WithScrollTo(uint32(first.Range.Start.Line), uint32(first.Range.Start.Character), true).
+	return h.gotoPosition(ctx, first.Range.Start).
 		WithData("diagnostic", first).
 		WithMessage(first.Message)
 }
@@ -893,8 +1134,7 @@ func (h *Handler) handlePrevDiagnostic(action input.Action, ctx *execctx.Executi
 		diag := diags[i]
 		if diag.Range.Start.Line < pos.Line ||
 			(diag.Range.Start.Line == pos.Line && diag.Range.Start.Character < pos.Character) {
-			return handler.Success().
-				WithScrollTo(uint32(diag.Range.Start.Line), uint32(diag.Range.Start.Character), true).
+			return h.gotoPosition(ctx, diag.Range.Start).
 				WithData("diagnostic", diag).
 				WithMessage(diag.Message)
 		}
@@ -902,8 +1142,7 @@ func (h *Handler) handlePrevDiagnostic(action input.Action, ctx *execctx.Executi
 
 	// Wrap around to last diagnostic
 	last := diags[len(diags)-1]
-	return handler.Success().
-		WithScrollTo(uint32(last.Range.Start.Line), uint32(last.Range.Start.Character), true).
+	return h.gotoPosition(ctx, last.Range.Start).
 		WithData("diagnostic", last).
 		WithMessage(last.Message)
 }
@@ -947,14 +1186,14 @@ func (h *Handler) handleRestartServer(action input.Action, ctx *execctx.Executio
 	// Get language from action args or detect from file
 	languageID := action.Args.GetString("language")
 	if languageID == "" {
-		languageID = DetectLanguageID(h.getFilePath(ctx))
+		languageID = langid.FromPath(h.getFilePath(ctx))
 	}
 
 	if languageID == "" {
 		return handler.Errorf("could not determine language")
 	}
 
-	reqCtx, cancel := h.getContext()
+	reqCtx, cancel := h.getContext(ctx)
 	defer cancel()
 
 	if err := h.client.RestartServer(reqCtx, languageID); err != nil {
@@ -972,7 +1211,7 @@ func (h *Handler) handleServerStatus(action input.Action, ctx *execctx.Execution
 	// Get language from action args or detect from file
 	languageID := action.Args.GetString("language")
 	if languageID == "" {
-		languageID = DetectLanguageID(h.getFilePath(ctx))
+		languageID = langid.FromPath(h.getFilePath(ctx))
 	}
 
 	if languageID == "" {
@@ -1009,6 +1248,8 @@ func ListActions() []string {
 		ActionHover,
 		ActionCompletion,
 		ActionSignatureHelp,
+		ActionNextSignature,
+		ActionPrevSignature,
 		// Symbols
 		ActionDocumentSymbols,
 		ActionWorkspaceSymbols,
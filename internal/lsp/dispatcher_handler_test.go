@@ -5,6 +5,10 @@ import (
 	"time"
 
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
+	"github.com/dshills/keystorm/internal/dispatcher/handler"
+	"github.com/dshills/keystorm/internal/engine"
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/engine/cursor"
 	"github.com/dshills/keystorm/internal/input"
 )
 
@@ -77,6 +81,8 @@ func TestHandlerCanHandle(t *testing.T) {
 		{ActionHover, true},
 		{ActionCompletion, true},
 		{ActionSignatureHelp, true},
+		{ActionNextSignature, true},
+		{ActionPrevSignature, true},
 		{ActionDocumentSymbols, true},
 		{ActionWorkspaceSymbols, true},
 		{ActionCodeAction, true},
@@ -206,7 +212,7 @@ func TestHandlerEnsureClient(t *testing.T) {
 func TestHandlerGetContext(t *testing.T) {
 	h := NewHandler(WithHandlerTimeout(5 * time.Second))
 
-	ctx, cancel := h.getContext()
+	ctx, cancel := h.getContext(nil)
 	defer cancel()
 
 	if ctx == nil {
@@ -284,3 +290,104 @@ func TestActionConstants(t *testing.T) {
 		}
 	}
 }
+
+func TestHandlerPostDispatchWithoutClient(t *testing.T) {
+	h := NewHandler()
+
+	action := input.Action{Name: editorInsertCharAction, Args: input.ActionArgs{Text: "("}}
+	result := handler.Success()
+	h.PostDispatch(&action, &execctx.ExecutionContext{FilePath: "/test.go"}, &result)
+
+	if _, ok := result.GetData("signatureHelp"); ok {
+		t.Error("PostDispatch without client should not add signature help")
+	}
+}
+
+func TestHandlerPostDispatchDefersSignatureHelp(t *testing.T) {
+	h := NewHandler(
+		WithLSPClient(NewClient()),
+		WithSignatureHelpCallback(func(string, *SignatureHelpResult) {}),
+	)
+
+	action := input.Action{Name: editorInsertCharAction, Args: input.ActionArgs{Text: "("}}
+	result := handler.Success()
+	h.PostDispatch(&action, &execctx.ExecutionContext{FilePath: "/test.go"}, &result)
+
+	if len(result.Data) != 0 {
+		t.Errorf("PostDispatch result data = %v, want none", result.Data)
+	}
+	if trigger, ok := h.sigPending["/test.go"]; !ok || trigger.char != "(" {
+		t.Fatalf("pending trigger = %+v, %v, want \"(\"", trigger, ok)
+	}
+
+	h.DocumentSynced("/other.go")
+	if _, ok := h.sigPending["/test.go"]; !ok {
+		t.Error("DocumentSynced for another path consumed the trigger")
+	}
+
+	h.DocumentSynced("/test.go")
+	if _, ok := h.sigPending["/test.go"]; ok {
+		t.Error("DocumentSynced should consume the pending trigger")
+	}
+}
+
+// gotoEngine implements the parts of execctx.EngineInterface that
+// navigation reads over a real engine; other methods panic.
+type gotoEngine struct {
+	execctx.EngineInterface
+	eng *engine.Engine
+}
+
+func (e gotoEngine) PointUTF16ToOffset(point buffer.PointUTF16) buffer.ByteOffset {
+	return e.eng.PointUTF16ToOffset(point)
+}
+
+func (e gotoEngine) OffsetToPoint(offset buffer.ByteOffset) buffer.Point {
+	return e.eng.OffsetToPoint(offset)
+}
+
+func TestNavigationResultClampsLocation(t *testing.T) {
+	h := NewHandler()
+	cursors := cursor.NewCursorSetAt(0)
+	ctx := execctx.New().
+		WithEngine(gotoEngine{eng: engine.New(engine.WithContent("one\ntwo"))}).
+		WithCursors(cursors)
+	ctx.FilePath = "/project/main.go"
+
+	// A stale server reports a position past the end of the buffer
+	result := h.navigationResultToHandler(ctx, &NavigationResult{Locations: []Location{{
+		URI:   FilePathToURI("/project/main.go"),
+		Range: Range{Start: Position{Line: 9, Character: 20}},
+	}}})
+	if result.Error != nil {
+		t.Fatalf("navigationResultToHandler() error = %v", result.Error)
+	}
+	if got := cursors.Primary().Head; got != 7 {
+		t.Errorf("cursor = %d, want 7", got)
+	}
+	if st := result.ViewUpdate.ScrollTo; st == nil || st.Line != 1 || st.Column != 3 {
+		t.Errorf("ScrollTo = %+v, want line 1 column 3", st)
+	}
+}
+
+func TestNavigationResultUTF16Column(t *testing.T) {
+	h := NewHandler()
+	cursors := cursor.NewCursorSetAt(0)
+	ctx := execctx.New().
+		WithEngine(gotoEngine{eng: engine.New(engine.WithContent("x := \"é😀\"; foo()"))}).
+		WithCursors(cursors)
+	ctx.FilePath = "/project/main.go"
+
+	// "foo" starts at UTF-16 column 12 but byte offset 15: é is one UTF-16
+	// unit in two bytes and 😀 two units in four bytes
+	result := h.navigationResultToHandler(ctx, &NavigationResult{Locations: []Location{{
+		URI:   FilePathToURI("/project/main.go"),
+		Range: Range{Start: Position{Line: 0, Character: 12}},
+	}}})
+	if result.Error != nil {
+		t.Fatalf("navigationResultToHandler() error = %v", result.Error)
+	}
+	if got := cursors.Primary().Head; got != 15 {
+		t.Errorf("cursor = %d, want 15", got)
+	}
+}
@@ -57,12 +57,28 @@
 //   - Hover information
 //   - Go-to-definition/type-definition
 //   - Find references
+//   - Document highlights of the symbol under the cursor (read/write)
 //   - Document and workspace symbols
 //   - Real-time diagnostics (errors, warnings)
 //   - Code actions (quick fixes, refactorings)
+//   - Code lenses (e.g. "run test"), with lazy command resolution
+//   - Inlay hints (inferred types, parameter names) for the visible range,
+//     re-requested on scroll with InlayHintRefresher and resolved lazily
 //   - Document formatting
-//   - Symbol renaming
-//   - Signature help
+//   - Symbol renaming, checked with prepareRename before asking for a name
+//   - Signature help, updated as arguments are typed
+//   - Multi-file workspace edits with single-step undo
+//   - File renames (willRenameFiles/didRenameFiles), so servers can update
+//     imports when a file moves; only files matching the globs a server
+//     registered are sent to it
+//
+// # Cancellation
+//
+// Every request observes its context: cancelling it, or reaching the
+// request timeout, sends $/cancelRequest to the server and returns
+// ctx.Err() at once. Client.CompleteLatest additionally cancels the
+// previous completion request for the same file, so completion while
+// typing only waits for the newest request.
 //
 // # Multi-Server Support
 //
@@ -46,6 +46,26 @@ var (
 	// ErrServerAlreadyRunning indicates the server is already running.
 	ErrServerAlreadyRunning = errors.New("server already running")
 
+	// ErrStaleDocumentVersion indicates a workspace edit targets an outdated document version.
+	ErrStaleDocumentVersion = errors.New("document version is stale")
+
+	// ErrInvalidWorkspaceEdit indicates a workspace edit could not be decoded.
+	ErrInvalidWorkspaceEdit = errors.New("invalid workspace edit")
+
+	// ErrNoDocumentResolver indicates workspace edits cannot be applied
+	// because no document resolver was set.
+	ErrNoDocumentResolver = errors.New("no document resolver")
+
+	// ErrNoWorkspaceEditToUndo indicates there is no applied workspace edit to undo.
+	ErrNoWorkspaceEditToUndo = errors.New("no workspace edit to undo")
+
+	// ErrWorkspaceEditModified indicates a document was edited after a
+	// workspace edit, so undoing the workspace edit would undo those edits.
+	ErrWorkspaceEditModified = errors.New("document modified since workspace edit")
+
+	// ErrCannotRename indicates there is no renameable symbol at a position.
+	ErrCannotRename = errors.New("no renameable symbol at position")
+
 	// ErrSupervisorFailed indicates the supervisor has given up on restarting.
 	ErrSupervisorFailed = errors.New("supervisor failed after max restarts")
 )
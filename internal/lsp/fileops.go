@@ -0,0 +1,263 @@
+package lsp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// WillRenameFiles asks the server for the edits to apply before files are
+// renamed, such as updated import paths. Only renames matching the server's
+// willRename filters are sent; if none match, no request is made and the
+// result is nil. It returns ErrNotSupported if the server did not register
+// for willRename.
+func (s *Server) WillRenameFiles(ctx context.Context, renames []FileRename) (*WorkspaceEdit, error) {
+	if s.Status() != ServerStatusReady {
+		return nil, ErrServerNotReady
+	}
+
+	ops := s.capabilities.fileOperations()
+	if ops == nil || ops.WillRename == nil {
+		return nil, ErrNotSupported
+	}
+
+	// The files have not moved yet, so their kind is read from the old path
+	files := filterFileRenames(ops.WillRename.Filters, renames, false)
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	var result *WorkspaceEdit
+	if err := s.transport.Call(ctx, "workspace/willRenameFiles", RenameFilesParams{Files: files}, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DidRenameFiles notifies the server that files were renamed. Only renames
+// matching the server's didRename filters are sent. It returns
+// ErrNotSupported if the server did not register for didRename.
+func (s *Server) DidRenameFiles(ctx context.Context, renames []FileRename) error {
+	if s.Status() != ServerStatusReady {
+		return ErrServerNotReady
+	}
+
+	ops := s.capabilities.fileOperations()
+	if ops == nil || ops.DidRename == nil {
+		return ErrNotSupported
+	}
+
+	files := filterFileRenames(ops.DidRename.Filters, renames, true)
+	if len(files) == 0 {
+		return nil
+	}
+
+	return s.transport.Notify(ctx, "workspace/didRenameFiles", RenameFilesParams{Files: files})
+}
+
+// fileOperations returns the server's file operation registrations, or nil.
+func (c ServerCapabilities) fileOperations() *FileOperationsServerCapabilities {
+	if c.Workspace == nil {
+		return nil
+	}
+	return c.Workspace.FileOperations
+}
+
+// WillRenameFiles asks every running server for the edits to apply before
+// files are renamed and merges them into one workspace edit. Servers that
+// did not register for willRename are skipped. Errors from individual
+// servers are joined; the edits of the other servers are still returned.
+func (m *Manager) WillRenameFiles(ctx context.Context, renames []FileRename) (WorkspaceEdit, error) {
+	var merged WorkspaceEdit
+	var errs []error
+	for _, server := range m.readyServers() {
+		edit, err := server.WillRenameFiles(ctx, renames)
+		if errors.Is(err, ErrNotSupported) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, &ServerError{LanguageID: server.LanguageID(), Err: err})
+			continue
+		}
+		mergeWorkspaceEdit(&merged, edit)
+	}
+	return merged, errors.Join(errs...)
+}
+
+// DidRenameFiles notifies every running server that registered for
+// didRename that files were renamed.
+func (m *Manager) DidRenameFiles(ctx context.Context, renames []FileRename) error {
+	var errs []error
+	for _, server := range m.readyServers() {
+		err := server.DidRenameFiles(ctx, renames)
+		if err != nil && !errors.Is(err, ErrNotSupported) {
+			errs = append(errs, &ServerError{LanguageID: server.LanguageID(), Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// readyServers returns the running servers that are ready for requests,
+// supervised or not. Servers are not started.
+func (m *Manager) readyServers() []*Server {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	servers := make([]*Server, 0, len(m.servers)+len(m.supervisors))
+	for _, server := range m.servers {
+		if server.Status() == ServerStatusReady {
+			servers = append(servers, server)
+		}
+	}
+	for _, supervisor := range m.supervisors {
+		if supervisor.IsReady() {
+			servers = append(servers, supervisor.Server())
+		}
+	}
+	return servers
+}
+
+// mergeWorkspaceEdit appends the changes of src to dst. Changes are ignored
+// when DocumentChanges is set, so once either edit uses DocumentChanges the
+// Changes of the other are converted into text document edits there. As
+// when applying it alone, the Changes of an edit with DocumentChanges are
+// dropped.
+func mergeWorkspaceEdit(dst, src *WorkspaceEdit) {
+	if src == nil {
+		return
+	}
+
+	if len(dst.DocumentChanges) == 0 && len(src.DocumentChanges) == 0 {
+		for uri, edits := range src.Changes {
+			if dst.Changes == nil {
+				dst.Changes = make(map[DocumentURI][]TextEdit)
+			}
+			dst.Changes[uri] = append(dst.Changes[uri], edits...)
+		}
+		return
+	}
+
+	if len(dst.Changes) > 0 {
+		dst.DocumentChanges = append(dst.DocumentChanges, changesToDocumentEdits(dst.Changes)...)
+		dst.Changes = nil
+	}
+	if len(src.DocumentChanges) > 0 {
+		dst.DocumentChanges = append(dst.DocumentChanges, src.DocumentChanges...)
+	} else {
+		dst.DocumentChanges = append(dst.DocumentChanges, changesToDocumentEdits(src.Changes)...)
+	}
+}
+
+// filterFileRenames returns the renames whose old URI matches one of
+// filters. Whether a rename is of a file or a folder is read from the new
+// path if renamed is true, and from the old path otherwise.
+func filterFileRenames(filters []FileOperationFilter, renames []FileRename, renamed bool) []FileRename {
+	var files []FileRename
+	for _, rename := range renames {
+		kindURI := rename.OldURI
+		if renamed {
+			kindURI = rename.NewURI
+		}
+		if fileOperationMatches(filters, rename.OldURI, fileOperationKind(kindURI)) {
+			files = append(files, rename)
+		}
+	}
+	return files
+}
+
+// fileOperationKind returns FileOperationPatternFile or
+// FileOperationPatternFolder for the file at uri, or "" if it cannot be
+// determined.
+func fileOperationKind(uri DocumentURI) string {
+	info, err := os.Stat(URIToFilePath(uri))
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return FileOperationPatternFolder
+	}
+	return FileOperationPatternFile
+}
+
+// fileOperationMatches returns true if uri matches one of filters. kind is
+// the kind of the file at uri, or "" to match patterns of either kind.
+func fileOperationMatches(filters []FileOperationFilter, uri DocumentURI, kind string) bool {
+	scheme, _, _ := strings.Cut(string(uri), ":")
+	path := filepath.ToSlash(URIToFilePath(uri))
+
+	for _, filter := range filters {
+		if filter.Scheme != "" && filter.Scheme != scheme {
+			continue
+		}
+		pattern := filter.Pattern
+		if pattern.Matches != "" && kind != "" && pattern.Matches != kind {
+			continue
+		}
+		ignoreCase := pattern.Options != nil && pattern.Options.IgnoreCase
+		re, err := compileFileOperationGlob(pattern.Glob, ignoreCase)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileFileOperationGlob compiles an LSP glob pattern to a regular
+// expression matching whole paths. The pattern syntax is '*' for any run of
+// characters within a path segment, '**' for any number of segments, '?'
+// for one character, '{a,b}' for alternatives and '[a-z]' or '[!a-z]' for
+// character ranges.
+func compileFileOperationGlob(glob string, ignoreCase bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if ignoreCase {
+		b.WriteString("(?i)")
+	}
+	b.WriteByte('^')
+
+	braces := 0
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case c == '*' && strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '{':
+			braces++
+			b.WriteString("(?:")
+		case c == '}' && braces > 0:
+			braces--
+			b.WriteByte(')')
+		case c == ',' && braces > 0:
+			b.WriteByte('|')
+		case c == '[' && strings.IndexByte(glob[i+1:], ']') > 0:
+			end := i + 1 + strings.IndexByte(glob[i+1:], ']')
+			class := glob[i+1 : end]
+			if class[0] == '!' {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+
+	b.WriteByte('$')
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,217 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fileOpsTestServer is a server whose peer records file operation
+// requests and answers willRenameFiles with a fixed edit.
+type fileOpsTestServer struct {
+	*Server
+
+	mu      sync.Mutex
+	methods []string
+	params  []RenameFilesParams
+}
+
+// recorded returns the methods and parameters received so far.
+func (s *fileOpsTestServer) recorded() ([]string, []RenameFilesParams) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.methods...), append([]RenameFilesParams(nil), s.params...)
+}
+
+// newFileOpsTestServer creates a ready server advertising ops as its file
+// operation capabilities. workspace/willRenameFiles is answered with edit.
+func newFileOpsTestServer(t *testing.T, ops *FileOperationsServerCapabilities, edit *WorkspaceEdit) *fileOpsTestServer {
+	t.Helper()
+
+	s := &fileOpsTestServer{}
+	caps := ServerCapabilities{Workspace: &ServerWorkspaceCapabilities{FileOperations: ops}}
+	s.Server, _ = newFakeLSPServer(t, caps, func(method string, params json.RawMessage) any {
+		var p RenameFilesParams
+		json.Unmarshal(params, &p)
+
+		s.mu.Lock()
+		s.methods = append(s.methods, method)
+		s.params = append(s.params, p)
+		s.mu.Unlock()
+
+		if method != "workspace/willRenameFiles" {
+			return nil
+		}
+		return edit
+	})
+
+	return s
+}
+
+func goFileOperations() *FileOperationsServerCapabilities {
+	filters := []FileOperationFilter{{
+		Scheme:  "file",
+		Pattern: FileOperationPattern{Glob: "**/*.go", Matches: FileOperationPatternFile},
+	}}
+	return &FileOperationsServerCapabilities{
+		WillRename: &FileOperationRegistrationOptions{Filters: filters},
+		DidRename:  &FileOperationRegistrationOptions{Filters: filters},
+	}
+}
+
+func TestServerWillRenameFiles(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "util.go")
+	if err := os.WriteFile(oldPath, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mainURI := FilePathToURI(filepath.Join(dir, "main.go"))
+	edit := &WorkspaceEdit{Changes: map[DocumentURI][]TextEdit{
+		mainURI: {{NewText: `"example/helpers"`}},
+	}}
+	s := newFileOpsTestServer(t, goFileOperations(), edit)
+
+	rename := FileRename{OldURI: FilePathToURI(oldPath), NewURI: FilePathToURI(filepath.Join(dir, "helpers.go"))}
+	got, err := s.WillRenameFiles(context.Background(), []FileRename{rename})
+	if err != nil {
+		t.Fatalf("WillRenameFiles() error = %v", err)
+	}
+	if got == nil || len(got.Changes[mainURI]) != 1 {
+		t.Fatalf("WillRenameFiles() = %+v, want edit to main.go", got)
+	}
+
+	methods, params := s.recorded()
+	if len(methods) != 1 || methods[0] != "workspace/willRenameFiles" {
+		t.Fatalf("methods = %v, want [workspace/willRenameFiles]", methods)
+	}
+	if len(params[0].Files) != 1 || params[0].Files[0] != rename {
+		t.Errorf("files = %+v, want [%+v]", params[0].Files, rename)
+	}
+}
+
+func TestServerWillRenameFilesSkipsUnmatched(t *testing.T) {
+	dir := t.TempDir()
+	s := newFileOpsTestServer(t, goFileOperations(), &WorkspaceEdit{})
+
+	rename := FileRename{
+		OldURI: FilePathToURI(filepath.Join(dir, "README.md")),
+		NewURI: FilePathToURI(filepath.Join(dir, "DOCS.md")),
+	}
+	got, err := s.WillRenameFiles(context.Background(), []FileRename{rename})
+	if err != nil {
+		t.Fatalf("WillRenameFiles() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("WillRenameFiles() = %+v, want nil", got)
+	}
+	if err := s.DidRenameFiles(context.Background(), []FileRename{rename}); err != nil {
+		t.Fatalf("DidRenameFiles() error = %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if methods, _ := s.recorded(); len(methods) != 0 {
+		t.Errorf("methods = %v, want no requests for unmatched rename", methods)
+	}
+}
+
+func TestServerRenameFilesNotSupported(t *testing.T) {
+	s := newFileOpsTestServer(t, nil, nil)
+	rename := FileRename{OldURI: "file:///tmp/a.go", NewURI: "file:///tmp/b.go"}
+
+	if _, err := s.WillRenameFiles(context.Background(), []FileRename{rename}); err != ErrNotSupported {
+		t.Errorf("WillRenameFiles() error = %v, want ErrNotSupported", err)
+	}
+	if err := s.DidRenameFiles(context.Background(), []FileRename{rename}); err != ErrNotSupported {
+		t.Errorf("DidRenameFiles() error = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestServerDidRenameFiles(t *testing.T) {
+	dir := t.TempDir()
+	newPath := filepath.Join(dir, "helpers.go")
+	if err := os.WriteFile(newPath, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := newFileOpsTestServer(t, goFileOperations(), nil)
+
+	rename := FileRename{OldURI: FilePathToURI(filepath.Join(dir, "util.go")), NewURI: FilePathToURI(newPath)}
+	if err := s.DidRenameFiles(context.Background(), []FileRename{rename}); err != nil {
+		t.Fatalf("DidRenameFiles() error = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if methods, _ := s.recorded(); len(methods) == 1 {
+			if methods[0] != "workspace/didRenameFiles" {
+				t.Errorf("method = %q, want workspace/didRenameFiles", methods[0])
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("didRenameFiles notification not received")
+}
+
+func TestFileOperationMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter FileOperationFilter
+		uri    DocumentURI
+		kind   string
+		want   bool
+	}{
+		{"doublestar", FileOperationFilter{Pattern: FileOperationPattern{Glob: "**/*.go"}}, "file:///src/a/b.go", "", true},
+		{"extension mismatch", FileOperationFilter{Pattern: FileOperationPattern{Glob: "**/*.go"}}, "file:///src/a/b.ts", "", false},
+		{"alternatives", FileOperationFilter{Pattern: FileOperationPattern{Glob: "**/*.{ts,tsx}"}}, "file:///src/app.tsx", "", true},
+		{"star stays in segment", FileOperationFilter{Pattern: FileOperationPattern{Glob: "/src/*.go"}}, "file:///src/a/b.go", "", false},
+		{"range", FileOperationFilter{Pattern: FileOperationPattern{Glob: "**/v[0-9].go"}}, "file:///src/v2.go", "", true},
+		{"ignore case", FileOperationFilter{Pattern: FileOperationPattern{Glob: "**/*.GO", Options: &FileOperationPatternOptions{IgnoreCase: true}}}, "file:///src/b.go", "", true},
+		{"scheme mismatch", FileOperationFilter{Scheme: "untitled", Pattern: FileOperationPattern{Glob: "**"}}, "file:///src/b.go", "", false},
+		{"folder only", FileOperationFilter{Pattern: FileOperationPattern{Glob: "**", Matches: FileOperationPatternFolder}}, "file:///src/b.go", FileOperationPatternFile, false},
+		{"unknown kind", FileOperationFilter{Pattern: FileOperationPattern{Glob: "**", Matches: FileOperationPatternFolder}}, "file:///src/b.go", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fileOperationMatches([]FileOperationFilter{tt.filter}, tt.uri, tt.kind); got != tt.want {
+				t.Errorf("fileOperationMatches(%q, %q) = %v, want %v", tt.filter.Pattern.Glob, tt.uri, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeWorkspaceEditMixed(t *testing.T) {
+	resolver := newMemResolver(map[string]string{
+		"/ws/a.go": "package a\n",
+		"/ws/b.go": "package b\n",
+	})
+
+	// One server answers with changes, the other with document changes
+	var merged WorkspaceEdit
+	mergeWorkspaceEdit(&merged, &WorkspaceEdit{Changes: map[DocumentURI][]TextEdit{
+		FilePathToURI("/ws/a.go"): {renameEdit(0, 8, 9, "x")},
+	}})
+	mergeWorkspaceEdit(&merged, &WorkspaceEdit{DocumentChanges: []any{TextDocumentEdit{
+		TextDocument: OptionalVersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: TextDocumentIdentifier{URI: FilePathToURI("/ws/b.go")},
+		},
+		Edits: []TextEdit{renameEdit(0, 8, 9, "y")},
+	}}})
+
+	if len(merged.Changes) != 0 || len(merged.DocumentChanges) != 2 {
+		t.Fatalf("merged = %+v, want both edits in DocumentChanges", merged)
+	}
+	if err := NewClient().ApplyWorkspaceEdit(context.Background(), merged, resolver); err != nil {
+		t.Fatalf("ApplyWorkspaceEdit() error = %v", err)
+	}
+	if got := resolver.text(t, "/ws/a.go"); got != "package x\n" {
+		t.Errorf("a.go = %q, want %q", got, "package x\n")
+	}
+	if got := resolver.text(t, "/ws/b.go"); got != "package y\n" {
+		t.Errorf("b.go = %q, want %q", got, "package y\n")
+	}
+}
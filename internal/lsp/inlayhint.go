@@ -0,0 +1,158 @@
+package lsp
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultInlayHintDelay is the default time an InlayHintRefresher waits for
+// scrolling to settle before requesting hints.
+const DefaultInlayHintDelay = 150 * time.Millisecond
+
+// InlayHintOffset is an inlay hint resolved to a byte offset, ready to be
+// shown by the renderer as virtual text before the character at Offset.
+type InlayHintOffset struct {
+	// Line is the zero-based line of the hint.
+	Line int
+
+	// Offset is the byte offset of the hint.
+	Offset int
+
+	// Text is the text to display, including padding.
+	Text string
+
+	// Hint is the inlay hint.
+	Hint InlayHint
+}
+
+// InlayHintOffsets converts inlay hints, whose positions are in UTF-16 code
+// units, to byte offsets in content. The result is ordered by position so
+// hints at the same offset keep their order. Returns nil for no hints.
+func InlayHintOffsets(content string, hints []InlayHint) []InlayHintOffset {
+	if len(hints) == 0 {
+		return nil
+	}
+
+	pc := NewPositionConverter(content)
+	result := make([]InlayHintOffset, 0, len(hints))
+	for _, hint := range hints {
+		result = append(result, InlayHintOffset{
+			Line:   hint.Position.Line,
+			Offset: pc.PositionToByteOffset(hint.Position),
+			Text:   hint.Text(),
+			Hint:   hint,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Offset < result[j].Offset
+	})
+	return result
+}
+
+// InlayHintFetcher requests the inlay hints of a document within a range,
+// such as Client.InlayHints.
+type InlayHintFetcher func(ctx context.Context, path string, rng Range) ([]InlayHint, error)
+
+// InlayHintRefresher requests inlay hints for the visible range of documents
+// as they scroll. Requests are debounced per document, so a burst of scroll
+// events results in a single request for the final range, and results for a
+// range that is no longer visible are discarded.
+type InlayHintRefresher struct {
+	mu      sync.Mutex
+	fetch   InlayHintFetcher
+	deliver func(path string, rng Range, hints []InlayHint)
+	delay   time.Duration
+	timers  map[string]*time.Timer
+	seq     map[string]uint64
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// NewInlayHintRefresher creates a refresher that requests hints with fetch
+// delay after the visible range last changed and passes them to deliver.
+// Failed requests, including ErrNotSupported, deliver nothing. A delay of
+// zero or less uses DefaultInlayHintDelay.
+func NewInlayHintRefresher(fetch InlayHintFetcher, delay time.Duration, deliver func(path string, rng Range, hints []InlayHint)) *InlayHintRefresher {
+	if delay <= 0 {
+		delay = DefaultInlayHintDelay
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &InlayHintRefresher{
+		fetch:   fetch,
+		deliver: deliver,
+		delay:   delay,
+		timers:  make(map[string]*time.Timer),
+		seq:     make(map[string]uint64),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Update records that rng of path is now visible, restarting the delay for
+// that document.
+func (r *InlayHintRefresher) Update(path string, rng Range) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ctx.Err() != nil {
+		return
+	}
+
+	r.seq[path]++
+	seq := r.seq[path]
+	if timer := r.timers[path]; timer != nil {
+		timer.Stop()
+	}
+	r.timers[path] = time.AfterFunc(r.delay, func() {
+		r.refresh(path, rng, seq)
+	})
+}
+
+// Forget cancels any pending request for path, such as when it is closed.
+func (r *InlayHintRefresher) Forget(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if timer := r.timers[path]; timer != nil {
+		timer.Stop()
+	}
+	delete(r.timers, path)
+	delete(r.seq, path)
+}
+
+// Close cancels pending and in-flight requests. The refresher cannot be
+// used after Close.
+func (r *InlayHintRefresher) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cancel()
+	for _, timer := range r.timers {
+		timer.Stop()
+	}
+	r.timers = make(map[string]*time.Timer)
+}
+
+// refresh requests and delivers hints for rng if it is still current.
+func (r *InlayHintRefresher) refresh(path string, rng Range, seq uint64) {
+	if !r.current(path, seq) {
+		return
+	}
+
+	hints, err := r.fetch(r.ctx, path, rng)
+	if err != nil || !r.current(path, seq) {
+		return
+	}
+	r.deliver(path, rng, hints)
+}
+
+// current returns true if seq is the latest update for path and the
+// refresher is open.
+func (r *InlayHintRefresher) current(path string, seq uint64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ctx.Err() == nil && r.seq[path] == seq
+}
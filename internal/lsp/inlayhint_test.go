@@ -0,0 +1,241 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newInlayHintTestServer creates a ready server advertising provider as its
+// inlay hint capability. Requests are answered with respond applied to the
+// method and params. The returned function reports the requests so far.
+func newInlayHintTestServer(t *testing.T, provider any, respond func(method string, params json.RawMessage) any) (*Server, func() []string) {
+	t.Helper()
+
+	return newFakeLSPServer(t, ServerCapabilities{InlayHintProvider: provider}, respond)
+}
+
+func TestInlayHintDecode(t *testing.T) {
+	data := `[
+		{"position": {"line": 0, "character": 5}, "label": ": int", "kind": 1, "paddingLeft": true},
+		{
+			"position": {"line": 1, "character": 4},
+			"label": [
+				{"value": "name"},
+				{"value": ":", "tooltip": "parameter", "command": {"title": "go", "command": "goto.param"}}
+			],
+			"kind": 2,
+			"paddingRight": true,
+			"tooltip": {"kind": "markdown", "value": "**name**"}
+		}
+	]`
+
+	var hints []InlayHint
+	if err := json.Unmarshal([]byte(data), &hints); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(hints) != 2 {
+		t.Fatalf("decoded %d hints, want 2", len(hints))
+	}
+
+	typeHint := hints[0]
+	if len(typeHint.Label) != 1 || typeHint.Label.String() != ": int" {
+		t.Errorf("string label = %+v, want one part %q", typeHint.Label, ": int")
+	}
+	if typeHint.Kind != InlayHintKindType {
+		t.Errorf("Kind = %v, want %v", typeHint.Kind, InlayHintKindType)
+	}
+	if got := typeHint.Text(); got != " : int" {
+		t.Errorf("Text() = %q, want %q", got, " : int")
+	}
+
+	paramHint := hints[1]
+	if len(paramHint.Label) != 2 || paramHint.Label.String() != "name:" {
+		t.Errorf("part label = %+v, want parts joining to %q", paramHint.Label, "name:")
+	}
+	part := paramHint.Label[1]
+	if part.Command == nil || part.Command.Command != "goto.param" {
+		t.Errorf("part command = %+v, want goto.param", part.Command)
+	}
+	if part.Tooltip == nil || part.Tooltip.Kind != MarkupKindPlainText || part.Tooltip.Value != "parameter" {
+		t.Errorf("part tooltip = %+v, want plaintext %q", part.Tooltip, "parameter")
+	}
+	if paramHint.Tooltip == nil || paramHint.Tooltip.Kind != MarkupKindMarkdown {
+		t.Errorf("hint tooltip = %+v, want markdown", paramHint.Tooltip)
+	}
+	if got := paramHint.Text(); got != "name: " {
+		t.Errorf("Text() = %q, want %q", got, "name: ")
+	}
+
+	// Hints round-trip for inlayHint/resolve
+	encoded, err := json.Marshal(paramHint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded InlayHint
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal(Marshal()) error = %v", err)
+	}
+	if decoded.Label.String() != "name:" || decoded.Tooltip == nil || decoded.Tooltip.Value != "**name**" {
+		t.Errorf("round trip = %+v, want the original hint", decoded)
+	}
+}
+
+func TestInlayHintOffsets(t *testing.T) {
+	// "😀" is 4 bytes and 2 UTF-16 code units, "é" is 2 bytes and 1 unit
+	content := "x := f(\"😀\", é)\ny := 1\n"
+	hints := []InlayHint{
+		{Position: Position{Line: 1, Character: 1}, Label: InlayHintLabel{{Value: ": int"}}},
+		{Position: Position{Line: 0, Character: 13}, Label: InlayHintLabel{{Value: "b:"}}, PaddingRight: true},
+		{Position: Position{Line: 0, Character: 7}, Label: InlayHintLabel{{Value: "a:"}}, PaddingRight: true},
+	}
+
+	got := InlayHintOffsets(content, hints)
+	want := []struct {
+		line   int
+		offset int
+		text   string
+	}{
+		{0, strings.Index(content, "\"😀"), "a: "},
+		{0, strings.Index(content, "é"), "b: "},
+		{1, strings.Index(content, "y") + 1, ": int"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("InlayHintOffsets() returned %d hints, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Line != w.line || got[i].Offset != w.offset || got[i].Text != w.text {
+			t.Errorf("hint %d = {%d %d %q}, want {%d %d %q}", i, got[i].Line, got[i].Offset, got[i].Text, w.line, w.offset, w.text)
+		}
+	}
+
+	if InlayHintOffsets(content, nil) != nil {
+		t.Error("InlayHintOffsets(nil) should be nil")
+	}
+}
+
+func TestServerInlayHints(t *testing.T) {
+	hint := InlayHint{
+		Position: Position{Line: 0, Character: 5},
+		Label:    InlayHintLabel{{Value: ": string"}},
+		Kind:     InlayHintKindType,
+		Data:     "h1",
+	}
+	var gotRange Range
+	respond := func(method string, params json.RawMessage) any {
+		if method == "inlayHint/resolve" {
+			var h InlayHint
+			json.Unmarshal(params, &h)
+			h.Tooltip = &InlayHintTooltip{Kind: MarkupKindPlainText, Value: "inferred"}
+			return h
+		}
+		var p InlayHintParams
+		json.Unmarshal(params, &p)
+		gotRange = p.Range
+		return []InlayHint{hint}
+	}
+	s, methods := newInlayHintTestServer(t, map[string]any{"resolveProvider": true}, respond)
+
+	visible := Range{Start: Position{Line: 10}, End: Position{Line: 40}}
+	hints, err := s.InlayHints(context.Background(), "/tmp/main.go", visible)
+	if err != nil {
+		t.Fatalf("InlayHints() error = %v", err)
+	}
+	if len(hints) != 1 || hints[0].Label.String() != ": string" {
+		t.Fatalf("InlayHints() = %+v, want the server's hint", hints)
+	}
+	if gotRange != visible {
+		t.Errorf("requested range = %+v, want %+v", gotRange, visible)
+	}
+
+	resolved, err := s.ResolveInlayHint(context.Background(), hints[0])
+	if err != nil {
+		t.Fatalf("ResolveInlayHint() error = %v", err)
+	}
+	if resolved.Tooltip == nil || resolved.Tooltip.Value != "inferred" {
+		t.Errorf("resolved tooltip = %+v, want inferred", resolved.Tooltip)
+	}
+
+	want := []string{"textDocument/inlayHint", "inlayHint/resolve"}
+	if got := methods(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("methods = %v, want %v", got, want)
+	}
+}
+
+func TestServerInlayHintsCapability(t *testing.T) {
+	s, methods := newInlayHintTestServer(t, nil, func(string, json.RawMessage) any { return nil })
+
+	if _, err := s.InlayHints(context.Background(), "/tmp/main.go", Range{}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("InlayHints() error = %v, want ErrNotSupported", err)
+	}
+
+	// Without resolve support hints are returned as-is
+	s.capabilities.InlayHintProvider = true
+	hint := InlayHint{Label: InlayHintLabel{{Value: "x"}}}
+	resolved, err := s.ResolveInlayHint(context.Background(), hint)
+	if err != nil || resolved.Label.String() != "x" {
+		t.Errorf("ResolveInlayHint() = %+v, %v, want the hint unchanged", resolved, err)
+	}
+	if got := methods(); len(got) != 0 {
+		t.Errorf("methods = %v, want none", got)
+	}
+}
+
+func TestInlayHintRefresher(t *testing.T) {
+	var mu sync.Mutex
+	var fetched []Range
+	fetch := func(ctx context.Context, path string, rng Range) ([]InlayHint, error) {
+		mu.Lock()
+		fetched = append(fetched, rng)
+		mu.Unlock()
+		return []InlayHint{{Position: rng.Start, Label: InlayHintLabel{{Value: path}}}}, nil
+	}
+
+	delivered := make(chan Range, 10)
+	r := NewInlayHintRefresher(fetch, 20*time.Millisecond, func(path string, rng Range, hints []InlayHint) {
+		if len(hints) != 1 || hints[0].Label.String() != path {
+			t.Errorf("delivered hints = %+v, want one for %s", hints, path)
+		}
+		delivered <- rng
+	})
+	defer r.Close()
+
+	// A burst of scrolling requests only the final range
+	for line := 0; line < 5; line++ {
+		r.Update("/tmp/main.go", Range{Start: Position{Line: line}, End: Position{Line: line + 30}})
+	}
+
+	select {
+	case rng := <-delivered:
+		if rng.Start.Line != 4 {
+			t.Errorf("delivered range starts at line %d, want 4", rng.Start.Line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no hints delivered")
+	}
+	select {
+	case rng := <-delivered:
+		t.Errorf("unexpected second delivery for %+v", rng)
+	case <-time.After(60 * time.Millisecond):
+	}
+
+	mu.Lock()
+	count := len(fetched)
+	mu.Unlock()
+	if count != 1 {
+		t.Errorf("fetched %d times, want 1", count)
+	}
+
+	// Forgotten documents are not requested
+	r.Update("/tmp/other.go", Range{})
+	r.Forget("/tmp/other.go")
+	select {
+	case rng := <-delivered:
+		t.Errorf("unexpected delivery after Forget for %+v", rng)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
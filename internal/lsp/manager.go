@@ -7,9 +7,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
 	"sync"
 	"time"
+
+	"github.com/dshills/keystorm/internal/langid"
 )
 
 // Manager coordinates multiple language servers.
@@ -225,7 +226,7 @@ func (m *Manager) forwardSupervisorEvents(supervisor *Supervisor) {
 
 // ServerForFile returns the server for a file, starting it if needed.
 func (m *Manager) ServerForFile(ctx context.Context, path string) (*Server, error) {
-	languageID := DetectLanguageID(path)
+	languageID := langid.FromPath(path)
 	if languageID == "" {
 		return nil, ErrNoServer
 	}
@@ -239,7 +240,7 @@ func (m *Manager) ServerForLanguage(ctx context.Context, languageID string) (*Se
 
 // OpenDocument opens a document with the appropriate server.
 func (m *Manager) OpenDocument(ctx context.Context, path, content string) error {
-	languageID := DetectLanguageID(path)
+	languageID := langid.FromPath(path)
 	if languageID == "" {
 		return nil // No server for this file type
 	}
@@ -254,7 +255,7 @@ func (m *Manager) OpenDocument(ctx context.Context, path, content string) error
 
 // CloseDocument closes a document.
 func (m *Manager) CloseDocument(ctx context.Context, path string) error {
-	languageID := DetectLanguageID(path)
+	languageID := langid.FromPath(path)
 	if languageID == "" {
 		return nil
 	}
@@ -272,7 +273,7 @@ func (m *Manager) CloseDocument(ctx context.Context, path string) error {
 
 // ChangeDocument notifies the server of document changes.
 func (m *Manager) ChangeDocument(ctx context.Context, path string, changes []TextDocumentContentChangeEvent) error {
-	languageID := DetectLanguageID(path)
+	languageID := langid.FromPath(path)
 	if languageID == "" {
 		return nil
 	}
@@ -333,6 +334,15 @@ func (m *Manager) References(ctx context.Context, path string, pos Position, inc
 	return server.References(ctx, path, pos, includeDecl)
 }
 
+// DocumentHighlight requests the occurrences of the symbol at a position.
+func (m *Manager) DocumentHighlight(ctx context.Context, path string, pos Position) ([]DocumentHighlight, error) {
+	server, err := m.ServerForFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return server.DocumentHighlight(ctx, path, pos)
+}
+
 // DocumentSymbols requests document symbols.
 func (m *Manager) DocumentSymbols(ctx context.Context, path string) ([]DocumentSymbol, error) {
 	server, err := m.ServerForFile(ctx, path)
@@ -342,6 +352,42 @@ func (m *Manager) DocumentSymbols(ctx context.Context, path string) ([]DocumentS
 	return server.DocumentSymbols(ctx, path)
 }
 
+// CodeLens requests the code lenses of a document.
+func (m *Manager) CodeLens(ctx context.Context, path string) ([]CodeLens, error) {
+	server, err := m.ServerForFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return server.CodeLens(ctx, path)
+}
+
+// ResolveCodeLens resolves the command of a code lens from a document.
+func (m *Manager) ResolveCodeLens(ctx context.Context, path string, lens CodeLens) (*CodeLens, error) {
+	server, err := m.ServerForFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return server.ResolveCodeLens(ctx, lens)
+}
+
+// InlayHints requests the inlay hints of a document within a range.
+func (m *Manager) InlayHints(ctx context.Context, path string, rng Range) ([]InlayHint, error) {
+	server, err := m.ServerForFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return server.InlayHints(ctx, path, rng)
+}
+
+// ResolveInlayHint resolves the details of an inlay hint from a document.
+func (m *Manager) ResolveInlayHint(ctx context.Context, path string, hint InlayHint) (*InlayHint, error) {
+	server, err := m.ServerForFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return server.ResolveInlayHint(ctx, hint)
+}
+
 // Format requests document formatting.
 func (m *Manager) Format(ctx context.Context, path string, opts FormattingOptions) ([]TextEdit, error) {
 	server, err := m.ServerForFile(ctx, path)
@@ -398,7 +444,7 @@ func (m *Manager) Diagnostics(ctx context.Context, path string) ([]Diagnostic, e
 
 // IsAvailable checks if LSP is available for a file.
 func (m *Manager) IsAvailable(path string) bool {
-	languageID := DetectLanguageID(path)
+	languageID := langid.FromPath(path)
 	if languageID == "" {
 		return false
 	}
@@ -623,82 +669,6 @@ func fileExists(path string) bool {
 	return err == nil
 }
 
-// LanguageIDForExtension returns the language ID for a file extension.
-func LanguageIDForExtension(ext string) string {
-	ext = strings.ToLower(strings.TrimPrefix(ext, "."))
-
-	// Map of extension to language ID
-	extMap := map[string]string{
-		"go":     "go",
-		"rs":     "rust",
-		"ts":     "typescript",
-		"tsx":    "typescriptreact",
-		"js":     "javascript",
-		"jsx":    "javascriptreact",
-		"py":     "python",
-		"c":      "c",
-		"h":      "c",
-		"cpp":    "cpp",
-		"cc":     "cpp",
-		"cxx":    "cpp",
-		"hpp":    "cpp",
-		"hxx":    "cpp",
-		"java":   "java",
-		"rb":     "ruby",
-		"php":    "php",
-		"swift":  "swift",
-		"kt":     "kotlin",
-		"kts":    "kotlin",
-		"scala":  "scala",
-		"lua":    "lua",
-		"sh":     "shellscript",
-		"bash":   "shellscript",
-		"zsh":    "shellscript",
-		"json":   "json",
-		"yaml":   "yaml",
-		"yml":    "yaml",
-		"xml":    "xml",
-		"html":   "html",
-		"htm":    "html",
-		"css":    "css",
-		"scss":   "scss",
-		"sass":   "sass",
-		"less":   "less",
-		"md":     "markdown",
-		"sql":    "sql",
-		"r":      "r",
-		"cs":     "csharp",
-		"fs":     "fsharp",
-		"vb":     "vb",
-		"dart":   "dart",
-		"ex":     "elixir",
-		"exs":    "elixir",
-		"erl":    "erlang",
-		"hrl":    "erlang",
-		"hs":     "haskell",
-		"lhs":    "haskell",
-		"ml":     "ocaml",
-		"mli":    "ocaml",
-		"clj":    "clojure",
-		"cljs":   "clojurescript",
-		"vim":    "vim",
-		"proto":  "protobuf",
-		"tf":     "terraform",
-		"tfvars": "terraform",
-		"vue":    "vue",
-		"svelte": "svelte",
-		"zig":    "zig",
-		"nim":    "nim",
-		"cr":     "crystal",
-		"jl":     "julia",
-	}
-
-	if langID, ok := extMap[ext]; ok {
-		return langID
-	}
-	return ""
-}
-
 // RestartServer restarts a language server.
 func (m *Manager) RestartServer(ctx context.Context, languageID string) error {
 	m.mu.Lock()
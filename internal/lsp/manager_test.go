@@ -140,55 +140,6 @@ func TestDefaultServerConfigs(t *testing.T) {
 	}
 }
 
-func TestDetectLanguageID(t *testing.T) {
-	tests := []struct {
-		path     string
-		expected string
-	}{
-		{"/path/to/file.go", "go"},
-		{"/path/to/file.rs", "rust"},
-		{"/path/to/file.ts", "typescript"},
-		{"/path/to/file.tsx", "typescriptreact"},
-		{"/path/to/file.js", "javascript"},
-		{"/path/to/file.jsx", "javascriptreact"},
-		{"/path/to/file.py", "python"},
-		{"/path/to/file.c", "c"},
-		{"/path/to/file.cpp", "cpp"},
-		{"/path/to/file.unknown", "plaintext"},
-	}
-
-	for _, tt := range tests {
-		result := DetectLanguageID(tt.path)
-		if result != tt.expected {
-			t.Errorf("DetectLanguageID(%s) = %s, expected %s", tt.path, result, tt.expected)
-		}
-	}
-}
-
-func TestLanguageIDForExtension(t *testing.T) {
-	tests := []struct {
-		ext      string
-		expected string
-	}{
-		{"go", "go"},
-		{".go", "go"},
-		{"rs", "rust"},
-		{"ts", "typescript"},
-		{"tsx", "typescriptreact"},
-		{"py", "python"},
-		{"java", "java"},
-		{"rb", "ruby"},
-		{"unknown", ""},
-	}
-
-	for _, tt := range tests {
-		result := LanguageIDForExtension(tt.ext)
-		if result != tt.expected {
-			t.Errorf("LanguageIDForExtension(%s) = %s, expected %s", tt.ext, result, tt.expected)
-		}
-	}
-}
-
 func TestWorkspaceFolderFromPath(t *testing.T) {
 	folder := WorkspaceFolderFromPath("/test/project")
 
@@ -1064,3 +1064,28 @@ func FindParentSymbol(symbols []DocumentSymbol, pos Position) *DocumentSymbol {
 	}
 	return nil
 }
+
+// HighlightRange is a document highlight resolved to byte offsets, ready to
+// be turned into renderer highlights.
+type HighlightRange struct {
+	Start int
+	End   int
+	Kind  DocumentHighlightKind
+}
+
+// HighlightRanges converts document highlights, whose positions are in
+// UTF-16 code units, to byte offsets in content. Returns nil for no
+// highlights.
+func HighlightRanges(content string, highlights []DocumentHighlight) []HighlightRange {
+	if len(highlights) == 0 {
+		return nil
+	}
+
+	pc := NewPositionConverter(content)
+	result := make([]HighlightRange, 0, len(highlights))
+	for _, h := range highlights {
+		start, end := pc.RangeToByteOffsets(h.Range)
+		result = append(result, HighlightRange{Start: start, End: end, Kind: h.Kind})
+	}
+	return result
+}
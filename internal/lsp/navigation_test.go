@@ -2,6 +2,8 @@ package lsp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -914,3 +916,76 @@ func TestSymbolContains(t *testing.T) {
 		t.Error("Should not contain position after range")
 	}
 }
+
+// newHighlightTestServer creates a ready server advertising provider as its
+// document highlight capability that answers every request with result.
+func newHighlightTestServer(t *testing.T, provider any, result []DocumentHighlight) *Server {
+	t.Helper()
+
+	s, _ := newFakeLSPServer(t, ServerCapabilities{DocumentHighlightProvider: provider}, func(string, json.RawMessage) any {
+		return result
+	})
+	return s
+}
+
+func TestServerDocumentHighlight(t *testing.T) {
+	content := "var é = 1\né = é + 2\n😀 é\n"
+	highlights := []DocumentHighlight{
+		{Range: Range{Start: Position{Line: 0, Character: 4}, End: Position{Line: 0, Character: 5}}, Kind: DocumentHighlightKindWrite},
+		{Range: Range{Start: Position{Line: 1, Character: 0}, End: Position{Line: 1, Character: 1}}, Kind: DocumentHighlightKindWrite},
+		{Range: Range{Start: Position{Line: 1, Character: 4}, End: Position{Line: 1, Character: 5}}, Kind: DocumentHighlightKindRead},
+		{Range: Range{Start: Position{Line: 2, Character: 3}, End: Position{Line: 2, Character: 4}}},
+	}
+	s := newHighlightTestServer(t, true, highlights)
+
+	got, err := s.DocumentHighlight(context.Background(), "/tmp/main.go", Position{Line: 0, Character: 4})
+	if err != nil {
+		t.Fatalf("DocumentHighlight() error = %v", err)
+	}
+	if len(got) != len(highlights) {
+		t.Fatalf("DocumentHighlight() returned %d highlights, want %d", len(got), len(highlights))
+	}
+	if got[3].Kind != DocumentHighlightKindText {
+		t.Errorf("missing kind = %v, want DocumentHighlightKindText", got[3].Kind)
+	}
+
+	want := []HighlightRange{
+		{Start: 4, End: 6, Kind: DocumentHighlightKindWrite},
+		{Start: 11, End: 13, Kind: DocumentHighlightKindWrite},
+		{Start: 16, End: 18, Kind: DocumentHighlightKindRead},
+		{Start: 28, End: 30, Kind: DocumentHighlightKindText},
+	}
+	ranges := HighlightRanges(content, got)
+	for i, r := range ranges {
+		if r != want[i] {
+			t.Errorf("HighlightRanges()[%d] = %+v, want %+v", i, r, want[i])
+		}
+		if text := content[r.Start:r.End]; text != "é" {
+			t.Errorf("highlight %d covers %q, want %q", i, text, "é")
+		}
+	}
+}
+
+func TestServerDocumentHighlightEmpty(t *testing.T) {
+	s := newHighlightTestServer(t, true, nil)
+
+	got, err := s.DocumentHighlight(context.Background(), "/tmp/main.go", Position{})
+	if err != nil {
+		t.Fatalf("DocumentHighlight() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("DocumentHighlight() = %v, want empty", got)
+	}
+	if ranges := HighlightRanges("x", got); ranges != nil {
+		t.Errorf("HighlightRanges() = %v, want nil", ranges)
+	}
+}
+
+func TestServerDocumentHighlightNotSupported(t *testing.T) {
+	s := newHighlightTestServer(t, nil, nil)
+
+	_, err := s.DocumentHighlight(context.Background(), "/tmp/main.go", Position{})
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("DocumentHighlight() error = %v, want ErrNotSupported", err)
+	}
+}
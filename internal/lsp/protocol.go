@@ -104,6 +104,78 @@ type WorkspaceEdit struct {
 	DocumentChanges []any                      `json:"documentChanges,omitempty"`
 }
 
+// OptionalVersionedTextDocumentIdentifier identifies a text document whose
+// version may be null, meaning the document is not open on the client.
+type OptionalVersionedTextDocumentIdentifier struct {
+	TextDocumentIdentifier
+	Version *int `json:"version"`
+}
+
+// TextDocumentEdit describes textual changes to a single versioned document.
+type TextDocumentEdit struct {
+	TextDocument OptionalVersionedTextDocumentIdentifier `json:"textDocument"`
+	Edits        []TextEdit                              `json:"edits"`
+}
+
+// Resource operation kinds used in WorkspaceEdit.DocumentChanges.
+const (
+	ResourceOperationCreate = "create"
+	ResourceOperationRename = "rename"
+	ResourceOperationDelete = "delete"
+)
+
+// CreateFileOptions are options for a create file operation.
+type CreateFileOptions struct {
+	Overwrite      bool `json:"overwrite,omitempty"`
+	IgnoreIfExists bool `json:"ignoreIfExists,omitempty"`
+}
+
+// CreateFile is a resource operation that creates a file.
+type CreateFile struct {
+	Kind    string             `json:"kind"`
+	URI     DocumentURI        `json:"uri"`
+	Options *CreateFileOptions `json:"options,omitempty"`
+}
+
+// FileRename describes a file or folder being renamed.
+type FileRename struct {
+	OldURI DocumentURI `json:"oldUri"`
+	NewURI DocumentURI `json:"newUri"`
+}
+
+// RenameFilesParams are the parameters of workspace/willRenameFiles and
+// workspace/didRenameFiles.
+type RenameFilesParams struct {
+	Files []FileRename `json:"files"`
+}
+
+// RenameFileOptions are options for a rename file operation.
+type RenameFileOptions struct {
+	Overwrite      bool `json:"overwrite,omitempty"`
+	IgnoreIfExists bool `json:"ignoreIfExists,omitempty"`
+}
+
+// RenameFile is a resource operation that renames a file.
+type RenameFile struct {
+	Kind    string             `json:"kind"`
+	OldURI  DocumentURI        `json:"oldUri"`
+	NewURI  DocumentURI        `json:"newUri"`
+	Options *RenameFileOptions `json:"options,omitempty"`
+}
+
+// DeleteFileOptions are options for a delete file operation.
+type DeleteFileOptions struct {
+	Recursive         bool `json:"recursive,omitempty"`
+	IgnoreIfNotExists bool `json:"ignoreIfNotExists,omitempty"`
+}
+
+// DeleteFile is a resource operation that deletes a file.
+type DeleteFile struct {
+	Kind    string             `json:"kind"`
+	URI     DocumentURI        `json:"uri"`
+	Options *DeleteFileOptions `json:"options,omitempty"`
+}
+
 // --- Initialize ---
 
 // InitializeParams are the parameters sent in an initialize request.
@@ -132,6 +204,11 @@ type InitializeServerInfo struct {
 // InitializedParams are the parameters sent in an initialized notification.
 type InitializedParams struct{}
 
+// CancelParams are the parameters sent in a $/cancelRequest notification.
+type CancelParams struct {
+	ID int64 `json:"id"`
+}
+
 // --- Capabilities ---
 
 // ClientCapabilities define capabilities the editor / tool provides on the client side.
@@ -151,6 +228,15 @@ type WorkspaceClientCapabilities struct {
 	Symbol                 *WorkspaceSymbolClientCapabilities  `json:"symbol,omitempty"`
 	WorkspaceFolders       bool                                `json:"workspaceFolders,omitempty"`
 	Configuration          bool                                `json:"configuration,omitempty"`
+	FileOperations         *FileOperationClientCapabilities    `json:"fileOperations,omitempty"`
+}
+
+// FileOperationClientCapabilities define which file operation requests
+// and notifications the client sends.
+type FileOperationClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+	WillRename          bool `json:"willRename,omitempty"`
+	DidRename           bool `json:"didRename,omitempty"`
 }
 
 // WorkspaceEditClientCapabilities define capabilities for workspace edits.
@@ -188,6 +274,8 @@ type TextDocumentClientCapabilities struct {
 	Formatting         *FormattingClientCapabilities         `json:"formatting,omitempty"`
 	RangeFormatting    *RangeFormattingClientCapabilities    `json:"rangeFormatting,omitempty"`
 	Rename             *RenameClientCapabilities             `json:"rename,omitempty"`
+	CodeLens           *CodeLensClientCapabilities           `json:"codeLens,omitempty"`
+	InlayHint          *InlayHintClientCapabilities          `json:"inlayHint,omitempty"`
 	PublishDiagnostics *PublishDiagnosticsClientCapabilities `json:"publishDiagnostics,omitempty"`
 }
 
@@ -276,6 +364,23 @@ type CodeActionKindSupport struct {
 	ValueSet []CodeActionKind `json:"valueSet,omitempty"`
 }
 
+// CodeLensClientCapabilities define capabilities for code lens.
+type CodeLensClientCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+}
+
+// InlayHintClientCapabilities define capabilities for inlay hints.
+type InlayHintClientCapabilities struct {
+	DynamicRegistration bool                     `json:"dynamicRegistration,omitempty"`
+	ResolveSupport      *InlayHintResolveSupport `json:"resolveSupport,omitempty"`
+}
+
+// InlayHintResolveSupport lists the inlay hint properties the client can
+// resolve lazily with inlayHint/resolve.
+type InlayHintResolveSupport struct {
+	Properties []string `json:"properties"`
+}
+
 // FormattingClientCapabilities define capabilities for formatting.
 type FormattingClientCapabilities struct {
 	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
@@ -338,12 +443,53 @@ type ServerCapabilities struct {
 	DocumentFormattingProvider      any                          `json:"documentFormattingProvider,omitempty"`
 	DocumentRangeFormattingProvider any                          `json:"documentRangeFormattingProvider,omitempty"`
 	RenameProvider                  any                          `json:"renameProvider,omitempty"`
+	CodeLensProvider                *CodeLensOptions             `json:"codeLensProvider,omitempty"`
+	InlayHintProvider               any                          `json:"inlayHintProvider,omitempty"`
 	Workspace                       *ServerWorkspaceCapabilities `json:"workspace,omitempty"`
 }
 
 // ServerWorkspaceCapabilities define workspace capabilities from the server.
 type ServerWorkspaceCapabilities struct {
 	WorkspaceFolders *WorkspaceFoldersServerCapabilities `json:"workspaceFolders,omitempty"`
+	FileOperations   *FileOperationsServerCapabilities   `json:"fileOperations,omitempty"`
+}
+
+// FileOperationsServerCapabilities define the file operations the server
+// wants to be told about. A nil field means the server is not interested.
+type FileOperationsServerCapabilities struct {
+	WillRename *FileOperationRegistrationOptions `json:"willRename,omitempty"`
+	DidRename  *FileOperationRegistrationOptions `json:"didRename,omitempty"`
+}
+
+// FileOperationRegistrationOptions restrict file operations to the files
+// matching one of the filters.
+type FileOperationRegistrationOptions struct {
+	Filters []FileOperationFilter `json:"filters"`
+}
+
+// FileOperationFilter matches files by URI scheme and glob pattern.
+type FileOperationFilter struct {
+	Scheme  string               `json:"scheme,omitempty"`
+	Pattern FileOperationPattern `json:"pattern"`
+}
+
+// File operation pattern kinds.
+const (
+	FileOperationPatternFile   = "file"
+	FileOperationPatternFolder = "folder"
+)
+
+// FileOperationPattern is a glob pattern, optionally restricted to files
+// or folders.
+type FileOperationPattern struct {
+	Glob    string                       `json:"glob"`
+	Matches string                       `json:"matches,omitempty"`
+	Options *FileOperationPatternOptions `json:"options,omitempty"`
+}
+
+// FileOperationPatternOptions are options for a file operation pattern.
+type FileOperationPatternOptions struct {
+	IgnoreCase bool `json:"ignoreCase,omitempty"`
 }
 
 // WorkspaceFoldersServerCapabilities define workspace folder support.
@@ -359,6 +505,16 @@ type CompletionOptions struct {
 	WorkDoneProgress  bool     `json:"workDoneProgress,omitempty"`
 }
 
+// CodeLensOptions define options for code lens.
+type CodeLensOptions struct {
+	ResolveProvider bool `json:"resolveProvider,omitempty"`
+}
+
+// InlayHintOptions define options for inlay hints.
+type InlayHintOptions struct {
+	ResolveProvider bool `json:"resolveProvider,omitempty"`
+}
+
 // SignatureHelpOptions define options for signature help.
 type SignatureHelpOptions struct {
 	TriggerCharacters   []string `json:"triggerCharacters,omitempty"`
@@ -635,9 +791,42 @@ type PrepareRenameParams struct {
 }
 
 // PrepareRenameResult is the result of a prepare rename request.
+// Servers answer with a range, a range and placeholder, or ask the client
+// to pick the word at the position itself (DefaultBehavior).
 type PrepareRenameResult struct {
-	Range       Range  `json:"range"`
-	Placeholder string `json:"placeholder,omitempty"`
+	Range           Range  `json:"range"`
+	Placeholder     string `json:"placeholder,omitempty"`
+	DefaultBehavior bool   `json:"defaultBehavior,omitempty"`
+}
+
+// UnmarshalJSON decodes any of the prepare rename result forms: a bare
+// range, a range with placeholder, or {"defaultBehavior": true}.
+func (r *PrepareRenameResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Range           *Range    `json:"range"`
+		Placeholder     string    `json:"placeholder"`
+		DefaultBehavior bool      `json:"defaultBehavior"`
+		Start           *Position `json:"start"`
+		End             *Position `json:"end"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = PrepareRenameResult{Placeholder: raw.Placeholder, DefaultBehavior: raw.DefaultBehavior}
+	switch {
+	case raw.Range != nil:
+		r.Range = *raw.Range
+	case raw.Start != nil && raw.End != nil:
+		r.Range = Range{Start: *raw.Start, End: *raw.End}
+	}
+	return nil
+}
+
+// RenameOptions are the options of the rename provider capability.
+type RenameOptions struct {
+	// PrepareProvider is true if the server supports prepareRename.
+	PrepareProvider bool `json:"prepareProvider,omitempty"`
 }
 
 // --- References ---
@@ -653,6 +842,152 @@ type ReferenceContext struct {
 	IncludeDeclaration bool `json:"includeDeclaration"`
 }
 
+// --- Document Highlight ---
+
+// DocumentHighlightParams are parameters for textDocument/documentHighlight.
+type DocumentHighlightParams struct {
+	TextDocumentPositionParams
+}
+
+// DocumentHighlight is a range in a document that refers to the symbol at
+// the requested position.
+type DocumentHighlight struct {
+	Range Range                 `json:"range"`
+	Kind  DocumentHighlightKind `json:"kind,omitempty"`
+}
+
+// DocumentHighlightKind distinguishes textual, read and write occurrences.
+type DocumentHighlightKind int
+
+const (
+	DocumentHighlightKindText  DocumentHighlightKind = 1
+	DocumentHighlightKindRead  DocumentHighlightKind = 2
+	DocumentHighlightKindWrite DocumentHighlightKind = 3
+)
+
+// --- Code Lens ---
+
+// CodeLensParams are parameters for textDocument/codeLens.
+type CodeLensParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// CodeLens is a command shown inline with source code, such as "run test"
+// or "3 references". A lens without a command is unresolved; its command
+// is filled in by codeLens/resolve.
+type CodeLens struct {
+	Range   Range    `json:"range"`
+	Command *Command `json:"command,omitempty"`
+	Data    any      `json:"data,omitempty"`
+}
+
+// IsResolved returns true if the lens has a command.
+func (l CodeLens) IsResolved() bool {
+	return l.Command != nil
+}
+
+// --- Inlay Hints ---
+
+// InlayHintParams are parameters for textDocument/inlayHint.
+type InlayHintParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+// InlayHint is virtual text shown inline with source code, such as an
+// inferred type or a parameter name. Tooltips, text edits and label part
+// details may be left out until filled in by inlayHint/resolve.
+type InlayHint struct {
+	Position     Position          `json:"position"`
+	Label        InlayHintLabel    `json:"label"`
+	Kind         InlayHintKind     `json:"kind,omitempty"`
+	TextEdits    []TextEdit        `json:"textEdits,omitempty"`
+	Tooltip      *InlayHintTooltip `json:"tooltip,omitempty"`
+	PaddingLeft  bool              `json:"paddingLeft,omitempty"`
+	PaddingRight bool              `json:"paddingRight,omitempty"`
+	Data         any               `json:"data,omitempty"`
+}
+
+// Text returns the text to display for the hint: its label with a space
+// on each padded side.
+func (h InlayHint) Text() string {
+	text := h.Label.String()
+	if h.PaddingLeft {
+		text = " " + text
+	}
+	if h.PaddingRight {
+		text += " "
+	}
+	return text
+}
+
+// InlayHintKind distinguishes type and parameter hints.
+type InlayHintKind int
+
+const (
+	InlayHintKindType      InlayHintKind = 1
+	InlayHintKindParameter InlayHintKind = 2
+)
+
+// InlayHintLabel is the label of an inlay hint. Servers send either a
+// plain string, decoded as a single part, or a list of parts.
+type InlayHintLabel []InlayHintLabelPart
+
+// UnmarshalJSON decodes a label from a string or a list of parts.
+func (l *InlayHintLabel) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*l = InlayHintLabel{{Value: text}}
+		return nil
+	}
+
+	var parts []InlayHintLabelPart
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return err
+	}
+	*l = parts
+	return nil
+}
+
+// String returns the label text, the values of all parts joined.
+func (l InlayHintLabel) String() string {
+	var sb strings.Builder
+	for _, part := range l {
+		sb.WriteString(part.Value)
+	}
+	return sb.String()
+}
+
+// InlayHintLabelPart is a part of an inlay hint label. A part with a
+// location or command can be clicked, such as a type name linking to its
+// definition.
+type InlayHintLabelPart struct {
+	Value    string            `json:"value"`
+	Tooltip  *InlayHintTooltip `json:"tooltip,omitempty"`
+	Location *Location         `json:"location,omitempty"`
+	Command  *Command          `json:"command,omitempty"`
+}
+
+// InlayHintTooltip is the tooltip of an inlay hint or label part. Servers
+// send either a plain string, decoded as plaintext, or markup content.
+type InlayHintTooltip MarkupContent
+
+// UnmarshalJSON decodes a tooltip from a string or markup content.
+func (t *InlayHintTooltip) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*t = InlayHintTooltip{Kind: MarkupKindPlainText, Value: text}
+		return nil
+	}
+
+	var content MarkupContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		return err
+	}
+	*t = InlayHintTooltip(content)
+	return nil
+}
+
 // --- Signature Help ---
 
 // SignatureHelpParams are parameters for textDocument/signatureHelp.
@@ -942,6 +1277,38 @@ func HasCapability(cap any) bool {
 	}
 }
 
+// HasPrepareRename returns true if a rename provider capability includes
+// prepareRename support.
+func HasPrepareRename(cap any) bool {
+	switch v := cap.(type) {
+	case RenameOptions:
+		return v.PrepareProvider
+	case *RenameOptions:
+		return v != nil && v.PrepareProvider
+	case map[string]any:
+		prepare, _ := v["prepareProvider"].(bool)
+		return prepare
+	default:
+		return false
+	}
+}
+
+// HasInlayHintResolve returns true if an inlay hint provider capability
+// includes inlayHint/resolve support.
+func HasInlayHintResolve(cap any) bool {
+	switch v := cap.(type) {
+	case InlayHintOptions:
+		return v.ResolveProvider
+	case *InlayHintOptions:
+		return v != nil && v.ResolveProvider
+	case map[string]any:
+		resolve, _ := v["resolveProvider"].(bool)
+		return resolve
+	default:
+		return false
+	}
+}
+
 // DefaultClientCapabilities returns reasonable default client capabilities.
 func DefaultClientCapabilities() ClientCapabilities {
 	return ClientCapabilities{
@@ -952,6 +1319,10 @@ func DefaultClientCapabilities() ClientCapabilities {
 			WorkspaceEdit: &WorkspaceEditClientCapabilities{
 				DocumentChanges: true,
 			},
+			FileOperations: &FileOperationClientCapabilities{
+				WillRename: true,
+				DidRename:  true,
+			},
 		},
 		TextDocument: &TextDocumentClientCapabilities{
 			Synchronization: &TextDocumentSyncClientCapabilities{
@@ -1001,6 +1372,12 @@ func DefaultClientCapabilities() ClientCapabilities {
 			Formatting:      &FormattingClientCapabilities{},
 			RangeFormatting: &RangeFormattingClientCapabilities{},
 			Rename:          &RenameClientCapabilities{PrepareSupport: true},
+			CodeLens:        &CodeLensClientCapabilities{},
+			InlayHint: &InlayHintClientCapabilities{
+				ResolveSupport: &InlayHintResolveSupport{
+					Properties: []string{"tooltip", "textEdits", "label.tooltip", "label.location", "label.command"},
+				},
+			},
 			PublishDiagnostics: &PublishDiagnosticsClientCapabilities{
 				RelatedInformation: true,
 				TagSupport: &DiagnosticTagSupport{
@@ -1016,105 +1393,3 @@ func DefaultClientCapabilities() ClientCapabilities {
 		},
 	}
 }
-
-// DetectLanguageID returns the LSP language ID for a file path.
-func DetectLanguageID(path string) string {
-	ext := strings.ToLower(filepath.Ext(path))
-
-	switch ext {
-	case ".go":
-		return "go"
-	case ".rs":
-		return "rust"
-	case ".ts":
-		return "typescript"
-	case ".tsx":
-		return "typescriptreact"
-	case ".js":
-		return "javascript"
-	case ".jsx":
-		return "javascriptreact"
-	case ".py":
-		return "python"
-	case ".rb":
-		return "ruby"
-	case ".java":
-		return "java"
-	case ".c":
-		return "c"
-	case ".cpp", ".cc", ".cxx":
-		return "cpp"
-	case ".h", ".hpp":
-		return "cpp"
-	case ".cs":
-		return "csharp"
-	case ".swift":
-		return "swift"
-	case ".kt", ".kts":
-		return "kotlin"
-	case ".scala":
-		return "scala"
-	case ".php":
-		return "php"
-	case ".lua":
-		return "lua"
-	case ".sh", ".bash":
-		return "shellscript"
-	case ".json":
-		return "json"
-	case ".yaml", ".yml":
-		return "yaml"
-	case ".toml":
-		return "toml"
-	case ".xml":
-		return "xml"
-	case ".html", ".htm":
-		return "html"
-	case ".css":
-		return "css"
-	case ".scss":
-		return "scss"
-	case ".less":
-		return "less"
-	case ".md", ".markdown":
-		return "markdown"
-	case ".sql":
-		return "sql"
-	case ".dockerfile":
-		return "dockerfile"
-	case ".proto":
-		return "protobuf"
-	case ".zig":
-		return "zig"
-	case ".nim":
-		return "nim"
-	case ".ex", ".exs":
-		return "elixir"
-	case ".erl", ".hrl":
-		return "erlang"
-	case ".hs":
-		return "haskell"
-	case ".ml", ".mli":
-		return "ocaml"
-	case ".fs", ".fsi", ".fsx":
-		return "fsharp"
-	case ".clj", ".cljs", ".cljc":
-		return "clojure"
-	case ".v":
-		return "v"
-	case ".d":
-		return "d"
-	default:
-		// Check filename for special cases
-		base := strings.ToLower(filepath.Base(path))
-		switch base {
-		case "dockerfile":
-			return "dockerfile"
-		case "makefile", "gnumakefile":
-			return "makefile"
-		case "cmakelists.txt":
-			return "cmake"
-		}
-		return "plaintext"
-	}
-}
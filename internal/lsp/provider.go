@@ -2,6 +2,7 @@ package lsp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -98,7 +99,11 @@ func (p *Provider) Completions(bufferPath string, offset int) ([]api.CompletionI
 	// Extract prefix for filtering (simple heuristic: word characters before cursor)
 	prefix := providerExtractPrefix(content, offset)
 
-	result, err := p.client.Complete(ctx, bufferPath, pos, prefix)
+	result, err := p.client.CompleteLatest(ctx, bufferPath, pos, prefix)
+	if errors.Is(err, context.Canceled) {
+		// A newer completion request for this buffer replaced this one
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
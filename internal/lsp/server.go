@@ -11,6 +11,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/dshills/keystorm/internal/langid"
 )
 
 // ServerStatus indicates the current state of a server.
@@ -809,6 +811,43 @@ func (s *Server) References(ctx context.Context, path string, pos Position, incl
 	return result, nil
 }
 
+// DocumentHighlight returns the occurrences of the symbol at a position.
+// Highlights without a kind are reported as DocumentHighlightKindText.
+func (s *Server) DocumentHighlight(ctx context.Context, path string, pos Position) ([]DocumentHighlight, error) {
+	if s.Status() != ServerStatusReady {
+		return nil, ErrServerNotReady
+	}
+
+	if !HasCapability(s.capabilities.DocumentHighlightProvider) {
+		return nil, ErrNotSupported
+	}
+
+	uri := FilePathToURI(path)
+
+	params := DocumentHighlightParams{
+		TextDocumentPositionParams: TextDocumentPositionParams{
+			TextDocument: TextDocumentIdentifier{URI: uri},
+			Position:     pos,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	var result []DocumentHighlight
+	if err := s.transport.Call(ctx, "textDocument/documentHighlight", params, &result); err != nil {
+		return nil, err
+	}
+
+	for i := range result {
+		if result[i].Kind == 0 {
+			result[i].Kind = DocumentHighlightKindText
+		}
+	}
+
+	return result, nil
+}
+
 // DocumentSymbols returns symbols in a document.
 func (s *Server) DocumentSymbols(ctx context.Context, path string) ([]DocumentSymbol, error) {
 	if s.Status() != ServerStatusReady {
@@ -892,6 +931,119 @@ func (s *Server) CodeActions(ctx context.Context, path string, rng Range, diags
 	return result, nil
 }
 
+// CodeLens returns the code lenses of a document. Lenses may be returned
+// unresolved, without a command; see ResolveCodeLens.
+func (s *Server) CodeLens(ctx context.Context, path string) ([]CodeLens, error) {
+	if s.Status() != ServerStatusReady {
+		return nil, ErrServerNotReady
+	}
+
+	if s.capabilities.CodeLensProvider == nil {
+		return nil, ErrNotSupported
+	}
+
+	uri := FilePathToURI(path)
+
+	params := CodeLensParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	var result []CodeLens
+	if err := s.transport.Call(ctx, "textDocument/codeLens", params, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ResolveCodeLens fills in the command of an unresolved code lens.
+// Resolved lenses, and lenses of servers without codeLens/resolve support,
+// are returned as-is.
+func (s *Server) ResolveCodeLens(ctx context.Context, lens CodeLens) (*CodeLens, error) {
+	if s.Status() != ServerStatusReady {
+		return nil, ErrServerNotReady
+	}
+
+	if lens.IsResolved() || !s.SupportsCodeLensResolve() {
+		return &lens, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	var result CodeLens
+	if err := s.transport.Call(ctx, "codeLens/resolve", lens, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SupportsCodeLensResolve returns true if the server supports codeLens/resolve.
+func (s *Server) SupportsCodeLensResolve() bool {
+	return s.capabilities.CodeLensProvider != nil && s.capabilities.CodeLensProvider.ResolveProvider
+}
+
+// InlayHints returns the inlay hints of a document within rng. Hints may
+// be returned without tooltips or edits; see ResolveInlayHint.
+func (s *Server) InlayHints(ctx context.Context, path string, rng Range) ([]InlayHint, error) {
+	if s.Status() != ServerStatusReady {
+		return nil, ErrServerNotReady
+	}
+
+	if !HasCapability(s.capabilities.InlayHintProvider) {
+		return nil, ErrNotSupported
+	}
+
+	uri := FilePathToURI(path)
+
+	params := InlayHintParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Range:        rng,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	var result []InlayHint
+	if err := s.transport.Call(ctx, "textDocument/inlayHint", params, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ResolveInlayHint fills in the tooltip, edits and label part details of an
+// inlay hint. Hints of servers without inlayHint/resolve support are
+// returned as-is.
+func (s *Server) ResolveInlayHint(ctx context.Context, hint InlayHint) (*InlayHint, error) {
+	if s.Status() != ServerStatusReady {
+		return nil, ErrServerNotReady
+	}
+
+	if !s.SupportsInlayHintResolve() {
+		return &hint, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	var result InlayHint
+	if err := s.transport.Call(ctx, "inlayHint/resolve", hint, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SupportsInlayHintResolve returns true if the server supports inlayHint/resolve.
+func (s *Server) SupportsInlayHintResolve() bool {
+	return HasInlayHintResolve(s.capabilities.InlayHintProvider)
+}
+
 // Format formats an entire document.
 func (s *Server) Format(ctx context.Context, path string, opts FormattingOptions) ([]TextEdit, error) {
 	if s.Status() != ServerStatusReady {
@@ -980,8 +1132,45 @@ func (s *Server) Rename(ctx context.Context, path string, pos Position, newName
 	return result, nil
 }
 
+// PrepareRename asks the server whether the symbol at pos can be renamed.
+// It returns nil if the server rejects the rename, and ErrNotSupported if
+// the server does not support prepareRename.
+func (s *Server) PrepareRename(ctx context.Context, path string, pos Position) (*PrepareRenameResult, error) {
+	if s.Status() != ServerStatusReady {
+		return nil, ErrServerNotReady
+	}
+
+	if !HasPrepareRename(s.capabilities.RenameProvider) {
+		return nil, ErrNotSupported
+	}
+
+	params := PrepareRenameParams{
+		TextDocumentPositionParams: TextDocumentPositionParams{
+			TextDocument: TextDocumentIdentifier{URI: FilePathToURI(path)},
+			Position:     pos,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	var result *PrepareRenameResult
+	if err := s.transport.Call(ctx, "textDocument/prepareRename", params, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // SignatureHelp returns signature help information.
 func (s *Server) SignatureHelp(ctx context.Context, path string, pos Position) (*SignatureHelp, error) {
+	return s.SignatureHelpWithContext(ctx, path, pos, nil)
+}
+
+// SignatureHelpWithContext requests signature help with a trigger context,
+// describing how the request was triggered and, for retriggers, the
+// signature help currently shown.
+func (s *Server) SignatureHelpWithContext(ctx context.Context, path string, pos Position, sigCtx *SignatureHelpContext) (*SignatureHelp, error) {
 	if s.Status() != ServerStatusReady {
 		return nil, ErrServerNotReady
 	}
@@ -997,6 +1186,7 @@ func (s *Server) SignatureHelp(ctx context.Context, path string, pos Position) (
 			TextDocument: TextDocumentIdentifier{URI: uri},
 			Position:     pos,
 		},
+		Context: sigCtx,
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
@@ -1015,7 +1205,7 @@ func (s *Server) SignatureHelp(ctx context.Context, path string, pos Position) (
 // MatchesFile returns true if this server handles the given file.
 func (s *Server) MatchesFile(path string) bool {
 	// Check language ID
-	langID := DetectLanguageID(path)
+	langID := langid.FromPath(path)
 	for _, id := range s.config.LanguageIDs {
 		if id == langID {
 			return true
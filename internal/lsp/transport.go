@@ -95,7 +95,9 @@ func (t *Transport) Close() error {
 	return nil
 }
 
-// Call sends a request and waits for a response.
+// Call sends a request and waits for a response. If ctx is cancelled or
+// times out first, Call sends $/cancelRequest so the server can stop
+// working on the request, and returns ctx.Err().
 func (t *Transport) Call(ctx context.Context, method string, params any, result any) error {
 	if t.closed.Load() {
 		return ErrShutdown
@@ -129,6 +131,11 @@ func (t *Transport) Call(ctx context.Context, method string, params any, result
 	// Wait for response
 	select {
 	case <-ctx.Done():
+		// Best effort: the server may already have answered, in which
+		// case it ignores the cancellation
+		if !t.closed.Load() {
+			_ = t.send(&Request{JSONRPC: "2.0", Method: "$/cancelRequest", Params: CancelParams{ID: id}})
+		}
 		return ctx.Err()
 	case <-t.done:
 		return ErrShutdown
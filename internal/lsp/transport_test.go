@@ -1,10 +1,13 @@
 package lsp
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -27,6 +30,82 @@ func (p *mockPipe) Close() error {
 	return nil
 }
 
+// newFakeLSPServer creates a ready server advertising caps whose peer is
+// answered in-process. Every message is passed to respond with its method
+// and params; requests are answered with its result. The returned
+// function reports the methods received so far.
+func newFakeLSPServer(t *testing.T, caps ServerCapabilities, respond func(method string, params json.RawMessage) any) (*Server, func() []string) {
+	t.Helper()
+
+	clientToServer := newMockPipe()
+	serverToClient := newMockPipe()
+	transport := NewTransport(serverToClient.reader, clientToServer.writer, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	transport.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		transport.Close()
+		clientToServer.Close()
+		serverToClient.Close()
+	})
+
+	s := NewServer(ServerConfig{Timeout: time.Second}, "go")
+	s.transport = transport
+	s.capabilities = caps
+	s.status.Store(int32(ServerStatusReady))
+
+	var mu sync.Mutex
+	var methods []string
+
+	go func() {
+		r := bufio.NewReader(clientToServer.reader)
+		for {
+			length := 0
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				line = strings.TrimSpace(line)
+				if line == "" {
+					break
+				}
+				fmt.Sscanf(line, "Content-Length: %d", &length)
+			}
+			body := make([]byte, length)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return
+			}
+
+			var req struct {
+				ID     *int64          `json:"id"`
+				Method string          `json:"method"`
+				Params json.RawMessage `json:"params"`
+			}
+			json.Unmarshal(body, &req)
+
+			mu.Lock()
+			methods = append(methods, req.Method)
+			mu.Unlock()
+
+			result := respond(req.Method, req.Params)
+			if req.ID == nil {
+				continue
+			}
+			data, _ := json.Marshal(result)
+			resp, _ := json.Marshal(Response{JSONRPC: "2.0", ID: *req.ID, Result: data})
+			fmt.Fprintf(serverToClient.writer, "Content-Length: %d\r\n\r\n%s", len(resp), resp)
+		}
+	}()
+
+	return s, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), methods...)
+	}
+}
+
 func TestTransport_SendNotification(t *testing.T) {
 	// Create pipes
 	clientToServer := newMockPipe()
@@ -389,3 +468,111 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+// fakeServer reads the messages a transport sends and can answer requests.
+type fakeServer struct {
+	in  *Transport // parses messages from the client
+	out io.Writer
+}
+
+// newFakeServerTransport returns a started client transport connected to
+// a fakeServer.
+func newFakeServerTransport(t *testing.T) (*Transport, *fakeServer) {
+	t.Helper()
+
+	clientToServer := newMockPipe()
+	serverToClient := newMockPipe()
+	transport := NewTransport(serverToClient.reader, clientToServer.writer, nil)
+	transport.Start(context.Background())
+	t.Cleanup(func() {
+		transport.Close()
+		clientToServer.Close()
+		serverToClient.Close()
+	})
+
+	return transport, &fakeServer{
+		in:  NewTransport(clientToServer.reader, nil, nil),
+		out: serverToClient.writer,
+	}
+}
+
+// next returns the next message sent by the client.
+func (s *fakeServer) next(t *testing.T) Request {
+	t.Helper()
+
+	type result struct {
+		req Request
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		data, err := s.in.readMessage()
+		var req Request
+		if err == nil {
+			err = json.Unmarshal(data, &req)
+		}
+		ch <- result{req, err}
+	}()
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatalf("reading client message: %v", r.err)
+		}
+		return r.req
+	case <-time.After(2 * time.Second):
+		t.Fatal("client sent no message")
+		return Request{}
+	}
+}
+
+// cancelledID returns the request ID of a $/cancelRequest notification.
+func cancelledID(t *testing.T, req Request) int64 {
+	t.Helper()
+
+	if req.Method != "$/cancelRequest" {
+		t.Fatalf("method = %q, want $/cancelRequest", req.Method)
+	}
+	data, _ := json.Marshal(req.Params)
+	var params CancelParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		t.Fatalf("cancel params: %v", err)
+	}
+	return params.ID
+}
+
+// reply answers a request with result.
+func (s *fakeServer) reply(id int64, result any) {
+	resultBytes, _ := json.Marshal(result)
+	respBytes, _ := json.Marshal(Response{JSONRPC: "2.0", ID: id, Result: resultBytes})
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(respBytes), respBytes)
+}
+
+func TestTransport_CallCancel(t *testing.T) {
+	transport, server := newFakeServerTransport(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- transport.Call(ctx, "textDocument/references", nil, nil)
+	}()
+
+	// The server is slow: it reads the request but never answers
+	req := server.next(t)
+	if req.Method != "textDocument/references" {
+		t.Fatalf("method = %q, want textDocument/references", req.Method)
+	}
+	cancel()
+
+	if id := cancelledID(t, server.next(t)); id != req.ID {
+		t.Errorf("cancelled ID = %d, want %d", id, req.ID)
+	}
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Call() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Call() did not return after cancellation")
+	}
+}
@@ -0,0 +1,500 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dshills/keystorm/internal/engine/buffer"
+)
+
+// maxWorkspaceEditHistory bounds the number of applied workspace edits kept for undo.
+const maxWorkspaceEditHistory = 100
+
+// EditableDocument is an open document that a workspace edit can modify.
+// *engine.Engine satisfies this interface.
+type EditableDocument interface {
+	// Text returns the full document content.
+	Text() string
+
+	// Replace replaces the byte range [start, end) with text.
+	Replace(start, end buffer.ByteOffset, text string) (buffer.ByteOffset, error)
+
+	// BeginUndoGroup starts grouping edits into a single undo unit.
+	BeginUndoGroup(name string)
+
+	// EndUndoGroup ends the current undo group.
+	EndUndoGroup()
+
+	// Undo reverts the most recent undo unit.
+	Undo() error
+
+	// RevisionID returns the current revision. It changes with every edit,
+	// including undo.
+	RevisionID() buffer.RevisionID
+}
+
+// SavableDocument is implemented by documents that must be written back
+// after a workspace edit changes them, such as files that are not open in
+// the editor. Save is called once after each text document edit; if it
+// fails, the edit is undone.
+type SavableDocument interface {
+	EditableDocument
+
+	// Save writes the document content to its file.
+	Save() error
+}
+
+// DocumentResolver gives workspace edit application access to the editor's
+// documents and to the file system.
+type DocumentResolver interface {
+	// Document returns the document for path, opening it if necessary.
+	Document(path string) (EditableDocument, error)
+
+	// DocumentVersion returns the version of path last sent to the server.
+	// It returns false if the document is not open.
+	DocumentVersion(path string) (int, bool)
+
+	// CreateFile creates an empty file.
+	CreateFile(path string, opts CreateFileOptions) error
+
+	// RenameFile renames a file.
+	RenameFile(oldPath, newPath string, opts RenameFileOptions) error
+
+	// DeleteFile deletes a file or directory.
+	DeleteFile(path string, opts DeleteFileOptions) error
+}
+
+// appliedWorkspaceEdit records how to revert an applied workspace edit.
+// Undo functions are stored in application order and run in reverse.
+type appliedWorkspaceEdit struct {
+	undo []func() error
+
+	// edited holds each edited document and its revision after the edit
+	edited []editedDocument
+}
+
+// editedDocument is a document changed by a workspace edit.
+type editedDocument struct {
+	path     string
+	doc      EditableDocument
+	revision buffer.RevisionID
+}
+
+// recordEdited records the revision of doc after the workspace edit
+// changed it, replacing the document and revision recorded by an earlier
+// change to path. Resolvers may return a new document for each change.
+func (a *appliedWorkspaceEdit) recordEdited(path string, doc EditableDocument) {
+	for i := range a.edited {
+		if a.edited[i].path == path {
+			a.edited[i].doc = doc
+			a.edited[i].revision = doc.RevisionID()
+			return
+		}
+	}
+	a.edited = append(a.edited, editedDocument{path: path, doc: doc, revision: doc.RevisionID()})
+}
+
+// checkUnchanged returns ErrWorkspaceEditModified if any edited document
+// has been changed since the workspace edit, so its top undo unit is no
+// longer the workspace edit.
+func (a *appliedWorkspaceEdit) checkUnchanged() error {
+	for _, e := range a.edited {
+		if e.doc.RevisionID() != e.revision {
+			return fmt.Errorf("%w: %s", ErrWorkspaceEditModified, e.path)
+		}
+	}
+	return nil
+}
+
+// revert undoes every step of the edit, most recent first.
+func (a *appliedWorkspaceEdit) revert() error {
+	var errs []error
+	for i := len(a.undo) - 1; i >= 0; i-- {
+		if err := a.undo[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ApplyWorkspaceEdit applies a workspace edit to every affected document.
+//
+// Documents are opened through resolver and edited in place. Text edits for
+// each document are applied in reverse position order so earlier edits do
+// not shift later ones. When the edit uses documentChanges, versioned text
+// edits are checked against resolver's document versions before anything is
+// changed and the whole edit is rejected with ErrStaleDocumentVersion if any
+// version does not match. Create, rename and delete file operations are
+// applied in order.
+//
+// The whole edit is recorded as a single unit: UndoWorkspaceEdit reverts all
+// affected files at once. If applying any part fails, the parts already
+// applied are reverted.
+func (c *Client) ApplyWorkspaceEdit(ctx context.Context, edit WorkspaceEdit, resolver DocumentResolver) error {
+	if resolver == nil {
+		return fmt.Errorf("%w: no document resolver", ErrInvalidWorkspaceEdit)
+	}
+
+	applied, err := applyWorkspaceEdit(ctx, edit, resolver)
+	if err != nil {
+		return err
+	}
+	if len(applied.undo) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.editHistory = append(c.editHistory, applied)
+	if len(c.editHistory) > maxWorkspaceEditHistory {
+		c.editHistory = c.editHistory[len(c.editHistory)-maxWorkspaceEditHistory:]
+	}
+	return nil
+}
+
+// SetDocumentResolver sets the resolver through which the workspace edits
+// of code actions are applied.
+func (c *Client) SetDocumentResolver(resolver DocumentResolver) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resolver = resolver
+}
+
+// applyWithResolver applies edit through the resolver set with
+// SetDocumentResolver.
+func (c *Client) applyWithResolver(ctx context.Context, edit WorkspaceEdit) error {
+	c.mu.RLock()
+	resolver := c.resolver
+	c.mu.RUnlock()
+
+	if resolver == nil {
+		return ErrNoDocumentResolver
+	}
+	return c.ApplyWorkspaceEdit(ctx, edit, resolver)
+}
+
+// UndoWorkspaceEdit reverts the most recently applied workspace edit
+// across all the files it touched. If any of those documents has been
+// edited since, nothing is reverted, the workspace edit is dropped from
+// the history and ErrWorkspaceEditModified is returned; the user's own
+// edits are undone with the document's undo instead.
+func (c *Client) UndoWorkspaceEdit() error {
+	c.mu.Lock()
+	if len(c.editHistory) == 0 {
+		c.mu.Unlock()
+		return ErrNoWorkspaceEditToUndo
+	}
+	applied := c.editHistory[len(c.editHistory)-1]
+	c.editHistory = c.editHistory[:len(c.editHistory)-1]
+	c.mu.Unlock()
+
+	if err := applied.checkUnchanged(); err != nil {
+		return err
+	}
+	return applied.revert()
+}
+
+// CanUndoWorkspaceEdit returns true if there is an applied workspace edit to undo.
+func (c *Client) CanUndoWorkspaceEdit() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.editHistory) > 0
+}
+
+// applyWorkspaceEdit applies edit and returns the record needed to revert it.
+func applyWorkspaceEdit(ctx context.Context, edit WorkspaceEdit, resolver DocumentResolver) (*appliedWorkspaceEdit, error) {
+	ops, err := planWorkspaceEdit(edit)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDocumentVersions(ops, resolver); err != nil {
+		return nil, err
+	}
+
+	applied := &appliedWorkspaceEdit{}
+	for _, op := range ops {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Join(err, applied.revert())
+		}
+
+		var undo func() error
+		switch op := op.(type) {
+		case TextDocumentEdit:
+			var doc EditableDocument
+			doc, err = applyTextDocumentEdit(op, resolver)
+			if doc != nil {
+				undo = doc.Undo
+				applied.recordEdited(URIToFilePath(op.TextDocument.URI), doc)
+			}
+		case CreateFile:
+			undo, err = applyCreateFile(op, resolver)
+		case RenameFile:
+			undo, err = applyRenameFile(op, resolver)
+		case DeleteFile:
+			undo, err = applyDeleteFile(op, resolver)
+		}
+		if err != nil {
+			return nil, errors.Join(err, applied.revert())
+		}
+		if undo != nil {
+			applied.undo = append(applied.undo, undo)
+		}
+	}
+
+	return applied, nil
+}
+
+// planWorkspaceEdit decodes edit into an ordered list of operations.
+// Per the protocol, documentChanges is preferred over changes when present.
+func planWorkspaceEdit(edit WorkspaceEdit) ([]any, error) {
+	if len(edit.DocumentChanges) > 0 {
+		ops := make([]any, 0, len(edit.DocumentChanges))
+		for i, change := range edit.DocumentChanges {
+			op, err := decodeDocumentChange(change)
+			if err != nil {
+				return nil, fmt.Errorf("document change %d: %w", i, err)
+			}
+			ops = append(ops, op)
+		}
+		return ops, nil
+	}
+	return changesToDocumentEdits(edit.Changes), nil
+}
+
+// changesToDocumentEdits converts WorkspaceEdit.Changes into unversioned
+// text document edits, ordered by URI for a deterministic application
+// order.
+func changesToDocumentEdits(changes map[DocumentURI][]TextEdit) []any {
+	uris := make([]DocumentURI, 0, len(changes))
+	for uri := range changes {
+		uris = append(uris, uri)
+	}
+	sort.Slice(uris, func(i, j int) bool { return uris[i] < uris[j] })
+
+	ops := make([]any, 0, len(uris))
+	for _, uri := range uris {
+		ops = append(ops, TextDocumentEdit{
+			TextDocument: OptionalVersionedTextDocumentIdentifier{
+				TextDocumentIdentifier: TextDocumentIdentifier{URI: uri},
+			},
+			Edits: changes[uri],
+		})
+	}
+	return ops
+}
+
+// decodeDocumentChange converts an entry of WorkspaceEdit.DocumentChanges
+// into a TextDocumentEdit, CreateFile, RenameFile or DeleteFile.
+// Entries decoded from JSON arrive as map[string]any and are re-decoded.
+func decodeDocumentChange(change any) (any, error) {
+	switch c := change.(type) {
+	case TextDocumentEdit, CreateFile, RenameFile, DeleteFile:
+		return c, nil
+	}
+
+	data, err := json.Marshal(change)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkspaceEdit, err)
+	}
+
+	var probe struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkspaceEdit, err)
+	}
+
+	var op any
+	switch probe.Kind {
+	case "":
+		var e TextDocumentEdit
+		if err = json.Unmarshal(data, &e); err == nil && e.TextDocument.URI == "" {
+			err = errors.New("missing text document")
+		}
+		op = e
+	case ResourceOperationCreate:
+		var c CreateFile
+		err = json.Unmarshal(data, &c)
+		op = c
+	case ResourceOperationRename:
+		var r RenameFile
+		err = json.Unmarshal(data, &r)
+		op = r
+	case ResourceOperationDelete:
+		var d DeleteFile
+		err = json.Unmarshal(data, &d)
+		op = d
+	default:
+		err = fmt.Errorf("unknown resource operation %q", probe.Kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkspaceEdit, err)
+	}
+	return op, nil
+}
+
+// checkDocumentVersions rejects the edit if any versioned text edit targets
+// a document version other than the one currently open.
+func checkDocumentVersions(ops []any, resolver DocumentResolver) error {
+	for _, op := range ops {
+		e, ok := op.(TextDocumentEdit)
+		if !ok || e.TextDocument.Version == nil {
+			continue
+		}
+
+		path := URIToFilePath(e.TextDocument.URI)
+		want := *e.TextDocument.Version
+		have, open := resolver.DocumentVersion(path)
+		if !open {
+			return fmt.Errorf("%w: %s is not open (edit targets version %d)", ErrStaleDocumentVersion, path, want)
+		}
+		if have != want {
+			return fmt.Errorf("%w: %s is at version %d (edit targets version %d)", ErrStaleDocumentVersion, path, have, want)
+		}
+	}
+	return nil
+}
+
+// editSpan is a text edit resolved to byte offsets.
+type editSpan struct {
+	start, end int
+	text       string
+	index      int
+}
+
+// applyTextDocumentEdit applies the edits for one document as a single
+// undo group and returns the edited document, whose Undo reverts them. It
+// returns nil if there was nothing to edit.
+func applyTextDocumentEdit(e TextDocumentEdit, resolver DocumentResolver) (EditableDocument, error) {
+	if len(e.Edits) == 0 {
+		return nil, nil
+	}
+
+	path := URIToFilePath(e.TextDocument.URI)
+	doc, err := resolver.Document(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+
+	// Resolve every position against the original content
+	pc := NewPositionConverter(doc.Text())
+	spans := make([]editSpan, len(e.Edits))
+	for i, te := range e.Edits {
+		start, end := pc.RangeToByteOffsets(te.Range)
+		if end < start {
+			return nil, fmt.Errorf("%w: %s: edit %d has an inverted range", ErrInvalidWorkspaceEdit, path, i)
+		}
+		spans[i] = editSpan{start: start, end: end, text: te.NewText, index: i}
+	}
+
+	// Apply from the end of the document backwards. Edits inserting at the
+	// same position are applied last-first so they end up in array order.
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start > spans[j].start
+		}
+		return spans[i].index > spans[j].index
+	})
+	for i := 1; i < len(spans); i++ {
+		if spans[i].end > spans[i-1].start {
+			return nil, fmt.Errorf("%w: %s: overlapping edits", ErrInvalidWorkspaceEdit, path)
+		}
+	}
+
+	doc.BeginUndoGroup("workspace edit")
+	for n, s := range spans {
+		if _, err := doc.Replace(buffer.ByteOffset(s.start), buffer.ByteOffset(s.end), s.text); err != nil {
+			doc.EndUndoGroup()
+			if n > 0 {
+				_ = doc.Undo()
+			}
+			return nil, fmt.Errorf("editing %s: %w", path, err)
+		}
+	}
+	doc.EndUndoGroup()
+
+	if saver, ok := doc.(SavableDocument); ok {
+		if err := saver.Save(); err != nil {
+			_ = doc.Undo()
+			return nil, fmt.Errorf("saving %s: %w", path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// applyCreateFile creates a file and returns a function that deletes it.
+func applyCreateFile(op CreateFile, resolver DocumentResolver) (func() error, error) {
+	path := URIToFilePath(op.URI)
+	var opts CreateFileOptions
+	if op.Options != nil {
+		opts = *op.Options
+	}
+
+	if err := resolver.CreateFile(path, opts); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", path, err)
+	}
+	return func() error {
+		return resolver.DeleteFile(path, DeleteFileOptions{IgnoreIfNotExists: true})
+	}, nil
+}
+
+// applyRenameFile renames a file and returns a function that renames it back.
+func applyRenameFile(op RenameFile, resolver DocumentResolver) (func() error, error) {
+	oldPath := URIToFilePath(op.OldURI)
+	newPath := URIToFilePath(op.NewURI)
+	var opts RenameFileOptions
+	if op.Options != nil {
+		opts = *op.Options
+	}
+
+	if err := resolver.RenameFile(oldPath, newPath, opts); err != nil {
+		return nil, fmt.Errorf("renaming %s to %s: %w", oldPath, newPath, err)
+	}
+	return func() error {
+		return resolver.RenameFile(newPath, oldPath, RenameFileOptions{})
+	}, nil
+}
+
+// applyDeleteFile deletes a file and returns a function that restores it.
+// The file content is captured before deletion; files that cannot be opened
+// as documents, such as directories, cannot be restored.
+func applyDeleteFile(op DeleteFile, resolver DocumentResolver) (func() error, error) {
+	path := URIToFilePath(op.URI)
+	var opts DeleteFileOptions
+	if op.Options != nil {
+		opts = *op.Options
+	}
+
+	content, captureErr := "", error(nil)
+	if doc, err := resolver.Document(path); err == nil {
+		content = doc.Text()
+	} else {
+		captureErr = err
+	}
+
+	if err := resolver.DeleteFile(path, opts); err != nil {
+		return nil, fmt.Errorf("deleting %s: %w", path, err)
+	}
+	return func() error {
+		if captureErr != nil {
+			return fmt.Errorf("restoring %s: %w", path, captureErr)
+		}
+		if err := resolver.CreateFile(path, CreateFileOptions{}); err != nil {
+			return fmt.Errorf("restoring %s: %w", path, err)
+		}
+		if content == "" {
+			return nil
+		}
+		doc, err := resolver.Document(path)
+		if err != nil {
+			return fmt.Errorf("restoring %s: %w", path, err)
+		}
+		_, err = doc.Replace(0, 0, content)
+		return err
+	}, nil
+}
@@ -0,0 +1,345 @@
+package lsp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/engine"
+)
+
+var _ EditableDocument = (*engine.Engine)(nil)
+
+// memResolver is an in-memory DocumentResolver backed by engines.
+type memResolver struct {
+	docs     map[string]*engine.Engine
+	versions map[string]int
+}
+
+func newMemResolver(files map[string]string) *memResolver {
+	r := &memResolver{
+		docs:     make(map[string]*engine.Engine),
+		versions: make(map[string]int),
+	}
+	for path, content := range files {
+		r.docs[path] = engine.New(engine.WithContent(content))
+		r.versions[path] = 1
+	}
+	return r
+}
+
+func (r *memResolver) Document(path string) (EditableDocument, error) {
+	doc, ok := r.docs[path]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return doc, nil
+}
+
+func (r *memResolver) DocumentVersion(path string) (int, bool) {
+	v, ok := r.versions[path]
+	return v, ok
+}
+
+func (r *memResolver) CreateFile(path string, opts CreateFileOptions) error {
+	if _, ok := r.docs[path]; ok && !opts.Overwrite {
+		if opts.IgnoreIfExists {
+			return nil
+		}
+		return os.ErrExist
+	}
+	r.docs[path] = engine.New()
+	return nil
+}
+
+func (r *memResolver) RenameFile(oldPath, newPath string, opts RenameFileOptions) error {
+	doc, ok := r.docs[oldPath]
+	if !ok {
+		return os.ErrNotExist
+	}
+	delete(r.docs, oldPath)
+	r.docs[newPath] = doc
+	return nil
+}
+
+func (r *memResolver) DeleteFile(path string, opts DeleteFileOptions) error {
+	if _, ok := r.docs[path]; !ok && !opts.IgnoreIfNotExists {
+		return os.ErrNotExist
+	}
+	delete(r.docs, path)
+	return nil
+}
+
+func (r *memResolver) text(t *testing.T, path string) string {
+	t.Helper()
+	doc, ok := r.docs[path]
+	if !ok {
+		t.Fatalf("document %s does not exist", path)
+	}
+	return doc.Text()
+}
+
+func renameEdit(line, start, end int, newText string) TextEdit {
+	return TextEdit{
+		Range: Range{
+			Start: Position{Line: line, Character: start},
+			End:   Position{Line: line, Character: end},
+		},
+		NewText: newText,
+	}
+}
+
+func TestApplyWorkspaceEdit_RenameAcrossFilesUndoesAsUnit(t *testing.T) {
+	files := map[string]string{
+		"/ws/a.go": "func foo() {}\n",
+		"/ws/b.go": "x := foo()\ny := foo()\n",
+		"/ws/c.go": "// foo\nfoo()\n",
+	}
+	resolver := newMemResolver(files)
+	client := NewClient()
+
+	edit := WorkspaceEdit{
+		Changes: map[DocumentURI][]TextEdit{
+			FilePathToURI("/ws/a.go"): {renameEdit(0, 5, 8, "bar")},
+			FilePathToURI("/ws/b.go"): {renameEdit(0, 5, 8, "bar"), renameEdit(1, 5, 8, "bar")},
+			FilePathToURI("/ws/c.go"): {renameEdit(0, 3, 6, "bar"), renameEdit(1, 0, 3, "bar")},
+		},
+	}
+
+	if err := client.ApplyWorkspaceEdit(context.Background(), edit, resolver); err != nil {
+		t.Fatalf("ApplyWorkspaceEdit() error = %v", err)
+	}
+
+	want := map[string]string{
+		"/ws/a.go": "func bar() {}\n",
+		"/ws/b.go": "x := bar()\ny := bar()\n",
+		"/ws/c.go": "// bar\nbar()\n",
+	}
+	for path, content := range want {
+		if got := resolver.text(t, path); got != content {
+			t.Errorf("%s = %q, want %q", path, got, content)
+		}
+	}
+
+	if !client.CanUndoWorkspaceEdit() {
+		t.Fatal("CanUndoWorkspaceEdit() = false, want true")
+	}
+	if err := client.UndoWorkspaceEdit(); err != nil {
+		t.Fatalf("UndoWorkspaceEdit() error = %v", err)
+	}
+	for path, content := range files {
+		if got := resolver.text(t, path); got != content {
+			t.Errorf("after undo %s = %q, want %q", path, got, content)
+		}
+	}
+
+	if err := client.UndoWorkspaceEdit(); !errors.Is(err, ErrNoWorkspaceEditToUndo) {
+		t.Errorf("second UndoWorkspaceEdit() error = %v, want ErrNoWorkspaceEditToUndo", err)
+	}
+}
+
+func TestUndoWorkspaceEdit_RefusesAfterUserEdit(t *testing.T) {
+	resolver := newMemResolver(map[string]string{
+		"/ws/a.go": "func foo() {}\n",
+		"/ws/b.go": "foo()\n",
+	})
+	client := NewClient()
+
+	edit := WorkspaceEdit{
+		Changes: map[DocumentURI][]TextEdit{
+			FilePathToURI("/ws/a.go"): {renameEdit(0, 5, 8, "bar")},
+			FilePathToURI("/ws/b.go"): {renameEdit(0, 0, 3, "bar")},
+		},
+	}
+	if err := client.ApplyWorkspaceEdit(context.Background(), edit, resolver); err != nil {
+		t.Fatalf("ApplyWorkspaceEdit() error = %v", err)
+	}
+
+	// The user types after the rename
+	if _, err := resolver.docs["/ws/b.go"].Insert(0, "x := "); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	if err := client.UndoWorkspaceEdit(); !errors.Is(err, ErrWorkspaceEditModified) {
+		t.Fatalf("UndoWorkspaceEdit() error = %v, want ErrWorkspaceEditModified", err)
+	}
+	if got := resolver.text(t, "/ws/a.go"); got != "func bar() {}\n" {
+		t.Errorf("a.go = %q, want the rename kept", got)
+	}
+	if got := resolver.text(t, "/ws/b.go"); got != "x := bar()\n" {
+		t.Errorf("b.go = %q, want the user's edit kept", got)
+	}
+	if client.CanUndoWorkspaceEdit() {
+		t.Error("refused edit should be dropped from the history")
+	}
+}
+
+func TestApplyWorkspaceEdit_DocumentChanges(t *testing.T) {
+	resolver := newMemResolver(map[string]string{
+		"/ws/old.go":  "package old\n",
+		"/ws/gone.go": "package gone\n",
+	})
+	client := NewClient()
+
+	// Entries arrive from JSON as maps
+	edit := WorkspaceEdit{
+		DocumentChanges: []any{
+			map[string]any{
+				"textDocument": map[string]any{"uri": string(FilePathToURI("/ws/old.go")), "version": float64(1)},
+				"edits": []any{map[string]any{
+					"range": map[string]any{
+						"start": map[string]any{"line": float64(0), "character": float64(8)},
+						"end":   map[string]any{"line": float64(0), "character": float64(11)},
+					},
+					"newText": "renamed",
+				}},
+			},
+			map[string]any{"kind": "rename", "oldUri": string(FilePathToURI("/ws/old.go")), "newUri": string(FilePathToURI("/ws/new.go"))},
+			CreateFile{Kind: ResourceOperationCreate, URI: FilePathToURI("/ws/created.go")},
+			DeleteFile{Kind: ResourceOperationDelete, URI: FilePathToURI("/ws/gone.go")},
+		},
+	}
+
+	if err := client.ApplyWorkspaceEdit(context.Background(), edit, resolver); err != nil {
+		t.Fatalf("ApplyWorkspaceEdit() error = %v", err)
+	}
+
+	if got := resolver.text(t, "/ws/new.go"); got != "package renamed\n" {
+		t.Errorf("new.go = %q, want %q", got, "package renamed\n")
+	}
+	if _, ok := resolver.docs["/ws/old.go"]; ok {
+		t.Error("old.go should have been renamed")
+	}
+	if _, ok := resolver.docs["/ws/created.go"]; !ok {
+		t.Error("created.go should exist")
+	}
+	if _, ok := resolver.docs["/ws/gone.go"]; ok {
+		t.Error("gone.go should have been deleted")
+	}
+
+	if err := client.UndoWorkspaceEdit(); err != nil {
+		t.Fatalf("UndoWorkspaceEdit() error = %v", err)
+	}
+	if got := resolver.text(t, "/ws/old.go"); got != "package old\n" {
+		t.Errorf("after undo old.go = %q, want %q", got, "package old\n")
+	}
+	if got := resolver.text(t, "/ws/gone.go"); got != "package gone\n" {
+		t.Errorf("after undo gone.go = %q, want %q", got, "package gone\n")
+	}
+	if _, ok := resolver.docs["/ws/created.go"]; ok {
+		t.Error("created.go should have been removed by undo")
+	}
+	if _, ok := resolver.docs["/ws/new.go"]; ok {
+		t.Error("new.go should have been renamed back by undo")
+	}
+}
+
+func TestApplyWorkspaceEdit_StaleVersion(t *testing.T) {
+	resolver := newMemResolver(map[string]string{
+		"/ws/a.go": "foo\n",
+		"/ws/b.go": "foo\n",
+	})
+	resolver.versions["/ws/b.go"] = 3
+	client := NewClient()
+
+	stale := 2
+	current := 1
+	edit := WorkspaceEdit{
+		DocumentChanges: []any{
+			TextDocumentEdit{
+				TextDocument: OptionalVersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: TextDocumentIdentifier{URI: FilePathToURI("/ws/a.go")},
+					Version:                &current,
+				},
+				Edits: []TextEdit{renameEdit(0, 0, 3, "bar")},
+			},
+			TextDocumentEdit{
+				TextDocument: OptionalVersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: TextDocumentIdentifier{URI: FilePathToURI("/ws/b.go")},
+					Version:                &stale,
+				},
+				Edits: []TextEdit{renameEdit(0, 0, 3, "bar")},
+			},
+		},
+	}
+
+	err := client.ApplyWorkspaceEdit(context.Background(), edit, resolver)
+	if !errors.Is(err, ErrStaleDocumentVersion) {
+		t.Fatalf("ApplyWorkspaceEdit() error = %v, want ErrStaleDocumentVersion", err)
+	}
+
+	// Nothing may be applied when any version is stale
+	if got := resolver.text(t, "/ws/a.go"); got != "foo\n" {
+		t.Errorf("a.go = %q, want unchanged", got)
+	}
+	if client.CanUndoWorkspaceEdit() {
+		t.Error("rejected edit should not be undoable")
+	}
+}
+
+func TestApplyWorkspaceEdit_RollsBackOnFailure(t *testing.T) {
+	resolver := newMemResolver(map[string]string{
+		"/ws/a.go": "foo\n",
+	})
+	client := NewClient()
+
+	edit := WorkspaceEdit{
+		Changes: map[DocumentURI][]TextEdit{
+			FilePathToURI("/ws/a.go"):       {renameEdit(0, 0, 3, "bar")},
+			FilePathToURI("/ws/missing.go"): {renameEdit(0, 0, 3, "bar")},
+		},
+	}
+
+	if err := client.ApplyWorkspaceEdit(context.Background(), edit, resolver); err == nil {
+		t.Fatal("ApplyWorkspaceEdit() error = nil, want error for missing document")
+	}
+	if got := resolver.text(t, "/ws/a.go"); got != "foo\n" {
+		t.Errorf("a.go = %q, want rolled back to %q", got, "foo\n")
+	}
+}
+
+func TestApplyWorkspaceEdit_SamePositionInsertsKeepOrder(t *testing.T) {
+	resolver := newMemResolver(map[string]string{"/ws/a.go": "x\n"})
+	client := NewClient()
+
+	edit := WorkspaceEdit{
+		Changes: map[DocumentURI][]TextEdit{
+			FilePathToURI("/ws/a.go"): {renameEdit(0, 0, 0, "a"), renameEdit(0, 0, 0, "b")},
+		},
+	}
+	if err := client.ApplyWorkspaceEdit(context.Background(), edit, resolver); err != nil {
+		t.Fatalf("ApplyWorkspaceEdit() error = %v", err)
+	}
+	if got := resolver.text(t, "/ws/a.go"); got != "abx\n" {
+		t.Errorf("a.go = %q, want %q", got, "abx\n")
+	}
+}
+
+func TestApplyCodeAction_AppliesThroughResolver(t *testing.T) {
+	resolver := newMemResolver(map[string]string{"/a.go": "package a\n"})
+	client := NewClient()
+	as := NewActionsService(nil, WithWorkspaceEditApplier(client.applyWithResolver))
+
+	action := CodeAction{Title: "rename package", Edit: &WorkspaceEdit{
+		Changes: map[DocumentURI][]TextEdit{FilePathToURI("/a.go"): {renameEdit(0, 8, 9, "b")}},
+	}}
+
+	// Without a resolver nothing is applied
+	result, err := as.ApplyCodeAction(context.Background(), action)
+	if !errors.Is(err, ErrNoDocumentResolver) || result.Applied {
+		t.Fatalf("ApplyCodeAction() = %+v, %v, want ErrNoDocumentResolver", result, err)
+	}
+
+	client.SetDocumentResolver(resolver)
+	result, err = as.ApplyCodeAction(context.Background(), action)
+	if err != nil || !result.Applied {
+		t.Fatalf("ApplyCodeAction() = %+v, %v, want applied", result, err)
+	}
+	if got := resolver.text(t, "/a.go"); got != "package b\n" {
+		t.Errorf("text = %q, want %q", got, "package b\n")
+	}
+	if !client.CanUndoWorkspaceEdit() {
+		t.Error("CanUndoWorkspaceEdit() = false, want the code action edit recorded")
+	}
+}
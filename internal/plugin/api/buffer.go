@@ -6,6 +6,29 @@ import (
 	"github.com/dshills/keystorm/internal/plugin/security"
 )
 
+// BatchBufferProvider is implemented by buffer providers that can apply
+// many edits atomically. When the context's BufferProvider implements it,
+// ks.buf.batch is available to plugins.
+type BatchBufferProvider interface {
+	// Batch calls fn to collect edits, then applies them as a single undo
+	// step named name. Nothing is applied if fn returns an error or the
+	// edits cannot be applied.
+	Batch(name string, fn func(b BufferBatch) error) error
+}
+
+// BufferBatch collects the edits of a BatchBufferProvider batch. Offsets
+// refer to the buffer as it was when the batch started.
+type BufferBatch interface {
+	// Insert inserts text at the given byte offset.
+	Insert(offset int, text string)
+
+	// Delete deletes text in the given byte range.
+	Delete(start, end int)
+
+	// Replace replaces text in the given byte range.
+	Replace(start, end int, text string)
+}
+
 // BufferModule implements the ks.buf API module.
 type BufferModule struct {
 	ctx *Context
@@ -39,6 +62,7 @@ func (m *BufferModule) Register(L *lua.LState) error {
 	L.SetField(mod, "insert", L.NewFunction(m.insert))
 	L.SetField(mod, "delete", L.NewFunction(m.delete))
 	L.SetField(mod, "replace", L.NewFunction(m.replace))
+	L.SetField(mod, "batch", L.NewFunction(m.batch))
 	L.SetField(mod, "undo", L.NewFunction(m.undo))
 	L.SetField(mod, "redo", L.NewFunction(m.redo))
 	L.SetField(mod, "path", L.NewFunction(m.path))
@@ -210,6 +234,77 @@ func (m *BufferModule) replace(L *lua.LState) int {
 	return 1
 }
 
+// batch([name,] fn)
+// Calls fn with a batch table offering insert, delete and replace, then
+// applies the collected edits as a single undo step. Offsets refer to the
+// buffer as it was before the batch. If fn raises an error nothing is
+// applied and the error is propagated.
+func (m *BufferModule) batch(L *lua.LState) int {
+	name := "plugin batch"
+	fnIdx := 1
+	if L.Get(1).Type() == lua.LTString {
+		name = L.CheckString(1)
+		fnIdx = 2
+	}
+	fn := L.CheckFunction(fnIdx)
+
+	if m.ctx.Buffer == nil {
+		L.RaiseError("batch: no buffer available")
+		return 0
+	}
+	provider, ok := m.ctx.Buffer.(BatchBufferProvider)
+	if !ok {
+		L.RaiseError("batch: not supported by buffer")
+		return 0
+	}
+
+	err := provider.Batch(name, func(b BufferBatch) error {
+		return L.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}, m.batchTable(L, b))
+	})
+	if err != nil {
+		L.RaiseError("batch: %v", err)
+	}
+	return 0
+}
+
+// batchTable returns the Lua table passed to a ks.buf.batch function.
+func (m *BufferModule) batchTable(L *lua.LState, b BufferBatch) *lua.LTable {
+	tbl := L.NewTable()
+	checkRange := func(L *lua.LState) (int, int) {
+		start := L.CheckInt(1)
+		end := L.CheckInt(2)
+		if start < 0 {
+			L.ArgError(1, "start must be non-negative")
+		}
+		if end < start {
+			L.ArgError(2, "end must be >= start")
+		}
+		return start, end
+	}
+
+	L.SetField(tbl, "insert", L.NewFunction(func(L *lua.LState) int {
+		offset := L.CheckInt(1)
+		text := L.CheckString(2)
+		if offset < 0 {
+			L.ArgError(1, "offset must be non-negative")
+			return 0
+		}
+		b.Insert(offset, text)
+		return 0
+	}))
+	L.SetField(tbl, "delete", L.NewFunction(func(L *lua.LState) int {
+		start, end := checkRange(L)
+		b.Delete(start, end)
+		return 0
+	}))
+	L.SetField(tbl, "replace", L.NewFunction(func(L *lua.LState) int {
+		start, end := checkRange(L)
+		b.Replace(start, end, L.CheckString(3))
+		return 0
+	}))
+	return tbl
+}
+
 // undo() -> bool
 // Undoes the last change.
 func (m *BufferModule) undo(L *lua.LState) int {
@@ -356,3 +356,106 @@ func TestBufferDeleteInvalidRange(t *testing.T) {
 		t.Error("delete with invalid range should error")
 	}
 }
+
+// mockBatchBufferProvider implements BatchBufferProvider for testing.
+type mockBatchBufferProvider struct {
+	mockBufferProvider
+	batches []string
+}
+
+type mockBufferEdit struct {
+	start, end int
+	text       string
+}
+
+type mockBufferBatch struct {
+	edits []mockBufferEdit
+}
+
+func (b *mockBufferBatch) Insert(offset int, text string) { b.Replace(offset, offset, text) }
+func (b *mockBufferBatch) Delete(start, end int)          { b.Replace(start, end, "") }
+func (b *mockBufferBatch) Replace(start, end int, text string) {
+	b.edits = append(b.edits, mockBufferEdit{start: start, end: end, text: text})
+}
+
+func (m *mockBatchBufferProvider) Batch(name string, fn func(b BufferBatch) error) error {
+	b := &mockBufferBatch{}
+	if err := fn(b); err != nil {
+		return err
+	}
+	// Apply from the end so earlier offsets stay valid
+	for i := len(b.edits) - 1; i >= 0; i-- {
+		e := b.edits[i]
+		if _, err := m.Replace(e.start, e.end, e.text); err != nil {
+			return err
+		}
+	}
+	m.batches = append(m.batches, name)
+	return nil
+}
+
+func TestBufferBatch(t *testing.T) {
+	buf := &mockBatchBufferProvider{mockBufferProvider: mockBufferProvider{text: "hello world"}}
+	L := lua.NewState()
+	t.Cleanup(func() { L.Close() })
+	if err := NewBufferModule(&Context{Buffer: buf}).Register(L); err != nil {
+		t.Fatalf("Register error = %v", err)
+	}
+
+	err := L.DoString(`
+		_ks_buf.batch("greet", function(b)
+			b.insert(0, ">")
+			b.replace(6, 11, "there")
+		end)
+		_ks_buf.batch(function(b)
+			b.delete(0, 1)
+		end)
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+
+	if buf.text != "hello there" {
+		t.Errorf("text = %q, want %q", buf.text, "hello there")
+	}
+	if len(buf.batches) != 2 || buf.batches[0] != "greet" {
+		t.Errorf("batches = %v, want [greet <default>]", buf.batches)
+	}
+}
+
+func TestBufferBatchError(t *testing.T) {
+	buf := &mockBatchBufferProvider{mockBufferProvider: mockBufferProvider{text: "hello"}}
+	L := lua.NewState()
+	t.Cleanup(func() { L.Close() })
+	if err := NewBufferModule(&Context{Buffer: buf}).Register(L); err != nil {
+		t.Fatalf("Register error = %v", err)
+	}
+
+	err := L.DoString(`
+		_ks_buf.batch(function(b)
+			b.insert(0, "x")
+			error("boom")
+		end)
+	`)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("DoString error = %v, want error containing boom", err)
+	}
+	if buf.text != "hello" || len(buf.batches) != 0 {
+		t.Errorf("text = %q, batches = %v; want nothing applied", buf.text, buf.batches)
+	}
+}
+
+func TestBufferBatchNotSupported(t *testing.T) {
+	buf := &mockBufferProvider{text: "hello"}
+	L, _ := setupBufferTest(t, buf)
+
+	err := L.DoString(`
+		_ks_buf.batch(function(b) b.insert(0, "x") end)
+	`)
+	if err == nil {
+		t.Error("batch without BatchBufferProvider should error")
+	}
+	if buf.text != "hello" {
+		t.Errorf("text = %q, want %q", buf.text, "hello")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	lua "github.com/yuin/gopher-lua"
 
+	plua "github.com/dshills/keystorm/internal/plugin/lua"
 	"github.com/dshills/keystorm/internal/plugin/security"
 )
 
@@ -44,10 +45,17 @@ type ConfigProvider interface {
 }
 
 // ConfigModule implements the ks.config API module.
+//
+// Plugins read any key, but write only keys under their own namespace,
+// "plugins.<name>", unless the permission checker set with
+// SetPermissionChecker allows further namespaces. Writes go through the
+// ConfigProvider, which validates them against the config schema.
 type ConfigModule struct {
 	ctx        *Context
 	pluginName string
 	L          *lua.LState
+	bridge     *plua.Bridge
+	checker    *security.PermissionChecker
 
 	// Track watches for cleanup
 	mu         sync.Mutex
@@ -59,8 +67,8 @@ type ConfigModule struct {
 
 // watchInfo tracks information about a config watch.
 type watchInfo struct {
-	pattern string
-	watchID string // ID from the ConfigProvider
+	pattern  string
+	watchIDs []string // IDs from the ConfigProvider
 }
 
 // NewConfigModule creates a new config module.
@@ -83,9 +91,18 @@ func (m *ConfigModule) RequiredCapability() security.Capability {
 	return security.CapabilityConfig
 }
 
+// SetPermissionChecker sets the checker deciding which keys the plugin may
+// write. Without one, writes are limited to the plugin's namespace.
+func (m *ConfigModule) SetPermissionChecker(checker *security.PermissionChecker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checker = checker
+}
+
 // Register registers the module into the Lua state.
 func (m *ConfigModule) Register(L *lua.LState) error {
 	m.L = L
+	m.bridge = plua.NewBridge(L)
 
 	// Create table to store handler functions (prevents GC)
 	m.handlerTbl = L.NewTable()
@@ -97,6 +114,7 @@ func (m *ConfigModule) Register(L *lua.LState) error {
 	L.SetField(mod, "get", L.NewFunction(m.get))
 	L.SetField(mod, "set", L.NewFunction(m.set))
 	L.SetField(mod, "watch", L.NewFunction(m.watch))
+	L.SetField(mod, "on_change", L.NewFunction(m.onChange))
 	L.SetField(mod, "unwatch", L.NewFunction(m.unwatch))
 	L.SetField(mod, "keys", L.NewFunction(m.keys))
 	L.SetField(mod, "has", L.NewFunction(m.has))
@@ -108,15 +126,36 @@ func (m *ConfigModule) Register(L *lua.LState) error {
 	return nil
 }
 
+// UnwatchAll removes all config watches of the plugin, leaving the module
+// usable. This should be called when the plugin is deactivated.
+func (m *ConfigModule) UnwatchAll() {
+	// Collect watch IDs while holding the lock
+	m.mu.Lock()
+	var watchIDs []string
+	for localID, info := range m.watches {
+		watchIDs = append(watchIDs, info.watchIDs...)
+		if m.handlerTbl != nil {
+			m.handlerTbl.RawSetString(localID, lua.LNil)
+		}
+	}
+	m.watches = make(map[string]watchInfo)
+	m.mu.Unlock()
+
+	// Unwatch outside the lock to avoid deadlock
+	if m.ctx.Config != nil {
+		for _, watchID := range watchIDs {
+			m.ctx.Config.Unwatch(watchID)
+		}
+	}
+}
+
 // Cleanup releases all handler references and unwatches all config changes.
 // This should be called when the plugin is unloaded.
 func (m *ConfigModule) Cleanup() {
-	// Collect watch IDs while holding the lock
+	m.UnwatchAll()
+
 	m.mu.Lock()
-	watchIDs := make([]string, 0, len(m.watches))
-	for _, info := range m.watches {
-		watchIDs = append(watchIDs, info.watchID)
-	}
+	defer m.mu.Unlock()
 
 	// Clear handler table
 	if m.L != nil {
@@ -125,16 +164,8 @@ func (m *ConfigModule) Cleanup() {
 
 	// Clear references to prevent use after cleanup
 	m.L = nil
+	m.bridge = nil
 	m.handlerTbl = nil
-	m.watches = make(map[string]watchInfo)
-	m.mu.Unlock()
-
-	// Unwatch all config watches outside the lock to avoid deadlock
-	if m.ctx.Config != nil {
-		for _, watchID := range watchIDs {
-			m.ctx.Config.Unwatch(watchID)
-		}
-	}
 }
 
 // generateWatchID generates a unique watch ID for this plugin.
@@ -145,7 +176,7 @@ func (m *ConfigModule) generateWatchID() string {
 
 // pluginNamespace returns the config namespace for this plugin.
 func (m *ConfigModule) pluginNamespace() string {
-	return "plugins." + m.pluginName
+	return security.PluginConfigNamespace(m.pluginName)
 }
 
 // isInPluginNamespace checks if a key is within the plugin's writable namespace.
@@ -175,12 +206,28 @@ func (m *ConfigModule) get(L *lua.LState) int {
 		return 1
 	}
 
-	L.Push(m.anyToLValue(L, value))
+	L.Push(m.toLua(L, value))
 	return 1
 }
 
+// checkWrite returns an error if the plugin may not write key.
+func (m *ConfigModule) checkWrite(key string) error {
+	m.mu.Lock()
+	checker := m.checker
+	m.mu.Unlock()
+
+	if checker != nil {
+		return checker.CheckConfigWrite(key)
+	}
+	if !m.isInPluginNamespace(key) {
+		return fmt.Errorf("key %q is outside plugin namespace %q", key, m.pluginNamespace())
+	}
+	return nil
+}
+
 // set(key, value) -> bool
-// Sets a configuration value. Only keys in the plugin's namespace can be set.
+// Sets a configuration value. Only keys in the plugin's namespace can be set,
+// unless the permission checker allows others.
 func (m *ConfigModule) set(L *lua.LState) int {
 	key := L.CheckString(1)
 	value := L.Get(2)
@@ -196,13 +243,12 @@ func (m *ConfigModule) set(L *lua.LState) int {
 	}
 
 	// Enforce namespace restriction
-	// Plugins can only write to their own namespace: plugins.<pluginName>.*
-	if !m.isInPluginNamespace(key) {
-		L.RaiseError("config.set: key %q is outside plugin namespace %q", key, m.pluginNamespace())
+	if err := m.checkWrite(key); err != nil {
+		L.RaiseError("config.set: %v", err)
 		return 0
 	}
 
-	goValue := m.lvalueToAny(value)
+	goValue := m.toGo(L, value)
 
 	if err := m.ctx.Config.Set(key, goValue); err != nil {
 		L.RaiseError("config.set: %v", err)
@@ -229,6 +275,47 @@ func (m *ConfigModule) watch(L *lua.LState) int {
 		return 0
 	}
 
+	localID, err := m.addWatch(pattern, handler, pattern)
+	if err != nil {
+		L.RaiseError("config.watch: %v", err)
+		return 0
+	}
+
+	L.Push(lua.LString(localID))
+	return 1
+}
+
+// on_change(path, handler) -> watchID
+// Watches for changes to path and the keys below it. The handler receives
+// the changed key, its old value and its new value. The watch ID can be
+// passed to unwatch; watches are removed when the plugin is deactivated.
+func (m *ConfigModule) onChange(L *lua.LState) int {
+	path := L.CheckString(1)
+	handler := L.CheckFunction(2)
+
+	if path == "" {
+		L.ArgError(1, "path cannot be empty")
+		return 0
+	}
+
+	if m.ctx.Config == nil {
+		L.RaiseError("config.on_change: no config provider available")
+		return 0
+	}
+
+	localID, err := m.addWatch(path, handler, path, path+".*")
+	if err != nil {
+		L.RaiseError("config.on_change: %v", err)
+		return 0
+	}
+
+	L.Push(lua.LString(localID))
+	return 1
+}
+
+// addWatch watches patterns with the config provider, calling handler for
+// changes, and returns the local watch ID.
+func (m *ConfigModule) addWatch(pattern string, handler *lua.LFunction, patterns ...string) (string, error) {
 	// Generate local watch ID
 	localID := m.generateWatchID()
 
@@ -236,12 +323,18 @@ func (m *ConfigModule) watch(L *lua.LState) int {
 	callback := m.createCallback(localID)
 
 	// Watch with the config provider first (before storing handler)
-	providerWatchID := m.ctx.Config.Watch(pattern, callback)
-
-	// Only store handler if provider returned a valid ID
-	if providerWatchID == "" {
-		L.RaiseError("config.watch: provider returned invalid watch ID")
-		return 0
+	providerWatchIDs := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		id := m.ctx.Config.Watch(p, callback)
+
+		// Only store handler if provider returned a valid ID
+		if id == "" {
+			for _, watchID := range providerWatchIDs {
+				m.ctx.Config.Unwatch(watchID)
+			}
+			return "", fmt.Errorf("provider returned invalid watch ID")
+		}
+		providerWatchIDs = append(providerWatchIDs, id)
 	}
 
 	// Store handler in our table to prevent GC
@@ -252,13 +345,12 @@ func (m *ConfigModule) watch(L *lua.LState) int {
 
 	// Track watch for cleanup
 	m.watches[localID] = watchInfo{
-		pattern: pattern,
-		watchID: providerWatchID,
+		pattern:  pattern,
+		watchIDs: providerWatchIDs,
 	}
 	m.mu.Unlock()
 
-	L.Push(lua.LString(localID))
-	return 1
+	return localID, nil
 }
 
 // unwatch(watchID) -> bool
@@ -294,7 +386,9 @@ func (m *ConfigModule) unwatch(L *lua.LState) int {
 	m.mu.Unlock()
 
 	// Unwatch from provider
-	m.ctx.Config.Unwatch(info.watchID)
+	for _, id := range info.watchIDs {
+		m.ctx.Config.Unwatch(id)
+	}
 
 	L.Push(lua.LTrue)
 	return 1
@@ -385,8 +479,8 @@ func (m *ConfigModule) executeConfigHandler(localID, key string, oldValue, newVa
 	// Call the handler with key, old_value, new_value
 	L.Push(handler)
 	L.Push(lua.LString(key))
-	L.Push(m.anyToLValue(L, oldValue))
-	L.Push(m.anyToLValue(L, newValue))
+	L.Push(m.toLua(L, oldValue))
+	L.Push(m.toLua(L, newValue))
 	if err := L.PCall(3, 0, nil); err != nil {
 		// Log error but don't propagate (config watchers shouldn't crash the system)
 		return err
@@ -394,103 +488,27 @@ func (m *ConfigModule) executeConfigHandler(localID, key string, oldValue, newVa
 	return nil
 }
 
-// anyToLValue converts a Go value to a Lua value.
-func (m *ConfigModule) anyToLValue(L *lua.LState, v any) lua.LValue {
-	switch val := v.(type) {
-	case nil:
-		return lua.LNil
-	case bool:
-		return lua.LBool(val)
-	case int:
-		return lua.LNumber(val)
-	case int64:
-		return lua.LNumber(val)
-	case float64:
-		return lua.LNumber(val)
-	case string:
-		return lua.LString(val)
-	case []any:
-		tbl := L.NewTable()
-		for i, item := range val {
-			tbl.RawSetInt(i+1, m.anyToLValue(L, item))
-		}
-		return tbl
-	case map[string]any:
-		tbl := L.NewTable()
-		for k, item := range val {
-			tbl.RawSetString(k, m.anyToLValue(L, item))
-		}
-		return tbl
-	default:
-		return lua.LString(fmt.Sprintf("%v", val))
+// toLua converts a config value to a Lua value.
+func (m *ConfigModule) toLua(L *lua.LState, v any) lua.LValue {
+	m.mu.Lock()
+	bridge := m.bridge
+	m.mu.Unlock()
+
+	if bridge == nil {
+		bridge = plua.NewBridge(L)
 	}
+	return bridge.ToLuaValue(v)
 }
 
-// lvalueToAny converts a Lua value to a Go value.
-func (m *ConfigModule) lvalueToAny(v lua.LValue) any {
-	if v == nil || v == lua.LNil {
-		return nil
-	}
-
-	switch val := v.(type) {
-	case lua.LBool:
-		return bool(val)
-	case lua.LNumber:
-		return float64(val)
-	case lua.LString:
-		return string(val)
-	case *lua.LTable:
-		// Check if it's an array-like table with contiguous integer keys starting at 1
-		isArray := true
-		maxIdx := 0
-		count := 0
-		val.ForEach(func(k, _ lua.LValue) {
-			count++
-			if num, ok := k.(lua.LNumber); ok {
-				idx := int(num)
-				// Verify it's a positive integer (Lua arrays are 1-indexed)
-				if float64(idx) != float64(num) || idx < 1 {
-					isArray = false
-					return
-				}
-				if idx > maxIdx {
-					maxIdx = idx
-				}
-			} else {
-				isArray = false
-			}
-		})
-
-		// Only treat as array if keys are contiguous (count == maxIdx)
-		if isArray && maxIdx > 0 && count == maxIdx {
-			arr := make([]any, maxIdx)
-			val.ForEach(func(k, v lua.LValue) {
-				if num, ok := k.(lua.LNumber); ok {
-					idx := int(num) - 1
-					if idx >= 0 && idx < maxIdx {
-						arr[idx] = m.lvalueToAny(v)
-					}
-				}
-			})
-			return arr
-		}
+// toGo converts a Lua value to a config value. Integral numbers become
+// int64, tables become []any or map[string]any.
+func (m *ConfigModule) toGo(L *lua.LState, v lua.LValue) any {
+	m.mu.Lock()
+	bridge := m.bridge
+	m.mu.Unlock()
 
-		// Treat as map
-		result := make(map[string]any)
-		val.ForEach(func(k, v lua.LValue) {
-			var keyStr string
-			switch key := k.(type) {
-			case lua.LString:
-				keyStr = string(key)
-			case lua.LNumber:
-				keyStr = fmt.Sprintf("%v", float64(key))
-			default:
-				keyStr = k.String()
-			}
-			result[keyStr] = m.lvalueToAny(v)
-		})
-		return result
-	default:
-		return v.String()
+	if bridge == nil {
+		bridge = plua.NewBridge(L)
 	}
+	return bridge.ToGoValue(v)
 }
@@ -250,7 +250,7 @@ func TestConfigSet(t *testing.T) {
 	if !ok {
 		t.Fatal("value should have been set")
 	}
-	if val.(float64) != 5000 {
+	if val != int64(5000) {
 		t.Errorf("value = %v, want 5000", val)
 	}
 }
@@ -278,7 +278,7 @@ func TestConfigSetNestedValue(t *testing.T) {
 	if settings["enabled"] != true {
 		t.Errorf("enabled = %v, want true", settings["enabled"])
 	}
-	if settings["level"] != float64(3) {
+	if settings["level"] != int64(3) {
 		t.Errorf("level = %v, want 3", settings["level"])
 	}
 }
@@ -312,6 +312,35 @@ func TestConfigSetAnotherPluginNamespace(t *testing.T) {
 	}
 }
 
+func TestConfigSetPermissionChecker(t *testing.T) {
+	cp := newMockConfigProvider()
+	L, mod := setupConfigTest(t, cp)
+
+	checker := security.NewPermissionChecker("testplugin")
+	checker.Grant(security.CapabilityConfig)
+	mod.SetPermissionChecker(checker)
+
+	if err := L.DoString(`_ks_config.set("plugins.testplugin.enabled", true)`); err != nil {
+		t.Errorf("set in own namespace error = %v", err)
+	}
+	err := L.DoString(`_ks_config.set("editor.tabSize", 8)`)
+	if err == nil || !strings.Contains(err.Error(), "outside plugin namespace") {
+		t.Errorf("set outside namespace error = %v, want namespace restriction", err)
+	}
+	if _, ok := cp.Get("editor.tabSize"); ok {
+		t.Error("blocked write reached the config provider")
+	}
+
+	// Broader access granted through the security layer
+	checker.AllowConfigNamespace("editor")
+	if err := L.DoString(`_ks_config.set("editor.tabSize", 8)`); err != nil {
+		t.Errorf("set in allowed namespace error = %v", err)
+	}
+	if val, _ := cp.Get("editor.tabSize"); val != int64(8) {
+		t.Errorf("editor.tabSize = %v, want 8", val)
+	}
+}
+
 func TestConfigHas(t *testing.T) {
 	cp := newMockConfigProvider()
 	cp.SetValue("editor.tabSize", 4)
@@ -673,6 +702,46 @@ func TestConfigCleanup(t *testing.T) {
 	}
 }
 
+func TestConfigOnChange(t *testing.T) {
+	cp := newMockConfigProvider()
+	L, mod := setupConfigTest(t, cp)
+
+	err := L.DoString(`
+		changes = {}
+		id = _ks_config.on_change("plugins.testplugin.ui", function(key, old, new)
+			table.insert(changes, key .. "=" .. tostring(new))
+		end)
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+
+	_ = cp.Set("plugins.testplugin.ui", "on")
+	_ = cp.Set("plugins.testplugin.ui.width", 40)
+	_ = cp.Set("plugins.testplugin.other", 1)
+
+	changes := L.GetGlobal("changes").(*lua.LTable)
+	if changes.Len() != 2 {
+		t.Fatalf("changes = %d, want 2", changes.Len())
+	}
+	if got := changes.RawGetInt(2).String(); got != "plugins.testplugin.ui.width=40" {
+		t.Errorf("change 2 = %q, want the nested key", got)
+	}
+
+	// Deactivation removes the watches but leaves the module usable
+	mod.UnwatchAll()
+	if cp.WatchCount() != 0 {
+		t.Errorf("watch count after UnwatchAll = %d, want 0", cp.WatchCount())
+	}
+	_ = cp.Set("plugins.testplugin.ui", "off")
+	if changes.Len() != 2 {
+		t.Errorf("changes after UnwatchAll = %d, want 2", changes.Len())
+	}
+	if err := L.DoString(`_ks_config.on_change("plugins.testplugin.ui", function() end)`); err != nil {
+		t.Errorf("on_change after UnwatchAll error = %v", err)
+	}
+}
+
 func TestConfigGetArrayValue(t *testing.T) {
 	cp := newMockConfigProvider()
 	cp.SetValue("editor.rulers", []any{80, 120, 160})
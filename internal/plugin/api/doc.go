@@ -4,18 +4,27 @@
 // Keystorm editor core. Plugins access editor functionality through the "ks"
 // namespace, which aggregates several submodules:
 //
-//   - ks.buf: Buffer operations (read, write, insert, delete)
+//   - ks.buf: Buffer operations (read, write, insert, delete, batched edits)
 //   - ks.cursor: Cursor manipulation (position, selection, multi-cursor)
 //   - ks.mode: Mode queries and switching (normal, insert, visual, etc.)
 //   - ks.util: Utility functions (string manipulation, table helpers)
+//   - ks.task: Runs a long computation in an isolated Lua state on another
+//     goroutine and delivers its results to a callback; created per plugin
+//     with the plugin's ResourceMonitor and LuaExecutor
+//   - ks.lsp: Language server queries (completion, hover, definition,
+//     diagnostics); registered when the Context has an LSPProvider and
+//     injected only for plugins granted CapabilityLSP
+//   - ks.http: HTTP get/post restricted by the plugin's host allow/block
+//     lists, rate limits and a response size cap; requires CapabilityNetwork
+//   - ks.ui: Notifications, input prompts, fuzzy quickpick lists and the
+//     plugin's statusline segment; registered when the Context has a
+//     UIProvider and injected only for plugins granted CapabilityUI
 //
 // Additional modules planned for future phases:
 //   - ks.keymap: Keybinding registration
 //   - ks.command: Command palette registration
 //   - ks.event: Event subscription
-//   - ks.config: Configuration access
-//   - ks.ui: UI notifications and overlays
-//   - ks.lsp: Language server protocol access
+//   - ks.config: Configuration access (get, set within the plugin namespace, on_change)
 //
 // # Architecture
 //
@@ -79,6 +88,12 @@
 //	local text = ks.buf.text()
 //	local line = ks.buf.line(1)
 //
+//	-- Apply many edits as one undo step
+//	ks.buf.batch("comment lines", function(b)
+//	    b.insert(0, "-- ")
+//	    b.insert(10, "-- ")
+//	end)
+//
 //	-- Manipulate cursor
 //	local pos = ks.cursor.get()
 //	ks.cursor.set(pos + 10)
@@ -91,4 +106,14 @@
 //	-- Use utilities
 //	local parts = ks.util.split("a,b,c", ",")
 //	local trimmed = ks.util.trim("  hello  ")
+//
+//	-- Compute without blocking the editor; the task function runs in its
+//	-- own Lua state, so it receives its input as arguments
+//	ks.task(function(n)
+//	    local sum = 0
+//	    for i = 1, n do sum = sum + i end
+//	    return sum
+//	end, function(sum, err)
+//	    ks.ui.notify(tostring(sum or err))
+//	end, 1e7)
 package api
@@ -328,6 +328,11 @@ func (m *EventModule) executeHandler(localID string, data map[string]any) error
 		return nil // Plugin unloaded
 	}
 
+	crashes := m.ctx.Crashes
+	if crashes != nil && crashes.PluginDisabled(m.pluginName) {
+		return nil // Plugin disabled after crashing
+	}
+
 	// Get the handler function from our table
 	handler := L.GetField(handlerTbl, localID)
 	if handler.Type() != lua.LTFunction {
@@ -341,7 +346,11 @@ func (m *EventModule) executeHandler(localID string, data map[string]any) error
 	L.Push(handler)
 	L.Push(dataTable)
 	if err := L.PCall(1, 0, nil); err != nil {
-		// Log error but don't propagate (event handlers shouldn't crash the system)
+		// Report the error but don't propagate (event handlers shouldn't
+		// crash the system)
+		if crashes != nil {
+			crashes.ReportCrash(m.pluginName, err)
+		}
 		return err
 	}
 	return nil
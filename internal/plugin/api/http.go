@@ -0,0 +1,284 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"github.com/dshills/keystorm/internal/plugin/security"
+)
+
+// DefaultHTTPTimeout bounds how long a ks.http request may take.
+const DefaultHTTPTimeout = 10 * time.Second
+
+// DefaultHTTPMaxResponseSize is the largest response body ks.http reads.
+const DefaultHTTPMaxResponseSize = 1 << 20 // 1 MB
+
+// maxHTTPRedirects is the number of redirects ks.http follows.
+const maxHTTPRedirects = 5
+
+var (
+	errHTTPRateLimited   = errors.New("network request rate limit exceeded")
+	errHTTPBadScheme     = errors.New("only http and https URLs are allowed")
+	errHTTPPrivateTarget = errors.New("private, loopback and link-local addresses are not allowed")
+	errHTTPTooManyHops   = errors.New("too many redirects")
+)
+
+// HTTPModule implements the ks.http API module.
+//
+// Every request, including each redirect, is checked against the plugin's
+// PermissionChecker host allow/block lists and counted against the
+// ResourceMonitor's network rate limit. Hosts that resolve to private,
+// loopback, link-local or unspecified addresses are refused unless the host
+// or address is explicitly on the allowed list, which protects against
+// server-side request forgery. Responses larger than the size cap fail.
+type HTTPModule struct {
+	checker *security.PermissionChecker
+	monitor *security.ResourceMonitor
+	client  *http.Client
+
+	mu              sync.Mutex
+	timeout         time.Duration
+	maxResponseSize int64
+}
+
+// NewHTTPModule creates a new HTTP module for a plugin. The monitor may be
+// nil to disable rate limiting.
+func NewHTTPModule(checker *security.PermissionChecker, monitor *security.ResourceMonitor) *HTTPModule {
+	m := &HTTPModule{
+		checker:         checker,
+		monitor:         monitor,
+		timeout:         DefaultHTTPTimeout,
+		maxResponseSize: DefaultHTTPMaxResponseSize,
+	}
+
+	dialer := &net.Dialer{Timeout: DefaultHTTPTimeout}
+	m.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 nil, // a proxy would bypass the address checks
+			DialContext:           m.dialContext(dialer),
+			TLSHandshakeTimeout:   DefaultHTTPTimeout,
+			ResponseHeaderTimeout: DefaultHTTPTimeout,
+			MaxIdleConns:          4,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxHTTPRedirects {
+				return errHTTPTooManyHops
+			}
+			return m.checkURL(req.URL)
+		},
+	}
+	return m
+}
+
+// SetTimeout sets the per-request timeout.
+func (m *HTTPModule) SetTimeout(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timeout = d
+}
+
+// SetMaxResponseSize sets the largest response body that will be read.
+func (m *HTTPModule) SetMaxResponseSize(n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxResponseSize = n
+}
+
+// Name returns the module name.
+func (m *HTTPModule) Name() string {
+	return "http"
+}
+
+// RequiredCapability returns the capability required for this module.
+func (m *HTTPModule) RequiredCapability() security.Capability {
+	return security.CapabilityNetwork
+}
+
+// Register registers the module into the Lua state.
+func (m *HTTPModule) Register(L *lua.LState) error {
+	mod := L.NewTable()
+
+	L.SetField(mod, "get", L.NewFunction(m.get))
+	L.SetField(mod, "post", L.NewFunction(m.post))
+
+	L.SetGlobal("_ks_http", mod)
+	return nil
+}
+
+// get(url, opts?) -> {status, body, headers} or nil, error
+// Performs a GET request. opts.headers is a table of request headers.
+func (m *HTTPModule) get(L *lua.LState) int {
+	rawURL := L.CheckString(1)
+	opts := L.OptTable(2, nil)
+	return m.do(L, http.MethodGet, rawURL, nil, opts)
+}
+
+// post(url, body, opts?) -> {status, body, headers} or nil, error
+// Performs a POST request with the given body. opts.headers is a table of
+// request headers and opts.content_type sets the Content-Type header.
+func (m *HTTPModule) post(L *lua.LState) int {
+	rawURL := L.CheckString(1)
+	body := L.CheckString(2)
+	opts := L.OptTable(3, nil)
+	return m.do(L, http.MethodPost, rawURL, strings.NewReader(body), opts)
+}
+
+// do performs a request and pushes the response table, or nil and an error
+// message.
+func (m *HTTPModule) do(L *lua.LState, method, rawURL string, body io.Reader, opts *lua.LTable) int {
+	resp, err := m.request(L.Context(), method, rawURL, body, opts)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	tbl := L.NewTable()
+	L.SetField(tbl, "status", lua.LNumber(resp.status))
+	L.SetField(tbl, "body", lua.LString(resp.body))
+	headers := L.NewTable()
+	for _, name := range resp.headerNames() {
+		L.SetField(headers, strings.ToLower(name), lua.LString(strings.Join(resp.headers[name], ", ")))
+	}
+	L.SetField(tbl, "headers", headers)
+	L.Push(tbl)
+	return 1
+}
+
+// httpResponse is a fully read response.
+type httpResponse struct {
+	status  int
+	body    []byte
+	headers http.Header
+}
+
+// headerNames returns the response header names in sorted order.
+func (r *httpResponse) headerNames() []string {
+	names := make([]string, 0, len(r.headers))
+	for name := range r.headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// request validates and performs a request, reading at most the size cap.
+func (m *HTTPModule) request(parent context.Context, method, rawURL string, body io.Reader, opts *lua.LTable) (*httpResponse, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := m.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	timeout := m.timeout
+	maxSize := m.maxResponseSize
+	m.mu.Unlock()
+
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if opts != nil {
+		if headers, ok := opts.RawGetString("headers").(*lua.LTable); ok {
+			headers.ForEach(func(k, v lua.LValue) {
+				req.Header.Set(k.String(), v.String())
+			})
+		}
+		if ct, ok := opts.RawGetString("content_type").(lua.LString); ok {
+			req.Header.Set("Content-Type", string(ct))
+		}
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("response body exceeds %d bytes", maxSize)
+	}
+
+	return &httpResponse{status: resp.StatusCode, body: data, headers: resp.Header}, nil
+}
+
+// checkURL checks the scheme, host permissions and rate limit for a
+// request or redirect target.
+func (m *HTTPModule) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errHTTPBadScheme
+	}
+	if m.checker == nil {
+		return security.NewCapabilityError(security.CapabilityNetwork, "network request", "not granted")
+	}
+	if err := m.checker.CheckNetwork(u.Host); err != nil {
+		return err
+	}
+	if m.monitor != nil && !m.monitor.TryNetworkRequest() {
+		return errHTTPRateLimited
+	}
+	return nil
+}
+
+// dialContext returns a dial function that resolves the target host itself
+// and refuses private addresses, so that DNS answers cannot redirect a
+// request to internal services.
+func (m *HTTPModule) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		hostAllowed := m.checker.HostExplicitlyAllowed(host)
+		var lastErr error = errHTTPPrivateTarget
+		for _, ip := range ips {
+			if isPrivateIP(ip.IP) && !hostAllowed && !m.checker.HostExplicitlyAllowed(ip.IP.String()) {
+				continue
+			}
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.IP.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+// isPrivateIP returns true for addresses that must not be reachable from
+// plugins by default.
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsUnspecified()
+}
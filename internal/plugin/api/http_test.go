@@ -0,0 +1,215 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"github.com/dshills/keystorm/internal/plugin/security"
+)
+
+// setupHTTPTest creates a Lua state with ks.http registered for a plugin
+// granted CapabilityNetwork.
+func setupHTTPTest(t *testing.T, configure func(*security.PermissionChecker)) (*lua.LState, *HTTPModule) {
+	t.Helper()
+
+	checker := security.NewPermissionChecker("test")
+	checker.Grant(security.CapabilityNetwork)
+	if configure != nil {
+		configure(checker)
+	}
+
+	mod := NewHTTPModule(checker, security.NewResourceMonitor(security.DefaultResourceLimits()))
+	L := lua.NewState()
+	t.Cleanup(L.Close)
+	if err := mod.Register(L); err != nil {
+		t.Fatalf("Register error = %v", err)
+	}
+	return L, mod
+}
+
+func TestHTTPModuleCapability(t *testing.T) {
+	mod := NewHTTPModule(nil, nil)
+	if mod.Name() != "http" {
+		t.Errorf("Name() = %q, want %q", mod.Name(), "http")
+	}
+	if mod.RequiredCapability() != security.CapabilityNetwork {
+		t.Errorf("RequiredCapability() = %q, want %q", mod.RequiredCapability(), security.CapabilityNetwork)
+	}
+}
+
+func TestHTTPGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", r.Header.Get("X-Req"))
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	// Loopback is reachable only because it is explicitly allowed
+	L, _ := setupHTTPTest(t, func(c *security.PermissionChecker) {
+		c.AllowHost("127.0.0.1")
+	})
+	L.SetGlobal("url", lua.LString(srv.URL))
+
+	err := L.DoString(`
+		resp, err = _ks_http.get(url, {headers = {["X-Req"] = "abc"}})
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if msg := L.GetGlobal("err"); msg != lua.LNil {
+		t.Fatalf("get error = %v", msg)
+	}
+
+	resp := L.GetGlobal("resp").(*lua.LTable)
+	if got := L.GetField(resp, "status"); got != lua.LNumber(http.StatusTeapot) {
+		t.Errorf("status = %v, want %d", got, http.StatusTeapot)
+	}
+	if got := L.GetField(resp, "body"); got != lua.LString("hello") {
+		t.Errorf("body = %v, want %q", got, "hello")
+	}
+	headers := L.GetField(resp, "headers").(*lua.LTable)
+	if got := L.GetField(headers, "x-test"); got != lua.LString("abc") {
+		t.Errorf("headers[x-test] = %v, want %q", got, "abc")
+	}
+}
+
+func TestHTTPPost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte(r.Method + " " + r.Header.Get("Content-Type") + " " + string(body)))
+	}))
+	defer srv.Close()
+
+	L, _ := setupHTTPTest(t, func(c *security.PermissionChecker) {
+		c.AllowHost("127.0.0.1")
+	})
+	L.SetGlobal("url", lua.LString(srv.URL))
+
+	err := L.DoString(`
+		resp, err = _ks_http.post(url, '{"a":1}', {content_type = "application/json"})
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	resp, ok := L.GetGlobal("resp").(*lua.LTable)
+	if !ok {
+		t.Fatalf("post failed: %v", L.GetGlobal("err"))
+	}
+	if got := L.GetField(resp, "body"); got != lua.LString(`POST application/json {"a":1}`) {
+		t.Errorf("body = %v", got)
+	}
+}
+
+func TestHTTPBlockedHost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("blocked host should not be contacted")
+	}))
+	defer srv.Close()
+
+	L, _ := setupHTTPTest(t, func(c *security.PermissionChecker) {
+		c.AllowHost("127.0.0.1")
+		c.BlockHost("127.0.0.1")
+	})
+	L.SetGlobal("url", lua.LString(srv.URL))
+
+	if err := L.DoString(`resp, err = _ks_http.get(url)`); err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if L.GetGlobal("resp") != lua.LNil {
+		t.Error("request to blocked host should fail")
+	}
+	if msg := L.GetGlobal("err").String(); !strings.Contains(msg, "blocked") {
+		t.Errorf("err = %q, want blocked host error", msg)
+	}
+}
+
+func TestHTTPPrivateAddressRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("private address should not be contacted")
+	}))
+	defer srv.Close()
+
+	// No allowed list: any host passes CheckNetwork, but loopback is refused
+	L, _ := setupHTTPTest(t, nil)
+	L.SetGlobal("url", lua.LString(srv.URL))
+
+	if err := L.DoString(`resp, err = _ks_http.get(url)`); err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if L.GetGlobal("resp") != lua.LNil {
+		t.Error("request to loopback should fail")
+	}
+	if msg := L.GetGlobal("err").String(); !strings.Contains(msg, "private") {
+		t.Errorf("err = %q, want private address error", msg)
+	}
+}
+
+func TestHTTPResponseSizeCap(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer srv.Close()
+
+	L, mod := setupHTTPTest(t, func(c *security.PermissionChecker) {
+		c.AllowHost("127.0.0.1")
+	})
+	mod.SetMaxResponseSize(64)
+	L.SetGlobal("url", lua.LString(srv.URL))
+
+	if err := L.DoString(`resp, err = _ks_http.get(url)`); err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if L.GetGlobal("resp") != lua.LNil {
+		t.Error("oversized response should fail")
+	}
+	if msg := L.GetGlobal("err").String(); !strings.Contains(msg, "exceeds 64 bytes") {
+		t.Errorf("err = %q, want size cap error", msg)
+	}
+}
+
+func TestHTTPRejectsScheme(t *testing.T) {
+	L, _ := setupHTTPTest(t, nil)
+
+	if err := L.DoString(`resp, err = _ks_http.get("file:///etc/passwd")`); err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if L.GetGlobal("resp") != lua.LNil {
+		t.Error("file URL should be rejected")
+	}
+}
+
+func TestHTTPRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	checker := security.NewPermissionChecker("test")
+	checker.Grant(security.CapabilityNetwork)
+	checker.AllowHost("127.0.0.1")
+	limits := security.DefaultResourceLimits()
+	limits.NetworkReqPerSecond = 1
+	mod := NewHTTPModule(checker, security.NewResourceMonitor(limits))
+
+	L := lua.NewState()
+	defer L.Close()
+	mod.Register(L)
+	L.SetGlobal("url", lua.LString(srv.URL))
+
+	if err := L.DoString(`
+		first = _ks_http.get(url)
+		second, err = _ks_http.get(url)
+	`); err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if L.GetGlobal("first") == lua.LNil {
+		t.Error("first request should succeed")
+	}
+	if msg := L.GetGlobal("err").String(); !strings.Contains(msg, "rate limit") {
+		t.Errorf("err = %q, want rate limit error", msg)
+	}
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"errors"
+	"strings"
 	"sync"
+	"time"
 
 	lua "github.com/yuin/gopher-lua"
 
@@ -190,11 +193,27 @@ const (
 	CodeActionKindSourceFixAll    CodeActionKind = "source.fixAll"
 )
 
+// DefaultLSPTimeout bounds how long a blocking ks.lsp query waits for the
+// language server. It matches the Lua sandbox's default execution timeout.
+const DefaultLSPTimeout = 5 * time.Second
+
+var (
+	errLSPUnavailable = errors.New("language server not available")
+	errLSPTimeout     = errors.New("language server request timed out")
+)
+
 // LSPModule implements the ks.lsp API module.
+//
+// Position-based queries accept either (line, col), 1-indexed in the
+// current buffer, or (path?, offset?) with a byte offset. Queries block up
+// to the module timeout, or run in the background when given a trailing
+// callback function, which receives the result (or nil and an error
+// message) on the plugin's goroutine via the context's LuaExecutor.
 type LSPModule struct {
 	ctx        *Context
 	pluginName string
 	L          *lua.LState
+	timeout    time.Duration
 
 	mu sync.Mutex
 }
@@ -204,9 +223,17 @@ func NewLSPModule(ctx *Context, pluginName string) *LSPModule {
 	return &LSPModule{
 		ctx:        ctx,
 		pluginName: pluginName,
+		timeout:    DefaultLSPTimeout,
 	}
 }
 
+// SetTimeout sets how long blocking queries wait for the language server.
+func (m *LSPModule) SetTimeout(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timeout = d
+}
+
 // Name returns the module name.
 func (m *LSPModule) Name() string {
 	return "lsp"
@@ -225,6 +252,7 @@ func (m *LSPModule) Register(L *lua.LState) error {
 
 	// Register LSP functions
 	L.SetField(mod, "completions", L.NewFunction(m.completions))
+	L.SetField(mod, "completion", L.NewFunction(m.completions))
 	L.SetField(mod, "diagnostics", L.NewFunction(m.diagnostics))
 	L.SetField(mod, "definition", L.NewFunction(m.definition))
 	L.SetField(mod, "references", L.NewFunction(m.references))
@@ -283,292 +311,124 @@ func (m *LSPModule) Cleanup() {
 	m.L = nil
 }
 
-// completions(path?, offset?) -> {items} or nil
+// completions(path?, offset?, callback?) -> {items} or nil
+// completions(line, col, callback?) -> {items} or nil
 // Returns completion items at the current or given position.
 func (m *LSPModule) completions(L *lua.LState) int {
-	path := L.OptString(1, "")
-	offset := L.OptInt(2, -1)
-
-	if m.ctx.LSP == nil {
-		L.Push(lua.LNil)
-		return 1
-	}
-
-	// Use current buffer path if not specified
-	if path == "" {
-		if m.ctx.Buffer != nil {
-			path = m.ctx.Buffer.Path()
-		}
-		if path == "" {
-			L.Push(lua.LNil)
-			return 1
-		}
-	}
-
-	// Use current cursor offset if not specified
-	if offset < 0 {
-		if m.ctx.Cursor != nil {
-			offset = m.ctx.Cursor.Get()
-		} else {
-			offset = 0
-		}
-	}
-
-	items, err := m.ctx.LSP.Completions(path, offset)
-	if err != nil {
-		L.Push(lua.LNil)
-		return 1
+	callback := popCallback(L)
+	path, offset, ok := m.position(L)
+	if !ok {
+		return m.unavailable(L, callback)
 	}
 
-	tbl := L.NewTable()
-	for i, item := range items {
-		itemTbl := L.NewTable()
-		L.SetField(itemTbl, "label", lua.LString(item.Label))
-		L.SetField(itemTbl, "kind", lua.LNumber(item.Kind))
-		L.SetField(itemTbl, "detail", lua.LString(item.Detail))
-		L.SetField(itemTbl, "documentation", lua.LString(item.Documentation))
-		L.SetField(itemTbl, "insert_text", lua.LString(item.InsertText))
-		L.SetField(itemTbl, "sort_text", lua.LString(item.SortText))
-		tbl.RawSetInt(i+1, itemTbl)
-	}
-
-	L.Push(tbl)
-	return 1
+	return lspRequest(m, L, callback, func() ([]CompletionItem, error) {
+		return m.ctx.LSP.Completions(path, offset)
+	}, m.completionsToTable)
 }
 
-// diagnostics(path?) -> {diagnostics} or nil
+// diagnostics(path?, callback?) -> {diagnostics} or nil
 // Returns diagnostics for the current or given file.
 func (m *LSPModule) diagnostics(L *lua.LState) int {
+	callback := popCallback(L)
 	path := L.OptString(1, "")
-
-	if m.ctx.LSP == nil {
-		L.Push(lua.LNil)
-		return 1
-	}
-
-	// Use current buffer path if not specified
 	if path == "" {
-		if m.ctx.Buffer != nil {
-			path = m.ctx.Buffer.Path()
-		}
-		if path == "" {
-			L.Push(lua.LNil)
-			return 1
-		}
+		path = m.currentPath()
 	}
-
-	diags, err := m.ctx.LSP.Diagnostics(path)
-	if err != nil {
-		L.Push(lua.LNil)
-		return 1
+	if m.ctx.LSP == nil || path == "" {
+		return m.unavailable(L, callback)
 	}
 
-	tbl := L.NewTable()
-	for i, diag := range diags {
-		diagTbl := m.diagnosticToTable(L, diag)
-		tbl.RawSetInt(i+1, diagTbl)
-	}
-
-	L.Push(tbl)
-	return 1
+	return lspRequest(m, L, callback, func() ([]Diagnostic, error) {
+		return m.ctx.LSP.Diagnostics(path)
+	}, func(L *lua.LState, diags []Diagnostic) lua.LValue {
+		tbl := L.NewTable()
+		for i, diag := range diags {
+			tbl.RawSetInt(i+1, m.diagnosticToTable(L, diag))
+		}
+		return tbl
+	})
 }
 
-// definition(path?, offset?) -> location or nil
+// definition(path?, offset?, callback?) -> location or nil
+// definition(line, col, callback?) -> location or nil
 // Returns the definition location for the symbol at the given position.
 func (m *LSPModule) definition(L *lua.LState) int {
-	path := L.OptString(1, "")
-	offset := L.OptInt(2, -1)
-
-	if m.ctx.LSP == nil {
-		L.Push(lua.LNil)
-		return 1
+	callback := popCallback(L)
+	path, offset, ok := m.position(L)
+	if !ok {
+		return m.unavailable(L, callback)
 	}
 
-	// Use current buffer path if not specified
-	if path == "" {
-		if m.ctx.Buffer != nil {
-			path = m.ctx.Buffer.Path()
-		}
-		if path == "" {
-			L.Push(lua.LNil)
-			return 1
-		}
-	}
-
-	// Use current cursor offset if not specified
-	if offset < 0 {
-		if m.ctx.Cursor != nil {
-			offset = m.ctx.Cursor.Get()
-		} else {
-			offset = 0
+	return lspRequest(m, L, callback, func() (*Location, error) {
+		return m.ctx.LSP.Definition(path, offset)
+	}, func(L *lua.LState, loc *Location) lua.LValue {
+		if loc == nil {
+			return lua.LNil
 		}
-	}
-
-	loc, err := m.ctx.LSP.Definition(path, offset)
-	if err != nil || loc == nil {
-		L.Push(lua.LNil)
-		return 1
-	}
-
-	L.Push(m.locationToTable(L, *loc))
-	return 1
+		return m.locationToTable(L, *loc)
+	})
 }
 
-// references(path?, offset?, include_declaration?) -> {locations} or nil
+// references(path?, offset?, include_declaration?, callback?) -> {locations} or nil
+// references(line, col, include_declaration?, callback?) -> {locations} or nil
 // Returns all references to the symbol at the given position.
 func (m *LSPModule) references(L *lua.LState) int {
-	path := L.OptString(1, "")
-	offset := L.OptInt(2, -1)
+	callback := popCallback(L)
 	includeDecl := L.OptBool(3, true)
-
-	if m.ctx.LSP == nil {
-		L.Push(lua.LNil)
-		return 1
+	path, offset, ok := m.position(L)
+	if !ok {
+		return m.unavailable(L, callback)
 	}
 
-	// Use current buffer path if not specified
-	if path == "" {
-		if m.ctx.Buffer != nil {
-			path = m.ctx.Buffer.Path()
-		}
-		if path == "" {
-			L.Push(lua.LNil)
-			return 1
-		}
-	}
-
-	// Use current cursor offset if not specified
-	if offset < 0 {
-		if m.ctx.Cursor != nil {
-			offset = m.ctx.Cursor.Get()
-		} else {
-			offset = 0
+	return lspRequest(m, L, callback, func() ([]Location, error) {
+		return m.ctx.LSP.References(path, offset, includeDecl)
+	}, func(L *lua.LState, locs []Location) lua.LValue {
+		tbl := L.NewTable()
+		for i, loc := range locs {
+			tbl.RawSetInt(i+1, m.locationToTable(L, loc))
 		}
-	}
-
-	locs, err := m.ctx.LSP.References(path, offset, includeDecl)
-	if err != nil {
-		L.Push(lua.LNil)
-		return 1
-	}
-
-	tbl := L.NewTable()
-	for i, loc := range locs {
-		tbl.RawSetInt(i+1, m.locationToTable(L, loc))
-	}
-
-	L.Push(tbl)
-	return 1
+		return tbl
+	})
 }
 
-// hover(path?, offset?) -> hover_info or nil
+// hover(path?, offset?, callback?) -> hover_info or nil
+// hover(line, col, callback?) -> hover_info or nil
 // Returns hover information for the symbol at the given position.
 func (m *LSPModule) hover(L *lua.LState) int {
-	path := L.OptString(1, "")
-	offset := L.OptInt(2, -1)
-
-	if m.ctx.LSP == nil {
-		L.Push(lua.LNil)
-		return 1
+	callback := popCallback(L)
+	path, offset, ok := m.position(L)
+	if !ok {
+		return m.unavailable(L, callback)
 	}
 
-	// Use current buffer path if not specified
-	if path == "" {
-		if m.ctx.Buffer != nil {
-			path = m.ctx.Buffer.Path()
+	return lspRequest(m, L, callback, func() (*HoverInfo, error) {
+		return m.ctx.LSP.Hover(path, offset)
+	}, func(L *lua.LState, info *HoverInfo) lua.LValue {
+		if info == nil {
+			return lua.LNil
 		}
-		if path == "" {
-			L.Push(lua.LNil)
-			return 1
+		tbl := L.NewTable()
+		L.SetField(tbl, "contents", lua.LString(info.Contents))
+		if info.Range != nil {
+			L.SetField(tbl, "range", m.rangeToTable(L, *info.Range))
 		}
-	}
-
-	// Use current cursor offset if not specified
-	if offset < 0 {
-		if m.ctx.Cursor != nil {
-			offset = m.ctx.Cursor.Get()
-		} else {
-			offset = 0
-		}
-	}
-
-	info, err := m.ctx.LSP.Hover(path, offset)
-	if err != nil || info == nil {
-		L.Push(lua.LNil)
-		return 1
-	}
-
-	tbl := L.NewTable()
-	L.SetField(tbl, "contents", lua.LString(info.Contents))
-	if info.Range != nil {
-		L.SetField(tbl, "range", m.rangeToTable(L, *info.Range))
-	}
-
-	L.Push(tbl)
-	return 1
+		return tbl
+	})
 }
 
-// signature_help(path?, offset?) -> signature_info or nil
+// signature_help(path?, offset?, callback?) -> signature_info or nil
+// signature_help(line, col, callback?) -> signature_info or nil
 // Returns signature help for the function at the given position.
 func (m *LSPModule) signatureHelp(L *lua.LState) int {
-	path := L.OptString(1, "")
-	offset := L.OptInt(2, -1)
-
-	if m.ctx.LSP == nil {
-		L.Push(lua.LNil)
-		return 1
-	}
-
-	// Use current buffer path if not specified
-	if path == "" {
-		if m.ctx.Buffer != nil {
-			path = m.ctx.Buffer.Path()
-		}
-		if path == "" {
-			L.Push(lua.LNil)
-			return 1
-		}
-	}
-
-	// Use current cursor offset if not specified
-	if offset < 0 {
-		if m.ctx.Cursor != nil {
-			offset = m.ctx.Cursor.Get()
-		} else {
-			offset = 0
-		}
-	}
-
-	info, err := m.ctx.LSP.SignatureHelp(path, offset)
-	if err != nil || info == nil {
-		L.Push(lua.LNil)
-		return 1
+	callback := popCallback(L)
+	path, offset, ok := m.position(L)
+	if !ok {
+		return m.unavailable(L, callback)
 	}
 
-	tbl := L.NewTable()
-	L.SetField(tbl, "active_signature", lua.LNumber(info.ActiveSignature+1)) // Convert to 1-indexed
-	L.SetField(tbl, "active_parameter", lua.LNumber(info.ActiveParameter+1)) // Convert to 1-indexed
-
-	sigsTbl := L.NewTable()
-	for i, sig := range info.Signatures {
-		sigTbl := L.NewTable()
-		L.SetField(sigTbl, "label", lua.LString(sig.Label))
-		L.SetField(sigTbl, "documentation", lua.LString(sig.Documentation))
-
-		paramsTbl := L.NewTable()
-		for j, param := range sig.Parameters {
-			paramTbl := L.NewTable()
-			L.SetField(paramTbl, "label", lua.LString(param.Label))
-			L.SetField(paramTbl, "documentation", lua.LString(param.Documentation))
-			paramsTbl.RawSetInt(j+1, paramTbl)
-		}
-		L.SetField(sigTbl, "parameters", paramsTbl)
-		sigsTbl.RawSetInt(i+1, sigTbl)
-	}
-	L.SetField(tbl, "signatures", sigsTbl)
-
-	L.Push(tbl)
-	return 1
+	return lspRequest(m, L, callback, func() (*SignatureInfo, error) {
+		return m.ctx.LSP.SignatureHelp(path, offset)
+	}, m.signatureToTable)
 }
 
 // format(path?, start_offset?, end_offset?) -> {edits} or nil
@@ -761,6 +621,230 @@ func (m *LSPModule) isAvailable(L *lua.LState) int {
 	return 1
 }
 
+// position resolves the buffer path and byte offset of a position query
+// from arguments 1 and 2: either a 1-indexed (line, col) in the current
+// buffer, or an optional path and byte offset defaulting to the current
+// buffer and cursor. Returns false if there is no provider or path.
+func (m *LSPModule) position(L *lua.LState) (string, int, bool) {
+	if m.ctx.LSP == nil {
+		return "", 0, false
+	}
+
+	if line, ok := L.Get(1).(lua.LNumber); ok {
+		col := L.OptInt(2, 1)
+		if m.ctx.Buffer == nil || m.ctx.Buffer.Path() == "" {
+			return "", 0, false
+		}
+		return m.ctx.Buffer.Path(), lineColumnOffset(m.ctx.Buffer.Text(), int(line), col), true
+	}
+
+	path := L.OptString(1, "")
+	offset := L.OptInt(2, -1)
+
+	// Use current buffer path if not specified
+	if path == "" {
+		path = m.currentPath()
+		if path == "" {
+			return "", 0, false
+		}
+	}
+
+	// Use current cursor offset if not specified
+	if offset < 0 {
+		if m.ctx.Cursor != nil {
+			offset = m.ctx.Cursor.Get()
+		} else {
+			offset = 0
+		}
+	}
+
+	return path, offset, true
+}
+
+// currentPath returns the path of the current buffer, or "" if none.
+func (m *LSPModule) currentPath() string {
+	if m.ctx.Buffer == nil {
+		return ""
+	}
+	return m.ctx.Buffer.Path()
+}
+
+// unavailable answers a query that cannot be sent to a language server.
+func (m *LSPModule) unavailable(L *lua.LState, callback *lua.LFunction) int {
+	if callback != nil {
+		_ = L.CallByParam(lua.P{Fn: callback, NRet: 0, Protect: true}, lua.LNil, lua.LString(errLSPUnavailable.Error()))
+		return 0
+	}
+	L.Push(lua.LNil)
+	return 1
+}
+
+// lspRequest runs an LSP query and delivers its result converted to Lua.
+//
+// Without a callback the query blocks until it completes or the module
+// timeout (or the Lua state's context) expires, and pushes the result or
+// nil. With a callback the query runs in the background and the callback
+// is invoked with the result, or nil and an error message, through the
+// LuaExecutor. Without an executor the callback can only be invoked safely
+// on this goroutine, so the query blocks and the callback runs before
+// returning.
+func lspRequest[T any](m *LSPModule, L *lua.LState, callback *lua.LFunction, query func() (T, error), convert func(*lua.LState, T) lua.LValue) int {
+	m.mu.Lock()
+	timeout := m.timeout
+	m.mu.Unlock()
+
+	if callback == nil {
+		result, err := awaitLSP(L, timeout, query)
+		if err != nil {
+			L.Push(lua.LNil)
+			return 1
+		}
+		L.Push(convert(L, result))
+		return 1
+	}
+
+	executor := m.ctx.LuaExecutor
+	if executor == nil {
+		result, err := awaitLSP(L, timeout, query)
+		_ = invokeLSPCallback(L, callback, convert, result, err)
+		return 0
+	}
+
+	go func() {
+		result, err := query()
+		_ = executor.ExecuteAsync(func(interface{}) error {
+			return invokeLSPCallback(L, callback, convert, result, err)
+		})
+	}()
+	return 0
+}
+
+// awaitLSP runs a query, giving up after timeout or when the Lua state's
+// context is done. The query keeps running in the background on timeout.
+func awaitLSP[T any](L *lua.LState, timeout time.Duration, query func() (T, error)) (T, error) {
+	type outcome struct {
+		value T
+		err   error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		value, err := query()
+		done <- outcome{value, err}
+	}()
+
+	var cancelled <-chan struct{}
+	if ctx := L.Context(); ctx != nil {
+		cancelled = ctx.Done()
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var zero T
+	select {
+	case o := <-done:
+		return o.value, o.err
+	case <-timer.C:
+		return zero, errLSPTimeout
+	case <-cancelled:
+		return zero, L.Context().Err()
+	}
+}
+
+// invokeLSPCallback calls a Lua callback with a query result, or with nil
+// and an error message. Must be called on the Lua state's owning goroutine.
+func invokeLSPCallback[T any](L *lua.LState, callback *lua.LFunction, convert func(*lua.LState, T) lua.LValue, result T, err error) error {
+	if err != nil {
+		return L.CallByParam(lua.P{Fn: callback, NRet: 0, Protect: true}, lua.LNil, lua.LString(err.Error()))
+	}
+	return L.CallByParam(lua.P{Fn: callback, NRet: 0, Protect: true}, convert(L, result))
+}
+
+// popCallback removes and returns a trailing function argument, if any.
+func popCallback(L *lua.LState) *lua.LFunction {
+	top := L.GetTop()
+	if top == 0 {
+		return nil
+	}
+	fn, ok := L.Get(top).(*lua.LFunction)
+	if !ok {
+		return nil
+	}
+	L.Remove(top)
+	return fn
+}
+
+// lineColumnOffset converts a 1-indexed line and byte column to a byte
+// offset in text, clamping to the line's bounds.
+func lineColumnOffset(text string, line, col int) int {
+	start := 0
+	for l := 1; l < line; l++ {
+		i := strings.IndexByte(text[start:], '\n')
+		if i < 0 {
+			return len(text)
+		}
+		start += i + 1
+	}
+
+	end := len(text)
+	if i := strings.IndexByte(text[start:], '\n'); i >= 0 {
+		end = start + i
+	}
+
+	offset := start + col - 1
+	if offset < start {
+		offset = start
+	}
+	if offset > end {
+		offset = end
+	}
+	return offset
+}
+
+func (m *LSPModule) completionsToTable(L *lua.LState, items []CompletionItem) lua.LValue {
+	tbl := L.NewTable()
+	for i, item := range items {
+		itemTbl := L.NewTable()
+		L.SetField(itemTbl, "label", lua.LString(item.Label))
+		L.SetField(itemTbl, "kind", lua.LNumber(item.Kind))
+		L.SetField(itemTbl, "detail", lua.LString(item.Detail))
+		L.SetField(itemTbl, "documentation", lua.LString(item.Documentation))
+		L.SetField(itemTbl, "insert_text", lua.LString(item.InsertText))
+		L.SetField(itemTbl, "sort_text", lua.LString(item.SortText))
+		tbl.RawSetInt(i+1, itemTbl)
+	}
+	return tbl
+}
+
+func (m *LSPModule) signatureToTable(L *lua.LState, info *SignatureInfo) lua.LValue {
+	if info == nil {
+		return lua.LNil
+	}
+
+	tbl := L.NewTable()
+	L.SetField(tbl, "active_signature", lua.LNumber(info.ActiveSignature+1)) // Convert to 1-indexed
+	L.SetField(tbl, "active_parameter", lua.LNumber(info.ActiveParameter+1)) // Convert to 1-indexed
+
+	sigsTbl := L.NewTable()
+	for i, sig := range info.Signatures {
+		sigTbl := L.NewTable()
+		L.SetField(sigTbl, "label", lua.LString(sig.Label))
+		L.SetField(sigTbl, "documentation", lua.LString(sig.Documentation))
+
+		paramsTbl := L.NewTable()
+		for j, param := range sig.Parameters {
+			paramTbl := L.NewTable()
+			L.SetField(paramTbl, "label", lua.LString(param.Label))
+			L.SetField(paramTbl, "documentation", lua.LString(param.Documentation))
+			paramsTbl.RawSetInt(j+1, paramTbl)
+		}
+		L.SetField(sigTbl, "parameters", paramsTbl)
+		sigsTbl.RawSetInt(i+1, sigTbl)
+	}
+	L.SetField(tbl, "signatures", sigsTbl)
+	return tbl
+}
+
 // Helper functions for converting Go types to Lua tables
 
 func (m *LSPModule) rangeToTable(L *lua.LState, r Range) *lua.LTable {
@@ -3,6 +3,7 @@ package api
 import (
 	"errors"
 	"testing"
+	"time"
 
 	lua "github.com/yuin/gopher-lua"
 
@@ -939,3 +940,157 @@ func TestLSPNoBufferPath(t *testing.T) {
 		t.Error("completions should return nil when no path available")
 	}
 }
+
+// positionLSPProvider records the offset of hover requests.
+type positionLSPProvider struct {
+	mockLSPProvider
+	hoverOffset int
+	block       chan struct{}
+}
+
+func (p *positionLSPProvider) Hover(bufferPath string, offset int) (*HoverInfo, error) {
+	if p.block != nil {
+		<-p.block
+	}
+	p.hoverOffset = offset
+	return &HoverInfo{Contents: "func Println(a ...any)"}, nil
+}
+
+// textBufferForLSP is a buffer provider with content, for line/col queries.
+type textBufferForLSP struct {
+	mockBufferProviderForLSP
+	text string
+}
+
+func (b *textBufferForLSP) Text() string { return b.text }
+
+// queueExecutor collects async Lua operations for the test goroutine to run.
+type queueExecutor struct {
+	ops chan func(L interface{}) error
+}
+
+func (e *queueExecutor) ExecuteAsync(fn func(L interface{}) error) error {
+	e.ops <- fn
+	return nil
+}
+
+func TestLSPHoverLineColumnViaRegistry(t *testing.T) {
+	lsp := &positionLSPProvider{}
+	ctx := &Context{
+		LSP:    lsp,
+		Buffer: &textBufferForLSP{mockBufferProviderForLSP{path: "/test/main.go"}, "package main\n\nfunc main() {\n\tfmt.Println()\n}\n"},
+	}
+	r, err := DefaultRegistry(ctx)
+	if err != nil {
+		t.Fatalf("DefaultRegistry error = %v", err)
+	}
+
+	L := lua.NewState()
+	defer L.Close()
+
+	checker := security.NewPermissionChecker("hover-plugin")
+	checker.Grant(security.CapabilityLSP)
+	if err := r.InjectAll(L, checker); err != nil {
+		t.Fatalf("InjectAll error = %v", err)
+	}
+
+	err = L.DoString(`
+		local ks = require("ks")
+		local info = ks.lsp.hover(4, 6)
+		text = info and info.contents
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+
+	if got := L.GetGlobal("text"); got.String() != "func Println(a ...any)" {
+		t.Errorf("hover text = %q, want Println signature", got.String())
+	}
+	// Line 4 starts at offset 28; column 6 is "Println" after the tab and "fmt."
+	if lsp.hoverOffset != 33 {
+		t.Errorf("hover offset = %d, want 33", lsp.hoverOffset)
+	}
+}
+
+func TestLSPCapabilityGating(t *testing.T) {
+	ctx := &Context{LSP: newMockLSPProvider()}
+	r, err := DefaultRegistry(ctx)
+	if err != nil {
+		t.Fatalf("DefaultRegistry error = %v", err)
+	}
+
+	L := lua.NewState()
+	defer L.Close()
+
+	checker := security.NewPermissionChecker("no-lsp-plugin")
+	if err := r.Inject(L, checker, "lsp"); err == nil {
+		t.Error("Inject(lsp) without CapabilityLSP should return error")
+	}
+	if err := r.InjectAll(L, checker); err != nil {
+		t.Fatalf("InjectAll error = %v", err)
+	}
+	if err := L.DoString(`assert(require("ks").lsp == nil, "ks.lsp should not be injected")`); err != nil {
+		t.Error(err)
+	}
+
+	// Without a provider the module is not registered at all
+	r, _ = DefaultRegistry(&Context{})
+	if _, ok := r.Get("lsp"); ok {
+		t.Error("lsp module should not be registered without an LSPProvider")
+	}
+}
+
+func TestLSPHoverCallback(t *testing.T) {
+	lsp := &positionLSPProvider{block: make(chan struct{})}
+	executor := &queueExecutor{ops: make(chan func(L interface{}) error, 1)}
+	ctx := &Context{
+		LSP:         lsp,
+		Buffer:      &mockBufferProviderForLSP{path: "/test/file.go"},
+		LuaExecutor: executor,
+	}
+	mod := NewLSPModule(ctx, "testplugin")
+
+	L := lua.NewState()
+	defer L.Close()
+	if err := mod.Register(L); err != nil {
+		t.Fatalf("Register error = %v", err)
+	}
+
+	// The call returns immediately while the server is still working
+	err := L.DoString(`
+		_ks_lsp.hover("/test/file.go", 7, function(info, err)
+			result = info and info.contents or err
+		end)
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if L.GetGlobal("result") != lua.LNil {
+		t.Fatal("callback should not run before the request completes")
+	}
+
+	close(lsp.block)
+	op := <-executor.ops
+	if err := op(L); err != nil {
+		t.Fatalf("callback error = %v", err)
+	}
+	if got := L.GetGlobal("result").String(); got != "func Println(a ...any)" {
+		t.Errorf("callback result = %q, want hover contents", got)
+	}
+}
+
+func TestLSPBlockingTimeout(t *testing.T) {
+	lsp := &positionLSPProvider{block: make(chan struct{})}
+	defer close(lsp.block)
+
+	L, mod := setupLSPTest(t, &lsp.mockLSPProvider)
+	mod.ctx.LSP = lsp
+	mod.SetTimeout(10 * time.Millisecond)
+
+	if err := L.DoString(`info = _ks_lsp.hover(1, 1)`); err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if L.GetGlobal("info") != lua.LNil {
+		t.Error("hover should return nil when the server times out")
+	}
+}
@@ -140,7 +140,7 @@ func installKSLoader(L *lua.LState) error {
 	// Collect all _ks_* globals into the ks table.
 	// Only modules that were successfully registered (based on capability checks) will have
 	// their _ks_* global set, so this effectively respects capability restrictions.
-	moduleNames := []string{"buf", "cursor", "mode", "util", "keymap", "command", "event", "config", "ui", "lsp", "project", "integration"}
+	moduleNames := []string{"buf", "cursor", "mode", "util", "keymap", "command", "event", "config", "ui", "lsp", "project", "integration", "http", "task"}
 	for _, name := range moduleNames {
 		globalName := "_ks_" + name
 		val := L.GetGlobal(globalName)
@@ -178,6 +178,11 @@ func DefaultRegistry(ctx *Context) (*Registry, error) {
 		NewUtilModule(),
 	}
 
+	// ks.lsp is only available when the editor provides a language server bridge
+	if ctx != nil && ctx.LSP != nil {
+		modules = append(modules, NewLSPModule(ctx, ""))
+	}
+
 	for _, mod := range modules {
 		if err := r.Register(mod); err != nil {
 			return nil, fmt.Errorf("failed to register module %q: %w", mod.Name(), err)
@@ -229,6 +234,20 @@ type Context struct {
 	// gopher-lua's LState is NOT goroutine-safe. The executor serializes all
 	// Lua operations through a single worker goroutine.
 	LuaExecutor LuaExecutorProvider
+
+	// Crashes is told about plugin callbacks that fail, so that plugins
+	// that keep failing can be disabled. Optional.
+	Crashes CrashReporter
+}
+
+// CrashReporter tracks failing plugin callbacks.
+type CrashReporter interface {
+	// ReportCrash records that a callback of the plugin failed with err.
+	ReportCrash(plugin string, err error)
+
+	// PluginDisabled returns true if the plugin was disabled and its
+	// callbacks must no longer run.
+	PluginDisabled(plugin string) bool
 }
 
 // LuaExecutorProvider defines the interface for thread-safe Lua execution.
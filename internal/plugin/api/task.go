@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	plua "github.com/dshills/keystorm/internal/plugin/lua"
+	"github.com/dshills/keystorm/internal/plugin/security"
+)
+
+// DefaultTaskTimeout bounds how long a ks.task computation may run when the
+// module has no ResourceMonitor.
+const DefaultTaskTimeout = 30 * time.Second
+
+var (
+	errTaskClosure    = errors.New("task function must not capture local variables; pass values as arguments")
+	errTaskLimit      = errors.New("too many running tasks")
+	errTaskTimeout    = errors.New("task timed out")
+	errTaskNoExecutor = errors.New("tasks are not available: no Lua executor to deliver results")
+)
+
+// TaskModule implements ks.task, which runs long computations off the
+// plugin's Lua goroutine.
+//
+// gopher-lua states cannot be shared between goroutines, so each task runs
+// in a fresh sandboxed state that only has the safe standard libraries and
+// none of the ks API. The task function is recompiled into that state from
+// its bytecode, which is why it may not capture local variables; its
+// arguments and results are copied between the states as plain data
+// (nil, booleans, numbers, strings and tables of those).
+//
+// Running tasks count against the ResourceMonitor's goroutine limit and
+// each task is cancelled after the monitor's execution timeout. Results are
+// delivered through the context's LuaExecutor, so the module is created per
+// plugin with that plugin's monitor and executor.
+type TaskModule struct {
+	ctx     *Context
+	monitor *security.ResourceMonitor
+}
+
+// NewTaskModule creates a new task module. The monitor may be nil, in which
+// case tasks are unlimited in number and bounded by DefaultTaskTimeout.
+func NewTaskModule(ctx *Context, monitor *security.ResourceMonitor) *TaskModule {
+	return &TaskModule{ctx: ctx, monitor: monitor}
+}
+
+// Name returns the module name.
+func (m *TaskModule) Name() string {
+	return "task"
+}
+
+// RequiredCapability returns the capability required for this module.
+// Tasks only compute and need no capability.
+func (m *TaskModule) RequiredCapability() security.Capability {
+	return ""
+}
+
+// Register registers the module into the Lua state. Unlike the other
+// modules, ks.task is a function rather than a table.
+func (m *TaskModule) Register(L *lua.LState) error {
+	L.SetGlobal("_ks_task", L.NewFunction(m.task))
+	return nil
+}
+
+// task(fn, callback, ...) -> nil
+// Runs fn(...) on a separate goroutine and calls callback with its results
+// on the plugin's Lua goroutine, or with nil and an error message if fn
+// fails or times out. Without a LuaExecutor the callback could only be
+// invoked by blocking the plugin until the task is done, so an error is
+// raised instead.
+func (m *TaskModule) task(L *lua.LState) int {
+	fn := L.CheckFunction(1)
+	callback := L.CheckFunction(2)
+	if fn.IsG {
+		L.ArgError(1, "Lua function expected")
+		return 0
+	}
+	if len(fn.Upvalues) > 0 {
+		L.ArgError(1, errTaskClosure.Error())
+		return 0
+	}
+
+	var executor LuaExecutorProvider
+	if m.ctx != nil {
+		executor = m.ctx.LuaExecutor
+	}
+	if executor == nil {
+		L.RaiseError("%s", errTaskNoExecutor.Error())
+		return 0
+	}
+
+	bridge := plua.NewBridge(L)
+	args := make([]interface{}, 0, L.GetTop()-2)
+	for i := 3; i <= L.GetTop(); i++ {
+		args = append(args, bridge.ToGoValue(L.Get(i)))
+	}
+
+	if m.monitor != nil && m.monitor.IncrementGoroutines() {
+		m.monitor.DecrementGoroutines()
+		L.RaiseError("%s", errTaskLimit.Error())
+		return 0
+	}
+
+	proto := fn.Proto
+	go func() {
+		results, err := m.run(proto, args)
+		_ = executor.ExecuteAsync(func(interface{}) error {
+			return invokeTaskCallback(L, callback, results, err)
+		})
+	}()
+	return 0
+}
+
+// timeout returns how long a task may run.
+func (m *TaskModule) timeout() time.Duration {
+	if m.monitor != nil && m.monitor.ExecutionTimeout() > 0 {
+		return m.monitor.ExecutionTimeout()
+	}
+	return DefaultTaskTimeout
+}
+
+// run executes a task function in a fresh Lua state and returns its results
+// as Go values, releasing the task's goroutine slot when done.
+func (m *TaskModule) run(proto *lua.FunctionProto, args []interface{}) ([]interface{}, error) {
+	if m.monitor != nil {
+		defer m.monitor.DecrementGoroutines()
+	}
+
+	state, err := plua.NewState()
+	if err != nil {
+		return nil, err
+	}
+	defer state.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout())
+	defer cancel()
+
+	WL := state.LuaState()
+	WL.SetContext(ctx)
+	bridge := plua.NewBridge(WL)
+
+	state.SetGlobal("_ks_task_fn", WL.NewFunctionFromProto(proto))
+	luaArgs := make([]lua.LValue, len(args))
+	for i, arg := range args {
+		luaArgs[i] = bridge.ToLuaValue(arg)
+	}
+
+	values, err := state.Call("_ks_task_fn", luaArgs...)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", errTaskTimeout, m.timeout())
+		}
+		return nil, err
+	}
+
+	results := make([]interface{}, len(values))
+	for i, value := range values {
+		results[i] = bridge.ToGoValue(value)
+	}
+	return results, nil
+}
+
+// invokeTaskCallback calls a Lua callback with task results, or with nil
+// and an error message. Must be called on the Lua state's owning goroutine.
+func invokeTaskCallback(L *lua.LState, callback *lua.LFunction, results []interface{}, err error) error {
+	if err != nil {
+		return L.CallByParam(lua.P{Fn: callback, NRet: 0, Protect: true}, lua.LNil, lua.LString(err.Error()))
+	}
+
+	bridge := plua.NewBridge(L)
+	args := make([]lua.LValue, len(results))
+	for i, result := range results {
+		args[i] = bridge.ToLuaValue(result)
+	}
+	return L.CallByParam(lua.P{Fn: callback, NRet: 0, Protect: true}, args...)
+}
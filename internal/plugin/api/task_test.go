@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"github.com/dshills/keystorm/internal/plugin/security"
+)
+
+// setupTaskTest creates a Lua state with ks.task registered.
+func setupTaskTest(t *testing.T, ctx *Context, monitor *security.ResourceMonitor) *lua.LState {
+	t.Helper()
+
+	mod := NewTaskModule(ctx, monitor)
+	L := lua.NewState()
+	t.Cleanup(L.Close)
+	if err := mod.Register(L); err != nil {
+		t.Fatalf("Register error = %v", err)
+	}
+	return L
+}
+
+// runQueued runs the next operation queued on executor.
+func runQueued(t *testing.T, L *lua.LState, executor *queueExecutor) {
+	t.Helper()
+
+	select {
+	case op := <-executor.ops:
+		if err := op(L); err != nil {
+			t.Fatalf("queued operation error = %v", err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("task result was not delivered")
+	}
+}
+
+func TestTaskModule(t *testing.T) {
+	mod := NewTaskModule(nil, nil)
+	if mod.Name() != "task" {
+		t.Errorf("Name() = %q, want %q", mod.Name(), "task")
+	}
+	if mod.RequiredCapability() != "" {
+		t.Errorf("RequiredCapability() = %q, want empty", mod.RequiredCapability())
+	}
+}
+
+func TestTaskRunsOffMainGoroutine(t *testing.T) {
+	executor := &queueExecutor{ops: make(chan func(L interface{}) error, 1)}
+	L := setupTaskTest(t, &Context{LuaExecutor: executor}, security.NewResourceMonitor(security.DefaultResourceLimits()))
+
+	// The plugin call must finish within its own short timeout while the
+	// computation keeps running
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	L.SetContext(ctx)
+	err := L.DoString(`
+		secret = "main state"
+		_ks_task(function(n, extra)
+			local sum = 0
+			for i = 1, n do sum = sum + i end
+			for _, v in ipairs(extra) do sum = sum + v end
+			return sum, {isolated = (secret == nil and _ks_task == nil)}
+		end, function(sum, info)
+			result = sum
+			isolated = info.isolated
+		end, 2000000, {1, 2})
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if L.GetGlobal("result") != lua.LNil {
+		t.Fatal("callback ran before the task was delivered")
+	}
+	L.RemoveContext()
+
+	runQueued(t, L, executor)
+	if got, want := L.GetGlobal("result"), lua.LNumber(2000000*2000001/2+3); got != want {
+		t.Errorf("result = %v, want %v", got, want)
+	}
+	if got := L.GetGlobal("isolated"); got != lua.LTrue {
+		t.Errorf("isolated = %v, want true", got)
+	}
+}
+
+func TestTaskError(t *testing.T) {
+	executor := &queueExecutor{ops: make(chan func(L interface{}) error, 1)}
+	L := setupTaskTest(t, &Context{LuaExecutor: executor}, nil)
+
+	err := L.DoString(`
+		_ks_task(function() error("boom") end, function(value, err)
+			result = value
+			message = err
+		end)
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	runQueued(t, L, executor)
+	if got := L.GetGlobal("result"); got != lua.LNil {
+		t.Errorf("result = %v, want nil", got)
+	}
+	if got := L.GetGlobal("message").String(); !strings.Contains(got, "boom") {
+		t.Errorf("message = %q, want it to contain %q", got, "boom")
+	}
+}
+
+func TestTaskRequiresExecutor(t *testing.T) {
+	L := setupTaskTest(t, &Context{}, nil)
+
+	err := L.DoString(`_ks_task(function() return 1 end, function() result = true end)`)
+	if err == nil || !strings.Contains(err.Error(), "no Lua executor") {
+		t.Errorf("DoString error = %v, want executor error", err)
+	}
+	if got := L.GetGlobal("result"); got != lua.LNil {
+		t.Errorf("result = %v, want the task not to run", got)
+	}
+}
+
+func TestTaskRejectsClosures(t *testing.T) {
+	L := setupTaskTest(t, &Context{}, nil)
+
+	err := L.DoString(`
+		local n = 10
+		_ks_task(function() return n end, function() end)
+	`)
+	if err == nil || !strings.Contains(err.Error(), "capture local variables") {
+		t.Errorf("DoString error = %v, want closure error", err)
+	}
+}
+
+func TestTaskTimeout(t *testing.T) {
+	limits := security.DefaultResourceLimits()
+	limits.ExecutionTimeout = 50 * time.Millisecond
+	executor := &queueExecutor{ops: make(chan func(L interface{}) error, 1)}
+	L := setupTaskTest(t, &Context{LuaExecutor: executor}, security.NewResourceMonitor(limits))
+
+	err := L.DoString(`
+		_ks_task(function() while true do end end, function(value, err)
+			message = err
+		end)
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	runQueued(t, L, executor)
+	if got := L.GetGlobal("message").String(); !strings.Contains(got, "timed out") {
+		t.Errorf("message = %q, want timeout", got)
+	}
+}
+
+func TestTaskGoroutineLimit(t *testing.T) {
+	limits := security.DefaultResourceLimits()
+	limits.ExecutionTimeout = 100 * time.Millisecond
+	limits.MaxGoroutines = 1
+	monitor := security.NewResourceMonitor(limits)
+	executor := &queueExecutor{ops: make(chan func(L interface{}) error, 1)}
+	L := setupTaskTest(t, &Context{LuaExecutor: executor}, monitor)
+
+	spin := `_ks_task(function() while true do end end, function(value, err) message = err end)`
+	if err := L.DoString(spin); err != nil {
+		t.Fatalf("first task error = %v", err)
+	}
+	if err := L.DoString(spin); err == nil || !strings.Contains(err.Error(), "too many running tasks") {
+		t.Errorf("second task error = %v, want limit error", err)
+	}
+
+	// The slot is released once the first task ends
+	runQueued(t, L, executor)
+	if got := monitor.GoroutineCount(); got != 0 {
+		t.Errorf("GoroutineCount() = %d, want 0", got)
+	}
+}
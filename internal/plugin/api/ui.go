@@ -6,6 +6,7 @@ import (
 
 	lua "github.com/yuin/gopher-lua"
 
+	"github.com/dshills/keystorm/internal/input/fuzzy"
 	"github.com/dshills/keystorm/internal/plugin/security"
 )
 
@@ -68,6 +69,92 @@ type UIProvider interface {
 	CloseOverlay(id string) error
 }
 
+// AsyncUIProvider is implemented by UI providers that can show prompts and
+// pickers without blocking the caller. When the context's UIProvider
+// implements it and a LuaExecutor is available, ks.ui.prompt and
+// ks.ui.quickpick return immediately and invoke their Lua callback once the
+// user answers; otherwise they fall back to Input and Select.
+type AsyncUIProvider interface {
+	// Prompt shows a single-line input prompt. done must be called exactly
+	// once, from any goroutine, with the entered text, or ok false if the
+	// prompt was cancelled.
+	Prompt(question string, opts PromptOptions, done func(value string, ok bool))
+
+	// QuickPick shows a list the user narrows by typing, using pick.Filter.
+	// done must be called exactly once, from any goroutine, with the index
+	// of the chosen item in pick.Items, or ok false if cancelled.
+	QuickPick(pick *QuickPick, done func(index int, ok bool))
+}
+
+// PromptOptions configures a single-line input prompt.
+type PromptOptions struct {
+	// Default is the initial text of the input.
+	Default string
+
+	// Placeholder is shown while the input is empty.
+	Placeholder string
+}
+
+// QuickPick is a list of items filtered by fuzzy matching as the user types.
+type QuickPick struct {
+	// Title is shown above the list.
+	Title string
+
+	// Placeholder is shown while the filter query is empty.
+	Placeholder string
+
+	// Items are the entries to pick from.
+	Items []string
+
+	matcher *fuzzy.Matcher
+	items   []fuzzy.Item
+}
+
+// QuickPickMatch is an item matching a QuickPick filter query.
+type QuickPickMatch struct {
+	// Index is the item's index in QuickPick.Items.
+	Index int
+
+	// Text is the item text.
+	Text string
+
+	// Matches contains the rune indices of matched characters.
+	Matches []int
+}
+
+// NewQuickPick creates a quick pick over items.
+func NewQuickPick(items []string, opts SelectOptions) *QuickPick {
+	fuzzyItems := make([]fuzzy.Item, len(items))
+	for i, item := range items {
+		fuzzyItems[i] = fuzzy.Item{Text: item, Data: i}
+	}
+
+	// Queries are only cached per quick pick, as the items differ
+	return &QuickPick{
+		Title:       opts.Title,
+		Placeholder: opts.Placeholder,
+		Items:       items,
+		matcher:     fuzzy.NewMatcher(fuzzy.Options{CacheSize: 64}),
+		items:       fuzzyItems,
+	}
+}
+
+// Filter returns up to limit items matching query, best match first.
+// An empty query returns the items in their original order. A limit of 0
+// returns all matches.
+func (p *QuickPick) Filter(query string, limit int) []QuickPickMatch {
+	results := p.matcher.Match(query, p.items, limit)
+	matches := make([]QuickPickMatch, len(results))
+	for i, r := range results {
+		matches[i] = QuickPickMatch{
+			Index:   r.Item.Data.(int),
+			Text:    r.Item.Text,
+			Matches: r.Matches,
+		}
+	}
+	return matches
+}
+
 // SelectOptions configures a selection menu.
 type SelectOptions struct {
 	Title       string
@@ -87,6 +174,10 @@ type OverlayOptions struct {
 }
 
 // UIModule implements the ks.ui API module.
+//
+// ks.ui.prompt and ks.ui.quickpick take a callback that receives the
+// user's answer, or nil if cancelled. With an AsyncUIProvider the callback
+// runs later on the plugin's goroutine via the context's LuaExecutor.
 type UIModule struct {
 	ctx        *Context
 	pluginName string
@@ -95,6 +186,10 @@ type UIModule struct {
 	// Track overlays for cleanup
 	mu       sync.Mutex
 	overlays map[string]bool
+
+	// generation is incremented by Cleanup; callbacks of prompts shown
+	// before then are dropped
+	generation uint64
 }
 
 // NewUIModule creates a new UI module.
@@ -127,11 +222,16 @@ func (m *UIModule) Register(L *lua.LState) error {
 	L.SetField(mod, "input", L.NewFunction(m.input))
 	L.SetField(mod, "select", L.NewFunction(m.selectMenu))
 	L.SetField(mod, "confirm", L.NewFunction(m.confirm))
+	L.SetField(mod, "prompt", L.NewFunction(m.prompt))
+	L.SetField(mod, "quickpick", L.NewFunction(m.quickpick))
 
-	// Create statusline sub-module
+	// Create statusline sub-module; calling it sets the left segment
 	statusline := L.NewTable()
 	L.SetField(statusline, "set", L.NewFunction(m.statuslineSet))
 	L.SetField(statusline, "clear", L.NewFunction(m.statuslineClear))
+	statuslineMeta := L.NewTable()
+	L.SetField(statuslineMeta, "__call", L.NewFunction(m.statuslineCall))
+	L.SetMetatable(statusline, statuslineMeta)
 	L.SetField(mod, "statusline", statusline)
 
 	// Create overlay sub-module
@@ -161,6 +261,8 @@ func (m *UIModule) Cleanup() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	m.generation++
+
 	if m.ctx.UI == nil {
 		return
 	}
@@ -297,6 +399,160 @@ func (m *UIModule) confirm(L *lua.LState) int {
 	return 1
 }
 
+// prompt(question, opts?, callback) -> nil
+// Prompts for a single line of input. opts may be a table with "default"
+// and "placeholder" fields, or the default text. The callback receives the
+// entered text, or nil if cancelled.
+func (m *UIModule) prompt(L *lua.LState) int {
+	callback := popCallback(L)
+	question := L.CheckString(1)
+	if callback == nil {
+		L.ArgError(L.GetTop()+1, "callback function expected")
+		return 0
+	}
+
+	var opts PromptOptions
+	switch v := L.Get(2).(type) {
+	case lua.LString:
+		opts.Default = string(v)
+	case *lua.LTable:
+		opts.Default = getTableString(L, v, "default")
+		opts.Placeholder = getTableString(L, v, "placeholder")
+	}
+
+	if m.ctx.UI == nil {
+		m.invokeCallback(L, callback, lua.LNil)
+		return 0
+	}
+
+	if async, ok := m.ctx.UI.(AsyncUIProvider); ok && m.ctx.LuaExecutor != nil {
+		var once sync.Once
+		gen := m.currentGeneration()
+		async.Prompt(question, opts, func(value string, ok bool) {
+			once.Do(func() {
+				m.deliver(L, callback, gen, func() []lua.LValue {
+					if !ok {
+						return []lua.LValue{lua.LNil}
+					}
+					return []lua.LValue{lua.LString(value)}
+				})
+			})
+		})
+		return 0
+	}
+
+	// Input reports cancellation as an error or empty result
+	value, err := m.ctx.UI.Input(question, opts.Default)
+	if err != nil || value == "" {
+		m.invokeCallback(L, callback, lua.LNil)
+		return 0
+	}
+	m.invokeCallback(L, callback, lua.LString(value))
+	return 0
+}
+
+// quickpick(items, opts?, callback) -> nil
+// Shows a fuzzy-filtered list of items. The callback receives the chosen
+// item and its 1-based index, or nil if cancelled.
+func (m *UIModule) quickpick(L *lua.LState) int {
+	callback := popCallback(L)
+	itemsTable := L.CheckTable(1)
+	if callback == nil {
+		L.ArgError(L.GetTop()+1, "callback function expected")
+		return 0
+	}
+
+	var items []string
+	itemsTable.ForEach(func(_, value lua.LValue) {
+		if str, ok := value.(lua.LString); ok {
+			items = append(items, string(str))
+		}
+	})
+
+	opts := SelectOptions{}
+	if optsTable, ok := L.Get(2).(*lua.LTable); ok {
+		opts.Title = getTableString(L, optsTable, "title")
+		opts.Placeholder = getTableString(L, optsTable, "placeholder")
+	}
+
+	if m.ctx.UI == nil || len(items) == 0 {
+		m.invokeCallback(L, callback, lua.LNil)
+		return 0
+	}
+
+	pick := NewQuickPick(items, opts)
+	result := func(idx int, ok bool) []lua.LValue {
+		if !ok || idx < 0 || idx >= len(items) {
+			return []lua.LValue{lua.LNil}
+		}
+		return []lua.LValue{lua.LString(items[idx]), lua.LNumber(idx + 1)}
+	}
+
+	if async, ok := m.ctx.UI.(AsyncUIProvider); ok && m.ctx.LuaExecutor != nil {
+		var once sync.Once
+		gen := m.currentGeneration()
+		async.QuickPick(pick, func(idx int, ok bool) {
+			once.Do(func() {
+				m.deliver(L, callback, gen, func() []lua.LValue { return result(idx, ok) })
+			})
+		})
+		return 0
+	}
+
+	idx, err := m.ctx.UI.Select(items, opts)
+	m.invokeCallback(L, callback, result(idx, err == nil)...)
+	return 0
+}
+
+// currentGeneration returns the module's cleanup generation.
+func (m *UIModule) currentGeneration() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.generation
+}
+
+// deliver queues callback to run with the values from args on the Lua
+// state's goroutine. The callback is dropped if the module has been
+// cleaned up since generation gen, as the plugin was deactivated.
+func (m *UIModule) deliver(L *lua.LState, callback *lua.LFunction, gen uint64, args func() []lua.LValue) {
+	_ = m.ctx.LuaExecutor.ExecuteAsync(func(interface{}) error {
+		if m.currentGeneration() != gen {
+			return nil
+		}
+		return L.CallByParam(lua.P{Fn: callback, NRet: 0, Protect: true}, args()...)
+	})
+}
+
+// invokeCallback calls callback with args. It must be called on the Lua
+// state's goroutine.
+func (m *UIModule) invokeCallback(L *lua.LState, callback *lua.LFunction, args ...lua.LValue) {
+	_ = L.CallByParam(lua.P{Fn: callback, NRet: 0, Protect: true}, args...)
+}
+
+// statuslineCall(self, text) -> nil
+// Sets the plugin's left statusline segment when ks.ui.statusline is called
+// directly. Empty text clears it.
+func (m *UIModule) statuslineCall(L *lua.LState) int {
+	content := L.OptString(2, "")
+
+	if m.ctx.UI == nil {
+		return 0
+	}
+
+	segment := "plugin:" + m.pluginName
+
+	var err error
+	if content == "" {
+		err = m.ctx.UI.ClearStatusline(StatuslineLeft, segment)
+	} else {
+		err = m.ctx.UI.SetStatusline(StatuslineLeft, segment, content)
+	}
+	if err != nil {
+		L.RaiseError("statusline: %v", err)
+	}
+	return 0
+}
+
 // statuslineSet(position, content) -> nil
 // Sets content in a statusline segment for this plugin.
 func (m *UIModule) statuslineSet(L *lua.LState) int {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	lua "github.com/yuin/gopher-lua"
 
@@ -744,3 +745,209 @@ func TestUINilProvider(t *testing.T) {
 		t.Error("overlay.create should error with nil provider")
 	}
 }
+
+// asyncMockUIProvider records async prompts and quick picks so tests can
+// answer them later.
+type asyncMockUIProvider struct {
+	*mockUIProvider
+
+	prompts    []string
+	promptDone func(value string, ok bool)
+	picks      []*QuickPick
+	pickDone   func(index int, ok bool)
+}
+
+func (m *asyncMockUIProvider) Prompt(question string, opts PromptOptions, done func(value string, ok bool)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prompts = append(m.prompts, question)
+	m.promptDone = done
+}
+
+func (m *asyncMockUIProvider) QuickPick(pick *QuickPick, done func(index int, ok bool)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.picks = append(m.picks, pick)
+	m.pickDone = done
+}
+
+func setupAsyncUITest(t *testing.T) (*lua.LState, *UIModule, *asyncMockUIProvider, *queueExecutor) {
+	t.Helper()
+
+	up := &asyncMockUIProvider{mockUIProvider: newMockUIProvider()}
+	executor := &queueExecutor{ops: make(chan func(L interface{}) error, 4)}
+	mod := NewUIModule(&Context{UI: up, LuaExecutor: executor}, "testplugin")
+
+	L := lua.NewState()
+	t.Cleanup(func() { L.Close() })
+	if err := mod.Register(L); err != nil {
+		t.Fatalf("Register error = %v", err)
+	}
+	return L, mod, up, executor
+}
+
+func TestUIQuickPickCallback(t *testing.T) {
+	L, _, up, executor := setupAsyncUITest(t)
+
+	err := L.DoString(`
+		_ks_ui.quickpick({"main.go", "README.md", "go.mod"}, {title = "Files"}, function(item, index)
+			picked, pickedIndex = item, index
+		end)
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if len(up.picks) != 1 || up.picks[0].Title != "Files" {
+		t.Fatalf("picks = %+v, want one titled Files", up.picks)
+	}
+	if got := L.GetGlobal("picked"); got != lua.LNil {
+		t.Fatalf("callback ran before selection, picked = %v", got)
+	}
+
+	// The provider answers from its own goroutine; only the first answer counts
+	go func() {
+		up.pickDone(2, true)
+		up.pickDone(0, true)
+	}()
+	op := <-executor.ops
+	if err := op(L); err != nil {
+		t.Fatalf("callback error = %v", err)
+	}
+	select {
+	case <-executor.ops:
+		t.Error("second answer should not queue a callback")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if got := L.GetGlobal("picked"); got.String() != "go.mod" {
+		t.Errorf("picked = %v, want go.mod", got)
+	}
+	if got := L.GetGlobal("pickedIndex"); got != lua.LNumber(3) {
+		t.Errorf("pickedIndex = %v, want 3", got)
+	}
+}
+
+func TestUIQuickPickCancelAfterCleanup(t *testing.T) {
+	L, mod, up, executor := setupAsyncUITest(t)
+
+	err := L.DoString(`
+		called = false
+		_ks_ui.quickpick({"a", "b"}, function() called = true end)
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+
+	mod.Cleanup()
+	up.pickDone(0, true)
+	op := <-executor.ops
+	if err := op(L); err != nil {
+		t.Fatalf("callback error = %v", err)
+	}
+	if L.GetGlobal("called") != lua.LFalse {
+		t.Error("callback should not run after Cleanup")
+	}
+
+	// Picks shown after Cleanup, once the plugin is reactivated, are answered
+	if err := L.DoString(`_ks_ui.quickpick({"a", "b"}, function() called = true end)`); err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	up.pickDone(1, true)
+	op = <-executor.ops
+	if err := op(L); err != nil {
+		t.Fatalf("callback error = %v", err)
+	}
+	if L.GetGlobal("called") != lua.LTrue {
+		t.Error("callback of a pick shown after Cleanup should run")
+	}
+}
+
+func TestUIQuickPickSyncFallback(t *testing.T) {
+	up := newMockUIProvider()
+	up.selectResponse = 0
+	L, _ := setupUITest(t, up)
+
+	err := L.DoString(`
+		_ks_ui.quickpick({"first", "second"}, function(item, index)
+			picked, pickedIndex = item, index
+		end)
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if got := L.GetGlobal("picked"); got.String() != "first" {
+		t.Errorf("picked = %v, want first", got)
+	}
+	if got := L.GetGlobal("pickedIndex"); got != lua.LNumber(1) {
+		t.Errorf("pickedIndex = %v, want 1", got)
+	}
+}
+
+func TestUIPromptCallback(t *testing.T) {
+	L, _, up, executor := setupAsyncUITest(t)
+
+	err := L.DoString(`
+		answer = "unset"
+		_ks_ui.prompt("Name?", function(value) answer = value end)
+	`)
+	if err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if len(up.prompts) != 1 || up.prompts[0] != "Name?" {
+		t.Fatalf("prompts = %v, want [Name?]", up.prompts)
+	}
+
+	// Cancelling delivers nil
+	up.promptDone("", false)
+	op := <-executor.ops
+	if err := op(L); err != nil {
+		t.Fatalf("callback error = %v", err)
+	}
+	if got := L.GetGlobal("answer"); got != lua.LNil {
+		t.Errorf("answer = %v, want nil", got)
+	}
+}
+
+func TestUIPromptRequiresCallback(t *testing.T) {
+	L, _ := setupUITest(t, newMockUIProvider())
+
+	if err := L.DoString(`_ks_ui.prompt("Name?")`); err == nil {
+		t.Error("prompt without callback should error")
+	}
+}
+
+func TestUIStatuslineCall(t *testing.T) {
+	up := newMockUIProvider()
+	L, _ := setupUITest(t, up)
+
+	if err := L.DoString(`_ks_ui.statusline("3 errors")`); err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if got, _ := up.GetStatusline(StatuslineLeft, "plugin:testplugin"); got != "3 errors" {
+		t.Errorf("statusline = %q, want %q", got, "3 errors")
+	}
+
+	if err := L.DoString(`_ks_ui.statusline("")`); err != nil {
+		t.Fatalf("DoString error = %v", err)
+	}
+	if _, ok := up.GetStatusline(StatuslineLeft, "plugin:testplugin"); ok {
+		t.Error("statusline segment should be cleared")
+	}
+}
+
+func TestQuickPickFilter(t *testing.T) {
+	pick := NewQuickPick([]string{"main.go", "README.md", "go.mod"}, SelectOptions{})
+
+	all := pick.Filter("", 0)
+	if len(all) != 3 || all[0].Index != 0 || all[2].Index != 2 {
+		t.Errorf("Filter(\"\") = %+v, want all items in order", all)
+	}
+
+	matches := pick.Filter("gomod", 0)
+	if len(matches) == 0 || matches[0].Text != "go.mod" || matches[0].Index != 2 {
+		t.Fatalf("Filter(gomod) = %+v, want go.mod first", matches)
+	}
+	if len(matches[0].Matches) != 5 {
+		t.Errorf("Matches = %v, want 5 rune indices", matches[0].Matches)
+	}
+}
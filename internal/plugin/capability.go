@@ -0,0 +1,125 @@
+package plugin
+
+import (
+	"context"
+	"slices"
+
+	plua "github.com/dshills/keystorm/internal/plugin/lua"
+	"github.com/dshills/keystorm/internal/plugin/security"
+)
+
+// CapabilityDecision is the user's answer to a capability prompt.
+type CapabilityDecision int
+
+const (
+	// CapabilityDeny withholds the capability for this load. The plugin
+	// still loads, without the capability or the API modules requiring it.
+	CapabilityDeny CapabilityDecision = iota
+
+	// CapabilityAllowOnce grants the capability until the plugin is
+	// unloaded. The user is asked again the next time it loads.
+	CapabilityAllowOnce
+
+	// CapabilityAllowAlways grants the capability and records it in the
+	// manager's granted capabilities so the user is not asked again.
+	CapabilityAllowAlways
+)
+
+// String returns a string representation of the decision.
+func (d CapabilityDecision) String() string {
+	switch d {
+	case CapabilityDeny:
+		return "deny"
+	case CapabilityAllowOnce:
+		return "allow once"
+	case CapabilityAllowAlways:
+		return "allow always"
+	default:
+		return "unknown"
+	}
+}
+
+// CapabilityRequest describes a capability a plugin requests in its
+// manifest but has not been granted.
+type CapabilityRequest struct {
+	// Plugin is the name of the requesting plugin.
+	Plugin string
+
+	// Capability is the requested capability.
+	Capability plua.Capability
+
+	// Info describes the capability for display (name, description,
+	// risk level). It is zero for capabilities unknown to the security
+	// package.
+	Info security.CapabilityInfo
+}
+
+// CapabilityPrompter asks the user whether a plugin may use a capability,
+// much like a browser permission prompt. It is called without manager
+// locks held and may block until the user answers; ctx is the context of
+// the load operation.
+type CapabilityPrompter func(ctx context.Context, req CapabilityRequest) CapabilityDecision
+
+// GrantedCapabilities returns the capabilities permanently granted to a
+// plugin, from ManagerConfig.GrantedCapabilities and "allow always"
+// decisions.
+func (m *Manager) GrantedCapabilities(plugin string) []plua.Capability {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.config.GrantedCapabilities[plugin])
+}
+
+// resolveCapabilities returns the capabilities to grant a plugin. Without
+// a prompter every capability in the manifest is granted; otherwise the
+// prompter is asked about each capability that was not granted before.
+func (m *Manager) resolveCapabilities(ctx context.Context, manifest *Manifest) []plua.Capability {
+	m.mu.RLock()
+	prompter := m.config.CapabilityPrompter
+	granted := slices.Clone(m.config.GrantedCapabilities[manifest.Name])
+	m.mu.RUnlock()
+
+	if prompter == nil {
+		return slices.Clone(manifest.Capabilities)
+	}
+
+	caps := make([]plua.Capability, 0, len(manifest.Capabilities))
+	for _, cap := range manifest.Capabilities {
+		if slices.Contains(granted, cap) {
+			caps = append(caps, cap)
+			continue
+		}
+
+		info, _ := security.GetCapabilityInfo(security.Capability(cap))
+		decision := prompter(ctx, CapabilityRequest{
+			Plugin:     manifest.Name,
+			Capability: cap,
+			Info:       info,
+		})
+		switch decision {
+		case CapabilityAllowAlways:
+			m.grantCapability(manifest.Name, cap)
+			caps = append(caps, cap)
+		case CapabilityAllowOnce:
+			caps = append(caps, cap)
+		}
+	}
+	return caps
+}
+
+// grantCapability records a permanent grant and reports it through
+// ManagerConfig.OnCapabilityGranted so it can be persisted.
+func (m *Manager) grantCapability(plugin string, cap plua.Capability) {
+	m.mu.Lock()
+	if m.config.GrantedCapabilities == nil {
+		m.config.GrantedCapabilities = make(map[string][]plua.Capability)
+	}
+	if !slices.Contains(m.config.GrantedCapabilities[plugin], cap) {
+		m.config.GrantedCapabilities[plugin] = append(m.config.GrantedCapabilities[plugin], cap)
+	}
+	onGranted := m.config.OnCapabilityGranted
+	m.mu.Unlock()
+
+	if onGranted != nil {
+		onGranted(plugin, cap)
+	}
+}
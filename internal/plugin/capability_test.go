@@ -0,0 +1,203 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+
+	plua "github.com/dshills/keystorm/internal/plugin/lua"
+	"github.com/dshills/keystorm/internal/plugin/security"
+)
+
+// createCapabilityPlugin creates a plugin whose manifest requests caps.
+func createCapabilityPlugin(t *testing.T, dir string, caps ...string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	quoted := make([]string, len(caps))
+	for i, c := range caps {
+		quoted[i] = `"` + c + `"`
+	}
+	manifest := `{
+		"name": "` + filepath.Base(dir) + `",
+		"version": "1.0.0",
+		"main": "init.lua",
+		"capabilities": [` + strings.Join(quoted, ",") + `]
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "plugin.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "init.lua"), []byte("-- empty"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// mockPrompter answers capability prompts from a fixed table and records
+// the requests it receives.
+type mockPrompter struct {
+	answers  map[plua.Capability]CapabilityDecision
+	requests []CapabilityRequest
+}
+
+func (p *mockPrompter) prompt(ctx context.Context, req CapabilityRequest) CapabilityDecision {
+	p.requests = append(p.requests, req)
+	return p.answers[req.Capability]
+}
+
+func (p *mockPrompter) asked() []plua.Capability {
+	caps := make([]plua.Capability, len(p.requests))
+	for i, r := range p.requests {
+		caps[i] = r.Capability
+	}
+	return caps
+}
+
+func TestManagerCapabilityPrompt(t *testing.T) {
+	tmpDir := t.TempDir()
+	createCapabilityPlugin(t, filepath.Join(tmpDir, "net-plugin"), "network", "clipboard", "shell")
+
+	prompter := &mockPrompter{answers: map[plua.Capability]CapabilityDecision{
+		plua.CapabilityNetwork:   CapabilityAllowAlways,
+		plua.CapabilityClipboard: CapabilityAllowOnce,
+		plua.CapabilityShell:     CapabilityDeny,
+	}}
+	var persisted []plua.Capability
+
+	config := DefaultManagerConfig()
+	config.PluginPaths = []string{tmpDir}
+	config.AutoActivate = false
+	config.CapabilityPrompter = prompter.prompt
+	config.OnCapabilityGranted = func(plugin string, cap plua.Capability) {
+		if plugin != "net-plugin" {
+			t.Errorf("OnCapabilityGranted plugin = %q, want %q", plugin, "net-plugin")
+		}
+		persisted = append(persisted, cap)
+	}
+	m := NewManager(config)
+	ctx := context.Background()
+
+	host, err := m.Load(ctx, "net-plugin")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := []plua.Capability{plua.CapabilityNetwork, plua.CapabilityClipboard, plua.CapabilityShell}
+	if got := prompter.asked(); !slices.Equal(got, want) {
+		t.Errorf("prompted for %v, want %v", got, want)
+	}
+	if info := prompter.requests[0].Info; info.RiskLevel != security.RiskHigh {
+		t.Errorf("network prompt RiskLevel = %v, want %v", info.RiskLevel, security.RiskHigh)
+	}
+
+	// Denial leaves the plugin loaded without the capability.
+	if host.State() != StateLoaded {
+		t.Errorf("State() = %v, want %v", host.State(), StateLoaded)
+	}
+	if got, want := host.Capabilities(), []plua.Capability{plua.CapabilityNetwork, plua.CapabilityClipboard}; !slices.Equal(got, want) {
+		t.Errorf("Capabilities() = %v, want %v", got, want)
+	}
+
+	// Only "allow always" is persisted.
+	if want := []plua.Capability{plua.CapabilityNetwork}; !slices.Equal(persisted, want) {
+		t.Errorf("persisted %v, want %v", persisted, want)
+	}
+	if got, want := m.GrantedCapabilities("net-plugin"), []plua.Capability{plua.CapabilityNetwork}; !slices.Equal(got, want) {
+		t.Errorf("GrantedCapabilities() = %v, want %v", got, want)
+	}
+
+	// On the next load only the capabilities not granted permanently are
+	// asked about again.
+	if err := m.Unload(ctx, "net-plugin"); err != nil {
+		t.Fatalf("Unload failed: %v", err)
+	}
+	prompter.requests = nil
+	if _, err := m.Load(ctx, "net-plugin"); err != nil {
+		t.Fatalf("second Load failed: %v", err)
+	}
+	if got, want := prompter.asked(), []plua.Capability{plua.CapabilityClipboard, plua.CapabilityShell}; !slices.Equal(got, want) {
+		t.Errorf("second load prompted for %v, want %v", got, want)
+	}
+}
+
+func TestManagerCapabilityNoPrompter(t *testing.T) {
+	tmpDir := t.TempDir()
+	createCapabilityPlugin(t, filepath.Join(tmpDir, "net-plugin"), "network")
+
+	config := DefaultManagerConfig()
+	config.PluginPaths = []string{tmpDir}
+	config.AutoActivate = false
+	m := NewManager(config)
+
+	host, err := m.Load(context.Background(), "net-plugin")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got, want := host.Capabilities(), []plua.Capability{plua.CapabilityNetwork}; !slices.Equal(got, want) {
+		t.Errorf("Capabilities() = %v, want %v", got, want)
+	}
+}
+
+// netModule is an API module that requires the network capability.
+type netModule struct{}
+
+func (netModule) Name() string                            { return "net" }
+func (netModule) RequiredCapability() security.Capability { return security.CapabilityNetwork }
+func (netModule) Register(L *lua.LState) error {
+	L.SetGlobal("net_module", lua.LTrue)
+	return nil
+}
+
+func TestSystemCapabilityPrompt(t *testing.T) {
+	tests := []struct {
+		decision   CapabilityDecision
+		wantModule bool
+	}{
+		{CapabilityAllowOnce, true},
+		{CapabilityAllowAlways, true},
+		{CapabilityDeny, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.decision.String(), func(t *testing.T) {
+			tmpDir := t.TempDir()
+			createCapabilityPlugin(t, filepath.Join(tmpDir, "net-plugin"), "network")
+
+			prompter := &mockPrompter{answers: map[plua.Capability]CapabilityDecision{
+				plua.CapabilityNetwork: tt.decision,
+			}}
+			config := DefaultSystemConfig()
+			config.ManagerConfig.PluginPaths = []string{tmpDir}
+			config.ManagerConfig.AutoActivate = false
+			config.CapabilityPrompter = prompter.prompt
+
+			sys := NewSystem(config)
+			if err := sys.Initialize(); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+			defer sys.Shutdown(context.Background())
+			if err := sys.Registry().Register(netModule{}); err != nil {
+				t.Fatalf("Register failed: %v", err)
+			}
+
+			host, err := sys.LoadPlugin(context.Background(), "net-plugin")
+			if err != nil {
+				t.Fatalf("LoadPlugin failed: %v", err)
+			}
+			if len(prompter.requests) != 1 {
+				t.Fatalf("got %d prompts, want 1", len(prompter.requests))
+			}
+
+			injected := host.GetGlobal("net_module") == true
+			if injected != tt.wantModule {
+				t.Errorf("net module injected = %v, want %v", injected, tt.wantModule)
+			}
+		})
+	}
+}
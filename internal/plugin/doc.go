@@ -85,6 +85,13 @@
 //   - process.spawn: Spawn external processes
 //   - unsafe: Full access (disables sandbox restrictions)
 //
+// When SystemConfig.CapabilityPrompter is set, the manager asks it about
+// each requested capability that is not in ManagerConfig.GrantedCapabilities
+// before loading the plugin. The host application can prompt the user to
+// allow once, allow always (recorded and reported through
+// OnCapabilityGranted for persistence) or deny. A denied capability leaves
+// the plugin loaded without it or the API modules that require it.
+//
 // # Plugin Lifecycle
 //
 // Plugins go through these states:
@@ -94,6 +101,12 @@
 //	StateActive -> Deactivate() -> StateLoaded
 //	StateLoaded -> Unload() -> StateUnloaded
 //
+// A plugin whose event handlers raise errors, or whose sandbox recovers a
+// panic, MaxCrashes times within CrashWindow (3 times a minute by default)
+// is disabled: its handlers stop running, it is deactivated, and
+// EventPluginDisabled is emitted. Manager.PluginHealth reports the crash
+// count and last error; activating the plugin again re-enables it.
+//
 // # Architecture
 //
 // The plugin system consists of several components:
@@ -0,0 +1,122 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Default crash limits.
+const (
+	// DefaultMaxCrashes is the number of crashes within DefaultCrashWindow
+	// after which a plugin is deactivated.
+	DefaultMaxCrashes = 3
+
+	// DefaultCrashWindow is the period crashes are counted over.
+	DefaultCrashWindow = time.Minute
+)
+
+// PluginHealth describes the crashes of a plugin.
+type PluginHealth struct {
+	// CrashCount is the number of crashes since the plugin was loaded or
+	// last activated by Activate.
+	CrashCount int
+
+	// LastError is the error of the most recent crash.
+	LastError error
+
+	// LastCrash is the time of the most recent crash.
+	LastCrash time.Time
+
+	// Disabled is true if the plugin was deactivated for crashing too
+	// often. Its callbacks no longer run until it is activated again.
+	Disabled bool
+}
+
+// pluginHealth tracks the crashes of a plugin.
+type pluginHealth struct {
+	PluginHealth
+
+	// recent holds the times of crashes within the crash window
+	recent []time.Time
+}
+
+// ReportCrash records that the plugin crashed with err, such as a panic
+// recovered by its sandbox or an event handler raising an error. A plugin
+// that crashes MaxCrashes times within CrashWindow is disabled: its
+// callbacks stop running at once, and it is deactivated in the background
+// before EventPluginDisabled is emitted.
+func (m *Manager) ReportCrash(name string, err error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	if _, exists := m.plugins[name]; !exists {
+		m.mu.Unlock()
+		return
+	}
+	h := m.health[name]
+	if h == nil {
+		h = &pluginHealth{}
+		m.health[name] = h
+	}
+	h.CrashCount++
+	h.LastError = err
+	h.LastCrash = now
+
+	// Forget crashes that fell out of the window
+	recent := h.recent[:0]
+	for _, t := range h.recent {
+		if now.Sub(t) < m.config.CrashWindow {
+			recent = append(recent, t)
+		}
+	}
+	h.recent = append(recent, now)
+
+	disable := !h.Disabled && m.config.MaxCrashes > 0 && len(h.recent) >= m.config.MaxCrashes
+	if disable {
+		h.Disabled = true
+	}
+	crashes := len(h.recent)
+	m.mu.Unlock()
+
+	m.emitEvent(ManagerEvent{Type: EventPluginError, Plugin: name, Error: err})
+
+	if disable {
+		reason := fmt.Errorf("plugin %q crashed %d times within %v: %w", name, crashes, m.config.CrashWindow, err)
+		// The crash may be reported while the plugin is running, so
+		// deactivate it once it returns
+		go m.disable(name, reason)
+	}
+}
+
+// disable deactivates a plugin that crashed too often.
+func (m *Manager) disable(name string, reason error) {
+	if host, ok := m.Get(name); ok && host.State() == StateActive {
+		if err := host.Deactivate(context.Background()); err != nil {
+			m.emitEvent(ManagerEvent{Type: EventPluginError, Plugin: name, Error: err})
+		}
+	}
+	m.emitEvent(ManagerEvent{Type: EventPluginDisabled, Plugin: name, Error: reason})
+}
+
+// PluginDisabled returns true if the plugin was disabled for crashing too
+// often.
+func (m *Manager) PluginDisabled(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	h := m.health[name]
+	return h != nil && h.Disabled
+}
+
+// PluginHealth returns the crash history of a loaded plugin. Plugins that
+// never crashed, and unknown plugins, report no crashes.
+func (m *Manager) PluginHealth(name string) PluginHealth {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if h := m.health[name]; h != nil {
+		return h.PluginHealth
+	}
+	return PluginHealth{}
+}
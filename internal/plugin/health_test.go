@@ -0,0 +1,209 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"github.com/dshills/keystorm/internal/event/events"
+	"github.com/dshills/keystorm/internal/plugin/api"
+)
+
+// testEventProvider delivers events synchronously on the emitting goroutine.
+type testEventProvider struct {
+	mu       sync.Mutex
+	handlers map[string]func(data map[string]any)
+	types    map[string]string
+	nextID   int
+}
+
+func newTestEventProvider() *testEventProvider {
+	return &testEventProvider{
+		handlers: make(map[string]func(data map[string]any)),
+		types:    make(map[string]string),
+	}
+}
+
+func (p *testEventProvider) Subscribe(eventType string, handler func(data map[string]any)) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nextID++
+	id := fmt.Sprintf("sub-%d", p.nextID)
+	p.handlers[id] = handler
+	p.types[id] = eventType
+	return id
+}
+
+func (p *testEventProvider) Unsubscribe(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.handlers[id]
+	delete(p.handlers, id)
+	delete(p.types, id)
+	return ok
+}
+
+func (p *testEventProvider) Emit(eventType string, data map[string]any) {
+	p.mu.Lock()
+	var handlers []func(data map[string]any)
+	for id, handler := range p.handlers {
+		if p.types[id] == eventType {
+			handlers = append(handlers, handler)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(data)
+	}
+}
+
+// waitForEvent returns the first manager event of the given type.
+func waitForEvent(t *testing.T, events <-chan ManagerEvent, typ ManagerEventType) ManagerEvent {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == typ {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("no %v event", typ)
+			return ManagerEvent{}
+		}
+	}
+}
+
+func newCrashTestManager(t *testing.T, luaCode string) (*Manager, *Host, <-chan ManagerEvent) {
+	t.Helper()
+	pluginsDir := t.TempDir()
+	createTestPluginDir(t, filepath.Join(pluginsDir, "crashy"), luaCode)
+
+	m := NewManager(ManagerConfig{
+		PluginPaths:  []string{pluginsDir},
+		AutoActivate: true,
+		MaxCrashes:   3,
+		CrashWindow:  time.Minute,
+	})
+	events := make(chan ManagerEvent, 100)
+	m.Subscribe(func(event ManagerEvent) { events <- event })
+
+	host, err := m.Load(context.Background(), "crashy")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if host.State() != StateActive {
+		t.Fatalf("state = %v, want active", host.State())
+	}
+	return m, host, events
+}
+
+func TestManagerDisablesCrashingPlugin(t *testing.T) {
+	m, host, events := newCrashTestManager(t, "calls = 0")
+
+	provider := newTestEventProvider()
+	module := api.NewEventModule(&api.Context{Event: provider, Crashes: m}, "crashy")
+	if err := module.Register(host.LuaState()); err != nil {
+		t.Fatal(err)
+	}
+	if err := host.DoString(`_ks_event.on("tick", function() calls = calls + 1; error("boom") end)`); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		provider.Emit("tick", nil)
+	}
+
+	event := waitForEvent(t, events, EventPluginDisabled)
+	if event.Plugin != "crashy" || event.Error == nil {
+		t.Errorf("disabled event = %+v, want plugin crashy with a reason", event)
+	}
+	if calls := host.GetGlobal("calls"); fmt.Sprint(calls) != "3" {
+		t.Errorf("handler called %v times, want 3", calls)
+	}
+	if host.State() != StateLoaded {
+		t.Errorf("state = %v, want loaded", host.State())
+	}
+
+	health := m.PluginHealth("crashy")
+	if health.CrashCount != 3 || !health.Disabled || health.LastError == nil {
+		t.Errorf("PluginHealth() = %+v, want 3 crashes and disabled", health)
+	}
+
+	// Activating the plugin again re-enables its handlers
+	if err := m.Activate(context.Background(), "crashy"); err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+	if health := m.PluginHealth("crashy"); health.CrashCount != 0 || health.Disabled {
+		t.Errorf("PluginHealth() after Activate = %+v, want cleared", health)
+	}
+	provider.Emit("tick", nil)
+	if calls := host.GetGlobal("calls"); fmt.Sprint(calls) != "4" {
+		t.Errorf("handler called %v times after Activate, want 4", calls)
+	}
+}
+
+func TestManagerCountsSandboxPanics(t *testing.T) {
+	m, host, events := newCrashTestManager(t, "-- panics")
+	host.RegisterFunc("explode", func(L *lua.LState) int {
+		panic("kaboom")
+	})
+
+	if _, err := host.Call("explode"); err == nil {
+		t.Fatal("Call(explode) should fail")
+	}
+
+	event := waitForEvent(t, events, EventPluginError)
+	if event.Plugin != "crashy" {
+		t.Errorf("error event plugin = %q, want crashy", event.Plugin)
+	}
+	health := m.PluginHealth("crashy")
+	if health.CrashCount != 1 || health.Disabled {
+		t.Errorf("PluginHealth() = %+v, want 1 crash, not disabled", health)
+	}
+	if m.PluginDisabled("crashy") {
+		t.Error("PluginDisabled() = true after one crash")
+	}
+}
+
+func TestSystemPublishesPluginDisabled(t *testing.T) {
+	pluginsDir := t.TempDir()
+	createTestPluginDir(t, filepath.Join(pluginsDir, "crashy"), "-- crashes")
+
+	provider := newTestEventProvider()
+	config := DefaultSystemConfig()
+	config.ManagerConfig.PluginPaths = []string{pluginsDir}
+	config.ManagerConfig.MaxCrashes = 3
+	config.EventProvider = provider
+
+	sys := NewSystem(config)
+	if err := sys.Initialize(); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer sys.Shutdown(context.Background())
+	if _, err := sys.LoadPlugin(context.Background(), "crashy"); err != nil {
+		t.Fatalf("LoadPlugin() error = %v", err)
+	}
+
+	published := make(chan map[string]any, 1)
+	provider.Subscribe(string(events.TopicPluginDisabled), func(data map[string]any) { published <- data })
+
+	for i := 0; i < 3; i++ {
+		sys.Manager().ReportCrash("crashy", fmt.Errorf("crash %d", i))
+	}
+
+	select {
+	case data := <-published:
+		if data["plugin"] != "crashy" || data["crash_count"] != 3 || data["reason"] == "" {
+			t.Errorf("plugin.disabled data = %v, want plugin crashy, 3 crashes and a reason", data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("plugin.disabled was not published")
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dshills/keystorm/internal/plugin/api"
 	plua "github.com/dshills/keystorm/internal/plugin/lua"
+	"github.com/dshills/keystorm/internal/plugin/security"
 	lua "github.com/yuin/gopher-lua"
 )
 
@@ -23,6 +25,9 @@ type Host struct {
 	state  *plua.State
 	bridge *plua.Bridge
 
+	// executor runs Lua callbacks queued from other goroutines
+	executor *plua.Executor
+
 	// State
 	pluginState State
 	err         error
@@ -30,14 +35,24 @@ type Host struct {
 	// Configuration
 	config map[string]interface{}
 
+	// Granted capabilities (default: all manifest capabilities)
+	capabilities []plua.Capability
+
 	// Resource tracking
 	commands      []string
 	keymaps       []string
 	subscriptions []string
 
+	// Called when the plugin is deactivated or unloaded
+	deactivateHooks []func()
+
+	// Rate limits and usage counters shared by the plugin's API modules
+	monitor *security.ResourceMonitor
+
 	// Options
 	memoryLimit      int64
 	executionTimeout time.Duration
+	onCrash          func(err error)
 }
 
 // HostOption configures a Host.
@@ -64,6 +79,23 @@ func WithHostConfig(config map[string]interface{}) HostOption {
 	}
 }
 
+// WithHostCapabilities sets the capabilities granted to the plugin,
+// replacing the capabilities requested in its manifest.
+func WithHostCapabilities(caps []plua.Capability) HostOption {
+	return func(h *Host) {
+		h.capabilities = append([]plua.Capability(nil), caps...)
+	}
+}
+
+// WithHostCrashHandler sets a function called when the sandbox recovers a
+// panic in the plugin's Lua code. It is called while the plugin is
+// executing and must not call back into the Host synchronously.
+func WithHostCrashHandler(fn func(err error)) HostOption {
+	return func(h *Host) {
+		h.onCrash = fn
+	}
+}
+
 // NewHost creates a new plugin host for the given manifest.
 func NewHost(manifest *Manifest, opts ...HostOption) (*Host, error) {
 	if manifest == nil {
@@ -75,6 +107,7 @@ func NewHost(manifest *Manifest, opts ...HostOption) (*Host, error) {
 		manifest:         manifest,
 		pluginState:      StateUnloaded,
 		config:           make(map[string]interface{}),
+		capabilities:     append([]plua.Capability(nil), manifest.Capabilities...),
 		memoryLimit:      plua.DefaultMemoryLimit,
 		executionTimeout: plua.DefaultExecutionTimeout,
 	}
@@ -84,6 +117,11 @@ func NewHost(manifest *Manifest, opts ...HostOption) (*Host, error) {
 		opt(h)
 	}
 
+	limits := security.DefaultResourceLimits()
+	limits.MemoryLimit = h.memoryLimit
+	limits.ExecutionTimeout = h.executionTimeout
+	h.monitor = security.NewResourceMonitor(limits)
+
 	// Apply manifest config defaults
 	for key, prop := range manifest.ConfigSchema {
 		if prop.Default != nil {
@@ -104,6 +142,12 @@ func (h *Host) Manifest() *Manifest {
 	return h.manifest
 }
 
+// ResourceMonitor returns the monitor that tracks the plugin's resource
+// usage across its API modules.
+func (h *Host) ResourceMonitor() *security.ResourceMonitor {
+	return h.monitor
+}
+
 // State returns the current plugin state.
 func (h *Host) State() State {
 	h.mu.RLock()
@@ -131,6 +175,13 @@ func (h *Host) Config() map[string]interface{} {
 	return config
 }
 
+// Capabilities returns the capabilities granted to the plugin.
+func (h *Host) Capabilities() []plua.Capability {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]plua.Capability(nil), h.capabilities...)
+}
+
 // SetConfig sets a configuration value.
 func (h *Host) SetConfig(key string, value interface{}) {
 	h.mu.Lock()
@@ -148,10 +199,14 @@ func (h *Host) Load(ctx context.Context) error {
 	}
 
 	// Create Lua state
-	state, err := plua.NewState(
+	stateOpts := []plua.StateOption{
 		plua.WithMemoryLimit(h.memoryLimit),
 		plua.WithExecutionTimeout(h.executionTimeout),
-	)
+	}
+	if h.onCrash != nil {
+		stateOpts = append(stateOpts, plua.WithPanicHandler(h.onCrash))
+	}
+	state, err := plua.NewState(stateOpts...)
 	if err != nil {
 		h.pluginState = StateError
 		h.err = err
@@ -162,7 +217,7 @@ func (h *Host) Load(ctx context.Context) error {
 	h.bridge = plua.NewBridge(state.LuaState())
 
 	// Grant capabilities
-	for _, cap := range h.manifest.Capabilities {
+	for _, cap := range h.capabilities {
 		h.state.Sandbox().Grant(cap)
 	}
 
@@ -176,6 +231,9 @@ func (h *Host) Load(ctx context.Context) error {
 		return h.err
 	}
 
+	h.executor = plua.NewExecutor(state.LuaState(), 0)
+	go h.executor.Run(context.Background())
+
 	h.pluginState = StateLoaded
 	h.err = nil
 	return nil
@@ -250,9 +308,8 @@ func (h *Host) callActivate() error {
 // Deactivate calls the plugin's deactivate function and cleans up.
 func (h *Host) Deactivate(ctx context.Context) error {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	if h.pluginState != StateActive {
+		h.mu.Unlock()
 		return nil // Nothing to deactivate
 	}
 
@@ -263,8 +320,14 @@ func (h *Host) Deactivate(ctx context.Context) error {
 		// Log but continue with cleanup
 		h.err = err
 	}
+	hooks := append([]func(){}, h.deactivateHooks...)
+	h.mu.Unlock()
+
+	runDeactivateHooks(hooks)
 
+	h.mu.Lock()
 	h.pluginState = StateLoaded
+	h.mu.Unlock()
 	return nil
 }
 
@@ -287,9 +350,8 @@ func (h *Host) callDeactivate() error {
 // Unload closes the Lua state and releases resources.
 func (h *Host) Unload(ctx context.Context) error {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	if h.pluginState == StateUnloaded {
+		h.mu.Unlock()
 		return nil
 	}
 
@@ -298,8 +360,22 @@ func (h *Host) Unload(ctx context.Context) error {
 		h.pluginState = StateDeactivating
 		_ = h.callDeactivate()
 	}
+	hooks := h.deactivateHooks
+	h.deactivateHooks = nil
+	h.mu.Unlock()
 
-	// Close Lua state
+	// The hooks run before the Lua state closes, since they may still
+	// touch it
+	runDeactivateHooks(hooks)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Stop running queued callbacks, then close Lua state
+	if h.executor != nil {
+		h.executor.Close()
+		h.executor = nil
+	}
 	if h.state != nil {
 		h.state.Close()
 		h.state = nil
@@ -440,6 +516,34 @@ func (h *Host) LuaState() *lua.LState {
 	return h.state.LuaState()
 }
 
+// LuaExecutor returns an executor that runs Lua callbacks from other
+// goroutines on the plugin's Lua state, one at a time and never during
+// another call into the plugin. Returns nil if the plugin is not loaded.
+func (h *Host) LuaExecutor() api.LuaExecutorProvider {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.executor == nil {
+		return nil
+	}
+	return &hostExecutor{executor: h.executor, state: h.state}
+}
+
+// hostExecutor implements api.LuaExecutorProvider for a plugin.
+type hostExecutor struct {
+	executor *plua.Executor
+	state    *plua.State
+}
+
+// ExecuteAsync queues fn to run with exclusive access to the Lua state.
+func (e *hostExecutor) ExecuteAsync(fn func(L interface{}) error) error {
+	return e.executor.ExecuteAsync(func(*lua.LState) error {
+		return e.state.Do(func(L *lua.LState) error {
+			return fn(L)
+		})
+	})
+}
+
 // Bridge returns the Go-Lua bridge.
 func (h *Host) Bridge() *plua.Bridge {
 	h.mu.RLock()
@@ -447,6 +551,23 @@ func (h *Host) Bridge() *plua.Bridge {
 	return h.bridge
 }
 
+// OnDeactivate registers fn to be called when the plugin is deactivated or
+// unloaded, to release what the plugin subscribed to through the API. Hooks
+// are kept across activations and dropped when the plugin is unloaded.
+func (h *Host) OnDeactivate(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.deactivateHooks = append(h.deactivateHooks, fn)
+}
+
+// runDeactivateHooks calls the given deactivate hooks. Hooks may call back
+// into the host, so h.mu must not be held.
+func runDeactivateHooks(hooks []func()) {
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
 // TrackCommand records a command registered by this plugin.
 func (h *Host) TrackCommand(id string) {
 	h.mu.Lock()
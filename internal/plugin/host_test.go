@@ -461,6 +461,43 @@ func TestHostTracking(t *testing.T) {
 	}
 }
 
+func TestHostOnDeactivate(t *testing.T) {
+	manifest := createTestPlugin(t, "test", `function activate() end`)
+	host, _ := NewHost(manifest)
+	ctx := context.Background()
+
+	// Hooks run without the host lock, so they may call back into the host
+	calls := 0
+	host.OnDeactivate(func() {
+		calls++
+		_ = host.State()
+	})
+
+	if err := host.Load(ctx); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := host.Activate(ctx); err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+	if err := host.Deactivate(ctx); err != nil {
+		t.Fatalf("Deactivate() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("hook calls after Deactivate = %d, want 1", calls)
+	}
+
+	// Hooks survive reactivation and run once more on unload
+	if err := host.Activate(ctx); err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+	if err := host.Unload(ctx); err != nil {
+		t.Fatalf("Unload() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("hook calls after Unload = %d, want 2", calls)
+	}
+}
+
 func TestHostStats(t *testing.T) {
 	manifest := &Manifest{Name: "test", Version: "1.0.0"}
 	host, _ := NewHost(manifest)
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/dshills/keystorm/internal/event/events"
 	"github.com/dshills/keystorm/internal/plugin/api"
+	"github.com/dshills/keystorm/internal/plugin/security"
 )
 
 // System provides a unified interface to the Keystorm plugin system.
@@ -48,6 +50,12 @@ type SystemConfig struct {
 	UIProvider      api.UIProvider
 	ConfigProvider  api.ConfigProvider
 	LSPProvider     api.LSPProvider
+
+	// CapabilityPrompter asks the user about capabilities a plugin
+	// requests but has not been granted. It overrides
+	// ManagerConfig.CapabilityPrompter when set. A denied capability
+	// leaves the plugin loaded without it or the API modules requiring it.
+	CapabilityPrompter CapabilityPrompter
 }
 
 // DefaultSystemConfig returns sensible default system configuration.
@@ -95,12 +103,35 @@ func (s *System) Initialize() error {
 	s.registry = registry
 
 	// Create plugin manager
-	s.manager = NewManager(s.config.ManagerConfig)
+	managerConfig := s.config.ManagerConfig
+	if s.config.CapabilityPrompter != nil {
+		managerConfig.CapabilityPrompter = s.config.CapabilityPrompter
+	}
+	s.manager = NewManager(managerConfig)
+	s.apiCtx.Crashes = s.manager
+	s.manager.Subscribe(s.publishDisabled)
 
 	s.initialized = true
 	return nil
 }
 
+// publishDisabled publishes plugin.disabled through the event provider when
+// the manager disables a plugin that crashed repeatedly.
+func (s *System) publishDisabled(event ManagerEvent) {
+	if event.Type != EventPluginDisabled || s.config.EventProvider == nil {
+		return
+	}
+	reason := ""
+	if event.Error != nil {
+		reason = event.Error.Error()
+	}
+	s.config.EventProvider.Emit(string(events.TopicPluginDisabled), map[string]any{
+		"plugin":      event.Plugin,
+		"reason":      reason,
+		"crash_count": s.manager.PluginHealth(event.Plugin).CrashCount,
+	})
+}
+
 // Shutdown gracefully shuts down the plugin system.
 // It deactivates and unloads all plugins.
 func (s *System) Shutdown(ctx context.Context) error {
@@ -368,10 +399,55 @@ func (s *System) injectAPIs(host *Host) error {
 		return nil // Plugin not loaded yet
 	}
 
-	// Inject all modules without capability checking
-	// The sandbox already handles capability-based restrictions
-	// API modules that need capability checking use the security package directly
-	return s.registry.InjectAll(L, nil)
+	// Inject the modules the plugin's granted capabilities allow.
+	// Denied capabilities leave their modules out.
+	checker := security.NewPermissionChecker(host.Name())
+	for _, cap := range host.Capabilities() {
+		checker.Grant(security.Capability(cap))
+	}
+
+	// ks.config is per plugin: writes are limited to its namespace, its
+	// watch callbacks run through its executor and its watches end when it
+	// is deactivated
+	if s.apiCtx.Config != nil && checker.HasCapability(security.CapabilityConfig) {
+		cfgCtx := &api.Context{Config: s.apiCtx.Config, LuaExecutor: host.LuaExecutor()}
+		config := api.NewConfigModule(cfgCtx, host.Name())
+		config.SetPermissionChecker(checker)
+		if err := config.Register(L); err != nil {
+			return fmt.Errorf("failed to register module %q: %w", config.Name(), err)
+		}
+		host.OnDeactivate(config.UnwatchAll)
+	}
+
+	// ks.ui is per plugin: its statusline segments and overlay titles
+	// carry the plugin's name, prompt answers arrive through its executor
+	// and its overlays close when it is deactivated
+	if s.apiCtx.UI != nil && checker.HasCapability(security.CapabilityUI) {
+		uiCtx := &api.Context{UI: s.apiCtx.UI, LuaExecutor: host.LuaExecutor()}
+		ui := api.NewUIModule(uiCtx, host.Name())
+		if err := ui.Register(L); err != nil {
+			return fmt.Errorf("failed to register module %q: %w", ui.Name(), err)
+		}
+		host.OnDeactivate(ui.Cleanup)
+	}
+
+	// ks.http checks the plugin's own capabilities and shares its
+	// network rate limit across requests
+	if checker.HasCapability(security.CapabilityNetwork) {
+		http := api.NewHTTPModule(checker, host.ResourceMonitor())
+		if err := http.Register(L); err != nil {
+			return fmt.Errorf("failed to register module %q: %w", http.Name(), err)
+		}
+	}
+
+	// ks.task runs within the plugin's resource limits and delivers results
+	// through its executor rather than blocking the plugin
+	task := api.NewTaskModule(&api.Context{LuaExecutor: host.LuaExecutor()}, host.ResourceMonitor())
+	if err := task.Register(L); err != nil {
+		return fmt.Errorf("failed to register module %q: %w", task.Name(), err)
+	}
+
+	return s.registry.InjectAll(L, checker)
 }
 
 // SetProvider updates a provider at runtime.
@@ -2,10 +2,17 @@ package plugin
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/dshills/keystorm/internal/plugin/api"
+	plua "github.com/dshills/keystorm/internal/plugin/lua"
+	"github.com/dshills/keystorm/internal/plugin/security"
+	lua "github.com/yuin/gopher-lua"
 )
 
 func TestNewSystem(t *testing.T) {
@@ -616,3 +623,225 @@ func TestSystemConcurrentAccess(t *testing.T) {
 		}
 	}
 }
+
+func TestSystemInjectsHTTPByCapability(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writePlugin := func(name, caps string) {
+		dir := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create plugin dir: %v", err)
+		}
+		manifest := `{"name": "` + name + `", "version": "1.0.0", "main": "init.lua", "capabilities": [` + caps + `]}`
+		if err := os.WriteFile(filepath.Join(dir, "plugin.json"), []byte(manifest), 0644); err != nil {
+			t.Fatalf("failed to write manifest: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "init.lua"), []byte("function setup() end"), 0644); err != nil {
+			t.Fatalf("failed to write plugin code: %v", err)
+		}
+	}
+	writePlugin("net-plugin", `"network"`)
+	writePlugin("offline-plugin", "")
+
+	config := DefaultSystemConfig()
+	config.ManagerConfig.PluginPaths = []string{tmpDir}
+	config.ManagerConfig.AutoActivate = false
+
+	sys := NewSystem(config)
+	if err := sys.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer sys.Shutdown(context.Background())
+	if _, err := sys.Discover(); err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"net-plugin", true},
+		{"offline-plugin", false},
+	}
+	for _, tt := range tests {
+		host, err := sys.LoadPlugin(context.Background(), tt.name)
+		if err != nil {
+			t.Fatalf("LoadPlugin(%q) failed: %v", tt.name, err)
+		}
+		if host.ResourceMonitor() == nil {
+			t.Errorf("%s: ResourceMonitor() = nil", tt.name)
+		}
+		L := host.LuaState()
+		if err := L.DoString(`has_http = require("ks").http ~= nil`); err != nil {
+			t.Fatalf("%s: DoString failed: %v", tt.name, err)
+		}
+		if got := L.GetGlobal("has_http") == lua.LTrue; got != tt.want {
+			t.Errorf("%s: ks.http present = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// statuslineRecorder records the statusline segments set through ks.ui.
+type statuslineRecorder struct {
+	api.UIProvider
+	segments []string
+}
+
+func (r *statuslineRecorder) SetStatusline(position api.StatuslinePosition, segment, content string) error {
+	r.segments = append(r.segments, segment)
+	return nil
+}
+
+func (r *statuslineRecorder) ClearStatusline(position api.StatuslinePosition, segment string) error {
+	return nil
+}
+
+func TestSystemInjectsUIPerPlugin(t *testing.T) {
+	ui := &statuslineRecorder{}
+	config := DefaultSystemConfig()
+	config.UIProvider = ui
+
+	sys := NewSystem(config)
+	if err := sys.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer sys.Shutdown(context.Background())
+
+	tests := []struct {
+		name string
+		caps []plua.Capability
+		want bool
+	}{
+		{"ui-plugin", []plua.Capability{plua.Capability(security.CapabilityUI)}, true},
+		{"headless-plugin", nil, false},
+	}
+	for _, tt := range tests {
+		host, err := NewHost(createTestPlugin(t, tt.name, "function setup() end"), WithHostCapabilities(tt.caps))
+		if err != nil {
+			t.Fatalf("NewHost(%q) failed: %v", tt.name, err)
+		}
+		if err := host.Load(context.Background()); err != nil {
+			t.Fatalf("Load(%q) failed: %v", tt.name, err)
+		}
+		defer host.Unload(context.Background())
+		if err := sys.injectAPIs(host); err != nil {
+			t.Fatalf("injectAPIs(%q) failed: %v", tt.name, err)
+		}
+
+		L := host.LuaState()
+		if err := L.DoString(`ui = require("ks").ui; if ui then ui.statusline("busy") end`); err != nil {
+			t.Fatalf("%s: DoString failed: %v", tt.name, err)
+		}
+		if got := L.GetGlobal("ui") != lua.LNil; got != tt.want {
+			t.Errorf("%s: ks.ui present = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if len(ui.segments) != 1 || ui.segments[0] != "plugin:ui-plugin" {
+		t.Errorf("segments = %v, want [plugin:ui-plugin]", ui.segments)
+	}
+}
+
+func TestSystemInjectsTaskPerPlugin(t *testing.T) {
+	sys := NewSystem(DefaultSystemConfig())
+	if err := sys.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer sys.Shutdown(context.Background())
+
+	host, err := NewHost(createTestPlugin(t, "task-plugin", `
+		function start(n)
+			require("ks").task(function(x) return x * 2 end, function(value, err)
+				result = value
+			end, n)
+		end
+	`))
+	if err != nil {
+		t.Fatalf("NewHost failed: %v", err)
+	}
+	if err := host.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	defer host.Unload(context.Background())
+	if err := sys.injectAPIs(host); err != nil {
+		t.Fatalf("injectAPIs failed: %v", err)
+	}
+
+	// The call returns before the result is delivered through the
+	// plugin's executor
+	if _, err := host.Call("start", 21); err != nil {
+		t.Fatalf("Call(start) failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for host.GetGlobal("result") == nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := host.GetGlobal("result"); got != int64(42) {
+		t.Errorf("result = %v, want 42", got)
+	}
+	if got := host.ResourceMonitor().GoroutineCount(); got != 0 {
+		t.Errorf("GoroutineCount() = %d, want the task's slot released", got)
+	}
+}
+
+// watchRecorder records the handlers registered through ks.config.
+type watchRecorder struct {
+	api.ConfigProvider
+	mu       sync.Mutex
+	handlers []func(key string, oldValue, newValue any)
+}
+
+func (r *watchRecorder) Watch(pattern string, handler func(key string, oldValue, newValue any)) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, handler)
+	return fmt.Sprintf("w%d", len(r.handlers))
+}
+
+func (r *watchRecorder) Unwatch(id string) bool { return true }
+
+func TestSystemConfigWatchRunsThroughExecutor(t *testing.T) {
+	cfg := &watchRecorder{}
+	config := DefaultSystemConfig()
+	config.ConfigProvider = cfg
+
+	sys := NewSystem(config)
+	if err := sys.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer sys.Shutdown(context.Background())
+
+	host, err := NewHost(createTestPlugin(t, "cfg-plugin", "function setup() end"),
+		WithHostCapabilities([]plua.Capability{plua.Capability(security.CapabilityConfig)}))
+	if err != nil {
+		t.Fatalf("NewHost failed: %v", err)
+	}
+	if err := host.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	defer host.Unload(context.Background())
+	if err := sys.injectAPIs(host); err != nil {
+		t.Fatalf("injectAPIs failed: %v", err)
+	}
+
+	if err := host.DoString(`require("ks").config.watch("cfg-plugin.mode", function(key, old, new) changed = new end)`); err != nil {
+		t.Fatalf("DoString(watch) failed: %v", err)
+	}
+	cfg.mu.Lock()
+	handlers := cfg.handlers
+	cfg.mu.Unlock()
+	if len(handlers) != 1 {
+		t.Fatalf("watch handlers = %d, want 1", len(handlers))
+	}
+
+	// The notifier goroutine hands the change to the plugin's executor
+	// instead of touching the Lua state itself
+	go handlers[0]("cfg-plugin.mode", "a", "b")
+	deadline := time.Now().Add(5 * time.Second)
+	for host.GetGlobal("changed") == nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := host.GetGlobal("changed"); got != "b" {
+		t.Errorf("changed = %v, want b", got)
+	}
+}
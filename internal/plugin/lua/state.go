@@ -2,6 +2,7 @@
 package lua
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -39,6 +40,9 @@ type State struct {
 	// Sandbox
 	sandbox *Sandbox
 
+	// onPanic is called when a panic is recovered
+	onPanic func(err error)
+
 	// Tracking
 	closed bool
 }
@@ -70,6 +74,16 @@ func WithInstructionLimit(limit int64) StateOption {
 	}
 }
 
+// WithPanicHandler sets a function called with the resulting error when a
+// Go panic during Lua execution is recovered, whether by the state or by
+// gopher-lua's protected call. It is called with the state locked and must
+// not call back into the State.
+func WithPanicHandler(fn func(err error)) StateOption {
+	return func(s *State) {
+		s.onPanic = fn
+	}
+}
+
 // NewState creates a new sandboxed Lua state.
 func NewState(opts ...StateOption) (*State, error) {
 	state := &State{
@@ -158,14 +172,52 @@ func (s *State) DoString(code string) error {
 	})
 }
 
+// Do runs fn with exclusive access to the Lua state, like the other
+// methods of State, so that code outside a call into Lua, such as a
+// callback queued by an Executor, does not race with the plugin.
+func (s *State) Do(fn func(L *lua.LState) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStateClosed
+	}
+
+	s.sandbox.ResetInstructionCount()
+
+	return s.doWithRecovery(func() error {
+		return fn(s.L)
+	})
+}
+
 // doWithRecovery executes a function with panic recovery.
 func (s *State) doWithRecovery(fn func() error) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("lua panic: %v", r)
+			err = s.recovered(r)
 		}
 	}()
-	return fn()
+	return s.reportPanic(fn())
+}
+
+// reportPanic reports err to the panic handler if it is a panic recovered
+// by a protected call, and returns it.
+func (s *State) reportPanic(err error) error {
+	var apiErr *lua.ApiError
+	if s.onPanic != nil && errors.As(err, &apiErr) && apiErr.Type == lua.ApiErrorPanic {
+		s.onPanic(err)
+	}
+	return err
+}
+
+// recovered converts a recovered panic to an error and reports it to the
+// panic handler.
+func (s *State) recovered(r any) error {
+	err := fmt.Errorf("lua panic: %v", r)
+	if s.onPanic != nil {
+		s.onPanic(err)
+	}
+	return err
 }
 
 // Call calls a global Lua function with the given arguments.
@@ -204,10 +256,10 @@ func (s *State) Call(fn string, args ...lua.LValue) ([]lua.LValue, error) {
 	func() {
 		defer func() {
 			if r := recover(); r != nil {
-				callErr = fmt.Errorf("lua panic: %v", r)
+				callErr = s.recovered(r)
 			}
 		}()
-		callErr = s.L.PCall(len(args), lua.MultRet, nil)
+		callErr = s.reportPanic(s.L.PCall(len(args), lua.MultRet, nil))
 	}()
 
 	if callErr != nil {
@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
+
+	plua "github.com/dshills/keystorm/internal/plugin/lua"
 )
 
 // Manager manages the lifecycle of all plugins.
@@ -24,6 +28,9 @@ type Manager struct {
 	// Event handlers (protected by mu)
 	eventHandlers []EventHandler
 
+	// Crash tracking by plugin name (protected by mu)
+	health map[string]*pluginHealth
+
 	// Configuration
 	config ManagerConfig
 }
@@ -41,6 +48,26 @@ type ManagerConfig struct {
 
 	// MaxParallel is the maximum number of parallel load operations (reserved for future use)
 	MaxParallel int
+
+	// GrantedCapabilities holds capabilities granted ahead of time, by
+	// plugin name. "Allow always" decisions are added to it.
+	GrantedCapabilities map[string][]plua.Capability
+
+	// CapabilityPrompter is asked about each capability in a plugin's
+	// manifest that is not in GrantedCapabilities. If nil, all manifest
+	// capabilities are granted.
+	CapabilityPrompter CapabilityPrompter
+
+	// OnCapabilityGranted is called after an "allow always" decision so
+	// the application can persist the grant to its configuration.
+	OnCapabilityGranted func(plugin string, cap plua.Capability)
+
+	// MaxCrashes is the number of crashes within CrashWindow after which a
+	// plugin is deactivated. Zero never deactivates plugins.
+	MaxCrashes int
+
+	// CrashWindow is the period crashes are counted over.
+	CrashWindow time.Duration
 }
 
 // DefaultManagerConfig returns sensible default configuration.
@@ -50,6 +77,8 @@ func DefaultManagerConfig() ManagerConfig {
 		AutoActivate: true,
 		ParallelLoad: false,
 		MaxParallel:  4,
+		MaxCrashes:   DefaultMaxCrashes,
+		CrashWindow:  DefaultCrashWindow,
 	}
 }
 
@@ -81,6 +110,9 @@ const (
 	EventPluginReloaded
 	// EventPluginError is emitted when a plugin encounters an error.
 	EventPluginError
+	// EventPluginDisabled is emitted when a plugin is deactivated because
+	// it crashed repeatedly. The event's Error gives the reason.
+	EventPluginDisabled
 )
 
 // String returns a string representation of the event type.
@@ -98,6 +130,8 @@ func (t ManagerEventType) String() string {
 		return "reloaded"
 	case EventPluginError:
 		return "error"
+	case EventPluginDisabled:
+		return "disabled"
 	default:
 		return "unknown"
 	}
@@ -105,10 +139,18 @@ func (t ManagerEventType) String() string {
 
 // NewManager creates a new plugin manager.
 func NewManager(config ManagerConfig) *Manager {
+	// Copy the grants so that "allow always" does not modify the caller's map.
+	grants := make(map[string][]plua.Capability, len(config.GrantedCapabilities))
+	for name, caps := range config.GrantedCapabilities {
+		grants[name] = slices.Clone(caps)
+	}
+	config.GrantedCapabilities = grants
+
 	return &Manager{
 		loader:    NewLoader(WithPaths(config.PluginPaths...)),
 		plugins:   make(map[string]*Host),
 		loadOrder: make([]string, 0),
+		health:    make(map[string]*pluginHealth),
 		config:    config,
 	}
 }
@@ -135,8 +177,14 @@ func (m *Manager) Load(ctx context.Context, name string) (*Host, error) {
 		return nil, err
 	}
 
+	// Decide which requested capabilities to grant, prompting if needed
+	caps := m.resolveCapabilities(ctx, info.Manifest)
+
 	// Create host (no lock needed)
-	host, err := NewHost(info.Manifest)
+	host, err := NewHost(info.Manifest,
+		WithHostCapabilities(caps),
+		WithHostCrashHandler(func(err error) { m.ReportCrash(name, err) }),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -204,6 +252,7 @@ func (m *Manager) Unload(ctx context.Context, name string) error {
 		return fmt.Errorf("plugin %q: %w", name, ErrPluginNotFound)
 	}
 	delete(m.plugins, name)
+	delete(m.health, name)
 	m.removeFromLoadOrder(name)
 	m.mu.Unlock()
 
@@ -303,12 +352,16 @@ func (m *Manager) ListByState(state State) []*Host {
 	return result
 }
 
-// Activate activates a loaded plugin.
+// Activate activates a loaded plugin. A plugin disabled after crashing is
+// enabled again with its crash history cleared.
 func (m *Manager) Activate(ctx context.Context, name string) error {
-	// Get the host (brief lock)
-	m.mu.RLock()
+	// Get the host and re-enable it (brief lock)
+	m.mu.Lock()
 	host, exists := m.plugins[name]
-	m.mu.RUnlock()
+	if exists {
+		delete(m.health, name)
+	}
+	m.mu.Unlock()
 
 	if !exists {
 		return fmt.Errorf("plugin %q: %w", name, ErrPluginNotFound)
@@ -324,12 +377,17 @@ func (m *Manager) Activate(ctx context.Context, name string) error {
 	return nil
 }
 
-// ActivateAll activates all loaded plugins.
+// ActivateAll activates all loaded plugins except those disabled after
+// crashing.
 func (m *Manager) ActivateAll(ctx context.Context) error {
 	// Get names (brief lock)
 	m.mu.RLock()
-	names := make([]string, len(m.loadOrder))
-	copy(names, m.loadOrder)
+	names := make([]string, 0, len(m.loadOrder))
+	for _, name := range m.loadOrder {
+		if h := m.health[name]; h == nil || !h.Disabled {
+			names = append(names, name)
+		}
+	}
 	m.mu.RUnlock()
 
 	var activateErrors []error
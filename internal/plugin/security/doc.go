@@ -27,6 +27,8 @@
 //   - File path allowlists/blocklists
 //   - Network host allowlists/blocklists
 //   - Workspace boundary enforcement
+//   - Config namespaces: plugins write config keys only under
+//     "plugins.<name>" unless granted further namespaces
 //
 // # Resource Limits
 //
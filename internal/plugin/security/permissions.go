@@ -1,6 +1,7 @@
 package security
 
 import (
+	"fmt"
 	"net"
 	"path/filepath"
 	"strings"
@@ -23,6 +24,9 @@ type PermissionChecker struct {
 	allowedHosts []string
 	blockedHosts []string
 
+	// Config namespaces writable besides the plugin's own
+	configNamespaces []string
+
 	// Plugin identity
 	pluginName string
 }
@@ -257,6 +261,23 @@ func (pc *PermissionChecker) CheckNetwork(host string) error {
 	return nil
 }
 
+// HostExplicitlyAllowed returns true if host matches an entry in the allowed
+// network list. Unlike CheckNetwork, an empty allowed list allows nothing.
+// It is used to opt private and link-local addresses back in, which are
+// otherwise refused to protect against SSRF.
+func (pc *PermissionChecker) HostExplicitlyAllowed(host string) bool {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	hostOnly := strings.ToLower(extractHost(host))
+	for _, allowedHost := range pc.allowedHosts {
+		if matchHost(hostOnly, allowedHost) {
+			return true
+		}
+	}
+	return false
+}
+
 // extractHost extracts the host from a host:port string.
 // Handles IPv6 addresses like [::1]:8080 and regular host:port.
 func extractHost(hostPort string) string {
@@ -321,6 +342,49 @@ func matchHost(host, pattern string) bool {
 	return false
 }
 
+// PluginConfigNamespace returns the config namespace owned by the plugin
+// pluginName. A plugin may always write keys under it.
+func PluginConfigNamespace(pluginName string) string {
+	return "plugins." + pluginName
+}
+
+// AllowConfigNamespace lets the plugin write config keys under namespace,
+// in addition to its own. The namespace "*" allows every key.
+func (pc *PermissionChecker) AllowConfigNamespace(namespace string) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.configNamespaces = append(pc.configNamespaces, namespace)
+}
+
+// CheckConfigWrite checks if writing the config key is permitted. Plugins
+// may write keys under their own namespace and under namespaces allowed
+// with AllowConfigNamespace.
+func (pc *PermissionChecker) CheckConfigWrite(key string) error {
+	if !pc.HasCapability(CapabilityConfig) {
+		return NewCapabilityError(CapabilityConfig, "config write", "not granted")
+	}
+
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	own := PluginConfigNamespace(pc.pluginName)
+	if isWithinNamespace(key, own) {
+		return nil
+	}
+	for _, ns := range pc.configNamespaces {
+		if ns == "*" || isWithinNamespace(key, ns) {
+			return nil
+		}
+	}
+	return NewCapabilityError(CapabilityConfig, "config write",
+		fmt.Sprintf("key %q is outside plugin namespace %q", key, own))
+}
+
+// isWithinNamespace checks if a dotted config key is namespace or below it.
+func isWithinNamespace(key, namespace string) bool {
+	return key == namespace || strings.HasPrefix(key, namespace+".")
+}
+
 // PermissionSet represents a collection of permissions for a plugin.
 type PermissionSet struct {
 	// Capabilities granted
@@ -333,6 +397,9 @@ type PermissionSet struct {
 	// Network permissions
 	AllowedHosts []string
 	BlockedHosts []string
+
+	// Config namespaces writable besides the plugin's own
+	ConfigNamespaces []string
 }
 
 // ApplyPermissionSet applies a permission set to a checker.
@@ -360,6 +427,8 @@ func (pc *PermissionChecker) ApplyPermissionSet(set *PermissionSet) {
 	for _, host := range set.BlockedHosts {
 		pc.blockedHosts = append(pc.blockedHosts, strings.ToLower(host))
 	}
+
+	pc.configNamespaces = append(pc.configNamespaces, set.ConfigNamespaces...)
 }
 
 // Reset clears all permissions.
@@ -372,4 +441,5 @@ func (pc *PermissionChecker) Reset() {
 	pc.blockedPaths = nil
 	pc.allowedHosts = nil
 	pc.blockedHosts = nil
+	pc.configNamespaces = nil
 }
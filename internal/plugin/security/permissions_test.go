@@ -307,6 +307,28 @@ func TestPermissionCheckerNetworkWithPort(t *testing.T) {
 	}
 }
 
+func TestPermissionCheckerHostExplicitlyAllowed(t *testing.T) {
+	pc := NewPermissionChecker("test")
+	pc.Grant(CapabilityNetwork)
+
+	// An empty allowed list allows every host but none explicitly
+	if pc.HostExplicitlyAllowed("localhost") {
+		t.Error("HostExplicitlyAllowed should be false with no allowed hosts")
+	}
+
+	pc.AllowHost("*.internal.example")
+	pc.AllowHost("10.0.0.5")
+	if !pc.HostExplicitlyAllowed("api.internal.example:8080") {
+		t.Error("wildcard host should be explicitly allowed")
+	}
+	if !pc.HostExplicitlyAllowed("10.0.0.5") {
+		t.Error("allowed address should be explicitly allowed")
+	}
+	if pc.HostExplicitlyAllowed("10.0.0.6") {
+		t.Error("unlisted address should not be explicitly allowed")
+	}
+}
+
 func TestPermissionCheckerCheckShell(t *testing.T) {
 	pc := NewPermissionChecker("test")
 
@@ -358,6 +380,46 @@ func TestPermissionCheckerCheckClipboard(t *testing.T) {
 	}
 }
 
+func TestPermissionCheckerCheckConfigWrite(t *testing.T) {
+	pc := NewPermissionChecker("test")
+
+	// Without capability
+	if err := pc.CheckConfigWrite("plugins.test.enabled"); err == nil {
+		t.Error("CheckConfigWrite should fail without capability")
+	}
+
+	pc.Grant(CapabilityConfig)
+	tests := []struct {
+		key     string
+		allowed bool
+	}{
+		{"plugins.test", true},
+		{"plugins.test.enabled", true},
+		{"plugins.tester.enabled", false},
+		{"plugins.other.enabled", false},
+		{"editor.tabSize", false},
+	}
+	for _, tt := range tests {
+		err := pc.CheckConfigWrite(tt.key)
+		if (err == nil) != tt.allowed {
+			t.Errorf("CheckConfigWrite(%q) error = %v, want allowed %v", tt.key, err, tt.allowed)
+		}
+	}
+
+	// Broader access
+	pc.AllowConfigNamespace("editor")
+	if err := pc.CheckConfigWrite("editor.tabSize"); err != nil {
+		t.Errorf("CheckConfigWrite(editor.tabSize) with namespace error = %v", err)
+	}
+	if err := pc.CheckConfigWrite("ui.theme"); err == nil {
+		t.Error("CheckConfigWrite(ui.theme) should fail outside allowed namespaces")
+	}
+	pc.AllowConfigNamespace("*")
+	if err := pc.CheckConfigWrite("ui.theme"); err != nil {
+		t.Errorf("CheckConfigWrite(ui.theme) with wildcard error = %v", err)
+	}
+}
+
 func TestPermissionCheckerApplyPermissionSet(t *testing.T) {
 	pc := NewPermissionChecker("test")
 
@@ -0,0 +1,188 @@
+package project
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBatchWindow is the default quiet time after the last file
+	// change event before a batch is delivered to OnFileChangeBatch
+	// handlers.
+	DefaultBatchWindow = 200 * time.Millisecond
+
+	// DefaultBatchMaxWait is the default longest time a batch is held back
+	// while events keep arriving.
+	DefaultBatchMaxWait = 2 * time.Second
+)
+
+// OnFileChangeBatch registers a handler for batches of file change events.
+// Events are collected until none has arrived for Config.BatchWindow, or
+// for at most Config.BatchMaxWait from the first event of a burst, then
+// coalesced by CoalesceFileChanges and delivered together. Use it
+// instead of OnFileChange when each delivery triggers expensive work, such
+// as reindexing or notifying a language server.
+func (p *DefaultProject) OnFileChangeBatch(handler func([]FileChangeEvent)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.fileChangeBatchHandlers = append(p.fileChangeBatchHandlers, handler)
+	if p.batcher == nil {
+		window := p.config.BatchWindow
+		if window <= 0 {
+			window = DefaultBatchWindow
+		}
+		maxWait := p.config.BatchMaxWait
+		if maxWait <= 0 {
+			maxWait = DefaultBatchMaxWait
+		}
+		p.batcher = newFileChangeBatcher(window, maxWait, p.deliverBatch)
+	}
+}
+
+// deliverBatch passes a coalesced batch to the batch handlers.
+func (p *DefaultProject) deliverBatch(events []FileChangeEvent) {
+	p.mu.RLock()
+	handlers := make([]func([]FileChangeEvent), len(p.fileChangeBatchHandlers))
+	copy(handlers, p.fileChangeBatchHandlers)
+	p.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(events)
+	}
+}
+
+// CoalesceFileChanges reduces a sequence of file change events to the
+// minimal set with the same net effect, ordered by path and, for the same
+// path, by occurrence. Events for a path are merged as follows:
+//
+//   - created then modified is created
+//   - created then deleted is dropped entirely
+//   - modified then deleted is deleted
+//   - deleted then modified is deleted
+//   - deleted then created is modified (the file was replaced)
+//
+// Renames are never merged, and later events for the renamed path start
+// afresh. Merged events carry the timestamp of the latest event.
+func CoalesceFileChanges(events []FileChangeEvent) []FileChangeEvent {
+	merged := make([]FileChangeEvent, 0, len(events))
+	dropped := make(map[int]bool)
+	latest := make(map[string]int)
+
+	for _, event := range events {
+		i, ok := latest[event.Path]
+		if event.Type == FileChangeRenamed || !ok {
+			merged = append(merged, event)
+			if event.Type == FileChangeRenamed {
+				delete(latest, event.Path)
+			} else {
+				latest[event.Path] = len(merged) - 1
+			}
+			continue
+		}
+
+		changeType, keep := mergeFileChange(merged[i].Type, event.Type)
+		if !keep {
+			dropped[i] = true
+			delete(latest, event.Path)
+			continue
+		}
+		merged[i].Type = changeType
+		merged[i].Timestamp = event.Timestamp
+	}
+
+	result := make([]FileChangeEvent, 0, len(merged)-len(dropped))
+	for i, event := range merged {
+		if !dropped[i] {
+			result = append(result, event)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
+
+// mergeFileChange returns the net change of prev followed by next on the
+// same path, or false if they cancel out.
+func mergeFileChange(prev, next FileChangeType) (FileChangeType, bool) {
+	switch prev {
+	case FileChangeCreated:
+		if next == FileChangeDeleted {
+			return 0, false
+		}
+		return FileChangeCreated, true
+	case FileChangeDeleted:
+		if next == FileChangeCreated {
+			return FileChangeModified, true
+		}
+		return FileChangeDeleted, true
+	default:
+		if next == FileChangeDeleted {
+			return FileChangeDeleted, true
+		}
+		return FileChangeModified, true
+	}
+}
+
+// fileChangeBatcher collects file change events and delivers them in
+// coalesced batches.
+type fileChangeBatcher struct {
+	mu      sync.Mutex
+	window  time.Duration
+	maxWait time.Duration
+	pending []FileChangeEvent
+	first   time.Time // when the first pending event arrived
+	timer   *time.Timer
+	deliver func([]FileChangeEvent)
+}
+
+// newFileChangeBatcher creates a batcher that delivers a batch once no
+// event has arrived for window, or maxWait after its first event.
+func newFileChangeBatcher(window, maxWait time.Duration, deliver func([]FileChangeEvent)) *fileChangeBatcher {
+	return &fileChangeBatcher{
+		window:  window,
+		maxWait: maxWait,
+		deliver: deliver,
+	}
+}
+
+// add queues an event and restarts the batch window, without extending it
+// past maxWait from the first pending event.
+func (b *fileChangeBatcher) add(event FileChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if len(b.pending) == 0 {
+		b.first = now
+	}
+	b.pending = append(b.pending, event)
+
+	delay := min(b.window, max(b.first.Add(b.maxWait).Sub(now), 0))
+	if b.timer == nil {
+		b.timer = time.AfterFunc(delay, b.flush)
+	} else {
+		b.timer.Reset(delay)
+	}
+}
+
+// flush delivers the pending events, if any.
+func (b *fileChangeBatcher) flush() {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	if batch := CoalesceFileChanges(pending); len(batch) > 0 {
+		b.deliver(batch)
+	}
+}
@@ -0,0 +1,179 @@
+package project
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dshills/keystorm/internal/project/watcher"
+)
+
+func TestCoalesceFileChangesBurst(t *testing.T) {
+	base := time.Now()
+	var events []FileChangeEvent
+	add := func(typ FileChangeType, path string) {
+		events = append(events, FileChangeEvent{Type: typ, Path: path, Timestamp: base.Add(time.Duration(len(events)))})
+	}
+
+	// A save-all rewrites ten files, each several times
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 10; i++ {
+			add(FileChangeModified, fmt.Sprintf("/p/src/%02d.go", 9-i))
+		}
+	}
+	// A checkout creates, edits and deletes files
+	for i := 0; i < 5; i++ {
+		add(FileChangeCreated, "/p/gen.go")
+		add(FileChangeModified, "/p/gen.go")
+	}
+	add(FileChangeDeleted, "/p/gen.go") // created, then deleted: nothing
+	add(FileChangeCreated, "/p/new.go")
+	add(FileChangeModified, "/p/new.go")
+	add(FileChangeDeleted, "/p/old.go")
+	add(FileChangeModified, "/p/old.go")
+	add(FileChangeModified, "/p/old.go")
+	add(FileChangeDeleted, "/p/swap.go")
+	add(FileChangeCreated, "/p/swap.go")
+	add(FileChangeModified, "/p/src/00.go")
+	add(FileChangeDeleted, "/p/src/00.go")
+
+	if len(events) != 50 {
+		t.Fatalf("test burst has %d events, want 50", len(events))
+	}
+
+	got := CoalesceFileChanges(events)
+
+	want := []FileChangeEvent{
+		{Type: FileChangeCreated, Path: "/p/new.go"},
+		{Type: FileChangeDeleted, Path: "/p/old.go"},
+		{Type: FileChangeDeleted, Path: "/p/src/00.go"},
+	}
+	for i := 1; i < 10; i++ {
+		want = append(want, FileChangeEvent{Type: FileChangeModified, Path: fmt.Sprintf("/p/src/%02d.go", i)})
+	}
+	want = append(want, FileChangeEvent{Type: FileChangeModified, Path: "/p/swap.go"})
+
+	if len(got) != len(want) {
+		t.Fatalf("CoalesceFileChanges() = %d events %v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i].Type != want[i].Type || got[i].Path != want[i].Path {
+			t.Errorf("event %d = %v %s, want %v %s", i, got[i].Type, got[i].Path, want[i].Type, want[i].Path)
+		}
+	}
+
+	// Merged events carry the latest timestamp
+	if got[0].Timestamp != base.Add(42) {
+		t.Errorf("new.go timestamp = %v, want latest", got[0].Timestamp)
+	}
+}
+
+func TestCoalesceFileChangesRename(t *testing.T) {
+	got := CoalesceFileChanges([]FileChangeEvent{
+		{Type: FileChangeModified, Path: "/a"},
+		{Type: FileChangeRenamed, Path: "/a"},
+		{Type: FileChangeCreated, Path: "/a"},
+		{Type: FileChangeModified, Path: "/a"},
+	})
+
+	want := []FileChangeType{FileChangeModified, FileChangeRenamed, FileChangeCreated}
+	if len(got) != len(want) {
+		t.Fatalf("CoalesceFileChanges() = %v, want types %v", got, want)
+	}
+	for i, typ := range want {
+		if got[i].Type != typ {
+			t.Errorf("event %d type = %v, want %v", i, got[i].Type, typ)
+		}
+	}
+}
+
+func TestProject_OnFileChangeBatch(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BatchWindow = 20 * time.Millisecond
+	p := New(WithConfig(cfg))
+
+	batches := make(chan []FileChangeEvent, 10)
+	p.OnFileChangeBatch(func(events []FileChangeEvent) {
+		batches <- events
+	})
+
+	var single int
+	p.OnFileChange(func(FileChangeEvent) { single++ })
+
+	for i := 0; i < 10; i++ {
+		p.handleWatchEvent(watcher.Event{Path: "/p/b.go", Op: watcher.OpWrite, Timestamp: time.Now()})
+		p.handleWatchEvent(watcher.Event{Path: "/p/a.go", Op: watcher.OpWrite, Timestamp: time.Now()})
+	}
+
+	select {
+	case batch := <-batches:
+		if len(batch) != 2 || batch[0].Path != "/p/a.go" || batch[1].Path != "/p/b.go" {
+			t.Errorf("batch = %v, want a.go and b.go modified", batch)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no batch delivered")
+	}
+	if single != 20 {
+		t.Errorf("OnFileChange handler called %d times, want 20", single)
+	}
+
+	select {
+	case batch := <-batches:
+		t.Errorf("unexpected second batch %v", batch)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFileChangeBatcher_Debounce(t *testing.T) {
+	batches := make(chan []FileChangeEvent, 10)
+	b := newFileChangeBatcher(40*time.Millisecond, time.Second, func(events []FileChangeEvent) {
+		batches <- events
+	})
+
+	// Events arriving faster than the window keep extending it
+	for _, path := range []string{"/p/a.go", "/p/b.go", "/p/c.go", "/p/d.go"} {
+		b.add(FileChangeEvent{Path: path, Type: FileChangeModified})
+		time.Sleep(15 * time.Millisecond)
+	}
+	select {
+	case batch := <-batches:
+		t.Fatalf("batch %v delivered while events were arriving", batch)
+	default:
+	}
+
+	select {
+	case batch := <-batches:
+		if len(batch) != 4 {
+			t.Errorf("batch = %v, want 4 events", batch)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no batch delivered")
+	}
+}
+
+func TestFileChangeBatcher_MaxWait(t *testing.T) {
+	batches := make(chan []FileChangeEvent, 10)
+	b := newFileChangeBatcher(40*time.Millisecond, 80*time.Millisecond, func(events []FileChangeEvent) {
+		batches <- events
+	})
+
+	// A steady stream of events is still delivered after maxWait
+	start := time.Now()
+	stop := time.After(500 * time.Millisecond)
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.add(FileChangeEvent{Path: "/p/a.go", Type: FileChangeModified})
+			continue
+		case <-batches:
+			if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+				t.Errorf("first batch after %v, want about 80ms", elapsed)
+			}
+		case <-stop:
+			t.Fatal("no batch delivered while events kept arriving")
+		}
+		return
+	}
+}
@@ -59,6 +59,13 @@
 //	    }
 //	})
 //
+// Bursts of changes, such as a save-all or a git checkout, can be received
+// as one coalesced batch once they pause for Config.BatchWindow instead:
+//
+//	proj.OnFileChangeBatch(func(events []project.FileChangeEvent) {
+//	    // Coalesced events, sorted by path
+//	})
+//
 // # Project Graph
 //
 // The graph tracks relationships between files:
@@ -66,11 +73,35 @@
 //	graph := proj.Graph()
 //	related, err := proj.RelatedFiles(ctx, "/path/to/file.go")
 //
+// # Sessions
+//
+// The editor's open files, their cursors, folds and scroll position, and
+// the active file are persisted in .keystorm/session.json. The editor
+// records the state of each open file before saving, and after restoring
+// reopens the files listed by SessionFiles:
+//
+//	proj.ClearDocumentStates()
+//	proj.SetDocumentState(path, project.DocumentState{Cursors: []int{42}})
+//	err := proj.SaveSession(w)
+//	err = proj.RestoreSession(ctx, r)
+//	files := proj.SessionFiles()
+//
+// # Language Detection
+//
+// DetectLanguage maps a file to an LSP language ID from its name, extension,
+// shebang line or content, with a confidence between 0 and 1, using the
+// langid package shared with the LSP client and the editor. The project's
+// DetectLanguage applies Config.LanguageOverrides and the workspace file
+// associations first, and is what open documents use for LanguageID:
+//
+//	lang, confidence := proj.DetectLanguage("scripts/deploy", content)
+//
 // # Integration Points
 //
 // The project package integrates with:
 //   - Dispatcher: File/project actions (open, save, search)
-//   - LSP: Workspace folders for language servers
+//   - LSP: Workspace folders for language servers, and RenameParticipant
+//     so servers can update imports when RenameFile moves a file
 //   - Context Engine: Project graph for AI prompts
 //   - Event Bus: File change notifications
 //   - Plugin API: ProjectProvider interface
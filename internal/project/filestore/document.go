@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dshills/keystorm/internal/langid"
 	"github.com/dshills/keystorm/internal/project/vfs"
 )
 
@@ -88,7 +89,7 @@ func NewDocument(path string, content []byte, diskModTime time.Time) *Document {
 		OpenedAt:        time.Now(),
 		ModifiedAt:      time.Now(),
 		DiskModTime:     diskModTime,
-		LanguageID:      detectLanguageID(path),
+		LanguageID:      langid.FromPath(path),
 	}
 }
 
@@ -263,108 +264,3 @@ func (d *Document) ContentForSave() []byte {
 
 	return content
 }
-
-// detectLanguageID returns the language identifier based on file extension.
-func detectLanguageID(path string) string {
-	ext := ""
-	for i := len(path) - 1; i >= 0; i-- {
-		if path[i] == '.' {
-			ext = path[i:]
-			break
-		}
-		if path[i] == '/' || path[i] == '\\' {
-			break
-		}
-	}
-
-	switch ext {
-	case ".go":
-		return "go"
-	case ".py":
-		return "python"
-	case ".js":
-		return "javascript"
-	case ".ts":
-		return "typescript"
-	case ".jsx":
-		return "javascriptreact"
-	case ".tsx":
-		return "typescriptreact"
-	case ".rs":
-		return "rust"
-	case ".rb":
-		return "ruby"
-	case ".java":
-		return "java"
-	case ".c":
-		return "c"
-	case ".cpp", ".cc", ".cxx":
-		return "cpp"
-	case ".h", ".hpp":
-		return "cpp"
-	case ".cs":
-		return "csharp"
-	case ".php":
-		return "php"
-	case ".swift":
-		return "swift"
-	case ".kt", ".kts":
-		return "kotlin"
-	case ".scala":
-		return "scala"
-	case ".html", ".htm":
-		return "html"
-	case ".css":
-		return "css"
-	case ".scss":
-		return "scss"
-	case ".less":
-		return "less"
-	case ".json":
-		return "json"
-	case ".yaml", ".yml":
-		return "yaml"
-	case ".xml":
-		return "xml"
-	case ".md", ".markdown":
-		return "markdown"
-	case ".sql":
-		return "sql"
-	case ".sh", ".bash":
-		return "shellscript"
-	case ".ps1":
-		return "powershell"
-	case ".dockerfile":
-		return "dockerfile"
-	case ".lua":
-		return "lua"
-	case ".r", ".R":
-		return "r"
-	case ".jl":
-		return "julia"
-	case ".ex", ".exs":
-		return "elixir"
-	case ".erl":
-		return "erlang"
-	case ".hs":
-		return "haskell"
-	case ".ml", ".mli":
-		return "ocaml"
-	case ".fs", ".fsi", ".fsx":
-		return "fsharp"
-	case ".clj", ".cljs", ".cljc":
-		return "clojure"
-	case ".vim":
-		return "vim"
-	case ".toml":
-		return "toml"
-	case ".ini", ".cfg":
-		return "ini"
-	case ".proto":
-		return "protobuf"
-	case ".graphql", ".gql":
-		return "graphql"
-	default:
-		return "plaintext"
-	}
-}
@@ -347,9 +347,9 @@ func TestDetectLanguageID(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		got := detectLanguageID(tt.path)
+		got := NewDocument(tt.path, nil, time.Time{}).LanguageID
 		if got != tt.want {
-			t.Errorf("detectLanguageID(%q) = %q, want %q", tt.path, got, tt.want)
+			t.Errorf("LanguageID of %q = %q, want %q", tt.path, got, tt.want)
 		}
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dshills/keystorm/internal/langid"
 	perrors "github.com/dshills/keystorm/internal/project/errors"
 	"github.com/dshills/keystorm/internal/project/vfs"
 )
@@ -18,7 +19,8 @@ type FileStore struct {
 	vfs       vfs.VFS
 
 	// Configuration
-	maxFileSize int64 // Maximum file size to open (0 = unlimited)
+	maxFileSize    int64                                    // Maximum file size to open (0 = unlimited)
+	detectLanguage func(path string, content []byte) string // Overrides langid.Detect
 
 	// Event handlers
 	onOpen   []func(doc *Document)
@@ -47,6 +49,25 @@ func WithMaxFileSize(size int64) Option {
 	}
 }
 
+// WithLanguageDetector sets the function that determines the LanguageID
+// of opened and renamed documents. By default it is detected with
+// langid.Detect.
+func WithLanguageDetector(detect func(path string, content []byte) string) Option {
+	return func(fs *FileStore) {
+		fs.detectLanguage = detect
+	}
+}
+
+// languageID returns the language of a document at path with content,
+// using the detector set with WithLanguageDetector if any.
+func (s *FileStore) languageID(path string, content []byte) string {
+	if s.detectLanguage != nil {
+		return s.detectLanguage(path, content)
+	}
+	lang, _ := langid.Detect(path, content)
+	return lang
+}
+
 // NewFileStoreWithOptions creates a new FileStore with options.
 func NewFileStoreWithOptions(vfs vfs.VFS, opts ...Option) *FileStore {
 	store := NewFileStore(vfs)
@@ -103,6 +124,7 @@ func (s *FileStore) Open(ctx context.Context, path string) (*Document, error) {
 
 	// Create document
 	doc := NewDocument(absPath, content, info.ModTime())
+	doc.LanguageID = s.languageID(absPath, doc.Content)
 
 	// Store document
 	s.mu.Lock()
@@ -335,7 +357,7 @@ func (s *FileStore) SaveAs(ctx context.Context, oldPath, newPath string) error {
 	// Update document path and state
 	doc.mu.Lock()
 	doc.Path = newAbsPath
-	doc.LanguageID = detectLanguageID(newAbsPath)
+	doc.LanguageID = s.languageID(newAbsPath, doc.Content)
 	doc.mu.Unlock()
 	doc.MarkSaved(modTime)
 
@@ -628,7 +650,7 @@ func (s *FileStore) RenameFile(ctx context.Context, oldPath, newPath string) err
 	if isOpen {
 		doc.mu.Lock()
 		doc.Path = newAbsPath
-		doc.LanguageID = detectLanguageID(newAbsPath)
+		doc.LanguageID = s.languageID(newAbsPath, doc.Content)
 		doc.mu.Unlock()
 
 		delete(s.documents, oldAbsPath)
@@ -3,6 +3,8 @@ package graph
 import (
 	"path/filepath"
 	"time"
+
+	"github.com/dshills/keystorm/internal/langid"
 )
 
 // NodeID uniquely identifies a node in the graph.
@@ -107,7 +109,7 @@ func NewFileNode(path string) Node {
 		Type:     NodeTypeFile,
 		Path:     path,
 		Name:     filepath.Base(path),
-		Language: detectLanguage(path),
+		Language: langid.FromPath(path),
 	}
 }
 
@@ -152,7 +154,7 @@ func NewFunctionNode(path, name string, startLine, endLine int) Node {
 		Type:     NodeTypeFunction,
 		Path:     path,
 		Name:     name,
-		Language: detectLanguage(path),
+		Language: langid.FromPath(path),
 		Metadata: NodeMeta{
 			StartLine: startLine,
 			EndLine:   endLine,
@@ -167,7 +169,7 @@ func NewTestNode(path, targetPath string) Node {
 		Type:     NodeTypeTest,
 		Path:     path,
 		Name:     filepath.Base(path),
-		Language: detectLanguage(path),
+		Language: langid.FromPath(path),
 		Metadata: NodeMeta{
 			TestTarget: targetPath,
 		},
@@ -193,62 +195,3 @@ func (n Node) IsCodeNode() bool {
 		return false
 	}
 }
-
-// detectLanguage detects the language from a file path.
-func detectLanguage(path string) string {
-	ext := filepath.Ext(path)
-	switch ext {
-	case ".go":
-		return "go"
-	case ".ts":
-		return "typescript"
-	case ".tsx":
-		return "typescriptreact"
-	case ".js":
-		return "javascript"
-	case ".jsx":
-		return "javascriptreact"
-	case ".py":
-		return "python"
-	case ".rs":
-		return "rust"
-	case ".rb":
-		return "ruby"
-	case ".java":
-		return "java"
-	case ".c", ".h":
-		return "c"
-	case ".cpp", ".cc", ".cxx", ".hpp":
-		return "cpp"
-	case ".cs":
-		return "csharp"
-	case ".swift":
-		return "swift"
-	case ".kt":
-		return "kotlin"
-	case ".scala":
-		return "scala"
-	case ".md":
-		return "markdown"
-	case ".json":
-		return "json"
-	case ".yaml", ".yml":
-		return "yaml"
-	case ".toml":
-		return "toml"
-	case ".xml":
-		return "xml"
-	case ".html", ".htm":
-		return "html"
-	case ".css":
-		return "css"
-	case ".scss":
-		return "scss"
-	case ".sql":
-		return "sql"
-	case ".sh", ".bash":
-		return "shellscript"
-	default:
-		return "plaintext"
-	}
-}
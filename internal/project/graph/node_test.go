@@ -158,49 +158,3 @@ func TestNode_IsCodeNode(t *testing.T) {
 		}
 	}
 }
-
-func TestDetectLanguage(t *testing.T) {
-	tests := []struct {
-		path string
-		want string
-	}{
-		{"/path/to/file.go", "go"},
-		{"/path/to/file.ts", "typescript"},
-		{"/path/to/file.tsx", "typescriptreact"},
-		{"/path/to/file.js", "javascript"},
-		{"/path/to/file.jsx", "javascriptreact"},
-		{"/path/to/file.py", "python"},
-		{"/path/to/file.rs", "rust"},
-		{"/path/to/file.rb", "ruby"},
-		{"/path/to/file.java", "java"},
-		{"/path/to/file.c", "c"},
-		{"/path/to/file.h", "c"},
-		{"/path/to/file.cpp", "cpp"},
-		{"/path/to/file.hpp", "cpp"},
-		{"/path/to/file.cs", "csharp"},
-		{"/path/to/file.swift", "swift"},
-		{"/path/to/file.kt", "kotlin"},
-		{"/path/to/file.scala", "scala"},
-		{"/path/to/file.md", "markdown"},
-		{"/path/to/file.json", "json"},
-		{"/path/to/file.yaml", "yaml"},
-		{"/path/to/file.yml", "yaml"},
-		{"/path/to/file.toml", "toml"},
-		{"/path/to/file.xml", "xml"},
-		{"/path/to/file.html", "html"},
-		{"/path/to/file.css", "css"},
-		{"/path/to/file.scss", "scss"},
-		{"/path/to/file.sql", "sql"},
-		{"/path/to/file.sh", "shellscript"},
-		{"/path/to/file.bash", "shellscript"},
-		{"/path/to/file.unknown", "plaintext"},
-		{"/path/to/file", "plaintext"},
-	}
-
-	for _, tt := range tests {
-		got := detectLanguage(tt.path)
-		if got != tt.want {
-			t.Errorf("detectLanguage(%q) = %q, want %q", tt.path, got, tt.want)
-		}
-	}
-}
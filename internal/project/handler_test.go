@@ -2,6 +2,7 @@ package project
 
 import (
 	"context"
+	"io"
 	"testing"
 
 	"github.com/dshills/keystorm/internal/dispatcher/execctx"
@@ -160,6 +161,10 @@ func (m *mockProject) RelatedFiles(ctx context.Context, path string) ([]RelatedF
 	}, nil
 }
 
+func (m *mockProject) DetectLanguage(path string, content []byte) (string, float64) {
+	return DetectLanguage(path, content)
+}
+
 func (m *mockProject) OpenDocuments() []*filestore.Document {
 	var docs []*filestore.Document
 	for _, doc := range m.openedFiles {
@@ -183,8 +188,12 @@ func (m *mockProject) DirtyDocuments() []*filestore.Document {
 
 func (m *mockProject) OnFileChange(handler func(FileChangeEvent)) {}
 
+func (m *mockProject) OnFileChangeBatch(handler func([]FileChangeEvent)) {}
+
 func (m *mockProject) OnWorkspaceChange(handler func(workspace.ChangeEvent)) {}
 
+func (m *mockProject) AddRenameParticipant(participant RenameParticipant) {}
+
 func (m *mockProject) IndexStatus() IndexStatus {
 	return IndexStatus{Status: "idle", TotalFiles: 100, IndexedFiles: 50}
 }
@@ -193,6 +202,26 @@ func (m *mockProject) WatcherStatus() WatcherStatus {
 	return WatcherStatus{WatchedPaths: 10, TotalEvents: 100}
 }
 
+func (m *mockProject) SaveSession(w io.Writer) error { return nil }
+
+func (m *mockProject) RestoreSession(ctx context.Context, r io.Reader) error { return nil }
+
+func (m *mockProject) SessionWarnings() []error { return nil }
+
+func (m *mockProject) SessionFiles() []string { return nil }
+
+func (m *mockProject) SetDocumentState(path string, state DocumentState) {}
+
+func (m *mockProject) ClearDocumentStates() {}
+
+func (m *mockProject) DocumentState(path string) (DocumentState, bool) {
+	return DocumentState{}, false
+}
+
+func (m *mockProject) SetActiveFile(path string) {}
+
+func (m *mockProject) ActiveFile() string { return "" }
+
 // Helper to create action with args
 func actionWithArgs(name string, args map[string]interface{}) input.Action {
 	return input.Action{
@@ -0,0 +1,38 @@
+package project
+
+import (
+	"path/filepath"
+
+	"github.com/dshills/keystorm/internal/langid"
+)
+
+// confidenceOverride is the confidence of a language set by a user
+// override.
+const confidenceOverride = 1.0
+
+// DetectLanguage returns the language of a file and how confident the
+// detection is, from 0 (no idea, reported as "plaintext") to 1. It is
+// langid.Detect; user overrides are applied by
+// DefaultProject.DetectLanguage.
+func DetectLanguage(path string, content []byte) (language string, confidence float64) {
+	return langid.Detect(path, content)
+}
+
+// lookupLanguageOverride returns the language an override map assigns to
+// path. Keys are file names ("Justfile") or extension patterns ("*.h");
+// file names take precedence.
+func lookupLanguageOverride(overrides map[string]string, path string) (string, bool) {
+	if len(overrides) == 0 {
+		return "", false
+	}
+	name := filepath.Base(path)
+	if lang, ok := overrides[name]; ok {
+		return lang, true
+	}
+	if ext := filepath.Ext(name); ext != "" {
+		if lang, ok := overrides["*"+ext]; ok {
+			return lang, true
+		}
+	}
+	return "", false
+}
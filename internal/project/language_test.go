@@ -0,0 +1,133 @@
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/langid"
+	"github.com/dshills/keystorm/internal/project/vfs"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+	}{
+		{"extension", "/src/main.go", "", "go"},
+		{"extension case", "/src/README.MD", "", "markdown"},
+		{"makefile", "/src/Makefile", "all:\n\tgo build", "makefile"},
+		{"dockerfile", "/src/Dockerfile", "FROM alpine", "dockerfile"},
+		{"dockerfile variant", "/src/Dockerfile.dev", "FROM alpine", "dockerfile"},
+		{"c header", "/src/util.h", "int add(int a, int b);", "c"},
+		{"cpp header", "/src/util.h", "namespace util {\nclass Adder;\n}", "cpp"},
+		{"xml content", "/src/data", "<?xml version=\"1.0\"?>", "xml"},
+		{"json content", "/src/data", "{\"key\": 1}", "json"},
+		{"unknown", "/src/notes", "just some words", "plaintext"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := DetectLanguage(tt.path, []byte(tt.content))
+			if got != tt.want {
+				t.Errorf("DetectLanguage(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLanguageShebang(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"#!/bin/sh\necho hi", "shellscript"},
+		{"#!/bin/bash -e\necho hi", "shellscript"},
+		{"#!/usr/bin/env python3\nprint('hi')", "python"},
+		{"#!/usr/bin/env python3.12\nprint('hi')", "python"},
+		{"#!/usr/bin/env -S node --experimental\nconsole.log(1)", "javascript"},
+		{"#!/usr/bin/ruby -w\nputs 1", "ruby"},
+	}
+
+	for _, tt := range tests {
+		got, confidence := DetectLanguage("/usr/local/bin/tool", []byte(tt.content))
+		if got != tt.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+		if confidence != langid.ConfidenceShebang {
+			t.Errorf("DetectLanguage(%q) confidence = %v, want %v", tt.content, confidence, langid.ConfidenceShebang)
+		}
+	}
+
+	// The extension wins over the shebang
+	if got, _ := DetectLanguage("/src/run.py", []byte("#!/bin/sh\n")); got != "python" {
+		t.Errorf("DetectLanguage(run.py) = %q, want python", got)
+	}
+	// An unknown interpreter is not a match
+	if got, confidence := DetectLanguage("/src/run", []byte("#!/usr/bin/awk -f\n")); got != "plaintext" || confidence != 0 {
+		t.Errorf("DetectLanguage(awk) = %q, %v, want plaintext, 0", got, confidence)
+	}
+}
+
+func TestProject_DetectLanguageOverride(t *testing.T) {
+	memfs := vfs.NewMemFS()
+	_ = memfs.Mkdir("/workspace", 0755)
+	_ = memfs.WriteFile("/workspace/api.h", []byte("int api(void);"), 0644)
+	_ = memfs.WriteFile("/workspace/Justfile", []byte("build:\n\tgo build"), 0644)
+
+	cfg := DefaultConfig()
+	cfg.EnableContentIndex = false
+	cfg.EnableGraph = false
+	cfg.LanguageOverrides = map[string]string{
+		"*.h":      "cpp",
+		"Justfile": "makefile",
+	}
+
+	p := New(WithVFS(memfs), WithConfig(cfg))
+	ctx := context.Background()
+	if err := p.Open(ctx, "/workspace"); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer p.Close(ctx)
+
+	lang, confidence := p.DetectLanguage("/workspace/api.h", []byte("int api(void);"))
+	if lang != "cpp" || confidence != 1 {
+		t.Errorf("DetectLanguage(api.h) = %q, %v, want cpp, 1", lang, confidence)
+	}
+	if lang, _ := p.DetectLanguage("/workspace/main.go", nil); lang != "go" {
+		t.Errorf("DetectLanguage(main.go) = %q, want go", lang)
+	}
+
+	// Opened documents get their language from the project
+	doc, err := p.OpenFile(ctx, "/workspace/Justfile")
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	if doc.LanguageID != "makefile" {
+		t.Errorf("LanguageID = %q, want makefile", doc.LanguageID)
+	}
+}
+
+func TestProject_DetectLanguageWithoutOverrides(t *testing.T) {
+	memfs := vfs.NewMemFS()
+	_ = memfs.Mkdir("/workspace", 0755)
+
+	cfg := DefaultConfig()
+	cfg.EnableContentIndex = false
+	cfg.EnableGraph = false
+
+	p := New(WithVFS(memfs), WithConfig(cfg))
+	ctx := context.Background()
+	if err := p.Open(ctx, "/workspace"); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer p.Close(ctx)
+
+	// The workspace has no built-in associations that would shadow
+	// content detection
+	lang, confidence := p.DetectLanguage("/workspace/util.h", []byte("namespace util {}"))
+	if lang != "cpp" || confidence != langid.ConfidenceContent {
+		t.Errorf("DetectLanguage(util.h) = %q, %v, want cpp, %v", lang, confidence, langid.ConfidenceContent)
+	}
+}
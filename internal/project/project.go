@@ -53,6 +53,9 @@ type Project interface {
 	Graph() graph.Graph
 	RelatedFiles(ctx context.Context, path string) ([]RelatedFile, error)
 
+	// Language detection
+	DetectLanguage(path string, content []byte) (language string, confidence float64)
+
 	// Open documents
 	OpenDocuments() []*filestore.Document
 	GetDocument(path string) (*filestore.Document, bool)
@@ -61,11 +64,24 @@ type Project interface {
 
 	// Events
 	OnFileChange(handler func(FileChangeEvent))
+	OnFileChangeBatch(handler func([]FileChangeEvent))
 	OnWorkspaceChange(handler func(workspace.ChangeEvent))
+	AddRenameParticipant(participant RenameParticipant)
 
 	// Status
 	IndexStatus() IndexStatus
 	WatcherStatus() WatcherStatus
+
+	// Session
+	SaveSession(w io.Writer) error
+	RestoreSession(ctx context.Context, r io.Reader) error
+	SessionWarnings() []error
+	SessionFiles() []string
+	SetDocumentState(path string, state DocumentState)
+	DocumentState(path string) (DocumentState, bool)
+	ClearDocumentStates()
+	SetActiveFile(path string)
+	ActiveFile() string
 }
 
 // FindOptions configures file search behavior.
@@ -186,9 +202,21 @@ type DefaultProject struct {
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 
+	// Session state
+	docStates       map[string]DocumentState
+	activeFile      string
+	sessionWarnings []error
+
 	// Event handlers
 	fileChangeHandlers      []func(FileChangeEvent)
+	fileChangeBatchHandlers []func([]FileChangeEvent)
 	workspaceChangeHandlers []func(workspace.ChangeEvent)
+
+	// Participants in RenameFile
+	renameParticipants []RenameParticipant
+
+	// batcher collects events for fileChangeBatchHandlers
+	batcher *fileChangeBatcher
 }
 
 // Config holds project configuration.
@@ -202,6 +230,14 @@ type Config struct {
 	// WatchDebounceDelay is the delay for debouncing file watch events
 	WatchDebounceDelay time.Duration
 
+	// BatchWindow is how long OnFileChangeBatch handlers wait for file
+	// change events to stop before a batch is delivered
+	BatchWindow time.Duration
+
+	// BatchMaxWait is the longest a batch is held back while file change
+	// events keep arriving
+	BatchMaxWait time.Duration
+
 	// ExcludePatterns are glob patterns to exclude from indexing/watching
 	ExcludePatterns []string
 
@@ -210,6 +246,10 @@ type Config struct {
 
 	// EnableGraph enables project graph building
 	EnableGraph bool
+
+	// LanguageOverrides maps file names ("Justfile") or extension patterns
+	// ("*.h") to language IDs, taking precedence over detection
+	LanguageOverrides map[string]string
 }
 
 // DefaultConfig returns sensible default configuration.
@@ -218,6 +258,8 @@ func DefaultConfig() Config {
 		MaxFileSize:        10 * 1024 * 1024, // 10MB
 		IndexWorkers:       4,
 		WatchDebounceDelay: 100 * time.Millisecond,
+		BatchWindow:        DefaultBatchWindow,
+		BatchMaxWait:       DefaultBatchMaxWait,
 		ExcludePatterns: []string{
 			"**/.git/**",
 			"**/node_modules/**",
@@ -304,7 +346,13 @@ func (p *DefaultProject) Open(ctx context.Context, roots ...string) error {
 	}
 
 	// Initialize file store
-	p.fileStore = filestore.NewFileStoreWithOptions(p.vfs, filestore.WithMaxFileSize(p.config.MaxFileSize))
+	p.fileStore = filestore.NewFileStoreWithOptions(p.vfs,
+		filestore.WithMaxFileSize(p.config.MaxFileSize),
+		filestore.WithLanguageDetector(func(path string, content []byte) string {
+			lang, _ := p.DetectLanguage(path, content)
+			return lang
+		}),
+	)
 
 	// Initialize file index
 	p.fileIndex = index.NewFileIndex()
@@ -406,6 +454,7 @@ func (p *DefaultProject) Close(ctx context.Context) error {
 	}
 
 	// Release lock before waiting for goroutines (they may need to acquire lock)
+	batcher := p.batcher
 	p.mu.Unlock()
 
 	// Wait for goroutines to finish with timeout
@@ -423,6 +472,11 @@ func (p *DefaultProject) Close(ctx context.Context) error {
 		// via the cancelled p.ctx but we won't wait for them
 	}
 
+	// Deliver the last batch of file changes
+	if batcher != nil {
+		batcher.flush()
+	}
+
 	// Reacquire lock for final cleanup
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -445,6 +499,9 @@ func (p *DefaultProject) Close(ctx context.Context) error {
 	p.workspace = nil
 	p.ctx = nil
 	p.cancel = nil
+	p.docStates = nil
+	p.activeFile = ""
+	p.sessionWarnings = nil
 	p.open = false
 	return nil
 }
@@ -502,6 +559,30 @@ func (p *DefaultProject) Workspace() *workspace.Workspace {
 	return p.workspace
 }
 
+// DetectLanguage returns the language of a file and the confidence of the
+// detection. Config.LanguageOverrides and the workspace file associations
+// are consulted first and reported with full confidence; otherwise the
+// package-level DetectLanguage is used. Open documents get their
+// LanguageID from this method.
+func (p *DefaultProject) DetectLanguage(path string, content []byte) (string, float64) {
+	p.mu.RLock()
+	overrides := p.config.LanguageOverrides
+	ws := p.workspace
+	p.mu.RUnlock()
+
+	if lang, ok := lookupLanguageOverride(overrides, path); ok {
+		return lang, confidenceOverride
+	}
+	if ws != nil {
+		if cfg := ws.Config(); cfg != nil {
+			if lang := cfg.GetLanguageID(path); lang != "" {
+				return lang, confidenceOverride
+			}
+		}
+	}
+	return DetectLanguage(path, content)
+}
+
 // OpenFile opens a file and returns its Document.
 func (p *DefaultProject) OpenFile(ctx context.Context, path string) (*filestore.Document, error) {
 	p.mu.RLock()
@@ -668,6 +749,14 @@ func (p *DefaultProject) RenameFile(ctx context.Context, oldPath, newPath string
 		}
 	}
 
+	// Let participants update references to the file before it moves
+	participants := p.renameParticipantsSnapshot()
+	for _, participant := range participants {
+		if err := participant.WillRename(ctx, oldPath, newPath); err != nil {
+			return NewPathError("rename", oldPath, err)
+		}
+	}
+
 	// Close if open
 	_ = store.Close(ctx, oldPath, true)
 
@@ -676,6 +765,10 @@ func (p *DefaultProject) RenameFile(ctx context.Context, oldPath, newPath string
 		return NewPathError("rename", oldPath, err)
 	}
 
+	for _, participant := range participants {
+		participant.DidRename(ctx, oldPath, newPath)
+	}
+
 	return nil
 }
 
@@ -1056,13 +1149,17 @@ func (p *DefaultProject) Load(fileIndexReader, contentIndexReader io.Reader) err
 }
 
 // processWatcherEvents processes file system events from the watcher.
+// The watcher is read under the lock, since Close clears it.
 func (p *DefaultProject) processWatcherEvents(ctx context.Context) {
-	if p.watcher == nil {
+	p.mu.RLock()
+	w := p.watcher
+	p.mu.RUnlock()
+	if w == nil {
 		return
 	}
 
-	events := p.watcher.Events()
-	errors := p.watcher.Errors()
+	events := w.Events()
+	errors := w.Errors()
 
 	for {
 		select {
@@ -1104,6 +1201,7 @@ func (p *DefaultProject) handleWatchEvent(event watcher.Event) {
 	increIndex := p.increIndex
 	handlers := make([]func(FileChangeEvent), len(p.fileChangeHandlers))
 	copy(handlers, p.fileChangeHandlers)
+	batcher := p.batcher
 	p.mu.RUnlock()
 
 	// Update incremental index (outside lock to avoid blocking)
@@ -1126,6 +1224,10 @@ func (p *DefaultProject) handleWatchEvent(event watcher.Event) {
 	for _, h := range handlers {
 		h(changeEvent)
 	}
+
+	if batcher != nil {
+		batcher.add(changeEvent)
+	}
 }
 
 // buildGraph builds the project graph in the background.
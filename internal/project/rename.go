@@ -0,0 +1,32 @@
+package project
+
+import "context"
+
+// RenameParticipant takes part in file renames made through RenameFile,
+// such as a language server updating the imports of a moved file.
+type RenameParticipant interface {
+	// WillRename is called before oldPath is renamed to newPath, so edits
+	// to other files can be applied first. Returning an error aborts the
+	// rename.
+	WillRename(ctx context.Context, oldPath, newPath string) error
+
+	// DidRename is called after oldPath was renamed to newPath.
+	DidRename(ctx context.Context, oldPath, newPath string)
+}
+
+// AddRenameParticipant registers p to take part in file renames.
+// Participants are called in registration order.
+func (p *DefaultProject) AddRenameParticipant(participant RenameParticipant) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.renameParticipants = append(p.renameParticipants, participant)
+}
+
+// renameParticipantsSnapshot returns a copy of the registered participants.
+func (p *DefaultProject) renameParticipantsSnapshot() []RenameParticipant {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	participants := make([]RenameParticipant, len(p.renameParticipants))
+	copy(participants, p.renameParticipants)
+	return participants
+}
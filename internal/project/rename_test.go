@@ -0,0 +1,63 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/project/vfs"
+)
+
+// recordingParticipant records rename calls and can veto renames.
+type recordingParticipant struct {
+	calls []string
+	err   error
+}
+
+func (r *recordingParticipant) WillRename(ctx context.Context, oldPath, newPath string) error {
+	r.calls = append(r.calls, "will "+oldPath+" "+newPath)
+	return r.err
+}
+
+func (r *recordingParticipant) DidRename(ctx context.Context, oldPath, newPath string) {
+	r.calls = append(r.calls, "did "+oldPath+" "+newPath)
+}
+
+func TestProject_RenameParticipants(t *testing.T) {
+	memfs := vfs.NewMemFS()
+	_ = memfs.Mkdir("/workspace", 0755)
+	_ = memfs.WriteFile("/workspace/old.go", []byte("package main"), 0644)
+
+	cfg := DefaultConfig()
+	cfg.EnableContentIndex = false
+	cfg.EnableGraph = false
+
+	p := New(WithVFS(memfs), WithConfig(cfg))
+	ctx := context.Background()
+	_ = p.Open(ctx, "/workspace")
+	defer p.Close(ctx)
+
+	participant := &recordingParticipant{}
+	p.AddRenameParticipant(participant)
+
+	if err := p.RenameFile(ctx, "/workspace/old.go", "/workspace/new.go"); err != nil {
+		t.Fatalf("RenameFile() error = %v", err)
+	}
+	want := []string{"will /workspace/old.go /workspace/new.go", "did /workspace/old.go /workspace/new.go"}
+	if len(participant.calls) != len(want) || participant.calls[0] != want[0] || participant.calls[1] != want[1] {
+		t.Errorf("participant calls = %q, want %q", participant.calls, want)
+	}
+
+	// A participant error aborts the rename
+	veto := errors.New("edits failed")
+	participant.calls, participant.err = nil, veto
+	if err := p.RenameFile(ctx, "/workspace/new.go", "/workspace/other.go"); !errors.Is(err, veto) {
+		t.Errorf("RenameFile() error = %v, want %v", err, veto)
+	}
+	if !memfs.Exists("/workspace/new.go") || memfs.Exists("/workspace/other.go") {
+		t.Error("file should not move when a participant fails")
+	}
+	if len(participant.calls) != 1 {
+		t.Errorf("participant calls = %q, want only WillRename", participant.calls)
+	}
+}
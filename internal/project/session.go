@@ -0,0 +1,212 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+)
+
+// SessionFile is the location of the session file relative to the workspace
+// root.
+const SessionFile = ".keystorm/session.json"
+
+// sessionVersion is the current session file format version.
+const sessionVersion = 1
+
+// SessionPath returns the session file path for a workspace root.
+func SessionPath(root string) string {
+	return filepath.Join(root, filepath.FromSlash(SessionFile))
+}
+
+// DocumentState is the per-file editor state persisted in a session.
+type DocumentState struct {
+	// Cursors are the byte offsets of each cursor, primary first.
+	Cursors []int `json:"cursors,omitempty"`
+
+	// Folds are the collapsed line ranges.
+	Folds []FoldState `json:"folds,omitempty"`
+
+	// ScrollLine is the first visible line.
+	ScrollLine uint32 `json:"scrollLine,omitempty"`
+}
+
+// FoldState is a collapsed range of lines (0-indexed, inclusive).
+type FoldState struct {
+	StartLine uint32 `json:"startLine"`
+	EndLine   uint32 `json:"endLine"`
+}
+
+// session is the serialized form of a workspace session.
+type session struct {
+	Version    int               `json:"version"`
+	ActiveFile string            `json:"activeFile,omitempty"`
+	Files      []sessionDocument `json:"files"`
+}
+
+// sessionDocument is an open document within a session.
+type sessionDocument struct {
+	Path string `json:"path"`
+	DocumentState
+}
+
+// SetDocumentState records the editor state of an open document so that it
+// is included in the next saved session. The editor calls this with its
+// per-buffer state (cursors, folds, scroll position) before saving.
+func (p *DefaultProject) SetDocumentState(path string, state DocumentState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.docStates == nil {
+		p.docStates = make(map[string]DocumentState)
+	}
+	p.docStates[p.sessionKey(path)] = state
+}
+
+// DocumentState returns the recorded editor state of a document.
+// After RestoreSession this is the state saved for each restored file.
+func (p *DefaultProject) DocumentState(path string) (DocumentState, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	state, ok := p.docStates[p.sessionKey(path)]
+	return state, ok
+}
+
+// SetActiveFile records the file that has focus in the editor.
+func (p *DefaultProject) SetActiveFile(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if path == "" {
+		p.activeFile = ""
+		return
+	}
+	p.activeFile = p.sessionKey(path)
+}
+
+// ActiveFile returns the file that has focus, or "" if none was recorded.
+func (p *DefaultProject) ActiveFile() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.activeFile
+}
+
+// SaveSession writes the documents whose state was recorded with
+// SetDocumentState, and the active file, to w as JSON. Documents are
+// written in path order.
+func (p *DefaultProject) SaveSession(w io.Writer) error {
+	p.mu.RLock()
+	if !p.open {
+		p.mu.RUnlock()
+		return ErrNotOpen
+	}
+
+	s := session{Version: sessionVersion, Files: []sessionDocument{}}
+	for path, state := range p.docStates {
+		s.Files = append(s.Files, sessionDocument{
+			Path:          path,
+			DocumentState: state,
+		})
+	}
+	if _, ok := p.docStates[p.activeFile]; ok {
+		s.ActiveFile = p.activeFile
+	}
+	p.mu.RUnlock()
+
+	sort.Slice(s.Files, func(i, j int) bool {
+		return s.Files[i].Path < s.Files[j].Path
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// RestoreSession reads a session written by SaveSession and replaces the
+// recorded document states and active file with its contents. Files are
+// not opened; the editor opens those listed by SessionFiles and applies
+// their DocumentState.
+//
+// Files that no longer exist (e.g. deleted since the session was saved)
+// are skipped; the reasons are available from SessionWarnings.
+func (p *DefaultProject) RestoreSession(ctx context.Context, r io.Reader) error {
+	if !p.IsOpen() {
+		return ErrNotOpen
+	}
+
+	var s session
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return err
+	}
+
+	var warnings []error
+	states := make(map[string]DocumentState, len(s.Files))
+	for _, f := range s.Files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		path := p.sessionKey(f.Path)
+		if _, err := p.vfs.Stat(path); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				err = NewPathError("restore", f.Path, ErrNotFound)
+			}
+			warnings = append(warnings, err)
+			continue
+		}
+		states[path] = f.DocumentState
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.docStates = states
+	p.activeFile = ""
+	if _, ok := states[s.ActiveFile]; ok {
+		p.activeFile = s.ActiveFile
+	}
+	p.sessionWarnings = warnings
+	return nil
+}
+
+// SessionFiles returns the files with recorded document state, in path
+// order. After RestoreSession these are the files to reopen.
+func (p *DefaultProject) SessionFiles() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	files := make([]string, 0, len(p.docStates))
+	for path := range p.docStates {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// ClearDocumentStates forgets the state recorded for every document, so
+// that the next session contains only the documents recorded afterwards.
+func (p *DefaultProject) ClearDocumentStates() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.docStates = nil
+}
+
+// SessionWarnings returns the files skipped by the last RestoreSession.
+func (p *DefaultProject) SessionWarnings() []error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]error(nil), p.sessionWarnings...)
+}
+
+// sessionKey returns the absolute path used to key session state. It only
+// reads p.vfs, which is fixed at construction, so it may be called with or
+// without the lock.
+func (p *DefaultProject) sessionKey(path string) string {
+	if abs, err := p.vfs.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,158 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/project/vfs"
+)
+
+func newSessionTestProject(t *testing.T, memfs *vfs.MemFS) *DefaultProject {
+	t.Helper()
+
+	cfg := DefaultConfig()
+	cfg.EnableContentIndex = false
+	cfg.EnableGraph = false
+
+	p := New(WithVFS(memfs), WithConfig(cfg))
+	if err := p.Open(context.Background(), "/ws"); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { p.Close(context.Background()) })
+	return p
+}
+
+func TestSession_RoundTrip(t *testing.T) {
+	memfs := vfs.NewMemFS()
+	_ = memfs.MkdirAll("/ws/pkg", 0755)
+	_ = memfs.WriteFile("/ws/a.go", []byte("package a\n"), 0644)
+	_ = memfs.WriteFile("/ws/b.go", []byte("package b\n\nfunc B() {}\n"), 0644)
+	_ = memfs.WriteFile("/ws/pkg/c.go", []byte("package pkg\n"), 0644)
+
+	ctx := context.Background()
+	p := newSessionTestProject(t, memfs)
+
+	states := map[string]DocumentState{
+		"/ws/a.go":     {Cursors: []int{3}},
+		"/ws/b.go":     {Cursors: []int{11, 2}, Folds: []FoldState{{StartLine: 2, EndLine: 3}}, ScrollLine: 1},
+		"/ws/pkg/c.go": {Cursors: []int{8}, ScrollLine: 4},
+	}
+	for path, state := range states {
+		p.SetDocumentState(path, state)
+	}
+	p.SetActiveFile("/ws/b.go")
+
+	var buf bytes.Buffer
+	if err := p.SaveSession(&buf); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	restored := newSessionTestProject(t, memfs)
+	if err := restored.RestoreSession(ctx, &buf); err != nil {
+		t.Fatalf("RestoreSession() error = %v", err)
+	}
+
+	wantFiles := []string{"/ws/a.go", "/ws/b.go", "/ws/pkg/c.go"}
+	if got := restored.SessionFiles(); fmt.Sprint(got) != fmt.Sprint(wantFiles) {
+		t.Errorf("SessionFiles() = %v, want %v", got, wantFiles)
+	}
+	if got := len(restored.OpenDocuments()); got != 0 {
+		t.Errorf("OpenDocuments() = %d, want 0; files are reopened by the editor", got)
+	}
+	for path, want := range states {
+		got, ok := restored.DocumentState(path)
+		if !ok {
+			t.Errorf("DocumentState(%s) missing", path)
+			continue
+		}
+		if len(got.Cursors) != len(want.Cursors) {
+			t.Errorf("%s cursors = %v, want %v", path, got.Cursors, want.Cursors)
+		} else {
+			for i := range want.Cursors {
+				if got.Cursors[i] != want.Cursors[i] {
+					t.Errorf("%s cursors = %v, want %v", path, got.Cursors, want.Cursors)
+					break
+				}
+			}
+		}
+		if len(got.Folds) != len(want.Folds) {
+			t.Errorf("%s folds = %v, want %v", path, got.Folds, want.Folds)
+		}
+		if got.ScrollLine != want.ScrollLine {
+			t.Errorf("%s ScrollLine = %d, want %d", path, got.ScrollLine, want.ScrollLine)
+		}
+	}
+	if got := restored.ActiveFile(); got != "/ws/b.go" {
+		t.Errorf("ActiveFile() = %q, want %q", got, "/ws/b.go")
+	}
+	if w := restored.SessionWarnings(); len(w) != 0 {
+		t.Errorf("SessionWarnings() = %v, want none", w)
+	}
+}
+
+func TestSession_RestoreMissingFile(t *testing.T) {
+	memfs := vfs.NewMemFS()
+	_ = memfs.MkdirAll("/ws", 0755)
+	_ = memfs.WriteFile("/ws/keep.txt", []byte("keep"), 0644)
+	_ = memfs.WriteFile("/ws/gone.txt", []byte("gone"), 0644)
+
+	ctx := context.Background()
+	p := newSessionTestProject(t, memfs)
+	for _, path := range []string{"/ws/keep.txt", "/ws/gone.txt"} {
+		p.SetDocumentState(path, DocumentState{})
+	}
+	p.SetActiveFile("/ws/gone.txt")
+
+	var buf bytes.Buffer
+	if err := p.SaveSession(&buf); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	_ = memfs.Remove("/ws/gone.txt")
+
+	restored := newSessionTestProject(t, memfs)
+	if err := restored.RestoreSession(ctx, &buf); err != nil {
+		t.Fatalf("RestoreSession() error = %v", err)
+	}
+
+	if got := restored.SessionFiles(); len(got) != 1 || got[0] != "/ws/keep.txt" {
+		t.Errorf("SessionFiles() = %v, want [/ws/keep.txt]", got)
+	}
+	warnings := restored.SessionWarnings()
+	if len(warnings) != 1 || !IsNotFound(warnings[0]) {
+		t.Errorf("SessionWarnings() = %v, want one not-found warning", warnings)
+	}
+	if got := restored.ActiveFile(); got != "" {
+		t.Errorf("ActiveFile() = %q, want empty", got)
+	}
+}
+
+func TestSession_ClearDocumentStates(t *testing.T) {
+	memfs := vfs.NewMemFS()
+	_ = memfs.MkdirAll("/ws", 0755)
+
+	p := newSessionTestProject(t, memfs)
+	p.SetDocumentState("/ws/closed.txt", DocumentState{Cursors: []int{1}})
+	p.ClearDocumentStates()
+	p.SetDocumentState("/ws/open.txt", DocumentState{Cursors: []int{2}})
+
+	var buf bytes.Buffer
+	if err := p.SaveSession(&buf); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("closed.txt")) {
+		t.Errorf("session contains a cleared document:\n%s", buf.String())
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("open.txt")) {
+		t.Errorf("session is missing a recorded document:\n%s", buf.String())
+	}
+}
+
+func TestSession_NotOpen(t *testing.T) {
+	p := New(WithVFS(vfs.NewMemFS()))
+	var buf bytes.Buffer
+	if err := p.SaveSession(&buf); err != ErrNotOpen {
+		t.Errorf("SaveSession() error = %v, want ErrNotOpen", err)
+	}
+}
@@ -26,7 +26,8 @@ type Config struct {
 	// IndexingConcurrency is the number of parallel indexing workers.
 	IndexingConcurrency int `json:"indexing_concurrency,omitempty"`
 
-	// FileAssociations maps file patterns to language IDs.
+	// FileAssociations maps file patterns to language IDs. They override
+	// the languages detected by langid.Detect.
 	FileAssociations map[string]string `json:"file_associations,omitempty"`
 
 	// EditorSettings holds editor-specific settings.
@@ -83,45 +84,7 @@ func DefaultConfig() *Config {
 		},
 		MaxFileSize:         10 * 1024 * 1024, // 10 MB
 		IndexingConcurrency: 4,
-		FileAssociations: map[string]string{
-			"*.go":         "go",
-			"*.mod":        "gomod",
-			"*.sum":        "gosum",
-			"*.ts":         "typescript",
-			"*.tsx":        "typescriptreact",
-			"*.js":         "javascript",
-			"*.jsx":        "javascriptreact",
-			"*.py":         "python",
-			"*.rs":         "rust",
-			"*.java":       "java",
-			"*.c":          "c",
-			"*.cpp":        "cpp",
-			"*.h":          "c",
-			"*.hpp":        "cpp",
-			"*.cs":         "csharp",
-			"*.rb":         "ruby",
-			"*.php":        "php",
-			"*.swift":      "swift",
-			"*.kt":         "kotlin",
-			"*.scala":      "scala",
-			"*.md":         "markdown",
-			"*.json":       "json",
-			"*.yaml":       "yaml",
-			"*.yml":        "yaml",
-			"*.toml":       "toml",
-			"*.xml":        "xml",
-			"*.html":       "html",
-			"*.css":        "css",
-			"*.scss":       "scss",
-			"*.less":       "less",
-			"*.sql":        "sql",
-			"*.sh":         "shellscript",
-			"*.bash":       "shellscript",
-			"*.zsh":        "shellscript",
-			"Makefile":     "makefile",
-			"Dockerfile":   "dockerfile",
-			"*.dockerfile": "dockerfile",
-		},
+		FileAssociations:    map[string]string{},
 		EditorSettings: EditorSettings{
 			TabSize:                4,
 			InsertSpaces:           true,
@@ -295,7 +258,8 @@ func (c *Config) IsWatcherExcluded(path string) bool {
 		matchesAnyPattern(path, c.WatcherExcludePatterns)
 }
 
-// GetLanguageID returns the language ID for a file path based on file associations.
+// GetLanguageID returns the language ID for a file path based on file
+// associations, or "" if none matches.
 func (c *Config) GetLanguageID(path string) string {
 	name := filepath.Base(path)
 	ext := filepath.Ext(path)
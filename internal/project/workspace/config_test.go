@@ -22,8 +22,8 @@ func TestDefaultConfig(t *testing.T) {
 		t.Error("ExcludePatterns should not be empty")
 	}
 
-	if len(config.FileAssociations) == 0 {
-		t.Error("FileAssociations should not be empty")
+	if config.FileAssociations == nil {
+		t.Error("FileAssociations should not be nil")
 	}
 
 	if config.EditorSettings.TabSize != 4 {
@@ -187,6 +187,14 @@ func TestConfig_IsSearchExcluded(t *testing.T) {
 
 func TestConfig_GetLanguageID(t *testing.T) {
 	config := DefaultConfig()
+	config.FileAssociations = map[string]string{
+		"*.go":       "go",
+		"*.ts":       "typescript",
+		"*.tsx":      "typescriptreact",
+		"*.py":       "python",
+		"Makefile":   "makefile",
+		"Dockerfile": "dockerfile",
+	}
 
 	tests := []struct {
 		path string
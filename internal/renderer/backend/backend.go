@@ -209,6 +209,12 @@ type Backend interface {
 	// DisablePaste disables bracketed paste mode.
 	DisablePaste()
 
+	// EnableFocus enables focus change reporting.
+	EnableFocus()
+
+	// DisableFocus disables focus change reporting.
+	DisableFocus()
+
 	// Suspend suspends the terminal (for shell escape).
 	Suspend() error
 
@@ -324,6 +330,8 @@ func (b *NullBackend) EnableMouse()       {}
 func (b *NullBackend) DisableMouse()      {}
 func (b *NullBackend) EnablePaste()       {}
 func (b *NullBackend) DisablePaste()      {}
+func (b *NullBackend) EnableFocus()       {}
+func (b *NullBackend) DisableFocus()      {}
 func (b *NullBackend) Suspend() error     { return nil }
 func (b *NullBackend) Resume() error      { return nil }
 
@@ -3,6 +3,8 @@ package backend
 import (
 	"testing"
 
+	"github.com/gdamore/tcell/v2"
+
 	"github.com/dshills/keystorm/internal/renderer/core"
 )
 
@@ -203,3 +205,42 @@ func TestEventTypes(t *testing.T) {
 		t.Error("should be focus event")
 	}
 }
+
+func TestTerminalPasteDropsSpecialKeys(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	defer screen.Fini()
+	term := &Terminal{screen: screen}
+
+	events := []tcell.Event{
+		tcell.NewEventPaste(true),
+		tcell.NewEventKey(tcell.KeyRune, 'h', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'i', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyCtrlA, 0, tcell.ModCtrl),
+		tcell.NewEventResize(100, 40),
+		tcell.NewEventPaste(false),
+		tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone),
+	}
+	for _, ev := range events {
+		if err := screen.PostEvent(ev); err != nil {
+			t.Fatalf("PostEvent() error = %v", err)
+		}
+	}
+
+	ev := term.PollEvent()
+	// Special keys are neither pasted nor replayed after the paste
+	if want := "hi"; ev.Type != EventPaste || ev.PasteText != want {
+		t.Fatalf("first event = %+v, want paste of %q", ev, want)
+	}
+	if ev := term.PollEvent(); ev.Type != EventResize || ev.Width != 100 {
+		t.Errorf("second event = %+v, want the queued resize", ev)
+	}
+	if ev := term.PollEvent(); ev.Type != EventKey || ev.Rune != 'x' {
+		t.Errorf("third event = %+v, want the key after the paste", ev)
+	}
+}
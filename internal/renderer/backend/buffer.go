@@ -381,6 +381,14 @@ func (b *BufferedBackend) DisablePaste() {
 	b.backend.DisablePaste()
 }
 
+func (b *BufferedBackend) EnableFocus() {
+	b.backend.EnableFocus()
+}
+
+func (b *BufferedBackend) DisableFocus() {
+	b.backend.DisableFocus()
+}
+
 func (b *BufferedBackend) Suspend() error {
 	return b.backend.Suspend()
 }
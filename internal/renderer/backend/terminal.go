@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/gdamore/tcell/v2"
@@ -13,6 +14,10 @@ type Terminal struct {
 	screen        tcell.Screen
 	resizeHandler func(width, height int)
 	mu            sync.Mutex
+
+	// queued holds events that arrived during a bracketed paste and are
+	// delivered after the paste event.
+	queued []Event
 }
 
 // NewTerminal creates a new terminal backend.
@@ -146,10 +151,68 @@ func (t *Terminal) SetCursorStyle(style CursorStyle) {
 }
 
 func (t *Terminal) PollEvent() Event {
+	if ev, ok := t.dequeue(); ok {
+		return ev
+	}
 	ev := t.screen.PollEvent()
+	if pe, ok := ev.(*tcell.EventPaste); ok && pe.Start() {
+		return t.collectPaste()
+	}
 	return convertEvent(ev, t)
 }
 
+// dequeue returns the oldest event queued during a paste.
+func (t *Terminal) dequeue() (Event, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.queued) == 0 {
+		return Event{}, false
+	}
+	ev := t.queued[0]
+	t.queued = t.queued[1:]
+	return ev, true
+}
+
+// enqueue queues an event to be returned by a later PollEvent.
+func (t *Terminal) enqueue(ev Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queued = append(t.queued, ev)
+}
+
+// collectPaste gathers the key events of a bracketed paste into a single
+// paste event so that pasted text is never interpreted as key presses.
+// Special keys such as Escape, Backspace and Ctrl-keys are dropped, so no
+// pasted byte runs as a command.
+// Events that are not keys, such as resizes and mouse events, are queued
+// and delivered after the paste.
+func (t *Terminal) collectPaste() Event {
+	var sb strings.Builder
+	for {
+		ev := t.screen.PollEvent()
+		switch e := ev.(type) {
+		case nil:
+			return Event{Type: EventPaste, PasteText: sb.String()}
+		case *tcell.EventPaste:
+			if !e.Start() {
+				return Event{Type: EventPaste, PasteText: sb.String()}
+			}
+		case *tcell.EventKey:
+			switch e.Key() {
+			case tcell.KeyRune:
+				sb.WriteRune(e.Rune())
+			case tcell.KeyEnter, tcell.KeyLF:
+				sb.WriteByte('\n')
+			case tcell.KeyTab:
+				sb.WriteByte('\t')
+			}
+		default:
+			t.enqueue(convertEvent(e, t))
+		}
+	}
+}
+
 func (t *Terminal) PostEvent(event Event) {
 	// Convert our event to tcell event and post it
 	// For now, we only support posting key events
@@ -201,6 +264,20 @@ func (t *Terminal) DisablePaste() {
 	t.screen.DisablePaste()
 }
 
+func (t *Terminal) EnableFocus() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.screen.EnableFocus()
+}
+
+func (t *Terminal) DisableFocus() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.screen.DisableFocus()
+}
+
 func (t *Terminal) Suspend() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
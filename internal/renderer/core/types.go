@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/dshills/keystorm/internal/textwidth"
 )
 
 // Attribute represents text attributes (bold, italic, etc.).
@@ -397,51 +399,7 @@ func ContinuationCell() Cell {
 
 // RuneWidth returns the display width of a rune.
 func RuneWidth(r rune) int {
-	if r < 32 || r == 0x7F {
-		return 0
-	}
-	if isWideRune(r) {
-		return 2
-	}
-	return 1
-}
-
-// isWideRune checks if a rune is a wide (double-width) character.
-func isWideRune(r rune) bool {
-	if r >= 0x1100 && r <= 0x115F {
-		return true
-	}
-	if r >= 0x3130 && r <= 0x318F {
-		return true
-	}
-	if r >= 0x2E80 && r <= 0x9FFF {
-		return true
-	}
-	if r >= 0xAC00 && r <= 0xD7A3 {
-		return true
-	}
-	if r >= 0xF900 && r <= 0xFAFF {
-		return true
-	}
-	if r >= 0xFE10 && r <= 0xFE1F {
-		return true
-	}
-	if r >= 0xFE30 && r <= 0xFE6F {
-		return true
-	}
-	if r >= 0xFF00 && r <= 0xFF60 {
-		return true
-	}
-	if r >= 0xFFE0 && r <= 0xFFE6 {
-		return true
-	}
-	if r >= 0x20000 && r <= 0x2FFFF {
-		return true
-	}
-	if r >= 0x2F800 && r <= 0x2FA1F {
-		return true
-	}
-	return false
+	return textwidth.RuneWidth(r)
 }
 
 // CellsFromString creates cells from a string.
@@ -656,6 +614,12 @@ type StyleSpan struct {
 	StartCol uint32 // Starting column (0-indexed)
 	EndCol   uint32 // Ending column (exclusive)
 	Style    Style
+
+	// Scopes is the token's scope stack, outermost first, for spans from
+	// semantic tokens or a tokenizer (e.g., "entity.name.function.go").
+	// A renderer with a theme styles such spans by their scopes instead
+	// of Style.
+	Scopes []string
 }
 
 // Len returns the length of the span in columns.
@@ -355,31 +355,6 @@ func TestCellWithStyle(t *testing.T) {
 	}
 }
 
-func TestRuneWidth(t *testing.T) {
-	tests := []struct {
-		r     rune
-		width int
-	}{
-		{'A', 1},
-		{'a', 1},
-		{'0', 1},
-		{' ', 1},
-		{'中', 2},
-		{'日', 2},
-		{'あ', 2},
-		{'\t', 0}, // Tab is a control character, display width handled by layout
-		{'\n', 0},
-		{'\x00', 0},
-	}
-
-	for _, tt := range tests {
-		got := RuneWidth(tt.r)
-		if got != tt.width {
-			t.Errorf("RuneWidth(%q) = %d, want %d", tt.r, got, tt.width)
-		}
-	}
-}
-
 func TestCellsFromString(t *testing.T) {
 	style := DefaultStyle()
 	cells := CellsFromString("Hello", style)
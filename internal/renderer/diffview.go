@@ -0,0 +1,597 @@
+package renderer
+
+import (
+	"unicode"
+
+	"github.com/dshills/keystorm/internal/engine/rope"
+	"github.com/dshills/keystorm/internal/engine/tracking"
+)
+
+// DiffViewMode selects how a diff view lays out the two texts.
+type DiffViewMode uint8
+
+const (
+	// DiffSideBySide shows the texts in two aligned panes.
+	DiffSideBySide DiffViewMode = iota
+
+	// DiffInline shows removed lines above the added lines that replace
+	// them, in a single pane.
+	DiffInline
+)
+
+// DiffSide identifies one of the two texts of a diff view.
+type DiffSide uint8
+
+const (
+	// DiffLeft is the old text.
+	DiffLeft DiffSide = iota
+
+	// DiffRight is the new text.
+	DiffRight
+)
+
+// DiffRowKind describes how a diff row differs between the texts.
+type DiffRowKind uint8
+
+const (
+	// DiffRowEqual is a line present unchanged in both texts.
+	DiffRowEqual DiffRowKind = iota
+
+	// DiffRowAdded is a line only in the right text.
+	DiffRowAdded
+
+	// DiffRowRemoved is a line only in the left text.
+	DiffRowRemoved
+
+	// DiffRowChanged pairs a removed line with the added line replacing
+	// it. Only used side by side.
+	DiffRowChanged
+)
+
+// DiffSpan is a changed range of rune columns [Start, End) within a line.
+type DiffSpan struct {
+	Start int
+	End   int
+}
+
+// DiffRow is a single row of a diff view.
+type DiffRow struct {
+	// Kind describes the change shown on the row.
+	Kind DiffRowKind
+
+	// Left and Right are the lines shown from each text, or -1 where the
+	// row is a filler keeping the panes aligned.
+	Left  int
+	Right int
+
+	// LeftChanges and RightChanges are the words that changed within a
+	// line replaced by another.
+	LeftChanges  []DiffSpan
+	RightChanges []DiffSpan
+}
+
+// Line returns the line shown from side, or -1 for a filler.
+func (row DiffRow) Line(side DiffSide) int {
+	if side == DiffLeft {
+		return row.Left
+	}
+	return row.Right
+}
+
+// Diff view styles.
+var (
+	diffAddedStyle       = DefaultStyle().WithBackground(ColorFromRGB(0x1f, 0x3a, 0x24))
+	diffRemovedStyle     = DefaultStyle().WithBackground(ColorFromRGB(0x43, 0x1f, 0x22))
+	diffAddedWordStyle   = DefaultStyle().WithBackground(ColorFromRGB(0x2e, 0x6b, 0x3a)).Bold()
+	diffRemovedWordStyle = DefaultStyle().WithBackground(ColorFromRGB(0x82, 0x2e, 0x33)).Bold()
+	diffFillerStyle      = DefaultStyle().WithForeground(ColorGray).Dim()
+	diffSeparatorStyle   = DefaultStyle().WithForeground(ColorGray)
+)
+
+const (
+	// diffFillerRune fills rows without a line in one pane.
+	diffFillerRune = '╱'
+
+	// diffSeparatorRune separates the panes of a side-by-side view.
+	diffSeparatorRune = '│'
+)
+
+// diffView holds the state of an active diff view.
+type diffView struct {
+	mode  DiffViewMode
+	left  []string
+	right []string
+	rows  []DiffRow
+
+	// leftRows and rightRows map each line to its row
+	leftRows  []int
+	rightRows []int
+
+	// cursorSide is the text the cursor provider's position refers to
+	cursorSide DiffSide
+}
+
+// SetDiffView switches the renderer to showing the differences between
+// left (old) and right (new) instead of the buffer. Lines are aligned with
+// the tracking package's diff, and replaced lines are compared word by word.
+// Both panes of a side-by-side view scroll together. The cursor provider's
+// position refers to the right text; see SetDiffCursorSide.
+func (r *Renderer) SetDiffView(left, right rope.Rope, mode DiffViewMode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dv := newDiffView(ropeLines(left), ropeLines(right), mode)
+	dv.cursorSide = DiffRight
+	if r.diff != nil {
+		dv.cursorSide = r.diff.cursorSide
+	}
+	r.diff = dv
+
+	r.viewport.SetMaxLine(uint32(len(dv.rows)))
+	r.needsRedraw = true
+	r.fullRedraw = true
+}
+
+// ClearDiffView leaves the diff view and shows the buffer again.
+func (r *Renderer) ClearDiffView() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.diff == nil {
+		return
+	}
+	r.diff = nil
+	if r.bufReader != nil {
+		r.viewport.SetMaxLine(r.bufReader.LineCount())
+	}
+	r.needsRedraw = true
+	r.fullRedraw = true
+}
+
+// HasDiffView returns true if a diff view is shown.
+func (r *Renderer) HasDiffView() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.diff != nil
+}
+
+// DiffRows returns the rows of the diff view, or nil if none is shown.
+func (r *Renderer) DiffRows() []DiffRow {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.diff == nil {
+		return nil
+	}
+	rows := make([]DiffRow, len(r.diff.rows))
+	copy(rows, r.diff.rows)
+	return rows
+}
+
+// SetDiffCursorSide selects which text the cursor provider's position
+// refers to in the diff view.
+func (r *Renderer) SetDiffCursorSide(side DiffSide) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.diff != nil {
+		r.diff.cursorSide = side
+		r.needsRedraw = true
+	}
+}
+
+// ScrollDiffToLine scrolls the diff view so that line of side is at the
+// top. The other pane follows, staying aligned.
+func (r *Renderer) ScrollDiffToLine(side DiffSide, line uint32, smooth bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.diff == nil {
+		return
+	}
+	r.viewport.ScrollTo(uint32(r.diff.rowForLine(side, int(line))), smooth)
+	r.needsRedraw = true
+}
+
+// RevealDiffLine scrolls the diff view minimally to make line of side
+// visible.
+func (r *Renderer) RevealDiffLine(side DiffSide, line uint32, smooth bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.diff == nil {
+		return
+	}
+	r.viewport.EnsureLineVisible(uint32(r.diff.rowForLine(side, int(line))), smooth)
+	r.needsRedraw = true
+}
+
+// DiffLineAt returns the line of side shown at screenRow. It returns false
+// if no diff view is shown or the row is a filler or past the end.
+func (r *Renderer) DiffLineAt(side DiffSide, screenRow int) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.diff == nil || screenRow < 0 || screenRow >= r.effectiveHeight() {
+		return -1, false
+	}
+	row := int(r.viewport.TopLine()) + screenRow
+	if row >= len(r.diff.rows) {
+		return -1, false
+	}
+	line := r.diff.rows[row].Line(side)
+	return line, line >= 0
+}
+
+// DiffSideAt returns the pane containing screen column screenX. An inline
+// view has a single pane showing the right text.
+func (r *Renderer) DiffSideAt(screenX int) DiffSide {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.diff == nil || r.diff.mode == DiffInline {
+		return DiffRight
+	}
+	if screenX < r.diffPaneWidth() {
+		return DiffLeft
+	}
+	return DiffRight
+}
+
+// newDiffView aligns left and right into diff rows.
+func newDiffView(left, right []string, mode DiffViewMode) *diffView {
+	dv := &diffView{
+		mode:      mode,
+		left:      left,
+		right:     right,
+		leftRows:  make([]int, len(left)),
+		rightRows: make([]int, len(right)),
+	}
+
+	var removed, added []int
+	flush := func() {
+		dv.appendChanges(removed, added)
+		removed, added = removed[:0], added[:0]
+	}
+
+	for _, edit := range tracking.ComputeEdits(left, right, tracking.DefaultDiffOptions()) {
+		switch edit.Type {
+		case tracking.DiffDelete:
+			removed = append(removed, edit.OldIndex)
+		case tracking.DiffInsert:
+			added = append(added, edit.NewIndex)
+		default:
+			flush()
+			dv.appendRow(DiffRow{Kind: DiffRowEqual, Left: edit.OldIndex, Right: edit.NewIndex})
+		}
+	}
+	flush()
+
+	return dv
+}
+
+// appendChanges appends the rows for a run of removed lines replaced by
+// added lines. Removed and added lines are paired up in order and compared
+// word by word.
+func (dv *diffView) appendChanges(removed, added []int) {
+	pairs := min(len(removed), len(added))
+	leftChanges := make([][]DiffSpan, pairs)
+	rightChanges := make([][]DiffSpan, pairs)
+	for i := 0; i < pairs; i++ {
+		leftChanges[i], rightChanges[i] = wordDiff(dv.left[removed[i]], dv.right[added[i]])
+	}
+
+	if dv.mode == DiffInline {
+		for i, line := range removed {
+			row := DiffRow{Kind: DiffRowRemoved, Left: line, Right: -1}
+			if i < pairs {
+				row.LeftChanges = leftChanges[i]
+			}
+			dv.appendRow(row)
+		}
+		for i, line := range added {
+			row := DiffRow{Kind: DiffRowAdded, Left: -1, Right: line}
+			if i < pairs {
+				row.RightChanges = rightChanges[i]
+			}
+			dv.appendRow(row)
+		}
+		return
+	}
+
+	for i := 0; i < pairs; i++ {
+		dv.appendRow(DiffRow{
+			Kind:         DiffRowChanged,
+			Left:         removed[i],
+			Right:        added[i],
+			LeftChanges:  leftChanges[i],
+			RightChanges: rightChanges[i],
+		})
+	}
+	for _, line := range removed[pairs:] {
+		dv.appendRow(DiffRow{Kind: DiffRowRemoved, Left: line, Right: -1})
+	}
+	for _, line := range added[pairs:] {
+		dv.appendRow(DiffRow{Kind: DiffRowAdded, Left: -1, Right: line})
+	}
+}
+
+// appendRow appends row and records the row of its lines.
+func (dv *diffView) appendRow(row DiffRow) {
+	index := len(dv.rows)
+	if row.Left >= 0 {
+		dv.leftRows[row.Left] = index
+	}
+	if row.Right >= 0 {
+		dv.rightRows[row.Right] = index
+	}
+	dv.rows = append(dv.rows, row)
+}
+
+// rowForLine returns the row showing line of side, clamped to the text.
+func (dv *diffView) rowForLine(side DiffSide, line int) int {
+	rows := dv.rightRows
+	if side == DiffLeft {
+		rows = dv.leftRows
+	}
+	if len(rows) == 0 {
+		return 0
+	}
+	if line < 0 {
+		line = 0
+	}
+	if line >= len(rows) {
+		line = len(rows) - 1
+	}
+	return rows[line]
+}
+
+// text returns the lines of side.
+func (dv *diffView) text(side DiffSide) []string {
+	if side == DiffLeft {
+		return dv.left
+	}
+	return dv.right
+}
+
+// ropeLines splits r into lines.
+func ropeLines(r rope.Rope) []string {
+	if r.Len() == 0 {
+		return nil
+	}
+	var lines []string
+	iter := r.Lines()
+	for iter.Next() {
+		lines = append(lines, iter.Text())
+	}
+	return lines
+}
+
+// wordDiff compares two lines word by word and returns the changed rune
+// column ranges in each.
+func wordDiff(oldLine, newLine string) (oldSpans, newSpans []DiffSpan) {
+	oldWords, oldCols := splitWords(oldLine)
+	newWords, newCols := splitWords(newLine)
+
+	for _, edit := range tracking.ComputeEdits(oldWords, newWords, tracking.DefaultDiffOptions()) {
+		switch edit.Type {
+		case tracking.DiffDelete:
+			oldSpans = appendSpan(oldSpans, oldCols[edit.OldIndex], oldCols[edit.OldIndex+1])
+		case tracking.DiffInsert:
+			newSpans = appendSpan(newSpans, newCols[edit.NewIndex], newCols[edit.NewIndex+1])
+		}
+	}
+	return oldSpans, newSpans
+}
+
+// appendSpan appends [start, end) to spans, merging it with the last span
+// if they touch.
+func appendSpan(spans []DiffSpan, start, end int) []DiffSpan {
+	if n := len(spans); n > 0 && spans[n-1].End == start {
+		spans[n-1].End = end
+		return spans
+	}
+	return append(spans, DiffSpan{Start: start, End: end})
+}
+
+// splitWords splits line into words, runs of whitespace and single
+// punctuation characters. cols holds the rune column where each token
+// starts, plus the line's length in runes.
+func splitWords(line string) (words []string, cols []int) {
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		j := i + 1
+		switch {
+		case isWordRune(runes[i]):
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+		case unicode.IsSpace(runes[i]):
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+		}
+		words = append(words, string(runes[i:j]))
+		cols = append(cols, i)
+		i = j
+	}
+	cols = append(cols, len(runes))
+	return words, cols
+}
+
+// isWordRune returns true if r is part of a word.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// renderDiff renders the diff view in place of the buffer (must hold lock).
+func (r *Renderer) renderDiff() {
+	r.viewport.SetMaxLine(uint32(len(r.diff.rows)))
+	r.viewport.SetTextOffset(0)
+
+	if r.fullRedraw {
+		r.clearContentArea()
+	}
+
+	top := int(r.viewport.TopLine())
+	effHeight := r.effectiveHeight()
+	paneWidth := r.diffPaneWidth()
+
+	for screenRow := 0; screenRow < effHeight; screenRow++ {
+		index := top + screenRow
+		if index >= len(r.diff.rows) {
+			r.fillDiffRow(0, r.width, screenRow, EmptyCell())
+			continue
+		}
+		row := r.diff.rows[index]
+
+		if r.diff.mode == DiffInline {
+			side := DiffRight
+			if row.Kind == DiffRowRemoved {
+				side = DiffLeft
+			}
+			r.renderDiffPane(row, side, 0, r.width, screenRow)
+			continue
+		}
+
+		r.renderDiffPane(row, DiffLeft, 0, paneWidth, screenRow)
+		r.backend.SetCell(paneWidth, screenRow, NewStyledCell(diffSeparatorRune, diffSeparatorStyle))
+		r.renderDiffPane(row, DiffRight, paneWidth+1, r.width-paneWidth-1, screenRow)
+	}
+
+	r.renderDiffCursor()
+	r.backend.Show()
+}
+
+// renderDiffPane renders side's part of row in the width columns starting
+// at screen column x (must hold lock).
+func (r *Renderer) renderDiffPane(row DiffRow, side DiffSide, x, width, screenRow int) {
+	line := row.Line(side)
+	if line < 0 {
+		r.fillDiffRow(x, width, screenRow, NewStyledCell(diffFillerRune, diffFillerStyle))
+		return
+	}
+
+	lineStyle, wordStyle, changes := DefaultStyle(), DefaultStyle(), row.RightChanges
+	switch {
+	case row.Kind == DiffRowAdded || (row.Kind == DiffRowChanged && side == DiffRight):
+		lineStyle, wordStyle = diffAddedStyle, diffAddedWordStyle
+	case row.Kind == DiffRowRemoved || (row.Kind == DiffRowChanged && side == DiffLeft):
+		lineStyle, wordStyle = diffRemovedStyle, diffRemovedWordStyle
+	}
+	if side == DiffLeft {
+		changes = row.LeftChanges
+	}
+
+	// Gutter: line number and, inline, the change sign
+	gutterWidth := r.diffGutterWidth()
+	gutter := formatLineNumber(uint32(line+1), gutterWidth-1)
+	if r.diff.mode == DiffInline {
+		sign := " "
+		switch row.Kind {
+		case DiffRowAdded:
+			sign = "+"
+		case DiffRowRemoved:
+			sign = "-"
+		}
+		gutter = formatLineNumber(uint32(line+1), gutterWidth-2) + sign
+	}
+	col := 0
+	for _, ch := range gutter {
+		if col < width {
+			r.backend.SetCell(x+col, screenRow, NewStyledCell(ch, DefaultStyle().Dim()))
+		}
+		col++
+	}
+	for ; col < gutterWidth && col < width; col++ {
+		r.backend.SetCell(x+col, screenRow, EmptyCell())
+	}
+
+	lineLayout := r.layout.Layout(r.diff.text(side)[line], uint32(line))
+	leftCol := r.viewport.LeftColumn()
+	for ; col < width; col++ {
+		visCol := leftCol + col - gutterWidth
+		cell := EmptyCell()
+		if visCol >= 0 && visCol < len(lineLayout.Cells) {
+			cell = lineLayout.Cells[visCol]
+		}
+		cell.Style = lineStyle
+		if visCol >= 0 && visCol < len(lineLayout.VisualCols) && inDiffSpans(changes, int(lineLayout.VisualCols[visCol])) {
+			cell.Style = wordStyle
+		}
+		r.backend.SetCell(x+col, screenRow, cell)
+	}
+}
+
+// renderDiffCursor shows the cursor in the pane of the cursor side (must
+// hold lock).
+func (r *Renderer) renderDiffCursor() {
+	if r.cursorProv == nil {
+		r.backend.HideCursor()
+		return
+	}
+
+	line, col := r.cursorProv.PrimaryCursor()
+	side := r.diff.cursorSide
+	text := r.diff.text(side)
+	if int(line) >= len(text) {
+		r.backend.HideCursor()
+		return
+	}
+
+	screenRow := r.viewport.LineToScreenRow(uint32(r.diff.rowForLine(side, int(line))))
+	if screenRow < 0 || screenRow >= r.effectiveHeight() {
+		r.backend.HideCursor()
+		return
+	}
+
+	x, width := 0, r.width
+	if r.diff.mode == DiffSideBySide {
+		width = r.diffPaneWidth()
+		if side == DiffRight {
+			x = width + 1
+			width = r.width - x
+		}
+	}
+
+	lineLayout := r.layout.Layout(text[line], line)
+	paneCol := r.diffGutterWidth() + lineLayout.VisualColumn(col) - r.viewport.LeftColumn()
+	if paneCol < r.diffGutterWidth() || paneCol >= width {
+		r.backend.HideCursor()
+		return
+	}
+	r.backend.ShowCursor(x+paneCol, screenRow)
+}
+
+// fillDiffRow fills width columns of screenRow starting at x with cell.
+func (r *Renderer) fillDiffRow(x, width, screenRow int, cell Cell) {
+	for col := 0; col < width; col++ {
+		r.backend.SetCell(x+col, screenRow, cell)
+	}
+}
+
+// diffPaneWidth returns the width of the left pane of a side-by-side view
+// (must hold lock).
+func (r *Renderer) diffPaneWidth() int {
+	return (r.width - 1) / 2
+}
+
+// diffGutterWidth returns the width of a pane's gutter: the line number, a
+// space and, inline, the change sign (must hold lock).
+func (r *Renderer) diffGutterWidth() int {
+	digits := len(uintToString(uint32(max(len(r.diff.left), len(r.diff.right), 1))))
+	if r.diff.mode == DiffInline {
+		return digits + 2
+	}
+	return digits + 1
+}
+
+// inDiffSpans returns true if rune column col is within spans.
+func inDiffSpans(spans []DiffSpan, col int) bool {
+	for _, span := range spans {
+		if col >= span.Start && col < span.End {
+			return true
+		}
+	}
+	return false
+}
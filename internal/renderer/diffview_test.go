@@ -0,0 +1,196 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/engine/rope"
+)
+
+func TestDiffViewAddedRegionShowsFiller(t *testing.T) {
+	b := newTestBackend(41, 10)
+	r := New(b, DefaultOptions())
+
+	left := rope.FromString("one\ntwo\nthree")
+	right := rope.FromString("one\nnew a\nnew b\ntwo\nthree")
+	r.SetDiffView(left, right, DiffSideBySide)
+
+	rows := r.DiffRows()
+	want := []DiffRow{
+		{Kind: DiffRowEqual, Left: 0, Right: 0},
+		{Kind: DiffRowAdded, Left: -1, Right: 1},
+		{Kind: DiffRowAdded, Left: -1, Right: 2},
+		{Kind: DiffRowEqual, Left: 1, Right: 3},
+		{Kind: DiffRowEqual, Left: 2, Right: 4},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("len(DiffRows) = %d, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		if row.Kind != want[i].Kind || row.Left != want[i].Left || row.Right != want[i].Right {
+			t.Errorf("row %d = %+v, want %+v", i, row, want[i])
+		}
+	}
+
+	r.RenderNow()
+
+	// Left pane of the added rows is filler; right pane shows the lines
+	paneWidth := (41 - 1) / 2
+	for _, screenRow := range []int{1, 2} {
+		if cell := b.GetCell(0, screenRow); cell.Rune != diffFillerRune {
+			t.Errorf("left pane row %d = %q, want filler", screenRow, cell.Rune)
+		}
+		if cell := b.GetCell(paneWidth, screenRow); cell.Rune != diffSeparatorRune {
+			t.Errorf("separator row %d = %q, want %q", screenRow, cell.Rune, diffSeparatorRune)
+		}
+	}
+	if got := diffPaneText(b, paneWidth+1, 1, 6); got != "2 new " {
+		t.Errorf("right pane row 1 = %q, want %q", got, "2 new ")
+	}
+	if cell := b.GetCell(paneWidth+3, 1); !cell.Style.Equals(diffAddedStyle) {
+		t.Errorf("added line style = %+v, want added style", cell.Style)
+	}
+}
+
+func TestDiffViewScrollKeepsPanesAligned(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 0; i < 50; i++ {
+		line := fmt.Sprintf("line %d", i)
+		oldLines = append(oldLines, line)
+		newLines = append(newLines, line)
+		if i == 10 {
+			// Three lines only on the right shift it by three from here on
+			newLines = append(newLines, "added 1", "added 2", "added 3")
+		}
+	}
+
+	b := newTestBackend(60, 10)
+	opts := DefaultOptions()
+	opts.SmoothScroll = false
+	r := New(b, opts)
+	r.SetDiffView(rope.FromString(strings.Join(oldLines, "\n")), rope.FromString(strings.Join(newLines, "\n")), DiffSideBySide)
+
+	r.ScrollDiffToLine(DiffLeft, 20, false)
+
+	left, ok := r.DiffLineAt(DiffLeft, 0)
+	if !ok || left != 20 {
+		t.Fatalf("left pane top line = %d, %v, want 20", left, ok)
+	}
+	right, ok := r.DiffLineAt(DiffRight, 0)
+	if !ok || right != 23 {
+		t.Errorf("right pane top line = %d, %v, want 23", right, ok)
+	}
+
+	r.RenderNow()
+	paneWidth := (60 - 1) / 2
+	if got := diffPaneText(b, 0, 0, 10); got != "21 line 20" {
+		t.Errorf("left pane top row = %q, want %q", got, "21 line 20")
+	}
+	if got := diffPaneText(b, paneWidth+1, 0, 10); got != "24 line 20" {
+		t.Errorf("right pane top row = %q, want %q", got, "24 line 20")
+	}
+
+	// Scrolling to a right-only line puts filler at the top of the left pane
+	r.ScrollDiffToLine(DiffRight, 12, false)
+	if _, ok := r.DiffLineAt(DiffLeft, 0); ok {
+		t.Error("left pane top row should be filler")
+	}
+	if right, _ := r.DiffLineAt(DiffRight, 0); right != 12 {
+		t.Errorf("right pane top line = %d, want 12", right)
+	}
+}
+
+func TestDiffViewWordDiff(t *testing.T) {
+	b := newTestBackend(60, 5)
+	r := New(b, DefaultOptions())
+	r.SetDiffView(rope.FromString("x := compute(a, b)"), rope.FromString("x := compute(a, c)"), DiffSideBySide)
+
+	rows := r.DiffRows()
+	if len(rows) != 1 || rows[0].Kind != DiffRowChanged {
+		t.Fatalf("DiffRows = %+v, want one changed row", rows)
+	}
+	if got, want := rows[0].LeftChanges, []DiffSpan{{Start: 16, End: 17}}; !equalSpans(got, want) {
+		t.Errorf("LeftChanges = %v, want %v", got, want)
+	}
+	if got, want := rows[0].RightChanges, []DiffSpan{{Start: 16, End: 17}}; !equalSpans(got, want) {
+		t.Errorf("RightChanges = %v, want %v", got, want)
+	}
+
+	r.RenderNow()
+	gutter := 2
+	if cell := b.GetCell(gutter+16, 0); !cell.Style.Equals(diffRemovedWordStyle) {
+		t.Errorf("changed word style = %+v, want removed word style", cell.Style)
+	}
+	if cell := b.GetCell(gutter, 0); !cell.Style.Equals(diffRemovedStyle) {
+		t.Errorf("unchanged word style = %+v, want removed line style", cell.Style)
+	}
+}
+
+func TestDiffViewInline(t *testing.T) {
+	b := newTestBackend(40, 6)
+	r := New(b, DefaultOptions())
+	r.SetDiffView(rope.FromString("a\nold\nz"), rope.FromString("a\nnew\nz"), DiffInline)
+
+	rows := r.DiffRows()
+	kinds := []DiffRowKind{DiffRowEqual, DiffRowRemoved, DiffRowAdded, DiffRowEqual}
+	if len(rows) != len(kinds) {
+		t.Fatalf("len(DiffRows) = %d, want %d", len(rows), len(kinds))
+	}
+	for i, kind := range kinds {
+		if rows[i].Kind != kind {
+			t.Errorf("row %d kind = %d, want %d", i, rows[i].Kind, kind)
+		}
+	}
+
+	r.RenderNow()
+	if got := diffPaneText(b, 0, 1, 6); got != "2- old" {
+		t.Errorf("removed row = %q, want %q", got, "2- old")
+	}
+	if got := diffPaneText(b, 0, 2, 6); got != "2+ new" {
+		t.Errorf("added row = %q, want %q", got, "2+ new")
+	}
+	if side := r.DiffSideAt(30); side != DiffRight {
+		t.Errorf("DiffSideAt = %d, want DiffRight", side)
+	}
+}
+
+func TestDiffViewCursorInRightPane(t *testing.T) {
+	b := newTestBackend(41, 5)
+	r := New(b, DefaultOptions())
+	r.SetCursorProvider(&mockCursorProvider{line: 1, col: 2})
+	r.SetDiffView(rope.FromString("a\nb"), rope.FromString("new\na\nbcd"), DiffSideBySide)
+
+	r.RenderNow()
+	x, y, visible := b.CursorPosition()
+	// Right line 1 ("a") is on row 1; pane starts at 21, gutter is 2 wide
+	if !visible || x != 21+2+2 || y != 1 {
+		t.Errorf("cursor = (%d, %d, %v), want (25, 1, true)", x, y, visible)
+	}
+
+	r.ClearDiffView()
+	if r.HasDiffView() {
+		t.Error("HasDiffView after ClearDiffView = true")
+	}
+}
+
+// diffPaneText returns the text of width cells starting at (x, y).
+func diffPaneText(b interface{ GetCell(x, y int) Cell }, x, y, width int) string {
+	var sb strings.Builder
+	for i := 0; i < width; i++ {
+		sb.WriteRune(b.GetCell(x+i, y).Rune)
+	}
+	return sb.String()
+}
+
+func equalSpans(a, b []DiffSpan) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -7,6 +7,12 @@
 //   - Cursor and selection rendering
 //   - AI overlay rendering (ghost text, diff previews)
 //   - Efficient dirty region tracking for incremental updates
+//   - Minimap overview of the buffer
+//   - Side-by-side and inline diff views
+//   - Pluggable gutter columns (line numbers, diagnostics, git, folds)
+//   - Whitespace, tab and invisible character markers, and trailing
+//     whitespace highlighting
+//   - Inlay hints drawn inline as virtual text
 //   - Backend abstraction for terminal/GUI output
 //
 // Architecture:
@@ -30,4 +36,23 @@
 //	r := renderer.New(backend, renderer.DefaultOptions())
 //	r.SetBuffer(engine)
 //	r.Render()
+//
+// Gutters:
+//
+// By default the gutter shows absolute line numbers. Registering
+// GutterProviders replaces it with the providers' columns, composited left
+// to right, and moves the viewport's text area to start after them:
+//
+//	r.AddGutter(renderer.NewDiagnosticGutter(signs))
+//	r.AddGutter(renderer.NewLineNumberGutter(gutter.LineNumberRelative))
+//
+// Diff views:
+//
+// SetDiffView shows the differences between two texts instead of the
+// buffer, until ClearDiffView. Side by side, filler rows keep the panes
+// aligned so that they scroll together; ScrollDiffToLine and DiffLineAt
+// translate between each pane's lines and the shared rows:
+//
+//	r.SetDiffView(oldRope, newRope, renderer.DiffSideBySide)
+//	r.ScrollDiffToLine(renderer.DiffLeft, 120, true)
 package renderer
@@ -291,8 +291,8 @@ func (g *Gutter) renderSigns(line uint32) []Cell {
 	}
 
 	// Display highest priority sign
-	sign := highestPriority(signs)
-	r, style := signGlyph(sign.Type)
+	sign := HighestPriority(signs)
+	r, style := SignGlyph(sign.Type)
 	if g.config.SignColumnWidth > 0 {
 		cells[0] = Cell{Rune: r, Style: style}
 	}
@@ -377,8 +377,9 @@ func FormatNumber(n uint32) string {
 	return string(buf[i:])
 }
 
-// highestPriority returns the sign with highest priority.
-func highestPriority(signs []Sign) Sign {
+// HighestPriority returns the sign with highest priority.
+// Returns a SignNone sign if signs is empty.
+func HighestPriority(signs []Sign) Sign {
 	if len(signs) == 0 {
 		return Sign{Type: SignNone}
 	}
@@ -418,8 +419,8 @@ func signPriority(st SignType) int {
 	}
 }
 
-// signGlyph returns the glyph and style for a sign type.
-func signGlyph(st SignType) (rune, CellStyle) {
+// SignGlyph returns the glyph and style for a sign type.
+func SignGlyph(st SignType) (rune, CellStyle) {
 	switch st {
 	case SignError:
 		return 'E', StyleError
@@ -0,0 +1,341 @@
+package renderer
+
+import (
+	"sync"
+
+	"github.com/dshills/keystorm/internal/renderer/gutter"
+)
+
+// GutterLine describes the screen row a gutter provider renders.
+type GutterLine struct {
+	// Line is the buffer line (0-indexed).
+	Line uint32
+
+	// Exists is false for rows below the end of the buffer.
+	Exists bool
+
+	// CursorLine is the line of the primary cursor.
+	CursorLine uint32
+
+	// LineCount is the total number of lines in the buffer.
+	LineCount uint32
+}
+
+// GutterProvider renders one column of the gutter, such as line numbers or
+// diagnostic signs. Providers registered with Renderer.AddGutter are
+// composited left to right in registration order, followed by a single
+// separator column, to the left of the text.
+type GutterProvider interface {
+	// Width returns the number of screen cells the provider occupies for a
+	// buffer with lineCount lines.
+	Width(lineCount uint32) int
+
+	// Render returns the cells for a row. Missing cells are drawn blank and
+	// cells beyond Width are dropped.
+	Render(line GutterLine) []Cell
+}
+
+// LineNumberGutter displays absolute, relative or hybrid line numbers.
+type LineNumberGutter struct {
+	mu       sync.RWMutex
+	mode     gutter.LineNumberMode
+	minWidth int
+}
+
+// NewLineNumberGutter creates a line number gutter with the given mode.
+func NewLineNumberGutter(mode gutter.LineNumberMode) *LineNumberGutter {
+	return &LineNumberGutter{
+		mode:     mode,
+		minWidth: 3,
+	}
+}
+
+// Mode returns the line number mode.
+func (g *LineNumberGutter) Mode() gutter.LineNumberMode {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.mode
+}
+
+// SetMode changes the line number mode.
+func (g *LineNumberGutter) SetMode(mode gutter.LineNumberMode) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.mode = mode
+}
+
+// Width implements GutterProvider.
+func (g *LineNumberGutter) Width(lineCount uint32) int {
+	return gutter.CalculateWidth(lineCount, g.minWidth)
+}
+
+// Render implements GutterProvider. The cursor line is highlighted; other
+// lines are dim. Rows past the end of the buffer show "~".
+func (g *LineNumberGutter) Render(line GutterLine) []Cell {
+	width := g.Width(line.LineCount)
+	if !line.Exists {
+		return styledCells(gutter.PadLeft("~", width), DefaultStyle().Dim())
+	}
+
+	f := gutter.NewLineNumberFormatter(g.Mode(), width)
+	f.SetCurrentLine(line.CursorLine)
+	text, current := f.FormatWithHighlight(line.Line)
+
+	style := DefaultStyle().Dim()
+	if current {
+		style = DefaultStyle().Bold()
+	}
+	return styledCells(text, style)
+}
+
+// SignGutter displays the highest priority sign of a line in a one cell
+// column. Only sign types accepted by the gutter are shown, so diagnostic
+// and git signs from the same provider can be split into separate columns.
+type SignGutter struct {
+	provider gutter.SignProvider
+	accept   func(gutter.SignType) bool
+}
+
+// NewSignGutter creates a gutter showing every sign from provider.
+func NewSignGutter(provider gutter.SignProvider) *SignGutter {
+	return &SignGutter{provider: provider}
+}
+
+// NewDiagnosticGutter creates a gutter showing the diagnostic severity
+// (error, warning, info) of each line.
+func NewDiagnosticGutter(provider gutter.SignProvider) *SignGutter {
+	return &SignGutter{
+		provider: provider,
+		accept: func(st gutter.SignType) bool {
+			return st == gutter.SignError || st == gutter.SignWarning || st == gutter.SignInfo
+		},
+	}
+}
+
+// NewGitGutter creates a gutter showing added, modified and deleted
+// markers.
+func NewGitGutter(provider gutter.SignProvider) *SignGutter {
+	return &SignGutter{
+		provider: provider,
+		accept: func(st gutter.SignType) bool {
+			return st == gutter.SignGitAdded || st == gutter.SignGitModified || st == gutter.SignGitDeleted
+		},
+	}
+}
+
+// Width implements GutterProvider.
+func (g *SignGutter) Width(lineCount uint32) int {
+	return 1
+}
+
+// Render implements GutterProvider.
+func (g *SignGutter) Render(line GutterLine) []Cell {
+	if !line.Exists || g.provider == nil {
+		return nil
+	}
+
+	signs := g.provider.SignsForLine(line.Line)
+	if g.accept != nil {
+		filtered := signs[:0:0]
+		for _, s := range signs {
+			if g.accept(s.Type) {
+				filtered = append(filtered, s)
+			}
+		}
+		signs = filtered
+	}
+	if len(signs) == 0 {
+		return nil
+	}
+
+	r, style := gutter.SignGlyph(gutter.HighestPriority(signs).Type)
+	return []Cell{NewStyledCell(r, gutterCellStyle(style))}
+}
+
+// FoldMarker describes the fold state of a line.
+type FoldMarker uint8
+
+const (
+	// FoldNone means no fold starts at the line.
+	FoldNone FoldMarker = iota
+
+	// FoldOpen means an expanded fold starts at the line.
+	FoldOpen
+
+	// FoldClosed means a collapsed fold starts at the line.
+	FoldClosed
+)
+
+// FoldProvider reports where folds start.
+type FoldProvider interface {
+	// FoldMarker returns the fold state of a line.
+	FoldMarker(line uint32) FoldMarker
+}
+
+// FoldGutter displays fold indicators.
+type FoldGutter struct {
+	provider FoldProvider
+}
+
+// NewFoldGutter creates a fold indicator gutter.
+func NewFoldGutter(provider FoldProvider) *FoldGutter {
+	return &FoldGutter{provider: provider}
+}
+
+// Width implements GutterProvider.
+func (g *FoldGutter) Width(lineCount uint32) int {
+	return 1
+}
+
+// Render implements GutterProvider.
+func (g *FoldGutter) Render(line GutterLine) []Cell {
+	if !line.Exists || g.provider == nil {
+		return nil
+	}
+
+	switch g.provider.FoldMarker(line.Line) {
+	case FoldOpen:
+		return []Cell{NewStyledCell('▾', DefaultStyle().Dim())}
+	case FoldClosed:
+		return []Cell{NewStyledCell('▸', DefaultStyle())}
+	default:
+		return nil
+	}
+}
+
+// gutterCellStyle maps a gutter cell style to a renderer style.
+func gutterCellStyle(cs gutter.CellStyle) Style {
+	switch cs {
+	case gutter.StyleCurrentLine:
+		return DefaultStyle().Bold()
+	case gutter.StyleDim:
+		return DefaultStyle().Dim()
+	case gutter.StyleError, gutter.StyleGitDelete:
+		return NewStyle(ColorRed)
+	case gutter.StyleWarning:
+		return NewStyle(ColorYellow)
+	case gutter.StyleInfo:
+		return NewStyle(ColorCyan)
+	case gutter.StyleGitAdd:
+		return NewStyle(ColorGreen)
+	case gutter.StyleGitModify:
+		return NewStyle(ColorBlue)
+	default:
+		return DefaultStyle()
+	}
+}
+
+// styledCells converts an ASCII string to cells with one style.
+func styledCells(s string, style Style) []Cell {
+	cells := make([]Cell, 0, len(s))
+	for _, r := range s {
+		cells = append(cells, NewStyledCell(r, style))
+	}
+	return cells
+}
+
+// AddGutter registers a gutter provider. Providers are drawn left to right
+// in the order they were added. While any provider is registered it
+// replaces the built-in line number gutter.
+func (r *Renderer) AddGutter(provider GutterProvider) {
+	if provider == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gutters = append(r.gutters, provider)
+	r.updateGutterWidth()
+	r.needsRedraw = true
+	r.fullRedraw = true
+}
+
+// RemoveGutter unregisters a gutter provider.
+func (r *Renderer) RemoveGutter(provider GutterProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filtered := r.gutters[:0]
+	for _, g := range r.gutters {
+		if g != provider {
+			filtered = append(filtered, g)
+		}
+	}
+	for i := len(filtered); i < len(r.gutters); i++ {
+		r.gutters[i] = nil
+	}
+	r.gutters = filtered
+	r.updateGutterWidth()
+	r.needsRedraw = true
+	r.fullRedraw = true
+}
+
+// Gutters returns the registered gutter providers.
+func (r *Renderer) Gutters() []GutterProvider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]GutterProvider(nil), r.gutters...)
+}
+
+// updateGutterWidth recalculates the gutter width and moves the viewport's
+// text area to start after it (must hold lock).
+func (r *Renderer) updateGutterWidth() {
+	r.gutterWidth = r.calculateGutterWidth()
+	r.viewport.SetTextOffset(r.gutterWidth)
+}
+
+// providerGutterWidth returns the width of the registered providers plus
+// the separator, or 0 if they are all empty (must hold lock).
+func (r *Renderer) providerGutterWidth() int {
+	var lineCount uint32
+	if r.bufReader != nil {
+		lineCount = r.bufReader.LineCount()
+	}
+
+	width := 0
+	for _, g := range r.gutters {
+		if w := g.Width(lineCount); w > 0 {
+			width += w
+		}
+	}
+	if width > 0 {
+		width++ // separator
+	}
+	return width
+}
+
+// renderProviderGutters composites the registered gutter providers for a
+// row (must hold lock).
+func (r *Renderer) renderProviderGutters(line uint32, screenRow int) {
+	lineCount := r.bufReader.LineCount()
+	info := GutterLine{
+		Line:      line,
+		Exists:    line < lineCount,
+		LineCount: lineCount,
+	}
+	if r.cursorProv != nil {
+		info.CursorLine, _ = r.cursorProv.PrimaryCursor()
+	}
+
+	empty := EmptyCell()
+	x := 0
+	for _, g := range r.gutters {
+		width := g.Width(lineCount)
+		if width <= 0 {
+			continue
+		}
+		cells := g.Render(info)
+		for i := 0; i < width && x < r.gutterWidth-1; i++ {
+			cell := empty
+			if i < len(cells) {
+				cell = cells[i]
+				cell.Width = 1
+			}
+			r.backend.SetCell(x, screenRow, cell)
+			x++
+		}
+	}
+
+	// Separator
+	r.backend.SetCell(r.gutterWidth-1, screenRow, empty)
+}
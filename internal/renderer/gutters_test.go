@@ -0,0 +1,156 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/dshills/keystorm/internal/renderer/gutter"
+)
+
+// mockSignProvider implements gutter.SignProvider for testing.
+type mockSignProvider struct {
+	signs []gutter.Sign
+}
+
+func (m *mockSignProvider) SignsForLine(line uint32) []gutter.Sign {
+	var result []gutter.Sign
+	for _, s := range m.signs {
+		if s.Line == line {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func (m *mockSignProvider) AllSigns() []gutter.Sign {
+	return m.signs
+}
+
+// gutterText returns the runes drawn in the first n columns of a row.
+func gutterText(b interface{ GetCell(x, y int) Cell }, row, n int) string {
+	runes := make([]rune, n)
+	for x := 0; x < n; x++ {
+		runes[x] = b.GetCell(x, row).Rune
+	}
+	return string(runes)
+}
+
+func TestRendererGutterProviders(t *testing.T) {
+	nullBackend := newTestBackend(40, 10)
+	r := New(nullBackend, DefaultOptions())
+
+	r.SetBuffer(newMockBuffer("alpha", "beta", "gamma", "delta"))
+	r.SetCursorProvider(&mockCursorProvider{line: 2, col: 1})
+
+	signs := &mockSignProvider{signs: []gutter.Sign{
+		{Line: 0, Type: gutter.SignWarning},
+		{Line: 3, Type: gutter.SignWarning},
+		{Line: 3, Type: gutter.SignError},
+		{Line: 1, Type: gutter.SignGitAdded}, // not a diagnostic
+	}}
+	diag := NewDiagnosticGutter(signs)
+	numbers := NewLineNumberGutter(gutter.LineNumberRelative)
+	r.AddGutter(diag)
+	r.AddGutter(numbers)
+
+	// 1 (diagnostics) + 3 (numbers) + 1 (separator)
+	if got := r.GutterWidth(); got != 5 {
+		t.Fatalf("GutterWidth() = %d, want 5", got)
+	}
+
+	r.RenderNow()
+
+	want := []string{
+		"W  2 a",
+		"   1 b",
+		"   0 g",
+		"E  1 d",
+	}
+	for row, w := range want {
+		if got := gutterText(nullBackend, row, len(w)); got != w {
+			t.Errorf("row %d = %q, want %q", row, got, w)
+		}
+	}
+
+	if got := nullBackend.GetCell(0, 3).Style.Foreground; got != ColorRed {
+		t.Errorf("error sign foreground = %v, want %v", got, ColorRed)
+	}
+	if got := nullBackend.GetCell(3, 2).Style.Attributes; got&AttrBold == 0 {
+		t.Errorf("cursor line number attributes = %v, want bold", got)
+	}
+
+	// Cursor and click mapping account for the gutter
+	cx, cy, visible := nullBackend.CursorPosition()
+	if !visible || cx != 6 || cy != 2 {
+		t.Errorf("cursor = (%d, %d, %v), want (6, 2, true)", cx, cy, visible)
+	}
+	line, col := r.Viewport().ScreenToBuffer(3, 7)
+	if line != 3 || col != 2 {
+		t.Errorf("ScreenToBuffer(3, 7) = (%d, %d), want (3, 2)", line, col)
+	}
+
+	r.RemoveGutter(diag)
+	if got := r.GutterWidth(); got != 4 {
+		t.Errorf("GutterWidth() after RemoveGutter = %d, want 4", got)
+	}
+	if got := len(r.Gutters()); got != 1 {
+		t.Errorf("len(Gutters()) = %d, want 1", got)
+	}
+}
+
+func TestLineNumberGutterModes(t *testing.T) {
+	line := GutterLine{Line: 4, Exists: true, CursorLine: 2, LineCount: 10}
+
+	tests := []struct {
+		mode gutter.LineNumberMode
+		want string
+	}{
+		{gutter.LineNumberAbsolute, "  5"},
+		{gutter.LineNumberRelative, "  2"},
+		{gutter.LineNumberHybrid, "  2"},
+	}
+
+	for _, tt := range tests {
+		g := NewLineNumberGutter(tt.mode)
+		cells := g.Render(line)
+		got := make([]rune, len(cells))
+		for i, c := range cells {
+			got[i] = c.Rune
+		}
+		if string(got) != tt.want {
+			t.Errorf("mode %d: Render() = %q, want %q", tt.mode, string(got), tt.want)
+		}
+	}
+}
+
+func TestGitAndFoldGutters(t *testing.T) {
+	signs := &mockSignProvider{signs: []gutter.Sign{
+		{Line: 0, Type: gutter.SignGitModified},
+		{Line: 0, Type: gutter.SignError},
+	}}
+	git := NewGitGutter(signs)
+
+	cells := git.Render(GutterLine{Line: 0, Exists: true})
+	if len(cells) != 1 || cells[0].Rune != '~' {
+		t.Errorf("git gutter = %v, want ~", cells)
+	}
+	if cells := git.Render(GutterLine{Line: 1, Exists: true}); len(cells) != 0 {
+		t.Errorf("git gutter on unchanged line = %v, want none", cells)
+	}
+
+	folds := NewFoldGutter(foldFunc(func(line uint32) FoldMarker {
+		if line == 1 {
+			return FoldClosed
+		}
+		return FoldNone
+	}))
+	if cells := folds.Render(GutterLine{Line: 1, Exists: true}); len(cells) != 1 || cells[0].Rune != '▸' {
+		t.Errorf("fold gutter = %v, want ▸", cells)
+	}
+}
+
+// foldFunc adapts a function to FoldProvider.
+type foldFunc func(line uint32) FoldMarker
+
+func (f foldFunc) FoldMarker(line uint32) FoldMarker {
+	return f(line)
+}
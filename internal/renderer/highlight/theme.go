@@ -1,14 +1,60 @@
 package highlight
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/dshills/keystorm/internal/renderer/core"
 )
 
+// ErrInvalidTheme is returned when a theme definition cannot be parsed.
+var ErrInvalidTheme = errors.New("invalid theme")
+
+// Variant tells light themes from dark ones.
+type Variant uint8
+
+const (
+	// VariantDark is a theme for dark backgrounds.
+	VariantDark Variant = iota
+
+	// VariantLight is a theme for light backgrounds.
+	VariantLight
+)
+
+// String returns the string representation of the variant.
+func (v Variant) String() string {
+	switch v {
+	case VariantDark:
+		return "dark"
+	case VariantLight:
+		return "light"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseVariant returns the variant named s ("dark" or "light").
+func ParseVariant(s string) (Variant, bool) {
+	switch strings.ToLower(s) {
+	case "dark":
+		return VariantDark, true
+	case "light":
+		return VariantLight, true
+	default:
+		return VariantDark, false
+	}
+}
+
 // Theme defines colors and styles for syntax highlighting.
 type Theme struct {
 	// Name is the display name of the theme.
 	Name string
 
+	// Variant tells whether the theme is for a light or dark background.
+	Variant Variant
+
 	// Background is the editor background color.
 	Background core.Color
 
@@ -31,54 +77,209 @@ type Theme struct {
 	ScopeStyles map[string]core.Style
 }
 
+// defaultStyle returns the style of text without a token or scope style.
+func (t *Theme) defaultStyle() core.Style {
+	return core.Style{
+		Foreground: t.Foreground,
+		Background: core.ColorDefault,
+	}
+}
+
 // StyleForToken returns the style for a given token type.
 func (t *Theme) StyleForToken(tokenType TokenType) core.Style {
 	if style, ok := t.TokenStyles[tokenType]; ok {
 		return style
 	}
-	// Fall back to default style
-	return core.Style{
-		Foreground: t.Foreground,
-		Background: core.ColorDefault,
-	}
+	return t.defaultStyle()
 }
 
-// StyleForScope returns the style for a given scope string.
+// StyleForScope returns the style for a given scope string. A scope without
+// a style of its own uses the style of its longest dot-separated prefix:
+// "entity.name.function.go" falls back to "entity.name.function", then
+// "entity.name", then "entity", and finally the default style. At each
+// prefix a custom scope style is preferred over a token type's style.
 func (t *Theme) StyleForScope(scope string) core.Style {
-	// Check exact match first
-	if style, ok := t.ScopeStyles[scope]; ok {
+	if style, ok := t.lookupScope(scope); ok {
 		return style
 	}
+	return t.defaultStyle()
+}
 
-	// Try token type mapping
-	if tokenType := TokenTypeFromString(scope); tokenType != TokenNone {
-		if style, ok := t.TokenStyles[tokenType]; ok {
+// StyleForScopes returns the style of a scope stack, such as the scopes of
+// a semantic token or a tokenizer match. Scopes are ordered from outermost
+// to innermost, as TextMate grammars report them, so they are tried from
+// last to first; the default style is used if none of them has a style.
+func (t *Theme) StyleForScopes(scopes []string) core.Style {
+	for i := len(scopes) - 1; i >= 0; i-- {
+		if style, ok := t.lookupScope(scopes[i]); ok {
 			return style
 		}
 	}
+	return t.defaultStyle()
+}
 
-	// Check parent scopes
-	for len(scope) > 0 {
+// lookupScope returns the style of scope's longest prefix with a scope or
+// token style. Returns false if no prefix has one.
+func (t *Theme) lookupScope(scope string) (core.Style, bool) {
+	for scope != "" {
 		if style, ok := t.ScopeStyles[scope]; ok {
-			return style
+			return style, true
 		}
-		// Remove last segment
-		for i := len(scope) - 1; i >= 0; i-- {
-			if scope[i] == '.' {
-				scope = scope[:i]
-				break
-			}
-			if i == 0 {
-				scope = ""
+		if tokenType, ok := scopeToToken[scope]; ok {
+			if style, ok := t.TokenStyles[tokenType]; ok {
+				return style, true
 			}
 		}
+		i := strings.LastIndexByte(scope, '.')
+		if i < 0 {
+			break
+		}
+		scope = scope[:i]
 	}
+	return core.Style{}, false
+}
 
-	// Fall back to default
-	return core.Style{
-		Foreground: t.Foreground,
-		Background: core.ColorDefault,
+// themeFile is the JSON form of a theme.
+type themeFile struct {
+	Name          string               `json:"name"`
+	Variant       string               `json:"variant"`
+	Background    string               `json:"background"`
+	Foreground    string               `json:"foreground"`
+	Selection     string               `json:"selection"`
+	Cursor        string               `json:"cursor"`
+	LineHighlight string               `json:"lineHighlight"`
+	Scopes        map[string]styleFile `json:"scopes"`
+}
+
+// styleFile is the JSON form of a style. Colors are "#rrggbb" or "default".
+type styleFile struct {
+	Foreground    string `json:"foreground"`
+	Background    string `json:"background"`
+	Bold          bool   `json:"bold"`
+	Dim           bool   `json:"dim"`
+	Italic        bool   `json:"italic"`
+	Underline     bool   `json:"underline"`
+	Strikethrough bool   `json:"strikethrough"`
+	Reverse       bool   `json:"reverse"`
+}
+
+// ParseTheme parses a theme from JSON:
+//
+//	{
+//	  "name": "Keystorm",
+//	  "variant": "dark",
+//	  "foreground": "#d4d4d4",
+//	  "background": "#1e1e1e",
+//	  "scopes": {
+//	    "keyword": {"foreground": "#569cd6", "bold": true},
+//	    "entity.name.function": {"foreground": "#dcdcaa"}
+//	  }
+//	}
+//
+// Scopes naming a token type, such as "keyword" or "comment.line", also
+// style tokens of that type. The variant defaults to dark. Returns an error
+// wrapping ErrInvalidTheme if the JSON, the variant or a color is malformed,
+// or if the theme has no name.
+func ParseTheme(data []byte) (*Theme, error) {
+	var file themeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTheme, err)
 	}
+	if file.Name == "" {
+		return nil, fmt.Errorf("%w: missing name", ErrInvalidTheme)
+	}
+
+	theme := &Theme{
+		Name:        file.Name,
+		TokenStyles: make(map[TokenType]core.Style),
+		ScopeStyles: make(map[string]core.Style),
+	}
+	if file.Variant != "" {
+		v, ok := ParseVariant(file.Variant)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown variant %q", ErrInvalidTheme, file.Variant)
+		}
+		theme.Variant = v
+	}
+
+	for _, c := range []struct {
+		name  string
+		value string
+		color *core.Color
+	}{
+		{"background", file.Background, &theme.Background},
+		{"foreground", file.Foreground, &theme.Foreground},
+		{"selection", file.Selection, &theme.Selection},
+		{"cursor", file.Cursor, &theme.Cursor},
+		{"lineHighlight", file.LineHighlight, &theme.LineHighlight},
+	} {
+		color, err := parseThemeColor(c.value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidTheme, c.name, err)
+		}
+		*c.color = color
+	}
+
+	for scope, sf := range file.Scopes {
+		style, err := sf.style()
+		if err != nil {
+			return nil, fmt.Errorf("%w: scope %q: %v", ErrInvalidTheme, scope, err)
+		}
+		theme.ScopeStyles[scope] = style
+		if tokenType, ok := scopeToToken[scope]; ok {
+			theme.TokenStyles[tokenType] = style
+		}
+	}
+	return theme, nil
+}
+
+// ThemeFromConfig builds a theme from a config section with the same
+// structure as the JSON accepted by ParseTheme.
+func ThemeFromConfig(section map[string]any) (*Theme, error) {
+	data, err := json.Marshal(section)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+	}
+	return ParseTheme(data)
+}
+
+// style converts the JSON form of a style.
+func (f styleFile) style() (core.Style, error) {
+	style := core.DefaultStyle()
+
+	var err error
+	if style.Foreground, err = parseThemeColor(f.Foreground); err != nil {
+		return style, err
+	}
+	if style.Background, err = parseThemeColor(f.Background); err != nil {
+		return style, err
+	}
+
+	for _, attr := range []struct {
+		set  bool
+		attr core.Attribute
+	}{
+		{f.Bold, core.AttrBold},
+		{f.Dim, core.AttrDim},
+		{f.Italic, core.AttrItalic},
+		{f.Underline, core.AttrUnderline},
+		{f.Strikethrough, core.AttrStrikethrough},
+		{f.Reverse, core.AttrReverse},
+	} {
+		if attr.set {
+			style.Attributes = style.Attributes.With(attr.attr)
+		}
+	}
+	return style, nil
+}
+
+// parseThemeColor parses a theme color. An empty color or "default" is the
+// terminal's default color.
+func parseThemeColor(s string) (core.Color, error) {
+	if s == "" || s == "default" {
+		return core.ColorDefault, nil
+	}
+	return core.ColorFromHex(s)
 }
 
 // DefaultTheme returns a sensible default dark theme.
@@ -141,6 +342,7 @@ func SolarizedDarkTheme() *Theme {
 func LightTheme() *Theme {
 	return &Theme{
 		Name:          "Light",
+		Variant:       VariantLight,
 		Background:    core.ColorFromRGB(255, 255, 255),
 		Foreground:    core.ColorFromRGB(0, 0, 0),
 		Selection:     core.ColorFromRGB(173, 214, 255),
@@ -496,16 +698,20 @@ func lightTokenStyles() map[TokenType]core.Style {
 	}
 }
 
-// ThemeRegistry holds available themes.
+// ThemeRegistry holds available themes and the theme used for each
+// variant.
 type ThemeRegistry struct {
-	themes  map[string]*Theme
-	current *Theme
+	themes   map[string]*Theme
+	current  *Theme
+	variants [2]string
 }
 
 // NewThemeRegistry creates a new theme registry with built-in themes.
+// "Default Dark" is used for the dark variant and "Light" for the light one.
 func NewThemeRegistry() *ThemeRegistry {
 	r := &ThemeRegistry{
-		themes: make(map[string]*Theme),
+		themes:   make(map[string]*Theme),
+		variants: [2]string{VariantDark: "Default Dark", VariantLight: "Light"},
 	}
 
 	// Register built-in themes
@@ -546,6 +752,36 @@ func (r *ThemeRegistry) SetCurrent(name string) bool {
 	return false
 }
 
+// SetVariantTheme sets the theme used for variant v by name. Returns false
+// if no such theme is registered or it is for the other variant.
+func (r *ThemeRegistry) SetVariantTheme(v Variant, name string) bool {
+	t, ok := r.themes[name]
+	if !ok || v > VariantLight || t.Variant != v {
+		return false
+	}
+	r.variants[v] = name
+	return true
+}
+
+// VariantTheme returns the theme used for variant v.
+func (r *ThemeRegistry) VariantTheme(v Variant) (*Theme, bool) {
+	if v > VariantLight {
+		return nil, false
+	}
+	return r.Get(r.variants[v])
+}
+
+// SetVariant makes the theme used for variant v current, switching between
+// light and dark. Returns false if v has no registered theme.
+func (r *ThemeRegistry) SetVariant(v Variant) bool {
+	t, ok := r.VariantTheme(v)
+	if !ok {
+		return false
+	}
+	r.current = t
+	return true
+}
+
 // Names returns all registered theme names.
 func (r *ThemeRegistry) Names() []string {
 	names := make([]string, 0, len(r.themes))
@@ -1,6 +1,7 @@
 package highlight
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/dshills/keystorm/internal/renderer/core"
@@ -181,6 +182,121 @@ func TestThemeRegistry(t *testing.T) {
 	})
 }
 
+const testThemeJSON = `{
+	"name": "Test",
+	"variant": "dark",
+	"foreground": "#d4d4d4",
+	"scopes": {
+		"entity": {"foreground": "#ff0000"},
+		"entity.name.function": {"foreground": "#dcdcaa", "bold": true},
+		"keyword": {"foreground": "#569cd6"}
+	}
+}`
+
+func TestThemeScopeFallback(t *testing.T) {
+	theme, err := ParseTheme([]byte(testThemeJSON))
+	if err != nil {
+		t.Fatalf("ParseTheme() error = %v", err)
+	}
+	function := core.NewStyle(core.ColorFromRGB(0xdc, 0xdc, 0xaa)).Bold()
+
+	tests := []struct {
+		name   string
+		scopes []string
+		want   core.Style
+	}{
+		{"exact", []string{"entity.name.function"}, function},
+		{"unknown specific scope", []string{"entity.name.function.go"}, function},
+		{"shorter prefix", []string{"entity.other.attribute"}, core.NewStyle(core.ColorFromRGB(0xff, 0, 0))},
+		{"innermost scope wins", []string{"keyword.control", "entity.name.function.go"}, function},
+		{"outer scope used", []string{"keyword.control.go", "meta.block.go"}, core.NewStyle(core.ColorFromRGB(0x56, 0x9c, 0xd6))},
+		{"default", []string{"comment.line"}, core.Style{Foreground: core.ColorFromRGB(0xd4, 0xd4, 0xd4), Background: core.ColorDefault}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := theme.StyleForScopes(tt.scopes); !got.Equals(tt.want) {
+				t.Errorf("StyleForScopes(%v) = %+v, want %+v", tt.scopes, got, tt.want)
+			}
+		})
+	}
+
+	// Scopes naming a token type also style those tokens
+	if got := theme.StyleForToken(TokenKeyword); !got.Equals(core.NewStyle(core.ColorFromRGB(0x56, 0x9c, 0xd6))) {
+		t.Errorf("StyleForToken(TokenKeyword) = %+v, want the keyword scope style", got)
+	}
+}
+
+func TestThemeStyleForScopeLongestPrefix(t *testing.T) {
+	theme := DefaultTheme()
+	theme.ScopeStyles["function"] = core.NewStyle(core.ColorFromRGB(1, 2, 3))
+
+	// The method token style is a longer prefix than the custom scope
+	want := theme.StyleForToken(TokenFunctionMethod)
+	if got := theme.StyleForScope("function.method.go"); !got.Equals(want) {
+		t.Errorf("StyleForScope() = %+v, want the method token style %+v", got, want)
+	}
+	// At the same prefix the custom scope style wins
+	if got := theme.StyleForScope("function.other"); !got.Equals(core.NewStyle(core.ColorFromRGB(1, 2, 3))) {
+		t.Errorf("StyleForScope() = %+v, want the function scope style", got)
+	}
+}
+
+func TestParseThemeErrors(t *testing.T) {
+	for _, data := range []string{
+		`{`,
+		`{"variant": "dark"}`,
+		`{"name": "x", "variant": "dim"}`,
+		`{"name": "x", "background": "black"}`,
+		`{"name": "x", "scopes": {"keyword": {"foreground": "blue"}}}`,
+	} {
+		if _, err := ParseTheme([]byte(data)); !errors.Is(err, ErrInvalidTheme) {
+			t.Errorf("ParseTheme(%s) error = %v, want ErrInvalidTheme", data, err)
+		}
+	}
+}
+
+func TestThemeFromConfig(t *testing.T) {
+	theme, err := ThemeFromConfig(map[string]any{
+		"name":    "Config",
+		"variant": "light",
+		"scopes": map[string]any{
+			"comment": map[string]any{"foreground": "#808080", "italic": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ThemeFromConfig() error = %v", err)
+	}
+	if theme.Variant != VariantLight {
+		t.Errorf("Variant = %v, want light", theme.Variant)
+	}
+	want := core.NewStyle(core.ColorFromRGB(0x80, 0x80, 0x80)).Italic()
+	if got := theme.StyleForScope("comment.block.go"); !got.Equals(want) {
+		t.Errorf("StyleForScope() = %+v, want %+v", got, want)
+	}
+}
+
+func TestThemeRegistryVariants(t *testing.T) {
+	registry := NewThemeRegistry()
+
+	if !registry.SetVariant(VariantLight) || registry.Current().Name != "Light" {
+		t.Errorf("Current() after SetVariant(light) = %q, want Light", registry.Current().Name)
+	}
+	if !registry.SetVariant(VariantDark) || registry.Current().Name != "Default Dark" {
+		t.Errorf("Current() after SetVariant(dark) = %q, want Default Dark", registry.Current().Name)
+	}
+
+	if registry.SetVariantTheme(VariantLight, "Monokai") {
+		t.Error("SetVariantTheme(light, Monokai) succeeded for a dark theme")
+	}
+	if !registry.SetVariantTheme(VariantDark, "Dracula") {
+		t.Fatal("SetVariantTheme(dark, Dracula) failed")
+	}
+	registry.SetVariant(VariantDark)
+	if registry.Current().Name != "Dracula" {
+		t.Errorf("Current() = %q, want Dracula", registry.Current().Name)
+	}
+}
+
 func TestThemeColors(t *testing.T) {
 	// Verify that theme colors are distinguishable
 	theme := MonokaiTheme()
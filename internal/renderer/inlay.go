@@ -0,0 +1,143 @@
+package renderer
+
+import (
+	"sort"
+
+	"github.com/dshills/keystorm/internal/renderer/core"
+	"github.com/dshills/keystorm/internal/renderer/layout"
+)
+
+// InlayHint is virtual text drawn before a character of a line, such as a
+// parameter name or an inferred type. Hints take up screen columns but are
+// not part of the buffer, so the cursor, selections and mouse positions
+// skip over them.
+type InlayHint struct {
+	// Column is the character index the hint is drawn before. Columns at
+	// or past the end of the line draw the hint after the line's content.
+	Column uint32
+
+	// Text is the text to draw.
+	Text string
+}
+
+// inlayHintStyle is the style of inlay hint text.
+var inlayHintStyle = DefaultStyle().WithForeground(ColorGray).WithAttributes(AttrItalic)
+
+// SetInlayHints replaces the inlay hints drawn on each line, keyed by line.
+// Hints at the same column are drawn in order. A nil map clears all hints.
+func (r *Renderer) SetInlayHints(hints map[uint32][]InlayHint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inlayHints = hints
+	r.needsRedraw = true
+	r.fullRedraw = true
+}
+
+// InlayHints returns the inlay hints drawn on line.
+func (r *Renderer) InlayHints(line uint32) []InlayHint {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.inlayHints[line]
+}
+
+// inlayRow is a line's layout with its inlay hint cells inserted.
+type inlayRow struct {
+	cells []Cell
+
+	// cols is the layout column of each cell. Hint cells hold the column
+	// of the character the hint is drawn before.
+	cols []int
+
+	// hint reports whether each cell belongs to a hint.
+	hint []bool
+
+	// starts and widths are the layout column and width of each hint.
+	starts []int
+	widths []int
+}
+
+// inlayRowFor returns the cells of line with its inlay hints, or nil if the
+// line has none (must hold lock).
+func (r *Renderer) inlayRowFor(line uint32, lineLayout *layout.LineLayout) *inlayRow {
+	hints := r.inlayHints[line]
+	if len(hints) == 0 {
+		return nil
+	}
+
+	sorted := make([]InlayHint, len(hints))
+	copy(sorted, hints)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Column < sorted[j].Column
+	})
+
+	row := &inlayRow{}
+	next := 0
+	for _, h := range sorted {
+		at := lineLayout.Width
+		if int(h.Column) < len(lineLayout.BufferCols) {
+			at = min(int(lineLayout.BufferCols[h.Column]), lineLayout.Width)
+		}
+		for ; next < at; next++ {
+			row.add(lineLayout.Cells[next], next, false)
+		}
+
+		cells := core.CellsFromString(h.Text, inlayHintStyle)
+		for _, cell := range cells {
+			row.add(cell, at, true)
+		}
+		row.starts = append(row.starts, at)
+		row.widths = append(row.widths, len(cells))
+	}
+	for ; next < len(lineLayout.Cells); next++ {
+		row.add(lineLayout.Cells[next], next, false)
+	}
+	return row
+}
+
+// add appends a cell to the row.
+func (row *inlayRow) add(cell Cell, col int, hint bool) {
+	row.cells = append(row.cells, cell)
+	row.cols = append(row.cols, col)
+	row.hint = append(row.hint, hint)
+}
+
+// hintCell returns the hint cell drawn at display column col, if any.
+func (row *inlayRow) hintCell(col int) (Cell, bool) {
+	if col < 0 || col >= len(row.cells) || !row.hint[col] {
+		return Cell{}, false
+	}
+	return row.cells[col], true
+}
+
+// layoutColumn converts a display column to the layout column drawn there.
+func (row *inlayRow) layoutColumn(col int) int {
+	if col < 0 {
+		return col
+	}
+	if col < len(row.cols) {
+		return row.cols[col]
+	}
+	return col - row.hintWidth()
+}
+
+// displayColumn converts a layout column to the display column it is drawn
+// at, after any hints before it.
+func (row *inlayRow) displayColumn(col int) int {
+	display := col
+	for i, start := range row.starts {
+		if start <= col {
+			display += row.widths[i]
+		}
+	}
+	return display
+}
+
+// hintWidth returns the total width of the row's hints.
+func (row *inlayRow) hintWidth() int {
+	width := 0
+	for _, w := range row.widths {
+		width += w
+	}
+	return width
+}
@@ -0,0 +1,53 @@
+package renderer
+
+import "testing"
+
+func TestRendererInlayHints(t *testing.T) {
+	b := newTestBackend(40, 10)
+	r := New(b, DefaultOptions())
+	r.SetBuffer(newMockBuffer("f(a, b)"))
+	r.SetCursorProvider(&mockCursorProvider{line: 0, col: 5})
+	r.SetInlayHints(map[uint32][]InlayHint{
+		0: {{Column: 5, Text: "y: "}, {Column: 2, Text: "x: "}, {Column: 99, Text: " int"}},
+	})
+	gutter := r.GutterWidth()
+
+	r.RenderNow()
+
+	if got := diffPaneText(b, gutter, 0, 17); got != "f(x: a, y: b) int" {
+		t.Errorf("line = %q, want %q", got, "f(x: a, y: b) int")
+	}
+	if cell := b.GetCell(gutter+2, 0); !cell.Style.Equals(inlayHintStyle) {
+		t.Errorf("hint style = %+v, want inlay hint style", cell.Style)
+	}
+	if cell := b.GetCell(gutter+5, 0); cell.Style.Equals(inlayHintStyle) {
+		t.Error("buffer text drawn with inlay hint style")
+	}
+
+	// The cursor on "b" is drawn after the hint before it
+	if x, _, _ := b.CursorPosition(); x != gutter+11 {
+		t.Errorf("cursor x = %d, want %d", x, gutter+11)
+	}
+
+	tests := []struct {
+		x    int
+		want uint32
+	}{
+		{gutter + 1, 1},
+		{gutter + 3, 2},
+		{gutter + 5, 2},
+		{gutter + 11, 5},
+		{gutter + 17, 7},
+	}
+	for _, tt := range tests {
+		if _, col, ok := r.ScreenToBuffer(tt.x, 0); !ok || col != tt.want {
+			t.Errorf("ScreenToBuffer(%d, 0) col = %d, %v, want %d", tt.x, col, ok, tt.want)
+		}
+	}
+
+	r.SetInlayHints(nil)
+	r.RenderNow()
+	if got := diffPaneText(b, gutter, 0, 7); got != "f(a, b)" {
+		t.Errorf("line after clearing = %q, want %q", got, "f(a, b)")
+	}
+}
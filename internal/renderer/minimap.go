@@ -0,0 +1,297 @@
+package renderer
+
+import (
+	"hash/fnv"
+
+	"github.com/dshills/keystorm/internal/engine/buffer"
+	"github.com/dshills/keystorm/internal/renderer/layout"
+	"github.com/dshills/keystorm/internal/renderer/style"
+)
+
+// minimapGlyphs are the cell glyphs used for increasing character density.
+var minimapGlyphs = []rune{' ', '░', '▒', '▓', '█'}
+
+// Minimap is a condensed overview of the buffer. Each row summarizes a
+// group of consecutive lines and each cell a group of columns within them.
+type Minimap struct {
+	// Width and Height are the minimap dimensions in cells.
+	Width  int
+	Height int
+
+	// Rows holds Height rows of Width cells each. A cell's glyph reflects
+	// the density of non-blank characters it covers and its foreground the
+	// most common highlight color.
+	Rows [][]Cell
+
+	// LinesPerRow is the number of buffer lines summarized by each row.
+	LinesPerRow int
+
+	// ColumnsPerCell is the number of visual columns summarized by each cell.
+	ColumnsPerCell int
+
+	// ViewportStart and ViewportEnd are the first and last rows (inclusive)
+	// covering the lines visible in the viewport, for drawing a scrollbar
+	// thumb.
+	ViewportStart int
+	ViewportEnd   int
+}
+
+// RowForLine returns the minimap row summarizing a buffer line, clamped to
+// the last row.
+func (m Minimap) RowForLine(line uint32) int {
+	if m.Height <= 0 || m.LinesPerRow <= 0 {
+		return 0
+	}
+	row := int(line) / m.LinesPerRow
+	if row >= m.Height {
+		row = m.Height - 1
+	}
+	return row
+}
+
+// LineForRow returns the first buffer line summarized by a minimap row.
+func (m Minimap) LineForRow(row int) uint32 {
+	if row < 0 {
+		row = 0
+	}
+	return uint32(row * m.LinesPerRow)
+}
+
+// minimapCache keeps computed minimap rows between calls so that only rows
+// whose lines changed or were invalidated are recomputed. Like the line
+// layout cache, rows are validated against a hash of their lines' text, so
+// edits are picked up without explicit invalidation. When the buffer is a
+// RevisionReader, the hashes are only checked after the revision changes.
+type minimapCache struct {
+	width          int
+	height         int
+	linesPerRow    int
+	columnsPerCell int
+	lineCount      uint32
+
+	// revision is the buffer revision the hashes were checked at, valid
+	// only if hasRevision is set.
+	revision    buffer.RevisionID
+	hasRevision bool
+
+	rows   [][]Cell
+	hashes []uint64
+	dirty  []bool
+}
+
+// newMinimapCache creates an empty minimap cache.
+func newMinimapCache() *minimapCache {
+	return &minimapCache{}
+}
+
+// invalidateAll marks every row for recomputation.
+func (c *minimapCache) invalidateAll() {
+	c.rows = nil
+	c.dirty = nil
+}
+
+// invalidateLines marks the rows covering lines startLine to endLine
+// (inclusive) for recomputation.
+func (c *minimapCache) invalidateLines(startLine, endLine uint32) {
+	if c.linesPerRow == 0 || startLine > endLine {
+		return
+	}
+	first := int(startLine) / c.linesPerRow
+	last := int(endLine) / c.linesPerRow
+	for row := first; row <= last && row < len(c.dirty); row++ {
+		c.dirty[row] = true
+	}
+}
+
+// RenderMinimap returns a width x height minimap of the buffer. Lines are
+// grouped evenly into rows and each cell covers enough columns for the
+// minimap to span the editor's text area. Rows are cached and recomputed
+// only when the text of their lines changes, when their lines are
+// invalidated (see InvalidateLine and InvalidateLines, needed for
+// highlight-only changes) or when the minimap geometry or line count
+// changes. If the buffer is a RevisionReader, line text is only compared
+// after its revision changes.
+func (r *Renderer) RenderMinimap(width, height int) Minimap {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if width <= 0 || height <= 0 {
+		return Minimap{}
+	}
+
+	var lineCount uint32
+	if r.bufReader != nil {
+		lineCount = r.bufReader.LineCount()
+	}
+
+	linesPerRow := 1
+	if lineCount > uint32(height) {
+		linesPerRow = int((lineCount + uint32(height) - 1) / uint32(height))
+	}
+
+	textWidth := r.width - r.calculateGutterWidth()
+	columnsPerCell := 1
+	if textWidth > width {
+		columnsPerCell = (textWidth + width - 1) / width
+	}
+
+	c := r.minimap
+	if c.width != width || c.height != height || c.linesPerRow != linesPerRow ||
+		c.columnsPerCell != columnsPerCell || c.lineCount != lineCount || c.rows == nil {
+		c.width = width
+		c.height = height
+		c.linesPerRow = linesPerRow
+		c.columnsPerCell = columnsPerCell
+		c.lineCount = lineCount
+		c.rows = make([][]Cell, height)
+		c.hashes = make([]uint64, height)
+		c.dirty = make([]bool, height)
+		for i := range c.dirty {
+			c.dirty[i] = true
+		}
+		c.hasRevision = false
+	}
+
+	// Rows are only rehashed if the buffer may have changed since the
+	// last call; otherwise only invalidated rows are recomputed.
+	checkHashes := true
+	if rr, ok := r.bufReader.(RevisionReader); ok {
+		rev := rr.RevisionID()
+		checkHashes = !c.hasRevision || c.revision != rev
+		c.revision = rev
+		c.hasRevision = true
+	}
+
+	for row := 0; row < height; row++ {
+		if !checkHashes && !c.dirty[row] {
+			continue
+		}
+		hash := r.minimapRowHash(row)
+		if c.dirty[row] || c.hashes[row] != hash {
+			c.rows[row] = r.minimapRow(row)
+			c.hashes[row] = hash
+			c.dirty[row] = false
+		}
+	}
+
+	m := Minimap{
+		Width:          width,
+		Height:         height,
+		Rows:           make([][]Cell, height),
+		LinesPerRow:    linesPerRow,
+		ColumnsPerCell: columnsPerCell,
+	}
+	for row := range c.rows {
+		m.Rows[row] = append([]Cell(nil), c.rows[row]...)
+	}
+	if lineCount > 0 {
+		m.ViewportStart = m.RowForLine(r.viewport.TopLine())
+		m.ViewportEnd = m.RowForLine(r.viewport.BottomLine())
+	}
+	return m
+}
+
+// minimapRowHash hashes the text of the lines summarized by one minimap
+// row (must hold lock).
+func (r *Renderer) minimapRowHash(row int) uint64 {
+	c := r.minimap
+	h := fnv.New64a()
+	firstLine := uint32(row * c.linesPerRow)
+	if r.bufReader == nil {
+		return h.Sum64()
+	}
+	for line := firstLine; line < firstLine+uint32(c.linesPerRow) && line < c.lineCount; line++ {
+		h.Write([]byte(r.bufReader.LineText(line)))
+		h.Write([]byte{'\n'})
+	}
+	return h.Sum64()
+}
+
+// minimapRow computes the cells of one minimap row (must hold lock).
+func (r *Renderer) minimapRow(row int) []Cell {
+	c := r.minimap
+	cells := make([]Cell, c.width)
+	for i := range cells {
+		cells[i] = EmptyCell()
+	}
+
+	firstLine := uint32(row * c.linesPerRow)
+	if r.bufReader == nil || firstLine >= c.lineCount {
+		return cells
+	}
+
+	filled := make([]int, c.width)
+	colors := make([]map[Color]int, c.width)
+	for line := firstLine; line < firstLine+uint32(c.linesPerRow) && line < c.lineCount; line++ {
+		lineLayout := r.layout.Layout(r.bufReader.LineText(line), line)
+		spans := r.minimapSpans(line, lineLayout)
+
+		for visCol, cell := range lineLayout.Cells {
+			x := visCol / c.columnsPerCell
+			if x >= c.width {
+				break
+			}
+			if cell.Rune == 0 || cell.Rune == ' ' || cell.Rune == '\t' {
+				continue
+			}
+			filled[x]++
+			fg := r.resolver.ResolveAt(uint32(visCol), spans).Foreground
+			if colors[x] == nil {
+				colors[x] = make(map[Color]int)
+			}
+			colors[x][fg]++
+		}
+	}
+
+	capacity := c.linesPerRow * c.columnsPerCell
+	for x := range cells {
+		if filled[x] == 0 {
+			continue
+		}
+		level := (filled[x]*(len(minimapGlyphs)-1) + capacity - 1) / capacity
+		cells[x] = NewStyledCell(minimapGlyphs[level], NewStyle(dominantColor(colors[x])))
+	}
+	return cells
+}
+
+// minimapSpans converts a line's highlight spans to visual-column resolver
+// spans (must hold lock).
+func (r *Renderer) minimapSpans(line uint32, lineLayout *layout.LineLayout) []style.Span {
+	if r.hlProvider == nil {
+		return nil
+	}
+	highlights := r.hlProvider.HighlightsForLine(line)
+	if len(highlights) == 0 {
+		return nil
+	}
+	spans := make([]style.Span, 0, len(highlights))
+	for _, hl := range highlights {
+		startCol := uint32(lineLayout.VisualColumn(hl.StartCol))
+		endCol := uint32(lineLayout.VisualColumn(hl.EndCol))
+		if len(hl.Scopes) > 0 && r.resolver.Theme() != nil {
+			spans = append(spans, r.resolver.ScopeSpan(startCol, endCol, hl.Scopes))
+			continue
+		}
+		spans = append(spans, style.Span{
+			StartCol: startCol,
+			EndCol:   endCol,
+			Style:    hl.Style,
+			Layer:    style.LayerSyntax,
+			Merge:    style.MergeOverlay,
+		})
+	}
+	return spans
+}
+
+// dominantColor returns the most frequent color. Ties are broken by the
+// color's string form so the result does not depend on map order.
+func dominantColor(counts map[Color]int) Color {
+	best := ColorDefault
+	bestCount := 0
+	for color, n := range counts {
+		if n > bestCount || (n == bestCount && color.String() < best.String()) {
+			best, bestCount = color, n
+		}
+	}
+	return best
+}
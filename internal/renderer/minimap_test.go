@@ -0,0 +1,189 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dshills/keystorm/internal/engine/buffer"
+)
+
+func TestRenderMinimapViewport(t *testing.T) {
+	r := New(newTestBackend(80, 24), DefaultOptions())
+
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = "Line content"
+	}
+	r.SetBuffer(newMockBuffer(lines...))
+
+	tests := []struct {
+		top       uint32
+		wantStart int
+		wantEnd   int
+	}{
+		{0, 0, 4},    // lines 0-23
+		{50, 10, 14}, // lines 50-73
+		{90, 18, 19}, // lines 90-99
+	}
+
+	for _, tt := range tests {
+		r.Viewport().ScrollTo(tt.top, false)
+		m := r.RenderMinimap(10, 20)
+
+		if m.LinesPerRow != 5 {
+			t.Fatalf("LinesPerRow = %d, want 5", m.LinesPerRow)
+		}
+		if m.ViewportStart != tt.wantStart || m.ViewportEnd != tt.wantEnd {
+			t.Errorf("top %d: viewport rows = %d-%d, want %d-%d",
+				tt.top, m.ViewportStart, m.ViewportEnd, tt.wantStart, tt.wantEnd)
+		}
+	}
+}
+
+func TestRenderMinimapCells(t *testing.T) {
+	opts := DefaultOptions()
+	opts.ShowGutter = false
+	r := New(newTestBackend(20, 24), opts)
+
+	r.SetBuffer(newMockBuffer(
+		"abcdabcd",
+		"ab  ab",
+		"",
+	))
+	r.SetHighlightProvider(&mockHighlightProvider{
+		highlights: map[uint32][]StyleSpan{
+			0: {{StartCol: 0, EndCol: 2, Style: NewStyle(ColorRed)}},
+		},
+	})
+
+	m := r.RenderMinimap(5, 2)
+	if m.LinesPerRow != 2 || m.ColumnsPerCell != 4 {
+		t.Fatalf("LinesPerRow, ColumnsPerCell = %d, %d, want 2, 4", m.LinesPerRow, m.ColumnsPerCell)
+	}
+	if len(m.Rows) != 2 || len(m.Rows[0]) != 5 {
+		t.Fatalf("minimap is %dx%d, want 5x2", len(m.Rows[0]), len(m.Rows))
+	}
+
+	// Row 0 covers lines 0-1: 6 of 8 cells are filled in each of the first
+	// two column groups
+	if got := StringFromCells(m.Rows[0]); got != "▓▓   " {
+		t.Errorf("row 0 = %q, want %q", got, "▓▓   ")
+	}
+	if fg := m.Rows[0][1].Style.Foreground; !fg.IsDefault() {
+		t.Errorf("row 0 cell 1 foreground = %v, want default", fg)
+	}
+	if got := strings.TrimSpace(StringFromCells(m.Rows[1])); got != "" {
+		t.Errorf("row 1 = %q, want blank", got)
+	}
+}
+
+func TestRenderMinimapColor(t *testing.T) {
+	opts := DefaultOptions()
+	opts.ShowGutter = false
+	r := New(newTestBackend(20, 24), opts)
+
+	r.SetBuffer(newMockBuffer("abcdefgh"))
+	r.SetHighlightProvider(&mockHighlightProvider{
+		highlights: map[uint32][]StyleSpan{
+			0: {{StartCol: 0, EndCol: 3, Style: NewStyle(ColorRed)}},
+		},
+	})
+
+	m := r.RenderMinimap(5, 1)
+	if fg := m.Rows[0][0].Style.Foreground; fg != ColorRed {
+		t.Errorf("cell 0 foreground = %v, want red", fg)
+	}
+	if fg := m.Rows[0][1].Style.Foreground; !fg.IsDefault() {
+		t.Errorf("cell 1 foreground = %v, want default", fg)
+	}
+}
+
+func TestRenderMinimapIncremental(t *testing.T) {
+	opts := DefaultOptions()
+	opts.ShowGutter = false
+	r := New(newTestBackend(10, 24), opts)
+
+	buf := newMockBuffer("aaaaaaaaaa", "bbbbbbbbbb", "cccccccccc", "dddddddddd")
+	r.SetBuffer(buf)
+
+	hl := &mockHighlightProvider{highlights: map[uint32][]StyleSpan{}}
+	r.SetHighlightProvider(hl)
+
+	before := r.RenderMinimap(10, 4)
+
+	// Edited lines are picked up without invalidation, like the main view
+	buf.lines[1] = ""
+	m := r.RenderMinimap(10, 4)
+	if got := strings.TrimSpace(StringFromCells(m.Rows[1])); got != "" {
+		t.Errorf("row 1 after edit = %q, want blank", got)
+	}
+
+	// Rows whose text is unchanged are reused: a highlight-only change
+	// shows only after the line is invalidated
+	hl.highlights[3] = []StyleSpan{{StartCol: 0, EndCol: 10, Style: NewStyle(ColorRed)}}
+	m = r.RenderMinimap(10, 4)
+	if m.Rows[3][0].Style.Foreground != before.Rows[3][0].Style.Foreground {
+		t.Error("row 3 recomputed without a change")
+	}
+	r.InvalidateLine(3)
+	m = r.RenderMinimap(10, 4)
+	if m.Rows[3][0].Style.Foreground != ColorRed {
+		t.Errorf("row 3 after invalidation = %v, want red", m.Rows[3][0].Style.Foreground)
+	}
+
+	// Changing the line count recomputes everything
+	buf.lines = buf.lines[:2]
+	m = r.RenderMinimap(10, 4)
+	if got := strings.TrimSpace(StringFromCells(m.Rows[3])); got != "" {
+		t.Errorf("row 3 after line count change = %q, want blank", got)
+	}
+}
+
+// revisionBuffer is a mock buffer that tracks revisions and counts line
+// reads.
+type revisionBuffer struct {
+	*mockBufferReader
+	revision buffer.RevisionID
+	reads    int
+}
+
+func (b *revisionBuffer) LineText(line uint32) string {
+	b.reads++
+	return b.mockBufferReader.LineText(line)
+}
+
+func (b *revisionBuffer) RevisionID() buffer.RevisionID {
+	return b.revision
+}
+
+func TestRenderMinimapRevision(t *testing.T) {
+	opts := DefaultOptions()
+	opts.ShowGutter = false
+	r := New(newTestBackend(10, 24), opts)
+
+	buf := &revisionBuffer{mockBufferReader: newMockBuffer("aaaaaaaaaa", "bbbbbbbbbb", "cccccccccc", "dddddddddd")}
+	r.SetBuffer(buf)
+	r.RenderMinimap(10, 4)
+
+	// An unchanged revision reads no lines
+	buf.reads = 0
+	r.RenderMinimap(10, 4)
+	if buf.reads != 0 {
+		t.Errorf("reads at unchanged revision = %d, want 0", buf.reads)
+	}
+
+	// Only an invalidated row is rehashed and recomputed
+	r.InvalidateLine(2)
+	r.RenderMinimap(10, 4)
+	if buf.reads != 2 {
+		t.Errorf("reads after invalidating one line = %d, want 2", buf.reads)
+	}
+
+	// A new revision picks up the edit
+	buf.lines[1] = ""
+	buf.revision++
+	m := r.RenderMinimap(10, 4)
+	if got := strings.TrimSpace(StringFromCells(m.Rows[1])); got != "" {
+		t.Errorf("row 1 after edit = %q, want blank", got)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dshills/keystorm/internal/engine/buffer"
 	"github.com/dshills/keystorm/internal/renderer/backend"
 	"github.com/dshills/keystorm/internal/renderer/cursor"
+	"github.com/dshills/keystorm/internal/renderer/highlight"
 	"github.com/dshills/keystorm/internal/renderer/layout"
 	"github.com/dshills/keystorm/internal/renderer/selection"
+	"github.com/dshills/keystorm/internal/renderer/style"
 	"github.com/dshills/keystorm/internal/renderer/viewport"
 )
 
@@ -24,6 +27,14 @@ type BufferReader interface {
 	TabWidth() int
 }
 
+// RevisionReader is optionally implemented by a BufferReader that tracks
+// revisions. Cached content is then only revalidated after the revision
+// changes, rather than on every render.
+type RevisionReader interface {
+	// RevisionID returns the current buffer revision.
+	RevisionID() buffer.RevisionID
+}
+
 // CursorProvider provides cursor and selection information.
 type CursorProvider interface {
 	// PrimaryCursor returns the primary cursor position (line, column).
@@ -124,6 +135,8 @@ type Renderer struct {
 	cursorRender *cursor.Renderer
 	selManager   *selection.Manager
 	selRenderer  *selection.Renderer
+	resolver     *style.Resolver
+	minimap      *minimapCache
 
 	// Frame timing
 	lastFrame    time.Time
@@ -134,9 +147,19 @@ type Renderer struct {
 
 	// Gutter state
 	gutterWidth int
+	gutters     []GutterProvider
 
 	// Reserved space at bottom (for status line, etc.)
 	reservedBottomRows int
+
+	// Diff view shown instead of the buffer, if any
+	diff *diffView
+
+	// Whitespace and invisible character markers
+	whitespace WhitespaceOptions
+
+	// Inlay hints by line
+	inlayHints map[uint32][]InlayHint
 }
 
 // New creates a new renderer with the given backend and options.
@@ -173,6 +196,9 @@ func New(backend backend.Backend, opts Options) *Renderer {
 		cursorRender: cursor.New(cursorConfig),
 		selManager:   selection.NewManager(),
 		selRenderer:  selection.NewRenderer(selection.DefaultConfig()),
+		resolver:     style.NewResolver(),
+		minimap:      newMinimapCache(),
+		whitespace:   DefaultWhitespaceOptions(),
 		lastFrame:    time.Now(),
 		minFrameTime: time.Second / time.Duration(maxFPS),
 		needsRedraw:  true,
@@ -206,7 +232,9 @@ func (r *Renderer) SetBuffer(buf BufferReader) {
 		r.layout.SetTabWidth(buf.TabWidth())
 		r.viewport.SetMaxLine(buf.LineCount())
 	}
+	r.updateGutterWidth()
 	r.lineCache.InvalidateAll()
+	r.minimap.invalidateAll()
 	r.needsRedraw = true
 	r.fullRedraw = true
 }
@@ -225,10 +253,50 @@ func (r *Renderer) SetHighlightProvider(hp HighlightProvider) {
 	defer r.mu.Unlock()
 	r.hlProvider = hp
 	r.lineCache.InvalidateAll()
+	r.minimap.invalidateAll()
+	r.needsRedraw = true
+	r.fullRedraw = true
+}
+
+// SetTheme sets the theme used to style highlight spans that carry token
+// scopes. Spans without scopes keep the style of the highlight provider.
+func (r *Renderer) SetTheme(theme *highlight.Theme) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resolver.SetTheme(theme)
+	r.lineCache.InvalidateAll()
+	r.minimap.invalidateAll()
 	r.needsRedraw = true
 	r.fullRedraw = true
 }
 
+// Theme returns the theme used to style scoped highlight spans, or nil.
+func (r *Renderer) Theme() *highlight.Theme {
+	return r.resolver.Theme()
+}
+
+// themeSpans styles spans that carry token scopes with the theme, if any
+// (must hold lock).
+func (r *Renderer) themeSpans(spans []StyleSpan) []StyleSpan {
+	if r.resolver.Theme() == nil {
+		return spans
+	}
+	var themed []StyleSpan
+	for i, span := range spans {
+		if len(span.Scopes) == 0 {
+			continue
+		}
+		if themed == nil {
+			themed = append([]StyleSpan(nil), spans...)
+		}
+		themed[i].Style = r.resolver.Resolve(span.Scopes)
+	}
+	if themed == nil {
+		return spans
+	}
+	return themed
+}
+
 // Resize handles terminal resize events.
 func (r *Renderer) Resize(width, height int) {
 	r.mu.Lock()
@@ -261,6 +329,7 @@ func (r *Renderer) InvalidateLine(line uint32) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.lineCache.Invalidate(line)
+	r.minimap.invalidateLines(line, line)
 	r.needsRedraw = true
 }
 
@@ -269,6 +338,7 @@ func (r *Renderer) InvalidateLines(startLine, endLine uint32) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.lineCache.InvalidateRange(startLine, endLine)
+	r.minimap.invalidateLines(startLine, endLine)
 	r.needsRedraw = true
 }
 
@@ -308,6 +378,7 @@ func (r *Renderer) SetOptions(opts Options) {
 	)
 	r.viewport.SetSmoothScroll(opts.SmoothScroll)
 	r.backend.SetCursorStyle(opts.CursorStyle)
+	r.updateGutterWidth()
 	r.fullRedraw = true
 	r.needsRedraw = true
 }
@@ -377,6 +448,11 @@ func (r *Renderer) RenderNow() {
 
 // render performs the actual rendering (must hold lock).
 func (r *Renderer) render() {
+	if r.diff != nil {
+		r.renderDiff()
+		return
+	}
+
 	if r.bufReader == nil {
 		r.renderEmpty()
 		return
@@ -385,11 +461,8 @@ func (r *Renderer) render() {
 	// Update max line in viewport
 	r.viewport.SetMaxLine(r.bufReader.LineCount())
 
-	// Calculate gutter width
-	r.gutterWidth = r.calculateGutterWidth()
-
-	// Update viewport content width (unused for now but needed for horizontal scroll)
-	_ = r.width - r.gutterWidth
+	// Calculate gutter width; the viewport's text area starts after it
+	r.updateGutterWidth()
 
 	// Clear content area if full redraw (leaving status line area untouched)
 	if r.fullRedraw {
@@ -446,12 +519,14 @@ func (r *Renderer) renderLine(line uint32, screenRow int) {
 	if r.hlProvider != nil {
 		spans := r.hlProvider.HighlightsForLine(line)
 		if len(spans) > 0 {
-			r.layout.ApplyStyles(lineLayout, spans)
+			r.layout.ApplyStyles(lineLayout, r.themeSpans(spans))
 		}
 	}
 
 	// Get selection ranges for this line
 	lineSelections := r.selManager.SelectionsOnLine(line)
+	marks := r.whitespaceMarksFor(text, lineLayout)
+	hints := r.inlayRowFor(line, lineLayout)
 
 	// Render cells
 	leftCol := r.viewport.LeftColumn()
@@ -461,6 +536,15 @@ func (r *Renderer) renderLine(line uint32, screenRow int) {
 		visCol := leftCol + x
 		screenX := r.gutterWidth + x
 
+		// Inlay hints are drawn as is; other columns map back to the layout
+		if hints != nil {
+			if cell, ok := hints.hintCell(visCol); ok {
+				r.backend.SetCell(screenX, screenRow, cell)
+				continue
+			}
+			visCol = hints.layoutColumn(visCol)
+		}
+
 		var cell Cell
 		if visCol >= 0 && visCol < len(lineLayout.Cells) {
 			cell = lineLayout.Cells[visCol]
@@ -468,8 +552,12 @@ func (r *Renderer) renderLine(line uint32, screenRow int) {
 			cell = EmptyCell()
 		}
 
-		// Apply selection highlighting
-		if r.isColumnSelected(lineSelections, uint32(visCol), len(lineLayout.Cells)) {
+		// Apply whitespace markers, then selection highlighting
+		selected := r.isColumnSelected(lineSelections, uint32(visCol), len(lineLayout.Cells))
+		if marks != nil {
+			cell = marks.apply(cell, visCol, selected)
+		}
+		if selected {
 			cell = r.selRenderer.ApplySelection(cell, true)
 		}
 
@@ -501,6 +589,10 @@ func (r *Renderer) isColumnSelected(selections []selection.LineSelection, col ui
 
 // renderGutter renders the gutter (line numbers) for a line.
 func (r *Renderer) renderGutter(line uint32, screenRow int) {
+	if len(r.gutters) > 0 {
+		r.renderProviderGutters(line, screenRow)
+		return
+	}
 	if !r.opts.ShowLineNumbers {
 		return
 	}
@@ -576,12 +668,15 @@ func (r *Renderer) renderCursor() {
 	text := r.bufReader.LineText(line)
 	lineLayout := r.lineCache.Get(line, text)
 
-	// Convert buffer column to visual column
+	// Convert buffer column to visual column, after any inlay hints
 	visCol := lineLayout.VisualColumn(col)
+	if hints := r.inlayRowFor(line, lineLayout); hints != nil {
+		visCol = hints.displayColumn(visCol)
+	}
 
 	// Convert to screen coordinates
 	screenRow := r.viewport.LineToScreenRow(line)
-	screenCol := visCol - r.viewport.LeftColumn() + r.gutterWidth
+	screenCol := r.viewport.ColumnToScreenCol(visCol)
 
 	// Check if cursor is in visible area
 	if screenCol < r.gutterWidth || screenCol >= r.width {
@@ -594,7 +689,13 @@ func (r *Renderer) renderCursor() {
 
 // calculateGutterWidth calculates the required gutter width.
 func (r *Renderer) calculateGutterWidth() int {
-	if !r.opts.ShowGutter || !r.opts.ShowLineNumbers {
+	if !r.opts.ShowGutter {
+		return 0
+	}
+	if len(r.gutters) > 0 {
+		return r.providerGutterWidth()
+	}
+	if !r.opts.ShowLineNumbers {
 		return 0
 	}
 
@@ -682,6 +783,33 @@ func (r *Renderer) GutterWidth() int {
 	return r.gutterWidth
 }
 
+// ScreenToBuffer converts screen coordinates to a buffer position. The
+// column counts characters, with tabs and wide characters mapped to the
+// character they display, and inlay hints mapped to the character they are
+// drawn before. It returns false over the gutter, outside the text area,
+// and below the last line.
+func (r *Renderer) ScreenToBuffer(screenX, screenY int) (line, col uint32, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.bufReader == nil || screenX < r.gutterWidth || screenX >= r.width ||
+		screenY < 0 || screenY >= r.effectiveHeight() {
+		return 0, 0, false
+	}
+
+	line = r.viewport.TopLine() + uint32(screenY)
+	if line >= r.bufReader.LineCount() {
+		return 0, 0, false
+	}
+
+	visCol := r.viewport.LeftColumn() + screenX - r.gutterWidth
+	lineLayout := r.lineCache.Get(line, r.bufReader.LineText(line))
+	if hints := r.inlayRowFor(line, lineLayout); hints != nil {
+		visCol = hints.layoutColumn(visCol)
+	}
+	return line, lineLayout.BufferColumn(visCol), true
+}
+
 // SetReservedBottom sets the number of rows reserved at the bottom.
 // This is used to leave space for status lines, command lines, etc.
 func (r *Renderer) SetReservedBottom(rows int) {
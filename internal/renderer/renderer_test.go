@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/dshills/keystorm/internal/renderer/backend"
+	"github.com/dshills/keystorm/internal/renderer/highlight"
 )
 
 // mockBufferReader implements BufferReader for testing.
@@ -127,6 +128,36 @@ func TestRendererSetBuffer(t *testing.T) {
 	}
 }
 
+func TestRendererScreenToBuffer(t *testing.T) {
+	r := New(newTestBackend(80, 24), DefaultOptions())
+	r.SetBuffer(newMockBuffer("\tab", "x"))
+	gutter := r.GutterWidth()
+
+	tests := []struct {
+		name     string
+		x, y     int
+		wantLine uint32
+		wantCol  uint32
+		wantOK   bool
+	}{
+		{"start of tab", gutter, 0, 0, 0, true},
+		{"inside tab", gutter + 2, 0, 0, 0, true},
+		{"after tab", gutter + 4, 0, 0, 1, true},
+		{"second line", gutter, 1, 1, 0, true},
+		{"gutter", gutter - 1, 0, 0, 0, false},
+		{"past last line", gutter, 5, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, col, ok := r.ScreenToBuffer(tt.x, tt.y)
+			if ok != tt.wantOK || (ok && (line != tt.wantLine || col != tt.wantCol)) {
+				t.Errorf("ScreenToBuffer(%d, %d) = %d, %d, %v, want %d, %d, %v",
+					tt.x, tt.y, line, col, ok, tt.wantLine, tt.wantCol, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestRendererSetCursorProvider(t *testing.T) {
 	nullBackend := newTestBackend(80, 24)
 	r := New(nullBackend, DefaultOptions())
@@ -542,6 +573,39 @@ func TestRendererWithHighlighting(t *testing.T) {
 	}
 }
 
+func TestRendererThemeScopes(t *testing.T) {
+	nullBackend := newTestBackend(80, 24)
+	opts := DefaultOptions()
+	opts.ShowGutter = false
+	r := New(nullBackend, opts)
+
+	r.SetBuffer(newMockBuffer("func main() {}"))
+	r.SetHighlightProvider(&mockHighlightProvider{
+		highlights: map[uint32][]StyleSpan{
+			0: {
+				{StartCol: 0, EndCol: 4, Style: DefaultStyle().WithForeground(ColorBlue), Scopes: []string{"source.go", "keyword"}},
+				{StartCol: 5, EndCol: 9, Style: DefaultStyle().WithForeground(ColorGreen)},
+			},
+		},
+	})
+	r.RenderNow()
+	if got := nullBackend.GetCell(0, 0).Style.Foreground; got != ColorBlue {
+		t.Errorf("foreground without theme = %v, want %v", got, ColorBlue)
+	}
+
+	r.SetTheme(&highlight.Theme{
+		Name:        "Test",
+		ScopeStyles: map[string]Style{"keyword": DefaultStyle().WithForeground(ColorRed)},
+	})
+	r.RenderNow()
+	if got := nullBackend.GetCell(0, 0).Style.Foreground; got != ColorRed {
+		t.Errorf("scoped span foreground = %v, want %v", got, ColorRed)
+	}
+	if got := nullBackend.GetCell(5, 0).Style.Foreground; got != ColorGreen {
+		t.Errorf("unscoped span foreground = %v, want %v", got, ColorGreen)
+	}
+}
+
 func TestRendererCursorRendering(t *testing.T) {
 	nullBackend := newTestBackend(80, 24)
 	nullBackend.Init()
@@ -1,10 +1,21 @@
 // Package style provides style resolution for combining styles from multiple sources.
 // The StyleResolver handles priority-based style merging from syntax highlighting,
 // selections, overlays, diagnostics, and other visual layers.
+//
+// A highlight.Theme colors syntax by scope name, such as
+// "entity.name.function.go", falling back to the longest prefix with a
+// style. Token spans from LSP semantic tokens or the tokenizer become syntax
+// spans with Resolver.ScopeSpan once a theme is set:
+//
+//	resolver.SetTheme(registry.Current())
+//	span := resolver.ScopeSpan(start, end, []string{"source.go", "entity.name.function.go"})
 package style
 
 import (
+	"sync"
+
 	"github.com/dshills/keystorm/internal/renderer/core"
+	"github.com/dshills/keystorm/internal/renderer/highlight"
 )
 
 // Layer represents a style layer with priority.
@@ -108,6 +119,12 @@ type Resolver struct {
 
 	// layerEnabled tracks which layers are enabled.
 	layerEnabled [LayerCount]bool
+
+	// themeMu guards theme.
+	themeMu sync.RWMutex
+
+	// theme styles token scopes (nil for none).
+	theme *highlight.Theme
 }
 
 // NewResolver creates a new style resolver.
@@ -144,8 +161,48 @@ func (r *Resolver) IsLayerEnabled(layer Layer) bool {
 	return r.layerEnabled[layer]
 }
 
-// Resolve combines styles from multiple spans at a specific column.
-func (r *Resolver) Resolve(col uint32, spans []Span) core.Style {
+// SetTheme sets the theme used to style token scopes. Switching between
+// light and dark is done by setting the registry's theme for the variant.
+func (r *Resolver) SetTheme(theme *highlight.Theme) {
+	r.themeMu.Lock()
+	defer r.themeMu.Unlock()
+	r.theme = theme
+}
+
+// Theme returns the theme used to style token scopes.
+func (r *Resolver) Theme() *highlight.Theme {
+	r.themeMu.RLock()
+	defer r.themeMu.RUnlock()
+	return r.theme
+}
+
+// Resolve returns the theme style of a token's scope stack, such as the
+// scopes of a semantic token or a tokenizer match. Scopes are ordered from
+// outermost to innermost and tried from innermost, each falling back to
+// its longest prefix with a style; see highlight.Theme.StyleForScopes.
+// Returns the theme's default style if no scope has one, or the base style
+// without a theme.
+func (r *Resolver) Resolve(scopes []string) core.Style {
+	theme := r.Theme()
+	if theme == nil {
+		return r.baseStyle
+	}
+	return theme.StyleForScopes(scopes)
+}
+
+// ScopeSpan returns a syntax span styled by the theme style of scopes.
+func (r *Resolver) ScopeSpan(startCol, endCol uint32, scopes []string) Span {
+	return Span{
+		StartCol: startCol,
+		EndCol:   endCol,
+		Style:    r.Resolve(scopes),
+		Layer:    LayerSyntax,
+		Merge:    MergeOverlay,
+	}
+}
+
+// ResolveAt combines styles from multiple spans at a specific column.
+func (r *Resolver) ResolveAt(col uint32, spans []Span) core.Style {
 	result := r.baseStyle
 
 	// Process spans in layer order (lower layers first)
@@ -172,7 +229,7 @@ func (r *Resolver) Resolve(col uint32, spans []Span) core.Style {
 
 // ResolveCell resolves the style for a cell and returns an updated cell.
 func (r *Resolver) ResolveCell(cell core.Cell, col uint32, spans []Span) core.Cell {
-	cell.Style = r.Resolve(col, spans)
+	cell.Style = r.ResolveAt(col, spans)
 	return cell
 }
 
@@ -187,7 +244,7 @@ func (r *Resolver) ResolveLine(cells []core.Cell, spans []Span) []core.Cell {
 	copy(result, cells)
 
 	for i := range result {
-		result[i].Style = r.Resolve(uint32(i), spans)
+		result[i].Style = r.ResolveAt(uint32(i), spans)
 	}
 
 	return result
@@ -338,7 +395,7 @@ func (lr *LineResolver) AddSpans(spans []Span) {
 
 // Resolve resolves the style at a column.
 func (lr *LineResolver) Resolve(col uint32) core.Style {
-	return lr.resolver.Resolve(col, lr.spans)
+	return lr.resolver.ResolveAt(col, lr.spans)
 }
 
 // ResolveCell resolves and updates a cell's style.
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/dshills/keystorm/internal/renderer/core"
+	"github.com/dshills/keystorm/internal/renderer/highlight"
 )
 
 func TestLayerString(t *testing.T) {
@@ -51,7 +52,7 @@ func TestResolverSetBaseStyle(t *testing.T) {
 	r.SetBaseStyle(style)
 
 	// Resolve with no spans should return base style
-	result := r.Resolve(0, nil)
+	result := r.ResolveAt(0, nil)
 	if result.Foreground != style.Foreground {
 		t.Error("Base style not applied correctly")
 	}
@@ -78,13 +79,13 @@ func TestResolverSetLayerEnabled(t *testing.T) {
 	}
 }
 
-func TestResolverResolve(t *testing.T) {
+func TestResolverResolveAt(t *testing.T) {
 	t.Run("no spans returns base style", func(t *testing.T) {
 		r := NewResolver()
 		baseStyle := core.NewStyle(core.ColorFromRGB(200, 200, 200))
 		r.SetBaseStyle(baseStyle)
 
-		result := r.Resolve(5, nil)
+		result := r.ResolveAt(5, nil)
 
 		if result.Foreground != baseStyle.Foreground {
 			t.Error("Should return base style with no spans")
@@ -102,7 +103,7 @@ func TestResolverResolve(t *testing.T) {
 			Merge:    MergeReplace,
 		}}
 
-		result := r.Resolve(5, spans)
+		result := r.ResolveAt(5, spans)
 
 		if result.Foreground != spanStyle.Foreground {
 			t.Error("Span style should be applied")
@@ -123,7 +124,7 @@ func TestResolverResolve(t *testing.T) {
 			Merge:    MergeReplace,
 		}}
 
-		result := r.Resolve(5, spans)
+		result := r.ResolveAt(5, spans)
 
 		if result.Foreground != baseStyle.Foreground {
 			t.Error("Column outside span should use base style")
@@ -140,7 +141,7 @@ func TestResolverResolve(t *testing.T) {
 			{StartCol: 0, EndCol: 10, Style: selectionStyle, Layer: LayerSelection, Merge: MergeReplace},
 		}
 
-		result := r.Resolve(5, spans)
+		result := r.ResolveAt(5, spans)
 
 		// Selection has higher priority than syntax
 		if result.Foreground != selectionStyle.Foreground {
@@ -164,7 +165,7 @@ func TestResolverResolve(t *testing.T) {
 			Merge:    MergeReplace,
 		}}
 
-		result := r.Resolve(5, spans)
+		result := r.ResolveAt(5, spans)
 
 		if result.Foreground != baseStyle.Foreground {
 			t.Error("Disabled layer should be skipped")
@@ -188,7 +189,7 @@ func TestResolverMergeModes(t *testing.T) {
 			Merge:    MergeReplace,
 		}}
 
-		result := r.Resolve(5, spans)
+		result := r.ResolveAt(5, spans)
 
 		if result.Foreground != overlayStyle.Foreground {
 			t.Error("MergeReplace should completely replace style")
@@ -214,7 +215,7 @@ func TestResolverMergeModes(t *testing.T) {
 			Merge:    MergeOverlay,
 		}}
 
-		result := r.Resolve(5, spans)
+		result := r.ResolveAt(5, spans)
 
 		if result.Foreground != overlayStyle.Foreground {
 			t.Error("MergeOverlay should apply overlay foreground")
@@ -238,7 +239,7 @@ func TestResolverMergeModes(t *testing.T) {
 			Merge:    MergeAttributes,
 		}}
 
-		result := r.Resolve(5, spans)
+		result := r.ResolveAt(5, spans)
 
 		// Should preserve base foreground
 		if result.Foreground != baseStyle.Foreground {
@@ -269,7 +270,7 @@ func TestResolverMergeModes(t *testing.T) {
 			Merge:    MergeForeground,
 		}}
 
-		result := r.Resolve(5, spans)
+		result := r.ResolveAt(5, spans)
 
 		if result.Foreground != overlayStyle.Foreground {
 			t.Error("MergeForeground should apply overlay foreground")
@@ -295,7 +296,7 @@ func TestResolverMergeModes(t *testing.T) {
 			Merge:    MergeBackground,
 		}}
 
-		result := r.Resolve(5, spans)
+		result := r.ResolveAt(5, spans)
 
 		if result.Foreground != baseStyle.Foreground {
 			t.Error("MergeBackground should preserve base foreground")
@@ -598,3 +599,30 @@ func TestDefaultStyles(t *testing.T) {
 	_ = ds.DiffModify
 	_ = ds.GhostText
 }
+
+func TestResolverResolve(t *testing.T) {
+	r := NewResolver()
+	base := core.NewStyle(core.ColorFromRGB(9, 9, 9))
+	r.SetBaseStyle(base)
+
+	if got := r.Resolve([]string{"keyword"}); !got.Equals(base) {
+		t.Errorf("Resolve() without theme = %+v, want base style", got)
+	}
+
+	theme := highlight.DefaultTheme()
+	theme.ScopeStyles["entity.name.function"] = core.NewStyle(core.ColorFromRGB(1, 2, 3)).Bold()
+	r.SetTheme(theme)
+
+	span := r.ScopeSpan(2, 6, []string{"source.go", "entity.name.function.go"})
+	if span.Layer != LayerSyntax || span.StartCol != 2 || span.EndCol != 6 {
+		t.Errorf("ScopeSpan() = %+v, want a syntax span over [2, 6)", span)
+	}
+	if !span.Style.Attributes.Has(core.AttrBold) {
+		t.Errorf("ScopeSpan() style = %+v, want the bold function style", span.Style)
+	}
+
+	r.SetTheme(highlight.LightTheme())
+	if got := r.Resolve([]string{"entity.name.function.go"}); got.Attributes.Has(core.AttrBold) {
+		t.Errorf("Resolve() after SetTheme = %+v, want the light style", got)
+	}
+}
@@ -18,6 +18,10 @@ type Viewport struct {
 	width  int
 	height int
 
+	// textOffset is the number of screen columns left of the text area
+	// (e.g. the gutter). Screen columns include it; buffer columns do not.
+	textOffset int
+
 	// Scroll margins (keep cursor this far from edges)
 	marginTop    int
 	marginBottom int
@@ -112,7 +116,43 @@ func (v *Viewport) LeftColumn() int {
 func (v *Viewport) RightColumn() int {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
-	return v.leftColumn + v.width
+	return v.leftColumn + v.textWidth()
+}
+
+// TextOffset returns the number of screen columns left of the text area.
+func (v *Viewport) TextOffset() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.textOffset
+}
+
+// SetTextOffset sets the number of screen columns left of the text area,
+// such as the gutter width. Screen column conversions and horizontal
+// scrolling account for the offset.
+func (v *Viewport) SetTextOffset(offset int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if offset < 0 {
+		offset = 0
+	}
+	v.textOffset = offset
+}
+
+// TextWidth returns the width of the text area in screen cells.
+func (v *Viewport) TextWidth() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.textWidth()
+}
+
+// textWidth returns the text area width (internal, no lock).
+// It is at least 1 to prevent underflow.
+func (v *Viewport) textWidth() int {
+	w := v.width - v.textOffset
+	if w < 1 {
+		w = 1
+	}
+	return w
 }
 
 // Resize updates the viewport size.
@@ -198,7 +238,7 @@ func (v *Viewport) IsLineVisible(line uint32) bool {
 func (v *Viewport) IsColumnVisible(col int) bool {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
-	return col >= v.leftColumn && col < v.leftColumn+v.width
+	return col >= v.leftColumn && col < v.leftColumn+v.textWidth()
 }
 
 // IsPositionVisible returns true if the position is within the viewport.
@@ -206,7 +246,7 @@ func (v *Viewport) IsPositionVisible(line uint32, col int) bool {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 	return line >= v.topLine && line <= v.bottomLine() &&
-		col >= v.leftColumn && col < v.leftColumn+v.width
+		col >= v.leftColumn && col < v.leftColumn+v.textWidth()
 }
 
 // LineToScreenRow converts a buffer line to a screen row.
@@ -240,14 +280,14 @@ func (v *Viewport) ScreenRowToLine(row int) uint32 {
 func (v *Viewport) ColumnToScreenCol(col int) int {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
-	return col - v.leftColumn
+	return col - v.leftColumn + v.textOffset
 }
 
 // ScreenColToColumn converts a screen column to a buffer column.
 func (v *Viewport) ScreenColToColumn(col int) int {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
-	return col + v.leftColumn
+	return col - v.textOffset + v.leftColumn
 }
 
 // BufferToScreen converts buffer coordinates to screen coordinates.
@@ -259,11 +299,11 @@ func (v *Viewport) BufferToScreen(line uint32, col int) (screenRow, screenCol in
 	if line < v.topLine || line > v.bottomLine() {
 		return -1, -1
 	}
-	if col < v.leftColumn || col >= v.leftColumn+v.width {
+	if col < v.leftColumn || col >= v.leftColumn+v.textWidth() {
 		return -1, -1
 	}
 
-	return int(line - v.topLine), col - v.leftColumn
+	return int(line - v.topLine), col - v.leftColumn + v.textOffset
 }
 
 // ScreenToBuffer converts screen coordinates to buffer coordinates.
@@ -272,7 +312,7 @@ func (v *Viewport) ScreenToBuffer(screenRow, screenCol int) (line uint32, col in
 	defer v.mu.RUnlock()
 
 	line = v.topLine + uint32(screenRow)
-	col = v.leftColumn + screenCol
+	col = v.leftColumn + screenCol - v.textOffset
 	return
 }
 
@@ -392,8 +432,8 @@ func (v *Viewport) ScrollToReveal(line uint32, col int, smooth bool) bool {
 			targetLeft = 0
 		}
 		needScroll = true
-	} else if screenCol > v.width-v.marginRight {
-		targetLeft = col - v.width + v.marginRight
+	} else if screenCol > v.textWidth()-v.marginRight {
+		targetLeft = col - v.textWidth() + v.marginRight
 		needScroll = true
 	}
 
@@ -623,6 +663,7 @@ func (v *Viewport) Clone() *Viewport {
 		leftColumn:       v.leftColumn,
 		width:            v.width,
 		height:           v.height,
+		textOffset:       v.textOffset,
 		marginTop:        v.marginTop,
 		marginBottom:     v.marginBottom,
 		marginLeft:       v.marginLeft,
@@ -416,3 +416,36 @@ func TestViewportMaxLineClamp(t *testing.T) {
 		t.Errorf("top line should be clamped, got %d", v.TopLine())
 	}
 }
+
+func TestViewportTextOffset(t *testing.T) {
+	v := NewViewport(80, 24)
+	v.SetMaxLine(100)
+	v.SetTextOffset(6)
+
+	if got := v.TextWidth(); got != 74 {
+		t.Errorf("TextWidth() = %d, want 74", got)
+	}
+	if got := v.ColumnToScreenCol(0); got != 6 {
+		t.Errorf("ColumnToScreenCol(0) = %d, want 6", got)
+	}
+	if got := v.ScreenColToColumn(6); got != 0 {
+		t.Errorf("ScreenColToColumn(6) = %d, want 0", got)
+	}
+
+	row, col := v.BufferToScreen(2, 10)
+	if row != 2 || col != 16 {
+		t.Errorf("BufferToScreen(2, 10) = (%d, %d), want (2, 16)", row, col)
+	}
+	line, bufCol := v.ScreenToBuffer(2, 16)
+	if line != 2 || bufCol != 10 {
+		t.Errorf("ScreenToBuffer(2, 16) = (%d, %d), want (2, 10)", line, bufCol)
+	}
+
+	// Columns past the text area are not visible
+	if v.IsColumnVisible(74) {
+		t.Error("column 74 should not be visible with a 6 column offset")
+	}
+	if row, col := v.BufferToScreen(2, 74); row != -1 || col != -1 {
+		t.Errorf("BufferToScreen(2, 74) = (%d, %d), want (-1, -1)", row, col)
+	}
+}
@@ -0,0 +1,165 @@
+package renderer
+
+import (
+	"github.com/dshills/keystorm/internal/renderer/layout"
+)
+
+// WhitespaceMode controls where whitespace markers are drawn.
+type WhitespaceMode uint8
+
+const (
+	// WhitespaceNone draws no markers.
+	WhitespaceNone WhitespaceMode = iota
+
+	// WhitespaceSelection draws markers only inside selections.
+	WhitespaceSelection
+
+	// WhitespaceAlways draws markers everywhere.
+	WhitespaceAlways
+)
+
+// WhitespaceOptions configures how whitespace and invisible characters are
+// drawn. Markers replace the glyph of the cells a character already
+// occupies, so they never change the layout or cursor columns. A zero
+// glyph leaves that kind of character unmarked.
+type WhitespaceOptions struct {
+	// Mode controls where markers are drawn.
+	Mode WhitespaceMode
+
+	// Space marks a space.
+	Space rune
+
+	// Tab marks the first cell of a tab; the rest of the tab stays blank.
+	Tab rune
+
+	// NonBreakingSpace marks U+00A0 and U+202F.
+	NonBreakingSpace rune
+
+	// ZeroWidth marks zero-width spaces, joiners and byte order marks.
+	ZeroWidth rune
+
+	// MarkerStyle is merged into the style of marked cells.
+	MarkerStyle Style
+
+	// HighlightTrailing highlights whitespace at the end of lines with
+	// TrailingStyle, whatever the Mode. Selections take precedence.
+	HighlightTrailing bool
+
+	// TrailingStyle is merged into the style of trailing whitespace.
+	TrailingStyle Style
+}
+
+// DefaultWhitespaceOptions returns the conventional markers, with markers
+// and the trailing whitespace highlight off.
+func DefaultWhitespaceOptions() WhitespaceOptions {
+	return WhitespaceOptions{
+		Mode:             WhitespaceNone,
+		Space:            '·',
+		Tab:              '→',
+		NonBreakingSpace: '⍽',
+		ZeroWidth:        '‸',
+		MarkerStyle:      DefaultStyle().WithForeground(ColorGray),
+		TrailingStyle:    DefaultStyle().WithBackground(ColorRed),
+	}
+}
+
+// SetWhitespaceRendering sets how whitespace and invisible characters are
+// drawn.
+func (r *Renderer) SetWhitespaceRendering(opts WhitespaceOptions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.whitespace = opts
+	r.needsRedraw = true
+	r.fullRedraw = true
+}
+
+// WhitespaceRendering returns how whitespace and invisible characters are
+// drawn.
+func (r *Renderer) WhitespaceRendering() WhitespaceOptions {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.whitespace
+}
+
+// whitespaceMarks decorates the cells of one laid out line.
+type whitespaceMarks struct {
+	opts   *WhitespaceOptions
+	layout *layout.LineLayout
+	runes  []rune
+
+	// trailing is the buffer column where trailing whitespace starts
+	trailing uint32
+}
+
+// whitespaceMarksFor returns the decorations for a line, or nil if nothing
+// is drawn.
+func (r *Renderer) whitespaceMarksFor(text string, lineLayout *layout.LineLayout) *whitespaceMarks {
+	if r.whitespace.Mode == WhitespaceNone && !r.whitespace.HighlightTrailing {
+		return nil
+	}
+
+	runes := []rune(text)
+	trailing := len(runes)
+	for trailing > 0 && isMarkedWhitespace(runes[trailing-1]) {
+		trailing--
+	}
+	return &whitespaceMarks{
+		opts:     &r.whitespace,
+		layout:   lineLayout,
+		runes:    runes,
+		trailing: uint32(trailing),
+	}
+}
+
+// apply returns the cell at visCol decorated according to the options.
+func (m *whitespaceMarks) apply(cell Cell, visCol int, selected bool) Cell {
+	if visCol < 0 || visCol >= len(m.layout.VisualCols) {
+		return cell
+	}
+	bufCol := m.layout.VisualCols[visCol]
+	if int(bufCol) >= len(m.runes) {
+		return cell
+	}
+
+	if m.opts.HighlightTrailing && !selected && bufCol >= m.trailing {
+		cell.Style = cell.Style.Merge(m.opts.TrailingStyle)
+	}
+
+	if m.opts.Mode == WhitespaceAlways || (m.opts.Mode == WhitespaceSelection && selected) {
+		if glyph := m.glyph(m.runes[bufCol], visCol, bufCol); glyph != 0 {
+			cell.Rune = glyph
+			cell.Style = cell.Style.Merge(m.opts.MarkerStyle)
+		}
+	}
+	return cell
+}
+
+// glyph returns the marker for the character at bufCol drawn at visCol, or
+// 0 if the cell is not marked.
+func (m *whitespaceMarks) glyph(r rune, visCol int, bufCol uint32) rune {
+	switch r {
+	case ' ':
+		return m.opts.Space
+	case '\t':
+		// Only the first cell of the expanded tab
+		if visCol > 0 && m.layout.VisualCols[visCol-1] == bufCol {
+			return 0
+		}
+		return m.opts.Tab
+	case '\u00a0', '\u202f':
+		return m.opts.NonBreakingSpace
+	case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+		return m.opts.ZeroWidth
+	}
+	return 0
+}
+
+// isMarkedWhitespace reports whether r counts as trailing whitespace.
+func isMarkedWhitespace(r rune) bool {
+	switch r {
+	case ' ', '\t', '\u00a0', '\u202f':
+		return true
+	}
+	return false
+}
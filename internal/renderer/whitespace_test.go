@@ -0,0 +1,75 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/dshills/keystorm/internal/renderer/selection"
+)
+
+// contentText returns the runes drawn in the first n content columns of a row.
+func contentText(r *Renderer, b interface{ GetCell(x, y int) Cell }, row, n int) string {
+	runes := make([]rune, n)
+	for x := 0; x < n; x++ {
+		runes[x] = b.GetCell(r.GutterWidth()+x, row).Rune
+	}
+	return string(runes)
+}
+
+func TestRendererWhitespaceAlways(t *testing.T) {
+	nullBackend := newTestBackend(40, 5)
+	r := New(nullBackend, DefaultOptions())
+	r.SetBuffer(newMockBuffer("\tx = 1  ", "a\u00a0b\u200bc"))
+	r.SetCursorProvider(&mockCursorProvider{line: 0, col: 2})
+
+	r.RenderNow()
+	cursorX, _, _ := nullBackend.CursorPosition()
+	if got := contentText(r, nullBackend, 0, 10); got != "    x = 1 " {
+		t.Errorf("line 0 without markers = %q", got)
+	}
+
+	opts := DefaultWhitespaceOptions()
+	opts.Mode = WhitespaceAlways
+	opts.HighlightTrailing = true
+	r.SetWhitespaceRendering(opts)
+	r.RenderNow()
+
+	if got, want := contentText(r, nullBackend, 0, 10), "→   x·=·1·"; got != want {
+		t.Errorf("line 0 = %q, want %q", got, want)
+	}
+	if got, want := contentText(r, nullBackend, 1, 5), "a⍽b‸c"; got != want {
+		t.Errorf("line 1 = %q, want %q", got, want)
+	}
+
+	// Only the two trailing spaces are highlighted
+	gutter := r.GutterWidth()
+	for x := 0; x < 11; x++ {
+		trailing := x >= 9
+		cell := nullBackend.GetCell(gutter+x, 0)
+		if highlighted := cell.Style.Background.Equals(ColorRed); highlighted != trailing {
+			t.Errorf("column %d highlighted = %v, want %v", x, highlighted, trailing)
+		}
+	}
+
+	// Markers are drawn over existing cells, so the cursor does not move
+	if x, _, _ := nullBackend.CursorPosition(); x != cursorX {
+		t.Errorf("cursor x = %d with markers, want %d", x, cursorX)
+	}
+}
+
+func TestRendererWhitespaceSelection(t *testing.T) {
+	nullBackend := newTestBackend(40, 5)
+	r := New(nullBackend, DefaultOptions())
+	r.SetBuffer(newMockBuffer("a b c d"))
+
+	opts := DefaultWhitespaceOptions()
+	opts.Mode = WhitespaceSelection
+	r.SetWhitespaceRendering(opts)
+
+	r.StartSelection(0, 2, selection.TypeNormal)
+	r.ExtendSelection(0, 5)
+	r.RenderNow()
+
+	if got, want := contentText(r, nullBackend, 0, 7), "a b·c d"; got != want {
+		t.Errorf("line = %q, want %q", got, want)
+	}
+}
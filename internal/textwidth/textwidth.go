@@ -0,0 +1,54 @@
+// Package textwidth measures the display width of text in terminal
+// columns. It is shared by the engine, which aligns text by display
+// column, and the renderer, which lays out cells.
+package textwidth
+
+// RuneWidth returns the number of terminal columns r takes up: 0 for
+// control characters, 2 for wide (East Asian) characters and 1 otherwise.
+func RuneWidth(r rune) int {
+	if r < 32 || r == 0x7F {
+		return 0
+	}
+	if isWideRune(r) {
+		return 2
+	}
+	return 1
+}
+
+// isWideRune checks if a rune is a wide (double-width) character.
+func isWideRune(r rune) bool {
+	if r >= 0x1100 && r <= 0x115F {
+		return true
+	}
+	if r >= 0x3130 && r <= 0x318F {
+		return true
+	}
+	if r >= 0x2E80 && r <= 0x9FFF {
+		return true
+	}
+	if r >= 0xAC00 && r <= 0xD7A3 {
+		return true
+	}
+	if r >= 0xF900 && r <= 0xFAFF {
+		return true
+	}
+	if r >= 0xFE10 && r <= 0xFE1F {
+		return true
+	}
+	if r >= 0xFE30 && r <= 0xFE6F {
+		return true
+	}
+	if r >= 0xFF00 && r <= 0xFF60 {
+		return true
+	}
+	if r >= 0xFFE0 && r <= 0xFFE6 {
+		return true
+	}
+	if r >= 0x20000 && r <= 0x2FFFF {
+		return true
+	}
+	if r >= 0x2F800 && r <= 0x2FA1F {
+		return true
+	}
+	return false
+}